@@ -0,0 +1,251 @@
+package chainfailover
+
+import (
+	"sync"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// ChainSource is a chain.Interface that passes all calls, including the
+// broadcast of transactions, on to the chain client of the active backend.
+// The chain client of a new backend notifies the wallet that it's connected
+// once it's started, upon which the wallet syncs its state with the chain of
+// the new backend and rebroadcasts its unconfirmed transactions.
+type ChainSource struct {
+	mtx sync.RWMutex
+
+	// active is the chain client of the active backend.
+	active chain.Interface
+
+	started bool
+	stopped bool
+
+	notifications chan interface{}
+
+	fwdQuit chan struct{}
+	fwdWg   sync.WaitGroup
+}
+
+// A compile-time check to ensure ChainSource implements chain.Interface.
+var _ chain.Interface = (*ChainSource)(nil)
+
+// newChainSource creates a new chain source that isn't backed by any backend
+// yet.
+func newChainSource() *ChainSource {
+	return &ChainSource{
+		notifications: make(chan interface{}),
+	}
+}
+
+// Active returns the chain client of the active backend.
+func (c *ChainSource) Active() chain.Interface {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.active
+}
+
+// Start starts the chain client of the active backend.
+func (c *ChainSource) Start() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.started {
+		return nil
+	}
+
+	if err := c.active.Start(); err != nil {
+		return err
+	}
+
+	c.startForwarder(c.active)
+	c.started = true
+
+	return nil
+}
+
+// Stop stops the chain client of the active backend.
+func (c *ChainSource) Stop() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.stopped {
+		return
+	}
+	c.stopped = true
+
+	c.stopForwarder()
+	c.active.Stop()
+
+	close(c.notifications)
+}
+
+// WaitForShutdown blocks until the chain client of the active backend has
+// shut down.
+func (c *ChainSource) WaitForShutdown() {
+	c.Active().WaitForShutdown()
+}
+
+// GetBestBlock returns the best block of the active backend.
+func (c *ChainSource) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return c.Active().GetBestBlock()
+}
+
+// GetBlock returns the block with the given hash.
+func (c *ChainSource) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return c.Active().GetBlock(hash)
+}
+
+// GetBlockHash returns the hash of the block at the given height.
+func (c *ChainSource) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return c.Active().GetBlockHash(height)
+}
+
+// GetBlockHeader returns the header of the block with the given hash.
+func (c *ChainSource) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	return c.Active().GetBlockHeader(hash)
+}
+
+// IsCurrent returns whether the active backend is synced to the chain tip.
+func (c *ChainSource) IsCurrent() bool {
+	return c.Active().IsCurrent()
+}
+
+// FilterBlocks scans the given blocks for addresses and outpoints of the
+// wallet.
+func (c *ChainSource) FilterBlocks(
+	req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+
+	return c.Active().FilterBlocks(req)
+}
+
+// BlockStamp returns the latest block notified by the chain client of the
+// active backend.
+func (c *ChainSource) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	return c.Active().BlockStamp()
+}
+
+// SendRawTransaction broadcasts the transaction using the active backend.
+func (c *ChainSource) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	return c.Active().SendRawTransaction(tx, allowHighFees)
+}
+
+// Rescan rescans the chain of the active backend from the given block for
+// the given addresses and outpoints.
+func (c *ChainSource) Rescan(startHash *chainhash.Hash,
+	addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	return c.Active().Rescan(startHash, addrs, outPoints)
+}
+
+// NotifyReceived watches the given addresses for received funds.
+func (c *ChainSource) NotifyReceived(addrs []btcutil.Address) error {
+	return c.Active().NotifyReceived(addrs)
+}
+
+// NotifyBlocks enables the notifications of new blocks.
+func (c *ChainSource) NotifyBlocks() error {
+	return c.Active().NotifyBlocks()
+}
+
+// Notifications returns the channel the notifications of the chain client of
+// the active backend are sent on. The channel stays the same across backend
+// switches.
+func (c *ChainSource) Notifications() <-chan interface{} {
+	return c.notifications
+}
+
+// BackEnd returns the name of the backend type of the active backend.
+func (c *ChainSource) BackEnd() string {
+	return c.Active().BackEnd()
+}
+
+// TestMempoolAccept checks whether the given transactions would be accepted
+// to the mempool of the active backend.
+func (c *ChainSource) TestMempoolAccept(txns []*wire.MsgTx,
+	maxFeeRate float64) ([]*btcjson.TestMempoolAcceptResult, error) {
+
+	return c.Active().TestMempoolAccept(txns, maxFeeRate)
+}
+
+// MapRPCErr maps an error of the active backend to an error of the chain
+// package.
+func (c *ChainSource) MapRPCErr(err error) error {
+	return c.Active().MapRPCErr(err)
+}
+
+// switchBackend replaces the chain client of the active backend with the one
+// of a new backend.
+func (c *ChainSource) switchBackend(source chain.Interface) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	prev := c.active
+	c.active = source
+
+	if prev == nil || !c.started || c.stopped {
+		return nil
+	}
+
+	c.stopForwarder()
+	prev.Stop()
+	prev.WaitForShutdown()
+
+	if err := source.Start(); err != nil {
+		return err
+	}
+
+	c.startForwarder(source)
+
+	return nil
+}
+
+// startForwarder starts sending the notifications of the given chain client
+// to the wallet.
+func (c *ChainSource) startForwarder(source chain.Interface) {
+	quit := make(chan struct{})
+	c.fwdQuit = quit
+
+	c.fwdWg.Add(1)
+	go func() {
+		defer c.fwdWg.Done()
+
+		for {
+			select {
+			case ntfn, ok := <-source.Notifications():
+				if !ok {
+					return
+				}
+
+				if !send(c.notifications, ntfn, quit) {
+					return
+				}
+
+			case <-quit:
+				return
+			}
+		}
+	}()
+}
+
+// stopForwarder stops sending the notifications of the current chain client,
+// if it's running.
+func (c *ChainSource) stopForwarder() {
+	if c.fwdQuit == nil {
+		return
+	}
+
+	close(c.fwdQuit)
+	c.fwdWg.Wait()
+	c.fwdQuit = nil
+}
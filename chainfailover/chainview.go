@@ -0,0 +1,419 @@
+package chainfailover
+
+import (
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/routing/chainview"
+)
+
+// maxBlockHistory is the number of blocks sent to the client that are kept to
+// detect blocks that aren't part of the chain of a new backend.
+const maxBlockHistory = 100
+
+// blockStamp identifies a block that was sent to the client.
+type blockStamp struct {
+	hash   chainhash.Hash
+	height uint32
+}
+
+// ChainView is a chainview.FilteredChainView that sends the blocks of the
+// chain view of the active backend. On a backend switch, blocks the client
+// was notified about that aren't part of the chain of the new backend are
+// disconnected, and blocks that were missed during the switch are filtered
+// and sent, such that no block is skipped.
+type ChainView struct {
+	mtx sync.Mutex
+
+	// active is the chain view of the active backend.
+	active chainview.FilteredChainView
+
+	// chainConn is the connection to the active backend.
+	chainConn ChainConn
+
+	started bool
+	stopped bool
+
+	// filter is the set of watched outpoints. It's loaded into the chain
+	// view of a new backend.
+	filter    map[wire.OutPoint]channeldb.EdgePoint
+	filterMtx sync.Mutex
+
+	// history holds the most recent blocks that were sent to the client,
+	// in ascending order. It's only accessed by the forwarding goroutine,
+	// or while it isn't running.
+	history []blockStamp
+
+	newBlocks   chan *chainview.FilteredBlock
+	staleBlocks chan *chainview.FilteredBlock
+
+	fwdQuit chan struct{}
+	fwdWg   sync.WaitGroup
+}
+
+// A compile-time check to ensure ChainView implements the FilteredChainView
+// interface.
+var _ chainview.FilteredChainView = (*ChainView)(nil)
+
+// newChainView creates a new chain view that isn't backed by any backend yet.
+func newChainView() *ChainView {
+	return &ChainView{
+		filter:      make(map[wire.OutPoint]channeldb.EdgePoint),
+		newBlocks:   make(chan *chainview.FilteredBlock),
+		staleBlocks: make(chan *chainview.FilteredBlock),
+	}
+}
+
+// Start starts the chain view of the active backend.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (c *ChainView) Start() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.started {
+		return nil
+	}
+
+	if err := c.active.Start(); err != nil {
+		return err
+	}
+
+	// The best block of the backend is where the client starts, so we'll
+	// catch up from there if the backend is switched before the next
+	// block.
+	if err := c.addBestBlock(c.chainConn); err != nil {
+		return err
+	}
+
+	c.startForwarder(c.active, c.chainConn, nil)
+	c.started = true
+
+	return nil
+}
+
+// Stop stops the chain view of the active backend.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (c *ChainView) Stop() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.stopped {
+		return nil
+	}
+	c.stopped = true
+
+	c.stopForwarder()
+
+	return c.active.Stop()
+}
+
+// FilteredBlocks returns the channel that filtered blocks are to be sent over.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (c *ChainView) FilteredBlocks() <-chan *chainview.FilteredBlock {
+	return c.newBlocks
+}
+
+// DisconnectedBlocks returns the channel that filtered blocks are to be sent
+// over in case of a reorg.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (c *ChainView) DisconnectedBlocks() <-chan *chainview.FilteredBlock {
+	return c.staleBlocks
+}
+
+// UpdateFilter updates the UTXO filter of the chain view of the active
+// backend. The outpoints are also loaded into the chain view of any backend
+// that becomes active later on.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (c *ChainView) UpdateFilter(ops []channeldb.EdgePoint,
+	updateHeight uint32) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.filterMtx.Lock()
+	for _, op := range ops {
+		c.filter[op.OutPoint] = op
+	}
+	c.filterMtx.Unlock()
+
+	return c.active.UpdateFilter(ops, updateHeight)
+}
+
+// FilterBlock filters the block with the given hash using the chain view of
+// the active backend.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (c *ChainView) FilterBlock(
+	blockHash *chainhash.Hash) (*chainview.FilteredBlock, error) {
+
+	c.mtx.Lock()
+	active := c.active
+	c.mtx.Unlock()
+
+	block, err := active.FilterBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	c.removeSpent(block)
+
+	return block, nil
+}
+
+// switchBackend replaces the chain view of the active backend with the one of
+// a new backend. Blocks that the client was notified about but aren't part of
+// the chain of the new backend are disconnected, before the chain view of the
+// new backend takes over from the best common block.
+func (c *ChainView) switchBackend(view chainview.FilteredChainView,
+	chainConn ChainConn) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	prev := c.active
+	c.active = view
+	c.chainConn = chainConn
+
+	if prev == nil || !c.started || c.stopped {
+		return nil
+	}
+
+	c.stopForwarder()
+	if err := prev.Stop(); err != nil {
+		log.Warnf("Unable to stop chain view of previous backend: %v",
+			err)
+	}
+
+	if err := view.Start(); err != nil {
+		return err
+	}
+
+	stale := c.reconcile(chainConn)
+	if len(c.history) == 0 {
+		log.Warnf("No common block with new backend found, resuming " +
+			"at its best block")
+
+		if err := c.addBestBlock(chainConn); err != nil {
+			return err
+		}
+	}
+
+	c.filterMtx.Lock()
+	ops := make([]channeldb.EdgePoint, 0, len(c.filter))
+	for _, op := range c.filter {
+		ops = append(ops, op)
+	}
+	c.filterMtx.Unlock()
+
+	// The filter is loaded from the best common block on, which rescans
+	// the blocks of the new backend that the client hasn't been notified
+	// about yet. Blocks without any matches are filled in by the
+	// forwarder.
+	err := view.UpdateFilter(ops, c.history[len(c.history)-1].height)
+
+	c.startForwarder(view, chainConn, stale)
+
+	return err
+}
+
+// reconcile removes the blocks from the history that aren't part of the chain
+// of the given backend, and returns them as disconnected blocks, starting
+// with the most recent one.
+func (c *ChainView) reconcile(chainConn ChainConn) []*chainview.FilteredBlock {
+	var stale []*chainview.FilteredBlock
+	for len(c.history) > 0 {
+		tip := c.history[len(c.history)-1]
+
+		hash, err := chainConn.GetBlockHash(int64(tip.height))
+		if err == nil && *hash == tip.hash {
+			break
+		}
+		if err != nil {
+			log.Debugf("Unable to get block hash at height %d: %v",
+				tip.height, err)
+		}
+
+		log.Infof("Block %v at height %d isn't part of the chain of "+
+			"the new backend", tip.hash, tip.height)
+
+		stale = append(stale, &chainview.FilteredBlock{
+			Hash:   tip.hash,
+			Height: tip.height,
+		})
+		c.history = c.history[:len(c.history)-1]
+	}
+
+	return stale
+}
+
+// addBestBlock adds the best block of the given backend to the history.
+func (c *ChainView) addBestBlock(chainConn ChainConn) error {
+	hash, height, err := chainConn.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	c.addHistory(*hash, uint32(height))
+
+	return nil
+}
+
+// addHistory adds a block that was sent to the client to the history.
+func (c *ChainView) addHistory(hash chainhash.Hash, height uint32) {
+	c.history = append(c.history, blockStamp{hash: hash, height: height})
+	if len(c.history) > maxBlockHistory {
+		c.history = c.history[len(c.history)-maxBlockHistory:]
+	}
+}
+
+// removeSpent removes the outpoints that are spent in the given block from
+// the filter.
+func (c *ChainView) removeSpent(block *chainview.FilteredBlock) {
+	c.filterMtx.Lock()
+	defer c.filterMtx.Unlock()
+
+	for _, tx := range block.Transactions {
+		for _, txIn := range tx.TxIn {
+			delete(c.filter, txIn.PreviousOutPoint)
+		}
+	}
+}
+
+// startForwarder starts sending the blocks of the given chain view to the
+// client, after the given stale blocks.
+func (c *ChainView) startForwarder(view chainview.FilteredChainView,
+	chainConn ChainConn, stale []*chainview.FilteredBlock) {
+
+	quit := make(chan struct{})
+	c.fwdQuit = quit
+
+	c.fwdWg.Add(1)
+	go func() {
+		defer c.fwdWg.Done()
+
+		c.forward(view, chainConn, stale, quit)
+	}()
+}
+
+// stopForwarder stops sending the blocks of the current chain view, if it's
+// running.
+func (c *ChainView) stopForwarder() {
+	if c.fwdQuit == nil {
+		return
+	}
+
+	close(c.fwdQuit)
+	c.fwdWg.Wait()
+	c.fwdQuit = nil
+}
+
+// forward sends the blocks of the given chain view to the client.
+func (c *ChainView) forward(view chainview.FilteredChainView,
+	chainConn ChainConn, stale []*chainview.FilteredBlock,
+	quit <-chan struct{}) {
+
+	for _, block := range stale {
+		if !send(c.staleBlocks, block, quit) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case block, ok := <-view.FilteredBlocks():
+			if !ok {
+				return
+			}
+
+			if !c.connectBlock(view, chainConn, block, quit) {
+				return
+			}
+
+		case block, ok := <-view.DisconnectedBlocks():
+			if !ok {
+				return
+			}
+
+			if !send(c.staleBlocks, block, quit) {
+				return
+			}
+
+			for len(c.history) > 0 &&
+				c.history[len(c.history)-1].height >=
+					block.Height {
+
+				c.history = c.history[:len(c.history)-1]
+			}
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// connectBlock sends a connected block to the client. If blocks are missing
+// between the last block sent and the given one, they're filtered and sent
+// first. It returns false if the forwarder is exiting.
+func (c *ChainView) connectBlock(view chainview.FilteredChainView,
+	chainConn ChainConn, block *chainview.FilteredBlock,
+	quit <-chan struct{}) bool {
+
+	// Blocks below the tip are the result of a rescan of a filter update,
+	// which we pass on as is.
+	var tipHeight uint32
+	if len(c.history) > 0 {
+		tipHeight = c.history[len(c.history)-1].height
+	}
+	if block.Height <= tipHeight {
+		c.removeSpent(block)
+
+		return send(c.newBlocks, block, quit)
+	}
+
+	for height := tipHeight + 1; height < block.Height; height++ {
+		// Without any history, there's nothing to catch up with.
+		if tipHeight == 0 {
+			break
+		}
+
+		missed, err := c.filterBlockAtHeight(view, chainConn, height)
+		if err != nil {
+			log.Errorf("Unable to filter missed block at height "+
+				"%d: %v", height, err)
+
+			break
+		}
+
+		c.removeSpent(missed)
+		if !send(c.newBlocks, missed, quit) {
+			return false
+		}
+		c.addHistory(missed.Hash, missed.Height)
+	}
+
+	c.removeSpent(block)
+	if !send(c.newBlocks, block, quit) {
+		return false
+	}
+	c.addHistory(block.Hash, block.Height)
+
+	return true
+}
+
+// filterBlockAtHeight filters the block of the main chain at the given height.
+func (c *ChainView) filterBlockAtHeight(view chainview.FilteredChainView,
+	chainConn ChainConn, height uint32) (*chainview.FilteredBlock, error) {
+
+	hash, err := chainConn.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, err
+	}
+
+	return view.FilterBlock(hash)
+}
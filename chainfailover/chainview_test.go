@@ -0,0 +1,110 @@
+package chainfailover
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChainViewSwitchBackend asserts that blocks that aren't part of the chain
+// of a new backend are disconnected on a switch, and that blocks missed
+// during the switch are filled in.
+func TestChainViewSwitchBackend(t *testing.T) {
+	t.Parallel()
+
+	// The chain of the second backend forks off at height 4 and is
+	// already two blocks further.
+	chainA := newMockChain(3, 0, 0)
+	chainB := newMockChain(6, 1, 4)
+	viewA := newMockChainView(chainA)
+	viewB := newMockChainView(chainB)
+
+	c := newChainView()
+	require.NoError(t, c.switchBackend(viewA, chainA))
+	require.NoError(t, c.Start())
+	require.True(t, viewA.started)
+
+	ops := []channeldb.EdgePoint{{
+		OutPoint: wire.OutPoint{Index: 1},
+	}}
+	require.NoError(t, c.UpdateFilter(ops, 3))
+
+	// The client is notified about block 4 of the first backend.
+	block := newMockChain(4, 0, 0).block(4)
+	viewA.newBlocks <- block
+	require.Equal(t, block, receive(t, c.FilteredBlocks()))
+
+	require.NoError(t, c.switchBackend(viewB, chainB))
+	require.True(t, viewA.stopped)
+	require.True(t, viewB.started)
+
+	// Block 4 isn't part of the chain of the new backend, so it's
+	// disconnected, and the filter is loaded from the common block on.
+	stale := receive(t, c.DisconnectedBlocks())
+	require.Equal(t, block.Hash, stale.Hash)
+	require.Equal(t, block.Height, stale.Height)
+
+	require.Equal(t, []uint32{3}, viewB.filterUpdates)
+	require.Equal(t, ops, viewB.filter)
+
+	// Once the new backend notifies about block 6, blocks 4 and 5 of its
+	// chain are sent first.
+	viewB.newBlocks <- chainB.block(6)
+	for height := uint32(4); height <= 6; height++ {
+		block := receive(t, c.FilteredBlocks())
+		require.Equal(t, chainB.block(height), block)
+	}
+
+	// Blocks of a rescan below the tip are passed on as is.
+	viewB.newBlocks <- chainB.block(5)
+	require.Equal(t, chainB.block(5), receive(t, c.FilteredBlocks()))
+
+	// A disconnected block is passed on and removed from the history.
+	viewB.staleBlocks <- chainB.block(6)
+	require.Equal(t, chainB.block(6), receive(t, c.DisconnectedBlocks()))
+
+	require.NoError(t, c.Stop())
+	require.True(t, viewB.stopped)
+
+	require.Equal(t, uint32(5), c.history[len(c.history)-1].height)
+}
+
+// TestChainViewRemoveSpent asserts that spent outpoints are no longer loaded
+// into the chain view of a new backend.
+func TestChainViewRemoveSpent(t *testing.T) {
+	t.Parallel()
+
+	chainA := newMockChain(3, 0, 0)
+	chainB := newMockChain(3, 0, 0)
+	viewA := newMockChainView(chainA)
+	viewB := newMockChainView(chainB)
+
+	c := newChainView()
+	require.NoError(t, c.switchBackend(viewA, chainA))
+	require.NoError(t, c.Start())
+
+	spent := channeldb.EdgePoint{OutPoint: wire.OutPoint{Index: 1}}
+	unspent := channeldb.EdgePoint{OutPoint: wire.OutPoint{Index: 2}}
+	require.NoError(t, c.UpdateFilter(
+		[]channeldb.EdgePoint{spent, unspent}, 3,
+	))
+
+	block := newMockChain(4, 0, 0).block(4)
+	block.Transactions = []*wire.MsgTx{{
+		TxIn: []*wire.TxIn{{PreviousOutPoint: spent.OutPoint}},
+	}}
+	viewA.newBlocks <- block
+	require.Equal(t, block, receive(t, c.FilteredBlocks()))
+
+	require.NoError(t, c.switchBackend(viewB, chainB))
+	require.Equal(t, []channeldb.EdgePoint{unspent}, viewB.filter)
+
+	// The new backend hasn't seen block 4 yet, so it's disconnected.
+	stale := receive(t, c.DisconnectedBlocks())
+	require.Equal(t, uint32(4), stale.Height)
+	require.Equal(t, []uint32{3}, viewB.filterUpdates)
+
+	require.NoError(t, c.Stop())
+}
@@ -0,0 +1,106 @@
+package chainfailover
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// FeeEstimator is a chainfee.Estimator that uses the fee estimator of the
+// active backend.
+type FeeEstimator struct {
+	mtx sync.RWMutex
+
+	// active is the fee estimator of the active backend.
+	active chainfee.Estimator
+
+	started bool
+	stopped bool
+}
+
+// A compile-time check to ensure FeeEstimator implements the Estimator
+// interface.
+var _ chainfee.Estimator = (*FeeEstimator)(nil)
+
+// Start starts the fee estimator of the active backend.
+//
+// NOTE: This method is part of the Estimator interface.
+func (f *FeeEstimator) Start() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.started {
+		return nil
+	}
+
+	if err := f.active.Start(); err != nil {
+		return err
+	}
+	f.started = true
+
+	return nil
+}
+
+// Stop stops the fee estimator of the active backend.
+//
+// NOTE: This method is part of the Estimator interface.
+func (f *FeeEstimator) Stop() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.stopped {
+		return nil
+	}
+	f.stopped = true
+
+	return f.active.Stop()
+}
+
+// EstimateFeePerKW returns the fee estimate of the active backend for the
+// given confirmation target.
+//
+// NOTE: This method is part of the Estimator interface.
+func (f *FeeEstimator) EstimateFeePerKW(
+	numBlocks uint32) (chainfee.SatPerKWeight, error) {
+
+	return f.activeEstimator().EstimateFeePerKW(numBlocks)
+}
+
+// RelayFeePerKW returns the minimum fee rate required for transactions to be
+// relayed by the active backend.
+//
+// NOTE: This method is part of the Estimator interface.
+func (f *FeeEstimator) RelayFeePerKW() chainfee.SatPerKWeight {
+	return f.activeEstimator().RelayFeePerKW()
+}
+
+// activeEstimator returns the fee estimator of the active backend. It's not
+// locked while in use, so queries of an unresponsive backend don't hold up a
+// switch.
+func (f *FeeEstimator) activeEstimator() chainfee.Estimator {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	return f.active
+}
+
+// switchBackend replaces the fee estimator of the active backend with the one
+// of a new backend.
+func (f *FeeEstimator) switchBackend(estimator chainfee.Estimator) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	prev := f.active
+	f.active = estimator
+
+	if prev == nil || !f.started || f.stopped {
+		return nil
+	}
+
+	if err := prev.Stop(); err != nil {
+		log.Warnf("Unable to stop fee estimator of previous backend: "+
+			"%v", err)
+	}
+
+	return estimator.Start()
+}
@@ -0,0 +1,400 @@
+package chainfailover
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/routing/chainview"
+)
+
+// mockConfReg is a confirmation registration at a mockNotifier.
+type mockConfReg struct {
+	heightHint uint32
+	event      *chainntnfs.ConfirmationEvent
+	canceled   bool
+}
+
+// mockSpendReg is a spend registration at a mockNotifier.
+type mockSpendReg struct {
+	event    *chainntnfs.SpendEvent
+	canceled bool
+}
+
+// mockEpochReg is a block epoch registration at a mockNotifier.
+type mockEpochReg struct {
+	bestBlock *chainntnfs.BlockEpoch
+	epochs    chan *chainntnfs.BlockEpoch
+	canceled  bool
+}
+
+// mockNotifier is a chainntnfs.ChainNotifier that records its registrations,
+// such that tests can dispatch notifications to them.
+type mockNotifier struct {
+	mtx sync.Mutex
+
+	started bool
+	stopped bool
+
+	confs  []*mockConfReg
+	spends []*mockSpendReg
+	epochs []*mockEpochReg
+}
+
+var _ chainntnfs.ChainNotifier = (*mockNotifier)(nil)
+
+func (m *mockNotifier) RegisterConfirmationsNtfn(*chainhash.Hash, []byte,
+	uint32, uint32, ...chainntnfs.NotifierOption) (
+	*chainntnfs.ConfirmationEvent, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	reg := &mockConfReg{}
+	reg.event = chainntnfs.NewConfirmationEvent(1, func() {
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+
+		reg.canceled = true
+	})
+	m.confs = append(m.confs, reg)
+
+	return reg.event, nil
+}
+
+func (m *mockNotifier) RegisterSpendNtfn(*wire.OutPoint, []byte,
+	uint32) (*chainntnfs.SpendEvent, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	reg := &mockSpendReg{}
+	reg.event = chainntnfs.NewSpendEvent(func() {
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+
+		reg.canceled = true
+	})
+	m.spends = append(m.spends, reg)
+
+	return reg.event, nil
+}
+
+func (m *mockNotifier) RegisterBlockEpochNtfn(
+	bestBlock *chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	reg := &mockEpochReg{
+		bestBlock: bestBlock,
+		epochs:    make(chan *chainntnfs.BlockEpoch, 20),
+	}
+	m.epochs = append(m.epochs, reg)
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: reg.epochs,
+		Cancel: func() {
+			m.mtx.Lock()
+			defer m.mtx.Unlock()
+
+			if !reg.canceled {
+				reg.canceled = true
+				close(reg.epochs)
+			}
+		},
+	}, nil
+}
+
+func (m *mockNotifier) Start() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.started = true
+
+	return nil
+}
+
+func (m *mockNotifier) Started() bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.started
+}
+
+func (m *mockNotifier) Stop() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.stopped = true
+
+	return nil
+}
+
+func (m *mockNotifier) confReg(i int) *mockConfReg {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.confs[i]
+}
+
+func (m *mockNotifier) spendReg(i int) *mockSpendReg {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.spends[i]
+}
+
+func (m *mockNotifier) epochReg(i int) *mockEpochReg {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.epochs[i]
+}
+
+// mockChain is a chain of blocks that is identified by its block hashes.
+type mockChain struct {
+	mtx sync.Mutex
+
+	hashes []chainhash.Hash
+}
+
+var _ ChainConn = (*mockChain)(nil)
+
+// newMockChain creates a chain with blocks up to the given height. The hashes
+// of the blocks are derived from the given fork byte, starting at forkHeight,
+// such that chains with the same fork byte share their blocks.
+func newMockChain(height uint32, fork byte, forkHeight uint32) *mockChain {
+	c := &mockChain{}
+	for h := uint32(0); h <= height; h++ {
+		c.hashes = append(c.hashes, mockHash(h, 0))
+		if h >= forkHeight {
+			c.hashes[h] = mockHash(h, fork)
+		}
+	}
+
+	return c
+}
+
+// mockHash returns the hash of the block at the given height of a fork.
+func mockHash(height uint32, fork byte) chainhash.Hash {
+	var hash chainhash.Hash
+	hash[0] = fork
+	hash[1] = byte(height)
+	hash[2] = byte(height >> 8)
+
+	return hash
+}
+
+func (c *mockChain) GetBestBlock() (*chainhash.Hash, int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	height := len(c.hashes) - 1
+	hash := c.hashes[height]
+
+	return &hash, int32(height), nil
+}
+
+func (c *mockChain) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height < 0 || height >= int64(len(c.hashes)) {
+		return nil, errors.New("block not found")
+	}
+	hash := c.hashes[height]
+
+	return &hash, nil
+}
+
+// height returns the height of the block with the given hash.
+func (c *mockChain) height(hash *chainhash.Hash) (uint32, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for height, h := range c.hashes {
+		if h == *hash {
+			return uint32(height), true
+		}
+	}
+
+	return 0, false
+}
+
+// block returns the filtered block at the given height, without any
+// transactions.
+func (c *mockChain) block(height uint32) *chainview.FilteredBlock {
+	hash, _ := c.GetBlockHash(int64(height))
+
+	return &chainview.FilteredBlock{
+		Hash:   *hash,
+		Height: height,
+	}
+}
+
+// mockChainView is a chainview.FilteredChainView on top of a mockChain whose
+// blocks are sent by the tests.
+type mockChainView struct {
+	mtx sync.Mutex
+
+	chain *mockChain
+
+	started bool
+	stopped bool
+
+	filterUpdates []uint32
+	filter        []channeldb.EdgePoint
+
+	newBlocks   chan *chainview.FilteredBlock
+	staleBlocks chan *chainview.FilteredBlock
+}
+
+var _ chainview.FilteredChainView = (*mockChainView)(nil)
+
+func newMockChainView(chain *mockChain) *mockChainView {
+	return &mockChainView{
+		chain:       chain,
+		newBlocks:   make(chan *chainview.FilteredBlock, 10),
+		staleBlocks: make(chan *chainview.FilteredBlock, 10),
+	}
+}
+
+func (m *mockChainView) Start() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.started = true
+
+	return nil
+}
+
+func (m *mockChainView) Stop() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.stopped = true
+
+	return nil
+}
+
+func (m *mockChainView) UpdateFilter(ops []channeldb.EdgePoint,
+	updateHeight uint32) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.filterUpdates = append(m.filterUpdates, updateHeight)
+	m.filter = append(m.filter, ops...)
+
+	return nil
+}
+
+func (m *mockChainView) FilterBlock(
+	hash *chainhash.Hash) (*chainview.FilteredBlock, error) {
+
+	height, ok := m.chain.height(hash)
+	if !ok {
+		return nil, errors.New("block not found")
+	}
+
+	return &chainview.FilteredBlock{
+		Hash:   *hash,
+		Height: height,
+	}, nil
+}
+
+func (m *mockChainView) FilteredBlocks() <-chan *chainview.FilteredBlock {
+	return m.newBlocks
+}
+
+func (m *mockChainView) DisconnectedBlocks() <-chan *chainview.FilteredBlock {
+	return m.staleBlocks
+}
+
+// mockChainSource is a chain.Interface that sends a ClientConnected
+// notification once it's started, like the chain clients of lnd's backends.
+type mockChainSource struct {
+	chain.Interface
+
+	mtx sync.Mutex
+
+	started bool
+	stopped bool
+
+	notifications chan interface{}
+}
+
+func newMockChainSource() *mockChainSource {
+	return &mockChainSource{
+		notifications: make(chan interface{}, 1),
+	}
+}
+
+func (m *mockChainSource) Start() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.started = true
+	m.notifications <- chain.ClientConnected{}
+
+	return nil
+}
+
+func (m *mockChainSource) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.stopped = true
+}
+
+func (m *mockChainSource) WaitForShutdown() {}
+
+func (m *mockChainSource) Notifications() <-chan interface{} {
+	return m.notifications
+}
+
+// mockFeeEstimator is a chainfee.Estimator that returns a static fee rate.
+type mockFeeEstimator struct {
+	mtx sync.Mutex
+
+	feeRate chainfee.SatPerKWeight
+
+	started bool
+	stopped bool
+}
+
+var _ chainfee.Estimator = (*mockFeeEstimator)(nil)
+
+func (m *mockFeeEstimator) Start() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.started = true
+
+	return nil
+}
+
+func (m *mockFeeEstimator) Stop() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.stopped = true
+
+	return nil
+}
+
+func (m *mockFeeEstimator) EstimateFeePerKW(
+	uint32) (chainfee.SatPerKWeight, error) {
+
+	return m.feeRate, nil
+}
+
+func (m *mockFeeEstimator) RelayFeePerKW() chainfee.SatPerKWeight {
+	return chainfee.FeePerKwFloor
+}
@@ -0,0 +1,644 @@
+package chainfailover
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+// ErrNotifierShuttingDown is returned when a registration is attempted after
+// the notifier has been stopped.
+var ErrNotifierShuttingDown = errors.New("chain failover notifier " +
+	"shutting down")
+
+// send delivers the value on the given channel unless the quit channel is
+// closed first. It returns false in that case.
+func send[T any](c chan<- T, value T, quit <-chan struct{}) bool {
+	select {
+	case c <- value:
+		return true
+
+	case <-quit:
+		return false
+	}
+}
+
+// attachment links a registration of a client of the Notifier to the same
+// registration at the notifier of the active backend.
+type attachment struct {
+	// cancel cancels the registration at the notifier of the backend.
+	cancel func()
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newAttachment starts the given forwarding function for a registration at
+// the notifier of a backend.
+func newAttachment(cancel func(),
+	forward func(quit <-chan struct{})) *attachment {
+
+	a := &attachment{
+		cancel: cancel,
+		quit:   make(chan struct{}),
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		forward(a.quit)
+	}()
+
+	return a
+}
+
+// detach stops forwarding the notifications of the backend and cancels the
+// registration at its notifier.
+func (a *attachment) detach() {
+	close(a.quit)
+	a.wg.Wait()
+
+	a.cancel()
+}
+
+// registration is a registration of a client of the Notifier that is kept
+// across backend switches.
+type registration interface {
+	// attach registers at the given notifier and starts forwarding its
+	// notifications to the client.
+	attach(notifier chainntnfs.ChainNotifier) error
+
+	// detach stops forwarding the notifications of the notifier the
+	// registration is attached to, if any.
+	detach()
+
+	// close closes the channels of the client. The teardown flag
+	// indicates whether the notifier is shutting down, as opposed to the
+	// client canceling the registration.
+	close(teardown bool)
+}
+
+// confRegistration is a confirmation registration of a client.
+type confRegistration struct {
+	txid       *chainhash.Hash
+	pkScript   []byte
+	numConfs   uint32
+	heightHint uint32
+	opts       []chainntnfs.NotifierOption
+
+	// event is the confirmation event of the client.
+	event *chainntnfs.ConfirmationEvent
+
+	att *attachment
+
+	// The following fields track what was delivered to the client, such
+	// that the notifications of a new backend aren't sent twice. They're
+	// only accessed by the forwarding goroutine, or while it isn't
+	// running.
+	confirmed    *chainntnfs.TxConfirmation
+	numConfsLeft uint32
+	updateSent   bool
+	done         bool
+}
+
+// A compile-time check to ensure confRegistration implements registration.
+var _ registration = (*confRegistration)(nil)
+
+// attach registers at the given notifier and starts forwarding its
+// notifications to the client.
+func (r *confRegistration) attach(notifier chainntnfs.ChainNotifier) error {
+	// A confirmation that has been fully dispatched won't send any
+	// further notifications.
+	if r.att != nil || r.done {
+		return nil
+	}
+
+	inner, err := notifier.RegisterConfirmationsNtfn(
+		r.txid, r.pkScript, r.numConfs, r.heightHint, r.opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.att = newAttachment(inner.Cancel, func(quit <-chan struct{}) {
+		r.forward(inner, quit)
+	})
+
+	return nil
+}
+
+// forward sends the notifications of the backend to the client, skipping the
+// ones the client has already received from a previous backend.
+func (r *confRegistration) forward(inner *chainntnfs.ConfirmationEvent,
+	quit <-chan struct{}) {
+
+	for {
+		select {
+		case conf, ok := <-inner.Confirmed:
+			if !ok {
+				return
+			}
+
+			if r.confirmed != nil &&
+				*r.confirmed.BlockHash == *conf.BlockHash {
+
+				continue
+			}
+
+			if !send(r.event.Confirmed, conf, quit) {
+				return
+			}
+			r.confirmed = conf
+
+		case numConfsLeft, ok := <-inner.Updates:
+			if !ok {
+				return
+			}
+
+			if r.updateSent && numConfsLeft >= r.numConfsLeft {
+				continue
+			}
+
+			if !send(r.event.Updates, numConfsLeft, quit) {
+				return
+			}
+			r.numConfsLeft = numConfsLeft
+			r.updateSent = true
+
+		case depth, ok := <-inner.NegativeConf:
+			if !ok {
+				return
+			}
+
+			if !send(r.event.NegativeConf, depth, quit) {
+				return
+			}
+			r.confirmed = nil
+			r.updateSent = false
+
+		case _, ok := <-inner.Done:
+			if !ok {
+				return
+			}
+
+			if send(r.event.Done, struct{}{}, quit) {
+				r.done = true
+			}
+
+			return
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// detach stops forwarding the notifications of the notifier the registration
+// is attached to, if any.
+func (r *confRegistration) detach() {
+	if r.att == nil {
+		return
+	}
+
+	r.att.detach()
+	r.att = nil
+}
+
+// close closes the channels of the client.
+func (r *confRegistration) close(teardown bool) {
+	close(r.event.Confirmed)
+	close(r.event.Updates)
+	close(r.event.NegativeConf)
+
+	if teardown {
+		close(r.event.Done)
+	}
+}
+
+// spendRegistration is a spend registration of a client.
+type spendRegistration struct {
+	outpoint   *wire.OutPoint
+	pkScript   []byte
+	heightHint uint32
+
+	// event is the spend event of the client.
+	event *chainntnfs.SpendEvent
+
+	att *attachment
+
+	// The following fields track what was delivered to the client, such
+	// that the notifications of a new backend aren't sent twice. They're
+	// only accessed by the forwarding goroutine, or while it isn't
+	// running.
+	spend *chainntnfs.SpendDetail
+	done  bool
+}
+
+// A compile-time check to ensure spendRegistration implements registration.
+var _ registration = (*spendRegistration)(nil)
+
+// attach registers at the given notifier and starts forwarding its
+// notifications to the client.
+func (r *spendRegistration) attach(notifier chainntnfs.ChainNotifier) error {
+	if r.att != nil || r.done {
+		return nil
+	}
+
+	inner, err := notifier.RegisterSpendNtfn(
+		r.outpoint, r.pkScript, r.heightHint,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.att = newAttachment(inner.Cancel, func(quit <-chan struct{}) {
+		r.forward(inner, quit)
+	})
+
+	return nil
+}
+
+// forward sends the notifications of the backend to the client, skipping the
+// ones the client has already received from a previous backend.
+func (r *spendRegistration) forward(inner *chainntnfs.SpendEvent,
+	quit <-chan struct{}) {
+
+	for {
+		select {
+		case spend, ok := <-inner.Spend:
+			if !ok {
+				return
+			}
+
+			spenderTxHash := *spend.SpenderTxHash
+			if r.spend != nil &&
+				*r.spend.SpenderTxHash == spenderTxHash &&
+				r.spend.SpendingHeight == spend.SpendingHeight {
+
+				continue
+			}
+
+			if !send(r.event.Spend, spend, quit) {
+				return
+			}
+			r.spend = spend
+
+		case _, ok := <-inner.Reorg:
+			if !ok {
+				return
+			}
+
+			if !send(r.event.Reorg, struct{}{}, quit) {
+				return
+			}
+			r.spend = nil
+
+		case _, ok := <-inner.Done:
+			if !ok {
+				return
+			}
+
+			if send(r.event.Done, struct{}{}, quit) {
+				r.done = true
+			}
+
+			return
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// detach stops forwarding the notifications of the notifier the registration
+// is attached to, if any.
+func (r *spendRegistration) detach() {
+	if r.att == nil {
+		return
+	}
+
+	r.att.detach()
+	r.att = nil
+}
+
+// close closes the channels of the client.
+func (r *spendRegistration) close(bool) {
+	close(r.event.Spend)
+	close(r.event.Reorg)
+	close(r.event.Done)
+}
+
+// epochRegistration is a block epoch registration of a client.
+type epochRegistration struct {
+	// epochs is the channel the client receives the block epochs on.
+	epochs chan *chainntnfs.BlockEpoch
+
+	att *attachment
+
+	// bestBlock is the last block the client was notified about, or the
+	// best block it provided on registration if it hasn't been notified
+	// yet. It's only accessed by the forwarding goroutine, or while it
+	// isn't running.
+	bestBlock *chainntnfs.BlockEpoch
+}
+
+// A compile-time check to ensure epochRegistration implements registration.
+var _ registration = (*epochRegistration)(nil)
+
+// attach registers at the given notifier and starts forwarding its block
+// epochs to the client. The notifier is given the last block the client was
+// notified about, such that it catches the client up with the blocks it
+// missed during the switch.
+func (r *epochRegistration) attach(notifier chainntnfs.ChainNotifier) error {
+	if r.att != nil {
+		return nil
+	}
+
+	inner, err := notifier.RegisterBlockEpochNtfn(r.bestBlock)
+	if err != nil {
+		return err
+	}
+
+	r.att = newAttachment(inner.Cancel, func(quit <-chan struct{}) {
+		r.forward(inner, quit)
+	})
+
+	return nil
+}
+
+// forward sends the block epochs of the backend to the client. The first epoch
+// of a new backend may be the best block of the client, which is skipped.
+func (r *epochRegistration) forward(inner *chainntnfs.BlockEpochEvent,
+	quit <-chan struct{}) {
+
+	for {
+		select {
+		case epoch, ok := <-inner.Epochs:
+			if !ok {
+				return
+			}
+
+			if r.bestBlock != nil && r.bestBlock.Hash != nil &&
+				*r.bestBlock.Hash == *epoch.Hash {
+
+				continue
+			}
+
+			if !send(r.epochs, epoch, quit) {
+				return
+			}
+			r.bestBlock = epoch
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// detach stops forwarding the notifications of the notifier the registration
+// is attached to, if any.
+func (r *epochRegistration) detach() {
+	if r.att == nil {
+		return
+	}
+
+	r.att.detach()
+	r.att = nil
+}
+
+// close closes the channel of the client.
+func (r *epochRegistration) close(bool) {
+	close(r.epochs)
+}
+
+// Notifier is a chainntnfs.ChainNotifier that dispatches the notifications of
+// the notifier of the active backend. On a backend switch, all registrations
+// are moved to the notifier of the new backend, such that clients don't
+// notice the switch.
+type Notifier struct {
+	mtx sync.Mutex
+
+	// active is the notifier of the active backend.
+	active chainntnfs.ChainNotifier
+
+	started bool
+	stopped bool
+
+	nextID        uint64
+	registrations map[uint64]registration
+}
+
+// A compile-time check to ensure Notifier implements the ChainNotifier
+// interface.
+var _ chainntnfs.ChainNotifier = (*Notifier)(nil)
+
+// newNotifier creates a new notifier that isn't backed by any backend yet.
+func newNotifier() *Notifier {
+	return &Notifier{
+		registrations: make(map[uint64]registration),
+	}
+}
+
+// Start starts the notifier of the active backend.
+func (n *Notifier) Start() error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.started {
+		return nil
+	}
+
+	if err := n.active.Start(); err != nil {
+		return err
+	}
+	n.started = true
+
+	return nil
+}
+
+// Started returns true if the notifier has been started.
+func (n *Notifier) Started() bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	return n.started && n.active.Started()
+}
+
+// Stop cancels all registrations and stops the notifier of the active
+// backend.
+func (n *Notifier) Stop() error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.stopped {
+		return nil
+	}
+	n.stopped = true
+
+	for id, reg := range n.registrations {
+		reg.detach()
+		reg.close(true)
+		delete(n.registrations, id)
+	}
+
+	return n.active.Stop()
+}
+
+// switchBackend moves all registrations to the notifier of a new backend and
+// stops the one of the previous backend. Registrations that can't be moved
+// are retried on the next switch.
+func (n *Notifier) switchBackend(notifier chainntnfs.ChainNotifier) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	prev := n.active
+	n.active = notifier
+
+	if prev == nil || !n.started || n.stopped {
+		return nil
+	}
+
+	if err := notifier.Start(); err != nil {
+		return err
+	}
+
+	for _, reg := range n.registrations {
+		reg.detach()
+	}
+
+	if err := prev.Stop(); err != nil {
+		log.Warnf("Unable to stop notifier of previous backend: %v",
+			err)
+	}
+
+	var firstErr error
+	for _, reg := range n.registrations {
+		err := reg.attach(notifier)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// register adds the registration and attaches it to the notifier of the
+// active backend. The returned function cancels the registration.
+func (n *Notifier) register(reg registration) (func(), error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.stopped {
+		return nil, ErrNotifierShuttingDown
+	}
+
+	if err := reg.attach(n.active); err != nil {
+		return nil, err
+	}
+
+	id := n.nextID
+	n.nextID++
+	n.registrations[id] = reg
+
+	cancel := func() {
+		n.mtx.Lock()
+		defer n.mtx.Unlock()
+
+		if _, ok := n.registrations[id]; !ok {
+			return
+		}
+		delete(n.registrations, id)
+
+		reg.detach()
+		reg.close(false)
+	}
+
+	return cancel, nil
+}
+
+// RegisterConfirmationsNtfn registers an intent to be notified once the
+// target txid/output script has reached numConfs confirmations on-chain.
+//
+// NOTE: This is part of the chainntnfs.ChainNotifier interface.
+func (n *Notifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	pkScript []byte, numConfs, heightHint uint32,
+	opts ...chainntnfs.NotifierOption) (*chainntnfs.ConfirmationEvent,
+	error) {
+
+	reg := &confRegistration{
+		txid:       txid,
+		pkScript:   pkScript,
+		numConfs:   numConfs,
+		heightHint: heightHint,
+		opts:       opts,
+	}
+
+	// The cancel function is only known once the registration is added,
+	// so the event refers to it indirectly.
+	var cancel func()
+	reg.event = chainntnfs.NewConfirmationEvent(numConfs, func() {
+		cancel()
+	})
+
+	cancel, err := n.register(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return reg.event, nil
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the target
+// outpoint/output script has been spent by a transaction on-chain.
+//
+// NOTE: This is part of the chainntnfs.ChainNotifier interface.
+func (n *Notifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	pkScript []byte, heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	reg := &spendRegistration{
+		outpoint:   outpoint,
+		pkScript:   pkScript,
+		heightHint: heightHint,
+	}
+
+	var cancel func()
+	reg.event = chainntnfs.NewSpendEvent(func() {
+		cancel()
+	})
+
+	cancel, err := n.register(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return reg.event, nil
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive notifications of each new block connected to the main
+// chain. Block epochs are neither skipped nor sent twice across backend
+// switches.
+//
+// NOTE: This is part of the chainntnfs.ChainNotifier interface.
+func (n *Notifier) RegisterBlockEpochNtfn(
+	bestBlock *chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error) {
+
+	reg := &epochRegistration{
+		epochs:    make(chan *chainntnfs.BlockEpoch, 20),
+		bestBlock: bestBlock,
+	}
+
+	cancel, err := n.register(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: reg.epochs,
+		Cancel: cancel,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package chainfailover
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+// receive returns the next value of the given channel, failing the test if
+// none is received in time.
+func receive[T any](t *testing.T, c <-chan T) T {
+	t.Helper()
+
+	select {
+	case value, ok := <-c:
+		require.True(t, ok, "channel closed")
+
+		return value
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no value received")
+
+		var zero T
+
+		return zero
+	}
+}
+
+// assertNoValue asserts that no value is received on the given channel.
+func assertNoValue[T any](t *testing.T, c <-chan T) {
+	t.Helper()
+
+	select {
+	case value := <-c:
+		t.Fatalf("unexpected value received: %v", value)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// assertClosed asserts that the given channel is closed.
+func assertClosed[T any](t *testing.T, c <-chan T) {
+	t.Helper()
+
+	select {
+	case _, ok := <-c:
+		require.False(t, ok, "channel not closed")
+
+	case <-time.After(testTimeout):
+		t.Fatalf("channel not closed")
+	}
+}
+
+// testEpoch returns the block epoch of the given height.
+func testEpoch(height int32) *chainntnfs.BlockEpoch {
+	hash := mockHash(uint32(height), 0)
+
+	return &chainntnfs.BlockEpoch{
+		Hash:   &hash,
+		Height: height,
+	}
+}
+
+// TestNotifierSwitchBackend asserts that registrations are moved to the
+// notifier of a new backend, and that notifications the client has already
+// received aren't sent again.
+func TestNotifierSwitchBackend(t *testing.T) {
+	t.Parallel()
+
+	notifierA := &mockNotifier{}
+	notifierB := &mockNotifier{}
+
+	n := newNotifier()
+	require.NoError(t, n.switchBackend(notifierA))
+	require.NoError(t, n.Start())
+	require.True(t, notifierA.Started())
+
+	epochs, err := n.RegisterBlockEpochNtfn(nil)
+	require.NoError(t, err)
+
+	txid := chainhash.Hash{1}
+	confs, err := n.RegisterConfirmationsNtfn(&txid, nil, 1, 100)
+	require.NoError(t, err)
+
+	spends, err := n.RegisterSpendNtfn(&wire.OutPoint{}, nil, 100)
+	require.NoError(t, err)
+
+	// The client is notified about the first block and the confirmation
+	// by the first backend.
+	notifierA.epochReg(0).epochs <- testEpoch(1)
+	require.Equal(t, int32(1), receive(t, epochs.Epochs).Height)
+
+	confBlock := chainhash.Hash{2}
+	conf := &chainntnfs.TxConfirmation{
+		BlockHash:   &confBlock,
+		BlockHeight: 1,
+	}
+	notifierA.confReg(0).event.Confirmed <- conf
+	require.Equal(t, conf, receive(t, confs.Confirmed))
+
+	// After the switch, the registrations are canceled at the previous
+	// notifier, which is stopped, and registered at the new one.
+	require.NoError(t, n.switchBackend(notifierB))
+	require.True(t, notifierA.stopped)
+	require.True(t, notifierA.epochReg(0).canceled)
+	require.True(t, notifierA.confReg(0).canceled)
+	require.True(t, notifierA.spendReg(0).canceled)
+	require.True(t, notifierB.Started())
+
+	// The block epochs of the new notifier start after the last block
+	// the client was notified about.
+	epochReg := notifierB.epochReg(0)
+	require.Equal(t, testEpoch(1), epochReg.bestBlock)
+
+	// The new notifier may notify about the best block of the client,
+	// which is skipped, before it continues with the next block.
+	epochReg.epochs <- testEpoch(1)
+	epochReg.epochs <- testEpoch(2)
+	require.Equal(t, int32(2), receive(t, epochs.Epochs).Height)
+
+	// The confirmation the client has already received isn't sent again.
+	notifierB.confReg(0).event.Confirmed <- conf
+	assertNoValue(t, confs.Confirmed)
+
+	// After a reorg, the confirmation is sent again.
+	notifierB.confReg(0).event.NegativeConf <- 1
+	require.Equal(t, int32(1), receive(t, confs.NegativeConf))
+
+	notifierB.confReg(0).event.Confirmed <- conf
+	require.Equal(t, conf, receive(t, confs.Confirmed))
+
+	spenderHash := chainhash.Hash{3}
+	spend := &chainntnfs.SpendDetail{
+		SpenderTxHash:  &spenderHash,
+		SpendingHeight: 2,
+	}
+	notifierB.spendReg(0).event.Spend <- spend
+	require.Equal(t, spend, receive(t, spends.Spend))
+
+	// Canceling a registration cancels it at the active notifier.
+	epochs.Cancel()
+	assertClosed(t, epochs.Epochs)
+	require.True(t, epochReg.canceled)
+
+	// Stopping the notifier closes the channels of the remaining
+	// registrations.
+	require.NoError(t, n.Stop())
+	require.True(t, notifierB.stopped)
+	assertClosed(t, confs.Done)
+	assertClosed(t, spends.Done)
+
+	_, err = n.RegisterBlockEpochNtfn(nil)
+	require.ErrorIs(t, err, ErrNotifierShuttingDown)
+}
+
+// TestNotifierSwitchBackendDone asserts that a confirmation registration that
+// has been fully dispatched isn't moved to a new backend.
+func TestNotifierSwitchBackendDone(t *testing.T) {
+	t.Parallel()
+
+	notifierA := &mockNotifier{}
+	notifierB := &mockNotifier{}
+
+	n := newNotifier()
+	require.NoError(t, n.switchBackend(notifierA))
+	require.NoError(t, n.Start())
+
+	txid := chainhash.Hash{1}
+	confs, err := n.RegisterConfirmationsNtfn(&txid, nil, 1, 100)
+	require.NoError(t, err)
+
+	notifierA.confReg(0).event.Done <- struct{}{}
+	receive(t, confs.Done)
+
+	require.NoError(t, n.switchBackend(notifierB))
+	require.Empty(t, notifierB.confs)
+
+	require.NoError(t, n.Stop())
+}
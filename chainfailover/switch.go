@@ -0,0 +1,381 @@
+// Package chainfailover backs lnd by a prioritized list of chain backends. The
+// active backend is health checked, and if it becomes unhealthy, the chain
+// notifier, chain view, fee estimator and wallet chain source are moved over
+// to the next healthy backend without their clients noticing.
+package chainfailover
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/healthcheck"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/routing/chainview"
+)
+
+// ErrNoHealthyBackend is returned if none of the configured backends is
+// healthy.
+var ErrNoHealthyBackend = errors.New("no healthy chain backend")
+
+// ChainConn is the part of the RPC interface of a backend that is used to
+// find the blocks of its chain across switches.
+type ChainConn interface {
+	// GetBestBlock returns the hash and height of the best block.
+	GetBestBlock() (*chainhash.Hash, int32, error)
+
+	// GetBlockHash returns the hash of the block at the given height.
+	GetBlockHash(height int64) (*chainhash.Hash, error)
+}
+
+// Backend houses the chain interfaces that are backed by a single chain
+// backend.
+type Backend struct {
+	// ChainNotifier is the chain notifier of the backend.
+	ChainNotifier chainntnfs.ChainNotifier
+
+	// ChainView is the filtered chain view of the backend.
+	ChainView chainview.FilteredChainView
+
+	// ChainSource is the chain client the wallet uses with the backend.
+	ChainSource chain.Interface
+
+	// FeeEstimator is the fee estimator of the backend.
+	FeeEstimator chainfee.Estimator
+
+	// ChainConn is a connection to the backend that is used to look up
+	// blocks of its chain.
+	ChainConn ChainConn
+
+	// HealthCheck checks that the backend is healthy.
+	HealthCheck func() error
+
+	// Cleanup is an optional function that releases the resources of the
+	// backend once it's no longer active.
+	Cleanup func()
+}
+
+// cleanup releases the resources of the backend.
+func (b *Backend) cleanup() {
+	if b.Cleanup != nil {
+		b.Cleanup()
+	}
+}
+
+// BackendConfig describes one of the prioritized chain backends.
+type BackendConfig struct {
+	// Name identifies the backend in logs.
+	Name string
+
+	// New creates the chain interfaces of the backend. It's called each
+	// time the backend becomes active, such that no connection to the
+	// backend is held while it's not in use.
+	New func() (*Backend, error)
+}
+
+// Config houses the configuration of a Switch.
+type Config struct {
+	// Backends are the chain backends in the order of their priority.
+	Backends []*BackendConfig
+
+	// HealthCheckInterval is the interval in which the active backend is
+	// health checked.
+	HealthCheckInterval time.Duration
+
+	// HealthCheckTimeout is the time after which a health check is
+	// considered failed.
+	HealthCheckTimeout time.Duration
+
+	// HealthCheckBackoff is the time to back off between failed health
+	// checks.
+	HealthCheckBackoff time.Duration
+
+	// HealthCheckAttempts is the number of failed health checks after
+	// which we fail over to the next healthy backend.
+	HealthCheckAttempts int
+}
+
+// Switch maintains the active chain backend. If the health checks of the
+// active backend fail, the Switch fails over to the healthy backend with the
+// highest priority.
+type Switch struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// mtx serializes backend switches.
+	mtx sync.Mutex
+
+	// active is the active backend, and activeIdx its index within the
+	// configured backends.
+	active    atomic.Pointer[Backend]
+	activeIdx int
+
+	// monitor runs the health check of the active backend.
+	monitor *healthcheck.Monitor
+
+	notifier     *Notifier
+	chainView    *ChainView
+	chainSource  *ChainSource
+	feeEstimator *FeeEstimator
+
+	// failoverReqs receives a request once the active backend is found to
+	// be unhealthy.
+	failoverReqs chan struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new Switch for the given backends.
+func New(cfg *Config) *Switch {
+	return &Switch{
+		cfg:          cfg,
+		notifier:     newNotifier(),
+		chainView:    newChainView(),
+		chainSource:  newChainSource(),
+		feeEstimator: &FeeEstimator{},
+		failoverReqs: make(chan struct{}, 1),
+		quit:         make(chan struct{}),
+	}
+}
+
+// ChainNotifier returns the chain notifier that is backed by the active
+// backend.
+func (s *Switch) ChainNotifier() *Notifier {
+	return s.notifier
+}
+
+// ChainView returns the chain view that is backed by the active backend.
+func (s *Switch) ChainView() *ChainView {
+	return s.chainView
+}
+
+// ChainSource returns the wallet chain source that is backed by the active
+// backend.
+func (s *Switch) ChainSource() *ChainSource {
+	return s.chainSource
+}
+
+// FeeEstimator returns the fee estimator that is backed by the active
+// backend.
+func (s *Switch) FeeEstimator() *FeeEstimator {
+	return s.feeEstimator
+}
+
+// ChainConn returns the connection to the active backend.
+func (s *Switch) ChainConn() ChainConn {
+	return s.active.Load().ChainConn
+}
+
+// HealthCheck checks the health of the active backend.
+func (s *Switch) HealthCheck() error {
+	return s.active.Load().HealthCheck()
+}
+
+// Start activates the healthy backend with the highest priority and starts
+// monitoring it. The chain interfaces of the Switch can only be used once it's
+// started.
+func (s *Switch) Start() error {
+	var err error
+	s.started.Do(func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+
+		if !s.activateBackend(-1) {
+			err = ErrNoHealthyBackend
+			return
+		}
+
+		s.wg.Add(1)
+		go s.failoverHandler()
+	})
+
+	return err
+}
+
+// Stop stops monitoring the active backend and releases its resources. The
+// chain interfaces of the Switch are expected to be stopped by their users
+// beforehand.
+func (s *Switch) Stop() {
+	s.stopped.Do(func() {
+		close(s.quit)
+		s.wg.Wait()
+
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+
+		s.stopMonitor()
+		if backend := s.active.Load(); backend != nil {
+			backend.cleanup()
+		}
+	})
+}
+
+// failoverHandler switches to another backend whenever the active one is found
+// to be unhealthy.
+func (s *Switch) failoverHandler() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.failoverReqs:
+			s.failover()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// requestFailover requests a switch to another backend.
+func (s *Switch) requestFailover() {
+	select {
+	case s.failoverReqs <- struct{}{}:
+	default:
+	}
+}
+
+// failover switches from the unhealthy active backend to the healthy backend
+// with the highest priority. If there is none, the active backend is kept
+// and monitored again.
+func (s *Switch) failover() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.stopMonitor()
+
+	prev := s.active.Load()
+	prevName := s.cfg.Backends[s.activeIdx].Name
+	if !s.activateBackend(s.activeIdx) {
+		log.Errorf("No healthy chain backend to fail over to, keeping "+
+			"%v", prevName)
+
+		s.startMonitor()
+
+		return
+	}
+
+	log.Infof("Failed over from chain backend %v to %v", prevName,
+		s.cfg.Backends[s.activeIdx].Name)
+
+	prev.cleanup()
+}
+
+// activateBackend makes the healthy backend with the highest priority the
+// active one, skipping the given index. All chain interfaces are switched over
+// to it and it's monitored from then on. It returns false if no backend could
+// be activated.
+func (s *Switch) activateBackend(skipIdx int) bool {
+	for idx, cfg := range s.cfg.Backends {
+		if idx == skipIdx {
+			continue
+		}
+
+		backend, err := newBackend(cfg)
+		if err != nil {
+			log.Warnf("Chain backend %v unavailable: %v", cfg.Name,
+				err)
+
+			continue
+		}
+
+		log.Infof("Activating chain backend %v", cfg.Name)
+
+		s.active.Store(backend)
+		s.activeIdx = idx
+
+		// Once the interfaces are switched, there's no going back, as
+		// the interfaces of the previous backend have been stopped. So
+		// if any of them fails to start, we'll move on with another
+		// backend right away.
+		if err := s.switchInterfaces(backend); err != nil {
+			log.Errorf("Unable to switch to chain backend %v: %v",
+				cfg.Name, err)
+
+			s.requestFailover()
+		}
+
+		s.startMonitor()
+
+		return true
+	}
+
+	return false
+}
+
+// newBackend creates the backend of the given config and ensures it's
+// healthy.
+func newBackend(cfg *BackendConfig) (*Backend, error) {
+	backend, err := cfg.New()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := backend.HealthCheck(); err != nil {
+		backend.cleanup()
+
+		return nil, fmt.Errorf("health check failed: %w", err)
+	}
+
+	return backend, nil
+}
+
+// switchInterfaces switches all chain interfaces over to the given backend.
+// Every interface is switched, even if one of them fails to.
+func (s *Switch) switchInterfaces(backend *Backend) error {
+	errs := []error{
+		s.feeEstimator.switchBackend(backend.FeeEstimator),
+		s.chainSource.switchBackend(backend.ChainSource),
+		s.notifier.switchBackend(backend.ChainNotifier),
+		s.chainView.switchBackend(
+			backend.ChainView, backend.ChainConn,
+		),
+	}
+
+	return errors.Join(errs...)
+}
+
+// startMonitor starts to health check the active backend. Once the checks
+// fail, we request a failover instead of a shutdown.
+func (s *Switch) startMonitor() {
+	backend := s.active.Load()
+	name := s.cfg.Backends[s.activeIdx].Name
+
+	check := healthcheck.NewObservation(
+		fmt.Sprintf("chain backend %v", name), backend.HealthCheck,
+		s.cfg.HealthCheckInterval, s.cfg.HealthCheckTimeout,
+		s.cfg.HealthCheckBackoff, s.cfg.HealthCheckAttempts,
+	)
+
+	s.monitor = healthcheck.NewMonitor(&healthcheck.Config{
+		Checks: []*healthcheck.Observation{check},
+		Shutdown: func(format string, params ...interface{}) {
+			log.Warnf(format, params...)
+
+			s.requestFailover()
+		},
+	})
+
+	if err := s.monitor.Start(); err != nil {
+		log.Errorf("Unable to start chain backend monitor: %v", err)
+	}
+}
+
+// stopMonitor stops the health checks of the active backend.
+func (s *Switch) stopMonitor() {
+	if s.monitor == nil {
+		return
+	}
+
+	if err := s.monitor.Stop(); err != nil {
+		log.Errorf("Unable to stop chain backend monitor: %v", err)
+	}
+	s.monitor = nil
+}
@@ -0,0 +1,221 @@
+package chainfailover
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// testInstance holds the chain interfaces of a single creation of a
+// testBackend.
+type testInstance struct {
+	notifier     *mockNotifier
+	chainView    *mockChainView
+	chainSource  *mockChainSource
+	feeEstimator *mockFeeEstimator
+
+	cleanedUp atomic.Bool
+}
+
+// testBackend is a backend of a Switch under test.
+type testBackend struct {
+	feeRate chainfee.SatPerKWeight
+
+	// healthy determines the result of the health check of the backend.
+	healthy atomic.Bool
+
+	mtx       sync.Mutex
+	instances []*testInstance
+}
+
+// newTestBackend creates a healthy backend whose fee estimator returns the
+// given fee rate.
+func newTestBackend(feeRate chainfee.SatPerKWeight) *testBackend {
+	b := &testBackend{feeRate: feeRate}
+	b.healthy.Store(true)
+
+	return b
+}
+
+// numInstances returns the number of times the backend has been created.
+func (b *testBackend) numInstances() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return len(b.instances)
+}
+
+// instance returns the chain interfaces of the backend created last.
+func (b *testBackend) instance() *testInstance {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.instances[len(b.instances)-1]
+}
+
+// config returns the config of the backend.
+func (b *testBackend) config(name string) *BackendConfig {
+	return &BackendConfig{
+		Name: name,
+		New: func() (*Backend, error) {
+			chain := newMockChain(3, 0, 0)
+			inst := &testInstance{
+				notifier:    &mockNotifier{},
+				chainView:   newMockChainView(chain),
+				chainSource: newMockChainSource(),
+				feeEstimator: &mockFeeEstimator{
+					feeRate: b.feeRate,
+				},
+			}
+
+			b.mtx.Lock()
+			b.instances = append(b.instances, inst)
+			b.mtx.Unlock()
+
+			return &Backend{
+				ChainNotifier: inst.notifier,
+				ChainView:     inst.chainView,
+				ChainSource:   inst.chainSource,
+				FeeEstimator:  inst.feeEstimator,
+				ChainConn:     chain,
+				HealthCheck: func() error {
+					if !b.healthy.Load() {
+						return errors.New("unhealthy")
+					}
+
+					return nil
+				},
+				Cleanup: func() {
+					inst.cleanedUp.Store(true)
+				},
+			}, nil
+		},
+	}
+}
+
+// TestSwitchFailover asserts that the Switch fails over to the healthy
+// backend with the highest priority once the active one becomes unhealthy.
+func TestSwitchFailover(t *testing.T) {
+	t.Parallel()
+
+	unavailable := &BackendConfig{
+		Name: "unavailable",
+		New: func() (*Backend, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	primary := newTestBackend(1000)
+	secondary := newTestBackend(2000)
+
+	s := New(&Config{
+		Backends: []*BackendConfig{
+			unavailable, primary.config("primary"),
+			secondary.config("secondary"),
+		},
+		HealthCheckInterval: 10 * time.Millisecond,
+		HealthCheckTimeout:  time.Second,
+		HealthCheckBackoff:  10 * time.Millisecond,
+		HealthCheckAttempts: 2,
+	})
+	require.NoError(t, s.Start())
+
+	// The unavailable backend is skipped, and only the primary one is
+	// created.
+	require.Equal(t, 1, primary.numInstances())
+	require.Zero(t, secondary.numInstances())
+	first := primary.instance()
+
+	require.NoError(t, s.FeeEstimator().Start())
+	require.NoError(t, s.ChainSource().Start())
+	require.NoError(t, s.ChainNotifier().Start())
+	require.NoError(t, s.ChainView().Start())
+	require.NoError(t, s.HealthCheck())
+
+	feeRate, err := s.FeeEstimator().EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, feeRate)
+
+	ntfn := receive(t, s.ChainSource().Notifications())
+	require.IsType(t, chain.ClientConnected{}, ntfn)
+
+	epochs, err := s.ChainNotifier().RegisterBlockEpochNtfn(nil)
+	require.NoError(t, err)
+
+	// Once the primary backend is unhealthy, the Switch fails over to the
+	// secondary one and cleans up the primary one.
+	primary.healthy.Store(false)
+	require.Eventually(t, first.cleanedUp.Load, testTimeout,
+		10*time.Millisecond)
+
+	require.Equal(t, 1, secondary.numInstances())
+	second := secondary.instance()
+	require.NoError(t, s.HealthCheck())
+
+	feeRate, err = s.FeeEstimator().EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 2000, feeRate)
+	require.True(t, first.feeEstimator.stopped)
+	require.True(t, second.feeEstimator.started)
+
+	// The wallet is notified that the chain client of the secondary
+	// backend is connected, upon which it resyncs.
+	require.True(t, first.chainSource.stopped)
+	require.Equal(t, second.chainSource, s.ChainSource().Active())
+	ntfn = receive(t, s.ChainSource().Notifications())
+	require.IsType(t, chain.ClientConnected{}, ntfn)
+
+	// The block epoch registration is moved to the secondary backend.
+	require.True(t, first.notifier.epochReg(0).canceled)
+	require.True(t, second.chainView.started)
+	second.notifier.epochReg(0).epochs <- testEpoch(4)
+	require.Equal(t, int32(4), receive(t, epochs.Epochs).Height)
+
+	// If the secondary backend becomes unhealthy as well while the
+	// primary one is still down, the secondary one is kept.
+	secondary.healthy.Store(false)
+	require.Eventually(t, func() bool {
+		return primary.numInstances() > 1
+	}, testTimeout, 10*time.Millisecond)
+	require.False(t, second.cleanedUp.Load())
+
+	// Once the primary backend recovers, the next failover switches back
+	// to it.
+	primary.healthy.Store(true)
+	require.Eventually(t, second.cleanedUp.Load, testTimeout,
+		10*time.Millisecond)
+
+	feeRate, err = s.FeeEstimator().EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, feeRate)
+
+	require.NoError(t, s.ChainView().Stop())
+	require.NoError(t, s.ChainNotifier().Stop())
+	s.ChainSource().Stop()
+	require.NoError(t, s.FeeEstimator().Stop())
+
+	s.Stop()
+	require.True(t, primary.instance().cleanedUp.Load())
+}
+
+// TestSwitchNoHealthyBackend asserts that the Switch can't be started without
+// a healthy backend.
+func TestSwitchNoHealthyBackend(t *testing.T) {
+	t.Parallel()
+
+	backend := newTestBackend(1000)
+	backend.healthy.Store(false)
+
+	s := New(&Config{
+		Backends: []*BackendConfig{backend.config("unhealthy")},
+	})
+	require.ErrorIs(t, s.Start(), ErrNoHealthyBackend)
+
+	// The unhealthy backend is released right away.
+	require.True(t, backend.instance().cleanedUp.Load())
+}
@@ -0,0 +1,60 @@
+package esploranotify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/esplora"
+)
+
+// createNewNotifier creates a new instance of the ChainNotifier interface
+// implemented by EsploraNotifier.
+func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
+	if len(args) != 4 {
+		return nil, fmt.Errorf("incorrect number of arguments to "+
+			".New(...), expected 4, instead passed %v", len(args))
+	}
+
+	conn, ok := args[0].(*esplora.Conn)
+	if !ok {
+		return nil, errors.New("first argument to esploranotify.New " +
+			"is incorrect, expected a *esplora.Conn")
+	}
+
+	spendHintCache, ok := args[1].(chainntnfs.SpendHintCache)
+	if !ok {
+		return nil, errors.New("second argument to esploranotify.New " +
+			"is incorrect, expected a chainntnfs.SpendHintCache")
+	}
+
+	confirmHintCache, ok := args[2].(chainntnfs.ConfirmHintCache)
+	if !ok {
+		return nil, errors.New("third argument to esploranotify.New " +
+			"is incorrect, expected a chainntnfs.ConfirmHintCache")
+	}
+
+	blockCache, ok := args[3].(*blockcache.BlockCache)
+	if !ok {
+		return nil, errors.New("fourth argument to esploranotify.New " +
+			"is incorrect, expected a *blockcache.BlockCache")
+	}
+
+	return New(conn, spendHintCache, confirmHintCache, blockCache), nil
+}
+
+// init registers a driver for the EsploraNotifier concrete implementation of
+// the chainntnfs.ChainNotifier interface.
+func init() {
+	// Register the driver.
+	notifier := &chainntnfs.NotifierDriver{
+		NotifierType: notifierType,
+		New:          createNewNotifier,
+	}
+
+	if err := chainntnfs.RegisterNotifier(notifier); err != nil {
+		panic(fmt.Sprintf("failed to register notifier driver '%s': %v",
+			notifierType, err))
+	}
+}
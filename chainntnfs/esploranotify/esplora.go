@@ -0,0 +1,866 @@
+package esploranotify
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/esplora"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+const (
+	// notifierType uniquely identifies this concrete implementation of the
+	// ChainNotifier interface.
+	notifierType = "esplora"
+)
+
+// EsploraNotifier implements the ChainNotifier interface using an Esplora
+// server. New blocks are fetched in full and matched against the registered
+// requests. Historical dispatches are resolved using the transaction, output
+// and script indexes of the server, such that no blocks need to be scanned
+// apart from the ones that include the transaction we're looking for.
+type EsploraNotifier struct {
+	epochClientCounter uint64 // To be used atomically.
+
+	start   sync.Once
+	active  int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	conn *esplora.Conn
+	sub  *esplora.BlockSubscription
+
+	notificationCancels  chan interface{}
+	notificationRegistry chan interface{}
+
+	txNotifier *chainntnfs.TxNotifier
+
+	blockEpochClients map[uint64]*blockEpochRegistration
+
+	bestBlock chainntnfs.BlockEpoch
+
+	// blockCache is a LRU block cache.
+	blockCache *blockcache.BlockCache
+
+	// spendHintCache is a cache used to query and update the latest height
+	// hints for an outpoint. Each height hint represents the earliest
+	// height at which the outpoint could have been spent within the chain.
+	spendHintCache chainntnfs.SpendHintCache
+
+	// confirmHintCache is a cache used to query the latest height hints for
+	// a transaction. Each height hint represents the earliest height at
+	// which the transaction could have confirmed within the chain.
+	confirmHintCache chainntnfs.ConfirmHintCache
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Ensure EsploraNotifier implements the ChainNotifier interface at compile
+// time.
+var _ chainntnfs.ChainNotifier = (*EsploraNotifier)(nil)
+
+// New returns a new EsploraNotifier instance. This function assumes the
+// passed connection is already started.
+func New(conn *esplora.Conn, spendHintCache chainntnfs.SpendHintCache,
+	confirmHintCache chainntnfs.ConfirmHintCache,
+	blockCache *blockcache.BlockCache) *EsploraNotifier {
+
+	return &EsploraNotifier{
+		conn: conn,
+
+		notificationCancels:  make(chan interface{}),
+		notificationRegistry: make(chan interface{}),
+
+		blockEpochClients: make(map[uint64]*blockEpochRegistration),
+
+		spendHintCache:   spendHintCache,
+		confirmHintCache: confirmHintCache,
+
+		blockCache: blockCache,
+
+		quit: make(chan struct{}),
+	}
+}
+
+// Start subscribes to the blocks of the Esplora connection and launches all
+// related helper goroutines.
+func (e *EsploraNotifier) Start() error {
+	var startErr error
+	e.start.Do(func() {
+		startErr = e.startNotifier()
+	})
+
+	return startErr
+}
+
+// Stop shuts down the EsploraNotifier.
+func (e *EsploraNotifier) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&e.stopped, 1) != 1 {
+		return nil
+	}
+
+	chainntnfs.Log.Info("esplora notifier shutting down...")
+	defer chainntnfs.Log.Debug("esplora notifier shutdown complete")
+
+	close(e.quit)
+	e.wg.Wait()
+
+	if e.sub != nil {
+		e.sub.Cancel()
+	}
+
+	// Notify all pending clients of our shutdown by closing the related
+	// notification channels.
+	for _, epochClient := range e.blockEpochClients {
+		close(epochClient.cancelChan)
+		epochClient.wg.Wait()
+
+		close(epochClient.epochChan)
+	}
+
+	// The txNotifier is only initialized in the start method therefore we
+	// need to make sure we don't access a nil pointer here.
+	if e.txNotifier != nil {
+		e.txNotifier.TearDown()
+	}
+
+	return nil
+}
+
+// Started returns true if this instance has been started, and false otherwise.
+func (e *EsploraNotifier) Started() bool {
+	return atomic.LoadInt32(&e.active) != 0
+}
+
+func (e *EsploraNotifier) startNotifier() error {
+	sub, err := e.conn.SubscribeBlocks()
+	if err != nil {
+		return err
+	}
+	e.sub = sub
+
+	e.txNotifier = chainntnfs.NewTxNotifier(
+		uint32(sub.BestBlock.Height), chainntnfs.ReorgSafetyLimit,
+		e.confirmHintCache, e.spendHintCache,
+	)
+
+	e.bestBlock = chainntnfs.BlockEpoch{
+		Height:      sub.BestBlock.Height,
+		Hash:        &sub.BestBlock.Hash,
+		BlockHeader: sub.BestBlock.Header,
+	}
+
+	e.wg.Add(1)
+	go e.notificationDispatcher()
+
+	// Set the active flag now that we've completed the full
+	// startup.
+	atomic.StoreInt32(&e.active, 1)
+
+	return nil
+}
+
+// notificationDispatcher is the primary goroutine which handles client
+// notification registrations, as well as notification dispatches.
+func (e *EsploraNotifier) notificationDispatcher() {
+	defer e.wg.Done()
+
+	for {
+		select {
+		case cancelMsg := <-e.notificationCancels:
+			switch msg := cancelMsg.(type) {
+			case *epochCancel:
+				chainntnfs.Log.Infof("Cancelling epoch "+
+					"notification, epoch_id=%v",
+					msg.epochID)
+
+				// First, we'll lookup the original
+				// registration in order to stop the active
+				// queue goroutine.
+				reg := e.blockEpochClients[msg.epochID]
+				reg.epochQueue.Stop()
+
+				// Next, close the cancel channel for this
+				// specific client, and wait for the client to
+				// exit.
+				close(reg.cancelChan)
+				reg.wg.Wait()
+
+				// Once the client has exited, we can then
+				// safely close the channel used to send epoch
+				// notifications, in order to notify any
+				// listeners that the intent has been
+				// canceled.
+				close(reg.epochChan)
+				delete(e.blockEpochClients, msg.epochID)
+			}
+
+		case registerMsg := <-e.notificationRegistry:
+			switch msg := registerMsg.(type) {
+			case *chainntnfs.HistoricalConfDispatch:
+				// The lookup may require several requests to
+				// the server, so we'll do it in a goroutine
+				// to prevent blocking the dispatcher.
+				e.wg.Add(1)
+				go e.dispatchHistoricalConf(msg)
+
+			case *chainntnfs.HistoricalSpendDispatch:
+				e.wg.Add(1)
+				go e.dispatchHistoricalSpend(msg)
+
+			case *blockEpochRegistration:
+				chainntnfs.Log.Infof("New block epoch " +
+					"subscription")
+
+				e.blockEpochClients[msg.epochID] = msg
+
+				// If the client did not provide their best
+				// known block, then we'll immediately dispatch
+				// a notification for the current tip.
+				if msg.bestBlock == nil {
+					e.notifyBlockEpochClient(
+						msg, e.bestBlock.Height,
+						e.bestBlock.Hash,
+						e.bestBlock.BlockHeader,
+					)
+
+					msg.errorChan <- nil
+					continue
+				}
+
+				// Otherwise, we'll attempt to deliver the
+				// backlog of notifications from their best
+				// known block.
+				missedBlocks, err :=
+					chainntnfs.GetClientMissedBlocks(
+						e.conn, msg.bestBlock,
+						e.bestBlock.Height, true,
+					)
+				if err != nil {
+					msg.errorChan <- err
+					continue
+				}
+
+				for _, block := range missedBlocks {
+					e.notifyBlockEpochClient(
+						msg, block.Height, block.Hash,
+						block.BlockHeader,
+					)
+				}
+
+				msg.errorChan <- nil
+			}
+
+		case event := <-e.sub.Events:
+			if event.Disconnected {
+				e.handleBlockDisconnected(event.Block)
+				continue
+			}
+
+			e.handleNewBlock(event.Block)
+
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+// handleNewBlock connects a new block of the server, catching up on any
+// blocks that we missed before.
+func (e *EsploraNotifier) handleNewBlock(block *esplora.Block) {
+	if block.Header.PrevBlock != *e.bestBlock.Hash {
+		// Handle the case where the notifier missed some blocks from
+		// its chain backend.
+		chainntnfs.Log.Infof("Missed blocks, attempting to catch up")
+
+		newBest, missedBlocks, err := chainntnfs.HandleMissedBlocks(
+			e.conn, e.txNotifier, e.bestBlock, block.Height, true,
+		)
+		if err != nil {
+			// Set the bestBlock here in case a catch up partially
+			// completed.
+			e.bestBlock = newBest
+			chainntnfs.Log.Error(err)
+			return
+		}
+
+		for _, missedBlock := range missedBlocks {
+			err := e.handleBlockConnected(missedBlock)
+			if err != nil {
+				chainntnfs.Log.Error(err)
+				return
+			}
+		}
+	}
+
+	newBlock := chainntnfs.BlockEpoch{
+		Height:      block.Height,
+		Hash:        &block.Hash,
+		BlockHeader: block.Header,
+	}
+	if err := e.handleBlockConnected(newBlock); err != nil {
+		chainntnfs.Log.Error(err)
+	}
+}
+
+// handleBlockDisconnected rewinds the chain to the block before the
+// disconnected one.
+func (e *EsploraNotifier) handleBlockDisconnected(block *esplora.Block) {
+	if block.Height != e.bestBlock.Height {
+		chainntnfs.Log.Infof("Missed disconnected blocks, attempting " +
+			"to catch up")
+	}
+
+	newBestBlock, err := chainntnfs.RewindChain(
+		e.conn, e.txNotifier, e.bestBlock, block.Height-1,
+	)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to rewind chain from height %d "+
+			"to height %d: %v", e.bestBlock.Height, block.Height-1,
+			err)
+	}
+
+	// Set the bestBlock here in case a chain rewind partially completed.
+	e.bestBlock = newBestBlock
+}
+
+// dispatchHistoricalConf looks up whether the transaction of the dispatch is
+// already confirmed and updates the txNotifier with the result.
+//
+// NOTE: This MUST be run as a goroutine.
+func (e *EsploraNotifier) dispatchHistoricalConf(
+	msg *chainntnfs.HistoricalConfDispatch) {
+
+	defer e.wg.Done()
+
+	confDetails, err := e.historicalConfDetails(
+		msg.ConfRequest, msg.StartHeight, msg.EndHeight,
+	)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to determine the conf details "+
+			"of %v within range %d-%d: %v", msg.ConfRequest,
+			msg.StartHeight, msg.EndHeight, err)
+
+		return
+	}
+
+	// If the historical dispatch finished without error, we will invoke
+	// UpdateConfDetails even if none were found. This allows the notifier
+	// to begin safely updating the height hint cache at tip, since any
+	// pending rescans have now completed.
+	err = e.txNotifier.UpdateConfDetails(msg.ConfRequest, confDetails)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to update conf details of %v: %v",
+			msg.ConfRequest, err)
+	}
+}
+
+// dispatchHistoricalSpend looks up whether the outpoint or script of the
+// dispatch is already spent and updates the txNotifier with the result.
+//
+// NOTE: This MUST be run as a goroutine.
+func (e *EsploraNotifier) dispatchHistoricalSpend(
+	msg *chainntnfs.HistoricalSpendDispatch) {
+
+	defer e.wg.Done()
+
+	spendDetails, err := e.historicalSpendDetails(
+		msg.SpendRequest, msg.StartHeight, msg.EndHeight,
+	)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to determine the spend details "+
+			"of %v within range %d-%d: %v", msg.SpendRequest,
+			msg.StartHeight, msg.EndHeight, err)
+
+		return
+	}
+
+	chainntnfs.Log.Infof("Historical spend dispatch finished for request "+
+		"%v (start=%v end=%v) with details: %v", msg.SpendRequest,
+		msg.StartHeight, msg.EndHeight, spendDetails)
+
+	// If the historical dispatch finished without error, we will invoke
+	// UpdateSpendDetails even if none were found. This allows the notifier
+	// to begin safely updating the height hint cache at tip, since any
+	// pending rescans have now completed.
+	err = e.txNotifier.UpdateSpendDetails(msg.SpendRequest, spendDetails)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to update spend details of "+
+			"%v: %v", msg.SpendRequest, err)
+	}
+}
+
+// scriptTxHeights returns the heights of the blocks within the given range
+// that include transactions paying to or spending from the given script, in
+// ascending order.
+func (e *EsploraNotifier) scriptTxHeights(pkScript []byte, startHeight,
+	endHeight uint32) (map[uint32]*chainhash.Hash, []uint32, error) {
+
+	txs, err := e.conn.GetScriptTxs(pkScript, int32(startHeight))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks := make(map[uint32]*chainhash.Hash)
+	for _, tx := range txs {
+		height := uint32(tx.Status.BlockHeight)
+		if height > endHeight {
+			continue
+		}
+
+		hash, err := chainhash.NewHashFromStr(tx.Status.BlockHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		blocks[height] = hash
+	}
+
+	heights := make([]uint32, 0, len(blocks))
+	for height := range blocks {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return blocks, heights, nil
+}
+
+// historicalConfDetails looks up whether a confirmation request (txid/output
+// script) has already been included in a block in the active chain and, if so,
+// returns details about said block.
+func (e *EsploraNotifier) historicalConfDetails(
+	confRequest chainntnfs.ConfRequest, startHeight, endHeight uint32) (
+	*chainntnfs.TxConfirmation, error) {
+
+	// If a txid was not provided, then we should dispatch upon seeing the
+	// script on-chain, so we'll look up the blocks that include
+	// transactions of the script.
+	if confRequest.TxID == chainntnfs.ZeroHash {
+		blocks, heights, err := e.scriptTxHeights(
+			confRequest.PkScript.Script(), startHeight, endHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, height := range heights {
+			txConf, err := e.confDetailsFromBlock(
+				confRequest, blocks[height], height,
+			)
+			if err != nil || txConf != nil {
+				return txConf, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	// Otherwise, we'll look up the status of the transaction directly.
+	status, err := e.conn.GetTxStatus(&confRequest.TxID)
+	switch {
+	case errors.Is(err, esplora.ErrNotFound):
+		return nil, nil
+
+	case err != nil:
+		return nil, err
+
+	// The transaction was found within the mempool.
+	case !status.Confirmed:
+		return nil, nil
+	}
+
+	blockHash, err := chainhash.NewHashFromStr(status.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.confDetailsFromBlock(
+		confRequest, blockHash, uint32(status.BlockHeight),
+	)
+}
+
+// confDetailsFromBlock returns the confirmation details of the first
+// transaction of the given block that matches the request, or nil if there
+// is none.
+func (e *EsploraNotifier) confDetailsFromBlock(
+	confRequest chainntnfs.ConfRequest, blockHash *chainhash.Hash,
+	height uint32) (*chainntnfs.TxConfirmation, error) {
+
+	block, err := e.GetBlock(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get block with hash %v: %w",
+			blockHash, err)
+	}
+
+	for txIndex, tx := range block.Transactions {
+		if !confRequest.MatchesTx(tx) {
+			continue
+		}
+
+		return &chainntnfs.TxConfirmation{
+			Tx:          tx.Copy(),
+			BlockHash:   blockHash,
+			BlockHeight: height,
+			TxIndex:     uint32(txIndex),
+			Block:       block,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// historicalSpendDetails looks up whether the outpoint/output script of the
+// request was spent within the given height range. If it was, the spend
+// details are returned.
+func (e *EsploraNotifier) historicalSpendDetails(
+	spendRequest chainntnfs.SpendRequest, startHeight, endHeight uint32) (
+	*chainntnfs.SpendDetail, error) {
+
+	// The spend of an outpoint can be looked up directly.
+	if spendRequest.OutPoint != chainntnfs.ZeroOutPoint {
+		outSpend, err := e.conn.GetTxOutSpend(&spendRequest.OutPoint)
+		switch {
+		case errors.Is(err, esplora.ErrNotFound):
+			return nil, nil
+
+		case err != nil:
+			return nil, err
+
+		case !outSpend.Spent || outSpend.Status == nil ||
+			!outSpend.Status.Confirmed:
+
+			return nil, nil
+		}
+
+		height := uint32(outSpend.Status.BlockHeight)
+		if height < startHeight || height > endHeight {
+			return nil, nil
+		}
+
+		spenderHash, err := chainhash.NewHashFromStr(outSpend.TxID)
+		if err != nil {
+			return nil, err
+		}
+		spendingTx, err := e.conn.GetRawTransaction(spenderHash)
+		if err != nil {
+			return nil, err
+		}
+
+		return spendDetails(spendRequest, spendingTx, height)
+	}
+
+	// For scripts, we'll look at the transactions in the blocks that
+	// include transactions of the script, as they include the spend.
+	blocks, heights, err := e.scriptTxHeights(
+		spendRequest.PkScript.Script(), startHeight, endHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, height := range heights {
+		block, err := e.GetBlock(blocks[height])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range block.Transactions {
+			details, err := spendDetails(spendRequest, tx, height)
+			if err != nil || details != nil {
+				return details, err
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// spendDetails returns the spend details of the given transaction if it
+// spends the request, and nil otherwise.
+func spendDetails(spendRequest chainntnfs.SpendRequest, tx *wire.MsgTx,
+	height uint32) (*chainntnfs.SpendDetail, error) {
+
+	matches, inputIdx, err := spendRequest.MatchesTx(tx)
+	if err != nil || !matches {
+		return nil, err
+	}
+
+	txCopy := tx.Copy()
+	txHash := txCopy.TxHash()
+
+	return &chainntnfs.SpendDetail{
+		SpentOutPoint:     &txCopy.TxIn[inputIdx].PreviousOutPoint,
+		SpenderTxHash:     &txHash,
+		SpendingTx:        txCopy,
+		SpenderInputIndex: inputIdx,
+		SpendingHeight:    int32(height),
+	}, nil
+}
+
+// handleBlockConnected applies a chain update for a new block. Any watched
+// transactions included this block will processed to either send notifications
+// now or after numConfirmations confs.
+func (e *EsploraNotifier) handleBlockConnected(
+	block chainntnfs.BlockEpoch) error {
+
+	// First, we'll fetch the raw block as we'll need to gather all the
+	// transactions to determine whether any are relevant to our registered
+	// clients.
+	rawBlock, err := e.GetBlock(block.Hash)
+	if err != nil {
+		return fmt.Errorf("unable to get block: %w", err)
+	}
+	utilBlock := btcutil.NewBlock(rawBlock)
+
+	// We'll then extend the txNotifier's height with the information of
+	// this new block, which will handle all of the notification logic for
+	// us.
+	err = e.txNotifier.ConnectTip(utilBlock, uint32(block.Height))
+	if err != nil {
+		return fmt.Errorf("unable to connect tip: %w", err)
+	}
+
+	chainntnfs.Log.Infof("New block: height=%v, sha=%v", block.Height,
+		block.Hash)
+
+	// Now that we've guaranteed the new block extends the txNotifier's
+	// current tip, we'll proceed to dispatch notifications to all of our
+	// registered clients whom have had notifications fulfilled. Before
+	// doing so, we'll make sure update our in memory state in order to
+	// satisfy any client requests based upon the new block.
+	e.bestBlock = block
+
+	e.notifyBlockEpochs(block.Height, block.Hash, block.BlockHeader)
+
+	return e.txNotifier.NotifyHeight(uint32(block.Height))
+}
+
+// notifyBlockEpochs notifies all registered block epoch clients of the newly
+// connected block to the main chain.
+func (e *EsploraNotifier) notifyBlockEpochs(newHeight int32,
+	newSha *chainhash.Hash, blockHeader *wire.BlockHeader) {
+
+	for _, client := range e.blockEpochClients {
+		e.notifyBlockEpochClient(client, newHeight, newSha, blockHeader)
+	}
+}
+
+// notifyBlockEpochClient sends a registered block epoch client a notification
+// about a specific block.
+func (e *EsploraNotifier) notifyBlockEpochClient(
+	epochClient *blockEpochRegistration, height int32,
+	sha *chainhash.Hash, header *wire.BlockHeader) {
+
+	epoch := &chainntnfs.BlockEpoch{
+		Height:      height,
+		Hash:        sha,
+		BlockHeader: header,
+	}
+
+	select {
+	case epochClient.epochQueue.ChanIn() <- epoch:
+	case <-epochClient.cancelChan:
+	case <-e.quit:
+	}
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the target
+// outpoint/output script has been spent by a transaction on-chain. When
+// intending to be notified of the spend of an output script, a nil outpoint
+// must be used. The heightHint should represent the earliest height in the
+// chain of the transaction that spent the outpoint/output script.
+//
+// Once a spend of has been detected, the details of the spending event will be
+// sent across the 'Spend' channel.
+func (e *EsploraNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	pkScript []byte, heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	// Register the spend notification with the TxNotifier. A non-nil
+	// value for `dispatch` will be returned if we are required to perform
+	// a historical lookup for the spend. Otherwise the notifier will begin
+	// watching at tip for the outpoint/output script to be spent.
+	ntfn, err := e.txNotifier.RegisterSpend(outpoint, pkScript, heightHint)
+	if err != nil {
+		return nil, err
+	}
+
+	if ntfn.HistoricalDispatch == nil {
+		return ntfn.Event, nil
+	}
+
+	select {
+	case e.notificationRegistry <- ntfn.HistoricalDispatch:
+		return ntfn.Event, nil
+	case <-e.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+}
+
+// RegisterConfirmationsNtfn registers an intent to be notified once the target
+// txid/output script has reached numConfs confirmations on-chain. When
+// intending to be notified of the confirmation of an output script, a nil txid
+// must be used. The heightHint should represent the earliest height at which
+// the txid/output script could have been included in the chain.
+//
+// Progress on the number of confirmations left can be read from the 'Updates'
+// channel. Once it has reached all of its confirmations, a notification will be
+// sent across the 'Confirmed' channel.
+func (e *EsploraNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	pkScript []byte, numConfs, heightHint uint32,
+	opts ...chainntnfs.NotifierOption) (*chainntnfs.ConfirmationEvent,
+	error) {
+
+	// Register the conf notification with the TxNotifier. A non-nil value
+	// for `dispatch` will be returned if we are required to perform a
+	// historical lookup for the confirmation. Otherwise the notifier will
+	// begin watching at tip for the transaction to confirm.
+	ntfn, err := e.txNotifier.RegisterConf(
+		txid, pkScript, numConfs, heightHint, opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ntfn.HistoricalDispatch == nil {
+		return ntfn.Event, nil
+	}
+
+	select {
+	case e.notificationRegistry <- ntfn.HistoricalDispatch:
+		return ntfn.Event, nil
+	case <-e.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+}
+
+// blockEpochRegistration represents a client's intent to receive a
+// notification with each newly connected block.
+type blockEpochRegistration struct {
+	epochID uint64
+
+	epochChan chan *chainntnfs.BlockEpoch
+
+	epochQueue *queue.ConcurrentQueue
+
+	bestBlock *chainntnfs.BlockEpoch
+
+	errorChan chan error
+
+	cancelChan chan struct{}
+
+	wg sync.WaitGroup
+}
+
+// epochCancel is a message sent to the EsploraNotifier when a client wishes
+// to cancel an outstanding epoch notification that has yet to be dispatched.
+type epochCancel struct {
+	epochID uint64
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive notifications, of each new block connected to the main
+// chain. Clients have the option of passing in their best known block, which
+// the notifier uses to check if they are behind on blocks and catch them up. If
+// they do not provide one, then a notification will be dispatched immediately
+// for the current tip of the chain upon a successful registration.
+func (e *EsploraNotifier) RegisterBlockEpochNtfn(
+	bestBlock *chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error) {
+
+	reg := &blockEpochRegistration{
+		epochQueue: queue.NewConcurrentQueue(20),
+		epochChan:  make(chan *chainntnfs.BlockEpoch, 20),
+		cancelChan: make(chan struct{}),
+		epochID:    atomic.AddUint64(&e.epochClientCounter, 1),
+		bestBlock:  bestBlock,
+		errorChan:  make(chan error, 1),
+	}
+	reg.epochQueue.Start()
+
+	// Before we send the request to the main goroutine, we'll launch a new
+	// goroutine to proxy items added to our queue to the client itself.
+	// This ensures that all notifications are received *in order*.
+	reg.wg.Add(1)
+	go func() {
+		defer reg.wg.Done()
+
+		for {
+			select {
+			case ntfn := <-reg.epochQueue.ChanOut():
+				blockNtfn := ntfn.(*chainntnfs.BlockEpoch)
+				select {
+				case reg.epochChan <- blockNtfn:
+
+				case <-reg.cancelChan:
+					return
+
+				case <-e.quit:
+					return
+				}
+
+			case <-reg.cancelChan:
+				return
+
+			case <-e.quit:
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-e.quit:
+		// As we're exiting before the registration could be sent,
+		// we'll stop the queue now ourselves.
+		reg.epochQueue.Stop()
+
+		return nil, errors.New("chainntnfs: system interrupt while " +
+			"attempting to register for block epoch notification.")
+
+	case e.notificationRegistry <- reg:
+		return &chainntnfs.BlockEpochEvent{
+			Epochs: reg.epochChan,
+			Cancel: func() {
+				cancel := &epochCancel{
+					epochID: reg.epochID,
+				}
+
+				// Submit epoch cancellation to notification
+				// dispatcher.
+				select {
+				case e.notificationCancels <- cancel:
+					// Cancellation is being handled, drain
+					// the epoch channel until it is closed
+					// before yielding to caller.
+					for {
+						select {
+						case _, ok := <-reg.epochChan:
+							if !ok {
+								return
+							}
+						case <-e.quit:
+							return
+						}
+					}
+				case <-e.quit:
+				}
+			},
+		}, nil
+	}
+}
+
+// GetBlock is used to retrieve the block with the given hash. This function
+// wraps the blockCache's GetBlock function.
+func (e *EsploraNotifier) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock,
+	error) {
+
+	return e.blockCache.GetBlock(hash, e.conn.GetBlock)
+}
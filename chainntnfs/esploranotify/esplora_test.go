@@ -0,0 +1,259 @@
+package esploranotify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/esplora"
+	"github.com/lightningnetwork/lnd/esplora/esploratest"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// testPubKey is the public key of the witness of the test inputs.
+	testPubKey = append([]byte{0x02}, make([]byte, 32)...)
+
+	// testScript is the P2WPKH output script of the test public key, such
+	// that it can be derived from the witness of the inputs that spend
+	// it.
+	testScript = append(
+		[]byte{0x00, 0x14}, btcutil.Hash160(testPubKey)...,
+	)
+
+	// testTimeout is the time after which an expected notification is
+	// considered missing.
+	testTimeout = 5 * time.Second
+)
+
+func initHintCache(t *testing.T) *channeldb.HeightHintCache {
+	t.Helper()
+
+	db, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err, "unable to create db")
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	testCfg := channeldb.CacheConfig{
+		QueryDisable: false,
+	}
+	hintCache, err := channeldb.NewHeightHintCache(testCfg, db.Backend)
+	require.NoError(t, err, "unable to create hint cache")
+
+	return hintCache
+}
+
+// setUpNotifier is a helper function to start a new notifier backed by the
+// given Esplora server.
+func setUpNotifier(t *testing.T,
+	server *esploratest.Server) *EsploraNotifier {
+
+	t.Helper()
+
+	conn := esplora.NewConn(&esplora.Config{
+		URL:            server.URL,
+		RequestTimeout: testTimeout,
+		PollInterval:   10 * time.Millisecond,
+	})
+	require.NoError(t, conn.Start())
+	t.Cleanup(conn.Stop)
+
+	hintCache := initHintCache(t)
+	notifier := New(
+		conn, hintCache, hintCache, blockcache.NewBlockCache(10000),
+	)
+	require.NoError(t, notifier.Start(), "unable to start notifier")
+	t.Cleanup(func() {
+		require.NoError(t, notifier.Stop())
+	})
+
+	return notifier
+}
+
+// newTestTx returns a transaction that spends the given outpoint to the test
+// script.
+func newTestTx(prevOut wire.OutPoint) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: prevOut,
+		Witness:          wire.TxWitness{make([]byte, 71), testPubKey},
+	})
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: testScript})
+
+	return tx
+}
+
+// TestHistoricalConfDetails ensures that the confirmation details of
+// transactions and scripts are looked up correctly.
+func TestHistoricalConfDetails(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	coinbase := server.MineBlock().Transactions[0]
+	tx := newTestTx(wire.OutPoint{Hash: coinbase.TxHash()})
+	block := server.MineBlock(tx)
+	server.MineBlock()
+
+	notifier := setUpNotifier(t, server)
+
+	// The transaction should be found at the second position of the
+	// second block, both by its txid and by its script only.
+	txid := tx.TxHash()
+	for _, txHash := range []*chainhash.Hash{&txid, nil} {
+		req, err := chainntnfs.NewConfRequest(txHash, testScript)
+		require.NoError(t, err)
+
+		txConf, err := notifier.historicalConfDetails(req, 1, 3)
+		require.NoError(t, err)
+		require.NotNil(t, txConf)
+		require.EqualValues(t, 2, txConf.BlockHeight)
+		require.Equal(t, block.BlockHash(), *txConf.BlockHash)
+		require.EqualValues(t, 1, txConf.TxIndex)
+		require.Equal(t, txid, txConf.Tx.TxHash())
+	}
+
+	// A script that confirmed outside the range isn't found.
+	req, err := chainntnfs.NewConfRequest(nil, testScript)
+	require.NoError(t, err)
+	txConf, err := notifier.historicalConfDetails(req, 3, 3)
+	require.NoError(t, err)
+	require.Nil(t, txConf)
+
+	// Neither are unknown nor unconfirmed transactions.
+	unconfirmedTx := newTestTx(wire.OutPoint{Hash: txid})
+	unconfirmedHash := unconfirmedTx.TxHash()
+	req, err = chainntnfs.NewConfRequest(&unconfirmedHash, testScript)
+	require.NoError(t, err)
+
+	txConf, err = notifier.historicalConfDetails(req, 1, 3)
+	require.NoError(t, err)
+	require.Nil(t, txConf)
+
+	_, err = notifier.conn.BroadcastTx(unconfirmedTx)
+	require.NoError(t, err)
+
+	txConf, err = notifier.historicalConfDetails(req, 1, 3)
+	require.NoError(t, err)
+	require.Nil(t, txConf)
+}
+
+// TestHistoricalSpendDetails ensures that the spend details of outpoints and
+// scripts are looked up correctly.
+func TestHistoricalSpendDetails(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	coinbase := server.MineBlock().Transactions[0]
+	fundingTx := newTestTx(wire.OutPoint{Hash: coinbase.TxHash()})
+
+	// The coinbase output doesn't pay to the test script, so the funding
+	// transaction must not look like a spend of it.
+	fundingTx.TxIn[0].Witness = nil
+	server.MineBlock(fundingTx)
+
+	notifier := setUpNotifier(t, server)
+
+	outpoint := wire.OutPoint{Hash: fundingTx.TxHash()}
+	opReq, err := chainntnfs.NewSpendRequest(&outpoint, testScript)
+	require.NoError(t, err)
+	scriptReq, err := chainntnfs.NewSpendRequest(nil, testScript)
+	require.NoError(t, err)
+
+	// The output isn't spent yet.
+	details, err := notifier.historicalSpendDetails(opReq, 1, 2)
+	require.NoError(t, err)
+	require.Nil(t, details)
+
+	// Once the spend is confirmed, it should be found within the range
+	// that includes its block, both by outpoint and by script.
+	spendTx := newTestTx(outpoint)
+	server.MineBlock(spendTx)
+
+	for _, req := range []chainntnfs.SpendRequest{opReq, scriptReq} {
+		details, err = notifier.historicalSpendDetails(req, 1, 3)
+		require.NoError(t, err)
+		require.NotNil(t, details)
+		require.Equal(t, spendTx.TxHash(), *details.SpenderTxHash)
+		require.Equal(t, outpoint, *details.SpentOutPoint)
+		require.EqualValues(t, 0, details.SpenderInputIndex)
+		require.EqualValues(t, 3, details.SpendingHeight)
+
+		details, err = notifier.historicalSpendDetails(req, 1, 2)
+		require.NoError(t, err)
+		require.Nil(t, details)
+	}
+}
+
+// TestNotificationsAtTip ensures that confirmations, spends and block epochs
+// are dispatched for new blocks.
+func TestNotificationsAtTip(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	coinbase := server.MineBlock().Transactions[0]
+	fundingTx := newTestTx(wire.OutPoint{Hash: coinbase.TxHash()})
+
+	notifier := setUpNotifier(t, server)
+
+	epochs, err := notifier.RegisterBlockEpochNtfn(nil)
+	require.NoError(t, err)
+	defer epochs.Cancel()
+
+	select {
+	case epoch := <-epochs.Epochs:
+		require.EqualValues(t, 1, epoch.Height)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no block epoch received")
+	}
+
+	// We register for the confirmation and the spend of the funding
+	// transaction before it confirms.
+	fundingHash := fundingTx.TxHash()
+	confNtfn, err := notifier.RegisterConfirmationsNtfn(
+		&fundingHash, testScript, 1, 1,
+	)
+	require.NoError(t, err)
+
+	outpoint := wire.OutPoint{Hash: fundingHash}
+	spendNtfn, err := notifier.RegisterSpendNtfn(&outpoint, testScript, 1)
+	require.NoError(t, err)
+
+	block := server.MineBlock(fundingTx)
+
+	select {
+	case epoch := <-epochs.Epochs:
+		require.EqualValues(t, 2, epoch.Height)
+		require.Equal(t, block.BlockHash(), *epoch.Hash)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no block epoch received")
+	}
+
+	select {
+	case txConf := <-confNtfn.Confirmed:
+		require.EqualValues(t, 2, txConf.BlockHeight)
+		require.Equal(t, block.BlockHash(), *txConf.BlockHash)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no confirmation received")
+	}
+
+	spendTx := newTestTx(outpoint)
+	server.MineBlock(spendTx)
+
+	select {
+	case details := <-spendNtfn.Spend:
+		require.Equal(t, spendTx.TxHash(), *details.SpenderTxHash)
+		require.EqualValues(t, 3, details.SpendingHeight)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no spend received")
+	}
+}
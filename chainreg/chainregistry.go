@@ -18,12 +18,15 @@ import (
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/chainfailover"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
 	"github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
+	"github.com/lightningnetwork/lnd/chainntnfs/esploranotify"
 	"github.com/lightningnetwork/lnd/chainntnfs/neutrinonotify"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/esplora"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -56,6 +59,13 @@ type Config struct {
 	// BtcdMode defines settings for connecting to a btcd node.
 	BtcdMode *lncfg.Btcd
 
+	// EsploraMode defines settings for connecting to an Esplora server.
+	EsploraMode *lncfg.Esplora
+
+	// ChainFailover defines the bitcoind and btcd backends to fail over
+	// to. This field is optional.
+	ChainFailover *lncfg.ChainFailover
+
 	// HeightHintDB is a pointer to the database that stores the height
 	// hints.
 	HeightHintDB kvdb.Backend
@@ -179,6 +189,12 @@ type PartialChainControl struct {
 	// notifications for received funds, etc.
 	ChainSource chain.Interface
 
+	// MempoolRPC is the RPC interface of a bitcoind or btcd backend. It's
+	// used to inspect the mempool of the backend for fee estimation and
+	// to submit packages of transactions to it. It's nil for other
+	// backends.
+	MempoolRPC chainfee.MempoolRPC
+
 	// RoutingPolicy is the routing policy we have decided to use.
 	RoutingPolicy models.ForwardingPolicy
 
@@ -266,6 +282,14 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		cfg.Fee.URL = cfg.FeeURL
 	}
 
+	// esploraConn is the connection to the Esplora server, if that backend
+	// is used. It needs to be stopped on clean up.
+	var esploraConn *esplora.Conn
+
+	// failoverSwitch maintains the active backend if failover backends
+	// are configured. It needs to be stopped on clean up.
+	var failoverSwitch *chainfailover.Switch
+
 	// If spv mode is active, then we'll be using a distinct set of
 	// chainControl interfaces that interface directly with the p2p network
 	// of the selected chain.
@@ -294,384 +318,96 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return err
 		}
 
-	case "bitcoind":
-		bitcoindMode := cfg.BitcoindMode
-
-		// Otherwise, we'll be speaking directly via RPC and ZMQ to a
-		// bitcoind node. If the specified host for the btcd RPC
-		// server already has a port specified, then we use that
-		// directly. Otherwise, we assume the default port according to
-		// the selected chain parameters.
-		var bitcoindHost string
-		if strings.Contains(bitcoindMode.RPCHost, ":") {
-			bitcoindHost = bitcoindMode.RPCHost
-		} else {
-			// The RPC ports specified in chainparams.go assume
-			// btcd, which picks a different port so that btcwallet
-			// can use the same RPC port as bitcoind. We convert
-			// this back to the btcwallet/bitcoind port.
-			rpcPort, err := strconv.Atoi(cfg.ActiveNetParams.RPCPort)
-			if err != nil {
-				return nil, nil, err
-			}
-			rpcPort -= 2
-			bitcoindHost = fmt.Sprintf("%v:%d",
-				bitcoindMode.RPCHost, rpcPort)
-			if cfg.Bitcoin.RegTest || cfg.Bitcoin.SigNet {
-				conn, err := net.Dial("tcp", bitcoindHost)
-				if err != nil || conn == nil {
-					switch {
-					case cfg.Bitcoin.RegTest:
-						rpcPort = 18443
-					case cfg.Bitcoin.SigNet:
-						rpcPort = 38332
-					}
-					bitcoindHost = fmt.Sprintf("%v:%d",
-						bitcoindMode.RPCHost,
-						rpcPort)
-				} else {
-					conn.Close()
-				}
-			}
-		}
-
-		bitcoindCfg := &chain.BitcoindConfig{
-			ChainParams:        cfg.ActiveNetParams.Params,
-			Host:               bitcoindHost,
-			User:               bitcoindMode.RPCUser,
-			Pass:               bitcoindMode.RPCPass,
-			Dialer:             cfg.Dialer,
-			PrunedModeMaxPeers: bitcoindMode.PrunedNodeMaxPeers,
-		}
-
-		if bitcoindMode.RPCPolling {
-			bitcoindCfg.PollingConfig = &chain.PollingConfig{
-				BlockPollingInterval:    bitcoindMode.BlockPollingInterval,
-				TxPollingInterval:       bitcoindMode.TxPollingInterval,
-				TxPollingIntervalJitter: lncfg.DefaultTxPollingJitter,
-			}
-		} else {
-			bitcoindCfg.ZMQConfig = &chain.ZMQConfig{
-				ZMQBlockHost:           bitcoindMode.ZMQPubRawBlock,
-				ZMQTxHost:              bitcoindMode.ZMQPubRawTx,
-				ZMQReadDeadline:        bitcoindMode.ZMQReadDeadline,
-				MempoolPollingInterval: bitcoindMode.TxPollingInterval,
-				PollingIntervalJitter:  lncfg.DefaultTxPollingJitter,
-			}
-		}
-
-		// Establish the connection to bitcoind and create the clients
-		// required for our relevant subsystems.
-		bitcoindConn, err := chain.NewBitcoindConn(bitcoindCfg)
+	case "bitcoind", "btcd":
+		backends, err := rpcBackends(cfg)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		if err := bitcoindConn.Start(); err != nil {
-			return nil, nil, fmt.Errorf("unable to connect to "+
-				"bitcoind: %v", err)
-		}
-
-		chainNotifier := bitcoindnotify.New(
-			bitcoindConn, cfg.ActiveNetParams.Params, hintCache,
-			hintCache, cfg.BlockCache,
-		)
-
-		cc.ChainNotifier = chainNotifier
-		cc.MempoolNotifier = chainNotifier
-
-		cc.ChainView = chainview.NewBitcoindFilteredChainView(
-			bitcoindConn, cfg.BlockCache,
-		)
-		cc.ChainSource = bitcoindConn.NewBitcoindClient()
-
-		// If we're not in regtest mode, then we'll attempt to use a
-		// proper fee estimator for testnet.
-		rpcConfig := &rpcclient.ConnConfig{
-			Host:                 bitcoindHost,
-			User:                 bitcoindMode.RPCUser,
-			Pass:                 bitcoindMode.RPCPass,
-			DisableConnectOnNew:  true,
-			DisableAutoReconnect: false,
-			DisableTLS:           true,
-			HTTPPostMode:         true,
-		}
-		if !cfg.Bitcoin.RegTest {
-			log.Infof("Initializing bitcoind backed fee estimator "+
-				"in %s mode", bitcoindMode.EstimateMode)
-
-			// Finally, we'll re-initialize the fee estimator, as
-			// if we're using bitcoind as a backend, then we can
-			// use live fee estimates, rather than a statically
-			// coded value.
-			fallBackFeeRate := chainfee.SatPerKVByte(25 * 1000)
-			cc.FeeEstimator, err = chainfee.NewBitcoindEstimator(
-				*rpcConfig, bitcoindMode.EstimateMode,
-				fallBackFeeRate.FeePerKWeight(),
+		// Without any backends to fail over to, we'll use the chain
+		// interfaces of the configured backend directly.
+		if len(backends) == 1 {
+			backend, err := newRPCBackend(
+				cfg, backends[0], hintCache,
 			)
 			if err != nil {
 				return nil, nil, err
 			}
-		}
 
-		// We need to use some apis that are not exposed by btcwallet,
-		// for a health check function so we create an ad-hoc bitcoind
-		// connection.
-		chainConn, err := rpcclient.New(rpcConfig, nil)
-		if err != nil {
-			return nil, nil, err
-		}
+			cc.ChainNotifier = backend.chainNotifier
+			cc.MempoolNotifier = backend.mempoolNotifier
+			cc.ChainView = backend.chainView
+			cc.ChainSource = backend.chainSource
+			cc.HealthCheck = backend.healthCheck
+			cc.MempoolRPC = backend.chainConn
+			if backend.feeEstimator != nil {
+				cc.FeeEstimator = backend.feeEstimator
+			}
 
-		// Before we continue any further, we'll ensure that the
-		// backend understands Taproot. If not, then all the default
-		// features can't be used.
-		if !backendSupportsTaproot(chainConn) {
-			return nil, nil, fmt.Errorf("node backend does not " +
-				"support taproot")
+			break
 		}
 
-		// The api we will use for our health check depends on the
-		// bitcoind version.
-		cmd, ver, err := getBitcoindHealthCheckCmd(chainConn)
+		// Otherwise, the chain interfaces are backed by the healthy
+		// backend with the highest priority. Mempool spends can't be
+		// watched across backend switches, so that's left disabled.
+		failoverSwitch, err = newFailoverSwitch(
+			cfg, backends, hintCache,
+		)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		// If the getzmqnotifications api is available (was added in
-		// version 0.17.0) we make sure lnd subscribes to the correct
-		// zmq events. We do this to avoid a situation in which we are
-		// not notified of new transactions or blocks.
-		if ver >= 170000 && !bitcoindMode.RPCPolling {
-			zmqPubRawBlockURL, err := url.Parse(bitcoindMode.ZMQPubRawBlock)
-			if err != nil {
-				return nil, nil, err
-			}
-			zmqPubRawTxURL, err := url.Parse(bitcoindMode.ZMQPubRawTx)
-			if err != nil {
-				return nil, nil, err
-			}
-
-			// Fetch all active zmq notifications from the bitcoind client.
-			resp, err := chainConn.RawRequest("getzmqnotifications", nil)
-			if err != nil {
-				return nil, nil, err
-			}
-
-			zmq := []struct {
-				Type    string `json:"type"`
-				Address string `json:"address"`
-			}{}
-
-			if err = json.Unmarshal([]byte(resp), &zmq); err != nil {
-				return nil, nil, err
-			}
-
-			pubRawBlockActive := false
-			pubRawTxActive := false
-
-			for i := range zmq {
-				if zmq[i].Type == "pubrawblock" {
-					url, err := url.Parse(zmq[i].Address)
-					if err != nil {
-						return nil, nil, err
-					}
-					if url.Port() != zmqPubRawBlockURL.Port() {
-						log.Warnf(
-							"unable to subscribe to zmq block events on "+
-								"%s (bitcoind is running on %s)",
-							zmqPubRawBlockURL.Host,
-							url.Host,
-						)
-					}
-					pubRawBlockActive = true
-				}
-				if zmq[i].Type == "pubrawtx" {
-					url, err := url.Parse(zmq[i].Address)
-					if err != nil {
-						return nil, nil, err
-					}
-					if url.Port() != zmqPubRawTxURL.Port() {
-						log.Warnf(
-							"unable to subscribe to zmq tx events on "+
-								"%s (bitcoind is running on %s)",
-							zmqPubRawTxURL.Host,
-							url.Host,
-						)
-					}
-					pubRawTxActive = true
-				}
-			}
-
-			// Return an error if raw tx or raw block notification over
-			// zmq is inactive.
-			if !pubRawBlockActive {
-				return nil, nil, errors.New(
-					"block notification over zmq is inactive on " +
-						"bitcoind",
-				)
-			}
-			if !pubRawTxActive {
-				return nil, nil, errors.New(
-					"tx notification over zmq is inactive on " +
-						"bitcoind",
-				)
-			}
+		cc.ChainNotifier = failoverSwitch.ChainNotifier()
+		cc.ChainView = failoverSwitch.ChainView()
+		cc.ChainSource = failoverSwitch.ChainSource()
+		cc.FeeEstimator = failoverSwitch.FeeEstimator()
+		cc.HealthCheck = failoverSwitch.HealthCheck
+		cc.MempoolRPC = &failoverMempoolRPC{
+			failoverSwitch: failoverSwitch,
 		}
 
-		cc.HealthCheck = func() error {
-			_, err := chainConn.RawRequest(cmd, nil)
-			if err != nil {
-				return err
-			}
-
-			// On local test networks we usually don't have multiple
-			// chain backend peers, so we can skip
-			// the checkOutboundPeers test.
-			if cfg.Bitcoin.SimNet || cfg.Bitcoin.RegTest {
-				return nil
-			}
-
-			// Make sure the bitcoind chain backend maintains a
-			// healthy connection to the network by checking the
-			// number of outbound peers.
-			return checkOutboundPeers(chainConn)
+	case "esplora":
+		// We'll be speaking to an Esplora server over its REST API. A
+		// single connection polls the server for new blocks and is
+		// shared by the notifier, the chain view and the wallet.
+		esploraConn = esplora.NewConn(&esplora.Config{
+			URL:            cfg.EsploraMode.URL,
+			RequestTimeout: cfg.EsploraMode.RequestTimeout,
+			PollInterval:   cfg.EsploraMode.PollInterval,
+		})
+		if err := esploraConn.Start(); err != nil {
+			return nil, nil, fmt.Errorf("unable to connect to "+
+				"esplora server: %w", err)
 		}
 
-	case "btcd":
-		// Otherwise, we'll be speaking directly via RPC to a node.
-		//
-		// So first we'll load btcd's TLS cert for the RPC
-		// connection. If a raw cert was specified in the config, then
-		// we'll set that directly. Otherwise, we attempt to read the
-		// cert from the path specified in the config.
-		var (
-			rpcCert  []byte
-			btcdMode = cfg.BtcdMode
+		cc.ChainNotifier = esploranotify.New(
+			esploraConn, hintCache, hintCache, cfg.BlockCache,
 		)
-		if btcdMode.RawRPCCert != "" {
-			rpcCert, err = hex.DecodeString(btcdMode.RawRPCCert)
-			if err != nil {
-				return nil, nil, err
-			}
-		} else {
-			certFile, err := os.Open(btcdMode.RPCCert)
-			if err != nil {
-				return nil, nil, err
-			}
-			rpcCert, err = io.ReadAll(certFile)
-			if err != nil {
-				return nil, nil, err
-			}
-			if err := certFile.Close(); err != nil {
-				return nil, nil, err
-			}
-		}
-
-		// If the specified host for the btcd RPC server already
-		// has a port specified, then we use that directly. Otherwise,
-		// we assume the default port according to the selected chain
-		// parameters.
-		var btcdHost string
-		if strings.Contains(btcdMode.RPCHost, ":") {
-			btcdHost = btcdMode.RPCHost
-		} else {
-			btcdHost = fmt.Sprintf("%v:%v", btcdMode.RPCHost,
-				cfg.ActiveNetParams.RPCPort)
-		}
-
-		btcdUser := btcdMode.RPCUser
-		btcdPass := btcdMode.RPCPass
-		rpcConfig := &rpcclient.ConnConfig{
-			Host:                 btcdHost,
-			Endpoint:             "ws",
-			User:                 btcdUser,
-			Pass:                 btcdPass,
-			Certificates:         rpcCert,
-			DisableTLS:           false,
-			DisableConnectOnNew:  true,
-			DisableAutoReconnect: false,
-		}
-
-		chainNotifier, err := btcdnotify.New(
-			rpcConfig, cfg.ActiveNetParams.Params, hintCache,
-			hintCache, cfg.BlockCache,
+		cc.ChainView = chainview.NewEsploraFilteredChainView(
+			esploraConn, cfg.BlockCache,
 		)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		cc.ChainNotifier = chainNotifier
-		cc.MempoolNotifier = chainNotifier
-
-		// Finally, we'll create an instance of the default chain view
-		// to be used within the routing layer.
-		cc.ChainView, err = chainview.NewBtcdFilteredChainView(
-			*rpcConfig, cfg.BlockCache,
+		cc.ChainSource = esploraConn.NewChainClient(
+			cfg.ActiveNetParams.Params, cfg.BlockCache,
 		)
-		if err != nil {
-			log.Errorf("unable to create chain view: %v", err)
-			return nil, nil, err
-		}
 
-		// Create a special websockets rpc client for btcd which will be
-		// used by the wallet for notifications, calls, etc.
-		chainRPC, err := chain.NewRPCClient(
-			cfg.ActiveNetParams.Params, btcdHost, btcdUser,
-			btcdPass, rpcCert, false, 20,
-		)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		// Before we continue any further, we'll ensure that the
-		// backend understands Taproot. If not, then all the default
-		// features can't be used.
-		restConfCopy := *rpcConfig
-		restConfCopy.Endpoint = ""
-		restConfCopy.HTTPPostMode = true
-		chainConn, err := rpcclient.New(&restConfCopy, nil)
-		if err != nil {
-			return nil, nil, err
-		}
-		if !backendSupportsTaproot(chainConn) {
-			return nil, nil, fmt.Errorf("node backend does not " +
-				"support taproot")
-		}
-
-		cc.ChainSource = chainRPC
-
-		// Use a query for our best block as a health check.
+		// Use a query for the tip of the server as a health check.
 		cc.HealthCheck = func() error {
-			_, _, err := cc.ChainSource.GetBestBlock()
-			if err != nil {
-				return err
-			}
-
-			// On local test networks we usually don't have multiple
-			// chain backend peers, so we can skip
-			// the checkOutboundPeers test.
-			if cfg.Bitcoin.SimNet || cfg.Bitcoin.RegTest {
-				return nil
-			}
-
-			// Make sure the btcd chain backend maintains a
-			// healthy connection to the network by checking the
-			// number of outbound peers.
-			return checkOutboundPeers(chainRPC.Client)
+			_, err := esploraConn.GetTipHash()
+			return err
 		}
 
-		// If we're not in simnet or regtest mode, then we'll attempt
-		// to use a proper fee estimator for testnet.
+		// If we're not in simnet or regtest mode, then we'll use the
+		// fee estimates of the server rather than a statically coded
+		// value.
 		if !cfg.Bitcoin.SimNet && !cfg.Bitcoin.RegTest {
-			log.Info("Initializing btcd backed fee estimator")
-
-			// Finally, we'll re-initialize the fee estimator, as
-			// if we're using btcd as a backend, then we can use
-			// live fee estimates, rather than a statically coded
-			// value.
-			fallBackFeeRate := chainfee.SatPerKVByte(25 * 1000)
-			cc.FeeEstimator, err = chainfee.NewBtcdEstimator(
-				*rpcConfig, fallBackFeeRate.FeePerKWeight(),
+			log.Info("Initializing esplora backed fee estimator")
+
+			cc.FeeEstimator, err = chainfee.NewWebAPIEstimator(
+				chainfee.EsploraFeeSource{
+					Conn: esploraConn,
+				},
+				false, cfg.Fee.MinUpdateTimeout,
+				cfg.Fee.MaxUpdateTimeout,
 			)
 			if err != nil {
 				return nil, nil, err
@@ -701,6 +437,10 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 	cc.BestBlockTracker =
 		chainntnfs.NewBestBlockTracker(cc.ChainNotifier)
 
+	// The fee estimator of the backend is kept as a source for the
+	// aggregating fee estimator, even if it's overridden by the fee URL.
+	backendFeeEstimator := cc.FeeEstimator
+
 	switch {
 	// If the fee URL isn't set, and the user is running mainnet, then
 	// we'll return an error to instruct them to set a proper fee
@@ -713,22 +453,22 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 	// Override default fee estimator if an external service is specified.
 	case cfg.Fee.URL != "":
-		// Do not cache fees on regtest to make it easier to execute
-		// manual or automated test cases.
-		cacheFees := !cfg.Bitcoin.RegTest
-
-		log.Infof("Using external fee estimator %v: cached=%v: "+
-			"min update timeout=%v, max update timeout=%v",
-			cfg.Fee.URL, cacheFees, cfg.Fee.MinUpdateTimeout,
-			cfg.Fee.MaxUpdateTimeout)
-
-		cc.FeeEstimator, err = chainfee.NewWebAPIEstimator(
-			chainfee.SparseConfFeeSource{
-				URL: cfg.Fee.URL,
-			},
-			!cacheFees,
-			cfg.Fee.MinUpdateTimeout,
-			cfg.Fee.MaxUpdateTimeout,
+		cc.FeeEstimator, err = newWebAPIEstimator(cfg, cfg.Fee.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// If requested, the estimates of the backend, the fee URLs and the
+	// mempool are combined, instead of relying on a single source.
+	if cfg.Fee.Aggregate {
+		var urlEstimator chainfee.Estimator
+		if cfg.Fee.URL != "" {
+			urlEstimator = cc.FeeEstimator
+		}
+
+		cc.FeeEstimator, err = newAggregatingEstimator(
+			cfg, backendFeeEstimator, urlEstimator, cc.MempoolRPC,
 		)
 		if err != nil {
 			return nil, nil, err
@@ -742,6 +482,14 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 					err)
 			}
 		}
+
+		if esploraConn != nil {
+			esploraConn.Stop()
+		}
+
+		if failoverSwitch != nil {
+			failoverSwitch.Stop()
+		}
 	}
 
 	// Start fee estimator.
@@ -752,6 +500,665 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 	return cc, ccCleanup, nil
 }
 
+// newWebAPIEstimator creates a fee estimator for the given fee URL.
+func newWebAPIEstimator(cfg *Config,
+	feeURL string) (*chainfee.WebAPIEstimator, error) {
+
+	// Do not cache fees on regtest to make it easier to execute manual or
+	// automated test cases.
+	cacheFees := !cfg.Bitcoin.RegTest
+
+	log.Infof("Using external fee estimator %v: cached=%v: min update "+
+		"timeout=%v, max update timeout=%v", feeURL, cacheFees,
+		cfg.Fee.MinUpdateTimeout, cfg.Fee.MaxUpdateTimeout)
+
+	return chainfee.NewWebAPIEstimator(
+		chainfee.SparseConfFeeSource{
+			URL: feeURL,
+		},
+		!cacheFees,
+		cfg.Fee.MinUpdateTimeout,
+		cfg.Fee.MaxUpdateTimeout,
+	)
+}
+
+// feeURLSourceName returns the name of the fee source of the given fee URL.
+func feeURLSourceName(feeURL string) string {
+	u, err := url.Parse(feeURL)
+	if err != nil || u.Host == "" {
+		return feeURL
+	}
+
+	return u.Host
+}
+
+// newAggregatingEstimator creates a fee estimator that combines the estimates
+// of the backend, the fee URLs and, if enabled, the mempool of the backend.
+func newAggregatingEstimator(cfg *Config, backendEstimator,
+	urlEstimator chainfee.Estimator,
+	mempoolRPC chainfee.MempoolRPC) (*chainfee.AggregatingEstimator,
+	error) {
+
+	sources := []*chainfee.FeeSource{{
+		Name:      cfg.Bitcoin.Node,
+		Estimator: backendEstimator,
+	}}
+
+	if urlEstimator != nil {
+		sources = append(sources, &chainfee.FeeSource{
+			Name:      feeURLSourceName(cfg.Fee.URL),
+			Estimator: urlEstimator,
+		})
+	}
+
+	for _, feeURL := range cfg.Fee.SourceURLs {
+		estimator, err := newWebAPIEstimator(cfg, feeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, &chainfee.FeeSource{
+			Name:      feeURLSourceName(feeURL),
+			Estimator: estimator,
+		})
+	}
+
+	if cfg.Fee.Mempool {
+		if mempoolRPC == nil {
+			return nil, fmt.Errorf("fee.mempool is not supported "+
+				"by the %v backend", cfg.Bitcoin.Node)
+		}
+
+		estimator, err := chainfee.NewMempoolEstimator(
+			chainfee.RPCMempoolSource{Conn: mempoolRPC},
+			cfg.Fee.MempoolUpdateInterval,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, &chainfee.FeeSource{
+			Name:      "mempool",
+			Estimator: estimator,
+		})
+	}
+
+	clamps, err := cfg.Fee.ParseClamps()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Using aggregating fee estimator with %d sources, max "+
+		"deviation=%v, %d clamps", len(sources), cfg.Fee.MaxDeviation,
+		len(clamps))
+
+	return chainfee.NewAggregatingEstimator(&chainfee.AggregatorConfig{
+		Sources:      sources,
+		MaxDeviation: cfg.Fee.MaxDeviation,
+		Clamps:       clamps,
+	})
+}
+
+// failoverMempoolRPC is a chainfee.MempoolRPC that sends its requests to the
+// active backend of a chain failover switch.
+type failoverMempoolRPC struct {
+	failoverSwitch *chainfailover.Switch
+}
+
+// RawRequest sends a raw JSON-RPC request to the active backend.
+func (f *failoverMempoolRPC) RawRequest(method string,
+	params []json.RawMessage) (json.RawMessage, error) {
+
+	conn, ok := f.failoverSwitch.ChainConn().(chainfee.MempoolRPC)
+	if !ok {
+		return nil, errors.New("active chain backend doesn't " +
+			"support raw requests")
+	}
+
+	return conn.RawRequest(method, params)
+}
+
+// rpcBackend houses the chain interfaces that are backed by the RPC interface
+// of a single bitcoind or btcd node.
+type rpcBackend struct {
+	chainNotifier   chainntnfs.ChainNotifier
+	mempoolNotifier chainntnfs.MempoolWatcher
+	chainView       chainview.FilteredChainView
+	chainSource     chain.Interface
+
+	// feeEstimator is the fee estimator of the node. It's nil if the
+	// static fee estimator is to be used.
+	feeEstimator chainfee.Estimator
+
+	// chainConn is an HTTP POST connection to the node's RPC interface.
+	chainConn *rpcclient.Client
+
+	healthCheck func() error
+
+	// cleanup closes the connections to the node.
+	cleanup func()
+}
+
+// failoverBackend returns the chain interfaces of the backend in the form
+// used by the chain failover switch.
+func (r *rpcBackend) failoverBackend() *chainfailover.Backend {
+	feeEstimator := r.feeEstimator
+	if feeEstimator == nil {
+		feeEstimator = chainfee.NewStaticEstimator(
+			DefaultBitcoinStaticFeePerKW,
+			DefaultBitcoinStaticMinRelayFeeRate,
+		)
+	}
+
+	return &chainfailover.Backend{
+		ChainNotifier: r.chainNotifier,
+		ChainView:     r.chainView,
+		ChainSource:   r.chainSource,
+		FeeEstimator:  feeEstimator,
+		ChainConn:     r.chainConn,
+		HealthCheck:   r.healthCheck,
+		Cleanup:       r.cleanup,
+	}
+}
+
+// rpcBackends returns the configured bitcoind and btcd backends in the order
+// of their priority, starting with the one of bitcoin.node.
+func rpcBackends(cfg *Config) ([]*lncfg.RPCBackend, error) {
+	primary := &lncfg.RPCBackend{
+		Node:     cfg.Bitcoin.Node,
+		Bitcoind: cfg.BitcoindMode,
+		Btcd:     cfg.BtcdMode,
+	}
+
+	if cfg.ChainFailover == nil {
+		return []*lncfg.RPCBackend{primary}, nil
+	}
+
+	failoverBackends, err := cfg.ChainFailover.ParseBackends(
+		cfg.BitcoindMode, cfg.BtcdMode,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]*lncfg.RPCBackend{primary}, failoverBackends...), nil
+}
+
+// newRPCBackend creates the chain interfaces of the given backend.
+func newRPCBackend(cfg *Config, backend *lncfg.RPCBackend,
+	hintCache *channeldb.HeightHintCache) (*rpcBackend, error) {
+
+	switch backend.Node {
+	case "bitcoind":
+		return newBitcoindBackend(cfg, backend.Bitcoind, hintCache)
+
+	case "btcd":
+		return newBtcdBackend(cfg, backend.Btcd, hintCache)
+
+	default:
+		return nil, fmt.Errorf("unknown node type: %s", backend.Node)
+	}
+}
+
+// newFailoverSwitch creates and starts a chain failover switch for the given
+// backends.
+func newFailoverSwitch(cfg *Config, backends []*lncfg.RPCBackend,
+	hintCache *channeldb.HeightHintCache) (*chainfailover.Switch, error) {
+
+	backendCfgs := make([]*chainfailover.BackendConfig, 0, len(backends))
+	for _, backend := range backends {
+		host := backend.Bitcoind.RPCHost
+		if backend.Node == "btcd" {
+			host = backend.Btcd.RPCHost
+		}
+
+		backendCfgs = append(backendCfgs, &chainfailover.BackendConfig{
+			Name: fmt.Sprintf("%v@%v", backend.Node, host),
+			New: func() (*chainfailover.Backend, error) {
+				b, err := newRPCBackend(cfg, backend, hintCache)
+				if err != nil {
+					return nil, err
+				}
+
+				return b.failoverBackend(), nil
+			},
+		})
+	}
+
+	healthCheck := cfg.ChainFailover.HealthCheck
+	failoverSwitch := chainfailover.New(&chainfailover.Config{
+		Backends:            backendCfgs,
+		HealthCheckInterval: healthCheck.Interval,
+		HealthCheckTimeout:  healthCheck.Timeout,
+		HealthCheckBackoff:  healthCheck.Backoff,
+		HealthCheckAttempts: healthCheck.Attempts,
+	})
+	if err := failoverSwitch.Start(); err != nil {
+		return nil, err
+	}
+
+	return failoverSwitch, nil
+}
+
+// newBitcoindBackend creates the chain interfaces that are backed by the
+// bitcoind node of the given configuration.
+func newBitcoindBackend(cfg *Config, bitcoindMode *lncfg.Bitcoind,
+	hintCache *channeldb.HeightHintCache) (*rpcBackend, error) {
+
+	backend := &rpcBackend{}
+
+	// We'll be speaking directly via RPC and ZMQ to a bitcoind
+	// node. If the specified host for the btcd RPC
+	// server already has a port specified, then we use that
+	// directly. Otherwise, we assume the default port according to
+	// the selected chain parameters.
+	var bitcoindHost string
+	if strings.Contains(bitcoindMode.RPCHost, ":") {
+		bitcoindHost = bitcoindMode.RPCHost
+	} else {
+		// The RPC ports specified in chainparams.go assume
+		// btcd, which picks a different port so that btcwallet
+		// can use the same RPC port as bitcoind. We convert
+		// this back to the btcwallet/bitcoind port.
+		rpcPort, err := strconv.Atoi(cfg.ActiveNetParams.RPCPort)
+		if err != nil {
+			return nil, err
+		}
+		rpcPort -= 2
+		bitcoindHost = fmt.Sprintf("%v:%d",
+			bitcoindMode.RPCHost, rpcPort)
+		if cfg.Bitcoin.RegTest || cfg.Bitcoin.SigNet {
+			conn, err := net.Dial("tcp", bitcoindHost)
+			if err != nil || conn == nil {
+				switch {
+				case cfg.Bitcoin.RegTest:
+					rpcPort = 18443
+				case cfg.Bitcoin.SigNet:
+					rpcPort = 38332
+				}
+				bitcoindHost = fmt.Sprintf("%v:%d",
+					bitcoindMode.RPCHost,
+					rpcPort)
+			} else {
+				conn.Close()
+			}
+		}
+	}
+
+	bitcoindCfg := &chain.BitcoindConfig{
+		ChainParams:        cfg.ActiveNetParams.Params,
+		Host:               bitcoindHost,
+		User:               bitcoindMode.RPCUser,
+		Pass:               bitcoindMode.RPCPass,
+		Dialer:             cfg.Dialer,
+		PrunedModeMaxPeers: bitcoindMode.PrunedNodeMaxPeers,
+	}
+
+	if bitcoindMode.RPCPolling {
+		bitcoindCfg.PollingConfig = &chain.PollingConfig{
+			BlockPollingInterval:    bitcoindMode.BlockPollingInterval,
+			TxPollingInterval:       bitcoindMode.TxPollingInterval,
+			TxPollingIntervalJitter: lncfg.DefaultTxPollingJitter,
+		}
+	} else {
+		bitcoindCfg.ZMQConfig = &chain.ZMQConfig{
+			ZMQBlockHost:           bitcoindMode.ZMQPubRawBlock,
+			ZMQTxHost:              bitcoindMode.ZMQPubRawTx,
+			ZMQReadDeadline:        bitcoindMode.ZMQReadDeadline,
+			MempoolPollingInterval: bitcoindMode.TxPollingInterval,
+			PollingIntervalJitter:  lncfg.DefaultTxPollingJitter,
+		}
+	}
+
+	// Establish the connection to bitcoind and create the clients
+	// required for our relevant subsystems.
+	bitcoindConn, err := chain.NewBitcoindConn(bitcoindCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bitcoindConn.Start(); err != nil {
+		return nil, fmt.Errorf("unable to connect to "+
+			"bitcoind: %v", err)
+	}
+
+	// Make sure the connection isn't left open if the backend can't be
+	// set up.
+	success := false
+	defer func() {
+		if !success {
+			bitcoindConn.Stop()
+		}
+	}()
+
+	chainNotifier := bitcoindnotify.New(
+		bitcoindConn, cfg.ActiveNetParams.Params, hintCache,
+		hintCache, cfg.BlockCache,
+	)
+
+	backend.chainNotifier = chainNotifier
+	backend.mempoolNotifier = chainNotifier
+
+	backend.chainView = chainview.NewBitcoindFilteredChainView(
+		bitcoindConn, cfg.BlockCache,
+	)
+	backend.chainSource = bitcoindConn.NewBitcoindClient()
+
+	// If we're not in regtest mode, then we'll attempt to use a
+	// proper fee estimator for testnet.
+	rpcConfig := &rpcclient.ConnConfig{
+		Host:                 bitcoindHost,
+		User:                 bitcoindMode.RPCUser,
+		Pass:                 bitcoindMode.RPCPass,
+		DisableConnectOnNew:  true,
+		DisableAutoReconnect: false,
+		DisableTLS:           true,
+		HTTPPostMode:         true,
+	}
+	if !cfg.Bitcoin.RegTest {
+		log.Infof("Initializing bitcoind backed fee estimator "+
+			"in %s mode", bitcoindMode.EstimateMode)
+
+		// Finally, we'll re-initialize the fee estimator, as
+		// if we're using bitcoind as a backend, then we can
+		// use live fee estimates, rather than a statically
+		// coded value.
+		fallBackFeeRate := chainfee.SatPerKVByte(25 * 1000)
+		backend.feeEstimator, err = chainfee.NewBitcoindEstimator(
+			*rpcConfig, bitcoindMode.EstimateMode,
+			fallBackFeeRate.FeePerKWeight(),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// We need to use some apis that are not exposed by btcwallet,
+	// for a health check function so we create an ad-hoc bitcoind
+	// connection.
+	chainConn, err := rpcclient.New(rpcConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Before we continue any further, we'll ensure that the
+	// backend understands Taproot. If not, then all the default
+	// features can't be used.
+	if !backendSupportsTaproot(chainConn) {
+		return nil, fmt.Errorf("node backend does not " +
+			"support taproot")
+	}
+
+	// The api we will use for our health check depends on the
+	// bitcoind version.
+	cmd, ver, err := getBitcoindHealthCheckCmd(chainConn)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the getzmqnotifications api is available (was added in
+	// version 0.17.0) we make sure lnd subscribes to the correct
+	// zmq events. We do this to avoid a situation in which we are
+	// not notified of new transactions or blocks.
+	if ver >= 170000 && !bitcoindMode.RPCPolling {
+		zmqPubRawBlockURL, err := url.Parse(bitcoindMode.ZMQPubRawBlock)
+		if err != nil {
+			return nil, err
+		}
+		zmqPubRawTxURL, err := url.Parse(bitcoindMode.ZMQPubRawTx)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fetch all active zmq notifications from the bitcoind client.
+		resp, err := chainConn.RawRequest("getzmqnotifications", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		zmq := []struct {
+			Type    string `json:"type"`
+			Address string `json:"address"`
+		}{}
+
+		if err = json.Unmarshal([]byte(resp), &zmq); err != nil {
+			return nil, err
+		}
+
+		pubRawBlockActive := false
+		pubRawTxActive := false
+
+		for i := range zmq {
+			if zmq[i].Type == "pubrawblock" {
+				url, err := url.Parse(zmq[i].Address)
+				if err != nil {
+					return nil, err
+				}
+				if url.Port() != zmqPubRawBlockURL.Port() {
+					log.Warnf(
+						"unable to subscribe to zmq block events on "+
+							"%s (bitcoind is running on %s)",
+						zmqPubRawBlockURL.Host,
+						url.Host,
+					)
+				}
+				pubRawBlockActive = true
+			}
+			if zmq[i].Type == "pubrawtx" {
+				url, err := url.Parse(zmq[i].Address)
+				if err != nil {
+					return nil, err
+				}
+				if url.Port() != zmqPubRawTxURL.Port() {
+					log.Warnf(
+						"unable to subscribe to zmq tx events on "+
+							"%s (bitcoind is running on %s)",
+						zmqPubRawTxURL.Host,
+						url.Host,
+					)
+				}
+				pubRawTxActive = true
+			}
+		}
+
+		// Return an error if raw tx or raw block notification over
+		// zmq is inactive.
+		if !pubRawBlockActive {
+			return nil, errors.New(
+				"block notification over zmq is inactive on " +
+					"bitcoind",
+			)
+		}
+		if !pubRawTxActive {
+			return nil, errors.New(
+				"tx notification over zmq is inactive on " +
+					"bitcoind",
+			)
+		}
+	}
+
+	backend.healthCheck = func() error {
+		_, err := chainConn.RawRequest(cmd, nil)
+		if err != nil {
+			return err
+		}
+
+		// On local test networks we usually don't have multiple
+		// chain backend peers, so we can skip
+		// the checkOutboundPeers test.
+		if cfg.Bitcoin.SimNet || cfg.Bitcoin.RegTest {
+			return nil
+		}
+
+		// Make sure the bitcoind chain backend maintains a
+		// healthy connection to the network by checking the
+		// number of outbound peers.
+		return checkOutboundPeers(chainConn)
+	}
+
+	backend.chainConn = chainConn
+	backend.cleanup = func() {
+		chainConn.Shutdown()
+		bitcoindConn.Stop()
+	}
+	success = true
+
+	return backend, nil
+}
+
+// newBtcdBackend creates the chain interfaces that are backed by the btcd node
+// of the given configuration.
+func newBtcdBackend(cfg *Config, btcdMode *lncfg.Btcd,
+	hintCache *channeldb.HeightHintCache) (*rpcBackend, error) {
+
+	backend := &rpcBackend{}
+
+	// We'll be speaking directly via RPC to a node.
+	//
+	// So first we'll load btcd's TLS cert for the RPC
+	// connection. If a raw cert was specified in the config, then
+	// we'll set that directly. Otherwise, we attempt to read the
+	// cert from the path specified in the config.
+	var (
+		rpcCert []byte
+		err     error
+	)
+	if btcdMode.RawRPCCert != "" {
+		rpcCert, err = hex.DecodeString(btcdMode.RawRPCCert)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		certFile, err := os.Open(btcdMode.RPCCert)
+		if err != nil {
+			return nil, err
+		}
+		rpcCert, err = io.ReadAll(certFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := certFile.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	// If the specified host for the btcd RPC server already
+	// has a port specified, then we use that directly. Otherwise,
+	// we assume the default port according to the selected chain
+	// parameters.
+	var btcdHost string
+	if strings.Contains(btcdMode.RPCHost, ":") {
+		btcdHost = btcdMode.RPCHost
+	} else {
+		btcdHost = fmt.Sprintf("%v:%v", btcdMode.RPCHost,
+			cfg.ActiveNetParams.RPCPort)
+	}
+
+	btcdUser := btcdMode.RPCUser
+	btcdPass := btcdMode.RPCPass
+	rpcConfig := &rpcclient.ConnConfig{
+		Host:                 btcdHost,
+		Endpoint:             "ws",
+		User:                 btcdUser,
+		Pass:                 btcdPass,
+		Certificates:         rpcCert,
+		DisableTLS:           false,
+		DisableConnectOnNew:  true,
+		DisableAutoReconnect: false,
+	}
+
+	chainNotifier, err := btcdnotify.New(
+		rpcConfig, cfg.ActiveNetParams.Params, hintCache,
+		hintCache, cfg.BlockCache,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	backend.chainNotifier = chainNotifier
+	backend.mempoolNotifier = chainNotifier
+
+	// Finally, we'll create an instance of the default chain view
+	// to be used within the routing layer.
+	backend.chainView, err = chainview.NewBtcdFilteredChainView(
+		*rpcConfig, cfg.BlockCache,
+	)
+	if err != nil {
+		log.Errorf("unable to create chain view: %v", err)
+		return nil, err
+	}
+
+	// Create a special websockets rpc client for btcd which will be
+	// used by the wallet for notifications, calls, etc.
+	chainRPC, err := chain.NewRPCClient(
+		cfg.ActiveNetParams.Params, btcdHost, btcdUser,
+		btcdPass, rpcCert, false, 20,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Before we continue any further, we'll ensure that the
+	// backend understands Taproot. If not, then all the default
+	// features can't be used.
+	restConfCopy := *rpcConfig
+	restConfCopy.Endpoint = ""
+	restConfCopy.HTTPPostMode = true
+	chainConn, err := rpcclient.New(&restConfCopy, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !backendSupportsTaproot(chainConn) {
+		return nil, fmt.Errorf("node backend does not " +
+			"support taproot")
+	}
+
+	backend.chainSource = chainRPC
+
+	// Use a query for our best block as a health check.
+	backend.healthCheck = func() error {
+		_, _, err := chainConn.GetBestBlock()
+		if err != nil {
+			return err
+		}
+
+		// On local test networks we usually don't have multiple
+		// chain backend peers, so we can skip
+		// the checkOutboundPeers test.
+		if cfg.Bitcoin.SimNet || cfg.Bitcoin.RegTest {
+			return nil
+		}
+
+		// Make sure the btcd chain backend maintains a
+		// healthy connection to the network by checking the
+		// number of outbound peers.
+		return checkOutboundPeers(chainConn)
+	}
+
+	// If we're not in simnet or regtest mode, then we'll attempt
+	// to use a proper fee estimator for testnet.
+	if !cfg.Bitcoin.SimNet && !cfg.Bitcoin.RegTest {
+		log.Info("Initializing btcd backed fee estimator")
+
+		// Finally, we'll re-initialize the fee estimator, as
+		// if we're using btcd as a backend, then we can use
+		// live fee estimates, rather than a statically coded
+		// value.
+		fallBackFeeRate := chainfee.SatPerKVByte(25 * 1000)
+		backend.feeEstimator, err = chainfee.NewBtcdEstimator(
+			*rpcConfig, fallBackFeeRate.FeePerKWeight(),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	backend.chainConn = chainConn
+	backend.cleanup = chainConn.Shutdown
+
+	return backend, nil
+}
+
 // NewChainControl attempts to create a ChainControl instance according
 // to the parameters in the passed configuration. Currently three
 // branches of ChainControl instances exist: one backed by a running btcd
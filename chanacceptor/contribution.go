@@ -0,0 +1,21 @@
+package chanacceptor
+
+import "github.com/btcsuite/btcd/btcutil"
+
+// ContributionPolicy decides how many satoshis we contribute to a dual funded
+// channel that a remote peer opens with us. It's only consulted if none of the
+// channel acceptors set a funding contribution in their response.
+type ContributionPolicy func(req *ChannelAcceptRequest) btcutil.Amount
+
+// MatchFundingPolicy returns a ContributionPolicy that matches the amount the
+// opener contributes to the channel, up to maxContribution. A maxContribution
+// of zero means we never contribute funds to a dual funded channel.
+func MatchFundingPolicy(maxContribution btcutil.Amount) ContributionPolicy {
+	return func(req *ChannelAcceptRequest) btcutil.Amount {
+		if req.OpenChannel2 == nil {
+			return 0
+		}
+
+		return min(req.OpenChannel2.FundingAmount, maxContribution)
+	}
+}
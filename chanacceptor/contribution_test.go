@@ -0,0 +1,36 @@
+package chanacceptor
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchFundingPolicy tests that the match funding policy contributes the
+// amount the opener contributes, capped at the configured maximum.
+func TestMatchFundingPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := MatchFundingPolicy(100_000)
+
+	// Single funded channels never get a contribution.
+	require.Zero(t, policy(&ChannelAcceptRequest{
+		OpenChanMsg: &lnwire.OpenChannel{FundingAmount: 50_000},
+	}))
+
+	dualFundReq := func(amt btcutil.Amount) *ChannelAcceptRequest {
+		return &ChannelAcceptRequest{
+			OpenChannel2: &lnwire.OpenChannel2{
+				FundingAmount: amt,
+			},
+		}
+	}
+
+	require.EqualValues(t, 50_000, policy(dualFundReq(50_000)))
+	require.EqualValues(t, 100_000, policy(dualFundReq(500_000)))
+
+	// A zero maximum disables contributions altogether.
+	require.Zero(t, MatchFundingPolicy(0)(dualFundReq(50_000)))
+}
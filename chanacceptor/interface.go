@@ -24,8 +24,14 @@ type ChannelAcceptRequest struct {
 	Node *btcec.PublicKey
 
 	// OpenChanMsg is the actual OpenChannel protocol message that the peer
-	// sent to us.
+	// sent to us. If the peer uses the dual funding protocol, this is an
+	// OpenChannel view of the OpenChannel2 message it sent.
 	OpenChanMsg *lnwire.OpenChannel
+
+	// OpenChannel2 is the OpenChannel2 message the peer sent to us if it
+	// uses the dual funding protocol. It is nil for single funded
+	// channels.
+	OpenChannel2 *lnwire.OpenChannel2
 }
 
 // ChannelAcceptResponse is a struct containing the response to a request to
@@ -66,6 +72,12 @@ type ChannelAcceptResponse struct {
 	// ZeroConf indicates that the fundee wishes to send min_depth = 0 and
 	// request a zero-conf channel with the counter-party.
 	ZeroConf bool
+
+	// FundingContribution is the amount we contribute to a dual funded
+	// channel. If it is zero, the node's default ContributionPolicy
+	// decides how much we contribute. It is ignored for single funded
+	// channels.
+	FundingContribution btcutil.Amount
 }
 
 // NewChannelAcceptResponse is a constructor for a channel accept response,
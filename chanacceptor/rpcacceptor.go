@@ -217,6 +217,8 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			MinHtlcIn:       resp.MinHtlcIn,
 			MinAcceptDepth:  resp.MinAcceptDepth,
 			ZeroConf:        resp.ZeroConf,
+
+			FundingContributionSat: resp.FundingContributionSat,
 		}
 
 		// We have received a decision for one of our channel
@@ -356,6 +358,30 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				):
 					commitmentType = lnrpc.CommitmentType_SIMPLE_TAPROOT
 
+				case channelFeatures.OnlyContains(
+					lnwire.ZeroFeeCommitmentsRequired,
+					lnwire.ZeroConfRequired,
+					lnwire.ScidAliasRequired,
+				):
+					commitmentType = lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS
+
+				case channelFeatures.OnlyContains(
+					lnwire.ZeroFeeCommitmentsRequired,
+					lnwire.ZeroConfRequired,
+				):
+					commitmentType = lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS
+
+				case channelFeatures.OnlyContains(
+					lnwire.ZeroFeeCommitmentsRequired,
+					lnwire.ScidAliasRequired,
+				):
+					commitmentType = lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS
+
+				case channelFeatures.OnlyContains(
+					lnwire.ZeroFeeCommitmentsRequired,
+				):
+					commitmentType = lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS
+
 				case channelFeatures.OnlyContains(
 					lnwire.StaticRemoteKeyRequired,
 				):
@@ -405,6 +431,7 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				CommitmentType:   commitmentType,
 				WantsZeroConf:    wantsZeroConf,
 				WantsScidAlias:   wantsScidAlias,
+				DualFund:         req.OpenChannel2 != nil,
 			}
 
 			if err := r.send(chanAcceptReq); err != nil {
@@ -435,7 +462,7 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				log.Errorf("Invalid acceptor response: %v", err)
 			}
 
-			requestInfo.response <- NewChannelAcceptResponse(
+			acceptResp := NewChannelAcceptResponse(
 				accept, acceptErr, shutdown,
 				uint16(resp.CsvDelay),
 				uint16(resp.MaxHtlcCount),
@@ -445,6 +472,11 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				lnwire.MilliSatoshi(resp.MinHtlcIn),
 				resp.ZeroConf,
 			)
+			acceptResp.FundingContribution = btcutil.Amount(
+				resp.FundingContributionSat,
+			)
+
+			requestInfo.response <- acceptResp
 
 			// Delete the channel from the acceptRequests map.
 			delete(acceptRequests, pendingID)
@@ -59,6 +59,18 @@ const (
 	// channel with a top level tapscript commitment.
 	TapscriptRootVersion = 6
 
+	// AcceptorLeaseVersion is a version that denotes this channel is using
+	// the script enforced lease commitment format of
+	// ScriptEnforcedLeaseVersion, but with the lease sold by the acceptor
+	// of the channel, so the additional CLTV requirement applies to the
+	// outputs that pay directly to the channel acceptor.
+	AcceptorLeaseVersion = 7
+
+	// ZeroFeeCommitmentsVersion is a version that denotes this channel is
+	// using version 3 commitment transactions that pay no fee and have a
+	// single pay-to-anchor output.
+	ZeroFeeCommitmentsVersion = 8
+
 	// closeTxVersionMask is the byte mask used that is ORed to version byte
 	// on wire indicating that the backup has CloseTxInputs.
 	closeTxVersionMask = 1 << 7
@@ -180,12 +192,12 @@ type Single struct {
 
 	// LeaseExpiry represents the absolute expiration as a height of the
 	// chain of a channel lease that is applied to every output that pays
-	// directly to the channel initiator in addition to the usual CSV
-	// requirement.
+	// directly to the lessor in addition to the usual CSV requirement.
 	//
 	// NOTE: This field will only be present for the following versions:
 	//
 	// - ScriptEnforcedLeaseVersion
+	// - AcceptorLeaseVersion
 	LeaseExpiry uint32
 
 	// CloseTxInputs contains data needed to produce a force close tx
@@ -311,8 +323,14 @@ func NewSingle(channel *channeldb.OpenChannel,
 
 	case channel.ChanType.HasLeaseExpiration():
 		single.Version = ScriptEnforcedLeaseVersion
+		if channel.ChanType.HasAcceptorLease() {
+			single.Version = AcceptorLeaseVersion
+		}
 		single.LeaseExpiry = channel.ThawHeight
 
+	case channel.ChanType.HasZeroFeeCommitments():
+		single.Version = ZeroFeeCommitmentsVersion
+
 	case channel.ChanType.ZeroHtlcTxFee():
 		single.Version = AnchorsZeroFeeHtlcTxCommitVersion
 
@@ -334,6 +352,13 @@ func NewSingle(channel *channeldb.OpenChannel,
 	return single
 }
 
+// hasLeaseExpiry returns true if the version of the backup is one of a leased
+// channel, which carries the expiry of the lease.
+func (s *Single) hasLeaseExpiry() bool {
+	return s.Version == ScriptEnforcedLeaseVersion ||
+		s.Version == AcceptorLeaseVersion
+}
+
 // errEmptyTapscriptRoot is returned by Serialize if field TapscriptRoot is
 // empty, when it should be filled according to the channel version.
 var errEmptyTapscriptRoot = errors.New("field TapscriptRoot is not filled")
@@ -351,6 +376,8 @@ func (s *Single) Serialize(w io.Writer) error {
 	case ScriptEnforcedLeaseVersion:
 	case SimpleTaprootVersion:
 	case TapscriptRootVersion:
+	case AcceptorLeaseVersion:
+	case ZeroFeeCommitmentsVersion:
 	default:
 		return fmt.Errorf("unable to serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -412,7 +439,7 @@ func (s *Single) Serialize(w io.Writer) error {
 	); err != nil {
 		return err
 	}
-	if s.Version == ScriptEnforcedLeaseVersion {
+	if s.hasLeaseExpiry() {
 		err := lnwire.WriteElements(&singleBytes, s.LeaseExpiry)
 		if err != nil {
 			return err
@@ -585,6 +612,8 @@ func (s *Single) Deserialize(r io.Reader) error {
 	case ScriptEnforcedLeaseVersion:
 	case SimpleTaprootVersion:
 	case TapscriptRootVersion:
+	case AcceptorLeaseVersion:
+	case ZeroFeeCommitmentsVersion:
 	default:
 		return fmt.Errorf("unable to de-serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -683,7 +712,7 @@ func (s *Single) Deserialize(r io.Reader) error {
 		return err
 	}
 
-	if s.Version == ScriptEnforcedLeaseVersion {
+	if s.hasLeaseExpiry() {
 		if err := lnwire.ReadElement(r, &s.LeaseExpiry); err != nil {
 			return err
 		}
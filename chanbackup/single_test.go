@@ -365,6 +365,20 @@ func TestSinglePackUnpack(t *testing.T) {
 			valid:   true,
 		},
 
+		// The new acceptor lease channel version should pack/unpack
+		// with no problem.
+		{
+			version: AcceptorLeaseVersion,
+			valid:   true,
+		},
+
+		// The new zero-fee commitments channel version should
+		// pack/unpack with no problem.
+		{
+			version: ZeroFeeCommitmentsVersion,
+			valid:   true,
+		},
+
 		// A non-default version, atm this should result in a failure.
 		{
 			version: 99,
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -260,6 +261,20 @@ type openChannelTlvData struct {
 	// customBlob is an optional TLV encoded blob of data representing
 	// custom channel funding information.
 	customBlob tlv.OptionalRecordT[tlv.TlvType7, tlv.Blob]
+
+	// commitParamsHistory is the optional list of commitment params that
+	// were replaced over the lifetime of the channel.
+	commitParamsHistory tlv.OptionalRecordT[
+		tlv.TlvType8, commitParamsHistoryRecord,
+	]
+
+	// spliceOutpoint is the optional outpoint of the funding output that
+	// replaced the original one through a splice.
+	spliceOutpoint tlv.OptionalRecordT[tlv.TlvType9, outPointRecord]
+
+	// spliceScid is the optional short channel ID of the funding output
+	// that replaced the original one through a splice.
+	spliceScid tlv.OptionalRecordT[tlv.TlvType10, lnwire.ShortChannelID]
 }
 
 // encode serializes the openChannelTlvData to the given io.Writer.
@@ -281,6 +296,21 @@ func (c *openChannelTlvData) encode(w io.Writer) error {
 	c.customBlob.WhenSome(func(blob tlv.RecordT[tlv.TlvType7, tlv.Blob]) {
 		tlvRecords = append(tlvRecords, blob.Record())
 	})
+	c.commitParamsHistory.WhenSome(
+		func(h tlv.RecordT[tlv.TlvType8, commitParamsHistoryRecord]) {
+			tlvRecords = append(tlvRecords, h.Record())
+		},
+	)
+	c.spliceOutpoint.WhenSome(
+		func(op tlv.RecordT[tlv.TlvType9, outPointRecord]) {
+			tlvRecords = append(tlvRecords, op.Record())
+		},
+	)
+	c.spliceScid.WhenSome(
+		func(scid tlv.RecordT[tlv.TlvType10, lnwire.ShortChannelID]) {
+			tlvRecords = append(tlvRecords, scid.Record())
+		},
+	)
 
 	// Create the tlv stream.
 	tlvStream, err := tlv.NewStream(tlvRecords...)
@@ -296,6 +326,9 @@ func (c *openChannelTlvData) decode(r io.Reader) error {
 	memo := c.memo.Zero()
 	tapscriptRoot := c.tapscriptRoot.Zero()
 	blob := c.customBlob.Zero()
+	commitParamsHistory := c.commitParamsHistory.Zero()
+	spliceOutpoint := c.spliceOutpoint.Zero()
+	spliceScid := c.spliceScid.Zero()
 
 	// Create the tlv stream.
 	tlvStream, err := tlv.NewStream(
@@ -306,6 +339,9 @@ func (c *openChannelTlvData) decode(r io.Reader) error {
 		memo.Record(),
 		tapscriptRoot.Record(),
 		blob.Record(),
+		commitParamsHistory.Record(),
+		spliceOutpoint.Record(),
+		spliceScid.Record(),
 	)
 	if err != nil {
 		return err
@@ -325,6 +361,15 @@ func (c *openChannelTlvData) decode(r io.Reader) error {
 	if _, ok := tlvs[c.customBlob.TlvType()]; ok {
 		c.customBlob = tlv.SomeRecordT(blob)
 	}
+	if _, ok := tlvs[commitParamsHistory.TlvType()]; ok {
+		c.commitParamsHistory = tlv.SomeRecordT(commitParamsHistory)
+	}
+	if _, ok := tlvs[spliceOutpoint.TlvType()]; ok {
+		c.spliceOutpoint = tlv.SomeRecordT(spliceOutpoint)
+	}
+	if _, ok := tlvs[spliceScid.TlvType()]; ok {
+		c.spliceScid = tlv.SomeRecordT(spliceScid)
+	}
 
 	return nil
 }
@@ -411,6 +456,20 @@ const (
 	// level tapscript commitment. This MUST be set along with the
 	// SimpleTaprootFeatureBit.
 	TapscriptRootBit ChannelType = 1 << 11
+
+	// AcceptorLeaseBit indicates that the lease of a leased channel was
+	// sold by the acceptor of the channel rather than its initiator, as
+	// is the case for leases bought through liquidity ads. The additional
+	// CLTV of the lease maturity then constrains the outputs that pay to
+	// the acceptor instead. This MUST be set along with the
+	// LeaseExpirationBit.
+	AcceptorLeaseBit ChannelType = 1 << 12
+
+	// ZeroFeeCommitmentsBit indicates that the channel uses zero-fee v3
+	// commitment transactions with a single pay-to-anchor output, which
+	// are only fee bumped through CPFP. This MUST be set along with the
+	// AnchorOutputsBit and ZeroHtlcTxFeeBit.
+	ZeroFeeCommitmentsBit ChannelType = 1 << 13
 )
 
 // IsSingleFunder returns true if the channel type if one of the known single
@@ -460,6 +519,20 @@ func (c ChannelType) HasLeaseExpiration() bool {
 	return c&LeaseExpirationBit == LeaseExpirationBit
 }
 
+// HasAcceptorLease returns true if the lease of a leased channel was sold by
+// the acceptor of the channel.
+func (c ChannelType) HasAcceptorLease() bool {
+	return c&AcceptorLeaseBit == AcceptorLeaseBit
+}
+
+// IsLessor returns true if the party that initiated the channel, if initiator
+// is set, or the one that accepted it otherwise, sold the lease of a leased
+// channel. The outputs paying to the lessor are constrained by the additional
+// CLTV of the lease maturity.
+func (c ChannelType) IsLessor(initiator bool) bool {
+	return c.HasLeaseExpiration() && initiator != c.HasAcceptorLease()
+}
+
 // HasZeroConf returns true if the channel is a zero-conf channel.
 func (c ChannelType) HasZeroConf() bool {
 	return c&ZeroConfBit == ZeroConfBit
@@ -487,6 +560,12 @@ func (c ChannelType) HasTapscriptRoot() bool {
 	return c&TapscriptRootBit == TapscriptRootBit
 }
 
+// HasZeroFeeCommitments returns true if the channel uses zero-fee commitment
+// transactions with a pay-to-anchor output.
+func (c ChannelType) HasZeroFeeCommitments() bool {
+	return c&ZeroFeeCommitmentsBit == ZeroFeeCommitmentsBit
+}
+
 // ChannelStateBounds are the parameters from OpenChannel and AcceptChannel
 // that are responsible for providing bounds on the state space of the abstract
 // channel state. These values must be remembered for normal channel operation
@@ -539,6 +618,32 @@ type CommitmentParams struct {
 	CsvDelay uint16
 }
 
+// CommitmentParamsChange records the commitment params of both parties and the
+// channel type that were replaced through a dynamic commitment negotiation,
+// together with the heights of the last commitments of both parties that were
+// created with them. These are required to resolve the past commitments on
+// chain.
+type CommitmentParamsChange struct {
+	// LocalHeight is the height of the last local commitment that was
+	// created with LocalParams.
+	LocalHeight uint64
+
+	// RemoteHeight is the height of the last remote commitment that was
+	// created with RemoteParams.
+	RemoteHeight uint64
+
+	// LocalParams are the commitment params of our channel config that
+	// were replaced.
+	LocalParams CommitmentParams
+
+	// RemoteParams are the commitment params of the remote party's
+	// channel config that were replaced.
+	RemoteParams CommitmentParams
+
+	// ChanType is the channel type that was replaced.
+	ChanType ChannelType
+}
+
 // ChannelConfig is a struct that houses the various configuration opens for
 // channels. Each side maintains an instance of this configuration file as it
 // governs: how the funding and commitment transaction to be created, the
@@ -1038,6 +1143,25 @@ type OpenChannel struct {
 	// immutable.
 	CustomBlob fn.Option[tlv.Blob]
 
+	// CommitParamsHistory holds the commitment params that were replaced
+	// over the lifetime of the channel, ordered from oldest to newest. The
+	// params in LocalChanCfg and RemoteChanCfg apply to all commitments
+	// created after the last change.
+	CommitParamsHistory []CommitmentParamsChange
+
+	// SpliceOutpoint is the outpoint of the funding output that currently
+	// backs the channel if the channel has been spliced since it was
+	// opened. The FundingOutpoint keeps identifying the channel in that
+	// case, but the channel's commitments spend from this outpoint.
+	SpliceOutpoint fn.Option[wire.OutPoint]
+
+	// SpliceScid is the short channel ID of the funding output that
+	// currently backs the channel if the channel has been spliced since it
+	// was opened. The ShortChannelID keeps identifying the channel within
+	// the daemon in that case, while the channel is known to the graph by
+	// this one.
+	SpliceScid fn.Option[lnwire.ShortChannelID]
+
 	// TODO(roasbeef): eww
 	Db *ChannelStateDB
 
@@ -1216,6 +1340,17 @@ func (c *OpenChannel) amendTlvData(auxData openChannelTlvData) {
 	auxData.customBlob.WhenSomeV(func(blob tlv.Blob) {
 		c.CustomBlob = fn.Some(blob)
 	})
+	auxData.commitParamsHistory.WhenSomeV(
+		func(h commitParamsHistoryRecord) {
+			c.CommitParamsHistory = h.changes
+		},
+	)
+	auxData.spliceOutpoint.WhenSomeV(func(op outPointRecord) {
+		c.SpliceOutpoint = fn.Some(op.OutPoint)
+	})
+	auxData.spliceScid.WhenSomeV(func(scid lnwire.ShortChannelID) {
+		c.SpliceScid = fn.Some(scid)
+	})
 }
 
 // extractTlvData creates a new openChannelTlvData from the given channel.
@@ -1250,10 +1385,47 @@ func (c *OpenChannel) extractTlvData() openChannelTlvData {
 			tlv.NewPrimitiveRecord[tlv.TlvType7](blob),
 		)
 	})
+	if len(c.CommitParamsHistory) != 0 {
+		auxData.commitParamsHistory = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType8](commitParamsHistoryRecord{
+				changes: c.CommitParamsHistory,
+			}),
+		)
+	}
+	c.SpliceOutpoint.WhenSome(func(op wire.OutPoint) {
+		auxData.spliceOutpoint = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType9](outPointRecord{op}),
+		)
+	})
+	c.SpliceScid.WhenSome(func(scid lnwire.ShortChannelID) {
+		auxData.spliceScid = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType10](scid),
+		)
+	})
 
 	return auxData
 }
 
+// CurrentFundingOutpoint returns the outpoint of the funding output that
+// currently backs the channel. This is the FundingOutpoint unless the channel
+// has been spliced, in which case it's the outpoint of the latest splice.
+func (c *OpenChannel) CurrentFundingOutpoint() wire.OutPoint {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.SpliceOutpoint.UnwrapOr(c.FundingOutpoint)
+}
+
+// SpliceShortChanID returns the short channel ID of the funding output that
+// currently backs the channel if the channel has been spliced since it was
+// opened.
+func (c *OpenChannel) SpliceShortChanID() fn.Option[lnwire.ShortChannelID] {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.SpliceScid
+}
+
 // Refresh updates the in-memory channel state using the latest state observed
 // on disk.
 func (c *OpenChannel) Refresh() error {
@@ -1598,6 +1770,212 @@ func (c *OpenChannel) MarkScidAliasNegotiated() error {
 	return nil
 }
 
+// UpdateChannelParams replaces the ChannelStateBounds and CommitmentParams of
+// both the local and remote channel configurations, as well as the channel
+// type, in-memory and in the database. This is used when the params are
+// renegotiated with the remote party over the lifetime of the channel, e.g.
+// through a dynamic commitment negotiation. If the commitment params or the
+// channel type change, the current ones are added to the CommitParamsHistory,
+// as they still apply to the current commitments of both parties and all
+// commitments before them.
+//
+// NOTE: This MUST only be called while neither party has a pending
+// commitment, as it would otherwise be attributed the new params.
+func (c *OpenChannel) UpdateChannelParams(localBounds,
+	remoteBounds ChannelStateBounds, localParams,
+	remoteParams CommitmentParams, chanType ChannelType) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	history := c.CommitParamsHistory
+	if localParams != c.LocalChanCfg.CommitmentParams ||
+		remoteParams != c.RemoteChanCfg.CommitmentParams ||
+		chanType != c.ChanType {
+
+		history = append(slices.Clone(history), CommitmentParamsChange{
+			LocalHeight:  c.LocalCommitment.CommitHeight,
+			RemoteHeight: c.RemoteCommitment.CommitHeight,
+			LocalParams:  c.LocalChanCfg.CommitmentParams,
+			RemoteParams: c.RemoteChanCfg.CommitmentParams,
+			ChanType:     c.ChanType,
+		})
+	}
+
+	if err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.LocalChanCfg.ChannelStateBounds = localBounds
+		channel.RemoteChanCfg.ChannelStateBounds = remoteBounds
+		channel.LocalChanCfg.CommitmentParams = localParams
+		channel.RemoteChanCfg.CommitmentParams = remoteParams
+		channel.ChanType = chanType
+		channel.CommitParamsHistory = history
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.LocalChanCfg.ChannelStateBounds = localBounds
+	c.RemoteChanCfg.ChannelStateBounds = remoteBounds
+	c.LocalChanCfg.CommitmentParams = localParams
+	c.RemoteChanCfg.CommitmentParams = remoteParams
+	c.ChanType = chanType
+	c.CommitParamsHistory = history
+
+	return nil
+}
+
+// commitParamsChangeAt returns the first change of the CommitParamsHistory
+// that applies to the commitment of the given party at the given height. None
+// is returned if the commitment was created after the last change.
+//
+// NOTE: This method requires the channel lock to be held.
+func (c *OpenChannel) commitParamsChangeAt(whoseCommit lntypes.ChannelParty,
+	height uint64) fn.Option[CommitmentParamsChange] {
+
+	for _, change := range c.CommitParamsHistory {
+		if whoseCommit.IsLocal() && height <= change.LocalHeight {
+			return fn.Some(change)
+		}
+		if whoseCommit.IsRemote() && height <= change.RemoteHeight {
+			return fn.Some(change)
+		}
+	}
+
+	return fn.None[CommitmentParamsChange]()
+}
+
+// CommitParamsAt returns the commitment params the commitment of the given
+// party at the given height was created with. These only differ from the
+// params of the party's current channel config if they were changed after the
+// commitment was created.
+func (c *OpenChannel) CommitParamsAt(whoseCommit lntypes.ChannelParty,
+	height uint64) CommitmentParams {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	current := c.LocalChanCfg.CommitmentParams
+	if whoseCommit.IsRemote() {
+		current = c.RemoteChanCfg.CommitmentParams
+	}
+
+	params := fn.MapOption(func(c CommitmentParamsChange) CommitmentParams {
+		if whoseCommit.IsLocal() {
+			return c.LocalParams
+		}
+
+		return c.RemoteParams
+	})(c.commitParamsChangeAt(whoseCommit, height))
+
+	return params.UnwrapOr(current)
+}
+
+// ChanTypeAt returns the channel type the commitment of the given party at the
+// given height was created with. This only differs from ChanType if the
+// channel type was upgraded after the commitment was created.
+func (c *OpenChannel) ChanTypeAt(whoseCommit lntypes.ChannelParty,
+	height uint64) ChannelType {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	chanType := fn.MapOption(func(c CommitmentParamsChange) ChannelType {
+		return c.ChanType
+	})(c.commitParamsChangeAt(whoseCommit, height))
+
+	return chanType.UnwrapOr(c.ChanType)
+}
+
+// RefreshChannelParams reloads the channel state bounds, commitment params,
+// channel type and their history from the database. This allows long-lived
+// copies of the channel state to pick up params that were renegotiated through
+// another copy of it.
+func (c *OpenChannel) RefreshChannelParams() error {
+	c.Lock()
+	defer c.Unlock()
+
+	var channel *OpenChannel
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err = fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+
+		return err
+	}, func() {
+		channel = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	local, remote := channel.LocalChanCfg, channel.RemoteChanCfg
+	c.LocalChanCfg.ChannelStateBounds = local.ChannelStateBounds
+	c.RemoteChanCfg.ChannelStateBounds = remote.ChannelStateBounds
+	c.LocalChanCfg.CommitmentParams = local.CommitmentParams
+	c.RemoteChanCfg.CommitmentParams = remote.CommitmentParams
+	c.ChanType = channel.ChanType
+	c.CommitParamsHistory = channel.CommitParamsHistory
+
+	return nil
+}
+
+// UpdateFundingTxn replaces the funding transaction stored for the channel,
+// in-memory and in the database. This is used for dual-funded channels, where
+// the funding transaction is only fully signed once the remote party has sent
+// the witnesses for its inputs.
+func (c *OpenChannel) UpdateFundingTxn(fundingTx *wire.MsgTx) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.FundingTxn = fundingTx
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.FundingTxn = fundingTx
+
+	return nil
+}
+
 // MarkDataLoss marks sets the channel status to LocalDataLoss and stores the
 // passed commitPoint for use to retrieve funds in case the remote force closes
 // the channel.
@@ -1834,6 +2212,27 @@ func (c *OpenChannel) ChanSyncMsg() (*lnwire.ChannelReestablish, error) {
 		nextTaprootNonce = lnwire.SomeMusig2Nonce(nextNonce.PubNonce)
 	}
 
+	// If we signed a splice transaction, but didn't receive the remote
+	// party's signatures for it yet, we'll ask for them to be
+	// retransmitted.
+	candidates, err := c.spliceCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	var nextFundingTxid tlv.OptionalRecordT[tlv.TlvType0, [32]byte]
+	for _, candidate := range candidates {
+		if candidate.FullySigned() {
+			continue
+		}
+
+		nextFundingTxid = tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType0, [32]byte](
+				candidate.Txid(),
+			),
+		)
+	}
+
 	return &lnwire.ChannelReestablish{
 		ChanID: lnwire.NewChanIDFromOutPoint(
 			c.FundingOutpoint,
@@ -1844,7 +2243,8 @@ func (c *OpenChannel) ChanSyncMsg() (*lnwire.ChannelReestablish, error) {
 		LocalUnrevokedCommitPoint: input.ComputeCommitmentPoint(
 			currentCommitSecret[:],
 		),
-		LocalNonce: nextTaprootNonce,
+		LocalNonce:      nextTaprootNonce,
+		NextFundingTxid: nextFundingTxid,
 	}, nil
 }
 
@@ -2271,11 +2671,16 @@ func syncNewChannel(tx kvdb.RwTx, c *OpenChannel, addrs []net.Addr) error {
 // occur. This method its to be called when we revoke our prior commitment
 // state.
 //
+// If the channel has pending splices, the local commitments spending their
+// funding outputs at the same height are passed as spliceCommits and stored
+// within the matching splice candidates.
+//
 // A map is returned of all the htlc resolutions that were locked in this
 // commitment. Keys correspond to htlc indices and values indicate whether the
 // htlc was settled or failed.
 func (c *OpenChannel) UpdateCommitment(newCommitment *ChannelCommitment,
-	unsignedAckedUpdates []LogUpdate) (map[uint64]bool, error) {
+	unsignedAckedUpdates []LogUpdate,
+	spliceCommits ...SpliceCommitment) (map[uint64]bool, error) {
 
 	c.Lock()
 	defer c.Unlock()
@@ -2321,6 +2726,12 @@ func (c *OpenChannel) UpdateCommitment(newCommitment *ChannelCommitment,
 				"revocations: %v", err)
 		}
 
+		err = putLocalSpliceCommitments(chanBucket, spliceCommits)
+		if err != nil {
+			return fmt.Errorf("unable to store splice "+
+				"commitments: %w", err)
+		}
+
 		// Persist unsigned but acked remote updates that need to be
 		// restored after a restart.
 		var b bytes.Buffer
@@ -2868,6 +3279,13 @@ type CommitDiff struct {
 	// settles and fails from the forwarding packages of other channels,
 	// such that they will not be reforwarded internally after a restart.
 	SettleFailAcks []SettleFailRef
+
+	// SpliceCommitments are the remote commitments at the same height
+	// that spend the funding outputs of pending splices.
+	//
+	// NOTE: This value is not serialized, the commitments are stored
+	// within their splice candidates instead.
+	SpliceCommitments []SpliceCommitment
 }
 
 // serializeLogUpdates serializes provided list of updates to a stream.
@@ -3094,6 +3512,13 @@ func (c *OpenChannel) AppendRemoteCommitChain(diff *CommitDiff) error {
 
 		// TODO(roasbeef): use seqno to derive key for later LCP
 
+		err = putRemotePendingSpliceCommitments(
+			chanBucket, diff.SpliceCommitments,
+		)
+		if err != nil {
+			return err
+		}
+
 		// With the bucket retrieved, we'll now serialize the commit
 		// diff itself, and write it to disk.
 		var b2 bytes.Buffer
@@ -3327,6 +3752,12 @@ func (c *OpenChannel) AdvanceCommitChainTail(fwdPkg *FwdPkg,
 			return err
 		}
 
+		// The pending remote commitments of any pending splices have
+		// now become current as well.
+		if err := advanceRemoteSpliceCommitments(chanBucket); err != nil {
+			return err
+		}
+
 		// With the commitment pointer swapped, we can now add the
 		// revoked (prior) state to the revocation log.
 		err = putRevocationLog(
@@ -3877,6 +4308,16 @@ func (c *OpenChannel) CloseChannel(summary *ChannelCloseSummary,
 			return err
 		}
 
+		// If the channel was spliced, its current funding outpoint no
+		// longer refers to it.
+		spliceBucket := tx.ReadWriteBucket(spliceOutpointBucket)
+		if spliceBucket != nil {
+			err := unindexSpliceOutpoint(spliceBucket, chanState)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Fetch the outpoint bucket to see if the outpoint exists or
 		// not.
 		opBucket := tx.ReadWriteBucket(outpointBucket)
@@ -4736,6 +5177,165 @@ func DKeyLocator(r io.Reader, val interface{}, buf *[8]byte, l uint64) error {
 	return tlv.NewTypeForDecodingErr(val, "keychain.KeyLocator", l, 8)
 }
 
+// commitParamsHistoryRecord is a wrapper struct around a list of
+// CommitmentParamsChange to implement the tlv.RecordProducer interface.
+type commitParamsHistoryRecord struct {
+	changes []CommitmentParamsChange
+}
+
+// commitParamsChangeSize is the encoded size of a CommitmentParamsChange: two
+// heights, a dust limit and CSV delay for each party, and the channel type.
+const commitParamsChangeSize = 8 + 8 + 2*(8+2) + 8
+
+// Record creates a Record out of the list of changes using the
+// eCommitParamsHistory and dCommitParamsHistory functions.
+//
+// NOTE: This is part of the tlv.RecordProducer interface.
+func (h *commitParamsHistoryRecord) Record() tlv.Record {
+	size := func() uint64 {
+		return uint64(len(h.changes) * commitParamsChangeSize)
+	}
+
+	// Note that we set the type here as zero, as when used with a
+	// tlv.RecordT, the type param will be used as the type.
+	return tlv.MakeDynamicRecord(
+		0, &h.changes, size, eCommitParamsHistory,
+		dCommitParamsHistory,
+	)
+}
+
+// eCommitParamsHistory is an encoder for a list of CommitmentParamsChange.
+func eCommitParamsHistory(w io.Writer, val interface{}, buf *[8]byte) error {
+	v, ok := val.(*[]CommitmentParamsChange)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(
+			val, "[]CommitmentParamsChange",
+		)
+	}
+
+	for _, change := range *v {
+		err := tlv.EUint64T(w, change.LocalHeight, buf)
+		if err != nil {
+			return err
+		}
+		err = tlv.EUint64T(w, change.RemoteHeight, buf)
+		if err != nil {
+			return err
+		}
+
+		params := []CommitmentParams{
+			change.LocalParams, change.RemoteParams,
+		}
+		for _, p := range params {
+			err := tlv.EUint64T(w, uint64(p.DustLimit), buf)
+			if err != nil {
+				return err
+			}
+			if err := tlv.EUint16T(w, p.CsvDelay, buf); err != nil {
+				return err
+			}
+		}
+
+		err = tlv.EUint64T(w, uint64(change.ChanType), buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dCommitParamsHistory is a decoder for a list of CommitmentParamsChange.
+func dCommitParamsHistory(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(*[]CommitmentParamsChange)
+	if !ok || l%commitParamsChangeSize != 0 {
+		return tlv.NewTypeForDecodingErr(
+			val, "[]CommitmentParamsChange", l,
+			commitParamsChangeSize,
+		)
+	}
+
+	changes := make(
+		[]CommitmentParamsChange, 0, l/commitParamsChangeSize,
+	)
+	for i := uint64(0); i < l/commitParamsChangeSize; i++ {
+		var change CommitmentParamsChange
+		err := tlv.DUint64(r, &change.LocalHeight, buf, 8)
+		if err != nil {
+			return err
+		}
+		err = tlv.DUint64(r, &change.RemoteHeight, buf, 8)
+		if err != nil {
+			return err
+		}
+
+		params := []*CommitmentParams{
+			&change.LocalParams, &change.RemoteParams,
+		}
+		for _, p := range params {
+			var dustLimit uint64
+			err := tlv.DUint64(r, &dustLimit, buf, 8)
+			if err != nil {
+				return err
+			}
+			p.DustLimit = btcutil.Amount(dustLimit)
+
+			err = tlv.DUint16(r, &p.CsvDelay, buf, 2)
+			if err != nil {
+				return err
+			}
+		}
+
+		var chanType uint64
+		if err := tlv.DUint64(r, &chanType, buf, 8); err != nil {
+			return err
+		}
+		change.ChanType = ChannelType(chanType)
+
+		changes = append(changes, change)
+	}
+	*v = changes
+
+	return nil
+}
+
+// outPointRecord is a wrapper struct around wire.OutPoint to implement the
+// tlv.RecordProducer interface.
+type outPointRecord struct {
+	wire.OutPoint
+}
+
+// Record creates a Record out of an OutPoint using the passed Type and the
+// EOutPoint and DOutPoint functions. The size will always be 36 as the hash is
+// 32 bytes and the index is a uint32.
+//
+// NOTE: This is part of the tlv.RecordProducer interface.
+func (o *outPointRecord) Record() tlv.Record {
+	// Note that we set the type here as zero, as when used with a
+	// tlv.RecordT, the type param will be used as the type.
+	return tlv.MakeStaticRecord(
+		0, &o.OutPoint, 36, EOutPoint, DOutPoint,
+	)
+}
+
+// EOutPoint is an encoder for wire.OutPoint.
+func EOutPoint(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*wire.OutPoint); ok {
+		return writeOutpoint(w, v)
+	}
+	return tlv.NewTypeForEncodingErr(val, "wire.OutPoint")
+}
+
+// DOutPoint is a decoder for wire.OutPoint.
+func DOutPoint(r io.Reader, val interface{}, _ *[8]byte, l uint64) error {
+	if v, ok := val.(*wire.OutPoint); ok && l == 36 {
+		return readOutpoint(r, v)
+	}
+	return tlv.NewTypeForDecodingErr(val, "wire.OutPoint", l, 36)
+}
+
 // ShutdownInfo contains various info about the shutdown initiation of a
 // channel.
 type ShutdownInfo struct {
@@ -1262,6 +1262,363 @@ func testShutdownInfo(t *testing.T, locallyInitiated bool) {
 	})
 }
 
+// TestUpdateChannelParams asserts that the channel state bounds and commitment
+// params of both parties are replaced in-memory and persisted to disk, and
+// that the replaced commitment params keep applying to past commitments.
+func TestUpdateChannelParams(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	localBounds := ChannelStateBounds{
+		ChanReserve:      btcutil.Amount(12_000),
+		MaxPendingAmount: lnwire.MilliSatoshi(500_000_000),
+		MinHTLC:          lnwire.MilliSatoshi(1_000),
+		MaxAcceptedHtlcs: 30,
+	}
+	remoteBounds := ChannelStateBounds{
+		ChanReserve:      btcutil.Amount(15_000),
+		MaxPendingAmount: lnwire.MilliSatoshi(400_000_000),
+		MinHTLC:          lnwire.MilliSatoshi(2_000),
+		MaxAcceptedHtlcs: 40,
+	}
+
+	localCommitParams := channel.LocalChanCfg.CommitmentParams
+	remoteCommitParams := channel.RemoteChanCfg.CommitmentParams
+	chanType := channel.ChanType
+	localHeight := channel.LocalCommitment.CommitHeight
+	remoteHeight := channel.RemoteCommitment.CommitHeight
+
+	// Changing only the bounds doesn't add to the history of commitment
+	// params.
+	err = channel.UpdateChannelParams(
+		localBounds, remoteBounds, localCommitParams,
+		remoteCommitParams, chanType,
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, localBounds, channel.LocalChanCfg.ChannelStateBounds)
+	require.Equal(t, remoteBounds, channel.RemoteChanCfg.ChannelStateBounds)
+	require.Empty(t, channel.CommitParamsHistory)
+
+	// Now we'll also change the commitment params of both parties.
+	newLocalParams := CommitmentParams{
+		DustLimit: localCommitParams.DustLimit + 100,
+		CsvDelay:  localCommitParams.CsvDelay + 10,
+	}
+	newRemoteParams := CommitmentParams{
+		DustLimit: remoteCommitParams.DustLimit + 200,
+		CsvDelay:  remoteCommitParams.CsvDelay + 20,
+	}
+	newChanType := chanType | AnchorOutputsBit | ZeroHtlcTxFeeBit
+	err = channel.UpdateChannelParams(
+		localBounds, remoteBounds, newLocalParams, newRemoteParams,
+		newChanType,
+	)
+	require.NoError(t, err)
+
+	assertParams := func(c *OpenChannel) {
+		t.Helper()
+
+		require.Equal(
+			t, localBounds, c.LocalChanCfg.ChannelStateBounds,
+		)
+		require.Equal(
+			t, remoteBounds, c.RemoteChanCfg.ChannelStateBounds,
+		)
+		require.Equal(
+			t, newLocalParams, c.LocalChanCfg.CommitmentParams,
+		)
+		require.Equal(
+			t, newRemoteParams, c.RemoteChanCfg.CommitmentParams,
+		)
+
+		// The current commitments and the ones before them were
+		// created with the old params, while the next ones use the
+		// new params.
+		require.Equal(
+			t, localCommitParams,
+			c.CommitParamsAt(lntypes.Local, localHeight),
+		)
+		require.Equal(
+			t, remoteCommitParams,
+			c.CommitParamsAt(lntypes.Remote, remoteHeight),
+		)
+		require.Equal(
+			t, newLocalParams,
+			c.CommitParamsAt(lntypes.Local, localHeight+1),
+		)
+		require.Equal(
+			t, newRemoteParams,
+			c.CommitParamsAt(lntypes.Remote, remoteHeight+1),
+		)
+
+		// The same applies to the channel type.
+		require.Equal(t, newChanType, c.ChanType)
+		require.Equal(
+			t, chanType, c.ChanTypeAt(lntypes.Local, localHeight),
+		)
+		require.Equal(
+			t, chanType, c.ChanTypeAt(lntypes.Remote, remoteHeight),
+		)
+		require.Equal(
+			t, newChanType,
+			c.ChanTypeAt(lntypes.Local, localHeight+1),
+		)
+		require.Equal(
+			t, newChanType,
+			c.ChanTypeAt(lntypes.Remote, remoteHeight+1),
+		)
+	}
+	assertParams(channel)
+
+	// The new params and their history should also be reflected when
+	// fetching the channel from disk.
+	channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assertParams(channels[0])
+
+	// A long-lived copy of the channel state picks up the new params once
+	// refreshed.
+	staleChannel := channels[0]
+	staleChannel.LocalChanCfg.CommitmentParams = localCommitParams
+	staleChannel.ChanType = chanType
+	staleChannel.CommitParamsHistory = nil
+	require.NoError(t, staleChannel.RefreshChannelParams())
+	assertParams(staleChannel)
+}
+
+// TestUpdateFundingTxn asserts that the funding transaction of a channel is
+// replaced in-memory and persisted to disk.
+func TestUpdateFundingTxn(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	// Attach a witness to the funding transaction, as is done once the
+	// remote party has signed its inputs of a dual-funded channel.
+	fundingTx := channel.FundingTxn.Copy()
+	fundingTx.TxIn[0].Witness = wire.TxWitness{{1, 2, 3}, {4, 5, 6}}
+
+	require.NoError(t, channel.UpdateFundingTxn(fundingTx))
+	require.Equal(t, fundingTx, channel.FundingTxn)
+
+	channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Equal(t, fundingTx, channels[0].FundingTxn)
+}
+
+// TestFundingCandidates tests that replacements of the funding transaction
+// can be stored for a pending channel, and that confirming one of them moves
+// the channel to its funding outpoint.
+func TestFundingCandidates(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb)
+	oldPoint := channel.FundingOutpoint
+
+	// Initially, there are no candidates and confirming one fails.
+	candidates, err := channel.FundingCandidates()
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	err = channel.ConfirmFundingCandidate(wire.NewMsgTx(2))
+	require.ErrorIs(t, err, ErrFundingCandidateNotFound)
+
+	newCandidate := func(lockTime uint32) *FundingCandidate {
+		fundingTx := wire.NewMsgTx(2)
+		fundingTx.LockTime = lockTime
+		fundingTx.AddTxIn(wire.NewTxIn(
+			&wire.OutPoint{Index: 1}, []byte{1}, nil,
+		))
+		fundingTx.AddTxOut(&wire.TxOut{
+			Value:    int64(channel.Capacity),
+			PkScript: []byte{2},
+		})
+
+		localCommit := channel.LocalCommitment
+		localCommit.CommitSig = []byte{byte(lockTime)}
+		remoteCommit := channel.RemoteCommitment
+		remoteCommit.CommitSig = []byte{byte(lockTime)}
+
+		return &FundingCandidate{
+			FundingTxn: fundingTx,
+			FundingOutpoint: wire.OutPoint{
+				Hash: fundingTx.TxHash(),
+			},
+			LocalCommitment:  localCommit,
+			RemoteCommitment: remoteCommit,
+		}
+	}
+
+	// Add two candidates, then add the first one again to make sure it's
+	// replaced rather than duplicated.
+	first, second := newCandidate(1), newCandidate(2)
+	require.NoError(t, channel.AddFundingCandidate(first))
+	require.NoError(t, channel.AddFundingCandidate(second))
+
+	first.FundingTxn.TxIn[0].Witness = wire.TxWitness{{1, 2, 3}}
+	require.NoError(t, channel.AddFundingCandidate(first))
+
+	candidates, err = channel.FundingCandidates()
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	require.Equal(t, second, candidates[0])
+	require.Equal(t, first, candidates[1])
+
+	// Confirm the second candidate, which should move the channel to its
+	// funding outpoint and discard all candidates.
+	require.NoError(t, channel.ConfirmFundingCandidate(second.FundingTxn))
+	require.Equal(t, second.FundingOutpoint, channel.FundingOutpoint)
+	require.Equal(t, second.LocalCommitment, channel.LocalCommitment)
+	require.Equal(t, second.RemoteCommitment, channel.RemoteCommitment)
+
+	_, err = cdb.FetchChannel(nil, oldPoint)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+
+	stored, err := cdb.FetchChannel(nil, second.FundingOutpoint)
+	require.NoError(t, err)
+	require.True(t, stored.IsPending)
+	require.Equal(t, second.LocalCommitment, stored.LocalCommitment)
+	require.Equal(t, second.RemoteCommitment, stored.RemoteCommitment)
+
+	candidates, err = stored.FundingCandidates()
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	// The channel can still be opened at its new funding outpoint.
+	require.NoError(t, stored.MarkAsOpen(stored.ShortChannelID))
+}
+
+// TestSpliceCandidates tests that splice candidates can be stored for a
+// channel and that locking one of them replaces the funding outpoint,
+// capacity and commitments of the channel.
+func TestSpliceCandidates(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+	require.Equal(t, channel.FundingOutpoint, channel.CurrentFundingOutpoint())
+
+	// Initially, there are no candidates and locking a splice fails.
+	candidates, err := channel.SpliceCandidates()
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	spliceScid := lnwire.NewShortChanIDFromInt(0xdeadbeef)
+	_, err = channel.LockSplice(chainhash.Hash{1}, spliceScid)
+	require.ErrorIs(t, err, ErrSpliceCandidateNotFound)
+
+	newCandidate := func(lockTime uint32) *SpliceCandidate {
+		spliceTx := wire.NewMsgTx(2)
+		spliceTx.LockTime = lockTime
+		spliceTx.AddTxIn(wire.NewTxIn(
+			&channel.FundingOutpoint, []byte{1}, nil,
+		))
+		spliceTx.AddTxOut(&wire.TxOut{
+			Value:    20_000_000,
+			PkScript: []byte{2},
+		})
+
+		localCommit := channel.LocalCommitment
+		localCommit.LocalBalance += 5_000_000_000
+		remoteCommit := channel.RemoteCommitment
+		remoteCommit.RemoteBalance += 5_000_000_000
+
+		return &SpliceCandidate{
+			SpliceTx: spliceTx,
+			FundingOutpoint: wire.OutPoint{
+				Hash: spliceTx.TxHash(),
+			},
+			Capacity:         channel.Capacity + 5_000_000,
+			LocalCommitment:  localCommit,
+			RemoteCommitment: remoteCommit,
+		}
+	}
+
+	// Add two candidates, then add the first one again to make sure it's
+	// replaced rather than duplicated.
+	first, second := newCandidate(1), newCandidate(2)
+	require.NoError(t, channel.AddSpliceCandidate(first))
+	require.NoError(t, channel.AddSpliceCandidate(second))
+
+	first.SpliceTx.TxIn[0].Witness = wire.TxWitness{{1, 2, 3}}
+	require.NoError(t, channel.AddSpliceCandidate(first))
+
+	candidates, err = channel.SpliceCandidates()
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	require.Equal(t, second, candidates[0])
+	require.Equal(t, first, candidates[1])
+
+	// Lock the second candidate, which should update the channel and
+	// discard all candidates.
+	locked, err := channel.LockSplice(second.Txid(), spliceScid)
+	require.NoError(t, err)
+	require.Equal(t, second, locked)
+
+	candidates, err = channel.SpliceCandidates()
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+
+	for _, c := range []*OpenChannel{channel, channels[0]} {
+		require.Equal(
+			t, second.FundingOutpoint, c.CurrentFundingOutpoint(),
+		)
+		require.Equal(t, second.Capacity, c.Capacity)
+		require.Equal(t, second.LocalCommitment, c.LocalCommitment)
+		require.Equal(t, second.RemoteCommitment, c.RemoteCommitment)
+		require.Equal(t, fn.Some(spliceScid), c.SpliceShortChanID())
+	}
+
+	// The channel is still identified by its original funding outpoint,
+	// but can be looked up by the new one as well.
+	require.Equal(t, channel.FundingOutpoint, channels[0].FundingOutpoint)
+
+	spliced, err := cdb.FetchChannel(nil, second.FundingOutpoint)
+	require.NoError(t, err)
+	require.Equal(t, channel.FundingOutpoint, spliced.FundingOutpoint)
+
+	_, err = cdb.FetchChannel(nil, first.FundingOutpoint)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+
+	// Once the channel is closed, the new funding outpoint doesn't refer
+	// to it anymore.
+	err = channel.CloseChannel(&ChannelCloseSummary{
+		ChanPoint: channel.FundingOutpoint,
+		RemotePub: channel.IdentityPub,
+	})
+	require.NoError(t, err)
+
+	_, err = cdb.FetchChannel(nil, second.FundingOutpoint)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+}
+
 // TestRefresh asserts that Refresh updates the in-memory state of another
 // OpenChannel to reflect a preceding call to MarkOpen on a different
 // OpenChannel.
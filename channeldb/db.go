@@ -457,6 +457,7 @@ var dbTopLevelBuckets = [][]byte{
 	outpointBucket,
 	chanIDBucket,
 	historicalChannelBucket,
+	spliceOutpointBucket,
 }
 
 // Wipe completely deletes all saved state within all used buckets within the
@@ -669,11 +670,41 @@ func (c *ChannelStateDB) fetchNodeChannels(chainBucket kvdb.RBucket) (
 }
 
 // FetchChannel attempts to locate a channel specified by the passed channel
-// point. If the channel cannot be found, then an error will be returned.
-// Optionally an existing db tx can be supplied.
+// point. A spliced channel is found by the funding outpoint of its latest
+// splice as well. If the channel cannot be found, then an error will be
+// returned. Optionally an existing db tx can be supplied.
 func (c *ChannelStateDB) FetchChannel(tx kvdb.RTx, chanPoint wire.OutPoint) (
 	*OpenChannel, error) {
 
+	channel, err := c.fetchChannel(tx, chanPoint)
+	if !errors.Is(err, ErrChannelNotFound) {
+		return channel, err
+	}
+
+	// The channel might have been spliced, in which case we'll look it up
+	// by the funding outpoint it was opened with.
+	var origChanPoint wire.OutPoint
+	fetchChanPoint := func(tx kvdb.RTx) error {
+		origChanPoint, err = fetchSplicedChanPoint(tx, chanPoint)
+		return err
+	}
+	if tx == nil {
+		err = kvdb.View(c.backend, fetchChanPoint, func() {})
+	} else {
+		err = fetchChanPoint(tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchChannel(tx, origChanPoint)
+}
+
+// fetchChannel attempts to locate a channel by the funding outpoint it was
+// opened with.
+func (c *ChannelStateDB) fetchChannel(tx kvdb.RTx, chanPoint wire.OutPoint) (
+	*OpenChannel, error) {
+
 	var targetChanPoint bytes.Buffer
 	if err := writeOutpoint(&targetChanPoint, &chanPoint); err != nil {
 		return nil, err
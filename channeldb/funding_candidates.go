@@ -0,0 +1,338 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// fundingCandidatesKey is the key within a pending channel's bucket
+	// under which the replacements of its funding transaction are
+	// stored.
+	fundingCandidatesKey = []byte("funding-candidates-key")
+
+	// ErrFundingCandidateNotFound is returned when a funding transaction
+	// confirms that doesn't match any of the stored funding candidates.
+	ErrFundingCandidateNotFound = errors.New("funding candidate not found")
+)
+
+// FundingCandidate is a replacement of the funding transaction of a pending
+// dual-funded channel, which was negotiated with tx_init_rbf. It spends some
+// of the inputs of the funding transaction it replaces, so at most one of them
+// confirms. The channel's balances don't change with the replacement, so its
+// commitments only differ from the ones of the channel by the funding outpoint
+// they spend and the signatures for them.
+type FundingCandidate struct {
+	// FundingTxn is the replacement funding transaction. It only carries
+	// the witnesses of our inputs until the remote party sent the ones of
+	// its inputs.
+	FundingTxn *wire.MsgTx
+
+	// FundingOutpoint is the funding outpoint created by FundingTxn.
+	FundingOutpoint wire.OutPoint
+
+	// LocalCommitment is our initial commitment spending FundingOutpoint.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the remote party's initial commitment spending
+	// FundingOutpoint.
+	RemoteCommitment ChannelCommitment
+}
+
+// Txid returns the txid of the replacement funding transaction.
+func (f *FundingCandidate) Txid() chainhash.Hash {
+	return f.FundingTxn.TxHash()
+}
+
+// serializeFundingCandidates serializes the given funding candidates.
+func serializeFundingCandidates(w io.Writer,
+	candidates []*FundingCandidate) error {
+
+	if err := WriteElement(w, uint16(len(candidates))); err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		err := WriteElements(w, c.FundingTxn, c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		err = serializeCommitWithAuxData(w, &c.LocalCommitment)
+		if err != nil {
+			return err
+		}
+		err = serializeCommitWithAuxData(w, &c.RemoteCommitment)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeFundingCandidates reads funding candidates that were serialized
+// using serializeFundingCandidates.
+func deserializeFundingCandidates(r io.Reader) ([]*FundingCandidate, error) {
+	var numCandidates uint16
+	if err := ReadElement(r, &numCandidates); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*FundingCandidate, 0, numCandidates)
+	for i := uint16(0); i < numCandidates; i++ {
+		c := &FundingCandidate{}
+		err := ReadElements(r, &c.FundingTxn, &c.FundingOutpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		c.LocalCommitment, err = deserializeCommitWithAuxData(r)
+		if err != nil {
+			return nil, err
+		}
+		c.RemoteCommitment, err = deserializeCommitWithAuxData(r)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// fetchFundingCandidates returns the funding candidates stored in the given
+// channel bucket.
+func fetchFundingCandidates(chanBucket kvdb.RBucket) ([]*FundingCandidate,
+	error) {
+
+	candidateBytes := chanBucket.Get(fundingCandidatesKey)
+	if candidateBytes == nil {
+		return nil, nil
+	}
+
+	return deserializeFundingCandidates(bytes.NewReader(candidateBytes))
+}
+
+// AddFundingCandidate persists a replacement of the funding transaction of
+// the pending channel. If a candidate with the same txid already exists, it's
+// replaced. This is used to update the stored transaction once it has been
+// fully signed.
+func (c *OpenChannel) AddFundingCandidate(candidate *FundingCandidate) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.IsPending {
+		return fmt.Errorf("channel %v is not pending", c.FundingOutpoint)
+	}
+
+	txid := candidate.Txid()
+
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := fetchFundingCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		candidates = fn.Filter(func(f *FundingCandidate) bool {
+			return f.Txid() != txid
+		}, candidates)
+		candidates = append(candidates, candidate)
+
+		var b bytes.Buffer
+		err = serializeFundingCandidates(&b, candidates)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(fundingCandidatesKey, b.Bytes())
+	}, func() {})
+}
+
+// FundingCandidates returns all replacements of the funding transaction of the
+// pending channel.
+func (c *OpenChannel) FundingCandidates() ([]*FundingCandidate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var candidates []*FundingCandidate
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err = fetchFundingCandidates(chanBucket)
+
+		return err
+	}, func() {
+		candidates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// copyBucket copies all keys and nested buckets of src into dst.
+func copyBucket(dst kvdb.RwBucket, src kvdb.RBucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+
+		nestedDst, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(nestedDst, src.NestedReadBucket(k))
+	})
+}
+
+// ConfirmFundingCandidate makes the passed replacement of the funding
+// transaction, which confirmed, the funding transaction of the pending
+// channel. As channels are stored under their funding outpoint, the channel is
+// moved to the outpoint of the candidate, and its commitments are replaced
+// with the ones of the candidate. All other candidates are discarded.
+func (c *OpenChannel) ConfirmFundingCandidate(fundingTx *wire.MsgTx) error {
+	c.Lock()
+	defer c.Unlock()
+
+	txid := fundingTx.TxHash()
+
+	var candidate *FundingCandidate
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		opBucket := tx.ReadWriteBucket(outpointBucket)
+		if opBucket == nil {
+			return ErrNoChanDBExists
+		}
+		cidBucket := tx.ReadWriteBucket(chanIDBucket)
+		if cidBucket == nil {
+			return ErrNoChanDBExists
+		}
+
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := fetchFundingCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		candidate, err = fn.Find(func(f *FundingCandidate) bool {
+			return f.Txid() == txid
+		}, candidates).UnwrapOrErr(ErrFundingCandidateNotFound)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+		if !channel.IsPending {
+			return fmt.Errorf("channel %v is not pending",
+				c.FundingOutpoint)
+		}
+
+		var oldKey, newKey bytes.Buffer
+		if err := writeOutpoint(&oldKey, &c.FundingOutpoint); err != nil {
+			return err
+		}
+		err = writeOutpoint(&newKey, &candidate.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		// The outpoint and channel ID indexes are moved over to the
+		// new funding outpoint.
+		status := opBucket.Get(oldKey.Bytes())
+		if status == nil {
+			return ErrChannelNotFound
+		}
+		if err := opBucket.Put(newKey.Bytes(), status); err != nil {
+			return err
+		}
+		if err := opBucket.Delete(oldKey.Bytes()); err != nil {
+			return err
+		}
+
+		oldCid := lnwire.NewChanIDFromOutPoint(c.FundingOutpoint)
+		newCid := lnwire.NewChanIDFromOutPoint(
+			candidate.FundingOutpoint,
+		)
+		if err := cidBucket.Put(newCid[:], []byte{}); err != nil {
+			return err
+		}
+		if err := cidBucket.Delete(oldCid[:]); err != nil {
+			return err
+		}
+
+		// Then, the channel bucket is copied to the new funding
+		// outpoint, without the candidates, and the old one deleted.
+		openChanBucket := tx.ReadWriteBucket(openChannelBucket)
+		chainBucket := openChanBucket.NestedReadWriteBucket(
+			c.IdentityPub.SerializeCompressed(),
+		).NestedReadWriteBucket(c.ChainHash[:])
+
+		newBucket, err := chainBucket.CreateBucket(newKey.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := copyBucket(newBucket, chanBucket); err != nil {
+			return err
+		}
+		if err := newBucket.Delete(fundingCandidatesKey); err != nil {
+			return err
+		}
+		err = chainBucket.DeleteNestedBucket(oldKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		channel.FundingOutpoint = candidate.FundingOutpoint
+		channel.FundingTxn = fundingTx
+		channel.LocalCommitment = candidate.LocalCommitment
+		channel.RemoteCommitment = candidate.RemoteCommitment
+
+		return putOpenChannel(newBucket, channel)
+	}, func() {
+		candidate = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.FundingOutpoint = candidate.FundingOutpoint
+	c.FundingTxn = fundingTx
+	c.LocalCommitment = candidate.LocalCommitment
+	c.RemoteCommitment = candidate.RemoteCommitment
+
+	return nil
+}
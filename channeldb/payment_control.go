@@ -227,6 +227,21 @@ func (p *PaymentControl) InitPayment(paymentHash lntypes.Hash,
 			return err
 		}
 
+		// The label and the mission control namespace are stored next
+		// to the creation info, replacing those of a previous attempt.
+		err = putPaymentValue(bucket, paymentLabelKey, info.Label)
+		if err != nil {
+			return err
+		}
+
+		err = putPaymentValue(
+			bucket, paymentMCNamespaceKey,
+			info.MissionControlNamespace,
+		)
+		if err != nil {
+			return err
+		}
+
 		// We'll delete any lingering HTLCs to start with, in case we
 		// are initializing a payment that was attempted earlier, but
 		// left in a state where we could retry.
@@ -282,6 +297,16 @@ func createPaymentIndexEntry(tx kvdb.RwTx, sequenceNumber []byte,
 	return indexes.Put(sequenceNumber, b.Bytes())
 }
 
+// putPaymentValue stores the given optional value of a payment under the key
+// in its bucket. An empty value deletes the key.
+func putPaymentValue(bucket kvdb.RwBucket, key []byte, value string) error {
+	if value == "" {
+		return bucket.Delete(key)
+	}
+
+	return bucket.Put(key, []byte(value))
+}
+
 // deserializePaymentIndex deserializes a payment index entry. This function
 // currently only supports deserialization of payment hash indexes, and will
 // fail for other types.
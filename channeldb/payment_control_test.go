@@ -1020,6 +1020,56 @@ func TestPaymentControlMPPRecordValidation(t *testing.T) {
 	}
 }
 
+// TestPaymentControlInfoKeys checks that the optional values of a payment that
+// older versions don't know are stored apart from its creation info, and that
+// they're replaced when the payment is retried.
+func TestPaymentControlInfoKeys(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to init db")
+
+	pControl := NewPaymentControl(db)
+
+	info, _, _, err := genInfo()
+	require.NoError(t, err)
+	info.Label = "rebalance"
+	info.MissionControlNamespace = "probing"
+
+	err = pControl.InitPayment(info.PaymentIdentifier, info)
+	require.NoError(t, err)
+	assertPaymentInfo(
+		t, pControl, info.PaymentIdentifier, info, nil, nil,
+	)
+
+	// The creation info doesn't carry any TLV records, so older versions
+	// can still read it.
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		bucket, err := fetchPaymentBucket(tx, info.PaymentIdentifier)
+		require.NoError(t, err)
+
+		infoBytes := bucket.Get(paymentCreationInfoKey)
+		baseLength := 32 + 8 + 8 + 4 + len(info.PaymentRequest)
+		require.Len(t, infoBytes, baseLength)
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// A retry of the failed payment without a label and namespace removes
+	// them.
+	_, err = pControl.Fail(info.PaymentIdentifier, FailureReasonNoRoute)
+	require.NoError(t, err)
+
+	info.Label = ""
+	info.MissionControlNamespace = ""
+	err = pControl.InitPayment(info.PaymentIdentifier, info)
+	require.NoError(t, err)
+	assertPaymentInfo(
+		t, pControl, info.PaymentIdentifier, info, nil, nil,
+	)
+}
+
 // TestDeleteFailedAttempts checks that DeleteFailedAttempts properly removes
 // failed HTLCs from finished payments.
 func TestDeleteFailedAttempts(t *testing.T) {
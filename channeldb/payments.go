@@ -71,6 +71,16 @@ var (
 	// store the creation info of the payment.
 	paymentCreationInfoKey = []byte("payment-creation-info")
 
+	// paymentLabelKey is a key used in the payment's sub-bucket to store
+	// the optional label of the payment. It's kept apart from the creation
+	// info, as older versions reject unknown records in its TLV stream.
+	paymentLabelKey = []byte("payment-label")
+
+	// paymentMCNamespaceKey is a key used in the payment's sub-bucket to
+	// store the optional mission control namespace of the payment. Like
+	// the label, it's kept apart from the creation info.
+	paymentMCNamespaceKey = []byte("payment-mc-namespace")
+
 	// paymentHtlcsBucket is a bucket where we'll store the information
 	// about the HTLCs that were attempted for a payment.
 	paymentHtlcsBucket = []byte("payment-htlcs-bucket")
@@ -200,6 +210,14 @@ type PaymentCreationInfo struct {
 	// first hop of this payment. These records will be transmitted via the
 	// wire message only and therefore do not affect the onion payload size.
 	FirstHopCustomRecords lnwire.CustomRecords
+
+	// Label is an optional label of the payment. It marks payments that
+	// are made by internal services, such as circular rebalances.
+	Label string
+
+	// MissionControlNamespace is the mission control namespace that the
+	// payment uses. If empty, the default namespace is used.
+	MissionControlNamespace string
 }
 
 // htlcBucketKey creates a composite key from prefix and id where the result is
@@ -273,7 +291,15 @@ func fetchCreationInfo(bucket kvdb.RBucket) (*PaymentCreationInfo, error) {
 	}
 
 	r := bytes.NewReader(b)
-	return deserializePaymentCreationInfo(r)
+	c, err := deserializePaymentCreationInfo(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Label = string(bucket.Get(paymentLabelKey))
+	c.MissionControlNamespace = string(bucket.Get(paymentMCNamespaceKey))
+
+	return c, nil
 }
 
 func fetchPayment(bucket kvdb.RBucket) (*MPPayment, error) {
@@ -1025,10 +1051,10 @@ func serializePaymentCreationInfo(w io.Writer, c *PaymentCreationInfo) error {
 		return err
 	}
 
-	// Any remaining bytes are TLV encoded records. Currently, these are
-	// only the custom records provided by the user to be sent to the first
-	// hop. But this can easily be extended with further records by merging
-	// the records into a single TLV stream.
+	// Any remaining bytes are TLV encoded records. These are only the
+	// custom records provided by the user to be sent to the first hop, as
+	// older versions reject any other records. Further values of the
+	// payment are stored under their own keys in the payment bucket.
 	err := c.FirstHopCustomRecords.SerializeTo(w)
 	if err != nil {
 		return err
@@ -1072,10 +1098,8 @@ func deserializePaymentCreationInfo(r io.Reader) (*PaymentCreationInfo,
 	}
 	c.PaymentRequest = payReq
 
-	// Any remaining bytes are TLV encoded records. Currently, these are
-	// only the custom records provided by the user to be sent to the first
-	// hop. But this can easily be extended with further records by merging
-	// the records into a single TLV stream.
+	// Any remaining bytes are TLV encoded records. These are only the
+	// custom records provided by the user to be sent to the first hop.
 	c.FirstHopCustomRecords, err = lnwire.ParseCustomRecordsFrom(r)
 	if err != nil {
 		return nil, err
@@ -1242,6 +1266,12 @@ func serializeHop(w io.Writer, h *route.Hop) error {
 		)
 	}
 
+	if h.TrampolineOnion != nil {
+		records = append(records, record.NewTrampolineOnionRecord(
+			&h.TrampolineOnion,
+		))
+	}
+
 	// Final sanity check to absolutely rule out custom records that are not
 	// custom and write into the standard range.
 	if err := h.CustomRecords.Validate(); err != nil {
@@ -1420,6 +1450,13 @@ func deserializeHop(r io.Reader) (*route.Hop, error) {
 		h.TotalAmtMsat = lnwire.MilliSatoshi(totalAmtMsatInt)
 	}
 
+	trampolineType := uint64(record.TrampolineOnionType)
+	if onion, ok := tlvMap[trampolineType]; ok {
+		delete(tlvMap, trampolineType)
+
+		h.TrampolineOnion = onion
+	}
+
 	h.CustomRecords = tlvMap
 
 	return h, nil
@@ -34,8 +34,9 @@ var (
 			65536: []byte{},
 			80001: []byte{},
 		},
-		MPP:      record.NewMPP(32, [32]byte{0x42}),
-		Metadata: []byte{1, 2, 3},
+		MPP:             record.NewMPP(32, [32]byte{0x42}),
+		Metadata:        []byte{1, 2, 3},
+		TrampolineOnion: []byte{4, 5, 6},
 	}
 
 	testHop2 = &route.Hop{
@@ -150,6 +151,21 @@ func TestSentPaymentSerialization(t *testing.T) {
 	require.NoError(t, err, "deserialize")
 	require.Equal(t, c, newCreationInfo)
 
+	// The label and the mission control namespace are stored apart from
+	// the creation info.
+	b.Reset()
+	c.Label = "rebalance"
+	c.MissionControlNamespace = "probing"
+	require.NoError(t, serializePaymentCreationInfo(&b, c), "serialize")
+
+	newCreationInfo, err = deserializePaymentCreationInfo(&b)
+	require.NoError(t, err, "deserialize")
+	require.Empty(t, newCreationInfo.Label)
+	require.Empty(t, newCreationInfo.MissionControlNamespace)
+	c.Label = ""
+	c.MissionControlNamespace = ""
+	require.Equal(t, c, newCreationInfo)
+
 	b.Reset()
 	require.NoError(t, serializeHTLCAttemptInfo(&b, s), "serialize")
 
@@ -0,0 +1,672 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// spliceCandidatesKey is the key within a channel's bucket under which
+	// the splice transactions that have been negotiated for the channel
+	// but haven't confirmed yet are stored.
+	spliceCandidatesKey = []byte("splice-candidates-key")
+
+	// spliceOutpointBucket maps the funding outpoints of spliced channels
+	// to the funding outpoints they were opened with, which keep
+	// identifying the channels.
+	//
+	// spliceOutpoint -> chanPoint
+	spliceOutpointBucket = []byte("splice-outpoint-bucket")
+
+	// ErrSpliceCandidateNotFound is returned when a splice transaction is
+	// locked that doesn't match any of the stored splice candidates.
+	ErrSpliceCandidateNotFound = errors.New("splice candidate not found")
+)
+
+// SpliceCandidate is a splice transaction that has been negotiated for a
+// channel, but hasn't been locked yet. Along with the transaction itself, it
+// holds the latest commitments of both parties that spend the new funding
+// output. Until the splice is locked, every new commitment of the channel is
+// signed for the new funding output as well, so these commitments are kept
+// up to date with the commitments of the channel. Once the splice is locked,
+// they replace the current commitments of the channel.
+type SpliceCandidate struct {
+	// SpliceTx is the splice transaction. This is fully signed by the
+	// party that publishes it, the other party may only store the unsigned
+	// transaction.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the outpoint of the new funding output created by
+	// the splice transaction.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel after the splice, which is
+	// the value of the new funding output.
+	Capacity btcutil.Amount
+
+	// LocalDelta is the amount our balance changes by with the splice.
+	LocalDelta btcutil.Amount
+
+	// RemoteDelta is the amount the balance of the remote party changes
+	// by with the splice.
+	RemoteDelta btcutil.Amount
+
+	// LocalCommitment is our latest commitment spending the new funding
+	// output.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the remote party's latest revocable commitment
+	// spending the new funding output.
+	RemoteCommitment ChannelCommitment
+
+	// RemotePendingCommitment is the remote party's commitment spending
+	// the new funding output that we signed, but which hasn't been
+	// revoked yet. It is only set while the remote party owes us a
+	// revocation.
+	RemotePendingCommitment *ChannelCommitment
+
+	// RemotePendingCommitSig is the commit_sig message we sent for
+	// RemotePendingCommitment. It is retransmitted along with the commit
+	// diff of the channel if the remote party didn't receive it.
+	RemotePendingCommitSig *lnwire.CommitSig
+
+	// LocalTxSigs is the tx_signatures message we sent for SpliceTx. It is
+	// retransmitted if the remote party didn't receive it before a
+	// disconnection.
+	LocalTxSigs *lnwire.TxSignatures
+}
+
+// SpliceCommitment is a commitment spending the funding output of a pending
+// splice. It is created alongside each commitment of the channel until the
+// splice is locked.
+type SpliceCommitment struct {
+	// SpliceTxid is the txid of the splice transaction.
+	SpliceTxid chainhash.Hash
+
+	// Commitment is the commitment spending the new funding output.
+	Commitment ChannelCommitment
+
+	// CommitSig is the commit_sig message we sent for the commitment. It
+	// is only set for commitments of the remote party.
+	CommitSig *lnwire.CommitSig
+}
+
+// Txid returns the txid of the splice transaction.
+func (s *SpliceCandidate) Txid() chainhash.Hash {
+	return s.SpliceTx.TxHash()
+}
+
+// FullySigned returns true if all inputs of the splice transaction carry a
+// witness, which is only the case once we received the remote party's
+// tx_signatures.
+func (s *SpliceCandidate) FullySigned() bool {
+	for _, txIn := range s.SpliceTx.TxIn {
+		if len(txIn.Witness) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// serializeCommitWithAuxData serializes the given commitment, including its
+// auxiliary TLV data, as a length prefixed byte slice.
+func serializeCommitWithAuxData(w io.Writer, c *ChannelCommitment) error {
+	var b bytes.Buffer
+	if err := serializeChanCommit(&b, c); err != nil {
+		return err
+	}
+
+	auxData := c.extractTlvData()
+	if err := auxData.encode(&b); err != nil {
+		return fmt.Errorf("unable to write aux data: %w", err)
+	}
+
+	return WriteElement(w, b.Bytes())
+}
+
+// deserializeCommitWithAuxData reads a commitment that was serialized using
+// serializeCommitWithAuxData.
+func deserializeCommitWithAuxData(r io.Reader) (ChannelCommitment, error) {
+	var commitBytes []byte
+	if err := ReadElement(r, &commitBytes); err != nil {
+		return ChannelCommitment{}, err
+	}
+
+	commitReader := bytes.NewReader(commitBytes)
+	chanCommit, err := deserializeChanCommit(commitReader)
+	if err != nil {
+		return ChannelCommitment{}, err
+	}
+
+	var auxData commitTlvData
+	if err := auxData.decode(commitReader); err != nil {
+		return ChannelCommitment{}, fmt.Errorf("unable to decode "+
+			"chan aux data: %w", err)
+	}
+	chanCommit.amendTlvData(auxData)
+
+	return chanCommit, nil
+}
+
+// serializeSpliceCandidates serializes the given splice candidates.
+func serializeSpliceCandidates(w io.Writer,
+	candidates []*SpliceCandidate) error {
+
+	if err := WriteElement(w, uint16(len(candidates))); err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		err := WriteElements(
+			w, c.SpliceTx, c.FundingOutpoint, c.Capacity,
+			c.LocalDelta, c.RemoteDelta,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = serializeCommitWithAuxData(w, &c.LocalCommitment)
+		if err != nil {
+			return err
+		}
+		err = serializeCommitWithAuxData(w, &c.RemoteCommitment)
+		if err != nil {
+			return err
+		}
+
+		hasTxSigs := c.LocalTxSigs != nil
+		if err := WriteElement(w, hasTxSigs); err != nil {
+			return err
+		}
+		if hasTxSigs {
+			if err := WriteElement(w, c.LocalTxSigs); err != nil {
+				return err
+			}
+		}
+
+		hasPending := c.RemotePendingCommitment != nil
+		if err := WriteElement(w, hasPending); err != nil {
+			return err
+		}
+		if !hasPending {
+			continue
+		}
+
+		err = serializeCommitWithAuxData(w, c.RemotePendingCommitment)
+		if err != nil {
+			return err
+		}
+		if err := WriteElement(w, c.RemotePendingCommitSig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeSpliceCandidates reads splice candidates that were serialized
+// using serializeSpliceCandidates.
+func deserializeSpliceCandidates(r io.Reader) ([]*SpliceCandidate, error) {
+	var numCandidates uint16
+	if err := ReadElement(r, &numCandidates); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*SpliceCandidate, 0, numCandidates)
+	for i := uint16(0); i < numCandidates; i++ {
+		c := &SpliceCandidate{}
+		err := ReadElements(
+			r, &c.SpliceTx, &c.FundingOutpoint, &c.Capacity,
+			&c.LocalDelta, &c.RemoteDelta,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.LocalCommitment, err = deserializeCommitWithAuxData(r)
+		if err != nil {
+			return nil, err
+		}
+		c.RemoteCommitment, err = deserializeCommitWithAuxData(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var hasTxSigs bool
+		if err := ReadElement(r, &hasTxSigs); err != nil {
+			return nil, err
+		}
+		if hasTxSigs {
+			var msg lnwire.Message
+			if err := ReadElement(r, &msg); err != nil {
+				return nil, err
+			}
+			txSigs, ok := msg.(*lnwire.TxSignatures)
+			if !ok {
+				return nil, fmt.Errorf("expected "+
+					"lnwire.TxSignatures, instead read: %T",
+					msg)
+			}
+			c.LocalTxSigs = txSigs
+		}
+
+		var hasPending bool
+		if err := ReadElement(r, &hasPending); err != nil {
+			return nil, err
+		}
+		if hasPending {
+			pending, err := deserializeCommitWithAuxData(r)
+			if err != nil {
+				return nil, err
+			}
+			c.RemotePendingCommitment = &pending
+
+			var msg lnwire.Message
+			if err := ReadElement(r, &msg); err != nil {
+				return nil, err
+			}
+			commitSig, ok := msg.(*lnwire.CommitSig)
+			if !ok {
+				return nil, fmt.Errorf("expected "+
+					"lnwire.CommitSig, instead read: %T",
+					msg)
+			}
+			c.RemotePendingCommitSig = commitSig
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// fetchSpliceCandidates returns the splice candidates stored in the given
+// channel bucket.
+func fetchSpliceCandidates(chanBucket kvdb.RBucket) ([]*SpliceCandidate,
+	error) {
+
+	candidateBytes := chanBucket.Get(spliceCandidatesKey)
+	if candidateBytes == nil {
+		return nil, nil
+	}
+
+	return deserializeSpliceCandidates(bytes.NewReader(candidateBytes))
+}
+
+// putSpliceCandidates stores the given splice candidates in the given channel
+// bucket, replacing any existing ones.
+func putSpliceCandidates(chanBucket kvdb.RwBucket,
+	candidates []*SpliceCandidate) error {
+
+	if len(candidates) == 0 {
+		return chanBucket.Delete(spliceCandidatesKey)
+	}
+
+	var b bytes.Buffer
+	if err := serializeSpliceCandidates(&b, candidates); err != nil {
+		return err
+	}
+
+	return chanBucket.Put(spliceCandidatesKey, b.Bytes())
+}
+
+// updateSpliceCandidates applies the passed function to all splice candidates
+// stored in the given channel bucket and stores the result. Nothing is
+// written if the channel doesn't have any splice candidates.
+func updateSpliceCandidates(chanBucket kvdb.RwBucket,
+	update func(*SpliceCandidate)) error {
+
+	candidates, err := fetchSpliceCandidates(chanBucket)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	for _, c := range candidates {
+		update(c)
+	}
+
+	return putSpliceCandidates(chanBucket, candidates)
+}
+
+// putLocalSpliceCommitments stores the given local commitments, which spend
+// the funding outputs of pending splices, within their splice candidates.
+func putLocalSpliceCommitments(chanBucket kvdb.RwBucket,
+	commits []SpliceCommitment) error {
+
+	if len(commits) == 0 {
+		return nil
+	}
+
+	return updateSpliceCandidates(chanBucket, func(c *SpliceCandidate) {
+		for _, commit := range commits {
+			if commit.SpliceTxid == c.Txid() {
+				c.LocalCommitment = commit.Commitment
+			}
+		}
+	})
+}
+
+// putRemotePendingSpliceCommitments stores the given remote commitments,
+// which spend the funding outputs of pending splices and haven't been revoked
+// yet, within their splice candidates.
+func putRemotePendingSpliceCommitments(chanBucket kvdb.RwBucket,
+	commits []SpliceCommitment) error {
+
+	if len(commits) == 0 {
+		return nil
+	}
+
+	return updateSpliceCandidates(chanBucket, func(c *SpliceCandidate) {
+		for _, commit := range commits {
+			if commit.SpliceTxid != c.Txid() {
+				continue
+			}
+
+			pending := commit.Commitment
+			c.RemotePendingCommitment = &pending
+			c.RemotePendingCommitSig = commit.CommitSig
+		}
+	})
+}
+
+// advanceRemoteSpliceCommitments makes the pending remote commitments of all
+// splice candidates their current remote commitments, which happens once the
+// remote party revoked its prior commitment.
+func advanceRemoteSpliceCommitments(chanBucket kvdb.RwBucket) error {
+	return updateSpliceCandidates(chanBucket, func(c *SpliceCandidate) {
+		if c.RemotePendingCommitment == nil {
+			return
+		}
+
+		c.RemoteCommitment = *c.RemotePendingCommitment
+		c.RemotePendingCommitment = nil
+		c.RemotePendingCommitSig = nil
+	})
+}
+
+// AddSpliceCandidate persists a splice transaction negotiated for the channel.
+// If a candidate with the same txid already exists, it's replaced. This is
+// used to update the stored transaction once it has been fully signed.
+func (c *OpenChannel) AddSpliceCandidate(candidate *SpliceCandidate) error {
+	c.Lock()
+	defer c.Unlock()
+
+	txid := candidate.Txid()
+
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := fetchSpliceCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		candidates = fn.Filter(func(s *SpliceCandidate) bool {
+			return s.Txid() != txid
+		}, candidates)
+		candidates = append(candidates, candidate)
+
+		return putSpliceCandidates(chanBucket, candidates)
+	}, func() {})
+}
+
+// SpliceCandidates returns all splice transactions that have been negotiated
+// for the channel, but haven't been locked yet.
+func (c *OpenChannel) SpliceCandidates() ([]*SpliceCandidate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.spliceCandidates()
+}
+
+// spliceCandidates returns all splice candidates of the channel.
+//
+// NOTE: This method requires the channel lock to be held.
+func (c *OpenChannel) spliceCandidates() ([]*SpliceCandidate, error) {
+	var candidates []*SpliceCandidate
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return nil
+		default:
+			return err
+		}
+
+		candidates, err = fetchSpliceCandidates(chanBucket)
+
+		return err
+	}, func() {
+		candidates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// LockSplice makes the splice candidate with the given txid the new funding
+// transaction of the channel, which confirmed at the location described by the
+// given short channel ID. The channel's capacity and commitments are replaced
+// with the ones of the candidate and all other candidates are discarded. If
+// the remote party owes us a revocation, the pending remote commitment is
+// replaced as well. The candidate is returned on success.
+func (c *OpenChannel) LockSplice(txid chainhash.Hash,
+	scid lnwire.ShortChannelID) (*SpliceCandidate, error) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	var candidate *SpliceCandidate
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := fetchSpliceCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		candidate, err = fn.Find(func(s *SpliceCandidate) bool {
+			return s.Txid() == txid
+		}, candidates).UnwrapOrErr(ErrSpliceCandidateNotFound)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		// The candidate commitments are updated along with the
+		// commitments of the channel, so they must be at the same
+		// height.
+		if channel.LocalCommitment.CommitHeight !=
+			candidate.LocalCommitment.CommitHeight ||
+			channel.RemoteCommitment.CommitHeight !=
+				candidate.RemoteCommitment.CommitHeight {
+
+			return fmt.Errorf("splice candidate %v commitments "+
+				"are stale", txid)
+		}
+
+		// If we're waiting for the remote party to revoke its current
+		// commitment, the pending commitment has to be replaced as
+		// well, so it's retransmitted for the new funding output if
+		// needed.
+		if diffBytes := chanBucket.Get(commitDiffKey); diffBytes != nil {
+			diff, err := deserializeCommitDiff(
+				bytes.NewReader(diffBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			pending := candidate.RemotePendingCommitment
+			if pending == nil || pending.CommitHeight !=
+				diff.Commitment.CommitHeight {
+
+				return fmt.Errorf("splice candidate %v is "+
+					"missing pending remote commitment",
+					txid)
+			}
+
+			// Once locked, the commitment is no longer signed as
+			// part of a batch.
+			commitSig := *candidate.RemotePendingCommitSig
+			commitSig.BatchSize = tlv.OptionalRecordT[
+				tlv.TlvType0, uint16,
+			]{}
+			commitSig.FundingTxid = tlv.OptionalRecordT[
+				tlv.TlvType1, [32]byte,
+			]{}
+
+			diff.Commitment = *pending
+			diff.CommitSig = &commitSig
+
+			var b bytes.Buffer
+			if err := serializeCommitDiff(&b, diff); err != nil {
+				return err
+			}
+			err = chanBucket.Put(commitDiffKey, b.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = indexSpliceOutpoint(
+			tx, channel, candidate.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.Capacity = candidate.Capacity
+		channel.SpliceOutpoint = fn.Some(candidate.FundingOutpoint)
+		channel.SpliceScid = fn.Some(scid)
+		channel.LocalCommitment = candidate.LocalCommitment
+		channel.RemoteCommitment = candidate.RemoteCommitment
+
+		if err := putOpenChannel(chanBucket, channel); err != nil {
+			return err
+		}
+
+		return putSpliceCandidates(chanBucket, nil)
+	}, func() {
+		candidate = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Capacity = candidate.Capacity
+	c.SpliceOutpoint = fn.Some(candidate.FundingOutpoint)
+	c.SpliceScid = fn.Some(scid)
+	c.LocalCommitment = candidate.LocalCommitment
+	c.RemoteCommitment = candidate.RemoteCommitment
+
+	return candidate, nil
+}
+
+// indexSpliceOutpoint maps the funding outpoint of the given splice to the
+// funding outpoint the channel was opened with, replacing the entry of a
+// previous splice of the channel.
+func indexSpliceOutpoint(tx kvdb.RwTx, channel *OpenChannel,
+	spliceOutpoint wire.OutPoint) error {
+
+	spliceBucket, err := tx.CreateTopLevelBucket(spliceOutpointBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := unindexSpliceOutpoint(spliceBucket, channel); err != nil {
+		return err
+	}
+
+	var chanPoint, spliceKey bytes.Buffer
+	err = writeOutpoint(&chanPoint, &channel.FundingOutpoint)
+	if err != nil {
+		return err
+	}
+	if err := writeOutpoint(&spliceKey, &spliceOutpoint); err != nil {
+		return err
+	}
+
+	return spliceBucket.Put(spliceKey.Bytes(), chanPoint.Bytes())
+}
+
+// unindexSpliceOutpoint removes the entry for the current funding outpoint of
+// the given channel from the splice outpoint index, if it has been spliced.
+func unindexSpliceOutpoint(spliceBucket kvdb.RwBucket,
+	channel *OpenChannel) error {
+
+	if channel.SpliceOutpoint.IsNone() {
+		return nil
+	}
+	spliceOutpoint := channel.SpliceOutpoint.UnsafeFromSome()
+
+	var spliceKey bytes.Buffer
+	if err := writeOutpoint(&spliceKey, &spliceOutpoint); err != nil {
+		return err
+	}
+
+	return spliceBucket.Delete(spliceKey.Bytes())
+}
+
+// fetchSplicedChanPoint returns the funding outpoint a channel was opened with
+// given the funding outpoint of one of its splices. ErrChannelNotFound is
+// returned if no channel was spliced into the given outpoint.
+func fetchSplicedChanPoint(tx kvdb.RTx,
+	spliceOutpoint wire.OutPoint) (wire.OutPoint, error) {
+
+	var chanPoint wire.OutPoint
+
+	spliceBucket := tx.ReadBucket(spliceOutpointBucket)
+	if spliceBucket == nil {
+		return chanPoint, ErrChannelNotFound
+	}
+
+	var spliceKey bytes.Buffer
+	if err := writeOutpoint(&spliceKey, &spliceOutpoint); err != nil {
+		return chanPoint, err
+	}
+
+	chanPointBytes := spliceBucket.Get(spliceKey.Bytes())
+	if chanPointBytes == nil {
+		return chanPoint, ErrChannelNotFound
+	}
+
+	err := readOutpoint(bytes.NewReader(chanPointBytes), &chanPoint)
+
+	return chanPoint, err
+}
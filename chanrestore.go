@@ -156,6 +156,13 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 		chanType |= channeldb.AnchorOutputsBit
 		chanType |= channeldb.SingleFunderTweaklessBit
 
+	case chanbackup.AcceptorLeaseVersion:
+		chanType = channeldb.AcceptorLeaseBit
+		chanType |= channeldb.LeaseExpirationBit
+		chanType |= channeldb.ZeroHtlcTxFeeBit
+		chanType |= channeldb.AnchorOutputsBit
+		chanType |= channeldb.SingleFunderTweaklessBit
+
 	case chanbackup.SimpleTaprootVersion:
 		chanType = channeldb.ZeroHtlcTxFeeBit
 		chanType |= channeldb.AnchorOutputsBit
@@ -169,6 +176,12 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 		chanType |= channeldb.SimpleTaprootFeatureBit
 		chanType |= channeldb.TapscriptRootBit
 
+	case chanbackup.ZeroFeeCommitmentsVersion:
+		chanType = channeldb.ZeroFeeCommitmentsBit
+		chanType |= channeldb.ZeroHtlcTxFeeBit
+		chanType |= channeldb.AnchorOutputsBit
+		chanType |= channeldb.SingleFunderTweaklessBit
+
 	default:
 		return nil, fmt.Errorf("unknown Single version: %w", err)
 	}
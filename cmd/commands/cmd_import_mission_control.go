@@ -91,7 +91,8 @@ func importMissionControl(ctx *cli.Context) error {
 		Pairs: []*routerrpc.PairHistory{
 			importResult,
 		},
-		Force: ctx.IsSet("force"),
+		Force:                   ctx.IsSet("force"),
+		MissionControlNamespace: ctx.String(mcNamespaceFlag.Name),
 	}
 
 	rpcCtx := context.Background()
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var listLiquidityAdsCommand = cli.Command{
+	Name:     "listliquidityads",
+	Category: "Channels",
+	Usage:    "List the nodes selling inbound liquidity.",
+	Description: `
+	List the nodes of the graph that sell inbound liquidity through channel
+	leases, along with the lease rates they advertise.
+
+	If an amount is given, the lease fee for that amount is computed for
+	each node.
+	`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount in satoshis to compute lease fees " +
+				"for",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "the funding fee rate in sat/vbyte used to " +
+				"compute lease fees, estimated if not set",
+		},
+	},
+	Action: actionDecorator(listLiquidityAds),
+}
+
+func listLiquidityAds(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListLiquidityAdsRequest{
+		AmountSat:   ctx.Int64("amt"),
+		SatPerVbyte: ctx.Uint64("sat_per_vbyte"),
+	}
+	resp, err := client.ListLiquidityAds(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var purchaseLeaseCommand = cli.Command{
+	Name:     "purchaselease",
+	Category: "Channels",
+	Usage:    "Buy inbound liquidity from a connected peer.",
+	Description: `
+	Buy inbound liquidity from a connected peer that advertises lease
+	rates. This node opens a dual-funded channel to the peer, requesting it
+	to contribute the requested amount, while this node contributes the
+	lease fee and the fees of the commitment transaction. The funds of the
+	peer are locked in the channel until the lease expires.
+	`,
+	ArgsUsage: "node_key amt max_fee",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "node_key",
+			Usage: "the identity public key of the peer",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount in satoshis the peer should " +
+				"contribute to the channel",
+		},
+		cli.Int64Flag{
+			Name: "max_fee",
+			Usage: "the largest lease fee in satoshis to pay for " +
+				"the lease",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "the funding fee rate in sat/vbyte, estimated " +
+				"if not set",
+		},
+		cli.BoolFlag{
+			Name: "block",
+			Usage: "block and wait until the leased channel is " +
+				"fully open",
+		},
+	},
+	Action: actionDecorator(purchaseLease),
+}
+
+func purchaseLease(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	var nodeKey string
+	switch {
+	case ctx.IsSet("node_key"):
+		nodeKey = ctx.String("node_key")
+	case args.Present():
+		nodeKey = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("node_key argument missing")
+	}
+	nodePubkey, err := hex.DecodeString(nodeKey)
+	if err != nil {
+		return fmt.Errorf("unable to decode node public key: %w", err)
+	}
+
+	amt, args, err := int64Arg(ctx, "amt", args)
+	if err != nil {
+		return err
+	}
+
+	maxFee, _, err := int64Arg(ctx, "max_fee", args)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.PurchaseLeaseRequest{
+		NodePubkey:  nodePubkey,
+		AmountSat:   amt,
+		MaxFeeSat:   maxFee,
+		SatPerVbyte: ctx.Uint64("sat_per_vbyte"),
+	}
+	stream, err := client.PurchaseLease(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch update := resp.Update.(type) {
+		case *lnrpc.OpenStatusUpdate_ChanPending:
+			err := printChanPending(update)
+			if err != nil {
+				return err
+			}
+
+			if !ctx.Bool("block") {
+				return nil
+			}
+
+		case *lnrpc.OpenStatusUpdate_ChanOpen:
+			return printChanOpen(update)
+		}
+	}
+}
+
+// int64Arg returns the value of the named flag if set, or otherwise parses it
+// from the first of the remaining positional arguments.
+func int64Arg(ctx *cli.Context, name string,
+	args cli.Args) (int64, cli.Args, error) {
+
+	switch {
+	case ctx.IsSet(name):
+		return ctx.Int64(name), args, nil
+
+	case args.Present():
+		val, err := strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("unable to decode %v: %w",
+				name, err)
+		}
+
+		return val, args.Tail(), nil
+
+	default:
+		return 0, nil, fmt.Errorf("%v argument missing", name)
+	}
+}
@@ -19,6 +19,7 @@ var getCfgCommand = cli.Command{
 	Description: `
 	Returns the config currently being used by mission control.
 	`,
+	Flags:  []cli.Flag{mcNamespaceFlag},
 	Action: actionDecorator(getCfg),
 }
 
@@ -30,7 +31,11 @@ func getCfg(ctx *cli.Context) error {
 	client := routerrpc.NewRouterClient(conn)
 
 	resp, err := client.GetMissionControlConfig(
-		ctxc, &routerrpc.GetMissionControlConfigRequest{},
+		ctxc, &routerrpc.GetMissionControlConfigRequest{
+			MissionControlNamespace: ctx.String(
+				mcNamespaceFlag.Name,
+			),
+		},
 	)
 	if err != nil {
 		return err
@@ -50,6 +55,7 @@ var setCfgCommand = cli.Command{
         probability that payment routes will succeed. The estimator type must be
         provided to set estimator-related parameters.`,
 	Flags: []cli.Flag{
+		mcNamespaceFlag,
 		// General settings.
 		cli.UintFlag{
 			Name: "pmtnr",
@@ -124,8 +130,11 @@ func setCfg(ctx *cli.Context) error {
 
 	// Fetch current mission control config which we update to create our
 	// response.
+	namespace := ctx.String(mcNamespaceFlag.Name)
 	mcCfg, err := client.GetMissionControlConfig(
-		ctxc, &routerrpc.GetMissionControlConfigRequest{},
+		ctxc, &routerrpc.GetMissionControlConfigRequest{
+			MissionControlNamespace: namespace,
+		},
 	)
 	if err != nil {
 		return err
@@ -262,7 +271,8 @@ func setCfg(ctx *cli.Context) error {
 
 	_, err = client.SetMissionControlConfig(
 		ctxc, &routerrpc.SetMissionControlConfigRequest{
-			Config: mcCfg.Config,
+			Config:                  mcCfg.Config,
+			MissionControlNamespace: namespace,
 		},
 	)
 
@@ -273,6 +283,7 @@ var queryMissionControlCommand = cli.Command{
 	Name:     "querymc",
 	Category: "Mission Control",
 	Usage:    "Query the internal mission control state.",
+	Flags:    []cli.Flag{mcNamespaceFlag},
 	Action:   actionDecorator(queryMissionControl),
 }
 
@@ -283,7 +294,9 @@ func queryMissionControl(ctx *cli.Context) error {
 
 	client := routerrpc.NewRouterClient(conn)
 
-	req := &routerrpc.QueryMissionControlRequest{}
+	req := &routerrpc.QueryMissionControlRequest{
+		MissionControlNamespace: ctx.String(mcNamespaceFlag.Name),
+	}
 	snapshot, err := client.QueryMissionControl(ctxc, req)
 	if err != nil {
 		return err
@@ -299,6 +312,7 @@ var queryProbCommand = cli.Command{
 	Category:  "Mission Control",
 	Usage:     "Deprecated. Estimate a success probability.",
 	ArgsUsage: "from-node to-node amt",
+	Flags:     []cli.Flag{mcNamespaceFlag},
 	Action:    actionDecorator(queryProb),
 	Hidden:    true,
 }
@@ -339,6 +353,9 @@ func queryProb(ctx *cli.Context) error {
 		FromNode: fromNode[:],
 		ToNode:   toNode[:],
 		AmtMsat:  int64(amtMsat),
+		MissionControlNamespace: ctx.String(
+			mcNamespaceFlag.Name,
+		),
 	}
 
 	response, err := client.QueryProbability(ctxc, req)
@@ -355,6 +372,7 @@ var resetMissionControlCommand = cli.Command{
 	Name:     "resetmc",
 	Category: "Mission Control",
 	Usage:    "Reset internal mission control state.",
+	Flags:    []cli.Flag{mcNamespaceFlag},
 	Action:   actionDecorator(resetMissionControl),
 }
 
@@ -365,8 +383,99 @@ func resetMissionControl(ctx *cli.Context) error {
 
 	client := routerrpc.NewRouterClient(conn)
 
-	req := &routerrpc.ResetMissionControlRequest{}
+	req := &routerrpc.ResetMissionControlRequest{
+		MissionControlNamespace: ctx.String(mcNamespaceFlag.Name),
+	}
 	_, err := client.ResetMissionControl(ctxc, req)
 
 	return err
 }
+
+var createMcNamespaceCommand = cli.Command{
+	Name:      "createmcnamespace",
+	Category:  "Mission Control",
+	Usage:     "Create a mission control namespace.",
+	ArgsUsage: "namespace",
+	Description: `
+	Creates a new mission control namespace with the config of the default
+	namespace. Payments that select the namespace with --mc_namespace only
+	learn from and are routed with the history of the namespace. The config
+	of the namespace can be changed with setmccfg --mc_namespace.`,
+	Action: actionDecorator(createMcNamespace),
+}
+
+func createMcNamespace(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "createmcnamespace")
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.CreateMissionControlNamespaceRequest{
+		Namespace: ctx.Args().First(),
+	}
+	_, err := client.CreateMissionControlNamespace(ctxc, req)
+
+	return err
+}
+
+var deleteMcNamespaceCommand = cli.Command{
+	Name:      "deletemcnamespace",
+	Category:  "Mission Control",
+	Usage:     "Delete a mission control namespace.",
+	ArgsUsage: "namespace",
+	Description: `
+	Deletes a mission control namespace together with its history and
+	config. The default namespace can't be deleted.`,
+	Action: actionDecorator(deleteMcNamespace),
+}
+
+func deleteMcNamespace(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "deletemcnamespace")
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.DeleteMissionControlNamespaceRequest{
+		Namespace: ctx.Args().First(),
+	}
+	_, err := client.DeleteMissionControlNamespace(ctxc, req)
+
+	return err
+}
+
+var listMcNamespacesCommand = cli.Command{
+	Name:     "listmcnamespaces",
+	Category: "Mission Control",
+	Usage:    "List the mission control namespaces.",
+	Action:   actionDecorator(listMcNamespaces),
+}
+
+func listMcNamespaces(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ListMissionControlNamespacesRequest{}
+	resp, err := client.ListMissionControlNamespaces(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var addOfferCommand = cli.Command{
+	Name:     "addoffer",
+	Category: "Offers",
+	Usage:    "Create a new BOLT 12 offer.",
+	Description: `
+	Create a reusable BOLT 12 offer that payers use to fetch invoices from
+	this node over onion messages. Onion messages must be enabled with the
+	protocol.onion-messages option.
+
+	If no amount is given, the payer chooses the amount to pay.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "description",
+			Usage: "a description of the purpose of the payment, " +
+				"required if an amount is set",
+		},
+		cli.Uint64Flag{
+			Name:  "amt_msat",
+			Usage: "the amount to pay per item in millisatoshis",
+		},
+		cli.StringFlag{
+			Name:  "issuer",
+			Usage: "an optional description of the issuer",
+		},
+		cli.BoolFlag{
+			Name:  "allow_quantity",
+			Usage: "allow the payer to request several items",
+		},
+		cli.Uint64Flag{
+			Name: "quantity_max",
+			Usage: "the maximum number of items the payer may " +
+				"request if --allow_quantity is set, no " +
+				"maximum if zero",
+		},
+		cli.DurationFlag{
+			Name: "expiry",
+			Usage: "the duration after which the offer is no " +
+				"longer valid, the offer doesn't expire if " +
+				"not set",
+		},
+	},
+	Action: actionDecorator(addOffer),
+}
+
+func addOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.AddOfferRequest{
+		Description:   ctx.String("description"),
+		AmountMsat:    ctx.Uint64("amt_msat"),
+		Issuer:        ctx.String("issuer"),
+		AllowQuantity: ctx.Bool("allow_quantity"),
+		QuantityMax:   ctx.Uint64("quantity_max"),
+	}
+	if ctx.IsSet("expiry") {
+		expiry := time.Now().Add(ctx.Duration("expiry"))
+		req.AbsoluteExpiry = expiry.Unix()
+	}
+
+	resp, err := client.AddOffer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listOffersCommand = cli.Command{
+	Name:     "listoffers",
+	Category: "Offers",
+	Usage:    "List the BOLT 12 offers created by this node.",
+	Action:   actionDecorator(listOffers),
+}
+
+func listOffers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListOffers(ctxc, &lnrpc.ListOffersRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var offerFlags = []cli.Flag{
+	cli.Uint64Flag{
+		Name: "amt_msat",
+		Usage: "the amount to pay in millisatoshis, required if the " +
+			"offer has no amount",
+	},
+	cli.Uint64Flag{
+		Name: "quantity",
+		Usage: "the number of items to request, required if the " +
+			"offer allows a quantity",
+	},
+	cli.StringFlag{
+		Name:  "payer_note",
+		Usage: "an optional note for the issuer",
+	},
+	cli.DurationFlag{
+		Name:  "fetch_timeout",
+		Usage: "the time to wait for the invoice",
+		Value: 30 * time.Second,
+	},
+}
+
+var fetchInvoiceCommand = cli.Command{
+	Name:     "fetchinvoice",
+	Category: "Offers",
+	Usage:    "Fetch an invoice for a BOLT 12 offer.",
+	Description: `
+	Request a BOLT 12 invoice for an offer from its issuer over onion
+	messages. The issuer, or the introduction node of the offer's blinded
+	path, must be a connected peer.
+	`,
+	ArgsUsage: "offer",
+	Flags:     offerFlags,
+	Action:    actionDecorator(fetchInvoice),
+}
+
+// fetchOfferInvoice fetches an invoice for the offer given as the first
+// argument.
+func fetchOfferInvoice(ctx *cli.Context,
+	client lnrpc.LightningClient) (*lnrpc.FetchInvoiceResponse, error) {
+
+	if !ctx.Args().Present() {
+		return nil, fmt.Errorf("offer argument missing")
+	}
+
+	return client.FetchInvoice(getContext(), &lnrpc.FetchInvoiceRequest{
+		Offer:          ctx.Args().First(),
+		AmountMsat:     ctx.Uint64("amt_msat"),
+		Quantity:       ctx.Uint64("quantity"),
+		PayerNote:      ctx.String("payer_note"),
+		TimeoutSeconds: uint32(ctx.Duration("fetch_timeout").Seconds()),
+	})
+}
+
+func fetchInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := fetchOfferInvoice(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var payOfferCommand = cli.Command{
+	Name:     "payoffer",
+	Category: "Offers",
+	Usage:    "Fetch an invoice for a BOLT 12 offer and pay it.",
+	Description: `
+	Request a BOLT 12 invoice for an offer from its issuer over onion
+	messages, and pay it along the blinded paths of the invoice. The
+	issuer, or the introduction node of the offer's blinded path, must be
+	a connected peer.
+	`,
+	ArgsUsage: "offer",
+	Flags:     append(PaymentFlags(), offerFlags...),
+	Action:    actionDecorator(payOffer),
+}
+
+func payOffer(ctx *cli.Context) error {
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := lnrpc.NewLightningClient(conn)
+	resp, err := fetchOfferInvoice(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	req := &routerrpc.SendPaymentRequest{
+		Bolt12Invoice:     resp.Invoice,
+		AmtMsat:           int64(resp.AmountMsat),
+		DestCustomRecords: make(map[uint64][]byte),
+		Cancelable:        ctx.Bool(cancelableFlag.Name),
+	}
+
+	return SendPaymentRequest(ctx, req, conn, conn, routerRPCSendPayment)
+}
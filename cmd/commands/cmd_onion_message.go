@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/urfave/cli"
+)
+
+var sendOnionMessageCommand = cli.Command{
+	Name:     "sendonionmessage",
+	Category: "Peers",
+	Usage:    "Send an onion message to a node or along a blinded path",
+	Description: `
+	Send an onion message to the node given by --dest, or along the blinded
+	path given by --introduction_node, --blinding_point and --blinded_hops.
+
+	The message is sent through the nodes given by --hop before it reaches
+	the destination or the introduction node of the blinded path. If no hops
+	are given, the destination or introduction node must be a connected
+	peer.
+
+	The contents of the message are given as --record type=hex_value, with
+	types of at least 64.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "dest",
+			Usage: "the hex encoded node ID of the recipient",
+		},
+		introductionNodeFlag,
+		blindingPointFlag,
+		blindedHopsFlag,
+		cli.StringSliceFlag{
+			Name: "hop",
+			Usage: "the hex encoded node ID of a node to send the " +
+				"message through, can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name: "record",
+			Usage: "a record of the message formatted as " +
+				"type=hex_value, can be repeated",
+		},
+		cli.BoolFlag{
+			Name: "reply_path",
+			Usage: "include a blinded path to this node the " +
+				"recipient can reply along",
+		},
+		cli.StringSliceFlag{
+			Name: "reply_hop",
+			Usage: "the hex encoded node ID of a node the reply " +
+				"path passes through before it reaches this " +
+				"node, can be repeated",
+		},
+		cli.StringFlag{
+			Name:  "reply_path_id",
+			Usage: "a hex encoded path ID to include in the reply path",
+		},
+	},
+	Action: actionDecorator(sendOnionMessage),
+}
+
+func sendOnionMessage(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SendOnionMessageRequest{
+		CustomRecords:    make(map[uint64][]byte),
+		IncludeReplyPath: ctx.Bool("reply_path"),
+	}
+
+	var err error
+	switch {
+	case ctx.IsSet("dest"):
+		req.Destination, err = hex.DecodeString(ctx.String("dest"))
+		if err != nil {
+			return fmt.Errorf("unable to decode dest: %w", err)
+		}
+
+	case ctx.IsSet(blindingPointFlag.Name):
+		req.BlindedPath, err = parseOnionMessagePath(ctx)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("dest or blinded path required")
+	}
+
+	req.Hops, err = parseNodeIDs(ctx.StringSlice("hop"))
+	if err != nil {
+		return err
+	}
+
+	req.ReplyPathHops, err = parseNodeIDs(ctx.StringSlice("reply_hop"))
+	if err != nil {
+		return err
+	}
+
+	req.ReplyPathId, err = hex.DecodeString(ctx.String("reply_path_id"))
+	if err != nil {
+		return fmt.Errorf("unable to decode reply_path_id: %w", err)
+	}
+
+	for _, record := range ctx.StringSlice("record") {
+		parts := strings.Split(record, "=")
+		if len(parts) != 2 {
+			return fmt.Errorf("records should be expressed as "+
+				"type=hex_value, got: %v", record)
+		}
+
+		typ, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid record type: %w", err)
+		}
+
+		req.CustomRecords[typ], err = hex.DecodeString(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid record value: %w", err)
+		}
+	}
+
+	resp, err := client.SendOnionMessage(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+// parseNodeIDs decodes a list of hex encoded node IDs.
+func parseNodeIDs(nodeIDs []string) ([][]byte, error) {
+	var decoded [][]byte
+	for _, nodeID := range nodeIDs {
+		vertex, err := route.NewVertexFromStr(nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node ID %v: %w",
+				nodeID, err)
+		}
+
+		decoded = append(decoded, vertex[:])
+	}
+
+	return decoded, nil
+}
+
+// parseOnionMessagePath parses the blinded path an onion message is sent
+// along.
+func parseOnionMessagePath(ctx *cli.Context) (*lnrpc.BlindedPath, error) {
+	introNode, err := route.NewVertexFromStr(
+		ctx.String(introductionNodeFlag.Name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("decode introduction node: %w", err)
+	}
+
+	blindingPoint, err := route.NewVertexFromStr(ctx.String(
+		blindingPointFlag.Name,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("decode blinding point: %w", err)
+	}
+
+	path := &lnrpc.BlindedPath{
+		IntroductionNode: introNode[:],
+		BlindingPoint:    blindingPoint[:],
+	}
+
+	for i, hop := range ctx.StringSlice(blindedHopsFlag.Name) {
+		parts := strings.Split(hop, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("blinded hops should be "+
+				"expressed as "+
+				"blinded_node_id:hex_encrypted_data, got: %v",
+				hop)
+		}
+
+		node, err := route.NewVertexFromStr(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("hop: %v node: %w", i, err)
+		}
+
+		data, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("hop: %v data: %w", i, err)
+		}
+
+		path.BlindedHops = append(path.BlindedHops, &lnrpc.BlindedHop{
+			BlindedNode:   node[:],
+			EncryptedData: data,
+		})
+	}
+
+	return path, nil
+}
+
+var subscribeOnionMessagesCommand = cli.Command{
+	Name:     "subscribeonionmessages",
+	Category: "Peers",
+	Usage:    "Subscribe to incoming onion messages sent to this node",
+	Action:   actionDecorator(subscribeOnionMessages),
+}
+
+func subscribeOnionMessages(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeOnionMessages(
+		ctxc, &lnrpc.SubscribeOnionMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
@@ -58,9 +58,10 @@ Signed base64 encoded PSBT or hex encoded raw wire TX (or path to file): `
 	// of memory issues or other weird errors.
 	psbtMaxFileSize = 1024 * 1024
 
-	channelTypeTweakless     = "tweakless"
-	channelTypeAnchors       = "anchors"
-	channelTypeSimpleTaproot = "taproot"
+	channelTypeTweakless          = "tweakless"
+	channelTypeAnchors            = "anchors"
+	channelTypeSimpleTaproot      = "taproot"
+	channelTypeZeroFeeCommitments = "zero-fee-commitments"
 )
 
 // TODO(roasbeef): change default number of confirmations.
@@ -253,9 +254,10 @@ var openChannelCommand = cli.Command{
 		cli.StringFlag{
 			Name: "channel_type",
 			Usage: fmt.Sprintf("(optional) the type of channel to "+
-				"propose to the remote peer (%q, %q, %q)",
+				"propose to the remote peer (%q, %q, %q, %q)",
 				channelTypeTweakless, channelTypeAnchors,
-				channelTypeSimpleTaproot),
+				channelTypeSimpleTaproot,
+				channelTypeZeroFeeCommitments),
 		},
 		cli.BoolFlag{
 			Name: "zero_conf",
@@ -446,6 +448,8 @@ func openChannel(ctx *cli.Context) error {
 		req.CommitmentType = lnrpc.CommitmentType_ANCHORS
 	case channelTypeSimpleTaproot:
 		req.CommitmentType = lnrpc.CommitmentType_SIMPLE_TAPROOT
+	case channelTypeZeroFeeCommitments:
+		req.CommitmentType = lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS
 	default:
 		return fmt.Errorf("unsupported channel type %v", channelType)
 	}
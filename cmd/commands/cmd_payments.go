@@ -90,6 +90,20 @@ var (
 			"specified in milli-satoshis",
 	}
 
+	minCostFlowFlag = cli.BoolFlag{
+		Name: "min_cost_flow",
+		Usage: "if set, the shards of a payment that needs to be " +
+			"split are planned at once with a min cost flow " +
+			"instead of halving the amount until a route is " +
+			"found",
+	}
+
+	mcNamespaceFlag = cli.StringFlag{
+		Name: "mc_namespace",
+		Usage: "(optional) the mission control namespace to use, " +
+			"the default namespace is used if not set",
+	}
+
 	ampFlag = cli.BoolFlag{
 		Name: "amp",
 		Usage: "if set to true, then AMP will be used to complete the " +
@@ -142,6 +156,15 @@ var (
 			"blinded portion of the route",
 	}
 
+	trampolineNodeFlag = cli.StringFlag{
+		Name: "trampoline_node",
+		Usage: "(optional) the pubkey of a trampoline node to " +
+			"send the payment through; the trampoline node " +
+			"finds the route to the recipient and is paid the " +
+			"configured trampoline fee budget on top of the " +
+			"amount",
+	}
+
 	cancelableFlag = cli.BoolFlag{
 		Name: "cancelable",
 		Usage: "if set to true, the payment loop can be interrupted " +
@@ -182,6 +205,7 @@ func PaymentFlags() []cli.Flag {
 		cancelableFlag,
 		cltvLimitFlag,
 		lastHopFlag,
+		trampolineNodeFlag,
 		cli.Int64SliceFlag{
 			Name: "outgoing_chan_id",
 			Usage: "short channel id of the outgoing channel to " +
@@ -200,7 +224,7 @@ func PaymentFlags() []cli.Flag {
 		},
 		dataFlag, inflightUpdatesFlag, maxPartsFlag, jsonFlag,
 		maxShardSizeSatFlag, maxShardSizeMsatFlag, ampFlag,
-		timePrefFlag,
+		timePrefFlag, minCostFlowFlag, mcNamespaceFlag,
 	}
 }
 
@@ -515,6 +539,16 @@ func SendPaymentRequest(ctx *cli.Context, req *routerrpc.SendPaymentRequest,
 		req.LastHopPubkey = lastHop[:]
 	}
 
+	if ctx.IsSet(trampolineNodeFlag.Name) {
+		trampolineNode, err := route.NewVertexFromStr(
+			ctx.String(trampolineNodeFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+		req.TrampolineNode = trampolineNode[:]
+	}
+
 	req.CltvLimit = int32(ctx.Int(cltvLimitFlag.Name))
 
 	pmtTimeout := ctx.Duration("timeout")
@@ -544,6 +578,13 @@ func SendPaymentRequest(ctx *cli.Context, req *routerrpc.SendPaymentRequest,
 		))
 	}
 
+	if ctx.Bool(minCostFlowFlag.Name) {
+		req.SplitStrategy =
+			routerrpc.PaymentSplitStrategy_SPLIT_MIN_COST_FLOW
+	}
+
+	req.MissionControlNamespace = ctx.String(mcNamespaceFlag.Name)
+
 	// Parse custom data records.
 	data := ctx.String(dataFlag.Name)
 	if data != "" {
@@ -603,8 +644,14 @@ func SendPaymentRequest(ctx *cli.Context, req *routerrpc.SendPaymentRequest,
 			}
 		}
 	} else {
+		amt := req.Amt
+		if req.AmtMsat != 0 {
+			amtMsat := lnwire.MilliSatoshi(req.AmtMsat)
+			amt = int64(amtMsat.ToSatoshis())
+		}
+
 		var err error
-		feeLimit, err = retrieveFeeLimit(ctx, req.Amt)
+		feeLimit, err = retrieveFeeLimit(ctx, amt)
 		if err != nil {
 			return err
 		}
@@ -1146,8 +1193,15 @@ var queryRoutesCommand = cli.Command{
 				"multiple times if multiple node pairs are " +
 				"to be ignored",
 		},
+		cli.UintFlag{
+			Name: "num_routes",
+			Usage: "(optional) the maximum number of distinct " +
+				"routes to return, ordered from best to " +
+				"worst",
+		},
 		timePrefFlag,
 		cltvLimitFlag,
+		mcNamespaceFlag,
 		introductionNodeFlag,
 		blindingPointFlag,
 		blindedHopsFlag,
@@ -1246,6 +1300,10 @@ func queryRoutes(ctx *cli.Context) error {
 		TimePref:            ctx.Float64(timePrefFlag.Name),
 		IgnoredPairs:        ignoredPairs,
 		BlindedPaymentPaths: blindedRoutes,
+		NumRoutes:           uint32(ctx.Uint("num_routes")),
+		MissionControlNamespace: ctx.String(
+			mcNamespaceFlag.Name,
+		),
 	}
 
 	route, err := client.QueryRoutes(ctxc, req)
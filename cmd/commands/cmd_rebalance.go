@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var rebalanceCommand = cli.Command{
+	Name:     "rebalance",
+	Category: "Channels",
+	Usage:    "Move liquidity between two of our channels.",
+	Description: `
+	Moves liquidity from the outgoing to the incoming channel by paying
+	ourselves over a circular route. The call blocks until the payment
+	completes. The payment is labeled "rebalance" in listpayments.
+
+	If no fee limit is given, the rebalance may spend the configured
+	fraction (routerrpc.rebalancer.fee-ratio) of the outbound fee rate of
+	the incoming channel.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "the short channel id of the channel to move " +
+				"out of",
+		},
+		cli.Uint64Flag{
+			Name: "incoming_chan_id",
+			Usage: "the short channel id of the channel to move " +
+				"into",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amount to move in satoshis",
+		},
+		cli.Uint64Flag{
+			Name: "max_fee_ppm",
+			Usage: "the maximum fee in parts per million of the " +
+				"amount",
+		},
+	},
+	Action: actionDecorator(rebalance),
+}
+
+func rebalance(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.RebalanceRequest{
+		OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
+		IncomingChanId: ctx.Uint64("incoming_chan_id"),
+		AmtSat:         ctx.Int64("amt"),
+		MaxFeePpm:      ctx.Uint64("max_fee_ppm"),
+	}
+	resp, err := client.Rebalance(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listRebalancesCommand = cli.Command{
+	Name:     "listrebalances",
+	Category: "Channels",
+	Usage:    "List the history of rebalances.",
+	Description: `
+	Returns all rebalances, including those of the autonomous rebalancer,
+	together with the total fees spent and volume moved.`,
+	Action: actionDecorator(listRebalances),
+}
+
+func listRebalances(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	resp, err := client.ListRebalances(
+		ctxc, &routerrpc.ListRebalancesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var queryReputationCommand = cli.Command{
+	Name:     "queryreputation",
+	Category: "Channels",
+	Usage: "Query the reputation and resource usage of channels that " +
+		"htlcs were forwarded on.",
+	Description: `
+	Returns the reputation that channels earned by forwarding htlcs to us,
+	their revenue as outgoing channel, and the usage of their general and
+	protected htlc slots and liquidity. If no channel id is given, all
+	channels that htlcs were forwarded on are returned.
+
+	This requires htlcswitch.reputation.enable to be set.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "chan_id",
+			Usage: "the short channel id of the channel to query",
+		},
+	},
+	Action: actionDecorator(queryReputation),
+}
+
+func queryReputation(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.QueryReputationRequest{
+		ChanId: ctx.Uint64("chan_id"),
+	}
+	resp, err := client.QueryReputation(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/urfave/cli"
+)
+
+var simulatePaymentCommand = cli.Command{
+	Name:     "simulatepayment",
+	Category: "Payments",
+	Usage:    "Plan a payment without sending it.",
+	Description: `
+	Runs the path finding of sendpayment against the local graph and
+	mission control without sending anything. The payment is split the
+	same way as a real payment, assuming that every shard succeeds.
+
+	The output lists the planned shards with their routes and success
+	probabilities, the total fees and time lock, and the edges that path
+	finding discarded along with the reason.`,
+	ArgsUsage: "pay_req | --dest=N --amt=A",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pay_req",
+			Usage: "a zpay32 encoded payment request to simulate",
+		},
+		cli.StringFlag{
+			Name: "dest, d",
+			Usage: "the compressed identity pubkey of the " +
+				"payment recipient",
+		},
+		cli.Int64Flag{
+			Name:  "amt, a",
+			Usage: "number of satoshis to send",
+		},
+		cli.Int64Flag{
+			Name:  "fee_limit",
+			Usage: "maximum fee allowed in satoshis",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the payment's amount used as " +
+				"the maximum fee allowed",
+		},
+		cli.Int64SliceFlag{
+			Name: "outgoing_chan_id",
+			Usage: "short channel id of the outgoing channel to " +
+				"use for the first hop of the payment; can " +
+				"be specified multiple times in the same " +
+				"command",
+			Value: &cli.Int64Slice{},
+		},
+		cltvLimitFlag, lastHopFlag, maxPartsFlag, minCostFlowFlag,
+		mcNamespaceFlag,
+	},
+	Action: actionDecorator(simulatePayment),
+}
+
+func simulatePayment(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.SendPaymentRequest{
+		Amt:       ctx.Int64("amt"),
+		CltvLimit: int32(ctx.Uint(cltvLimitFlag.Name)),
+		MaxParts:  uint32(ctx.Uint(maxPartsFlag.Name)),
+		MissionControlNamespace: ctx.String(
+			mcNamespaceFlag.Name,
+		),
+	}
+
+	switch {
+	case ctx.IsSet("pay_req"):
+		req.PaymentRequest = StripPrefix(ctx.String("pay_req"))
+
+	case ctx.Args().Present():
+		req.PaymentRequest = StripPrefix(ctx.Args().First())
+
+	case ctx.IsSet("dest"):
+		dest, err := route.NewVertexFromStr(ctx.String("dest"))
+		if err != nil {
+			return err
+		}
+		req.Dest = dest[:]
+
+		// Nothing is sent, so any payment hash will do.
+		req.PaymentHash = make([]byte, 32)
+
+	default:
+		return errors.New("either pay_req or dest must be set")
+	}
+
+	for _, chanID := range ctx.Int64Slice("outgoing_chan_id") {
+		req.OutgoingChanIds = append(
+			req.OutgoingChanIds, uint64(chanID),
+		)
+	}
+
+	if ctx.IsSet(lastHopFlag.Name) {
+		lastHop, err := route.NewVertexFromStr(
+			ctx.String(lastHopFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+		req.LastHopPubkey = lastHop[:]
+	}
+
+	if ctx.Bool(minCostFlowFlag.Name) {
+		req.SplitStrategy =
+			routerrpc.PaymentSplitStrategy_SPLIT_MIN_COST_FLOW
+	}
+
+	// The default fee limit depends on the amount, which needs to be
+	// taken from the invoice if there is one.
+	amt := req.Amt
+	if req.PaymentRequest != "" {
+		client := lnrpc.NewLightningClient(conn)
+		decodeResp, err := client.DecodePayReq(
+			ctxc, &lnrpc.PayReqString{PayReq: req.PaymentRequest},
+		)
+		if err != nil {
+			return err
+		}
+
+		if decodeResp.NumSatoshis != 0 {
+			amt = decodeResp.NumSatoshis
+		}
+	}
+
+	feeLimit, err := retrieveFeeLimit(ctx, amt)
+	if err != nil {
+		return err
+	}
+	req.FeeLimitSat = feeLimit
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.SimulatePayment(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
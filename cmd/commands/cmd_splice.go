@@ -0,0 +1,432 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+const (
+	userMsgSpliceFund = `PSBT funding of the splice initiated.
+Please fund the PSBT below, which sends %v (%d satoshi) to the new funding
+address %s. It also spends the current funding output of the channel, which
+MUST stay in the transaction. Your wallet only needs to add inputs worth the
+splice amount plus fees.
+
+%s
+
+!!! WARNING !!!
+DO NOT PUBLISH the finished transaction by yourself or with another tool.
+The splice transaction can only be published once lnd has negotiated it with
+the remote peer.
+
+Base64 encoded PSBT (or path to file): `
+)
+
+// parseSpliceChanPoint parses the channel point of a splice command, which can
+// be given either as a flag or as the first positional argument.
+func parseSpliceChanPoint(ctx *cli.Context) (*lnrpc.ChannelPoint, error) {
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+	case ctx.Args().Present():
+		chanPointStr = ctx.Args().First()
+	default:
+		return nil, fmt.Errorf("chan_point argument missing")
+	}
+
+	chanPoint, err := parseChanPoint(chanPointStr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	return chanPoint, nil
+}
+
+var spliceInCommand = cli.Command{
+	Name:      "splicein",
+	Category:  "Channels",
+	Usage:     "Add funds to an active channel.",
+	ArgsUsage: "chan_point amt",
+	Description: `
+	Adds funds to an active channel without closing it. The funds are taken
+	from the on-chain wallet, or from an externally funded PSBT if the
+	--psbt flag is set. The channel is paused while the splice transaction
+	is negotiated with the remote peer, and can be used as usual again
+	while the splice transaction confirms. Channel points are encoded as:
+	funding_txid:output_index
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "chan_point",
+			Usage: "the channel that funds should be added to",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of satoshis to add to the channel",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the transaction",
+		},
+		cli.Uint64Flag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of " +
+				"confirmations each one of your outputs used " +
+				"for the splice transaction must satisfy",
+			Value: defaultUtxoMinConf,
+		},
+		cli.BoolFlag{
+			Name: "psbt",
+			Usage: "start an interactive mode that funds the " +
+				"splice using a PSBT created by an external " +
+				"wallet",
+		},
+		cli.StringFlag{
+			Name: "base_psbt",
+			Usage: "when using the interactive PSBT mode, an " +
+				"existing PSBT that the new funding output " +
+				"is added to",
+		},
+		cli.BoolFlag{
+			Name: "no_publish",
+			Usage: "when using the interactive PSBT mode, don't " +
+				"publish the splice transaction once it has " +
+				"been negotiated",
+		},
+	},
+	Action: actionDecorator(spliceIn),
+}
+
+func spliceIn(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	chanPoint, err := parseSpliceChanPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := ctx.Args()
+	if !ctx.IsSet("chan_point") && args.Present() {
+		args = args.Tail()
+	}
+
+	amt := ctx.Int64("amt")
+	if !ctx.IsSet("amt") && args.Present() {
+		_, err = fmt.Sscan(args.First(), &amt)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt: %w", err)
+		}
+	}
+	if amt <= 0 {
+		return fmt.Errorf("amt must be positive")
+	}
+
+	req := &lnrpc.SpliceInRequest{
+		ChannelPoint: chanPoint,
+		LocalAmt:     amt,
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		MinConfs:     int32(ctx.Uint64("min_confs")),
+	}
+	req.SpendUnconfirmed = req.MinConfs == 0
+
+	if !ctx.Bool("psbt") {
+		stream, err := client.SpliceIn(ctxc, req)
+		if err != nil {
+			return err
+		}
+
+		return printSpliceUpdates(stream)
+	}
+
+	if req.SatPerVbyte != 0 || req.TargetConf != 0 {
+		return fmt.Errorf("setting fee estimation parameters not " +
+			"supported for PSBT funding")
+	}
+
+	return spliceInPsbt(ctxc, ctx, client, req)
+}
+
+// printSpliceUpdates prints the updates of a splice until the splice
+// transaction has been negotiated.
+func printSpliceUpdates(stream lnrpc.Lightning_SpliceInClient) error {
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if pending := resp.GetSplicePending(); pending != nil {
+			return printSplicePending(pending)
+		}
+	}
+}
+
+// printSplicePending prints the txid of a negotiated splice transaction.
+func printSplicePending(pending *lnrpc.PendingUpdate) error {
+	txid, err := chainhash.NewHash(pending.Txid)
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		SpliceTxid  string `json:"splice_txid"`
+		OutputIndex uint32 `json:"output_index"`
+	}{
+		SpliceTxid:  txid.String(),
+		OutputIndex: pending.OutputIndex,
+	})
+
+	return nil
+}
+
+// spliceInPsbt funds a splice in interactively using a PSBT that is created and
+// signed by an external wallet.
+func spliceInPsbt(rpcCtx context.Context, ctx *cli.Context,
+	client lnrpc.LightningClient, req *lnrpc.SpliceInRequest) error {
+
+	var (
+		pendingChanID [32]byte
+		basePsbtBytes []byte
+		quit          = make(chan struct{})
+		err           error
+	)
+	ctxc, cancel := context.WithCancel(rpcCtx)
+	defer cancel()
+
+	if basePsbt := ctx.String("base_psbt"); basePsbt != "" {
+		basePsbtBytes, err = base64.StdEncoding.DecodeString(basePsbt)
+		if err != nil {
+			return fmt.Errorf("error parsing base PSBT: %w", err)
+		}
+	}
+
+	if _, err := rand.Read(pendingChanID[:]); err != nil {
+		return fmt.Errorf("unable to generate random chan ID: %w", err)
+	}
+	fmt.Printf("Starting PSBT funding flow with pending channel ID %x.\n",
+		pendingChanID)
+
+	req.PsbtShim = &lnrpc.PsbtShim{
+		PendingChanId: pendingChanID[:],
+		BasePsbt:      basePsbtBytes,
+		NoPublish:     ctx.Bool("no_publish"),
+	}
+
+	stream, err := client.SpliceIn(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("opening stream to server failed: %w", err)
+	}
+
+	// Once the PSBT is finalized, there's nothing left to cancel.
+	shimPending := true
+	defer func() {
+		if !shimPending {
+			return
+		}
+
+		fmt.Printf("Canceling PSBT funding flow for pending channel "+
+			"ID %x.\n", pendingChanID)
+		cancelMsg := &lnrpc.FundingTransitionMsg{
+			Trigger: &lnrpc.FundingTransitionMsg_ShimCancel{
+				ShimCancel: &lnrpc.FundingShimCancel{
+					PendingChanId: pendingChanID[:],
+				},
+			},
+		}
+		err := sendFundingState(rpcCtx, ctx, cancelMsg)
+		if err != nil {
+			fmt.Printf("Error canceling shim: %v\n", err)
+		}
+	}()
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("got error from server: %w", err)
+		}
+
+		switch update := resp.Update.(type) {
+		case *lnrpc.SpliceUpdate_PsbtFund:
+			amt := btcutil.Amount(update.PsbtFund.FundingAmount)
+			fmt.Printf(
+				userMsgSpliceFund, amt, amt,
+				update.PsbtFund.FundingAddress,
+				base64.StdEncoding.EncodeToString(
+					update.PsbtFund.Psbt,
+				),
+			)
+
+			inputPsbt, err := readTerminalOrFile(quit)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading from terminal or "+
+					"file failed: %v", err)
+			}
+			fundedPsbt, err := decodePsbt(inputPsbt)
+			if err != nil {
+				return fmt.Errorf("psbt decode failed: %w",
+					err)
+			}
+			verifyMsg := &lnrpc.FundingTransitionMsg{
+				Trigger: &lnrpc.FundingTransitionMsg_PsbtVerify{
+					PsbtVerify: &lnrpc.FundingPsbtVerify{
+						FundedPsbt:    fundedPsbt,
+						PendingChanId: pendingChanID[:],
+					},
+				},
+			}
+			err = sendFundingState(ctxc, ctx, verifyMsg)
+			if err != nil {
+				return fmt.Errorf("verifying PSBT by lnd "+
+					"failed: %v", err)
+			}
+
+			fmt.Print(userMsgSign)
+
+			finalTxStr, err := readTerminalOrFile(quit)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading from terminal or "+
+					"file failed: %v", err)
+			}
+			finalizeMsg, err := finalizeMsgFromString(
+				finalTxStr, pendingChanID[:],
+			)
+			if err != nil {
+				return err
+			}
+			transitionMsg := &lnrpc.FundingTransitionMsg{
+				Trigger: finalizeMsg,
+			}
+			err = sendFundingState(ctxc, ctx, transitionMsg)
+			if err != nil {
+				return fmt.Errorf("finalizing PSBT funding "+
+					"flow failed: %v", err)
+			}
+			shimPending = false
+
+		case *lnrpc.SpliceUpdate_SplicePending:
+			shimPending = false
+
+			return printSplicePending(update.SplicePending)
+		}
+	}
+}
+
+var spliceOutCommand = cli.Command{
+	Name:      "spliceout",
+	Category:  "Channels",
+	Usage:     "Move funds out of an active channel.",
+	ArgsUsage: "chan_point addr amt",
+	Description: `
+	Moves funds out of an active channel to an on-chain address without
+	closing it. The amount and the fee of the splice transaction are
+	deducted from the local balance of the channel. The channel can be
+	used as usual while the splice transaction confirms. Channel points are
+	encoded as: funding_txid:output_index
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "chan_point",
+			Usage: "the channel that funds should be moved out of",
+		},
+		cli.StringFlag{
+			Name:  "addr",
+			Usage: "the address to send the funds to",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of satoshis to send to the address",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the transaction",
+		},
+	},
+	Action: actionDecorator(spliceOut),
+}
+
+func spliceOut(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	chanPoint, err := parseSpliceChanPoint(ctx)
+	if err != nil {
+		return err
+	}
+	args := ctx.Args()
+	if !ctx.IsSet("chan_point") && args.Present() {
+		args = args.Tail()
+	}
+
+	addr := ctx.String("addr")
+	if !ctx.IsSet("addr") {
+		if !args.Present() {
+			return fmt.Errorf("addr argument missing")
+		}
+		addr = args.First()
+		args = args.Tail()
+	}
+
+	amt := ctx.Int64("amt")
+	if !ctx.IsSet("amt") && args.Present() {
+		_, err = fmt.Sscan(args.First(), &amt)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt: %w", err)
+		}
+	}
+	if amt <= 0 {
+		return fmt.Errorf("amt must be positive")
+	}
+
+	resp, err := client.SpliceOut(ctxc, &lnrpc.SpliceOutRequest{
+		ChannelPoint: chanPoint,
+		Amount:       amt,
+		Addr:         addr,
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+		TargetConf:   int32(ctx.Int64("conf_target")),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -1072,6 +1072,86 @@ func executeChannelClose(ctxc context.Context, client lnrpc.LightningClient,
 	}
 }
 
+var bumpCloseFeeCommand = cli.Command{
+	Name:     "bumpclosefee",
+	Category: "Channels",
+	Usage: "Bump the fee of a cooperative close transaction with " +
+		"RBF.",
+	Description: `
+	Replace the cooperative close transaction of a channel with one paying
+	a higher fee rate. The full fee of the new closing transaction is paid
+	from the local balance of the channel.
+
+	This is only possible if the channel is being closed with the RBF-able
+	cooperative close protocol, which requires protocol.rbf-coop-close to
+	be set and the remote peer to support it.
+
+	The fee rate can be set via either the --conf_target or --sat_per_vbyte
+	arguments. The format for a channel_point is
+	'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid output_index",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel point. If set, " +
+				"funding_txid and output_index flags and " +
+				"positional arguments will be ignored",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the transaction",
+		},
+	},
+	Action: actionDecorator(bumpCloseFee),
+}
+
+func bumpCloseFee(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "bumpclosefee")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.BumpCloseFee(ctxc, &lnrpc.BumpCloseFeeRequest{
+		ChannelPoint: channelPoint,
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var closeAllChannelsCommand = cli.Command{
 	Name:     "closeallchannels",
 	Category: "Channels",
@@ -2570,6 +2650,116 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	return nil
 }
 
+var updateChannelParamsCommand = cli.Command{
+	Name:      "updatechanparams",
+	Category:  "Channels",
+	Usage:     "Renegotiate the parameters of an active channel.",
+	ArgsUsage: "chan_point",
+	Description: `
+	Renegotiates the parameters the remote peer must adhere to within a
+	single active channel, as well as the dust limit of our own commitment,
+	without closing it. The channel is paused while the new parameters are
+	negotiated, and the command returns once they are in effect or the
+	remote peer rejects them. A new dust limit or CSV delay applies from
+	the next commitment on. Parameters that are not set retain their
+	current value. A channel with a static remote key and no active HTLCs
+	can also be upgraded to anchor outputs if both peers support them.
+	Channel points are encoded as:
+	funding_txid:output_index
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel whose parameters should be " +
+				"updated",
+		},
+		cli.Uint64Flag{
+			Name: "remote_max_value_in_flight_msat",
+			Usage: "the maximum value in msat the remote peer " +
+				"may have in flight towards us at once",
+		},
+		cli.Uint64Flag{
+			Name: "remote_chan_reserve_sat",
+			Usage: "the amount in sat the remote peer must keep " +
+				"as its channel reserve",
+		},
+		cli.Uint64Flag{
+			Name: "remote_max_htlcs",
+			Usage: "the maximum number of concurrent HTLCs the " +
+				"remote peer may offer us",
+		},
+		cli.Uint64Flag{
+			Name: "local_dust_limit_sat",
+			Usage: "the dust limit in sat of our own commitment " +
+				"transactions",
+		},
+		cli.Uint64Flag{
+			Name: "remote_csv_delay",
+			Usage: "the number of blocks the remote peer must " +
+				"wait to sweep its funds after a unilateral " +
+				"close",
+		},
+		cli.BoolFlag{
+			Name: "upgrade_to_anchors",
+			Usage: "upgrade the channel to anchor outputs with " +
+				"zero-fee HTLC transactions",
+		},
+	},
+	Action: actionDecorator(updateChannelParams),
+}
+
+func updateChannelParams(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+	case ctx.Args().Present():
+		chanPointStr = ctx.Args().First()
+	default:
+		return fmt.Errorf("chan_point argument missing")
+	}
+
+	chanPoint, err := parseChanPoint(chanPointStr)
+	if err != nil {
+		return fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	maxHtlcs := ctx.Uint64("remote_max_htlcs")
+	if maxHtlcs > math.MaxUint16 {
+		return errors.New("remote_max_htlcs out of range")
+	}
+
+	csvDelay := ctx.Uint64("remote_csv_delay")
+	if csvDelay > math.MaxUint16 {
+		return errors.New("remote_csv_delay out of range")
+	}
+
+	req := &lnrpc.UpdateChannelParamsRequest{
+		ChanPoint: chanPoint,
+		RemoteMaxValueInFlightMsat: ctx.Uint64(
+			"remote_max_value_in_flight_msat",
+		),
+		RemoteChanReserveSat: ctx.Uint64("remote_chan_reserve_sat"),
+		RemoteMaxHtlcs:       uint32(maxHtlcs),
+		LocalDustLimitSat:    ctx.Uint64("local_dust_limit_sat"),
+		RemoteCsvDelay:       uint32(csvDelay),
+		UpgradeToAnchors:     ctx.Bool("upgrade_to_anchors"),
+	}
+
+	resp, err := client.UpdateChannelParams(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var fishCompletionCommand = cli.Command{
 	Name:   "fish-completion",
 	Hidden: true,
@@ -2849,7 +3039,8 @@ var restoreChanBackupCommand = cli.Command{
 	Category: "Channels",
 	Usage: "Restore an existing single or multi-channel static channel " +
 		"backup.",
-	ArgsUsage: "[--single_backup] [--multi_backup] [--multi_file=",
+	ArgsUsage: "[--single_backup] [--multi_backup] [--multi_file=] " +
+		"[--peer_storage]",
 	Description: `
 	Allows a user to restore a Static Channel Backup (SCB) that was
 	obtained either via the exportchanbackup command, or from lnd's
@@ -2874,6 +3065,10 @@ var restoreChanBackupCommand = cli.Command{
 	   * A file path which points to a packed multi-channel backup within a
 	     file, using the same format that lnd does in its channel.backup
 	     file.
+
+	Alternatively, the --peer_storage flag restores the channels from the
+	encrypted backups returned by connected peers that store a backup on
+	behalf of this node.
 	`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -2898,6 +3093,12 @@ var restoreChanBackupCommand = cli.Command{
 			Usage:     "the path to a multi-channel back up file",
 			TakesFile: true,
 		},
+
+		cli.BoolFlag{
+			Name: "peer_storage",
+			Usage: "restore the channel backups returned by " +
+				"peers that store a backup on our behalf",
+		},
 	},
 	Action: actionDecorator(restoreChanBackup),
 }
@@ -2992,6 +3193,22 @@ func restoreChanBackup(ctx *cli.Context) error {
 
 	var req lnrpc.RestoreChanBackupRequest
 
+	if ctx.Bool("peer_storage") {
+		req.Backup = &lnrpc.RestoreChanBackupRequest_PeerStorage{
+			PeerStorage: true,
+		}
+
+		resp, err := client.RestoreChannelBackups(ctxc, &req)
+		if err != nil {
+			return fmt.Errorf("unable to restore chan backups: %w",
+				err)
+		}
+
+		printRespJSON(resp)
+
+		return nil
+	}
+
 	backups, err := parseChanBackups(ctx)
 	if err != nil {
 		return err
@@ -460,6 +460,7 @@ func Main() {
 		openChannelCommand,
 		batchOpenChannelCommand,
 		closeChannelCommand,
+		bumpCloseFeeCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
 		listPeersCommand,
@@ -494,6 +495,9 @@ func Main() {
 		verifyMessageCommand,
 		feeReportCommand,
 		updateChannelPolicyCommand,
+		updateChannelParamsCommand,
+		spliceInCommand,
+		spliceOutCommand,
 		forwardingHistoryCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
@@ -511,6 +515,14 @@ func Main() {
 		deletePaymentsCommand,
 		sendCustomCommand,
 		subscribeCustomCommand,
+		sendOnionMessageCommand,
+		subscribeOnionMessagesCommand,
+		addOfferCommand,
+		listOffersCommand,
+		fetchInvoiceCommand,
+		payOfferCommand,
+		listLiquidityAdsCommand,
+		purchaseLeaseCommand,
 		fishCompletionCommand,
 		listAliasesCommand,
 		estimateRouteFeeCommand,
@@ -13,5 +13,12 @@ func routerCommands() []cli.Command {
 		getCfgCommand,
 		setCfgCommand,
 		updateChanStatusCommand,
+		queryReputationCommand,
+		rebalanceCommand,
+		listRebalancesCommand,
+		simulatePaymentCommand,
+		createMcNamespaceCommand,
+		deleteMcNamespaceCommand,
+		listMcNamespacesCommand,
 	}
 }
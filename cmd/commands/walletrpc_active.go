@@ -134,7 +134,8 @@ var estimateFeeRateCommand = cli.Command{
 	Returns the fee rate estimate for on-chain transactions in sat/kw and
 	sat/vb to achieve a given confirmation target. The source of the fee
 	rate depends on the configuration and is either the on-chain backend or
-	alternatively an external URL.
+	alternatively an external URL. If lnd combines the estimates of several
+	sources, the estimate of each source is listed as well.
 	`,
 	Action: actionDecorator(estimateFeeRate),
 }
@@ -171,11 +172,14 @@ func estimateFeeRate(ctx *cli.Context) error {
 		MinRelayFeeSatPerKw int64 `json:"min_relay_fee_sat_per_kw"`
 		//nolint:lll
 		MinRelayFeeSatPerVByte int64 `json:"min_relay_fee_sat_per_vbyte"`
+		//nolint:lll
+		SourceEstimates []*walletrpc.FeeSourceEstimate `json:"source_estimates,omitempty"`
 	}{
 		SatPerKw:               int64(rateKW),
 		SatPerVByte:            int64(rateVB),
 		MinRelayFeeSatPerKw:    int64(relayFeeKW),
 		MinRelayFeeSatPerVByte: int64(relayFeeVB),
+		SourceEstimates:        resp.SourceEstimates,
 	})
 
 	return nil
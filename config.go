@@ -40,7 +40,9 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/reputation"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/tor"
@@ -244,6 +246,7 @@ const (
 	bitcoindBackendName = "bitcoind"
 	btcdBackendName     = "btcd"
 	neutrinoBackendName = "neutrino"
+	esploraBackendName  = "esplora"
 )
 
 var (
@@ -366,6 +369,9 @@ type Config struct {
 	BtcdMode     *lncfg.Btcd     `group:"btcd" namespace:"btcd"`
 	BitcoindMode *lncfg.Bitcoind `group:"bitcoind" namespace:"bitcoind"`
 	NeutrinoMode *lncfg.Neutrino `group:"neutrino" namespace:"neutrino"`
+	EsploraMode  *lncfg.Esplora  `group:"esplora" namespace:"esplora"`
+
+	ChainFailover *lncfg.ChainFailover `group:"chainfailover" namespace:"chainfailover"`
 
 	BlockCacheSize uint64 `long:"blockcachesize" description:"The maximum capacity of the block cache"`
 
@@ -397,6 +403,7 @@ type Config struct {
 	Color                         string        `long:"color" description:"The color of the node in hex format (i.e. '#3399FF'). Used to customize node appearance in intelligence services"`
 	MinChanSize                   int64         `long:"minchansize" description:"The smallest channel size (in satoshis) that we should accept. Incoming channels smaller than this will be rejected"`
 	MaxChanSize                   int64         `long:"maxchansize" description:"The largest channel size (in satoshis) that we should accept. Incoming channels larger than this will be rejected"`
+	DualFundMaxContribution       int64         `long:"dual-fund-max-contribution" description:"The largest amount (in satoshis) that we contribute to a dual-funded channel opened by a remote peer. We match the amount the opener contributes up to this limit."`
 	CoopCloseTargetConfs          uint32        `long:"coop-close-target-confs" description:"The target number of blocks that a cooperative channel close transaction should confirm in. This is used to estimate the fee to use as the lower bound during fee negotiation for the channel closure."`
 
 	ChannelCommitInterval time.Duration `long:"channel-commit-interval" description:"The maximum time that is allowed to pass between receiving a channel state update and signing the next commitment. Setting this to a longer duration allows for more efficient channel operations at the cost of latency."`
@@ -488,6 +495,10 @@ type Config struct {
 
 	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
 
+	LiquidityAds *lncfg.LiquidityAds `group:"liquidity-ads" namespace:"liquidity-ads"`
+
+	PeerStorage *lncfg.PeerStorage `group:"peer-storage" namespace:"peer-storage"`
+
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
 
 	GRPC *GRPCConfig `group:"grpc" namespace:"grpc"`
@@ -592,6 +603,18 @@ func DefaultConfig() Config {
 			UserAgentName:    neutrino.UserAgentName,
 			UserAgentVersion: neutrino.UserAgentVersion,
 		},
+		EsploraMode: &lncfg.Esplora{
+			RequestTimeout: lncfg.DefaultEsploraRequestTimeout,
+			PollInterval:   lncfg.DefaultEsploraPollInterval,
+		},
+		ChainFailover: &lncfg.ChainFailover{
+			HealthCheck: &lncfg.CheckConfig{
+				Interval: lncfg.DefaultChainFailoverInterval,
+				Timeout:  lncfg.DefaultChainFailoverTimeout,
+				Backoff:  lncfg.DefaultChainFailoverBackoff,
+				Attempts: lncfg.DefaultChainFailoverAttempts,
+			},
+		},
 		BlockCacheSize:     defaultBlockCacheSize,
 		MaxPendingChannels: lncfg.DefaultMaxPendingChannels,
 		NoSeedBackup:       defaultNoSeedBackup,
@@ -600,8 +623,10 @@ func DefaultConfig() Config {
 		ConnectionTimeout:  tor.DefaultConnTimeout,
 
 		Fee: &lncfg.Fee{
-			MinUpdateTimeout: lncfg.DefaultMinUpdateTimeout,
-			MaxUpdateTimeout: lncfg.DefaultMaxUpdateTimeout,
+			MinUpdateTimeout:      lncfg.DefaultMinUpdateTimeout,
+			MaxUpdateTimeout:      lncfg.DefaultMaxUpdateTimeout,
+			MempoolUpdateInterval: chainfee.DefaultMempoolUpdateInterval,
+			MaxDeviation:          chainfee.DefaultMaxFeeDeviation,
 		},
 
 		SubRPCServers: &subRPCServerConfigs{
@@ -725,9 +750,17 @@ func DefaultConfig() Config {
 		RemoteSigner: &lncfg.RemoteSigner{
 			Timeout: lncfg.DefaultRemoteSignerRPCTimeout,
 		},
-		Sweeper: lncfg.DefaultSweeperConfig(),
+		Sweeper:      lncfg.DefaultSweeperConfig(),
+		LiquidityAds: lncfg.DefaultLiquidityAds(),
+		PeerStorage:  lncfg.DefaultPeerStorage(),
 		Htlcswitch: &lncfg.Htlcswitch{
 			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
+			Reputation: lncfg.Reputation{
+				RevenueWindow:    reputation.DefaultRevenueWindow,
+				Multiplier:       reputation.DefaultReputationMultiplier,
+				ResolutionPeriod: reputation.DefaultResolutionPeriod,
+				ProtectedPercent: reputation.DefaultProtectedPercentage,
+			},
 		},
 		GRPC: &GRPCConfig{
 			ServerPingTime:    defaultGrpcServerPingTime,
@@ -1053,6 +1086,25 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 	}
 
+	// A negative dual-funding contribution doesn't make sense.
+	if cfg.DualFundMaxContribution < 0 {
+		return nil, mkErr("dual-fund-max-contribution must not be " +
+			"negative")
+	}
+
+	// Leased channels are opened using the dual-funded channel
+	// establishment protocol, and use script enforced leases.
+	if cfg.LiquidityAds.Active && !cfg.ProtocolOptions.DualFund {
+		return nil, mkErr("liquidity-ads.active requires " +
+			"protocol.dual-fund")
+	}
+	if cfg.LiquidityAds.Active &&
+		cfg.ProtocolOptions.NoScriptEnforcementLease() {
+
+		return nil, mkErr("liquidity-ads.active requires script " +
+			"enforced leases")
+	}
+
 	// Ensure that the amount data for revoked commitment transactions is
 	// stored if the watchtower client is active.
 	if cfg.DB.NoRevLogAmtData && cfg.WtClient.Active {
@@ -1288,17 +1340,47 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	case neutrinoBackendName:
 		// No need to get RPC parameters.
 
+	case esploraBackendName:
+		if err := cfg.EsploraMode.Validate(); err != nil {
+			return nil, mkErr("error validating esplora "+
+				"config: %v", err)
+		}
+
 	case "nochainbackend":
 		// Nothing to configure, we're running without any chain
 		// backend whatsoever (pure signing mode).
 
 	default:
-		str := "only btcd, bitcoind, and neutrino mode " +
+		str := "only btcd, bitcoind, neutrino, and esplora mode " +
 			"supported for bitcoin at this time"
 
 		return nil, mkErr(str)
 	}
 
+	// Failing over is only supported between bitcoind and btcd backends.
+	if len(cfg.ChainFailover.Backends) != 0 {
+		if cfg.Bitcoin.Node != bitcoindBackendName &&
+			cfg.Bitcoin.Node != btcdBackendName {
+
+			return nil, mkErr("chainfailover.backend is only "+
+				"supported with the %v and %v backends",
+				bitcoindBackendName, btcdBackendName)
+		}
+
+		if err := cfg.ChainFailover.Validate(); err != nil {
+			return nil, mkErr("error validating chain failover "+
+				"config: %v", err)
+		}
+
+		_, err := cfg.ChainFailover.ParseBackends(
+			cfg.BitcoindMode, cfg.BtcdMode,
+		)
+		if err != nil {
+			return nil, mkErr("error validating chain failover "+
+				"config: %v", err)
+		}
+	}
+
 	cfg.Bitcoin.ChainDir = filepath.Join(
 		cfg.DataDir, defaultChainSubDirname, BitcoinChainName,
 	)
@@ -1747,6 +1829,9 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.RPCMiddleware,
 		cfg.RemoteSigner,
 		cfg.Sweeper,
+		cfg.Fee,
+		cfg.LiquidityAds,
+		cfg.PeerStorage,
 		cfg.Htlcswitch,
 		cfg.Invoices,
 		cfg.Routing,
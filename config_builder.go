@@ -598,6 +598,8 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		NeutrinoMode:                d.cfg.NeutrinoMode,
 		BitcoindMode:                d.cfg.BitcoindMode,
 		BtcdMode:                    d.cfg.BtcdMode,
+		EsploraMode:                 d.cfg.EsploraMode,
+		ChainFailover:               d.cfg.ChainFailover,
 		HeightHintDB:                dbs.HeightHintDB,
 		ChanStateDB:                 dbs.ChanStateDB.ChannelStateDB(),
 		NeutrinoCS:                  neutrinoCS,
@@ -633,6 +635,18 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		return nil, nil, nil, err
 	}
 
+	// Zero-fee commitments only make it into the mempool as a package
+	// together with a child paying for them, which needs the raw RPC
+	// connection of the chain backend.
+	if d.cfg.ProtocolOptions.ZeroFeeCommitments &&
+		partialChainControl.MempoolRPC == nil {
+
+		err := fmt.Errorf("protocol.zero-fee-commitments is not "+
+			"supported by the %v backend", d.cfg.Bitcoin.Node)
+		d.logger.Error(err)
+		return nil, nil, nil, err
+	}
+
 	walletConfig := &btcwallet.Config{
 		PrivatePass:      privateWalletPw,
 		PublicPass:       publicWalletPw,
@@ -643,6 +657,7 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		Wallet:           walletInitParams.Wallet,
 		LoaderOptions:    []btcwallet.LoaderOption{dbs.WalletDB},
 		ChainSource:      partialChainControl.ChainSource,
+		MempoolRPC:       partialChainControl.MempoolRPC,
 		WatchOnly:        d.watchOnly,
 		MigrateWatchOnly: d.migrateWatchOnly,
 	}
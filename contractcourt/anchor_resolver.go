@@ -102,8 +102,13 @@ func (c *anchorResolver) Resolve(_ bool) (ContractResolver, error) {
 	witnessType := input.CommitmentAnchor
 
 	// For taproot channels, we need to use the proper witness type.
-	if c.chanType.IsTaproot() {
+	// Zero-fee commitments have a keyless pay-to-anchor output instead.
+	switch {
+	case c.chanType.IsTaproot():
 		witnessType = input.TaprootAnchorSweepSpend
+
+	case c.chanType.HasZeroFeeCommitments():
+		witnessType = input.PayToAnchorSpend
 	}
 
 	anchorInput := input.MakeBaseInput(
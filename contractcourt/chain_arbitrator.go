@@ -511,7 +511,18 @@ func (c *ChainArbitrator) ResolveContract(chanPoint wire.OutPoint) error {
 
 	// Now that the channel has been marked as fully closed, we'll stop
 	// both the channel arbitrator and chain watcher for this channel if
-	// they're still active.
+	// they're still active. This is done after marking the channel
+	// resolved, as otherwise, the arbitrator would be re-created, and think
+	// it was starting from the default state.
+	return c.StopWatchingChannel(chanPoint)
+}
+
+// StopWatchingChannel stops the channel arbitrator and chain watcher of the
+// given channel, if they're active, and wipes the log the channel arbitrator
+// was using to store its persistent state. This is used directly for pending
+// channels whose funding outpoint changed, as they're watched again under
+// their new funding outpoint.
+func (c *ChainArbitrator) StopWatchingChannel(chanPoint wire.OutPoint) error {
 	var arbLog ArbitratorLog
 	c.Lock()
 	chainArb := c.activeChannels[chanPoint]
@@ -536,11 +547,6 @@ func (c *ChainArbitrator) ResolveContract(chanPoint wire.OutPoint) error {
 		}
 	}
 
-	// Once this has been marked as resolved, we'll wipe the log that the
-	// channel arbitrator was using to store its persistent state. We do
-	// this after marking the channel resolved, as otherwise, the
-	// arbitrator would be re-created, and think it was starting from the
-	// default state.
 	if arbLog != nil {
 		if err := arbLog.WipeHistory(); err != nil {
 			return err
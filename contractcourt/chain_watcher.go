@@ -2,6 +2,7 @@ package contractcourt
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"slices"
 	"sync"
@@ -64,6 +65,27 @@ type RemoteUnilateralCloseInfo struct {
 	CommitSet CommitSet
 }
 
+// SpliceLockedInfo encapsulates the information about a splice transaction of
+// a channel that has been confirmed.
+type SpliceLockedInfo struct {
+	// SpliceTx is the confirmed splice transaction.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the new funding outpoint of the channel.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the new capacity of the channel.
+	Capacity btcutil.Amount
+
+	// SpliceHeight is the height at which the splice transaction
+	// confirmed.
+	SpliceHeight uint32
+
+	// ShortChanID is the short channel ID of the new funding output, which
+	// the channel is known by to the network once the splice is locked.
+	ShortChanID lnwire.ShortChannelID
+}
+
 // BreachResolution wraps the outpoint of the breached channel.
 type BreachResolution struct {
 	FundingOutPoint wire.OutPoint
@@ -156,6 +178,12 @@ type ChainEventSubscription struct {
 	// material required to bring the cheating channel peer to justice.
 	ContractBreach chan *BreachCloseInfo
 
+	// SpliceLocked is a channel that will be sent upon once a splice
+	// transaction of the channel has reached the depth negotiated for the
+	// channel, so we can lock it on our end. The channel only switches
+	// over to the new funding output once both parties locked the splice.
+	SpliceLocked chan *SpliceLockedInfo
+
 	// Cancel cancels the subscription to the event stream for a particular
 	// channel. This method should be called once the caller no longer needs to
 	// be notified of any on-chain events for a particular channel.
@@ -236,6 +264,11 @@ type chainWatcher struct {
 	// clientSubscriptions is a map that keeps track of all the active
 	// client subscriptions for events related to this channel.
 	clientSubscriptions map[uint64]*ChainEventSubscription
+
+	// confirmedSplice is the splice of the channel that reached the
+	// negotiated depth, if any. It's handed to every new subscriber, as
+	// the splice might not be locked by both parties yet.
+	confirmedSplice *SpliceLockedInfo
 }
 
 // newChainWatcher returns a new instance of a chainWatcher for a channel given
@@ -280,8 +313,9 @@ func (c *chainWatcher) Start() error {
 		chanState.FundingOutpoint)
 
 	// First, we'll register for a notification to be dispatched if the
-	// funding output is spent.
-	fundingOut := &chanState.FundingOutpoint
+	// funding output is spent. If the channel has been spliced, this is
+	// the funding output of the latest splice.
+	fundingOut := chanState.CurrentFundingOutpoint()
 
 	// As a height hint, we'll try to use the opening height, but if the
 	// channel isn't yet open, then we'll use the height it was broadcast
@@ -334,7 +368,7 @@ func (c *chainWatcher) Start() error {
 	}
 
 	spendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
-		fundingOut, c.fundingPkScript, c.heightHint,
+		&fundingOut, c.fundingPkScript, c.heightHint,
 	)
 	if err != nil {
 		return err
@@ -381,6 +415,7 @@ func (c *chainWatcher) SubscribeChannelEvents() *ChainEventSubscription {
 		LocalUnilateralClosure:  make(chan *LocalUnilateralCloseInfo, 1),
 		CooperativeClosure:      make(chan *CooperativeCloseInfo, 1),
 		ContractBreach:          make(chan *BreachCloseInfo, 1),
+		SpliceLocked:            make(chan *SpliceLockedInfo, 1),
 		Cancel: func() {
 			c.Lock()
 			delete(c.clientSubscriptions, clientID)
@@ -390,11 +425,176 @@ func (c *chainWatcher) SubscribeChannelEvents() *ChainEventSubscription {
 
 	c.Lock()
 	c.clientSubscriptions[clientID] = sub
+	if c.confirmedSplice != nil {
+		sub.SpliceLocked <- c.confirmedSplice
+	}
 	c.Unlock()
 
 	return sub
 }
 
+// handleSpliceSpend checks whether the passed spend of the funding output is
+// one of the splice transactions negotiated for the channel. If so, we'll wait
+// for the splice to reach the depth negotiated for the channel, notify all
+// subscribers and launch a new close observer for the new funding output. If
+// the splice is reorged out before that, we'll go back to watching the current
+// funding output. If this isn't a splice, false is returned.
+func (c *chainWatcher) handleSpliceSpend(spendNtfn *chainntnfs.SpendEvent,
+	spend *chainntnfs.SpendDetail) (bool, error) {
+
+	chanState := c.cfg.chanState
+
+	candidates, err := chanState.SpliceCandidates()
+	if err != nil {
+		return false, err
+	}
+
+	spliceTxid := *spend.SpenderTxHash
+	candidateOpt := fn.Find(func(s *channeldb.SpliceCandidate) bool {
+		return s.Txid() == spliceTxid
+	}, candidates)
+	if candidateOpt.IsNone() {
+		return false, nil
+	}
+	candidate := candidateOpt.UnwrapOr(nil)
+
+	// The new funding output shares the pkScript of the current one, so
+	// we can use it to watch for the confirmation of the splice.
+	numConfs := max(uint32(chanState.NumConfsRequired), 1)
+	confNtfn, err := c.cfg.notifier.RegisterConfirmationsNtfn(
+		&spliceTxid, c.fundingPkScript, numConfs,
+		uint32(spend.SpendingHeight),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	log.Infof("Splice %v of ChannelPoint(%v) detected, waiting for %v "+
+		"confirmations", spliceTxid, chanState.FundingOutpoint,
+		numConfs)
+
+	var (
+		spliceHeight uint32
+		spliceScid   lnwire.ShortChannelID
+	)
+	select {
+	case conf, ok := <-confNtfn.Confirmed:
+		// If the channel was closed, then this means that the
+		// notifier exited, so we will as well.
+		if !ok {
+			return true, nil
+		}
+
+		spliceHeight = conf.BlockHeight
+		spliceScid = lnwire.ShortChannelID{
+			BlockHeight: conf.BlockHeight,
+			TxIndex:     conf.TxIndex,
+			TxPosition:  uint16(candidate.FundingOutpoint.Index),
+		}
+
+	// If the splice is reorged out, the current funding output might be
+	// spent by another candidate or a commitment transaction, so we'll
+	// go back to watching it.
+	case <-spendNtfn.Reorg:
+		log.Infof("Splice %v of ChannelPoint(%v) reorged out",
+			spliceTxid, chanState.FundingOutpoint)
+
+		confNtfn.Cancel()
+
+		c.wg.Add(1)
+		go c.closeObserver(spendNtfn)
+
+		return true, nil
+
+	case <-c.quit:
+		confNtfn.Cancel()
+
+		return true, nil
+	}
+
+	log.Infof("Splice %v of ChannelPoint(%v) confirmed, new funding "+
+		"outpoint is %v", spliceTxid, chanState.FundingOutpoint,
+		candidate.FundingOutpoint)
+
+	spliceInfo := &SpliceLockedInfo{
+		SpliceTx:        spend.SpendingTx,
+		FundingOutpoint: candidate.FundingOutpoint,
+		Capacity:        candidate.Capacity,
+		SpliceHeight:    spliceHeight,
+		ShortChanID:     spliceScid,
+	}
+
+	// We'll remember the splice for subscribers that only show up later,
+	// and notify the current ones without holding the lock, so they can
+	// cancel their subscription in the meantime.
+	c.Lock()
+	c.confirmedSplice = spliceInfo
+	subs := make([]*ChainEventSubscription, 0, len(c.clientSubscriptions))
+	for _, sub := range c.clientSubscriptions {
+		subs = append(subs, sub)
+	}
+	c.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.SpliceLocked <- spliceInfo:
+		case <-c.quit:
+			return true, nil
+		}
+	}
+
+	// Now we'll watch the new funding output in place of the old one.
+	c.heightHint = spliceHeight
+	newSpendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
+		&candidate.FundingOutpoint, c.fundingPkScript, c.heightHint,
+	)
+	if err != nil {
+		return true, err
+	}
+
+	c.wg.Add(1)
+	go c.closeObserver(newSpendNtfn)
+
+	return true, nil
+}
+
+// lockConfirmedSplice locks the confirmed splice of the channel if the passed
+// transaction spends its funding output before both parties exchanged
+// splice_locked. This makes sure the spend is matched against the commitments
+// of the splice.
+func (c *chainWatcher) lockConfirmedSplice(spendingTx *wire.MsgTx) error {
+	c.Lock()
+	spliceInfo := c.confirmedSplice
+	c.Unlock()
+
+	if spliceInfo == nil {
+		return nil
+	}
+
+	spendsSplice := fn.Any(func(txIn *wire.TxIn) bool {
+		return txIn.PreviousOutPoint == spliceInfo.FundingOutpoint
+	}, spendingTx.TxIn)
+	if !spendsSplice {
+		return nil
+	}
+
+	chanState := c.cfg.chanState
+	_, err := chanState.LockSplice(
+		spliceInfo.SpliceTx.TxHash(), spliceInfo.ShortChanID,
+	)
+	switch {
+	// The splice was already locked by the link, so we only need to catch
+	// up with the persisted channel state.
+	case errors.Is(err, channeldb.ErrSpliceCandidateNotFound):
+		return chanState.Refresh()
+
+	case err != nil:
+		return err
+	}
+
+	return nil
+}
+
 // handleUnknownLocalState checks whether the passed spend _could_ be a local
 // state that for some reason is unknown to us. This could be a state published
 // by us before we lost state, which we will try to sweep. Or it could be one
@@ -421,11 +621,17 @@ func (c *chainWatcher) handleUnknownLocalState(
 	}
 	commitPoint := input.ComputeCommitmentPoint(commitSecret[:])
 
+	// A past state may have been created before the channel type was
+	// upgraded, so we'll use the type it was created with.
+	chanType := c.cfg.chanState.ChanTypeAt(
+		lntypes.Local, broadcastStateNum,
+	)
+
 	// Now that we have the commit point, we'll derive the tweaked local
 	// and remote keys for this state. We use our point as only we can
 	// revoke our own commitment.
 	commitKeyRing := lnwallet.DeriveCommitmentKeys(
-		commitPoint, lntypes.Local, c.cfg.chanState.ChanType,
+		commitPoint, lntypes.Local, chanType,
 		&c.cfg.chanState.LocalChanCfg, &c.cfg.chanState.RemoteChanCfg,
 	)
 
@@ -446,7 +652,7 @@ func (c *chainWatcher) handleUnknownLocalState(
 	// With the keys derived, we'll construct the remote script that'll be
 	// present if they have a non-dust balance on the commitment.
 	var leaseExpiry uint32
-	if c.cfg.chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = c.cfg.chanState.ThawHeight
 	}
 
@@ -456,7 +662,7 @@ func (c *chainWatcher) handleUnknownLocalState(
 		},
 	)(auxResult.AuxLeaves)
 	remoteScript, _, err := lnwallet.CommitScriptToRemote(
-		c.cfg.chanState.ChanType, c.cfg.chanState.IsInitiator,
+		chanType, c.cfg.chanState.IsInitiator,
 		commitKeyRing.ToRemoteKey, leaseExpiry,
 		remoteAuxLeaf,
 	)
@@ -472,11 +678,16 @@ func (c *chainWatcher) handleUnknownLocalState(
 			return l.LocalAuxLeaf
 		},
 	)(auxResult.AuxLeaves)
+	//
+	// The commitment may have been created before our commitment params
+	// were changed, so we'll use the csv delay it was created with.
+	csvDelay := c.cfg.chanState.CommitParamsAt(
+		lntypes.Local, broadcastStateNum,
+	).CsvDelay
 	localScript, err := lnwallet.CommitScriptToSelf(
-		c.cfg.chanState.ChanType, c.cfg.chanState.IsInitiator,
+		chanType, c.cfg.chanState.IsInitiator,
 		commitKeyRing.ToLocalKey, commitKeyRing.RevocationKey,
-		uint32(c.cfg.chanState.LocalChanCfg.CsvDelay), leaseExpiry,
-		localAuxLeaf,
+		uint32(csvDelay), leaseExpiry, localAuxLeaf,
 	)
 	if err != nil {
 		return false, err
@@ -557,12 +768,21 @@ func newChainSet(chanState *channeldb.OpenChannel) (*chainSet, error) {
 			"chan_point=%v", chanState.FundingOutpoint)
 	}
 
+	// The commitment params may have been renegotiated by the link since
+	// we last read them, so we'll refresh them as well.
+	if err := chanState.RefreshChannelParams(); err != nil {
+		return nil, fmt.Errorf("unable to refresh channel params for "+
+			"chan_point=%v: %w", chanState.FundingOutpoint, err)
+	}
+
 	log.Tracef("ChannelPoint(%v): local_commit_type=%v, local_commit=%v",
-		chanState.FundingOutpoint, chanState.ChanType,
-		spew.Sdump(localCommit))
+		chanState.FundingOutpoint, chanState.ChanTypeAt(
+			lntypes.Local, localCommit.CommitHeight,
+		), spew.Sdump(localCommit))
 	log.Tracef("ChannelPoint(%v): remote_commit_type=%v, remote_commit=%v",
-		chanState.FundingOutpoint, chanState.ChanType,
-		spew.Sdump(remoteCommit))
+		chanState.FundingOutpoint, chanState.ChanTypeAt(
+			lntypes.Remote, remoteCommit.CommitHeight,
+		), spew.Sdump(remoteCommit))
 
 	// Fetch the current known commit height for the remote party, and
 	// their pending commitment chain tip if it exists.
@@ -669,6 +889,28 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			return
 		}
 
+		// If the funding output was spent by a splice transaction we
+		// negotiated, then the channel isn't closed but continues with
+		// the new funding output.
+		spliced, err := c.handleSpliceSpend(spendNtfn, commitSpend)
+		if err != nil {
+			log.Errorf("Unable to handle splice: %v", err)
+			return
+		}
+
+		if spliced {
+			return
+		}
+
+		// If this is the funding output of a confirmed splice, the
+		// channel has to be switched over to its commitments before
+		// we can tell what kind of spend this is.
+		err = c.lockConfirmedSplice(commitSpend.SpendingTx)
+		if err != nil {
+			log.Errorf("Unable to lock splice: %v", err)
+			return
+		}
+
 		// Otherwise, the remote party might have broadcast a prior
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
@@ -924,8 +1166,9 @@ func (c *chainWatcher) handlePossibleBreach(commitSpend *chainntnfs.SpendDetail,
 
 	// Create an AnchorResolution for the breached state.
 	anchorRes, err := lnwallet.NewAnchorResolution(
-		c.cfg.chanState, commitSpend.SpendingTx, retribution.KeyRing,
-		lntypes.Remote,
+		c.cfg.chanState, c.cfg.chanState.ChanTypeAt(
+			lntypes.Remote, broadcastStateNum,
+		), commitSpend.SpendingTx, retribution.KeyRing, lntypes.Remote,
 	)
 	if err != nil {
 		return false, fmt.Errorf("unable to create anchor "+
@@ -8,11 +8,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/require"
@@ -79,6 +81,125 @@ func TestChainWatcherRemoteUnilateralClose(t *testing.T) {
 	}
 }
 
+// TestChainWatcherSplice tests that the chain watcher waits for a splice
+// transaction to confirm instead of treating it as a channel close, that it
+// goes back to watching the current funding output if the splice is reorged
+// out, and that it goes on to watch the new funding output once the splice
+// confirmed.
+func TestChainWatcherSplice(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	// Alice negotiated a splice that adds funds to the channel.
+	aliceState := aliceChannel.State()
+	oldFundingOutpoint := aliceState.FundingOutpoint
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	spliceTx.AddTxIn(wire.NewTxIn(&aliceState.FundingOutpoint, nil, nil))
+	spliceTx.AddTxOut(&wire.TxOut{
+		PkScript: aliceChannel.FundingPkScript(),
+		Value:    int64(aliceState.Capacity + 100_000),
+	})
+
+	candidate, err := aliceChannel.NewSpliceCandidate(
+		spliceTx, lntypes.Dual[btcutil.Amount]{Local: 100_000},
+	)
+	require.NoError(t, err)
+	require.NoError(t, aliceState.AddSpliceCandidate(candidate))
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan:      make(chan *chainntnfs.SpendDetail),
+		EpochChan:      make(chan *chainntnfs.BlockEpoch),
+		ConfChan:       make(chan *chainntnfs.TxConfirmation),
+		SpendReorgChan: make(chan struct{}),
+	}
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceState,
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	require.NoError(t, aliceChainWatcher.Start())
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	// The splice transaction is included in a block, but then reorged out
+	// again before it reached the required depth.
+	spliceTxid := spliceTx.TxHash()
+	spliceSpend := &chainntnfs.SpendDetail{
+		SpenderTxHash:  &spliceTxid,
+		SpendingTx:     spliceTx,
+		SpendingHeight: 100,
+	}
+	aliceNotifier.SpendChan <- spliceSpend
+	aliceNotifier.SpendReorgChan <- struct{}{}
+
+	// Once it's mined again and confirmed, the watcher should notify its
+	// subscribers.
+	aliceNotifier.SpendChan <- spliceSpend
+	aliceNotifier.ConfChan <- &chainntnfs.TxConfirmation{
+		BlockHeight: 101,
+		TxIndex:     7,
+	}
+
+	var spliceInfo *SpliceLockedInfo
+	select {
+	case spliceInfo = <-chanEvents.SpliceLocked:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive splice locked event")
+	}
+	require.Equal(t, candidate.FundingOutpoint, spliceInfo.FundingOutpoint)
+	require.Equal(t, candidate.Capacity, spliceInfo.Capacity)
+	require.EqualValues(t, 101, spliceInfo.SpliceHeight)
+	require.Equal(t, lnwire.ShortChannelID{
+		BlockHeight: 101,
+		TxIndex:     7,
+		TxPosition:  uint16(candidate.FundingOutpoint.Index),
+	}, spliceInfo.ShortChanID)
+
+	// The splice isn't locked by the watcher itself, as that requires the
+	// remote party's splice_locked as well.
+	require.Equal(t, oldFundingOutpoint, aliceState.CurrentFundingOutpoint())
+
+	// A subscriber that shows up later learns about the splice as well.
+	lateEvents := aliceChainWatcher.SubscribeChannelEvents()
+	select {
+	case spliceInfo = <-lateEvents.SpliceLocked:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive splice locked event")
+	}
+	require.Equal(t, candidate.FundingOutpoint, spliceInfo.FundingOutpoint)
+
+	// If Bob now broadcasts his commitment spending the new funding
+	// output, the splice is locked and this should be detected as a
+	// unilateral close.
+	bobCommit := candidate.RemoteCommitment.CommitTx
+	bobTxHash := bobCommit.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash: &bobTxHash,
+		SpendingTx:    bobCommit,
+	}
+
+	select {
+	case uniClose := <-chanEvents.RemoteUnilateralClosure:
+		require.NotNil(t, uniClose.CommitResolution)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive unilateral close event")
+	}
+	require.Equal(
+		t, candidate.FundingOutpoint, aliceState.CurrentFundingOutpoint(),
+	)
+}
+
 func addFakeHTLC(t *testing.T, htlcAmount lnwire.MilliSatoshi, id uint64,
 	aliceChannel, bobChannel *lnwallet.LightningChannel) {
 
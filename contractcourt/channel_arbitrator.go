@@ -1140,11 +1140,20 @@ func (c *ChannelArbitrator) stateStep(
 			c.cfg.ChanPoint, lnutils.SpewLogClosure(closeTx))
 
 		// At this point, we'll now broadcast the commitment
-		// transaction itself.
+		// transaction itself. Zero-fee commitments can't be relayed
+		// on their own, so they're submitted as a package together
+		// with the anchor sweep once that's offered to the sweeper.
 		label := labels.MakeLabel(
 			labels.LabelTypeChannelClose, &c.cfg.ShortChanID,
 		)
-		if err := c.cfg.PublishTx(closeTx, label); err != nil {
+		zeroFee, _ := input.FindScriptOutputIndex(
+			closeTx, input.PayToAnchorScript(),
+		)
+		if zeroFee {
+			log.Infof("ChannelArbitrator(%v): zero-fee close tx "+
+				"will be published along with the anchor sweep",
+				c.cfg.ChanPoint)
+		} else if err := c.cfg.PublishTx(closeTx, label); err != nil {
 			log.Errorf("ChannelArbitrator(%v): unable to broadcast "+
 				"close tx: %v", c.cfg.ChanPoint, err)
 
@@ -3001,6 +3010,15 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 				log.Errorf("Unable to advance state: %v", err)
 			}
 
+		// A splice of the channel has confirmed. The channel remains
+		// open with a new funding output, which the chain watcher is
+		// already watching, so there's nothing for us to do.
+		case spliceInfo := <-c.cfg.ChainEvents.SpliceLocked:
+			log.Infof("ChannelArbitrator(%v): splice confirmed at "+
+				"height=%v, new funding outpoint %v",
+				c.cfg.ChanPoint, spliceInfo.SpliceHeight,
+				spliceInfo.FundingOutpoint)
+
 		// A new contract has just been resolved, we'll now check our
 		// log to see if all contracts have been resolved. If so, then
 		// we can exit as the contract is fully resolved.
@@ -3141,12 +3159,15 @@ func (c *ChannelArbitrator) createSweepRequest(
 
 	witnessType := input.CommitmentAnchor
 
-	// For taproot channels, we need to use the proper witness type.
-	if txscript.IsPayToTaproot(
-		anchor.AnchorSignDescriptor.Output.PkScript,
-	) {
-
+	// For taproot channels, we need to use the proper witness type, and
+	// zero-fee commitments have a keyless pay-to-anchor output instead.
+	anchorScript := anchor.AnchorSignDescriptor.Output.PkScript
+	switch {
+	case txscript.IsPayToTaproot(anchorScript):
 		witnessType = input.TaprootAnchorSweepSpend
+
+	case input.IsPayToAnchor(anchorScript):
+		witnessType = input.PayToAnchorSpend
 	}
 
 	// Prepare anchor output for sweeping.
@@ -3158,6 +3179,7 @@ func (c *ChannelArbitrator) createSweepRequest(
 		&input.TxInfo{
 			Fee:    anchor.CommitFee,
 			Weight: anchor.CommitWeight,
+			Tx:     anchor.CommitTx,
 		},
 	)
 
@@ -3172,11 +3194,20 @@ func (c *ChannelArbitrator) createSweepRequest(
 
 	// Calculate the budget based on the value under protection, which is
 	// the sum of all HTLCs on this commitment subtracted by their budgets.
-	// The anchor output in itself has a small output value of 330 sats so
-	// we also include it in the budget to pay for the cpfp transaction.
+	// A zero-fee commitment can't confirm without the cpfp transaction, so
+	// our own balance on it is under protection as well. The anchor output
+	// in itself has a small output value, so we also include it in the
+	// budget to pay for the cpfp transaction.
+	anchorValue := AnchorOutputValue
+	if witnessType == input.PayToAnchorSpend {
+		value += anchor.CommitBalance
+		anchorValue = btcutil.Amount(
+			anchor.AnchorSignDescriptor.Output.Value,
+		)
+	}
 	budget := calculateBudget(
 		value, c.cfg.Budget.AnchorCPFPRatio, c.cfg.Budget.AnchorCPFP,
-	) + AnchorOutputValue
+	) + anchorValue
 
 	log.Infof("ChannelArbitrator(%v): offering anchor from %s commitment "+
 		"%v to sweeper with deadline=%v, budget=%v", c.cfg.ChanPoint,
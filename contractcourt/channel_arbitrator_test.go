@@ -339,6 +339,7 @@ func createTestChannelArbitrator(t *testing.T, log ArbitratorLog,
 		LocalUnilateralClosure:  make(chan *LocalUnilateralCloseInfo, 1),
 		CooperativeClosure:      make(chan *CooperativeCloseInfo, 1),
 		ContractBreach:          make(chan *BreachCloseInfo, 1),
+		SpliceLocked:            make(chan *SpliceLockedInfo, 1),
 	}
 
 	resolutionChan := make(chan []ResolutionMsg, 1)
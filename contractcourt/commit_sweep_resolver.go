@@ -50,14 +50,14 @@ type commitSweepResolver struct {
 	// chanPoint is the channel point of the original contract.
 	chanPoint wire.OutPoint
 
-	// channelInitiator denotes whether the party responsible for resolving
-	// the contract initiated the channel.
-	channelInitiator bool
+	// lessor denotes whether the party responsible for resolving the
+	// contract sold the lease of a leased channel.
+	lessor bool
 
 	// leaseExpiry denotes the additional waiting period the contract must
 	// hold until it can be resolved. This waiting period is known as the
 	// expiration of a script-enforced leased channel and only applies to
-	// the channel initiator.
+	// the lessor.
 	//
 	// NOTE: This value should only be set when the contract belongs to a
 	// leased channel.
@@ -311,7 +311,7 @@ func (c *commitSweepResolver) Resolve(_ bool) (ContractResolver, error) {
 		witnessType = input.TaprootRemoteCommitSpend
 
 	// Delayed output to us on our local commitment for a channel lease in
-	// which we are the initiator.
+	// which we are the lessor.
 	case isLocalCommitTx && c.hasCLTV():
 		witnessType = input.LeaseCommitmentTimeLock
 
@@ -320,7 +320,7 @@ func (c *commitSweepResolver) Resolve(_ bool) (ContractResolver, error) {
 		witnessType = input.CommitmentTimeLock
 
 	// A confirmed output to us on the remote commitment for a channel lease
-	// in which we are the initiator.
+	// in which we are the lessor.
 	case isDelayedOutput && c.hasCLTV():
 		witnessType = input.LeaseCommitmentToRemoteConfirmed
 
@@ -472,14 +472,14 @@ func (c *commitSweepResolver) SupplementState(state *channeldb.OpenChannel) {
 		c.leaseExpiry = state.ThawHeight
 	}
 	c.localChanCfg = state.LocalChanCfg
-	c.channelInitiator = state.IsInitiator
+	c.lessor = state.ChanType.IsLessor(state.IsInitiator)
 	c.chanType = state.ChanType
 }
 
 // hasCLTV denotes whether the resolver must wait for an additional CLTV to
 // expire before resolving the contract.
 func (c *commitSweepResolver) hasCLTV() bool {
-	return c.channelInitiator && c.leaseExpiry > 0
+	return c.lessor && c.leaseExpiry > 0
 }
 
 // Encode writes an encoded version of the ContractResolver into the passed
@@ -13,14 +13,14 @@ import (
 // logic. This includes deriving the _true_ waiting height, as well as the
 // input to offer to the sweeper.
 type htlcLeaseResolver struct {
-	// channelInitiator denotes whether the party responsible for resolving
-	// the contract initiated the channel.
-	channelInitiator bool
+	// lessor denotes whether the party responsible for resolving the
+	// contract sold the lease of a leased channel.
+	lessor bool
 
 	// leaseExpiry denotes the additional waiting period the contract must
 	// hold until it can be resolved. This waiting period is known as the
 	// expiration of a script-enforced leased channel and only applies to
-	// the channel initiator.
+	// the lessor.
 	//
 	// NOTE: This value should only be set when the contract belongs to a
 	// leased channel.
@@ -30,7 +30,7 @@ type htlcLeaseResolver struct {
 // hasCLTV denotes whether the resolver must wait for an additional CLTV to
 // expire before resolving the contract.
 func (h *htlcLeaseResolver) hasCLTV() bool {
-	return h.channelInitiator && h.leaseExpiry > 0
+	return h.lessor && h.leaseExpiry > 0
 }
 
 // deriveWaitHeight computes the height the resolver needs to wait until it can
@@ -80,5 +80,5 @@ func (h *htlcLeaseResolver) SupplementState(state *channeldb.OpenChannel) {
 	if state.ChanType.HasLeaseExpiration() {
 		h.leaseExpiry = state.ThawHeight
 	}
-	h.channelInitiator = state.IsInitiator
+	h.lessor = state.ChanType.IsLessor(state.IsInitiator)
 }
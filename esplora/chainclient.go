@@ -0,0 +1,667 @@
+package esplora
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+const (
+	// backEndName is the name of the Esplora chain backend.
+	backEndName = "esplora"
+
+	// isCurrentDelta is the maximum age of the best block for which the
+	// server is considered to be synced to the tip of the chain.
+	isCurrentDelta = 2 * time.Hour
+)
+
+var (
+	// errClientShuttingDown is returned when the client is stopped while
+	// a request is pending.
+	errClientShuttingDown = errors.New("esplora chain client shutting " +
+		"down")
+
+	// bitcoindErrMapper is used to map the errors of a broadcast. Esplora
+	// servers relay the errors of their bitcoind backend, which can be
+	// mapped like the ones of a bitcoind client. The mapping doesn't
+	// access the client itself.
+	bitcoindErrMapper *chain.BitcoindClient
+)
+
+// rescanReq is a request to rescan the chain from the given block.
+type rescanReq struct {
+	startHash chainhash.Hash
+	err       chan error
+}
+
+// ChainClient is an implementation of the btcwallet chain.Interface that is
+// backed by an Esplora server. Instead of scanning every block, rescans are
+// performed using the transaction history of the watched addresses. Blocks
+// connected at the tip are filtered for watched addresses and outpoints.
+//
+// NOTE: Unconfirmed transactions that we didn't broadcast ourselves are only
+// noticed once they confirm.
+type ChainClient struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	conn        *Conn
+	chainParams *chaincfg.Params
+	blockCache  *blockcache.BlockCache
+
+	sub        *BlockSubscription
+	ntfnQueue  *queue.ConcurrentQueue
+	rescanReqs chan *rescanReq
+
+	// mtx guards the fields below.
+	mtx sync.Mutex
+
+	// bestBlock is the last block that the client notified about.
+	bestBlock *Block
+
+	// watchedScripts is the set of output scripts of the watched
+	// addresses.
+	watchedScripts map[string]struct{}
+
+	// watchedOutPoints is the set of outpoints whose spends are watched.
+	watchedOutPoints map[wire.OutPoint]struct{}
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// A compile time check to ensure ChainClient implements the chain.Interface.
+var _ chain.Interface = (*ChainClient)(nil)
+
+// NewChainClient creates a new wallet chain source that uses the connection.
+// Blocks that are fetched to filter them are stored in the given cache.
+func (c *Conn) NewChainClient(chainParams *chaincfg.Params,
+	blockCache *blockcache.BlockCache) *ChainClient {
+
+	return &ChainClient{
+		conn:             c,
+		chainParams:      chainParams,
+		blockCache:       blockCache,
+		ntfnQueue:        queue.NewConcurrentQueue(20),
+		rescanReqs:       make(chan *rescanReq),
+		watchedScripts:   make(map[string]struct{}),
+		watchedOutPoints: make(map[wire.OutPoint]struct{}),
+		quit:             make(chan struct{}),
+	}
+}
+
+// Start subscribes to the blocks of the connection and notifies the wallet
+// that the client is connected.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) Start() error {
+	if !atomic.CompareAndSwapInt32(&c.started, 0, 1) {
+		return nil
+	}
+
+	sub, err := c.conn.SubscribeBlocks()
+	if err != nil {
+		return err
+	}
+	c.sub = sub
+
+	c.mtx.Lock()
+	c.bestBlock = sub.BestBlock
+	c.mtx.Unlock()
+
+	c.ntfnQueue.Start()
+	c.notify(chain.ClientConnected{})
+
+	c.wg.Add(1)
+	go c.ntfnHandler()
+
+	return nil
+}
+
+// Stop stops the client.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) Stop() {
+	if !atomic.CompareAndSwapInt32(&c.stopped, 0, 1) {
+		return
+	}
+
+	close(c.quit)
+	if c.sub != nil {
+		c.sub.Cancel()
+		c.ntfnQueue.Stop()
+	}
+}
+
+// WaitForShutdown blocks until the client has shut down.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// notify queues a notification for the wallet.
+func (c *ChainClient) notify(ntfn interface{}) {
+	select {
+	case c.ntfnQueue.ChanIn() <- ntfn:
+	case <-c.quit:
+	}
+}
+
+// ntfnHandler processes the block events of the connection and the rescan
+// requests of the wallet. Both are handled in the same goroutine, so that
+// the notifications of a rescan and of new blocks don't interleave.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *ChainClient) ntfnHandler() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case event := <-c.sub.Events:
+			if err := c.handleBlockEvent(event); err != nil {
+				log.Errorf("Unable to handle block %v: %v",
+					event.Block.Hash, err)
+			}
+
+		case req := <-c.rescanReqs:
+			req.err <- c.rescan(&req.startHash)
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// handleBlockEvent notifies the wallet about a connected or disconnected
+// block. Connected blocks are filtered for relevant transactions.
+func (c *ChainClient) handleBlockEvent(event *BlockEvent) error {
+	block := event.Block
+
+	if event.Disconnected {
+		prevHash := &block.Header.PrevBlock
+		prevHeader, err := c.conn.GetBlockHeader(prevHash)
+		if err != nil {
+			return err
+		}
+
+		c.mtx.Lock()
+		c.bestBlock = &Block{
+			Height: block.Height - 1,
+			Hash:   block.Header.PrevBlock,
+			Header: prevHeader,
+		}
+		c.mtx.Unlock()
+
+		c.notify(chain.BlockDisconnected{
+			Block: wtxmgr.Block{
+				Hash:   block.Hash,
+				Height: block.Height,
+			},
+			Time: block.Header.Timestamp,
+		})
+
+		return nil
+	}
+
+	meta := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   block.Hash,
+			Height: block.Height,
+		},
+		Time: block.Header.Timestamp,
+	}
+
+	var relevantTxs []*wtxmgr.TxRecord
+	if c.isWatching() {
+		rawBlock, err := c.GetBlock(&block.Hash)
+		if err != nil {
+			return err
+		}
+
+		relevantTxs, err = c.filterBlock(rawBlock, meta.Time)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mtx.Lock()
+	c.bestBlock = block
+	c.mtx.Unlock()
+
+	c.notify(chain.FilteredBlockConnected{
+		Block:       meta,
+		RelevantTxs: relevantTxs,
+	})
+	c.notify(chain.BlockConnected(*meta))
+
+	return nil
+}
+
+// isWatching returns true if any address or outpoint is watched.
+func (c *ChainClient) isWatching() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return len(c.watchedScripts) > 0 || len(c.watchedOutPoints) > 0
+}
+
+// filterBlock returns the transactions of the block that pay to a watched
+// address or spend a watched outpoint. The outputs that pay to watched
+// addresses are watched from then on.
+func (c *ChainClient) filterBlock(block *wire.MsgBlock,
+	blockTime time.Time) ([]*wtxmgr.TxRecord, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var relevantTxs []*wtxmgr.TxRecord
+	for _, tx := range block.Transactions {
+		var relevant bool
+		for _, txIn := range tx.TxIn {
+			_, ok := c.watchedOutPoints[txIn.PreviousOutPoint]
+			if ok {
+				relevant = true
+			}
+		}
+
+		txHash := tx.TxHash()
+		for i, txOut := range tx.TxOut {
+			pkScript := string(txOut.PkScript)
+			if _, ok := c.watchedScripts[pkScript]; !ok {
+				continue
+			}
+
+			relevant = true
+			c.watchedOutPoints[wire.OutPoint{
+				Hash:  txHash,
+				Index: uint32(i),
+			}] = struct{}{}
+		}
+
+		if !relevant {
+			continue
+		}
+
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, blockTime)
+		if err != nil {
+			return nil, err
+		}
+		relevantTxs = append(relevantTxs, rec)
+	}
+
+	return relevantTxs, nil
+}
+
+// watchAddrs adds the output scripts of the given addresses to the watched
+// scripts.
+func (c *ChainClient) watchAddrs(addrs []btcutil.Address) error {
+	scripts := make([][]byte, 0, len(addrs))
+	for _, addr := range addrs {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return fmt.Errorf("unable to create script for %v: %w",
+				addr, err)
+		}
+		scripts = append(scripts, pkScript)
+	}
+
+	c.mtx.Lock()
+	for _, pkScript := range scripts {
+		c.watchedScripts[string(pkScript)] = struct{}{}
+	}
+	c.mtx.Unlock()
+
+	return nil
+}
+
+// rescan notifies the wallet about all transactions of the watched addresses
+// and outpoints from the given block up to our best block. Only the blocks
+// that include such transactions are fetched, which are found using the
+// transaction history of the watched scripts.
+func (c *ChainClient) rescan(startHash *chainhash.Hash) error {
+	startHeight, err := c.conn.GetBlockHeight(startHash)
+	if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	bestBlock := c.bestBlock
+	scripts := make([][]byte, 0, len(c.watchedScripts))
+	for pkScript := range c.watchedScripts {
+		scripts = append(scripts, []byte(pkScript))
+	}
+	c.mtx.Unlock()
+
+	// Collect the blocks that include transactions of the watched
+	// scripts. Blocks above our best block are filtered once we're
+	// notified about them.
+	blocks := make(map[int32]chainhash.Hash)
+	for _, pkScript := range scripts {
+		select {
+		case <-c.quit:
+			return errClientShuttingDown
+		default:
+		}
+
+		txs, err := c.conn.GetScriptTxs(pkScript, startHeight)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range txs {
+			height := tx.Status.BlockHeight
+			if height > bestBlock.Height {
+				continue
+			}
+
+			hash, err := chainhash.NewHashFromStr(
+				tx.Status.BlockHash,
+			)
+			if err != nil {
+				return err
+			}
+			blocks[height] = *hash
+		}
+	}
+
+	heights := make([]int32, 0, len(blocks))
+	for height := range blocks {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	// The blocks are filtered in order, so that outputs that are created
+	// in one block are watched when they are spent in a later one.
+	for _, height := range heights {
+		hash := blocks[height]
+		block, err := c.GetBlock(&hash)
+		if err != nil {
+			return err
+		}
+
+		blockTime := block.Header.Timestamp
+		relevantTxs, err := c.filterBlock(block, blockTime)
+		if err != nil {
+			return err
+		}
+
+		meta := &wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   hash,
+				Height: height,
+			},
+			Time: blockTime,
+		}
+		c.notify(chain.FilteredBlockConnected{
+			Block:       meta,
+			RelevantTxs: relevantTxs,
+		})
+		c.notify(&chain.RescanProgress{
+			Hash:   hash,
+			Height: height,
+			Time:   blockTime,
+		})
+	}
+
+	log.Debugf("Rescanned %d blocks with relevant transactions from "+
+		"height %d to %d", len(heights), startHeight, bestBlock.Height)
+
+	c.notify(&chain.RescanFinished{
+		Hash:   &bestBlock.Hash,
+		Height: bestBlock.Height,
+		Time:   bestBlock.Header.Timestamp,
+	})
+
+	return nil
+}
+
+// GetBestBlock returns the hash and height of the last block that the
+// client notified about.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	bestBlock, err := c.getBestBlock()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &bestBlock.Hash, bestBlock.Height, nil
+}
+
+// getBestBlock returns the last block that the client notified about, or
+// the best block of the connection if the client isn't started yet.
+func (c *ChainClient) getBestBlock() (*Block, error) {
+	c.mtx.Lock()
+	bestBlock := c.bestBlock
+	c.mtx.Unlock()
+
+	if bestBlock != nil {
+		return bestBlock, nil
+	}
+
+	return c.conn.BestBlock()
+}
+
+// GetBlock returns the block with the given hash.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return c.blockCache.GetBlock(hash, c.conn.GetBlock)
+}
+
+// GetBlockHash returns the hash of the block at the given height.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return c.conn.GetBlockHash(height)
+}
+
+// GetBlockHeader returns the header of the block with the given hash.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	return c.conn.GetBlockHeader(hash)
+}
+
+// GetRawTransaction returns the transaction with the given id.
+func (c *ChainClient) GetRawTransaction(
+	txid *chainhash.Hash) (*wire.MsgTx, error) {
+
+	return c.conn.GetRawTransaction(txid)
+}
+
+// GetTxOutSpend returns whether the given output is spent, and by which
+// transaction.
+func (c *ChainClient) GetTxOutSpend(op *wire.OutPoint) (*OutSpend, error) {
+	return c.conn.GetTxOutSpend(op)
+}
+
+// IsCurrent returns true if the best block is recent enough for the server
+// to be considered synced to the tip of the chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) IsCurrent() bool {
+	bestBlock, err := c.getBestBlock()
+	if err != nil {
+		return false
+	}
+
+	return bestBlock.Header.Timestamp.After(
+		time.Now().Add(-isCurrentDelta),
+	)
+}
+
+// FilterBlocks scans the requested blocks for the addresses and outpoints of
+// the request, stopping at the first block with matches.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) FilterBlocks(
+	req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+
+	blockFilterer := chain.NewBlockFilterer(c.chainParams, req)
+
+	for i, blockMeta := range req.Blocks {
+		block, err := c.GetBlock(&blockMeta.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if !blockFilterer.FilterBlock(block) {
+			continue
+		}
+
+		return &chain.FilterBlocksResponse{
+			BatchIndex:         uint32(i),
+			BlockMeta:          blockMeta,
+			FoundExternalAddrs: blockFilterer.FoundExternal,
+			FoundInternalAddrs: blockFilterer.FoundInternal,
+			FoundOutPoints:     blockFilterer.FoundOutPoints,
+			RelevantTxns:       blockFilterer.RelevantTxns,
+		}, nil
+	}
+
+	// No addresses were found for this range.
+	return nil, nil
+}
+
+// BlockStamp returns the last block that the client notified about.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	bestBlock, err := c.getBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &waddrmgr.BlockStamp{
+		Height:    bestBlock.Height,
+		Hash:      bestBlock.Hash,
+		Timestamp: bestBlock.Header.Timestamp,
+	}, nil
+}
+
+// SendRawTransaction broadcasts the transaction through the server.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) SendRawTransaction(tx *wire.MsgTx,
+	_ bool) (*chainhash.Hash, error) {
+
+	txid, err := c.conn.BroadcastTx(tx)
+	if err != nil {
+		return nil, c.MapRPCErr(err)
+	}
+
+	return txid, nil
+}
+
+// Rescan rescans the chain from the given block for transactions of the
+// given addresses and outpoints, which are watched from then on. It returns
+// once the rescan finished.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) Rescan(startHash *chainhash.Hash,
+	addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	outPointAddrs := make([]btcutil.Address, 0, len(outPoints))
+	for _, addr := range outPoints {
+		outPointAddrs = append(outPointAddrs, addr)
+	}
+
+	if err := c.watchAddrs(addrs); err != nil {
+		return err
+	}
+	if err := c.watchAddrs(outPointAddrs); err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	for op := range outPoints {
+		c.watchedOutPoints[op] = struct{}{}
+	}
+	c.mtx.Unlock()
+
+	req := &rescanReq{
+		startHash: *startHash,
+		err:       make(chan error, 1),
+	}
+
+	select {
+	case c.rescanReqs <- req:
+	case <-c.quit:
+		return errClientShuttingDown
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+	case <-c.quit:
+		return errClientShuttingDown
+	}
+}
+
+// NotifyReceived watches the given addresses for transactions in new blocks.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) NotifyReceived(addrs []btcutil.Address) error {
+	return c.watchAddrs(addrs)
+}
+
+// NotifyBlocks is a no-op, as the client always notifies about new blocks.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) NotifyBlocks() error {
+	return nil
+}
+
+// Notifications returns the channel over which the wallet is notified.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) Notifications() <-chan interface{} {
+	return c.ntfnQueue.ChanOut()
+}
+
+// BackEnd returns the name of the backend.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) BackEnd() string {
+	return backEndName
+}
+
+// TestMempoolAccept isn't supported by Esplora servers, so
+// chain.ErrUnimplemented is returned.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) TestMempoolAccept(_ []*wire.MsgTx,
+	_ float64) ([]*btcjson.TestMempoolAcceptResult, error) {
+
+	return nil, chain.ErrUnimplemented
+}
+
+// MapRPCErr maps an error that the server returned for a broadcast to the
+// errors of the chain package.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ChainClient) MapRPCErr(err error) error {
+	return bitcoindErrMapper.MapRPCErr(err)
+}
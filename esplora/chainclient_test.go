@@ -0,0 +1,164 @@
+package esplora
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/esplora/esploratest"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestChainClient starts a chain client for the given server that is
+// stopped when the test finishes.
+func newTestChainClient(t *testing.T,
+	server *esploratest.Server) *ChainClient {
+
+	t.Helper()
+
+	conn := newTestConn(t, server)
+	client := conn.NewChainClient(
+		&chaincfg.RegressionNetParams, blockcache.NewBlockCache(1000),
+	)
+	require.NoError(t, client.Start())
+	t.Cleanup(func() {
+		client.Stop()
+		client.WaitForShutdown()
+	})
+
+	return client
+}
+
+// receiveNtfn returns the next notification of the client.
+func receiveNtfn(t *testing.T, client *ChainClient) interface{} {
+	t.Helper()
+
+	select {
+	case ntfn := <-client.Notifications():
+		return ntfn
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no notification received")
+		return nil
+	}
+}
+
+// TestChainClientNotifications checks that the client notifies the wallet
+// about the relevant transactions of rescans and new blocks.
+func TestChainClientNotifications(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	// We fund the address in the second block, followed by an unrelated
+	// one.
+	coinbase := server.MineBlock().Transactions[0]
+	fundingTx := newTestTx(
+		wire.OutPoint{Hash: coinbase.TxHash()}, pkScript,
+	)
+	fundingBlock := server.MineBlock(fundingTx)
+	tipBlock := server.MineBlock()
+
+	client := newTestChainClient(t, server)
+	require.IsType(t, chain.ClientConnected{}, receiveNtfn(t, client))
+
+	// A rescan from the genesis block should only notify about the
+	// funding block before it finishes at the tip.
+	genesisHash := chaincfg.RegressionNetParams.GenesisHash
+	err = client.Rescan(genesisHash, []btcutil.Address{addr}, nil)
+	require.NoError(t, err)
+
+	filtered, ok := receiveNtfn(t, client).(chain.FilteredBlockConnected)
+	require.True(t, ok)
+	require.Equal(t, fundingBlock.BlockHash(), filtered.Block.Hash)
+	require.EqualValues(t, 2, filtered.Block.Height)
+	require.Len(t, filtered.RelevantTxs, 1)
+	require.Equal(t, fundingTx.TxHash(), filtered.RelevantTxs[0].Hash)
+
+	progress, ok := receiveNtfn(t, client).(*chain.RescanProgress)
+	require.True(t, ok)
+	require.EqualValues(t, 2, progress.Height)
+
+	finished, ok := receiveNtfn(t, client).(*chain.RescanFinished)
+	require.True(t, ok)
+	require.Equal(t, tipBlock.BlockHash(), *finished.Hash)
+	require.EqualValues(t, 3, finished.Height)
+
+	// The output of the funding transaction is watched from now on, so a
+	// new block that spends it is relevant.
+	spendTx := newTestTx(
+		wire.OutPoint{Hash: fundingTx.TxHash()}, []byte{0x51},
+	)
+	spendBlock := server.MineBlock(spendTx)
+
+	filtered, ok = receiveNtfn(t, client).(chain.FilteredBlockConnected)
+	require.True(t, ok)
+	require.Equal(t, spendBlock.BlockHash(), filtered.Block.Hash)
+	require.Len(t, filtered.RelevantTxs, 1)
+	require.Equal(t, spendTx.TxHash(), filtered.RelevantTxs[0].Hash)
+
+	connected, ok := receiveNtfn(t, client).(chain.BlockConnected)
+	require.True(t, ok)
+	require.EqualValues(t, 4, connected.Height)
+
+	// If the block is replaced, it is disconnected first.
+	server.DisconnectBlocks(1)
+	newBlock := server.MineBlock()
+
+	disconnected, ok := receiveNtfn(t, client).(chain.BlockDisconnected)
+	require.True(t, ok)
+	require.Equal(t, spendBlock.BlockHash(), disconnected.Hash)
+
+	filtered, ok = receiveNtfn(t, client).(chain.FilteredBlockConnected)
+	require.True(t, ok)
+	require.Equal(t, newBlock.BlockHash(), filtered.Block.Hash)
+	require.Empty(t, filtered.RelevantTxs)
+
+	require.IsType(t, chain.BlockConnected{}, receiveNtfn(t, client))
+
+	hash, height, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.Equal(t, newBlock.BlockHash(), *hash)
+	require.EqualValues(t, 4, height)
+}
+
+// TestChainClientSendRawTransaction checks that broadcast errors are mapped
+// to the errors of the chain package.
+func TestChainClientSendRawTransaction(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	client := newTestChainClient(t, server)
+
+	coinbase := server.MineBlock().Transactions[0]
+	tx := newTestTx(wire.OutPoint{Hash: coinbase.TxHash()}, []byte{0x51})
+
+	txid, err := client.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), *txid)
+	require.Len(t, server.Mempool(), 1)
+
+	server.MineBlock(tx)
+	_, err = client.SendRawTransaction(tx, false)
+	require.ErrorIs(t, err, chain.ErrTxAlreadyConfirmed)
+
+	server.SetBroadcastErr("sendrawtransaction RPC error: " +
+		`{"code":-26,"message":"mempool min fee not met"}`)
+	_, err = client.SendRawTransaction(tx, false)
+	require.ErrorIs(t, err, chain.ErrMempoolMinFeeNotMet)
+
+	_, err = client.TestMempoolAccept([]*wire.MsgTx{tx}, 0)
+	require.ErrorIs(t, err, chain.ErrUnimplemented)
+}
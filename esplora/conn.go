@@ -0,0 +1,391 @@
+package esplora
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+const (
+	// maxReorgDepth is the number of recent blocks of the best chain that
+	// are kept in memory in order to detect reorgs. Deeper reorgs can't
+	// be handled.
+	maxReorgDepth = 100
+)
+
+var (
+	// ErrConnNotStarted is returned when blocks are subscribed to before
+	// the connection was started.
+	ErrConnNotStarted = errors.New("esplora connection not started")
+)
+
+// Config houses the parameters of a connection to an Esplora server.
+type Config struct {
+	// URL is the base URL of the REST API of the server, e.g.
+	// https://blockstream.info/api.
+	URL string
+
+	// RequestTimeout is the time after which a request to the server is
+	// given up.
+	RequestTimeout time.Duration
+
+	// PollInterval is the interval in which the server is polled for new
+	// blocks.
+	PollInterval time.Duration
+}
+
+// Block is a block of the best chain of the server.
+type Block struct {
+	// Height is the height of the block.
+	Height int32
+
+	// Hash is the hash of the block.
+	Hash chainhash.Hash
+
+	// Header is the header of the block.
+	Header *wire.BlockHeader
+}
+
+// BlockEvent notifies about a block that was connected to or disconnected
+// from the best chain of the server.
+type BlockEvent struct {
+	// Block is the block that was connected or disconnected.
+	Block *Block
+
+	// Disconnected is true if the block was disconnected from the best
+	// chain in a reorg.
+	Disconnected bool
+}
+
+// BlockSubscription is a subscription to the blocks that are connected to
+// and disconnected from the best chain of the server.
+type BlockSubscription struct {
+	// BestBlock is the best block at the time of the subscription. The
+	// events of the subscription start right after it.
+	BestBlock *Block
+
+	// Events is the channel over which the block events are sent, in the
+	// order in which they happened.
+	Events <-chan *BlockEvent
+
+	id     uint64
+	conn   *Conn
+	queue  *queue.ConcurrentQueue
+	events chan *BlockEvent
+
+	cancelOnce sync.Once
+	wg         sync.WaitGroup
+	quit       chan struct{}
+}
+
+// Cancel ends the subscription.
+func (s *BlockSubscription) Cancel() {
+	s.cancelOnce.Do(func() {
+		s.conn.mtx.Lock()
+		delete(s.conn.subscriptions, s.id)
+		s.conn.mtx.Unlock()
+
+		close(s.quit)
+		s.wg.Wait()
+		s.queue.Stop()
+	})
+}
+
+// forwardEvents hands the events of the subscription's queue to its
+// subscriber.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *BlockSubscription) forwardEvents() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case item := <-s.queue.ChanOut():
+			select {
+			case s.events <- item.(*BlockEvent):
+			case <-s.quit:
+				return
+			}
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Conn is a connection to an Esplora server. It polls the server for new
+// blocks and hands them to its subscribers. A single connection is meant to
+// be shared by all the clients of the server.
+type Conn struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	cfg        *Config
+	url        string
+	httpClient *http.Client
+
+	// mtx guards the fields below.
+	mtx sync.RWMutex
+
+	// recentBlocks are the most recent blocks of the best chain, ordered
+	// by height.
+	recentBlocks []*Block
+
+	subscriptions  map[uint64]*BlockSubscription
+	subscriptionID uint64
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewConn creates a new connection to the Esplora server of the given config.
+func NewConn(cfg *Config) *Conn {
+	return &Conn{
+		cfg: cfg,
+		url: strings.TrimSuffix(cfg.URL, "/"),
+		httpClient: &http.Client{
+			Timeout: cfg.RequestTimeout,
+		},
+		subscriptions: make(map[uint64]*BlockSubscription),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start fetches the best block of the server and starts polling for new
+// blocks.
+func (c *Conn) Start() error {
+	if !atomic.CompareAndSwapInt32(&c.started, 0, 1) {
+		return nil
+	}
+
+	if err := c.pollBestBlock(); err != nil {
+		return fmt.Errorf("unable to fetch best block from %v: %w",
+			c.url, err)
+	}
+
+	c.wg.Add(1)
+	go c.blockPoller()
+
+	return nil
+}
+
+// Stop stops polling the server for new blocks.
+func (c *Conn) Stop() {
+	if !atomic.CompareAndSwapInt32(&c.stopped, 0, 1) {
+		return
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// BestBlock returns the best block of the server as of the last poll.
+func (c *Conn) BestBlock() (*Block, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if len(c.recentBlocks) == 0 {
+		return nil, ErrConnNotStarted
+	}
+
+	return c.recentBlocks[len(c.recentBlocks)-1], nil
+}
+
+// SubscribeBlocks returns a subscription to the blocks that are connected to
+// and disconnected from the best chain after the current best block.
+func (c *Conn) SubscribeBlocks() (*BlockSubscription, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if len(c.recentBlocks) == 0 {
+		return nil, ErrConnNotStarted
+	}
+
+	c.subscriptionID++
+	events := make(chan *BlockEvent)
+	sub := &BlockSubscription{
+		BestBlock: c.recentBlocks[len(c.recentBlocks)-1],
+		Events:    events,
+		id:        c.subscriptionID,
+		conn:      c,
+		queue:     queue.NewConcurrentQueue(20),
+		events:    events,
+		quit:      make(chan struct{}),
+	}
+	sub.queue.Start()
+
+	sub.wg.Add(1)
+	go sub.forwardEvents()
+
+	c.subscriptions[sub.id] = sub
+
+	return sub, nil
+}
+
+// blockPoller polls the server for new blocks until the connection is
+// stopped.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *Conn) blockPoller() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.pollBestBlock(); err != nil {
+				log.Errorf("Unable to poll best block: %v", err)
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// pollBestBlock fetches the best block of the server. If it changed since
+// the last poll, the blocks that were disconnected and connected in the
+// meantime are determined and sent to the subscribers.
+func (c *Conn) pollBestBlock() error {
+	tipHash, err := c.GetTipHash()
+	if err != nil {
+		return err
+	}
+
+	// The recent blocks are only modified by this method, which is never
+	// run concurrently, so we don't need to hold the lock while we query
+	// the server. We work on a copy, so that readers of the current blocks
+	// aren't affected.
+	c.mtx.RLock()
+	recentBlocks := append([]*Block(nil), c.recentBlocks...)
+	c.mtx.RUnlock()
+
+	numRecent := len(recentBlocks)
+	if numRecent > 0 && recentBlocks[numRecent-1].Hash == *tipHash {
+		return nil
+	}
+
+	tipHeight, err := c.GetBlockHeight(tipHash)
+	if err != nil {
+		return err
+	}
+
+	// recentBlock returns our block at the given height, if we know it.
+	recentBlock := func(height int32) *Block {
+		if numRecent == 0 {
+			return nil
+		}
+
+		idx := int(height - recentBlocks[0].Height)
+		if idx < 0 || idx >= numRecent {
+			return nil
+		}
+
+		return recentBlocks[idx]
+	}
+
+	// Starting at the new tip, we walk back the chain of the server until
+	// we reach a block that we already know. All blocks on the way are
+	// new to us.
+	var (
+		newBlocks []*Block
+		hash      = *tipHash
+		height    = tipHeight
+	)
+	for {
+		header, err := c.GetBlockHeader(&hash)
+		if err != nil {
+			return err
+		}
+		newBlocks = append(newBlocks, &Block{
+			Height: height,
+			Hash:   hash,
+			Header: header,
+		})
+
+		// On the first poll, we only need to know the tip.
+		if numRecent == 0 || height == 0 {
+			break
+		}
+
+		prev := recentBlock(height - 1)
+		if prev != nil && prev.Hash == header.PrevBlock {
+			break
+		}
+
+		if prev == nil && height-1 < recentBlocks[0].Height {
+			return fmt.Errorf("reorg at height %d is deeper than "+
+				"%d blocks", height, maxReorgDepth)
+		}
+
+		hash = header.PrevBlock
+		height--
+	}
+
+	// All our blocks above the fork point were disconnected from the best
+	// chain.
+	var (
+		forkHeight = newBlocks[len(newBlocks)-1].Height - 1
+		events     []*BlockEvent
+	)
+	for i := numRecent - 1; i >= 0; i-- {
+		if recentBlocks[i].Height <= forkHeight {
+			break
+		}
+
+		log.Infof("Block %v at height %d disconnected",
+			recentBlocks[i].Hash, recentBlocks[i].Height)
+
+		events = append(events, &BlockEvent{
+			Block:        recentBlocks[i],
+			Disconnected: true,
+		})
+		recentBlocks = recentBlocks[:i]
+	}
+
+	// The new blocks were collected from the tip downwards, so we connect
+	// them in reverse order.
+	for i := len(newBlocks) - 1; i >= 0; i-- {
+		log.Debugf("Block %v at height %d connected",
+			newBlocks[i].Hash, newBlocks[i].Height)
+
+		events = append(events, &BlockEvent{Block: newBlocks[i]})
+		recentBlocks = append(recentBlocks, newBlocks[i])
+	}
+
+	if len(recentBlocks) > maxReorgDepth {
+		recentBlocks = recentBlocks[len(recentBlocks)-maxReorgDepth:]
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.recentBlocks = recentBlocks
+
+	// The first poll only establishes the best block.
+	if numRecent == 0 {
+		return nil
+	}
+
+	for _, sub := range c.subscriptions {
+		for _, event := range events {
+			select {
+			case sub.queue.ChanIn() <- event:
+			case <-c.quit:
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
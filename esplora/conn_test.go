@@ -0,0 +1,244 @@
+package esplora
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/esplora/esploratest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testPollInterval is the interval in which the tests poll the server
+	// for new blocks.
+	testPollInterval = 10 * time.Millisecond
+
+	// testTimeout is the time after which an expected event is considered
+	// missing.
+	testTimeout = 5 * time.Second
+)
+
+// newTestConn starts a connection to the given server that is stopped when
+// the test finishes.
+func newTestConn(t *testing.T, server *esploratest.Server) *Conn {
+	t.Helper()
+
+	conn := NewConn(&Config{
+		URL:            server.URL,
+		RequestTimeout: testTimeout,
+		PollInterval:   testPollInterval,
+	})
+	require.NoError(t, conn.Start())
+	t.Cleanup(conn.Stop)
+
+	return conn
+}
+
+// newTestTx returns a transaction that spends the given outpoint to the given
+// output script.
+func newTestTx(prevOut wire.OutPoint, pkScript []byte) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: prevOut})
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: pkScript})
+
+	return tx
+}
+
+// receiveEvent returns the next event of the subscription.
+func receiveEvent(t *testing.T, sub *BlockSubscription) *BlockEvent {
+	t.Helper()
+
+	select {
+	case event := <-sub.Events:
+		return event
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no block event received")
+		return nil
+	}
+}
+
+// TestConnBlockEvents checks that connected and disconnected blocks are sent
+// to subscribers in order.
+func TestConnBlockEvents(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	server.MineBlock()
+	conn := newTestConn(t, server)
+
+	genesisHash := chaincfg.RegressionNetParams.GenesisHash
+	sub, err := conn.SubscribeBlocks()
+	require.NoError(t, err)
+	defer sub.Cancel()
+
+	require.EqualValues(t, 1, sub.BestBlock.Height)
+	require.Equal(t, *genesisHash, sub.BestBlock.Header.PrevBlock)
+
+	// Two new blocks should be connected in order.
+	block2 := server.MineBlock()
+	block3 := server.MineBlock()
+	for i, block := range []*wire.MsgBlock{block2, block3} {
+		event := receiveEvent(t, sub)
+		require.False(t, event.Disconnected)
+		require.EqualValues(t, i+2, event.Block.Height)
+		require.Equal(t, block.BlockHash(), event.Block.Hash)
+	}
+
+	// We now replace the two blocks with three new ones. The old ones
+	// should be disconnected from the tip downwards, followed by the new
+	// ones.
+	server.DisconnectBlocks(2)
+	newBlocks := []*wire.MsgBlock{
+		server.MineBlock(), server.MineBlock(), server.MineBlock(),
+	}
+
+	for i, block := range []*wire.MsgBlock{block3, block2} {
+		event := receiveEvent(t, sub)
+		require.True(t, event.Disconnected)
+		require.EqualValues(t, 3-i, event.Block.Height)
+		require.Equal(t, block.BlockHash(), event.Block.Hash)
+	}
+	for i, block := range newBlocks {
+		event := receiveEvent(t, sub)
+		require.False(t, event.Disconnected)
+		require.EqualValues(t, i+2, event.Block.Height)
+		require.Equal(t, block.BlockHash(), event.Block.Hash)
+	}
+
+	best, err := conn.BestBlock()
+	require.NoError(t, err)
+	require.EqualValues(t, 4, best.Height)
+	require.Equal(t, newBlocks[2].BlockHash(), best.Hash)
+}
+
+// TestConnNotStarted checks that blocks can't be subscribed to before the
+// connection is started.
+func TestConnNotStarted(t *testing.T) {
+	t.Parallel()
+
+	conn := NewConn(&Config{URL: "http://127.0.0.1:0"})
+
+	_, err := conn.SubscribeBlocks()
+	require.ErrorIs(t, err, ErrConnNotStarted)
+
+	_, err = conn.BestBlock()
+	require.ErrorIs(t, err, ErrConnNotStarted)
+}
+
+// TestConnREST checks the queries of the REST API against the test server.
+func TestConnREST(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	conn := newTestConn(t, server)
+
+	pkScript := []byte{0x00, 0x14, 0x01, 0x02}
+	coinbase := server.MineBlock().Transactions[0]
+	fundingTx := newTestTx(
+		wire.OutPoint{Hash: coinbase.TxHash()}, pkScript,
+	)
+	block := server.MineBlock(fundingTx)
+	blockHash := block.BlockHash()
+
+	hash, err := conn.GetBlockHash(2)
+	require.NoError(t, err)
+	require.Equal(t, blockHash, *hash)
+
+	tipHash, err := conn.GetTipHash()
+	require.NoError(t, err)
+	require.Equal(t, blockHash, *tipHash)
+
+	header, err := conn.GetBlockHeader(&blockHash)
+	require.NoError(t, err)
+	require.Equal(t, block.Header.BlockHash(), header.BlockHash())
+
+	height, err := conn.GetBlockHeight(&blockHash)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, height)
+
+	rawBlock, err := conn.GetBlock(&blockHash)
+	require.NoError(t, err)
+	require.Len(t, rawBlock.Transactions, 2)
+
+	// The funding transaction is confirmed in the block, and its output
+	// isn't spent yet.
+	fundingHash := fundingTx.TxHash()
+	status, err := conn.GetTxStatus(&fundingHash)
+	require.NoError(t, err)
+	require.True(t, status.Confirmed)
+	require.EqualValues(t, 2, status.BlockHeight)
+	require.Equal(t, blockHash.String(), status.BlockHash)
+
+	tx, err := conn.GetRawTransaction(&fundingHash)
+	require.NoError(t, err)
+	require.Equal(t, fundingHash, tx.TxHash())
+
+	fundingOut := wire.OutPoint{Hash: fundingHash}
+	outSpend, err := conn.GetTxOutSpend(&fundingOut)
+	require.NoError(t, err)
+	require.False(t, outSpend.Spent)
+
+	// Once a transaction spending the output is broadcast, the output is
+	// reported as spent by an unconfirmed transaction.
+	spendTx := newTestTx(fundingOut, pkScript)
+	spendHash, err := conn.BroadcastTx(spendTx)
+	require.NoError(t, err)
+	require.Equal(t, spendTx.TxHash(), *spendHash)
+
+	outSpend, err = conn.GetTxOutSpend(&fundingOut)
+	require.NoError(t, err)
+	require.True(t, outSpend.Spent)
+	require.Equal(t, spendHash.String(), outSpend.TxID)
+	require.False(t, outSpend.Status.Confirmed)
+
+	// Both transactions pay to the script, but only confirmed ones are
+	// returned.
+	server.MineBlock(spendTx)
+	txs, err := conn.GetScriptTxs(pkScript, 0)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.Equal(t, spendHash.String(), txs[0].TxID)
+	require.EqualValues(t, 3, txs[0].Status.BlockHeight)
+	require.Equal(t, fundingHash.String(), txs[1].TxID)
+
+	txs, err = conn.GetScriptTxs(pkScript, 3)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+
+	// Unknown transactions result in ErrNotFound.
+	_, err = conn.GetTxStatus(&chainhash.Hash{0x01})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	server.SetFeeEstimates(map[uint32]float64{1: 20.5, 6: 10})
+	estimates, err := conn.GetFeeEstimates()
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]float64{1: 20.5, 6: 10}, estimates)
+}
+
+// TestConnScriptTxsPagination checks that the history of a script is fetched
+// across several pages.
+func TestConnScriptTxsPagination(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	conn := newTestConn(t, server)
+
+	pkScript := []byte{0x51}
+	numTxs := scriptTxsPageSize + 5
+	for i := 0; i < numTxs; i++ {
+		server.MineBlock()
+	}
+
+	// The coinbase outputs of the test server pay to OP_TRUE, so every
+	// block includes a transaction of the script.
+	txs, err := conn.GetScriptTxs(pkScript, 1)
+	require.NoError(t, err)
+	require.Len(t, txs, numTxs)
+	for i, tx := range txs {
+		require.EqualValues(t, numTxs-i, tx.Status.BlockHeight)
+	}
+}
@@ -0,0 +1,515 @@
+package esploratest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// scriptTxsPageSize is the number of confirmed transactions that are returned
+// per page of a script's history, mirroring the Esplora API.
+const scriptTxsPageSize = 25
+
+// Server is an Esplora server for tests that serves a chain of blocks held in
+// memory. Blocks are only mined when requested by the test.
+type Server struct {
+	*httptest.Server
+
+	mtx sync.Mutex
+
+	// blocks is the best chain, starting with the genesis block.
+	blocks []*wire.MsgBlock
+
+	// mempool holds the transactions that were broadcast but aren't
+	// mined yet.
+	mempool map[chainhash.Hash]*wire.MsgTx
+
+	// feeEstimates are the fee rates in sat/vbyte per confirmation
+	// target.
+	feeEstimates map[string]float64
+
+	// broadcastErr is returned for broadcasts if set.
+	broadcastErr string
+
+	// numMined is the number of blocks mined so far. It is used as the
+	// nonce of new blocks, so that blocks that replace disconnected ones
+	// get a different hash.
+	numMined uint32
+}
+
+// NewServer starts a new server whose chain consists of the regtest genesis
+// block. The server is closed when the test finishes.
+func NewServer(t *testing.T) *Server {
+	s := &Server{
+		blocks: []*wire.MsgBlock{
+			chaincfg.RegressionNetParams.GenesisBlock,
+		},
+		mempool:      make(map[chainhash.Hash]*wire.MsgTx),
+		feeEstimates: make(map[string]float64),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /blocks/tip/hash", s.handleTipHash)
+	mux.HandleFunc("GET /block-height/{height}", s.handleBlockHeight)
+	mux.HandleFunc("GET /block/{hash}", s.handleBlockInfo)
+	mux.HandleFunc("GET /block/{hash}/header", s.handleBlockHeader)
+	mux.HandleFunc("GET /block/{hash}/raw", s.handleRawBlock)
+	mux.HandleFunc("GET /tx/{txid}/status", s.handleTxStatus)
+	mux.HandleFunc("GET /tx/{txid}/hex", s.handleTxHex)
+	mux.HandleFunc("GET /tx/{txid}/outspend/{vout}", s.handleOutSpend)
+	mux.HandleFunc("GET /scripthash/{hash}/txs/chain", s.handleScriptTxs)
+	mux.HandleFunc(
+		"GET /scripthash/{hash}/txs/chain/{last}", s.handleScriptTxs,
+	)
+	mux.HandleFunc("POST /tx", s.handleBroadcast)
+	mux.HandleFunc("GET /fee-estimates", s.handleFeeEstimates)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// MineBlock mines a block on top of the best chain that includes the given
+// transactions, which are removed from the mempool.
+func (s *Server) MineBlock(txs ...*wire.MsgTx) *wire.MsgBlock {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	tip := s.blocks[len(s.blocks)-1]
+	height := int64(len(s.blocks))
+
+	// The coinbase commits to the height, which makes its hash unique.
+	coinbaseScript, err := txscript.NewScriptBuilder().
+		AddInt64(height).Script()
+	if err != nil {
+		panic(err)
+	}
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  coinbaseScript,
+	})
+	coinbase.AddTxOut(&wire.TxOut{Value: 50, PkScript: []byte{0x51}})
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   4,
+			PrevBlock: tip.BlockHash(),
+			Timestamp: time.Unix(time.Now().Unix(), 0),
+			Bits:      tip.Header.Bits,
+			Nonce:     s.numMined,
+		},
+		Transactions: append([]*wire.MsgTx{coinbase}, txs...),
+	}
+
+	for _, tx := range txs {
+		delete(s.mempool, tx.TxHash())
+	}
+	s.blocks = append(s.blocks, block)
+	s.numMined++
+
+	return block
+}
+
+// DisconnectBlocks removes the given number of blocks from the tip of the
+// best chain. Their transactions are dropped.
+func (s *Server) DisconnectBlocks(numBlocks int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.blocks = s.blocks[:len(s.blocks)-numBlocks]
+}
+
+// Mempool returns the transactions in the mempool.
+func (s *Server) Mempool() []*wire.MsgTx {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	txs := make([]*wire.MsgTx, 0, len(s.mempool))
+	for _, tx := range s.mempool {
+		txs = append(txs, tx)
+	}
+
+	return txs
+}
+
+// SetFeeEstimates sets the fee rates in sat/vbyte per confirmation target.
+func (s *Server) SetFeeEstimates(estimates map[uint32]float64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.feeEstimates = make(map[string]float64, len(estimates))
+	for target, feeRate := range estimates {
+		s.feeEstimates[strconv.Itoa(int(target))] = feeRate
+	}
+}
+
+// SetBroadcastErr sets the error that is returned for broadcasts. An empty
+// error lets broadcasts succeed.
+func (s *Server) SetBroadcastErr(err string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.broadcastErr = err
+}
+
+// txStatus is the json representation of the status of a transaction.
+type txStatus struct {
+	Confirmed   bool   `json:"confirmed"`
+	BlockHeight int32  `json:"block_height,omitempty"`
+	BlockHash   string `json:"block_hash,omitempty"`
+	BlockTime   int64  `json:"block_time,omitempty"`
+}
+
+// txInfo is the json representation of a transaction.
+type txInfo struct {
+	TxID   string   `json:"txid"`
+	Status txStatus `json:"status"`
+}
+
+// confirmedStatus returns the status of a transaction in the block at the
+// given height.
+func (s *Server) confirmedStatus(height int) txStatus {
+	block := s.blocks[height]
+
+	return txStatus{
+		Confirmed:   true,
+		BlockHeight: int32(height),
+		BlockHash:   block.BlockHash().String(),
+		BlockTime:   block.Header.Timestamp.Unix(),
+	}
+}
+
+// findBlock returns the height of the block with the hash of the request.
+func (s *Server) findBlock(r *http.Request) (int, bool) {
+	for height, block := range s.blocks {
+		if block.BlockHash().String() == r.PathValue("hash") {
+			return height, true
+		}
+	}
+
+	return 0, false
+}
+
+// findTx returns the transaction with the id of the request along with its
+// status.
+func (s *Server) findTx(r *http.Request) (*wire.MsgTx, *txStatus) {
+	txid, err := chainhash.NewHashFromStr(r.PathValue("txid"))
+	if err != nil {
+		return nil, nil
+	}
+
+	for height, block := range s.blocks {
+		for _, tx := range block.Transactions {
+			if tx.TxHash() == *txid {
+				status := s.confirmedStatus(height)
+				return tx, &status
+			}
+		}
+	}
+
+	if tx, ok := s.mempool[*txid]; ok {
+		return tx, &txStatus{}
+	}
+
+	return nil, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) handleTipHash(w http.ResponseWriter, _ *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	fmt.Fprint(w, s.blocks[len(s.blocks)-1].BlockHash())
+}
+
+func (s *Server) handleBlockHeight(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	height, err := strconv.Atoi(r.PathValue("height"))
+	if err != nil || height < 0 || height >= len(s.blocks) {
+		http.NotFound(w, r)
+		return
+	}
+
+	fmt.Fprint(w, s.blocks[height].BlockHash())
+}
+
+func (s *Server) handleBlockInfo(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	height, ok := s.findBlock(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	header := s.blocks[height].Header
+	info := map[string]interface{}{
+		"id":          header.BlockHash().String(),
+		"height":      height,
+		"version":     header.Version,
+		"timestamp":   header.Timestamp.Unix(),
+		"bits":        header.Bits,
+		"nonce":       header.Nonce,
+		"merkle_root": header.MerkleRoot.String(),
+	}
+	if height > 0 {
+		info["previousblockhash"] = header.PrevBlock.String()
+	}
+
+	writeJSON(w, info)
+}
+
+func (s *Server) handleBlockHeader(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	height, ok := s.findBlock(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var buf bytes.Buffer
+	_ = s.blocks[height].Header.Serialize(&buf)
+	fmt.Fprint(w, hex.EncodeToString(buf.Bytes()))
+}
+
+func (s *Server) handleRawBlock(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	height, ok := s.findBlock(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	_ = s.blocks[height].Serialize(w)
+}
+
+func (s *Server) handleTxStatus(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	_, status := s.findTx(r)
+	if status == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, status)
+}
+
+func (s *Server) handleTxHex(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	tx, _ := s.findTx(r)
+	if tx == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var buf bytes.Buffer
+	_ = tx.Serialize(&buf)
+	fmt.Fprint(w, hex.EncodeToString(buf.Bytes()))
+}
+
+func (s *Server) handleOutSpend(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	tx, _ := s.findTx(r)
+	vout, err := strconv.Atoi(r.PathValue("vout"))
+	if tx == nil || err != nil || vout < 0 || vout >= len(tx.TxOut) {
+		http.NotFound(w, r)
+		return
+	}
+	op := wire.OutPoint{Hash: tx.TxHash(), Index: uint32(vout)}
+
+	// spentBy returns the index of the input of the transaction that
+	// spends the outpoint, if any.
+	spentBy := func(tx *wire.MsgTx) (int, bool) {
+		for i, txIn := range tx.TxIn {
+			if txIn.PreviousOutPoint == op {
+				return i, true
+			}
+		}
+
+		return 0, false
+	}
+
+	for height, block := range s.blocks {
+		for _, tx := range block.Transactions {
+			if vin, ok := spentBy(tx); ok {
+				writeJSON(w, map[string]interface{}{
+					"spent":  true,
+					"txid":   tx.TxHash().String(),
+					"vin":    vin,
+					"status": s.confirmedStatus(height),
+				})
+
+				return
+			}
+		}
+	}
+
+	for _, tx := range s.mempool {
+		if vin, ok := spentBy(tx); ok {
+			writeJSON(w, map[string]interface{}{
+				"spent":  true,
+				"txid":   tx.TxHash().String(),
+				"vin":    vin,
+				"status": txStatus{},
+			})
+
+			return
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{"spent": false})
+}
+
+func (s *Server) handleScriptTxs(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	scriptHash := r.PathValue("hash")
+	matchesScript := func(pkScript []byte) bool {
+		hash := sha256.Sum256(pkScript)
+		return hex.EncodeToString(hash[:]) == scriptHash
+	}
+
+	// Collect the confirmed transactions that pay to or spend from the
+	// script, from the oldest to the most recent one.
+	var (
+		txs     []txInfo
+		outputs = make(map[wire.OutPoint][]byte)
+	)
+	for height, block := range s.blocks {
+		for _, tx := range block.Transactions {
+			var relevant bool
+			for _, txIn := range tx.TxIn {
+				pkScript := outputs[txIn.PreviousOutPoint]
+				if pkScript != nil && matchesScript(pkScript) {
+					relevant = true
+				}
+			}
+
+			txHash := tx.TxHash()
+			for i, txOut := range tx.TxOut {
+				outputs[wire.OutPoint{
+					Hash:  txHash,
+					Index: uint32(i),
+				}] = txOut.PkScript
+
+				if matchesScript(txOut.PkScript) {
+					relevant = true
+				}
+			}
+
+			if relevant {
+				txs = append(txs, txInfo{
+					TxID:   txHash.String(),
+					Status: s.confirmedStatus(height),
+				})
+			}
+		}
+	}
+
+	// The history is returned from the most recent transaction onwards,
+	// starting after the last one of the previous page.
+	page := make([]txInfo, 0, scriptTxsPageSize)
+	last := r.PathValue("last")
+	for i := len(txs) - 1; i >= 0; i-- {
+		if last != "" {
+			if txs[i].TxID == last {
+				last = ""
+			}
+
+			continue
+		}
+
+		page = append(page, txs[i])
+		if len(page) == scriptTxsPageSize {
+			break
+		}
+	}
+
+	writeJSON(w, page)
+}
+
+func (s *Server) handleBroadcast(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	txBytes, err := hex.DecodeString(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx := &wire.MsgTx{}
+	if err := tx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if s.broadcastErr != "" {
+		http.Error(w, s.broadcastErr, http.StatusBadRequest)
+		return
+	}
+
+	txHash := tx.TxHash()
+	for _, block := range s.blocks {
+		for _, blockTx := range block.Transactions {
+			if blockTx.TxHash() == txHash {
+				http.Error(w, "sendrawtransaction RPC "+
+					`error: {"code":-27,"message":`+
+					`"Transaction already in block chain"}`,
+					http.StatusBadRequest)
+
+				return
+			}
+		}
+	}
+
+	s.mempool[txHash] = tx
+	fmt.Fprint(w, txHash)
+}
+
+func (s *Server) handleFeeEstimates(w http.ResponseWriter, _ *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	writeJSON(w, s.feeEstimates)
+}
@@ -0,0 +1,377 @@
+package esplora
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// maxResponseSize is the maximum size of a response that we read from
+	// the server. It leaves enough room for the largest possible block.
+	maxResponseSize = 32 << 20
+
+	// scriptTxsPageSize is the number of confirmed transactions that the
+	// server returns per page of a script's history.
+	scriptTxsPageSize = 25
+)
+
+var (
+	// ErrNotFound is returned when the server doesn't know the requested
+	// block, transaction or output.
+	ErrNotFound = errors.New("not found")
+)
+
+// TxStatus is the confirmation status of a transaction.
+type TxStatus struct {
+	// Confirmed is true if the transaction is included in a block of the
+	// best chain.
+	Confirmed bool `json:"confirmed"`
+
+	// BlockHeight is the height of the block that includes the
+	// transaction.
+	BlockHeight int32 `json:"block_height"`
+
+	// BlockHash is the hash of the block that includes the transaction.
+	BlockHash string `json:"block_hash"`
+
+	// BlockTime is the timestamp of the block that includes the
+	// transaction.
+	BlockTime int64 `json:"block_time"`
+}
+
+// TxInfo is an entry of the transaction history of a script.
+type TxInfo struct {
+	// TxID is the id of the transaction.
+	TxID string `json:"txid"`
+
+	// Status is the confirmation status of the transaction.
+	Status TxStatus `json:"status"`
+}
+
+// OutSpend describes whether an output is spent, and by which transaction.
+type OutSpend struct {
+	// Spent is true if the output is spent by a transaction that is
+	// either confirmed or in the mempool.
+	Spent bool `json:"spent"`
+
+	// TxID is the id of the spending transaction.
+	TxID string `json:"txid"`
+
+	// Vin is the index of the spending input.
+	Vin uint32 `json:"vin"`
+
+	// Status is the confirmation status of the spending transaction.
+	Status *TxStatus `json:"status"`
+}
+
+// blockInfo is the json representation of a block, excluding its
+// transactions.
+type blockInfo struct {
+	ID           string `json:"id"`
+	Height       int32  `json:"height"`
+	Version      int32  `json:"version"`
+	Timestamp    int64  `json:"timestamp"`
+	Bits         uint32 `json:"bits"`
+	Nonce        uint32 `json:"nonce"`
+	MerkleRoot   string `json:"merkle_root"`
+	PreviousHash string `json:"previousblockhash"`
+}
+
+// scriptHash returns the hash that identifies the given output script in the
+// Esplora API, which is its sha256 hash.
+func scriptHash(pkScript []byte) string {
+	hash := sha256.Sum256(pkScript)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// request sends a request to the given path of the REST API and returns the
+// body of the response. ErrNotFound is returned if the server responds with
+// a 404 status.
+func (c *Conn) request(method, path string, body []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return respBody, nil
+
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, path)
+
+	default:
+		return nil, fmt.Errorf("%v %v failed with status %v: %s",
+			method, path, resp.StatusCode,
+			strings.TrimSpace(string(respBody)))
+	}
+}
+
+// getText returns the trimmed body of the response to a GET request.
+func (c *Conn) getText(path string) (string, error) {
+	body, err := c.request(http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// getHex returns the decoded body of the response to a GET request that
+// returns hex encoded data.
+func (c *Conn) getHex(path string) ([]byte, error) {
+	text, err := c.getText(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(text)
+}
+
+// getJSON decodes the body of the response to a GET request into v.
+func (c *Conn) getJSON(path string, v interface{}) error {
+	body, err := c.request(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// GetTipHash returns the hash of the best block of the server.
+func (c *Conn) GetTipHash() (*chainhash.Hash, error) {
+	text, err := c.getText("/blocks/tip/hash")
+	if err != nil {
+		return nil, err
+	}
+
+	return chainhash.NewHashFromStr(text)
+}
+
+// GetBlockHash returns the hash of the block at the given height of the best
+// chain.
+func (c *Conn) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	text, err := c.getText(fmt.Sprintf("/block-height/%d", height))
+	if err != nil {
+		return nil, err
+	}
+
+	return chainhash.NewHashFromStr(text)
+}
+
+// GetBlockHeader returns the header of the block with the given hash.
+func (c *Conn) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	b, err := c.getHex(fmt.Sprintf("/block/%v/header", hash))
+	if err != nil {
+		return nil, err
+	}
+
+	header := &wire.BlockHeader{}
+	if err := header.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// GetBlockHeaderVerbose returns the header of the block with the given hash
+// along with its height.
+func (c *Conn) GetBlockHeaderVerbose(
+	hash *chainhash.Hash) (*btcjson.GetBlockHeaderVerboseResult, error) {
+
+	var info blockInfo
+	if err := c.getJSON(fmt.Sprintf("/block/%v", hash), &info); err != nil {
+		return nil, err
+	}
+
+	return &btcjson.GetBlockHeaderVerboseResult{
+		Hash:         info.ID,
+		Height:       info.Height,
+		Version:      info.Version,
+		VersionHex:   fmt.Sprintf("%08x", uint32(info.Version)),
+		MerkleRoot:   info.MerkleRoot,
+		Time:         info.Timestamp,
+		Nonce:        uint64(info.Nonce),
+		Bits:         fmt.Sprintf("%08x", info.Bits),
+		PreviousHash: info.PreviousHash,
+	}, nil
+}
+
+// GetBlockHeight returns the height of the block with the given hash.
+func (c *Conn) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	header, err := c.GetBlockHeaderVerbose(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Height, nil
+}
+
+// GetBlock returns the block with the given hash.
+func (c *Conn) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	b, err := c.request(
+		http.MethodGet, fmt.Sprintf("/block/%v/raw", hash), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &wire.MsgBlock{}
+	if err := block.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// GetTxStatus returns the confirmation status of the given transaction.
+func (c *Conn) GetTxStatus(txid *chainhash.Hash) (*TxStatus, error) {
+	var status TxStatus
+	err := c.getJSON(fmt.Sprintf("/tx/%v/status", txid), &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetRawTransaction returns the transaction with the given id.
+func (c *Conn) GetRawTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	b, err := c.getHex(fmt.Sprintf("/tx/%v/hex", txid))
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &wire.MsgTx{}
+	if err := tx.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// GetTxOutSpend returns whether the given output is spent, and by which
+// transaction.
+func (c *Conn) GetTxOutSpend(op *wire.OutPoint) (*OutSpend, error) {
+	var outSpend OutSpend
+	err := c.getJSON(
+		fmt.Sprintf("/tx/%v/outspend/%d", op.Hash, op.Index), &outSpend,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outSpend, nil
+}
+
+// GetScriptTxs returns the confirmed transactions that pay to or spend from
+// the given output script and that were included in a block at or above the
+// given height. The transactions are ordered from the most recent to the
+// oldest one.
+func (c *Conn) GetScriptTxs(pkScript []byte,
+	minHeight int32) ([]*TxInfo, error) {
+
+	path := fmt.Sprintf("/scripthash/%s/txs/chain", scriptHash(pkScript))
+
+	var (
+		txs    []*TxInfo
+		lastID string
+	)
+	for {
+		pagePath := path
+		if lastID != "" {
+			pagePath += "/" + lastID
+		}
+
+		var page []*TxInfo
+		if err := c.getJSON(pagePath, &page); err != nil {
+			return nil, err
+		}
+
+		for _, tx := range page {
+			if tx.Status.BlockHeight < minHeight {
+				return txs, nil
+			}
+
+			txs = append(txs, tx)
+		}
+
+		if len(page) < scriptTxsPageSize {
+			return txs, nil
+		}
+		lastID = page[len(page)-1].TxID
+	}
+}
+
+// BroadcastTx sends the given transaction to the server, which relays it to
+// the network.
+func (c *Conn) BroadcastTx(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	body := []byte(hex.EncodeToString(buf.Bytes()))
+	resp, err := c.request(http.MethodPost, "/tx", body)
+	if err != nil {
+		return nil, err
+	}
+
+	return chainhash.NewHashFromStr(strings.TrimSpace(string(resp)))
+}
+
+// GetFeeEstimates returns the fee rates in sat/vbyte that the server
+// estimates for the given confirmation targets.
+func (c *Conn) GetFeeEstimates() (map[uint32]float64, error) {
+	var resp map[string]float64
+	if err := c.getJSON("/fee-estimates", &resp); err != nil {
+		return nil, err
+	}
+
+	estimates := make(map[uint32]float64, len(resp))
+	for target, feeRate := range resp {
+		numBlocks, err := strconv.ParseUint(target, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid confirmation target "+
+				"%v: %w", target, err)
+		}
+
+		estimates[uint32(numBlocks)] = feeRate
+	}
+
+	return estimates, nil
+}
@@ -67,6 +67,10 @@ var defaultSetDesc = setDesc{
 	lnwire.KeysendOptional: {
 		SetNodeAnn: {}, // N
 	},
+	lnwire.TrampolineRoutingOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
 	lnwire.ScriptEnforcedLeaseOptional: {
 		SetInit:    {}, // I
 		SetNodeAnn: {}, // N
@@ -92,8 +96,36 @@ var defaultSetDesc = setDesc{
 		SetInit:    {}, // I
 		SetNodeAnn: {}, // N
 	},
+	lnwire.QuiescenceOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
+	lnwire.SimpleCloseOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
+	lnwire.SpliceOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
+	lnwire.DualFundOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
+	lnwire.OnionMessagesOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
+	lnwire.ProvideStorageOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
 	lnwire.SimpleTaprootOverlayChansOptional: {
 		SetInit:    {}, // I
 		SetNodeAnn: {}, // N
 	},
+	lnwire.ZeroFeeCommitmentsOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
 }
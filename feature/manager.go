@@ -66,6 +66,35 @@ type Config struct {
 	// NoTaprootOverlay unsets the taproot overlay channel feature bits.
 	NoTaprootOverlay bool
 
+	// NoZeroFeeCommitments unsets the zero-fee commitment channel feature
+	// bits.
+	NoZeroFeeCommitments bool
+
+	// NoQuiescence unsets the quiescence feature bits.
+	NoQuiescence bool
+
+	// NoSplice unsets the splice feature bits.
+	NoSplice bool
+
+	// NoDualFund unsets the dual funding feature bits.
+	NoDualFund bool
+
+	// NoOnionMessages unsets the onion message feature bits.
+	NoOnionMessages bool
+
+	// NoPeerStorage unsets the bits signaling that we store blobs on
+	// behalf of our peers.
+	NoPeerStorage bool
+
+	// NoTrampoline unsets the bits signaling that we forward trampoline
+	// payments.
+	NoTrampoline bool
+
+	// NoRbfCoopClose unsets the simple close feature bits. As the simple
+	// close protocol depends on option_shutdown_anysegwit, these bits are
+	// also unset if NoAnySegwit is set.
+	NoRbfCoopClose bool
+
 	// CustomFeatures is a set of custom features to advertise in each
 	// set.
 	CustomFeatures map[Set][]lnwire.FeatureBit
@@ -199,6 +228,38 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.SimpleTaprootOverlayChansOptional)
 			raw.Unset(lnwire.SimpleTaprootOverlayChansRequired)
 		}
+		if cfg.NoZeroFeeCommitments {
+			raw.Unset(lnwire.ZeroFeeCommitmentsOptional)
+			raw.Unset(lnwire.ZeroFeeCommitmentsRequired)
+		}
+		if cfg.NoQuiescence {
+			raw.Unset(lnwire.QuiescenceOptional)
+			raw.Unset(lnwire.QuiescenceRequired)
+		}
+		if cfg.NoSplice || cfg.NoQuiescence {
+			raw.Unset(lnwire.SpliceOptional)
+			raw.Unset(lnwire.SpliceRequired)
+		}
+		if cfg.NoDualFund {
+			raw.Unset(lnwire.DualFundOptional)
+			raw.Unset(lnwire.DualFundRequired)
+		}
+		if cfg.NoOnionMessages {
+			raw.Unset(lnwire.OnionMessagesOptional)
+			raw.Unset(lnwire.OnionMessagesRequired)
+		}
+		if cfg.NoPeerStorage {
+			raw.Unset(lnwire.ProvideStorageOptional)
+			raw.Unset(lnwire.ProvideStorageRequired)
+		}
+		if cfg.NoTrampoline {
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
+		}
+		if cfg.NoRbfCoopClose || cfg.NoAnySegwit {
+			raw.Unset(lnwire.SimpleCloseOptional)
+			raw.Unset(lnwire.SimpleCloseRequired)
+		}
 		for _, custom := range cfg.CustomFeatures[set] {
 			if custom > set.Maximum() {
 				return nil, fmt.Errorf("feature bit: %v "+
@@ -375,6 +375,74 @@ func explicitNegotiateCommitmentType(channelType lnwire.ChannelType, local,
 
 		return lnwallet.CommitmentTypeSimpleTaprootOverlay, nil
 
+	// Zero-fee commitments only.
+	case channelFeatures.OnlyContains(
+		lnwire.ZeroFeeCommitmentsRequired,
+	):
+
+		if !hasFeatures(
+			local, remote,
+			lnwire.ZeroFeeCommitmentsOptional,
+		) {
+
+			return 0, errUnsupportedChannelType
+		}
+
+		return lnwallet.CommitmentTypeZeroFeeCommitments, nil
+
+	// Zero-fee commitments with scid only.
+	case channelFeatures.OnlyContains(
+		lnwire.ZeroFeeCommitmentsRequired,
+		lnwire.ScidAliasRequired,
+	):
+
+		if !hasFeatures(
+			local, remote,
+			lnwire.ZeroFeeCommitmentsOptional,
+			lnwire.ScidAliasOptional,
+		) {
+
+			return 0, errUnsupportedChannelType
+		}
+
+		return lnwallet.CommitmentTypeZeroFeeCommitments, nil
+
+	// Zero-fee commitments with zero conf only.
+	case channelFeatures.OnlyContains(
+		lnwire.ZeroFeeCommitmentsRequired,
+		lnwire.ZeroConfRequired,
+	):
+
+		if !hasFeatures(
+			local, remote,
+			lnwire.ZeroFeeCommitmentsOptional,
+			lnwire.ZeroConfOptional,
+		) {
+
+			return 0, errUnsupportedChannelType
+		}
+
+		return lnwallet.CommitmentTypeZeroFeeCommitments, nil
+
+	// Zero-fee commitments with scid and zero conf.
+	case channelFeatures.OnlyContains(
+		lnwire.ZeroFeeCommitmentsRequired,
+		lnwire.ZeroConfRequired,
+		lnwire.ScidAliasRequired,
+	):
+
+		if !hasFeatures(
+			local, remote,
+			lnwire.ZeroFeeCommitmentsOptional,
+			lnwire.ZeroConfOptional,
+			lnwire.ScidAliasOptional,
+		) {
+
+			return 0, errUnsupportedChannelType
+		}
+
+		return lnwallet.CommitmentTypeZeroFeeCommitments, nil
+
 	// No features, use legacy commitment type.
 	case channelFeatures.IsEmpty():
 		return lnwallet.CommitmentTypeLegacy, nil
@@ -214,6 +214,50 @@ func TestCommitmentTypeNegotiation(t *testing.T) {
 			),
 			expectsErr: nil,
 		},
+		{
+			name: "explicit zero-fee commitments",
+			channelFeatures: lnwire.NewRawFeatureVector(
+				lnwire.ZeroFeeCommitmentsRequired,
+			),
+			localFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ZeroFeeCommitmentsOptional,
+				lnwire.ExplicitChannelTypeOptional,
+			),
+			remoteFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyOptional,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ZeroFeeCommitmentsOptional,
+				lnwire.ExplicitChannelTypeOptional,
+			),
+			//nolint:lll
+			expectsCommitType: lnwallet.CommitmentTypeZeroFeeCommitments,
+			expectsChanType: (*lnwire.ChannelType)(
+				lnwire.NewRawFeatureVector(
+					lnwire.ZeroFeeCommitmentsRequired,
+				),
+			),
+			expectsErr: nil,
+		},
+		{
+			name: "explicit zero-fee commitments missing remote",
+			channelFeatures: lnwire.NewRawFeatureVector(
+				lnwire.ZeroFeeCommitmentsRequired,
+			),
+			localFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ZeroFeeCommitmentsOptional,
+				lnwire.ExplicitChannelTypeOptional,
+			),
+			remoteFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyOptional,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ExplicitChannelTypeOptional,
+			),
+			expectsErr: errUnsupportedChannelType,
+		},
 		{
 			name: "explicit tweakless",
 			channelFeatures: lnwire.NewRawFeatureVector(
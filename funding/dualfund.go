@@ -0,0 +1,2054 @@
+package funding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// errDualFundNotNegotiated is returned when a peer attempts to open a
+	// dual-funded channel without both of us signaling support for it.
+	errDualFundNotNegotiated = errors.New("dual funding not negotiated")
+
+	// errDualFundChanType is returned when a dual-funded channel is
+	// proposed with a channel type we don't support for such channels.
+	errDualFundChanType = errors.New("channel type not supported for " +
+		"dual-funded channels")
+
+	// errDualFundRbfRestarted is sent to the remote party if it attempts
+	// to replace the funding transaction of a dual-funded channel that was
+	// negotiated before we restarted. The coins we contributed to it are
+	// only tracked by its reservation, which doesn't survive a restart.
+	// The funding transactions that were signed before are still watched.
+	errDualFundRbfRestarted = errors.New("funding transaction can't be " +
+		"replaced after a restart")
+)
+
+// dualFundState describes the step of a dual-funded channel open we're
+// currently in, once both parties exchanged their channel parameters.
+type dualFundState uint8
+
+const (
+	// dualFundNegotiating means that the inputs and outputs of the
+	// funding transaction are being exchanged.
+	dualFundNegotiating dualFundState = iota
+
+	// dualFundAwaitCommitSig means that we sent our signature for the
+	// remote party's commitment and are waiting for theirs.
+	dualFundAwaitCommitSig
+
+	// dualFundAwaitTxSigs means that the commitments are signed and the
+	// pending channel is written to disk, and we're waiting for the remote
+	// party's signatures for the funding transaction.
+	dualFundAwaitTxSigs
+
+	// dualFundAwaitAckRbf means that we proposed to replace the funding
+	// transaction with tx_init_rbf, and are waiting for tx_ack_rbf.
+	dualFundAwaitAckRbf
+
+	// dualFundSigned means that the funding transaction is fully signed,
+	// and no replacement of it is being negotiated.
+	dualFundSigned
+)
+
+// dualFundSession tracks the interactive construction of the funding
+// transaction of a dual-funded channel. It's created once both parties have
+// exchanged their channel parameters, and lives until a funding transaction
+// confirms, as the opener may replace it with tx_init_rbf until then.
+type dualFundSession struct {
+	// opener is the party that sent open_channel2.
+	opener lntypes.ChannelParty
+
+	// tempChanID is the temporary channel ID of the open_channel2 message,
+	// under which the reservation is tracked.
+	tempChanID PendingChanID
+
+	// chanID is the channel ID derived from the revocation basepoints of
+	// both parties. It's used by all messages sent after accept_channel2.
+	chanID lnwire.ChannelID
+
+	// state is the current step of the negotiation.
+	state dualFundState
+
+	// remoteAmt is the amount the remote party contributes to the funding
+	// output.
+	remoteAmt btcutil.Amount
+
+	// interactiveTx tracks the construction of the funding transaction.
+	interactiveTx *chanfunding.InteractiveTx
+
+	// pendingUpdates are the tx_add_input and tx_add_output messages we
+	// still have to send, one per turn.
+	pendingUpdates []lnwire.Message
+
+	// fundingTx is the funding transaction once its construction is
+	// complete. It carries the witnesses of our inputs.
+	fundingTx *wire.MsgTx
+
+	// prevOutFetcher returns the outputs spent by the inputs of fundingTx.
+	prevOutFetcher *txscript.MultiPrevOutFetcher
+
+	// channel is the pending channel, once it has been written to disk.
+	channel *channeldb.OpenChannel
+
+	// sentTxSigs is true once we sent our signatures for the funding
+	// transaction. From that point on, the remote party is able to publish
+	// it, so we must not forget the channel.
+	sentTxSigs bool
+
+	// replacing is true once the funding transaction is fully signed. Any
+	// funding transaction constructed afterwards replaces it, and is
+	// stored as a candidate of the pending channel.
+	replacing bool
+
+	// feeRate is the fee rate of the latest funding transaction that was
+	// fully signed. A replacement must pay a higher fee rate.
+	feeRate chainfee.SatPerKWeight
+
+	// rbfFeeRate is the fee rate of the replacement being negotiated.
+	rbfFeeRate chainfee.SatPerKWeight
+
+	// candidate is the replacement being negotiated, once its commitments
+	// are known.
+	candidate *channeldb.FundingCandidate
+}
+
+// pendingDualFund tracks a dual-funded channel whose funding transaction is
+// signed, until one of the candidate funding transactions confirms.
+type pendingDualFund struct {
+	// peer is the node the channel is opened with.
+	peer serializedPubKey
+
+	// channel is the pending channel.
+	channel *channeldb.OpenChannel
+
+	// resCtx is the reservation context the channel was negotiated with,
+	// which is used to negotiate replacements of its funding transaction.
+	// It's nil for channels loaded after a restart.
+	resCtx *reservationWithCtx
+
+	// newCandidate is signaled when a replacement of the funding
+	// transaction was stored, so that its confirmation is watched as well.
+	//
+	// NOTE: This channel MUST be buffered.
+	newCandidate chan struct{}
+}
+
+// dualFundBumpReq is a request to replace the funding transaction of a
+// pending dual-funded channel we opened.
+type dualFundBumpReq struct {
+	chanPoint wire.OutPoint
+	feeRate   chainfee.SatPerKWeight
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
+// dualFundChanID returns the channel ID of a dual-funded channel, which is
+// derived from the revocation basepoints of both parties.
+func dualFundChanID(channel *channeldb.OpenChannel) lnwire.ChannelID {
+	return lnwire.NewChanIDFromRevocationBasepoints(
+		channel.LocalChanCfg.RevocationBasePoint.PubKey,
+		channel.RemoteChanCfg.RevocationBasePoint.PubKey,
+	)
+}
+
+// checkRbfFeeRate checks that the fee rate of a replacement of a funding
+// transaction is at least 25/24 of the fee rate of the one it replaces.
+func checkRbfFeeRate(prev, feeRate chainfee.SatPerKWeight) error {
+	if uint64(feeRate)*24 < uint64(prev)*25 {
+		return fmt.Errorf("fee rate %v of replacement too low, "+
+			"previous fee rate is %v", feeRate, prev)
+	}
+
+	return nil
+}
+
+// spendsSameInput returns true if both transactions spend a common input,
+// which means that at most one of them can confirm.
+func spendsSameInput(a, b *wire.MsgTx) bool {
+	prevOuts := make(map[wire.OutPoint]struct{}, len(a.TxIn))
+	for _, txIn := range a.TxIn {
+		prevOuts[txIn.PreviousOutPoint] = struct{}{}
+	}
+	for _, txIn := range b.TxIn {
+		if _, ok := prevOuts[txIn.PreviousOutPoint]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dualFundChanReserve returns the channel reserve both parties of a
+// dual-funded channel require from each other. It's 1% of the channel
+// capacity, but at least the larger of the two dust limits.
+func dualFundChanReserve(capacity, localDust,
+	remoteDust btcutil.Amount) btcutil.Amount {
+
+	return max(capacity/100, localDust, remoteDust)
+}
+
+// canDualFund returns true if we can open the channel requested by the passed
+// InitFundingMsg using the dual-funded channel establishment protocol. This is
+// only the case if both parties signal support for it, and if the request
+// doesn't make use of any features that are specific to the single funder
+// protocol.
+func (f *Manager) canDualFund(msg *InitFundingMsg,
+	commitType lnwallet.CommitmentType, zeroConf bool) bool {
+
+	if !hasFeatures(
+		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) {
+
+		return false
+	}
+
+	switch {
+	case msg.ChanFunder != nil, msg.PushAmt != 0, msg.SubtractFees,
+		msg.FundUpToMaxAmt != 0, len(msg.Outpoints) != 0,
+		msg.PendingChanID != zeroID:
+
+		return false
+
+	case f.cfg.AuxFundingController.IsSome():
+		return false
+
+	case commitType.IsTaproot(), zeroConf,
+		commitType == lnwallet.CommitmentTypeZeroFeeCommitments:
+
+		return false
+
+	// Script enforced leases are only used for leases we buy through
+	// liquidity ads.
+	case commitType == lnwallet.CommitmentTypeScriptEnforcedLease:
+		return msg.lease != nil
+	}
+
+	return true
+}
+
+// sendOpenChannel2 sends the open_channel2 message kicking off the dual-funded
+// channel open tracked by the passed reservation context.
+func (f *Manager) sendOpenChannel2(resCtx *reservationWithCtx,
+	pendingChanID PendingChanID, fundingFeePerKw,
+	commitFeePerKw chainfee.SatPerKWeight, channelFlags lnwire.FundingFlag,
+	shutdown lnwire.DeliveryAddress) error {
+
+	reservation := resCtx.reservation
+	ourContribution := reservation.OurContribution()
+
+	// The remote contribution isn't known yet, so the capacity is what we
+	// contribute to the channel.
+	fundingAmt := reservation.Capacity()
+
+	secondPoint, err := reservation.ChanState().SecondCommitmentPoint()
+	if err != nil {
+		return err
+	}
+
+	// The funding transaction uses the current height as its lock time to
+	// discourage fee sniping.
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	resCtx.dualFund = &dualFundSession{
+		opener:     lntypes.Local,
+		tempChanID: pendingChanID,
+		state:      dualFundNegotiating,
+		interactiveTx: chanfunding.NewInteractiveTx(
+			lntypes.Local, uint32(bestHeight),
+		),
+		feeRate: fundingFeePerKw,
+	}
+	reservation.SetState(lnwallet.SentOpenChannel)
+
+	fundingOpen := &lnwire.OpenChannel2{
+		ChainHash:             *f.cfg.Wallet.Cfg.NetParams.GenesisHash,
+		PendingChannelID:      pendingChanID,
+		FundingFeePerKWeight:  fundingFeePerKw,
+		CommitFeePerKWeight:   commitFeePerKw,
+		FundingAmount:         fundingAmt,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      resCtx.remoteMaxValue,
+		HtlcMinimum:           resCtx.remoteMinHtlc,
+		CsvDelay:              resCtx.remoteCsvDelay,
+		MaxAcceptedHTLCs:      resCtx.remoteMaxHtlcs,
+		LockTime:              uint32(bestHeight),
+		FundingKey:            ourContribution.MultiSigKey.PubKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint.PubKey,
+		PaymentPoint:          ourContribution.PaymentBasePoint.PubKey,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint.PubKey,
+		HtlcPoint:             ourContribution.HtlcBasePoint.PubKey,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		SecondCommitmentPoint: secondPoint,
+		ChannelFlags:          channelFlags,
+		UpfrontShutdownScript: shutdown,
+		ChannelType:           resCtx.channelType,
+	}
+	if resCtx.lease != nil {
+		request := resCtx.lease.request
+		fundingOpen.RequestFunding = &request
+	}
+	log.Infof("Starting dual-funded workflow with %v for pending_id(%x)",
+		resCtx.peer.Address(), pendingChanID[:])
+
+	return resCtx.peer.SendMessage(true, fundingOpen)
+}
+
+// openChannelView returns an OpenChannel message with the fields of the passed
+// OpenChannel2 message, so that channel acceptors that only know about the
+// single funder protocol can make a decision about the channel.
+func openChannelView(msg *lnwire.OpenChannel2) *lnwire.OpenChannel {
+	return &lnwire.OpenChannel{
+		ChainHash:             msg.ChainHash,
+		PendingChannelID:      msg.PendingChannelID,
+		FundingAmount:         msg.FundingAmount,
+		DustLimit:             msg.DustLimit,
+		MaxValueInFlight:      msg.MaxValueInFlight,
+		HtlcMinimum:           msg.HtlcMinimum,
+		FeePerKiloWeight:      uint32(msg.CommitFeePerKWeight),
+		CsvDelay:              msg.CsvDelay,
+		MaxAcceptedHTLCs:      msg.MaxAcceptedHTLCs,
+		FundingKey:            msg.FundingKey,
+		RevocationPoint:       msg.RevocationPoint,
+		PaymentPoint:          msg.PaymentPoint,
+		DelayedPaymentPoint:   msg.DelayedPaymentPoint,
+		HtlcPoint:             msg.HtlcPoint,
+		FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+		ChannelFlags:          msg.ChannelFlags,
+		UpfrontShutdownScript: msg.UpfrontShutdownScript,
+		ChannelType:           msg.ChannelType,
+	}
+}
+
+// fundeeProcessOpenChannel2 handles a dual-funded channel proposed by the
+// remote peer. If we accept it, we decide on our contribution to the channel,
+// reserve the coins needed for it and respond with accept_channel2.
+//
+//nolint:funlen
+func (f *Manager) fundeeProcessOpenChannel2(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel2) {
+
+	peerPubKey := peer.IdentityKey()
+	peerIDKey := newSerializedKey(peerPubKey)
+	openerAmt := msg.FundingAmount
+
+	// Create the channel identifier.
+	cid := newChanIdentifier(msg.PendingChannelID)
+
+	if !hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) {
+
+		f.failFundingFlow(peer, cid, errDualFundNotNegotiated)
+		return
+	}
+
+	// The temporary channel ID must be derived from the revocation
+	// basepoint of the opener, which ensures it can't collide with the ID
+	// of another pending channel.
+	tempChanID := lnwire.NewTempChanIDFromRevocationBasepoint(
+		msg.RevocationPoint,
+	)
+	if tempChanID != msg.PendingChannelID {
+		err := errors.New("invalid temporary channel id")
+		f.failFundingFlow(peer, cid, err)
+
+		return
+	}
+
+	if f.IsPendingChannel(msg.PendingChannelID, peer) {
+		log.Warnf("Received duplicate open_channel2 for "+
+			"pending_id(%x) from peer(%x)", msg.PendingChannelID[:],
+			peerPubKey.SerializeCompressed())
+
+		return
+	}
+
+	if err := f.checkPendingChannelLimits(peerPubKey); err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	if openerAmt > f.cfg.MaxChanSize {
+		err := lnwallet.ErrChanTooLarge(openerAmt, f.cfg.MaxChanSize)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// If the opener requests to buy a lease from us, we'll check the
+	// request against the rates we sell leases at.
+	var lease *leaseTerms
+	if msg.RequestFunding != nil {
+		var err error
+		lease, err = f.validateLeaseRequest(msg)
+		if err != nil {
+			f.failFundingFlow(peer, cid, err)
+			return
+		}
+	}
+
+	chanType, commitType, err := negotiateCommitmentType(
+		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
+	)
+	if err != nil {
+		log.Errorf("channel type negotiation failed: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	var scid bool
+	if chanType != nil {
+		featureVec := lnwire.RawFeatureVector(*chanType)
+		scid = featureVec.IsSet(lnwire.ScidAliasRequired)
+
+		if featureVec.IsSet(lnwire.ZeroConfRequired) {
+			f.failFundingFlow(peer, cid, errDualFundChanType)
+			return
+		}
+	}
+
+	public := msg.ChannelFlags&lnwire.FFAnnounceChannel != 0
+	isLease := commitType == lnwallet.CommitmentTypeScriptEnforcedLease
+	switch {
+	// Script enforced leases are only used for leases requested through
+	// request_funding, which must use them.
+	case commitType.IsTaproot(), isLease != (lease != nil):
+		f.failFundingFlow(peer, cid, errDualFundChanType)
+		return
+
+	// Sending the option-scid-alias channel type for a public channel is
+	// disallowed.
+	case public && scid:
+		err = fmt.Errorf("option-scid-alias chantype for public " +
+			"channel")
+		f.failFundingFlow(peer, cid, err)
+
+		return
+	}
+
+	// Query our channel acceptor to determine whether we should reject the
+	// channel, and how much we'll contribute to it.
+	chanReq := &chanacceptor.ChannelAcceptRequest{
+		Node:         peerPubKey,
+		OpenChanMsg:  openChannelView(msg),
+		OpenChannel2: msg,
+	}
+	acceptorResp := f.cfg.OpenChannelPredicate.Accept(chanReq)
+	if acceptorResp.RejectChannel() {
+		f.failFundingFlow(peer, cid, acceptorResp.ChanAcceptError)
+		return
+	}
+
+	contribution := acceptorResp.FundingContribution
+	if contribution == 0 && f.cfg.DualFundContribution != nil {
+		contribution = f.cfg.DualFundContribution(chanReq)
+	}
+
+	// Make sure that our contribution doesn't push the channel above our
+	// maximum channel size.
+	contribution = max(min(contribution, f.cfg.MaxChanSize-openerAmt), 0)
+
+	// If we sell a lease of the channel, we contribute exactly the leased
+	// amount, which validateLeaseRequest already checked against our
+	// maximum channel size.
+	if lease != nil {
+		contribution = lease.request.RequestedAmount
+	}
+
+	log.Infof("Recv'd dual-funded fundingRequest(amt=%v, contribution=%v, "+
+		"delay=%v, pendingId=%x) from peer(%x)", openerAmt,
+		contribution, msg.CsvDelay, msg.PendingChannelID,
+		peerPubKey.SerializeCompressed())
+
+	req := &lnwallet.InitFundingReserveMsg{
+		ChainHash:           &msg.ChainHash,
+		PendingChanID:       msg.PendingChannelID,
+		NodeID:              peerPubKey,
+		NodeAddr:            peer.Address(),
+		LocalFundingAmt:     contribution,
+		RemoteFundingAmt:    openerAmt,
+		CommitFeePerKw:      msg.CommitFeePerKWeight,
+		FundingFeePerKw:     msg.FundingFeePerKWeight,
+		Flags:               msg.ChannelFlags,
+		MinConfs:            1,
+		CommitType:          commitType,
+		AllowUtxoForFunding: f.allowUtxoForFunding,
+		OptionScidAlias:     scid,
+		ScidAliasFeature: hasFeatures(
+			peer.LocalFeatures(), peer.RemoteFeatures(),
+			lnwire.ScidAliasOptional,
+		),
+		DualFundOpener: fn.Some(lntypes.Remote),
+	}
+	if lease != nil {
+		req.LeaseExpiry = lease.expiry()
+		req.LeaseFee = lease.fee
+	}
+
+	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
+
+	// If we're unable to fund our contribution, we'll still accept the
+	// channel, but without contributing to it. This isn't possible for
+	// leased channels, as we must contribute the leased amount.
+	if err != nil && contribution != 0 && lease == nil {
+		log.Warnf("Unable to contribute %v to dual-funded channel "+
+			"pendingId=%x, accepting it without contribution: %v",
+			contribution, msg.PendingChannelID, err)
+
+		// Release anything that was reserved by the failed attempt.
+		_ = f.cfg.Wallet.CancelFundingIntent(msg.PendingChannelID)
+
+		req.LocalFundingAmt = 0
+		reservation, err = f.cfg.Wallet.InitChannelReservation(req)
+	}
+	if err != nil {
+		log.Errorf("Unable to initialize reservation: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// From now on, we'll fail the funding flow through the reservation
+	// context, which releases the reservation.
+	capacity := reservation.Capacity()
+	fail := func(err error) {
+		if cancelErr := reservation.Cancel(); cancelErr != nil {
+			log.Errorf("Unable to cancel reservation: %v",
+				cancelErr)
+		}
+		f.failFundingFlow(peer, cid, err)
+	}
+
+	if capacity < f.cfg.MinChanSize {
+		fail(lnwallet.ErrChanTooSmall(capacity, f.cfg.MinChanSize))
+		return
+	}
+
+	// As we're the responder, we get to specify the number of
+	// confirmations that we require before both of us consider the
+	// channel open.
+	numConfsReq := f.cfg.NumRequiredConfs(capacity, 0)
+	if acceptorResp.MinAcceptDepth != 0 {
+		numConfsReq = acceptorResp.MinAcceptDepth
+	}
+	reservation.SetNumConfsRequired(numConfsReq)
+
+	// Both parties require the same channel reserve, which is derived
+	// from the capacity of the channel.
+	ourDustLimit := reservation.OurContribution().DustLimit
+	chanReserve := dualFundChanReserve(
+		capacity, ourDustLimit, msg.DustLimit,
+	)
+
+	// We'll also validate and apply all the constraints the opener is
+	// attempting to dictate for our commitment transaction.
+	stateBounds := &channeldb.ChannelStateBounds{
+		ChanReserve:      chanReserve,
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+	}
+	commitParams := &channeldb.CommitmentParams{
+		DustLimit: msg.DustLimit,
+		CsvDelay:  msg.CsvDelay,
+	}
+	err = reservation.CommitConstraints(
+		stateBounds, commitParams, f.cfg.MaxLocalCSVDelay, true,
+	)
+	if err != nil {
+		log.Errorf("Unacceptable channel constraints: %v", err)
+		fail(err)
+		return
+	}
+
+	shutdown, err := getUpfrontShutdownScript(
+		f.cfg.EnableUpfrontShutdown, peer, acceptorResp.UpfrontShutdown,
+		f.selectShutdownScript,
+	)
+	if err != nil {
+		fail(fmt.Errorf("getUpfrontShutdownScript error: %w", err))
+		return
+	}
+	reservation.SetOurUpfrontShutdown(shutdown)
+
+	// Generate our required constraints for the remote party, using the
+	// values provided by the channel acceptor if they are non-zero.
+	remoteCsvDelay := f.cfg.RequiredRemoteDelay(capacity)
+	if acceptorResp.CSVDelay != 0 {
+		remoteCsvDelay = acceptorResp.CSVDelay
+	}
+
+	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(capacity)
+	if acceptorResp.InFlightTotal != 0 {
+		remoteMaxValue = acceptorResp.InFlightTotal
+	}
+
+	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(capacity)
+	if acceptorResp.HtlcLimit != 0 {
+		maxHtlcs = acceptorResp.HtlcLimit
+	}
+
+	minHtlc := f.cfg.DefaultMinHtlcIn
+	if acceptorResp.MinHtlcIn != 0 {
+		minHtlc = acceptorResp.MinHtlcIn
+	}
+
+	ourContribution := reservation.OurContribution()
+	forwardingPolicy := f.defaultForwardingPolicy(
+		ourContribution.ChannelStateBounds,
+	)
+
+	// We'll promise not to charge more than our advertised maximum routing
+	// fees on a leased channel.
+	if lease != nil {
+		forwardingPolicy.BaseFee = min(
+			forwardingPolicy.BaseFee, lease.rates.ChannelFeeMaxBase,
+		)
+		forwardingPolicy.FeeRate = min(
+			forwardingPolicy.FeeRate,
+			lnwire.MilliSatoshi(lease.rates.ChannelFeeMaxPPM()),
+		)
+	}
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FirstCommitmentPoint: msg.FirstCommitmentPoint,
+		ChannelConfig: dualFundRemoteChanCfg(
+			msg.FundingKey, msg.RevocationPoint, msg.PaymentPoint,
+			msg.DelayedPaymentPoint, msg.HtlcPoint,
+			channeldb.ChannelStateBounds{
+				MaxPendingAmount: remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          minHtlc,
+				MaxAcceptedHtlcs: maxHtlcs,
+			},
+			channeldb.CommitmentParams{
+				DustLimit: msg.DustLimit,
+				CsvDelay:  remoteCsvDelay,
+			},
+		),
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+	err = reservation.ProcessDualFundContribution(remoteContribution)
+	if err != nil {
+		log.Errorf("Unable to add contribution reservation: %v", err)
+		fail(err)
+		return
+	}
+
+	secondPoint, err := reservation.ChanState().SecondCommitmentPoint()
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	chanID := lnwire.NewChanIDFromRevocationBasepoints(
+		ourContribution.RevocationBasePoint.PubKey,
+		msg.RevocationPoint,
+	)
+
+	resCtx := &reservationWithCtx{
+		reservation:       reservation,
+		chanAmt:           capacity,
+		forwardingPolicy:  *forwardingPolicy,
+		remoteCsvDelay:    remoteCsvDelay,
+		remoteMinHtlc:     minHtlc,
+		remoteMaxValue:    remoteMaxValue,
+		remoteMaxHtlcs:    maxHtlcs,
+		remoteChanReserve: chanReserve,
+		maxLocalCsv:       f.cfg.MaxLocalCSVDelay,
+		channelType:       chanType,
+		err:               make(chan error, 1),
+		peer:              peer,
+		dualFund: &dualFundSession{
+			opener:     lntypes.Remote,
+			tempChanID: msg.PendingChannelID,
+			chanID:     chanID,
+			state:      dualFundNegotiating,
+			remoteAmt:  openerAmt,
+			interactiveTx: chanfunding.NewInteractiveTx(
+				lntypes.Remote, msg.LockTime,
+			),
+			feeRate: msg.FundingFeePerKWeight,
+		},
+	}
+
+	updates, err := f.dualFundTxUpdates(resCtx)
+	if err != nil {
+		fail(err)
+		return
+	}
+	resCtx.dualFund.pendingUpdates = updates
+
+	// Once the reservation has been created successfully, we add it to
+	// this peer's map of pending reservations to track this particular
+	// reservation until either abort or completion.
+	f.resMtx.Lock()
+	if _, ok := f.activeReservations[peerIDKey]; !ok {
+		f.activeReservations[peerIDKey] = make(pendingChannels)
+	}
+	f.activeReservations[peerIDKey][msg.PendingChannelID] = resCtx
+	f.dualFundChanIDs[chanID] = msg.PendingChannelID
+	f.resMtx.Unlock()
+
+	// Update the timestamp once the OpenChannel2 message has been handled.
+	defer resCtx.updateTimestamp()
+
+	// Our contribution may differ from our initial balance, which includes
+	// the fee of a lease we sell.
+	fundingAmt := capacity - openerAmt
+
+	log.Infof("Sending dual-funded fundingResp for pending_id(%x), "+
+		"contribution=%v", msg.PendingChannelID, fundingAmt)
+
+	reservation.SetState(lnwallet.SentAcceptChannel)
+
+	fundingAccept := &lnwire.AcceptChannel2{
+		PendingChannelID:      msg.PendingChannelID,
+		FundingAmount:         fundingAmt,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      remoteMaxValue,
+		HtlcMinimum:           minHtlc,
+		MinAcceptDepth:        uint32(numConfsReq),
+		CsvDelay:              remoteCsvDelay,
+		MaxAcceptedHTLCs:      maxHtlcs,
+		FundingKey:            ourContribution.MultiSigKey.PubKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint.PubKey,
+		PaymentPoint:          ourContribution.PaymentBasePoint.PubKey,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint.PubKey,
+		HtlcPoint:             ourContribution.HtlcBasePoint.PubKey,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		SecondCommitmentPoint: secondPoint,
+		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
+		ChannelType:           chanType,
+	}
+
+	// If we sell a lease of the channel, we commit to it with our
+	// signature.
+	if lease != nil {
+		log.Infof("Selling lease of %v for fee %v to peer %x, "+
+			"pending_id(%x), expiry=%d",
+			lease.request.RequestedAmount, lease.fee,
+			peerPubKey.SerializeCompressed(),
+			msg.PendingChannelID[:], lease.expiry())
+
+		fundingAccept.WillFund, err = f.signLease(
+			ourContribution.MultiSigKey.PubKey, lease,
+		)
+		if err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	if err := peer.SendMessage(true, fundingAccept); err != nil {
+		log.Errorf("Unable to send AcceptChannel2 to peer: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+}
+
+// funderProcessAcceptChannel2 processes the response to a dual-funded channel
+// we proposed, and starts the construction of the funding transaction.
+func (f *Manager) funderProcessAcceptChannel2(peer lnpeer.Peer,
+	msg *lnwire.AcceptChannel2) {
+
+	pendingChanID := msg.PendingChannelID
+	peerKey := peer.IdentityKey()
+
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil {
+		log.Warnf("Can't find reservation (peerKey:%x, chan_id:%v)",
+			peerKey.SerializeCompressed(), pendingChanID)
+		return
+	}
+
+	// Update the timestamp once the AcceptChannel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	// The session is created once we send open_channel2, and its channel
+	// ID is only known once we processed accept_channel2.
+	reservation := resCtx.reservation
+	session := resCtx.dualFund
+	if reservation.State() != lnwallet.SentOpenChannel || session == nil ||
+		session.chanID != (lnwire.ChannelID{}) {
+
+		return
+	}
+
+	log.Infof("Recv'd dual-funded fundingResponse for pending_id(%x), "+
+		"contribution=%v", pendingChanID[:], msg.FundingAmount)
+
+	// Create the channel identifier.
+	cid := newChanIdentifier(pendingChanID)
+
+	// The channel type must be echoed back if we proposed one, and must
+	// not be set otherwise.
+	switch {
+	case resCtx.channelType == nil && msg.ChannelType == nil:
+
+	case resCtx.channelType == nil || msg.ChannelType == nil:
+		err := errors.New("channel type mismatch")
+		f.failFundingFlow(peer, cid, err)
+		return
+
+	default:
+		proposed := lnwire.RawFeatureVector(*resCtx.channelType)
+		acked := lnwire.RawFeatureVector(*msg.ChannelType)
+		if !proposed.Equals(&acked) {
+			err := errors.New("channel type mismatch")
+			f.failFundingFlow(peer, cid, err)
+			return
+		}
+	}
+
+	// If we requested a lease, the acceptor must commit to it at the rates
+	// it advertises.
+	if err := checkWillFund(peerKey, resCtx.lease, msg); err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// The required number of confirmations should not be greater than the
+	// maximum number of confirmations required by the ChainNotifier to
+	// properly dispatch confirmations.
+	if msg.MinAcceptDepth > chainntnfs.MaxNumConfs {
+		err := lnwallet.ErrNumConfsTooLarge(
+			msg.MinAcceptDepth, chainntnfs.MaxNumConfs,
+		)
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	reservation.SetNumConfsRequired(uint16(max(msg.MinAcceptDepth, 1)))
+
+	// With the contribution of the remote party known, the capacity of the
+	// channel is final.
+	err = reservation.SetDualFundRemoteAmt(msg.FundingAmount)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	capacity := reservation.Capacity()
+	resCtx.chanAmt = capacity
+
+	ourContribution := reservation.OurContribution()
+	chanReserve := dualFundChanReserve(
+		capacity, ourContribution.DustLimit, msg.DustLimit,
+	)
+	resCtx.remoteChanReserve = chanReserve
+
+	bounds := channeldb.ChannelStateBounds{
+		ChanReserve:      chanReserve,
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+	}
+	commitParams := channeldb.CommitmentParams{
+		DustLimit: msg.DustLimit,
+		CsvDelay:  msg.CsvDelay,
+	}
+	err = reservation.CommitConstraints(
+		&bounds, &commitParams, resCtx.maxLocalCsv, false,
+	)
+	if err != nil {
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FirstCommitmentPoint: msg.FirstCommitmentPoint,
+		ChannelConfig: dualFundRemoteChanCfg(
+			msg.FundingKey, msg.RevocationPoint, msg.PaymentPoint,
+			msg.DelayedPaymentPoint, msg.HtlcPoint,
+			channeldb.ChannelStateBounds{
+				MaxPendingAmount: resCtx.remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          resCtx.remoteMinHtlc,
+				MaxAcceptedHtlcs: resCtx.remoteMaxHtlcs,
+			},
+			channeldb.CommitmentParams{
+				DustLimit: msg.DustLimit,
+				CsvDelay:  resCtx.remoteCsvDelay,
+			},
+		),
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+	err = reservation.ProcessDualFundContribution(remoteContribution)
+	if err != nil {
+		log.Errorf("Unable to process contribution from %x: %v",
+			peerKey.SerializeCompressed(), err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	chanID := lnwire.NewChanIDFromRevocationBasepoints(
+		ourContribution.RevocationBasePoint.PubKey,
+		msg.RevocationPoint,
+	)
+
+	session.chanID = chanID
+	session.remoteAmt = msg.FundingAmount
+
+	updates, err := f.dualFundTxUpdates(resCtx)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	session.pendingUpdates = updates
+
+	f.resMtx.Lock()
+	f.dualFundChanIDs[chanID] = pendingChanID
+	f.resMtx.Unlock()
+
+	// As the opener, we're the first to add inputs and outputs to the
+	// funding transaction.
+	f.sendNextDualFundTxUpdate(resCtx)
+}
+
+// dualFundRemoteChanCfg returns the channel config of the remote party of a
+// dual-funded channel.
+func dualFundRemoteChanCfg(fundingKey, revocationPoint, paymentPoint,
+	delayPoint, htlcPoint *btcec.PublicKey,
+	bounds channeldb.ChannelStateBounds,
+	params channeldb.CommitmentParams) *channeldb.ChannelConfig {
+
+	return &channeldb.ChannelConfig{
+		ChannelStateBounds: bounds,
+		CommitmentParams:   params,
+		MultiSigKey: keychain.KeyDescriptor{
+			PubKey: copyPubKey(fundingKey),
+		},
+		RevocationBasePoint: keychain.KeyDescriptor{
+			PubKey: copyPubKey(revocationPoint),
+		},
+		PaymentBasePoint: keychain.KeyDescriptor{
+			PubKey: copyPubKey(paymentPoint),
+		},
+		DelayBasePoint: keychain.KeyDescriptor{
+			PubKey: copyPubKey(delayPoint),
+		},
+		HtlcBasePoint: keychain.KeyDescriptor{
+			PubKey: copyPubKey(htlcPoint),
+		},
+	}
+}
+
+// dualFundTxUpdates returns the messages adding our inputs and outputs to the
+// funding transaction of a dual-funded channel. The opener is the one adding
+// the funding output.
+func (f *Manager) dualFundTxUpdates(
+	resCtx *reservationWithCtx) ([]lnwire.Message, error) {
+
+	session := resCtx.dualFund
+
+	inputs, changeOutputs, fundingOutput, err :=
+		resCtx.reservation.DualFundTxContribution()
+	if err != nil {
+		return nil, err
+	}
+
+	// Our serial IDs must have the parity of our role in the negotiation,
+	// which is even for the opener.
+	serialID := uint64(0)
+	if session.opener == lntypes.Remote {
+		serialID = 1
+	}
+	nextSerialID := func() uint64 {
+		id := serialID
+		serialID += 2
+
+		return id
+	}
+
+	var updates []lnwire.Message
+	for _, prevOut := range inputs {
+		prevTx, err := f.cfg.Wallet.FetchTx(prevOut.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch previous tx "+
+				"of input %v: %w", prevOut, err)
+		}
+
+		var b bytes.Buffer
+		if err := prevTx.Serialize(&b); err != nil {
+			return nil, err
+		}
+
+		updates = append(updates, &lnwire.TxAddInput{
+			ChanID:    session.chanID,
+			SerialID:  nextSerialID(),
+			PrevTx:    b.Bytes(),
+			PrevTxOut: prevOut.Index,
+			Sequence:  mempool.MaxRBFSequence,
+		})
+	}
+
+	outputs := changeOutputs
+	if session.opener == lntypes.Local {
+		outputs = append(outputs, fundingOutput)
+	}
+	for _, txOut := range outputs {
+		updates = append(updates, &lnwire.TxAddOutput{
+			ChanID:   session.chanID,
+			SerialID: nextSerialID(),
+			Amount:   btcutil.Amount(txOut.Value),
+			PkScript: txOut.PkScript,
+		})
+	}
+
+	return updates, nil
+}
+
+// handleDualFundMsg processes a message that is part of the construction of
+// the funding transaction of a dual-funded channel. These messages refer to
+// the channel by the ID derived from both revocation basepoints.
+func (f *Manager) handleDualFundMsg(peer lnpeer.Peer,
+	msg lnwire.LinkUpdater) {
+
+	peerKey := peer.IdentityKey()
+	chanID := msg.TargetChanID()
+
+	resCtx, err := f.getDualFundCtx(peer, chanID)
+	switch {
+	// The funding transaction of a channel that was negotiated before a
+	// restart can't be replaced anymore.
+	case errors.Is(err, errDualFundRbfRestarted):
+		if _, ok := msg.(*lnwire.TxInitRbf); ok {
+			f.sendDualFundTxAbort(peer, chanID, err)
+		}
+
+		return
+
+	case err != nil:
+		log.Warnf("Received %v for unknown dual-funded channel %v "+
+			"from peer(%x): %v", msg.MsgType(), chanID,
+			peerKey.SerializeCompressed(), err)
+
+		return
+	}
+
+	defer resCtx.updateTimestamp()
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput, *lnwire.TxAddOutput, *lnwire.TxRemoveInput,
+		*lnwire.TxRemoveOutput:
+
+		f.handleDualFundTxUpdate(resCtx, msg)
+
+	case *lnwire.TxComplete:
+		f.handleDualFundTxComplete(resCtx)
+
+	case *lnwire.CommitSig:
+		f.handleDualFundCommitSig(resCtx, msg)
+
+	case *lnwire.TxSignatures:
+		f.handleDualFundTxSigs(resCtx, msg)
+
+	case *lnwire.TxInitRbf:
+		f.handleDualFundTxInitRbf(resCtx, msg)
+
+	case *lnwire.TxAckRbf:
+		f.handleDualFundTxAckRbf(resCtx, msg)
+
+	case *lnwire.TxAbort:
+		err := fmt.Errorf("dual-funded channel open aborted by "+
+			"remote party: %v", string(msg.Data))
+		log.Warn(err)
+		f.failDualFund(resCtx, err, false)
+
+	default:
+		log.Warnf("Unexpected %v for dual-funded channel %v",
+			msg.MsgType(), msg.TargetChanID())
+	}
+}
+
+// getDualFundCtx returns the reservation context of the dual-funded channel
+// with the given ID, whose funding transaction is either being negotiated, or
+// signed and waiting to confirm.
+func (f *Manager) getDualFundCtx(peer lnpeer.Peer,
+	chanID lnwire.ChannelID) (*reservationWithCtx, error) {
+
+	peerKey := peer.IdentityKey()
+
+	f.resMtx.RLock()
+	pendingChanID, negotiating := f.dualFundChanIDs[chanID]
+	pending, signed := f.dualFundPending[chanID]
+	f.resMtx.RUnlock()
+
+	switch {
+	case negotiating:
+		resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+		if err != nil {
+			return nil, err
+		}
+		if resCtx.dualFund == nil {
+			return nil, errors.New("reservation isn't dual-funded")
+		}
+
+		return resCtx, nil
+
+	case !signed || pending.peer != newSerializedKey(peerKey):
+		return nil, errors.New("unknown channel")
+
+	case pending.resCtx == nil:
+		return nil, errDualFundRbfRestarted
+	}
+
+	// The peer may have reconnected since the funding transaction was
+	// signed, so we'll reply to the connection the message came from.
+	pending.resCtx.peer = peer
+
+	return pending.resCtx, nil
+}
+
+// sendDualFundTxAbort sends tx_abort for the given dual-funded channel.
+func (f *Manager) sendDualFundTxAbort(peer lnpeer.Peer, chanID lnwire.ChannelID,
+	abortErr error) {
+
+	txAbort := &lnwire.TxAbort{
+		ChanID: chanID,
+		Data:   lnwire.ErrorData(abortErr.Error()),
+	}
+	if err := peer.SendMessage(false, txAbort); err != nil {
+		log.Errorf("Unable to send tx_abort: %v", err)
+	}
+}
+
+// resetDualFundTx starts the construction of a replacement of the funding
+// transaction at the given fee rate. Our contribution to the funding output
+// stays the same, and the fee for the higher fee rate of our inputs and
+// outputs is paid from our change.
+func (f *Manager) resetDualFundTx(resCtx *reservationWithCtx, lockTime uint32,
+	feeRate chainfee.SatPerKWeight) error {
+
+	session := resCtx.dualFund
+
+	err := resCtx.reservation.SetDualFundFeeRate(feeRate)
+	if err != nil {
+		return err
+	}
+
+	session.interactiveTx = chanfunding.NewInteractiveTx(
+		session.opener, lockTime,
+	)
+	session.fundingTx = nil
+	session.prevOutFetcher = nil
+	session.candidate = nil
+	session.sentTxSigs = false
+	session.rbfFeeRate = feeRate
+	session.state = dualFundNegotiating
+
+	updates, err := f.dualFundTxUpdates(resCtx)
+	if err != nil {
+		return err
+	}
+	session.pendingUpdates = updates
+
+	return nil
+}
+
+// BumpDualFundFee proposes to replace the funding transaction of the given
+// pending dual-funded channel, which we opened, with one paying the given fee
+// rate. Our inputs and contribution stay the same, while our change pays for
+// the higher fee. The method returns once tx_init_rbf has been sent, and the
+// replacement is published once the negotiation with the remote party
+// completes.
+func (f *Manager) BumpDualFundFee(chanPoint wire.OutPoint,
+	feeRate chainfee.SatPerKWeight) error {
+
+	req := &dualFundBumpReq{
+		chanPoint: chanPoint,
+		feeRate:   feeRate,
+		err:       make(chan error, 1),
+	}
+
+	select {
+	case f.dualFundBumps <- req:
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+}
+
+// handleDualFundBump sends tx_init_rbf to replace the funding transaction of a
+// pending dual-funded channel we opened.
+func (f *Manager) handleDualFundBump(req *dualFundBumpReq) {
+	var pending *pendingDualFund
+	f.resMtx.RLock()
+	for _, p := range f.dualFundPending {
+		if p.channel.FundingOutpoint == req.chanPoint {
+			pending = p
+			break
+		}
+	}
+	f.resMtx.RUnlock()
+
+	switch {
+	case pending == nil:
+		req.err <- fmt.Errorf("no pending dual-funded channel %v",
+			req.chanPoint)
+		return
+
+	case pending.resCtx == nil:
+		req.err <- errDualFundRbfRestarted
+		return
+
+	case pending.resCtx.dualFund.opener != lntypes.Local:
+		req.err <- errors.New("only the opener can replace the " +
+			"funding transaction")
+		return
+	}
+
+	resCtx := pending.resCtx
+	session := resCtx.dualFund
+	if err := checkRbfFeeRate(session.feeRate, req.feeRate); err != nil {
+		req.err <- err
+		return
+	}
+
+	// A replacement that is still being negotiated is abandoned in favor
+	// of the new one.
+	if session.state != dualFundSigned {
+		f.failDualFund(
+			resCtx, errors.New("replacement superseded"), true,
+		)
+	}
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		req.err <- err
+		return
+	}
+
+	err = f.resetDualFundTx(resCtx, uint32(bestHeight), req.feeRate)
+	if err != nil {
+		session.state = dualFundSigned
+		req.err <- err
+
+		return
+	}
+	session.state = dualFundAwaitAckRbf
+
+	localAmt := session.channel.Capacity - session.remoteAmt
+	initRbf := &lnwire.TxInitRbf{
+		ChanID:   session.chanID,
+		LockTime: uint32(bestHeight),
+		FeeRate:  req.feeRate,
+		FundingOutputContribution: tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType0](uint64(localAmt)),
+		),
+	}
+	if err := resCtx.peer.SendMessage(true, initRbf); err != nil {
+		session.state = dualFundSigned
+		req.err <- err
+
+		return
+	}
+
+	log.Infof("Proposed replacement of funding tx of ChannelPoint(%v) "+
+		"at fee rate %v", req.chanPoint, req.feeRate)
+
+	req.err <- nil
+}
+
+// checkRbfContribution checks that the contribution of the remote party to
+// the funding output of a replacement doesn't change, as the balances of the
+// channel are final once its commitments are signed.
+func checkRbfContribution(session *dualFundSession,
+	contribution tlv.OptionalRecordT[tlv.TlvType0, uint64]) error {
+
+	return fn.MapOptionZ(
+		contribution.ValOpt(), func(amt uint64) error {
+			if btcutil.Amount(amt) == session.remoteAmt {
+				return nil
+			}
+
+			return fmt.Errorf("contribution of %v to replacement "+
+				"differs from %v", btcutil.Amount(amt),
+				session.remoteAmt)
+		},
+	)
+}
+
+// handleDualFundTxInitRbf processes the opener's proposal to replace the
+// signed funding transaction with one at a higher fee rate, and acknowledges
+// it with tx_ack_rbf.
+func (f *Manager) handleDualFundTxInitRbf(resCtx *reservationWithCtx,
+	msg *lnwire.TxInitRbf) {
+
+	session := resCtx.dualFund
+
+	var err error
+	switch {
+	case !session.replacing:
+		err = errors.New("unexpected tx_init_rbf")
+
+	case session.opener != lntypes.Remote:
+		err = errors.New("only the opener can replace the funding " +
+			"transaction")
+
+	default:
+		err = checkRbfFeeRate(session.feeRate, msg.FeeRate)
+		if err == nil {
+			err = checkRbfContribution(
+				session, msg.FundingOutputContribution,
+			)
+		}
+	}
+	if err == nil {
+		err = f.resetDualFundTx(resCtx, msg.LockTime, msg.FeeRate)
+	}
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	log.Infof("Replacing funding tx of dual-funded channel %v at fee "+
+		"rate %v", session.chanID, msg.FeeRate)
+
+	localAmt := session.channel.Capacity - session.remoteAmt
+	err = resCtx.peer.SendMessage(false, &lnwire.TxAckRbf{
+		ChanID: session.chanID,
+		FundingOutputContribution: tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType0](uint64(localAmt)),
+		),
+	})
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+	}
+}
+
+// handleDualFundTxAckRbf processes the remote party's acknowledgement of our
+// proposal to replace the funding transaction, after which we start adding
+// our inputs and outputs to the replacement.
+func (f *Manager) handleDualFundTxAckRbf(resCtx *reservationWithCtx,
+	msg *lnwire.TxAckRbf) {
+
+	session := resCtx.dualFund
+	if session.state != dualFundAwaitAckRbf {
+		f.failDualFund(resCtx, errors.New("unexpected tx_ack_rbf"),
+			true)
+		return
+	}
+
+	err := checkRbfContribution(session, msg.FundingOutputContribution)
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	session.state = dualFundNegotiating
+	f.sendNextDualFundTxUpdate(resCtx)
+}
+
+// dualFundInput converts the passed tx_add_input into an input of the funding
+// transaction of a dual-funded channel.
+func dualFundInput(
+	msg *lnwire.TxAddInput) (chanfunding.InteractiveTxInput, error) {
+
+	in := chanfunding.InteractiveTxInput{
+		SerialID: msg.SerialID,
+		Sequence: msg.Sequence,
+	}
+
+	if msg.SharedInputTxid.IsSome() {
+		return in, errors.New("unexpected shared input")
+	}
+
+	var prevTx wire.MsgTx
+	if err := prevTx.Deserialize(bytes.NewReader(msg.PrevTx)); err != nil {
+		return in, fmt.Errorf("invalid prev tx: %w", err)
+	}
+	if int(msg.PrevTxOut) >= len(prevTx.TxOut) {
+		return in, fmt.Errorf("invalid prev tx output index %d",
+			msg.PrevTxOut)
+	}
+
+	in.OutPoint = wire.OutPoint{
+		Hash:  prevTx.TxHash(),
+		Index: msg.PrevTxOut,
+	}
+	in.PrevOut = *prevTx.TxOut[msg.PrevTxOut]
+
+	// Only segwit inputs are allowed, as the txid of the funding
+	// transaction must not change once it's signed.
+	if !txscript.IsWitnessProgram(in.PrevOut.PkScript) {
+		return in, fmt.Errorf("input %v is not a segwit input",
+			in.OutPoint)
+	}
+
+	return in, nil
+}
+
+// applyDualFundTxUpdate applies an input or output added or removed by the
+// given party to the funding transaction.
+func applyDualFundTxUpdate(session *dualFundSession,
+	party lntypes.ChannelParty, msg lnwire.Message) error {
+
+	interactiveTx := session.interactiveTx
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		in, err := dualFundInput(msg)
+		if err != nil {
+			return err
+		}
+
+		return interactiveTx.AddInput(party, in)
+
+	case *lnwire.TxAddOutput:
+		return interactiveTx.AddOutput(
+			party, chanfunding.InteractiveTxOutput{
+				SerialID: msg.SerialID,
+				TxOut: wire.TxOut{
+					Value:    int64(msg.Amount),
+					PkScript: msg.PkScript,
+				},
+			},
+		)
+
+	case *lnwire.TxRemoveInput:
+		return interactiveTx.RemoveInput(party, msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		return interactiveTx.RemoveOutput(party, msg.SerialID)
+
+	default:
+		return fmt.Errorf("unexpected funding tx update %T", msg)
+	}
+}
+
+// sendNextDualFundTxUpdate sends our next input or output of the funding
+// transaction, or tx_complete if all of them have been sent.
+func (f *Manager) sendNextDualFundTxUpdate(resCtx *reservationWithCtx) {
+	session := resCtx.dualFund
+
+	if len(session.pendingUpdates) == 0 {
+		f.sendDualFundTxComplete(resCtx)
+		return
+	}
+
+	update := session.pendingUpdates[0]
+	session.pendingUpdates = session.pendingUpdates[1:]
+
+	err := applyDualFundTxUpdate(session, lntypes.Local, update)
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	if err := resCtx.peer.SendMessage(false, update); err != nil {
+		f.failDualFund(resCtx, err, true)
+	}
+}
+
+// sendDualFundTxComplete signals the remote party that we have nothing left to
+// add to the funding transaction, and completes the construction if the
+// remote party did the same.
+func (f *Manager) sendDualFundTxComplete(resCtx *reservationWithCtx) {
+	session := resCtx.dualFund
+
+	txComplete := &lnwire.TxComplete{
+		ChanID: session.chanID,
+	}
+	if err := resCtx.peer.SendMessage(false, txComplete); err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	if session.interactiveTx.Complete(lntypes.Local) {
+		f.completeDualFundTx(resCtx)
+	}
+}
+
+// handleDualFundTxUpdate processes an input or output the remote party added
+// to, or removed from, the funding transaction.
+func (f *Manager) handleDualFundTxUpdate(resCtx *reservationWithCtx,
+	msg lnwire.Message) {
+
+	session := resCtx.dualFund
+	if session.state != dualFundNegotiating {
+		f.failDualFund(
+			resCtx, fmt.Errorf("unexpected %v", msg.MsgType()),
+			true,
+		)
+		return
+	}
+
+	err := applyDualFundTxUpdate(session, lntypes.Remote, msg)
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	f.sendNextDualFundTxUpdate(resCtx)
+}
+
+// handleDualFundTxComplete processes the remote party's tx_complete.
+func (f *Manager) handleDualFundTxComplete(resCtx *reservationWithCtx) {
+	session := resCtx.dualFund
+	if session.state != dualFundNegotiating {
+		f.failDualFund(resCtx, errors.New("unexpected tx_complete"),
+			true)
+		return
+	}
+
+	if session.interactiveTx.Complete(lntypes.Remote) {
+		f.completeDualFundTx(resCtx)
+		return
+	}
+
+	f.sendNextDualFundTxUpdate(resCtx)
+}
+
+// completeDualFundTx validates the constructed funding transaction, signs our
+// inputs to it and sends our signature for the remote party's commitment.
+func (f *Manager) completeDualFundTx(resCtx *reservationWithCtx) {
+	session := resCtx.dualFund
+	interactiveTx := session.interactiveTx
+
+	_, _, fundingOutput, err := resCtx.reservation.DualFundTxContribution()
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	// The remote party must contribute at least the amount it announced
+	// to the funding output. As we pay for our own inputs and outputs, and
+	// the opener pays for the funding output, the remaining fees are paid
+	// by the remote party.
+	isFundingOutput := func(txOut *wire.TxOut) bool {
+		return bytes.Equal(txOut.PkScript, fundingOutput.PkScript)
+	}
+	remoteContribution := interactiveTx.Contribution(
+		lntypes.Remote, isFundingOutput,
+	)
+	if remoteContribution < session.remoteAmt {
+		err := fmt.Errorf("remote party contributes %v to the "+
+			"funding tx, expected at least %v", remoteContribution,
+			session.remoteAmt)
+		f.failDualFund(resCtx, err, true)
+
+		return
+	}
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for _, in := range interactiveTx.Inputs() {
+		prevOut := in.PrevOut
+		prevOuts[in.OutPoint] = &prevOut
+	}
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+
+	fundingTx := interactiveTx.Tx()
+
+	var sig input.Signature
+	if session.replacing {
+		sig, err = f.signFundingCandidate(
+			resCtx, fundingTx, prevOutFetcher,
+		)
+	} else {
+		sig, err = resCtx.reservation.CompleteDualFundTx(
+			fundingTx, prevOutFetcher,
+		)
+	}
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	commitSig, err := lnwire.NewSigFromSignature(sig)
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	log.Infof("Constructed dual-funded tx %v for pending_id(%x)",
+		fundingTx.TxHash(), session.tempChanID[:])
+
+	session.fundingTx = fundingTx
+	session.prevOutFetcher = prevOutFetcher
+	session.state = dualFundAwaitCommitSig
+
+	err = resCtx.peer.SendMessage(false, &lnwire.CommitSig{
+		ChanID:    session.chanID,
+		CommitSig: commitSig,
+	})
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+	}
+}
+
+// signFundingCandidate signs our inputs to a replacement of the funding
+// transaction, along with the remote party's commitment spending its funding
+// output.
+func (f *Manager) signFundingCandidate(resCtx *reservationWithCtx,
+	fundingTx *wire.MsgTx,
+	prevOutFetcher txscript.PrevOutputFetcher) (input.Signature, error) {
+
+	session := resCtx.dualFund
+	channel := session.channel
+
+	// Every replacement must spend an input of each funding transaction
+	// signed before, so that only one of them can confirm.
+	candidates, err := channel.FundingCandidates()
+	if err != nil {
+		return nil, err
+	}
+	prevTxs := []*wire.MsgTx{channel.FundingTxn}
+	for _, candidate := range candidates {
+		prevTxs = append(prevTxs, candidate.FundingTxn)
+	}
+	for _, prevTx := range prevTxs {
+		if !spendsSameInput(fundingTx, prevTx) {
+			return nil, fmt.Errorf("replacement doesn't spend any "+
+				"input of funding tx %v", prevTx.TxHash())
+		}
+	}
+
+	err = resCtx.reservation.SignDualFundInputs(fundingTx, prevOutFetcher)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign funding tx: %w", err)
+	}
+
+	candidate, sig, err := f.cfg.Wallet.NewFundingCandidate(
+		channel, fundingTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	session.candidate = candidate
+
+	return sig, nil
+}
+
+// handleDualFundCommitSig processes the remote party's signature for our
+// commitment. Once it's verified, the pending channel is written to disk, and
+// the party that contributed less to the inputs of the funding transaction
+// sends its signatures for it first.
+func (f *Manager) handleDualFundCommitSig(resCtx *reservationWithCtx,
+	msg *lnwire.CommitSig) {
+
+	session := resCtx.dualFund
+	if session.state != dualFundAwaitCommitSig {
+		f.failDualFund(resCtx, errors.New("unexpected commit_sig"),
+			true)
+		return
+	}
+
+	commitSig, err := msg.CommitSig.ToSignature()
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	if session.replacing {
+		err = f.cfg.Wallet.VerifyFundingCandidate(
+			session.channel, session.candidate, commitSig,
+		)
+	} else {
+		session.channel, err = resCtx.reservation.
+			CompleteDualFundReservation(commitSig)
+	}
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	session.state = dualFundAwaitTxSigs
+
+	if !f.sendsTxSigsFirst(session, resCtx.peer.IdentityKey()) {
+		return
+	}
+
+	if err := f.sendDualFundTxSigs(resCtx); err != nil {
+		f.failDualFund(resCtx, err, true)
+	}
+}
+
+// sendsTxSigsFirst returns true if we must send our signatures for the
+// funding transaction before the remote party does. This is the case if the
+// total value of our inputs is lower, with ties broken by the node keys.
+func (f *Manager) sendsTxSigsFirst(session *dualFundSession,
+	peerKey *btcec.PublicKey) bool {
+
+	var localInputs, remoteInputs btcutil.Amount
+	for _, in := range session.interactiveTx.Inputs() {
+		amt := btcutil.Amount(in.PrevOut.Value)
+		if session.interactiveTx.SerialIDParty(in.SerialID) ==
+			lntypes.Local {
+
+			localInputs += amt
+		} else {
+			remoteInputs += amt
+		}
+	}
+
+	if localInputs != remoteInputs {
+		return localInputs < remoteInputs
+	}
+
+	localKey := f.cfg.IDKey.SerializeCompressed()
+
+	return bytes.Compare(localKey, peerKey.SerializeCompressed()) < 0
+}
+
+// dualFundInputIndexes returns the indexes of the inputs of the funding
+// transaction that were added by the given party, in serial ID order.
+func dualFundInputIndexes(session *dualFundSession,
+	party lntypes.ChannelParty) []int {
+
+	var indexes []int
+	for i, in := range session.interactiveTx.Inputs() {
+		if session.interactiveTx.SerialIDParty(in.SerialID) == party {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// sendDualFundTxSigs sends the witnesses of our inputs to the funding
+// transaction to the remote party.
+func (f *Manager) sendDualFundTxSigs(resCtx *reservationWithCtx) error {
+	session := resCtx.dualFund
+
+	txSigs := &lnwire.TxSignatures{
+		ChanID: session.chanID,
+		Txid:   session.fundingTx.TxHash(),
+	}
+	for _, i := range dualFundInputIndexes(session, lntypes.Local) {
+		txSigs.Witnesses = append(
+			txSigs.Witnesses, session.fundingTx.TxIn[i].Witness,
+		)
+	}
+
+	// Once the remote party has our signatures, it's able to publish the
+	// replacement, so we must watch it from then on.
+	if session.replacing {
+		err := f.storeFundingCandidate(session)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := resCtx.peer.SendMessage(true, txSigs); err != nil {
+		return err
+	}
+	session.sentTxSigs = true
+
+	return nil
+}
+
+// handleDualFundTxSigs processes the remote party's signatures for the
+// funding transaction. Once verified, we send ours if we haven't yet, and
+// publish the fully signed funding transaction.
+func (f *Manager) handleDualFundTxSigs(resCtx *reservationWithCtx,
+	msg *lnwire.TxSignatures) {
+
+	session := resCtx.dualFund
+	if session.state != dualFundAwaitTxSigs {
+		f.failDualFund(resCtx, errors.New("unexpected tx_signatures"),
+			true)
+		return
+	}
+
+	fundingTx := session.fundingTx
+	if msg.Txid != fundingTx.TxHash() {
+		err := fmt.Errorf("tx_signatures for unknown tx %v", msg.Txid)
+		f.failDualFund(resCtx, err, true)
+
+		return
+	}
+
+	remoteInputs := dualFundInputIndexes(session, lntypes.Remote)
+	if len(msg.Witnesses) != len(remoteInputs) {
+		err := fmt.Errorf("expected %d witnesses, got %d",
+			len(remoteInputs), len(msg.Witnesses))
+		f.failDualFund(resCtx, err, true)
+
+		return
+	}
+	for j, i := range remoteInputs {
+		fundingTx.TxIn[i].Witness = msg.Witnesses[j]
+	}
+
+	err := lnwallet.VerifyFundingTxInputs(fundingTx, session.prevOutFetcher)
+	if err != nil {
+		f.failDualFund(resCtx, err, true)
+		return
+	}
+
+	if !session.sentTxSigs {
+		if err := f.sendDualFundTxSigs(resCtx); err != nil {
+			f.failDualFund(resCtx, err, true)
+			return
+		}
+	}
+
+	// Now that the funding transaction is fully signed, we'll replace the
+	// partially signed version we stored along with the channel.
+	if session.replacing {
+		err = f.storeFundingCandidate(session)
+	} else {
+		err = session.channel.UpdateFundingTxn(fundingTx)
+	}
+	if err != nil {
+		log.Errorf("Unable to store funding tx %v: %v",
+			fundingTx.TxHash(), err)
+	}
+
+	log.Infof("Broadcasting dual-funded funding tx %v for "+
+		"ChannelPoint(%v)", fundingTx.TxHash(),
+		session.channel.FundingOutpoint)
+
+	// Both parties broadcast the funding transaction. Even if we fail to
+	// do so, we'll watch the channel regardless, as the remote party may
+	// have succeeded.
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	if err := f.cfg.PublishTransaction(fundingTx, label); err != nil {
+		log.Errorf("Unable to broadcast funding tx %v for "+
+			"ChannelPoint(%v): %v", fundingTx.TxHash(),
+			session.channel.FundingOutpoint, err)
+	}
+
+	// A replacement is watched along with the funding transactions signed
+	// before, and may itself be replaced.
+	if session.replacing {
+		session.state = dualFundSigned
+		session.feeRate = session.rbfFeeRate
+
+		return
+	}
+
+	f.finishDualFund(resCtx)
+}
+
+// storeFundingCandidate stores the replacement of the funding transaction
+// that is being negotiated, and signals that it must be watched.
+func (f *Manager) storeFundingCandidate(session *dualFundSession) error {
+	err := session.channel.AddFundingCandidate(session.candidate)
+	if err != nil {
+		return err
+	}
+
+	f.resMtx.RLock()
+	pending, ok := f.dualFundPending[session.chanID]
+	f.resMtx.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case pending.newCandidate <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// finishDualFund hands the pending dual-funded channel over to the regular
+// funding workflow, which waits for the funding transaction to confirm.
+func (f *Manager) finishDualFund(resCtx *reservationWithCtx) {
+	session := resCtx.dualFund
+	channel := session.channel
+	peer := resCtx.peer
+	peerKey := peer.IdentityKey()
+	fundingPoint := channel.FundingOutpoint
+	chanID := lnwire.NewChanIDFromOutPoint(fundingPoint)
+
+	// The channel is marked IsPending in the database, and can be removed
+	// from the set of active reservations. It's tracked until its funding
+	// transaction confirms, as it may be replaced until then.
+	f.deleteReservationCtx(peerKey, session.tempChanID)
+
+	session.replacing = true
+	session.state = dualFundSigned
+
+	f.resMtx.Lock()
+	f.dualFundPending[session.chanID] = &pendingDualFund{
+		peer:         newSerializedKey(peerKey),
+		channel:      channel,
+		resCtx:       resCtx,
+		newCandidate: make(chan struct{}, 1),
+	}
+	f.resMtx.Unlock()
+
+	if err := peer.AddPendingChannel(chanID, f.quit); err != nil {
+		log.Errorf("Unable to add pending channel %v with peer %x: %v",
+			chanID, peerKey.SerializeCompressed(), err)
+	}
+
+	// Create an entry in the local discovery map so we can ensure that we
+	// process the channel confirmation fully before we receive a
+	// channel_ready message.
+	f.localDiscoverySignals.Store(chanID, make(chan struct{}))
+
+	err := f.saveInitialForwardingPolicy(chanID, &resCtx.forwardingPolicy)
+	if err != nil {
+		log.Errorf("Unable to store the forwarding policy: %v", err)
+	}
+
+	if err := f.cfg.WatchNewChannel(channel, peerKey); err != nil {
+		log.Errorf("Unable to send new ChannelPoint(%v) for "+
+			"arbitration: %v", fundingPoint, err)
+	}
+
+	log.Infof("Finalizing dual-funded pending_id(%x) over "+
+		"ChannelPoint(%v), waiting for channel open on-chain",
+		session.tempChanID[:], fundingPoint)
+
+	// If we opened the channel, or bought a lease for it, we'll send an
+	// update to the upstream client that the negotiation process is over.
+	updates := resCtx.updates
+	if updates != nil {
+		upd := &lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_ChanPending{
+				ChanPending: &lnrpc.PendingUpdate{
+					Txid:        fundingPoint.Hash[:],
+					OutputIndex: fundingPoint.Index,
+				},
+			},
+			PendingChanId: session.tempChanID[:],
+		}
+
+		select {
+		case updates <- upd:
+		case <-f.quit:
+			return
+		}
+	}
+
+	// Inform the ChannelNotifier that the channel has entered pending open
+	// state.
+	f.cfg.NotifyPendingOpenChannelEvent(fundingPoint, channel)
+
+	f.wg.Add(1)
+	go f.advanceFundingState(channel, session.tempChanID, updates)
+}
+
+// failDualFund fails the dual-funded channel open tracked by the passed
+// reservation context. If notifyPeer is true, the remote party is informed
+// about the failure. Once we sent our signatures for the funding transaction,
+// the remote party is able to publish it, so we'll keep the channel and wait
+// for the funding transaction to confirm instead. Once it's fully signed,
+// only the replacement being negotiated fails.
+func (f *Manager) failDualFund(resCtx *reservationWithCtx, fundingErr error,
+	notifyPeer bool) {
+
+	session := resCtx.dualFund
+	peer := resCtx.peer
+	peerKey := peer.IdentityKey()
+
+	switch {
+	// The funding transactions signed so far are still watched, and the
+	// remote party is informed with tx_abort, which leaves the channel
+	// untouched.
+	case session.replacing:
+		log.Warnf("Replacement of funding tx of dual-funded channel "+
+			"%v failed: %v", session.chanID, fundingErr)
+
+		session.state = dualFundSigned
+		session.pendingUpdates = nil
+		session.candidate = nil
+
+		if notifyPeer {
+			f.sendDualFundTxAbort(peer, session.chanID, fundingErr)
+		}
+
+	// As long as the pending channel isn't written to disk, the
+	// reservation can be canceled as usual.
+	case session.channel == nil && notifyPeer:
+		cid := newChanIdentifier(session.tempChanID)
+		f.failFundingFlow(peer, cid, fundingErr)
+
+	case session.channel == nil:
+		ctx, err := f.cancelReservationCtx(
+			peerKey, session.tempChanID, true,
+		)
+		if err != nil {
+			log.Errorf("Unable to cancel reservation: %v", err)
+		}
+		if ctx != nil {
+			ctx.err <- fundingErr
+		}
+
+	case session.sentTxSigs:
+		log.Warnf("Dual-funded channel open of ChannelPoint(%v) "+
+			"failed after sending our tx_signatures, waiting for "+
+			"funding tx to confirm: %v",
+			session.channel.FundingOutpoint, fundingErr)
+
+		f.finishDualFund(resCtx)
+
+	// The remote party can't publish the funding transaction without our
+	// signatures, so we can safely forget the channel.
+	default:
+		f.deleteReservationCtx(peerKey, session.tempChanID)
+		f.deletePendingChannel(session.channel)
+
+		resCtx.err <- fundingErr
+
+		if notifyPeer {
+			f.sendFundingError(peer, session.tempChanID, fundingErr)
+		}
+	}
+}
+
+// deletePendingChannel deletes a pending channel whose funding transaction
+// will never be published from the database.
+func (f *Manager) deletePendingChannel(channel *channeldb.OpenChannel) {
+	localBalance := channel.LocalCommitment.LocalBalance.ToSatoshis()
+	closeInfo := &channeldb.ChannelCloseSummary{
+		ChanPoint:               channel.FundingOutpoint,
+		ChainHash:               channel.ChainHash,
+		RemotePub:               channel.IdentityPub,
+		CloseType:               channeldb.FundingCanceled,
+		Capacity:                channel.Capacity,
+		SettledBalance:          localBalance,
+		RemoteCurrentRevocation: channel.RemoteCurrentRevocation,
+		RemoteNextRevocation:    channel.RemoteNextRevocation,
+		LocalChanConfig:         channel.LocalChanCfg,
+	}
+
+	err := channel.CloseChannel(
+		closeInfo, channeldb.ChanStatusLocalCloseInitiator,
+	)
+	if err != nil {
+		log.Errorf("Failed closing channel %v: %v",
+			channel.FundingOutpoint, err)
+	}
+}
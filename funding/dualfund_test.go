@@ -0,0 +1,823 @@
+package funding
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// dualFundAddInput returns a tx_add_input spending an output of the given
+// value and script.
+func dualFundAddInput(t *testing.T, serialID uint64, value int64,
+	pkScript []byte) *lnwire.TxAddInput {
+
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: uint32(serialID)},
+	})
+	prevTx.AddTxOut(&wire.TxOut{Value: value, PkScript: pkScript})
+
+	var b bytes.Buffer
+	require.NoError(t, prevTx.Serialize(&b))
+
+	return &lnwire.TxAddInput{
+		SerialID: serialID,
+		PrevTx:   b.Bytes(),
+		Sequence: wire.MaxTxInSequenceNum - 2,
+	}
+}
+
+// p2wpkhScript returns a P2WPKH script paying to a dummy key hash.
+func p2wpkhScript(t *testing.T) []byte {
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(bytes.Repeat([]byte{1}, 20)).Script()
+	require.NoError(t, err)
+
+	return script
+}
+
+// TestDualFundChanReserve tests that the reserve of dual-funded channels is 1%
+// of the capacity, but never below either dust limit.
+func TestDualFundChanReserve(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t, btcutil.Amount(10_000),
+		dualFundChanReserve(1_000_000, 354, 546),
+	)
+	require.Equal(
+		t, btcutil.Amount(546), dualFundChanReserve(20_000, 354, 546),
+	)
+	require.Equal(
+		t, btcutil.Amount(660), dualFundChanReserve(20_000, 660, 546),
+	)
+}
+
+// TestCanDualFund tests that dual funding is only used if both parties
+// support it and the request doesn't rely on single funder features.
+func TestCanDualFund(t *testing.T) {
+	t.Parallel()
+
+	f := &Manager{cfg: &Config{}}
+	peer := &testNode{
+		localFeatures:  []lnwire.FeatureBit{lnwire.DualFundOptional},
+		remoteFeatures: []lnwire.FeatureBit{lnwire.DualFundOptional},
+	}
+	commitType := lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx
+
+	msg := &InitFundingMsg{Peer: peer}
+	require.True(t, f.canDualFund(msg, commitType, false))
+
+	// Zero-conf, taproot and lease channels aren't supported.
+	require.False(t, f.canDualFund(msg, commitType, true))
+	require.False(t, f.canDualFund(
+		msg, lnwallet.CommitmentTypeSimpleTaproot, false,
+	))
+	require.False(t, f.canDualFund(
+		msg, lnwallet.CommitmentTypeScriptEnforcedLease, false,
+	))
+
+	// Unless it's a lease we buy.
+	leaseMsg := &InitFundingMsg{Peer: peer, lease: &leaseTerms{}}
+	require.True(t, f.canDualFund(
+		leaseMsg, lnwallet.CommitmentTypeScriptEnforcedLease, false,
+	))
+
+	// Neither are pushed amounts or custom coin selections.
+	msg = &InitFundingMsg{Peer: peer, PushAmt: 1000}
+	require.False(t, f.canDualFund(msg, commitType, false))
+
+	msg = &InitFundingMsg{
+		Peer:      peer,
+		Outpoints: []wire.OutPoint{{Index: 1}},
+	}
+	require.False(t, f.canDualFund(msg, commitType, false))
+
+	// Both parties must signal support.
+	msg = &InitFundingMsg{Peer: &testNode{
+		localFeatures: []lnwire.FeatureBit{lnwire.DualFundOptional},
+	}}
+	require.False(t, f.canDualFund(msg, commitType, false))
+}
+
+// TestApplyDualFundTxUpdate tests that inputs and outputs added to the funding
+// transaction are validated.
+func TestApplyDualFundTxUpdate(t *testing.T) {
+	t.Parallel()
+
+	session := &dualFundSession{
+		interactiveTx: chanfunding.NewInteractiveTx(lntypes.Local, 0),
+	}
+	pkScript := p2wpkhScript(t)
+
+	// The remote party may only add segwit inputs.
+	nonSegwit := dualFundAddInput(t, 1, 5000, []byte{txscript.OP_TRUE})
+	err := applyDualFundTxUpdate(session, lntypes.Remote, nonSegwit)
+	require.ErrorContains(t, err, "not a segwit input")
+
+	// Shared inputs don't exist for new channels.
+	shared := dualFundAddInput(t, 1, 5000, pkScript)
+	shared.SharedInputTxid = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType0, [32]byte]([32]byte{1}),
+	)
+	err = applyDualFundTxUpdate(session, lntypes.Remote, shared)
+	require.ErrorContains(t, err, "unexpected shared input")
+
+	// The referenced output must exist.
+	invalidIndex := dualFundAddInput(t, 1, 5000, pkScript)
+	invalidIndex.PrevTxOut = 1
+	err = applyDualFundTxUpdate(session, lntypes.Remote, invalidIndex)
+	require.ErrorContains(t, err, "invalid prev tx output index")
+
+	// Serial IDs must match the role of the party adding the input.
+	err = applyDualFundTxUpdate(
+		session, lntypes.Remote, dualFundAddInput(t, 2, 5000, pkScript),
+	)
+	require.ErrorIs(t, err, chanfunding.ErrInvalidSerialID)
+
+	require.NoError(t, applyDualFundTxUpdate(
+		session, lntypes.Local, dualFundAddInput(t, 0, 4000, pkScript),
+	))
+	require.NoError(t, applyDualFundTxUpdate(
+		session, lntypes.Remote, dualFundAddInput(t, 1, 5000, pkScript),
+	))
+	require.NoError(t, applyDualFundTxUpdate(
+		session, lntypes.Local, &lnwire.TxAddOutput{
+			SerialID: 2,
+			Amount:   8000,
+			PkScript: pkScript,
+		},
+	))
+
+	inputs := session.interactiveTx.Inputs()
+	require.Len(t, inputs, 2)
+	require.EqualValues(t, 5000, inputs[1].PrevOut.Value)
+	require.Equal(
+		t, []int{1}, dualFundInputIndexes(session, lntypes.Remote),
+	)
+	require.Equal(
+		t, []int{0}, dualFundInputIndexes(session, lntypes.Local),
+	)
+
+	// As our inputs are worth less, we're the first to send our
+	// signatures for the funding transaction.
+	localKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	remoteKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	f := &Manager{cfg: &Config{IDKey: localKey.PubKey()}}
+	require.True(t, f.sendsTxSigsFirst(session, remoteKey.PubKey()))
+
+	// Once our inputs are worth more, the remote party goes first.
+	require.NoError(t, applyDualFundTxUpdate(
+		session, lntypes.Local, &lnwire.TxRemoveInput{SerialID: 0},
+	))
+	require.NoError(t, applyDualFundTxUpdate(
+		session, lntypes.Local, dualFundAddInput(t, 4, 6000, pkScript),
+	))
+	require.False(t, f.sendsTxSigsFirst(session, remoteKey.PubKey()))
+}
+
+// dualFundMsg is a message sent by one of the nodes of a dual-funded channel
+// open.
+type dualFundMsg struct {
+	from *testNode
+	msg  lnwire.Message
+}
+
+// fundDualFundWallet funds the mock wallet of the given node with a single
+// confirmed P2WPKH output that can be spent by the mock signer.
+func fundDualFundWallet(t *testing.T, node *testNode, id byte,
+	value btcutil.Amount) *wire.MsgTx {
+
+	pkScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(btcutil.Hash160(
+			alicePrivKey.PubKey().SerializeCompressed(),
+		)).Script()
+	require.NoError(t, err)
+
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{id}},
+	})
+	prevTx.AddTxOut(&wire.TxOut{Value: int64(value), PkScript: pkScript})
+
+	wallet := node.fundingMgr.cfg.Wallet
+	wc, ok := wallet.WalletController.(*mock.WalletController)
+	require.True(t, ok)
+
+	wc.Utxos = []*lnwallet.Utxo{{
+		AddressType:   lnwallet.WitnessPubKey,
+		Value:         value,
+		PkScript:      pkScript,
+		Confirmations: 6,
+		OutPoint:      wire.OutPoint{Hash: prevTx.TxHash()},
+	}}
+	wc.Txs = map[chainhash.Hash]*wire.MsgTx{prevTx.TxHash(): prevTx}
+
+	return prevTx
+}
+
+// setupDualFundManagers creates two funding managers that support dual
+// funding, whose wallets hold a single output of the given values. Bob
+// contributes the given amount to the channels Alice opens.
+func setupDualFundManagers(t *testing.T, aliceUtxo, bobUtxo,
+	bobAmt btcutil.Amount) (*testNode, *testNode) {
+
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.DualFundContribution = func(
+			*chanacceptor.ChannelAcceptRequest) btcutil.Amount {
+
+			return bobAmt
+		}
+	})
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	for _, node := range []*testNode{alice, bob} {
+		node.localFeatures = []lnwire.FeatureBit{
+			lnwire.DualFundOptional,
+		}
+		node.remoteFeatures = node.localFeatures
+	}
+
+	fundDualFundWallet(t, alice, 1, aliceUtxo)
+	fundDualFundWallet(t, bob, 2, bobUtxo)
+
+	return alice, bob
+}
+
+// initDualFund has Alice open a dual-funded channel with Bob.
+func initDualFund(alice, bob *testNode,
+	amt btcutil.Amount) *InitFundingMsg {
+
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		MinConfs:        1,
+		LocalFundingAmt: amt,
+		FundingFeePerKw: 1000,
+		Updates:         make(chan *lnrpc.OpenStatusUpdate, 1),
+		Err:             make(chan error, 1),
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	return initReq
+}
+
+// relayDualFundMsgs relays the messages Alice and Bob send each other until
+// stop returns true for a message. All messages are returned, with the last
+// one being the message that stopped the relay, which isn't delivered.
+func relayDualFundMsgs(t *testing.T, alice, bob *testNode,
+	stop func(dualFundMsg) bool) []dualFundMsg {
+
+	t.Helper()
+
+	var msgs []dualFundMsg
+	for {
+		var sent dualFundMsg
+		select {
+		case msg := <-alice.msgChan:
+			sent = dualFundMsg{from: alice, msg: msg}
+
+		case msg := <-bob.msgChan:
+			sent = dualFundMsg{from: bob, msg: msg}
+
+		case <-time.After(time.Second * 5):
+			t.Fatalf("no message sent after %d messages", len(msgs))
+		}
+
+		msgs = append(msgs, sent)
+		if stop(sent) {
+			return msgs
+		}
+
+		if _, ok := sent.msg.(*lnwire.Error); ok {
+			t.Fatalf("unexpected error: %v", sent.msg)
+		}
+
+		sent.from.remotePeer.fundingMgr.ProcessFundingMsg(
+			sent.msg, sent.from,
+		)
+	}
+}
+
+// stopAtMsg returns a stop function for relayDualFundMsgs that stops at the
+// n-th message of the given type.
+func stopAtMsg(msgType lnwire.MessageType, n int) func(dualFundMsg) bool {
+	var seen int
+	return func(sent dualFundMsg) bool {
+		if sent.msg.MsgType() == msgType {
+			seen++
+		}
+
+		return seen == n
+	}
+}
+
+// receivePublishedTx asserts that the node published a transaction.
+func receivePublishedTx(t *testing.T, node *testNode) *wire.MsgTx {
+	t.Helper()
+
+	select {
+	case tx := <-node.publTxChan:
+		return tx
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no transaction published")
+		return nil
+	}
+}
+
+// receiveFundingErr asserts that the funding flow failed.
+func receiveFundingErr(t *testing.T, errChan chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(time.Second * 5):
+		t.Fatalf("funding flow didn't fail")
+		return nil
+	}
+}
+
+// assertNumPendingChannels asserts the number of pending channels of the
+// node.
+func assertNumPendingChannels(t *testing.T, node *testNode, n int) {
+	t.Helper()
+
+	channels, err := node.fundingMgr.cfg.ChannelDB.FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, channels, n)
+}
+
+// assertNumPendingReservationsBecomes asserts that the number of pending
+// reservations of the node with the given peer eventually becomes the expected
+// number.
+func assertNumPendingReservationsBecomes(t *testing.T, node *testNode,
+	peerPubKey *btcec.PublicKey, expectedNum int) {
+
+	t.Helper()
+
+	serializedPubKey := newSerializedKey(peerPubKey)
+	require.Eventually(t, func() bool {
+		node.fundingMgr.resMtx.RLock()
+		defer node.fundingMgr.resMtx.RUnlock()
+
+		reservations := node.fundingMgr.activeReservations
+		return len(reservations[serializedPubKey]) == expectedNum
+	}, time.Second*5, testPollSleepMs*time.Millisecond)
+}
+
+// TestFundingManagerDualFund tests a dual-funded channel open in which both
+// parties contribute inputs to the funding transaction.
+func TestFundingManagerDualFund(t *testing.T) {
+	t.Parallel()
+
+	const (
+		aliceAmt = btcutil.Amount(500_000)
+		bobAmt   = btcutil.Amount(300_000)
+	)
+	alice, bob := setupDualFundManagers(t, 1_000_000, 2_000_000, bobAmt)
+
+	initReq := initDualFund(alice, bob, aliceAmt)
+
+	// We relay all messages until the second tx_signatures.
+	msgs := relayDualFundMsgs(t, alice, bob, stopAtMsg(
+		lnwire.MsgTxSignatures, 2,
+	))
+
+	// The channel parameters are exchanged first.
+	require.IsType(t, &lnwire.OpenChannel2{}, msgs[0].msg)
+	require.Equal(t, alice, msgs[0].from)
+	require.IsType(t, &lnwire.AcceptChannel2{}, msgs[1].msg)
+	require.Equal(t, bob, msgs[1].from)
+
+	accept, _ := msgs[1].msg.(*lnwire.AcceptChannel2)
+	require.Equal(t, bobAmt, accept.FundingAmount)
+
+	// Then, the parties take turns adding inputs and outputs, starting
+	// with Alice, until both sent tx_complete. Alice uses even serial IDs
+	// and Bob odd ones.
+	i := 2
+	for ; i < len(msgs); i++ {
+		if msgs[i].msg.MsgType() == lnwire.MsgCommitSig {
+			break
+		}
+
+		expected := alice
+		if i%2 == 1 {
+			expected = bob
+		}
+		require.Equal(t, expected, msgs[i].from, "message %d", i)
+
+		var serialID uint64
+		switch msg := msgs[i].msg.(type) {
+		case *lnwire.TxAddInput:
+			serialID = msg.SerialID
+		case *lnwire.TxAddOutput:
+			serialID = msg.SerialID
+		case *lnwire.TxComplete:
+			continue
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+		require.Equal(t, uint64(i%2), serialID%2)
+	}
+	require.IsType(t, &lnwire.TxComplete{}, msgs[i-1].msg)
+	require.IsType(t, &lnwire.TxComplete{}, msgs[i-2].msg)
+
+	// Both parties then exchange their commitment signatures, before
+	// Alice, whose inputs are worth less, sends her tx_signatures first.
+	require.IsType(t, &lnwire.CommitSig{}, msgs[i].msg)
+	require.IsType(t, &lnwire.CommitSig{}, msgs[i+1].msg)
+	require.IsType(t, &lnwire.TxSignatures{}, msgs[i+2].msg)
+	require.Equal(t, alice, msgs[i+2].from)
+	require.Len(t, msgs, i+4)
+
+	last := msgs[len(msgs)-1]
+	require.Equal(t, bob, last.from)
+	bobSigs, ok := last.msg.(*lnwire.TxSignatures)
+	require.True(t, ok)
+	alice.fundingMgr.ProcessFundingMsg(last.msg, bob)
+
+	// Both parties publish the same funding transaction, which is fully
+	// signed.
+	fundingTx := receivePublishedTx(t, alice)
+	require.Equal(t, fundingTx.TxHash(), receivePublishedTx(t, bob).TxHash())
+	require.Len(t, fundingTx.TxIn, 2)
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for _, node := range []*testNode{alice, bob} {
+		wallet := node.fundingMgr.cfg.Wallet
+		wc, _ := wallet.WalletController.(*mock.WalletController)
+		for _, prevTx := range wc.Txs {
+			prevOuts.AddPrevOut(
+				wire.OutPoint{Hash: prevTx.TxHash()},
+				prevTx.TxOut[0],
+			)
+		}
+	}
+	require.NoError(t, lnwallet.VerifyFundingTxInputs(fundingTx, prevOuts))
+
+	select {
+	case update := <-initReq.Updates:
+		pending := update.GetChanPending()
+		require.NotNil(t, pending)
+
+		txid := fundingTx.TxHash()
+		require.Equal(t, txid[:], pending.Txid)
+
+	case err := <-initReq.Err:
+		t.Fatalf("funding flow failed: %v", err)
+	}
+
+	// The capacity is made up of both contributions, and each party starts
+	// out with its own contribution, minus the commitment fee paid by
+	// Alice.
+	aliceChans, err := alice.fundingMgr.cfg.ChannelDB.FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, aliceChans, 1)
+	aliceChan := aliceChans[0]
+	require.Equal(t, aliceAmt+bobAmt, aliceChan.Capacity)
+	require.Equal(
+		t, aliceAmt+bobAmt,
+		btcutil.Amount(fundingTx.TxOut[aliceChan.FundingOutpoint.Index].
+			Value),
+	)
+
+	commitFee := aliceChan.LocalCommitment.CommitFee
+	require.Equal(
+		t, lnwire.NewMSatFromSatoshis(aliceAmt-commitFee),
+		aliceChan.LocalCommitment.LocalBalance,
+	)
+	require.Equal(
+		t, lnwire.NewMSatFromSatoshis(bobAmt),
+		aliceChan.LocalCommitment.RemoteBalance,
+	)
+	assertNumPendingChannels(t, bob, 1)
+	assertNumPendingReservations(t, alice, bobPubKey, 0)
+	assertNumPendingReservations(t, bob, alicePubKey, 0)
+
+	// Only the opener can replace the funding transaction, so Alice
+	// refuses Bob's attempt to do so, while the channel stays pending.
+	alice.fundingMgr.ProcessFundingMsg(&lnwire.TxInitRbf{
+		ChanID:  bobSigs.ChanID,
+		FeeRate: 2000,
+	}, bob)
+
+	var msg lnwire.Message
+	select {
+	case msg = <-alice.msgChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("tx_init_rbf not aborted")
+	}
+	txAbort, ok := msg.(*lnwire.TxAbort)
+	require.True(t, ok)
+	require.Equal(t, bobSigs.ChanID, txAbort.ChanID)
+	assertNumPendingChannels(t, alice, 1)
+
+	// A replacement must pay a higher fee rate.
+	fundingPoint := aliceChan.FundingOutpoint
+	err = alice.fundingMgr.BumpDualFundFee(fundingPoint, 1000)
+	require.ErrorContains(t, err, "too low")
+
+	// Alice then replaces the funding transaction with one paying twice
+	// the fee rate. Both parties keep their contribution, and pay for the
+	// higher fee from their change.
+	bumpErr := make(chan error, 1)
+	go func() {
+		bumpErr <- alice.fundingMgr.BumpDualFundFee(fundingPoint, 2000)
+	}()
+
+	msgs = relayDualFundMsgs(t, alice, bob, stopAtMsg(
+		lnwire.MsgTxSignatures, 2,
+	))
+	require.NoError(t, <-bumpErr)
+	require.IsType(t, &lnwire.TxInitRbf{}, msgs[0].msg)
+	require.Equal(t, alice, msgs[0].from)
+	require.IsType(t, &lnwire.TxAckRbf{}, msgs[1].msg)
+	require.Equal(t, bob, msgs[1].from)
+
+	last = msgs[len(msgs)-1]
+	require.Equal(t, bob, last.from)
+	alice.fundingMgr.ProcessFundingMsg(last.msg, bob)
+
+	replacement := receivePublishedTx(t, alice)
+	require.Equal(
+		t, replacement.TxHash(), receivePublishedTx(t, bob).TxHash(),
+	)
+	require.NoError(
+		t, lnwallet.VerifyFundingTxInputs(replacement, prevOuts),
+	)
+
+	txFee := func(tx *wire.MsgTx) btcutil.Amount {
+		var fee int64
+		for _, txIn := range tx.TxIn {
+			fee += prevOuts.FetchPrevOutput(txIn.PreviousOutPoint).
+				Value
+		}
+		for _, txOut := range tx.TxOut {
+			fee -= txOut.Value
+		}
+
+		return btcutil.Amount(fee)
+	}
+	require.Greater(t, txFee(replacement), txFee(fundingTx))
+
+	// Both parties store the replacement along with the pending channel.
+	for _, node := range []*testNode{alice, bob} {
+		channels, err := node.fundingMgr.cfg.ChannelDB.
+			FetchPendingChannels()
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+
+		candidates, err := channels[0].FundingCandidates()
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		require.Equal(t, replacement.TxHash(), candidates[0].Txid())
+	}
+
+	// Once the replacement confirms, the channel is moved to its funding
+	// outpoint, and channel_ready is sent for it.
+	alice.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: replacement,
+	}
+	bob.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: replacement,
+	}
+
+	_, index := input.FindScriptOutputIndex(
+		replacement, fundingTx.TxOut[fundingPoint.Index].PkScript,
+	)
+	newPoint := wire.OutPoint{Hash: replacement.TxHash(), Index: index}
+	assertMarkedOpen(t, alice, bob, &newPoint)
+
+	for _, node := range []*testNode{alice, bob} {
+		channelReady, ok := assertFundingMsgSent(
+			t, node.msgChan, "ChannelReady",
+		).(*lnwire.ChannelReady)
+		require.True(t, ok)
+		require.Equal(
+			t, lnwire.NewChanIDFromOutPoint(newPoint),
+			channelReady.ChanID,
+		)
+
+		channel, err := node.fundingMgr.cfg.ChannelDB.FetchChannel(
+			nil, newPoint,
+		)
+		require.NoError(t, err)
+
+		// Like for single funder channels, only the opener stores the
+		// funding transaction.
+		if channel.IsInitiator {
+			require.Equal(
+				t, replacement.TxHash(),
+				channel.FundingTxn.TxHash(),
+			)
+		}
+
+		candidates, err := channel.FundingCandidates()
+		require.NoError(t, err)
+		require.Empty(t, candidates)
+	}
+}
+
+// TestFundingManagerDualFundContribution tests that the contribution of the
+// acceptor is capped, that the channel is accepted without contribution if
+// the acceptor can't fund it, and that the opener rejects a funding
+// transaction in which the acceptor contributes less than it announced.
+func TestFundingManagerDualFundContribution(t *testing.T) {
+	t.Parallel()
+
+	const aliceAmt = btcutil.Amount(500_000)
+
+	// Bob can't contribute more than allowed by his maximum channel size.
+	t.Run("capped contribution", func(t *testing.T) {
+		alice, bob := setupDualFundManagers(
+			t, 1_000_000, 20_000_000, MaxBtcFundingAmount,
+		)
+		initDualFund(alice, bob, aliceAmt)
+
+		msgs := relayDualFundMsgs(t, alice, bob, stopAtMsg(
+			lnwire.MsgAcceptChannel2, 1,
+		))
+		accept, ok := msgs[1].msg.(*lnwire.AcceptChannel2)
+		require.True(t, ok)
+		require.Equal(
+			t, MaxBtcFundingAmount-aliceAmt, accept.FundingAmount,
+		)
+	})
+
+	// If Bob's wallet can't fund his contribution, he accepts the channel
+	// without contributing to it.
+	t.Run("unfunded contribution", func(t *testing.T) {
+		alice, bob := setupDualFundManagers(
+			t, 1_000_000, 10_000, 300_000,
+		)
+		initDualFund(alice, bob, aliceAmt)
+
+		msgs := relayDualFundMsgs(t, alice, bob, stopAtMsg(
+			lnwire.MsgTxSignatures, 1,
+		))
+		accept, ok := msgs[1].msg.(*lnwire.AcceptChannel2)
+		require.True(t, ok)
+		require.Zero(t, accept.FundingAmount)
+
+		// Bob doesn't add anything to the funding transaction, so
+		// he's the first to send his (empty) tx_signatures.
+		for _, sent := range msgs[2:] {
+			if sent.from != bob {
+				continue
+			}
+
+			switch msg := sent.msg.(type) {
+			case *lnwire.TxComplete, *lnwire.CommitSig:
+			case *lnwire.TxSignatures:
+				require.Empty(t, msg.Witnesses)
+			default:
+				t.Fatalf("unexpected message %T from bob", msg)
+			}
+		}
+	})
+
+	// Bob announces a contribution, but adds an input that is worth less.
+	t.Run("insufficient contribution", func(t *testing.T) {
+		alice, bob := setupDualFundManagers(
+			t, 1_000_000, 2_000_000, 300_000,
+		)
+		initReq := initDualFund(alice, bob, aliceAmt)
+
+		msgs := relayDualFundMsgs(t, alice, bob,
+			func(sent dualFundMsg) bool {
+				_, ok := sent.msg.(*lnwire.TxAddInput)
+				return ok && sent.from == bob
+			},
+		)
+		bobInput, _ := msgs[len(msgs)-1].msg.(*lnwire.TxAddInput)
+
+		smallInput := dualFundAddInput(
+			t, bobInput.SerialID, 100_000, p2wpkhScript(t),
+		)
+		smallInput.ChanID = bobInput.ChanID
+		alice.fundingMgr.ProcessFundingMsg(smallInput, bob)
+
+		msgs = relayDualFundMsgs(t, alice, bob,
+			func(sent dualFundMsg) bool {
+				_, ok := sent.msg.(*lnwire.Error)
+				return ok
+			},
+		)
+		require.Equal(t, alice, msgs[len(msgs)-1].from)
+
+		err := receiveFundingErr(t, initReq.Err)
+		require.ErrorContains(t, err, "remote party contributes")
+		assertNumPendingReservations(t, alice, bobPubKey, 0)
+	})
+}
+
+// TestFundingManagerDualFundAbort tests that a dual-funded channel open is
+// aborted if the remote party sends tx_abort, and that the pending channel is
+// only kept if we already sent our signatures for the funding transaction.
+func TestFundingManagerDualFundAbort(t *testing.T) {
+	t.Parallel()
+
+	const (
+		aliceAmt = btcutil.Amount(500_000)
+		bobAmt   = btcutil.Amount(300_000)
+	)
+
+	// A negotiation is aborted while the funding transaction is
+	// constructed.
+	t.Run("during construction", func(t *testing.T) {
+		alice, bob := setupDualFundManagers(
+			t, 1_000_000, 2_000_000, bobAmt,
+		)
+		initReq := initDualFund(alice, bob, aliceAmt)
+
+		msgs := relayDualFundMsgs(t, alice, bob, stopAtMsg(
+			lnwire.MsgTxAddInput, 2,
+		))
+		bobInput, _ := msgs[len(msgs)-1].msg.(*lnwire.TxAddInput)
+
+		alice.fundingMgr.ProcessFundingMsg(&lnwire.TxAbort{
+			ChanID: bobInput.ChanID,
+			Data:   []byte("abort"),
+		}, bob)
+
+		err := receiveFundingErr(t, initReq.Err)
+		require.ErrorContains(t, err, "aborted by remote party")
+		assertNumPendingReservations(t, alice, bobPubKey, 0)
+
+		bob.fundingMgr.ProcessFundingMsg(&lnwire.TxAbort{
+			ChanID: bobInput.ChanID,
+		}, alice)
+		assertNumPendingReservationsBecomes(t, bob, alicePubKey, 0)
+
+		assertNumPendingChannels(t, alice, 0)
+		assertNumPendingChannels(t, bob, 0)
+	})
+
+	// A negotiation is aborted after Alice sent her signatures for the
+	// funding transaction, which Bob is now able to publish. Alice must
+	// keep the channel, while Bob can forget about it.
+	t.Run("after tx_signatures", func(t *testing.T) {
+		alice, bob := setupDualFundManagers(
+			t, 1_000_000, 2_000_000, bobAmt,
+		)
+		initReq := initDualFund(alice, bob, aliceAmt)
+
+		msgs := relayDualFundMsgs(t, alice, bob, stopAtMsg(
+			lnwire.MsgTxSignatures, 1,
+		))
+		txSigs, _ := msgs[len(msgs)-1].msg.(*lnwire.TxSignatures)
+		require.Equal(t, alice, msgs[len(msgs)-1].from)
+
+		assertNumPendingChannelsBecomes(t, alice, 1)
+		assertNumPendingChannelsBecomes(t, bob, 1)
+
+		alice.fundingMgr.ProcessFundingMsg(&lnwire.TxAbort{
+			ChanID: txSigs.ChanID,
+		}, bob)
+		bob.fundingMgr.ProcessFundingMsg(&lnwire.TxAbort{
+			ChanID: txSigs.ChanID,
+		}, alice)
+
+		// Alice hands the channel over to the funding workflow, which
+		// waits for the funding transaction to confirm.
+		select {
+		case update := <-initReq.Updates:
+			require.NotNil(t, update.GetChanPending())
+		case err := <-initReq.Err:
+			t.Fatalf("funding flow failed: %v", err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("channel not pending")
+		}
+		assertNumPendingChannels(t, alice, 1)
+		assertNumPendingReservations(t, alice, bobPubKey, 0)
+
+		// Bob never sent his signatures, so he forgets the channel.
+		assertNumPendingChannelsBecomes(t, bob, 0)
+		assertNumPendingReservationsBecomes(t, bob, alicePubKey, 0)
+	})
+}
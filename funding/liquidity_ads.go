@@ -0,0 +1,295 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// leaseHeightTolerance is the number of blocks the height a requested
+	// lease starts at may be off from our best height, which allows for
+	// both parties seeing slightly different chain tips.
+	leaseHeightTolerance = 6
+)
+
+var (
+	// errLeaseNotNegotiated is returned when a lease is requested from a
+	// peer we can't open a dual-funded channel with.
+	errLeaseNotNegotiated = errors.New("dual funding or script " +
+		"enforced leases not negotiated")
+
+	// errNoLeaseRates is returned when a peer requests a lease while we
+	// don't sell any.
+	errNoLeaseRates = leaseError{errors.New("not selling leases")}
+
+	// errLeaseNotDualFunded is returned when a leased channel would be
+	// opened without the dual-funded channel establishment protocol.
+	errLeaseNotDualFunded = errors.New("lease requires dual funding")
+
+	// errLeaseNotAccepted is returned when the acceptor of a channel we
+	// requested a lease for doesn't commit to the lease.
+	errLeaseNotAccepted = errors.New("lease request not accepted")
+
+	// errUnexpectedWillFund is returned when the acceptor of a channel
+	// commits to a lease we didn't request.
+	errUnexpectedWillFund = errors.New("unexpected will_fund")
+)
+
+// leaseError is an error rejecting a lease request, which is sent to the
+// peer that requested the lease.
+type leaseError struct {
+	error
+}
+
+// leaseTerms are the terms of a channel lease, which are agreed upon in
+// open_channel2 and accept_channel2.
+type leaseTerms struct {
+	// request is the lease requested by the opener.
+	request lnwire.RequestFunding
+
+	// rates are the rates the lease is sold at.
+	rates lnwire.LeaseRates
+
+	// fee is the lease fee the opener pays to the acceptor within the
+	// first commitment.
+	fee btcutil.Amount
+}
+
+// expiry returns the absolute height the lease expires at.
+func (l *leaseTerms) expiry() uint32 {
+	return l.request.LeaseExpiry()
+}
+
+// LeaseRequest is a request to buy inbound liquidity from a peer that
+// advertises lease rates in its node announcement.
+type LeaseRequest struct {
+	// Peer is the peer we lease the inbound liquidity from.
+	Peer lnpeer.Peer
+
+	// Amount is the amount the peer should contribute to the channel.
+	Amount btcutil.Amount
+
+	// Rates are the lease rates the peer advertises.
+	Rates lnwire.LeaseRates
+
+	// MaxFee is the largest lease fee we're willing to pay.
+	MaxFee btcutil.Amount
+
+	// FundingFeePerKw is the fee rate of the funding transaction.
+	FundingFeePerKw chainfee.SatPerKWeight
+
+	// Updates is a channel which updates to the opening status of the
+	// leased channel are sent on.
+	Updates chan *lnrpc.OpenStatusUpdate
+
+	// Err is a channel which errors encountered while purchasing the
+	// lease are sent on.
+	//
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	Err chan error
+}
+
+// leaseChannelType returns the channel type of leased channels.
+func leaseChannelType() *lnwire.ChannelType {
+	chanType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.ScriptEnforcedLeaseRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+		lnwire.StaticRemoteKeyRequired,
+	))
+
+	return &chanType
+}
+
+// checkLeaseHeight checks that the height a requested lease starts at is close
+// enough to our best height.
+func checkLeaseHeight(height uint32, bestHeight int32) error {
+	diff := int64(height) - int64(bestHeight)
+	if diff < -leaseHeightTolerance || diff > leaseHeightTolerance {
+		return leaseError{fmt.Errorf("lease start height %d too far "+
+			"from current height %d", height, bestHeight)}
+	}
+
+	return nil
+}
+
+// PurchaseLease buys a channel lease from the peer of the passed request. We
+// open a dual-funded channel to the peer, requesting it to contribute the
+// leased amount through the request_funding record of open_channel2, while
+// we contribute the lease fee and the fees of the commitment transaction. The
+// progress of the purchase is reported on the channels of the request.
+func (f *Manager) PurchaseLease(req *LeaseRequest) error {
+	peer := req.Peer
+	if !hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) || !hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.ScriptEnforcedLeaseOptional,
+	) {
+
+		return errLeaseNotNegotiated
+	}
+
+	if req.Amount <= 0 {
+		return errors.New("lease amount must be positive")
+	}
+
+	if req.FundingFeePerKw < chainfee.FeePerKwFloor {
+		return fmt.Errorf("funding fee rate %v below minimum of %v",
+			req.FundingFeePerKw, chainfee.FeePerKwFloor)
+	}
+
+	fee := req.Rates.LeaseFee(req.Amount, req.FundingFeePerKw)
+	if fee > req.MaxFee {
+		return fmt.Errorf("lease fee of %v exceeds maximum fee of %v",
+			fee, req.MaxFee)
+	}
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Purchasing lease of %v for fee %v from peer %x", req.Amount,
+		fee, peer.IdentityKey().SerializeCompressed())
+
+	// Our contribution is derived from the lease fee once the fee rate of
+	// the commitment transaction is known.
+	f.InitFundingWorkflow(&InitFundingMsg{
+		Peer:            peer,
+		TargetPubkey:    peer.IdentityKey(),
+		ChainHash:       *f.cfg.Wallet.Cfg.NetParams.GenesisHash,
+		FundingFeePerKw: req.FundingFeePerKw,
+		MinConfs:        1,
+		ChannelType:     leaseChannelType(),
+		Updates:         req.Updates,
+		Err:             req.Err,
+		lease: &leaseTerms{
+			request: lnwire.RequestFunding{
+				RequestedAmount: req.Amount,
+				BlockHeight:     uint32(bestHeight),
+			},
+			rates: req.Rates,
+			fee:   fee,
+		},
+	})
+
+	return nil
+}
+
+// validateLeaseRequest checks the lease requested by the passed open_channel2
+// message against our lease rates, and returns the terms of the lease.
+func (f *Manager) validateLeaseRequest(
+	msg *lnwire.OpenChannel2) (*leaseTerms, error) {
+
+	rates, err := f.cfg.LeaseRates.UnwrapOrErr(errNoLeaseRates)
+	if err != nil {
+		return nil, err
+	}
+
+	request := msg.RequestFunding
+	amt := request.RequestedAmount
+
+	maxAmt := f.cfg.MaxChanSize - msg.FundingAmount
+	if f.cfg.MaxLeaseAmt != 0 {
+		maxAmt = min(maxAmt, f.cfg.MaxLeaseAmt)
+	}
+	if amt < f.cfg.MinChanSize || amt > maxAmt {
+		return nil, leaseError{fmt.Errorf("requested amount %v "+
+			"outside of range [%v, %v]", amt, f.cfg.MinChanSize,
+			maxAmt)}
+	}
+
+	if msg.FundingFeePerKWeight < chainfee.FeePerKwFloor {
+		return nil, leaseError{fmt.Errorf("funding fee rate %v below "+
+			"minimum of %v", msg.FundingFeePerKWeight,
+			chainfee.FeePerKwFloor)}
+	}
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	err = checkLeaseHeight(request.BlockHeight, bestHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaseTerms{
+		request: *request,
+		rates:   rates,
+		fee:     rates.LeaseFee(amt, msg.FundingFeePerKWeight),
+	}, nil
+}
+
+// signLease returns the will_fund record with which we commit to the passed
+// lease we sell, using the given funding key of the leased channel.
+func (f *Manager) signLease(fundingKey *btcec.PublicKey,
+	lease *leaseTerms) (*lnwire.WillFund, error) {
+
+	commitment := lnwire.LeaseCommitment(
+		fundingKey, lease.expiry(), &lease.rates,
+	)
+	sig, err := f.cfg.SignMessage(f.cfg.IDKeyLoc, commitment, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign lease: %w", err)
+	}
+
+	wireSig, err := lnwire.NewSigFromSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwire.WillFund{
+		Sig:   wireSig,
+		Rates: lease.rates,
+	}, nil
+}
+
+// checkWillFund checks that the acceptor of a channel we requested the passed
+// lease for, identified by the given key, committed to the lease in the passed
+// accept_channel2 message at the requested rates.
+func checkWillFund(peerKey *btcec.PublicKey, lease *leaseTerms,
+	msg *lnwire.AcceptChannel2) error {
+
+	willFund := msg.WillFund
+	switch {
+	case lease == nil && willFund == nil:
+		return nil
+
+	case lease == nil:
+		return errUnexpectedWillFund
+
+	case willFund == nil:
+		return errLeaseNotAccepted
+
+	case willFund.Rates != lease.rates:
+		return errors.New("lease rates don't match advertised rates")
+
+	case msg.FundingAmount < lease.request.RequestedAmount:
+		return fmt.Errorf("leased channel funded with %v, requested %v",
+			msg.FundingAmount, lease.request.RequestedAmount)
+	}
+
+	sig, err := willFund.Sig.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	commitment := lnwire.LeaseCommitment(
+		msg.FundingKey, lease.expiry(), &willFund.Rates,
+	)
+	if !sig.Verify(chainhash.HashB(commitment), peerKey) {
+		return errors.New("invalid lease signature")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckLeaseHeight tests that the height a requested lease starts at may
+// only be slightly off from our best height.
+func TestCheckLeaseHeight(t *testing.T) {
+	t.Parallel()
+
+	const height = 800_000
+
+	require.NoError(t, checkLeaseHeight(height, height))
+	require.NoError(t, checkLeaseHeight(
+		height+leaseHeightTolerance, height,
+	))
+	require.NoError(t, checkLeaseHeight(
+		height-leaseHeightTolerance, height,
+	))
+
+	require.ErrorAs(t, checkLeaseHeight(
+		height+leaseHeightTolerance+1, height,
+	), &leaseError{})
+	require.ErrorAs(t, checkLeaseHeight(
+		height-leaseHeightTolerance-1, height,
+	), &leaseError{})
+}
+
+// TestCheckWillFund tests that the acceptor of a channel we requested a lease
+// for must commit to the lease with a valid signature at the requested rates.
+func TestCheckWillFund(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fundingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	lease := &leaseTerms{
+		request: lnwire.RequestFunding{
+			RequestedAmount: 1_000_000,
+			BlockHeight:     800_000,
+		},
+		rates: lnwire.LeaseRates{
+			FundingWeight:             600,
+			LeaseFeeBasis:             50,
+			ChannelFeeMaxProportional: 10,
+			LeaseFeeBase:              1_000,
+			ChannelFeeMaxBase:         5_000,
+		},
+		fee: 6_500,
+	}
+
+	// sign returns a will_fund record for the lease signed by the given
+	// node key.
+	sign := func(key *btcec.PrivateKey) *lnwire.WillFund {
+		commitment := lnwire.LeaseCommitment(
+			fundingKey.PubKey(), lease.expiry(), &lease.rates,
+		)
+		sig := ecdsa.Sign(key, chainhash.HashB(commitment))
+
+		wireSig, err := lnwire.NewSigFromSignature(sig)
+		require.NoError(t, err)
+
+		return &lnwire.WillFund{
+			Sig:   wireSig,
+			Rates: lease.rates,
+		}
+	}
+
+	newMsg := func() *lnwire.AcceptChannel2 {
+		return &lnwire.AcceptChannel2{
+			FundingAmount: lease.request.RequestedAmount,
+			FundingKey:    fundingKey.PubKey(),
+			WillFund:      sign(nodeKey),
+		}
+	}
+	peerKey := nodeKey.PubKey()
+
+	// A valid commitment to the lease is accepted.
+	require.NoError(t, checkWillFund(peerKey, lease, newMsg()))
+
+	// Channels without lease don't carry will_fund.
+	require.NoError(t, checkWillFund(
+		peerKey, nil, &lnwire.AcceptChannel2{},
+	))
+	require.ErrorIs(t, checkWillFund(
+		peerKey, nil, newMsg(),
+	), errUnexpectedWillFund)
+
+	// The acceptor must commit to the lease we requested.
+	msg := newMsg()
+	msg.WillFund = nil
+	require.ErrorIs(t, checkWillFund(
+		peerKey, lease, msg,
+	), errLeaseNotAccepted)
+
+	// The lease must be sold at the advertised rates.
+	msg = newMsg()
+	msg.WillFund.Rates.ChannelFeeMaxBase++
+	require.Error(t, checkWillFund(peerKey, lease, msg))
+
+	// The acceptor must contribute the requested amount.
+	msg = newMsg()
+	msg.FundingAmount--
+	require.Error(t, checkWillFund(peerKey, lease, msg))
+
+	// The commitment must be signed by the acceptor's node key, over its
+	// funding key.
+	msg = newMsg()
+	msg.WillFund = sign(otherKey)
+	require.Error(t, checkWillFund(peerKey, lease, msg))
+
+	msg = newMsg()
+	msg.FundingKey = otherKey.PubKey()
+	require.Error(t, checkWillFund(peerKey, lease, msg))
+}
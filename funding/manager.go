@@ -31,6 +31,7 @@ import (
 	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -166,6 +167,15 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// dualFund tracks the construction of the funding transaction if the
+	// channel is opened using the dual-funded channel establishment
+	// protocol. It's nil for single funder channels.
+	dualFund *dualFundSession
+
+	// lease holds the terms of the lease we buy if the channel is a
+	// leased dual-funded channel we opened.
+	lease *leaseTerms
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -306,6 +316,10 @@ type InitFundingMsg struct {
 	// Err is a channel which errors encountered during the funding flow are
 	// sent on.
 	Err chan error
+
+	// lease holds the terms of the lease if we open a leased channel to
+	// buy inbound liquidity from the peer.
+	lease *leaseTerms
 }
 
 // fundingMsg is sent by the ProcessFundingMsg function and packages a
@@ -472,6 +486,13 @@ type Config struct {
 	// node we're establishing a channel with for reconnection purposes.
 	WatchNewChannel func(*channeldb.OpenChannel, *btcec.PublicKey) error
 
+	// StopWatchingChannel is called once a replacement of the funding
+	// transaction of a pending dual-funded channel confirmed, which
+	// changes its funding outpoint. The ChainArbitrator stops watching the
+	// channel under its former funding outpoint, before it's watched again
+	// with WatchNewChannel.
+	StopWatchingChannel func(wire.OutPoint) error
+
 	// ReportShortChanID allows the funding manager to report the confirmed
 	// short channel ID of a formerly pending zero-conf channel to outside
 	// sub-systems.
@@ -517,6 +538,20 @@ type Config struct {
 	// tells the funding manager whether or not to accept the channel.
 	OpenChannelPredicate chanacceptor.ChannelAcceptor
 
+	// DualFundContribution decides how much we contribute to a
+	// dual-funded channel proposed by a remote peer, unless the channel
+	// acceptor already set a contribution. If nil, we don't contribute to
+	// such channels.
+	DualFundContribution chanacceptor.ContributionPolicy
+
+	// LeaseRates are the rates we sell channel leases at to peers
+	// requesting them. If none, we reject all lease requests.
+	LeaseRates fn.Option[lnwire.LeaseRates]
+
+	// MaxLeaseAmt is the largest amount we contribute to a single leased
+	// channel. If zero, MaxChanSize applies.
+	MaxLeaseAmt btcutil.Amount
+
 	// NotifyPendingOpenChannelEvent informs the ChannelNotifier when
 	// channels enter a pending state.
 	NotifyPendingOpenChannelEvent func(wire.OutPoint,
@@ -535,6 +570,14 @@ type Config struct {
 	DeleteAliasEdge func(scid lnwire.ShortChannelID) (
 		*models.ChannelEdgePolicy, error)
 
+	// DeleteSplicedEdge allows the Manager to delete the edge of a spliced
+	// channel from the graph, which is still known by the funding output
+	// the channel had before its latest splice. It returns the SCID of
+	// the deleted edge along with our policy, or a nil policy if no such
+	// edge exists.
+	DeleteSplicedEdge func(*channeldb.OpenChannel) (lnwire.ShortChannelID,
+		*models.ChannelEdgePolicy, error)
+
 	// AliasManager is an implementation of the aliasHandler interface that
 	// abstracts away the handling of many alias functions.
 	AliasManager aliasHandler
@@ -545,6 +588,12 @@ type Config struct {
 	// subsystem.
 	IsSweeperOutpoint func(wire.OutPoint) bool
 
+	// InitSplice asks the link of the given channel to negotiate a splice
+	// with the remote peer. The returned channel is resolved with the
+	// fully signed splice transaction once the negotiation completes.
+	InitSplice func(lnwire.ChannelID, *lnwallet.SpliceContribution) (
+		<-chan fn.Result[*wire.MsgTx], error)
+
 	// AuxLeafStore is an optional store that can be used to store auxiliary
 	// leaves for certain custom channel types.
 	AuxLeafStore fn.Option[lnwallet.AuxLeafStore]
@@ -614,7 +663,18 @@ type Manager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID]PendingChanID
 
-	// resMtx guards both of the maps above to ensure that all access is
+	// dualFundChanIDs maps the channel ID of a dual-funded channel, which
+	// is derived from the revocation basepoints of both parties, to the
+	// temporary channel ID its reservation is tracked under.
+	dualFundChanIDs map[lnwire.ChannelID]PendingChanID
+
+	// dualFundPending tracks the pending dual-funded channels whose
+	// funding transaction is signed, by their channel ID derived from the
+	// revocation basepoints of both parties, until one of their funding
+	// transactions confirms.
+	dualFundPending map[lnwire.ChannelID]*pendingDualFund
+
+	// resMtx guards all of the maps above to ensure that all access is
 	// goroutine safe.
 	resMtx sync.RWMutex
 
@@ -626,6 +686,10 @@ type Manager struct {
 	// requests from a local subsystem within the daemon.
 	fundingRequests chan *InitFundingMsg
 
+	// dualFundBumps is a channel used to receive requests to replace the
+	// funding transaction of a pending dual-funded channel.
+	dualFundBumps chan *dualFundBumpReq
+
 	localDiscoverySignals *lnutils.SyncMap[lnwire.ChannelID, chan struct{}]
 
 	handleChannelReadyBarriers *lnutils.SyncMap[lnwire.ChannelID, struct{}]
@@ -682,12 +746,19 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		signedReservations: make(
 			map[lnwire.ChannelID][32]byte,
 		),
+		dualFundChanIDs: make(
+			map[lnwire.ChannelID]PendingChanID,
+		),
+		dualFundPending: make(
+			map[lnwire.ChannelID]*pendingDualFund,
+		),
 		fundingMsgs: make(
 			chan *fundingMsg, msgBufferSize,
 		),
 		fundingRequests: make(
 			chan *InitFundingMsg, msgBufferSize,
 		),
+		dualFundBumps: make(chan *dualFundBumpReq),
 		localDiscoverySignals: &lnutils.SyncMap[
 			lnwire.ChannelID, chan struct{},
 		]{},
@@ -740,6 +811,26 @@ func (f *Manager) start() error {
 				chanID, make(chan struct{}),
 			)
 
+			// The funding transaction of a dual-funded channel may
+			// have been replaced before the restart. Its candidates
+			// are watched along with it, and further replacements
+			// are refused.
+			candidates, err := channel.FundingCandidates()
+			if err != nil {
+				return err
+			}
+			if len(candidates) > 0 {
+				peerKey := newSerializedKey(channel.IdentityPub)
+				f.dualFundPending[dualFundChanID(channel)] =
+					&pendingDualFund{
+						peer:    peerKey,
+						channel: channel,
+						newCandidate: make(
+							chan struct{}, 1,
+						),
+					}
+			}
+
 			// Rebroadcast the funding transaction for any pending
 			// channel that we initiated. No error will be returned
 			// if the transaction already has been broadcast.
@@ -874,6 +965,12 @@ func (f *Manager) CancelPeerReservations(nodePub [33]byte) {
 
 		resCtx.err <- fmt.Errorf("peer disconnected")
 		delete(nodeReservations, pendingID)
+
+		for chanID, tempChanID := range f.dualFundChanIDs {
+			if tempChanID == pendingID {
+				delete(f.dualFundChanIDs, chanID)
+			}
+		}
 	}
 
 	// Finally, we'll delete the node itself from the set of reservations.
@@ -961,6 +1058,14 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, cid *chanIdentifier,
 		ctx.err <- fundingErr
 	}
 
+	f.sendFundingError(peer, cid.tempChanID, fundingErr)
+}
+
+// sendFundingError sends the passed funding error to the remote peer, using the
+// given channel ID.
+func (f *Manager) sendFundingError(peer lnpeer.Peer, chanID lnwire.ChannelID,
+	fundingErr error) {
+
 	// We only send the exact error if it is part of out whitelisted set of
 	// errors (lnwire.FundingError or lnwallet.ReservationError).
 	var msg lnwire.ErrorData
@@ -973,6 +1078,8 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, cid *chanIdentifier,
 		msg = lnwire.ErrorData(e.Error())
 	case chanacceptor.ChanAcceptError:
 		msg = lnwire.ErrorData(e.Error())
+	case leaseError:
+		msg = lnwire.ErrorData(e.Error())
 
 	// For all other error types we just send a generic error.
 	default:
@@ -980,7 +1087,7 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, cid *chanIdentifier,
 	}
 
 	errMsg := &lnwire.Error{
-		ChanID: cid.tempChanID,
+		ChanID: chanID,
 		Data:   msg,
 	}
 
@@ -1039,6 +1146,23 @@ func (f *Manager) reservationCoordinator() {
 			case *lnwire.FundingSigned:
 				f.funderProcessFundingSigned(fmsg.peer, msg)
 
+			case *lnwire.OpenChannel2:
+				f.fundeeProcessOpenChannel2(fmsg.peer, msg)
+
+			case *lnwire.AcceptChannel2:
+				f.funderProcessAcceptChannel2(fmsg.peer, msg)
+
+			case *lnwire.TxAddInput, *lnwire.TxAddOutput,
+				*lnwire.TxRemoveInput, *lnwire.TxRemoveOutput,
+				*lnwire.TxComplete, *lnwire.CommitSig,
+				*lnwire.TxSignatures, *lnwire.TxInitRbf,
+				*lnwire.TxAckRbf, *lnwire.TxAbort:
+
+				//nolint:forcetypeassert
+				f.handleDualFundMsg(
+					fmsg.peer, msg.(lnwire.LinkUpdater),
+				)
+
 			case *lnwire.ChannelReady:
 				f.wg.Add(1)
 				go f.handleChannelReady(fmsg.peer, msg)
@@ -1052,6 +1176,9 @@ func (f *Manager) reservationCoordinator() {
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
 
+		case req := <-f.dualFundBumps:
+			f.handleDualFundBump(req)
+
 		case <-zombieSweepTicker.C:
 			f.pruneZombieReservations()
 
@@ -1221,7 +1348,10 @@ func (f *Manager) stateStep(channel *channeldb.OpenChannel,
 	// The channel was added to the Router's topology, but the channel
 	// announcement was not sent.
 	case addedToGraph:
-		if channel.IsZeroConf() {
+		// A spliced channel was added to the graph under the SCID of
+		// its new funding output, which is confirmed already.
+		isSpliced := channel.SpliceShortChanID().IsSome()
+		if channel.IsZeroConf() && !isSpliced {
 			// If this is a zero-conf channel, then we will wait
 			// for it to be confirmed before announcing it to the
 			// greater network.
@@ -1327,6 +1457,7 @@ func (f *Manager) advancePendingChannelState(channel *channeldb.OpenChannel,
 
 	confChannel, err := f.waitForFundingWithTimeout(channel)
 	if err == ErrConfirmationTimeout {
+		f.removePendingDualFund(channel)
 		return f.fundingTimeout(channel, pendingChanID)
 	} else if err != nil {
 		return fmt.Errorf("error waiting for funding "+
@@ -1334,6 +1465,19 @@ func (f *Manager) advancePendingChannelState(channel *channeldb.OpenChannel,
 			channel.FundingOutpoint, err)
 	}
 
+	// Once a funding transaction of a dual-funded channel confirmed, it
+	// can't be replaced anymore. If it's a replacement, the channel moves
+	// to its funding outpoint.
+	f.removePendingDualFund(channel)
+	if confChannel.fundingTx.TxHash() != channel.FundingOutpoint.Hash {
+		err := f.confirmFundingCandidate(channel, confChannel.fundingTx)
+		if err != nil {
+			return fmt.Errorf("unable to confirm replacement of "+
+				"funding tx of ChannelPoint(%v): %w",
+				channel.FundingOutpoint, err)
+		}
+	}
+
 	if blockchain.IsCoinBaseTx(confChannel.fundingTx) {
 		// If it's a coinbase transaction, we need to wait for it to
 		// mature. We wait out an additional MinAcceptDepth on top of
@@ -1406,25 +1550,12 @@ func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
 	}
 }
 
-// fundeeProcessOpenChannel creates an initial 'ChannelReservation' within the
-// wallet, then responds to the source peer with an accept channel message
-// progressing the funding workflow.
-//
-// TODO(roasbeef): add error chan to all, let channelManager handle
-// error+propagate.
-//
-//nolint:funlen
-func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
-	msg *lnwire.OpenChannel) {
-
-	// Check number of pending channels to be smaller than maximum allowed
-	// number and send ErrorGeneric to remote peer if condition is
-	// violated.
-	peerPubKey := peer.IdentityKey()
+// checkPendingChannelLimits returns an error if we can't accept another
+// pending channel from the given peer, either because of the limits on the
+// number of pending channels, or because we're not synced to the chain yet.
+func (f *Manager) checkPendingChannelLimits(peerPubKey *btcec.PublicKey) error {
 	peerIDKey := newSerializedKey(peerPubKey)
 
-	amt := msg.FundingAmount
-
 	// We get all pending channels for this peer. This is the list of the
 	// active reservations and the channels pending open in the database.
 	f.resMtx.RLock()
@@ -1441,15 +1572,11 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 	}
 	f.resMtx.RUnlock()
 
-	// Create the channel identifier.
-	cid := newChanIdentifier(msg.PendingChannelID)
-
 	// Also count the channels that are already pending. There we don't know
 	// the underlying intent anymore, unfortunately.
 	channels, err := f.cfg.ChannelDB.FetchOpenChannels(peerPubKey)
 	if err != nil {
-		f.failFundingFlow(peer, cid, err)
-		return
+		return err
 	}
 
 	for _, c := range channels {
@@ -1459,7 +1586,10 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		//
 		// TODO(guggero): Properly store the funding type (wallet, shim,
 		// PSBT) on the channel so we don't need to use the thaw height.
-		if c.IsPending && c.ThawHeight == 0 {
+		// Leases bought through liquidity ads have a thaw height as
+		// well, but aren't created through a funding shim.
+		shimFunded := c.ThawHeight != 0 && !c.ChanType.HasAcceptorLease()
+		if c.IsPending && !shimFunded {
 			numPending++
 		}
 	}
@@ -1467,21 +1597,17 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 	// TODO(roasbeef): modify to only accept a _single_ pending channel per
 	// block unless white listed
 	if numPending >= f.cfg.MaxPendingChannels {
-		f.failFundingFlow(peer, cid, lnwire.ErrMaxPendingChannels)
-
-		return
+		return lnwire.ErrMaxPendingChannels
 	}
 
 	// Ensure that the pendingChansLimit is respected.
 	pendingChans, err := f.cfg.ChannelDB.FetchPendingChannels()
 	if err != nil {
-		f.failFundingFlow(peer, cid, err)
-		return
+		return err
 	}
 
 	if len(pendingChans) > pendingChansLimit {
-		f.failFundingFlow(peer, cid, lnwire.ErrMaxPendingChannels)
-		return
+		return lnwire.ErrMaxPendingChannels
 	}
 
 	// We'll also reject any requests to create channels until we're fully
@@ -1492,7 +1618,35 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		if err != nil {
 			log.Errorf("unable to query wallet: %v", err)
 		}
-		err := errors.New("Synchronizing blockchain")
+		return errors.New("Synchronizing blockchain")
+	}
+
+	return nil
+}
+
+// fundeeProcessOpenChannel creates an initial 'ChannelReservation' within the
+// wallet, then responds to the source peer with an accept channel message
+// progressing the funding workflow.
+//
+// TODO(roasbeef): add error chan to all, let channelManager handle
+// error+propagate.
+//
+//nolint:funlen
+func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel) {
+
+	// Check number of pending channels to be smaller than maximum allowed
+	// number and send ErrorGeneric to remote peer if condition is
+	// violated.
+	peerPubKey := peer.IdentityKey()
+	peerIDKey := newSerializedKey(peerPubKey)
+
+	amt := msg.FundingAmount
+
+	// Create the channel identifier.
+	cid := newChanIdentifier(msg.PendingChannelID)
+
+	if err := f.checkPendingChannelLimits(peerPubKey); err != nil {
 		f.failFundingFlow(peer, cid, err)
 		return
 	}
@@ -1825,6 +1979,14 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		maxHtlcs = acceptorResp.HtlcLimit
 	}
 
+	// Zero-fee commitments allow fewer HTLCs to stay within the size limit
+	// of TRUC transactions.
+	if commitType == lnwallet.CommitmentTypeZeroFeeCommitments {
+		maxHtlcs = min(
+			maxHtlcs, uint16(input.MaxZeroFeeCommitHTLCNumber/2),
+		)
+	}
+
 	// Default to our default minimum hltc value, replacing it with the
 	// channel acceptor's value if it is set.
 	minHtlc := f.cfg.DefaultMinHtlcIn
@@ -1997,7 +2159,10 @@ func (f *Manager) funderProcessAcceptChannel(peer lnpeer.Peer,
 	// Update the timestamp once the fundingAcceptMsg has been handled.
 	defer resCtx.updateTimestamp()
 
-	if resCtx.reservation.State() != lnwallet.SentOpenChannel {
+	// Dual-funded channels are answered with accept_channel2.
+	if resCtx.reservation.State() != lnwallet.SentOpenChannel ||
+		resCtx.dualFund != nil {
+
 		return
 	}
 
@@ -2463,7 +2628,9 @@ func (f *Manager) fundeeProcessFundingCreated(peer lnpeer.Peer,
 	log.Infof("completing pending_id(%x) with ChannelPoint(%v)",
 		pendingChanID[:], fundingOut)
 
-	if resCtx.reservation.State() != lnwallet.SentAcceptChannel {
+	if resCtx.reservation.State() != lnwallet.SentAcceptChannel ||
+		resCtx.dualFund != nil {
+
 		return
 	}
 
@@ -2884,6 +3051,74 @@ func (f *Manager) funderProcessFundingSigned(peer lnpeer.Peer,
 	go f.advanceFundingState(completeChan, pendingChanID, resCtx.updates)
 }
 
+// removePendingDualFund stops tracking the replacements of the funding
+// transaction of the given channel, if it's dual-funded.
+func (f *Manager) removePendingDualFund(channel *channeldb.OpenChannel) {
+	f.resMtx.Lock()
+	delete(f.dualFundPending, dualFundChanID(channel))
+	f.resMtx.Unlock()
+}
+
+// confirmFundingCandidate moves a pending dual-funded channel to the funding
+// outpoint of the confirmed replacement of its funding transaction. All state
+// tracked under the former channel ID is moved along with it.
+func (f *Manager) confirmFundingCandidate(channel *channeldb.OpenChannel,
+	fundingTx *wire.MsgTx) error {
+
+	oldPoint := channel.FundingOutpoint
+	oldChanID := lnwire.NewChanIDFromOutPoint(oldPoint)
+
+	if err := channel.ConfirmFundingCandidate(fundingTx); err != nil {
+		return err
+	}
+
+	newPoint := channel.FundingOutpoint
+	newChanID := lnwire.NewChanIDFromOutPoint(newPoint)
+
+	log.Infof("Replacement of funding tx of ChannelPoint(%v) confirmed, "+
+		"moved channel to ChannelPoint(%v)", oldPoint, newPoint)
+
+	// The ChainArbitrator watches the channel under its new funding
+	// outpoint from now on.
+	if err := f.cfg.StopWatchingChannel(oldPoint); err != nil {
+		log.Errorf("Unable to stop watching ChannelPoint(%v): %v",
+			oldPoint, err)
+	}
+	err := f.cfg.WatchNewChannel(channel, channel.IdentityPub)
+	if err != nil {
+		log.Errorf("Unable to send new ChannelPoint(%v) for "+
+			"arbitration: %v", newPoint, err)
+	}
+
+	if signal, ok := f.localDiscoverySignals.LoadAndDelete(oldChanID); ok {
+		f.localDiscoverySignals.Store(newChanID, signal)
+	}
+
+	policy, err := f.getInitialForwardingPolicy(oldChanID)
+	if err == nil {
+		err = f.saveInitialForwardingPolicy(newChanID, policy)
+	}
+	if err == nil {
+		err = f.deleteInitialForwardingPolicy(oldChanID)
+	}
+	if err != nil {
+		log.Errorf("Unable to move forwarding policy of "+
+			"ChannelPoint(%v): %v", oldPoint, err)
+	}
+
+	// Finally, the peer tracks the pending channel by its new ID as well.
+	peer, err := f.waitForPeerOnline(channel.IdentityPub)
+	if err != nil {
+		return err
+	}
+	if err := peer.RemovePendingChannel(oldChanID); err != nil {
+		log.Errorf("Unable to remove pending channel %v: %v",
+			oldChanID, err)
+	}
+
+	return peer.AddPendingChannel(newChanID, f.quit)
+}
+
 // confirmedChannel wraps a confirmed funding transaction, as well as the short
 // channel ID which identifies that channel into a single struct. We'll use
 // this to pass around the final state of a channel after it has been
@@ -3047,7 +3282,9 @@ func makeFundingScript(channel *channeldb.OpenChannel) ([]byte, error) {
 // process once the funding transaction has been broadcast. The primary
 // function of waitForFundingConfirmation is to wait for blockchain
 // confirmation, and then to notify the other systems that must be notified
-// when a channel has become active for lightning transactions.
+// when a channel has become active for lightning transactions. For
+// dual-funded channels, all candidates of the funding transaction are
+// watched, including the ones added while waiting.
 // The wait can be canceled by closing the cancelChan. In case of success,
 // a *lnwire.ShortChannelID will be passed to confChan.
 //
@@ -3077,10 +3314,79 @@ func (f *Manager) waitForFundingConfirmation(
 		numConfs = 6
 	}
 
-	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
-		&txid, fundingScript, numConfs,
-		completeChan.BroadcastHeight(),
-	)
+	// Each registered transaction forwards its confirmation to the same
+	// channel, on which a nil confirmation signals that the ChainNotifier
+	// is shutting down.
+	confirmed := make(chan *chainntnfs.TxConfirmation)
+	watched := make(map[chainhash.Hash]struct{})
+	watch := func(txid chainhash.Hash) error {
+		if _, ok := watched[txid]; ok {
+			return nil
+		}
+
+		confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
+			&txid, fundingScript, numConfs,
+			completeChan.BroadcastHeight(),
+		)
+		if err != nil {
+			return err
+		}
+		watched[txid] = struct{}{}
+
+		log.Infof("Waiting for funding tx (%v) to reach %v "+
+			"confirmations", txid, numConfs)
+
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+
+			var confDetails *chainntnfs.TxConfirmation
+			select {
+			case confDetails = <-confNtfn.Confirmed:
+			case <-cancelChan:
+				return
+			case <-f.quit:
+				return
+			}
+
+			select {
+			case confirmed <- confDetails:
+			case <-cancelChan:
+			case <-f.quit:
+			}
+		}()
+
+		return nil
+	}
+
+	// The replacements of the funding transaction of a dual-funded
+	// channel are watched as well.
+	var newCandidate <-chan struct{}
+	watchCandidates := func() error {
+		candidates, err := completeChan.FundingCandidates()
+		if err != nil {
+			return err
+		}
+		for _, candidate := range candidates {
+			if err := watch(candidate.Txid()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err = watch(txid)
+	if err == nil {
+		f.resMtx.RLock()
+		pending, ok := f.dualFundPending[dualFundChanID(completeChan)]
+		f.resMtx.RUnlock()
+		if ok {
+			newCandidate = pending.newCandidate
+		}
+
+		err = watchCandidates()
+	}
 	if err != nil {
 		log.Errorf("Unable to register for confirmation of "+
 			"ChannelPoint(%v): %v", completeChan.FundingOutpoint,
@@ -3088,39 +3394,56 @@ func (f *Manager) waitForFundingConfirmation(
 		return
 	}
 
-	log.Infof("Waiting for funding tx (%v) to reach %v confirmations",
-		txid, numConfs)
-
-	var confDetails *chainntnfs.TxConfirmation
-	var ok bool
-
 	// Wait until the specified number of confirmations has been reached,
 	// we get a cancel signal, or the wallet signals a shutdown.
-	select {
-	case confDetails, ok = <-confNtfn.Confirmed:
-		// fallthrough
+	var confDetails *chainntnfs.TxConfirmation
+	for confDetails == nil {
+		select {
+		case confDetails = <-confirmed:
+			if confDetails == nil {
+				log.Warnf("ChainNotifier shutting down, cannot "+
+					"complete funding flow for "+
+					"ChannelPoint(%v)",
+					completeChan.FundingOutpoint)
+				return
+			}
 
-	case <-cancelChan:
-		log.Warnf("canceled waiting for funding confirmation, "+
-			"stopping funding flow for ChannelPoint(%v)",
-			completeChan.FundingOutpoint)
-		return
+		case <-newCandidate:
+			if err := watchCandidates(); err != nil {
+				log.Errorf("Unable to register for "+
+					"confirmation of funding candidates "+
+					"of ChannelPoint(%v): %v",
+					completeChan.FundingOutpoint, err)
+				return
+			}
 
-	case <-f.quit:
-		log.Warnf("fundingManager shutting down, stopping funding "+
-			"flow for ChannelPoint(%v)",
-			completeChan.FundingOutpoint)
-		return
-	}
+		case <-cancelChan:
+			log.Warnf("canceled waiting for funding confirmation, "+
+				"stopping funding flow for ChannelPoint(%v)",
+				completeChan.FundingOutpoint)
+			return
 
-	if !ok {
-		log.Warnf("ChainNotifier shutting down, cannot complete "+
-			"funding flow for ChannelPoint(%v)",
-			completeChan.FundingOutpoint)
-		return
+		case <-f.quit:
+			log.Warnf("fundingManager shutting down, stopping "+
+				"funding flow for ChannelPoint(%v)",
+				completeChan.FundingOutpoint)
+			return
+		}
 	}
 
+	// If a replacement of the funding transaction confirmed, the funding
+	// output is the one of the replacement.
 	fundingPoint := completeChan.FundingOutpoint
+	if confDetails.Tx != nil && confDetails.Tx.TxHash() != txid {
+		_, index := input.FindScriptOutputIndex(
+			confDetails.Tx, fundingScript,
+		)
+		fundingPoint = wire.OutPoint{
+			Hash:  confDetails.Tx.TxHash(),
+			Index: index,
+		}
+	}
+
 	log.Infof("ChannelPoint(%v) is now active: ChannelID(%v)",
 		fundingPoint, lnwire.NewChanIDFromOutPoint(fundingPoint))
 
@@ -3563,7 +3886,7 @@ func (f *Manager) addToGraph(completeChan *channeldb.OpenChannel,
 	// to the Router's topology.
 	errChan := f.cfg.SendAnnouncement(
 		ann.chanAnn, discovery.ChannelCapacity(completeChan.Capacity),
-		discovery.ChannelPoint(completeChan.FundingOutpoint),
+		discovery.ChannelPoint(completeChan.CurrentFundingOutpoint()),
 		discovery.TapscriptRoot(completeChan.TapscriptRoot),
 	)
 	select {
@@ -3655,11 +3978,13 @@ func (f *Manager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 		if numConfs < 6 {
 			numConfs = 6
 		}
-		txid := completeChan.FundingOutpoint.Hash
+		// A spliced channel is announced once its splice transaction
+		// reached the required depth.
+		fundingPoint := completeChan.CurrentFundingOutpoint()
+		txid := fundingPoint.Hash
 		log.Debugf("Will announce channel %v after ChannelPoint"+
 			"(%v) has gotten %d confirmations",
-			shortChanID.ToUint64(), completeChan.FundingOutpoint,
-			numConfs)
+			shortChanID.ToUint64(), fundingPoint, numConfs)
 
 		fundingScript, err := makeFundingScript(completeChan)
 		if err != nil {
@@ -3699,8 +4024,9 @@ func (f *Manager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 				completeChan.FundingOutpoint)
 		}
 
-		fundingPoint := completeChan.FundingOutpoint
-		chanID := lnwire.NewChanIDFromOutPoint(fundingPoint)
+		chanID := lnwire.NewChanIDFromOutPoint(
+			completeChan.FundingOutpoint,
+		)
 
 		log.Infof("Announcing ChannelPoint(%v), short_chan_id=%v",
 			&fundingPoint, shortChanID)
@@ -3708,10 +4034,12 @@ func (f *Manager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 		// If this is a non-zero-conf option-scid-alias channel, we'll
 		// delete the mappings the gossiper uses so that ChannelUpdates
 		// with aliases won't be accepted. This is done elsewhere for
-		// zero-conf channels.
+		// zero-conf channels, and was done when the channel was first
+		// announced for spliced channels.
 		isScidFeature := completeChan.NegotiatedAliasFeature()
 		isZeroConf := completeChan.IsZeroConf()
-		if isScidFeature && !isZeroConf {
+		isSpliced := completeChan.SpliceShortChanID().IsSome()
+		if isScidFeature && !isZeroConf && !isSpliced {
 			baseScid := completeChan.ShortChanID()
 			err := f.cfg.AliasManager.DeleteSixConfs(baseScid)
 			if err != nil {
@@ -4645,6 +4973,21 @@ func getUpfrontShutdownScript(enableUpfrontShutdown bool, peer lnpeer.Peer,
 	return getScript(taprootOK)
 }
 
+// allowUtxoForFunding returns true if the given utxo may be used to fund a
+// channel.
+func (f *Manager) allowUtxoForFunding(u lnwallet.Utxo) bool {
+	// Utxos with at least 1 confirmation are safe to use for channel
+	// openings because they don't bare the risk of being replaced (BIP 125
+	// RBF).
+	if u.Confirmations > 0 {
+		return true
+	}
+
+	// Query the sweeper storage to make sure we don't use an unconfirmed
+	// utxo still in use by the sweeper subsystem.
+	return !f.cfg.IsSweeperOutpoint(u.OutPoint)
+}
+
 // handleInitFundingMsg creates a channel reservation within the daemon's
 // wallet, then sends a funding request to the remote peer kicking off the
 // funding workflow.
@@ -4754,6 +5097,19 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		}
 	}
 
+	// If both of us support it, and the request doesn't rely on features
+	// of the single funder protocol, we'll open a dual-funded channel,
+	// allowing the remote peer to contribute to it.
+	dualFund := f.canDualFund(msg, commitType, zeroConf)
+
+	// Leases can only be bought in dual-funded channels.
+	if msg.lease != nil && !dualFund {
+		log.Error(errLeaseNotDualFunded)
+		msg.Err <- errLeaseNotDualFunded
+
+		return
+	}
+
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
 	// of 3). We target the near blocks here to ensure that we'll be able
@@ -4764,14 +5120,39 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// Zero-fee commitments allow fewer HTLCs to stay within the size limit
+	// of TRUC transactions.
+	maxZeroFeeHtlcs := uint16(input.MaxZeroFeeCommitHTLCNumber / 2)
+	zeroFee := commitType == lnwallet.CommitmentTypeZeroFeeCommitments
+	if zeroFee && maxHtlcs > maxZeroFeeHtlcs {
+		err := fmt.Errorf("max htlcs of %v exceeds the maximum of %v "+
+			"for zero-fee commitments", maxHtlcs, maxZeroFeeHtlcs)
+		log.Error(err)
+		msg.Err <- err
+
+		return
+	}
+
 	// For anchor channels cap the initial commit fee rate at our defined
-	// maximum.
-	if commitType.HasAnchors() &&
-		commitFeePerKw > f.cfg.MaxAnchorsCommitFeeRate {
+	// maximum. Zero-fee commitments don't pay any fee at all.
+	switch {
+	case zeroFee:
+		commitFeePerKw = 0
+
+	case commitType.HasAnchors() &&
+		commitFeePerKw > f.cfg.MaxAnchorsCommitFeeRate:
 
 		commitFeePerKw = f.cfg.MaxAnchorsCommitFeeRate
 	}
 
+	// As the buyer of a lease, we contribute just enough to pay the lease
+	// fee, along with the fees of the commitment transaction and the value
+	// of its anchor outputs we pay for as the opener.
+	if msg.lease != nil {
+		localAmt = msg.lease.fee + 2*lnwallet.AnchorSize +
+			commitFeePerKw.FeeForWeight(input.AnchorCommitWeight)
+	}
+
 	var scidFeatureVal bool
 	if hasFeatures(
 		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
@@ -4820,24 +5201,19 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		// Unconfirmed Utxos which are marked by the sweeper subsystem
 		// are excluded from the coin selection because they are not
 		// final and can be RBFed by the sweeper subsystem.
-		AllowUtxoForFunding: func(u lnwallet.Utxo) bool {
-			// Utxos with at least 1 confirmation are safe to use
-			// for channel openings because they don't bare the risk
-			// of being replaced (BIP 125 RBF).
-			if u.Confirmations > 0 {
-				return true
-			}
-
-			// Query the sweeper storage to make sure we don't use
-			// an unconfirmed utxo still in use by the sweeper
-			// subsystem.
-			return !f.cfg.IsSweeperOutpoint(u.OutPoint)
-		},
-		ZeroConf:         zeroConf,
-		OptionScidAlias:  scid,
-		ScidAliasFeature: scidFeatureVal,
-		Memo:             msg.Memo,
-		TapscriptRoot:    tapscriptRoot,
+		AllowUtxoForFunding: f.allowUtxoForFunding,
+		ZeroConf:            zeroConf,
+		OptionScidAlias:     scid,
+		ScidAliasFeature:    scidFeatureVal,
+		Memo:                msg.Memo,
+		TapscriptRoot:       tapscriptRoot,
+	}
+	if dualFund {
+		req.DualFundOpener = fn.Some(lntypes.Local)
+	}
+	if msg.lease != nil {
+		req.LeaseExpiry = msg.lease.expiry()
+		req.LeaseFee = msg.lease.fee
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -4889,12 +5265,24 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 
 	if maxHtlcs == 0 {
 		maxHtlcs = f.cfg.RequiredRemoteMaxHTLCs(capacity)
+
+		if zeroFee {
+			maxHtlcs = min(maxHtlcs, maxZeroFeeHtlcs)
+		}
 	}
 
 	// Once the reservation has been created, and indexed, queue a funding
 	// request to the remote peer, kicking off the funding workflow.
 	ourContribution := reservation.OurContribution()
 
+	// The temporary channel ID of a dual-funded channel is derived from
+	// our revocation basepoint.
+	if dualFund {
+		chanID = lnwire.NewTempChanIDFromRevocationBasepoint(
+			ourContribution.RevocationBasePoint.PubKey,
+		)
+	}
+
 	// Prepare the optional channel fee values from the initFundingMsg. If
 	// useBaseFee or useFeeRate are false the client did not provide fee
 	// values hence we assume default fee settings from the config.
@@ -4944,6 +5332,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		channelType:       chanType,
 		reservation:       reservation,
 		peer:              msg.Peer,
+		lease:             msg.lease,
 		updates:           msg.Updates,
 		err:               msg.Err,
 	}
@@ -4978,6 +5367,26 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	if dualFund {
+		sendErr := f.sendOpenChannel2(
+			resCtx, chanID, msg.FundingFeePerKw, commitFeePerKw,
+			channelFlags, shutdown,
+		)
+		if sendErr != nil {
+			log.Errorf("Unable to send open_channel2: %v", sendErr)
+
+			_, err := f.cancelReservationCtx(peerKey, chanID, false)
+			if err != nil {
+				log.Errorf("unable to cancel reservation: %v",
+					err)
+			}
+
+			msg.Err <- sendErr
+		}
+
+		return
+	}
+
 	// When opening a script enforced channel lease, include the required
 	// expiry TLV record in our proposal.
 	var leaseExpiry *lnwire.LeaseExpiry
@@ -5051,6 +5460,27 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer, msg *lnwire.Error) {
 	chanID := msg.ChanID
 	peerKey := peer.IdentityKey()
 
+	// Errors for dual-funded channels may refer to the channel by the ID
+	// derived from both revocation basepoints, and need to be handled by
+	// the dual funding workflow, as the pending channel may already be
+	// written to disk.
+	f.resMtx.RLock()
+	if pendingChanID, ok := f.dualFundChanIDs[chanID]; ok {
+		chanID = pendingChanID
+	}
+	f.resMtx.RUnlock()
+
+	dualFundCtx, err := f.getReservationCtx(peerKey, chanID)
+	if err == nil && dualFundCtx.dualFund != nil {
+		fundingErr := fmt.Errorf("received funding error from %x: %v",
+			peerKey.SerializeCompressed(), msg.Error(),
+		)
+		log.Errorf(fundingErr.Error())
+
+		f.failDualFund(dualFundCtx, fundingErr, false)
+		return
+	}
+
 	// First, we'll attempt to retrieve and cancel the funding workflow
 	// that this error was tied to. If we're unable to do so, then we'll
 	// exit early as this was an unwarranted error.
@@ -5113,6 +5543,11 @@ func (f *Manager) pruneZombieReservations() {
 			pendingChanID[:])
 		log.Warnf(err.Error())
 
+		if resCtx.dualFund != nil {
+			f.failDualFund(resCtx, err, true)
+			continue
+		}
+
 		chanID := lnwire.NewChanIDFromOutPoint(
 			*resCtx.reservation.FundingOutpoint(),
 		)
@@ -5165,6 +5600,9 @@ func (f *Manager) cancelReservationCtx(peerKey *btcec.PublicKey,
 	}
 
 	delete(nodeReservations, pendingChanID)
+	if ctx.dualFund != nil {
+		delete(f.dualFundChanIDs, ctx.dualFund.chanID)
+	}
 
 	// If this was the last active reservation for this peer, delete the
 	// peer's entry altogether.
@@ -5188,6 +5626,11 @@ func (f *Manager) deleteReservationCtx(peerKey *btcec.PublicKey,
 		// No reservations for this node.
 		return
 	}
+	if ctx, ok := nodeReservations[pendingChanID]; ok &&
+		ctx.dualFund != nil {
+
+		delete(f.dualFundChanIDs, ctx.dualFund.chanID)
+	}
 	delete(nodeReservations, pendingChanID)
 
 	// If this was the last active reservation for this peer, delete the
@@ -5225,10 +5668,14 @@ func (f *Manager) IsPendingChannel(pendingChanID PendingChanID,
 
 	peerIDKey := newSerializedKey(peer.IdentityKey())
 	f.resMtx.RLock()
+	if tempChanID, ok := f.dualFundChanIDs[pendingChanID]; ok {
+		pendingChanID = tempChanID
+	}
 	_, ok := f.activeReservations[peerIDKey][pendingChanID]
+	pending, signed := f.dualFundPending[pendingChanID]
 	f.resMtx.RUnlock()
 
-	return ok
+	return ok || (signed && pending.peer == peerIDKey)
 }
 
 func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
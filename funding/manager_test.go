@@ -534,6 +534,9 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 
 			return nil
 		},
+		StopWatchingChannel: func(wire.OutPoint) error {
+			return nil
+		},
 		ReportShortChanID: func(wire.OutPoint) error {
 			reportScidChan <- struct{}{}
 			return nil
@@ -558,6 +561,12 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 
 			return nil, nil
 		},
+		DeleteSplicedEdge: func(*channeldb.OpenChannel) (
+			lnwire.ShortChannelID, *models.ChannelEdgePolicy,
+			error) {
+
+			return lnwire.ShortChannelID{}, nil, nil
+		},
 		AliasManager: aliasMgr,
 		// For unit tests we default to false meaning that no funds
 		// originated from the sweeper.
@@ -678,6 +687,7 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		ReservationTimeout:    oldCfg.ReservationTimeout,
 		OpenChannelPredicate:  chainedAcceptor,
 		DeleteAliasEdge:       oldCfg.DeleteAliasEdge,
+		DeleteSplicedEdge:     oldCfg.DeleteSplicedEdge,
 		AliasManager:          oldCfg.AliasManager,
 		AuxLeafStore:          oldCfg.AuxLeafStore,
 		AuxSigner:             oldCfg.AuxSigner,
@@ -4671,6 +4681,7 @@ func TestCommitmentTypeFundmaxSanityCheck(t *testing.T) {
 		"SCRIPT_ENFORCED_LEASE":   4,
 		"SIMPLE_TAPROOT":          5,
 		"SIMPLE_TAPROOT_OVERLAY":  6,
+		"ZERO_FEE_COMMITMENTS":    7,
 	}
 
 	for commitmentType := range lnrpc.CommitmentType_value {
@@ -0,0 +1,621 @@
+package funding
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrInvalidSpliceAmt is returned when a splice request doesn't
+	// specify exactly one of the splice in and splice out amounts.
+	ErrInvalidSpliceAmt = errors.New("exactly one of the splice in and " +
+		"splice out amounts must be set")
+
+	// ErrSpliceNotSupported is returned when a splice is requested, but
+	// the funding manager wasn't configured to support splicing.
+	ErrSpliceNotSupported = errors.New("splicing not supported")
+)
+
+// SpliceReq is a request to splice funds into or out of an active channel.
+type SpliceReq struct {
+	// ChanPoint is the funding outpoint the channel was opened with.
+	ChanPoint wire.OutPoint
+
+	// SpliceInAmt is the amount that is added to the channel.
+	SpliceInAmt btcutil.Amount
+
+	// SpliceOutAmt is the amount that is moved out of the channel to
+	// SpliceOutScript.
+	SpliceOutAmt btcutil.Amount
+
+	// SpliceOutScript is the script the spliced out funds are sent to.
+	SpliceOutScript []byte
+
+	// FeeRate is the fee rate of the splice transaction. For externally
+	// funded splices, the fee rate is determined by the funded PSBT.
+	FeeRate chainfee.SatPerKWeight
+
+	// MinConfs is the minimum number of confirmations the wallet inputs
+	// used for a splice in must have.
+	MinConfs int32
+
+	// ChanFunder is an optional assembler that provisions the inputs of a
+	// splice in. If nil, the wallet funds the splice using coin selection.
+	ChanFunder chanfunding.Assembler
+
+	// PendingChanID is the ID under which the funding intent of a splice
+	// in is registered, which is used to drive the PSBT funding flow. If
+	// not set, a random ID is used.
+	PendingChanID [32]byte
+
+	// Updates is a channel which updates will be sent on as the splice
+	// progresses.
+	Updates chan *lnrpc.SpliceUpdate
+
+	// Err is a channel which errors will be sent on.
+	Err chan error
+}
+
+// splicePlan is a splice transaction that is ready to be negotiated with the
+// remote peer.
+type splicePlan struct {
+	// contribution is our contribution to the splice, which is handed to
+	// the link.
+	contribution *lnwallet.SpliceContribution
+
+	// shouldPublish is false if the caller asked to publish the splice
+	// transaction themselves.
+	shouldPublish bool
+}
+
+// SpliceChannel splices funds into or out of an active channel. The splice
+// transaction is assembled in the background, negotiated with the remote peer
+// through the channel link, and finally published. Progress is reported on
+// the Updates channel of the request, and failures on its Err channel.
+func (f *Manager) SpliceChannel(req *SpliceReq) {
+	f.wg.Add(1)
+	go f.handleSpliceReq(req)
+}
+
+// handleSpliceReq carries out a splice request.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) handleSpliceReq(req *SpliceReq) {
+	defer f.wg.Done()
+
+	if err := f.spliceChannel(req); err != nil {
+		log.Errorf("Unable to splice ChannelPoint(%v): %v",
+			req.ChanPoint, err)
+
+		req.Err <- err
+	}
+}
+
+// SpliceLocked moves the channel with the given outpoint over to the funding
+// output of its splice that was just locked by both parties. The edge of the
+// channel in the graph is replaced by one for the new funding output, which is
+// announced to the network once the splice transaction is six blocks deep.
+func (f *Manager) SpliceLocked(chanPoint wire.OutPoint) {
+	f.wg.Add(1)
+	go f.handleSpliceLocked(chanPoint)
+}
+
+// handleSpliceLocked moves the edge of a spliced channel to its new funding
+// output, and resumes the funding flow of the channel to announce it.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) handleSpliceLocked(chanPoint wire.OutPoint) {
+	defer f.wg.Done()
+
+	channel, err := f.moveSplicedEdge(chanPoint)
+	if err != nil {
+		log.Errorf("Unable to move edge of spliced ChannelPoint(%v): "+
+			"%v", chanPoint, err)
+
+		return
+	}
+
+	// The channel is now in the addedToGraph state again, so the funding
+	// flow will announce the new edge after six confirmations.
+	f.wg.Add(1)
+	go f.advanceFundingState(channel, PendingChanID{}, nil)
+}
+
+// moveSplicedEdge replaces the edge of a spliced channel in the graph by one
+// for the funding output of its latest splice, keeping our policy. The channel
+// is moved back to the addedToGraph state of its funding flow.
+func (f *Manager) moveSplicedEdge(
+	chanPoint wire.OutPoint) (*channeldb.OpenChannel, error) {
+
+	channel, err := f.cfg.ChannelDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel: %w", err)
+	}
+
+	spliceScid, err := channel.SpliceShortChanID().UnwrapOrErr(
+		fmt.Errorf("channel wasn't spliced"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	oldScid, ourPolicy, err := f.cfg.DeleteSplicedEdge(channel)
+	if err != nil {
+		return nil, fmt.Errorf("unable to delete edge: %w", err)
+	}
+
+	// Spliced out funds may leave our former maximum HTLC above what the
+	// channel can carry.
+	capacity := lnwire.NewMSatFromSatoshis(channel.Capacity)
+	if ourPolicy != nil && ourPolicy.MaxHTLC > capacity {
+		ourPolicy.MaxHTLC = capacity
+	}
+
+	// Private zero-conf channels are known to the graph by their alias,
+	// which they keep. The peer gets a ChannelUpdate with its own alias
+	// for them.
+	edgeScid := spliceScid
+	var peerAlias *lnwire.ShortChannelID
+	isPublic := channel.ChannelFlags&lnwire.FFAnnounceChannel != 0
+	if !isPublic && channel.IsZeroConf() {
+		edgeScid = channel.ShortChanID()
+
+		chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+		alias, err := f.cfg.AliasManager.GetPeerAlias(chanID)
+		if err == nil {
+			peerAlias = &alias
+		}
+	}
+
+	log.Infof("Moving ChannelPoint(%v) from short_chan_id=%v to "+
+		"short_chan_id=%v at %v", chanPoint, oldScid, edgeScid,
+		channel.CurrentFundingOutpoint())
+
+	// Forwards under the new SCID must find the channel's link before the
+	// new edge makes it to the graph.
+	err = f.cfg.ReportShortChanID(chanPoint)
+	if err != nil {
+		// This should only fail if the link is not found in the
+		// Switch, in which case it picks up the new SCID once the link
+		// is loaded again.
+		log.Errorf("Unable to report scid for spliced channel: %v",
+			err)
+	}
+
+	err = f.addToGraph(channel, &edgeScid, peerAlias, ourPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("unable to add edge to graph: %w", err)
+	}
+
+	err = f.saveChannelOpeningState(&chanPoint, addedToGraph, &edgeScid)
+	if err != nil {
+		return nil, fmt.Errorf("error setting channel state to "+
+			"addedToGraph: %w", err)
+	}
+
+	return channel, nil
+}
+
+// spliceChannel assembles the splice transaction described by the request,
+// negotiates it with the remote peer and publishes it.
+func (f *Manager) spliceChannel(req *SpliceReq) error {
+	if f.cfg.InitSplice == nil {
+		return ErrSpliceNotSupported
+	}
+
+	spliceIn := req.SpliceInAmt > 0
+	spliceOut := req.SpliceOutAmt > 0
+	if spliceIn == spliceOut {
+		return ErrInvalidSpliceAmt
+	}
+
+	channel, err := f.cfg.ChannelDB.FetchChannel(nil, req.ChanPoint)
+	if err != nil {
+		return fmt.Errorf("unable to fetch channel: %w", err)
+	}
+
+	var zeroID [32]byte
+	if spliceIn && req.PendingChanID == zeroID {
+		if _, err := rand.Read(req.PendingChanID[:]); err != nil {
+			return err
+		}
+	}
+
+	var plan *splicePlan
+	if spliceIn {
+		plan, err = f.assembleSpliceIn(req, channel)
+	} else {
+		plan, err = f.assembleSpliceOut(req, channel)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Splices in have a registered funding intent, which we'll need to
+	// clean up once we're done with it. If the splice fails, the intent is
+	// canceled as well to release any wallet inputs leased for it.
+	spliceFailed := true
+	if spliceIn {
+		defer func() {
+			if !spliceFailed {
+				f.cfg.Wallet.ForgetFundingIntent(
+					req.PendingChanID,
+				)
+				return
+			}
+
+			err := f.cfg.Wallet.CancelFundingIntent(
+				req.PendingChanID,
+			)
+			if err != nil {
+				log.Errorf("Unable to cancel splice intent: "+
+					"%v", err)
+			}
+		}()
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+	resChan, err := f.cfg.InitSplice(chanID, plan.contribution)
+	if err != nil {
+		return err
+	}
+
+	var spliceTx *wire.MsgTx
+	select {
+	case res := <-resChan:
+		spliceTx, err = res.Unpack()
+		if err != nil {
+			return err
+		}
+
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+	spliceFailed = false
+
+	spliceTxid := spliceTx.TxHash()
+	ok, outputIndex := findSpliceOutput(spliceTx, channel, plan)
+	if !ok {
+		return fmt.Errorf("funding output not found in splice tx %v",
+			spliceTxid)
+	}
+
+	log.Infof("Splice tx %v for ChannelPoint(%v) negotiated, new "+
+		"funding output %v:%d", spliceTxid, req.ChanPoint, spliceTxid,
+		outputIndex)
+
+	// The splice tx is persisted by the link, so we'd be able to republish
+	// it later on. We therefore only log failures to publish it.
+	if plan.shouldPublish {
+		label := labels.MakeLabel(labels.LabelTypeSplice, nil)
+		err := f.cfg.PublishTransaction(spliceTx, label)
+		if err != nil {
+			log.Errorf("Unable to broadcast splice tx %v for "+
+				"ChannelPoint(%v): %v", spliceTxid,
+				req.ChanPoint, err)
+		}
+	}
+
+	req.Updates <- &lnrpc.SpliceUpdate{
+		Update: &lnrpc.SpliceUpdate_SplicePending{
+			SplicePending: &lnrpc.PendingUpdate{
+				Txid:        spliceTxid[:],
+				OutputIndex: outputIndex,
+			},
+		},
+	}
+
+	return nil
+}
+
+// findSpliceOutput returns the index of the new funding output within the
+// splice transaction.
+func findSpliceOutput(spliceTx *wire.MsgTx, channel *channeldb.OpenChannel,
+	plan *splicePlan) (bool, uint32) {
+
+	newCapacity := channel.Capacity + plan.contribution.LocalDelta
+	_, fundingOutput, err := spliceFundingOutput(channel, newCapacity)
+	if err != nil {
+		return false, 0
+	}
+
+	return input.FindScriptOutputIndex(spliceTx, fundingOutput.PkScript)
+}
+
+// spliceFundingOutput returns the funding script and the funding output of the
+// channel for the given capacity.
+func spliceFundingOutput(channel *channeldb.OpenChannel,
+	capacity btcutil.Amount) ([]byte, *wire.TxOut, error) {
+
+	localKey := channel.LocalChanCfg.MultiSigKey.PubKey
+	remoteKey := channel.RemoteChanCfg.MultiSigKey.PubKey
+
+	return input.GenFundingPkScript(
+		localKey.SerializeCompressed(), remoteKey.SerializeCompressed(),
+		int64(capacity),
+	)
+}
+
+// assembleSpliceOut creates a splice transaction that moves funds out of the
+// channel. It only spends the current funding output, the amount and the fee
+// are both deducted from our balance.
+func (f *Manager) assembleSpliceOut(req *SpliceReq,
+	channel *channeldb.OpenChannel) (*splicePlan, error) {
+
+	if len(req.SpliceOutScript) == 0 {
+		return nil, fmt.Errorf("no splice out script specified")
+	}
+
+	spliceOutput := &wire.TxOut{
+		PkScript: req.SpliceOutScript,
+		Value:    int64(req.SpliceOutAmt),
+	}
+	err := txrules.CheckOutput(spliceOutput, txrules.DefaultRelayFeePerKb)
+	if err != nil {
+		return nil, fmt.Errorf("invalid splice out: %w", err)
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+	weightEstimate.AddP2WSHOutput()
+	weightEstimate.AddOutput(req.SpliceOutScript)
+	fee := req.FeeRate.FeeForWeight(weightEstimate.Weight())
+
+	newCapacity := channel.Capacity - req.SpliceOutAmt - fee
+	if newCapacity <= 0 {
+		return nil, fmt.Errorf("splice out amount %v exceeds channel "+
+			"capacity %v", req.SpliceOutAmt, channel.Capacity)
+	}
+
+	_, fundingOutput, err := spliceFundingOutput(channel, newCapacity)
+	if err != nil {
+		return nil, err
+	}
+
+	fundingOutpoint := channel.CurrentFundingOutpoint()
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(wire.NewTxIn(&fundingOutpoint, nil, nil))
+	spliceTx.AddTxOut(fundingOutput)
+	spliceTx.AddTxOut(spliceOutput)
+
+	return &splicePlan{
+		contribution: &lnwallet.SpliceContribution{
+			Tx:         spliceTx,
+			PrevTxs:    make(map[wire.OutPoint]*wire.MsgTx),
+			LocalDelta: newCapacity - channel.Capacity,
+			FeeRate:    req.FeeRate,
+
+			// We don't contribute any inputs besides the shared
+			// one, so there's nothing to sign.
+			SignInputs: func(*wire.MsgTx,
+				txscript.PrevOutputFetcher) error {
+
+				return nil
+			},
+		},
+		shouldPublish: true,
+	}, nil
+}
+
+// assembleSpliceIn creates a splice transaction that adds funds to the
+// channel, using either the wallet or an external PSBT for funding.
+func (f *Manager) assembleSpliceIn(req *SpliceReq,
+	channel *channeldb.OpenChannel) (_ *splicePlan, err error) {
+
+	intent, err := f.cfg.Wallet.ProvisionSplice(&lnwallet.SpliceRequest{
+		PendingID:  req.PendingChanID,
+		Channel:    channel,
+		LocalAmt:   req.SpliceInAmt,
+		FeeRate:    req.FeeRate,
+		MinConfs:   req.MinConfs,
+		ChanFunder: req.ChanFunder,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to provision splice: %w", err)
+	}
+
+	// Release the wallet inputs leased for the splice if we fail to
+	// assemble it.
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		cancelErr := f.cfg.Wallet.CancelFundingIntent(req.PendingChanID)
+		if cancelErr != nil {
+			log.Errorf("Unable to cancel splice intent: %v",
+				cancelErr)
+		}
+	}()
+
+	var (
+		spliceTx      *wire.MsgTx
+		prevTxs       = make(map[wire.OutPoint]*wire.MsgTx)
+		feeRate       = req.FeeRate
+		shouldPublish = true
+		signInputs    func(*wire.MsgTx, txscript.PrevOutputFetcher) error
+	)
+	switch intent := intent.(type) {
+	case *chanfunding.PsbtIntent:
+		spliceTx, err = f.waitForSplicePsbt(req, intent)
+		if err != nil {
+			break
+		}
+		shouldPublish = intent.ShouldPublishFundingTX()
+
+		// The user's wallet may have included the previous
+		// transactions of its inputs, which saves us a lookup.
+		for idx, pIn := range intent.PendingPsbt.Inputs {
+			if pIn.NonWitnessUtxo == nil {
+				continue
+			}
+
+			txIn := intent.PendingPsbt.UnsignedTx.TxIn[idx]
+			prevTxs[txIn.PreviousOutPoint] = pIn.NonWitnessUtxo
+		}
+
+	case *chanfunding.FullIntent:
+		spliceTx, err = intent.CompileFundingTx(nil, nil)
+
+		// The remote party may add inputs and outputs of its own, so
+		// the wallet inputs are signed again once the final splice
+		// transaction is known.
+		signInputs = func(tx *wire.MsgTx,
+			prevOuts txscript.PrevOutputFetcher) error {
+
+			return signSpliceInputs(intent, tx, prevOuts)
+		}
+
+	default:
+		err = fmt.Errorf("unsupported splice funding intent %T",
+			intent)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fundingOutpoint := channel.CurrentFundingOutpoint()
+	var inputAmt btcutil.Amount
+	for _, txIn := range spliceTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if prevOut == fundingOutpoint {
+			inputAmt += channel.Capacity
+			continue
+		}
+
+		prevTx, ok := prevTxs[prevOut]
+		if !ok {
+			prevTx, err = f.cfg.Wallet.FetchTx(prevOut.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch "+
+					"previous tx of input %v: %w", prevOut,
+					err)
+			}
+			prevTxs[prevOut] = prevTx
+		}
+
+		if int(prevOut.Index) >= len(prevTx.TxOut) {
+			return nil, fmt.Errorf("invalid previous output %v",
+				prevOut)
+		}
+		inputAmt += btcutil.Amount(prevTx.TxOut[prevOut.Index].Value)
+	}
+
+	// For externally funded splices, the fee rate is determined by the
+	// PSBT, so we'll compute it from the final transaction. The shared
+	// input doesn't carry a witness yet, so its weight is added
+	// separately.
+	if _, ok := intent.(*chanfunding.PsbtIntent); ok {
+		var outputAmt btcutil.Amount
+		for _, txOut := range spliceTx.TxOut {
+			outputAmt += btcutil.Amount(txOut.Value)
+		}
+
+		weight := blockchain.GetTransactionWeight(
+			btcutil.NewTx(spliceTx),
+		) + input.MultiSigWitnessSize
+		feeRate = chainfee.NewSatPerKWeight(
+			inputAmt-outputAmt, lntypes.WeightUnit(weight),
+		)
+	}
+
+	_, fundingOutput, err := intent.FundingOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	return &splicePlan{
+		contribution: &lnwallet.SpliceContribution{
+			Tx:      spliceTx,
+			PrevTxs: prevTxs,
+			LocalDelta: btcutil.Amount(fundingOutput.Value) -
+				channel.Capacity,
+			FeeRate:    feeRate,
+			SignInputs: signInputs,
+		},
+		shouldPublish: shouldPublish,
+	}, nil
+}
+
+// signSpliceInputs signs the wallet inputs of the passed intent that are part
+// of the final splice transaction. Besides our own contribution, the new
+// funding output carries the current capacity and the remote party's
+// contribution, which are both accounted for as the remote amount.
+func signSpliceInputs(intent *chanfunding.FullIntent, spliceTx *wire.MsgTx,
+	prevOuts txscript.PrevOutputFetcher) error {
+
+	_, fundingOutput, err := intent.FundingOutput()
+	if err != nil {
+		return err
+	}
+
+	found, idx := input.FindScriptOutputIndex(
+		spliceTx, fundingOutput.PkScript,
+	)
+	if !found {
+		return fmt.Errorf("funding output not found in splice tx")
+	}
+
+	fundingAmt := btcutil.Amount(spliceTx.TxOut[idx].Value)
+	intent.SetRemoteFundingAmt(fundingAmt - intent.LocalFundingAmt())
+
+	return intent.SignFundingTx(spliceTx, prevOuts)
+}
+
+// waitForSplicePsbt hands the funding parameters of an externally funded
+// splice to the caller and waits until the PSBT has been funded and signed.
+func (f *Manager) waitForSplicePsbt(req *SpliceReq,
+	intent *chanfunding.PsbtIntent) (*wire.MsgTx, error) {
+
+	addr, amt, packet, err := intent.FundingParams()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get splice PSBT params: %w",
+			err)
+	}
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("unable to serialize PSBT: %w", err)
+	}
+
+	req.Updates <- &lnrpc.SpliceUpdate{
+		Update: &lnrpc.SpliceUpdate_PsbtFund{
+			PsbtFund: &lnrpc.ReadyForPsbtFunding{
+				FundingAddress: addr.EncodeAddress(),
+				FundingAmount:  amt,
+				Psbt:           buf.Bytes(),
+			},
+		},
+		PendingChanId: req.PendingChanID[:],
+	}
+
+	select {
+	case err := <-intent.PsbtReady:
+		if err != nil {
+			return nil, fmt.Errorf("error waiting for PSBT: %w",
+				err)
+		}
+
+	case <-f.quit:
+		return nil, ErrFundingManagerShuttingDown
+	}
+
+	return intent.CompileFundingTx()
+}
@@ -0,0 +1,298 @@
+package funding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFundingManagerSpliceOut tests that a splice out creates a splice
+// transaction spending the current funding output, hands it to the link and
+// publishes it once the link has negotiated it.
+func TestFundingManagerSpliceOut(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	localAmt := btcutil.Amount(500000)
+	fundingOutPoint, _ := openChannel(
+		t, alice, bob, localAmt, 0, 1, updateChan, true, nil,
+	)
+
+	// The link is mocked to resolve the splice with the transaction it
+	// was handed.
+	contributions := make(chan *lnwallet.SpliceContribution, 1)
+	alice.fundingMgr.cfg.InitSplice = func(chanID lnwire.ChannelID,
+		c *lnwallet.SpliceContribution) (<-chan fn.Result[*wire.MsgTx],
+		error) {
+
+		require.Equal(
+			t, lnwire.NewChanIDFromOutPoint(*fundingOutPoint),
+			chanID,
+		)
+		contributions <- c
+
+		resChan := make(chan fn.Result[*wire.MsgTx], 1)
+		resChan <- fn.Ok(c.Tx)
+
+		return resChan, nil
+	}
+
+	newReq := func() *SpliceReq {
+		return &SpliceReq{
+			ChanPoint:       *fundingOutPoint,
+			SpliceOutScript: []byte{txscript.OP_TRUE},
+			FeeRate:         chainfee.FeePerKwFloor,
+			Updates:         make(chan *lnrpc.SpliceUpdate, 1),
+			Err:             make(chan error, 1),
+		}
+	}
+
+	// A request must either splice in or splice out.
+	req := newReq()
+	req.SpliceInAmt = 1000
+	req.SpliceOutAmt = 1000
+	alice.fundingMgr.SpliceChannel(req)
+
+	select {
+	case err := <-req.Err:
+		require.ErrorIs(t, err, ErrInvalidSpliceAmt)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("splice request not rejected")
+	}
+
+	// Now we splice out some of Alice's funds.
+	const spliceAmt = btcutil.Amount(100_000)
+	req = newReq()
+	req.SpliceOutAmt = spliceAmt
+	alice.fundingMgr.SpliceChannel(req)
+
+	var contribution *lnwallet.SpliceContribution
+	select {
+	case contribution = <-contributions:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("splice not handed to link")
+	}
+
+	spliceTx := contribution.Tx
+	require.Len(t, spliceTx.TxIn, 1)
+	require.Equal(t, *fundingOutPoint, spliceTx.TxIn[0].PreviousOutPoint)
+	require.Len(t, spliceTx.TxOut, 2)
+	require.EqualValues(t, spliceAmt, spliceTx.TxOut[1].Value)
+
+	// The amount and the fee are deducted from our balance.
+	newCapacity := btcutil.Amount(spliceTx.TxOut[0].Value)
+	require.Equal(t, newCapacity-localAmt, contribution.LocalDelta)
+	require.Less(t, newCapacity, localAmt-spliceAmt)
+
+	select {
+	case update := <-req.Updates:
+		pending := update.GetSplicePending()
+		require.NotNil(t, pending)
+
+		txid := spliceTx.TxHash()
+		require.Equal(t, txid[:], pending.Txid)
+		require.Zero(t, pending.OutputIndex)
+
+	case err := <-req.Err:
+		t.Fatalf("splice failed: %v", err)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("splice not pending")
+	}
+
+	select {
+	case tx := <-alice.publTxChan:
+		require.Equal(t, spliceTx.TxHash(), tx.TxHash())
+	case <-time.After(time.Second * 5):
+		t.Fatalf("splice tx not published")
+	}
+}
+
+// TestFundingManagerSpliceLocked tests that the edge of a channel is moved to
+// the funding output of a locked splice while keeping our policy, and that the
+// new edge is announced once the splice transaction is six blocks deep.
+func TestFundingManagerSpliceLocked(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// Run through the funding flow until the channel is announced.
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	localAmt := btcutil.Amount(500000)
+	fundingOutPoint, fundingTx := openChannel(
+		t, alice, bob, localAmt, 0, 1, updateChan, true, nil,
+	)
+
+	alice.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	bob.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	assertMarkedOpen(t, alice, bob, fundingOutPoint)
+
+	channelReadyAlice := assertFundingMsgSent(
+		t, alice.msgChan, "ChannelReady",
+	).(*lnwire.ChannelReady)
+	channelReadyBob := assertFundingMsgSent(
+		t, bob.msgChan, "ChannelReady",
+	).(*lnwire.ChannelReady)
+	alice.fundingMgr.ProcessFundingMsg(channelReadyBob, bob)
+	bob.fundingMgr.ProcessFundingMsg(channelReadyAlice, alice)
+	assertHandleChannelReady(t, alice, bob)
+
+	assertChannelAnnouncements(t, alice, bob, localAmt, nil, nil, nil, nil)
+	assertAddedToGraph(t, alice, bob, fundingOutPoint)
+	waitForOpenUpdate(t, updateChan)
+
+	alice.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	bob.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	assertAnnouncementSignatures(t, alice, bob)
+	assertNoChannelState(t, alice, bob, fundingOutPoint)
+
+	// Alice now locks a splice out of the channel, which confirmed at
+	// spliceScid.
+	channel, err := alice.fundingMgr.cfg.ChannelDB.FetchChannel(
+		nil, *fundingOutPoint,
+	)
+	require.NoError(t, err)
+
+	const newCapacity = btcutil.Amount(400000)
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{PreviousOutPoint: *fundingOutPoint})
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    int64(newCapacity),
+		PkScript: fundingTx.TxOut[fundingOutPoint.Index].PkScript,
+	})
+	spliceOutPoint := wire.OutPoint{Hash: spliceTx.TxHash()}
+
+	err = channel.AddSpliceCandidate(&channeldb.SpliceCandidate{
+		SpliceTx:         spliceTx,
+		FundingOutpoint:  spliceOutPoint,
+		Capacity:         newCapacity,
+		LocalCommitment:  channel.LocalCommitment,
+		RemoteCommitment: channel.RemoteCommitment,
+	})
+	require.NoError(t, err)
+
+	spliceScid := lnwire.ShortChannelID{BlockHeight: 1000, TxIndex: 1}
+	_, err = channel.LockSplice(spliceTx.TxHash(), spliceScid)
+	require.NoError(t, err)
+
+	// The old edge is deleted along with our policy, which the new edge
+	// keeps. Its maximum HTLC exceeds the new capacity though.
+	ourPolicy := &models.ChannelEdgePolicy{
+		MessageFlags:              lnwire.ChanUpdateRequiredMaxHtlc,
+		TimeLockDelta:             80,
+		MinHTLC:                   1000,
+		MaxHTLC:                   lnwire.NewMSatFromSatoshis(localAmt),
+		FeeBaseMSat:               42,
+		FeeProportionalMillionths: 7,
+	}
+	deletedEdges := make(chan wire.OutPoint, 1)
+	alice.fundingMgr.cfg.DeleteSplicedEdge = func(
+		c *channeldb.OpenChannel) (lnwire.ShortChannelID,
+		*models.ChannelEdgePolicy, error) {
+
+		deletedEdges <- c.FundingOutpoint
+
+		return c.ShortChanID(), ourPolicy, nil
+	}
+
+	alice.fundingMgr.SpliceLocked(*fundingOutPoint)
+
+	select {
+	case op := <-deletedEdges:
+		require.Equal(t, *fundingOutPoint, op)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("old edge not deleted")
+	}
+
+	// The switch learns about the new SCID of the channel.
+	select {
+	case <-alice.reportScidChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("did not call ReportShortChanID in time")
+	}
+
+	// The channel is added to the graph under the new SCID.
+	var ann *lnwire.ChannelAnnouncement1
+	select {
+	case msg := <-alice.announceChan:
+		ann = assertType[*lnwire.ChannelAnnouncement1](t, msg)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("channel announcement not sent")
+	}
+	require.Equal(t, spliceScid, ann.ShortChannelID)
+
+	var update *lnwire.ChannelUpdate1
+	select {
+	case msg := <-alice.announceChan:
+		update = assertType[*lnwire.ChannelUpdate1](t, msg)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("channel update not sent")
+	}
+	require.Equal(t, spliceScid, update.ShortChannelID)
+	require.EqualValues(t, ourPolicy.TimeLockDelta, update.TimeLockDelta)
+	require.Equal(t, ourPolicy.MinHTLC, update.HtlcMinimumMsat)
+	require.EqualValues(t, ourPolicy.FeeBaseMSat, update.BaseFee)
+	require.EqualValues(
+		t, ourPolicy.FeeProportionalMillionths, update.FeeRate,
+	)
+	require.Equal(
+		t, lnwire.NewMSatFromSatoshis(newCapacity),
+		update.HtlcMaximumMsat,
+	)
+
+	// The new edge is announced once the splice transaction is six blocks
+	// deep.
+	assertDatabaseState(t, alice, fundingOutPoint, addedToGraph)
+	alice.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: spliceTx,
+	}
+
+	var gotAnnounceSignatures bool
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-alice.announceChan:
+			annSigs, ok := msg.(*lnwire.AnnounceSignatures1)
+			if !ok {
+				assertType[*lnwire.NodeAnnouncement](t, msg)
+				continue
+			}
+
+			require.Equal(t, spliceScid, annSigs.ShortChannelID)
+			gotAnnounceSignatures = true
+
+		case <-time.After(time.Second * 5):
+			t.Fatalf("channel not announced")
+		}
+	}
+	require.True(t, gotAnnounceSignatures)
+
+	assertErrChannelNotFound(t, alice, fundingOutPoint)
+}
@@ -111,6 +111,11 @@ type Config struct {
 	// IsAlias returns whether a passed ShortChannelID is an alias. This is
 	// only used for our local channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// IsLocalSplice returns whether the passed transaction splices one of
+	// our local channels. The funding output it spends doesn't close the
+	// channel in that case, so its edge must not be pruned.
+	IsLocalSplice func(tx *wire.MsgTx) (bool, error)
 }
 
 // Builder builds and maintains a view of the Lightning Network graph.
@@ -1005,6 +1010,20 @@ func (b *Builder) updateGraphWithClosedChannels(
 	// each tx and input.
 	var spentOutputs []*wire.OutPoint
 	for _, tx := range chainUpdate.Transactions {
+		// A splice of one of our channels spends its funding output,
+		// but the channel stays open. Its edge is moved to the new
+		// funding output once the splice is locked.
+		isSplice, err := b.cfg.IsLocalSplice(tx)
+		if err != nil {
+			return err
+		}
+		if isSplice {
+			log.Debugf("Not pruning funding output spent by "+
+				"splice %v", tx.TxHash())
+
+			continue
+		}
+
 		for _, txIn := range tx.TxIn {
 			spentOutputs = append(spentOutputs,
 				&txIn.PreviousOutPoint)
@@ -353,6 +353,9 @@ func TestWakeUpOnStaleBranch(t *testing.T) {
 		IsAlias: func(scid lnwire.ShortChannelID) bool {
 			return false
 		},
+		IsLocalSplice: func(*wire.MsgTx) (bool, error) {
+			return false, nil
+		},
 	})
 	require.NoError(t, err)
 
@@ -726,6 +729,75 @@ func TestRouterChansClosedOfflinePruneGraph(t *testing.T) {
 	}
 }
 
+// TestSpliceDoesNotPruneGraph tests that the edge of one of our channels isn't
+// pruned from the graph when its funding output is spent by a splice of the
+// channel.
+func TestSpliceDoesNotPruneGraph(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxSingleNode(t, startingBlockHeight)
+
+	const chanValue = 10000
+	fundingTx, chanUtxo, chanID, err := createChannelEdge(ctx,
+		bitcoinKey1.SerializeCompressed(),
+		bitcoinKey2.SerializeCompressed(), chanValue,
+		startingBlockHeight)
+	require.NoError(t, err)
+
+	fundingBlock := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{fundingTx},
+	}
+	ctx.chain.addBlock(fundingBlock, chanID.BlockHeight, chanID.BlockHeight)
+
+	node1 := createTestNode(t)
+	node2 := createTestNode(t)
+	edge := &models.ChannelEdgeInfo{
+		ChannelID:     chanID.ToUint64(),
+		NodeKey1Bytes: node1.PubKeyBytes,
+		NodeKey2Bytes: node2.PubKeyBytes,
+	}
+	copy(edge.BitcoinKey1Bytes[:], bitcoinKey1.SerializeCompressed())
+	copy(edge.BitcoinKey2Bytes[:], bitcoinKey2.SerializeCompressed())
+	require.NoError(t, ctx.builder.AddEdge(edge))
+
+	// The channel is spliced in the next block, which spends its funding
+	// output.
+	spliceTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: *chanUtxo,
+		}},
+		TxOut: []*wire.TxOut{{
+			Value: chanValue * 2,
+		}},
+	}
+	ctx.builder.cfg.IsLocalSplice = func(tx *wire.MsgTx) (bool, error) {
+		return tx.TxHash() == spliceTx.TxHash(), nil
+	}
+
+	const blockHeight = startingBlockHeight + 1
+	spliceBlock := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{spliceTx},
+	}
+	ctx.chain.addBlock(spliceBlock, blockHeight, blockHeight)
+	ctx.chainView.notifyBlock(spliceBlock.Header.BlockHash(), blockHeight,
+		spliceBlock.Transactions, t)
+
+	// Once the block has been processed, the edge must still be part of
+	// the graph.
+	require.Eventually(t, func() bool {
+		_, height, err := ctx.graph.PruneTip()
+		return err == nil && height == blockHeight
+	}, time.Second*5, time.Millisecond*10)
+
+	_, _, hasChan, isZombie, err := ctx.graph.HasChannelEdge(
+		chanID.ToUint64(),
+	)
+	require.NoError(t, err)
+	require.True(t, hasChan)
+	require.False(t, isZombie)
+}
+
 // TestPruneChannelGraphStaleEdges ensures that we properly prune stale edges
 // from the channel graph.
 func TestPruneChannelGraphStaleEdges(t *testing.T) {
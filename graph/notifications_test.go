@@ -1077,6 +1077,9 @@ func (c *testCtx) RestartBuilder(t *testing.T) {
 		IsAlias: func(scid lnwire.ShortChannelID) bool {
 			return false
 		},
+		IsLocalSplice: func(*wire.MsgTx) (bool, error) {
+			return false, nil
+		},
 	})
 	require.NoError(t, err)
 	require.NoError(t, builder.Start())
@@ -1175,6 +1178,9 @@ func createTestCtxFromGraphInstanceAssumeValid(t *testing.T,
 		IsAlias: func(scid lnwire.ShortChannelID) bool {
 			return false
 		},
+		IsLocalSplice: func(*wire.MsgTx) (bool, error) {
+			return false, nil
+		},
 	})
 	require.NoError(t, err)
 	require.NoError(t, graphBuilder.Start())
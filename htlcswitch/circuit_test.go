@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -74,6 +75,10 @@ func initTestExtracter() {
 		panic("did not extract sphinx error encrypter")
 	}
 
+	// The time an htlc was received is not persisted, so we clear it to
+	// be able to compare the extracter with decoded circuits.
+	sphinxExtracter.CreatedAt = time.Time{}
+
 	testExtracter = sphinxExtracter
 
 	// We also set this error extracter on startup, otherwise it will be nil
@@ -0,0 +1,540 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrDynCommitInProgress is returned when a dynamic commitment
+	// negotiation is requested while another one is still in progress.
+	ErrDynCommitInProgress = errors.New(
+		"dynamic commitment negotiation already in progress",
+	)
+
+	// ErrNoDynCommitParams is returned when a dynamic commitment
+	// negotiation is requested without any parameters to change.
+	ErrNoDynCommitParams = errors.New(
+		"no channel parameters to change",
+	)
+
+	// ErrDynCommitNotInitiator is returned when we requested a dynamic
+	// commitment negotiation, but the remote party won the right to
+	// propose changes in the quiescence session.
+	ErrDynCommitNotInitiator = errors.New(
+		"remote party is the quiescence initiator",
+	)
+
+	// ErrDynCommitRejected is returned when the remote party rejects the
+	// parameters we proposed.
+	ErrDynCommitRejected = errors.New(
+		"dynamic commitment proposal rejected",
+	)
+
+	// ErrChanTypeUpgradeUnsupported is returned when an upgrade of the
+	// channel type is requested, but either we or the remote party don't
+	// support the channel type the channel would be upgraded to.
+	ErrChanTypeUpgradeUnsupported = errors.New(
+		"channel type upgrade not supported by both peers",
+	)
+)
+
+// DynCommitParams is the set of channel parameters that can be changed on a
+// live channel by means of a dynamic commitment negotiation. Like their
+// open_channel counterparts, all of them except for the dust limit and the
+// channel type constrain the counterparty of the proposer. Parameters that are
+// not set are left unchanged.
+type DynCommitParams struct {
+	// DustLimit is the dust limit of the commitment transactions of the
+	// proposer. It applies from the next commitment on.
+	DustLimit fn.Option[btcutil.Amount]
+
+	// MaxValueInFlight is the maximum total value of HTLCs the
+	// counterparty may have outstanding towards the proposer.
+	MaxValueInFlight fn.Option[lnwire.MilliSatoshi]
+
+	// ChannelReserve is the amount the counterparty must keep as its
+	// reserve.
+	ChannelReserve fn.Option[btcutil.Amount]
+
+	// CsvDelay is the delay the counterparty must wait before it can
+	// sweep its funds after a unilateral close. It applies from the next
+	// commitment of the counterparty on.
+	CsvDelay fn.Option[uint16]
+
+	// MaxAcceptedHTLCs is the maximum number of HTLCs the counterparty
+	// may offer the proposer.
+	MaxAcceptedHTLCs fn.Option[uint16]
+
+	// ChannelType is the channel type the channel is upgraded to. It
+	// applies from the next commitments of both parties on. Only upgrades
+	// that keep the funding output are supported, see
+	// lnwallet.AnchorsUpgradeChanType.
+	ChannelType fn.Option[channeldb.ChannelType]
+}
+
+// IsEmpty returns true if none of the parameters are set.
+func (p DynCommitParams) IsEmpty() bool {
+	return p.DustLimit.IsNone() && p.MaxValueInFlight.IsNone() &&
+		p.ChannelReserve.IsNone() && p.CsvDelay.IsNone() &&
+		p.MaxAcceptedHTLCs.IsNone() && p.ChannelType.IsNone()
+}
+
+// apply returns copies of the passed local and remote channel configs with
+// the parameters proposed by the given party that are set replaced, along
+// with the channel type that results from the passed one.
+func (p DynCommitParams) apply(proposer lntypes.ChannelParty, local,
+	remote channeldb.ChannelConfig, chanType channeldb.ChannelType) (
+	channeldb.ChannelConfig, channeldb.ChannelConfig,
+	channeldb.ChannelType) {
+
+	proposerCfg, counterpartyCfg := &local, &remote
+	if proposer.IsRemote() {
+		proposerCfg, counterpartyCfg = &remote, &local
+	}
+
+	p.DustLimit.WhenSome(func(v btcutil.Amount) {
+		proposerCfg.DustLimit = v
+	})
+	p.MaxValueInFlight.WhenSome(func(v lnwire.MilliSatoshi) {
+		counterpartyCfg.MaxPendingAmount = v
+	})
+	p.ChannelReserve.WhenSome(func(v btcutil.Amount) {
+		counterpartyCfg.ChanReserve = v
+	})
+	p.CsvDelay.WhenSome(func(v uint16) {
+		counterpartyCfg.CsvDelay = v
+	})
+	p.MaxAcceptedHTLCs.WhenSome(func(v uint16) {
+		counterpartyCfg.MaxAcceptedHtlcs = v
+	})
+
+	return local, remote, p.ChannelType.UnwrapOr(chanType)
+}
+
+// DynCommitReq is the type used to request that a link initiate a dynamic
+// commitment negotiation. It is resolved with a nil error once both parties
+// have agreed to and applied the new parameters.
+type DynCommitReq = fn.Req[DynCommitParams, error]
+
+// dynCommitSession tracks a dynamic commitment negotiation that we initiated.
+type dynCommitSession struct {
+	// req is the request that started the negotiation, which we'll
+	// resolve once it completes.
+	req DynCommitReq
+
+	// stfuRes delivers the outcome of quiescing the channel, which has to
+	// happen before we can propose the new parameters.
+	stfuRes <-chan fn.Result[lntypes.ChannelParty]
+
+	// proposed is true once we've sent our DynPropose to the remote party.
+	proposed bool
+}
+
+// ProposeDynCommit requests that the link negotiate a change of channel
+// parameters with the remote party. The channel will be quiesced for the
+// duration of the negotiation. The returned channel is resolved once the new
+// parameters are in effect, or the negotiation definitively fails.
+func (l *channelLink) ProposeDynCommit(params DynCommitParams) <-chan error {
+	req, out := fn.NewReq[DynCommitParams, error](params)
+
+	select {
+	case l.dynCommitReqs <- req:
+	case <-l.Quit:
+		req.Resolve(ErrLinkShuttingDown)
+	}
+
+	return out
+}
+
+// handleDynCommitReq starts a new dynamic commitment negotiation by asking the
+// remote party to quiesce the channel.
+func (l *channelLink) handleDynCommitReq(req DynCommitReq) {
+	if l.dynCommit != nil || l.splice != nil {
+		req.Resolve(ErrDynCommitInProgress)
+		return
+	}
+
+	params := req.Request
+	if params.IsEmpty() {
+		req.Resolve(ErrNoDynCommitParams)
+		return
+	}
+
+	if params.ChannelType.IsSome() && l.cfg.DisallowChanTypeUpgrade {
+		req.Resolve(ErrChanTypeUpgradeUnsupported)
+		return
+	}
+
+	// Before involving the remote party, we make sure that we'd be able
+	// to apply the new parameters ourselves.
+	state := l.channel.State()
+	local, remote, chanType := params.apply(
+		lntypes.Local, state.LocalChanCfg, state.RemoteChanCfg,
+		state.ChanType,
+	)
+	err := l.channel.ValidateChannelParams(
+		local.ChannelStateBounds, remote.ChannelStateBounds,
+		local.CommitmentParams, remote.CommitmentParams, chanType,
+	)
+	if err != nil {
+		req.Resolve(err)
+		return
+	}
+
+	stfuReq, stfuRes := fn.NewReq[fn.Unit, fn.Result[lntypes.ChannelParty]](
+		fn.Unit{},
+	)
+	l.quiescer.InitStfu(stfuReq)
+
+	l.dynCommit = &dynCommitSession{
+		req:     req,
+		stfuRes: stfuRes,
+	}
+
+	if err := l.sendOwedStfu(); err != nil {
+		l.stfuFailf("unable to send stfu: %v", err)
+		return
+	}
+
+	l.maybeSendDynPropose()
+}
+
+// maybeSendDynPropose sends our DynPropose to the remote party once the
+// channel has become quiescent with us as the initiator.
+func (l *channelLink) maybeSendDynPropose() {
+	session := l.dynCommit
+	if session == nil || session.proposed {
+		return
+	}
+
+	var res fn.Result[lntypes.ChannelParty]
+	select {
+	case res = <-session.stfuRes:
+	default:
+		// The channel isn't quiescent yet.
+		return
+	}
+
+	initiator, err := res.Unpack()
+	if err != nil {
+		l.resolveDynCommit(err)
+		return
+	}
+
+	// Only the initiator of the quiescence session may propose new
+	// parameters. If both of us attempted to quiesce the channel at the
+	// same time and the remote party won, we'll wait for their proposal
+	// instead.
+	if initiator != lntypes.Local {
+		l.resolveDynCommit(ErrDynCommitNotInitiator)
+		return
+	}
+
+	params := session.req.Request
+	propose := &lnwire.DynPropose{
+		ChanID:           l.ChanID(),
+		Initiator:        true,
+		DustLimit:        params.DustLimit,
+		MaxValueInFlight: params.MaxValueInFlight,
+		ChannelReserve:   params.ChannelReserve,
+		CsvDelay:         params.CsvDelay,
+		MaxAcceptedHTLCs: params.MaxAcceptedHTLCs,
+		ChannelType:      fn.MapOption(wireChanType)(params.ChannelType),
+	}
+	if err := l.cfg.Peer.SendMessage(false, propose); err != nil {
+		l.resolveDynCommit(err)
+		l.quiescer.Resume()
+
+		return
+	}
+
+	session.proposed = true
+}
+
+// resolveDynCommit resolves the active dynamic commitment request, if any,
+// with the passed error.
+func (l *channelLink) resolveDynCommit(err error) {
+	if l.dynCommit == nil {
+		return
+	}
+
+	l.dynCommit.req.Resolve(err)
+	l.dynCommit = nil
+}
+
+// handleDynPropose processes the parameters proposed by the remote party,
+// which must be the initiator of the current quiescence session. We respond
+// with either a DynAck after applying the new parameters, or a DynReject, and
+// resume the channel in both cases.
+func (l *channelLink) handleDynPropose(msg *lnwire.DynPropose) {
+	initiator, err := l.quiescer.QuiescenceInitiator().Unpack()
+	switch {
+	case err != nil:
+		l.dynCommitFailf("dyn_propose received while not quiescent")
+		return
+
+	case initiator != lntypes.Remote || !msg.Initiator:
+		l.dynCommitFailf("dyn_propose received from non-initiator")
+		return
+	}
+
+	// If we were waiting to contribute to a splice of the remote party,
+	// the quiescence session is used for something else.
+	l.maybeSendSpliceInit()
+	if l.splice != nil && l.splice.state == spliceAwaitInit {
+		l.resolveSplice(ErrSpliceNotInitiator)
+	}
+
+	// The only channel type we can switch to is the upgrade of the
+	// current one, and only if both of us support it.
+	upgradeType := fn.None[channeldb.ChannelType]()
+	if !l.cfg.DisallowChanTypeUpgrade {
+		upgradeType = fn.Some(lnwallet.AnchorsUpgradeChanType(
+			l.channel.State().ChanType,
+		))
+	}
+
+	rejections := validateDynPropose(
+		msg, l.cfg.MaxLocalCSVDelay, upgradeType,
+	)
+	if rejections.IsEmpty() {
+		params := DynCommitParams{
+			DustLimit:        msg.DustLimit,
+			MaxValueInFlight: msg.MaxValueInFlight,
+			ChannelReserve:   msg.ChannelReserve,
+			CsvDelay:         msg.CsvDelay,
+			MaxAcceptedHTLCs: msg.MaxAcceptedHTLCs,
+		}
+		if msg.ChannelType.IsSome() {
+			params.ChannelType = upgradeType
+		}
+
+		err := l.applyDynCommitParams(lntypes.Remote, params)
+		if err != nil {
+			l.log.Infof("rejecting dyn_propose: %v", err)
+
+			rejections = dynProposeRecordTypes(msg)
+		}
+	}
+
+	var reply lnwire.Message = &lnwire.DynAck{
+		ChanID: l.ChanID(),
+	}
+	if !rejections.IsEmpty() {
+		reply = &lnwire.DynReject{
+			ChanID:           l.ChanID(),
+			UpdateRejections: *rejections,
+		}
+	}
+
+	if err := l.cfg.Peer.SendMessage(false, reply); err != nil {
+		l.log.Errorf("unable to send %v: %v", reply.MsgType(), err)
+	}
+
+	l.quiescer.Resume()
+}
+
+// handleDynAck applies the parameters we proposed once the remote party has
+// accepted them, and resumes the channel.
+func (l *channelLink) handleDynAck() {
+	if l.dynCommit == nil || !l.dynCommit.proposed {
+		l.dynCommitFailf("unexpected dyn_ack")
+		return
+	}
+
+	params := l.dynCommit.req.Request
+	if err := l.applyDynCommitParams(lntypes.Local, params); err != nil {
+		l.resolveDynCommit(err)
+		l.dynCommitFailf("unable to apply accepted parameters: %v",
+			err)
+
+		return
+	}
+
+	state := l.channel.State()
+	l.log.Infof("dynamic commitment negotiation complete, local: %v %v, "+
+		"remote: %v %v", state.LocalChanCfg.ChannelStateBounds,
+		state.LocalChanCfg.CommitmentParams,
+		state.RemoteChanCfg.ChannelStateBounds,
+		state.RemoteChanCfg.CommitmentParams)
+
+	l.resolveDynCommit(nil)
+	l.quiescer.Resume()
+}
+
+// applyDynCommitParams validates and persists the parameters proposed by the
+// given party. The new bounds apply to all subsequent updates, and the new
+// commitment params to the next commitment of each party.
+func (l *channelLink) applyDynCommitParams(proposer lntypes.ChannelParty,
+	params DynCommitParams) error {
+
+	state := l.channel.State()
+	local, remote, chanType := params.apply(
+		proposer, state.LocalChanCfg, state.RemoteChanCfg,
+		state.ChanType,
+	)
+
+	return l.channel.UpdateChannelParams(
+		local.ChannelStateBounds, remote.ChannelStateBounds,
+		local.CommitmentParams, remote.CommitmentParams, chanType,
+	)
+}
+
+// handleDynReject resolves our dynamic commitment request with an error once
+// the remote party rejects the parameters we proposed, and resumes the
+// channel.
+func (l *channelLink) handleDynReject(msg *lnwire.DynReject) {
+	if l.dynCommit == nil || !l.dynCommit.proposed {
+		l.dynCommitFailf("unexpected dyn_reject")
+		return
+	}
+
+	l.resolveDynCommit(fmt.Errorf("%w: rejected record types %v",
+		ErrDynCommitRejected, rejectedRecordTypes(
+			&msg.UpdateRejections,
+		)))
+	l.quiescer.Resume()
+}
+
+// dynCommitFailf fails the link in the case where the requirements of the
+// dynamic commitment negotiation are violated. As with violations of the
+// quiescence protocol, only link state is affected, so we merely drop the
+// connection.
+func (l *channelLink) dynCommitFailf(format string, args ...interface{}) {
+	l.failf(LinkFailureError{
+		code:             ErrDynCommitViolation,
+		FailureAction:    LinkFailureDisconnect,
+		PermanentFailure: false,
+		Warning:          true,
+	}, format, args...)
+}
+
+// validateDynPropose checks whether we support changing all the parameters
+// contained in the passed DynPropose, and whether a proposed CSV delay for our
+// own funds is acceptable. A proposed channel type must match the passed
+// upgradeType, which is the only type the channel can be switched to, if any.
+// The set of TLV record types we can't accept is returned as a feature vector,
+// as expected by DynReject.
+//
+// NOTE: Changing the funding key requires a new funding output, which isn't
+// currently supported, so we'll reject it. The same goes for the kickoff fee
+// rate, which is only used for channel types that require a new funding
+// output.
+func validateDynPropose(msg *lnwire.DynPropose, maxLocalCSVDelay uint16,
+	upgradeType fn.Option[channeldb.ChannelType]) *lnwire.RawFeatureVector {
+
+	rejections := lnwire.NewRawFeatureVector()
+
+	reject := func(recordType tlv.Type) {
+		rejections.Set(lnwire.FeatureBit(recordType))
+	}
+
+	msg.CsvDelay.WhenSome(func(csvDelay uint16) {
+		if csvDelay > maxLocalCSVDelay {
+			reject(lnwire.DPToSelfDelay)
+		}
+	})
+	if msg.FundingKey.IsSome() {
+		reject(lnwire.DPFundingPubkey)
+	}
+	msg.ChannelType.WhenSome(func(proposed lnwire.ChannelType) {
+		supported := fn.MapOptionZ(
+			upgradeType, func(chanType channeldb.ChannelType) bool {
+				expected := lnwire.RawFeatureVector(
+					wireChanType(chanType),
+				)
+				actual := lnwire.RawFeatureVector(proposed)
+
+				return expected.Equals(&actual)
+			},
+		)
+		if !supported {
+			reject(lnwire.DPChannelType)
+		}
+	})
+	if msg.KickoffFeerate.IsSome() {
+		reject(lnwire.DPKickoffFeerate)
+	}
+
+	return rejections
+}
+
+// wireChanType returns the channel type feature vector that is sent on the
+// wire for the passed channel type. Only the types a channel can be upgraded
+// to through a dynamic commitment negotiation are supported.
+func wireChanType(chanType channeldb.ChannelType) lnwire.ChannelType {
+	features := lnwire.NewRawFeatureVector()
+	if chanType.IsTweakless() {
+		features.Set(lnwire.StaticRemoteKeyRequired)
+	}
+	if chanType.HasAnchors() && chanType.ZeroHtlcTxFee() {
+		features.Set(lnwire.AnchorsZeroFeeHtlcTxRequired)
+	}
+	if chanType.HasLeaseExpiration() {
+		features.Set(lnwire.ScriptEnforcedLeaseRequired)
+	}
+	if chanType.HasScidAliasChan() {
+		features.Set(lnwire.ScidAliasRequired)
+	}
+	if chanType.HasZeroConf() {
+		features.Set(lnwire.ZeroConfRequired)
+	}
+
+	return lnwire.ChannelType(*features)
+}
+
+// dynProposeRecordTypes returns the TLV record types of all the parameters
+// contained in the passed DynPropose as a feature vector.
+func dynProposeRecordTypes(msg *lnwire.DynPropose) *lnwire.RawFeatureVector {
+	rejections := validateDynPropose(
+		msg, math.MaxUint16, fn.None[channeldb.ChannelType](),
+	)
+
+	if msg.DustLimit.IsSome() {
+		rejections.Set(lnwire.FeatureBit(lnwire.DPDustLimitSatoshis))
+	}
+
+	if msg.MaxValueInFlight.IsSome() {
+		rejections.Set(lnwire.FeatureBit(
+			lnwire.DPMaxHtlcValueInFlightMsat,
+		))
+	}
+	if msg.ChannelReserve.IsSome() {
+		rejections.Set(lnwire.FeatureBit(
+			lnwire.DPChannelReserveSatoshis,
+		))
+	}
+	if msg.CsvDelay.IsSome() {
+		rejections.Set(lnwire.FeatureBit(lnwire.DPToSelfDelay))
+	}
+	if msg.MaxAcceptedHTLCs.IsSome() {
+		rejections.Set(lnwire.FeatureBit(lnwire.DPMaxAcceptedHtlcs))
+	}
+
+	return rejections
+}
+
+// rejectedRecordTypes returns the DynPropose TLV record types that are set in
+// the passed rejection vector.
+func rejectedRecordTypes(rejections *lnwire.RawFeatureVector) []tlv.Type {
+	var recordTypes []tlv.Type
+	for recordType := lnwire.DPDustLimitSatoshis; recordType <=
+		lnwire.DPKickoffFeerate; recordType++ {
+
+		if rejections.IsSet(lnwire.FeatureBit(recordType)) {
+			recordTypes = append(recordTypes, recordType)
+		}
+	}
+
+	return recordTypes
+}
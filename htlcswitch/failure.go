@@ -3,6 +3,7 @@ package htlcswitch
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
@@ -92,6 +93,11 @@ type ForwardingError struct {
 	// be nil in the case where we fail to decode failure message sent by
 	// a peer.
 	msg lnwire.FailureMessage
+
+	// HoldTimes are the hold times reported in the attribution data of
+	// the failure by the hops up to the source of the failure, in route
+	// order. It is nil if the failure didn't carry valid attribution data.
+	HoldTimes []time.Duration
 }
 
 // WireMessage extracts a valid wire failure message from an internal
@@ -133,14 +139,51 @@ func NewUnknownForwardingError(index int) *ForwardingError {
 	}
 }
 
+// UnreadableFailureError is returned when a failure can't be decrypted, but
+// its attribution data tells us which pair of hops tampered with it.
+type UnreadableFailureError struct {
+	// FailingHopIdx is the index of the node in the route whose HMAC in
+	// the attribution data was invalid. Either this node or the one
+	// before it tampered with the failure. Index zero is the self node,
+	// which is never failing.
+	FailingHopIdx int
+
+	// HoldTimes are the hold times reported by the hops before the
+	// failing hop, in route order.
+	HoldTimes []time.Duration
+}
+
+// Error implements the built-in error interface.
+func (u *UnreadableFailureError) Error() string {
+	return fmt.Sprintf("%v@%v", ErrUnreadableFailureMessage,
+		u.FailingHopIdx)
+}
+
+// Unwrap returns ErrUnreadableFailureMessage, so that attributed unreadable
+// failures are handled like any other unreadable failure.
+func (u *UnreadableFailureError) Unwrap() error {
+	return ErrUnreadableFailureMessage
+}
+
+// attrDataRecord returns the attribution data record of a failure, which is
+// only set if there is attribution data to pass back.
+func attrDataRecord(attrData []byte) lnwire.AttrDataRecord {
+	if len(attrData) == 0 {
+		return lnwire.AttrDataRecord{}
+	}
+
+	return lnwire.SomeAttrData(attrData)
+}
+
 // ErrorDecrypter is an interface that is used to decrypt the onion encrypted
 // failure reason an extra out a well formed error.
 type ErrorDecrypter interface {
 	// DecryptError peels off each layer of onion encryption from the first
 	// hop, to the source of the error. A fully populated
 	// lnwire.FailureMessage is returned along with the source of the
-	// error.
-	DecryptError(lnwire.OpaqueReason) (*ForwardingError, error)
+	// error. The attribution data of the failure, if any, is used to
+	// attribute failures that can't be decrypted.
+	DecryptError(lnwire.OpaqueReason, []byte) (*ForwardingError, error)
 }
 
 // UnknownEncrypterType is an error message used to signal that an unexpected
@@ -167,6 +210,11 @@ type OnionErrorDecrypter interface {
 // returned errors to concrete lnwire.FailureMessage instances.
 type SphinxErrorDecrypter struct {
 	OnionErrorDecrypter
+
+	// Circuit is the circuit of the payment, which is used to verify the
+	// attribution data of failures. If it is nil, attribution data is
+	// ignored.
+	Circuit *sphinx.Circuit
 }
 
 // DecryptError peels off each layer of onion encryption from the first hop, to
@@ -174,23 +222,78 @@ type SphinxErrorDecrypter struct {
 // along with the source of the error.
 //
 // NOTE: Part of the ErrorDecrypter interface.
-func (s *SphinxErrorDecrypter) DecryptError(reason lnwire.OpaqueReason) (
-	*ForwardingError, error) {
+func (s *SphinxErrorDecrypter) DecryptError(reason lnwire.OpaqueReason,
+	attrData []byte) (*ForwardingError, error) {
 
 	failure, err := s.OnionErrorDecrypter.DecryptError(reason)
 	if err != nil {
-		return nil, err
+		// Without a valid failure, the hops whose HMACs are valid are
+		// known to have passed the failure back as they received it.
+		// The first hop with an invalid HMAC, or the last hop if all
+		// of them are valid, is responsible for the garbled failure.
+		attribution := s.decryptAttribution(reason, attrData, 0)
+		if attribution == nil {
+			return nil, err
+		}
+
+		failingHop := attribution.ValidHops + 1
+		if failingHop > len(s.Circuit.PaymentPath) {
+			failingHop = len(s.Circuit.PaymentPath)
+		}
+
+		return nil, &UnreadableFailureError{
+			FailingHopIdx: failingHop,
+			HoldTimes:     attribution.HoldTimes,
+		}
 	}
 
 	// Decode the failure. If an error occurs, we leave the failure message
 	// field nil.
+	var fwdErr *ForwardingError
 	r := bytes.NewReader(failure.Message)
 	failureMsg, err := lnwire.DecodeFailure(r, 0)
 	if err != nil {
-		return NewUnknownForwardingError(failure.SenderIdx), nil
+		fwdErr = NewUnknownForwardingError(failure.SenderIdx)
+	} else {
+		fwdErr = NewForwardingError(failureMsg, failure.SenderIdx)
+	}
+
+	// The hops after the source of the failure never saw it, so we only
+	// verify the attribution data up to the source. We only report hold
+	// times if the HMACs of all of them are valid.
+	attribution := s.decryptAttribution(
+		reason, attrData, failure.SenderIdx,
+	)
+	if attribution != nil && attribution.ValidHops == failure.SenderIdx {
+		fwdErr.HoldTimes = attribution.HoldTimes
+	}
+
+	return fwdErr, nil
+}
+
+// decryptAttribution verifies the attribution data of a failure for the given
+// number of hops, or all hops if it is zero. It returns nil if there is no
+// attribution data to verify.
+func (s *SphinxErrorDecrypter) decryptAttribution(reason lnwire.OpaqueReason,
+	attrData []byte, numHops int) *hop.Attribution {
+
+	if s.Circuit == nil || len(attrData) == 0 {
+		return nil
+	}
+
+	if numHops == 0 {
+		numHops = len(s.Circuit.PaymentPath)
+	}
+
+	attribution, err := hop.DecryptAttribution(
+		s.Circuit, reason, attrData, numHops,
+	)
+	if err != nil {
+		log.Debugf("Unable to decrypt attribution data: %v", err)
+		return nil
 	}
 
-	return NewForwardingError(failureMsg, failure.SenderIdx), nil
+	return attribution
 }
 
 // A compile time check to ensure ErrorDecrypter implements the Deobfuscator
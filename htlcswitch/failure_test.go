@@ -56,7 +56,7 @@ func TestLongFailureMessage(t *testing.T) {
 	}
 
 	// Assert that the failure message can still be extracted.
-	failure, err := errorDecryptor.DecryptError(reason)
+	failure, err := errorDecryptor.DecryptError(reason, nil)
 	require.NoError(t, err)
 
 	incorrectDetails, ok := failure.msg.(*lnwire.FailIncorrectDetails)
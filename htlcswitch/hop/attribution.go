@@ -0,0 +1,251 @@
+package hop
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	sphinx "github.com/lightningnetwork/lightning-onion"
+)
+
+const (
+	// AttrMaxHops is the maximum number of hops that attribution data can
+	// account for.
+	AttrMaxHops = 20
+
+	// HoldTimeUnit is the unit in which hops report their hold times.
+	HoldTimeUnit = 100 * time.Millisecond
+
+	// attrHoldTimeSize is the size of a single hold time.
+	attrHoldTimeSize = 4
+
+	// attrHmacSize is the size of a single truncated attribution HMAC.
+	attrHmacSize = 4
+
+	// attrNumHmacs is the number of HMACs in the attribution data. Every
+	// hop adds one HMAC for each position it may have in the route, and
+	// covers the HMACs of the downstream hops for the positions that
+	// follow from it.
+	attrNumHmacs = AttrMaxHops * (AttrMaxHops + 1) / 2
+
+	// attrHoldTimesSize is the size of the hold times of all hops.
+	attrHoldTimesSize = AttrMaxHops * attrHoldTimeSize
+
+	// AttributionDataSize is the size of the attribution data that is
+	// attached to a failure.
+	AttributionDataSize = attrHoldTimesSize + attrNumHmacs*attrHmacSize
+)
+
+var (
+	// ErrInvalidAttributionData is returned when the attribution data of
+	// a failure doesn't have the expected size.
+	ErrInvalidAttributionData = errors.New("invalid attribution data")
+)
+
+// Attribution is what the sender learns from the attribution data of a
+// failure.
+type Attribution struct {
+	// HoldTimes are the hold times reported by the hops whose HMACs were
+	// valid, in route order. The hold time of a hop includes the hold
+	// times of all hops after it.
+	HoldTimes []time.Duration
+
+	// ValidHops is the number of hops, starting from the first hop, whose
+	// HMACs were valid. If this is less than the number of verified hops,
+	// either the hop at this index or the hop before it tampered with the
+	// failure.
+	ValidHops int
+}
+
+// holdTimeUnits converts the time that passed since the htlc was received
+// into the units that are reported in attribution data.
+func holdTimeUnits(createdAt time.Time) uint32 {
+	// The time an htlc was received is not persisted, so we report a zero
+	// hold time for htlcs that we received before a restart.
+	if createdAt.IsZero() {
+		return 0
+	}
+
+	units := time.Since(createdAt) / HoldTimeUnit
+	switch {
+	case units < 0:
+		return 0
+
+	case units > math.MaxUint32:
+		return math.MaxUint32
+	}
+
+	return uint32(units)
+}
+
+// hmacIndex returns the index of the HMAC that the hop at the given distance
+// downstream made for the given position in the route. The HMACs of the hop
+// at distance d only cover the positions d and higher, as it has at least d
+// hops in front of it.
+func hmacIndex(distance, position int) int {
+	return distance*AttrMaxHops - distance*(distance-1)/2 +
+		position - distance
+}
+
+// hmacSlice returns the slice of the attribution data that holds the HMAC of
+// the hop at the given distance for the given position.
+func hmacSlice(data []byte, distance, position int) []byte {
+	start := attrHoldTimesSize + hmacIndex(distance, position)*attrHmacSize
+
+	return data[start : start+attrHmacSize]
+}
+
+// attributionHmac computes the HMAC that a hop at the given position adds to
+// the attribution data. It commits to the failure reason, the hold times of
+// the hop and all downstream hops, and the HMACs the downstream hops made for
+// their positions relative to this one.
+func attributionHmac(key [32]byte, reason, data []byte,
+	position int) []byte {
+
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(reason)
+
+	numHops := AttrMaxHops - position
+	mac.Write(data[:numHops*attrHoldTimeSize])
+	for distance := 1; distance < numHops; distance++ {
+		mac.Write(hmacSlice(data, distance, position+distance))
+	}
+
+	return mac.Sum(nil)[:attrHmacSize]
+}
+
+// addAttribution adds our hold time and HMACs to the attribution data of the
+// given failure reason. The reason must be the one we send back, including our
+// own layer of encryption. Attribution data with an invalid size, which is
+// what we get from a downstream peer that doesn't support it, is replaced by
+// zeroes, so that the sender can still attribute the failure to our position.
+func addAttribution(secret [32]byte, reason, attrData []byte,
+	holdTime uint32) ([]byte, error) {
+
+	data := make([]byte, AttributionDataSize)
+	if len(attrData) == AttributionDataSize {
+		shiftAttribution(data, attrData)
+	}
+
+	binary.BigEndian.PutUint32(data, holdTime)
+
+	key := GenerateKey("um", secret)
+	for position := 0; position < AttrMaxHops; position++ {
+		copy(
+			hmacSlice(data, 0, position),
+			attributionHmac(key, reason, data, position),
+		)
+	}
+
+	if err := xorCipherStream("ammagext", secret, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// shiftAttribution moves the attribution data of the downstream hops one hop
+// further away, making room for our own hold time and HMACs. The data of the
+// hop that would end up beyond the maximum number of hops is dropped, as are
+// the HMACs of the downstream hops for positions they can no longer have.
+func shiftAttribution(dst, src []byte) {
+	copy(
+		dst[attrHoldTimeSize:attrHoldTimesSize],
+		src[:attrHoldTimesSize-attrHoldTimeSize],
+	)
+
+	for distance := 1; distance < AttrMaxHops; distance++ {
+		for position := distance; position < AttrMaxHops; position++ {
+			copy(
+				hmacSlice(dst, distance, position),
+				hmacSlice(src, distance-1, position),
+			)
+		}
+	}
+}
+
+// unshiftAttribution reverses shiftAttribution, so that the data of the next
+// hop is at the front again. The data that was dropped while shifting is left
+// zero, and is not needed to verify the remaining hops.
+func unshiftAttribution(data []byte) []byte {
+	next := make([]byte, AttributionDataSize)
+
+	copy(
+		next[:attrHoldTimesSize-attrHoldTimeSize],
+		data[attrHoldTimeSize:attrHoldTimesSize],
+	)
+
+	for distance := 1; distance < AttrMaxHops; distance++ {
+		for position := distance; position < AttrMaxHops; position++ {
+			copy(
+				hmacSlice(next, distance-1, position),
+				hmacSlice(data, distance, position),
+			)
+		}
+	}
+
+	return next
+}
+
+// DecryptAttribution verifies the attribution data of a failure that was
+// received for a payment along the given circuit, starting from the first
+// hop. It stops at the first hop whose HMAC is invalid, or after numHops
+// hops. For a failure that could be decrypted, numHops is the index of the
+// hop that reported the failure plus one. The hops after it never saw the
+// failure.
+func DecryptAttribution(circuit *sphinx.Circuit, reason, attrData []byte,
+	numHops int) (*Attribution, error) {
+
+	if len(attrData) != AttributionDataSize {
+		return nil, ErrInvalidAttributionData
+	}
+
+	if numHops > len(circuit.PaymentPath) {
+		numHops = len(circuit.PaymentPath)
+	}
+	if numHops > AttrMaxHops {
+		numHops = AttrMaxHops
+	}
+
+	secrets, err := SharedSecrets(circuit.PaymentPath, circuit.SessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		attribution Attribution
+		data        = append([]byte(nil), attrData...)
+		msg         = append([]byte(nil), reason...)
+	)
+	for i := 0; i < numHops; i++ {
+		secret := secrets[i]
+		if err := xorCipherStream("ammagext", secret, data); err != nil {
+			return nil, err
+		}
+
+		// The hop computed its HMAC over the reason it sent back, which
+		// is the reason we received minus the encryption layers of the
+		// hops in front of it.
+		key := GenerateKey("um", secret)
+		expected := attributionHmac(key, msg, data, i)
+		if !hmac.Equal(expected, hmacSlice(data, 0, i)) {
+			break
+		}
+
+		holdTime := time.Duration(binary.BigEndian.Uint32(data))
+		attribution.HoldTimes = append(
+			attribution.HoldTimes, holdTime*HoldTimeUnit,
+		)
+		attribution.ValidHops++
+
+		if err := xorCipherStream("ammag", secret, msg); err != nil {
+			return nil, err
+		}
+		data = unshiftAttribution(data)
+	}
+
+	return &attribution, nil
+}
@@ -0,0 +1,286 @@
+package hop
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAttributionCircuit creates a circuit of the given number of hops, and
+// the error encrypters of these hops. Every hop has held the htlc one second
+// longer than the hop after it.
+func newTestAttributionCircuit(t *testing.T,
+	numHops int) (*sphinx.Circuit, []*SphinxErrorEncrypter) {
+
+	t.Helper()
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	circuit := &sphinx.Circuit{
+		SessionKey: sessionKey,
+	}
+	for i := 0; i < numHops; i++ {
+		nodeKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		circuit.PaymentPath = append(
+			circuit.PaymentPath, nodeKey.PubKey(),
+		)
+	}
+
+	secrets, err := SharedSecrets(circuit.PaymentPath, sessionKey)
+	require.NoError(t, err)
+
+	now := time.Now()
+	encrypters := make([]*SphinxErrorEncrypter, numHops)
+	for i, secret := range secrets {
+		onionEncrypter := &sphinx.OnionErrorEncrypter{}
+		err := onionEncrypter.Decode(bytes.NewReader(secret[:]))
+		require.NoError(t, err)
+
+		encrypters[i] = &SphinxErrorEncrypter{
+			OnionErrorEncrypter: onionEncrypter,
+			CreatedAt: now.Add(
+				-time.Duration(numHops-i) * time.Second,
+			),
+		}
+	}
+
+	return circuit, encrypters
+}
+
+// TestAttribution tests that the sender is able to verify the attribution
+// data of a failure, and to learn the hold times of the hops.
+func TestAttribution(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 5
+	circuit, encrypters := newTestAttributionCircuit(t, numHops)
+
+	// The fourth hop fails the htlc, and the hops in front of it pass the
+	// failure back.
+	const failingHop = 3
+	failure := lnwire.NewTemporaryChannelFailure(nil)
+	reason, attrData, err := encrypters[failingHop].EncryptFirstHop(
+		failure,
+	)
+	require.NoError(t, err)
+	require.Len(t, attrData, AttributionDataSize)
+
+	for i := failingHop - 1; i >= 0; i-- {
+		reason, attrData = encrypters[i].IntermediateEncrypt(
+			reason, attrData,
+		)
+	}
+
+	// The failure is still readable for the sender, which means that the
+	// shared secrets we derive match the ones of the sphinx package.
+	decrypted, err := sphinx.NewOnionErrorDecrypter(circuit).DecryptError(
+		reason,
+	)
+	require.NoError(t, err)
+	require.Equal(t, failingHop+1, decrypted.SenderIdx)
+
+	attribution, err := DecryptAttribution(
+		circuit, reason, attrData, decrypted.SenderIdx,
+	)
+	require.NoError(t, err)
+	require.Equal(t, failingHop+1, attribution.ValidHops)
+	require.Len(t, attribution.HoldTimes, failingHop+1)
+
+	for i, holdTime := range attribution.HoldTimes {
+		expected := time.Duration(numHops-i) * time.Second
+		require.GreaterOrEqual(t, holdTime, expected)
+		require.Less(t, holdTime, expected+time.Second)
+	}
+}
+
+// TestAttributionTampered tests that a hop tampering with a failure is
+// narrowed down to the pair of hops around it.
+func TestAttributionTampered(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 5
+	circuit, encrypters := newTestAttributionCircuit(t, numHops)
+
+	// The final hop fails the htlc.
+	failure := lnwire.NewFailIncorrectDetails(1000, 100)
+	reason, attrData, err := encrypters[numHops-1].EncryptFirstHop(
+		failure,
+	)
+	require.NoError(t, err)
+
+	// The second hop tampers with the failure before passing it back.
+	const tamperingHop = 1
+	for i := numHops - 2; i >= 0; i-- {
+		if i == tamperingHop {
+			reason[0] ^= 1
+		}
+
+		reason, attrData = encrypters[i].IntermediateEncrypt(
+			reason, attrData,
+		)
+	}
+
+	_, err = sphinx.NewOnionErrorDecrypter(circuit).DecryptError(reason)
+	require.Error(t, err)
+
+	// The HMAC of the tampering hop covers the failure it passed back, so
+	// the first invalid HMAC is the one of the hop after it.
+	attribution, err := DecryptAttribution(
+		circuit, reason, attrData, numHops,
+	)
+	require.NoError(t, err)
+	require.Equal(t, tamperingHop+1, attribution.ValidHops)
+	require.Len(t, attribution.HoldTimes, tamperingHop+1)
+}
+
+// TestAttributionUnsupported tests that a failure from a hop that doesn't
+// support attribution data is attributed to that hop.
+func TestAttributionUnsupported(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 4
+	circuit, encrypters := newTestAttributionCircuit(t, numHops)
+
+	// The final hop fails the htlc without adding attribution data, and
+	// garbles the failure in the process.
+	reason := encrypters[numHops-1].EncryptError(
+		true, bytes.Repeat([]byte{1}, 300),
+	)
+
+	var attrData []byte
+	for i := numHops - 2; i >= 0; i-- {
+		reason, attrData = encrypters[i].IntermediateEncrypt(
+			reason, attrData,
+		)
+	}
+
+	attribution, err := DecryptAttribution(
+		circuit, reason, attrData, numHops,
+	)
+	require.NoError(t, err)
+	require.Equal(t, numHops-1, attribution.ValidHops)
+
+	// Without attribution data, there is nothing to verify.
+	_, err = DecryptAttribution(circuit, reason, nil, numHops)
+	require.ErrorIs(t, err, ErrInvalidAttributionData)
+}
+
+// specSessionKey, specPaymentPath and specSharedSecrets are the route of the
+// onion failure test vectors of BOLT 4.
+var (
+	specSessionKey = bytes.Repeat([]byte{0x41}, 32)
+
+	specPaymentPath = []string{
+		"02eec7245d6b7d2ccb30380bfbe2a3648cd7a942653f5aa340edcea1f283686619",
+		"0324653eac434488002cc06bbfb7f10fe18991e35f9fe4302dbea6d2353dc0ab1c",
+		"027f31ebc5462c1fdce1b737ecff52d37d75dea43ce11c74d25aa297165faa2007",
+		"032c0b7cf95324a07d05398b240174dc0c2be444d96b159aa6c7f7b1e668680991",
+		"02edabbd16b41c8371b92ef2f04c1185b4f03b6dcd52ba9b78d9d7c89c8f221145",
+	}
+
+	specSharedSecrets = []string{
+		"53eb63ea8a3fec3b3cd433b85cd62a4b145e1dda09391b348c4e1cd36a03ea66",
+		"a6519e98832a0b179f62123b3567c106db99ee37bef036e783263602f3488fae",
+		"3a6b412548762f0dbccce5c7ae7bb8147d1caf9b5471c34120b30bc9c04891cc",
+		"21e13c2d7cfe7e18836df50872466117a295783ab8aab0e7ecc8c725503ad02d",
+		"b5756b9b542727dbafc6765a49488b023a725d631af688fc031217e90770c328",
+	}
+
+	// specFailureReasons are the failure reasons of the final hop's
+	// temporary_node_failure, as passed back by every hop starting with
+	// the final one.
+	//
+	//nolint:lll
+	specFailureReasons = []string{
+		"a5e6bd0c74cb347f10cce367f949098f2457d14c046fd8a22cb96efb30b0fdcda8cb9168b50f2fd45edd73c1b0c8b33002df376801ff58aaa94000bf8a86f92620f343baef38a580102395ae3abf9128d1047a0736ff9b83d456740ebbb4aeb3aa9737f18fb4afb4aa074fb26c4d702f42968888550a3bded8c05247e045b866baef0499f079fdaeef6538f31d44deafffdfd3afa2fb4ca9082b8f1c465371a9894dd8c243fb4847e004f5256b3e90e2edde4c9fb3082ddfe4d1e734cacd96ef0706bf63c9984e22dc98851bcccd1c3494351feb458c9c6af41c0044bea3c47552b1d992ae542b17a2d0bba1a096c78d169034ecb55b6e3a7263c26017f033031228833c1daefc0dedb8cf7c3e37c9c37ebfe42f3225c326e8bcfd338804c145b16e34e4",
+		"c49a1ce81680f78f5f2000cda36268de34a3f0a0662f55b4e837c83a8773c22aa081bab1616a0011585323930fa5b9fae0c85770a2279ff59ec427ad1bbff9001c0cd1497004bd2a0f68b50704cf6d6a4bf3c8b6a0833399a24b3456961ba00736785112594f65b6b2d44d9f5ea4e49b5e1ec2af978cbe31c67114440ac51a62081df0ed46d4a3df295da0b0fe25c0115019f03f15ec86fabb4c852f83449e812f141a9395b3f70b766ebbd4ec2fae2b6955bd8f32684c15abfe8fd3a6261e52650e8807a92158d9f1463261a925e4bfba44bd20b166d532f0017185c3a6ac7957adefe45559e3072c8dc35abeba835a8cb01a71a15c736911126f27d46a36168ca5ef7dccd4e2886212602b181463e0dd30185c96348f9743a02aca8ec27c0b90dca270",
+		"a5d3e8634cfe78b2307d87c6d90be6fe7855b4f2cc9b1dfb19e92e4b79103f61ff9ac25f412ddfb7466e74f81b3e545563cdd8f5524dae873de61d7bdfccd496af2584930d2b566b4f8d3881f8c043df92224f38cf094cfc09d92655989531524593ec6d6caec1863bdfaa79229b5020acc034cd6deeea1021c50586947b9b8e6faa83b81fbfa6133c0af5d6b07c017f7158fa94f0d206baf12dda6b68f785b773b360fd0497e16cc402d779c8d48d0fa6315536ef0660f3f4e1865f5b38ea49c7da4fd959de4e83ff3ab686f059a45c65ba2af4a6a79166aa0f496bf04d06987b6d2ea205bdb0d347718b9aeff5b61dfff344993a275b79717cd815b6ad4c0beb568c4ac9c36ff1c315ec1119a1993c4b61e6eaa0375e0aaf738ac691abd3263bf937e3",
+		"aac3200c4968f56b21f53e5e374e3a2383ad2b1b6501bbcc45abc31e59b26881b7dfadbb56ec8dae8857add94e6702fb4c3a4de22e2e669e1ed926b04447fc73034bb730f4932acd62727b75348a648a1128744657ca6a4e713b9b646c3ca66cac02cdab44dd3439890ef3aaf61708714f7375349b8da541b2548d452d84de7084bb95b3ac2345201d624d31f4d52078aa0fa05a88b4e20202bd2b86ac5b52919ea305a8949de95e935eed0319cf3cf19ebea61d76ba92532497fcdc9411d06bcd4275094d0a4a3c5d3a945e43305a5a9256e333e1f64dbca5fcd4e03a39b9012d197506e06f29339dfee3331995b21615337ae060233d39befea925cc262873e0530408e6990f1cbd233a150ef7b004ff6166c70c68d9f8c853c1abca640b8660db2921",
+		"9c5add3963fc7f6ed7f148623c84134b5647e1306419dbe2174e523fa9e2fbed3a06a19f899145610741c83ad40b7712aefaddec8c6baf7325d92ea4ca4d1df8bce517f7e54554608bf2bd8071a4f52a7a2f7ffbb1413edad81eeea5785aa9d990f2865dc23b4bc3c301a94eec4eabebca66be5cf638f693ec256aec514620cc28ee4a94bd9565bc4d4962b9d3641d4278fb319ed2b84de5b665f307a2db0f7fbb757366067d88c50f7e829138fde4f78d39b5b5802f1b92a8a820865af5cc79f9f30bc3f461c66af95d13e5e1f0381c184572a91dee1c849048a647a1158cf884064deddbf1b0b88dfe2f791428d0ba0f6fb2f04e14081f69165ae66d9297c118f0907705c9c4954a199bae0bb96fad763d690e7daa6cfda59ba7f2c8d11448b604d12d",
+	}
+)
+
+// TestAttributionSpecVectors tests the shared secrets and failure reasons of
+// the attribution encrypters against the test vectors of BOLT 4, and that the
+// attribution data added on the way back verifies for all hops of the route.
+func TestAttributionSpecVectors(t *testing.T) {
+	t.Parallel()
+
+	sessionKey, _ := btcec.PrivKeyFromBytes(specSessionKey)
+	circuit := &sphinx.Circuit{
+		SessionKey: sessionKey,
+	}
+	for _, keyHex := range specPaymentPath {
+		keyBytes, err := hex.DecodeString(keyHex)
+		require.NoError(t, err)
+		nodeKey, err := btcec.ParsePubKey(keyBytes)
+		require.NoError(t, err)
+
+		circuit.PaymentPath = append(circuit.PaymentPath, nodeKey)
+	}
+
+	secrets, err := SharedSecrets(circuit.PaymentPath, sessionKey)
+	require.NoError(t, err)
+	require.Len(t, secrets, len(specSharedSecrets))
+	for i, secret := range secrets {
+		require.Equal(t, specSharedSecrets[i], hex.EncodeToString(
+			secret[:],
+		))
+	}
+
+	numHops := len(secrets)
+	now := time.Now()
+	encrypters := make([]*SphinxErrorEncrypter, numHops)
+	for i, secret := range secrets {
+		onionEncrypter := &sphinx.OnionErrorEncrypter{}
+		err := onionEncrypter.Decode(bytes.NewReader(secret[:]))
+		require.NoError(t, err)
+
+		encrypters[i] = &SphinxErrorEncrypter{
+			OnionErrorEncrypter: onionEncrypter,
+			CreatedAt: now.Add(
+				-time.Duration(numHops-i) * time.Second,
+			),
+		}
+	}
+
+	// The final hop fails the htlc, and every hop obfuscates the failure
+	// exactly as the spec does while adding its attribution data.
+	reason, attrData, err := encrypters[numHops-1].EncryptFirstHop(
+		&lnwire.FailTemporaryNodeFailure{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, specFailureReasons[0], hex.EncodeToString(reason))
+
+	for i := numHops - 2; i >= 0; i-- {
+		reason, attrData = encrypters[i].IntermediateEncrypt(
+			reason, attrData,
+		)
+		require.Equal(
+			t, specFailureReasons[numHops-1-i],
+			hex.EncodeToString(reason),
+		)
+	}
+
+	decrypted, err := sphinx.NewOnionErrorDecrypter(circuit).DecryptError(
+		reason,
+	)
+	require.NoError(t, err)
+	require.Equal(t, numHops, decrypted.SenderIdx)
+
+	attribution, err := DecryptAttribution(
+		circuit, reason, attrData, decrypted.SenderIdx,
+	)
+	require.NoError(t, err)
+	require.Equal(t, numHops, attribution.ValidHops)
+	require.Len(t, attribution.HoldTimes, numHops)
+}
@@ -0,0 +1,87 @@
+package hop
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"golang.org/x/crypto/chacha20"
+)
+
+// The sphinx package doesn't export the primitives of the onion construction,
+// so the ones needed for attribution data and trampoline onions live here.
+
+// SharedSecrets derives the shared secret of every hop of a payment path. The
+// ephemeral key of each hop is the one of the previous hop multiplied with the
+// blinding factor of the previous hop.
+func SharedSecrets(nodeKeys []*btcec.PublicKey,
+	sessionKey *btcec.PrivateKey) ([][32]byte, error) {
+
+	var ephemeral btcec.ModNScalar
+	ephemeral.Set(&sessionKey.Key)
+
+	secrets := make([][32]byte, len(nodeKeys))
+	for i, nodeKey := range nodeKeys {
+		ephemeralKey := &sphinx.PrivKeyECDH{
+			PrivKey: btcec.PrivKeyFromScalar(&ephemeral),
+		}
+
+		secret, err := ephemeralKey.ECDH(nodeKey)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = secret
+
+		h := sha256.New()
+		h.Write(ephemeralKey.PubKey().SerializeCompressed())
+		h.Write(secret[:])
+
+		var blindingFactor btcec.ModNScalar
+		blindingFactor.SetByteSlice(h.Sum(nil))
+		ephemeral.Mul(&blindingFactor)
+	}
+
+	return secrets, nil
+}
+
+// GenerateKey derives a key of the given type from a shared secret.
+func GenerateKey(keyType string, secret [32]byte) [32]byte {
+	mac := hmac.New(sha256.New, []byte(keyType))
+	mac.Write(secret[:])
+
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+
+	return key
+}
+
+// CipherStream generates numBytes of the chacha20 key stream of the given
+// key, using a zero nonce.
+func CipherStream(key [32]byte, numBytes int) ([]byte, error) {
+	var nonce [chacha20.NonceSize]byte
+	cipher, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		return nil, err
+	}
+
+	stream := make([]byte, numBytes)
+	cipher.XORKeyStream(stream, stream)
+
+	return stream, nil
+}
+
+// xorCipherStream xors the key stream of the key derived from the shared
+// secret into data.
+func xorCipherStream(keyType string, secret [32]byte, data []byte) error {
+	stream, err := CipherStream(GenerateKey(keyType, secret), len(data))
+	if err != nil {
+		return err
+	}
+
+	for i := range data {
+		data[i] ^= stream[i]
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	sphinx "github.com/lightningnetwork/lightning-onion"
@@ -57,20 +58,27 @@ type ErrorEncrypter interface {
 	// EncryptFirstHop transforms a concrete failure message into an
 	// encrypted opaque failure reason. This method will be used at the
 	// source that the error occurs. It differs from IntermediateEncrypt
-	// slightly, in that it computes a proper MAC over the error.
-	EncryptFirstHop(lnwire.FailureMessage) (lnwire.OpaqueReason, error)
+	// slightly, in that it computes a proper MAC over the error. Along
+	// with the reason, the attribution data for the failure is returned.
+	EncryptFirstHop(lnwire.FailureMessage) (lnwire.OpaqueReason, []byte,
+		error)
 
 	// EncryptMalformedError is similar to EncryptFirstHop (it adds the
 	// MAC), but it accepts an opaque failure reason rather than a failure
 	// message. This method is used when we receive an
 	// UpdateFailMalformedHTLC from the remote peer and then need to
-	// convert that into a proper error from only the raw bytes.
-	EncryptMalformedError(lnwire.OpaqueReason) lnwire.OpaqueReason
+	// convert that into a proper error from only the raw bytes. Along with
+	// the reason, the attribution data for the failure is returned.
+	EncryptMalformedError(lnwire.OpaqueReason) (lnwire.OpaqueReason,
+		[]byte)
 
 	// IntermediateEncrypt wraps an already encrypted opaque reason error
 	// in an additional layer of onion encryption. This process repeats
-	// until the error arrives at the source of the payment.
-	IntermediateEncrypt(lnwire.OpaqueReason) lnwire.OpaqueReason
+	// until the error arrives at the source of the payment. Our hold time
+	// and HMACs are added to the given attribution data of the downstream
+	// hops, which is returned along with the reason.
+	IntermediateEncrypt(lnwire.OpaqueReason, []byte) (lnwire.OpaqueReason,
+		[]byte)
 
 	// Type returns an enum indicating the underlying concrete instance
 	// backing this interface.
@@ -100,6 +108,12 @@ type SphinxErrorEncrypter struct {
 	*sphinx.OnionErrorEncrypter
 
 	EphemeralKey *btcec.PublicKey
+
+	// CreatedAt is the time the htlc was received, which is used to
+	// report our hold time in the attribution data of failures. It is
+	// not persisted, so it is zero for encrypters that were restored
+	// after a restart.
+	CreatedAt time.Time
 }
 
 // NewSphinxErrorEncrypter initializes a blank sphinx error encrypter, that
@@ -123,16 +137,18 @@ func NewSphinxErrorEncrypter() *SphinxErrorEncrypter {
 //
 // NOTE: Part of the ErrorEncrypter interface.
 func (s *SphinxErrorEncrypter) EncryptFirstHop(
-	failure lnwire.FailureMessage) (lnwire.OpaqueReason, error) {
+	failure lnwire.FailureMessage) (lnwire.OpaqueReason, []byte, error) {
 
 	var b bytes.Buffer
 	if err := lnwire.EncodeFailure(&b, failure, 0); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// We pass a true as the first parameter to indicate that a MAC should
 	// be added.
-	return s.EncryptError(true, b.Bytes()), nil
+	reason := s.EncryptError(true, b.Bytes())
+
+	return reason, s.addAttribution(reason, nil), nil
 }
 
 // EncryptMalformedError is similar to EncryptFirstHop (it adds the MAC), but
@@ -143,9 +159,11 @@ func (s *SphinxErrorEncrypter) EncryptFirstHop(
 //
 // NOTE: Part of the ErrorEncrypter interface.
 func (s *SphinxErrorEncrypter) EncryptMalformedError(
-	reason lnwire.OpaqueReason) lnwire.OpaqueReason {
+	reason lnwire.OpaqueReason) (lnwire.OpaqueReason, []byte) {
 
-	return s.EncryptError(true, reason)
+	reason = s.EncryptError(true, reason)
+
+	return reason, s.addAttribution(reason, nil)
 }
 
 // IntermediateEncrypt wraps an already encrypted opaque reason error in an
@@ -155,10 +173,38 @@ func (s *SphinxErrorEncrypter) EncryptMalformedError(
 // error seen.
 //
 // NOTE: Part of the ErrorEncrypter interface.
-func (s *SphinxErrorEncrypter) IntermediateEncrypt(
-	reason lnwire.OpaqueReason) lnwire.OpaqueReason {
+func (s *SphinxErrorEncrypter) IntermediateEncrypt(reason lnwire.OpaqueReason,
+	attrData []byte) (lnwire.OpaqueReason, []byte) {
+
+	reason = s.EncryptError(false, reason)
+
+	return reason, s.addAttribution(reason, attrData)
+}
+
+// addAttribution adds our hold time and HMACs to the attribution data of the
+// given reason, which already carries our layer of encryption.
+func (s *SphinxErrorEncrypter) addAttribution(reason lnwire.OpaqueReason,
+	attrData []byte) []byte {
+
+	// The error encrypter only exposes its shared secret through its
+	// serialization.
+	var b bytes.Buffer
+	if err := s.OnionErrorEncrypter.Encode(&b); err != nil {
+		return nil
+	}
+
+	var secret [32]byte
+	copy(secret[:], b.Bytes())
+
+	data, err := addAttribution(
+		secret, reason, attrData, holdTimeUnits(s.CreatedAt),
+	)
+	if err != nil {
+		log.Errorf("Unable to add attribution data: %v", err)
+		return nil
+	}
 
-	return s.EncryptError(false, reason)
+	return data
 }
 
 // Type returns the identifier for a sphinx error encrypter.
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -925,5 +926,6 @@ func (p *OnionProcessor) ExtractErrorEncrypter(ephemeralKey *btcec.PublicKey) (
 	return &SphinxErrorEncrypter{
 		OnionErrorEncrypter: onionObfuscator,
 		EphemeralKey:        ephemeralKey,
+		CreatedAt:           time.Now(),
 	}, lnwire.CodeNone
 }
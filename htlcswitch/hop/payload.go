@@ -117,6 +117,10 @@ type Payload struct {
 	// totalAmtMsat holds the info provided in total_amount_msat when
 	// parsed from a TLV onion payload.
 	totalAmtMsat lnwire.MilliSatoshi
+
+	// trampolineOnion is the serialized trampoline onion packet that was
+	// included in the payload of the final hop, if any.
+	trampolineOnion []byte
 }
 
 // NewLegacyPayload builds a Payload from the amount, cltv, and next hop
@@ -148,6 +152,7 @@ func ParseTLVPayload(r io.Reader) (*Payload, map[tlv.Type][]byte, error) {
 		encryptedData []byte
 		blindingPoint *btcec.PublicKey
 		metadata      []byte
+		trampoline    []byte
 	)
 
 	tlvStream, err := tlv.NewStream(
@@ -160,6 +165,7 @@ func ParseTLVPayload(r io.Reader) (*Payload, map[tlv.Type][]byte, error) {
 		amp.Record(),
 		record.NewMetadataRecord(&metadata),
 		record.NewTotalAmtMsatBlinded(&totalAmtMsat),
+		record.NewTrampolineOnionRecord(&trampoline),
 	)
 	if err != nil {
 		return nil, nil, err
@@ -196,6 +202,12 @@ func ParseTLVPayload(r io.Reader) (*Payload, map[tlv.Type][]byte, error) {
 		metadata = nil
 	}
 
+	// If no trampoline onion was parsed, set the field on the resulting
+	// payload to nil.
+	if _, ok := parsedTypes[record.TrampolineOnionType]; !ok {
+		trampoline = nil
+	}
+
 	// Filter out the custom records.
 	customRecords := NewCustomRecords(parsedTypes)
 
@@ -205,13 +217,14 @@ func ParseTLVPayload(r io.Reader) (*Payload, map[tlv.Type][]byte, error) {
 			AmountToForward: lnwire.MilliSatoshi(amt),
 			OutgoingCTLV:    cltv,
 		},
-		MPP:           mpp,
-		AMP:           amp,
-		metadata:      metadata,
-		encryptedData: encryptedData,
-		blindingPoint: blindingPoint,
-		customRecords: customRecords,
-		totalAmtMsat:  lnwire.MilliSatoshi(totalAmtMsat),
+		MPP:             mpp,
+		AMP:             amp,
+		metadata:        metadata,
+		encryptedData:   encryptedData,
+		blindingPoint:   blindingPoint,
+		customRecords:   customRecords,
+		totalAmtMsat:    lnwire.MilliSatoshi(totalAmtMsat),
+		trampolineOnion: trampoline,
 	}, parsedTypes, nil
 }
 
@@ -275,6 +288,7 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 	_, hasAMP := parsedTypes[record.AMPOnionType]
 	_, hasEncryptedData := parsedTypes[record.EncryptedDataOnionType]
 	_, hasBlinding := parsedTypes[record.BlindingPointOnionType]
+	_, hasTrampoline := parsedTypes[record.TrampolineOnionType]
 
 	// All cleartext hops (including final hop) and the final hop in a
 	// blinded path require the forwading amount and expiry TLVs to be set.
@@ -375,6 +389,23 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			Violation: IncludedViolation,
 			FinalHop:  isFinalHop,
 		}
+
+	// Intermediate nodes should never receive a trampoline onion.
+	case !isFinalHop && hasTrampoline:
+		return ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// A trampoline onion must be paid to with an MPP record, so that the
+	// trampoline node can tell the parts of a payment apart.
+	case hasTrampoline && !hasMPP:
+		return ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
 	}
 
 	return nil
@@ -427,6 +458,12 @@ func (h *Payload) TotalAmtMsat() lnwire.MilliSatoshi {
 	return h.totalAmtMsat
 }
 
+// TrampolineOnion returns the serialized trampoline onion packet that was
+// included in the final hop payload, or nil if there is none.
+func (h *Payload) TrampolineOnion() []byte {
+	return h.trampolineOnion
+}
+
 // getMinRequiredViolation checks for unrecognized required (even) fields in the
 // standard range and returns the lowest required type. Always returning the
 // lowest required type allows a failure message to be deterministic.
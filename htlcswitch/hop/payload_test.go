@@ -35,6 +35,7 @@ type decodePayloadTest struct {
 	shouldHaveBlinding bool
 	shouldHaveMetadata bool
 	shouldHaveTotalAmt bool
+	shouldHaveTramp    bool
 }
 
 var decodePayloadTests = []decodePayloadTest{
@@ -340,6 +341,64 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 		shouldHaveAMP: true,
 	},
+	{
+		name:       "intermediate hop with trampoline onion",
+		isFinalHop: false,
+		payload: []byte{
+			// amount
+			0x02, 0x00,
+			// cltv
+			0x04, 0x00,
+			// next hop id
+			0x06, 0x08,
+			0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			// trampoline onion
+			0x14, 0x03, 0x04, 0x05, 0x06,
+		},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: hop.IncludedViolation,
+			FinalHop:  false,
+		},
+	},
+	{
+		name:       "final hop with trampoline onion no mpp",
+		isFinalHop: true,
+		payload: []byte{
+			// amount
+			0x02, 0x00,
+			// cltv
+			0x04, 0x00,
+			// trampoline onion
+			0x14, 0x03, 0x04, 0x05, 0x06,
+		},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: hop.OmittedViolation,
+			FinalHop:  true,
+		},
+	},
+	{
+		name:       "final hop with trampoline onion",
+		isFinalHop: true,
+		payload: []byte{
+			// amount
+			0x02, 0x00,
+			// cltv
+			0x04, 0x00,
+			// mpp
+			0x08, 0x21,
+			0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11,
+			0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11,
+			0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11,
+			0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11,
+			0x08,
+			// trampoline onion
+			0x14, 0x03, 0x04, 0x05, 0x06,
+		},
+		shouldHaveMPP:   true,
+		shouldHaveTramp: true,
+	},
 	{
 		name:       "final hop with metadata",
 		isFinalHop: true,
@@ -543,6 +602,7 @@ func testDecodeHopPayloadValidation(t *testing.T, test decodePayloadTest) {
 			0x13, 0x13, 0x13, 0x13, 0x13, 0x13, 0x13, 0x13,
 		}
 		testEncData    = []byte{3, 2, 1}
+		testTramp      = []byte{4, 5, 6}
 		testMetadata   = []byte{1, 2, 3}
 		testChildIndex = uint32(9)
 	)
@@ -621,6 +681,12 @@ func testDecodeHopPayloadValidation(t *testing.T, test decodePayloadTest) {
 		require.Zero(t, p.TotalAmtMsat())
 	}
 
+	if test.shouldHaveTramp {
+		require.Equal(t, testTramp, p.TrampolineOnion())
+	} else {
+		require.Nil(t, p.TrampolineOnion())
+	}
+
 	// Convert expected nil map to empty map, because we always expect an
 	// initiated map from the payload.
 	expCustomRecords := make(record.CustomSet)
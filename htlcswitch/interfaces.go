@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -47,6 +48,24 @@ type InvoiceDatabase interface {
 	HodlUnsubscribeAll(subscriber chan<- interface{})
 }
 
+// TrampolineHandler is an interface which represents the subsystem that
+// forwards payments that reach us as the final hop of the outer onion, but
+// carry a trampoline onion instructing us to pay the next node.
+type TrampolineHandler interface {
+	// NotifyTrampolineHtlc hands an exit hop htlc carrying the given
+	// trampoline onion to the handler. The return value describes how the
+	// htlc should be resolved. If the htlc cannot be resolved immediately,
+	// the resolution is sent on the passed in hodlChan later.
+	NotifyTrampolineHtlc(payHash lntypes.Hash,
+		paidAmount lnwire.MilliSatoshi, expiry uint32,
+		currentHeight int32, circuitKey models.CircuitKey,
+		hodlChan chan<- interface{}, payload invoices.Payload,
+		onion []byte) (invoices.HtlcResolution, error)
+
+	// TrampolineUnsubscribeAll unsubscribes from all htlc resolutions.
+	TrampolineUnsubscribeAll(subscriber chan<- interface{})
+}
+
 // packetHandler is an interface used exclusively by the Switch to handle
 // htlcPacket and pass them to the link implementation.
 type packetHandler interface {
@@ -108,6 +127,10 @@ type scidAliasHandler interface {
 	// zeroConfConfirmed returns whether or not the zero-conf channel has
 	// confirmed.
 	zeroConfConfirmed() bool
+
+	// spliceScid returns the SCID of the funding output of the channel if
+	// it has been spliced since it was opened.
+	spliceScid() fn.Option[lnwire.ShortChannelID]
 }
 
 // ChannelUpdateHandler is an interface that provides methods that allow
@@ -170,6 +193,25 @@ type ChannelUpdateHandler interface {
 	// will only ever be called once. If no CommitSig is owed in the
 	// argument's LinkDirection, then we will call this hook immediately.
 	OnCommitOnce(LinkDirection, func())
+
+	// InitStfu allows us to initiate quiescence on this link. It returns
+	// a receive only channel that will block until quiescence has been
+	// achieved, or definitively fails. The result carries the party that
+	// is considered the initiator of the quiescence session.
+	InitStfu() <-chan fn.Result[lntypes.ChannelParty]
+
+	// ProposeDynCommit requests that the link negotiate a change of
+	// channel parameters with the remote party. It returns a receive only
+	// channel that will be resolved once the new parameters are in effect,
+	// or the negotiation definitively fails.
+	ProposeDynCommit(params DynCommitParams) <-chan error
+
+	// InitSplice requests that the link negotiate a splice of the channel
+	// with the remote party. It returns a receive only channel that will
+	// be resolved with the fully signed splice transaction, or an error if
+	// the negotiation fails.
+	InitSplice(
+		c *lnwallet.SpliceContribution) <-chan fn.Result[*wire.MsgTx]
 }
 
 // CommitHookID is a value that is used to uniquely identify hooks in the
@@ -230,6 +272,10 @@ type ChannelLink interface {
 	// unadvertised.
 	IsUnadvertised() bool
 
+	// OutgoingHtlcLimits returns the limits the remote peer imposes on the
+	// htlcs we offer on the channel.
+	OutgoingHtlcLimits() ChannelLimits
+
 	// ChannelPoint returns the channel outpoint for the channel link.
 	ChannelPoint() wire.OutPoint
 
@@ -449,3 +495,89 @@ type htlcNotifier interface {
 	NotifyFinalHtlcEvent(key models.CircuitKey,
 		info channeldb.FinalHtlcInfo)
 }
+
+// ForwardOutcome is the decision of a ResourceManager on an htlc that is
+// about to be forwarded.
+type ForwardOutcome uint8
+
+const (
+	// ForwardUnendorsed indicates that the htlc may be forwarded using the
+	// general resources of the outgoing channel, and that the outgoing
+	// htlc must not be endorsed.
+	ForwardUnendorsed ForwardOutcome = iota
+
+	// ForwardEndorsed indicates that the htlc may be forwarded using the
+	// protected resources of the outgoing channel, and that the outgoing
+	// htlc is endorsed.
+	ForwardEndorsed
+
+	// ForwardNoResources indicates that the resources available to the
+	// htlc on the outgoing channel are exhausted, so it must be failed.
+	ForwardNoResources
+)
+
+// String returns a human-readable representation of the forward outcome.
+func (f ForwardOutcome) String() string {
+	switch f {
+	case ForwardUnendorsed:
+		return "unendorsed"
+
+	case ForwardEndorsed:
+		return "endorsed"
+
+	case ForwardNoResources:
+		return "no resources"
+
+	default:
+		return "unknown"
+	}
+}
+
+// ProposedHTLC describes an htlc that the switch is about to forward.
+type ProposedHTLC struct {
+	// IncomingCircuit identifies the htlc on the incoming channel.
+	IncomingCircuit models.CircuitKey
+
+	// OutgoingChannel is the channel the htlc is forwarded on.
+	OutgoingChannel lnwire.ShortChannelID
+
+	// IncomingAmount is the amount of the incoming htlc.
+	IncomingAmount lnwire.MilliSatoshi
+
+	// OutgoingAmount is the amount of the outgoing htlc.
+	OutgoingAmount lnwire.MilliSatoshi
+
+	// IncomingExpiry is the absolute block height at which the incoming
+	// htlc expires.
+	IncomingExpiry uint32
+
+	// CurrentHeight is the current block height.
+	CurrentHeight uint32
+
+	// IncomingEndorsed is true if the incoming htlc was endorsed by the
+	// peer that sent it.
+	IncomingEndorsed bool
+}
+
+// ChannelLimits are the limits that the remote peer of a channel imposes on
+// the htlcs we offer to it.
+type ChannelLimits struct {
+	// MaxHTLCs is the maximum number of htlcs we may offer at once.
+	MaxHTLCs uint16
+
+	// MaxInFlight is the maximum total value of the htlcs we may offer at
+	// once.
+	MaxInFlight lnwire.MilliSatoshi
+}
+
+// ResourceManager decides whether the switch may forward an htlc, and whether
+// the outgoing htlc is endorsed. This allows the resources of a channel to be
+// protected against jamming by reserving part of them for endorsed htlcs from
+// peers with a good reputation.
+type ResourceManager interface {
+	// ForwardHTLC is called for every htlc that is about to be forwarded
+	// on the outgoing channel. The resources assigned to an htlc that is
+	// forwarded are held until the incoming htlc is resolved.
+	ForwardHTLC(htlc *ProposedHTLC,
+		limits ChannelLimits) (ForwardOutcome, error)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btclog/v2"
 	"github.com/lightningnetwork/lnd/build"
@@ -65,6 +66,11 @@ const (
 	// a channel's commitment fee to be of its balance. This only applies to
 	// the initiator of the channel.
 	DefaultMaxLinkFeeAllocation float64 = 0.5
+
+	// DefaultQuiescenceTimeout is the maximum amount of time a link will
+	// remain quiescent, after it has sent its Stfu, before it disconnects
+	// from the remote peer to recover the ability to send updates.
+	DefaultQuiescenceTimeout = time.Minute
 )
 
 // ExpectedFee computes the expected fee for a given htlc amount. The value
@@ -131,6 +137,10 @@ type ChannelLinkConfig struct {
 	// in thread-safe manner.
 	Registry InvoiceDatabase
 
+	// TrampolineHandler forwards exit hop htlcs that carry a trampoline
+	// onion. If nil, such htlcs are failed back.
+	TrampolineHandler TrampolineHandler
+
 	// PreimageCache is a global witness beacon that houses any new
 	// preimages discovered by other links. We'll use this to add new
 	// witnesses that we discover which will notify any sub-systems
@@ -252,6 +262,11 @@ type ChannelLinkConfig struct {
 	// when channels become inactive.
 	NotifyInactiveChannel func(wire.OutPoint)
 
+	// NotifySpliceLocked allows the link to tell the funding manager when
+	// a splice of the channel has been locked by both parties, so the
+	// channel can be moved to its new funding output in the graph.
+	NotifySpliceLocked func(wire.OutPoint)
+
 	// NotifyInactiveLinkEvent allows the switch to tell the
 	// ChannelNotifier when a channel link become inactive.
 	NotifyInactiveLinkEvent func(wire.OutPoint)
@@ -285,6 +300,37 @@ type ChannelLinkConfig struct {
 	// MaxFeeExposure is the threshold in milli-satoshis after which we'll
 	// restrict the flow of HTLCs and fee updates.
 	MaxFeeExposure lnwire.MilliSatoshi
+
+	// MaxLocalCSVDelay is the maximum delay we accept on our own funds
+	// when the remote party proposes a new one through a dynamic
+	// commitment negotiation.
+	MaxLocalCSVDelay uint16
+
+	// DisallowQuiescence is a flag that can be used to disable the
+	// quiescence protocol on this link, either because we don't support
+	// it or because the remote peer hasn't signaled support for it.
+	DisallowQuiescence bool
+
+	// DisallowSplice is a flag that can be used to disable splicing of
+	// the channel, either because we don't support it or because the
+	// remote peer hasn't signaled support for it.
+	DisallowSplice bool
+
+	// DisallowChanTypeUpgrade is a flag that can be used to disable
+	// upgrades of the channel type through a dynamic commitment
+	// negotiation, either because we don't support the type the channel
+	// would be upgraded to or because the remote peer hasn't signaled
+	// support for it.
+	DisallowChanTypeUpgrade bool
+
+	// QuiescenceTimeout is the maximum amount of time the link will stay
+	// quiescent after sending its Stfu before disconnecting the peer. If
+	// zero, DefaultQuiescenceTimeout is used.
+	QuiescenceTimeout time.Duration
+
+	// DisallowExpEndorsement is a flag that can be used to stop the link
+	// from forwarding the experimental endorsement signal of htlcs.
+	DisallowExpEndorsement bool
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -387,6 +433,44 @@ type channelLink struct {
 	// our next CommitSig.
 	incomingCommitHooks hookMap
 
+	// quiescer is the state machine that tracks where this channel is with
+	// respect to the quiescence protocol.
+	quiescer quiescer
+
+	// quiescenceReqs is a queue of requests to quiesce this link. The
+	// members of the queue are send-only channels we should call back with
+	// the result.
+	quiescenceReqs chan StfuReq
+
+	// dynCommitReqs is a queue of requests to negotiate new channel
+	// parameters with the remote party.
+	dynCommitReqs chan DynCommitReq
+
+	// dynCommit tracks the dynamic commitment negotiation we initiated, if
+	// any. It is only accessed from the htlcManager goroutine.
+	dynCommit *dynCommitSession
+
+	// spliceReqs is a queue of requests to splice the channel.
+	spliceReqs chan SpliceReq
+
+	// splice tracks the splice negotiation we're currently part of, if
+	// any. It is only accessed from the htlcManager goroutine.
+	splice *spliceSession
+
+	// commitSigBatch holds the commit_sig messages of a batch that we
+	// received so far. It is only accessed from the htlcManager goroutine.
+	commitSigBatch []*lnwire.CommitSig
+
+	// spliceLocked tracks the splice each party sent splice_locked for. A
+	// splice only takes effect once both parties locked the same one. It
+	// is only accessed from the htlcManager goroutine.
+	spliceLocked lntypes.Dual[fn.Option[chainhash.Hash]]
+
+	// lockedSpliceScid is the SCID of the funding output of the splice
+	// we sent splice_locked for. It is only accessed from the htlcManager
+	// goroutine.
+	lockedSpliceScid lnwire.ShortChannelID
+
 	// ContextGuard is a helper that encapsulates a wait group and quit
 	// channel and allows contexts that either block or cancel on those
 	// depending on the use case.
@@ -462,6 +546,37 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		cfg.MaxFeeExposure = DefaultMaxFeeExposure
 	}
 
+	// If the quiescence timeout isn't set, use the default.
+	if cfg.QuiescenceTimeout == 0 {
+		cfg.QuiescenceTimeout = DefaultQuiescenceTimeout
+	}
+
+	var qsm quiescer
+	if !cfg.DisallowQuiescence {
+		channelInitiator := lntypes.Remote
+		if channel.IsInitiator() {
+			channelInitiator = lntypes.Local
+		}
+
+		qsm = newQuiescer(QuiescerCfg{
+			chanID: lnwire.NewChanIDFromOutPoint(
+				channel.ChannelPoint(),
+			),
+			channelInitiator: channelInitiator,
+			sendMsg: func(s lnwire.Stfu) error {
+				return cfg.Peer.SendMessage(false, &s)
+			},
+			timeoutDuration: cfg.QuiescenceTimeout,
+			onTimeout: func() {
+				cfg.Peer.Disconnect(fmt.Errorf("channel %v "+
+					"quiescence timed out",
+					channel.ChannelPoint()))
+			},
+		})
+	} else {
+		qsm = &quiescerNoop{}
+	}
+
 	return &channelLink{
 		cfg:                 cfg,
 		channel:             channel,
@@ -471,6 +586,10 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		flushHooks:          newHookMap(),
 		outgoingCommitHooks: newHookMap(),
 		incomingCommitHooks: newHookMap(),
+		quiescer:            qsm,
+		quiescenceReqs:      make(chan StfuReq),
+		dynCommitReqs:       make(chan DynCommitReq),
+		spliceReqs:          make(chan SpliceReq),
 		ContextGuard:        fn.NewContextGuard(),
 	}
 }
@@ -571,6 +690,11 @@ func (l *channelLink) Stop() {
 	// As the link is stopping, we are no longer interested in htlc
 	// resolutions coming from the invoice registry.
 	l.cfg.Registry.HodlUnsubscribeAll(l.hodlQueue.ChanIn())
+	if l.cfg.TrampolineHandler != nil {
+		l.cfg.TrampolineHandler.TrampolineUnsubscribeAll(
+			l.hodlQueue.ChanIn(),
+		)
+	}
 
 	if l.cfg.ChainEvents.Cancel != nil {
 		l.cfg.ChainEvents.Cancel()
@@ -622,10 +746,12 @@ func (l *channelLink) WaitForShutdown() {
 // EligibleToForward returns a bool indicating if the channel is able to
 // actively accept requests to forward HTLC's. We're able to forward HTLC's if
 // we are eligible to update AND the channel isn't currently flushing the
-// outgoing half of the channel.
+// outgoing half of the channel AND the channel isn't quiescent or in the
+// process of becoming quiescent.
 func (l *channelLink) EligibleToForward() bool {
 	return l.EligibleToUpdate() &&
-		!l.IsFlushing(Outgoing)
+		!l.IsFlushing(Outgoing) &&
+		l.quiescer.CanSendUpdates()
 }
 
 // EligibleToUpdate returns a bool indicating if the channel is able to update
@@ -700,6 +826,29 @@ func (l *channelLink) OnCommitOnce(direction LinkDirection, hook func()) {
 	}
 }
 
+// InitStfu allows us to initiate quiescence on this link. It returns a receive
+// only channel that will block until quiescence has been achieved, or
+// definitively fails. On success, the result carries the party that is
+// considered the initiator of the quiescence session, which downstream
+// protocols use to decide who may propose the next change.
+//
+// This operation has been added to allow channels to be quiesced via RPC. It
+// may be removed or reworked in the future as RPC initiated quiescence is a
+// holdover until we have downstream protocols that use it.
+func (l *channelLink) InitStfu() <-chan fn.Result[lntypes.ChannelParty] {
+	req, out := fn.NewReq[fn.Unit, fn.Result[lntypes.ChannelParty]](
+		fn.Unit{},
+	)
+
+	select {
+	case l.quiescenceReqs <- req:
+	case <-l.Quit:
+		req.Resolve(fn.Err[lntypes.ChannelParty](ErrLinkShuttingDown))
+	}
+
+	return out
+}
+
 // isReestablished returns true if the link has successfully completed the
 // channel reestablishment dance.
 func (l *channelLink) isReestablished() bool {
@@ -719,6 +868,19 @@ func (l *channelLink) IsUnadvertised() bool {
 	return state.ChannelFlags&lnwire.FFAnnounceChannel == 0
 }
 
+// OutgoingHtlcLimits returns the limits the remote peer imposes on the htlcs we
+// offer on the channel.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) OutgoingHtlcLimits() ChannelLimits {
+	state := l.channel.State()
+
+	return ChannelLimits{
+		MaxHTLCs:    state.RemoteChanCfg.MaxAcceptedHtlcs,
+		MaxInFlight: state.RemoteChanCfg.MaxPendingAmount,
+	}
+}
+
 // sampleNetworkFee samples the current fee rate on the network to get into the
 // chain in a timely manner. The returned value is expressed in fee-per-kw, as
 // this is the native rate used when computing the fee for commitment
@@ -913,6 +1075,19 @@ func (l *channelLink) syncChanStates() error {
 			return err
 		}
 
+		// If the remote party didn't receive our signatures for a
+		// splice transaction before the disconnection, we'll
+		// retransmit them after any commitment updates.
+		nextFunding := remoteChanSyncMsg.NextFundingTxid.ValOpt()
+		if nextFunding.IsSome() {
+			txid := chainhash.Hash(nextFunding.UnwrapOr([32]byte{}))
+			txSigsMsg, err := l.resendSpliceTxSigs(txid)
+			if err != nil {
+				return err
+			}
+			msgsToReSend = append(msgsToReSend, txSigsMsg)
+		}
+
 		// Repopulate any identifiers for circuits that may have been
 		// opened or unclosed. This may happen if we needed to
 		// retransmit a commitment signature message.
@@ -1167,6 +1342,8 @@ func (l *channelLink) handleChanSyncErr(err error) {
 func (l *channelLink) htlcManager() {
 	defer func() {
 		l.cfg.BatchTicker.Stop()
+		l.resolveDynCommit(ErrLinkShuttingDown)
+		l.resolveSplice(ErrLinkShuttingDown)
 		l.Wg.Done()
 		l.log.Infof("exited")
 	}()
@@ -1292,6 +1469,20 @@ func (l *channelLink) htlcManager() {
 				"NumPendingUpdates(Local, Remote)")
 		}
 
+		// While the channel is quiescent, or on its way to becoming
+		// quiescent, we aren't allowed to propose any new updates. The
+		// same applies while a splice is waiting to confirm. We'll
+		// leave any packets from the switch and any pending hodl
+		// resolutions queued up until the channel is resumed.
+		var (
+			downstream = l.downstream
+			hodlQueue  = l.hodlQueue.ChanOut()
+		)
+		if !l.quiescer.CanSendUpdates() {
+			downstream = nil
+			hodlQueue = nil
+		}
+
 		select {
 		// We have a new hook that needs to be run when we reach a clean
 		// channel state.
@@ -1331,6 +1522,18 @@ func (l *channelLink) htlcManager() {
 				continue
 			}
 
+			// Zero-fee commitments don't pay any fee, so there's
+			// nothing to adjust.
+			if l.channel.State().ChanType.HasZeroFeeCommitments() {
+				continue
+			}
+
+			// If we aren't currently permitted to send updates,
+			// we'll wait for the next tick to reconsider the fee.
+			if !l.quiescer.CanSendUpdates() {
+				continue
+			}
+
 			// If we are the initiator, then we'll sample the
 			// current fee rate to get into the chain within 3
 			// blocks.
@@ -1386,6 +1589,11 @@ func (l *channelLink) htlcManager() {
 
 			return
 
+		// A splice of the channel has confirmed, so we'll switch over
+		// to the new funding output.
+		case info := <-l.cfg.ChainEvents.SpliceLocked:
+			l.handleSpliceLocked(info)
+
 		case <-l.cfg.BatchTicker.Ticks():
 			// Attempt to extend the remote commitment chain
 			// including all the currently pending entries. If the
@@ -1408,7 +1616,7 @@ func (l *channelLink) htlcManager() {
 		// A message from the switch was just received. This indicates
 		// that the link is an intermediate hop in a multi-hop HTLC
 		// circuit.
-		case pkt := <-l.downstream:
+		case pkt := <-downstream:
 			l.handleDownstreamPkt(pkt)
 
 		// A message from the connected peer was just received. This
@@ -1419,7 +1627,7 @@ func (l *channelLink) htlcManager() {
 
 		// A htlc resolution is received. This means that we now have a
 		// resolution for a previously accepted htlc.
-		case hodlItem := <-l.hodlQueue.ChanOut():
+		case hodlItem := <-hodlQueue:
 			htlcResolution := hodlItem.(invoices.HtlcResolution)
 			err := l.processHodlQueue(htlcResolution)
 			switch err {
@@ -1445,6 +1653,26 @@ func (l *channelLink) htlcManager() {
 				)
 			}
 
+		// A request to quiesce the channel has been made, so we'll
+		// start draining our updates and send our Stfu as soon as we
+		// are able to.
+		case qReq := <-l.quiescenceReqs:
+			l.quiescer.InitStfu(qReq)
+
+			if err := l.sendOwedStfu(); err != nil {
+				l.stfuFailf("unable to send stfu: %v", err)
+			}
+
+		// A request to change the parameters of the channel has been
+		// made, which requires us to quiesce the channel first.
+		case req := <-l.dynCommitReqs:
+			l.handleDynCommitReq(req)
+
+		// A request to splice the channel has been made, which also
+		// requires us to quiesce the channel first.
+		case req := <-l.spliceReqs:
+			l.handleSpliceReq(req)
+
 		case <-l.Quit:
 			return
 		}
@@ -1543,18 +1771,41 @@ func (l *channelLink) processHtlcResolution(resolution invoices.HtlcResolution,
 func getResolutionFailure(resolution *invoices.HtlcFailResolution,
 	amount lnwire.MilliSatoshi) *LinkError {
 
+	switch resolution.Outcome {
 	// If the resolution has been resolved as part of a MPP timeout,
 	// we need to fail the htlc with lnwire.FailMppTimeout.
-	if resolution.Outcome == invoices.ResultMppTimeout {
+	case invoices.ResultMppTimeout:
 		return NewDetailedLinkError(
 			&lnwire.FailMPPTimeout{}, resolution.Outcome,
 		)
+
+	// Trampoline payments that don't leave us enough fee or CLTV budget
+	// are failed with the dedicated trampoline failures, so the sender
+	// can retry with a larger budget.
+	case invoices.ResultTrampolineFeeInsufficient:
+		return NewDetailedLinkError(
+			&lnwire.FailTrampolineFeeInsufficient{},
+			resolution.Outcome,
+		)
+
+	case invoices.ResultTrampolineExpiryTooSoon:
+		return NewDetailedLinkError(
+			&lnwire.FailTrampolineExpiryTooSoon{},
+			resolution.Outcome,
+		)
+
+	// If we couldn't find a route for a trampoline payment, the sender
+	// may try again through another trampoline node.
+	case invoices.ResultTrampolineForwardFailed:
+		return NewDetailedLinkError(
+			&lnwire.FailTemporaryNodeFailure{}, resolution.Outcome,
+		)
 	}
 
-	// If the htlc is not a MPP timeout, we fail it with
-	// FailIncorrectDetails. This error is sent for invoice payment
-	// failures such as underpayment/ expiry too soon and hodl invoices
-	// (which return FailIncorrectDetails to avoid leaking information).
+	// Otherwise, we fail the htlc with FailIncorrectDetails. This error
+	// is sent for invoice payment failures such as underpayment/ expiry
+	// too soon and hodl invoices (which return FailIncorrectDetails to
+	// avoid leaking information).
 	incorrectDetails := lnwire.NewFailIncorrectDetails(
 		amount, uint32(resolution.AcceptHeight),
 	)
@@ -1781,6 +2032,9 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 			pkt.sourceRef,
 			pkt.destRef,
 			&inKey,
+			lnwallet.WithFailAttrData(
+				htlc.AttrData.ValOpt().UnwrapOr(nil),
+			),
 		)
 		if err != nil {
 			l.log.Errorf("unable to cancel incoming HTLC for "+
@@ -1823,6 +2077,7 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 			htlc.ID,
 			pkt.obfuscator,
 			htlc.Reason,
+			htlc.AttrData.ValOpt().UnwrapOr(nil),
 		); err != nil {
 			l.log.Errorf("unable to send HTLC failure: %v",
 				err)
@@ -1932,6 +2187,13 @@ func (l *channelLink) cleanupSpuriousResponse(pkt *htlcPacket) {
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	// If the remote party has already sent us its Stfu, it is forbidden
+	// from sending us any further updates until the channel is resumed.
+	if isChannelUpdate(msg) && !l.quiescer.CanRecvUpdates() {
+		l.stfuFailf("update received after stfu: %T", msg)
+		return
+	}
+
 	switch msg := msg.(type) {
 	case *lnwire.UpdateAddHTLC:
 		if l.IsFlushing(Incoming) {
@@ -2154,9 +2416,14 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			}
 		}
 
-		// Add fail to the update log.
+		// Add fail to the update log, along with the attribution data
+		// of the downstream hops, if any.
 		idx := msg.ID
-		err := l.channel.ReceiveFailHTLC(idx, msg.Reason[:])
+		err := l.channel.ReceiveFailHTLC(
+			idx, msg.Reason[:], lnwallet.WithFailAttrData(
+				msg.AttrData.ValOpt().UnwrapOr(nil),
+			),
+		)
 		if err != nil {
 			l.failf(LinkFailureError{code: ErrInvalidUpdate},
 				"unable to handle upstream fail HTLC: %v", err)
@@ -2164,6 +2431,22 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		}
 
 	case *lnwire.CommitSig:
+		// While a splice is being negotiated, the channel is quiescent
+		// and the only commitment we expect is the one spending the
+		// new funding output.
+		if l.splice != nil && l.splice.state != spliceAwaitStfu {
+			l.handleSpliceCommitSig(msg)
+			break
+		}
+
+		// While the channel has pending splices, the remote party
+		// signs our next commitment for each funding output, sending
+		// the signatures as a batch of commit_sig messages.
+		batch, complete := l.collectCommitSigBatch(msg)
+		if !complete {
+			break
+		}
+
 		// Since we may have learned new preimages for the first time,
 		// we'll add them to our preimage cache. By doing this, we
 		// ensure any contested contracts watched by any on-chain
@@ -2205,12 +2488,27 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 
 			return
 		}
-		err = l.channel.ReceiveNewCommitment(&lnwallet.CommitSigs{
+		commitSigs := &lnwallet.CommitSigs{
 			CommitSig:  msg.CommitSig,
 			HtlcSigs:   msg.HtlcSigs,
 			PartialSig: msg.PartialSig,
 			AuxSigBlob: auxSigBlob,
-		})
+		}
+		if len(batch) > 0 {
+			commitSigs, err = l.channel.CommitSigsFromBatch(batch)
+			if err != nil {
+				l.failf(
+					LinkFailureError{
+						code: ErrInvalidCommitment,
+					},
+					"invalid commit_sig batch: %v", err,
+				)
+
+				return
+			}
+		}
+
+		err = l.channel.ReceiveNewCommitment(commitSigs)
 		if err != nil {
 			// If we were unable to reconstruct their proposed
 			// commitment, then we'll examine the type of error. If
@@ -2387,11 +2685,32 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		}
 
 		l.processRemoteSettleFails(fwdPkg)
-		l.processRemoteAdds(fwdPkg)
 
-		// If the link failed during processing the adds, we must
-		// return to ensure we won't attempted to update the state
-		// further.
+		// Processing the remote adds may require us to fail or settle
+		// some of them, which would add new updates to the channel.
+		// If we aren't currently permitted to do so, we'll defer the
+		// processing until the channel is resumed. The forwarding
+		// package is persisted, so these will also be reprocessed if
+		// the link restarts in the meantime.
+		l.quiescer.OnResume(func() {
+			l.processRemoteAdds(fwdPkg)
+
+			// If the link failed during processing the adds, we
+			// must return to ensure we won't attempt to update the
+			// state further.
+			if l.failed {
+				return
+			}
+
+			// Settling or failing the adds added new local
+			// updates, which we'll commit to right away.
+			if l.channel.OweCommitment() {
+				l.updateCommitTxOrFail()
+			}
+		})
+
+		// The link may have failed if the adds were processed right
+		// away, in which case we won't update the state further.
 		if l.failed {
 			return
 		}
@@ -2399,10 +2718,11 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// The revocation window opened up. If there are pending local
 		// updates, try to update the commit tx. Pending updates could
 		// already have been present because of a previously failed
-		// update to the commit tx or freshly added in by
-		// processRemoteAdds. Also in case there are no local updates,
-		// but there are still remote updates that are not in the remote
-		// commit tx yet, send out an update.
+		// update to the commit tx. Also in case there are no local
+		// updates, but there are still remote updates that are not in
+		// the remote commit tx yet, send out an update. This is still
+		// required while the channel is being quiesced, as it drains
+		// the updates of the remote party.
 		if l.channel.OweCommitment() {
 			if !l.updateCommitTxOrFail() {
 				return
@@ -2417,7 +2737,62 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		}
 		l.RWMutex.Unlock()
 
+	case *lnwire.Stfu:
+		err := l.quiescer.RecvStfu(*msg, l.numPendingRemoteUpdates())
+		if err != nil {
+			l.stfuFailf("unable to handle stfu: %v", err)
+			return
+		}
+
+	case *lnwire.DynPropose:
+		l.handleDynPropose(msg)
+
+	case *lnwire.DynAck:
+		l.handleDynAck()
+
+	case *lnwire.DynReject:
+		l.handleDynReject(msg)
+
+	case *lnwire.SpliceInit:
+		l.handleSpliceInit(msg)
+
+	case *lnwire.SpliceAck:
+		l.handleSpliceAck(msg)
+
+	case *lnwire.TxAddInput, *lnwire.TxAddOutput, *lnwire.TxRemoveInput,
+		*lnwire.TxRemoveOutput:
+
+		l.handleSpliceTxUpdate(msg)
+
+	case *lnwire.TxComplete:
+		l.handleSpliceTxComplete()
+
+	case *lnwire.TxSignatures:
+		l.handleSpliceTxSigs(msg)
+
+	case *lnwire.TxAbort:
+		l.handleTxAbort(msg)
+
+	case *lnwire.SpliceLocked:
+		l.handleRemoteSpliceLocked(msg)
+
 	case *lnwire.UpdateFee:
+		// Zero-fee commitments don't allow fee updates. As this
+		// doesn't put any funds at risk, we only warn and disconnect
+		// the peer instead of force closing the channel.
+		if l.channel.State().ChanType.HasZeroFeeCommitments() {
+			l.failf(
+				LinkFailureError{
+					code:          ErrInvalidUpdate,
+					Warning:       true,
+					FailureAction: LinkFailureDisconnect,
+				},
+				"received update_fee for zero-fee commitment",
+			)
+
+			return
+		}
+
 		// Check and see if their proposed fee-rate would make us
 		// exceed the fee threshold.
 		fee := chainfee.SatPerKWeight(msg.FeePerKw)
@@ -2483,6 +2858,72 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		l.log.Warnf("received unknown message of type %T", msg)
 	}
 
+	// Having processed the message, we may now be in a position where we
+	// owe the remote party an Stfu and have no more pending updates
+	// blocking us from sending it.
+	if l.failed {
+		return
+	}
+	if err := l.sendOwedStfu(); err != nil {
+		l.stfuFailf("unable to send stfu: %v", err)
+		return
+	}
+
+	// If we're waiting to propose new channel parameters or a splice, the
+	// channel may now have become quiescent.
+	l.maybeSendDynPropose()
+	l.maybeSendSpliceInit()
+}
+
+// isChannelUpdate returns true if the given message is one of the update
+// messages that modifies the pending commitment state of a channel. These are
+// the messages that are forbidden while a channel is quiescent.
+func isChannelUpdate(msg lnwire.Message) bool {
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC,
+		*lnwire.UpdateFulfillHTLC,
+		*lnwire.UpdateFailHTLC,
+		*lnwire.UpdateFailMalformedHTLC,
+		*lnwire.UpdateFee:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// numPendingLocalUpdates returns the number of updates we've proposed that
+// have not yet been committed to both our and the remote party's commitment.
+func (l *channelLink) numPendingLocalUpdates() uint64 {
+	return l.channel.NumPendingUpdates(lntypes.Local, lntypes.Local) +
+		l.channel.NumPendingUpdates(lntypes.Local, lntypes.Remote)
+}
+
+// numPendingRemoteUpdates returns the number of updates the remote party has
+// proposed that have not yet been committed to both our and the remote
+// party's commitment.
+func (l *channelLink) numPendingRemoteUpdates() uint64 {
+	return l.channel.NumPendingUpdates(lntypes.Remote, lntypes.Local) +
+		l.channel.NumPendingUpdates(lntypes.Remote, lntypes.Remote)
+}
+
+// sendOwedStfu sends our Stfu to the remote party if we owe one and all of
+// our own updates have been irrevocably committed.
+func (l *channelLink) sendOwedStfu() error {
+	return l.quiescer.SendOwedStfu(l.numPendingLocalUpdates())
+}
+
+// stfuFailf fails the link in the case where the requirements of the
+// quiescence protocol are violated. In all cases we opt to drop the connection
+// as only link state (as opposed to channel state) is affected.
+func (l *channelLink) stfuFailf(format string, args ...interface{}) {
+	l.failf(LinkFailureError{
+		code:             ErrStfuViolation,
+		FailureAction:    LinkFailureDisconnect,
+		PermanentFailure: false,
+		Warning:          true,
+	}, format, args...)
 }
 
 // ackDownStreamPackets is responsible for removing htlcs from a link's mailbox
@@ -2644,14 +3085,16 @@ func (l *channelLink) updateCommitTx() error {
 		return fmt.Errorf("error parsing aux sigs: %w", err)
 	}
 
-	commitSig := &lnwire.CommitSig{
-		ChanID:        l.ChanID(),
-		CommitSig:     newCommit.CommitSig,
-		HtlcSigs:      newCommit.HtlcSigs,
-		PartialSig:    newCommit.PartialSig,
-		CustomRecords: auxBlobRecords,
+	// If the channel has pending splices, we also signed the commitments
+	// spending their funding outputs, which are sent as a batch.
+	commitSigs := lnwallet.CommitSigBatch(
+		l.ChanID(), newCommit.CommitSigs, auxBlobRecords,
+	)
+	msgs := make([]lnwire.Message, 0, len(commitSigs))
+	for _, commitSig := range commitSigs {
+		msgs = append(msgs, commitSig)
 	}
-	l.cfg.Peer.SendMessage(false, commitSig)
+	l.cfg.Peer.SendMessage(false, msgs...)
 
 	// Now that we have sent out a new CommitSig, we invoke the outgoing set
 	// of commit hooks.
@@ -2971,6 +3414,14 @@ func (l *channelLink) confirmedScid() lnwire.ShortChannelID {
 	return l.channel.State().ZeroConfRealScid()
 }
 
+// spliceScid returns the SCID of the funding output of the underlying channel
+// if it has been spliced since it was opened.
+//
+// Part of the scidAliasHandler interface.
+func (l *channelLink) spliceScid() fn.Option[lnwire.ShortChannelID] {
+	return l.channel.State().SpliceShortChanID()
+}
+
 // isZeroConf returns whether or not the underlying channel is a zero-conf
 // channel.
 //
@@ -3665,11 +4116,15 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 
 				// Otherwise, it was already processed, we can
 				// can collect it and continue.
+				endorsement := l.experimentalEndorsement(
+					add.CustomRecords,
+				)
 				outgoingAdd := &lnwire.UpdateAddHTLC{
 					Expiry:        fwdInfo.OutgoingCTLV,
 					Amount:        fwdInfo.AmountToForward,
 					PaymentHash:   add.PaymentHash,
 					BlindingPoint: fwdInfo.NextBlinding,
+					CustomRecords: endorsement,
 				}
 
 				// Finally, we'll encode the onion packet for
@@ -3720,6 +4175,9 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 				Amount:        fwdInfo.AmountToForward,
 				PaymentHash:   add.PaymentHash,
 				BlindingPoint: fwdInfo.NextBlinding,
+				CustomRecords: l.experimentalEndorsement(
+					add.CustomRecords,
+				),
 			}
 
 			// Finally, we'll encode the onion packet for the
@@ -3809,12 +4267,47 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 	l.forwardBatch(replay, switchPackets...)
 }
 
+// experimentalEndorsement returns the custom records to set on an outgoing
+// htlc to forward the experimental endorsement signal of the incoming htlc.
+// An outgoing htlc is only endorsed if the incoming one was endorsed, and it
+// is explicitly unendorsed in any other case. The switch may still downgrade
+// an endorsed htlc, depending on the reputation of the incoming channel.
+func (l *channelLink) experimentalEndorsement(
+	incoming lnwire.CustomRecords) lnwire.CustomRecords {
+
+	if l.cfg.DisallowExpEndorsement {
+		return nil
+	}
+
+	value := byte(lnwire.ExperimentalUnendorsed)
+	if isEndorsed(incoming) {
+		value = lnwire.ExperimentalEndorsed
+	}
+
+	return lnwire.CustomRecords{
+		uint64(lnwire.ExperimentalEndorsementType): {value},
+	}
+}
+
+// isEndorsed returns true if the given custom records of an htlc carry an
+// experimental endorsement signal that endorses the htlc. Any value other
+// than the endorsed one, including values that use more than the three
+// allowed bits, is treated as unendorsed.
+func isEndorsed(records lnwire.CustomRecords) bool {
+	value, ok := records[uint64(lnwire.ExperimentalEndorsementType)]
+	if !ok || len(value) == 0 {
+		return false
+	}
+
+	return value[0] == lnwire.ExperimentalEndorsed
+}
+
 // processExitHop handles an htlc for which this link is the exit hop. It
 // returns a boolean indicating whether the commitment tx needs an update.
 func (l *channelLink) processExitHop(add lnwire.UpdateAddHTLC,
 	sourceRef channeldb.AddRef, obfuscator hop.ErrorEncrypter,
 	fwdInfo hop.ForwardingInfo, heightNow uint32,
-	payload invoices.Payload) error {
+	payload *hop.Payload) error {
 
 	// If hodl.ExitSettle is requested, we will not validate the final hop's
 	// ADD, nor will we settle the corresponding invoice or respond with the
@@ -3834,9 +4327,16 @@ func (l *channelLink) processExitHop(add lnwire.UpdateAddHTLC,
 	// information, and the incoming HTLC had special extra data, then
 	// we'll skip this amount check. The invoice acceptor will make sure we
 	// reject the HTLC if it's not containing the correct amount after
-	// examining the custom data.
+	// examining the custom data. The experimental endorsement signal is
+	// not considered to be special extra data.
+	numCustomRecords := len(add.CustomRecords)
+	endorsementType := uint64(lnwire.ExperimentalEndorsementType)
+	if _, ok := add.CustomRecords[endorsementType]; ok {
+		numCustomRecords--
+	}
+
 	hasBlindedPath := fwdInfo.NextBlinding.IsSome()
-	customHTLC := len(add.CustomRecords) > 0 && !hasBlindedPath
+	customHTLC := numCustomRecords > 0 && !hasBlindedPath
 	log.Tracef("Exit hop has_blinded_path=%v custom_htlc_bypass=%v",
 		hasBlindedPath, customHTLC)
 
@@ -3879,10 +4379,37 @@ func (l *channelLink) processExitHop(add lnwire.UpdateAddHTLC,
 		HtlcID: add.ID,
 	}
 
-	event, err := l.cfg.Registry.NotifyExitHopHtlc(
-		invoiceHash, add.Amount, add.Expiry, int32(heightNow),
-		circuitKey, l.hodlQueue.ChanIn(), add.CustomRecords, payload,
+	var (
+		event invoices.HtlcResolution
+		err   error
 	)
+	switch {
+	// If the payload carries a trampoline onion, the payment isn't meant
+	// for us but has to be forwarded by our trampoline handler.
+	case payload.TrampolineOnion() != nil && l.cfg.TrampolineHandler != nil:
+		event, err = l.cfg.TrampolineHandler.NotifyTrampolineHtlc(
+			invoiceHash, add.Amount, add.Expiry, int32(heightNow),
+			circuitKey, l.hodlQueue.ChanIn(), payload,
+			payload.TrampolineOnion(),
+		)
+
+	// We don't forward trampoline payments, so we fail the htlc.
+	case payload.TrampolineOnion() != nil:
+		l.log.Debugf("rejecting trampoline htlc(%x), trampoline "+
+			"forwarding is disabled", add.PaymentHash)
+
+		event = invoices.NewFailResolution(
+			circuitKey, int32(heightNow),
+			invoices.ResultTrampolineUnsupported,
+		)
+
+	default:
+		event, err = l.cfg.Registry.NotifyExitHopHtlc(
+			invoiceHash, add.Amount, add.Expiry, int32(heightNow),
+			circuitKey, l.hodlQueue.ChanIn(), add.CustomRecords,
+			payload,
+		)
+	}
 	if err != nil {
 		return err
 	}
@@ -3980,13 +4507,16 @@ func (l *channelLink) sendHTLCError(add lnwire.UpdateAddHTLC,
 	sourceRef channeldb.AddRef, failure *LinkError,
 	e hop.ErrorEncrypter, isReceive bool) {
 
-	reason, err := e.EncryptFirstHop(failure.WireMessage())
+	reason, attrData, err := e.EncryptFirstHop(failure.WireMessage())
 	if err != nil {
 		l.log.Errorf("unable to obfuscate error: %v", err)
 		return
 	}
 
-	err = l.channel.FailHTLC(add.ID, reason, &sourceRef, nil, nil)
+	err = l.channel.FailHTLC(
+		add.ID, reason, &sourceRef, nil, nil,
+		lnwallet.WithFailAttrData(attrData),
+	)
 	if err != nil {
 		l.log.Errorf("unable cancel htlc: %v", err)
 		return
@@ -3995,7 +4525,7 @@ func (l *channelLink) sendHTLCError(add lnwire.UpdateAddHTLC,
 	// Send the appropriate failure message depending on whether we're
 	// in a blinded route or not.
 	if err := l.sendIncomingHTLCFailureMsg(
-		add.ID, e, reason,
+		add.ID, e, reason, attrData,
 	); err != nil {
 		l.log.Errorf("unable to send HTLC failure: %v", err)
 		return
@@ -4034,13 +4564,13 @@ func (l *channelLink) sendHTLCError(add lnwire.UpdateAddHTLC,
 // - Forwarding nodes must switch out any errors with MalformedFailHTLC
 // - Introduction nodes should return regular HTLC failure messages.
 //
-// It accepts the original opaque failure, which will be used in the case
-// that we're not part of a blinded route and an error encrypter that'll be
-// used if we are the introduction node and need to present an error as if
-// we're the failing party.
+// It accepts the original opaque failure and its attribution data, which will
+// be used in the case that we're not part of a blinded route and an error
+// encrypter that'll be used if we are the introduction node and need to
+// present an error as if we're the failing party.
 func (l *channelLink) sendIncomingHTLCFailureMsg(htlcIndex uint64,
-	e hop.ErrorEncrypter,
-	originalFailure lnwire.OpaqueReason) error {
+	e hop.ErrorEncrypter, originalFailure lnwire.OpaqueReason,
+	attrData []byte) error {
 
 	var msg lnwire.Message
 	switch {
@@ -4053,9 +4583,10 @@ func (l *channelLink) sendIncomingHTLCFailureMsg(htlcIndex uint64,
 	// code.
 	case e == nil:
 		msg = &lnwire.UpdateFailHTLC{
-			ChanID: l.ChanID(),
-			ID:     htlcIndex,
-			Reason: originalFailure,
+			ChanID:   l.ChanID(),
+			ID:       htlcIndex,
+			Reason:   originalFailure,
+			AttrData: attrDataRecord(attrData),
 		}
 
 		l.log.Errorf("Unexpected blinded failure when "+
@@ -4066,9 +4597,10 @@ func (l *channelLink) sendIncomingHTLCFailureMsg(htlcIndex uint64,
 	// transformation on the error message and can just send the original.
 	case !e.Type().IsBlinded():
 		msg = &lnwire.UpdateFailHTLC{
-			ChanID: l.ChanID(),
-			ID:     htlcIndex,
-			Reason: originalFailure,
+			ChanID:   l.ChanID(),
+			ID:       htlcIndex,
+			Reason:   originalFailure,
+			AttrData: attrDataRecord(attrData),
 		}
 
 	// When we're the introduction node, we need to convert the error to
@@ -4082,15 +4614,16 @@ func (l *channelLink) sendIncomingHTLCFailureMsg(htlcIndex uint64,
 		failureMsg := lnwire.NewInvalidBlinding(
 			fn.None[[lnwire.OnionPacketSize]byte](),
 		)
-		reason, err := e.EncryptFirstHop(failureMsg)
+		reason, attrData, err := e.EncryptFirstHop(failureMsg)
 		if err != nil {
 			return err
 		}
 
 		msg = &lnwire.UpdateFailHTLC{
-			ChanID: l.ChanID(),
-			ID:     htlcIndex,
-			Reason: reason,
+			ChanID:   l.ChanID(),
+			ID:       htlcIndex,
+			Reason:   reason,
+			AttrData: attrDataRecord(attrData),
 		}
 
 	// If we are a relaying node, we need to switch out any error that
@@ -271,3 +271,95 @@ func (l *linkTestContext) assertNoMsgFromAlice(timeout time.Duration) {
 	case <-time.After(timeout):
 	}
 }
+
+// receiveStfuAliceToBob waits for Alice to send an Stfu to Bob and returns it.
+func (l *linkTestContext) receiveStfuAliceToBob() *lnwire.Stfu {
+	l.t.Helper()
+
+	var msg lnwire.Message
+	select {
+	case msg = <-l.aliceMsgs:
+	case <-time.After(15 * time.Second):
+		l.t.Fatalf("did not receive message")
+	}
+
+	stfu, ok := msg.(*lnwire.Stfu)
+	if !ok {
+		l.t.Fatalf("expected Stfu, got %T", msg)
+	}
+
+	return stfu
+}
+
+// sendStfuBobToAlice makes Bob send an Stfu to Alice, claiming the initiator
+// role if initiator is set.
+func (l *linkTestContext) sendStfuBobToAlice(initiator bool) {
+	l.t.Helper()
+
+	l.aliceLink.HandleChannelUpdate(&lnwire.Stfu{
+		ChanID:    l.aliceLink.ChanID(),
+		Initiator: initiator,
+	})
+}
+
+// receiveDynProposeAliceToBob waits for Alice to send a DynPropose to Bob and
+// returns it.
+func (l *linkTestContext) receiveDynProposeAliceToBob() *lnwire.DynPropose {
+	l.t.Helper()
+
+	var msg lnwire.Message
+	select {
+	case msg = <-l.aliceMsgs:
+	case <-time.After(15 * time.Second):
+		l.t.Fatalf("did not receive message")
+	}
+
+	propose, ok := msg.(*lnwire.DynPropose)
+	if !ok {
+		l.t.Fatalf("expected DynPropose, got %T", msg)
+	}
+
+	return propose
+}
+
+// receiveDynReplyAliceToBob waits for Alice to reply to a DynPropose from Bob
+// and returns her DynAck or DynReject.
+func (l *linkTestContext) receiveDynReplyAliceToBob() lnwire.Message {
+	l.t.Helper()
+
+	var msg lnwire.Message
+	select {
+	case msg = <-l.aliceMsgs:
+	case <-time.After(15 * time.Second):
+		l.t.Fatalf("did not receive message")
+	}
+
+	switch msg.(type) {
+	case *lnwire.DynAck, *lnwire.DynReject:
+	default:
+		l.t.Fatalf("expected DynAck or DynReject, got %T", msg)
+	}
+
+	return msg
+}
+
+// receiveSpliceMsgAliceToBob receives a message sent by Alice during a splice
+// negotiation and asserts that it's of the expected type.
+func (l *linkTestContext) receiveSpliceMsgAliceToBob(
+	msgType lnwire.MessageType) lnwire.Message {
+
+	l.t.Helper()
+
+	var msg lnwire.Message
+	select {
+	case msg = <-l.aliceMsgs:
+	case <-time.After(15 * time.Second):
+		l.t.Fatalf("did not receive message")
+	}
+
+	if msg.MsgType() != msgType {
+		l.t.Fatalf("expected %v, got %v", msgType, msg.MsgType())
+	}
+
+	return msg
+}
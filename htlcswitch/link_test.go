@@ -20,6 +20,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
 	sphinx "github.com/lightningnetwork/lightning-onion"
@@ -40,6 +41,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -2087,10 +2089,12 @@ func (m *mockPeer) SendMessage(sync bool, msgs ...lnwire.Message) error {
 		return fmt.Errorf("disconnected")
 	}
 
-	select {
-	case m.sentMsgs <- msgs[0]:
-	case <-m.quit:
-		return fmt.Errorf("mockPeer shutting down")
+	for _, msg := range msgs {
+		select {
+		case m.sentMsgs <- msg:
+		case <-m.quit:
+			return fmt.Errorf("mockPeer shutting down")
+		}
 	}
 	return nil
 }
@@ -2243,8 +2247,10 @@ func newSingleLinkTestHarness(t *testing.T, chanAmt,
 		NotifyActiveChannel:     func(wire.OutPoint) {},
 		NotifyInactiveChannel:   func(wire.OutPoint) {},
 		NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+		NotifySpliceLocked:      func(wire.OutPoint) {},
 		HtlcNotifier:            aliceSwitch.cfg.HtlcNotifier,
 		GetAliases:              getAliases,
+		MaxLocalCSVDelay:        1000,
 	}
 
 	aliceLink := NewChannelLink(aliceCfg, aliceLc.channel)
@@ -4695,6 +4701,10 @@ type persistentLinkHarness struct {
 	batchTicker chan time.Time
 	msgs        chan lnwire.Message
 
+	// chainEvents is the chain event subscription handed to the link on
+	// each restart.
+	chainEvents *contractcourt.ChainEventSubscription
+
 	restoreChan func() (*lnwallet.LightningChannel, error)
 }
 
@@ -4715,6 +4725,7 @@ func newPersistentLinkHarness(t *testing.T, hSwitch *Switch, link ChannelLink,
 		channel:     coreLink.channel,
 		batchTicker: batchTicker,
 		msgs:        coreLink.cfg.Peer.(*mockPeer).sentMsgs,
+		chainEvents: &contractcourt.ChainEventSubscription{},
 		restoreChan: restore,
 	}
 }
@@ -4912,7 +4923,7 @@ func (h *persistentLinkHarness) restartLink(
 		NotifyContractUpdate: notifyContractUpdate,
 		Registry:             h.coreLink.cfg.Registry,
 		FeeEstimator:         newMockFeeEstimator(),
-		ChainEvents:          &contractcourt.ChainEventSubscription{},
+		ChainEvents:          h.chainEvents,
 		BatchTicker:          bticker,
 		FwdPkgGCTicker:       ticker.New(5 * time.Second),
 		PendingCommitTicker:  ticker.New(time.Minute),
@@ -4929,6 +4940,7 @@ func (h *persistentLinkHarness) restartLink(
 		NotifyActiveChannel:     func(wire.OutPoint) {},
 		NotifyInactiveChannel:   func(wire.OutPoint) {},
 		NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+		NotifySpliceLocked:      func(wire.OutPoint) {},
 		HtlcNotifier:            h.hSwitch.cfg.HtlcNotifier,
 		SyncStates:              syncStates,
 		GetAliases:              getAliases,
@@ -7536,3 +7548,1117 @@ func TestLinkFlushHooksCalled(t *testing.T) {
 	ctx.receiveRevAndAckAliceToBob()
 	assertHookCalled(true)
 }
+
+// TestLinkQuiescenceInitiator tests that a link that is asked to quiesce stops
+// adding new updates, waits for its pending updates to be irrevocably
+// committed before sending its Stfu, and resolves the request once the remote
+// party responds.
+func TestLinkQuiescenceInitiator(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+	require.NoError(t, harness.start(), "could not start link")
+
+	var (
+		//nolint:forcetypeassert
+		coreLink = harness.aliceLink.(*channelLink)
+
+		//nolint:forcetypeassert
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  harness.bobChannel,
+		aliceMsgs:   aliceMsgs,
+	}
+
+	// Alice adds an HTLC which is not yet committed when she is asked to
+	// quiesce the channel.
+	htlc1, _ := generateHtlcAndInvoice(t, 0)
+	ctx.sendHtlcAliceToBob(0, htlc1)
+	ctx.receiveHtlcAliceToBob()
+
+	stfuRes := harness.aliceLink.InitStfu()
+
+	// Alice still has a pending update, so she must not send Stfu yet and
+	// the link should no longer be eligible to forward.
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+	require.False(t, harness.aliceLink.EligibleToForward())
+
+	// Drive the commitment dance to lock in Alice's update.
+	select {
+	case harness.aliceBatchTicker <- time.Now():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("could not force commit sig")
+	}
+
+	ctx.receiveCommitSigAliceToBob(1)
+	ctx.sendRevAndAckBobToAlice()
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+
+	ctx.sendCommitSigBobToAlice(1)
+	ctx.receiveRevAndAckAliceToBob()
+
+	// Now that all of Alice's updates are irrevocably committed, she
+	// should send her Stfu claiming the initiator role.
+	stfu := ctx.receiveStfuAliceToBob()
+	require.True(t, stfu.Initiator)
+	require.Equal(t, harness.aliceLink.ChanID(), stfu.ChanID)
+
+	// Any new HTLCs from the switch must be held back while the channel
+	// is quiescent.
+	htlc2, _ := generateHtlcAndInvoice(t, 1)
+	ctx.sendHtlcAliceToBob(1, htlc2)
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+
+	// Once Bob responds, the request should resolve with Alice as the
+	// initiator.
+	ctx.sendStfuBobToAlice(false)
+
+	select {
+	case res := <-stfuRes:
+		party, err := res.Unpack()
+		require.NoError(t, err)
+		require.Equal(t, lntypes.Local, party)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("quiescence request not resolved")
+	}
+}
+
+// TestLinkQuiescenceResponder tests that a link responds to an Stfu from the
+// remote party with its own Stfu and stops forwarding.
+func TestLinkQuiescenceResponder(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+	require.NoError(t, harness.start(), "could not start link")
+
+	var (
+		//nolint:forcetypeassert
+		coreLink = harness.aliceLink.(*channelLink)
+
+		//nolint:forcetypeassert
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  harness.bobChannel,
+		aliceMsgs:   aliceMsgs,
+	}
+
+	ctx.sendStfuBobToAlice(true)
+
+	stfu := ctx.receiveStfuAliceToBob()
+	require.False(t, stfu.Initiator)
+	require.False(t, harness.aliceLink.EligibleToForward())
+
+	// A request to initiate quiescence now fails as we've already sent
+	// our Stfu.
+	select {
+	case res := <-harness.aliceLink.InitStfu():
+		require.ErrorIs(t, res.Err(), ErrStfuAlreadySent)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("quiescence request not resolved")
+	}
+}
+
+// TestLinkDynCommitInitiator tests that a link asked to change the channel
+// parameters quiesces the channel, proposes the new parameters, and applies
+// them once the remote party accepts.
+func TestLinkDynCommitInitiator(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+	require.NoError(t, harness.start(), "could not start link")
+
+	var (
+		//nolint:forcetypeassert
+		coreLink = harness.aliceLink.(*channelLink)
+
+		//nolint:forcetypeassert
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  harness.bobChannel,
+		aliceMsgs:   aliceMsgs,
+	}
+
+	// A request without any parameters should be refused right away.
+	select {
+	case err := <-harness.aliceLink.ProposeDynCommit(DynCommitParams{}):
+		require.ErrorIs(t, err, ErrNoDynCommitParams)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("dynamic commitment request not resolved")
+	}
+
+	// The same goes for a channel type upgrade if one of the peers
+	// doesn't support the new channel type.
+	coreLink.cfg.DisallowChanTypeUpgrade = true
+	chanType := lnwallet.AnchorsUpgradeChanType(
+		coreLink.channel.State().ChanType,
+	)
+	select {
+	case err := <-harness.aliceLink.ProposeDynCommit(DynCommitParams{
+		ChannelType: fn.Some(chanType),
+	}):
+		require.ErrorIs(t, err, ErrChanTypeUpgradeUnsupported)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("dynamic commitment request not resolved")
+	}
+
+	const (
+		maxHtlcs = 10
+		csvDelay = 200
+	)
+	dustLimit := 2 * lnwallet.DustLimitUnknownWitness()
+	resChan := harness.aliceLink.ProposeDynCommit(DynCommitParams{
+		DustLimit:        fn.Some(dustLimit),
+		CsvDelay:         fn.Some[uint16](csvDelay),
+		MaxAcceptedHTLCs: fn.Some[uint16](maxHtlcs),
+	})
+
+	// Alice must first quiesce the channel, and may only propose the new
+	// parameters once Bob has responded.
+	stfu := ctx.receiveStfuAliceToBob()
+	require.True(t, stfu.Initiator)
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+
+	ctx.sendStfuBobToAlice(false)
+
+	propose := ctx.receiveDynProposeAliceToBob()
+	require.True(t, propose.Initiator)
+	require.Equal(t, fn.Some[uint16](maxHtlcs), propose.MaxAcceptedHTLCs)
+	require.Equal(t, fn.Some(dustLimit), propose.DustLimit)
+	require.Equal(t, fn.Some[uint16](csvDelay), propose.CsvDelay)
+	require.True(t, propose.ChannelReserve.IsNone())
+
+	// A second request can't be made while the negotiation is ongoing.
+	secondRes := harness.aliceLink.ProposeDynCommit(DynCommitParams{
+		MaxAcceptedHTLCs: fn.Some[uint16](maxHtlcs + 1),
+	})
+	select {
+	case err := <-secondRes:
+		require.ErrorIs(t, err, ErrDynCommitInProgress)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("dynamic commitment request not resolved")
+	}
+
+	harness.aliceLink.HandleChannelUpdate(&lnwire.DynAck{
+		ChanID: harness.aliceLink.ChanID(),
+	})
+
+	select {
+	case err := <-resChan:
+		require.NoError(t, err)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("dynamic commitment request not resolved")
+	}
+
+	// The new bounds and csv delay constrain Bob, so they should be
+	// applied to his side of the channel, while the dust limit is Alice's
+	// own.
+	state := coreLink.channel.State()
+	require.EqualValues(
+		t, maxHtlcs, state.RemoteChanCfg.MaxAcceptedHtlcs,
+	)
+	require.NotEqualValues(
+		t, maxHtlcs, state.LocalChanCfg.MaxAcceptedHtlcs,
+	)
+	require.EqualValues(t, csvDelay, state.RemoteChanCfg.CsvDelay)
+	require.Equal(t, dustLimit, state.LocalChanCfg.DustLimit)
+
+	// The current commitments were created with the previous params, so
+	// these must be kept for resolving them.
+	require.Len(t, state.CommitParamsHistory, 1)
+	require.NotEqualValues(t, csvDelay, state.CommitParamsAt(
+		lntypes.Remote, state.RemoteCommitment.CommitHeight,
+	).CsvDelay)
+	require.True(t, harness.aliceLink.EligibleToForward())
+}
+
+// TestLinkDynCommitResponder tests that a link rejects proposed parameters it
+// can't apply, and applies the ones it can before acknowledging them.
+func TestLinkDynCommitResponder(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+	require.NoError(t, harness.start(), "could not start link")
+
+	var (
+		//nolint:forcetypeassert
+		coreLink = harness.aliceLink.(*channelLink)
+
+		//nolint:forcetypeassert
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  harness.bobChannel,
+		aliceMsgs:   aliceMsgs,
+	}
+
+	quiesce := func() {
+		ctx.sendStfuBobToAlice(true)
+		stfu := ctx.receiveStfuAliceToBob()
+		require.False(t, stfu.Initiator)
+	}
+
+	// Bob proposes a csv delay for Alice's funds above her maximum, so
+	// Alice should reject the proposal and leave her parameters untouched.
+	state := coreLink.channel.State()
+	oldCsvDelay := state.LocalChanCfg.CsvDelay
+
+	quiesce()
+	harness.aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:    harness.aliceLink.ChanID(),
+		Initiator: true,
+		CsvDelay:  fn.Some(coreLink.cfg.MaxLocalCSVDelay + 1),
+	})
+
+	reply := ctx.receiveDynReplyAliceToBob()
+	reject, ok := reply.(*lnwire.DynReject)
+	require.True(t, ok, "expected DynReject, got %T", reply)
+	require.True(t, reject.UpdateRejections.IsSet(
+		lnwire.FeatureBit(lnwire.DPToSelfDelay),
+	))
+	require.Equal(t, oldCsvDelay, state.LocalChanCfg.CsvDelay)
+	require.True(t, harness.aliceLink.EligibleToForward())
+
+	// Once the channel has resumed, Bob may try again with parameters
+	// that Alice accepts and applies to the channel.
+	const (
+		maxHtlcs = 10
+		csvDelay = 200
+	)
+	dustLimit := 2 * lnwallet.DustLimitUnknownWitness()
+	quiesce()
+	harness.aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:           harness.aliceLink.ChanID(),
+		Initiator:        true,
+		DustLimit:        fn.Some(dustLimit),
+		CsvDelay:         fn.Some[uint16](csvDelay),
+		MaxAcceptedHTLCs: fn.Some[uint16](maxHtlcs),
+	})
+
+	reply = ctx.receiveDynReplyAliceToBob()
+	require.IsType(t, &lnwire.DynAck{}, reply)
+
+	require.EqualValues(t, maxHtlcs, state.LocalChanCfg.MaxAcceptedHtlcs)
+	require.EqualValues(t, csvDelay, state.LocalChanCfg.CsvDelay)
+	require.Equal(t, dustLimit, state.RemoteChanCfg.DustLimit)
+	require.True(t, harness.aliceLink.EligibleToForward())
+
+	// Switching the channel to taproot requires a new funding output, so
+	// Alice must reject it.
+	oldChanType := state.ChanType
+	taprootType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.SimpleTaprootChannelsRequiredStaging,
+	))
+	quiesce()
+	harness.aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:      harness.aliceLink.ChanID(),
+		Initiator:   true,
+		ChannelType: fn.Some(taprootType),
+	})
+
+	reply = ctx.receiveDynReplyAliceToBob()
+	reject, ok = reply.(*lnwire.DynReject)
+	require.True(t, ok, "expected DynReject, got %T", reply)
+	require.True(t, reject.UpdateRejections.IsSet(
+		lnwire.FeatureBit(lnwire.DPChannelType),
+	))
+	require.Equal(t, oldChanType, state.ChanType)
+
+	// The upgrade to anchor outputs keeps the funding output, so it is
+	// accepted and applies from the next commitments on.
+	anchorsType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+	quiesce()
+	harness.aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:      harness.aliceLink.ChanID(),
+		Initiator:   true,
+		ChannelType: fn.Some(anchorsType),
+	})
+
+	reply = ctx.receiveDynReplyAliceToBob()
+	require.IsType(t, &lnwire.DynAck{}, reply)
+
+	require.Equal(
+		t, lnwallet.AnchorsUpgradeChanType(oldChanType), state.ChanType,
+	)
+	require.Equal(t, oldChanType, state.ChanTypeAt(
+		lntypes.Local, state.LocalCommitment.CommitHeight,
+	))
+	require.True(t, harness.aliceLink.EligibleToForward())
+}
+
+// newSpliceOutTx creates a transaction splicing the given amount out of the
+// channel, paying the given fee from the channel funds.
+func newSpliceOutTx(lc *lnwallet.LightningChannel, amt,
+	fee btcutil.Amount) *wire.MsgTx {
+
+	state := lc.State()
+	fundingOutpoint := state.CurrentFundingOutpoint()
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(wire.NewTxIn(&fundingOutpoint, nil, nil))
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    int64(state.Capacity - amt - fee),
+		PkScript: lc.FundingPkScript(),
+	})
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    int64(amt),
+		PkScript: []byte{txscript.OP_TRUE},
+	})
+
+	return spliceTx
+}
+
+// spliceCommitSigs converts the passed commit_sig into the signatures
+// expected by the lnwallet channel.
+func spliceCommitSigs(msg *lnwire.CommitSig) *lnwallet.CommitSigs {
+	return &lnwallet.CommitSigs{
+		CommitSig: msg.CommitSig,
+		HtlcSigs:  msg.HtlcSigs,
+	}
+}
+
+// TestLinkSpliceInitiator tests that a link asked to splice the channel
+// quiesces the channel, negotiates the splice transaction with the remote
+// party, which contributes an input of its own, signs the new commitments and
+// returns the fully signed splice transaction. The channel must keep working
+// afterwards.
+func TestLinkSpliceInitiator(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+	require.NoError(t, harness.start(), "could not start link")
+
+	var (
+		//nolint:forcetypeassert
+		coreLink = harness.aliceLink.(*channelLink)
+
+		//nolint:forcetypeassert
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+
+		chanID     = harness.aliceLink.ChanID()
+		bobChannel = harness.bobChannel
+		bobState   = bobChannel.State()
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  bobChannel,
+		aliceMsgs:   aliceMsgs,
+	}
+
+	const (
+		spliceAmt = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		spliceFee = btcutil.Amount(1000)
+		bobAmt    = btcutil.Amount(btcutil.SatoshiPerBitcoin / 2)
+		bobFee    = btcutil.Amount(500)
+	)
+	spliceTx := newSpliceOutTx(coreLink.channel, spliceAmt, spliceFee)
+
+	resChan := harness.aliceLink.InitSplice(&lnwallet.SpliceContribution{
+		Tx:         spliceTx,
+		LocalDelta: -(spliceAmt + spliceFee),
+		FeeRate:    chainfee.FeePerKwFloor,
+		SignInputs: func(*wire.MsgTx, txscript.PrevOutputFetcher) error {
+			return nil
+		},
+	})
+
+	// Alice must first quiesce the channel, and may only propose the
+	// splice once Bob has responded.
+	stfu := ctx.receiveStfuAliceToBob()
+	require.True(t, stfu.Initiator)
+	ctx.sendStfuBobToAlice(false)
+
+	msg := ctx.receiveSpliceMsgAliceToBob(lnwire.MsgSpliceInit)
+	//nolint:forcetypeassert
+	spliceInit := msg.(*lnwire.SpliceInit)
+	require.Equal(t, -(spliceAmt + spliceFee),
+		spliceInit.FundingContribution)
+	require.True(t, spliceInit.FundingPubKey.IsEqual(
+		bobState.RemoteChanCfg.MultiSigKey.PubKey,
+	))
+
+	// Bob adds half a BTC to the channel.
+	harness.aliceLink.HandleChannelUpdate(&lnwire.SpliceAck{
+		ChanID:              chanID,
+		FundingContribution: bobAmt,
+		FundingPubKey:       bobState.LocalChanCfg.MultiSigKey.PubKey,
+	})
+
+	// Alice adds the shared input, which Bob answers with an input of his
+	// own.
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxAddInput)
+	//nolint:forcetypeassert
+	addInput := msg.(*lnwire.TxAddInput)
+	require.Zero(t, addInput.SerialID)
+	require.True(t, addInput.SharedInputTxid.IsSome())
+
+	bobPrevTx := wire.NewMsgTx(2)
+	bobPrevTx.AddTxIn(&wire.TxIn{})
+	bobPrevTx.AddTxOut(&wire.TxOut{
+		Value:    int64(bobAmt + bobFee),
+		PkScript: append([]byte{txscript.OP_0, 20}, make([]byte, 20)...),
+	})
+	var bobPrevTxBytes bytes.Buffer
+	require.NoError(t, bobPrevTx.Serialize(&bobPrevTxBytes))
+	harness.aliceLink.HandleChannelUpdate(&lnwire.TxAddInput{
+		ChanID:   chanID,
+		SerialID: 1,
+		PrevTx:   bobPrevTxBytes.Bytes(),
+		Sequence: wire.MaxTxInSequenceNum,
+	})
+
+	// Alice then adds both outputs, with the funding output carrying Bob's
+	// contribution as well, before signaling completion herself.
+	txComplete := &lnwire.TxComplete{ChanID: chanID}
+	for i, txOut := range spliceTx.TxOut {
+		msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxAddOutput)
+		//nolint:forcetypeassert
+		addOutput := msg.(*lnwire.TxAddOutput)
+		require.EqualValues(t, 2*i, addOutput.SerialID)
+
+		expectedAmt := btcutil.Amount(txOut.Value)
+		if i == 0 {
+			expectedAmt += bobAmt
+		}
+		require.Equal(t, expectedAmt, addOutput.Amount)
+
+		harness.aliceLink.HandleChannelUpdate(txComplete)
+	}
+
+	ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxComplete)
+
+	// Bob arrives at the same transaction, ordered by serial IDs.
+	fundingOutpoint := bobState.CurrentFundingOutpoint()
+	bobSpliceTx := wire.NewMsgTx(2)
+	bobSpliceTx.AddTxIn(wire.NewTxIn(&fundingOutpoint, nil, nil))
+	bobSpliceTx.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: bobPrevTx.TxHash()}, nil, nil,
+	))
+	bobSpliceTx.AddTxOut(&wire.TxOut{
+		Value:    spliceTx.TxOut[0].Value + int64(bobAmt),
+		PkScript: spliceTx.TxOut[0].PkScript,
+	})
+	bobSpliceTx.AddTxOut(spliceTx.TxOut[1])
+
+	// Both parties now exchange signatures for their new commitments.
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgCommitSig)
+	//nolint:forcetypeassert
+	aliceCommitSig := msg.(*lnwire.CommitSig)
+	require.Empty(t, aliceCommitSig.HtlcSigs)
+
+	bobCandidate, err := bobChannel.NewSpliceCandidate(
+		bobSpliceTx, lntypes.Dual[btcutil.Amount]{
+			Local:  bobAmt,
+			Remote: -(spliceAmt + spliceFee),
+		},
+	)
+	require.NoError(t, err)
+	err = bobChannel.ReceiveSpliceCommitment(
+		bobCandidate, spliceCommitSigs(aliceCommitSig),
+	)
+	require.NoError(t, err)
+
+	bobCommitSigs, err := bobChannel.SignSpliceCommitment(
+		context.Background(), bobCandidate,
+	)
+	require.NoError(t, err)
+	harness.aliceLink.HandleChannelUpdate(&lnwire.CommitSig{
+		ChanID:    chanID,
+		CommitSig: bobCommitSigs.CommitSig,
+		HtlcSigs:  bobCommitSigs.HtlcSigs,
+	})
+
+	// As the acceptor, Bob must send his signatures for the splice
+	// transaction first, which include the witness of his input.
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+
+	bobInputSig, err := bobChannel.SignSpliceInput(bobSpliceTx)
+	require.NoError(t, err)
+	bobWireInputSig, err := lnwire.NewSigFromSignature(bobInputSig)
+	require.NoError(t, err)
+	bobWitness := wire.TxWitness{{0x01}, {0x02}}
+	harness.aliceLink.HandleChannelUpdate(&lnwire.TxSignatures{
+		ChanID:    chanID,
+		Txid:      bobSpliceTx.TxHash(),
+		Witnesses: []wire.TxWitness{bobWitness},
+		SharedInputSig: tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType0](bobWireInputSig),
+		),
+	})
+
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxSignatures)
+	//nolint:forcetypeassert
+	aliceTxSigs := msg.(*lnwire.TxSignatures)
+	require.Equal(t, bobSpliceTx.TxHash(), aliceTxSigs.Txid)
+	require.Empty(t, aliceTxSigs.Witnesses)
+
+	aliceInputSig := aliceTxSigs.SharedInputSig.UnwrapOrFailV(t)
+	err = bobChannel.CompleteSpliceInput(
+		bobSpliceTx, bobInputSig, aliceInputSig,
+	)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.AddSpliceCandidate(bobCandidate))
+
+	select {
+	case res := <-resChan:
+		signedTx, err := res.Unpack()
+		require.NoError(t, err)
+		require.Equal(t, bobSpliceTx.TxHash(), signedTx.TxHash())
+		require.NotEmpty(t, signedTx.TxIn[0].Witness)
+		require.Equal(t, bobWitness, signedTx.TxIn[1].Witness)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("splice request not resolved")
+	}
+
+	// The channel keeps working while the splice confirms, but another
+	// splice can't be requested.
+	require.True(t, harness.aliceLink.EligibleToForward())
+
+	select {
+	case res := <-harness.aliceLink.InitSplice(
+		&lnwallet.SpliceContribution{Tx: spliceTx},
+	):
+		require.ErrorIs(t, res.Err(), ErrSpliceInProgress)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("splice request not resolved")
+	}
+}
+
+// TestLinkSpliceResponder tests that a link accepts a splice proposed by the
+// remote party, persists the new commitments before handing out its
+// signatures for the splice transaction, keeps forwarding HTLCs with
+// commitments signed for both funding outputs while the splice is pending, and
+// switches over to the new funding output once both parties locked the
+// splice, after which payments are routed over the SCID of the new output.
+func TestLinkSpliceResponder(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+
+	//nolint:forcetypeassert
+	coreLink := harness.aliceLink.(*channelLink)
+
+	spliceLocked := make(chan *contractcourt.SpliceLockedInfo, 1)
+	coreLink.cfg.ChainEvents.SpliceLocked = spliceLocked
+
+	notifiedLocked := make(chan wire.OutPoint, 1)
+	coreLink.cfg.NotifySpliceLocked = func(chanPoint wire.OutPoint) {
+		notifiedLocked <- chanPoint
+	}
+
+	require.NoError(t, harness.start(), "could not start link")
+
+	var (
+		//nolint:forcetypeassert
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+
+		chanID     = harness.aliceLink.ChanID()
+		bobChannel = harness.bobChannel
+		bobState   = bobChannel.State()
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  bobChannel,
+		aliceMsgs:   aliceMsgs,
+	}
+
+	const (
+		spliceAmt = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		spliceFee = btcutil.Amount(1000)
+	)
+	spliceTx := newSpliceOutTx(bobChannel, spliceAmt, spliceFee)
+
+	ctx.sendStfuBobToAlice(true)
+	stfu := ctx.receiveStfuAliceToBob()
+	require.False(t, stfu.Initiator)
+
+	harness.aliceLink.HandleChannelUpdate(&lnwire.SpliceInit{
+		ChanID:              chanID,
+		FundingContribution: -(spliceAmt + spliceFee),
+		FundingFeerate:      chainfee.FeePerKwFloor,
+		FundingPubKey:       bobState.LocalChanCfg.MultiSigKey.PubKey,
+	})
+
+	msg := ctx.receiveSpliceMsgAliceToBob(lnwire.MsgSpliceAck)
+	//nolint:forcetypeassert
+	spliceAck := msg.(*lnwire.SpliceAck)
+	require.Zero(t, spliceAck.FundingContribution)
+
+	// Bob adds the shared input and both outputs, each of which Alice
+	// answers with tx_complete as she has nothing to contribute.
+	fundingOutpoint := bobState.CurrentFundingOutpoint()
+	harness.aliceLink.HandleChannelUpdate(&lnwire.TxAddInput{
+		ChanID:    chanID,
+		SerialID:  0,
+		PrevTxOut: fundingOutpoint.Index,
+		Sequence:  spliceTx.TxIn[0].Sequence,
+		SharedInputTxid: tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType0, [32]byte](
+				fundingOutpoint.Hash,
+			),
+		),
+	})
+	ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxComplete)
+
+	for i, txOut := range spliceTx.TxOut {
+		harness.aliceLink.HandleChannelUpdate(&lnwire.TxAddOutput{
+			ChanID:   chanID,
+			SerialID: uint64(2 * i),
+			Amount:   btcutil.Amount(txOut.Value),
+			PkScript: txOut.PkScript,
+		})
+		ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxComplete)
+	}
+
+	harness.aliceLink.HandleChannelUpdate(&lnwire.TxComplete{
+		ChanID: chanID,
+	})
+
+	// Alice has now constructed the same transaction and sends her
+	// signature for Bob's new commitment.
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgCommitSig)
+	//nolint:forcetypeassert
+	aliceCommitSig := msg.(*lnwire.CommitSig)
+
+	bobCandidate, err := bobChannel.NewSpliceCandidate(
+		spliceTx, lntypes.Dual[btcutil.Amount]{
+			Local: -(spliceAmt + spliceFee),
+		},
+	)
+	require.NoError(t, err)
+	err = bobChannel.ReceiveSpliceCommitment(
+		bobCandidate, spliceCommitSigs(aliceCommitSig),
+	)
+	require.NoError(t, err)
+
+	bobCommitSigs, err := bobChannel.SignSpliceCommitment(
+		context.Background(), bobCandidate,
+	)
+	require.NoError(t, err)
+	harness.aliceLink.HandleChannelUpdate(&lnwire.CommitSig{
+		ChanID:    chanID,
+		CommitSig: bobCommitSigs.CommitSig,
+		HtlcSigs:  bobCommitSigs.HtlcSigs,
+	})
+
+	// Alice sends her signatures first, which allow Bob to complete the
+	// splice transaction.
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxSignatures)
+	//nolint:forcetypeassert
+	aliceTxSigs := msg.(*lnwire.TxSignatures)
+	require.Equal(t, spliceTx.TxHash(), aliceTxSigs.Txid)
+
+	bobInputSig, err := bobChannel.SignSpliceInput(spliceTx)
+	require.NoError(t, err)
+	err = bobChannel.CompleteSpliceInput(
+		spliceTx, bobInputSig,
+		aliceTxSigs.SharedInputSig.UnwrapOrFailV(t),
+	)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.AddSpliceCandidate(bobCandidate))
+
+	bobWireInputSig, err := lnwire.NewSigFromSignature(bobInputSig)
+	require.NoError(t, err)
+	harness.aliceLink.HandleChannelUpdate(&lnwire.TxSignatures{
+		ChanID: chanID,
+		Txid:   spliceTx.TxHash(),
+		SharedInputSig: tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType0](bobWireInputSig),
+		),
+	})
+
+	// Alice should end up storing the fully signed splice transaction.
+	aliceState := coreLink.channel.State()
+	spliceTxid := spliceTx.TxHash()
+	require.Eventually(t, func() bool {
+		candidates, err := aliceState.SpliceCandidates()
+		require.NoError(t, err)
+
+		return len(candidates) == 1 &&
+			len(candidates[0].SpliceTx.TxIn[0].Witness) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, harness.aliceLink.EligibleToForward())
+
+	// While the splice is pending, Bob sends Alice an HTLC, signing her
+	// commitments for both funding outputs in a batch.
+	htlc := generateHtlc(t, coreLink, 0)
+	ctx.sendHtlcBobToAlice(htlc)
+
+	bobSigs, err := bobChannel.SignNextCommitment(context.Background())
+	require.NoError(t, err)
+	bobBatch := lnwallet.CommitSigBatch(chanID, bobSigs.CommitSigs, nil)
+	require.Len(t, bobBatch, 2)
+	for _, commitSig := range bobBatch {
+		harness.aliceLink.HandleChannelUpdate(commitSig)
+	}
+
+	// Alice revokes her current commitment and signs Bob's new ones in a
+	// batch as well.
+	ctx.receiveRevAndAckAliceToBob()
+
+	aliceBatch := make([]*lnwire.CommitSig, 2)
+	for i := range aliceBatch {
+		msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgCommitSig)
+		//nolint:forcetypeassert
+		aliceBatch[i] = msg.(*lnwire.CommitSig)
+		require.Len(t, aliceBatch[i].HtlcSigs, 1)
+	}
+	aliceSigs, err := bobChannel.CommitSigsFromBatch(aliceBatch)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.ReceiveNewCommitment(aliceSigs))
+	ctx.sendRevAndAckBobToAlice()
+
+	// Alice settles the HTLC, again signing Bob's commitments for both
+	// funding outputs.
+	ctx.receiveSettleAliceToBob()
+	for i := range aliceBatch {
+		msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgCommitSig)
+		//nolint:forcetypeassert
+		aliceBatch[i] = msg.(*lnwire.CommitSig)
+		require.Empty(t, aliceBatch[i].HtlcSigs)
+	}
+	aliceSigs, err = bobChannel.CommitSigsFromBatch(aliceBatch)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.ReceiveNewCommitment(aliceSigs))
+	ctx.sendRevAndAckBobToAlice()
+
+	bobSigs, err = bobChannel.SignNextCommitment(context.Background())
+	require.NoError(t, err)
+	bobBatch = lnwallet.CommitSigBatch(chanID, bobSigs.CommitSigs, nil)
+	for _, commitSig := range bobBatch {
+		harness.aliceLink.HandleChannelUpdate(commitSig)
+	}
+	ctx.receiveRevAndAckAliceToBob()
+
+	// Once the splice confirms, Alice lets Bob know, but she only switches
+	// over to the new funding output once Bob locked the splice as well.
+	spliceScid := lnwire.ShortChannelID{
+		BlockHeight: testStartingHeight + 10,
+		TxIndex:     1,
+		TxPosition:  uint16(bobCandidate.FundingOutpoint.Index),
+	}
+	spliceLocked <- &contractcourt.SpliceLockedInfo{
+		SpliceTx:        spliceTx,
+		FundingOutpoint: bobCandidate.FundingOutpoint,
+		Capacity:        bobCandidate.Capacity,
+		ShortChanID:     spliceScid,
+	}
+
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgSpliceLocked)
+	//nolint:forcetypeassert
+	aliceLocked := msg.(*lnwire.SpliceLocked)
+	require.Equal(t, spliceTxid, aliceLocked.SpliceTxid)
+	require.Equal(
+		t, []chainhash.Hash{spliceTxid}, coreLink.channel.PendingSplices(),
+	)
+
+	harness.aliceLink.HandleChannelUpdate(&lnwire.SpliceLocked{
+		ChanID:     chanID,
+		SpliceTxid: spliceTxid,
+	})
+	require.Eventually(t, func() bool {
+		return len(coreLink.channel.PendingSplices()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, bobCandidate.FundingOutpoint,
+		aliceState.CurrentFundingOutpoint())
+	require.Equal(t, fn.Some(spliceScid), aliceState.SpliceShortChanID())
+	require.True(t, harness.aliceLink.EligibleToForward())
+
+	// The funding manager is notified so that it moves the channel over to
+	// the new SCID in the graph, after which it makes the switch aware of
+	// it.
+	select {
+	case chanPoint := <-notifiedLocked:
+		require.Equal(t, harness.aliceLink.ChannelPoint(), chanPoint)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("splice_locked not notified")
+	}
+	require.NoError(t, harness.aliceSwitch.UpdateShortChanID(chanID))
+	require.NoError(t, bobChannel.LockSplice(spliceTxid, spliceScid))
+
+	// A payment routed over the new SCID of the channel is sent to Bob,
+	// who signs it for the new funding output only.
+	htlc, _ = generateHtlcAndInvoice(t, 0)
+	err = harness.aliceSwitch.SendHTLC(spliceScid, 0, htlc)
+	require.NoError(t, err)
+	ctx.receiveHtlcAliceToBob()
+
+	select {
+	case harness.aliceBatchTicker <- time.Now():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("could not force commit sig")
+	}
+	ctx.receiveCommitSigAliceToBob(1)
+	ctx.sendRevAndAckBobToAlice()
+	ctx.sendCommitSigBobToAlice(1)
+	ctx.receiveRevAndAckAliceToBob()
+}
+
+// TestLinkSpliceReconnect tests that signatures for a splice transaction that
+// were lost due to a disconnection are requested in channel_reestablish and
+// retransmitted, and that splice_locked is sent again after a reconnection.
+func TestLinkSpliceReconnect(t *testing.T) {
+	t.Parallel()
+
+	harness, err := newSingleLinkTestHarness(
+		t, 5*btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+	require.NoError(t, harness.start(), "could not start link")
+
+	alice := newPersistentLinkHarness(
+		t, harness.aliceSwitch, harness.aliceLink,
+		harness.aliceBatchTicker, harness.aliceRestore,
+	)
+
+	var (
+		chanID     = harness.aliceLink.ChanID()
+		bobChannel = harness.bobChannel
+		bobState   = bobChannel.State()
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		bobChannel:  bobChannel,
+		aliceMsgs:   alice.msgs,
+	}
+
+	const (
+		spliceAmt = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		spliceFee = btcutil.Amount(1000)
+	)
+	spliceTx := newSpliceOutTx(bobChannel, spliceAmt, spliceFee)
+	spliceTxid := spliceTx.TxHash()
+
+	// Bob splices out of the channel, with Alice not contributing
+	// anything.
+	ctx.sendStfuBobToAlice(true)
+	ctx.receiveStfuAliceToBob()
+
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.SpliceInit{
+		ChanID:              chanID,
+		FundingContribution: -(spliceAmt + spliceFee),
+		FundingFeerate:      chainfee.FeePerKwFloor,
+		FundingPubKey:       bobState.LocalChanCfg.MultiSigKey.PubKey,
+	})
+	ctx.receiveSpliceMsgAliceToBob(lnwire.MsgSpliceAck)
+
+	fundingOutpoint := bobState.CurrentFundingOutpoint()
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.TxAddInput{
+		ChanID:    chanID,
+		SerialID:  0,
+		PrevTxOut: fundingOutpoint.Index,
+		Sequence:  spliceTx.TxIn[0].Sequence,
+		SharedInputTxid: tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType0, [32]byte](
+				fundingOutpoint.Hash,
+			),
+		),
+	})
+	ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxComplete)
+
+	for i, txOut := range spliceTx.TxOut {
+		ctx.aliceLink.HandleChannelUpdate(&lnwire.TxAddOutput{
+			ChanID:   chanID,
+			SerialID: uint64(2 * i),
+			Amount:   btcutil.Amount(txOut.Value),
+			PkScript: txOut.PkScript,
+		})
+		ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxComplete)
+	}
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.TxComplete{ChanID: chanID})
+
+	msg := ctx.receiveSpliceMsgAliceToBob(lnwire.MsgCommitSig)
+	//nolint:forcetypeassert
+	aliceCommitSig := msg.(*lnwire.CommitSig)
+
+	bobCandidate, err := bobChannel.NewSpliceCandidate(
+		spliceTx, lntypes.Dual[btcutil.Amount]{
+			Local: -(spliceAmt + spliceFee),
+		},
+	)
+	require.NoError(t, err)
+	err = bobChannel.ReceiveSpliceCommitment(
+		bobCandidate, spliceCommitSigs(aliceCommitSig),
+	)
+	require.NoError(t, err)
+
+	bobCommitSigs, err := bobChannel.SignSpliceCommitment(
+		context.Background(), bobCandidate,
+	)
+	require.NoError(t, err)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.CommitSig{
+		ChanID:    chanID,
+		CommitSig: bobCommitSigs.CommitSig,
+		HtlcSigs:  bobCommitSigs.HtlcSigs,
+	})
+
+	// Alice sends her signatures, which Bob uses to complete the splice
+	// transaction. His own signatures are lost as the connection drops.
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxSignatures)
+	//nolint:forcetypeassert
+	aliceTxSigs := msg.(*lnwire.TxSignatures)
+
+	bobInputSig, err := bobChannel.SignSpliceInput(spliceTx)
+	require.NoError(t, err)
+	err = bobChannel.CompleteSpliceInput(
+		spliceTx, bobInputSig,
+		aliceTxSigs.SharedInputSig.UnwrapOrFailV(t),
+	)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.AddSpliceCandidate(bobCandidate))
+
+	// reconnect restarts Alice's link and exchanges channel_reestablish
+	// with Bob, returning the one sent by Alice. Bob's message is passed
+	// through the given function first.
+	reconnect := func(
+		modifyBobReest func(*lnwire.ChannelReestablish),
+	) *lnwire.ChannelReestablish {
+
+		alice.restart(false, true)
+		ctx.aliceLink = alice.link
+		ctx.aliceMsgs = alice.msgs
+
+		msg := ctx.receiveSpliceMsgAliceToBob(
+			lnwire.MsgChannelReestablish,
+		)
+
+		bobReest, err := bobState.ChanSyncMsg()
+		require.NoError(t, err)
+		require.True(t, bobReest.NextFundingTxid.IsNone())
+		modifyBobReest(bobReest)
+		ctx.aliceLink.HandleChannelUpdate(bobReest)
+
+		// No updates have been exchanged yet, so Alice also resends
+		// channel_ready.
+		ctx.receiveSpliceMsgAliceToBob(lnwire.MsgChannelReady)
+
+		//nolint:forcetypeassert
+		return msg.(*lnwire.ChannelReestablish)
+	}
+
+	// Alice hasn't received Bob's signatures, so she asks for them. We'll
+	// pretend that Bob didn't receive hers either, which she should
+	// retransmit.
+	aliceReest := reconnect(func(bobReest *lnwire.ChannelReestablish) {
+		bobReest.NextFundingTxid = tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType0, [32]byte](
+				spliceTxid,
+			),
+		)
+	})
+	require.Equal(
+		t, fn.Some([32]byte(spliceTxid)),
+		aliceReest.NextFundingTxid.ValOpt(),
+	)
+
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgTxSignatures)
+	require.Equal(t, aliceTxSigs, msg)
+
+	// Once Bob retransmits his signatures, Alice stores the fully signed
+	// splice transaction and stops asking for them.
+	bobWireInputSig, err := lnwire.NewSigFromSignature(bobInputSig)
+	require.NoError(t, err)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.TxSignatures{
+		ChanID: chanID,
+		Txid:   spliceTxid,
+		SharedInputSig: tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType0](bobWireInputSig),
+		),
+	})
+
+	aliceState := alice.channel.State()
+	require.Eventually(t, func() bool {
+		candidates, err := aliceState.SpliceCandidates()
+		require.NoError(t, err)
+
+		return len(candidates) == 1 && candidates[0].FullySigned()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, ctx.aliceLink.EligibleToForward())
+
+	// The splice confirms, but the connection drops before Bob receives
+	// Alice's splice_locked. After reconnecting, Alice sends it again.
+	spliceLocked := make(chan *contractcourt.SpliceLockedInfo, 1)
+	spliceLocked <- &contractcourt.SpliceLockedInfo{
+		SpliceTx:        spliceTx,
+		FundingOutpoint: bobCandidate.FundingOutpoint,
+		Capacity:        bobCandidate.Capacity,
+	}
+	alice.chainEvents = &contractcourt.ChainEventSubscription{
+		SpliceLocked: spliceLocked,
+	}
+
+	aliceReest = reconnect(func(*lnwire.ChannelReestablish) {})
+	require.True(t, aliceReest.NextFundingTxid.IsNone())
+
+	msg = ctx.receiveSpliceMsgAliceToBob(lnwire.MsgSpliceLocked)
+	//nolint:forcetypeassert
+	require.Equal(t, spliceTxid, msg.(*lnwire.SpliceLocked).SpliceTxid)
+
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.SpliceLocked{
+		ChanID:     chanID,
+		SpliceTxid: spliceTxid,
+	})
+	require.Eventually(t, func() bool {
+		return len(alice.channel.PendingSplices()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(
+		t, bobCandidate.FundingOutpoint,
+		alice.channel.State().CurrentFundingOutpoint(),
+	)
+}
@@ -51,6 +51,22 @@ const (
 	// circuit map. This is non-fatal and will resolve itself (usually
 	// within several minutes).
 	ErrCircuitError
+
+	// ErrStfuViolation indicates that the quiescence protocol has been
+	// violated, either because Stfu has been sent/received at an invalid
+	// time, or that an update has been sent/received while the channel is
+	// quiesced.
+	ErrStfuViolation
+
+	// ErrDynCommitViolation indicates that the dynamic commitment
+	// negotiation protocol has been violated, either because a message was
+	// sent/received outside of a quiescence session or out of order.
+	ErrDynCommitViolation
+
+	// ErrSpliceViolation indicates that the splice negotiation protocol
+	// has been violated, either because a message was sent/received
+	// outside of a quiescence session or out of order.
+	ErrSpliceViolation
 )
 
 // LinkFailureAction is an enum-like type that describes the action that should
@@ -122,6 +138,12 @@ func (e LinkFailureError) Error() string {
 		return "unable to resume channel, recovery required"
 	case ErrCircuitError:
 		return "non-fatal circuit map error"
+	case ErrStfuViolation:
+		return "quiescence protocol executed improperly"
+	case ErrDynCommitViolation:
+		return "dynamic commitment negotiation executed improperly"
+	case ErrSpliceViolation:
+		return "splice negotiation executed improperly"
 	default:
 		return "unknown error"
 	}
@@ -142,7 +164,10 @@ func (e LinkFailureError) ShouldSendToPeer() bool {
 		ErrInvalidUpdate,
 		ErrInvalidCommitment,
 		ErrInvalidRevocation,
-		ErrRecoveryError:
+		ErrRecoveryError,
+		ErrStfuViolation,
+		ErrDynCommitViolation,
+		ErrSpliceViolation:
 
 		return true
 
@@ -697,6 +697,7 @@ func (m *memoryMailBox) FailAdd(pkt *htlcPacket) {
 	var (
 		localFailure = false
 		reason       lnwire.OpaqueReason
+		attrData     []byte
 	)
 
 	// Create a temporary channel failure which we will send back to our
@@ -721,7 +722,7 @@ func (m *memoryMailBox) FailAdd(pkt *htlcPacket) {
 		// If the packet is part of a forward, (identified by a non-nil
 		// obfuscator) we need to encrypt the error back to the source.
 		var err error
-		reason, err = pkt.obfuscator.EncryptFirstHop(failure)
+		reason, attrData, err = pkt.obfuscator.EncryptFirstHop(failure)
 		if err != nil {
 			log.Errorf("Unable to obfuscate error: %v", err)
 			return
@@ -744,7 +745,8 @@ func (m *memoryMailBox) FailAdd(pkt *htlcPacket) {
 		obfuscator:     pkt.obfuscator,
 		linkFailure:    linkError,
 		htlc: &lnwire.UpdateFailHTLC{
-			Reason: reason,
+			Reason:   reason,
+			AttrData: attrDataRecord(attrData),
 		},
 	}
 
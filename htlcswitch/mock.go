@@ -28,10 +28,12 @@ import (
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/ticker"
@@ -422,7 +424,7 @@ func (o *mockObfuscator) Reextract(
 var fakeHmac = []byte("hmachmachmachmachmachmachmachmac")
 
 func (o *mockObfuscator) EncryptFirstHop(failure lnwire.FailureMessage) (
-	lnwire.OpaqueReason, error) {
+	lnwire.OpaqueReason, []byte, error) {
 
 	o.failure = failure
 
@@ -430,22 +432,26 @@ func (o *mockObfuscator) EncryptFirstHop(failure lnwire.FailureMessage) (
 	b.Write(fakeHmac)
 
 	if err := lnwire.EncodeFailure(&b, failure, 0); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return b.Bytes(), nil
+	return b.Bytes(), nil, nil
 }
 
-func (o *mockObfuscator) IntermediateEncrypt(reason lnwire.OpaqueReason) lnwire.OpaqueReason {
-	return reason
+func (o *mockObfuscator) IntermediateEncrypt(reason lnwire.OpaqueReason,
+	attrData []byte) (lnwire.OpaqueReason, []byte) {
+
+	return reason, attrData
 }
 
-func (o *mockObfuscator) EncryptMalformedError(reason lnwire.OpaqueReason) lnwire.OpaqueReason {
+func (o *mockObfuscator) EncryptMalformedError(
+	reason lnwire.OpaqueReason) (lnwire.OpaqueReason, []byte) {
+
 	var b bytes.Buffer
 	b.Write(fakeHmac)
 
 	b.Write(reason)
 
-	return b.Bytes()
+	return b.Bytes(), nil
 }
 
 // mockDeobfuscator mock implementation of the failure deobfuscator which
@@ -456,8 +462,8 @@ func newMockDeobfuscator() ErrorDecrypter {
 	return &mockDeobfuscator{}
 }
 
-func (o *mockDeobfuscator) DecryptError(reason lnwire.OpaqueReason) (
-	*ForwardingError, error) {
+func (o *mockDeobfuscator) DecryptError(reason lnwire.OpaqueReason,
+	_ []byte) (*ForwardingError, error) {
 
 	if !bytes.Equal(reason[:32], fakeHmac) {
 		return nil, errors.New("fake decryption error")
@@ -738,6 +744,9 @@ type mockChannelLink struct {
 		incoming bool) *lnwire.ChannelUpdate1
 
 	confirmedZC bool
+
+	// Only used for spliced channels.
+	splicedScid fn.Option[lnwire.ShortChannelID]
 }
 
 // completeCircuit is a helper method for adding the finalized payment circuit
@@ -895,6 +904,10 @@ func (f *mockChannelLink) zeroConfConfirmed() bool {
 	return f.confirmedZC
 }
 
+func (f *mockChannelLink) spliceScid() fn.Option[lnwire.ShortChannelID] {
+	return f.splicedScid
+}
+
 func (f *mockChannelLink) Start() error {
 	f.mailBox.ResetMessages()
 	f.mailBox.ResetPackets()
@@ -926,6 +939,13 @@ func (f *mockChannelLink) EligibleToForward() bool                      { return
 func (f *mockChannelLink) MayAddOutgoingHtlc(lnwire.MilliSatoshi) error { return nil }
 func (f *mockChannelLink) setLiveShortChanID(sid lnwire.ShortChannelID) { f.shortChanID = sid }
 func (f *mockChannelLink) IsUnadvertised() bool                         { return f.unadvertised }
+func (f *mockChannelLink) OutgoingHtlcLimits() ChannelLimits {
+	return ChannelLimits{
+		MaxHTLCs:    input.MaxHTLCNumber / 2,
+		MaxInFlight: lnwire.MaxMilliSatoshi,
+	}
+}
+
 func (f *mockChannelLink) UpdateShortChanID() (lnwire.ShortChannelID, error) {
 	f.eligible = true
 	return f.shortChanID, nil
@@ -950,6 +970,32 @@ func (f *mockChannelLink) OnFlushedOnce(func()) {
 func (f *mockChannelLink) OnCommitOnce(LinkDirection, func()) {
 	// TODO(proofofkeags): Implement
 }
+func (f *mockChannelLink) InitStfu() <-chan fn.Result[lntypes.ChannelParty] {
+	// TODO(proofofkeags): Implement
+	c := make(chan fn.Result[lntypes.ChannelParty], 1)
+
+	c <- fn.Errf[lntypes.ChannelParty]("InitStfu not implemented")
+
+	return c
+}
+
+func (f *mockChannelLink) ProposeDynCommit(DynCommitParams) <-chan error {
+	c := make(chan error, 1)
+
+	c <- fmt.Errorf("ProposeDynCommit not implemented")
+
+	return c
+}
+
+func (f *mockChannelLink) InitSplice(
+	*lnwallet.SpliceContribution) <-chan fn.Result[*wire.MsgTx] {
+
+	c := make(chan fn.Result[*wire.MsgTx], 1)
+
+	c <- fn.Errf[*wire.MsgTx]("InitSplice not implemented")
+
+	return c
+}
 
 func (f *mockChannelLink) FundingCustomBlob() fn.Option[tlv.Blob] {
 	return fn.None[tlv.Blob]()
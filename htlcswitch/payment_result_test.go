@@ -41,17 +41,15 @@ func TestNetworkResultSerialization(t *testing.T) {
 	}
 
 	fail := &lnwire.UpdateFailHTLC{
-		ChanID:    chanID,
-		ID:        1,
-		Reason:    []byte{},
-		ExtraData: make([]byte, 0),
+		ChanID: chanID,
+		ID:     1,
+		Reason: []byte{},
 	}
 
 	fail2 := &lnwire.UpdateFailHTLC{
-		ChanID:    chanID,
-		ID:        1,
-		Reason:    reason[:],
-		ExtraData: make([]byte, 0),
+		ChanID: chanID,
+		ID:     1,
+		Reason: reason[:],
 	}
 
 	testCases := []*networkResult{
@@ -0,0 +1,521 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrStfuAlreadySent indicates that this channel has already sent an
+	// Stfu message for this negotiation.
+	ErrStfuAlreadySent = errors.New("stfu already sent")
+
+	// ErrStfuAlreadyRcvd indicates that this channel has already received
+	// an Stfu message for this negotiation.
+	ErrStfuAlreadyRcvd = errors.New("stfu already received")
+
+	// ErrNoQuiescenceInitiator indicates that the caller has requested the
+	// quiescence initiator for a channel that is not yet quiescent.
+	ErrNoQuiescenceInitiator = errors.New(
+		"indeterminate quiescence initiator: channel is not quiescent",
+	)
+
+	// ErrPendingRemoteUpdates indicates that we have received an Stfu
+	// while the remote party has issued updates that are not yet
+	// bilaterally committed.
+	ErrPendingRemoteUpdates = errors.New(
+		"stfu received with pending remote updates",
+	)
+
+	// ErrQuiescenceInProgress indicates that a request to initiate
+	// quiescence was made while we were already waiting on a previous
+	// request to complete.
+	ErrQuiescenceInProgress = errors.New(
+		"quiescence negotiation already in progress",
+	)
+
+	// ErrQuiescenceNotSupported is returned when quiescence is requested
+	// on, or the remote party attempts to quiesce, a link that hasn't
+	// negotiated the quiescence feature.
+	ErrQuiescenceNotSupported = errors.New(
+		"quiescence not supported on this link",
+	)
+)
+
+// StfuReq is the type used to request that a link initiate quiescence. The
+// response will be the ChannelParty that is considered the initiator of the
+// quiescence session once the channel is quiescent, or an error if quiescence
+// could not be achieved.
+type StfuReq = fn.Req[fn.Unit, fn.Result[lntypes.ChannelParty]]
+
+// quiescer is a state machine that tracks the progression of a channel
+// through the quiescence protocol defined in BOLT 2. A channel is considered
+// quiescent once both parties have sent an Stfu message, at which point no
+// further updates may be proposed by either side until quiescence is resumed.
+type quiescer interface {
+	// IsQuiescent returns true if the state machine has been driven all
+	// the way to completion, meaning both sides have sent an Stfu.
+	IsQuiescent() bool
+
+	// QuiescenceInitiator determines which ChannelParty is the initiator
+	// of quiescence for the purposes of downstream protocols. If the
+	// channel is not currently quiescent, an error is returned.
+	QuiescenceInitiator() fn.Result[lntypes.ChannelParty]
+
+	// InitStfu instructs the quiescer that we intend to begin a quiescence
+	// negotiation where we are the initiator. The request will be
+	// resolved once the channel becomes quiescent.
+	InitStfu(req StfuReq)
+
+	// RecvStfu is called when we receive an Stfu message from the remote
+	// party. The number of remote updates that are not yet committed to
+	// both commitments is passed in so that protocol violations can be
+	// detected.
+	RecvStfu(msg lnwire.Stfu, numPendingRemoteUpdates uint64) error
+
+	// CanRecvUpdates returns true if the remote party is still permitted
+	// to send us channel updates.
+	CanRecvUpdates() bool
+
+	// CanSendUpdates returns true if we are still permitted to propose
+	// new channel updates to the remote party.
+	CanSendUpdates() bool
+
+	// SendOwedStfu sends an Stfu message to the remote party if we owe
+	// one and are able to do so given the number of local updates that
+	// are not yet committed to both commitments.
+	SendOwedStfu(numPendingLocalUpdates uint64) error
+
+	// OnResume accepts a closure that will be run once the quiescer is
+	// resumed. If the quiescer is not currently blocking updates, the
+	// closure is executed immediately.
+	OnResume(hook func())
+
+	// Resume runs all of the deferred actions that have accumulated while
+	// the channel was quiescent and resets the state machine so that a
+	// new negotiation can take place.
+	Resume()
+}
+
+// QuiescerCfg is a config structure used to initialize a quiescer giving it
+// the appropriate functionality to interact with the channel state that the
+// quiescer must synchronize with.
+type QuiescerCfg struct {
+	// chanID marks what channel we are managing the state machine for.
+	// This is important because the quiescer needs to know the ChannelID
+	// to construct the Stfu message.
+	chanID lnwire.ChannelID
+
+	// channelInitiator indicates which ChannelParty originally opened the
+	// channel. This is used to break ties when both sides of the channel
+	// send Stfu claiming to be the initiator.
+	channelInitiator lntypes.ChannelParty
+
+	// sendMsg is a function that can be used to send an Stfu message over
+	// the wire.
+	sendMsg func(lnwire.Stfu) error
+
+	// timeoutDuration is the maximum time we will wait, after sending our
+	// Stfu, for the channel to be resumed before onTimeout is called. A
+	// zero value disables the timeout.
+	timeoutDuration time.Duration
+
+	// onTimeout is called if the channel hasn't been resumed within
+	// timeoutDuration of us sending our Stfu.
+	onTimeout func()
+}
+
+// quiescerLive is a state machine that tracks progression through the
+// quiescence protocol.
+type quiescerLive struct {
+	cfg QuiescerCfg
+
+	// localInit indicates whether our path through this state machine was
+	// initiated by our node. This can be true or false independently of
+	// remoteInit.
+	localInit bool
+
+	// remoteInit indicates whether we received Stfu from our peer where
+	// the message indicated that the remote node believes it was the
+	// initiator. This can be true or false independently of localInit.
+	remoteInit bool
+
+	// sent tracks whether or not we have emitted Stfu for sending.
+	sent bool
+
+	// received tracks whether or not we have received Stfu from our peer.
+	received bool
+
+	// activeQuiescenceReq is a possibly None Request that we should
+	// resolve when we complete quiescence.
+	activeQuiescenceReq fn.Option[StfuReq]
+
+	// resumeQueue is a slice of hooks that will be called when the
+	// quiescer is resumed. These are actions that needed to be deferred
+	// while the channel was quiescent.
+	resumeQueue []func()
+
+	// timeoutTimer is the timer that fires onTimeout if the channel isn't
+	// resumed in time.
+	timeoutTimer *time.Timer
+
+	sync.RWMutex
+}
+
+// A compile time check to ensure quiescerLive implements the quiescer
+// interface.
+var _ quiescer = (*quiescerLive)(nil)
+
+// newQuiescer creates a new quiescer for the given channel.
+func newQuiescer(cfg QuiescerCfg) quiescer {
+	return &quiescerLive{
+		cfg: cfg,
+	}
+}
+
+// RecvStfu is called when we receive an Stfu message from the remote.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) RecvStfu(msg lnwire.Stfu,
+	numPendingRemoteUpdates uint64) error {
+
+	q.Lock()
+	defer q.Unlock()
+
+	return q.recvStfu(msg, numPendingRemoteUpdates)
+}
+
+// recvStfu is the internal implementation of RecvStfu that does not grab the
+// quiescer's lock.
+func (q *quiescerLive) recvStfu(msg lnwire.Stfu,
+	numPendingRemoteUpdates uint64) error {
+
+	// At the time of this writing, this check that we have already
+	// received an Stfu is not strictly necessary, according to the
+	// specification. However, it is fishy if we do and it is unclear how
+	// we should handle such a case so we will err on the side of caution.
+	if q.received {
+		return fmt.Errorf("%w for channel %v", ErrStfuAlreadyRcvd,
+			q.cfg.chanID)
+	}
+
+	// We need to check that the Stfu we are receiving is valid. The
+	// remote party MUST NOT send Stfu while any of their updates are
+	// still pending on either commitment.
+	if numPendingRemoteUpdates != 0 {
+		return fmt.Errorf("%w: %d updates pending on channel %v",
+			ErrPendingRemoteUpdates, numPendingRemoteUpdates,
+			q.cfg.chanID)
+	}
+
+	q.received = true
+
+	// If the remote party sets the initiator bit to true then we will
+	// remember that they are making a claim to the initiator role. This
+	// does not necessarily mean they will get it, though.
+	q.remoteInit = msg.Initiator
+
+	// Since we just received an Stfu, we may have a newly quiesced state.
+	// If so, we will try to resolve any outstanding StfuReqs.
+	q.tryResolveStfuReq()
+
+	return nil
+}
+
+// SendOwedStfu sends Stfu if it owes one. It returns an error if the state
+// machine is in an invalid state.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) SendOwedStfu(numPendingLocalUpdates uint64) error {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.sendOwedStfu(numPendingLocalUpdates)
+}
+
+// sendOwedStfu is the internal implementation of SendOwedStfu that does not
+// grab the quiescer's lock.
+func (q *quiescerLive) sendOwedStfu(numPendingLocalUpdates uint64) error {
+	// If we don't owe an Stfu, or aren't yet able to send one, then
+	// there's nothing to do.
+	if !q.oweStfu() || numPendingLocalUpdates != 0 {
+		return nil
+	}
+
+	err := q.cfg.sendMsg(lnwire.Stfu{
+		ChanID:    q.cfg.chanID,
+		Initiator: q.localInit,
+	})
+	if err != nil {
+		return err
+	}
+
+	q.sent = true
+
+	// Now that we've sent our Stfu, we'll start the clock on how long the
+	// remote party and any downstream protocol has to complete.
+	q.startTimeout()
+
+	// Since we just sent an Stfu, we may have a newly quiesced state. If
+	// so, we will try to resolve any outstanding StfuReqs.
+	q.tryResolveStfuReq()
+
+	return nil
+}
+
+// oweStfu returns true if we owe the other party an Stfu. We owe the remote
+// an Stfu when we have received but not yet sent an Stfu, or we are the
+// initiator but have not yet sent an Stfu.
+func (q *quiescerLive) oweStfu() bool {
+	return (q.received || q.localInit) && !q.sent
+}
+
+// IsQuiescent returns true if the state machine has been driven all the way
+// to completion. If this returns true, processes that depend on channel
+// quiescence may proceed.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) IsQuiescent() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.isQuiescent()
+}
+
+// isQuiescent is the internal implementation of IsQuiescent that does not
+// grab the quiescer's lock.
+func (q *quiescerLive) isQuiescent() bool {
+	return q.sent && q.received
+}
+
+// QuiescenceInitiator determines which ChannelParty is the initiator of
+// quiescence for the purposes of downstream protocols. If the channel is not
+// currently quiescent, this method will return ErrNoQuiescenceInitiator.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) QuiescenceInitiator() fn.Result[lntypes.ChannelParty] {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.quiescenceInitiator()
+}
+
+// quiescenceInitiator is the internal implementation of QuiescenceInitiator
+// that does not grab the quiescer's lock.
+func (q *quiescerLive) quiescenceInitiator() fn.Result[lntypes.ChannelParty] {
+	switch {
+	case !q.isQuiescent():
+		return fn.Err[lntypes.ChannelParty](ErrNoQuiescenceInitiator)
+
+	// If both sides claim to be the initiator, the tie is broken in favor
+	// of the party that originally opened the channel.
+	case q.localInit && q.remoteInit:
+		return fn.Ok(q.cfg.channelInitiator)
+
+	case q.localInit:
+		return fn.Ok(lntypes.Local)
+
+	case q.remoteInit:
+		return fn.Ok(lntypes.Remote)
+	}
+
+	// Getting here means neither side claimed the initiator role, which
+	// should be impossible since one of the two had to kick off the
+	// negotiation.
+	return fn.Errf[lntypes.ChannelParty]("no initiator found for "+
+		"quiescent channel %v", q.cfg.chanID)
+}
+
+// CanSendUpdates returns true if we haven't yet sent an Stfu, haven't
+// received one from our peer, and haven't requested to initiate quiescence
+// ourselves. Once any of those happen we must stop proposing new updates so
+// that the channel can drain.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) CanSendUpdates() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return !q.sent && !q.received && !q.localInit
+}
+
+// CanRecvUpdates returns true if we haven't yet received an Stfu which will
+// mark the end of the remote party's ability to send updates.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) CanRecvUpdates() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return !q.received
+}
+
+// InitStfu instructs the quiescer that we intend to begin a quiescence
+// negotiation where we are the initiator. The passed request is resolved
+// once the channel is quiescent.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) InitStfu(req StfuReq) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.sent {
+		req.Resolve(fn.Errf[lntypes.ChannelParty]("%w for channel %v",
+			ErrStfuAlreadySent, q.cfg.chanID))
+
+		return
+	}
+
+	if q.activeQuiescenceReq.IsSome() {
+		req.Resolve(fn.Err[lntypes.ChannelParty](
+			ErrQuiescenceInProgress,
+		))
+
+		return
+	}
+
+	q.localInit = true
+	q.activeQuiescenceReq = fn.Some(req)
+}
+
+// tryResolveStfuReq attempts to resolve the active quiescence request if the
+// state machine has reached a quiescent state.
+func (q *quiescerLive) tryResolveStfuReq() {
+	if !q.isQuiescent() {
+		return
+	}
+
+	q.activeQuiescenceReq.WhenSome(func(req StfuReq) {
+		req.Resolve(q.quiescenceInitiator())
+	})
+	q.activeQuiescenceReq = fn.None[StfuReq]()
+}
+
+// OnResume accepts a no return closure that will run when the quiescer is
+// resumed. If updates are currently permitted, the hook is run immediately.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) OnResume(hook func()) {
+	q.Lock()
+	if !q.sent && !q.received && !q.localInit {
+		q.Unlock()
+		hook()
+
+		return
+	}
+
+	q.resumeQueue = append(q.resumeQueue, hook)
+	q.Unlock()
+}
+
+// Resume runs all of the deferred actions that have accumulated while the
+// channel has been quiescent and then resets the quiescer state to its
+// initial state.
+//
+// NOTE: Part of the quiescer interface.
+func (q *quiescerLive) Resume() {
+	q.Lock()
+
+	if q.timeoutTimer != nil {
+		q.timeoutTimer.Stop()
+		q.timeoutTimer = nil
+	}
+
+	// Any request that is still outstanding can no longer be fulfilled as
+	// the negotiation it was waiting on has been abandoned.
+	q.activeQuiescenceReq.WhenSome(func(req StfuReq) {
+		req.Resolve(fn.Errf[lntypes.ChannelParty]("quiescence "+
+			"resumed before completion for channel %v",
+			q.cfg.chanID))
+	})
+
+	hooks := q.resumeQueue
+
+	q.localInit = false
+	q.remoteInit = false
+	q.sent = false
+	q.received = false
+	q.activeQuiescenceReq = fn.None[StfuReq]()
+	q.resumeQueue = nil
+
+	q.Unlock()
+
+	// We run the hooks outside of the lock as they will typically try to
+	// issue new updates, which in turn will consult the quiescer.
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// startTimeout arms the timer that will call onTimeout if the quiescence
+// session isn't resumed in time.
+func (q *quiescerLive) startTimeout() {
+	if q.cfg.timeoutDuration == 0 || q.cfg.onTimeout == nil {
+		return
+	}
+
+	if q.timeoutTimer != nil {
+		q.timeoutTimer.Stop()
+	}
+
+	q.timeoutTimer = time.AfterFunc(q.cfg.timeoutDuration, q.cfg.onTimeout)
+}
+
+// quiescerNoop is a quiescer that is used for links that haven't negotiated
+// the quiescence feature. It never blocks updates and rejects any attempt to
+// quiesce the channel.
+type quiescerNoop struct{}
+
+// A compile time check to ensure quiescerNoop implements the quiescer
+// interface.
+var _ quiescer = (*quiescerNoop)(nil)
+
+// InitStfu resolves the request with an error as quiescence isn't supported.
+func (q *quiescerNoop) InitStfu(req StfuReq) {
+	req.Resolve(fn.Err[lntypes.ChannelParty](ErrQuiescenceNotSupported))
+}
+
+// RecvStfu always returns an error as the remote party shouldn't be sending
+// Stfu on a link where quiescence hasn't been negotiated.
+func (q *quiescerNoop) RecvStfu(_ lnwire.Stfu, _ uint64) error {
+	return ErrQuiescenceNotSupported
+}
+
+// CanRecvUpdates always returns true.
+func (q *quiescerNoop) CanRecvUpdates() bool {
+	return true
+}
+
+// CanSendUpdates always returns true.
+func (q *quiescerNoop) CanSendUpdates() bool {
+	return true
+}
+
+// SendOwedStfu is a no-op.
+func (q *quiescerNoop) SendOwedStfu(_ uint64) error {
+	return nil
+}
+
+// IsQuiescent always returns false.
+func (q *quiescerNoop) IsQuiescent() bool {
+	return false
+}
+
+// QuiescenceInitiator always returns ErrNoQuiescenceInitiator.
+func (q *quiescerNoop) QuiescenceInitiator() fn.Result[lntypes.ChannelParty] {
+	return fn.Err[lntypes.ChannelParty](ErrNoQuiescenceInitiator)
+}
+
+// OnResume runs the hook immediately as updates are never blocked.
+func (q *quiescerNoop) OnResume(hook func()) {
+	hook()
+}
+
+// Resume is a no-op.
+func (q *quiescerNoop) Resume() {}
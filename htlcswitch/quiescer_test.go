@@ -0,0 +1,326 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+var cid = lnwire.ChannelID(bytes.Repeat([]byte{0x00}, 32))
+
+type quiescerTestHarness struct {
+	pendingUpdates lntypes.Dual[uint64]
+	quiescer       quiescer
+	conn           <-chan lnwire.Stfu
+}
+
+func initQuiescerTestHarness(
+	channelInitiator lntypes.ChannelParty) *quiescerTestHarness {
+
+	conn := make(chan lnwire.Stfu, 1)
+
+	harness := &quiescerTestHarness{
+		pendingUpdates: lntypes.Dual[uint64]{},
+		conn:           conn,
+	}
+
+	harness.quiescer = newQuiescer(QuiescerCfg{
+		chanID:           cid,
+		channelInitiator: channelInitiator,
+		sendMsg: func(msg lnwire.Stfu) error {
+			conn <- msg
+			return nil
+		},
+	})
+
+	return harness
+}
+
+// recvStfu delivers an Stfu from the remote party to the quiescer under test.
+func (h *quiescerTestHarness) recvStfu(initiator bool) error {
+	return h.quiescer.RecvStfu(
+		lnwire.Stfu{ChanID: cid, Initiator: initiator},
+		h.pendingUpdates.GetForParty(lntypes.Remote),
+	)
+}
+
+// sendOwedStfu asks the quiescer under test to send its Stfu if it owes one.
+func (h *quiescerTestHarness) sendOwedStfu() error {
+	return h.quiescer.SendOwedStfu(
+		h.pendingUpdates.GetForParty(lntypes.Local),
+	)
+}
+
+// TestQuiescerDoubleRecvInvalid ensures that we get an error response when we
+// receive the Stfu message twice during the lifecycle of the quiescer.
+func TestQuiescerDoubleRecvInvalid(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Local)
+
+	require.NoError(t, harness.recvStfu(true))
+	require.ErrorIs(t, harness.recvStfu(true), ErrStfuAlreadyRcvd)
+}
+
+// TestQuiescerPendingUpdatesRecvInvalid ensures that we get an error if we
+// receive the Stfu message while the Remote party has panding updates on the
+// channel.
+func TestQuiescerPendingUpdatesRecvInvalid(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Local)
+
+	harness.pendingUpdates.SetForParty(lntypes.Remote, 1)
+	require.ErrorIs(t, harness.recvStfu(true), ErrPendingRemoteUpdates)
+}
+
+// TestQuiesceRemoteInitiator ensures that we can successfully traverse the
+// state graph of quiescence beginning with the Remote party initiating
+// quiescence.
+func TestQuiescenceRemoteInitiator(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Local)
+
+	// Set up a pending local update so that we can verify that we don't
+	// send Stfu until we've drained it.
+	harness.pendingUpdates.SetForParty(lntypes.Local, 1)
+
+	// We should be able to send and receive updates before any Stfu has
+	// been exchanged.
+	require.True(t, harness.quiescer.CanSendUpdates())
+	require.True(t, harness.quiescer.CanRecvUpdates())
+
+	require.NoError(t, harness.recvStfu(true))
+
+	// Once the remote party has sent Stfu, neither side may propose new
+	// updates.
+	require.False(t, harness.quiescer.CanSendUpdates())
+	require.False(t, harness.quiescer.CanRecvUpdates())
+
+	// We still have a pending local update, so we shouldn't send our Stfu
+	// yet.
+	require.NoError(t, harness.sendOwedStfu())
+	select {
+	case <-harness.conn:
+		t.Fatalf("stfu sent before pending updates drained")
+	default:
+	}
+	require.False(t, harness.quiescer.IsQuiescent())
+
+	// Once our update has been committed we should send our Stfu, after
+	// which the channel is quiescent with the remote as the initiator.
+	harness.pendingUpdates.SetForParty(lntypes.Local, 0)
+	require.NoError(t, harness.sendOwedStfu())
+
+	select {
+	case msg := <-harness.conn:
+		require.False(t, msg.Initiator)
+	default:
+		t.Fatalf("stfu not sent when fully drained")
+	}
+
+	require.True(t, harness.quiescer.IsQuiescent())
+	require.Equal(
+		t, fn.Ok(lntypes.Remote),
+		harness.quiescer.QuiescenceInitiator(),
+	)
+}
+
+// TestQuiescenceLocalInitiator ensures that we can successfully traverse the
+// state graph of quiescence beginning with the Local party initiating
+// quiescence.
+func TestQuiescenceLocalInitiator(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Local)
+
+	stfuReq, stfuRes := fn.NewReq[fn.Unit, fn.Result[lntypes.ChannelParty]](
+		fn.Unit{},
+	)
+	harness.quiescer.InitStfu(stfuReq)
+
+	// Once we've asked to quiesce, we must stop adding new updates even
+	// though we haven't sent our Stfu yet.
+	require.False(t, harness.quiescer.CanSendUpdates())
+	require.True(t, harness.quiescer.CanRecvUpdates())
+
+	harness.pendingUpdates.SetForParty(lntypes.Local, 1)
+	require.NoError(t, harness.sendOwedStfu())
+
+	select {
+	case <-harness.conn:
+		t.Fatalf("stfu sent before pending updates drained")
+	default:
+	}
+
+	harness.pendingUpdates.SetForParty(lntypes.Local, 0)
+	require.NoError(t, harness.sendOwedStfu())
+
+	select {
+	case msg := <-harness.conn:
+		require.True(t, msg.Initiator)
+	default:
+		t.Fatalf("stfu not sent when fully drained")
+	}
+
+	// The request shouldn't be resolved until the remote party responds.
+	select {
+	case <-stfuRes:
+		t.Fatalf("stfu request resolved before quiescence")
+	default:
+	}
+
+	require.NoError(t, harness.recvStfu(false))
+
+	select {
+	case party := <-stfuRes:
+		require.Equal(t, fn.Ok(lntypes.Local), party)
+	default:
+		t.Fatalf("stfu request not resolved on quiescence")
+	}
+}
+
+// TestQuiescenceInitiator ensures that the quiescenceInitiator is the
+// Remote party when we have a concurrent initialization of quiescence and
+// the remote party is the channel initiator.
+func TestQuiescenceInitiator(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Remote)
+	require.True(
+		t, harness.quiescer.QuiescenceInitiator().IsErr(),
+	)
+
+	// Receive Stfu claiming to be the initiator, while also asking to
+	// initiate ourselves.
+	require.NoError(t, harness.recvStfu(true))
+
+	stfuReq, stfuRes := fn.NewReq[fn.Unit, fn.Result[lntypes.ChannelParty]](
+		fn.Unit{},
+	)
+	harness.quiescer.InitStfu(stfuReq)
+
+	require.NoError(t, harness.sendOwedStfu())
+
+	select {
+	case msg := <-harness.conn:
+		require.True(t, msg.Initiator)
+	default:
+		t.Fatalf("stfu not sent")
+	}
+
+	// Since both sides claimed the initiator role, the tie should be
+	// broken in favor of the channel opener, which is the remote party.
+	select {
+	case party := <-stfuRes:
+		require.Equal(t, fn.Ok(lntypes.Remote), party)
+	default:
+		t.Fatalf("stfu request not resolved on quiescence")
+	}
+
+	require.Equal(
+		t, fn.Ok(lntypes.Remote),
+		harness.quiescer.QuiescenceInitiator(),
+	)
+}
+
+// TestQuiescenceCantReceiveUpdatesAfterStfu tests that we can receive
+// channel updates prior to a receiving Stfu but not after.
+func TestQuiescenceCantReceiveUpdatesAfterStfu(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Local)
+	require.True(t, harness.quiescer.CanRecvUpdates())
+
+	require.NoError(t, harness.recvStfu(true))
+	require.False(t, harness.quiescer.CanRecvUpdates())
+}
+
+// TestQuiescenceResume ensures that hooks deferred while the channel is
+// quiescent are run on resume, and that the quiescer returns to its initial
+// state afterwards.
+func TestQuiescenceResume(t *testing.T) {
+	t.Parallel()
+
+	harness := initQuiescerTestHarness(lntypes.Local)
+
+	// While no negotiation is in progress, hooks should be run right
+	// away.
+	var ranImmediately bool
+	harness.quiescer.OnResume(func() {
+		ranImmediately = true
+	})
+	require.True(t, ranImmediately)
+
+	require.NoError(t, harness.recvStfu(true))
+	require.NoError(t, harness.sendOwedStfu())
+	<-harness.conn
+
+	require.True(t, harness.quiescer.IsQuiescent())
+
+	var ranOnResume bool
+	harness.quiescer.OnResume(func() {
+		ranOnResume = true
+	})
+	require.False(t, ranOnResume)
+
+	harness.quiescer.Resume()
+	require.True(t, ranOnResume)
+
+	require.False(t, harness.quiescer.IsQuiescent())
+	require.True(t, harness.quiescer.CanSendUpdates())
+	require.True(t, harness.quiescer.CanRecvUpdates())
+}
+
+// TestQuiescenceTimeout ensures that the timeout callback fires if the channel
+// isn't resumed in time after we've sent our Stfu, and that resuming the
+// channel disarms it.
+func TestQuiescenceTimeout(t *testing.T) {
+	t.Parallel()
+
+	conn := make(chan lnwire.Stfu, 1)
+	timedOut := make(chan struct{}, 1)
+
+	q := newQuiescer(QuiescerCfg{
+		chanID:           cid,
+		channelInitiator: lntypes.Local,
+		sendMsg: func(msg lnwire.Stfu) error {
+			conn <- msg
+			return nil
+		},
+		timeoutDuration: 10 * time.Millisecond,
+		onTimeout: func() {
+			timedOut <- struct{}{}
+		},
+	})
+
+	require.NoError(t, q.RecvStfu(lnwire.Stfu{ChanID: cid}, 0))
+	require.NoError(t, q.SendOwedStfu(0))
+	<-conn
+
+	select {
+	case <-timedOut:
+	case <-time.After(time.Second):
+		t.Fatalf("quiescence timeout not fired")
+	}
+
+	// Now run through the negotiation again, but resume before the timer
+	// fires.
+	q.Resume()
+	require.NoError(t, q.RecvStfu(lnwire.Stfu{ChanID: cid}, 0))
+	require.NoError(t, q.SendOwedStfu(0))
+	<-conn
+	q.Resume()
+
+	select {
+	case <-timedOut:
+		t.Fatalf("quiescence timeout fired after resume")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,1223 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrSpliceInProgress is returned when a splice is requested while
+	// another one is still being negotiated or awaiting confirmation.
+	ErrSpliceInProgress = errors.New("splice already in progress")
+
+	// ErrSpliceNotInitiator is returned when we requested a splice, but
+	// the remote party won the right to propose changes in the quiescence
+	// session.
+	ErrSpliceNotInitiator = errors.New(
+		"remote party is the quiescence initiator",
+	)
+
+	// ErrSpliceAborted is returned when the splice negotiation is aborted
+	// by either party.
+	ErrSpliceAborted = errors.New("splice aborted")
+
+	// ErrSpliceDisallowed is returned when a splice is requested on a link
+	// that doesn't support splicing, either because we don't support it or
+	// because the remote party hasn't signaled support for it.
+	ErrSpliceDisallowed = errors.New("splicing not supported on link")
+)
+
+// SpliceReq is the type used to request that a link negotiate a splice of its
+// channel with the remote party. It is resolved with the fully signed splice
+// transaction, which is ready to be published.
+type SpliceReq = fn.Req[*lnwallet.SpliceContribution, fn.Result[*wire.MsgTx]]
+
+// spliceState describes the step of the splice negotiation we're currently
+// in.
+type spliceState uint8
+
+const (
+	// spliceAwaitStfu means that we requested a splice and are waiting for
+	// the channel to become quiescent.
+	spliceAwaitStfu spliceState = iota
+
+	// spliceAwaitInit means that we requested a splice, but the remote
+	// party won the right to propose one. We're waiting for its
+	// splice_init to contribute to its splice instead.
+	spliceAwaitInit
+
+	// spliceAwaitAck means that we sent splice_init and are waiting for
+	// the remote party's splice_ack.
+	spliceAwaitAck
+
+	// spliceNegotiating means that the inputs and outputs of the splice
+	// transaction are being exchanged.
+	spliceNegotiating
+
+	// spliceAwaitCommitSig means that we sent our signature for the
+	// remote party's new commitment and are waiting for theirs.
+	spliceAwaitCommitSig
+
+	// spliceAwaitTxSigs means that the new commitments are signed and we
+	// are waiting for the remote party's signatures for the splice
+	// transaction.
+	spliceAwaitTxSigs
+)
+
+// spliceSession tracks the negotiation of a splice transaction. Both parties
+// may contribute inputs and outputs to it.
+type spliceSession struct {
+	// initiator is the party that initiated the splice.
+	initiator lntypes.ChannelParty
+
+	// req is the request that started the negotiation. It is only set if
+	// we requested a splice.
+	req fn.Option[SpliceReq]
+
+	// proposal is the splice we requested. It is only set if we requested
+	// a splice. If the remote party is the initiator, only our inputs and
+	// outputs other than the funding ones are added to its splice.
+	proposal *lnwallet.SpliceContribution
+
+	// stfuRes delivers the outcome of quiescing the channel, which has to
+	// happen before we can send splice_init. It is only set if we
+	// requested a splice.
+	stfuRes <-chan fn.Result[lntypes.ChannelParty]
+
+	// state is the current step of the negotiation.
+	state spliceState
+
+	// balanceDeltas are the amounts both parties add to, or remove from,
+	// their channel balance as announced in splice_init and splice_ack.
+	balanceDeltas lntypes.Dual[btcutil.Amount]
+
+	// interactiveTx tracks the construction of the splice transaction.
+	interactiveTx *chanfunding.InteractiveTx
+
+	// pendingUpdates are the tx_add_input and tx_add_output messages we
+	// still have to send, one per turn.
+	pendingUpdates []lnwire.Message
+
+	// spliceTx is the splice transaction once its construction is
+	// complete.
+	spliceTx *wire.MsgTx
+
+	// candidate holds the new commitments spending the funding output
+	// created by spliceTx.
+	candidate *channeldb.SpliceCandidate
+
+	// localInputSig is our signature for the input of spliceTx spending
+	// the current funding output.
+	localInputSig input.Signature
+}
+
+// InitSplice requests that the link negotiate the splice transaction described
+// by the passed contribution with the remote party. The channel is quiesced
+// for the duration of the negotiation. The returned channel is resolved with
+// the fully signed splice transaction, or an error if the negotiation fails.
+func (l *channelLink) InitSplice(
+	c *lnwallet.SpliceContribution) <-chan fn.Result[*wire.MsgTx] {
+
+	req, out := fn.NewReq[*lnwallet.SpliceContribution,
+		fn.Result[*wire.MsgTx]](c)
+
+	select {
+	case l.spliceReqs <- req:
+	case <-l.Quit:
+		req.Resolve(fn.Err[*wire.MsgTx](ErrLinkShuttingDown))
+	}
+
+	return out
+}
+
+// hasPendingSplice returns true if a negotiated splice of the channel hasn't
+// been locked by both parties yet.
+func (l *channelLink) hasPendingSplice() bool {
+	return len(l.channel.PendingSplices()) > 0
+}
+
+// handleSpliceReq starts a new splice negotiation by asking the remote party
+// to quiesce the channel.
+func (l *channelLink) handleSpliceReq(req SpliceReq) {
+	if l.cfg.DisallowSplice || l.cfg.DisallowQuiescence {
+		req.Resolve(fn.Err[*wire.MsgTx](ErrSpliceDisallowed))
+		return
+	}
+
+	if l.splice != nil || l.dynCommit != nil || l.hasPendingSplice() {
+		req.Resolve(fn.Err[*wire.MsgTx](ErrSpliceInProgress))
+		return
+	}
+
+	if err := l.channel.CheckSpliceable(); err != nil {
+		req.Resolve(fn.Err[*wire.MsgTx](err))
+		return
+	}
+
+	stfuReq, stfuRes := fn.NewReq[fn.Unit, fn.Result[lntypes.ChannelParty]](
+		fn.Unit{},
+	)
+	l.quiescer.InitStfu(stfuReq)
+
+	l.splice = &spliceSession{
+		initiator: lntypes.Local,
+		req:       fn.Some(req),
+		proposal:  req.Request,
+		stfuRes:   stfuRes,
+		state:     spliceAwaitStfu,
+	}
+
+	if err := l.sendOwedStfu(); err != nil {
+		l.stfuFailf("unable to send stfu: %v", err)
+		return
+	}
+
+	l.maybeSendSpliceInit()
+}
+
+// maybeSendSpliceInit sends our splice_init to the remote party once the
+// channel has become quiescent with us as the initiator.
+func (l *channelLink) maybeSendSpliceInit() {
+	session := l.splice
+	if session == nil || session.state != spliceAwaitStfu {
+		return
+	}
+
+	var res fn.Result[lntypes.ChannelParty]
+	select {
+	case res = <-session.stfuRes:
+	default:
+		// The channel isn't quiescent yet.
+		return
+	}
+
+	initiator, err := res.Unpack()
+	if err != nil {
+		l.resolveSplice(err)
+		return
+	}
+
+	// Only the initiator of the quiescence session may propose a splice.
+	// If both of us attempted to quiesce the channel at the same time and
+	// the remote party won, we'll contribute to its splice instead.
+	if initiator != lntypes.Local {
+		l.log.Infof("remote party is quiescence initiator, waiting " +
+			"for its splice_init")
+
+		session.state = spliceAwaitInit
+
+		return
+	}
+
+	if err := l.channel.CheckSpliceable(); err != nil {
+		l.resolveSplice(err)
+		l.quiescer.Resume()
+
+		return
+	}
+
+	proposal := session.proposal
+	state := l.channel.State()
+	spliceInit := &lnwire.SpliceInit{
+		ChanID:              l.ChanID(),
+		FundingContribution: proposal.LocalDelta,
+		FundingFeerate:      proposal.FeeRate,
+		Locktime:            proposal.Tx.LockTime,
+		FundingPubKey:       state.LocalChanCfg.MultiSigKey.PubKey,
+	}
+	if err := l.cfg.Peer.SendMessage(false, spliceInit); err != nil {
+		l.resolveSplice(err)
+		l.quiescer.Resume()
+
+		return
+	}
+
+	session.state = spliceAwaitAck
+	session.balanceDeltas.Local = proposal.LocalDelta
+	session.interactiveTx = chanfunding.NewInteractiveTx(
+		lntypes.Local, proposal.Tx.LockTime,
+	)
+}
+
+// spliceTxUpdates returns the messages adding our inputs and outputs of the
+// splice transaction described by the passed contribution. Each of them is
+// identified by a serial ID matching its position in the transaction, which
+// ensures that the remote party orders our inputs and outputs the same way.
+// As the initiator, we also add the input spending the current funding output
+// and the new funding output, which carries the remote party's contribution
+// on top of ours. Otherwise both of them are left to the initiator.
+func (l *channelLink) spliceTxUpdates(c *lnwallet.SpliceContribution,
+	initiator lntypes.ChannelParty,
+	remoteDelta btcutil.Amount) ([]lnwire.Message, error) {
+
+	fundingOutpoint := l.channel.State().CurrentFundingOutpoint()
+	fundingPkScript := l.channel.FundingPkScript()
+
+	// The initiator uses even serial IDs, the acceptor odd ones.
+	serialID := func(i int) uint64 {
+		if initiator == lntypes.Local {
+			return uint64(2 * i)
+		}
+
+		return uint64(2*i + 1)
+	}
+
+	var updates []lnwire.Message
+	for i, txIn := range c.Tx.TxIn {
+		addInput := &lnwire.TxAddInput{
+			ChanID:    l.ChanID(),
+			SerialID:  serialID(i),
+			PrevTxOut: txIn.PreviousOutPoint.Index,
+			Sequence:  txIn.Sequence,
+		}
+
+		if txIn.PreviousOutPoint == fundingOutpoint {
+			if initiator != lntypes.Local {
+				continue
+			}
+
+			txid := [32]byte(fundingOutpoint.Hash)
+			addInput.SharedInputTxid = tlv.SomeRecordT(
+				tlv.NewPrimitiveRecord[tlv.TlvType0](txid),
+			)
+			updates = append(updates, addInput)
+
+			continue
+		}
+
+		prevTx, ok := c.PrevTxs[txIn.PreviousOutPoint]
+		if !ok {
+			return nil, fmt.Errorf("missing previous transaction "+
+				"for input %v", txIn.PreviousOutPoint)
+		}
+
+		var b bytes.Buffer
+		if err := prevTx.Serialize(&b); err != nil {
+			return nil, err
+		}
+		addInput.PrevTx = b.Bytes()
+
+		updates = append(updates, addInput)
+	}
+
+	for i, txOut := range c.Tx.TxOut {
+		amt := btcutil.Amount(txOut.Value)
+		if bytes.Equal(txOut.PkScript, fundingPkScript) {
+			if initiator != lntypes.Local {
+				continue
+			}
+
+			amt += remoteDelta
+		}
+
+		updates = append(updates, &lnwire.TxAddOutput{
+			ChanID:   l.ChanID(),
+			SerialID: serialID(i),
+			Amount:   amt,
+			PkScript: txOut.PkScript,
+		})
+	}
+
+	return updates, nil
+}
+
+// resolveSplice resolves the active splice request, if any, with the passed
+// error and ends the splice session.
+func (l *channelLink) resolveSplice(err error) {
+	if l.splice == nil {
+		return
+	}
+
+	l.splice.req.WhenSome(func(req SpliceReq) {
+		req.Resolve(fn.Err[*wire.MsgTx](err))
+	})
+	l.splice = nil
+}
+
+// abortSplice ends the splice negotiation with the passed error, informs the
+// remote party using tx_abort and resumes the channel.
+func (l *channelLink) abortSplice(err error) {
+	l.log.Warnf("aborting splice: %v", err)
+
+	txAbort := &lnwire.TxAbort{
+		ChanID: l.ChanID(),
+		Data:   lnwire.ErrorData(err.Error()),
+	}
+	if err := l.cfg.Peer.SendMessage(false, txAbort); err != nil {
+		l.log.Errorf("unable to send tx_abort: %v", err)
+	}
+
+	l.resolveSplice(fmt.Errorf("%w: %w", ErrSpliceAborted, err))
+	l.quiescer.Resume()
+}
+
+// handleSpliceInit processes a splice proposed by the remote party, which must
+// be the initiator of the current quiescence session. If we accept it, we
+// respond with splice_ack and start adding our own inputs and outputs to the
+// splice transaction once it's our turn. If we requested a splice ourselves,
+// but lost the right to propose it, it becomes our contribution.
+func (l *channelLink) handleSpliceInit(msg *lnwire.SpliceInit) {
+	initiator, err := l.quiescer.QuiescenceInitiator().Unpack()
+	switch {
+	case err != nil:
+		l.spliceFailf("splice_init received while not quiescent")
+		return
+
+	case initiator != lntypes.Remote:
+		l.spliceFailf("splice_init received from non-initiator")
+		return
+	}
+
+	// Make sure we learned about the outcome of quiescing the channel if
+	// we requested a splice ourselves.
+	l.maybeSendSpliceInit()
+
+	session := l.splice
+	if session != nil && session.state != spliceAwaitInit {
+		l.spliceFailf("splice_init received during splice")
+		return
+	}
+
+	state := l.channel.State()
+	remoteKey := state.RemoteChanCfg.MultiSigKey.PubKey
+
+	switch {
+	case l.cfg.DisallowSplice:
+		l.abortSplice(ErrSpliceDisallowed)
+		return
+
+	case l.hasPendingSplice():
+		l.abortSplice(ErrSpliceInProgress)
+		return
+
+	case !msg.FundingPubKey.IsEqual(remoteKey):
+		l.abortSplice(errors.New("funding key rotation not supported"))
+		return
+	}
+
+	if err := l.channel.CheckSpliceable(); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	// Our inputs are only signed once the final transaction is known, so
+	// a splice we requested with inputs that are already signed can't be
+	// added to the remote party's splice.
+	if session != nil && session.proposal.SignInputs == nil {
+		l.resolveSplice(ErrSpliceNotInitiator)
+		session = nil
+	}
+
+	var (
+		localDelta btcutil.Amount
+		updates    []lnwire.Message
+	)
+	if session != nil {
+		localDelta = session.proposal.LocalDelta
+		updates, err = l.spliceTxUpdates(
+			session.proposal, lntypes.Remote, 0,
+		)
+		if err != nil {
+			l.abortSplice(err)
+			return
+		}
+	} else {
+		session = &spliceSession{}
+		l.splice = session
+	}
+
+	spliceAck := &lnwire.SpliceAck{
+		ChanID:              l.ChanID(),
+		FundingContribution: localDelta,
+		FundingPubKey:       state.LocalChanCfg.MultiSigKey.PubKey,
+	}
+	if err := l.cfg.Peer.SendMessage(false, spliceAck); err != nil {
+		l.log.Errorf("unable to send splice_ack: %v", err)
+		l.resolveSplice(err)
+		l.quiescer.Resume()
+
+		return
+	}
+
+	session.initiator = lntypes.Remote
+	session.state = spliceNegotiating
+	session.balanceDeltas = lntypes.Dual[btcutil.Amount]{
+		Local:  localDelta,
+		Remote: msg.FundingContribution,
+	}
+	session.interactiveTx = chanfunding.NewInteractiveTx(
+		lntypes.Remote, msg.Locktime,
+	)
+	session.pendingUpdates = updates
+}
+
+// handleSpliceAck starts the construction of the splice transaction once the
+// remote party has accepted our splice_init.
+func (l *channelLink) handleSpliceAck(msg *lnwire.SpliceAck) {
+	session := l.splice
+	if session == nil || session.state != spliceAwaitAck {
+		l.spliceFailf("unexpected splice_ack")
+		return
+	}
+
+	remoteKey := l.channel.State().RemoteChanCfg.MultiSigKey.PubKey
+
+	switch {
+	// If the remote party contributes to the splice, the funding output
+	// changes, so we must be able to sign our inputs afterwards.
+	case msg.FundingContribution != 0 &&
+		session.proposal.SignInputs == nil:
+
+		l.abortSplice(errors.New("remote contributions not " +
+			"supported for pre-signed splice"))
+
+		return
+
+	case !msg.FundingPubKey.IsEqual(remoteKey):
+		l.abortSplice(errors.New("funding key rotation not supported"))
+		return
+	}
+
+	updates, err := l.spliceTxUpdates(
+		session.proposal, lntypes.Local, msg.FundingContribution,
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	session.balanceDeltas.Remote = msg.FundingContribution
+	session.pendingUpdates = updates
+	session.state = spliceNegotiating
+	l.sendNextSpliceTxUpdate()
+}
+
+// sendNextSpliceTxUpdate sends our next input or output of the splice
+// transaction, or tx_complete if all of them have been sent.
+func (l *channelLink) sendNextSpliceTxUpdate() {
+	session := l.splice
+
+	if len(session.pendingUpdates) == 0 {
+		l.sendSpliceTxComplete()
+		return
+	}
+
+	update := session.pendingUpdates[0]
+	session.pendingUpdates = session.pendingUpdates[1:]
+
+	if err := l.applySpliceTxUpdate(lntypes.Local, update); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	if err := l.cfg.Peer.SendMessage(false, update); err != nil {
+		l.abortSplice(err)
+	}
+}
+
+// sendSpliceTxComplete signals the remote party that we have nothing left to
+// add to the splice transaction, and completes the construction if the
+// remote party did the same.
+func (l *channelLink) sendSpliceTxComplete() {
+	txComplete := &lnwire.TxComplete{
+		ChanID: l.ChanID(),
+	}
+	if err := l.cfg.Peer.SendMessage(false, txComplete); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	if l.splice.interactiveTx.Complete(lntypes.Local) {
+		l.completeSpliceTx()
+	}
+}
+
+// spliceInput converts the passed tx_add_input into an input of the splice
+// transaction. The input spending the current funding output is marked as
+// shared.
+func (l *channelLink) spliceInput(
+	msg *lnwire.TxAddInput) (chanfunding.InteractiveTxInput, error) {
+
+	in := chanfunding.InteractiveTxInput{
+		SerialID: msg.SerialID,
+		Sequence: msg.Sequence,
+	}
+
+	sharedTxid, err := msg.SharedInputTxid.UnwrapOrErrV(errNoSharedInput)
+	if err == nil {
+		state := l.channel.State()
+		fundingOutpoint := state.CurrentFundingOutpoint()
+
+		in.OutPoint = wire.OutPoint{
+			Hash:  chainhash.Hash(sharedTxid),
+			Index: msg.PrevTxOut,
+		}
+		if in.OutPoint != fundingOutpoint {
+			return in, fmt.Errorf("shared input %v doesn't spend "+
+				"funding output %v", in.OutPoint,
+				fundingOutpoint)
+		}
+
+		in.PrevOut = wire.TxOut{
+			Value:    int64(state.Capacity),
+			PkScript: l.channel.FundingPkScript(),
+		}
+		in.Shared = true
+
+		return in, nil
+	}
+
+	var prevTx wire.MsgTx
+	if err := prevTx.Deserialize(bytes.NewReader(msg.PrevTx)); err != nil {
+		return in, fmt.Errorf("invalid prev tx: %w", err)
+	}
+	if int(msg.PrevTxOut) >= len(prevTx.TxOut) {
+		return in, fmt.Errorf("invalid prev tx output index %d",
+			msg.PrevTxOut)
+	}
+
+	in.OutPoint = wire.OutPoint{
+		Hash:  prevTx.TxHash(),
+		Index: msg.PrevTxOut,
+	}
+	in.PrevOut = *prevTx.TxOut[msg.PrevTxOut]
+
+	return in, nil
+}
+
+// errNoSharedInput is used to detect inputs that don't carry the txid of the
+// shared input.
+var errNoSharedInput = errors.New("not a shared input")
+
+// applySpliceTxUpdate applies an input or output added or removed by the given
+// party to the splice transaction.
+func (l *channelLink) applySpliceTxUpdate(party lntypes.ChannelParty,
+	msg lnwire.Message) error {
+
+	interactiveTx := l.splice.interactiveTx
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		in, err := l.spliceInput(msg)
+		if err != nil {
+			return err
+		}
+
+		return interactiveTx.AddInput(party, in)
+
+	case *lnwire.TxAddOutput:
+		return interactiveTx.AddOutput(
+			party, chanfunding.InteractiveTxOutput{
+				SerialID: msg.SerialID,
+				TxOut: wire.TxOut{
+					Value:    int64(msg.Amount),
+					PkScript: msg.PkScript,
+				},
+			},
+		)
+
+	case *lnwire.TxRemoveInput:
+		return interactiveTx.RemoveInput(party, msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		return interactiveTx.RemoveOutput(party, msg.SerialID)
+
+	default:
+		return fmt.Errorf("unexpected splice tx update %T", msg)
+	}
+}
+
+// handleSpliceTxUpdate processes an input or output the remote party added
+// to, or removed from, the splice transaction. It's our turn afterwards.
+func (l *channelLink) handleSpliceTxUpdate(msg lnwire.Message) {
+	session := l.splice
+	if session == nil || session.state != spliceNegotiating {
+		l.spliceFailf("unexpected %v", msg.MsgType())
+		return
+	}
+
+	if err := l.applySpliceTxUpdate(lntypes.Remote, msg); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	l.sendNextSpliceTxUpdate()
+}
+
+// handleSpliceTxComplete processes the remote party's tx_complete.
+func (l *channelLink) handleSpliceTxComplete() {
+	session := l.splice
+	if session == nil || session.state != spliceNegotiating {
+		l.spliceFailf("unexpected tx_complete")
+		return
+	}
+
+	if session.interactiveTx.Complete(lntypes.Remote) {
+		l.completeSpliceTx()
+		return
+	}
+
+	// It's our turn, so we'll either send our next update or signal that
+	// we're done as well.
+	l.sendNextSpliceTxUpdate()
+}
+
+// completeSpliceTx validates the constructed splice transaction, signs our
+// inputs, creates the new commitments spending its funding output and sends
+// our signature for the remote party's commitment.
+func (l *channelLink) completeSpliceTx() {
+	session := l.splice
+	spliceTx := session.interactiveTx.Tx()
+
+	// The remote party must fund the change of its balance with the inputs
+	// and outputs it added, the funding output is shared by both of us.
+	// Anything beyond that goes towards the fees.
+	fundingPkScript := l.channel.FundingPkScript()
+	remoteFunds := session.interactiveTx.Contribution(
+		lntypes.Remote, func(txOut *wire.TxOut) bool {
+			return bytes.Equal(txOut.PkScript, fundingPkScript)
+		},
+	)
+	if remoteFunds < session.balanceDeltas.Remote {
+		l.abortSplice(fmt.Errorf("remote party contributes %v to "+
+			"splice, expected at least %v", remoteFunds,
+			session.balanceDeltas.Remote))
+
+		return
+	}
+
+	// The change in capacity must match the contributions announced in
+	// splice_init and splice_ack, which is checked when creating the new
+	// commitments.
+	candidate, err := l.channel.NewSpliceCandidate(
+		spliceTx, session.balanceDeltas,
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	if err := l.signSpliceTxInputs(spliceTx); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	ctx, done := l.WithCtxQuitNoTimeout()
+	defer done()
+
+	sigs, err := l.channel.SignSpliceCommitment(ctx, candidate)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	err = l.cfg.Peer.SendMessage(false, &lnwire.CommitSig{
+		ChanID:    l.ChanID(),
+		CommitSig: sigs.CommitSig,
+		HtlcSigs:  sigs.HtlcSigs,
+	})
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	session.spliceTx = spliceTx
+	session.candidate = candidate
+	session.state = spliceAwaitCommitSig
+}
+
+// signSpliceTxInputs adds the witnesses of the inputs we contributed to the
+// splice transaction.
+func (l *channelLink) signSpliceTxInputs(spliceTx *wire.MsgTx) error {
+	session := l.splice
+	proposal := session.proposal
+	if proposal == nil {
+		return nil
+	}
+
+	// Without a way to sign the final transaction, our inputs were signed
+	// up front, which requires the transaction to be unchanged.
+	if proposal.SignInputs == nil {
+		if spliceTx.TxHash() != proposal.Tx.TxHash() {
+			return fmt.Errorf("constructed splice tx %v doesn't "+
+				"match requested tx %v", spliceTx.TxHash(),
+				proposal.Tx.TxHash())
+		}
+
+		for i, txIn := range proposal.Tx.TxIn {
+			spliceTx.TxIn[i].SignatureScript = txIn.SignatureScript
+			spliceTx.TxIn[i].Witness = txIn.Witness
+		}
+
+		return nil
+	}
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for _, in := range session.interactiveTx.Inputs() {
+		prevOut := in.PrevOut
+		prevOuts.AddPrevOut(in.OutPoint, &prevOut)
+	}
+
+	return proposal.SignInputs(spliceTx, prevOuts)
+}
+
+// handleSpliceCommitSig processes the remote party's signature for our new
+// commitment spending the funding output of the splice transaction. Once
+// received, the acceptor of the splice is the first to send its signatures
+// for the splice transaction.
+func (l *channelLink) handleSpliceCommitSig(msg *lnwire.CommitSig) {
+	session := l.splice
+	if session.state != spliceAwaitCommitSig {
+		l.spliceFailf("unexpected commit_sig")
+		return
+	}
+
+	err := l.channel.ReceiveSpliceCommitment(
+		session.candidate, &lnwallet.CommitSigs{
+			CommitSig: msg.CommitSig,
+			HtlcSigs:  msg.HtlcSigs,
+		},
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	session.localInputSig, err = l.channel.SignSpliceInput(
+		session.spliceTx,
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	session.state = spliceAwaitTxSigs
+	if session.initiator == lntypes.Local {
+		return
+	}
+
+	// As soon as we hand out our signature, the remote party is able to
+	// publish the splice transaction, so we must persist the new
+	// commitments first, along with our signatures in case they need to
+	// be retransmitted.
+	session.candidate.LocalTxSigs, err = l.spliceTxSigs()
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+	if err := l.channel.AddSpliceCandidate(session.candidate); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	err = l.cfg.Peer.SendMessage(false, session.candidate.LocalTxSigs)
+	if err != nil {
+		l.log.Errorf("unable to send tx_signatures: %v", err)
+	}
+}
+
+// spliceTxSigs returns our signatures for the splice transaction. These are
+// the witnesses of all the inputs we contributed in the order of their serial
+// IDs, along with our signature for the shared input.
+func (l *channelLink) spliceTxSigs() (*lnwire.TxSignatures, error) {
+	session := l.splice
+
+	sharedSig, err := lnwire.NewSigFromSignature(session.localInputSig)
+	if err != nil {
+		return nil, err
+	}
+
+	txSigs := &lnwire.TxSignatures{
+		ChanID: l.ChanID(),
+		Txid:   session.spliceTx.TxHash(),
+		SharedInputSig: tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType0](sharedSig),
+		),
+	}
+
+	for _, idx := range spliceInputIndices(
+		session.interactiveTx, lntypes.Local,
+	) {
+
+		txSigs.Witnesses = append(
+			txSigs.Witnesses, session.spliceTx.TxIn[idx].Witness,
+		)
+	}
+
+	return txSigs, nil
+}
+
+// handleSpliceTxSigs processes the remote party's signatures for the splice
+// transaction, which completes the splice negotiation.
+func (l *channelLink) handleSpliceTxSigs(msg *lnwire.TxSignatures) {
+	session := l.splice
+	if session == nil {
+		l.handleResentSpliceTxSigs(msg)
+		return
+	}
+	if session.state != spliceAwaitTxSigs {
+		l.spliceFailf("unexpected tx_signatures")
+		return
+	}
+
+	spliceTxid := session.spliceTx.TxHash()
+	if msg.Txid != spliceTxid {
+		l.abortSplice(fmt.Errorf("tx_signatures for unknown tx %v",
+			msg.Txid))
+
+		return
+	}
+
+	remoteSig, err := msg.SharedInputSig.UnwrapOrErrV(
+		errors.New("missing shared input signature"),
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	err = setSpliceWitnesses(
+		session.spliceTx, session.interactiveTx, msg.Witnesses,
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	err = l.channel.CompleteSpliceInput(
+		session.spliceTx, session.localInputSig, remoteSig,
+	)
+	if err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	// Now that the splice transaction is fully signed, we'll persist it
+	// along with the new commitments. As the initiator, we send our
+	// signatures only now, so we'll persist them as well in case they
+	// need to be retransmitted.
+	if session.initiator == lntypes.Local {
+		session.candidate.LocalTxSigs, err = l.spliceTxSigs()
+		if err != nil {
+			l.abortSplice(err)
+			return
+		}
+	}
+	if err := l.channel.AddSpliceCandidate(session.candidate); err != nil {
+		l.abortSplice(err)
+		return
+	}
+
+	if session.initiator == lntypes.Local {
+		err := l.cfg.Peer.SendMessage(
+			false, session.candidate.LocalTxSigs,
+		)
+		if err != nil {
+			l.log.Errorf("unable to send tx_signatures: %v", err)
+		}
+	}
+
+	l.log.Infof("splice negotiation complete, awaiting confirmation "+
+		"of splice tx %v", spliceTxid)
+
+	session.req.WhenSome(func(req SpliceReq) {
+		req.Resolve(fn.Ok(session.spliceTx))
+	})
+	l.splice = nil
+
+	// The channel keeps operating on both the current and the new funding
+	// output until the splice is locked, so we'll leave quiescence.
+	l.quiescer.Resume()
+}
+
+// handleResentSpliceTxSigs processes tx_signatures the remote party
+// retransmitted after a reconnection, as we asked for them in our
+// channel_reestablish. The splice session is gone by then, but our own inputs
+// of the stored splice transaction are already signed, so the remote party's
+// witnesses belong to the remaining inputs other than the shared one.
+func (l *channelLink) handleResentSpliceTxSigs(msg *lnwire.TxSignatures) {
+	state := l.channel.State()
+	candidates, err := state.SpliceCandidates()
+	if err != nil {
+		l.failf(LinkFailureError{code: ErrInternalError},
+			"unable to fetch splice candidates: %v", err)
+
+		return
+	}
+
+	idx := slices.IndexFunc(
+		candidates, func(c *channeldb.SpliceCandidate) bool {
+			return c.Txid() == msg.Txid && !c.FullySigned()
+		},
+	)
+	if idx < 0 {
+		l.spliceFailf("unexpected tx_signatures for %v", msg.Txid)
+		return
+	}
+	candidate := candidates[idx]
+	spliceTx := candidate.SpliceTx
+
+	remoteSig, err := msg.SharedInputSig.UnwrapOrErrV(
+		errors.New("missing shared input signature"),
+	)
+	if err != nil {
+		l.spliceFailf("invalid tx_signatures: %v", err)
+		return
+	}
+
+	fundingOutpoint := state.CurrentFundingOutpoint()
+	var remoteInputs []int
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == fundingOutpoint ||
+			len(txIn.Witness) != 0 {
+
+			continue
+		}
+
+		remoteInputs = append(remoteInputs, i)
+	}
+	if len(remoteInputs) != len(msg.Witnesses) {
+		l.spliceFailf("expected %d witnesses, got %d",
+			len(remoteInputs), len(msg.Witnesses))
+
+		return
+	}
+	for i, idx := range remoteInputs {
+		spliceTx.TxIn[idx].Witness = msg.Witnesses[i]
+	}
+
+	localSig, err := l.channel.SignSpliceInput(spliceTx)
+	if err != nil {
+		l.failf(LinkFailureError{code: ErrInternalError},
+			"unable to sign splice input: %v", err)
+
+		return
+	}
+	err = l.channel.CompleteSpliceInput(spliceTx, localSig, remoteSig)
+	if err != nil {
+		l.spliceFailf("invalid tx_signatures: %v", err)
+		return
+	}
+
+	// The commitments of the candidate are attached to the channel
+	// already, so we only replace the stored splice transaction.
+	if err := state.AddSpliceCandidate(candidate); err != nil {
+		l.failf(LinkFailureError{code: ErrInternalError},
+			"unable to store splice tx: %v", err)
+
+		return
+	}
+
+	l.log.Infof("received retransmitted tx_signatures, splice tx %v is "+
+		"fully signed", msg.Txid)
+}
+
+// resendSpliceTxSigs returns the message to retransmit to a remote party that
+// told us in its channel_reestablish that it didn't receive our tx_signatures
+// for the splice transaction with the given txid. If we don't know the
+// transaction, we'll send tx_abort so it knows the splice won't be completed.
+func (l *channelLink) resendSpliceTxSigs(
+	txid chainhash.Hash) (lnwire.Message, error) {
+
+	candidates, err := l.channel.State().SpliceCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range candidates {
+		if c.Txid() == txid && c.LocalTxSigs != nil {
+			l.log.Infof("resending tx_signatures for splice %v",
+				txid)
+
+			return c.LocalTxSigs, nil
+		}
+	}
+
+	l.log.Warnf("remote party awaits tx_signatures for unknown splice %v",
+		txid)
+
+	reason := fmt.Sprintf("unknown splice %v", txid)
+
+	return &lnwire.TxAbort{
+		ChanID: l.ChanID(),
+		Data:   lnwire.ErrorData(reason),
+	}, nil
+}
+
+// spliceInputIndices returns the indices of the inputs the given party
+// contributed to the splice transaction, leaving out the shared input.
+func spliceInputIndices(interactiveTx *chanfunding.InteractiveTx,
+	party lntypes.ChannelParty) []int {
+
+	var indices []int
+	for i, in := range interactiveTx.Inputs() {
+		if in.Shared || interactiveTx.SerialIDParty(in.SerialID) != party {
+			continue
+		}
+
+		indices = append(indices, i)
+	}
+
+	return indices
+}
+
+// setSpliceWitnesses sets the passed witnesses on the inputs the remote party
+// contributed to the splice transaction.
+func setSpliceWitnesses(spliceTx *wire.MsgTx,
+	interactiveTx *chanfunding.InteractiveTx,
+	witnesses []wire.TxWitness) error {
+
+	indices := spliceInputIndices(interactiveTx, lntypes.Remote)
+	if len(witnesses) != len(indices) {
+		return fmt.Errorf("expected %d witnesses, got %d",
+			len(indices), len(witnesses))
+	}
+
+	for i, idx := range indices {
+		spliceTx.TxIn[idx].Witness = witnesses[i]
+	}
+
+	return nil
+}
+
+// handleTxAbort processes the remote party's tx_abort, which ends the splice
+// negotiation.
+func (l *channelLink) handleTxAbort(msg *lnwire.TxAbort) {
+	if l.splice == nil {
+		l.log.Debugf("ignoring tx_abort outside of splice "+
+			"negotiation: %v", msg.Data)
+
+		return
+	}
+
+	l.log.Warnf("remote party aborted splice: %v", msg.Data)
+
+	l.resolveSplice(fmt.Errorf("%w by remote party: %v",
+		ErrSpliceAborted, string(msg.Data)))
+	l.quiescer.Resume()
+}
+
+// handleSpliceLocked lets the remote party know that a splice transaction of
+// the channel reached the negotiated depth, and locks the splice if the
+// remote party did the same.
+func (l *channelLink) handleSpliceLocked(
+	info *contractcourt.SpliceLockedInfo) {
+
+	spliceTxid := info.SpliceTx.TxHash()
+
+	// We might learn about the same splice more than once, for example if
+	// it was already locked by both of us. The chain watcher delivers a
+	// confirmed splice to every new subscription, so we resend our
+	// splice_locked after each reconnection until the splice is locked.
+	if !slices.Contains(l.channel.PendingSplices(), spliceTxid) ||
+		l.spliceLocked.Local == fn.Some(spliceTxid) {
+
+		return
+	}
+
+	spliceLocked := &lnwire.SpliceLocked{
+		ChanID:     l.ChanID(),
+		SpliceTxid: spliceTxid,
+	}
+	if err := l.cfg.Peer.SendMessage(false, spliceLocked); err != nil {
+		l.log.Errorf("unable to send splice_locked: %v", err)
+		return
+	}
+	l.spliceLocked.Local = fn.Some(spliceTxid)
+	l.lockedSpliceScid = info.ShortChanID
+
+	l.maybeLockSplice()
+}
+
+// handleRemoteSpliceLocked processes the remote party's splice_locked, and
+// locks the splice if we did the same.
+func (l *channelLink) handleRemoteSpliceLocked(msg *lnwire.SpliceLocked) {
+	l.log.Infof("remote party locked splice %v", msg.SpliceTxid)
+
+	// The remote party resends splice_locked on reconnection, even if the
+	// splice was already locked by both of us.
+	if !slices.Contains(l.channel.PendingSplices(), msg.SpliceTxid) {
+		return
+	}
+
+	l.spliceLocked.Remote = fn.Some(msg.SpliceTxid)
+
+	l.maybeLockSplice()
+}
+
+// maybeLockSplice switches the channel over to the funding output of a splice
+// once both parties sent splice_locked for it.
+func (l *channelLink) maybeLockSplice() {
+	local, remote := l.spliceLocked.Local, l.spliceLocked.Remote
+	if local.IsNone() || local != remote {
+		return
+	}
+
+	spliceTxid := local.UnwrapOr(chainhash.Hash{})
+	err := l.channel.LockSplice(spliceTxid, l.lockedSpliceScid)
+	if err != nil {
+		l.failf(LinkFailureError{code: ErrInternalError},
+			"unable to lock splice: %v", err)
+
+		return
+	}
+	l.spliceLocked = lntypes.Dual[fn.Option[chainhash.Hash]]{}
+
+	l.log.Infof("splice %v locked, new capacity=%v, short_chan_id=%v",
+		spliceTxid, l.channel.Capacity, l.lockedSpliceScid)
+
+	// Now that the channel is backed by the new funding output, its edge
+	// in the graph needs to be moved over as well.
+	l.cfg.NotifySpliceLocked(l.ChannelPoint())
+}
+
+// collectCommitSigBatch collects the passed commit_sig if it's part of a
+// batch. It returns the complete batch along with true once all of its
+// messages have been received. A commit_sig that isn't part of a batch is
+// complete on its own, in which case the returned batch is empty.
+func (l *channelLink) collectCommitSigBatch(
+	msg *lnwire.CommitSig) ([]*lnwire.CommitSig, bool) {
+
+	batchSize := msg.BatchSize.ValOpt().UnwrapOr(1)
+	if batchSize <= 1 {
+		return nil, true
+	}
+
+	l.commitSigBatch = append(l.commitSigBatch, msg)
+	if len(l.commitSigBatch) < int(batchSize) {
+		return nil, false
+	}
+
+	batch := l.commitSigBatch
+	l.commitSigBatch = nil
+
+	return batch, true
+}
+
+// spliceFailf fails the link in the case where the requirements of the splice
+// negotiation are violated. Only link state is affected as long as the splice
+// transaction isn't signed, so we merely drop the connection.
+func (l *channelLink) spliceFailf(format string, args ...interface{}) {
+	l.failf(LinkFailureError{
+		code:             ErrSpliceViolation,
+		FailureAction:    LinkFailureDisconnect,
+		PermanentFailure: false,
+		Warning:          true,
+	}, format, args...)
+}
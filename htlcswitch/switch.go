@@ -226,6 +226,11 @@ type Config struct {
 
 	// IsAlias returns whether or not a given SCID is an alias.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// ResourceManager, if set, decides whether forwarded htlcs may use
+	// the resources of their outgoing channel, and whether they are
+	// endorsed.
+	ResourceManager ResourceManager
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -1097,12 +1102,21 @@ func (s *Switch) parseFailedPayment(deobfuscator ErrorDecrypter,
 	default:
 		// We'll attempt to fully decrypt the onion encrypted
 		// error. If we're unable to then we'll bail early.
-		failure, err := deobfuscator.DecryptError(htlc.Reason)
+		failure, err := deobfuscator.DecryptError(
+			htlc.Reason, htlc.AttrData.ValOpt().UnwrapOr(nil),
+		)
 		if err != nil {
 			log.Errorf("unable to de-obfuscate onion failure "+
 				"(hash=%v, pid=%d): %v",
 				paymentHash, attemptID, err)
 
+			// If the attribution data tells us which hop garbled
+			// the failure, we pass that on.
+			var unreadable *UnreadableFailureError
+			if errors.As(err, &unreadable) {
+				return unreadable
+			}
+
 			return ErrUnreadableFailureMessage
 		}
 
@@ -1215,7 +1229,9 @@ func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
 	// Encrypt the failure so that the sender will be able to read the error
 	// message. Since we failed this packet, we use EncryptFirstHop to
 	// obfuscate the failure for their eyes only.
-	reason, err := packet.obfuscator.EncryptFirstHop(failure.WireMessage())
+	reason, attrData, err := packet.obfuscator.EncryptFirstHop(
+		failure.WireMessage(),
+	)
 	if err != nil {
 		err := fmt.Errorf("unable to obfuscate "+
 			"error: %v", err)
@@ -1242,7 +1258,8 @@ func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
 		obfuscator:      packet.obfuscator,
 		linkFailure:     failure,
 		htlc: &lnwire.UpdateFailHTLC{
-			Reason: reason,
+			Reason:   reason,
+			AttrData: attrDataRecord(attrData),
 		},
 	}
 
@@ -2151,6 +2168,13 @@ func (s *Switch) updateLinkAliases(link ChannelLink) {
 		// the baseIndex above as a key. Add it now.
 		s.baseIndex[linkScid] = linkScid
 	}
+
+	// If the channel has been spliced, it's known to the network by the
+	// SCID of its new funding output, so we'll map that one to the link
+	// as well.
+	link.spliceScid().WhenSome(func(spliceScid lnwire.ShortChannelID) {
+		s.baseIndex[spliceScid] = linkScid
+	})
 }
 
 // GetLink is used to initiate the handling of the get link command. The
@@ -2291,8 +2315,11 @@ func (s *Switch) getLinkByMapping(pkt *htlcPacket) (ChannelLink, error) {
 
 	// If the link is unadvertised, we fail since the real SCID was used to
 	// forward over it and this is a channel where the option-scid-alias
-	// feature bit was negotiated.
-	if link.IsUnadvertised() {
+	// feature bit was negotiated. Spliced channels without that feature
+	// are found through the SCID of their new funding output, which is
+	// the one that was handed out for them.
+	aliasFeature := link.isZeroConf() || link.negotiatedAliasFeature()
+	if link.IsUnadvertised() && aliasFeature {
 		return nil, ErrChannelLinkNotFound
 	}
 
@@ -2400,8 +2427,9 @@ func (s *Switch) removeLink(chanID lnwire.ChannelID) ChannelLink {
 }
 
 // UpdateShortChanID locates the link with the passed-in chanID and updates the
-// underlying channel state. This is only used in zero-conf channels to allow
-// the confirmed SCID to be updated.
+// underlying channel state. This is used in zero-conf channels to allow the
+// confirmed SCID to be updated, and in spliced channels to map the SCID of
+// their new funding output to the link.
 func (s *Switch) UpdateShortChanID(chanID lnwire.ChannelID) error {
 	s.indexMtx.Lock()
 	defer s.indexMtx.Unlock()
@@ -2420,17 +2448,10 @@ func (s *Switch) UpdateShortChanID(chanID lnwire.ChannelID) error {
 		return err
 	}
 
-	// Since the zero-conf channel is confirmed, we should populate the
-	// aliasToReal map and update the baseIndex.
-	aliases := link.getAliases()
-
-	confirmedScid := link.confirmedScid()
-
-	for _, alias := range aliases {
-		s.aliasToReal[alias] = confirmedScid
-	}
-
-	s.baseIndex[confirmedScid] = link.ShortChanID()
+	// Since the zero-conf channel is confirmed or the channel has been
+	// spliced, we should populate the aliasToReal map and update the
+	// baseIndex.
+	s.updateLinkAliases(link)
 
 	return nil
 }
@@ -2982,6 +3003,15 @@ func (s *Switch) handlePacketAdd(packet *htlcPacket,
 		return s.failAddPacket(packet, linkErr)
 	}
 
+	// Check that the htlc can use the resources of the destination link,
+	// and whether it should be endorsed.
+	if s.cfg.ResourceManager != nil {
+		linkErr := s.checkResources(packet, htlc, destination)
+		if linkErr != nil {
+			return s.failAddPacket(packet, linkErr)
+		}
+	}
+
 	// Send the packet to the destination channel link which manages the
 	// channel.
 	packet.outgoingChanID = destination.ShortChanID()
@@ -2989,6 +3019,60 @@ func (s *Switch) handlePacketAdd(packet *htlcPacket,
 	return destination.handleSwitchPacket(packet)
 }
 
+// checkResources asks the resource manager whether the htlc may be forwarded
+// on the destination link, and sets the experimental endorsement signal of
+// the outgoing htlc according to its decision. The signal is only updated if
+// the incoming link attached one, as that means it is forwarding the signal.
+func (s *Switch) checkResources(packet *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC, destination ChannelLink) *LinkError {
+
+	proposed := &ProposedHTLC{
+		IncomingCircuit:  packet.inKey(),
+		OutgoingChannel:  destination.ShortChanID(),
+		IncomingAmount:   packet.incomingAmount,
+		OutgoingAmount:   packet.amount,
+		IncomingExpiry:   packet.incomingTimeout,
+		CurrentHeight:    atomic.LoadUint32(&s.bestHeight),
+		IncomingEndorsed: isEndorsed(packet.inWireCustomRecords),
+	}
+
+	outcome, err := s.cfg.ResourceManager.ForwardHTLC(
+		proposed, destination.OutgoingHtlcLimits(),
+	)
+	if err != nil {
+		log.Errorf("Unable to check resources for htlc %v: %v",
+			packet.inKey(), err)
+
+		return NewLinkError(&lnwire.FailTemporaryChannelFailure{})
+	}
+
+	log.Tracef("Resource manager outcome for htlc %v: %v",
+		packet.inKey(), outcome)
+
+	var endorsement byte
+	switch outcome {
+	case ForwardEndorsed:
+		endorsement = lnwire.ExperimentalEndorsed
+
+	case ForwardUnendorsed:
+		endorsement = lnwire.ExperimentalUnendorsed
+
+	default:
+		return NewLinkError(&lnwire.FailTemporaryChannelFailure{})
+	}
+
+	endorsementType := uint64(lnwire.ExperimentalEndorsementType)
+	if _, ok := htlc.CustomRecords[endorsementType]; ok {
+		htlc.CustomRecords = htlc.CustomRecords.MergedCopy(
+			lnwire.CustomRecords{
+				endorsementType: {endorsement},
+			},
+		)
+	}
+
+	return nil
+}
+
 // handlePacketSettle handles forwarding a settle packet.
 func (s *Switch) handlePacketSettle(packet *htlcPacket) error {
 	// If the source of this packet has not been set, use the circuit map
@@ -3110,10 +3194,9 @@ func (s *Switch) handlePacketFail(packet *htlcPacket,
 	// If this is a resolution message, then we'll need to encrypt it as
 	// it's actually internally sourced.
 	case packet.isResolution:
-		var err error
 		// TODO(roasbeef): don't need to pass actually?
 		failure := &lnwire.FailPermanentChannelFailure{}
-		htlc.Reason, err = circuit.ErrorEncrypter.EncryptFirstHop(
+		reason, attrData, err := circuit.ErrorEncrypter.EncryptFirstHop(
 			failure,
 		)
 		if err != nil {
@@ -3121,6 +3204,9 @@ func (s *Switch) handlePacketFail(packet *htlcPacket,
 			log.Error(err)
 		}
 
+		htlc.Reason = reason
+		htlc.AttrData = attrDataRecord(attrData)
+
 	// Alternatively, if the remote party sends us an
 	// UpdateFailMalformedHTLC, then we'll need to convert this into a
 	// proper well formatted onion error as there's no HMAC currently.
@@ -3131,16 +3217,22 @@ func (s *Switch) handlePacketFail(packet *htlcPacket,
 			packet.incomingChanID, packet.incomingHTLCID,
 			packet.outgoingChanID, packet.outgoingHTLCID)
 
-		htlc.Reason = circuit.ErrorEncrypter.EncryptMalformedError(
-			htlc.Reason,
-		)
+		encrypter := circuit.ErrorEncrypter
+		reason, attrData := encrypter.EncryptMalformedError(htlc.Reason)
+
+		htlc.Reason = reason
+		htlc.AttrData = attrDataRecord(attrData)
 
 	default:
 		// Otherwise, it's a forwarded error, so we'll perform a
-		// wrapper encryption as normal.
-		htlc.Reason = circuit.ErrorEncrypter.IntermediateEncrypt(
-			htlc.Reason,
+		// wrapper encryption as normal, adding our hold time to the
+		// attribution data of the downstream hops.
+		reason, attrData := circuit.ErrorEncrypter.IntermediateEncrypt(
+			htlc.Reason, htlc.AttrData.ValOpt().UnwrapOr(nil),
 		)
+
+		htlc.Reason = reason
+		htlc.AttrData = attrDataRecord(attrData)
 	}
 
 	// Deliver this packet.
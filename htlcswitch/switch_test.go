@@ -2766,7 +2766,7 @@ func TestSwitchSendPayment(t *testing.T) {
 	// back. This request should be forwarded back to alice channel link.
 	obfuscator := NewMockObfuscator()
 	failure := lnwire.NewFailIncorrectDetails(update.Amount, 100)
-	reason, err := obfuscator.EncryptFirstHop(failure)
+	reason, _, err := obfuscator.EncryptFirstHop(failure)
 	require.NoError(t, err, "unable obfuscate failure")
 
 	if s.IsForwardedHTLC(aliceChannelLink.ShortChanID(), update.ID) {
@@ -4094,7 +4094,9 @@ func TestSwitchHoldForward(t *testing.T) {
 		OnionSHA256: shaOnionBlob,
 	}
 
-	fwdErr, err := newMockDeobfuscator().DecryptError(failPacket.Reason)
+	fwdErr, err := newMockDeobfuscator().DecryptError(
+		failPacket.Reason, nil,
+	)
 	require.NoError(t, err)
 	require.Equal(t, expectedFailure, fwdErr.WireMessage())
 
@@ -5568,7 +5570,7 @@ func testSwitchAliasInterceptFail(t *testing.T, zeroConf bool) {
 		require.True(t, ok)
 
 		fwdErr, err := newMockDeobfuscator().DecryptError(
-			failHtlc.Reason,
+			failHtlc.Reason, nil,
 		)
 		require.NoError(t, err)
 
@@ -5587,3 +5589,132 @@ func testSwitchAliasInterceptFail(t *testing.T, zeroConf bool) {
 
 	require.NoError(t, interceptSwitch.Stop())
 }
+
+// mockResourceManager is a resource manager that returns a fixed outcome, and
+// records the htlcs it was asked about.
+type mockResourceManager struct {
+	outcome  ForwardOutcome
+	proposed []*ProposedHTLC
+}
+
+// ForwardHTLC records the proposed htlc and returns the configured outcome.
+func (m *mockResourceManager) ForwardHTLC(htlc *ProposedHTLC,
+	_ ChannelLimits) (ForwardOutcome, error) {
+
+	m.proposed = append(m.proposed, htlc)
+
+	return m.outcome, nil
+}
+
+// TestSwitchResourceManager tests that the switch consults the resource
+// manager before forwarding htlcs, updates the endorsement signal of the
+// outgoing htlc according to its decision, and fails htlcs for which no
+// resources are left.
+func TestSwitchResourceManager(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err)
+
+	resourceMgr := &mockResourceManager{outcome: ForwardUnendorsed}
+	s.cfg.ResourceManager = resourceMgr
+
+	require.NoError(t, s.Start())
+	t.Cleanup(func() {
+		require.NoError(t, s.Stop())
+	})
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	endorsementType := uint64(lnwire.ExperimentalEndorsementType)
+	newPacket := func(htlcID uint64) *htlcPacket {
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			incomingAmount: 1010,
+			amount:         1000,
+			obfuscator:     NewMockObfuscator(),
+			inWireCustomRecords: lnwire.CustomRecords{
+				endorsementType: {lnwire.ExperimentalEndorsed},
+			},
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: [32]byte{byte(htlcID)},
+				Amount:      1000,
+				CustomRecords: lnwire.CustomRecords{
+					endorsementType: {
+						lnwire.ExperimentalEndorsed,
+					},
+				},
+			},
+		}
+	}
+
+	// The endorsed htlc isn't endorsed by the resource manager, so the
+	// outgoing htlc is unendorsed.
+	require.NoError(t, s.ForwardPackets(nil, newPacket(0)))
+
+	select {
+	case pkt := <-bobChannelLink.packets:
+		htlc, ok := pkt.htlc.(*lnwire.UpdateAddHTLC)
+		require.True(t, ok)
+		require.Equal(t, []byte{lnwire.ExperimentalUnendorsed},
+			htlc.CustomRecords[endorsementType])
+
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	require.Len(t, resourceMgr.proposed, 1)
+	require.Equal(t, &ProposedHTLC{
+		IncomingCircuit: CircuitKey{
+			ChanID: aliceChannelLink.ShortChanID(),
+			HtlcID: 0,
+		},
+		OutgoingChannel:  bobChannelLink.ShortChanID(),
+		IncomingAmount:   1010,
+		OutgoingAmount:   1000,
+		CurrentHeight:    testStartingHeight,
+		IncomingEndorsed: true,
+	}, resourceMgr.proposed[0])
+
+	// Without resources, the htlc is failed back to the incoming link.
+	resourceMgr.outcome = ForwardNoResources
+	require.NoError(t, s.ForwardPackets(nil, newPacket(1)))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		_, ok := pkt.htlc.(*lnwire.UpdateFailHTLC)
+		require.True(t, ok)
+
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to the incoming link")
+	}
+
+	select {
+	case <-bobChannelLink.packets:
+		t.Fatal("htlc forwarded without resources")
+
+	default:
+	}
+}
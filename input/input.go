@@ -78,6 +78,11 @@ type TxInfo struct {
 
 	// Weight is the weight of the tx.
 	Weight lntypes.WeightUnit
+
+	// Tx is the tx itself, if we're able to broadcast it. It allows the
+	// tx to be submitted as a package together with a child paying for
+	// it, in case it doesn't meet the mempool minimum fee on its own.
+	Tx *wire.MsgTx
 }
 
 // String returns a human readable version of the tx info.
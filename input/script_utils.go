@@ -3029,6 +3029,27 @@ func CommitSpendAnchorAnyone(script []byte) (wire.TxWitness, error) {
 	return witnessStack, nil
 }
 
+// payToAnchorScript is the output script of a pay-to-anchor output.
+var payToAnchorScript = []byte{txscript.OP_1, txscript.OP_DATA_2, 0x4e, 0x73}
+
+// PayToAnchorScript returns the output script of a pay-to-anchor output, which
+// is the single anchor output of zero-fee commitments. As a segwit v1 output
+// with a two byte witness program, it isn't a taproot output and can be spent
+// by anyone with an empty witness.
+//
+// Output Script:
+//
+//	OP_1 <0x4e73>
+func PayToAnchorScript() []byte {
+	return bytes.Clone(payToAnchorScript)
+}
+
+// IsPayToAnchor returns true if the given output script is the one of a
+// pay-to-anchor output.
+func IsPayToAnchor(pkScript []byte) bool {
+	return bytes.Equal(pkScript, payToAnchorScript)
+}
+
 // SingleTweakBytes computes set of bytes we call the single tweak. The purpose
 // of the single tweak is to randomize all regular delay and payment base
 // points. To do this, we generate a hash that binds the commitment point to
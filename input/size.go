@@ -97,6 +97,18 @@ const (
 	//      - pkscript (p2tr): 34 bytes
 	P2TROutputSize = BaseOutputSize + P2TRSize
 
+	// P2ASize 4 bytes
+	//	- OP_1: 1 byte
+	//	- OP_DATA: 1 byte (witness program length)
+	//	- witness program: 2 bytes
+	P2ASize = 4
+
+	// P2AOutputSize 13 bytes
+	//      - value: 8 bytes
+	//      - var_int: 1 byte (pkscript_length)
+	//      - pkscript (p2a): 4 bytes
+	P2AOutputSize = BaseOutputSize + P2ASize
+
 	// P2PKHScriptSigSize 108 bytes
 	//      - OP_DATA: 1 byte (signature length)
 	//      - signature
@@ -256,6 +268,26 @@ const (
 		2*TaprootCommitmentOutput + 2*TaprootCommitmentAnchorOutput +
 		4) * witnessScaleFactor
 
+	// BaseZeroFeeCommitmentTxSize 150 + 43 * num-htlc-outputs bytes
+	//	- Version: 4 bytes
+	//	- WitnessHeader <---- part of the witness data
+	//	- CountTxIn: 1 byte
+	//	- TxIn: 41 bytes
+	//		FundingInput
+	//	- CountTxOut: 1 byte
+	//	- TxOut: 2*43 + 13 + 43 * num-htlc-outputs bytes
+	//		OutputPayingToThem,
+	//		OutputPayingToUs,
+	//		PayToAnchor,
+	//		....HTLCOutputs...
+	//	- LockTime: 4 bytes
+	BaseZeroFeeCommitmentTxSize = 4 + 1 + FundingInputSize + 1 +
+		2*CommitmentDelayOutput + P2AOutputSize + 4
+
+	// BaseZeroFeeCommitmentTxWeight 600 weight.
+	BaseZeroFeeCommitmentTxWeight = witnessScaleFactor *
+		BaseZeroFeeCommitmentTxSize
+
 	// CommitWeight 724 weight.
 	CommitWeight = BaseCommitmentTxWeight + WitnessCommitmentTxWeight
 
@@ -266,6 +298,10 @@ const (
 	TaprootCommitWeight = (BaseTaprootCommitmentTxWeight +
 		WitnessHeaderSize + TaprootKeyPathWitnessSize)
 
+	// ZeroFeeCommitWeight 824 weight.
+	ZeroFeeCommitWeight = BaseZeroFeeCommitmentTxWeight +
+		WitnessCommitmentTxWeight
+
 	// HTLCWeight 172 weight.
 	HTLCWeight = witnessScaleFactor * HTLCSize
 
@@ -315,6 +351,14 @@ const (
 	// weight limits.
 	MaxHTLCNumber = 966
 
+	// MaxZeroFeeCommitHTLCNumber 228
+	// MaxZeroFeeCommitHTLCNumber is the maximum number of HTLCs which can
+	// be included in a zero-fee commitment transaction. As these are v3
+	// transactions, the limit of 114 HTLCs per direction is set by the
+	// protocol to keep the commitment within the standard size limit of
+	// 10,000 vbytes of TRUC transactions.
+	MaxZeroFeeCommitHTLCNumber = 228
+
 	// ToLocalScriptSize 79 bytes
 	//      - OP_IF: 1 byte
 	//          - OP_DATA: 1 byte
@@ -580,6 +624,10 @@ const (
 	//      - witness_script (anchor_script)
 	AnchorWitnessSize = 1 + 1 + 73 + 1 + AnchorScriptSize
 
+	// PayToAnchorWitnessSize 1 byte
+	//      - number_of_witness_elements: 1 byte
+	PayToAnchorWitnessSize = 1
+
 	// TaprootSignatureWitnessSize 65 bytes
 	//	- sigLength: 1 byte
 	//	- sig: 64 bytes
@@ -255,6 +255,11 @@ const (
 	// settled output of a malicious counterparty's who broadcasts a
 	// revoked taproot commitment transaction.
 	TaprootCommitmentRevoke StandardWitnessType = 34
+
+	// PayToAnchorSpend is a witness that allows us to spend the
+	// pay-to-anchor output of a zero-fee commitment transaction, which
+	// anyone can spend with an empty witness.
+	PayToAnchorSpend StandardWitnessType = 35
 )
 
 // String returns a human readable version of the target WitnessType.
@@ -367,6 +372,9 @@ func (wt StandardWitnessType) String() string {
 	case TaprootCommitmentRevoke:
 		return "TaprootCommitmentRevoke"
 
+	case PayToAnchorSpend:
+		return "PayToAnchorSpend"
+
 	default:
 		return fmt.Sprintf("Unknown WitnessType: %v", uint32(wt))
 	}
@@ -715,6 +723,13 @@ func (wt StandardWitnessType) WitnessGenerator(signer Signer,
 				Witness: witness,
 			}, nil
 
+		case PayToAnchorSpend:
+			// A pay-to-anchor output is spent with an empty
+			// witness, so no signature is needed.
+			return &Script{
+				Witness: wire.TxWitness{},
+			}, nil
+
 		default:
 			return nil, fmt.Errorf("unknown witness type: %v", wt)
 		}
@@ -869,6 +884,9 @@ func (wt StandardWitnessType) SizeUpperBound() (lntypes.WeightUnit,
 
 	case TaprootCommitmentRevoke:
 		return TaprootToLocalRevokeWitnessSize, false, nil
+
+	case PayToAnchorSpend:
+		return PayToAnchorWitnessSize, false, nil
 	}
 
 	return 0, false, fmt.Errorf("unexpected witness type: %v", wt)
@@ -120,6 +120,27 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultTrampolineUnsupported is returned when we receive a trampoline
+	// payment that we can't forward, either because trampoline forwarding
+	// is disabled or because the trampoline onion is invalid.
+	ResultTrampolineUnsupported
+
+	// ResultTrampolineFeeInsufficient is returned when the fee budget of a
+	// trampoline payment is lower than what we require to forward it.
+	ResultTrampolineFeeInsufficient
+
+	// ResultTrampolineExpiryTooSoon is returned when the CLTV budget of a
+	// trampoline payment is lower than what we require to forward it.
+	ResultTrampolineExpiryTooSoon
+
+	// ResultTrampolineIncorrectDetails is returned when the recipient of a
+	// trampoline payment rejected the payment we forwarded.
+	ResultTrampolineIncorrectDetails
+
+	// ResultTrampolineForwardFailed is returned when we were unable to
+	// forward a trampoline payment to the next node.
+	ResultTrampolineForwardFailed
 )
 
 // String returns a string representation of the result.
@@ -189,6 +210,21 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultTrampolineUnsupported:
+		return "trampoline payment not supported"
+
+	case ResultTrampolineFeeInsufficient:
+		return "trampoline fee insufficient"
+
+	case ResultTrampolineExpiryTooSoon:
+		return "trampoline expiry too soon"
+
+	case ResultTrampolineIncorrectDetails:
+		return "trampoline recipient rejected payment"
+
+	case ResultTrampolineForwardFailed:
+		return "trampoline forward failed"
+
 	default:
 		return "unknown failure resolution result"
 	}
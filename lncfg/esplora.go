@@ -0,0 +1,44 @@
+package lncfg
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultEsploraRequestTimeout is the default time after which a
+	// request to the Esplora server is given up.
+	DefaultEsploraRequestTimeout = 30 * time.Second
+
+	// DefaultEsploraPollInterval is the default interval in which the
+	// Esplora server is polled for new blocks.
+	DefaultEsploraPollInterval = 20 * time.Second
+)
+
+// Esplora holds the configuration options for the daemon's connection to an
+// Esplora server.
+//
+//nolint:lll
+type Esplora struct {
+	URL            string        `long:"url" description:"The base URL of the REST API of the Esplora server, e.g. https://blockstream.info/api"`
+	RequestTimeout time.Duration `long:"requesttimeout" description:"The time after which a request to the Esplora server is given up."`
+	PollInterval   time.Duration `long:"pollinterval" description:"The interval in which the Esplora server is polled for new blocks."`
+}
+
+// Validate checks the values configured for the Esplora backend.
+func (e *Esplora) Validate() error {
+	if e.URL == "" {
+		return errors.New("esplora.url must be set when using the " +
+			"esplora backend")
+	}
+
+	if e.RequestTimeout <= 0 {
+		return errors.New("esplora.requesttimeout must be positive")
+	}
+
+	if e.PollInterval <= 0 {
+		return errors.New("esplora.pollinterval must be positive")
+	}
+
+	return nil
+}
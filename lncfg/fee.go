@@ -1,6 +1,14 @@
 package lncfg
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
 
 // DefaultMinUpdateTimeout represents the minimum interval in which a
 // WebAPIEstimator will request fresh fees from its API.
@@ -17,4 +25,108 @@ type Fee struct {
 	URL              string        `long:"url" description:"Optional URL for external fee estimation. If no URL is specified, the method for fee estimation will depend on the chosen backend and network. Must be set for neutrino on mainnet."`
 	MinUpdateTimeout time.Duration `long:"min-update-timeout" description:"The minimum interval in which fees will be updated from the specified fee URL."`
 	MaxUpdateTimeout time.Duration `long:"max-update-timeout" description:"The maximum interval in which fees will be updated from the specified fee URL."`
+
+	Aggregate             bool          `long:"aggregate" description:"Combine the fee estimates of the chain backend, the fee URLs and, if enabled, the mempool of the chain backend. Estimates that deviate too much from the median of all sources are rejected as outliers."`
+	SourceURLs            []string      `long:"source-url" description:"An additional URL for external fee estimation that is combined with the other sources if fee.aggregate is set. Can be set multiple times."`
+	Mempool               bool          `long:"mempool" description:"Derive fee estimates from the mempool of the chain backend and combine them with the other sources if fee.aggregate is set. Only available for bitcoind and btcd."`
+	MempoolUpdateInterval time.Duration `long:"mempool-update-interval" description:"The interval in which a new snapshot of the mempool of the chain backend is taken. Every snapshot fetches the whole mempool over RPC, so the interval must be at least 1m."`
+	MaxDeviation          float64       `long:"max-deviation" description:"The relative deviation from the median of all sources above which a fee estimate is rejected as an outlier. A value of 1 rejects estimates that are more than twice or less than half the median."`
+	Clamps                []string      `long:"clamp" description:"Bounds the combined fee estimate for confirmation targets starting at the given one, up to the next clamp, in the format conftarget:min:max with fee rates in sat/vb. Either bound can be left empty. Can be set multiple times."`
+}
+
+// Validate checks the values configured for fee estimation.
+func (f *Fee) Validate() error {
+	if !f.Aggregate {
+		if len(f.SourceURLs) > 0 || f.Mempool || len(f.Clamps) > 0 {
+			return errors.New("fee.source-url, fee.mempool and " +
+				"fee.clamp require fee.aggregate")
+		}
+
+		return nil
+	}
+
+	if f.MaxDeviation <= 0 {
+		return errors.New("fee.max-deviation must be positive")
+	}
+
+	if f.Mempool &&
+		f.MempoolUpdateInterval < chainfee.MinMempoolUpdateInterval {
+
+		return fmt.Errorf("fee.mempool-update-interval must be at "+
+			"least %v", chainfee.MinMempoolUpdateInterval)
+	}
+
+	_, err := f.ParseClamps()
+
+	return err
+}
+
+// ParseClamps parses the configured fee rate clamps.
+func (f *Fee) ParseClamps() ([]chainfee.FeeRateClamp, error) {
+	clamps := make([]chainfee.FeeRateClamp, 0, len(f.Clamps))
+	for _, clamp := range f.Clamps {
+		parsed, err := parseFeeRateClamp(clamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee.clamp %q: %w",
+				clamp, err)
+		}
+
+		clamps = append(clamps, parsed)
+	}
+
+	return clamps, nil
+}
+
+// parseFeeRateClamp parses a clamp in the format conftarget:min:max, with the
+// fee rates given in sat/vb.
+func parseFeeRateClamp(clamp string) (chainfee.FeeRateClamp, error) {
+	parts := strings.Split(clamp, ":")
+	if len(parts) != 3 {
+		return chainfee.FeeRateClamp{}, errors.New("expected format " +
+			"conftarget:min:max")
+	}
+
+	confTarget, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return chainfee.FeeRateClamp{}, fmt.Errorf("invalid conf "+
+			"target: %w", err)
+	}
+	if confTarget == 0 {
+		return chainfee.FeeRateClamp{}, errors.New("conf target " +
+			"must be positive")
+	}
+
+	parseFeeRate := func(s string) (chainfee.SatPerKWeight, error) {
+		if s == "" {
+			return 0, nil
+		}
+
+		satPerVByte, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid fee rate: %w", err)
+		}
+
+		return chainfee.SatPerVByte(satPerVByte).FeePerKWeight(), nil
+	}
+
+	minFeeRate, err := parseFeeRate(parts[1])
+	if err != nil {
+		return chainfee.FeeRateClamp{}, err
+	}
+
+	maxFeeRate, err := parseFeeRate(parts[2])
+	if err != nil {
+		return chainfee.FeeRateClamp{}, err
+	}
+
+	if maxFeeRate != 0 && minFeeRate > maxFeeRate {
+		return chainfee.FeeRateClamp{}, errors.New("min fee rate " +
+			"exceeds max fee rate")
+	}
+
+	return chainfee.FeeRateClamp{
+		ConfTarget: uint32(confTarget),
+		MinFeeRate: minFeeRate,
+		MaxFeeRate: maxFeeRate,
+	}, nil
 }
@@ -0,0 +1,105 @@
+package lncfg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeValidate tests the validation of the fee estimation options.
+func TestFeeValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		fee    *Fee
+		err    string
+		clamps []chainfee.FeeRateClamp
+	}{{
+		name: "no aggregation",
+		fee:  &Fee{},
+	}, {
+		name: "sources without aggregation",
+		fee: &Fee{
+			Mempool: true,
+		},
+		err: "require fee.aggregate",
+	}, {
+		name: "aggregation with clamps",
+		fee: &Fee{
+			Aggregate:    true,
+			MaxDeviation: chainfee.DefaultMaxFeeDeviation,
+			Clamps:       []string{"1:5:500", "144::50"},
+		},
+		clamps: []chainfee.FeeRateClamp{{
+			ConfTarget: 1,
+			MinFeeRate: 1250,
+			MaxFeeRate: 125_000,
+		}, {
+			ConfTarget: 144,
+			MaxFeeRate: 12_500,
+		}},
+	}, {
+		name: "invalid max deviation",
+		fee: &Fee{
+			Aggregate: true,
+		},
+		err: "fee.max-deviation must be positive",
+	}, {
+		name: "invalid mempool update interval",
+		fee: &Fee{
+			Aggregate:             true,
+			Mempool:               true,
+			MempoolUpdateInterval: 30 * time.Second,
+			MaxDeviation:          chainfee.DefaultMaxFeeDeviation,
+		},
+		err: "fee.mempool-update-interval must be at least 1m0s",
+	}, {
+		name: "invalid clamp format",
+		fee: &Fee{
+			Aggregate:    true,
+			MaxDeviation: chainfee.DefaultMaxFeeDeviation,
+			Clamps:       []string{"1:5"},
+		},
+		err: "expected format",
+	}, {
+		name: "invalid clamp conf target",
+		fee: &Fee{
+			Aggregate:    true,
+			MaxDeviation: chainfee.DefaultMaxFeeDeviation,
+			Clamps:       []string{"0:5:10"},
+		},
+		err: "conf target must be positive",
+	}, {
+		name: "invalid clamp bounds",
+		fee: &Fee{
+			Aggregate:    true,
+			MaxDeviation: chainfee.DefaultMaxFeeDeviation,
+			Clamps:       []string{"6:50:10"},
+		},
+		err: "min fee rate exceeds max fee rate",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.fee.Validate()
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+
+				return
+			}
+			require.NoError(t, err)
+
+			clamps, err := tc.fee.ParseClamps()
+			require.NoError(t, err)
+			require.Equal(t, len(tc.clamps), len(clamps))
+			for i, clamp := range tc.clamps {
+				require.Equal(t, clamp, clamps[i])
+			}
+		})
+	}
+}
@@ -16,6 +16,24 @@ var (
 //nolint:lll
 type Htlcswitch struct {
 	MailboxDeliveryTimeout time.Duration `long:"mailboxdeliverytimeout" description:"The timeout value when delivering HTLCs to a channel link. Setting this value too small will result in local payment failures if large number of payments are sent over a short period."`
+
+	Reputation Reputation `group:"reputation" namespace:"reputation"`
+}
+
+// Reputation holds the configuration of the local reputation tracker that
+// protects the resources of our channels against jamming.
+//
+//nolint:lll
+type Reputation struct {
+	Enable bool `long:"enable" description:"If set, the reputation of the peers forwarding htlcs to us is tracked, and part of the htlc slots and liquidity of every channel is reserved for endorsed htlcs from reputable peers."`
+
+	RevenueWindow time.Duration `long:"revenuewindow" description:"The period over which the fee revenue of an outgoing channel is tracked."`
+
+	Multiplier uint32 `long:"multiplier" description:"The number of revenue windows over which the reputation of an incoming channel is tracked."`
+
+	ResolutionPeriod time.Duration `long:"resolutionperiod" description:"The time within which htlcs are expected to resolve. Endorsed htlcs that are held for longer are charged an opportunity cost."`
+
+	ProtectedPercent uint8 `long:"protectedpercent" description:"The percentage of the htlc slots and liquidity of every channel that is reserved for endorsed htlcs from reputable peers."`
 }
 
 // Validate checks the values configured for htlcswitch.
@@ -30,5 +48,25 @@ func (h *Htlcswitch) Validate() error {
 			MaxMailboxDeliveryTimeout)
 	}
 
+	if !h.Reputation.Enable {
+		return nil
+	}
+
+	switch {
+	case h.Reputation.RevenueWindow <= 0:
+		return fmt.Errorf("reputation.revenuewindow must be positive")
+
+	case h.Reputation.Multiplier == 0:
+		return fmt.Errorf("reputation.multiplier must be positive")
+
+	case h.Reputation.ResolutionPeriod <= 0:
+		return fmt.Errorf("reputation.resolutionperiod must be " +
+			"positive")
+
+	case h.Reputation.ProtectedPercent > 100:
+		return fmt.Errorf("reputation.protectedpercent must not " +
+			"exceed 100")
+	}
+
 	return nil
 }
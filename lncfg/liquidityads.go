@@ -0,0 +1,92 @@
+package lncfg
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultLeaseFundingWeight is the default weight of the inputs and
+	// outputs we add to the funding transaction of a leased channel. It
+	// covers two P2WPKH inputs and a P2WPKH change output.
+	DefaultLeaseFundingWeight = 2*272 + 124
+
+	// DefaultLeaseFeeBase is the default fixed part of the lease fee in
+	// satoshis.
+	DefaultLeaseFeeBase = 1000
+
+	// DefaultLeaseFeeBasis is the default proportional part of the lease
+	// fee in basis points of the leased amount.
+	DefaultLeaseFeeBasis = 50
+
+	// DefaultLeaseMaxBaseFee is the default largest base routing fee in
+	// millisatoshis we charge on leased channels.
+	DefaultLeaseMaxBaseFee = 5000
+
+	// DefaultLeaseMaxFeeProportional is the default largest proportional
+	// routing fee we charge on leased channels, in units of 1000 ppm.
+	DefaultLeaseMaxFeeProportional = 1
+)
+
+// LiquidityAds holds the configuration for selling inbound liquidity through
+// lease rates advertised in our node announcement.
+//
+//nolint:lll
+type LiquidityAds struct {
+	Active bool `long:"active" description:"If set, lnd advertises the configured lease rates in its node announcement and contributes the leased amount to dual-funded channels opened by buyers requesting it. Requires protocol.dual-fund, and conflicts with protocol.no-script-enforced-lease."`
+
+	FundingWeight uint16 `long:"funding-weight" description:"The weight of the inputs and outputs we add to the funding transaction of a leased channel, which the buyer pays for at the funding fee rate."`
+
+	LeaseFeeBase int64 `long:"lease-fee-base" description:"The fixed part of the lease fee in satoshis."`
+
+	LeaseFeeBasis uint16 `long:"lease-fee-basis" description:"The proportional part of the lease fee in basis points of the leased amount."`
+
+	ChannelFeeMaxBase uint32 `long:"channel-fee-max-base-msat" description:"The largest base routing fee in millisatoshis we promise to charge on leased channels for the duration of the lease."`
+
+	ChannelFeeMaxProportional uint16 `long:"channel-fee-max-proportional-thousandths" description:"The largest proportional routing fee we promise to charge on leased channels for the duration of the lease, in units of 1000 ppm."`
+
+	MaxLeaseAmt int64 `long:"max-lease-amt" description:"The largest amount in satoshis we contribute to a single leased channel. If zero, the maximum channel size applies."`
+}
+
+// DefaultLiquidityAds returns the default configuration for liquidity ads.
+func DefaultLiquidityAds() *LiquidityAds {
+	return &LiquidityAds{
+		FundingWeight:             DefaultLeaseFundingWeight,
+		LeaseFeeBase:              DefaultLeaseFeeBase,
+		LeaseFeeBasis:             DefaultLeaseFeeBasis,
+		ChannelFeeMaxBase:         DefaultLeaseMaxBaseFee,
+		ChannelFeeMaxProportional: DefaultLeaseMaxFeeProportional,
+	}
+}
+
+// Validate checks the values configured for liquidity ads.
+//
+// NOTE: this is part of the Validator interface.
+func (l *LiquidityAds) Validate() error {
+	if l.LeaseFeeBase < 0 || l.LeaseFeeBase > math.MaxUint32 {
+		return fmt.Errorf("lease-fee-base must be between 0 and %d",
+			uint32(math.MaxUint32))
+	}
+
+	if l.MaxLeaseAmt < 0 {
+		return fmt.Errorf("max-lease-amt must not be negative")
+	}
+
+	return nil
+}
+
+// LeaseRates returns the lease rates we advertise.
+func (l *LiquidityAds) LeaseRates() lnwire.LeaseRates {
+	return lnwire.LeaseRates{
+		FundingWeight:             l.FundingWeight,
+		LeaseFeeBasis:             l.LeaseFeeBasis,
+		ChannelFeeMaxProportional: l.ChannelFeeMaxProportional,
+		LeaseFeeBase:              btcutil.Amount(l.LeaseFeeBase),
+		ChannelFeeMaxBase: lnwire.MilliSatoshi(
+			l.ChannelFeeMaxBase,
+		),
+	}
+}
@@ -0,0 +1,56 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultPeerStorageMaxBlobSize is the default largest blob in bytes
+	// we store on behalf of a single peer.
+	DefaultPeerStorageMaxBlobSize = lnwire.MaxPeerStorageBlobSize
+
+	// DefaultPeerStorageMaxTotalSize is the default largest number of
+	// bytes we store on behalf of all our peers combined.
+	DefaultPeerStorageMaxTotalSize = 10 * 1024 * 1024
+)
+
+// PeerStorage holds the configuration for storing encrypted blobs on behalf
+// of our peers, and for handing our own encrypted channel backup to them.
+//
+//nolint:lll
+type PeerStorage struct {
+	Provide bool `long:"provide" description:"If set, lnd advertises option_provide_storage and stores a small blob on behalf of each peer it has a channel with, returning it whenever the peer reconnects."`
+
+	MaxBlobSize uint32 `long:"max-blob-size" description:"The largest blob in bytes we store on behalf of a single peer."`
+
+	MaxTotalSize uint64 `long:"max-total-size" description:"The largest number of bytes we store on behalf of all our peers combined. Blobs of new peers are rejected once this quota is reached."`
+
+	UploadBackup bool `long:"upload-backup" description:"If set, lnd hands its encrypted static channel backup to every peer that advertises option_provide_storage, so it can be retrieved from them during a restore."`
+}
+
+// DefaultPeerStorage returns the default configuration for peer storage.
+func DefaultPeerStorage() *PeerStorage {
+	return &PeerStorage{
+		MaxBlobSize:  DefaultPeerStorageMaxBlobSize,
+		MaxTotalSize: DefaultPeerStorageMaxTotalSize,
+	}
+}
+
+// Validate checks the values configured for peer storage.
+//
+// NOTE: this is part of the Validator interface.
+func (p *PeerStorage) Validate() error {
+	if p.MaxBlobSize > lnwire.MaxPeerStorageBlobSize {
+		return fmt.Errorf("max-blob-size must not exceed %d",
+			lnwire.MaxPeerStorageBlobSize)
+	}
+
+	if p.Provide && uint64(p.MaxBlobSize) > p.MaxTotalSize {
+		return fmt.Errorf("max-total-size must be at least "+
+			"max-blob-size (%d)", p.MaxBlobSize)
+	}
+
+	return nil
+}
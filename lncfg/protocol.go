@@ -35,6 +35,27 @@ type ProtocolOptions struct {
 	// the experimental taproot overlay chan type.
 	TaprootOverlayChans bool `long:"simple-taproot-overlay-chans" description:"if set, then lnd will create and accept requests for channels using the taproot overlay commitment type"`
 
+	// ZeroFeeCommitments should be set if we want to enable support for
+	// the experimental zero-fee commitment channel type.
+	ZeroFeeCommitments bool `long:"zero-fee-commitments" description:"if set, then lnd will create and accept requests for channels using the zero-fee commitment type, which requires a chain backend with package relay"`
+
+	// Splice should be set if we want to enable support for the
+	// experimental splicing of channels.
+	Splice bool `long:"splice" description:"if set, then lnd will allow splicing funds into and out of channels with peers that support it"`
+
+	// DualFund should be set if we want to enable support for the
+	// experimental dual-funded channel establishment protocol.
+	DualFund bool `long:"dual-fund" description:"if set, then lnd will open and accept dual-funded channels with peers that support it"`
+
+	// OnionMessages should be set if we want to relay onion messages and
+	// allow applications to send and receive them.
+	OnionMessages bool `long:"onion-messages" description:"if set, then lnd will relay onion messages and allow sending and receiving them over RPC"`
+
+	// RbfCoopClose should be set if we want to use the simple cooperative
+	// close protocol, which allows the fee of a closing transaction to be
+	// bumped with RBF.
+	RbfCoopClose bool `long:"rbf-coop-close" description:"if set, then lnd will use the RBF-able simple cooperative close protocol with peers that support it"`
+
 	// NoAnchors should be set if we don't want to support opening or accepting
 	// channels having the anchor commitment type.
 	NoAnchors bool `long:"no-anchors" description:"disable support for anchor commitments"`
@@ -67,6 +88,13 @@ type ProtocolOptions struct {
 	// NoRouteBlindingOption disables forwarding of payments in blinded routes.
 	NoRouteBlindingOption bool `long:"no-route-blinding" description:"do not forward payments that are a part of a blinded route"`
 
+	// NoQuiescenceOption disables support for the quiescence protocol.
+	NoQuiescenceOption bool `long:"no-quiescence" description:"do not allow or advertise the quiescence protocol"`
+
+	// NoExperimentalEndorsementOption disables propagation of the
+	// experimental endorsement signal on forwarded htlcs.
+	NoExperimentalEndorsementOption bool `long:"no-experimental-endorsement" description:"do not forward the experimental endorsement signal"`
+
 	// CustomMessage allows the custom message APIs to handle messages with
 	// the provided protocol numbers, which fall outside the custom message
 	// number range.
@@ -132,6 +160,17 @@ func (l *ProtocolOptions) NoRouteBlinding() bool {
 	return l.NoRouteBlindingOption
 }
 
+// NoQuiescence returns true if quiescence is disabled.
+func (l *ProtocolOptions) NoQuiescence() bool {
+	return l.NoQuiescenceOption
+}
+
+// NoExpEndorsement returns true if experimental endorsement should be
+// disabled.
+func (l *ProtocolOptions) NoExpEndorsement() bool {
+	return l.NoExperimentalEndorsementOption
+}
+
 // CustomMessageOverrides returns the set of protocol messages that we override
 // to allow custom handling.
 func (p ProtocolOptions) CustomMessageOverrides() []uint16 {
@@ -17,6 +17,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/invoices"
@@ -24,6 +25,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/offers"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -621,6 +623,78 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	return &paymentHash, newInvoice, nil
 }
 
+// AddOfferInvoice adds an invoice with blinded payment paths to the invoice
+// registry in response to a BOLT 12 invoice request. The blinded paths and
+// payment hash of the invoice are returned for the BOLT 12 invoice that is
+// sent to the payer.
+func AddOfferInvoice(ctx context.Context, cfg *AddInvoiceConfig,
+	blindCfg *BlindedPathConfig, req *offers.InvoiceRequest,
+	amt lnwire.MilliSatoshi) (*offers.CreatedInvoice, error) {
+
+	// The description of the offer is kept as the memo of the invoice if
+	// it fits.
+	memo := req.Offer.Description
+	if len(memo) > invoices.MaxMemoSize {
+		memo = ""
+	}
+
+	hash, invoice, err := AddInvoice(ctx, cfg, &AddInvoiceData{
+		Memo:           memo,
+		Value:          amt,
+		Expiry:         int64(offers.DefaultInvoiceExpiry.Seconds()),
+		BlindedPathCfg: blindCfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The blinded paths were encoded in the BOLT 11 payment request of the
+	// invoice, so we decode them from there.
+	payReq, err := zpay32.Decode(
+		string(invoice.PaymentRequest), cfg.ChainParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payments to the invoice may be split into several parts.
+	created := &offers.CreatedInvoice{
+		PaymentHash: *hash,
+		Expiry:      invoice.Terms.Expiry,
+		Features: lnwire.NewRawFeatureVector(
+			lnwire.MPPOptional,
+		),
+	}
+	for _, path := range payReq.BlindedPaymentPaths {
+		created.Paths = append(created.Paths, &sphinx.BlindedPath{
+			IntroductionPoint: path.Hops[0].BlindedNodePub,
+			BlindingPoint:     path.FirstEphemeralBlindingPoint,
+			BlindedHops:       path.Hops,
+		})
+
+		features := lnwire.NewRawFeatureVector()
+		if path.Features != nil {
+			features = path.Features.RawFeatureVector
+		}
+
+		payInfo := &offers.BlindedPayInfo{
+			FeeBaseMsat:               path.FeeBaseMsat,
+			FeeProportionalMillionths: path.FeeRate,
+			CltvExpiryDelta:           path.CltvExpiryDelta,
+			HtlcMinimumMsat: lnwire.MilliSatoshi(
+				path.HTLCMinMsat,
+			),
+			HtlcMaximumMsat: lnwire.MilliSatoshi(
+				path.HTLCMaxMsat,
+			),
+			Features: features,
+		}
+		created.PayInfo = append(created.PayInfo, payInfo)
+	}
+
+	return created, nil
+}
+
 // chanCanBeHopHint returns true if the target channel is eligible to be a hop
 // hint.
 func chanCanBeHopHint(channel *HopHintInfo, cfg *SelectHopHintsCfg) (
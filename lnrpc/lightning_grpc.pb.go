@@ -177,6 +177,26 @@ type LightningClient interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// lncli: `bumpclosefee`
+	// BumpCloseFee replaces the cooperative close transaction of a channel with
+	// one paying a higher fee rate, which is fully paid for by the local node.
+	// This is only possible if the channel is being closed with the RBF-able
+	// simple cooperative close protocol, which must be enabled with
+	// protocol.rbf-coop-close and supported by the remote peer.
+	BumpCloseFee(ctx context.Context, in *BumpCloseFeeRequest, opts ...grpc.CallOption) (*BumpCloseFeeResponse, error)
+	// lncli: `splicein`
+	// SpliceIn adds funds to an active channel without closing it. The funds are
+	// taken from the on-chain wallet, or from an externally funded PSBT if a PSBT
+	// shim is specified. The channel is quiesced while the splice transaction is
+	// negotiated with the remote peer. Once it has been signed, the channel is
+	// used as usual while the splice transaction confirms.
+	SpliceIn(ctx context.Context, in *SpliceInRequest, opts ...grpc.CallOption) (Lightning_SpliceInClient, error)
+	// lncli: `spliceout`
+	// SpliceOut moves funds out of an active channel to an on-chain address
+	// without closing it. The amount and the fee of the splice transaction are
+	// deducted from the local balance of the channel. The channel keeps being
+	// used as usual while the splice transaction confirms.
+	SpliceOut(ctx context.Context, in *SpliceOutRequest, opts ...grpc.CallOption) (*SpliceOutResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -317,6 +337,12 @@ type LightningClient interface {
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
 	// channel policies for all channels globally, or a particular channel.
 	UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error)
+	// lncli: `updatechanparams`
+	// UpdateChannelParams allows the caller to renegotiate the parameters of an
+	// active channel with the remote peer without closing it. The channel is
+	// quiesced for the duration of the negotiation, and the call returns once the
+	// new parameters are in effect or the remote peer rejects them.
+	UpdateChannelParams(ctx context.Context, in *UpdateChannelParamsRequest, opts ...grpc.CallOption) (*UpdateChannelParamsResponse, error)
 	// lncli: `fwdinghistory`
 	// ForwardingHistory allows the caller to query the htlcswitch for a record of
 	// all HTLCs forwarded within the target time range, and integer offset
@@ -407,6 +433,42 @@ type LightningClient interface {
 	// needs to be compiled with  the `dev` build tag, and the message type to
 	// override should be specified in lnd's experimental protocol configuration.
 	SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	// lncli: `sendonionmessage`
+	// SendOnionMessage sends an onion message to a node, either directly or
+	// along a blinded path. The nodes relaying the message learn neither its
+	// sender nor its contents. Onion messages must be enabled with the
+	// protocol.onion-messages option.
+	SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error)
+	// lncli: `subscribeonionmessages`
+	// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+	// that this node is the final recipient of.
+	SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error)
+	// lncli: `addoffer`
+	// AddOffer creates a new BOLT 12 offer, a reusable payment code that payers
+	// use to fetch invoices from this node over onion messages. Onion messages
+	// must be enabled with the protocol.onion-messages option.
+	AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*AddOfferResponse, error)
+	// lncli: `listoffers`
+	// ListOffers returns the BOLT 12 offers created by this node.
+	ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error)
+	// lncli: `fetchinvoice`
+	// FetchInvoice requests a BOLT 12 invoice for an offer from its issuer over
+	// onion messages. The issuer, or the introduction node of the offer's
+	// blinded path, must be a connected peer. The invoice can be paid with
+	// routerrpc.SendPaymentV2.
+	FetchInvoice(ctx context.Context, in *FetchInvoiceRequest, opts ...grpc.CallOption) (*FetchInvoiceResponse, error)
+	// lncli: `listliquidityads`
+	// ListLiquidityAds returns the nodes of the graph that sell inbound
+	// liquidity through channel leases, along with the rates they advertise.
+	ListLiquidityAds(ctx context.Context, in *ListLiquidityAdsRequest, opts ...grpc.CallOption) (*ListLiquidityAdsResponse, error)
+	// lncli: `purchaselease`
+	// PurchaseLease buys inbound liquidity from a connected peer that advertises
+	// lease rates. This node opens a dual-funded channel to the peer, requesting
+	// it to contribute the requested amount, while this node contributes the
+	// lease fee and the fees of the commitment transaction. The funds of the peer
+	// are locked in the channel until the lease expires. The stream reports the
+	// status of the leased channel like OpenChannel does.
+	PurchaseLease(ctx context.Context, in *PurchaseLeaseRequest, opts ...grpc.CallOption) (Lightning_PurchaseLeaseClient, error)
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -815,6 +877,56 @@ func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
 	return m, nil
 }
 
+func (c *lightningClient) BumpCloseFee(ctx context.Context, in *BumpCloseFeeRequest, opts ...grpc.CallOption) (*BumpCloseFeeResponse, error) {
+	out := new(BumpCloseFeeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BumpCloseFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SpliceIn(ctx context.Context, in *SpliceInRequest, opts ...grpc.CallOption) (Lightning_SpliceInClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SpliceIn", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSpliceInClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SpliceInClient interface {
+	Recv() (*SpliceUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSpliceInClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSpliceInClient) Recv() (*SpliceUpdate, error) {
+	m := new(SpliceUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SpliceOut(ctx context.Context, in *SpliceOutRequest, opts ...grpc.CallOption) (*SpliceOutResponse, error) {
+	out := new(SpliceOutResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SpliceOut", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
 	out := new(AbandonChannelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, opts...)
@@ -826,7 +938,7 @@ func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannel
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -867,7 +979,7 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendToRoute(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendToRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendToRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SendToRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -934,7 +1046,7 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 }
 
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1065,7 +1177,7 @@ func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts
 }
 
 func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1123,6 +1235,15 @@ func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpd
 	return out, nil
 }
 
+func (c *lightningClient) UpdateChannelParams(ctx context.Context, in *UpdateChannelParamsRequest, opts ...grpc.CallOption) (*UpdateChannelParamsResponse, error) {
+	out := new(UpdateChannelParamsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelParams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error) {
 	out := new(ForwardingHistoryResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ForwardingHistory", in, out, opts...)
@@ -1169,7 +1290,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1246,7 +1367,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1286,7 +1407,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1317,6 +1438,115 @@ func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error)
 	return m, nil
 }
 
+func (c *lightningClient) SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error) {
+	out := new(SendOnionMessageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SendOnionMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[14], "/lnrpc.Lightning/SubscribeOnionMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeOnionMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeOnionMessagesClient interface {
+	Recv() (*OnionMessageUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeOnionMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeOnionMessagesClient) Recv() (*OnionMessageUpdate, error) {
+	m := new(OnionMessageUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*AddOfferResponse, error) {
+	out := new(AddOfferResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AddOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error) {
+	out := new(ListOffersResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListOffers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FetchInvoice(ctx context.Context, in *FetchInvoiceRequest, opts ...grpc.CallOption) (*FetchInvoiceResponse, error) {
+	out := new(FetchInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/FetchInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListLiquidityAds(ctx context.Context, in *ListLiquidityAdsRequest, opts ...grpc.CallOption) (*ListLiquidityAdsResponse, error) {
+	out := new(ListLiquidityAdsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListLiquidityAds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PurchaseLease(ctx context.Context, in *PurchaseLeaseRequest, opts ...grpc.CallOption) (Lightning_PurchaseLeaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[15], "/lnrpc.Lightning/PurchaseLease", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningPurchaseLeaseClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_PurchaseLeaseClient interface {
+	Recv() (*OpenStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningPurchaseLeaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningPurchaseLeaseClient) Recv() (*OpenStatusUpdate, error) {
+	m := new(OpenStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) ListAliases(ctx context.Context, in *ListAliasesRequest, opts ...grpc.CallOption) (*ListAliasesResponse, error) {
 	out := new(ListAliasesResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAliases", in, out, opts...)
@@ -1498,6 +1728,26 @@ type LightningServer interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// lncli: `bumpclosefee`
+	// BumpCloseFee replaces the cooperative close transaction of a channel with
+	// one paying a higher fee rate, which is fully paid for by the local node.
+	// This is only possible if the channel is being closed with the RBF-able
+	// simple cooperative close protocol, which must be enabled with
+	// protocol.rbf-coop-close and supported by the remote peer.
+	BumpCloseFee(context.Context, *BumpCloseFeeRequest) (*BumpCloseFeeResponse, error)
+	// lncli: `splicein`
+	// SpliceIn adds funds to an active channel without closing it. The funds are
+	// taken from the on-chain wallet, or from an externally funded PSBT if a PSBT
+	// shim is specified. The channel is quiesced while the splice transaction is
+	// negotiated with the remote peer. Once it has been signed, the channel is
+	// used as usual while the splice transaction confirms.
+	SpliceIn(*SpliceInRequest, Lightning_SpliceInServer) error
+	// lncli: `spliceout`
+	// SpliceOut moves funds out of an active channel to an on-chain address
+	// without closing it. The amount and the fee of the splice transaction are
+	// deducted from the local balance of the channel. The channel keeps being
+	// used as usual while the splice transaction confirms.
+	SpliceOut(context.Context, *SpliceOutRequest) (*SpliceOutResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -1638,6 +1888,12 @@ type LightningServer interface {
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
 	// channel policies for all channels globally, or a particular channel.
 	UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error)
+	// lncli: `updatechanparams`
+	// UpdateChannelParams allows the caller to renegotiate the parameters of an
+	// active channel with the remote peer without closing it. The channel is
+	// quiesced for the duration of the negotiation, and the call returns once the
+	// new parameters are in effect or the remote peer rejects them.
+	UpdateChannelParams(context.Context, *UpdateChannelParamsRequest) (*UpdateChannelParamsResponse, error)
 	// lncli: `fwdinghistory`
 	// ForwardingHistory allows the caller to query the htlcswitch for a record of
 	// all HTLCs forwarded within the target time range, and integer offset
@@ -1728,6 +1984,42 @@ type LightningServer interface {
 	// needs to be compiled with  the `dev` build tag, and the message type to
 	// override should be specified in lnd's experimental protocol configuration.
 	SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error
+	// lncli: `sendonionmessage`
+	// SendOnionMessage sends an onion message to a node, either directly or
+	// along a blinded path. The nodes relaying the message learn neither its
+	// sender nor its contents. Onion messages must be enabled with the
+	// protocol.onion-messages option.
+	SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error)
+	// lncli: `subscribeonionmessages`
+	// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+	// that this node is the final recipient of.
+	SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error
+	// lncli: `addoffer`
+	// AddOffer creates a new BOLT 12 offer, a reusable payment code that payers
+	// use to fetch invoices from this node over onion messages. Onion messages
+	// must be enabled with the protocol.onion-messages option.
+	AddOffer(context.Context, *AddOfferRequest) (*AddOfferResponse, error)
+	// lncli: `listoffers`
+	// ListOffers returns the BOLT 12 offers created by this node.
+	ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error)
+	// lncli: `fetchinvoice`
+	// FetchInvoice requests a BOLT 12 invoice for an offer from its issuer over
+	// onion messages. The issuer, or the introduction node of the offer's
+	// blinded path, must be a connected peer. The invoice can be paid with
+	// routerrpc.SendPaymentV2.
+	FetchInvoice(context.Context, *FetchInvoiceRequest) (*FetchInvoiceResponse, error)
+	// lncli: `listliquidityads`
+	// ListLiquidityAds returns the nodes of the graph that sell inbound
+	// liquidity through channel leases, along with the rates they advertise.
+	ListLiquidityAds(context.Context, *ListLiquidityAdsRequest) (*ListLiquidityAdsResponse, error)
+	// lncli: `purchaselease`
+	// PurchaseLease buys inbound liquidity from a connected peer that advertises
+	// lease rates. This node opens a dual-funded channel to the peer, requesting
+	// it to contribute the requested amount, while this node contributes the
+	// lease fee and the fees of the commitment transaction. The funds of the peer
+	// are locked in the channel until the lease expires. The stream reports the
+	// status of the leased channel like OpenChannel does.
+	PurchaseLease(*PurchaseLeaseRequest, Lightning_PurchaseLeaseServer) error
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -1828,6 +2120,15 @@ func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorSer
 func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
 }
+func (UnimplementedLightningServer) BumpCloseFee(context.Context, *BumpCloseFeeRequest) (*BumpCloseFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpCloseFee not implemented")
+}
+func (UnimplementedLightningServer) SpliceIn(*SpliceInRequest, Lightning_SpliceInServer) error {
+	return status.Errorf(codes.Unimplemented, "method SpliceIn not implemented")
+}
+func (UnimplementedLightningServer) SpliceOut(context.Context, *SpliceOutRequest) (*SpliceOutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpliceOut not implemented")
+}
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
@@ -1900,6 +2201,9 @@ func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest
 func (UnimplementedLightningServer) UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelPolicy not implemented")
 }
+func (UnimplementedLightningServer) UpdateChannelParams(context.Context, *UpdateChannelParamsRequest) (*UpdateChannelParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelParams not implemented")
+}
 func (UnimplementedLightningServer) ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardingHistory not implemented")
 }
@@ -1942,6 +2246,27 @@ func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCust
 func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
 }
+func (UnimplementedLightningServer) SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOnionMessage not implemented")
+}
+func (UnimplementedLightningServer) SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOnionMessages not implemented")
+}
+func (UnimplementedLightningServer) AddOffer(context.Context, *AddOfferRequest) (*AddOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOffer not implemented")
+}
+func (UnimplementedLightningServer) ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOffers not implemented")
+}
+func (UnimplementedLightningServer) FetchInvoice(context.Context, *FetchInvoiceRequest) (*FetchInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchInvoice not implemented")
+}
+func (UnimplementedLightningServer) ListLiquidityAds(context.Context, *ListLiquidityAdsRequest) (*ListLiquidityAdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLiquidityAds not implemented")
+}
+func (UnimplementedLightningServer) PurchaseLease(*PurchaseLeaseRequest, Lightning_PurchaseLeaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method PurchaseLease not implemented")
+}
 func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
 }
@@ -2488,6 +2813,63 @@ func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_BumpCloseFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpCloseFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BumpCloseFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BumpCloseFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BumpCloseFee(ctx, req.(*BumpCloseFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SpliceIn_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SpliceInRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SpliceIn(m, &lightningSpliceInServer{stream})
+}
+
+type Lightning_SpliceInServer interface {
+	Send(*SpliceUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSpliceInServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSpliceInServer) Send(m *SpliceUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SpliceOut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpliceOutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SpliceOut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SpliceOut",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SpliceOut(ctx, req.(*SpliceOutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AbandonChannelRequest)
 	if err := dec(in); err != nil {
@@ -2942,6 +3324,24 @@ func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_UpdateChannelParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateChannelParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UpdateChannelParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpdateChannelParams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpdateChannelParams(ctx, req.(*UpdateChannelParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ForwardingHistoryRequest)
 	if err := dec(in); err != nil {
@@ -3208,6 +3608,138 @@ func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SendOnionMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOnionMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendOnionMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendOnionMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendOnionMessage(ctx, req.(*SendOnionMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeOnionMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOnionMessagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeOnionMessages(m, &lightningSubscribeOnionMessagesServer{stream})
+}
+
+type Lightning_SubscribeOnionMessagesServer interface {
+	Send(*OnionMessageUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeOnionMessagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeOnionMessagesServer) Send(m *OnionMessageUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_AddOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddOffer(ctx, req.(*AddOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListOffers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOffersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListOffers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListOffers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListOffers(ctx, req.(*ListOffersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_FetchInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).FetchInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/FetchInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).FetchInvoice(ctx, req.(*FetchInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListLiquidityAds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLiquidityAdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListLiquidityAds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListLiquidityAds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListLiquidityAds(ctx, req.(*ListLiquidityAdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_PurchaseLease_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PurchaseLeaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).PurchaseLease(m, &lightningPurchaseLeaseServer{stream})
+}
+
+type Lightning_PurchaseLeaseServer interface {
+	Send(*OpenStatusUpdate) error
+	grpc.ServerStream
+}
+
+type lightningPurchaseLeaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningPurchaseLeaseServer) Send(m *OpenStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAliasesRequest)
 	if err := dec(in); err != nil {
@@ -3339,6 +3871,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FundingStateStep",
 			Handler:    _Lightning_FundingStateStep_Handler,
 		},
+		{
+			MethodName: "BumpCloseFee",
+			Handler:    _Lightning_BumpCloseFee_Handler,
+		},
+		{
+			MethodName: "SpliceOut",
+			Handler:    _Lightning_SpliceOut_Handler,
+		},
 		{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
@@ -3419,6 +3959,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChannelPolicy",
 			Handler:    _Lightning_UpdateChannelPolicy_Handler,
 		},
+		{
+			MethodName: "UpdateChannelParams",
+			Handler:    _Lightning_UpdateChannelParams_Handler,
+		},
 		{
 			MethodName: "ForwardingHistory",
 			Handler:    _Lightning_ForwardingHistory_Handler,
@@ -3463,6 +4007,26 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendCustomMessage",
 			Handler:    _Lightning_SendCustomMessage_Handler,
 		},
+		{
+			MethodName: "SendOnionMessage",
+			Handler:    _Lightning_SendOnionMessage_Handler,
+		},
+		{
+			MethodName: "AddOffer",
+			Handler:    _Lightning_AddOffer_Handler,
+		},
+		{
+			MethodName: "ListOffers",
+			Handler:    _Lightning_ListOffers_Handler,
+		},
+		{
+			MethodName: "FetchInvoice",
+			Handler:    _Lightning_FetchInvoice_Handler,
+		},
+		{
+			MethodName: "ListLiquidityAds",
+			Handler:    _Lightning_ListLiquidityAds_Handler,
+		},
 		{
 			MethodName: "ListAliases",
 			Handler:    _Lightning_ListAliases_Handler,
@@ -3504,6 +4068,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_CloseChannel_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SpliceIn",
+			Handler:       _Lightning_SpliceIn_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SendPayment",
 			Handler:       _Lightning_SendPayment_Handler,
@@ -3542,6 +4111,16 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeCustomMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeOnionMessages",
+			Handler:       _Lightning_SubscribeOnionMessages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PurchaseLease",
+			Handler:       _Lightning_PurchaseLease_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lightning.proto",
 }
@@ -1,9 +1,16 @@
 package routerrpc
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/lightningnetwork/lnd/aliasmgr"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/rebalance"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/trampoline"
 )
 
 // Config is the main configuration file for the router RPC server. It contains
@@ -55,6 +62,11 @@ type Config struct {
 
 // DefaultConfig defines the config defaults.
 func DefaultConfig() *Config {
+	var (
+		probeAmt         = routing.DefaultProbeAmount
+		probeMaxInFlight = routing.DefaultProbeMaxInFlight
+	)
+
 	defaultRoutingConfig := RoutingConfig{
 		ProbabilityEstimatorType: routing.DefaultEstimator,
 		MinRouteProbability:      routing.DefaultMinRouteProbability,
@@ -75,6 +87,24 @@ func DefaultConfig() *Config {
 			DecayTime:  routing.DefaultBimodalDecayTime,
 		},
 		FeeEstimationTimeout: routing.DefaultFeeEstimationTimeout,
+		TrampolineConfig: &TrampolineConfig{
+			FeeBaseMsat: uint64(trampoline.DefaultFeeBaseMsat),
+			FeeRatePPM:  trampoline.DefaultFeeRatePPM,
+			CltvDelta:   trampoline.DefaultCltvDelta,
+		},
+		ProberConfig: &ProberConfig{
+			Namespace:       routing.DefaultProbeNamespace,
+			Interval:        routing.DefaultProbeInterval,
+			Amount:          probeAmt.ToSatoshis(),
+			MaxInFlight:     probeMaxInFlight.ToSatoshis(),
+			TopDestinations: routing.DefaultProbeTopDestinations,
+		},
+		RebalancerConfig: &RebalancerConfig{
+			Interval:       rebalance.DefaultInterval,
+			FeeRatio:       rebalance.DefaultFeeRatio,
+			MaxAmount:      rebalance.DefaultMaxAmount.ToSatoshis(),
+			PaymentTimeout: rebalance.DefaultPaymentTimeout,
+		},
 	}
 
 	return &Config{
@@ -89,6 +119,7 @@ func GetRoutingConfig(cfg *Config) *RoutingConfig {
 		MinRouteProbability:      cfg.MinRouteProbability,
 		AttemptCost:              cfg.AttemptCost,
 		AttemptCostPPM:           cfg.AttemptCostPPM,
+		LatencyCost:              cfg.LatencyCost,
 		MaxMcHistory:             cfg.MaxMcHistory,
 		McFlushInterval:          cfg.McFlushInterval,
 		AprioriConfig: &AprioriConfig{
@@ -103,5 +134,71 @@ func GetRoutingConfig(cfg *Config) *RoutingConfig {
 			DecayTime:  cfg.BimodalConfig.DecayTime,
 		},
 		FeeEstimationTimeout: cfg.FeeEstimationTimeout,
+		TrampolineConfig: &TrampolineConfig{
+			Forward:     cfg.TrampolineConfig.Forward,
+			FeeBaseMsat: cfg.TrampolineConfig.FeeBaseMsat,
+			FeeRatePPM:  cfg.TrampolineConfig.FeeRatePPM,
+			CltvDelta:   cfg.TrampolineConfig.CltvDelta,
+		},
+		ProberConfig: &ProberConfig{
+			Enable:          cfg.ProberConfig.Enable,
+			Namespace:       cfg.ProberConfig.Namespace,
+			Interval:        cfg.ProberConfig.Interval,
+			Amount:          cfg.ProberConfig.Amount,
+			MaxInFlight:     cfg.ProberConfig.MaxInFlight,
+			TopDestinations: cfg.ProberConfig.TopDestinations,
+			Nodes:           cfg.ProberConfig.Nodes,
+			Channels:        cfg.ProberConfig.Channels,
+		},
+		RebalancerConfig: &RebalancerConfig{
+			Interval:       cfg.RebalancerConfig.Interval,
+			FeeRatio:       cfg.RebalancerConfig.FeeRatio,
+			MaxAmount:      cfg.RebalancerConfig.MaxAmount,
+			PaymentTimeout: cfg.RebalancerConfig.PaymentTimeout,
+			Targets:        cfg.RebalancerConfig.Targets,
+		},
 	}
 }
+
+// Budget returns the trampoline hop budget defined by the config.
+func (c *TrampolineConfig) Budget() trampoline.Budget {
+	return trampoline.Budget{
+		FeeBaseMsat: lnwire.MilliSatoshi(c.FeeBaseMsat),
+		FeeRatePPM:  c.FeeRatePPM,
+		CltvDelta:   c.CltvDelta,
+	}
+}
+
+// ParseTargets parses the target balances of the autonomous rebalancer into
+// a map from short channel ID to target ratio.
+func (c *RebalancerConfig) ParseTargets() (map[uint64]float64, error) {
+	targets := make(map[uint64]float64, len(c.Targets))
+	for _, target := range c.Targets {
+		scid, ratio, ok := strings.Cut(target, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rebalance target %q, "+
+				"expected scid:ratio", target)
+		}
+
+		chanID, err := strconv.ParseUint(scid, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel in rebalance "+
+				"target %q: %w", target, err)
+		}
+
+		value, err := strconv.ParseFloat(ratio, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ratio in rebalance "+
+				"target %q: %w", target, err)
+		}
+
+		if value < 0 || value > 1 {
+			return nil, fmt.Errorf("ratio of rebalance target %q "+
+				"must be in [0, 1]", target)
+		}
+
+		targets[chanID] = value
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,253 @@
+package routerrpc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultPathFinderTimeout is the time we wait for an answer of an
+	// external path finder if the client doesn't specify a timeout.
+	DefaultPathFinderTimeout = time.Second
+
+	// MaxPathFinderTimeout is the maximum time that a client can ask us
+	// to wait for its answers. Payments are blocked while we wait, so the
+	// timeout is capped.
+	MaxPathFinderTimeout = time.Minute
+)
+
+var (
+	// ErrPathFinderTimeout is returned when the external path finder
+	// didn't answer a request in time.
+	ErrPathFinderTimeout = errors.New("external path finder timed out")
+
+	// ErrPathFinderDisconnected is returned when the external path finder
+	// disconnects while a request is pending.
+	ErrPathFinderDisconnected = errors.New("external path finder " +
+		"disconnected")
+)
+
+// externalPathFinder is a helper struct that handles the lifecycle of an RPC
+// path finding streaming session. It is created when the stream opens and
+// acts as the path finding provider of the router until the stream closes.
+type externalPathFinder struct {
+	// stream is the bidirectional RPC stream.
+	stream Router_ExternalPathFinderServer
+
+	// sendMu serializes the requests that are sent on the stream, as path
+	// finding runs of several payments can happen concurrently.
+	sendMu sync.Mutex
+
+	// mode and timeout are set by the registration of the client.
+	mode    PathFinderMode
+	timeout time.Duration
+
+	// nextID is the identifier of the next request.
+	nextID atomic.Uint64
+
+	// pending holds the channels that the answers to the pending requests
+	// are delivered on.
+	pending   map[uint64]chan *PathFinderResult
+	pendingMu sync.Mutex
+
+	quit chan struct{}
+}
+
+// A compile time check to ensure externalPathFinder implements the
+// routing.PathFindingProvider interface.
+var _ routing.PathFindingProvider = (*externalPathFinder)(nil)
+
+// newExternalPathFinder creates a new externalPathFinder.
+func newExternalPathFinder(
+	stream Router_ExternalPathFinderServer) *externalPathFinder {
+
+	return &externalPathFinder{
+		stream:  stream,
+		pending: make(map[uint64]chan *PathFinderResult),
+		quit:    make(chan struct{}),
+	}
+}
+
+// run receives the registration of the client and registers it as path
+// finding provider. Afterwards, it delivers the answers of the client to the
+// pending requests until the stream closes.
+func (e *externalPathFinder) run(hook *routing.ExternalPathFinder) error {
+	msg, err := e.stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	registration := msg.GetRegistration()
+	if registration == nil {
+		return status.Error(codes.InvalidArgument, "first message "+
+			"must be a registration")
+	}
+
+	switch registration.Mode {
+	case PathFinderMode_PROBABILITY_ESTIMATOR,
+		PathFinderMode_ROUTE_PROVIDER:
+
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown path "+
+			"finder mode %v", registration.Mode)
+	}
+	e.mode = registration.Mode
+
+	e.timeout = DefaultPathFinderTimeout
+	if registration.TimeoutMs != 0 {
+		e.timeout = time.Duration(registration.TimeoutMs) *
+			time.Millisecond
+	}
+	if e.timeout > MaxPathFinderTimeout {
+		return status.Errorf(codes.InvalidArgument, "timeout exceeds "+
+			"maximum of %v", MaxPathFinderTimeout)
+	}
+
+	if err := hook.RegisterProvider(e); err != nil {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	defer hook.UnregisterProvider(e)
+
+	// Unblock the pending requests when the stream closes.
+	defer close(e.quit)
+
+	log.Infof("External path finder registered: mode=%v, timeout=%v",
+		e.mode, e.timeout)
+
+	for {
+		msg, err := e.stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		result := msg.GetResult()
+		if result == nil {
+			return status.Error(codes.InvalidArgument, "result "+
+				"expected")
+		}
+
+		e.pendingMu.Lock()
+		resultChan, ok := e.pending[result.RequestId]
+		delete(e.pending, result.RequestId)
+		e.pendingMu.Unlock()
+
+		// The request may have timed out already.
+		if !ok {
+			log.Debugf("Dropping path finder result for unknown "+
+				"request %v", result.RequestId)
+
+			continue
+		}
+
+		resultChan <- result
+	}
+}
+
+// QueryPathFinding sends the request to the client and waits for its answer.
+//
+// NOTE: This is part of the routing.PathFindingProvider interface.
+func (e *externalPathFinder) QueryPathFinding(
+	req *routing.PathFindingRequest) (*routing.PathFindingResponse,
+	error) {
+
+	id := e.nextID.Add(1)
+
+	// The channel is buffered, so that the answer can be delivered even
+	// if we stopped waiting for it.
+	resultChan := make(chan *PathFinderResult, 1)
+
+	e.pendingMu.Lock()
+	e.pending[id] = resultChan
+	e.pendingMu.Unlock()
+
+	defer func() {
+		e.pendingMu.Lock()
+		delete(e.pending, id)
+		e.pendingMu.Unlock()
+	}()
+
+	rpcReq := &PathFinderRequest{
+		RequestId:       id,
+		Source:          req.Source[:],
+		Target:          req.Target[:],
+		AmtMsat:         uint64(req.Amount),
+		FeeLimitMsat:    uint64(req.FeeLimit),
+		CltvLimit:       req.CltvLimit,
+		OutgoingChanIds: req.OutgoingChannelIDs,
+	}
+	if req.LastHop != nil {
+		rpcReq.LastHopPubkey = req.LastHop[:]
+	}
+
+	e.sendMu.Lock()
+	err := e.stream.Send(rpcReq)
+	e.sendMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case result := <-resultChan:
+		return e.parseResult(result)
+
+	case <-time.After(e.timeout):
+		return nil, ErrPathFinderTimeout
+
+	case <-e.quit:
+		return nil, ErrPathFinderDisconnected
+	}
+}
+
+// parseResult converts the answer of the client into a path finding response
+// according to the registered mode.
+func (e *externalPathFinder) parseResult(
+	result *PathFinderResult) (*routing.PathFindingResponse, error) {
+
+	resp := &routing.PathFindingResponse{}
+
+	if e.mode == PathFinderMode_ROUTE_PROVIDER {
+		for _, pubKey := range result.HopPubkeys {
+			hop, err := route.NewVertexFromBytes(pubKey)
+			if err != nil {
+				return nil, err
+			}
+			resp.Hops = append(resp.Hops, hop)
+		}
+
+		return resp, nil
+	}
+
+	resp.Probabilities = make(
+		map[routing.DirectedNodePair]float64,
+		len(result.Probabilities),
+	)
+	for _, p := range result.Probabilities {
+		if p.Probability < 0 || p.Probability > 1 {
+			return nil, fmt.Errorf("probability %v out of range",
+				p.Probability)
+		}
+
+		from, err := route.NewVertexFromBytes(p.NodeFrom)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := route.NewVertexFromBytes(p.NodeTo)
+		if err != nil {
+			return nil, err
+		}
+
+		pair := routing.NewDirectedNodePair(from, to)
+		resp.Probabilities[pair] = p.Probability
+	}
+
+	return resp, nil
+}
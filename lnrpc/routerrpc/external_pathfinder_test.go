@@ -0,0 +1,203 @@
+package routerrpc
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pathFinderStreamMock is a mock of the ExternalPathFinder stream.
+type pathFinderStreamMock struct {
+	grpc.ServerStream
+
+	// recvCalls is signaled whenever Recv is called.
+	recvCalls chan struct{}
+
+	toServer   chan *PathFinderResponse
+	fromServer chan *PathFinderRequest
+}
+
+func newPathFinderStreamMock() *pathFinderStreamMock {
+	return &pathFinderStreamMock{
+		recvCalls:  make(chan struct{}, 10),
+		toServer:   make(chan *PathFinderResponse),
+		fromServer: make(chan *PathFinderRequest, 10),
+	}
+}
+
+func (m *pathFinderStreamMock) Send(req *PathFinderRequest) error {
+	m.fromServer <- req
+	return nil
+}
+
+func (m *pathFinderStreamMock) Recv() (*PathFinderResponse, error) {
+	m.recvCalls <- struct{}{}
+
+	msg, ok := <-m.toServer
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return msg, nil
+}
+
+// TestExternalPathFinder tests the lifecycle of an external path finder
+// stream and the delivery of its answers.
+func TestExternalPathFinder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hook   = routing.NewExternalPathFinder()
+		stream = newPathFinderStreamMock()
+		finder = newExternalPathFinder(stream)
+		errs   = make(chan error, 1)
+	)
+
+	go func() {
+		errs <- finder.run(hook)
+	}()
+
+	// Register as probability estimator and wait until the registration
+	// has been processed.
+	mode := PathFinderMode_PROBABILITY_ESTIMATOR
+	registration := &PathFinderResponse{
+		Response: &PathFinderResponse_Registration{
+			Registration: &PathFinderRegistration{
+				Mode:      mode,
+				TimeoutMs: 200,
+			},
+		},
+	}
+	stream.toServer <- registration
+	<-stream.recvCalls
+	<-stream.recvCalls
+
+	// A second client can't register at the same time.
+	secondStream := newPathFinderStreamMock()
+	secondErrs := make(chan error, 1)
+	go func() {
+		secondErrs <- newExternalPathFinder(secondStream).run(hook)
+	}()
+	secondStream.toServer <- registration
+	require.Equal(t, codes.AlreadyExists, status.Code(<-secondErrs))
+
+	query := func() chan error {
+		t.Helper()
+
+		queryErrs := make(chan error, 1)
+		go func() {
+			resp, err := finder.QueryPathFinding(
+				&routing.PathFindingRequest{
+					Source: route.Vertex{1},
+					Target: route.Vertex{2},
+					Amount: lnwire.MilliSatoshi(1000),
+				},
+			)
+			if err != nil {
+				queryErrs <- err
+				return
+			}
+
+			pair := routing.NewDirectedNodePair(
+				route.Vertex{1}, route.Vertex{2},
+			)
+			if resp.Probabilities[pair] != 0.3 {
+				err = fmt.Errorf("unexpected probabilities: "+
+					"%v", resp.Probabilities)
+			}
+			queryErrs <- err
+		}()
+
+		return queryErrs
+	}
+
+	// The request is sent to the client, and its answer is returned.
+	queryErrs := query()
+	req := <-stream.fromServer
+	require.Equal(t, route.Vertex{2}, route.Vertex(req.Target))
+	require.EqualValues(t, 1000, req.AmtMsat)
+
+	stream.toServer <- &PathFinderResponse{
+		Response: &PathFinderResponse_Result{
+			Result: &PathFinderResult{
+				RequestId: req.RequestId,
+				Probabilities: []*NodePairProbability{{
+					NodeFrom:    req.Source,
+					NodeTo:      req.Target,
+					Probability: 0.3,
+				}},
+			},
+		},
+	}
+	require.NoError(t, <-queryErrs)
+
+	// If the client doesn't answer in time, the request times out. A late
+	// answer is dropped.
+	queryErrs = query()
+	req = <-stream.fromServer
+	require.ErrorIs(t, <-queryErrs, ErrPathFinderTimeout)
+
+	stream.toServer <- &PathFinderResponse{
+		Response: &PathFinderResponse_Result{
+			Result: &PathFinderResult{
+				RequestId: req.RequestId,
+			},
+		},
+	}
+
+	// Pending requests fail when the client disconnects, after which
+	// another provider can register.
+	queryErrs = query()
+	<-stream.fromServer
+	close(stream.toServer)
+
+	require.ErrorIs(t, <-errs, io.EOF)
+	require.ErrorIs(t, <-queryErrs, ErrPathFinderDisconnected)
+	require.NoError(t, hook.RegisterProvider(finder))
+}
+
+// TestExternalPathFinderRegistration tests that clients must register before
+// they send results.
+func TestExternalPathFinderRegistration(t *testing.T) {
+	t.Parallel()
+
+	hook := routing.NewExternalPathFinder()
+
+	run := func(msg *PathFinderResponse) error {
+		stream := newPathFinderStreamMock()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- newExternalPathFinder(stream).run(hook)
+		}()
+
+		stream.toServer <- msg
+
+		return <-errs
+	}
+
+	err := run(&PathFinderResponse{
+		Response: &PathFinderResponse_Result{
+			Result: &PathFinderResult{},
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = run(&PathFinderResponse{
+		Response: &PathFinderResponse_Registration{
+			Registration: &PathFinderRegistration{
+				TimeoutMs: uint32(
+					2 * MaxPathFinderTimeout.Milliseconds(),
+				),
+			},
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
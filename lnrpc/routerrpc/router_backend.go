@@ -21,10 +21,14 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/offers"
+	"github.com/lightningnetwork/lnd/rebalance"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/reputation"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/trampoline"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"google.golang.org/protobuf/proto"
 )
@@ -36,6 +40,11 @@ const (
 	// TODO(roasbeef): make this value dynamic based on expected number of
 	// attempts for given amount.
 	DefaultMaxParts = 16
+
+	// MaxQueryRoutes is the maximum number of alternative routes that can
+	// be requested from QueryRoutes. Every additional route requires
+	// several path finding runs, so the number is capped.
+	MaxQueryRoutes = 20
 )
 
 // RouterBackend contains the backend implementation of the router rpc sub
@@ -62,8 +71,33 @@ type RouterBackend struct {
 	// routes.
 	FindRoute func(*routing.RouteRequest) (*route.Route, float64, error)
 
+	// FindRoutes is a closure that locates up to the given number of
+	// distinct routes, ordered from best to worst, together with their
+	// success probabilities.
+	FindRoutes func(*routing.RouteRequest, uint32) ([]*route.Route,
+		[]float64, error)
+
 	MissionControl MissionControl
 
+	// FetchMissionControl returns the mission control of the given
+	// namespace. It returns routing.ErrNamespaceNotFound if the namespace
+	// doesn't exist.
+	FetchMissionControl func(namespace string) (MissionControl, error)
+
+	// CreateMissionControlNamespace creates a new mission control
+	// namespace. If the config is nil, the config of the default namespace
+	// is used.
+	CreateMissionControlNamespace func(namespace string,
+		cfg *routing.MissionControlConfig) error
+
+	// DeleteMissionControlNamespace deletes a mission control namespace
+	// along with its history.
+	DeleteMissionControlNamespace func(namespace string) error
+
+	// ListMissionControlNamespaces returns the names of the existing
+	// mission control namespaces.
+	ListMissionControlNamespaces func() []string
+
 	// ActiveNetParams are the network parameters of the primary network
 	// that the route is operating on. This is necessary so we can ensure
 	// that we receive payment requests that send to destinations on our
@@ -110,6 +144,25 @@ type RouterBackend struct {
 	// ParseCustomChannelData is a function that can be used to parse custom
 	// channel data from the first hop of a route.
 	ParseCustomChannelData func(message proto.Message) error
+
+	// TrampolineBudget is the fee and CLTV budget we leave to trampoline
+	// nodes that we send payments through.
+	TrampolineBudget trampoline.Budget
+
+	// BestHeight returns the current best block height.
+	BestHeight func() (uint32, error)
+
+	// Reputation is the manager that tracks the reputation of our
+	// channels. It is nil if reputation tracking is disabled.
+	Reputation *reputation.Manager
+
+	// ExternalPathFinder is the hook that external path finding providers
+	// register with.
+	ExternalPathFinder *routing.ExternalPathFinder
+
+	// Rebalancer moves liquidity between our channels with circular
+	// payments.
+	Rebalancer *rebalance.Rebalancer
 }
 
 // MissionControl defines the mission control dependencies of routerrpc.
@@ -119,6 +172,10 @@ type MissionControl interface {
 	GetProbability(fromNode, toNode route.Vertex,
 		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 
+	// GetLatency is expected to return the expected latency of forwarding
+	// an htlc from fromNode to toNode and relaying the result back.
+	GetLatency(fromNode, toNode route.Vertex) time.Duration
+
 	// ResetHistory resets the history of MissionControl returning it to a
 	// state as if no payment attempts have been made.
 	ResetHistory() error
@@ -145,41 +202,70 @@ type MissionControl interface {
 	SetConfig(cfg *routing.MissionControlConfig) error
 }
 
-// QueryRoutes attempts to query the daemons' Channel Router for a possible
-// route to a target destination capable of carrying a specific amount of
-// satoshis within the route's flow. The returned route contains the full
+// namespacedMissionControl returns the mission control of the given namespace.
+// An empty namespace refers to the default one.
+func (r *RouterBackend) namespacedMissionControl(
+	namespace string) (MissionControl, error) {
+
+	if namespace == "" {
+		return r.MissionControl, nil
+	}
+
+	if r.FetchMissionControl == nil {
+		return nil, fmt.Errorf("%w: %v", routing.ErrNamespaceNotFound,
+			namespace)
+	}
+
+	return r.FetchMissionControl(namespace)
+}
+
+// QueryRoutes attempts to query the daemons' Channel Router for possible
+// routes to a target destination capable of carrying a specific amount of
+// satoshis within the route's flow. The returned routes contain the full
 // details required to craft and send an HTLC, also including the necessary
 // information that should be present within the Sphinx packet encapsulated
-// within the HTLC.
-//
-// TODO(roasbeef): should return a slice of routes in reality * create separate
-// PR to send based on well formatted route
+// within the HTLC. Unless more are requested, a single route is returned.
 func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	in *lnrpc.QueryRoutesRequest) (*lnrpc.QueryRoutesResponse, error) {
 
+	numRoutes := in.NumRoutes
+	switch {
+	case numRoutes == 0:
+		numRoutes = 1
+
+	case numRoutes > MaxQueryRoutes:
+		return nil, fmt.Errorf("number of routes %v exceeds maximum "+
+			"of %v", numRoutes, MaxQueryRoutes)
+	}
+
 	routeReq, err := r.parseQueryRoutesRequest(in)
 	if err != nil {
 		return nil, err
 	}
 
-	// Query the channel router for a possible path to the destination that
+	// Query the channel router for possible paths to the destination that
 	// can carry `in.Amt` satoshis _including_ the total fee required on
 	// the route
-	route, successProb, err := r.FindRoute(routeReq)
+	routes, successProbs, err := r.FindRoutes(routeReq, numRoutes)
 	if err != nil {
 		return nil, err
 	}
 
 	// For each valid route, we'll convert the result into the format
 	// required by the RPC system.
-	rpcRoute, err := r.MarshallRoute(route)
-	if err != nil {
-		return nil, err
+	rpcRoutes := make([]*lnrpc.Route, 0, len(routes))
+	for _, route := range routes {
+		rpcRoute, err := r.MarshallRoute(route)
+		if err != nil {
+			return nil, err
+		}
+		rpcRoutes = append(rpcRoutes, rpcRoute)
 	}
 
 	routeResp := &lnrpc.QueryRoutesResponse{
-		Routes:      []*lnrpc.Route{rpcRoute},
-		SuccessProb: successProb,
+		Routes:       rpcRoutes,
+		SuccessProb:  successProbs[0],
+		SuccessProbs: successProbs,
 	}
 
 	return routeResp, nil
@@ -368,6 +454,11 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 		return nil, err
 	}
 
+	mc, err := r.namespacedMissionControl(in.MissionControlNamespace)
+	if err != nil {
+		return nil, err
+	}
+
 	restrictions := &routing.RestrictParams{
 		FeeLimit: feeLimit,
 		ProbabilitySource: func(fromNode, toNode route.Vertex,
@@ -390,7 +481,7 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 				return 1
 			}
 
-			return r.MissionControl.GetProbability(
+			return mc.GetProbability(
 				fromNode, toNode, amt, capacity,
 			)
 		},
@@ -400,6 +491,12 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 		BlindedPaymentPathSet: blindedPathSet,
 	}
 
+	// Take the measured latencies of the pairs into account if mission
+	// control is used.
+	if in.UseMissionControl {
+		restrictions.LatencySource = mc.GetLatency
+	}
+
 	// Pass along an outgoing channel restriction if specified.
 	if in.OutgoingChanId != 0 {
 		restrictions.OutgoingChannelIDs = []uint64{in.OutgoingChanId}
@@ -866,6 +963,19 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.MaxShardAmt = &shardAmtMsat
 	}
 
+	// Pass along the strategy to split the payment with.
+	switch rpcPayReq.SplitStrategy {
+	case PaymentSplitStrategy_SPLIT_BINARY:
+		payIntent.SplitStrategy = routing.SplitStrategyBinary
+
+	case PaymentSplitStrategy_SPLIT_MIN_COST_FLOW:
+		payIntent.SplitStrategy = routing.SplitStrategyMinCostFlow
+
+	default:
+		return nil, fmt.Errorf("unknown split strategy %v",
+			rpcPayReq.SplitStrategy)
+	}
+
 	// Take fee limit from request.
 	payIntent.FeeLimit, err = lnrpc.UnmarshallAmt(
 		rpcPayReq.FeeLimitSat, rpcPayReq.FeeLimitMsat,
@@ -891,6 +1001,8 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	}
 	payIntent.FirstHopCustomRecords = firstHopRecords
 
+	payIntent.MissionControlNamespace = rpcPayReq.MissionControlNamespace
+
 	payIntent.PayAttemptTimeout = time.Second *
 		time.Duration(rpcPayReq.TimeoutSeconds)
 
@@ -1054,6 +1166,11 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 				payIntent.DestFeatures = pathFeatures.Clone()
 			}
 		}
+	} else if rpcPayReq.Bolt12Invoice != "" {
+		err := r.extractBolt12Intent(payIntent, rpcPayReq, reqAmt)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		// Otherwise, If the payment request field was not specified
 		// (and a custom route wasn't specified), construct the payment
@@ -1175,6 +1292,15 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.DestFeatures = features
 	}
 
+	// If a trampoline node is specified, we pay the trampoline node
+	// instead, which in turn pays the recipient.
+	if len(rpcPayReq.TrampolineNode) > 0 {
+		err := r.applyTrampoline(payIntent, rpcPayReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Do bounds checking with the block padding so the router isn't
 	// left with a zombie payment in case the user messes up.
 	err = routing.ValidateCLTVLimit(
@@ -1192,6 +1318,184 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	return payIntent, nil
 }
 
+// applyTrampoline turns the payment into a payment to the trampoline node
+// given in the request, carrying a trampoline onion that instructs it to pay
+// the original recipient.
+func (r *RouterBackend) applyTrampoline(payIntent *routing.LightningPayment,
+	rpcPayReq *SendPaymentRequest) error {
+
+	switch {
+	case rpcPayReq.Amp:
+		return errors.New("AMP payments cannot be sent through a " +
+			"trampoline node")
+
+	case len(payIntent.DestCustomRecords) > 0:
+		return errors.New("custom records cannot be sent through a " +
+			"trampoline node")
+
+	case len(payIntent.RouteHints) > 0 || payIntent.BlindedPathSet != nil:
+		return errors.New("payments with route hints or blinded " +
+			"paths cannot be sent through a trampoline node")
+	}
+
+	trampolineNode, err := btcec.ParsePubKey(rpcPayReq.TrampolineNode)
+	if err != nil {
+		return fmt.Errorf("invalid trampoline node: %w", err)
+	}
+
+	target, err := btcec.ParsePubKey(payIntent.Target[:])
+	if err != nil {
+		return err
+	}
+
+	height, err := r.BestHeight()
+	if err != nil {
+		return err
+	}
+
+	payment, err := trampoline.NewPayment(&trampoline.PaymentRequest{
+		TrampolineNode: trampolineNode,
+		Target:         target,
+		Amount:         payIntent.Amount,
+		PaymentHash:    payIntent.Identifier(),
+		PaymentAddr:    payIntent.PaymentAddr,
+		Metadata:       payIntent.Metadata,
+		FinalCLTVDelta: payIntent.FinalCLTVDelta,
+		DestFeatures:   payIntent.DestFeatures,
+		CurrentHeight:  height,
+		Budget:         r.TrampolineBudget,
+	})
+	if err != nil {
+		return err
+	}
+
+	// We may not know the trampoline node from the graph, so we assume
+	// the features it needs to forward the payment.
+	payIntent.DestFeatures = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadOptional,
+			lnwire.PaymentAddrOptional,
+			lnwire.MPPOptional,
+			lnwire.TrampolineRoutingOptional,
+		), lnwire.Features,
+	)
+	payIntent.Target = route.NewVertex(trampolineNode)
+	payIntent.Amount = payment.Amount
+	payIntent.FinalCLTVDelta = payment.FinalCLTVDelta
+	payIntent.PaymentAddr = fn.Some(payment.PaymentAddr)
+	payIntent.TrampolineOnion = payment.Onion
+	payIntent.Metadata = nil
+
+	return nil
+}
+
+// extractBolt12Intent populates the payment intent with the details of the
+// BOLT 12 invoice of the request. The payment is sent along the blinded paths
+// of the invoice.
+func (r *RouterBackend) extractBolt12Intent(payIntent *routing.LightningPayment,
+	rpcPayReq *SendPaymentRequest, reqAmt lnwire.MilliSatoshi) error {
+
+	switch {
+	case rpcPayReq.PaymentRequest != "":
+		return errors.New("payment_request and bolt12_invoice " +
+			"cannot appear together")
+
+	case len(rpcPayReq.Dest) > 0:
+		return errors.New("dest and bolt12_invoice cannot appear " +
+			"together")
+
+	case len(rpcPayReq.PaymentHash) > 0:
+		return errors.New("payment_hash and bolt12_invoice cannot " +
+			"appear together")
+
+	case rpcPayReq.FinalCltvDelta != 0:
+		return errors.New("final_cltv_delta and bolt12_invoice " +
+			"cannot appear together")
+
+	case rpcPayReq.Amp:
+		return errors.New("AMP payments of BOLT 12 invoices are not " +
+			"supported")
+	}
+
+	invoice, err := offers.DecodeInvoice(rpcPayReq.Bolt12Invoice)
+	if err != nil {
+		return err
+	}
+
+	if invoice.IsExpired(time.Now()) {
+		return fmt.Errorf("invoice expired. Valid until %v",
+			invoice.ExpiresAt())
+	}
+
+	if reqAmt != 0 && reqAmt != invoice.Amount {
+		return errors.New("amount must not be specified when paying " +
+			"a BOLT 12 invoice")
+	}
+	payIntent.Amount = invoice.Amount
+
+	err = payIntent.SetPaymentHash(invoice.PaymentHash)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]*zpay32.BlindedPaymentPath, len(invoice.Paths))
+	for i, path := range invoice.Paths {
+		payInfo := invoice.PayInfo[i]
+		paths[i] = &zpay32.BlindedPaymentPath{
+			FeeBaseMsat:     payInfo.FeeBaseMsat,
+			FeeRate:         payInfo.FeeProportionalMillionths,
+			CltvExpiryDelta: payInfo.CltvExpiryDelta,
+			HTLCMinMsat:     uint64(payInfo.HtlcMinimumMsat),
+			HTLCMaxMsat:     uint64(payInfo.HtlcMaximumMsat),
+			Features: lnwire.NewFeatureVector(
+				payInfo.Features, lnwire.Features,
+			),
+			FirstEphemeralBlindingPoint: path.BlindingPoint,
+			Hops: append(
+				[]*sphinx.BlindedHopInfo{}, path.BlindedHops...,
+			),
+		}
+
+		// The introduction node is reached by its real node ID.
+		paths[i].Hops[0] = &sphinx.BlindedHopInfo{
+			BlindedNodePub: path.IntroductionPoint,
+			CipherText:     path.BlindedHops[0].CipherText,
+		}
+	}
+
+	pathSet, err := BuildBlindedPathSet(paths)
+	if err != nil {
+		return err
+	}
+	payIntent.BlindedPathSet = pathSet
+
+	copy(payIntent.Target[:], pathSet.TargetPubKey().SerializeCompressed())
+
+	// The final CLTV delta of the recipient is included in the CLTV delta
+	// of the blinded paths.
+	payIntent.FinalCLTVDelta = 0
+
+	invoiceFeatures := lnwire.NewRawFeatureVector()
+	if invoice.Features != nil {
+		invoiceFeatures = invoice.Features
+	}
+	payIntent.DestFeatures = lnwire.NewFeatureVector(
+		invoiceFeatures, lnwire.Features,
+	)
+	if !payIntent.DestFeatures.HasFeature(lnwire.MPPOptional) {
+		payIntent.MaxParts = 1
+	}
+
+	pathFeatures := pathSet.Features()
+	if !pathFeatures.IsEmpty() {
+		payIntent.DestFeatures = pathFeatures.Clone()
+	}
+
+	payIntent.PaymentRequest = []byte(rpcPayReq.Bolt12Invoice)
+
+	return nil
+}
+
 // BuildBlindedPathSet marshals a set of zpay32.BlindedPaymentPath and uses
 // the result to build a new routing.BlindedPaymentPathSet.
 func BuildBlindedPathSet(paths []*zpay32.BlindedPaymentPath) (
@@ -1495,7 +1799,7 @@ func MarshalTimeNano(t time.Time) int64 {
 func marshallError(sendError error) (*lnrpc.Failure, error) {
 	response := &lnrpc.Failure{}
 
-	if sendError == htlcswitch.ErrUnreadableFailureMessage {
+	if errors.Is(sendError, htlcswitch.ErrUnreadableFailureMessage) {
 		response.Code = lnrpc.Failure_UNREADABLE_FAILURE
 		return response, nil
 	}
@@ -1725,6 +2029,7 @@ func (r *RouterBackend) MarshallPayment(payment *channeldb.MPPayment) (
 		PaymentIndex:          payment.SequenceNum,
 		FailureReason:         failureReason,
 		FirstHopCustomRecords: payment.Info.FirstHopCustomRecords,
+		Label:                 payment.Info.Label,
 	}, nil
 }
 
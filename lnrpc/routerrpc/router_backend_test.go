@@ -5,12 +5,14 @@ import (
 	"context"
 	"encoding/hex"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/trampoline"
 	"github.com/stretchr/testify/require"
 )
 
@@ -192,9 +194,22 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 		return route, expectedProb, err
 	}
 
+	findRoutes := func(req *routing.RouteRequest, numRoutes uint32) (
+		[]*route.Route, []float64, error) {
+
+		require.EqualValues(t, 1, numRoutes)
+
+		rt, prob, err := findRoute(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []*route.Route{rt}, []float64{prob}, nil
+	}
+
 	backend := &RouterBackend{
-		FindRoute: findRoute,
-		SelfNode:  route.Vertex{1, 2, 3},
+		FindRoutes: findRoutes,
+		SelfNode:   route.Vertex{1, 2, 3},
 		FetchChannelCapacity: func(chanID uint64) (
 			btcutil.Amount, error) {
 
@@ -241,6 +256,62 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 	}
 }
 
+// TestQueryRoutesNumRoutes tests that the requested number of routes is passed
+// on to the router and that all returned routes end up in the response.
+func TestQueryRoutesNumRoutes(t *testing.T) {
+	t.Parallel()
+
+	probs := []float64{0.9, 0.5, 0.2}
+	backend := &RouterBackend{
+		SelfNode: route.Vertex{1, 2, 3},
+		FindRoutes: func(req *routing.RouteRequest, numRoutes uint32) (
+			[]*route.Route, []float64, error) {
+
+			require.EqualValues(t, 5, numRoutes)
+
+			var routes []*route.Route
+			for i := range probs {
+				hops := []*route.Hop{{ChannelID: uint64(i)}}
+				rt, err := route.NewRouteFromHops(
+					req.Amount, 144, req.Source, hops,
+				)
+				require.NoError(t, err)
+
+				routes = append(routes, rt)
+			}
+
+			return routes, probs, nil
+		},
+		FetchChannelCapacity: func(chanID uint64) (
+			btcutil.Amount, error) {
+
+			return 1, nil
+		},
+		MissionControl:   &mockMissionControl{},
+		MaxTotalTimelock: 1000,
+	}
+
+	request := &lnrpc.QueryRoutesRequest{
+		PubKey:    destKey,
+		Amt:       100000,
+		NumRoutes: 5,
+	}
+
+	resp, err := backend.QueryRoutes(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, resp.Routes, len(probs))
+	for i, rpcRoute := range resp.Routes {
+		require.EqualValues(t, i, rpcRoute.Hops[0].ChanId)
+	}
+	require.Equal(t, probs, resp.SuccessProbs)
+	require.Equal(t, probs[0], resp.SuccessProb)
+
+	// Requesting more than the maximum number of routes fails.
+	request.NumRoutes = MaxQueryRoutes + 1
+	_, err = backend.QueryRoutes(context.Background(), request)
+	require.ErrorContains(t, err, "exceeds maximum")
+}
+
 type mockMissionControl struct {
 	MissionControl
 }
@@ -251,6 +322,12 @@ func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
 	return testMissionControlProb
 }
 
+func (m *mockMissionControl) GetLatency(fromNode,
+	toNode route.Vertex) time.Duration {
+
+	return routing.DefaultPairLatency
+}
+
 func (m *mockMissionControl) ResetHistory() error {
 	return nil
 }
@@ -475,3 +552,98 @@ func testUnmarshalAMP(t *testing.T, test unmarshalAMPTest) {
 		t.Fatalf("test case has non-standard outcome")
 	}
 }
+
+// TestExtractIntentTrampoline asserts that payments through a trampoline node
+// are turned into payments to the trampoline node carrying a trampoline onion.
+func TestExtractIntentTrampoline(t *testing.T) {
+	t.Parallel()
+
+	trampolineNode, err := route.NewVertexFromStr(hintNodeKey)
+	require.NoError(t, err)
+
+	dest, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	budget := trampoline.Budget{
+		FeeBaseMsat: 1000,
+		FeeRatePPM:  1000,
+		CltvDelta:   100,
+	}
+	backend := &RouterBackend{
+		SelfNode:              sourceKey,
+		MaxTotalTimelock:      1000,
+		DefaultFinalCltvDelta: 40,
+		TrampolineBudget:      budget,
+		BestHeight: func() (uint32, error) {
+			return 100, nil
+		},
+	}
+
+	req := &SendPaymentRequest{
+		Dest:           dest,
+		AmtMsat:        100_000,
+		PaymentHash:    make([]byte, 32),
+		PaymentAddr:    make([]byte, 32),
+		TimeoutSeconds: 60,
+		TrampolineNode: trampolineNode[:],
+	}
+
+	payIntent, err := backend.extractIntentFromSendRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, trampolineNode, payIntent.Target)
+	require.EqualValues(t, 100_000+1000+100, payIntent.Amount)
+	require.EqualValues(t, 40+100, payIntent.FinalCLTVDelta)
+	require.NotEmpty(t, payIntent.TrampolineOnion)
+	require.True(t, payIntent.DestFeatures.HasFeature(
+		lnwire.TrampolineRoutingOptional,
+	))
+
+	// Trampoline payments require a payment address.
+	req.PaymentAddr = nil
+	_, err = backend.extractIntentFromSendRequest(req)
+	require.Error(t, err)
+
+	// Custom records can't be passed through the trampoline node.
+	req.PaymentAddr = make([]byte, 32)
+	req.DestCustomRecords = map[uint64][]byte{65536: {1}}
+	_, err = backend.extractIntentFromSendRequest(req)
+	require.Error(t, err)
+}
+
+// TestExtractIntentSplitStrategy asserts that the split strategy of a payment
+// request is passed on to the payment.
+func TestExtractIntentSplitStrategy(t *testing.T) {
+	t.Parallel()
+
+	dest, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	backend := &RouterBackend{
+		SelfNode:              sourceKey,
+		MaxTotalTimelock:      1000,
+		DefaultFinalCltvDelta: 40,
+	}
+
+	req := &SendPaymentRequest{
+		Dest:           dest,
+		AmtMsat:        100_000,
+		PaymentHash:    make([]byte, 32),
+		PaymentAddr:    make([]byte, 32),
+		TimeoutSeconds: 60,
+	}
+
+	payIntent, err := backend.extractIntentFromSendRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, routing.SplitStrategyBinary, payIntent.SplitStrategy)
+
+	req.SplitStrategy = PaymentSplitStrategy_SPLIT_MIN_COST_FLOW
+	payIntent, err = backend.extractIntentFromSendRequest(req)
+	require.NoError(t, err)
+	require.Equal(
+		t, routing.SplitStrategyMinCostFlow, payIntent.SplitStrategy,
+	)
+
+	req.SplitStrategy = 2
+	_, err = backend.extractIntentFromSendRequest(req)
+	require.Error(t, err)
+}
@@ -128,6 +128,53 @@ type RouterClient interface {
 	// operation is returned. The deletion will not be communicated to the channel
 	// peer via any message.
 	XDeleteLocalChanAliases(ctx context.Context, in *DeleteAliasesRequest, opts ...grpc.CallOption) (*DeleteAliasesResponse, error)
+	// lncli: `queryreputation`
+	// QueryReputation returns the reputation that our channels earned by
+	// forwarding htlcs to us, and the usage of the htlc slots and liquidity of
+	// our channels that are reserved for endorsed htlcs. This requires
+	// htlcswitch.reputation.enable to be set.
+	QueryReputation(ctx context.Context, in *QueryReputationRequest, opts ...grpc.CallOption) (*QueryReputationResponse, error)
+	// ExternalPathFinder dispatches a bi-directional streaming RPC in which an
+	// external process takes part in the path finding of our payments. The first
+	// message of the client registers it as probability estimator or route
+	// provider. Afterwards, a request is sent to the client for every path
+	// finding run, which the client answers with success probabilities for node
+	// pairs or with a complete route. If the client doesn't answer in time or
+	// its answer can't be used, the builtin path finding is used. Only one
+	// client can be registered at a time.
+	ExternalPathFinder(ctx context.Context, opts ...grpc.CallOption) (Router_ExternalPathFinderClient, error)
+	// Rebalance moves liquidity from one of our channels to another by paying
+	// ourselves over a circular route that leaves over the outgoing channel and
+	// returns over the incoming channel. The call blocks until the payment
+	// completes. The payment shows up in ListPayments with the label
+	// "rebalance", and the outcome is added to the rebalance history.
+	Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error)
+	// ListRebalances returns the history of rebalances, including the ones of
+	// the autonomous rebalancer, together with the total fees spent and volume
+	// moved.
+	ListRebalances(ctx context.Context, in *ListRebalancesRequest, opts ...grpc.CallOption) (*ListRebalancesResponse, error)
+	// SimulatePayment runs the path finding of SendPaymentV2 for the given
+	// payment against the local graph and mission control without sending
+	// anything. The shards are planned the same way as for a real payment,
+	// including splitting, fee limits and blinded paths of the invoice, assuming
+	// that every shard succeeds. The response holds the planned shards, their
+	// fees, time lock and success probability, and why edges were discarded
+	// during path finding. The timeout of the request is ignored.
+	SimulatePayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*SimulatePaymentResponse, error)
+	// lncli: `createmcnamespace`
+	// CreateMissionControlNamespace creates a new mission control namespace.
+	// Payments that select the namespace learn from and are routed with the
+	// history of the namespace only, which keeps e.g. probing payments apart from
+	// regular ones. The namespace and its config are persisted across restarts.
+	CreateMissionControlNamespace(ctx context.Context, in *CreateMissionControlNamespaceRequest, opts ...grpc.CallOption) (*CreateMissionControlNamespaceResponse, error)
+	// lncli: `deletemcnamespace`
+	// DeleteMissionControlNamespace deletes a mission control namespace together
+	// with its history and config. The default namespace can't be deleted.
+	DeleteMissionControlNamespace(ctx context.Context, in *DeleteMissionControlNamespaceRequest, opts ...grpc.CallOption) (*DeleteMissionControlNamespaceResponse, error)
+	// lncli: `listmcnamespaces`
+	// ListMissionControlNamespaces lists the existing mission control
+	// namespaces, including the default one.
+	ListMissionControlNamespaces(ctx context.Context, in *ListMissionControlNamespacesRequest, opts ...grpc.CallOption) (*ListMissionControlNamespacesResponse, error)
 }
 
 type routerClient struct {
@@ -481,6 +528,100 @@ func (c *routerClient) XDeleteLocalChanAliases(ctx context.Context, in *DeleteAl
 	return out, nil
 }
 
+func (c *routerClient) QueryReputation(ctx context.Context, in *QueryReputationRequest, opts ...grpc.CallOption) (*QueryReputationResponse, error) {
+	out := new(QueryReputationResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/QueryReputation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ExternalPathFinder(ctx context.Context, opts ...grpc.CallOption) (Router_ExternalPathFinderClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[7], "/routerrpc.Router/ExternalPathFinder", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerExternalPathFinderClient{stream}
+	return x, nil
+}
+
+type Router_ExternalPathFinderClient interface {
+	Send(*PathFinderResponse) error
+	Recv() (*PathFinderRequest, error)
+	grpc.ClientStream
+}
+
+type routerExternalPathFinderClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerExternalPathFinderClient) Send(m *PathFinderResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *routerExternalPathFinderClient) Recv() (*PathFinderRequest, error) {
+	m := new(PathFinderRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routerClient) Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error) {
+	out := new(RebalanceResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/Rebalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListRebalances(ctx context.Context, in *ListRebalancesRequest, opts ...grpc.CallOption) (*ListRebalancesResponse, error) {
+	out := new(ListRebalancesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListRebalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) SimulatePayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*SimulatePaymentResponse, error) {
+	out := new(SimulatePaymentResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/SimulatePayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) CreateMissionControlNamespace(ctx context.Context, in *CreateMissionControlNamespaceRequest, opts ...grpc.CallOption) (*CreateMissionControlNamespaceResponse, error) {
+	out := new(CreateMissionControlNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/CreateMissionControlNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) DeleteMissionControlNamespace(ctx context.Context, in *DeleteMissionControlNamespaceRequest, opts ...grpc.CallOption) (*DeleteMissionControlNamespaceResponse, error) {
+	out := new(DeleteMissionControlNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/DeleteMissionControlNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListMissionControlNamespaces(ctx context.Context, in *ListMissionControlNamespacesRequest, opts ...grpc.CallOption) (*ListMissionControlNamespacesResponse, error) {
+	out := new(ListMissionControlNamespacesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListMissionControlNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 // All implementations must embed UnimplementedRouterServer
 // for forward compatibility
@@ -594,6 +735,53 @@ type RouterServer interface {
 	// operation is returned. The deletion will not be communicated to the channel
 	// peer via any message.
 	XDeleteLocalChanAliases(context.Context, *DeleteAliasesRequest) (*DeleteAliasesResponse, error)
+	// lncli: `queryreputation`
+	// QueryReputation returns the reputation that our channels earned by
+	// forwarding htlcs to us, and the usage of the htlc slots and liquidity of
+	// our channels that are reserved for endorsed htlcs. This requires
+	// htlcswitch.reputation.enable to be set.
+	QueryReputation(context.Context, *QueryReputationRequest) (*QueryReputationResponse, error)
+	// ExternalPathFinder dispatches a bi-directional streaming RPC in which an
+	// external process takes part in the path finding of our payments. The first
+	// message of the client registers it as probability estimator or route
+	// provider. Afterwards, a request is sent to the client for every path
+	// finding run, which the client answers with success probabilities for node
+	// pairs or with a complete route. If the client doesn't answer in time or
+	// its answer can't be used, the builtin path finding is used. Only one
+	// client can be registered at a time.
+	ExternalPathFinder(Router_ExternalPathFinderServer) error
+	// Rebalance moves liquidity from one of our channels to another by paying
+	// ourselves over a circular route that leaves over the outgoing channel and
+	// returns over the incoming channel. The call blocks until the payment
+	// completes. The payment shows up in ListPayments with the label
+	// "rebalance", and the outcome is added to the rebalance history.
+	Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error)
+	// ListRebalances returns the history of rebalances, including the ones of
+	// the autonomous rebalancer, together with the total fees spent and volume
+	// moved.
+	ListRebalances(context.Context, *ListRebalancesRequest) (*ListRebalancesResponse, error)
+	// SimulatePayment runs the path finding of SendPaymentV2 for the given
+	// payment against the local graph and mission control without sending
+	// anything. The shards are planned the same way as for a real payment,
+	// including splitting, fee limits and blinded paths of the invoice, assuming
+	// that every shard succeeds. The response holds the planned shards, their
+	// fees, time lock and success probability, and why edges were discarded
+	// during path finding. The timeout of the request is ignored.
+	SimulatePayment(context.Context, *SendPaymentRequest) (*SimulatePaymentResponse, error)
+	// lncli: `createmcnamespace`
+	// CreateMissionControlNamespace creates a new mission control namespace.
+	// Payments that select the namespace learn from and are routed with the
+	// history of the namespace only, which keeps e.g. probing payments apart from
+	// regular ones. The namespace and its config are persisted across restarts.
+	CreateMissionControlNamespace(context.Context, *CreateMissionControlNamespaceRequest) (*CreateMissionControlNamespaceResponse, error)
+	// lncli: `deletemcnamespace`
+	// DeleteMissionControlNamespace deletes a mission control namespace together
+	// with its history and config. The default namespace can't be deleted.
+	DeleteMissionControlNamespace(context.Context, *DeleteMissionControlNamespaceRequest) (*DeleteMissionControlNamespaceResponse, error)
+	// lncli: `listmcnamespaces`
+	// ListMissionControlNamespaces lists the existing mission control
+	// namespaces, including the default one.
+	ListMissionControlNamespaces(context.Context, *ListMissionControlNamespacesRequest) (*ListMissionControlNamespacesResponse, error)
 	mustEmbedUnimplementedRouterServer()
 }
 
@@ -661,6 +849,30 @@ func (UnimplementedRouterServer) XAddLocalChanAliases(context.Context, *AddAlias
 func (UnimplementedRouterServer) XDeleteLocalChanAliases(context.Context, *DeleteAliasesRequest) (*DeleteAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method XDeleteLocalChanAliases not implemented")
 }
+func (UnimplementedRouterServer) QueryReputation(context.Context, *QueryReputationRequest) (*QueryReputationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryReputation not implemented")
+}
+func (UnimplementedRouterServer) ExternalPathFinder(Router_ExternalPathFinderServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExternalPathFinder not implemented")
+}
+func (UnimplementedRouterServer) Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
+func (UnimplementedRouterServer) ListRebalances(context.Context, *ListRebalancesRequest) (*ListRebalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRebalances not implemented")
+}
+func (UnimplementedRouterServer) SimulatePayment(context.Context, *SendPaymentRequest) (*SimulatePaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulatePayment not implemented")
+}
+func (UnimplementedRouterServer) CreateMissionControlNamespace(context.Context, *CreateMissionControlNamespaceRequest) (*CreateMissionControlNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMissionControlNamespace not implemented")
+}
+func (UnimplementedRouterServer) DeleteMissionControlNamespace(context.Context, *DeleteMissionControlNamespaceRequest) (*DeleteMissionControlNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMissionControlNamespace not implemented")
+}
+func (UnimplementedRouterServer) ListMissionControlNamespaces(context.Context, *ListMissionControlNamespacesRequest) (*ListMissionControlNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMissionControlNamespaces not implemented")
+}
 func (UnimplementedRouterServer) mustEmbedUnimplementedRouterServer() {}
 
 // UnsafeRouterServer may be embedded to opt out of forward compatibility for this service.
@@ -1060,6 +1272,158 @@ func _Router_XDeleteLocalChanAliases_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_QueryReputation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryReputationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).QueryReputation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/QueryReputation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).QueryReputation(ctx, req.(*QueryReputationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ExternalPathFinder_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RouterServer).ExternalPathFinder(&routerExternalPathFinderServer{stream})
+}
+
+type Router_ExternalPathFinderServer interface {
+	Send(*PathFinderRequest) error
+	Recv() (*PathFinderResponse, error)
+	grpc.ServerStream
+}
+
+type routerExternalPathFinderServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerExternalPathFinderServer) Send(m *PathFinderRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *routerExternalPathFinderServer) Recv() (*PathFinderResponse, error) {
+	m := new(PathFinderResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Router_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/Rebalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).Rebalance(ctx, req.(*RebalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListRebalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRebalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListRebalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListRebalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListRebalances(ctx, req.(*ListRebalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_SimulatePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).SimulatePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/SimulatePayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).SimulatePayment(ctx, req.(*SendPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_CreateMissionControlNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMissionControlNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).CreateMissionControlNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/CreateMissionControlNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).CreateMissionControlNamespace(ctx, req.(*CreateMissionControlNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_DeleteMissionControlNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMissionControlNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).DeleteMissionControlNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/DeleteMissionControlNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).DeleteMissionControlNamespace(ctx, req.(*DeleteMissionControlNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListMissionControlNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMissionControlNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListMissionControlNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListMissionControlNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListMissionControlNamespaces(ctx, req.(*ListMissionControlNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Router_ServiceDesc is the grpc.ServiceDesc for Router service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1119,6 +1483,34 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "XDeleteLocalChanAliases",
 			Handler:    _Router_XDeleteLocalChanAliases_Handler,
 		},
+		{
+			MethodName: "QueryReputation",
+			Handler:    _Router_QueryReputation_Handler,
+		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _Router_Rebalance_Handler,
+		},
+		{
+			MethodName: "ListRebalances",
+			Handler:    _Router_ListRebalances_Handler,
+		},
+		{
+			MethodName: "SimulatePayment",
+			Handler:    _Router_SimulatePayment_Handler,
+		},
+		{
+			MethodName: "CreateMissionControlNamespace",
+			Handler:    _Router_CreateMissionControlNamespace_Handler,
+		},
+		{
+			MethodName: "DeleteMissionControlNamespace",
+			Handler:    _Router_DeleteMissionControlNamespace_Handler,
+		},
+		{
+			MethodName: "ListMissionControlNamespaces",
+			Handler:    _Router_ListMissionControlNamespaces_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1157,6 +1549,12 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ExternalPathFinder",
+			Handler:       _Router_ExternalPathFinder_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "routerrpc/router.proto",
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -22,6 +23,8 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/rebalance"
+	"github.com/lightningnetwork/lnd/reputation"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/zpay32"
@@ -64,6 +67,11 @@ var (
 	// allowed SCID aliases.
 	ErrNoValidAlias = errors.New("not a valid alias")
 
+	// ErrReputationDisabled is returned when the reputation of channels is
+	// queried while reputation tracking is disabled.
+	ErrReputationDisabled = errors.New("reputation tracking is disabled, " +
+		"set htlcswitch.reputation.enable to enable it")
+
 	// macaroonOps are the set of capabilities that our minted macaroon (if
 	// it doesn't already exist) will have.
 	macaroonOps = []bakery.Op{
@@ -159,6 +167,38 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/QueryReputation": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/ExternalPathFinder": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/Rebalance": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/ListRebalances": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/SimulatePayment": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/CreateMissionControlNamespace": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/DeleteMissionControlNamespace": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/ListMissionControlNamespaces": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -368,6 +408,10 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 			)
 		}
 
+		if errors.Is(err, routing.ErrNamespaceNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+
 		return err
 	}
 
@@ -923,11 +967,15 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 func (s *Server) ResetMissionControl(ctx context.Context,
 	req *ResetMissionControlRequest) (*ResetMissionControlResponse, error) {
 
-	err := s.cfg.RouterBackend.MissionControl.ResetHistory()
+	mc, err := s.missionControl(req.MissionControlNamespace)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := mc.ResetHistory(); err != nil {
+		return nil, err
+	}
+
 	return &ResetMissionControlResponse{}, nil
 }
 
@@ -936,8 +984,13 @@ func (s *Server) GetMissionControlConfig(ctx context.Context,
 	req *GetMissionControlConfigRequest) (*GetMissionControlConfigResponse,
 	error) {
 
+	mc, err := s.missionControl(req.MissionControlNamespace)
+	if err != nil {
+		return nil, err
+	}
+
 	// Query the current mission control config.
-	cfg := s.cfg.RouterBackend.MissionControl.GetConfig()
+	cfg := mc.GetConfig()
 	resp := &GetMissionControlConfigResponse{
 		Config: &MissionControlConfig{
 			MaximumPaymentResults: uint32(cfg.MaxMcHistory),
@@ -993,20 +1046,42 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 	req *SetMissionControlConfigRequest) (*SetMissionControlConfigResponse,
 	error) {
 
+	mc, err := s.missionControl(req.MissionControlNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	mcCfg, err := unmarshallMissionControlConfig(req.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetMissionControlConfigResponse{}, mc.SetConfig(mcCfg)
+}
+
+// unmarshallMissionControlConfig converts an rpc mission control config into
+// the config of the routing package.
+func unmarshallMissionControlConfig(
+	config *MissionControlConfig) (*routing.MissionControlConfig, error) {
+
+	if config == nil {
+		return nil, errors.New("mission control config not set")
+	}
+
 	mcCfg := &routing.MissionControlConfig{
-		MaxMcHistory: int(req.Config.MaximumPaymentResults),
+		MaxMcHistory: int(config.MaximumPaymentResults),
 		MinFailureRelaxInterval: time.Duration(
-			req.Config.MinimumFailureRelaxInterval,
+			config.MinimumFailureRelaxInterval,
 		) * time.Second,
 	}
 
-	switch req.Config.Model {
+	switch config.Model {
 	case MissionControlConfig_APRIORI:
 		var aprioriConfig routing.AprioriConfig
 
 		// Determine the apriori config with backward compatibility
 		// should the api use deprecated fields.
-		switch v := req.Config.EstimatorConfig.(type) {
+		switch v := config.EstimatorConfig.(type) {
 		case *MissionControlConfig_Bimodal:
 			return nil, fmt.Errorf("bimodal config " +
 				"provided, but apriori model requested")
@@ -1025,12 +1100,12 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 		default:
 			aprioriConfig = routing.AprioriConfig{
 				PenaltyHalfLife: time.Duration(
-					int64(req.Config.HalfLifeSeconds),
+					int64(config.HalfLifeSeconds),
 				) * time.Second,
 				AprioriHopProbability: float64(
-					req.Config.HopProbability,
+					config.HopProbability,
 				),
-				AprioriWeight:    float64(req.Config.Weight),
+				AprioriWeight:    float64(config.Weight),
 				CapacityFraction: routing.DefaultCapacityFraction, //nolint:lll
 			}
 		}
@@ -1042,7 +1117,7 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 		mcCfg.Estimator = estimator
 
 	case MissionControlConfig_BIMODAL:
-		cfg, ok := req.Config.
+		cfg, ok := config.
 			EstimatorConfig.(*MissionControlConfig_Bimodal)
 		if !ok {
 			return nil, fmt.Errorf("bimodal estimator requested " +
@@ -1066,29 +1141,36 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 
 	default:
 		return nil, fmt.Errorf("unknown estimator type %v",
-			req.Config.Model)
+			config.Model)
 	}
 
-	return &SetMissionControlConfigResponse{},
-		s.cfg.RouterBackend.MissionControl.SetConfig(mcCfg)
+	return mcCfg, nil
 }
 
 // QueryMissionControl exposes the internal mission control state to callers. It
 // is a development feature.
 func (s *Server) QueryMissionControl(_ context.Context,
-	_ *QueryMissionControlRequest) (*QueryMissionControlResponse, error) {
+	req *QueryMissionControlRequest) (*QueryMissionControlResponse, error) {
+
+	mc, err := s.missionControl(req.MissionControlNamespace)
+	if err != nil {
+		return nil, err
+	}
 
-	snapshot := s.cfg.RouterBackend.MissionControl.GetHistorySnapshot()
+	snapshot := mc.GetHistorySnapshot()
 
 	rpcPairs := make([]*PairHistory, 0, len(snapshot.Pairs))
 	for _, p := range snapshot.Pairs {
 		// Prevent binding to loop variable.
 		pair := p
 
+		history := toRPCPairData(&pair.TimedPairResult)
+		setRPCPairLatency(history, &pair.Latency)
+
 		rpcPair := PairHistory{
 			NodeFrom: pair.Pair.From[:],
 			NodeTo:   pair.Pair.To[:],
-			History:  toRPCPairData(&pair.TimedPairResult),
+			History:  history,
 		}
 
 		rpcPairs = append(rpcPairs, &rpcPair)
@@ -1121,6 +1203,18 @@ func toRPCPairData(data *routing.TimedPairResult) *PairData {
 	return &rpcData
 }
 
+// setRPCPairLatency adds the latency statistics of a pair to its rpc pair
+// data.
+func setRPCPairLatency(rpcData *PairData, latency *routing.PairLatency) {
+	if latency.Samples == 0 {
+		return
+	}
+
+	rpcData.LatencyMs = latency.Latency.Milliseconds()
+	rpcData.LatencySamples = latency.Samples
+	rpcData.LatencyTime = latency.LastUpdate.Unix()
+}
+
 // XImportMissionControl imports the state provided to our internal mission
 // control. Only entries that are fresher than our existing state will be used.
 func (s *Server) XImportMissionControl(_ context.Context,
@@ -1131,6 +1225,11 @@ func (s *Server) XImportMissionControl(_ context.Context,
 		return nil, errors.New("at least one pair required for import")
 	}
 
+	mc, err := s.missionControl(req.MissionControlNamespace)
+	if err != nil {
+		return nil, err
+	}
+
 	snapshot := &routing.MissionControlSnapshot{
 		Pairs: make(
 			[]routing.MissionControlPairSnapshot, len(req.Pairs),
@@ -1146,14 +1245,103 @@ func (s *Server) XImportMissionControl(_ context.Context,
 		snapshot.Pairs[i] = *pairSnapshot
 	}
 
-	err := s.cfg.RouterBackend.MissionControl.ImportHistory(
-		snapshot, req.Force,
+	if err := mc.ImportHistory(snapshot, req.Force); err != nil {
+		return nil, err
+	}
+
+	return &XImportMissionControlResponse{}, nil
+}
+
+// missionControl returns the mission control of the given namespace. An empty
+// namespace refers to the default one.
+func (s *Server) missionControl(namespace string) (MissionControl, error) {
+	mc, err := s.cfg.RouterBackend.namespacedMissionControl(namespace)
+	if errors.Is(err, routing.ErrNamespaceNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return mc, err
+}
+
+// CreateMissionControlNamespace creates a new mission control namespace.
+func (s *Server) CreateMissionControlNamespace(_ context.Context,
+	req *CreateMissionControlNamespaceRequest) (
+	*CreateMissionControlNamespaceResponse, error) {
+
+	if s.cfg.RouterBackend.CreateMissionControlNamespace == nil {
+		return nil, status.Error(codes.Unimplemented,
+			"mission control namespaces not supported")
+	}
+
+	if req.Namespace == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"namespace must be set")
+	}
+
+	// Without a config, the namespace inherits the config of the default
+	// namespace.
+	var mcCfg *routing.MissionControlConfig
+	if req.Config != nil {
+		var err error
+		mcCfg, err = unmarshallMissionControlConfig(req.Config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.cfg.RouterBackend.CreateMissionControlNamespace(
+		req.Namespace, mcCfg,
 	)
+	if errors.Is(err, routing.ErrNamespaceExists) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &XImportMissionControlResponse{}, nil
+	return &CreateMissionControlNamespaceResponse{}, nil
+}
+
+// DeleteMissionControlNamespace deletes a mission control namespace along with
+// its history and config.
+func (s *Server) DeleteMissionControlNamespace(_ context.Context,
+	req *DeleteMissionControlNamespaceRequest) (
+	*DeleteMissionControlNamespaceResponse, error) {
+
+	if s.cfg.RouterBackend.DeleteMissionControlNamespace == nil {
+		return nil, status.Error(codes.Unimplemented,
+			"mission control namespaces not supported")
+	}
+
+	err := s.cfg.RouterBackend.DeleteMissionControlNamespace(req.Namespace)
+	switch {
+	case errors.Is(err, routing.ErrNamespaceNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, routing.ErrDefaultNamespace):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &DeleteMissionControlNamespaceResponse{}, nil
+}
+
+// ListMissionControlNamespaces lists the existing mission control namespaces.
+func (s *Server) ListMissionControlNamespaces(_ context.Context,
+	_ *ListMissionControlNamespacesRequest) (
+	*ListMissionControlNamespacesResponse, error) {
+
+	namespaces := []string{routing.DefaultMissionControlNamespace}
+	if s.cfg.RouterBackend.ListMissionControlNamespaces != nil {
+		namespaces = s.cfg.RouterBackend.ListMissionControlNamespaces()
+	}
+	sort.Strings(namespaces)
+
+	return &ListMissionControlNamespacesResponse{
+		Namespaces: namespaces,
+	}, nil
 }
 
 func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapshot,
@@ -1198,9 +1386,15 @@ func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapsho
 			err)
 	}
 
-	if successAmt == 0 && failAmt == 0 {
+	latency, err := getPairLatency(pairResult.History)
+	if err != nil {
+		return nil, fmt.Errorf("%v invalid latency: %w", pairPrefix,
+			err)
+	}
+
+	if successAmt == 0 && failAmt == 0 && latency.Samples == 0 {
 		return nil, fmt.Errorf("%v: either success or failure result "+
-			"required", pairPrefix)
+			"or latency required", pairPrefix)
 	}
 
 	pair := routing.NewDirectedNodePair(from, to)
@@ -1215,6 +1409,33 @@ func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapsho
 	return &routing.MissionControlPairSnapshot{
 		Pair:            pair,
 		TimedPairResult: *result,
+		Latency:         *latency,
+	}, nil
+}
+
+// getPairLatency validates the latency statistics provided for a mission
+// control pair.
+func getPairLatency(data *PairData) (*routing.PairLatency, error) {
+	if data.LatencySamples == 0 {
+		if data.LatencyMs != 0 || data.LatencyTime != 0 {
+			return nil, errors.New("latency set without samples")
+		}
+
+		return &routing.PairLatency{}, nil
+	}
+
+	if data.LatencyMs < 0 {
+		return nil, errors.New("latency must be non-negative")
+	}
+
+	if data.LatencyTime <= 0 {
+		return nil, errors.New("latency time must be set")
+	}
+
+	return &routing.PairLatency{
+		Latency:    time.Duration(data.LatencyMs) * time.Millisecond,
+		Samples:    data.LatencySamples,
+		LastUpdate: time.Unix(data.LatencyTime, 0),
 	}, nil
 }
 
@@ -1579,6 +1800,212 @@ func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	).run()
 }
 
+// ExternalPathFinder is a bidirectional stream in which an external process
+// takes part in the path finding of our payments. The first message of the
+// client registers it as probability estimator or route provider. Only one
+// client can be registered at a time.
+func (s *Server) ExternalPathFinder(
+	stream Router_ExternalPathFinderServer) error {
+
+	if s.cfg.RouterBackend.ExternalPathFinder == nil {
+		return status.Error(codes.Unimplemented, "external path "+
+			"finding not available")
+	}
+
+	return newExternalPathFinder(stream).run(
+		s.cfg.RouterBackend.ExternalPathFinder,
+	)
+}
+
+// Rebalance moves liquidity from one of our channels to another by paying
+// ourselves over a circular route.
+func (s *Server) Rebalance(_ context.Context,
+	req *RebalanceRequest) (*RebalanceResponse, error) {
+
+	rebalancer := s.cfg.RouterBackend.Rebalancer
+	if rebalancer == nil {
+		return nil, status.Error(codes.Unimplemented, "rebalancing "+
+			"not available")
+	}
+
+	if req.AmtSat <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must "+
+			"be positive")
+	}
+
+	record, err := rebalancer.Rebalance(&rebalance.Request{
+		OutgoingChanID: req.OutgoingChanId,
+		IncomingChanID: req.IncomingChanId,
+		Amount: lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(req.AmtSat),
+		),
+		MaxFeePPM: req.MaxFeePpm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RebalanceResponse{
+		Rebalance: marshallRebalanceRecord(record),
+	}, nil
+}
+
+// ListRebalances returns the history of rebalances.
+func (s *Server) ListRebalances(_ context.Context,
+	_ *ListRebalancesRequest) (*ListRebalancesResponse, error) {
+
+	rebalancer := s.cfg.RouterBackend.Rebalancer
+	if rebalancer == nil {
+		return nil, status.Error(codes.Unimplemented, "rebalancing "+
+			"not available")
+	}
+
+	records, err := rebalancer.History()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListRebalancesResponse{
+		Rebalances: make([]*RebalanceRecord, 0, len(records)),
+	}
+	for _, record := range records {
+		resp.Rebalances = append(
+			resp.Rebalances, marshallRebalanceRecord(record),
+		)
+
+		if record.Succeeded {
+			resp.TotalFeeMsat += uint64(record.Fee)
+			resp.TotalAmtMsat += uint64(record.Amount)
+		}
+	}
+
+	return resp, nil
+}
+
+// marshallRebalanceRecord converts a rebalance record to its rpc
+// representation.
+func marshallRebalanceRecord(record *rebalance.Record) *RebalanceRecord {
+	return &RebalanceRecord{
+		TimeNs:         record.Time.UnixNano(),
+		OutgoingChanId: record.OutgoingChanID,
+		IncomingChanId: record.IncomingChanID,
+		AmtMsat:        uint64(record.Amount),
+		FeeMsat:        uint64(record.Fee),
+		PaymentHash:    record.PaymentHash[:],
+		Succeeded:      record.Succeeded,
+		Autonomous:     record.Autonomous,
+	}
+}
+
+// SimulatePayment runs the path finding of a payment against the local graph
+// and mission control without sending anything.
+func (s *Server) SimulatePayment(_ context.Context,
+	req *SendPaymentRequest) (*SimulatePaymentResponse, error) {
+
+	// Nothing is sent, so the payment timeout is irrelevant. It is only
+	// set to pass the validation of the request.
+	if req.TimeoutSeconds == 0 {
+		req.TimeoutSeconds = 1
+	}
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := s.cfg.Router.SimulatePayment(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	failureReason, err := marshallPaymentFailureReason(sim.FailureReason)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SimulatePaymentResponse{
+		TotalFeesMsat: int64(sim.TotalFees),
+		TotalTimeLock: sim.TotalTimeLock,
+		SuccessProb:   sim.SuccessProbability,
+		FailureReason: failureReason,
+	}
+
+	for _, shard := range sim.Shards {
+		rpcRoute, err := s.cfg.RouterBackend.MarshallRoute(shard.Route)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Shards = append(resp.Shards, &SimulatedShard{
+			Route:       rpcRoute,
+			Probability: shard.Probability,
+		})
+	}
+
+	for _, edge := range sim.RejectedEdges {
+		reason, err := marshallEdgeRejectReason(edge.Reason)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.RejectedEdges = append(resp.RejectedEdges, &RejectedEdge{
+			FromNode: edge.FromNode[:],
+			ToNode:   edge.ToNode[:],
+			Reason:   reason,
+			AmtMsat:  int64(edge.Amount),
+			Count:    edge.Count,
+		})
+	}
+
+	for rejectReason, count := range sim.RejectionCounts {
+		reason, err := marshallEdgeRejectReason(rejectReason)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.RejectionCounts = append(
+			resp.RejectionCounts, &EdgeRejectionCount{
+				Reason: reason,
+				Count:  count,
+			},
+		)
+	}
+	sort.Slice(resp.RejectionCounts, func(i, j int) bool {
+		return resp.RejectionCounts[i].Reason <
+			resp.RejectionCounts[j].Reason
+	})
+
+	return resp, nil
+}
+
+// marshallEdgeRejectReason converts the reason why path finding discarded an
+// edge to its rpc representation.
+func marshallEdgeRejectReason(reason routing.EdgeRejectReason) (
+	EdgeRejectReason, error) {
+
+	switch reason {
+	case routing.EdgeRejectNoCapacity:
+		return EdgeRejectReason_NO_CAPACITY, nil
+
+	case routing.EdgeRejectFeeLimit:
+		return EdgeRejectReason_FEE_LIMIT, nil
+
+	case routing.EdgeRejectZeroProbability:
+		return EdgeRejectReason_ZERO_PROBABILITY, nil
+
+	case routing.EdgeRejectCltvLimit:
+		return EdgeRejectReason_CLTV_LIMIT, nil
+
+	case routing.EdgeRejectMinProbability:
+		return EdgeRejectReason_MIN_PROBABILITY, nil
+
+	case routing.EdgeRejectPayloadSize:
+		return EdgeRejectReason_PAYLOAD_SIZE, nil
+	}
+
+	return 0, fmt.Errorf("unknown edge reject reason %v", reason)
+}
+
 // XAddLocalChanAliases is an experimental API that creates a set of new
 // channel SCID alias mappings. The final total set of aliases in the manager
 // after the add operation is returned. This is only a locally stored alias, and
@@ -1738,3 +2165,59 @@ func (s *Server) UpdateChanStatus(_ context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// QueryReputation returns the reputation and resource usage of a single
+// channel, or of all channels that htlcs were forwarded on.
+func (s *Server) QueryReputation(_ context.Context,
+	req *QueryReputationRequest) (*QueryReputationResponse, error) {
+
+	manager := s.cfg.RouterBackend.Reputation
+	if manager == nil {
+		return nil, ErrReputationDisabled
+	}
+
+	var reports []*reputation.ChannelReport
+	if req.ChanId != 0 {
+		report, err := manager.ChannelReport(
+			lnwire.NewShortChanIDFromInt(req.ChanId),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	} else {
+		reports = manager.ChannelReports()
+	}
+
+	resp := &QueryReputationResponse{
+		Channels: make([]*ChannelReputation, 0, len(reports)),
+	}
+	for _, report := range reports {
+		resp.Channels = append(resp.Channels, &ChannelReputation{
+			ChanId:           report.ChannelID.ToUint64(),
+			ReputationMsat:   report.ReputationMsat,
+			RevenueMsat:      report.RevenueMsat,
+			InFlightRiskMsat: uint64(report.InFlightRisk),
+			GeneralBucket: marshallResourceBucket(
+				report.General,
+			),
+			ProtectedBucket: marshallResourceBucket(
+				report.Protected,
+			),
+		})
+	}
+
+	return resp, nil
+}
+
+// marshallResourceBucket converts the usage of a resource bucket into its rpc
+// representation.
+func marshallResourceBucket(bucket reputation.BucketReport) *ResourceBucket {
+	return &ResourceBucket{
+		SlotsUsed:         uint32(bucket.SlotsUsed),
+		Slots:             uint32(bucket.Slots),
+		LiquidityUsedMsat: uint64(bucket.LiquidityUsed),
+		LiquidityMsat:     uint64(bucket.Liquidity),
+	}
+}
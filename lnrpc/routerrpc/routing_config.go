@@ -28,6 +28,11 @@ type RoutingConfig struct {
 	// expressed in parts per million of the total payment amount.
 	AttemptCostPPM int64 `long:"attemptcostppm" description:"The proportional (virtual) cost in sats of a failed payment attempt expressed in parts per million of the total payment amount"`
 
+	// LatencyCost is the virtual cost in path finding of one second of
+	// expected route latency. It is used to trade off cheaper routes
+	// against faster ones.
+	LatencyCost btcutil.Amount `long:"latencycost" description:"The (virtual) cost in sats of one second of expected route latency, as measured by mission control. Zero disables latency-aware path finding"`
+
 	// MaxMcHistory defines the maximum number of payment results that
 	// are held on disk by mission control.
 	MaxMcHistory int `long:"maxmchistory" description:"the maximum number of payment results that are held on disk by mission control"`
@@ -44,6 +49,100 @@ type RoutingConfig struct {
 
 	// FeeEstimationTimeout is the maximum time to wait for routing fees to be estimated.
 	FeeEstimationTimeout time.Duration `long:"fee-estimation-timeout" description:"the maximum time to wait for routing fees to be estimated by payment probes"`
+
+	// TrampolineConfig defines the fee and CLTV budgets of trampoline
+	// hops.
+	TrampolineConfig *TrampolineConfig `group:"trampoline" namespace:"trampoline" description:"configuration for trampoline payments and forwarding"`
+
+	// ProberConfig defines the targets and budgets of the background
+	// liquidity prober.
+	ProberConfig *ProberConfig `group:"prober" namespace:"prober" description:"configuration for the background liquidity prober"`
+
+	// RebalancerConfig defines the budgets and targets of the rebalancer.
+	RebalancerConfig *RebalancerConfig `group:"rebalancer" namespace:"rebalancer" description:"configuration for circular rebalancing"`
+}
+
+// RebalancerConfig defines the fee budget of rebalances and the target
+// balances of the autonomous rebalancer.
+//
+//nolint:lll
+type RebalancerConfig struct {
+	// Interval is the time between two rebalances of the autonomous
+	// rebalancer.
+	Interval time.Duration `long:"interval" description:"The time between two rebalances of the autonomous rebalancer"`
+
+	// FeeRatio is the fraction of the outbound fee rate of the incoming
+	// channel that a rebalance may spend on fees.
+	FeeRatio float64 `long:"fee-ratio" description:"The fraction of the outbound fee rate of the incoming channel that a rebalance may spend on fees, if the request doesn't set a fee limit"`
+
+	// MaxAmount is the maximum amount of a rebalance of the autonomous
+	// rebalancer.
+	MaxAmount btcutil.Amount `long:"max-amt" description:"The maximum amount in satoshis of a rebalance of the autonomous rebalancer"`
+
+	// PaymentTimeout is the time spent trying to complete a rebalance.
+	PaymentTimeout time.Duration `long:"payment-timeout" description:"The time spent trying to complete a rebalance"`
+
+	// Targets are the target balances of the channels that the
+	// autonomous rebalancer manages.
+	Targets []string `long:"target" description:"The target ratio of local balance to capacity of a channel in the form scid:ratio, e.g. 123456789:0.5. Can be specified multiple times. If none are set, the autonomous rebalancer doesn't run"`
+}
+
+// ProberConfig defines the targets and budgets of the background liquidity
+// prober, which keeps the liquidity information of mission control up to
+// date.
+//
+//nolint:lll
+type ProberConfig struct {
+	// Enable enables the prober.
+	Enable bool `long:"enable" description:"If true, probes are periodically sent to the configured nodes and channels and to the top destinations of our payments"`
+
+	// Namespace is the mission control namespace that the probe results
+	// are recorded in.
+	Namespace string `long:"namespace" description:"The mission control namespace that probe results are recorded in. Use the namespace 'default' to let regular payments use the results directly"`
+
+	// Interval is the time between two probes.
+	Interval time.Duration `long:"interval" description:"The time between two probes, which limits the rate of probing"`
+
+	// Amount is the amount of probes to the configured nodes and
+	// channels.
+	Amount btcutil.Amount `long:"amt" description:"The amount in satoshis of probes to the configured nodes and channels"`
+
+	// MaxInFlight is the maximum total amount of the probes that are in
+	// flight at the same time.
+	MaxInFlight btcutil.Amount `long:"max-in-flight" description:"The maximum total amount in satoshis of the probes that are in flight at the same time"`
+
+	// TopDestinations is the number of the most frequent destinations of
+	// recent payments that are probed.
+	TopDestinations int `long:"top-destinations" description:"The number of the most frequent destinations of recent payments that are probed with the average amount paid to them"`
+
+	// Nodes are the public keys of the nodes that are probed.
+	Nodes []string `long:"node" description:"The hex-encoded public key of a node to probe. Can be specified multiple times"`
+
+	// Channels are the short channel IDs of the channels that are probed
+	// in both directions.
+	Channels []uint64 `long:"channel" description:"The short channel ID of a channel to probe in both directions. Can be specified multiple times"`
+}
+
+// TrampolineConfig defines the fee and CLTV budget of trampoline hops. The
+// budget is left to the trampoline node when sending a trampoline payment,
+// and required from the sender when forwarding one.
+//
+//nolint:lll
+type TrampolineConfig struct {
+	// Forward enables forwarding trampoline payments.
+	Forward bool `long:"forward" description:"If true, trampoline payments are forwarded and trampoline support is signaled to the network"`
+
+	// FeeBaseMsat is the fixed part of the fee budget of a trampoline
+	// hop.
+	FeeBaseMsat uint64 `long:"fee-base-msat" description:"The fixed part of the fee budget of a trampoline hop in msat"`
+
+	// FeeRatePPM is the proportional part of the fee budget of a
+	// trampoline hop, expressed in parts per million of the forwarded
+	// amount.
+	FeeRatePPM uint64 `long:"fee-rate-ppm" description:"The proportional part of the fee budget of a trampoline hop in parts per million of the forwarded amount"`
+
+	// CltvDelta is the CLTV budget of a trampoline hop.
+	CltvDelta uint16 `long:"cltv-delta" description:"The CLTV budget of a trampoline hop in blocks"`
 }
 
 // AprioriConfig defines parameters for the apriori probability.
@@ -236,6 +236,7 @@ var (
 		input.TaprootHtlcAcceptedRemoteSuccess:             WitnessType_TAPROOT_HTLC_ACCEPTED_REMOTE_SUCCESS,
 		input.TaprootHtlcAcceptedLocalSuccess:              WitnessType_TAPROOT_HTLC_ACCEPTED_LOCAL_SUCCESS,
 		input.TaprootCommitmentRevoke:                      WitnessType_TAPROOT_COMMITMENT_REVOKE,
+		input.PayToAnchorSpend:                             WitnessType_PAY_TO_ANCHOR_SPEND,
 	}
 )
 
@@ -850,11 +851,29 @@ func (w *WalletKit) EstimateFee(ctx context.Context,
 			"than 1")
 	}
 
-	satPerKw, err := w.cfg.FeeEstimator.EstimateFeePerKW(
-		uint32(req.ConfTarget),
-	)
-	if err != nil {
-		return nil, err
+	// If the estimates of several sources are combined, we'll also
+	// report the estimate of each source.
+	var sourceEstimates []*FeeSourceEstimate
+	var satPerKw chainfee.SatPerKWeight
+	var err error
+	aggregator, ok := w.cfg.FeeEstimator.(*chainfee.AggregatingEstimator)
+	if ok {
+		var estimates []chainfee.SourceEstimate
+		satPerKw, estimates, err = aggregator.EstimateFeeSources(
+			uint32(req.ConfTarget),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sourceEstimates = marshallSourceEstimates(estimates)
+	} else {
+		satPerKw, err = w.cfg.FeeEstimator.EstimateFeePerKW(
+			uint32(req.ConfTarget),
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	relayFeePerKw := w.cfg.FeeEstimator.RelayFeePerKW()
@@ -862,9 +881,32 @@ func (w *WalletKit) EstimateFee(ctx context.Context,
 	return &EstimateFeeResponse{
 		SatPerKw:            int64(satPerKw),
 		MinRelayFeeSatPerKw: int64(relayFeePerKw),
+		SourceEstimates:     sourceEstimates,
 	}, nil
 }
 
+// marshallSourceEstimates converts the estimates of the fee sources of an
+// aggregating fee estimator into their RPC representation.
+func marshallSourceEstimates(
+	estimates []chainfee.SourceEstimate) []*FeeSourceEstimate {
+
+	rpcEstimates := make([]*FeeSourceEstimate, 0, len(estimates))
+	for _, estimate := range estimates {
+		rpcEstimate := &FeeSourceEstimate{
+			Source:   estimate.Source,
+			SatPerKw: int64(estimate.FeeRate),
+			Outlier:  estimate.Outlier,
+		}
+		if estimate.Err != nil {
+			rpcEstimate.Error = estimate.Err.Error()
+		}
+
+		rpcEstimates = append(rpcEstimates, rpcEstimate)
+	}
+
+	return rpcEstimates
+}
+
 // PendingSweeps returns lists of on-chain outputs that lnd is currently
 // attempting to sweep within its central batching engine. Outputs with similar
 // fee rates are batched together in order to sweep them within a single
@@ -1236,7 +1278,8 @@ func (w *WalletKit) BumpForceCloseFee(_ context.Context,
 		// inputs which can be used to bump a closed unconfirmed
 		// commitment transaction.
 		if sweep.WitnessType != input.CommitmentAnchor &&
-			sweep.WitnessType != input.TaprootAnchorSweepSpend {
+			sweep.WitnessType != input.TaprootAnchorSweepSpend &&
+			sweep.WitnessType != input.PayToAnchorSpend {
 
 			return false
 		}
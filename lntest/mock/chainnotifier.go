@@ -11,6 +11,10 @@ type ChainNotifier struct {
 	SpendChan chan *chainntnfs.SpendDetail
 	EpochChan chan *chainntnfs.BlockEpoch
 	ConfChan  chan *chainntnfs.TxConfirmation
+
+	// SpendReorgChan is used as the Reorg channel of all spend events, if
+	// set.
+	SpendReorgChan chan struct{}
 }
 
 // RegisterConfirmationsNtfn returns a ConfirmationEvent that contains a channel
@@ -32,6 +36,7 @@ func (c *ChainNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
 
 	return &chainntnfs.SpendEvent{
 		Spend:  c.SpendChan,
+		Reorg:  c.SpendReorgChan,
 		Cancel: func() {},
 	}, nil
 }
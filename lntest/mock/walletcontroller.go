@@ -32,6 +32,9 @@ type WalletController struct {
 	PublishedTransactions chan *wire.MsgTx
 	index                 uint32
 	Utxos                 []*lnwallet.Utxo
+
+	// Txs are the transactions returned by FetchTx.
+	Txs map[chainhash.Hash]*wire.MsgTx
 }
 
 // A compile time check to ensure this mocked WalletController implements the
@@ -280,8 +283,9 @@ func (w *WalletController) Stop() error {
 	return nil
 }
 
-func (w *WalletController) FetchTx(chainhash.Hash) (*wire.MsgTx, error) {
-	return nil, nil
+// FetchTx returns the transaction with the given hash from Txs, if any.
+func (w *WalletController) FetchTx(txid chainhash.Hash) (*wire.MsgTx, error) {
+	return w.Txs[txid], nil
 }
 
 func (w *WalletController) RemoveDescendants(*wire.MsgTx) error {
@@ -292,6 +296,13 @@ func (w *WalletController) CheckMempoolAcceptance(tx *wire.MsgTx) error {
 	return nil
 }
 
+// SubmitPackage submits a package of a parent and a child transaction.
+func (w *WalletController) SubmitPackage(parent, child *wire.MsgTx,
+	_ string) error {
+
+	return lnwallet.ErrPackageRelayUnsupported
+}
+
 // FetchDerivationInfo queries for the wallet's knowledge of the passed
 // pkScript and constructs the derivation info and returns it.
 func (w *WalletController) FetchDerivationInfo(
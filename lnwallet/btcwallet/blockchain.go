@@ -11,6 +11,8 @@ import (
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightninglabs/neutrino/headerfs"
+	"github.com/lightningnetwork/lnd/chainfailover"
+	"github.com/lightningnetwork/lnd/esplora"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 )
@@ -40,7 +42,15 @@ func (b *BtcWallet) GetBestBlock() (*chainhash.Hash, int32, error) {
 func (b *BtcWallet) GetUtxo(op *wire.OutPoint, pkScript []byte,
 	heightHint uint32, cancel <-chan struct{}) (*wire.TxOut, error) {
 
-	switch backend := b.chain.(type) {
+	return getUtxo(b.chain, op, pkScript, heightHint, cancel)
+}
+
+// getUtxo returns the original output referenced by the passed outpoint using
+// the given chain backend.
+func getUtxo(chainSource chain.Interface, op *wire.OutPoint, pkScript []byte,
+	heightHint uint32, cancel <-chan struct{}) (*wire.TxOut, error) {
+
+	switch backend := chainSource.(type) {
 
 	case *chain.NeutrinoClient:
 		spendReport, err := backend.CS.GetUtxo(
@@ -122,6 +132,37 @@ func (b *BtcWallet) GetUtxo(op *wire.OutPoint, pkScript []byte,
 			PkScript: pkScript,
 		}, nil
 
+	case *esplora.ChainClient:
+		tx, err := backend.GetRawTransaction(&op.Hash)
+		switch {
+		case errors.Is(err, esplora.ErrNotFound):
+			return nil, ErrOutputNotFound
+
+		case err != nil:
+			return nil, err
+
+		case op.Index >= uint32(len(tx.TxOut)):
+			return nil, ErrOutputNotFound
+		}
+
+		// The server tracks the spends of all outputs, including the
+		// ones of transactions in the mempool.
+		outSpend, err := backend.GetTxOutSpend(op)
+		if err != nil {
+			return nil, err
+		} else if outSpend.Spent {
+			return nil, ErrOutputSpent
+		}
+
+		return tx.TxOut[op.Index], nil
+
+	// With chain failover, the output is looked up using the currently
+	// active backend.
+	case *chainfailover.ChainSource:
+		return getUtxo(
+			backend.Active(), op, pkScript, heightHint, cancel,
+		)
+
 	default:
 		return nil, fmt.Errorf("unknown backend")
 	}
@@ -1251,6 +1251,15 @@ func (b *BtcWallet) PublishTransaction(tx *wire.MsgTx, label string) error {
 			return mapRpcclientError(err)
 		}
 
+		// Some backends, like Esplora, don't offer a mempool
+		// acceptance test at all, so we'll just attempt to publish
+		// the transaction.
+		if errors.Is(err, chain.ErrUnimplemented) {
+			err := b.wallet.PublishTransaction(tx, label)
+
+			return mapRpcclientError(err)
+		}
+
 		return err
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
 var (
@@ -52,6 +53,11 @@ type Config struct {
 	// notifications for received funds, etc.
 	ChainSource chain.Interface
 
+	// MempoolRPC is an optional connection to the RPC interface of a
+	// bitcoind or btcd chain backend. If set, it's used to submit packages
+	// of transactions to the mempool of the backend.
+	MempoolRPC chainfee.MempoolRPC
+
 	// NetParams is the net parameters for the target chain.
 	NetParams *chaincfg.Params
 
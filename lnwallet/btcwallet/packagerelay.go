@@ -0,0 +1,141 @@
+package btcwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// packageAccepted is the package message returned by submitpackage if
+	// all transactions of the package were accepted to the mempool.
+	packageAccepted = "success"
+
+	// regtestOnlyMsg is part of the error message returned by versions of
+	// bitcoind that only allow submitpackage in regtest mode.
+	regtestOnlyMsg = "regression testing"
+)
+
+// submitPackageTxResult is the result of a single transaction of a package
+// in the submitpackage response.
+type submitPackageTxResult struct {
+	TxID  string `json:"txid"`
+	Error string `json:"error"`
+}
+
+// submitPackageResult is the response of the submitpackage RPC.
+type submitPackageResult struct {
+	PackageMsg string                           `json:"package_msg"`
+	TxResults  map[string]submitPackageTxResult `json:"tx-results"`
+}
+
+// SubmitPackage submits an unconfirmed parent transaction together with a
+// child spending it to the mempool of the chain backend using bitcoind's
+// submitpackage RPC, so the child can pay for a parent that doesn't meet the
+// mempool minimum fee on its own. Once accepted, both transactions are also
+// published through the wallet so that they are recorded and labeled like
+// any other transaction. If the chain backend doesn't support package relay,
+// ErrPackageRelayUnsupported is returned.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) SubmitPackage(parent, child *wire.MsgTx,
+	label string) error {
+
+	if b.cfg.MempoolRPC == nil {
+		return lnwallet.ErrPackageRelayUnsupported
+	}
+
+	// The package is passed as an array of raw transactions, with the
+	// parent preceding the child.
+	rawTxns := make([]string, 0, 2)
+	for _, tx := range []*wire.MsgTx{parent, child} {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			return err
+		}
+
+		rawTxns = append(rawTxns, hex.EncodeToString(buf.Bytes()))
+	}
+
+	params, err := json.Marshal(rawTxns)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.cfg.MempoolRPC.RawRequest(
+		"submitpackage", []json.RawMessage{params},
+	)
+
+	// Backends that don't know the call, or only allow it in regtest mode,
+	// don't support package relay.
+	var rpcErr *btcjson.RPCError
+	switch {
+	case errors.As(err, &rpcErr) &&
+		rpcErr.Code == btcjson.ErrRPCMethodNotFound.Code:
+
+		return lnwallet.ErrPackageRelayUnsupported
+
+	case err != nil && strings.Contains(err.Error(), regtestOnlyMsg):
+		return lnwallet.ErrPackageRelayUnsupported
+
+	case err != nil:
+		return fmt.Errorf("unable to submit package: %w", err)
+	}
+
+	var result submitPackageResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+
+	log.Debugf("Submitted package of parent %v and child %v: %v",
+		parent.TxHash(), child.TxHash(), result.PackageMsg)
+
+	if result.PackageMsg != packageAccepted {
+		return b.packageRejection(parent, child, &result)
+	}
+
+	// Now that the package is in the mempool, publishing the individual
+	// transactions only records them in the wallet, as the backend
+	// already knows them.
+	if err := b.wallet.PublishTransaction(parent, ""); err != nil {
+		return mapRpcclientError(err)
+	}
+
+	return mapRpcclientError(b.wallet.PublishTransaction(child, label))
+}
+
+// packageRejection maps the reason a package was rejected for to an error.
+// The rejection reason of the parent takes precedence over the one of the
+// child, as the child can't be accepted without its parent.
+func (b *BtcWallet) packageRejection(parent, child *wire.MsgTx,
+	result *submitPackageResult) error {
+
+	reasons := make(map[string]string, len(result.TxResults))
+	for _, txResult := range result.TxResults {
+		reasons[txResult.TxID] = txResult.Error
+	}
+
+	for _, tx := range []*wire.MsgTx{parent, child} {
+		reason := reasons[tx.TxHash().String()]
+		if reason == "" {
+			continue
+		}
+
+		log.Warnf("Transaction %v of package not accepted by mempool: "+
+			"%v", tx.TxHash(), reason)
+
+		err := b.chain.MapRPCErr(errors.New(reason))
+
+		return fmt.Errorf("package rejection: %w",
+			mapRpcclientError(err))
+	}
+
+	return fmt.Errorf("package rejection: %v", result.PackageMsg)
+}
@@ -0,0 +1,111 @@
+package btcwallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightningnetwork/lnd/lnmock"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMempoolRPC is a chainfee.MempoolRPC that returns a static response.
+type mockMempoolRPC struct {
+	resp string
+	err  error
+}
+
+func (m *mockMempoolRPC) RawRequest(method string,
+	_ []json.RawMessage) (json.RawMessage, error) {
+
+	if method != "submitpackage" {
+		return nil, fmt.Errorf("unexpected method %v", method)
+	}
+
+	return json.RawMessage(m.resp), m.err
+}
+
+// TestSubmitPackageRejected asserts that SubmitPackage reports backends
+// without package relay and maps the reason a package was rejected for.
+func TestSubmitPackageRejected(t *testing.T) {
+	t.Parallel()
+
+	parent := &wire.MsgTx{Version: 3, LockTime: 1}
+	child := &wire.MsgTx{Version: 3, LockTime: 2}
+
+	rejectedResp := fmt.Sprintf(`{
+		"package_msg": "transaction failed",
+		"tx-results": {
+			"aa": {
+				"txid": "%v",
+				"error": "mempool min fee not met"
+			},
+			"bb": {
+				"txid": "%v",
+				"error": "bad-txns-inputs-missingorspent"
+			}
+		}
+	}`, child.TxHash(), parent.TxHash())
+
+	testCases := []struct {
+		name        string
+		rpc         *mockMempoolRPC
+		mapErr      error
+		expectedErr error
+	}{{
+		name:        "no rpc connection",
+		expectedErr: lnwallet.ErrPackageRelayUnsupported,
+	}, {
+		name: "method not found",
+		rpc: &mockMempoolRPC{
+			err: btcjson.ErrRPCMethodNotFound,
+		},
+		expectedErr: lnwallet.ErrPackageRelayUnsupported,
+	}, {
+		name: "regtest only",
+		rpc: &mockMempoolRPC{
+			err: errors.New("-1: submitpackage is for regression " +
+				"testing (-regtest mode) only"),
+		},
+		expectedErr: lnwallet.ErrPackageRelayUnsupported,
+	}, {
+		name: "parent rejection takes precedence",
+		rpc: &mockMempoolRPC{
+			resp: rejectedResp,
+		},
+		mapErr:      chain.ErrMissingInputsOrSpent,
+		expectedErr: chain.ErrMissingInputsOrSpent,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockChain := &lnmock.MockChain{}
+			defer mockChain.AssertExpectations(t)
+
+			cfg := &Config{}
+			if tc.rpc != nil {
+				cfg.MempoolRPC = tc.rpc
+			}
+			wallet := &BtcWallet{
+				chain: mockChain,
+				cfg:   cfg,
+			}
+
+			if tc.mapErr != nil {
+				mockChain.On("MapRPCErr", errors.New(
+					"bad-txns-inputs-missingorspent",
+				)).Return(tc.mapErr).Once()
+			}
+
+			err := wallet.SubmitPackage(parent, child, "")
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
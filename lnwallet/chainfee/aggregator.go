@@ -0,0 +1,429 @@
+package chainfee
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// DefaultMaxFeeDeviation is the default relative deviation from the
+	// median of all sources above which the estimate of a source is
+	// rejected as an outlier. A value of 1 rejects estimates that are
+	// more than twice or less than half the median.
+	DefaultMaxFeeDeviation = 1.0
+
+	// minOutlierSources is the minimum number of estimates needed to tell
+	// outliers apart. With fewer estimates, the median can't be trusted
+	// to be representative.
+	minOutlierSources = 3
+)
+
+var (
+	// ErrNoFeeEstimate is returned if none of the sources of an
+	// AggregatingEstimator provided an estimate.
+	ErrNoFeeEstimate = errors.New("no fee source provided an estimate")
+)
+
+// FeeSource is a named fee estimator whose estimates are combined by an
+// AggregatingEstimator.
+type FeeSource struct {
+	// Name identifies the source in logs and RPC responses.
+	Name string
+
+	// Estimator is the fee estimator of the source.
+	Estimator Estimator
+}
+
+// FeeRateClamp bounds the fee rate estimated for confirmation targets starting
+// at ConfTarget, up to the conf target of the next clamp.
+type FeeRateClamp struct {
+	// ConfTarget is the lowest confirmation target the clamp applies to.
+	ConfTarget uint32
+
+	// MinFeeRate is the lowest fee rate that's returned. Zero means no
+	// lower bound.
+	MinFeeRate SatPerKWeight
+
+	// MaxFeeRate is the highest fee rate that's returned. Zero means no
+	// upper bound.
+	MaxFeeRate SatPerKWeight
+}
+
+// apply bounds the given fee rate by the clamp.
+func (c FeeRateClamp) apply(feeRate SatPerKWeight) SatPerKWeight {
+	if c.MinFeeRate != 0 && feeRate < c.MinFeeRate {
+		return c.MinFeeRate
+	}
+
+	if c.MaxFeeRate != 0 && feeRate > c.MaxFeeRate {
+		return c.MaxFeeRate
+	}
+
+	return feeRate
+}
+
+// SourceEstimate is the estimate of a single source of an
+// AggregatingEstimator.
+type SourceEstimate struct {
+	// Source is the name of the source.
+	Source string
+
+	// FeeRate is the fee rate estimated by the source. It's zero if the
+	// source failed to provide an estimate.
+	FeeRate SatPerKWeight
+
+	// Err is the error of the source, if it failed to provide an
+	// estimate.
+	Err error
+
+	// Outlier indicates whether the estimate was rejected because it
+	// deviates too much from the estimates of the other sources.
+	Outlier bool
+}
+
+// AggregatorConfig houses the configuration of an AggregatingEstimator.
+type AggregatorConfig struct {
+	// Sources are the fee sources whose estimates are combined.
+	Sources []*FeeSource
+
+	// MaxDeviation is the relative deviation from the median of all
+	// estimates above which the estimate of a source is rejected as an
+	// outlier.
+	MaxDeviation float64
+
+	// Clamps bound the combined estimates per confirmation target.
+	Clamps []FeeRateClamp
+}
+
+// AggregatingEstimator is an implementation of the Estimator interface that
+// combines the estimates of several fee sources. Estimates that deviate too
+// much from the median of all sources are rejected as outliers, and the
+// median of the remaining ones is bounded by the clamp of the confirmation
+// target. This way, a single misbehaving source can't push our fee rates to
+// extremes.
+type AggregatingEstimator struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *AggregatorConfig
+
+	// clamps are the configured clamps, sorted by their conf target.
+	clamps []FeeRateClamp
+
+	// startErrs holds the error each source failed to start with, if any.
+	// Sources that failed to start aren't queried.
+	startErrs []error
+}
+
+// NewAggregatingEstimator creates a new AggregatingEstimator from the given
+// configuration.
+func NewAggregatingEstimator(
+	cfg *AggregatorConfig) (*AggregatingEstimator, error) {
+
+	if len(cfg.Sources) == 0 {
+		return nil, errors.New("at least one fee source is required")
+	}
+
+	if cfg.MaxDeviation <= 0 {
+		return nil, fmt.Errorf("max deviation must be positive, got "+
+			"%v", cfg.MaxDeviation)
+	}
+
+	clamps := make([]FeeRateClamp, len(cfg.Clamps))
+	copy(clamps, cfg.Clamps)
+	sort.Slice(clamps, func(i, j int) bool {
+		return clamps[i].ConfTarget < clamps[j].ConfTarget
+	})
+
+	for i, clamp := range clamps {
+		if i > 0 && clamps[i-1].ConfTarget == clamp.ConfTarget {
+			return nil, fmt.Errorf("duplicate clamp for conf "+
+				"target %d", clamp.ConfTarget)
+		}
+
+		maxFeeRate := clamp.MaxFeeRate
+		if maxFeeRate != 0 && clamp.MinFeeRate > maxFeeRate {
+			return nil, fmt.Errorf("min fee rate of %v exceeds "+
+				"max fee rate of %v for conf target %d",
+				clamp.MinFeeRate, clamp.MaxFeeRate,
+				clamp.ConfTarget)
+		}
+	}
+
+	return &AggregatingEstimator{
+		cfg:       cfg,
+		clamps:    clamps,
+		startErrs: make([]error, len(cfg.Sources)),
+	}, nil
+}
+
+// Start signals the Estimator to start any processes or goroutines it needs
+// to perform its duty. Sources that fail to start are left out, unless none
+// of them starts.
+//
+// NOTE: This method is part of the Estimator interface.
+func (a *AggregatingEstimator) Start() error {
+	var err error
+	a.started.Do(func() {
+		log.Infof("Starting aggregating fee estimator with %d sources",
+			len(a.cfg.Sources))
+
+		numStarted := 0
+		for i, source := range a.cfg.Sources {
+			startErr := source.Estimator.Start()
+			if startErr != nil {
+				log.Errorf("Unable to start fee source %v: %v",
+					source.Name, startErr)
+
+				a.startErrs[i] = fmt.Errorf("not started: %w",
+					startErr)
+
+				continue
+			}
+
+			numStarted++
+		}
+
+		if numStarted == 0 {
+			err = errors.Join(a.startErrs...)
+		}
+	})
+
+	return err
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used by the
+// fee estimator.
+//
+// NOTE: This method is part of the Estimator interface.
+func (a *AggregatingEstimator) Stop() error {
+	var err error
+	a.stopped.Do(func() {
+		var errs []error
+		for i, source := range a.cfg.Sources {
+			if a.startErrs[i] != nil {
+				continue
+			}
+
+			if err := source.Estimator.Stop(); err != nil {
+				errs = append(errs, fmt.Errorf("unable to "+
+					"stop fee source %v: %w", source.Name,
+					err))
+			}
+		}
+
+		err = errors.Join(errs...)
+	})
+
+	return err
+}
+
+// EstimateFeePerKW takes in a target for the number of blocks until an initial
+// confirmation and returns the estimated fee expressed in sat/kw.
+//
+// NOTE: This method is part of the Estimator interface.
+func (a *AggregatingEstimator) EstimateFeePerKW(
+	numBlocks uint32) (SatPerKWeight, error) {
+
+	feeRate, _, err := a.EstimateFeeSources(numBlocks)
+
+	return feeRate, err
+}
+
+// EstimateFeeSources returns the combined fee estimate for the given
+// confirmation target, along with the estimates of the individual sources.
+func (a *AggregatingEstimator) EstimateFeeSources(
+	numBlocks uint32) (SatPerKWeight, []SourceEstimate, error) {
+
+	estimates := a.querySources(func(e Estimator) (SatPerKWeight, error) {
+		return e.EstimateFeePerKW(numBlocks)
+	})
+
+	feeRate, ok := combine(estimates, a.cfg.MaxDeviation, median)
+	if !ok {
+		return 0, estimates, fmt.Errorf("%w for conf target %d",
+			ErrNoFeeEstimate, numBlocks)
+	}
+
+	for _, estimate := range estimates {
+		switch {
+		case estimate.Err != nil:
+			log.Warnf("Fee source %v failed to estimate fee rate "+
+				"for conf target %d: %v", estimate.Source,
+				numBlocks, estimate.Err)
+
+		case estimate.Outlier:
+			log.Warnf("Rejecting outlier fee rate of %v from "+
+				"source %v for conf target %d",
+				estimate.FeeRate, estimate.Source, numBlocks)
+		}
+	}
+
+	if clamp, ok := a.clamp(numBlocks); ok {
+		clamped := clamp.apply(feeRate)
+		if clamped != feeRate {
+			log.Debugf("Clamping fee rate of %v to %v for conf "+
+				"target %d", feeRate, clamped, numBlocks)
+		}
+		feeRate = clamped
+	}
+
+	log.Debugf("Aggregated fee rate of %v for conf target %d",
+		feeRate, numBlocks)
+
+	return feeRate, estimates, nil
+}
+
+// RelayFeePerKW returns the minimum fee rate required for transactions to be
+// relayed. This is the highest relay fee rate of the sources that isn't an
+// outlier, such that transactions propagate through all of them.
+//
+// NOTE: This method is part of the Estimator interface.
+func (a *AggregatingEstimator) RelayFeePerKW() SatPerKWeight {
+	estimates := a.querySources(func(e Estimator) (SatPerKWeight, error) {
+		return e.RelayFeePerKW(), nil
+	})
+
+	feeRate, ok := combine(estimates, a.cfg.MaxDeviation, maximum)
+	if !ok || feeRate < FeePerKwFloor {
+		return FeePerKwFloor
+	}
+
+	return feeRate
+}
+
+// querySources queries the started sources concurrently using the given
+// function.
+func (a *AggregatingEstimator) querySources(
+	query func(Estimator) (SatPerKWeight, error)) []SourceEstimate {
+
+	estimates := make([]SourceEstimate, len(a.cfg.Sources))
+
+	var wg sync.WaitGroup
+	for i, source := range a.cfg.Sources {
+		estimates[i] = SourceEstimate{
+			Source: source.Name,
+			Err:    a.startErrs[i],
+		}
+		if estimates[i].Err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			estimates[i].FeeRate, estimates[i].Err = query(
+				source.Estimator,
+			)
+		}()
+	}
+	wg.Wait()
+
+	return estimates
+}
+
+// clamp returns the clamp that applies to the given confirmation target, if
+// any.
+func (a *AggregatingEstimator) clamp(numBlocks uint32) (FeeRateClamp, bool) {
+	idx := sort.Search(len(a.clamps), func(i int) bool {
+		return a.clamps[i].ConfTarget > numBlocks
+	})
+	if idx == 0 {
+		return FeeRateClamp{}, false
+	}
+
+	return a.clamps[idx-1], true
+}
+
+// combine marks the outliers among the given estimates and combines the
+// remaining ones using the given function. It returns false if none of the
+// sources provided an estimate.
+func combine(estimates []SourceEstimate, maxDeviation float64,
+	combineFn func([]SatPerKWeight) SatPerKWeight) (SatPerKWeight, bool) {
+
+	var feeRates []SatPerKWeight
+	for _, estimate := range estimates {
+		if estimate.Err == nil {
+			feeRates = append(feeRates, estimate.FeeRate)
+		}
+	}
+
+	if len(feeRates) == 0 {
+		return 0, false
+	}
+
+	if len(feeRates) < minOutlierSources {
+		return combineFn(feeRates), true
+	}
+
+	// An estimate is an outlier if it's off from the median by more than
+	// the max deviation in either direction. We compare ratios, such that
+	// an estimate that's ten times too low is as far off as one that's
+	// ten times too high.
+	mid := float64(median(feeRates))
+	maxRatio := 1 + maxDeviation
+
+	var inliers []SatPerKWeight
+	for i := range estimates {
+		estimate := &estimates[i]
+		if estimate.Err != nil {
+			continue
+		}
+
+		feeRate := float64(estimate.FeeRate)
+		if feeRate*maxRatio < mid || feeRate > mid*maxRatio {
+			estimate.Outlier = true
+			continue
+		}
+
+		inliers = append(inliers, estimate.FeeRate)
+	}
+
+	// If the sources are scattered such that all of them are off, we
+	// can't tell which ones are right, so we fall back to the median.
+	if len(inliers) == 0 {
+		for i := range estimates {
+			estimates[i].Outlier = false
+		}
+
+		return SatPerKWeight(mid), true
+	}
+
+	return combineFn(inliers), true
+}
+
+// median returns the median of the given fee rates. For an even number of fee
+// rates, it's the mean of the two middle ones.
+func median(feeRates []SatPerKWeight) SatPerKWeight {
+	sorted := make([]SatPerKWeight, len(feeRates))
+	copy(sorted, feeRates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
+
+// maximum returns the highest of the given fee rates.
+func maximum(feeRates []SatPerKWeight) SatPerKWeight {
+	var highest SatPerKWeight
+	for _, feeRate := range feeRates {
+		if feeRate > highest {
+			highest = feeRate
+		}
+	}
+
+	return highest
+}
+
+// A compile-time assertion to ensure that AggregatingEstimator implements the
+// Estimator interface.
+var _ Estimator = (*AggregatingEstimator)(nil)
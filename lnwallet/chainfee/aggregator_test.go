@@ -0,0 +1,289 @@
+package chainfee
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// staticSource returns a fee source with a static estimate.
+func staticSource(name string, feeRate, relayFee SatPerKWeight) *FeeSource {
+	return &FeeSource{
+		Name:      name,
+		Estimator: NewStaticEstimator(feeRate, relayFee),
+	}
+}
+
+// TestAggregatingEstimator checks that the estimates of the sources are
+// combined, with outliers and failing sources left out.
+func TestAggregatingEstimator(t *testing.T) {
+	t.Parallel()
+
+	errSource := errors.New("source down")
+
+	testCases := []struct {
+		name          string
+		sources       []*FeeSource
+		maxDeviation  float64
+		expectedRate  SatPerKWeight
+		outliers      []bool
+		expectedRelay SatPerKWeight
+		err           error
+	}{{
+		name: "single source",
+		sources: []*FeeSource{
+			staticSource("a", 1000, 300),
+		},
+		expectedRate:  1000,
+		outliers:      []bool{false},
+		expectedRelay: 300,
+	}, {
+		name: "two sources use the mean",
+		sources: []*FeeSource{
+			staticSource("a", 1000, 300),
+			staticSource("b", 5000, 253),
+		},
+		expectedRate:  3000,
+		outliers:      []bool{false, false},
+		expectedRelay: 300,
+	}, {
+		name: "high outlier rejected",
+		sources: []*FeeSource{
+			staticSource("a", 1000, 253),
+			staticSource("b", 1200, 253),
+			staticSource("c", 100_000, 253),
+		},
+		expectedRate:  1100,
+		outliers:      []bool{false, false, true},
+		expectedRelay: 253,
+	}, {
+		name: "low outlier rejected",
+		sources: []*FeeSource{
+			staticSource("a", 253, 253),
+			staticSource("b", 2000, 253),
+			staticSource("c", 2400, 253),
+			staticSource("d", 2200, 253),
+		},
+		expectedRate:  2200,
+		outliers:      []bool{true, false, false, false},
+		expectedRelay: 253,
+	}, {
+		name: "relay fee outlier rejected",
+		sources: []*FeeSource{
+			staticSource("a", 1000, 253),
+			staticSource("b", 1000, 300),
+			staticSource("c", 1000, 50_000),
+		},
+		expectedRate:  1000,
+		outliers:      []bool{false, false, false},
+		expectedRelay: 300,
+	}, {
+		name: "split sources",
+		sources: []*FeeSource{
+			staticSource("a", 1000, 253),
+			staticSource("b", 1000, 253),
+			staticSource("c", 10_000, 253),
+			staticSource("d", 10_000, 253),
+		},
+		expectedRate:  10_000,
+		outliers:      []bool{true, true, false, false},
+		expectedRelay: 253,
+	}, {
+		name: "scattered sources use the median",
+		sources: []*FeeSource{
+			staticSource("a", 1000, 253),
+			staticSource("b", 1000, 253),
+			staticSource("c", 10_000, 253),
+			staticSource("d", 10_000, 253),
+		},
+		maxDeviation:  0.5,
+		expectedRate:  5500,
+		outliers:      []bool{false, false, false, false},
+		expectedRelay: 253,
+	}, {
+		name: "all sources failing",
+		sources: func() []*FeeSource {
+			estimator := &MockEstimator{}
+			estimator.On("Start").Return(nil)
+			estimator.On("EstimateFeePerKW", mock.Anything).Return(
+				nil, errSource,
+			)
+			estimator.On("RelayFeePerKW").Return(SatPerKWeight(0))
+
+			return []*FeeSource{{Name: "a", Estimator: estimator}}
+		}(),
+		outliers:      []bool{false},
+		expectedRelay: FeePerKwFloor,
+		err:           ErrNoFeeEstimate,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			maxDeviation := tc.maxDeviation
+			if maxDeviation == 0 {
+				maxDeviation = DefaultMaxFeeDeviation
+			}
+
+			estimator, err := NewAggregatingEstimator(
+				&AggregatorConfig{
+					Sources:      tc.sources,
+					MaxDeviation: maxDeviation,
+				},
+			)
+			require.NoError(t, err)
+			require.NoError(t, estimator.Start())
+
+			feeRate, estimates, err := estimator.EstimateFeeSources(
+				6,
+			)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.expectedRate, feeRate)
+
+			require.Len(t, estimates, len(tc.sources))
+			for i, estimate := range estimates {
+				require.Equal(t, tc.sources[i].Name,
+					estimate.Source)
+				require.Equal(t, tc.outliers[i],
+					estimate.Outlier)
+			}
+
+			require.Equal(t, tc.expectedRelay,
+				estimator.RelayFeePerKW())
+		})
+	}
+}
+
+// TestAggregatingEstimatorFailingSource checks that a source that fails to
+// start or to estimate is left out.
+func TestAggregatingEstimatorFailingSource(t *testing.T) {
+	t.Parallel()
+
+	errSource := errors.New("source down")
+
+	notStarted := &MockEstimator{}
+	notStarted.On("Start").Return(errSource)
+
+	failing := &MockEstimator{}
+	failing.On("Start").Return(nil)
+	failing.On("Stop").Return(nil)
+	failing.On("EstimateFeePerKW", uint32(6)).Return(nil, errSource)
+
+	estimator, err := NewAggregatingEstimator(&AggregatorConfig{
+		Sources: []*FeeSource{
+			{Name: "not started", Estimator: notStarted},
+			{Name: "failing", Estimator: failing},
+			staticSource("static", 1000, 253),
+		},
+		MaxDeviation: DefaultMaxFeeDeviation,
+	})
+	require.NoError(t, err)
+	require.NoError(t, estimator.Start())
+
+	feeRate, estimates, err := estimator.EstimateFeeSources(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, feeRate)
+
+	require.ErrorIs(t, estimates[0].Err, errSource)
+	require.ErrorIs(t, estimates[1].Err, errSource)
+	require.NoError(t, estimates[2].Err)
+
+	// The source that failed to start isn't stopped.
+	require.NoError(t, estimator.Stop())
+	notStarted.AssertExpectations(t)
+	failing.AssertExpectations(t)
+
+	// If no source starts, the estimator fails to start.
+	estimator, err = NewAggregatingEstimator(&AggregatorConfig{
+		Sources: []*FeeSource{
+			{Name: "not started", Estimator: notStarted},
+		},
+		MaxDeviation: DefaultMaxFeeDeviation,
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, estimator.Start(), errSource)
+}
+
+// TestAggregatingEstimatorClamps checks that the combined estimate is bounded
+// by the clamp of the confirmation target.
+func TestAggregatingEstimatorClamps(t *testing.T) {
+	t.Parallel()
+
+	estimator, err := NewAggregatingEstimator(&AggregatorConfig{
+		Sources: []*FeeSource{
+			staticSource("a", 5000, 253),
+		},
+		MaxDeviation: DefaultMaxFeeDeviation,
+		Clamps: []FeeRateClamp{{
+			ConfTarget: 144,
+			MaxFeeRate: 1000,
+		}, {
+			ConfTarget: 1,
+			MinFeeRate: 10_000,
+		}, {
+			ConfTarget: 6,
+			MinFeeRate: 2000,
+			MaxFeeRate: 4000,
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, estimator.Start())
+
+	testCases := []struct {
+		numBlocks uint32
+		expected  SatPerKWeight
+	}{
+		{numBlocks: 1, expected: 10_000},
+		{numBlocks: 5, expected: 10_000},
+		{numBlocks: 6, expected: 4000},
+		{numBlocks: 143, expected: 4000},
+		{numBlocks: 144, expected: 1000},
+		{numBlocks: 1008, expected: 1000},
+	}
+	for _, tc := range testCases {
+		feeRate, err := estimator.EstimateFeePerKW(tc.numBlocks)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, feeRate, "conf target %d",
+			tc.numBlocks)
+	}
+}
+
+// TestNewAggregatingEstimatorValidation checks that invalid configurations
+// are rejected.
+func TestNewAggregatingEstimatorValidation(t *testing.T) {
+	t.Parallel()
+
+	source := staticSource("a", 1000, 253)
+
+	_, err := NewAggregatingEstimator(&AggregatorConfig{
+		MaxDeviation: DefaultMaxFeeDeviation,
+	})
+	require.ErrorContains(t, err, "at least one fee source")
+
+	_, err = NewAggregatingEstimator(&AggregatorConfig{
+		Sources: []*FeeSource{source},
+	})
+	require.ErrorContains(t, err, "max deviation")
+
+	_, err = NewAggregatingEstimator(&AggregatorConfig{
+		Sources:      []*FeeSource{source},
+		MaxDeviation: DefaultMaxFeeDeviation,
+		Clamps: []FeeRateClamp{
+			{ConfTarget: 6, MinFeeRate: 1000},
+			{ConfTarget: 6, MaxFeeRate: 2000},
+		},
+	})
+	require.ErrorContains(t, err, "duplicate clamp")
+
+	_, err = NewAggregatingEstimator(&AggregatorConfig{
+		Sources:      []*FeeSource{source},
+		MaxDeviation: DefaultMaxFeeDeviation,
+		Clamps: []FeeRateClamp{
+			{ConfTarget: 6, MinFeeRate: 2000, MaxFeeRate: 1000},
+		},
+	})
+	require.ErrorContains(t, err, "exceeds max fee rate")
+}
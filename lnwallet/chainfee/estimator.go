@@ -15,6 +15,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/lightningnetwork/lnd/esplora"
 	"github.com/lightningnetwork/lnd/lnutils"
 )
 
@@ -724,6 +725,43 @@ func (s SparseConfFeeSource) GetFeeInfo() (WebAPIResponse, error) {
 // WebAPIFeeSource interface.
 var _ WebAPIFeeSource = (*SparseConfFeeSource)(nil)
 
+// EsploraFeeSource is an implementation of the WebAPIFeeSource that queries
+// the fee estimates of an Esplora server, which are given in sat/vbyte for a
+// set of confirmation targets.
+type EsploraFeeSource struct {
+	// Conn is the connection to the Esplora server.
+	Conn *esplora.Conn
+}
+
+// GetFeeInfo will query the Esplora server and return a map of confirmation
+// targets to sat/kvb fees. As the server doesn't expose the minimum relay fee
+// rate, the fee floor is returned as such.
+func (s EsploraFeeSource) GetFeeInfo() (WebAPIResponse, error) {
+	estimates, err := s.Conn.GetFeeEstimates()
+	if err != nil {
+		log.Errorf("unable to query esplora for fee estimates: %v",
+			err)
+
+		return WebAPIResponse{}, err
+	}
+
+	resp := WebAPIResponse{
+		FeeByBlockTarget: make(map[uint32]uint32, len(estimates)),
+		MinRelayFeerate:  FeePerKwFloor.FeePerKVByte(),
+	}
+	for numBlocks, satPerVByte := range estimates {
+		resp.FeeByBlockTarget[numBlocks] = uint32(
+			math.Ceil(satPerVByte * 1000),
+		)
+	}
+
+	return resp, nil
+}
+
+// A compile-time assertion to ensure that EsploraFeeSource implements the
+// WebAPIFeeSource interface.
+var _ WebAPIFeeSource = (*EsploraFeeSource)(nil)
+
 // WebAPIEstimator is an implementation of the Estimator interface that
 // queries an HTTP-based fee estimation from an existing web API.
 type WebAPIEstimator struct {
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/esplora"
+	"github.com/lightningnetwork/lnd/esplora/esploratest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -134,6 +136,36 @@ func TestSparseConfFeeSource(t *testing.T) {
 	require.Error(t, err, "expected error when parsing bad JSON")
 }
 
+// TestEsploraFeeSource checks that EsploraFeeSource converts the fee
+// estimates of an Esplora server from sat/vbyte to sat/kvbyte.
+func TestEsploraFeeSource(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	server.SetFeeEstimates(map[uint32]float64{
+		1:   20.5,
+		6:   10,
+		144: 1.0012,
+	})
+
+	conn := esplora.NewConn(&esplora.Config{
+		URL:            server.URL,
+		RequestTimeout: time.Second,
+	})
+	feeSource := EsploraFeeSource{Conn: conn}
+
+	resp, err := feeSource.GetFeeInfo()
+	require.NoError(t, err)
+	require.Equal(t, WebAPIResponse{
+		FeeByBlockTarget: map[uint32]uint32{
+			1:   20500,
+			6:   10000,
+			144: 1002,
+		},
+		MinRelayFeerate: FeePerKwFloor.FeePerKVByte(),
+	}, resp)
+}
+
 // TestFeeSourceCompatibility checks that when a fee source doesn't return a
 // `min_relay_feerate` field in its response, the floor feerate is used.
 //
@@ -0,0 +1,382 @@
+package chainfee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+const (
+	// DefaultMempoolUpdateInterval is the default interval in which a
+	// MempoolEstimator takes a new snapshot of the mempool.
+	DefaultMempoolUpdateInterval = 5 * time.Minute
+
+	// MinMempoolUpdateInterval is the minimum interval in which a
+	// MempoolEstimator takes a new snapshot of the mempool of a node over
+	// RPC. As every snapshot transfers the whole mempool, taking them more
+	// often puts too much load on the node.
+	MinMempoolUpdateInterval = time.Minute
+
+	// mempoolBlockWeight is the weight of a block that's available to
+	// mempool transactions. Some room is left for the coinbase transaction.
+	mempoolBlockWeight = blockchain.MaxBlockWeight - 4_000
+)
+
+var (
+	// errNoMempoolSnapshot is returned if the mempool hasn't been fetched
+	// yet.
+	errNoMempoolSnapshot = errors.New("no mempool snapshot available")
+)
+
+// MempoolEntry is a transaction in the mempool.
+type MempoolEntry struct {
+	// FeeRate is the fee rate of the transaction.
+	FeeRate SatPerKWeight
+
+	// Weight is the weight of the transaction.
+	Weight lntypes.WeightUnit
+}
+
+// MempoolSnapshot is the state of a mempool at a point in time.
+type MempoolSnapshot struct {
+	// Entries are the transactions in the mempool.
+	Entries []MempoolEntry
+
+	// MinFeeRate is the minimum fee rate required for a transaction to be
+	// accepted to the mempool.
+	MinFeeRate SatPerKWeight
+}
+
+// MempoolSource is an interface that allows the MempoolEstimator to take
+// snapshots of the mempool of a node.
+type MempoolSource interface {
+	// FetchMempool returns a snapshot of the mempool.
+	FetchMempool() (*MempoolSnapshot, error)
+}
+
+// MempoolRPC is the part of the RPC interface of a bitcoind or btcd node
+// that's needed to inspect its mempool.
+type MempoolRPC interface {
+	// RawRequest sends a raw JSON-RPC request to the node.
+	RawRequest(method string,
+		params []json.RawMessage) (json.RawMessage, error)
+}
+
+// RPCMempoolSource is an implementation of the MempoolSource that queries the
+// mempool of a bitcoind or btcd node over its RPC interface.
+//
+// Every snapshot fetches the verbose getrawmempool response, which describes
+// every transaction in the mempool. For a full mempool that's tens of
+// megabytes of JSON that the node has to serialize and we have to parse, which
+// is why snapshots are taken at most once per MinMempoolUpdateInterval. The
+// mempool notifier of chainntnfs isn't used instead, as it only tracks the
+// inputs spent by mempool transactions and not their fees.
+type RPCMempoolSource struct {
+	// Conn is the RPC connection to the node.
+	Conn MempoolRPC
+}
+
+// rawMempoolEntry is an entry of the verbose getrawmempool response. Bitcoind
+// reports the fee in the fees object, while btcd only reports it as fee.
+type rawMempoolEntry struct {
+	Size   int64   `json:"size"`
+	VSize  int64   `json:"vsize"`
+	Weight int64   `json:"weight"`
+	Fee    float64 `json:"fee"`
+	Fees   *struct {
+		Base float64 `json:"base"`
+	} `json:"fees"`
+}
+
+// FetchMempool returns a snapshot of the mempool of the node.
+func (s RPCMempoolSource) FetchMempool() (*MempoolSnapshot, error) {
+	verbose, err := json.Marshal(true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Conn.RawRequest(
+		"getrawmempool", []json.RawMessage{verbose},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawEntries map[string]rawMempoolEntry
+	if err := json.Unmarshal(resp, &rawEntries); err != nil {
+		return nil, err
+	}
+
+	snapshot := &MempoolSnapshot{
+		Entries: make([]MempoolEntry, 0, len(rawEntries)),
+	}
+	for txid, rawEntry := range rawEntries {
+		weight := rawEntry.Weight
+		switch {
+		case weight == 0 && rawEntry.VSize != 0:
+			weight = rawEntry.VSize * blockchain.WitnessScaleFactor
+
+		case weight == 0:
+			weight = rawEntry.Size * blockchain.WitnessScaleFactor
+		}
+
+		if weight == 0 {
+			log.Debugf("Skipping mempool tx %v without size", txid)
+			continue
+		}
+
+		btcFee := rawEntry.Fee
+		if rawEntry.Fees != nil {
+			btcFee = rawEntry.Fees.Base
+		}
+
+		fee, err := btcutil.NewAmount(btcFee)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee of mempool tx "+
+				"%v: %w", txid, err)
+		}
+
+		snapshot.Entries = append(snapshot.Entries, MempoolEntry{
+			FeeRate: NewSatPerKWeight(
+				fee, lntypes.WeightUnit(weight),
+			),
+			Weight: lntypes.WeightUnit(weight),
+		})
+	}
+
+	// Only bitcoind reports the minimum mempool fee, for btcd we fall back
+	// to the fee floor.
+	snapshot.MinFeeRate = FeePerKwFloor
+	resp, err = s.Conn.RawRequest("getmempoolinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := struct {
+		MempoolMinFee float64 `json:"mempoolminfee"`
+	}{}
+	if err := json.Unmarshal(resp, &info); err != nil {
+		return nil, err
+	}
+
+	minMempoolFee, err := btcutil.NewAmount(info.MempoolMinFee)
+	if err != nil {
+		return nil, err
+	}
+
+	minFeeRate := SatPerKVByte(minMempoolFee).FeePerKWeight()
+	if minFeeRate > snapshot.MinFeeRate {
+		snapshot.MinFeeRate = minFeeRate
+	}
+
+	return snapshot, nil
+}
+
+// A compile-time assertion to ensure that RPCMempoolSource implements the
+// MempoolSource interface.
+var _ MempoolSource = (*RPCMempoolSource)(nil)
+
+// MempoolEstimator is an implementation of the Estimator interface that
+// derives fee estimates from the mempool of a node. For a confirmation target
+// of n blocks, the estimate is the fee rate a transaction needs to be within
+// the n blocks worth of the most profitable mempool transactions. As
+// transactions arriving in the meantime aren't accounted for, the estimate
+// is a lower bound that's best combined with other sources.
+type MempoolEstimator struct {
+	started atomic.Bool
+	stopped atomic.Bool
+
+	// source is the mempool the estimates are derived from.
+	source MempoolSource
+
+	// updateInterval is the interval in which a new snapshot of the
+	// mempool is taken.
+	updateInterval time.Duration
+
+	// entries are the transactions of the latest snapshot of the mempool,
+	// sorted by their fee rate in descending order. It's nil if no
+	// snapshot has been taken yet.
+	snapshotMtx sync.RWMutex
+	entries     []MempoolEntry
+	minFeeRate  SatPerKWeight
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewMempoolEstimator creates a new MempoolEstimator that takes a snapshot of
+// the given mempool source in the given interval.
+func NewMempoolEstimator(source MempoolSource,
+	updateInterval time.Duration) (*MempoolEstimator, error) {
+
+	if updateInterval <= 0 {
+		return nil, fmt.Errorf("mempool update interval must be " +
+			"positive")
+	}
+
+	return &MempoolEstimator{
+		source:         source,
+		updateInterval: updateInterval,
+		minFeeRate:     FeePerKwFloor,
+		quit:           make(chan struct{}),
+	}, nil
+}
+
+// Start signals the Estimator to start any processes or goroutines it needs
+// to perform its duty.
+//
+// NOTE: This method is part of the Estimator interface.
+func (m *MempoolEstimator) Start() error {
+	if m.started.Swap(true) {
+		return fmt.Errorf("mempool fee estimator already started")
+	}
+
+	log.Infof("Starting mempool fee estimator with update interval %v",
+		m.updateInterval)
+
+	// An unavailable mempool isn't fatal, as we'll try again on the next
+	// update.
+	m.updateSnapshot()
+
+	m.wg.Add(1)
+	go m.snapshotManager()
+
+	return nil
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used by the
+// fee estimator.
+//
+// NOTE: This method is part of the Estimator interface.
+func (m *MempoolEstimator) Stop() error {
+	if m.stopped.Swap(true) {
+		return fmt.Errorf("mempool fee estimator already stopped")
+	}
+
+	close(m.quit)
+	m.wg.Wait()
+
+	return nil
+}
+
+// EstimateFeePerKW takes in a target for the number of blocks until an initial
+// confirmation and returns the estimated fee expressed in sat/kw.
+//
+// NOTE: This method is part of the Estimator interface.
+func (m *MempoolEstimator) EstimateFeePerKW(
+	numBlocks uint32) (SatPerKWeight, error) {
+
+	if numBlocks > MaxBlockTarget {
+		numBlocks = MaxBlockTarget
+	} else if numBlocks < minBlockTarget {
+		return 0, fmt.Errorf("conf target of %v is too low, minimum "+
+			"accepted is %v", numBlocks, minBlockTarget)
+	}
+
+	m.snapshotMtx.RLock()
+	defer m.snapshotMtx.RUnlock()
+
+	if m.entries == nil {
+		return 0, errNoMempoolSnapshot
+	}
+
+	feeRate := mempoolFeeRate(m.entries, numBlocks)
+	if feeRate < m.minFeeRate {
+		feeRate = m.minFeeRate
+	}
+
+	log.Debugf("Mempool returning %v sat/kw for conf target of %v",
+		int64(feeRate), numBlocks)
+
+	return feeRate, nil
+}
+
+// RelayFeePerKW returns the minimum fee rate required for transactions to be
+// accepted to the mempool.
+//
+// NOTE: This method is part of the Estimator interface.
+func (m *MempoolEstimator) RelayFeePerKW() SatPerKWeight {
+	m.snapshotMtx.RLock()
+	defer m.snapshotMtx.RUnlock()
+
+	return m.minFeeRate
+}
+
+// mempoolFeeRate returns the fee rate of the transaction at the boundary of
+// the given number of blocks, given the mempool transactions sorted by their
+// fee rate in descending order. If the transactions fit into the blocks, zero
+// is returned.
+func mempoolFeeRate(entries []MempoolEntry, numBlocks uint32) SatPerKWeight {
+	capacity := lntypes.WeightUnit(numBlocks) * mempoolBlockWeight
+
+	var weight lntypes.WeightUnit
+	for _, entry := range entries {
+		weight += entry.Weight
+		if weight > capacity {
+			return entry.FeeRate
+		}
+	}
+
+	return 0
+}
+
+// updateSnapshot takes a new snapshot of the mempool.
+func (m *MempoolEstimator) updateSnapshot() {
+	snapshot, err := m.source.FetchMempool()
+	if err != nil {
+		log.Errorf("Unable to fetch mempool: %v", err)
+		return
+	}
+
+	entries := snapshot.Entries
+	if entries == nil {
+		entries = []MempoolEntry{}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FeeRate > entries[j].FeeRate
+	})
+
+	minFeeRate := snapshot.MinFeeRate
+	if minFeeRate < FeePerKwFloor {
+		minFeeRate = FeePerKwFloor
+	}
+
+	log.Debugf("Took mempool snapshot with %d transactions and min fee "+
+		"rate of %v", len(entries), minFeeRate)
+
+	m.snapshotMtx.Lock()
+	m.entries = entries
+	m.minFeeRate = minFeeRate
+	m.snapshotMtx.Unlock()
+}
+
+// snapshotManager takes a new snapshot of the mempool in every update
+// interval.
+func (m *MempoolEstimator) snapshotManager() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.updateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.updateSnapshot()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// A compile-time assertion to ensure that MempoolEstimator implements the
+// Estimator interface.
+var _ Estimator = (*MempoolEstimator)(nil)
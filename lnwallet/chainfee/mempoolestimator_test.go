@@ -0,0 +1,155 @@
+package chainfee
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockMempoolRPC is a MempoolRPC that returns static responses.
+type mockMempoolRPC struct {
+	responses map[string]string
+}
+
+func (m *mockMempoolRPC) RawRequest(method string,
+	_ []json.RawMessage) (json.RawMessage, error) {
+
+	resp, ok := m.responses[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown method %v", method)
+	}
+
+	return json.RawMessage(resp), nil
+}
+
+// TestRPCMempoolSource checks that the mempool responses of bitcoind and btcd
+// are parsed.
+func TestRPCMempoolSource(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		responses map[string]string
+		expected  *MempoolSnapshot
+	}{{
+		name: "bitcoind",
+		responses: map[string]string{
+			"getrawmempool": `{
+				"aa": {"vsize": 250, "weight": 1000,
+					"fees": {"base": 0.00005}},
+				"bb": {"vsize": 100, "weight": 400,
+					"fees": {"base": 0.00004}}
+			}`,
+			"getmempoolinfo": `{"mempoolminfee": 0.00002}`,
+		},
+		expected: &MempoolSnapshot{
+			Entries: []MempoolEntry{
+				{FeeRate: 5000, Weight: 1000},
+				{FeeRate: 10000, Weight: 400},
+			},
+			MinFeeRate: 500,
+		},
+	}, {
+		name: "btcd",
+		responses: map[string]string{
+			"getrawmempool": `{
+				"aa": {"size": 300, "vsize": 250,
+					"fee": 0.00005}
+			}`,
+			"getmempoolinfo": `{"size": 1, "bytes": 300}`,
+		},
+		expected: &MempoolSnapshot{
+			Entries: []MempoolEntry{
+				{FeeRate: 5000, Weight: 1000},
+			},
+			MinFeeRate: FeePerKwFloor,
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := RPCMempoolSource{
+				Conn: &mockMempoolRPC{responses: tc.responses},
+			}
+			snapshot, err := source.FetchMempool()
+			require.NoError(t, err)
+
+			require.ElementsMatch(t, tc.expected.Entries,
+				snapshot.Entries)
+			require.Equal(t, tc.expected.MinFeeRate,
+				snapshot.MinFeeRate)
+		})
+	}
+}
+
+// mockMempoolSource is a MempoolSource that returns a static snapshot.
+type mockMempoolSource struct {
+	snapshot *MempoolSnapshot
+	err      error
+}
+
+func (m *mockMempoolSource) FetchMempool() (*MempoolSnapshot, error) {
+	return m.snapshot, m.err
+}
+
+// TestMempoolEstimator checks that the fee rate estimated for a conf target
+// is the one needed to be within the blocks of the most profitable
+// transactions.
+func TestMempoolEstimator(t *testing.T) {
+	t.Parallel()
+
+	source := &mockMempoolSource{
+		err: fmt.Errorf("mempool unavailable"),
+	}
+	estimator, err := NewMempoolEstimator(source, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, estimator.Start())
+	t.Cleanup(func() {
+		require.NoError(t, estimator.Stop())
+	})
+
+	// Without a snapshot of the mempool, there's no estimate.
+	_, err = estimator.EstimateFeePerKW(1)
+	require.ErrorIs(t, err, errNoMempoolSnapshot)
+	require.Equal(t, FeePerKwFloor, estimator.RelayFeePerKW())
+
+	// The first block is filled by the first transaction and part of
+	// the second one, the second block by the rest of the second one and
+	// part of the third one. The third block has room to spare.
+	source.snapshot = &MempoolSnapshot{
+		Entries: []MempoolEntry{
+			{FeeRate: 2000, Weight: 4_000_000},
+			{FeeRate: 10_000, Weight: 2_000_000},
+			{FeeRate: 5000, Weight: 3_000_000},
+		},
+		MinFeeRate: 300,
+	}
+	source.err = nil
+	estimator.updateSnapshot()
+
+	testCases := []struct {
+		numBlocks uint32
+		expected  SatPerKWeight
+	}{
+		{numBlocks: 1, expected: 5000},
+		{numBlocks: 2, expected: 2000},
+		{numBlocks: 3, expected: 300},
+		{numBlocks: 2000, expected: 300},
+	}
+	for _, tc := range testCases {
+		feeRate, err := estimator.EstimateFeePerKW(tc.numBlocks)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, feeRate, "conf target %d",
+			tc.numBlocks)
+	}
+
+	require.EqualValues(t, 300, estimator.RelayFeePerKW())
+
+	_, err = estimator.EstimateFeePerKW(0)
+	require.Error(t, err)
+}
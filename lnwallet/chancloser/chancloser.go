@@ -157,6 +157,11 @@ type ChanCloseCfg struct {
 	// AuxCloser is an optional interface that can be used to modify the
 	// way the co-op close process proceeds.
 	AuxCloser fn.Option[AuxChanCloser]
+
+	// SimpleClose indicates that the closing transaction should be
+	// negotiated using the simple close protocol (ClosingComplete and
+	// ClosingSig) rather than the legacy ClosingSigned fee negotiation.
+	SimpleClose bool
 }
 
 // ChanCloser is a state machine that handles the cooperative channel closure
@@ -250,6 +255,20 @@ type ChanCloser struct {
 	// auxOutputs are the optional additional outputs that might be added to
 	// the closing transaction.
 	auxOutputs fn.Option[AuxCloseOutputs]
+
+	// localSimpleClose is the last closing transaction we proposed as the
+	// closer with the simple close protocol, if any.
+	localSimpleClose fn.Option[simpleCloseProposal]
+
+	// lastSimpleCloseFee is the fee of the last closing transaction we
+	// proposed as the closer with the simple close protocol, or of the
+	// closing transaction restored from disk. A new proposal must pay a
+	// higher fee in order to replace it.
+	lastSimpleCloseFee btcutil.Amount
+
+	// cachedClosingComplete is a cached copy of a received ClosingComplete
+	// that arrived before our link was flushed.
+	cachedClosingComplete fn.Option[lnwire.ClosingComplete]
 }
 
 // calcCoopCloseFee computes an "ideal" absolute co-op close fee given the
@@ -328,21 +347,7 @@ func NewChanCloser(cfg ChanCloseCfg, deliveryScript DeliveryAddrWithKey,
 // initFeeBaseline computes our ideal fee rate, and also the largest fee we'll
 // accept given information about the delivery script of the remote party.
 func (c *ChanCloser) initFeeBaseline() {
-	// Depending on if a balance ends up being dust or not, we'll pass a
-	// nil TxOut into the EstimateFee call which can handle it.
-	var localTxOut, remoteTxOut *wire.TxOut
-	if isDust, _ := c.cfg.Channel.LocalBalanceDust(); !isDust {
-		localTxOut = &wire.TxOut{
-			PkScript: c.localDeliveryScript,
-			Value:    0,
-		}
-	}
-	if isDust, _ := c.cfg.Channel.RemoteBalanceDust(); !isDust {
-		remoteTxOut = &wire.TxOut{
-			PkScript: c.remoteDeliveryScript,
-			Value:    0,
-		}
-	}
+	localTxOut, remoteTxOut := c.closeTxOuts()
 
 	// Given the target fee-per-kw, we'll compute what our ideal _total_
 	// fee will be starting at for this fee negotiation.
@@ -365,6 +370,27 @@ func (c *ChanCloser) initFeeBaseline() {
 		int64(c.idealFeeSat), int64(c.maxFee))
 }
 
+// closeTxOuts returns the outputs of both parties used to estimate the fee of
+// the closing transaction. Depending on if a balance ends up being dust or
+// not, a nil TxOut is returned which the EstimateFee call can handle.
+func (c *ChanCloser) closeTxOuts() (*wire.TxOut, *wire.TxOut) {
+	var localTxOut, remoteTxOut *wire.TxOut
+	if isDust, _ := c.cfg.Channel.LocalBalanceDust(); !isDust {
+		localTxOut = &wire.TxOut{
+			PkScript: c.localDeliveryScript,
+			Value:    0,
+		}
+	}
+	if isDust, _ := c.cfg.Channel.RemoteBalanceDust(); !isDust {
+		remoteTxOut = &wire.TxOut{
+			PkScript: c.remoteDeliveryScript,
+			Value:    0,
+		}
+	}
+
+	return localTxOut, remoteTxOut
+}
+
 // initChanShutdown begins the shutdown process by un-registering the channel,
 // and creating a valid shutdown message to our target delivery address.
 func (c *ChanCloser) initChanShutdown() (*lnwire.Shutdown, error) {
@@ -800,6 +826,13 @@ func (c *ChanCloser) ReceiveClosingSigned( //nolint:funlen
 
 	noClosing := fn.None[lnwire.ClosingSigned]()
 
+	// If we negotiated the simple close protocol, then the legacy fee
+	// negotiation isn't allowed.
+	if c.cfg.SimpleClose {
+		return noClosing, fmt.Errorf("%w: received ClosingSigned",
+			ErrSimpleCloseNegotiated)
+	}
+
 	switch c.state {
 	case closeAwaitingFlush:
 		// If we hit this case it either means there's a protocol
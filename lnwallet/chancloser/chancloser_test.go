@@ -214,6 +214,8 @@ func (m *mockChannel) CompleteCooperativeClose(localSig,
 	return &wire.MsgTx{}, 0, nil
 }
 
+func (m *mockChannel) ResetState() {}
+
 func (m *mockChannel) LocalBalanceDust() (bool, btcutil.Amount) {
 	return false, 0
 }
@@ -108,6 +108,11 @@ type Channel interface { //nolint:interfacebloat
 		localDeliveryScript, remoteDeliveryScript []byte,
 		proposedFee btcutil.Amount, closeOpt ...lnwallet.ChanCloseOpt,
 	) (*wire.MsgTx, btcutil.Amount, error)
+
+	// ResetState resets the closing state of the channel, allowing a new
+	// co-op close transaction to be created after a prior one has already
+	// been completed.
+	ResetState()
 }
 
 // MusigSession is an interface that abstracts away the details of the musig2
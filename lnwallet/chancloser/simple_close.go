@@ -0,0 +1,593 @@
+package chancloser
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnutils"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrSimpleCloseNotNegotiated is returned when a simple close
+	// operation is attempted for a channel that doesn't use the simple
+	// close protocol.
+	ErrSimpleCloseNotNegotiated = fmt.Errorf("simple close not " +
+		"negotiated")
+
+	// ErrSimpleCloseNegotiated is returned when a legacy closing message
+	// is received for a channel that uses the simple close protocol.
+	ErrSimpleCloseNegotiated = fmt.Errorf("simple close negotiated")
+
+	// ErrSimpleCloseTaproot is returned when the simple close protocol is
+	// attempted for a taproot channel, for which the closing
+	// transactions would require MuSig2 nonces that the protocol doesn't
+	// exchange yet.
+	ErrSimpleCloseTaproot = fmt.Errorf("simple close not supported for " +
+		"taproot channels")
+
+	// ErrCloseFeeTooLow is returned when a new closing transaction doesn't
+	// pay a higher fee than the one it is meant to replace.
+	ErrCloseFeeTooLow = fmt.Errorf("close fee must be higher than the " +
+		"prior close fee")
+
+	// ErrCloseProposalPending is returned when we attempt to propose a new
+	// closing transaction while the remote party hasn't yet signed the
+	// prior one we proposed.
+	ErrCloseProposalPending = fmt.Errorf("prior closing transaction " +
+		"proposal still pending")
+
+	// ErrNoCloseOutputs is returned when the closing transaction would be
+	// left without any outputs after paying the closing fee.
+	ErrNoCloseOutputs = fmt.Errorf("closing transaction has no outputs")
+
+	// ErrMissingClosingSig is returned when a closing message doesn't
+	// carry a signature for the expected closing transaction.
+	ErrMissingClosingSig = fmt.Errorf("missing signature for closing " +
+		"transaction")
+
+	// errNoSimpleCloseProposal is returned when we receive a ClosingSig
+	// without having sent a ClosingComplete first.
+	errNoSimpleCloseProposal = fmt.Errorf("no closing transaction " +
+		"proposed")
+)
+
+// closeSigType denotes which outputs of the closing transaction a simple close
+// signature covers.
+type closeSigType uint8
+
+const (
+	// closerAndClosee denotes a closing transaction that pays to both the
+	// closer and the closee.
+	closerAndClosee closeSigType = iota
+
+	// closerNoClosee denotes a closing transaction that only pays to the
+	// closer, as the output of the closee is dust.
+	closerNoClosee
+
+	// noCloserClosee denotes a closing transaction that only pays to the
+	// closee, as the output of the closer is dust after paying the fee.
+	noCloserClosee
+)
+
+// String returns a human readable version of the closeSigType.
+func (c closeSigType) String() string {
+	switch c {
+	case closerAndClosee:
+		return "closer_and_closee"
+
+	case closerNoClosee:
+		return "closer_no_closee"
+
+	case noCloserClosee:
+		return "no_closer_closee"
+
+	default:
+		return "unknown"
+	}
+}
+
+// setSig populates the signature record matching the closeSigType.
+func (c closeSigType) setSig(sigs *lnwire.ClosingSigs, sig lnwire.Sig) {
+	switch c {
+	case closerAndClosee:
+		sigs.CloserAndClosee = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType3](sig),
+		)
+
+	case closerNoClosee:
+		sigs.CloserNoClosee = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType1](sig),
+		)
+
+	case noCloserClosee:
+		sigs.NoCloserClosee = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType2](sig),
+		)
+	}
+}
+
+// sig returns the signature record matching the closeSigType, failing if it
+// isn't present.
+func (c closeSigType) sig(sigs lnwire.ClosingSigs) (input.Signature, error) {
+	errMissing := fmt.Errorf("%w: %v", ErrMissingClosingSig, c)
+
+	var (
+		wireSig lnwire.Sig
+		err     error
+	)
+	switch c {
+	case closerAndClosee:
+		wireSig, err = sigs.CloserAndClosee.UnwrapOrErrV(errMissing)
+
+	case closerNoClosee:
+		wireSig, err = sigs.CloserNoClosee.UnwrapOrErrV(errMissing)
+
+	case noCloserClosee:
+		wireSig, err = sigs.NoCloserClosee.UnwrapOrErrV(errMissing)
+
+	default:
+		err = errMissing
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return wireSig.ToSignature()
+}
+
+// simpleCloseProposal is a closing transaction we proposed as the closer with
+// the simple close protocol.
+type simpleCloseProposal struct {
+	// fee is the absolute fee of the closing transaction, which is paid by
+	// us.
+	fee btcutil.Amount
+
+	// sequence is the sequence of the input spending the funding output.
+	sequence uint32
+
+	// sigType denotes the outputs present in the closing transaction.
+	sigType closeSigType
+
+	// sig is our signature for the closing transaction.
+	sig input.Signature
+}
+
+// simpleCloseOpts returns the set of close options to create the closing
+// transaction paid for by the given closer.
+func simpleCloseOpts(closer lntypes.ChannelParty,
+	sequence uint32) []lnwallet.ChanCloseOpt {
+
+	return []lnwallet.ChanCloseOpt{
+		lnwallet.WithCustomPayer(closer),
+		lnwallet.WithCustomSequence(sequence),
+	}
+}
+
+// SimpleClose returns true if the closing transaction is negotiated using the
+// simple close protocol.
+func (c *ChanCloser) SimpleClose() bool {
+	return c.cfg.SimpleClose
+}
+
+// simpleCloseSigType determines which outputs will be present on the closing
+// transaction for which the given closer pays the given fee. As each party
+// uses its own dust limit for its output, both parties arrive at the same
+// result.
+func (c *ChanCloser) simpleCloseSigType(fee btcutil.Amount,
+	closer lntypes.ChannelParty) (closeSigType, error) {
+
+	localBalance, remoteBalance := c.cfg.Channel.CommitBalances()
+
+	// The commitment fee is returned to the initiator of the channel.
+	if c.cfg.Channel.IsInitiator() {
+		localBalance += c.cfg.Channel.CommitFee()
+	} else {
+		remoteBalance += c.cfg.Channel.CommitFee()
+	}
+
+	_, localDust := c.cfg.Channel.LocalBalanceDust()
+	_, remoteDust := c.cfg.Channel.RemoteBalanceDust()
+
+	closerBalance, closerDust := localBalance, localDust
+	closeeBalance, closeeDust := remoteBalance, remoteDust
+	if !closer.IsLocal() {
+		closerBalance, closerDust = remoteBalance, remoteDust
+		closeeBalance, closeeDust = localBalance, localDust
+	}
+
+	if fee > closerBalance {
+		return 0, fmt.Errorf("closer balance of %v cannot pay close "+
+			"fee of %v", closerBalance, fee)
+	}
+	closerBalance -= fee
+
+	hasCloser := closerBalance >= closerDust
+	hasClosee := closeeBalance >= closeeDust
+
+	switch {
+	case hasCloser && hasClosee:
+		return closerAndClosee, nil
+
+	case hasCloser:
+		return closerNoClosee, nil
+
+	case hasClosee:
+		return noCloserClosee, nil
+
+	default:
+		return 0, ErrNoCloseOutputs
+	}
+}
+
+// checkSimpleClose returns an error if the simple close protocol can't be
+// used for the channel.
+func (c *ChanCloser) checkSimpleClose() error {
+	switch {
+	case !c.cfg.SimpleClose:
+		return ErrSimpleCloseNotNegotiated
+
+	case c.cfg.Channel.ChanType().IsTaproot():
+		return ErrSimpleCloseTaproot
+
+	default:
+		return nil
+	}
+}
+
+// RestoreSimpleClose restores the latest closing transaction that was
+// negotiated with the simple close protocol before a restart or reconnect, so
+// the negotiation can be resumed after the shutdown messages have been
+// exchanged again. Any closing transaction proposed afterwards must pay a
+// higher fee than the restored one in order to replace it.
+func (c *ChanCloser) RestoreSimpleClose(closeTx *wire.MsgTx) error {
+	if err := c.checkSimpleClose(); err != nil {
+		return err
+	}
+
+	if c.state != closeIdle {
+		return ErrInvalidState
+	}
+
+	// Each party pays the full closing fee from its own output, so the fee
+	// of the restored transaction is simply the difference between the
+	// channel capacity and the closing outputs.
+	fee := btcutil.Amount(c.cfg.Channel.FundingTxOut().Value)
+	for _, txOut := range closeTx.TxOut {
+		fee -= btcutil.Amount(txOut.Value)
+	}
+
+	c.closingTx = closeTx
+	c.lastSimpleCloseFee = fee
+
+	chancloserLog.Infof("ChannelPoint(%v): restored closing tx %v with "+
+		"fee of %v sat", c.chanPoint, closeTx.TxHash(), int64(fee))
+
+	return nil
+}
+
+// prepareSimpleClose ensures that the state machine is in a state where a
+// closing transaction can be created with the simple close protocol.
+func (c *ChanCloser) prepareSimpleClose() error {
+	if err := c.checkSimpleClose(); err != nil {
+		return err
+	}
+
+	switch c.state {
+	case closeFeeNegotiation:
+		return nil
+
+	// If we already completed a closing transaction, then the channel was
+	// marked as closed. As we're about to replace that transaction, we'll
+	// reset the channel state so a new one can be created.
+	case closeFinished:
+		c.cfg.Channel.ResetState()
+
+		return nil
+
+	default:
+		return ErrInvalidState
+	}
+}
+
+// BeginSimpleClose is the simple close counterpart of BeginNegotiation. It
+// should be called once the channel has reached a clean state. If we initiated
+// the shutdown, a ClosingComplete proposing a closing transaction at our ideal
+// fee rate is returned.
+func (c *ChanCloser) BeginSimpleClose() (fn.Option[lnwire.ClosingComplete],
+	error) {
+
+	noClosingComplete := fn.None[lnwire.ClosingComplete]()
+
+	if err := c.checkSimpleClose(); err != nil {
+		return noClosingComplete, err
+	}
+
+	if c.state != closeAwaitingFlush {
+		return noClosingComplete, ErrInvalidState
+	}
+
+	// Before continuing, mark the channel as cooperatively closed with a
+	// nil txn, just like we do for the legacy negotiation. This leaves a
+	// closing transaction that was already stored untouched.
+	err := c.cfg.Channel.MarkCoopBroadcasted(nil, c.closer)
+	if err != nil {
+		return noClosingComplete, err
+	}
+
+	// If we restored a closing transaction, then it's still valid, so
+	// we'll resume where we left off. Either party may replace it with
+	// one paying a higher fee.
+	if c.closingTx != nil {
+		c.state = closeFinished
+
+		return noClosingComplete, nil
+	}
+
+	c.state = closeFeeNegotiation
+
+	// Each party pays the fee of the closing transaction it proposes, so
+	// either party may propose one. We'll only do so right away if we
+	// initiated the shutdown.
+	if !c.closer.IsLocal() {
+		return noClosingComplete, nil
+	}
+
+	closingComplete, _, err := c.ProposeSimpleClose(c.idealFeeRate)
+	if err != nil {
+		return noClosingComplete, err
+	}
+
+	return fn.Some(*closingComplete), nil
+}
+
+// CachedClosingComplete returns the ClosingComplete of the remote party that
+// was received while we were waiting for the channel to be flushed, if any.
+// The message is removed from the cache, and should be processed again with
+// ReceiveClosingComplete once BeginSimpleClose has been called.
+func (c *ChanCloser) CachedClosingComplete() fn.Option[lnwire.ClosingComplete] {
+	cached := c.cachedClosingComplete
+	c.cachedClosingComplete = fn.None[lnwire.ClosingComplete]()
+
+	return cached
+}
+
+// ProposeSimpleClose creates a ClosingComplete message proposing a closing
+// transaction at the given fee rate, for which we pay the full fee. If we
+// already proposed a closing transaction, the new one must pay a higher fee so
+// it can replace the prior one, and the remote party must have signed the
+// prior one already. The txid of the proposed closing transaction is returned
+// as well.
+func (c *ChanCloser) ProposeSimpleClose(feeRate chainfee.SatPerKWeight) (
+	*lnwire.ClosingComplete, *chainhash.Hash, error) {
+
+	if err := c.prepareSimpleClose(); err != nil {
+		return nil, nil, err
+	}
+
+	// As the ClosingSig of the remote party doesn't identify the proposal
+	// it signs, we only allow a single outstanding proposal.
+	if c.localSimpleClose.IsSome() {
+		return nil, nil, ErrCloseProposalPending
+	}
+
+	localTxOut, remoteTxOut := c.closeTxOuts()
+	fee := c.cfg.FeeEstimator.EstimateFee(
+		c.cfg.Channel.ChanType(), localTxOut, remoteTxOut, feeRate,
+	)
+	if fee <= c.lastSimpleCloseFee {
+		return nil, nil, fmt.Errorf("%w: %v <= %v", ErrCloseFeeTooLow,
+			fee, c.lastSimpleCloseFee)
+	}
+
+	sigType, err := c.simpleCloseSigType(fee, lntypes.Local)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// We signal RBF on the funding input so the closing transaction can be
+	// replaced by either party later on.
+	sequence := uint32(mempool.MaxRBFSequence)
+	rawSig, txid, _, err := c.cfg.Channel.CreateCloseProposal(
+		fee, c.localDeliveryScript, c.remoteDeliveryScript,
+		simpleCloseOpts(lntypes.Local, sequence)...,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wireSig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.localSimpleClose = fn.Some(simpleCloseProposal{
+		fee:      fee,
+		sequence: sequence,
+		sigType:  sigType,
+		sig:      rawSig,
+	})
+	c.lastSimpleCloseFee = fee
+
+	chancloserLog.Infof("ChannelPoint(%v): proposing closing tx %v "+
+		"(%v) with fee of %v sat", c.chanPoint, txid, sigType,
+		int64(fee))
+
+	closingComplete := &lnwire.ClosingComplete{
+		ChannelID:   c.cid,
+		FeeSatoshis: fee,
+		Sequence:    sequence,
+	}
+	sigType.setSig(&closingComplete.ClosingSigs, wireSig)
+
+	return closingComplete, txid, nil
+}
+
+// ReceiveClosingComplete processes a ClosingComplete of the remote party,
+// which proposes a closing transaction paid for by them. We'll counter-sign
+// the closing transaction, broadcast it, and return the ClosingSig that should
+// be sent back to the remote party.
+func (c *ChanCloser) ReceiveClosingComplete(msg lnwire.ClosingComplete) (
+	fn.Option[lnwire.ClosingSig], error) {
+
+	noClosingSig := fn.None[lnwire.ClosingSig]()
+
+	if err := c.checkSimpleClose(); err != nil {
+		return noClosingSig, err
+	}
+
+	// If we receive the message before our link was flushed, we'll cache
+	// it and process it once we begin the negotiation.
+	if c.state == closeAwaitingFlush {
+		c.cachedClosingComplete = fn.Some(msg)
+		return noClosingSig, nil
+	}
+
+	replacing := c.state == closeFinished
+	if err := c.prepareSimpleClose(); err != nil {
+		return noClosingSig, err
+	}
+
+	sigType, err := c.simpleCloseSigType(msg.FeeSatoshis, lntypes.Remote)
+	if err != nil {
+		return noClosingSig, err
+	}
+
+	remoteSig, err := sigType.sig(msg.ClosingSigs)
+	if err != nil {
+		return noClosingSig, err
+	}
+
+	opts := simpleCloseOpts(lntypes.Remote, msg.Sequence)
+	localSig, _, _, err := c.cfg.Channel.CreateCloseProposal(
+		msg.FeeSatoshis, c.localDeliveryScript, c.remoteDeliveryScript,
+		opts...,
+	)
+	if err != nil {
+		return noClosingSig, err
+	}
+
+	chancloserLog.Infof("ChannelPoint(%v): accepting closing tx (%v) "+
+		"with remote fee of %v sat", c.chanPoint, sigType,
+		int64(msg.FeeSatoshis))
+
+	err = c.completeSimpleClose(
+		localSig, remoteSig, msg.FeeSatoshis, opts, replacing,
+	)
+	if err != nil {
+		return noClosingSig, err
+	}
+
+	wireSig, err := lnwire.NewSigFromSignature(localSig)
+	if err != nil {
+		return noClosingSig, err
+	}
+
+	closingSig := lnwire.ClosingSig{
+		ChannelID: c.cid,
+	}
+	sigType.setSig(&closingSig.ClosingSigs, wireSig)
+
+	return fn.Some(closingSig), nil
+}
+
+// ReceiveClosingSig processes a ClosingSig of the remote party, which
+// counter-signs the last closing transaction we proposed. The fully signed
+// closing transaction is then broadcast.
+func (c *ChanCloser) ReceiveClosingSig(msg lnwire.ClosingSig) error {
+	if err := c.checkSimpleClose(); err != nil {
+		return err
+	}
+
+	replacing := c.state == closeFinished
+	if err := c.prepareSimpleClose(); err != nil {
+		return err
+	}
+
+	proposal, err := c.localSimpleClose.UnwrapOrErr(
+		errNoSimpleCloseProposal,
+	)
+	if err != nil {
+		return err
+	}
+
+	remoteSig, err := proposal.sigType.sig(msg.ClosingSigs)
+	if err != nil {
+		return err
+	}
+
+	err = c.completeSimpleClose(
+		proposal.sig, remoteSig, proposal.fee,
+		simpleCloseOpts(lntypes.Local, proposal.sequence), replacing,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.localSimpleClose = fn.None[simpleCloseProposal]()
+
+	return nil
+}
+
+// completeSimpleClose assembles the fully signed closing transaction from both
+// signatures, persists and broadcasts it, and transitions the state machine to
+// the closeFinished state.
+func (c *ChanCloser) completeSimpleClose(localSig, remoteSig input.Signature,
+	fee btcutil.Amount, opts []lnwallet.ChanCloseOpt,
+	replacing bool) error {
+
+	closeTx, _, err := c.cfg.Channel.CompleteCooperativeClose(
+		localSig, remoteSig, c.localDeliveryScript,
+		c.remoteDeliveryScript, fee, opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Before publishing the closing tx, we persist it to the database,
+	// such that it can be republished if something goes wrong.
+	err = c.cfg.Channel.MarkCoopBroadcasted(closeTx, c.closer)
+	if err != nil {
+		return err
+	}
+
+	chancloserLog.Infof("Broadcasting cooperative close tx: %v",
+		lnutils.SpewLogClosure(closeTx))
+
+	chanID := c.cfg.Channel.ShortChanID()
+	closeLabel := labels.MakeLabel(labels.LabelTypeChannelClose, &chanID)
+
+	err = c.cfg.BroadcastTx(closeTx, closeLabel)
+	switch {
+	// If this transaction was meant to replace a prior closing
+	// transaction, then failing to broadcast it isn't fatal, as the prior
+	// transaction is still valid. We'll persist the prior transaction
+	// again, so it's the one we resume with after a restart.
+	case err != nil && replacing:
+		chancloserLog.Warnf("ChannelPoint(%v): unable to broadcast "+
+			"replacement close tx %v: %v", c.chanPoint,
+			closeTx.TxHash(), err)
+
+		return c.cfg.Channel.MarkCoopBroadcasted(
+			c.closingTx, c.closer,
+		)
+
+	case err != nil:
+		return err
+	}
+
+	c.closingTx = closeTx
+	c.state = closeFinished
+
+	return nil
+}
@@ -0,0 +1,553 @@
+package chancloser
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newSimpleCloser creates a ChanCloser for the given channel that uses the
+// simple close protocol. The returned channel receives all the transactions
+// the closer broadcasts.
+func newSimpleCloser(t *testing.T, channel *lnwallet.LightningChannel,
+	deliveryScript []byte, closer lntypes.ChannelParty) (*ChanCloser,
+	chan *wire.MsgTx) {
+
+	broadcasts := make(chan *wire.MsgTx, 10)
+	cfg := ChanCloseCfg{
+		Channel:      channel,
+		FeeEstimator: &SimpleCoopFeeEstimator{},
+		BroadcastTx: func(tx *wire.MsgTx, _ string) error {
+			broadcasts <- tx
+			return nil
+		},
+		DisableChannel: func(wire.OutPoint) error {
+			return nil
+		},
+		Disconnect: func() error {
+			return nil
+		},
+		ChainParams: &chaincfg.RegressionNetParams,
+		SimpleClose: true,
+	}
+
+	chanCloser := NewChanCloser(
+		cfg, DeliveryAddrWithKey{DeliveryAddress: deliveryScript},
+		chainfee.FeePerKwFloor, 0, nil, closer,
+	)
+
+	return chanCloser, broadcasts
+}
+
+// p2wkhScript returns a P2WKH script paying to a dummy key hash.
+func p2wkhScript(t *testing.T, b byte) []byte {
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(bytes.Repeat([]byte{b}, 20)).Script()
+	require.NoError(t, err)
+
+	return script
+}
+
+// receiveTx asserts that a transaction was broadcast and returns it.
+func receiveTx(t *testing.T, broadcasts chan *wire.MsgTx) *wire.MsgTx {
+	t.Helper()
+
+	select {
+	case tx := <-broadcasts:
+		return tx
+	default:
+		t.Fatalf("no transaction broadcast")
+		return nil
+	}
+}
+
+// findOutput returns the value of the output paying to the given script, or
+// zero if there's none.
+func findOutput(tx *wire.MsgTx, pkScript []byte) btcutil.Amount {
+	for _, txOut := range tx.TxOut {
+		if bytes.Equal(txOut.PkScript, pkScript) {
+			return btcutil.Amount(txOut.Value)
+		}
+	}
+
+	return 0
+}
+
+// TestSimpleClose tests that both parties can close a channel using the simple
+// close protocol, with each party paying the fee of the closing transaction it
+// proposes, and that a closing transaction can be replaced by one paying a
+// higher fee.
+func TestSimpleClose(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	aliceScript := p2wkhScript(t, 1)
+	bobScript := p2wkhScript(t, 2)
+
+	alice, aliceTxs := newSimpleCloser(
+		t, aliceChannel, aliceScript, lntypes.Local,
+	)
+	bob, bobTxs := newSimpleCloser(t, bobChannel, bobScript, lntypes.Remote)
+
+	// First, the shutdown messages are exchanged as usual.
+	aliceShutdown, err := alice.ShutdownChan()
+	require.NoError(t, err)
+
+	bobShutdown, err := bob.ReceiveShutdown(*aliceShutdown)
+	require.NoError(t, err)
+	require.True(t, bobShutdown.IsSome())
+
+	bobShutdown.WhenSome(func(msg lnwire.Shutdown) {
+		noShutdown, err := alice.ReceiveShutdown(msg)
+		require.NoError(t, err)
+		require.True(t, noShutdown.IsNone())
+	})
+
+	// A simple close channel must not accept legacy negotiation messages.
+	_, err = alice.ReceiveClosingSigned(lnwire.ClosingSigned{})
+	require.ErrorIs(t, err, ErrSimpleCloseNegotiated)
+
+	// Once the channel is flushed, Alice proposes a closing transaction as
+	// she initiated the shutdown.
+	aliceMsg, err := alice.BeginSimpleClose()
+	require.NoError(t, err)
+	require.True(t, aliceMsg.IsSome())
+
+	closingComplete := aliceMsg.UnwrapOrFail(t)
+	require.True(t, closingComplete.CloserAndClosee.IsSome())
+	require.EqualValues(t, mempool.MaxRBFSequence, closingComplete.Sequence)
+
+	// Bob receives the proposal before his channel is flushed, so he caches
+	// it.
+	closingSig, err := bob.ReceiveClosingComplete(closingComplete)
+	require.NoError(t, err)
+	require.True(t, closingSig.IsNone())
+
+	// Bob doesn't propose a closing transaction of his own.
+	bobMsg, err := bob.BeginSimpleClose()
+	require.NoError(t, err)
+	require.True(t, bobMsg.IsNone())
+
+	cached := bob.CachedClosingComplete()
+	require.Equal(t, fn.Some(closingComplete), cached)
+	require.True(t, bob.CachedClosingComplete().IsNone())
+
+	// Bob counter-signs and broadcasts the closing transaction.
+	closingSig, err = bob.ReceiveClosingComplete(closingComplete)
+	require.NoError(t, err)
+	require.True(t, closingSig.IsSome())
+
+	bobCloseTx := receiveTx(t, bobTxs)
+	require.Len(t, bobCloseTx.TxOut, 2)
+	require.EqualValues(
+		t, mempool.MaxRBFSequence, bobCloseTx.TxIn[0].Sequence,
+	)
+
+	// Alice pays the full fee, so Bob receives his full balance.
+	aliceBalance, bobBalance := aliceChannel.CommitBalances()
+	aliceBalance += aliceChannel.CommitFee()
+	require.Equal(t, bobBalance, findOutput(bobCloseTx, bobScript))
+	require.Equal(
+		t, aliceBalance-closingComplete.FeeSatoshis,
+		findOutput(bobCloseTx, aliceScript),
+	)
+
+	// Once Alice receives the signature, she broadcasts the very same
+	// transaction.
+	closingSig.WhenSome(func(msg lnwire.ClosingSig) {
+		require.NoError(t, alice.ReceiveClosingSig(msg))
+	})
+	aliceCloseTx := receiveTx(t, aliceTxs)
+	require.Equal(t, bobCloseTx.TxHash(), aliceCloseTx.TxHash())
+
+	closingTx, err := alice.ClosingTx()
+	require.NoError(t, err)
+	require.Equal(t, aliceCloseTx.TxHash(), closingTx.TxHash())
+
+	// A replacement must pay a higher fee than the prior transaction.
+	_, _, err = alice.ProposeSimpleClose(chainfee.FeePerKwFloor)
+	require.ErrorIs(t, err, ErrCloseFeeTooLow)
+
+	// Alice now bumps the fee of her closing transaction.
+	bumpComplete, bumpTxid, err := alice.ProposeSimpleClose(
+		chainfee.FeePerKwFloor * 4,
+	)
+	require.NoError(t, err)
+	require.Greater(
+		t, bumpComplete.FeeSatoshis, closingComplete.FeeSatoshis,
+	)
+
+	// Until Bob signs it, Alice can't propose another one.
+	_, _, err = alice.ProposeSimpleClose(chainfee.FeePerKwFloor * 5)
+	require.ErrorIs(t, err, ErrCloseProposalPending)
+
+	closingSig, err = bob.ReceiveClosingComplete(*bumpComplete)
+	require.NoError(t, err)
+	require.Equal(t, *bumpTxid, receiveTx(t, bobTxs).TxHash())
+
+	closingSig.WhenSome(func(msg lnwire.ClosingSig) {
+		require.NoError(t, alice.ReceiveClosingSig(msg))
+	})
+	require.Equal(t, *bumpTxid, receiveTx(t, aliceTxs).TxHash())
+
+	// Alice can't complete the same proposal twice.
+	closingSig.WhenSome(func(msg lnwire.ClosingSig) {
+		err := alice.ReceiveClosingSig(msg)
+		require.ErrorIs(t, err, errNoSimpleCloseProposal)
+	})
+
+	// Bob may also propose a closing transaction which he pays for.
+	bobComplete, bobTxid, err := bob.ProposeSimpleClose(
+		chainfee.FeePerKwFloor * 8,
+	)
+	require.NoError(t, err)
+
+	closingSig, err = alice.ReceiveClosingComplete(*bobComplete)
+	require.NoError(t, err)
+
+	aliceCloseTx = receiveTx(t, aliceTxs)
+	require.Equal(t, *bobTxid, aliceCloseTx.TxHash())
+	require.Equal(
+		t, bobBalance-bobComplete.FeeSatoshis,
+		findOutput(aliceCloseTx, bobScript),
+	)
+	require.Equal(t, aliceBalance, findOutput(aliceCloseTx, aliceScript))
+
+	closingSig.WhenSome(func(msg lnwire.ClosingSig) {
+		require.NoError(t, bob.ReceiveClosingSig(msg))
+	})
+	require.Equal(t, *bobTxid, receiveTx(t, bobTxs).TxHash())
+
+	// A proposal with a missing signature is rejected.
+	bobComplete, _, err = bob.ProposeSimpleClose(
+		chainfee.FeePerKwFloor * 16,
+	)
+	require.NoError(t, err)
+	bobComplete.ClosingSigs = lnwire.ClosingSigs{}
+
+	_, err = alice.ReceiveClosingComplete(*bobComplete)
+	require.ErrorIs(t, err, ErrMissingClosingSig)
+}
+
+// exchangeShutdown exchanges the shutdown messages between the closer that
+// initiates the shutdown and the responder, and begins the simple close
+// negotiation for both of them. The ClosingComplete sent by the initiator, if
+// any, is returned.
+func exchangeShutdown(t *testing.T, initiator,
+	responder *ChanCloser) fn.Option[lnwire.ClosingComplete] {
+
+	t.Helper()
+
+	shutdown, err := initiator.ShutdownChan()
+	require.NoError(t, err)
+
+	respShutdown, err := responder.ReceiveShutdown(*shutdown)
+	require.NoError(t, err)
+	require.True(t, respShutdown.IsSome())
+
+	noShutdown, err := initiator.ReceiveShutdown(
+		respShutdown.UnwrapOrFail(t),
+	)
+	require.NoError(t, err)
+	require.True(t, noShutdown.IsNone())
+
+	respComplete, err := responder.BeginSimpleClose()
+	require.NoError(t, err)
+	require.True(t, respComplete.IsNone())
+
+	closingComplete, err := initiator.BeginSimpleClose()
+	require.NoError(t, err)
+
+	return closingComplete
+}
+
+// signCloseProposal has the closee counter-sign the given proposal, and
+// asserts that both parties broadcast the same closing transaction, which is
+// returned.
+func signCloseProposal(t *testing.T, closer, closee *ChanCloser,
+	closerTxs, closeeTxs chan *wire.MsgTx,
+	msg lnwire.ClosingComplete) *wire.MsgTx {
+
+	t.Helper()
+
+	closingSig, err := closee.ReceiveClosingComplete(msg)
+	require.NoError(t, err)
+	closeeTx := receiveTx(t, closeeTxs)
+
+	require.NoError(t, closer.ReceiveClosingSig(closingSig.UnwrapOrFail(t)))
+	closerTx := receiveTx(t, closerTxs)
+	require.Equal(t, closeeTx.TxHash(), closerTx.TxHash())
+
+	return closerTx
+}
+
+// TestSimpleCloseRemoteInitiated tests the simple close protocol when the
+// party that didn't open the channel initiates the shutdown, and that both
+// parties can replace the closing transaction over several rounds.
+func TestSimpleCloseRemoteInitiated(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	aliceScript := p2wkhScript(t, 1)
+	bobScript := p2wkhScript(t, 2)
+
+	alice, aliceTxs := newSimpleCloser(
+		t, aliceChannel, aliceScript, lntypes.Remote,
+	)
+	bob, bobTxs := newSimpleCloser(t, bobChannel, bobScript, lntypes.Local)
+
+	// Bob initiated the shutdown, so he proposes the first closing
+	// transaction, even though Alice opened the channel.
+	closingComplete := exchangeShutdown(t, bob, alice).UnwrapOrFail(t)
+	closeTx := signCloseProposal(
+		t, bob, alice, bobTxs, aliceTxs, closingComplete,
+	)
+
+	// Bob pays the fee, while Alice receives her full balance including
+	// the commitment fee.
+	aliceBalance, bobBalance := aliceChannel.CommitBalances()
+	aliceBalance += aliceChannel.CommitFee()
+	require.Equal(t, aliceBalance, findOutput(closeTx, aliceScript))
+	require.Equal(
+		t, bobBalance-closingComplete.FeeSatoshis,
+		findOutput(closeTx, bobScript),
+	)
+
+	// Both parties now take turns replacing the closing transaction, each
+	// paying the full fee of their own proposal.
+	rounds := []struct {
+		closer, closee       *ChanCloser
+		closerTxs, closeeTxs chan *wire.MsgTx
+		closerScript         []byte
+		closerBalance        btcutil.Amount
+	}{
+		{bob, alice, bobTxs, aliceTxs, bobScript, bobBalance},
+		{alice, bob, aliceTxs, bobTxs, aliceScript, aliceBalance},
+		{bob, alice, bobTxs, aliceTxs, bobScript, bobBalance},
+		{alice, bob, aliceTxs, bobTxs, aliceScript, aliceBalance},
+	}
+	feeRate := chainfee.FeePerKwFloor
+	for i, round := range rounds {
+		feeRate *= 2
+
+		msg, txid, err := round.closer.ProposeSimpleClose(feeRate)
+		require.NoError(t, err, "round %d", i)
+
+		tx := signCloseProposal(
+			t, round.closer, round.closee, round.closerTxs,
+			round.closeeTxs, *msg,
+		)
+		require.Equal(t, *txid, tx.TxHash())
+		require.NotEqual(t, closeTx.TxHash(), tx.TxHash())
+		require.Equal(
+			t, round.closerBalance-msg.FeeSatoshis,
+			findOutput(tx, round.closerScript),
+		)
+
+		closeTx = tx
+	}
+}
+
+// TestSimpleCloseRestore tests that the latest closing transaction is
+// persisted, and that the negotiation can be resumed from it after a restart
+// or reconnect.
+func TestSimpleCloseRestore(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	aliceScript := p2wkhScript(t, 1)
+	bobScript := p2wkhScript(t, 2)
+
+	alice, aliceTxs := newSimpleCloser(
+		t, aliceChannel, aliceScript, lntypes.Local,
+	)
+	bob, bobTxs := newSimpleCloser(t, bobChannel, bobScript, lntypes.Remote)
+
+	closingComplete := exchangeShutdown(t, alice, bob).UnwrapOrFail(t)
+	signCloseProposal(t, alice, bob, aliceTxs, bobTxs, closingComplete)
+
+	// Bob replaces the closing transaction, which fails to broadcast.
+	// Both parties must keep the prior transaction persisted.
+	bobComplete, _, err := bob.ProposeSimpleClose(
+		chainfee.FeePerKwFloor * 2,
+	)
+	require.NoError(t, err)
+	alice.cfg.BroadcastTx = func(*wire.MsgTx, string) error {
+		return fmt.Errorf("rejected")
+	}
+	_, err = alice.ReceiveClosingComplete(*bobComplete)
+	require.NoError(t, err)
+
+	persistedTx, err := aliceChannel.State().BroadcastedCooperative()
+	require.NoError(t, err)
+	aliceCloseTx, err := alice.ClosingTx()
+	require.NoError(t, err)
+	require.Equal(t, aliceCloseTx.TxHash(), persistedTx.TxHash())
+
+	// Alice replaces it herself, which is broadcast successfully.
+	alice.cfg.BroadcastTx = func(tx *wire.MsgTx, _ string) error {
+		aliceTxs <- tx
+		return nil
+	}
+	aliceComplete, _, err := alice.ProposeSimpleClose(
+		chainfee.FeePerKwFloor * 3,
+	)
+	require.NoError(t, err)
+	latestTx := signCloseProposal(
+		t, alice, bob, aliceTxs, bobTxs, *aliceComplete,
+	)
+
+	// Both parties now restart, and restore the latest closing
+	// transaction from disk.
+	restore := func(channel *lnwallet.LightningChannel, script []byte,
+		closer lntypes.ChannelParty) (*ChanCloser, chan *wire.MsgTx) {
+
+		closeTx, err := channel.State().BroadcastedCooperative()
+		require.NoError(t, err)
+		require.Equal(t, latestTx.TxHash(), closeTx.TxHash())
+
+		channel.ResetState()
+		chanCloser, txs := newSimpleCloser(t, channel, script, closer)
+		require.NoError(t, chanCloser.RestoreSimpleClose(closeTx))
+
+		return chanCloser, txs
+	}
+	alice, aliceTxs = restore(aliceChannel, aliceScript, lntypes.Local)
+	bob, bobTxs = restore(bobChannel, bobScript, lntypes.Remote)
+
+	// After the shutdown messages are exchanged again, Alice doesn't
+	// propose a new closing transaction, as the restored one is still
+	// valid.
+	require.True(t, exchangeShutdown(t, alice, bob).IsNone())
+
+	closeTx, err := alice.ClosingTx()
+	require.NoError(t, err)
+	require.Equal(t, latestTx.TxHash(), closeTx.TxHash())
+
+	// A replacement must pay a higher fee than the restored transaction.
+	_, _, err = alice.ProposeSimpleClose(chainfee.FeePerKwFloor * 3)
+	require.ErrorIs(t, err, ErrCloseFeeTooLow)
+
+	aliceComplete, txid, err := alice.ProposeSimpleClose(
+		chainfee.FeePerKwFloor * 4,
+	)
+	require.NoError(t, err)
+	bumpTx := signCloseProposal(
+		t, alice, bob, aliceTxs, bobTxs, *aliceComplete,
+	)
+	require.Equal(t, *txid, bumpTx.TxHash())
+
+	persistedTx, err = bobChannel.State().BroadcastedCooperative()
+	require.NoError(t, err)
+	require.Equal(t, bumpTx.TxHash(), persistedTx.TxHash())
+}
+
+// TestSimpleCloseErrors tests that invalid simple close operations and
+// messages are rejected.
+func TestSimpleCloseErrors(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	aliceScript := p2wkhScript(t, 1)
+	bobScript := p2wkhScript(t, 2)
+
+	alice, _ := newSimpleCloser(t, aliceChannel, aliceScript, lntypes.Local)
+	bob, _ := newSimpleCloser(t, bobChannel, bobScript, lntypes.Remote)
+
+	// No closing transaction can be proposed before the shutdown.
+	_, _, err = alice.ProposeSimpleClose(chainfee.FeePerKwFloor)
+	require.ErrorIs(t, err, ErrInvalidState)
+
+	closingComplete := exchangeShutdown(t, alice, bob).UnwrapOrFail(t)
+
+	// A closing transaction can't be restored once the negotiation has
+	// started.
+	err = bob.RestoreSimpleClose(wire.NewMsgTx(2))
+	require.ErrorIs(t, err, ErrInvalidState)
+
+	// The closer can't pay more than its balance.
+	_, _, err = bob.ProposeSimpleClose(chainfee.SatPerKWeight(1e10))
+	require.ErrorContains(t, err, "cannot pay close fee")
+
+	// Nor can the remote party.
+	tooHigh := closingComplete
+	tooHigh.FeeSatoshis = btcutil.SatoshiPerBitcoin * 100
+	_, err = bob.ReceiveClosingComplete(tooHigh)
+	require.ErrorContains(t, err, "cannot pay close fee")
+
+	// A signature for a closing transaction without Bob's output is
+	// rejected, as his output isn't dust.
+	sig, err := closingComplete.CloserAndClosee.UnwrapOrErrV(
+		ErrMissingClosingSig,
+	)
+	require.NoError(t, err)
+	wrongType := closingComplete
+	wrongType.ClosingSigs = lnwire.ClosingSigs{}
+	closerNoClosee.setSig(&wrongType.ClosingSigs, sig)
+	_, err = bob.ReceiveClosingComplete(wrongType)
+	require.ErrorIs(t, err, ErrMissingClosingSig)
+
+	// A signature that doesn't match the proposed fee is invalid.
+	wrongFee := closingComplete
+	wrongFee.FeeSatoshis++
+	_, err = bob.ReceiveClosingComplete(wrongFee)
+	require.Error(t, err)
+
+	// A channel that didn't negotiate the simple close protocol rejects
+	// its messages.
+	legacy, _ := newSimpleCloser(t, bobChannel, bobScript, lntypes.Remote)
+	legacy.cfg.SimpleClose = false
+	_, err = legacy.ReceiveClosingComplete(closingComplete)
+	require.ErrorIs(t, err, ErrSimpleCloseNotNegotiated)
+	err = legacy.ReceiveClosingSig(lnwire.ClosingSig{})
+	require.ErrorIs(t, err, ErrSimpleCloseNotNegotiated)
+	_, err = legacy.BeginSimpleClose()
+	require.ErrorIs(t, err, ErrSimpleCloseNotNegotiated)
+
+	// Taproot channels keep using the legacy negotiation.
+	taprootChannel, _, err := lnwallet.CreateTestChannels(
+		t, channeldb.SimpleTaprootFeatureBit,
+	)
+	require.NoError(t, err)
+
+	taproot, _ := newSimpleCloser(
+		t, taprootChannel, aliceScript, lntypes.Local,
+	)
+	_, err = taproot.BeginSimpleClose()
+	require.ErrorIs(t, err, ErrSimpleCloseTaproot)
+	_, _, err = taproot.ProposeSimpleClose(chainfee.FeePerKwFloor)
+	require.ErrorIs(t, err, ErrSimpleCloseTaproot)
+	_, err = taproot.ReceiveClosingComplete(closingComplete)
+	require.ErrorIs(t, err, ErrSimpleCloseTaproot)
+	err = taproot.RestoreSimpleClose(wire.NewMsgTx(2))
+	require.ErrorIs(t, err, ErrSimpleCloseTaproot)
+}
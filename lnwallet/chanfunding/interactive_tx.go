@@ -0,0 +1,354 @@
+package chanfunding
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+const (
+	// MaxInteractiveTxInputs is the maximum number of inputs a transaction
+	// that is constructed interactively may have.
+	MaxInteractiveTxInputs = 252
+
+	// MaxInteractiveTxOutputs is the maximum number of outputs a
+	// transaction that is constructed interactively may have.
+	MaxInteractiveTxOutputs = 252
+
+	// MaxInteractiveTxUpdates is the maximum number of inputs and outputs
+	// a single party may add during an interactive transaction
+	// construction. This bounds the length of the negotiation.
+	MaxInteractiveTxUpdates = 4096
+)
+
+var (
+	// ErrInvalidSerialID is returned when a party uses a serial ID with a
+	// parity that doesn't match its role in the negotiation.
+	ErrInvalidSerialID = errors.New("serial id has invalid parity")
+
+	// ErrDuplicateSerialID is returned when a serial ID is added twice.
+	ErrDuplicateSerialID = errors.New("serial id already in use")
+
+	// ErrUnknownSerialID is returned when a party attempts to remove an
+	// input or output that doesn't exist.
+	ErrUnknownSerialID = errors.New("unknown serial id")
+
+	// ErrDuplicateInput is returned when the same previous outpoint is
+	// added as an input twice.
+	ErrDuplicateInput = errors.New("input already added")
+
+	// ErrTooManyInputs is returned when adding an input would exceed
+	// MaxInteractiveTxInputs.
+	ErrTooManyInputs = errors.New("too many inputs")
+
+	// ErrTooManyOutputs is returned when adding an output would exceed
+	// MaxInteractiveTxOutputs.
+	ErrTooManyOutputs = errors.New("too many outputs")
+
+	// ErrTooManyUpdates is returned when a party exceeds
+	// MaxInteractiveTxUpdates.
+	ErrTooManyUpdates = errors.New("too many interactive tx updates")
+
+	// ErrInteractiveTxComplete is returned when the transaction is
+	// modified after the negotiation has already completed.
+	ErrInteractiveTxComplete = errors.New("interactive tx negotiation " +
+		"already complete")
+)
+
+// InteractiveTxInput is an input added to a transaction that is being
+// constructed interactively.
+type InteractiveTxInput struct {
+	// SerialID is the identifier of the input chosen by the party that
+	// added it. Inputs are ordered by their serial ID in the final
+	// transaction.
+	SerialID uint64
+
+	// OutPoint is the previous outpoint spent by the input.
+	OutPoint wire.OutPoint
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// PrevOut is the previous output spent by the input.
+	PrevOut wire.TxOut
+
+	// Shared is true if the input spends an output that is owned by both
+	// parties, such as the funding output of a channel being spliced.
+	Shared bool
+}
+
+// InteractiveTxOutput is an output added to a transaction that is being
+// constructed interactively.
+type InteractiveTxOutput struct {
+	// SerialID is the identifier of the output chosen by the party that
+	// added it. Outputs are ordered by their serial ID in the final
+	// transaction.
+	SerialID uint64
+
+	// TxOut is the output itself.
+	TxOut wire.TxOut
+}
+
+// InteractiveTx tracks the state of a transaction that is collaboratively
+// constructed by two parties as described by the interactive transaction
+// construction protocol. Both parties take turns adding or removing inputs and
+// outputs, which they identify with serial IDs of a parity determined by their
+// role: the initiator of the negotiation uses even serial IDs, the other party
+// odd ones. The negotiation ends once both parties signal completion one after
+// another.
+type InteractiveTx struct {
+	// initiator is the party that initiated the negotiation.
+	initiator lntypes.ChannelParty
+
+	// version and lockTime are the version and lock time of the final
+	// transaction.
+	version  int32
+	lockTime uint32
+
+	inputs  map[uint64]*InteractiveTxInput
+	outputs map[uint64]*InteractiveTxOutput
+
+	// numUpdates tracks the number of inputs and outputs each party added
+	// during the negotiation.
+	numUpdates lntypes.Dual[int]
+
+	// completed tracks which parties have signaled completion since the
+	// transaction was last modified.
+	completed lntypes.Dual[bool]
+}
+
+// NewInteractiveTx creates a new, empty InteractiveTx for a negotiation
+// started by the given party.
+func NewInteractiveTx(initiator lntypes.ChannelParty,
+	lockTime uint32) *InteractiveTx {
+
+	return &InteractiveTx{
+		initiator: initiator,
+		version:   2,
+		lockTime:  lockTime,
+		inputs:    make(map[uint64]*InteractiveTxInput),
+		outputs:   make(map[uint64]*InteractiveTxOutput),
+	}
+}
+
+// SerialIDParty returns the party that is allowed to use the given serial ID.
+func (t *InteractiveTx) SerialIDParty(serialID uint64) lntypes.ChannelParty {
+	if serialID%2 == 0 {
+		return t.initiator
+	}
+
+	return t.initiator.CounterParty()
+}
+
+// checkUpdate makes sure that the given party may add or remove the input or
+// output with the given serial ID.
+func (t *InteractiveTx) checkUpdate(party lntypes.ChannelParty,
+	serialID uint64) error {
+
+	if t.IsComplete() {
+		return ErrInteractiveTxComplete
+	}
+
+	if t.SerialIDParty(serialID) != party {
+		return fmt.Errorf("%w: %d used by %v", ErrInvalidSerialID,
+			serialID, party)
+	}
+
+	return nil
+}
+
+// recordAdd accounts for an input or output added by the given party.
+func (t *InteractiveTx) recordAdd(party lntypes.ChannelParty) error {
+	numUpdates := t.numUpdates.GetForParty(party)
+	if numUpdates >= MaxInteractiveTxUpdates {
+		return ErrTooManyUpdates
+	}
+	t.numUpdates.SetForParty(party, numUpdates+1)
+
+	t.completed = lntypes.Dual[bool]{}
+
+	return nil
+}
+
+// AddInput adds an input on behalf of the given party.
+func (t *InteractiveTx) AddInput(party lntypes.ChannelParty,
+	in InteractiveTxInput) error {
+
+	if err := t.checkUpdate(party, in.SerialID); err != nil {
+		return err
+	}
+
+	if _, ok := t.inputs[in.SerialID]; ok {
+		return fmt.Errorf("%w: input %d", ErrDuplicateSerialID,
+			in.SerialID)
+	}
+	for _, existing := range t.inputs {
+		if existing.OutPoint == in.OutPoint {
+			return fmt.Errorf("%w: %v", ErrDuplicateInput,
+				in.OutPoint)
+		}
+	}
+	if len(t.inputs) >= MaxInteractiveTxInputs {
+		return ErrTooManyInputs
+	}
+
+	if err := t.recordAdd(party); err != nil {
+		return err
+	}
+	t.inputs[in.SerialID] = &in
+
+	return nil
+}
+
+// RemoveInput removes an input that was previously added by the given party.
+func (t *InteractiveTx) RemoveInput(party lntypes.ChannelParty,
+	serialID uint64) error {
+
+	if err := t.checkUpdate(party, serialID); err != nil {
+		return err
+	}
+
+	if _, ok := t.inputs[serialID]; !ok {
+		return fmt.Errorf("%w: input %d", ErrUnknownSerialID, serialID)
+	}
+	delete(t.inputs, serialID)
+
+	t.completed = lntypes.Dual[bool]{}
+
+	return nil
+}
+
+// AddOutput adds an output on behalf of the given party.
+func (t *InteractiveTx) AddOutput(party lntypes.ChannelParty,
+	out InteractiveTxOutput) error {
+
+	if err := t.checkUpdate(party, out.SerialID); err != nil {
+		return err
+	}
+
+	if _, ok := t.outputs[out.SerialID]; ok {
+		return fmt.Errorf("%w: output %d", ErrDuplicateSerialID,
+			out.SerialID)
+	}
+	if len(t.outputs) >= MaxInteractiveTxOutputs {
+		return ErrTooManyOutputs
+	}
+
+	if err := t.recordAdd(party); err != nil {
+		return err
+	}
+	t.outputs[out.SerialID] = &out
+
+	return nil
+}
+
+// RemoveOutput removes an output that was previously added by the given
+// party.
+func (t *InteractiveTx) RemoveOutput(party lntypes.ChannelParty,
+	serialID uint64) error {
+
+	if err := t.checkUpdate(party, serialID); err != nil {
+		return err
+	}
+
+	if _, ok := t.outputs[serialID]; !ok {
+		return fmt.Errorf("%w: output %d", ErrUnknownSerialID,
+			serialID)
+	}
+	delete(t.outputs, serialID)
+
+	t.completed = lntypes.Dual[bool]{}
+
+	return nil
+}
+
+// Complete records that the given party signaled completion of the
+// negotiation. It returns true if the negotiation is now complete, which is
+// the case once both parties signaled completion without modifying the
+// transaction in between.
+func (t *InteractiveTx) Complete(party lntypes.ChannelParty) bool {
+	t.completed.SetForParty(party, true)
+
+	return t.IsComplete()
+}
+
+// IsComplete returns true once both parties have signaled completion of the
+// negotiation.
+func (t *InteractiveTx) IsComplete() bool {
+	return t.completed.Local && t.completed.Remote
+}
+
+// Inputs returns all inputs of the transaction ordered by their serial ID.
+func (t *InteractiveTx) Inputs() []*InteractiveTxInput {
+	inputs := make([]*InteractiveTxInput, 0, len(t.inputs))
+	for _, in := range t.inputs {
+		inputs = append(inputs, in)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].SerialID < inputs[j].SerialID
+	})
+
+	return inputs
+}
+
+// Outputs returns all outputs of the transaction ordered by their serial ID.
+func (t *InteractiveTx) Outputs() []*InteractiveTxOutput {
+	outputs := make([]*InteractiveTxOutput, 0, len(t.outputs))
+	for _, out := range t.outputs {
+		outputs = append(outputs, out)
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].SerialID < outputs[j].SerialID
+	})
+
+	return outputs
+}
+
+// Contribution returns the value the given party contributed to the
+// transaction, which is the total value of its inputs minus the total value of
+// its outputs. Shared inputs and outputs matching the passed predicate aren't
+// attributed to either party.
+func (t *InteractiveTx) Contribution(party lntypes.ChannelParty,
+	isShared func(*wire.TxOut) bool) btcutil.Amount {
+
+	var contribution btcutil.Amount
+	for _, in := range t.inputs {
+		if in.Shared || t.SerialIDParty(in.SerialID) != party {
+			continue
+		}
+		contribution += btcutil.Amount(in.PrevOut.Value)
+	}
+	for _, out := range t.outputs {
+		if isShared(&out.TxOut) ||
+			t.SerialIDParty(out.SerialID) != party {
+
+			continue
+		}
+		contribution -= btcutil.Amount(out.TxOut.Value)
+	}
+
+	return contribution
+}
+
+// Tx returns the unsigned transaction resulting from the negotiation, with its
+// inputs and outputs ordered by their serial IDs.
+func (t *InteractiveTx) Tx() *wire.MsgTx {
+	tx := wire.NewMsgTx(t.version)
+	tx.LockTime = t.lockTime
+
+	for _, in := range t.Inputs() {
+		txIn := wire.NewTxIn(&in.OutPoint, nil, nil)
+		txIn.Sequence = in.Sequence
+		tx.AddTxIn(txIn)
+	}
+	for _, out := range t.Outputs() {
+		txOut := out.TxOut
+		tx.AddTxOut(&txOut)
+	}
+
+	return tx
+}
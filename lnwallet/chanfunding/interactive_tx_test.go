@@ -0,0 +1,134 @@
+package chanfunding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// testInput returns an input with the given serial ID spending a unique
+// outpoint of the given value.
+func testInput(serialID uint64, value int64) InteractiveTxInput {
+	return InteractiveTxInput{
+		SerialID: serialID,
+		OutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{byte(serialID)},
+			Index: uint32(serialID),
+		},
+		Sequence: wire.MaxTxInSequenceNum - 2,
+		PrevOut:  wire.TxOut{Value: value},
+	}
+}
+
+// TestInteractiveTxSerialIDs tests that serial IDs must match the parity of
+// the party using them and can't be reused.
+func TestInteractiveTxSerialIDs(t *testing.T) {
+	t.Parallel()
+
+	tx := NewInteractiveTx(lntypes.Local, 0)
+
+	// The initiator must use even serial IDs, the other party odd ones.
+	err := tx.AddInput(lntypes.Local, testInput(1, 1000))
+	require.ErrorIs(t, err, ErrInvalidSerialID)
+	err = tx.AddInput(lntypes.Remote, testInput(2, 1000))
+	require.ErrorIs(t, err, ErrInvalidSerialID)
+
+	require.NoError(t, tx.AddInput(lntypes.Local, testInput(0, 1000)))
+	require.NoError(t, tx.AddInput(lntypes.Remote, testInput(1, 1000)))
+
+	// Serial IDs and outpoints can't be reused.
+	err = tx.AddInput(lntypes.Local, testInput(0, 1000))
+	require.ErrorIs(t, err, ErrDuplicateSerialID)
+
+	dupInput := testInput(2, 1000)
+	dupInput.OutPoint = testInput(1, 0).OutPoint
+	err = tx.AddInput(lntypes.Local, dupInput)
+	require.ErrorIs(t, err, ErrDuplicateInput)
+
+	// A party can only remove its own, existing inputs and outputs.
+	err = tx.RemoveInput(lntypes.Local, 1)
+	require.ErrorIs(t, err, ErrInvalidSerialID)
+	err = tx.RemoveInput(lntypes.Local, 4)
+	require.ErrorIs(t, err, ErrUnknownSerialID)
+	err = tx.RemoveOutput(lntypes.Remote, 3)
+	require.ErrorIs(t, err, ErrUnknownSerialID)
+
+	require.NoError(t, tx.RemoveInput(lntypes.Remote, 1))
+	require.Len(t, tx.Inputs(), 1)
+}
+
+// TestInteractiveTxCompletion tests that the negotiation only completes once
+// both parties signal completion without modifying the transaction in between.
+func TestInteractiveTxCompletion(t *testing.T) {
+	t.Parallel()
+
+	tx := NewInteractiveTx(lntypes.Remote, 100)
+
+	require.NoError(t, tx.AddInput(lntypes.Remote, testInput(0, 5000)))
+	require.False(t, tx.Complete(lntypes.Local))
+
+	// Modifying the transaction resets the completion of the other party.
+	err := tx.AddOutput(lntypes.Remote, InteractiveTxOutput{
+		SerialID: 2,
+		TxOut:    wire.TxOut{Value: 4000},
+	})
+	require.NoError(t, err)
+	require.False(t, tx.Complete(lntypes.Remote))
+	require.False(t, tx.IsComplete())
+
+	require.True(t, tx.Complete(lntypes.Local))
+
+	// No further modifications are allowed once complete.
+	err = tx.AddInput(lntypes.Local, testInput(1, 1000))
+	require.ErrorIs(t, err, ErrInteractiveTxComplete)
+}
+
+// TestInteractiveTxBuild tests that the final transaction orders inputs and
+// outputs by their serial ID and attributes contributions correctly.
+func TestInteractiveTxBuild(t *testing.T) {
+	t.Parallel()
+
+	tx := NewInteractiveTx(lntypes.Local, 500)
+
+	shared := testInput(4, 100_000)
+	shared.Shared = true
+	require.NoError(t, tx.AddInput(lntypes.Local, shared))
+	require.NoError(t, tx.AddInput(lntypes.Local, testInput(0, 20_000)))
+	require.NoError(t, tx.AddInput(lntypes.Remote, testInput(3, 10_000)))
+
+	sharedOut := wire.TxOut{Value: 125_000, PkScript: []byte{1}}
+	require.NoError(t, tx.AddOutput(lntypes.Remote, InteractiveTxOutput{
+		SerialID: 1,
+		TxOut:    wire.TxOut{Value: 4_000, PkScript: []byte{2}},
+	}))
+	require.NoError(t, tx.AddOutput(lntypes.Local, InteractiveTxOutput{
+		SerialID: 2,
+		TxOut:    sharedOut,
+	}))
+
+	msgTx := tx.Tx()
+	require.EqualValues(t, 2, msgTx.Version)
+	require.EqualValues(t, 500, msgTx.LockTime)
+
+	require.Len(t, msgTx.TxIn, 3)
+	require.Equal(t, testInput(0, 0).OutPoint,
+		msgTx.TxIn[0].PreviousOutPoint)
+	require.Equal(t, testInput(3, 0).OutPoint,
+		msgTx.TxIn[1].PreviousOutPoint)
+	require.Equal(t, shared.OutPoint, msgTx.TxIn[2].PreviousOutPoint)
+
+	require.Len(t, msgTx.TxOut, 2)
+	require.EqualValues(t, 4_000, msgTx.TxOut[0].Value)
+	require.EqualValues(t, 125_000, msgTx.TxOut[1].Value)
+
+	isShared := func(out *wire.TxOut) bool {
+		return out.Value == sharedOut.Value
+	}
+	require.EqualValues(t, 20_000, tx.Contribution(lntypes.Local, isShared))
+	require.EqualValues(
+		t, 6_000, tx.Contribution(lntypes.Remote, isShared),
+	)
+}
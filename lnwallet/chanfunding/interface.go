@@ -9,6 +9,7 @@ import (
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
@@ -126,6 +127,39 @@ type Request struct {
 	// create the funding output. This field will only be utilized if the
 	// Musig2 flag above is set to true.
 	TapscriptRoot fn.Option[chainhash.Hash]
+
+	// SharedInput is the current funding output of a channel that is being
+	// spliced. If set, the funding transaction spends it in addition to
+	// the inputs contributed by the Assembler, and RemoteAmt should carry
+	// its value so that the new funding output reflects the new channel
+	// capacity.
+	SharedInput fn.Option[SharedInput]
+
+	// SkipFundingOutputFee should be set if the remote party pays for the
+	// funding output of the transaction, as is the case for the accepting
+	// side of a dual-funded channel. Coin selection then only accounts for
+	// the inputs and change output we contribute.
+	SkipFundingOutputFee bool
+}
+
+// SharedInput is an input to a splice transaction that spends the existing
+// funding output of a channel. It can only be signed cooperatively by both
+// channel parties, so Assemblers leave it unsigned.
+type SharedInput struct {
+	// OutPoint is the current funding outpoint of the channel.
+	OutPoint wire.OutPoint
+
+	// TxOut is the current funding output of the channel.
+	TxOut wire.TxOut
+
+	// WitnessSize is the expected size of the witness that spends the
+	// funding output, which is used for fee estimation.
+	WitnessSize lntypes.WeightUnit
+}
+
+// TxIn returns the shared input as an unsigned transaction input.
+func (s *SharedInput) TxIn() *wire.TxIn {
+	return wire.NewTxIn(&s.OutPoint, nil, nil)
 }
 
 // Intent is returned by an Assembler and represents the base functionality the
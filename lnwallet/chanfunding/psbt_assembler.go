@@ -1,6 +1,7 @@
 package chanfunding
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"sync"
@@ -153,6 +154,11 @@ type PsbtIntent struct {
 	// netParams are the network parameters used to encode the P2WSH funding
 	// address.
 	netParams *chaincfg.Params
+
+	// sharedInput is the funding output of the channel being spliced, if
+	// any. It is added to the PSBT, but can only be signed by both channel
+	// parties later on, so the finalize step skips it.
+	sharedInput fn.Option[SharedInput]
 }
 
 // BindKeys sets both the remote and local node's keys that will be used for the
@@ -219,6 +225,18 @@ func (i *PsbtIntent) FundingParams() (btcutil.Address, int64, *psbt.Packet,
 	}
 	packet.UnsignedTx.TxOut = append(packet.UnsignedTx.TxOut, out)
 
+	// When splicing, the current funding output of the channel is also
+	// spent. We add it along with its UTXO information, so the user's
+	// wallet can account for its value when funding the PSBT.
+	i.sharedInput.WhenSome(func(in SharedInput) {
+		packet.UnsignedTx.TxIn = append(
+			packet.UnsignedTx.TxIn, in.TxIn(),
+		)
+		packet.Inputs = append(packet.Inputs, psbt.PInput{
+			WitnessUtxo: &in.TxOut,
+		})
+	})
+
 	var pOut psbt.POutput
 
 	// If this is a MuSig2 channel, we also need to communicate the internal
@@ -321,6 +339,18 @@ func (i *PsbtIntent) Finalize(packet *psbt.Packet) error {
 			PsbtVerified)
 	}
 
+	// A shared input can't be signed by the user, so we'll finalize and
+	// extract the remaining inputs ourselves.
+	if i.sharedInput.IsSome() {
+		rawTx, err := i.extractSpliceTx(packet)
+		if err != nil {
+			return fmt.Errorf("unable to extract splice TX: %w",
+				err)
+		}
+
+		return i.FinalizeRawTX(rawTx)
+	}
+
 	// Make sure the PSBT itself thinks it's finalized and ready to be
 	// broadcast.
 	err := psbt.MaybeFinalizeAll(packet)
@@ -335,6 +365,78 @@ func (i *PsbtIntent) Finalize(packet *psbt.Packet) error {
 	return i.FinalizeRawTX(rawTx)
 }
 
+// extractSpliceTx finalizes all inputs of the passed PSBT except for the
+// shared input, and extracts the resulting transaction. The shared input is
+// left without any witness data.
+func (i *PsbtIntent) extractSpliceTx(packet *psbt.Packet) (*wire.MsgTx,
+	error) {
+
+	rawTx := packet.UnsignedTx.Copy()
+	for idx, txIn := range rawTx.TxIn {
+		if i.isSharedInput(txIn) {
+			continue
+		}
+
+		_, err := psbt.MaybeFinalize(packet, idx)
+		if err != nil {
+			return nil, fmt.Errorf("error finalizing input %d: %w",
+				idx, err)
+		}
+
+		pIn := packet.Inputs[idx]
+		txIn.SignatureScript = pIn.FinalScriptSig
+
+		if len(pIn.FinalScriptWitness) == 0 {
+			continue
+		}
+
+		txIn.Witness, err = parseWitness(pIn.FinalScriptWitness)
+		if err != nil {
+			return nil, fmt.Errorf("invalid witness for input "+
+				"%d: %w", idx, err)
+		}
+	}
+
+	return rawTx, nil
+}
+
+// isSharedInput returns true if the passed transaction input spends the shared
+// input of a splice.
+func (i *PsbtIntent) isSharedInput(txIn *wire.TxIn) bool {
+	return fn.MapOptionZ(i.sharedInput, func(in SharedInput) bool {
+		return txIn.PreviousOutPoint == in.OutPoint
+	})
+}
+
+// parseWitness parses a witness stack that is serialized in the format used by
+// the PSBT final script witness field.
+func parseWitness(rawWitness []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(rawWitness)
+
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each witness item is at least one byte long, which allows us to
+	// sanity check the number of items before allocating.
+	if numItems > uint64(len(rawWitness)) {
+		return nil, fmt.Errorf("too many witness items: %d", numItems)
+	}
+
+	witness := make(wire.TxWitness, numItems)
+	for idx := range witness {
+		witness[idx], err = wire.ReadVarBytes(
+			r, 0, txscript.MaxScriptSize, "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return witness, nil
+}
+
 // FinalizeRawTX makes sure the final raw transaction that is given to the
 // intent is fully valid and signed but still contains the same UTXOs and
 // outputs as the pending transaction we previously verified. If everything
@@ -373,7 +475,12 @@ func (i *PsbtIntent) FinalizeRawTX(rawTx *wire.MsgTx) error {
 	// We also check that we have a signed TX. This is only necessary if the
 	// FinalizeRawTX is called directly with a wire format TX instead of
 	// extracting the TX from a PSBT.
-	err = verifyInputsSigned(rawTx.TxIn)
+	//
+	// The shared input of a splice is the exception, as it's only signed
+	// once the splice has been negotiated with the remote peer.
+	err = verifyInputsSigned(fn.Filter(func(txIn *wire.TxIn) bool {
+		return !i.isSharedInput(txIn)
+	}, rawTx.TxIn))
 	if err != nil {
 		return fmt.Errorf("inputs not signed: %w", err)
 	}
@@ -490,6 +597,12 @@ func (i *PsbtIntent) Outputs() []*wire.TxOut {
 	}
 }
 
+// SharedInput returns the funding output of the channel being spliced, if this
+// intent was created for a splice.
+func (i *PsbtIntent) SharedInput() fn.Option[SharedInput] {
+	return i.sharedInput
+}
+
 // ShouldPublishFundingTX returns true if the intent assumes that its assembler
 // should publish the funding TX once the funding negotiation is complete.
 func (i *PsbtIntent) ShouldPublishFundingTX() bool {
@@ -562,6 +675,7 @@ func (p *PsbtAssembler) ProvisionChannel(req *Request) (Intent, error) {
 		PsbtReady:     make(chan error, 1),
 		shouldPublish: p.shouldPublish,
 		netParams:     p.netParams,
+		sharedInput:   req.SharedInput,
 	}
 
 	// A simple sanity check to ensure the provisioned request matches the
@@ -12,8 +12,10 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
 const (
@@ -62,6 +64,10 @@ type FullIntent struct {
 	// change from the main funding transaction.
 	ChangeOutputs []*wire.TxOut
 
+	// SharedInput is the funding output of the channel being spliced, if
+	// any. It is added to the funding transaction, but left unsigned.
+	SharedInput fn.Option[SharedInput]
+
 	// coinLeaser is the Assembler's instance of the OutputLeaser
 	// interface.
 	coinLeaser OutputLeaser
@@ -106,6 +112,9 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 	for _, theirInput := range extraInputs {
 		fundingTx.AddTxIn(theirInput)
 	}
+	f.SharedInput.WhenSome(func(in SharedInput) {
+		fundingTx.AddTxIn(in.TxIn())
+	})
 	for _, ourChangeOutput := range f.ChangeOutputs {
 		fundingTx.AddTxOut(ourChangeOutput)
 	}
@@ -136,12 +145,15 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 	prevOutFetcher := NewSegWitV0DualFundingPrevOutputFetcher(
 		f.coinSource, extraInputs,
 	)
+
+	// The previous output of a shared input is known exactly, so we add
+	// it as well. This allows us to also sign taproot inputs of our own
+	// when splicing.
+	f.SharedInput.WhenSome(func(in SharedInput) {
+		prevOutFetcher.remote.AddPrevOut(in.OutPoint, &in.TxOut)
+	})
 	sigHashes := txscript.NewTxSigHashes(fundingTx, prevOutFetcher)
 	for i, txIn := range fundingTx.TxIn {
-		signDesc := input.SignDescriptor{
-			SigHashes:         sigHashes,
-			PrevOutputFetcher: prevOutFetcher,
-		}
 		// We can only sign this input if it's ours, so we'll ask the
 		// coin source if it can map this outpoint into a coin we own.
 		// If not, then we'll continue as it isn't our input.
@@ -152,42 +164,186 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 			continue
 		}
 
-		// Now that we know the input is ours, we'll populate the
-		// signDesc with the per input unique information.
-		signDesc.Output = &wire.TxOut{
-			Value:    info.Value,
-			PkScript: info.PkScript,
+		err = f.signInput(
+			fundingTx, i, &info.TxOut, sigHashes, prevOutFetcher,
+		)
+		if err != nil {
+			return nil, err
 		}
-		signDesc.InputIndex = i
+	}
+
+	// Finally, we'll populate the chanPoint now that we've fully
+	// constructed the funding transaction.
+	f.chanPoint = &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: multiSigIndex,
+	}
+
+	return fundingTx, nil
+}
+
+// signInput signs the input at the given index of the funding transaction,
+// which spends the passed output of our wallet, and populates its witness and
+// sigScript (if needed).
+func (f *FullIntent) signInput(fundingTx *wire.MsgTx, idx int,
+	prevOut *wire.TxOut, sigHashes *txscript.TxSigHashes,
+	prevOutFetcher txscript.PrevOutputFetcher) error {
+
+	signDesc := input.SignDescriptor{
+		Output:            prevOut,
+		InputIndex:        idx,
+		SigHashes:         sigHashes,
+		PrevOutputFetcher: prevOutFetcher,
+	}
+
+	// We support spending a p2tr input ourselves. But not as part of their
+	// inputs.
+	signDesc.HashType = txscript.SigHashAll
+	if txscript.IsPayToTaproot(prevOut.PkScript) {
+		signDesc.HashType = txscript.SigHashDefault
+	}
+
+	inputScript, err := f.signer.ComputeInputScript(fundingTx, &signDesc)
+	if err != nil {
+		return err
+	}
+
+	fundingTx.TxIn[idx].SignatureScript = inputScript.SigScript
+	fundingTx.TxIn[idx].Witness = inputScript.Witness
+
+	return nil
+}
+
+// SetRemoteFundingAmt updates the amount the remote party contributes to the
+// funding output. This is used for funding transactions that are constructed
+// interactively, where the amount the remote party contributes is only known
+// after the intent has been created.
+func (f *FullIntent) SetRemoteFundingAmt(amt btcutil.Amount) {
+	f.remoteFundingAmt = amt
+}
+
+// UpdateChange recomputes the change of the intent for a funding transaction
+// that spends the same coins at the given fee rate, such as a replacement of an
+// interactively constructed funding transaction. If payFundingOutput is true,
+// the fee for the funding output is paid by us. The change output is removed
+// if the remaining change would be dust.
+func (f *FullIntent) UpdateChange(feeRate chainfee.SatPerKWeight,
+	dustLimit btcutil.Amount, payFundingOutput bool) error {
+
+	// Without any coins of our own, there's no fee for us to pay.
+	if len(f.InputCoins) == 0 {
+		return nil
+	}
 
-		// We support spending a p2tr input ourselves. But not as part
-		// of their inputs.
-		signDesc.HashType = txscript.SigHashAll
-		if txscript.IsPayToTaproot(info.PkScript) {
-			signDesc.HashType = txscript.SigHashDefault
+	var fundingOutputWeight input.TxWeightEstimator
+	if payFundingOutput {
+		fundingOutputWeight.AddP2WSHOutput()
+	}
+
+	feeNoChange, feeWithChange, err := calculateFees(
+		f.InputCoins, feeRate, fundingOutputWeight, P2TRChangeAddress,
+	)
+	if err != nil {
+		return err
+	}
+
+	var totalIn btcutil.Amount
+	for _, coin := range f.InputCoins {
+		totalIn += btcutil.Amount(coin.Value)
+	}
+
+	changeAmt, needMore, err := CalculateChangeAmount(
+		totalIn, f.localFundingAmt, feeNoChange, feeWithChange,
+		dustLimit, P2TRChangeAddress, DefaultMaxFeeRatio,
+	)
+	if err != nil {
+		return err
+	}
+	if needMore != 0 {
+		return &ErrInsufficientFunds{needMore, totalIn}
+	}
+
+	switch {
+	case changeAmt == 0:
+		f.ChangeOutputs = nil
+
+	// We can't create a change output that didn't exist before, as we
+	// don't have a change address anymore. This only happens if the fee
+	// rate is lowered, which isn't allowed for replacements.
+	case len(f.ChangeOutputs) == 0:
+		return fmt.Errorf("no change output to hold change of %v",
+			changeAmt)
+
+	default:
+		f.ChangeOutputs = []*wire.TxOut{{
+			Value:    int64(changeAmt),
+			PkScript: f.ChangeOutputs[0].PkScript,
+		}}
+	}
+
+	return nil
+}
+
+// SignFundingTx signs the inputs we contributed to a funding transaction that
+// was constructed interactively with the remote party, such as a splice
+// transaction. The passed fetcher must be able to return the previous
+// outputs of all inputs of the transaction. Only the coins selected by this
+// intent are signed, even if the remote party added other inputs that belong
+// to our wallet. BindKeys must be called before this method, and the
+// transaction must contain the funding output.
+func (f *FullIntent) SignFundingTx(fundingTx *wire.MsgTx,
+	prevOutFetcher txscript.PrevOutputFetcher) error {
+
+	_, fundingOutput, err := f.FundingOutput()
+	if err != nil {
+		return err
+	}
+
+	found, multiSigIndex := input.FindScriptOutputIndex(
+		fundingTx, fundingOutput.PkScript,
+	)
+	if !found {
+		return fmt.Errorf("funding output not found in transaction")
+	}
+	if fundingTx.TxOut[multiSigIndex].Value != fundingOutput.Value {
+		return fmt.Errorf("funding output has value %v, expected %v",
+			fundingTx.TxOut[multiSigIndex].Value,
+			fundingOutput.Value)
+	}
+
+	ourCoins := make(map[wire.OutPoint]*wire.TxOut, len(f.InputCoins))
+	for i := range f.InputCoins {
+		coin := f.InputCoins[i]
+		ourCoins[coin.OutPoint] = &coin.TxOut
+	}
+
+	sigHashes := txscript.NewTxSigHashes(fundingTx, prevOutFetcher)
+	for i, txIn := range fundingTx.TxIn {
+		prevOut, ok := ourCoins[txIn.PreviousOutPoint]
+		if !ok {
+			continue
 		}
 
-		// Finally, we'll sign the input as is, and populate the input
-		// with the witness and sigScript (if needed).
-		inputScript, err := f.signer.ComputeInputScript(
-			fundingTx, &signDesc,
+		err := f.signInput(
+			fundingTx, i, prevOut, sigHashes, prevOutFetcher,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		delete(ourCoins, txIn.PreviousOutPoint)
+	}
 
-		txIn.SignatureScript = inputScript.SigScript
-		txIn.Witness = inputScript.Witness
+	if len(ourCoins) != 0 {
+		return fmt.Errorf("funding transaction is missing %d of our "+
+			"inputs", len(ourCoins))
 	}
 
-	// Finally, we'll populate the chanPoint now that we've fully
-	// constructed the funding transaction.
 	f.chanPoint = &wire.OutPoint{
 		Hash:  fundingTx.TxHash(),
 		Index: multiSigIndex,
 	}
 
-	return fundingTx, nil
+	return nil
 }
 
 // Inputs returns all inputs to the final funding transaction that we
@@ -348,9 +504,18 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 		// the same length). And we currently don't support specifying a
 		// change output type, so we always use P2TR.
 		var fundingOutputWeight input.TxWeightEstimator
-		fundingOutputWeight.AddP2WSHOutput()
+		if !r.SkipFundingOutputFee {
+			fundingOutputWeight.AddP2WSHOutput()
+		}
 		changeType := P2TRChangeAddress
 
+		// If we're splicing an existing channel, the funding
+		// transaction also spends the channel's current funding
+		// output, and we're the ones paying for it.
+		r.SharedInput.WhenSome(func(in SharedInput) {
+			fundingOutputWeight.AddWitnessInput(in.WitnessSize)
+		})
+
 		var (
 			coins                []wallet.Coin
 			selectedCoins        []wallet.Coin
@@ -543,10 +708,11 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 				musig2:           r.Musig2,
 				tapscriptRoot:    r.TapscriptRoot,
 			},
-			InputCoins: selectedCoins,
-			coinLeaser: w.cfg.CoinLeaser,
-			coinSource: w.cfg.CoinSource,
-			signer:     w.cfg.Signer,
+			InputCoins:  selectedCoins,
+			SharedInput: r.SharedInput,
+			coinLeaser:  w.cfg.CoinLeaser,
+			coinSource:  w.cfg.CoinSource,
+			signer:      w.cfg.Signer,
 		}
 
 		if changeOutput != nil {
@@ -618,7 +784,7 @@ var _ txscript.PrevOutputFetcher = (*SegWitV0DualFundingPrevOutputFetcher)(nil)
 // So basically dual-funding won't work with Taproot inputs unless the UTXO info
 // is exchanged between the peers.
 func NewSegWitV0DualFundingPrevOutputFetcher(localSource CoinSource,
-	remoteInputs []*wire.TxIn) txscript.PrevOutputFetcher {
+	remoteInputs []*wire.TxIn) *SegWitV0DualFundingPrevOutputFetcher {
 
 	remote := txscript.NewMultiPrevOutFetcher(nil)
 	for _, inp := range remoteInputs {
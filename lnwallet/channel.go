@@ -133,6 +133,22 @@ var (
 	ErrForceCloseLocalDataLoss = errors.New("cannot force close " +
 		"channel with local data loss")
 
+	// ErrChanNotQuiescent is returned when an operation that requires all
+	// updates to be irrevocably committed is attempted while there are
+	// still pending updates on the channel.
+	ErrChanNotQuiescent = errors.New("channel has pending updates")
+
+	// ErrUnsupportedChanTypeUpgrade is returned when the channel type of
+	// an active channel is to be changed to a type that can't be reached
+	// from its current one.
+	ErrUnsupportedChanTypeUpgrade = errors.New("unsupported channel " +
+		"type upgrade")
+
+	// ErrZeroFeeCommitFeeUpdate is returned when a fee update is attempted
+	// on a channel with zero-fee commitments, which don't pay any fee.
+	ErrZeroFeeCommitFeeUpdate = errors.New("fee updates are not allowed " +
+		"for zero-fee commitments")
+
 	// errNoNonce is returned when a nonce is required, but none is found.
 	errNoNonce = errors.New("no nonce found")
 
@@ -262,6 +278,11 @@ type commitment struct {
 	// view.
 	outgoingHTLCIndex map[int32]*paymentDescriptor
 	incomingHTLCIndex map[int32]*paymentDescriptor
+
+	// spliceCommits are the commitments at the same height that spend the
+	// funding outputs of the pending splices of the channel, keyed by the
+	// txid of the splice transaction.
+	spliceCommits map[chainhash.Hash]*commitment
 }
 
 // locateOutputIndex is a small helper function to locate the output index of a
@@ -484,9 +505,11 @@ func (c *commitment) toDiskCommit(
 // commitment state to the form required to manipulate in memory within the
 // commitment struct and updateLog. This function is used when we need to
 // restore commitment state written to disk back into memory once we need to
-// restart a channel session.
+// restart a channel session. The dust limits are those of the commitments
+// the HTLC is restored for.
 func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 	htlc *channeldb.HTLC, commitKeys lntypes.Dual[*CommitmentKeyRing],
+	dustLimits lntypes.Dual[btcutil.Amount],
 	whoseCommit lntypes.ChannelParty,
 	auxLeaf input.AuxTapLeaf) (paymentDescriptor, error) {
 
@@ -507,7 +530,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 	// on-disk state snapshot.
 	isDustLocal := HtlcIsDust(
 		chanType, htlc.Incoming, lntypes.Local, feeRate,
-		htlc.Amt.ToSatoshis(), lc.channelState.LocalChanCfg.DustLimit,
+		htlc.Amt.ToSatoshis(), dustLimits.Local,
 	)
 	localCommitKeys := commitKeys.GetForParty(lntypes.Local)
 	if !isDustLocal && localCommitKeys != nil {
@@ -524,7 +547,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 	}
 	isDustRemote := HtlcIsDust(
 		chanType, htlc.Incoming, lntypes.Remote, feeRate,
-		htlc.Amt.ToSatoshis(), lc.channelState.RemoteChanCfg.DustLimit,
+		htlc.Amt.ToSatoshis(), dustLimits.Remote,
 	)
 	remoteCommitKeys := commitKeys.GetForParty(lntypes.Remote)
 	if !isDustRemote && remoteCommitKeys != nil {
@@ -582,6 +605,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 // for each side.
 func (lc *LightningChannel) extractPayDescs(feeRate chainfee.SatPerKWeight,
 	htlcs []channeldb.HTLC, commitKeys lntypes.Dual[*CommitmentKeyRing],
+	dustLimits lntypes.Dual[btcutil.Amount],
 	whoseCommit lntypes.ChannelParty,
 	auxLeaves fn.Option[CommitAuxLeaves]) ([]paymentDescriptor,
 	[]paymentDescriptor, error) {
@@ -613,7 +637,8 @@ func (lc *LightningChannel) extractPayDescs(feeRate chainfee.SatPerKWeight,
 		)(auxLeaves)
 
 		payDesc, err := lc.diskHtlcToPayDesc(
-			feeRate, &htlc, commitKeys, whoseCommit, auxLeaf,
+			feeRate, &htlc, commitKeys, dustLimits, whoseCommit,
+			auxLeaf,
 		)
 		if err != nil {
 			return incomingHtlcs, outgoingHtlcs, err
@@ -673,12 +698,24 @@ func (lc *LightningChannel) diskCommitToMemCommit(
 		return nil, fmt.Errorf("unable to fetch aux leaves: %w", err)
 	}
 
+	// The commitment may have been created before the dust limit of its
+	// owner was changed, so we'll use the dust limit it was created with.
+	dustLimits := lntypes.Dual[btcutil.Amount]{
+		Local:  lc.channelState.LocalChanCfg.DustLimit,
+		Remote: lc.channelState.RemoteChanCfg.DustLimit,
+	}
+	dustLimit := lc.channelState.CommitParamsAt(
+		whoseCommit, diskCommit.CommitHeight,
+	).DustLimit
+	dustLimits.SetForParty(whoseCommit, dustLimit)
+
 	// With the key rings re-created, we'll now convert all the on-disk
 	// HTLC"s into paymentDescriptor's so we can re-insert them into our
 	// update log.
 	incomingHtlcs, outgoingHtlcs, err := lc.extractPayDescs(
 		chainfee.SatPerKWeight(diskCommit.FeePerKw),
-		diskCommit.Htlcs, commitKeys, whoseCommit, auxResult.AuxLeaves,
+		diskCommit.Htlcs, commitKeys, dustLimits, whoseCommit,
+		auxResult.AuxLeaves,
 	)
 	if err != nil {
 		return nil, err
@@ -707,11 +744,7 @@ func (lc *LightningChannel) diskCommitToMemCommit(
 		outgoingHTLCs:  outgoingHtlcs,
 		customBlob:     diskCommit.CustomBlob,
 	}
-	if whoseCommit.IsLocal() {
-		commit.dustLimit = lc.channelState.LocalChanCfg.DustLimit
-	} else {
-		commit.dustLimit = lc.channelState.RemoteChanCfg.DustLimit
-	}
+	commit.dustLimit = dustLimit
 
 	return commit, nil
 }
@@ -819,6 +852,11 @@ type LightningChannel struct {
 	// fundingOutput is the funding output (script+value).
 	fundingOutput wire.TxOut
 
+	// pendingSplices are the splices of the channel that have been signed,
+	// but not locked by both parties yet. Every commitment is also created
+	// for the funding outputs of these splices.
+	pendingSplices []*pendingSplice
+
 	// opts is the set of options that channel was initialized with.
 	opts *channelOpts
 
@@ -994,6 +1032,12 @@ func NewLightningChannel(signer input.Signer,
 		return nil, err
 	}
 
+	// Finally, we'll restore the commitments spending the funding outputs
+	// of any pending splices.
+	if err := lc.restorePendingSplices(); err != nil {
+		return nil, err
+	}
+
 	return lc, nil
 }
 
@@ -1153,13 +1197,14 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 		ogHTLC := remoteUpdateLog.lookupHtlc(wireMsg.ID)
 
 		pd = &paymentDescriptor{
-			ChanID:      wireMsg.ChanID,
-			Amount:      ogHTLC.Amount,
-			RHash:       ogHTLC.RHash,
-			ParentIndex: ogHTLC.HtlcIndex,
-			LogIndex:    logUpdate.LogIndex,
-			EntryType:   Fail,
-			FailReason:  wireMsg.Reason[:],
+			ChanID:       wireMsg.ChanID,
+			Amount:       ogHTLC.Amount,
+			RHash:        ogHTLC.RHash,
+			ParentIndex:  ogHTLC.HtlcIndex,
+			LogIndex:     logUpdate.LogIndex,
+			EntryType:    Fail,
+			FailReason:   wireMsg.Reason[:],
+			FailAttrData: wireMsg.AttrData.ValOpt().UnwrapOr(nil),
 			removeCommitHeights: lntypes.Dual[uint64]{
 				Remote: commitHeight,
 			},
@@ -1253,13 +1298,14 @@ func (lc *LightningChannel) localLogUpdateToPayDesc(logUpdate *channeldb.LogUpda
 		ogHTLC := remoteUpdateLog.lookupHtlc(wireMsg.ID)
 
 		return &paymentDescriptor{
-			ChanID:      wireMsg.ChanID,
-			Amount:      ogHTLC.Amount,
-			RHash:       ogHTLC.RHash,
-			ParentIndex: ogHTLC.HtlcIndex,
-			LogIndex:    logUpdate.LogIndex,
-			EntryType:   Fail,
-			FailReason:  wireMsg.Reason[:],
+			ChanID:       wireMsg.ChanID,
+			Amount:       ogHTLC.Amount,
+			RHash:        ogHTLC.RHash,
+			ParentIndex:  ogHTLC.HtlcIndex,
+			LogIndex:     logUpdate.LogIndex,
+			EntryType:    Fail,
+			FailReason:   wireMsg.Reason[:],
+			FailAttrData: wireMsg.AttrData.ValOpt().UnwrapOr(nil),
 			removeCommitHeights: lntypes.Dual[uint64]{
 				Remote: commitHeight,
 			},
@@ -1368,13 +1414,14 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 		ogHTLC := localUpdateLog.lookupHtlc(wireMsg.ID)
 
 		return &paymentDescriptor{
-			ChanID:      wireMsg.ChanID,
-			Amount:      ogHTLC.Amount,
-			RHash:       ogHTLC.RHash,
-			ParentIndex: ogHTLC.HtlcIndex,
-			LogIndex:    logUpdate.LogIndex,
-			EntryType:   Fail,
-			FailReason:  wireMsg.Reason[:],
+			ChanID:       wireMsg.ChanID,
+			Amount:       ogHTLC.Amount,
+			RHash:        ogHTLC.RHash,
+			ParentIndex:  ogHTLC.HtlcIndex,
+			LogIndex:     logUpdate.LogIndex,
+			EntryType:    Fail,
+			FailReason:   wireMsg.Reason[:],
+			FailAttrData: wireMsg.AttrData.ValOpt().UnwrapOr(nil),
 			removeCommitHeights: lntypes.Dual[uint64]{
 				Local: commitHeight,
 			},
@@ -1844,14 +1891,16 @@ func (lc *LightningChannel) restorePendingLocalUpdates(
 
 	// If we did have a dangling commit, then we'll examine which updates
 	// we included in that state and re-insert them into our update log.
+	remoteParams := lc.channelState.CommitParamsAt(
+		lntypes.Remote, pendingHeight,
+	)
 	for _, logUpdate := range pendingRemoteCommitDiff.LogUpdates {
 		logUpdate := logUpdate
 
 		payDesc, err := lc.logUpdateToPayDesc(
 			&logUpdate, lc.updateLogs.Remote, pendingHeight,
 			chainfee.SatPerKWeight(pendingCommit.FeePerKw),
-			pendingRemoteKeys,
-			lc.channelState.RemoteChanCfg.DustLimit,
+			pendingRemoteKeys, remoteParams.DustLimit,
 			auxResult.AuxLeaves,
 		)
 		if err != nil {
@@ -2062,10 +2111,15 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		revocationPreimage[:],
 	)
 
+	// The revoked state may have been created before the channel type was
+	// upgraded, so we'll reconstruct it using the type it was created
+	// with.
+	chanType := chanState.ChanTypeAt(lntypes.Remote, stateNum)
+
 	// With the commitment point generated, we can now generate the four
 	// keys we'll need to reconstruct the commitment state,
 	keyRing := DeriveCommitmentKeys(
-		commitmentPoint, lntypes.Remote, chanState.ChanType,
+		commitmentPoint, lntypes.Remote, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -2073,7 +2127,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// number so we can have the proper witness script to sign and include
 	// within the final witness.
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 
@@ -2095,7 +2149,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	)(auxResult.AuxLeaves)
 	isRemoteInitiator := !chanState.IsInitiator
 	ourScript, ourDelay, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry, remoteAuxLeaf,
 	)
 	if err != nil {
@@ -2107,9 +2161,13 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 			return l.LocalAuxLeaf
 		},
 	)(auxResult.AuxLeaves)
-	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
+	// The revoked state may have been created before the commitment params
+	// of the remote party were changed, so we'll use the ones it was
+	// created with.
+	commitParams := chanState.CommitParamsAt(lntypes.Remote, stateNum)
+	theirDelay := uint32(commitParams.CsvDelay)
 	theirScript, err := CommitScriptToSelf(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToLocalKey,
+		chanType, isRemoteInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, theirDelay, leaseExpiry, localAuxLeaf,
 	)
 	if err != nil {
@@ -2126,13 +2184,33 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// If the returned *RevocationLog is non-nil, use it to derive the info
 	// we need.
 	if revokedLog != nil {
+		// If the revoked state was broadcast using the commitment that
+		// spends the funding output of a splice that was pending at
+		// the time, its outputs may be ordered differently.
+		spliceCommit := spendTx != nil &&
+			spendTx.TxHash() != revokedLog.CommitTxHash.Val
+		if spliceCommit {
+			revokedLog = spliceRevocationLog(
+				revokedLog, spendTx, ourScript.PkScript(),
+				theirScript.PkScript(),
+			)
+		}
+
 		br, ourAmt, theirAmt, err = createBreachRetribution(
-			revokedLog, spendTx, chanState, keyRing,
-			commitmentSecret, leaseExpiry, auxResult.AuxLeaves,
+			revokedLog, spendTx, chanState, chanType, keyRing,
+			commitmentSecret, leaseExpiry, theirDelay,
+			auxResult.AuxLeaves,
 		)
 		if err != nil {
 			return nil, err
 		}
+
+		if spliceCommit {
+			err := remapHtlcRetributions(br, spendTx)
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		// The returned revocation log is in legacy format, which is a
 		// *ChannelCommitment.
@@ -2142,8 +2220,9 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		// data can still function. This branch can be deleted once we
 		// are confident that no legacy format is in use.
 		br, ourAmt, theirAmt, err = createBreachRetributionLegacy(
-			revokedLogLegacy, chanState, keyRing, commitmentSecret,
-			ourScript, theirScript, leaseExpiry,
+			revokedLogLegacy, chanState, chanType, keyRing,
+			commitmentSecret, ourScript, theirScript, leaseExpiry,
+			commitParams,
 		)
 		if err != nil {
 			return nil, err
@@ -2156,7 +2235,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	//
 	// If our balance exceeds the remote party's dust limit, instantiate
 	// the sign descriptor for our output.
-	if ourAmt >= int64(chanState.RemoteChanCfg.DustLimit) {
+	if ourAmt >= int64(commitParams.DustLimit) {
 		// As we're about to sweep our own output w/o a delay, we'll
 		// obtain the witness script for the success/delay path.
 		witnessScript, err := ourScript.WitnessScriptForPath(
@@ -2174,7 +2253,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 				PkScript: ourScript.PkScript(),
 				Value:    ourAmt,
 			},
-			HashType: sweepSigHash(chanState.ChanType),
+			HashType: sweepSigHash(chanType),
 		}
 
 		// For taproot channels, we'll make sure to set the script path
@@ -2233,7 +2312,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 
 	// Similarly, if their balance exceeds the remote party's dust limit,
 	// assemble the sign descriptor for their output, which we can sweep.
-	if theirAmt >= int64(chanState.RemoteChanCfg.DustLimit) {
+	if theirAmt >= int64(commitParams.DustLimit) {
 		// As we're trying to defend the channel against a breach
 		// attempt from the remote party, we want to obain the
 		// revocation witness script here.
@@ -2253,7 +2332,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 				PkScript: theirScript.PkScript(),
 				Value:    theirAmt,
 			},
-			HashType: sweepSigHash(chanState.ChanType),
+			HashType: sweepSigHash(chanType),
 		}
 
 		// For taproot channels, the remote output (the revoked output)
@@ -2321,16 +2400,16 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 }
 
 // createHtlcRetribution is a helper function to construct an HtlcRetribution
-// based on the passed params.
+// based on the passed params. The chanType and theirDelay are the channel type
+// and CSV delay of the remote party the revoked commitment was created with.
 func createHtlcRetribution(chanState *channeldb.OpenChannel,
-	keyRing *CommitmentKeyRing, commitHash chainhash.Hash,
-	commitmentSecret *btcec.PrivateKey, leaseExpiry uint32,
+	chanType channeldb.ChannelType, keyRing *CommitmentKeyRing, commitHash chainhash.Hash,
+	commitmentSecret *btcec.PrivateKey, leaseExpiry, theirDelay uint32,
 	htlc *channeldb.HTLCEntry,
 	auxLeaves fn.Option[CommitAuxLeaves]) (HtlcRetribution, error) {
 
 	var emptyRetribution HtlcRetribution
 
-	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
 	isRemoteInitiator := !chanState.IsInitiator
 
 	// We'll generate the original second level witness script now, as
@@ -2351,7 +2430,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 		},
 	)(auxLeaves)
 	secondLevelScript, err := SecondLevelHtlcScript(
-		chanState.ChanType, isRemoteInitiator,
+		chanType, isRemoteInitiator,
 		keyRing.RevocationKey, keyRing.ToLocalKey, theirDelay,
 		leaseExpiry, fn.FlattenOption(secondLevelAuxLeaf),
 	)
@@ -2379,7 +2458,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 		},
 	)(auxLeaves)
 	scriptInfo, err := genHtlcScript(
-		chanState.ChanType, htlc.Incoming.Val, lntypes.Remote,
+		chanType, htlc.Incoming.Val, lntypes.Remote,
 		htlc.RefundTimeout.Val, htlc.RHash.Val, keyRing,
 		fn.FlattenOption(htlcLeaf),
 	)
@@ -2396,7 +2475,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 			PkScript: scriptInfo.PkScript(),
 			Value:    int64(htlc.Amt.Val.Int()),
 		},
-		HashType: sweepSigHash(chanState.ChanType),
+		HashType: sweepSigHash(chanType),
 	}
 
 	// For taproot HTLC outputs, we need to set the sign method to key
@@ -2444,8 +2523,8 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 // ErrRevLogDataMissing is returned.
 func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	spendTx *wire.MsgTx, chanState *channeldb.OpenChannel,
-	keyRing *CommitmentKeyRing, commitmentSecret *btcec.PrivateKey,
-	leaseExpiry uint32,
+	chanType channeldb.ChannelType, keyRing *CommitmentKeyRing,
+	commitmentSecret *btcec.PrivateKey, leaseExpiry, theirDelay uint32,
 	auxLeaves fn.Option[CommitAuxLeaves]) (*BreachRetribution, int64, int64,
 	error) {
 
@@ -2455,8 +2534,9 @@ func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	htlcRetributions := make([]HtlcRetribution, len(revokedLog.HTLCEntries))
 	for i, htlc := range revokedLog.HTLCEntries {
 		hr, err := createHtlcRetribution(
-			chanState, keyRing, commitHash.Val,
-			commitmentSecret, leaseExpiry, htlc, auxLeaves,
+			chanState, chanType, keyRing, commitHash.Val,
+			commitmentSecret, leaseExpiry, theirDelay, htlc,
+			auxLeaves,
 		)
 		if err != nil {
 			return nil, 0, 0, err
@@ -2555,14 +2635,84 @@ func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	}, ourAmt, theirAmt, nil
 }
 
+// spliceRevocationLog returns a copy of the passed revocation log for the
+// given breach transaction, which is the commitment at the same state that
+// spends the funding output of a splice. The indexes of the commitment outputs
+// are located using their pkScripts.
+func spliceRevocationLog(revokedLog *channeldb.RevocationLog,
+	spendTx *wire.MsgTx, ourPkScript,
+	theirPkScript []byte) *channeldb.RevocationLog {
+
+	spliceLog := *revokedLog
+	spliceLog.CommitTxHash = tlv.NewPrimitiveRecord[tlv.TlvType2, [32]byte](
+		spendTx.TxHash(),
+	)
+
+	findIndex := func(pkScript []byte) uint16 {
+		for i, txOut := range spendTx.TxOut {
+			if bytes.Equal(txOut.PkScript, pkScript) {
+				return uint16(i)
+			}
+		}
+
+		return channeldb.OutputIndexEmpty
+	}
+	spliceLog.OurOutputIndex.Val = findIndex(ourPkScript)
+	spliceLog.TheirOutputIndex.Val = findIndex(theirPkScript)
+
+	return &spliceLog
+}
+
+// remapHtlcRetributions updates the outpoints of the HTLC retributions to the
+// outputs of the given breach transaction that pay to their scripts.
+func remapHtlcRetributions(br *BreachRetribution, spendTx *wire.MsgTx) error {
+	used := make(map[int]struct{}, len(br.HtlcRetributions))
+	for i := range br.HtlcRetributions {
+		hr := &br.HtlcRetributions[i]
+
+		found := false
+		for idx, txOut := range spendTx.TxOut {
+			if _, ok := used[idx]; ok {
+				continue
+			}
+
+			if txOut.Value != hr.SignDesc.Output.Value ||
+				!bytes.Equal(
+					txOut.PkScript,
+					hr.SignDesc.Output.PkScript,
+				) {
+
+				continue
+			}
+
+			hr.OutPoint = wire.OutPoint{
+				Hash:  spendTx.TxHash(),
+				Index: uint32(idx),
+			}
+			used[idx] = struct{}{}
+			found = true
+
+			break
+		}
+
+		if !found {
+			return fmt.Errorf("htlc output %v not found in breach "+
+				"tx %v", hr.OutPoint, spendTx.TxHash())
+		}
+	}
+
+	return nil
+}
+
 // createBreachRetributionLegacy creates a partially initiated
 // BreachRetribution using a ChannelCommitment. Returns the constructed
 // retribution, our amount, their amount, and a possible non-nil error.
 func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
-	chanState *channeldb.OpenChannel, keyRing *CommitmentKeyRing,
-	commitmentSecret *btcec.PrivateKey,
-	ourScript, theirScript input.ScriptDescriptor,
-	leaseExpiry uint32) (*BreachRetribution, int64, int64, error) {
+	chanState *channeldb.OpenChannel, chanType channeldb.ChannelType,
+	keyRing *CommitmentKeyRing, commitmentSecret *btcec.PrivateKey,
+	ourScript, theirScript input.ScriptDescriptor, leaseExpiry uint32,
+	commitParams channeldb.CommitmentParams) (*BreachRetribution, int64,
+	int64, error) {
 
 	commitHash := revokedLog.CommitTx.TxHash()
 	ourOutpoint := wire.OutPoint{
@@ -2591,10 +2741,9 @@ func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
 		// If the HTLC is dust, then we'll skip it as it doesn't have
 		// an output on the commitment transaction.
 		if HtlcIsDust(
-			chanState.ChanType, htlc.Incoming, lntypes.Remote,
+			chanType, htlc.Incoming, lntypes.Remote,
 			chainfee.SatPerKWeight(revokedLog.FeePerKw),
-			htlc.Amt.ToSatoshis(),
-			chanState.RemoteChanCfg.DustLimit,
+			htlc.Amt.ToSatoshis(), commitParams.DustLimit,
 		) {
 
 			continue
@@ -2606,8 +2755,9 @@ func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
 		}
 
 		hr, err := createHtlcRetribution(
-			chanState, keyRing, commitHash,
-			commitmentSecret, leaseExpiry, entry,
+			chanState, chanType, keyRing, commitHash,
+			commitmentSecret, leaseExpiry,
+			uint32(commitParams.CsvDelay), entry,
 			fn.None[CommitAuxLeaves](),
 		)
 		if err != nil {
@@ -2780,7 +2930,7 @@ func (lc *LightningChannel) fetchCommitmentView(
 	commitTx, err := lc.commitBuilder.createUnsignedCommitmentTx(
 		ourBalance, theirBalance, whoseCommitChain, feePerKw,
 		nextHeight, htlcView, filteredHTLCView, keyRing,
-		commitChain.tip(),
+		commitChain.tip(), channelFunding(lc.channelState),
 	)
 	if err != nil {
 		return nil, err
@@ -2806,9 +2956,13 @@ func (lc *LightningChannel) fetchCommitmentView(
 	uTx := btcutil.NewTx(commitTx.txn)
 	weight := blockchain.GetTransactionWeight(uTx) + witnessWeight
 
+	// Zero-fee commitments intentionally pay no fee, so they can't be
+	// held to the fee floor.
 	effFeeRate := chainfee.SatPerKWeight(fee) * 1000 /
 		chainfee.SatPerKWeight(weight)
-	if effFeeRate < chainfee.AbsoluteFeePerKwFloor {
+	if effFeeRate < chainfee.AbsoluteFeePerKwFloor &&
+		!lc.channelState.ChanType.HasZeroFeeCommitments() {
+
 		return nil, fmt.Errorf("height=%v, for ChannelPoint(%v) "+
 			"attempts to create commitment with feerate %v: %v",
 			nextHeight, lc.channelState.FundingOutpoint,
@@ -2879,14 +3033,23 @@ func (lc *LightningChannel) fetchCommitmentView(
 		return nil, err
 	}
 
+	// Until the pending splices of the channel are locked, each commitment
+	// is also created for the funding output of every splice.
+	err = lc.addSpliceCommitments(c, keyRing)
+	if err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
 // fundingTxIn returns the funding output as a transaction input. The input
 // returned by this function uses a max sequence number, so it isn't able to be
-// used with RBF by default.
+// used with RBF by default. If the channel has been spliced, the input spends
+// the funding output of the latest splice.
 func fundingTxIn(chanState *channeldb.OpenChannel) wire.TxIn {
-	return *wire.NewTxIn(&chanState.FundingOutpoint, nil, nil)
+	fundingOutpoint := chanState.CurrentFundingOutpoint()
+	return *wire.NewTxIn(&fundingOutpoint, nil, nil)
 }
 
 // evaluateHTLCView processes all update entries in both HTLC update logs,
@@ -3694,8 +3857,11 @@ func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
 	feePerKw := filteredView.FeePerKw
 
 	// Ensure that the fee being applied is enough to be relayed across the
-	// network in a reasonable time frame.
-	if feePerKw < chainfee.FeePerKwFloor {
+	// network in a reasonable time frame. Zero-fee commitments are relayed
+	// as a package together with a child paying for them instead.
+	if feePerKw < chainfee.FeePerKwFloor &&
+		!lc.channelState.ChanType.HasZeroFeeCommitments() {
+
 		return fmt.Errorf("commitment fee per kw %v below fee floor %v",
 			feePerKw, chainfee.FeePerKwFloor)
 	}
@@ -3784,6 +3950,25 @@ func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
 			ErrBelowChanReserve)
 	}
 
+	// Until the pending splices of the channel are locked, the commitment
+	// is also created for each of their funding outputs, so the balances
+	// must cover any funds spliced out as well.
+	for _, splice := range lc.pendingSplices {
+		_, err := applySpliceDelta(
+			ourBalance, splice.balanceDeltas.Local,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = applySpliceDelta(
+			theirBalance, splice.balanceDeltas.Remote,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	// validateUpdates take a set of updates, and validates them against
 	// the passed channel constraints.
 	validateUpdates := func(updates []*paymentDescriptor,
@@ -3872,6 +4057,17 @@ type CommitSigs struct {
 	// AuxSigBlob is the blob containing all the auxiliary signatures for
 	// this new commitment state.
 	AuxSigBlob tlv.Blob
+
+	// FundingTxid is the txid of the funding transaction whose output the
+	// signed commitment spends. It is only set if the commitment is signed
+	// as part of a batch, which is the case while the channel has pending
+	// splices.
+	FundingTxid fn.Option[chainhash.Hash]
+
+	// SpliceSigs are the signatures for the commitments at the same height
+	// that spend the funding outputs of the pending splices of the
+	// channel. Each of them has its FundingTxid set.
+	SpliceSigs []*CommitSigs
 }
 
 // NewCommitState wraps the various signatures needed to properly
@@ -4106,6 +4302,15 @@ func (lc *LightningChannel) SignNextCommitment(
 		auxSigs = append(auxSigs, auxJobResp.SigBlob)
 	}
 
+	// If the channel has pending splices, we'll also sign the commitments
+	// spending their funding outputs.
+	spliceSigs, err := lc.signSpliceCommitments(
+		ctx, newCommitView, keyRing,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// As we're about to proposer a new commitment state for the remote
 	// party, we'll write this pending state to disk before we exit, so we
 	// can retransmit it if necessary.
@@ -4116,6 +4321,9 @@ func (lc *LightningChannel) SignNextCommitment(
 		return nil, err
 	}
 
+	fundingTxid := lc.spliceBatchFundingTxid()
+	lc.addSpliceCommitDiff(commitDiff, newCommitView, spliceSigs)
+
 	err = lc.channelState.AppendRemoteCommitChain(commitDiff)
 	if err != nil {
 		return nil, err
@@ -4137,10 +4345,12 @@ func (lc *LightningChannel) SignNextCommitment(
 
 	return &NewCommitState{
 		CommitSigs: &CommitSigs{
-			CommitSig:  sig,
-			HtlcSigs:   htlcSigs,
-			PartialSig: lnwire.MaybePartialSigWithNonce(partialSig),
-			AuxSigBlob: auxSigBlob,
+			CommitSig:   sig,
+			HtlcSigs:    htlcSigs,
+			PartialSig:  lnwire.MaybePartialSigWithNonce(partialSig),
+			AuxSigBlob:  auxSigBlob,
+			FundingTxid: fundingTxid,
+			SpliceSigs:  spliceSigs,
 		},
 		PendingHTLCs: commitDiff.Commitment.Htlcs,
 	}, nil
@@ -4369,18 +4579,16 @@ func (lc *LightningChannel) ProcessChanSyncMsg(ctx context.Context,
 					return nil, nil, nil, sErr
 				}
 
-				commitSig := &lnwire.CommitSig{
-					ChanID: lnwire.NewChanIDFromOutPoint(
+				commitSigs := CommitSigBatch(
+					lnwire.NewChanIDFromOutPoint(
 						lc.channelState.FundingOutpoint,
 					),
-					CommitSig:     newCommit.CommitSig,
-					HtlcSigs:      newCommit.HtlcSigs,
-					PartialSig:    newCommit.PartialSig,
-					CustomRecords: customRecords,
+					newCommit.CommitSigs, customRecords,
+				)
+				for _, commitSig := range commitSigs {
+					updates = append(updates, commitSig)
 				}
 
-				updates = append(updates, commitSig)
-
 			// If we get a failure due to not knowing their next
 			// point, then this is fine as they'll either send
 			// ChannelReady, or revoke their next state to allow
@@ -4475,6 +4683,16 @@ func (lc *LightningChannel) ProcessChanSyncMsg(ctx context.Context,
 		// commitment chain with our local version of their chain.
 		commitUpdates = append(commitUpdates, commitDiff.CommitSig)
 
+		// If the commitment was signed for pending splices as well,
+		// the rest of the batch has to be retransmitted too.
+		spliceSigs, err := lc.pendingSpliceCommitSigs()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, commitSig := range spliceSigs {
+			commitUpdates = append(commitUpdates, commitSig)
+		}
+
 		// NOTE: If a revocation is not owed, then updates is empty.
 		if lc.channelState.LastWasRevoke {
 			// If lastWasRevoke is set to true, a revocation was last and we
@@ -4590,6 +4808,21 @@ func (lc *LightningChannel) computeView(view *HtlcView,
 	}
 	nextHeight := commitChain.tip().height + 1
 
+	// If the channel type was upgraded since the last committed state,
+	// the initiator also pays for the anchor outputs that were added.
+	tipChanType := lc.channelState.ChanTypeAt(
+		whoseCommitChain, commitChain.tip().height,
+	)
+	anchorDelta := lnwire.NewMSatFromSatoshis(
+		AnchorOutputsValue(lc.channelState.ChanType) -
+			AnchorOutputsValue(tipChanType),
+	)
+	if lc.channelState.IsInitiator {
+		ourBalance -= anchorDelta
+	} else {
+		theirBalance -= anchorDelta
+	}
+
 	// Initiate feePerKw to the last committed fee for this chain as we'll
 	// need this to determine which HTLCs are dust, and also the final fee
 	// rate.
@@ -5112,6 +5345,12 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSigs *CommitSigs) error {
 		lc.log.Warnf("empty commit sig message received")
 	}
 
+	// While the channel has pending splices, the commitments spending
+	// their funding outputs must have been signed as well.
+	if err := lc.checkSpliceSigCount(commitSigs); err != nil {
+		return err
+	}
+
 	// Determine the last update on the local log that has been locked in.
 	localACKedIndex := lc.commitChains.Remote.tail().messageIndices.Local
 	localHtlcIndex := lc.commitChains.Remote.tail().ourHtlcIndex
@@ -5350,6 +5589,15 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSigs *CommitSigs) error {
 		return fmt.Errorf("unable to validate aux sigs: %w", err)
 	}
 
+	// If the channel has pending splices, the remote party must also have
+	// signed the commitments spending their funding outputs.
+	err = lc.verifySpliceCommitments(
+		localCommitmentView, keyRing, commitSigs.SpliceSigs,
+	)
+	if err != nil {
+		return err
+	}
+
 	// The signature checks out, so we can now add the new commitment to
 	// our local commitment chain. For regular channels, we can just
 	// serialize the ECDSA sig. For taproot channels, we'll serialize the
@@ -5511,6 +5759,194 @@ func (lc *LightningChannel) NumPendingUpdates(whoseUpdates lntypes.ChannelParty,
 	return updateIndex - lastCommit.messageIndices.GetForParty(whoseUpdates)
 }
 
+// ValidateChannelParams checks whether the passed channel state bounds,
+// commitment params and channel type could be applied to the channel, given
+// its capacity and current state.
+func (lc *LightningChannel) ValidateChannelParams(localBounds,
+	remoteBounds channeldb.ChannelStateBounds, localParams,
+	remoteParams channeldb.CommitmentParams,
+	chanType channeldb.ChannelType) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.validateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams, chanType,
+	)
+}
+
+// validateChannelParams checks whether the passed channel state bounds,
+// commitment params and channel type could be applied to the channel.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) validateChannelParams(localBounds,
+	remoteBounds channeldb.ChannelStateBounds, localParams,
+	remoteParams channeldb.CommitmentParams,
+	chanType channeldb.ChannelType) error {
+
+	capacity := lc.channelState.Capacity
+	if err := verifyStateBounds(&localBounds, capacity); err != nil {
+		return err
+	}
+	if err := verifyStateBounds(&remoteBounds, capacity); err != nil {
+		return err
+	}
+
+	// A changed dust limit must be within the same range we require when
+	// the channel is opened.
+	minDustLimit := DustLimitForSize(input.UnknownWitnessSize)
+	checkParams := func(current, params channeldb.CommitmentParams) error {
+		dustLimit := params.DustLimit
+		validDust := dustLimit >= minDustLimit &&
+			dustLimit <= 3*minDustLimit
+		if dustLimit != current.DustLimit && !validDust {
+			return ErrInvalidDustLimit(dustLimit)
+		}
+
+		if params.CsvDelay == 0 {
+			return errors.New("csv delay must be non-zero")
+		}
+
+		return nil
+	}
+	err := checkParams(
+		lc.channelState.LocalChanCfg.CommitmentParams, localParams,
+	)
+	if err != nil {
+		return err
+	}
+	err = checkParams(
+		lc.channelState.RemoteChanCfg.CommitmentParams, remoteParams,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Both channel reserves must be above both dust limits, otherwise the
+	// channel may get stuck.
+	minReserve := min(localBounds.ChanReserve, remoteBounds.ChanReserve)
+	maxDustLimit := max(localParams.DustLimit, remoteParams.DustLimit)
+	if minReserve < maxDustLimit {
+		return ErrChanReserveTooSmall(minReserve, maxDustLimit)
+	}
+
+	if chanType != lc.channelState.ChanType {
+		return lc.validateChanTypeUpgrade(chanType, localBounds,
+			remoteBounds)
+	}
+
+	return nil
+}
+
+// validateChanTypeUpgrade checks whether the channel can be upgraded to the
+// passed channel type. The upgrade must keep the funding output, as we don't
+// support moving the funds of a live channel to a new one, which rules out
+// taproot channels. Channels with a tweakless to_remote output can be upgraded
+// to anchor outputs with zero-fee HTLC transactions. To keep the resolution of
+// the current commitments simple, neither of them may carry any HTLCs, and
+// the initiator must be able to pay for the anchor outputs and the heavier
+// commitment transaction while staying above its reserve.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) validateChanTypeUpgrade(
+	chanType channeldb.ChannelType, localBounds,
+	remoteBounds channeldb.ChannelStateBounds) error {
+
+	current := lc.channelState.ChanType
+	upgrade := AnchorsUpgradeChanType(current)
+
+	switch {
+	case current.IsTaproot() || chanType.IsTaproot():
+		return fmt.Errorf("%w: switching to or from taproot requires "+
+			"a new funding output", ErrUnsupportedChanTypeUpgrade)
+
+	case current.HasZeroFeeCommitments() || !current.IsTweakless() ||
+		chanType != upgrade:
+
+		return fmt.Errorf("%w: from %v to %v",
+			ErrUnsupportedChanTypeUpgrade, current, chanType)
+	}
+
+	localCommit := lc.channelState.LocalCommitment
+	remoteCommit := lc.channelState.RemoteCommitment
+	if len(localCommit.Htlcs) != 0 || len(remoteCommit.Htlcs) != 0 {
+		return fmt.Errorf("%w: channel has active HTLCs",
+			ErrUnsupportedChanTypeUpgrade)
+	}
+
+	// The initiator pays for the anchors and the commitment fee, which is
+	// already deducted from its balance at the current weight.
+	feePerKw := chainfee.SatPerKWeight(localCommit.FeePerKw)
+	newCommitFee := feePerKw.FeeForWeight(CommitWeight(chanType))
+	initiatorBalance := localCommit.LocalBalance.ToSatoshis()
+	initiatorReserve := localBounds.ChanReserve
+	if !lc.channelState.IsInitiator {
+		initiatorBalance = localCommit.RemoteBalance.ToSatoshis()
+		initiatorReserve = remoteBounds.ChanReserve
+	}
+	initiatorBalance += localCommit.CommitFee - newCommitFee -
+		AnchorOutputsValue(chanType)
+
+	if initiatorBalance < initiatorReserve {
+		return fmt.Errorf("%w: initiator balance of %v after the "+
+			"upgrade would be below its reserve of %v",
+			ErrUnsupportedChanTypeUpgrade, initiatorBalance,
+			initiatorReserve)
+	}
+
+	return nil
+}
+
+// AnchorsUpgradeChanType returns the channel type a channel of the passed type
+// has after upgrading it to anchor outputs with zero-fee HTLC transactions.
+// This is the only channel type upgrade of a live channel we support.
+func AnchorsUpgradeChanType(
+	chanType channeldb.ChannelType) channeldb.ChannelType {
+
+	return chanType | channeldb.AnchorOutputsBit |
+		channeldb.ZeroHtlcTxFeeBit
+}
+
+// UpdateChannelParams replaces the channel state bounds and commitment params
+// of both parties, as well as the channel type, with the passed values, after
+// validating them. The new values are persisted. The bounds will be enforced
+// for all subsequent updates, while the commitment params and channel type
+// apply from the next commitment of each party on. Resolving any of the
+// current or past commitments still uses the params and channel type they
+// were created with.
+//
+// NOTE: This MUST only be called while the channel is quiescent, as any
+// in-flight updates were validated against the previous bounds.
+func (lc *LightningChannel) UpdateChannelParams(localBounds,
+	remoteBounds channeldb.ChannelStateBounds, localParams,
+	remoteParams channeldb.CommitmentParams,
+	chanType channeldb.ChannelType) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	// Besides all updates being locked in, neither party may have an
+	// unrevoked pending commitment, as it was created with the current
+	// params.
+	if lc.hasPendingUpdates() ||
+		lc.commitChains.Local.tip() != lc.commitChains.Local.tail() ||
+		lc.commitChains.Remote.tip() != lc.commitChains.Remote.tail() {
+
+		return ErrChanNotQuiescent
+	}
+
+	err := lc.validateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams, chanType,
+	)
+	if err != nil {
+		return err
+	}
+
+	return lc.channelState.UpdateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams, chanType,
+	)
+}
+
 // RevokeCurrentCommitment revokes the next lowest unrevoked commitment
 // transaction in the local commitment chain. As a result the edge of our
 // revocation window is extended by one, and the tail of our local commitment
@@ -5550,6 +5986,7 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck,
 
 	finalHtlcs, err := lc.channelState.UpdateCommitment(
 		newCommitment, unsignedAckedUpdates,
+		spliceDiskCommits(chainTail, lntypes.Local)...,
 	)
 	if err != nil {
 		return nil, nil, nil, err
@@ -6235,6 +6672,23 @@ func (lc *LightningChannel) ReceiveHTLCSettle(preimage [32]byte, htlcIndex uint6
 	return nil
 }
 
+// FailHTLCOpt is a functional option that can be used to modify the failure
+// of an HTLC.
+type FailHTLCOpt func(*failHTLCOpts)
+
+// failHTLCOpts holds the optional parameters of the failure of an HTLC.
+type failHTLCOpts struct {
+	attrData []byte
+}
+
+// WithFailAttrData attaches the attribution data of a failure, which is sent
+// or was received along with the failure reason.
+func WithFailAttrData(attrData []byte) FailHTLCOpt {
+	return func(opts *failHTLCOpts) {
+		opts.attrData = attrData
+	}
+}
+
 // FailHTLC attempts to fail a targeted HTLC by its payment hash, inserting an
 // entry which will remove the target log entry within the next commitment
 // update. This method is intended to be called in order to cancel in
@@ -6261,7 +6715,12 @@ func (lc *LightningChannel) ReceiveHTLCSettle(preimage [32]byte, htlcIndex uint6
 // testing the wallet.
 func (lc *LightningChannel) FailHTLC(htlcIndex uint64, reason []byte,
 	sourceRef *channeldb.AddRef, destRef *channeldb.SettleFailRef,
-	closeKey *models.CircuitKey) error {
+	closeKey *models.CircuitKey, opts ...FailHTLCOpt) error {
+
+	var failOpts failHTLCOpts
+	for _, opt := range opts {
+		opt(&failOpts)
+	}
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -6285,6 +6744,7 @@ func (lc *LightningChannel) FailHTLC(htlcIndex uint64, reason []byte,
 		LogIndex:         lc.updateLogs.Local.logIndex,
 		EntryType:        Fail,
 		FailReason:       reason,
+		FailAttrData:     failOpts.attrData,
 		SourceRef:        sourceRef,
 		DestRef:          destRef,
 		ClosedCircuitKey: closeKey,
@@ -6355,7 +6815,12 @@ func (lc *LightningChannel) MalformedFailHTLC(htlcIndex uint64,
 // commitment update. This method should be called in response to the upstream
 // party cancelling an outgoing HTLC.
 func (lc *LightningChannel) ReceiveFailHTLC(htlcIndex uint64, reason []byte,
-) error {
+	opts ...FailHTLCOpt) error {
+
+	var failOpts failHTLCOpts
+	for _, opt := range opts {
+		opt(&failOpts)
+	}
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -6372,13 +6837,14 @@ func (lc *LightningChannel) ReceiveFailHTLC(htlcIndex uint64, reason []byte,
 	}
 
 	pd := &paymentDescriptor{
-		ChanID:      lc.ChannelID(),
-		Amount:      htlc.Amount,
-		RHash:       htlc.RHash,
-		ParentIndex: htlc.HtlcIndex,
-		LogIndex:    lc.updateLogs.Remote.logIndex,
-		EntryType:   Fail,
-		FailReason:  reason,
+		ChanID:       lc.ChannelID(),
+		Amount:       htlc.Amount,
+		RHash:        htlc.RHash,
+		ParentIndex:  htlc.HtlcIndex,
+		LogIndex:     lc.updateLogs.Remote.logIndex,
+		EntryType:    Fail,
+		FailReason:   reason,
+		FailAttrData: failOpts.attrData,
 	}
 
 	lc.updateLogs.Remote.appendUpdate(pd)
@@ -6711,11 +7177,21 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel,
 	auxResolver fn.Option[AuxContractResolver]) (*UnilateralCloseSummary,
 	error) {
 
+	// The commitment may have been created before the channel type was
+	// upgraded. If we don't have it stored, it is a future state, which
+	// was created with the current type.
+	chanType := chanState.ChanType
+	if remoteCommit.CommitTx != nil {
+		chanType = chanState.ChanTypeAt(
+			lntypes.Remote, remoteCommit.CommitHeight,
+		)
+	}
+
 	// First, we'll generate the commitment point and the revocation point
 	// so we can re-construct the HTLC state and also our payment key.
 	commitType := lntypes.Remote
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, commitType, chanState.ChanType,
+		commitPoint, commitType, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -6741,15 +7217,17 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel,
 		commitTxBroadcast = commitSpend.SpendingTx
 	)
 
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
+	commitParams := chanState.CommitParamsAt(
+		lntypes.Remote, remoteCommit.CommitHeight,
+	)
 	htlcResolutions, err := extractHtlcResolutions(
 		chainfee.SatPerKWeight(remoteCommit.FeePerKw), commitType,
 		signer, remoteCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
-		&chanState.RemoteChanCfg, commitSpend.SpendingTx,
-		chanState.ChanType, isRemoteInitiator, leaseExpiry,
-		auxResult.AuxLeaves,
+		commitParams, commitSpend.SpendingTx, chanType,
+		isRemoteInitiator, leaseExpiry, auxResult.AuxLeaves,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create htlc resolutions: %w",
@@ -6765,7 +7243,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel,
 		},
 	)(auxResult.AuxLeaves)
 	selfScript, maturityDelay, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry, remoteAuxLeaf,
 	)
 	if err != nil {
@@ -6809,7 +7287,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel,
 					Value:    localBalance,
 					PkScript: selfScript.PkScript(),
 				},
-				HashType: sweepSigHash(chanState.ChanType),
+				HashType: sweepSigHash(chanType),
 			},
 			MaturityDelay: maturityDelay,
 		}
@@ -6891,7 +7369,8 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel,
 	}
 
 	anchorResolution, err := NewAnchorResolution(
-		chanState, commitTxBroadcast, keyRing, lntypes.Remote,
+		chanState, chanType, commitTxBroadcast, keyRing,
+		lntypes.Remote,
 	)
 	if err != nil {
 		return nil, err
@@ -7563,22 +8042,20 @@ func (r *OutgoingHtlcResolution) HtlcPoint() wire.OutPoint {
 
 // extractHtlcResolutions creates a series of outgoing HTLC resolutions, and
 // the local key used when generating the HTLC scrips. This function is to be
-// used in two cases: force close, or a unilateral close.
+// used in two cases: force close, or a unilateral close. The commitParams are
+// the params of the owner of the commitment it was created with.
 func extractHtlcResolutions(feePerKw chainfee.SatPerKWeight,
 	whoseCommit lntypes.ChannelParty, signer input.Signer,
 	htlcs []channeldb.HTLC, keyRing *CommitmentKeyRing,
-	localChanCfg, remoteChanCfg *channeldb.ChannelConfig,
-	commitTx *wire.MsgTx, chanType channeldb.ChannelType,
-	isCommitFromInitiator bool, leaseExpiry uint32,
+	localChanCfg *channeldb.ChannelConfig,
+	commitParams channeldb.CommitmentParams, commitTx *wire.MsgTx,
+	chanType channeldb.ChannelType, isCommitFromInitiator bool,
+	leaseExpiry uint32,
 	auxLeaves fn.Option[CommitAuxLeaves]) (*HtlcResolutions, error) {
 
 	// TODO(roasbeef): don't need to swap csv delay?
-	dustLimit := remoteChanCfg.DustLimit
-	csvDelay := remoteChanCfg.CsvDelay
-	if whoseCommit.IsLocal() {
-		dustLimit = localChanCfg.DustLimit
-		csvDelay = localChanCfg.CsvDelay
-	}
+	dustLimit := commitParams.DustLimit
+	csvDelay := commitParams.CsvDelay
 
 	incomingResolutions := make([]IncomingHtlcResolution, 0, len(htlcs))
 	outgoingResolutions := make([]OutgoingHtlcResolution, 0, len(htlcs))
@@ -7649,6 +8126,16 @@ type AnchorResolution struct {
 
 	// CommitWeight is the weight of the commit tx.
 	CommitWeight lntypes.WeightUnit
+
+	// CommitTx is the fully signed commit tx. It's only set for our local
+	// commitment once we've broadcast it, so it can be rebroadcast along
+	// with the anchor sweep.
+	CommitTx *wire.MsgTx
+
+	// CommitBalance is the value of our own output on the commit tx. It's
+	// only set for zero-fee commitments, which can't confirm without a
+	// child spending the anchor, so that our balance is at stake as well.
+	CommitBalance btcutil.Amount
 }
 
 // LocalForceCloseSummary describes the final commitment state before the
@@ -7745,7 +8232,14 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	// commitment transaction. We'll need this to find the corresponding
 	// output in the commitment transaction and potentially for creating
 	// the sign descriptor.
-	csvTimeout := uint32(chanState.LocalChanCfg.CsvDelay)
+	//
+	// The commitment may have been created before our commitment params
+	// or the channel type were changed, so we'll use the ones it was
+	// created with.
+	csvTimeout := uint32(
+		chanState.CommitParamsAt(lntypes.Local, stateNum).CsvDelay,
+	)
+	chanType := chanState.ChanTypeAt(lntypes.Local, stateNum)
 
 	// We use the passed state num to derive our scripts, since in case
 	// this is after recovery, our latest channels state might not be up to
@@ -7756,7 +8250,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	}
 	commitPoint := input.ComputeCommitmentPoint(revocation[:])
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, lntypes.Local, chanState.ChanType,
+		commitPoint, lntypes.Local, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -7773,7 +8267,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	}
 
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 
@@ -7783,7 +8277,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 		},
 	)(auxResult.AuxLeaves)
 	toLocalScript, err := CommitScriptToSelf(
-		chanState.ChanType, chanState.IsInitiator, keyRing.ToLocalKey,
+		chanType, chanState.IsInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, csvTimeout, leaseExpiry, localAuxLeaf,
 	)
 	if err != nil {
@@ -7839,7 +8333,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 					PkScript: delayOut.PkScript,
 					Value:    localBalance,
 				},
-				HashType: sweepSigHash(chanState.ChanType),
+				HashType: sweepSigHash(chanType),
 			},
 			MaturityDelay: csvTimeout,
 		}
@@ -7900,10 +8394,13 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	// recovery there is not much we can do with HTLCs, so we'll always
 	// use what we have in our latest state when extracting resolutions.
 	localCommit := chanState.LocalCommitment
+	commitParams := chanState.CommitParamsAt(
+		lntypes.Local, localCommit.CommitHeight,
+	)
 	htlcResolutions, err := extractHtlcResolutions(
 		chainfee.SatPerKWeight(localCommit.FeePerKw), lntypes.Local,
 		signer, localCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
-		&chanState.RemoteChanCfg, commitTx, chanState.ChanType,
+		commitParams, commitTx, chanType,
 		chanState.IsInitiator, leaseExpiry, auxResult.AuxLeaves,
 	)
 	if err != nil {
@@ -7911,7 +8408,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	}
 
 	anchorResolution, err := NewAnchorResolution(
-		chanState, commitTx, keyRing, lntypes.Local,
+		chanState, chanType, commitTx, keyRing, lntypes.Local,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to gen anchor "+
@@ -7952,6 +8449,16 @@ type chanCloseOpt struct {
 	// transaction outputs. If this isn't set, then the default BIP-69
 	// sorting is used.
 	customSort CloseSortFunc
+
+	// customSequence is an optional sequence to use on the input spending
+	// the funding output. If this isn't set, then the default sequence is
+	// used.
+	customSequence fn.Option[uint32]
+
+	// customPayer is the party that pays the fee of the closing
+	// transaction. If this isn't set, then the channel initiator pays the
+	// full fee.
+	customPayer fn.Option[lntypes.ChannelParty]
 }
 
 // ChanCloseOpt is a closure type that cen be used to modify the set of default
@@ -7988,6 +8495,22 @@ func WithCustomCoopSort(sorter CloseSortFunc) ChanCloseOpt {
 	}
 }
 
+// WithCustomSequence can be used to set a custom sequence on the input spending
+// the funding output of the co-op close transaction.
+func WithCustomSequence(sequence uint32) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.customSequence = fn.Some(sequence)
+	}
+}
+
+// WithCustomPayer can be used to specify the party that pays the fee of the
+// co-op close transaction.
+func WithCustomPayer(payer lntypes.ChannelParty) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.customPayer = fn.Some(payer)
+	}
+}
+
 // CreateCloseProposal is used by both parties in a cooperative channel close
 // workflow to generate proposed close transactions and signatures. This method
 // should only be executed once all pending HTLCs (if any) on the channel have
@@ -8016,12 +8539,15 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	// Get the final balances after subtracting the proposed fee, taking
 	// care not to persist the adjusted balance, as the feeRate may change
 	// during the channel closing process.
+	localCommit := lc.channelState.LocalCommitment
+	chanType := lc.channelState.ChanTypeAt(
+		lntypes.Local, localCommit.CommitHeight,
+	)
 	ourBalance, theirBalance, err := CoopCloseBalance(
-		lc.channelState.ChanType, lc.channelState.IsInitiator,
-		proposedFee,
-		lc.channelState.LocalCommitment.LocalBalance.ToSatoshis(),
-		lc.channelState.LocalCommitment.RemoteBalance.ToSatoshis(),
-		lc.channelState.LocalCommitment.CommitFee,
+		chanType, lc.channelState.IsInitiator, proposedFee,
+		localCommit.LocalBalance.ToSatoshis(),
+		localCommit.RemoteBalance.ToSatoshis(),
+		localCommit.CommitFee, opts.customPayer,
 	)
 	if err != nil {
 		return nil, nil, 0, err
@@ -8046,6 +8572,9 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 			closeTxOpts, WithCustomTxSort(opts.customSort),
 		)
 	}
+	opts.customSequence.WhenSome(func(sequence uint32) {
+		closeTxOpts = append(closeTxOpts, WithTxSequence(sequence))
+	})
 
 	closeTx, err := CreateCooperativeCloseTx(
 		fundingTxIn(lc.channelState), lc.channelState.LocalChanCfg.DustLimit,
@@ -8117,12 +8646,15 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	}
 
 	// Get the final balances after subtracting the proposed fee.
+	localCommit := lc.channelState.LocalCommitment
+	chanType := lc.channelState.ChanTypeAt(
+		lntypes.Local, localCommit.CommitHeight,
+	)
 	ourBalance, theirBalance, err := CoopCloseBalance(
-		lc.channelState.ChanType, lc.channelState.IsInitiator,
-		proposedFee,
-		lc.channelState.LocalCommitment.LocalBalance.ToSatoshis(),
-		lc.channelState.LocalCommitment.RemoteBalance.ToSatoshis(),
-		lc.channelState.LocalCommitment.CommitFee,
+		chanType, lc.channelState.IsInitiator, proposedFee,
+		localCommit.LocalBalance.ToSatoshis(),
+		localCommit.RemoteBalance.ToSatoshis(),
+		localCommit.CommitFee, opts.customPayer,
 	)
 	if err != nil {
 		return nil, 0, err
@@ -8147,6 +8679,9 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 			closeTxOpts, WithCustomTxSort(opts.customSort),
 		)
 	}
+	opts.customSequence.WhenSome(func(sequence uint32) {
+		closeTxOpts = append(closeTxOpts, WithTxSequence(sequence))
+	})
 
 	// Create the transaction used to return the current settled balance
 	// on this active channel back to both parties. In this current model,
@@ -8274,24 +8809,44 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 		localCommitPoint, lntypes.Local, lc.channelState.ChanType,
 		&lc.channelState.LocalChanCfg, &lc.channelState.RemoteChanCfg,
 	)
+	localCommit := lc.channelState.LocalCommitment
 	localRes, err := NewAnchorResolution(
-		lc.channelState, lc.channelState.LocalCommitment.CommitTx,
-		localKeyRing, lntypes.Local,
+		lc.channelState, lc.channelState.ChanTypeAt(
+			lntypes.Local, localCommit.CommitHeight,
+		), localCommit.CommitTx, localKeyRing, lntypes.Local,
 	)
 	if err != nil {
 		return nil, err
 	}
 	resolutions.Local = localRes
 
+	// If we've broadcast our local commitment, we'll attach it to the
+	// resolution, so it can be submitted together with the anchor sweep
+	// if it doesn't make it into the mempool on its own.
+	if localRes != nil {
+		closeTx, err := lc.channelState.BroadcastedCommitment()
+		switch {
+		case err == nil &&
+			closeTx.TxHash() == localRes.CommitAnchor.Hash:
+
+			localRes.CommitTx = closeTx
+
+		case err != nil && !errors.Is(err, channeldb.ErrNoCloseTx):
+			return nil, err
+		}
+	}
+
 	// Add anchor for remote commitment tx, if any.
 	remoteKeyRing := DeriveCommitmentKeys(
 		lc.channelState.RemoteCurrentRevocation, lntypes.Remote,
 		lc.channelState.ChanType, &lc.channelState.LocalChanCfg,
 		&lc.channelState.RemoteChanCfg,
 	)
+	remoteCommit := lc.channelState.RemoteCommitment
 	remoteRes, err := NewAnchorResolution(
-		lc.channelState, lc.channelState.RemoteCommitment.CommitTx,
-		remoteKeyRing, lntypes.Remote,
+		lc.channelState, lc.channelState.ChanTypeAt(
+			lntypes.Remote, remoteCommit.CommitHeight,
+		), remoteCommit.CommitTx, remoteKeyRing, lntypes.Remote,
 	)
 	if err != nil {
 		return nil, err
@@ -8310,10 +8865,12 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 			lc.channelState.ChanType, &lc.channelState.LocalChanCfg,
 			&lc.channelState.RemoteChanCfg,
 		)
+		pendingCommit := remotePendingCommit.Commitment
 		remotePendingRes, err := NewAnchorResolution(
-			lc.channelState,
-			remotePendingCommit.Commitment.CommitTx,
-			pendingRemoteKeyRing, lntypes.Remote,
+			lc.channelState, lc.channelState.ChanTypeAt(
+				lntypes.Remote, pendingCommit.CommitHeight,
+			), pendingCommit.CommitTx, pendingRemoteKeyRing,
+			lntypes.Remote,
 		)
 		if err != nil {
 			return nil, err
@@ -8325,28 +8882,39 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 }
 
 // NewAnchorResolution returns the information that is required to sweep the
-// local anchor.
+// local anchor. The chanType is the channel type the commitment was created
+// with, which may differ from the current one of the channel if it was
+// upgraded since.
 func NewAnchorResolution(chanState *channeldb.OpenChannel,
-	commitTx *wire.MsgTx, keyRing *CommitmentKeyRing,
+	chanType channeldb.ChannelType, commitTx *wire.MsgTx,
+	keyRing *CommitmentKeyRing,
 	whoseCommit lntypes.ChannelParty) (*AnchorResolution, error) {
 
 	// Return nil resolution if the channel has no anchors.
-	if !chanState.ChanType.HasAnchors() {
+	if !chanType.HasAnchors() {
 		return nil, nil
 	}
 
+	// Zero-fee commitments have a single anchor output that isn't tied to
+	// a key of either party.
+	if chanType.HasZeroFeeCommitments() {
+		return newPayToAnchorResolution(
+			chanState, commitTx, keyRing, whoseCommit,
+		)
+	}
+
 	// Derive our local anchor script. For taproot channels, rather than
 	// use the same multi-sig key for both commitments, the anchor script
 	// will differ depending on if this is our local or remote
 	// commitment.
 	localAnchor, remoteAnchor, err := CommitScriptAnchors(
-		chanState.ChanType, &chanState.LocalChanCfg,
+		chanType, &chanState.LocalChanCfg,
 		&chanState.RemoteChanCfg, keyRing,
 	)
 	if err != nil {
 		return nil, err
 	}
-	if chanState.ChanType.IsTaproot() && whoseCommit.IsRemote() {
+	if chanType.IsTaproot() && whoseCommit.IsRemote() {
 		//nolint:ineffassign
 		localAnchor, remoteAnchor = remoteAnchor, localAnchor
 	}
@@ -8384,7 +8952,7 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 			PkScript: localAnchor.PkScript(),
 			Value:    int64(AnchorSize),
 		},
-		HashType: sweepSigHash(chanState.ChanType),
+		HashType: sweepSigHash(chanType),
 	}
 
 	// For taproot outputs, we'll need to ensure that the proper sign
@@ -8424,7 +8992,7 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 	}
 
 	var witnessWeight int64
-	if chanState.ChanType.IsTaproot() {
+	if chanType.IsTaproot() {
 		witnessWeight = input.TaprootKeyPathWitnessSize
 	} else {
 		witnessWeight = input.WitnessCommitmentTxWeight
@@ -8450,6 +9018,90 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 	}, nil
 }
 
+// newPayToAnchorResolution returns the information that is required to sweep
+// the pay-to-anchor output of a zero-fee commitment.
+func newPayToAnchorResolution(chanState *channeldb.OpenChannel,
+	commitTx *wire.MsgTx, keyRing *CommitmentKeyRing,
+	whoseCommit lntypes.ChannelParty) (*AnchorResolution, error) {
+
+	found, index := input.FindScriptOutputIndex(
+		commitTx, input.PayToAnchorScript(),
+	)
+	if !found {
+		return nil, fmt.Errorf("zero-fee commitment %v has no "+
+			"pay-to-anchor output", commitTx.TxHash())
+	}
+	anchorOutput := commitTx.TxOut[index]
+
+	// Our own output is the to_local output on our commitment, and the
+	// to_remote output on the commitment of the remote party.
+	var ourScript input.ScriptDescriptor
+	if whoseCommit.IsLocal() {
+		csvDelay := chanState.CommitParamsAt(
+			lntypes.Local, chanState.LocalCommitment.CommitHeight,
+		).CsvDelay
+		toLocalScript, err := CommitScriptToSelf(
+			chanState.ChanType, chanState.IsInitiator,
+			keyRing.ToLocalKey, keyRing.RevocationKey,
+			uint32(csvDelay), 0, input.NoneTapLeaf(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		ourScript = toLocalScript
+	} else {
+		toRemoteScript, _, err := CommitScriptToRemote(
+			chanState.ChanType, !chanState.IsInitiator,
+			keyRing.ToRemoteKey, 0, input.NoneTapLeaf(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		ourScript = toRemoteScript
+	}
+
+	var ourBalance btcutil.Amount
+	found, ourIndex := input.FindScriptOutputIndex(
+		commitTx, ourScript.PkScript(),
+	)
+	if found {
+		ourBalance = btcutil.Amount(commitTx.TxOut[ourIndex].Value)
+	}
+
+	// The anchor is spent with an empty witness, so the sign descriptor
+	// only carries the output.
+	signDesc := input.SignDescriptor{
+		Output: &wire.TxOut{
+			PkScript: anchorOutput.PkScript,
+			Value:    anchorOutput.Value,
+		},
+		HashType: txscript.SigHashAll,
+	}
+
+	// Calculate commit tx weight. This commit tx doesn't yet include the
+	// witness spending the funding output, so we add the weight for that
+	// too.
+	utx := btcutil.NewTx(commitTx)
+	weight := blockchain.GetTransactionWeight(utx) +
+		input.WitnessCommitmentTxWeight
+
+	fee := chanState.Capacity
+	for _, out := range commitTx.TxOut {
+		fee -= btcutil.Amount(out.Value)
+	}
+
+	return &AnchorResolution{
+		CommitAnchor: wire.OutPoint{
+			Hash:  commitTx.TxHash(),
+			Index: index,
+		},
+		AnchorSignDescriptor: signDesc,
+		CommitWeight:         lntypes.WeightUnit(weight),
+		CommitFee:            fee,
+		CommitBalance:        ourBalance,
+	}, nil
+}
+
 // AvailableBalance returns the current balance available for sending within
 // the channel. By available balance, we mean that if at this very instance a
 // new commitment were to be created which evals all the log entries, what
@@ -8703,6 +9355,11 @@ func (lc *LightningChannel) UpdateFee(feePerKw chainfee.SatPerKWeight) error {
 		return fmt.Errorf("local fee update as non-initiator")
 	}
 
+	// The fee rate of zero-fee commitments is fixed at zero.
+	if lc.channelState.ChanType.HasZeroFeeCommitments() {
+		return ErrZeroFeeCommitFeeUpdate
+	}
+
 	// Ensure that the passed fee rate meets our current requirements.
 	if err := lc.validateFeeRate(feePerKw); err != nil {
 		return err
@@ -8780,6 +9437,11 @@ func (lc *LightningChannel) ReceiveUpdateFee(feePerKw chainfee.SatPerKWeight) er
 		return fmt.Errorf("received fee update as initiator")
 	}
 
+	// The fee rate of zero-fee commitments is fixed at zero.
+	if lc.channelState.ChanType.HasZeroFeeCommitments() {
+		return ErrZeroFeeCommitFeeUpdate
+	}
+
 	// TODO(roasbeef): or just modify to use the other balance?
 	pd := &paymentDescriptor{
 		ChanID:    lc.ChannelID(),
@@ -8865,6 +9527,10 @@ type closeTxOpts struct {
 	// transaction outputs. If this isn't set, then the default BIP-69
 	// sorting is used.
 	customSort CloseSortFunc
+
+	// customSequence is an optional sequence to use on the funding input.
+	// If set, this takes precedence over enableRBF.
+	customSequence fn.Option[uint32]
 }
 
 // defaultCloseTxOpts returns a closeTxOpts struct with default values.
@@ -8901,6 +9567,14 @@ func WithCustomTxSort(sorter CloseSortFunc) CloseTxOpt {
 	}
 }
 
+// WithTxSequence can be used to set the sequence of the funding input of the
+// close transaction.
+func WithTxSequence(sequence uint32) CloseTxOpt {
+	return func(opts *closeTxOpts) {
+		opts.customSequence = fn.Some(sequence)
+	}
+}
+
 // CreateCooperativeCloseTx creates a transaction which if signed by both
 // parties, then broadcast cooperatively closes an active channel. The creation
 // of the closure transaction is modified by a boolean indicating if the party
@@ -8922,6 +9596,9 @@ func CreateCooperativeCloseTx(fundingTxIn wire.TxIn,
 	if opts.enableRBF {
 		fundingTxIn.Sequence = mempool.MaxRBFSequence
 	}
+	opts.customSequence.WhenSome(func(sequence uint32) {
+		fundingTxIn.Sequence = sequence
+	})
 
 	// Construct the transaction to perform a cooperative closure of the
 	// channel. In the event that one side doesn't have any settled funds
@@ -9062,13 +9739,16 @@ func (lc *LightningChannel) LocalBalanceDust() (bool, btcutil.Amount) {
 	defer lc.RUnlock()
 
 	chanState := lc.channelState
-	localBalance := chanState.LocalCommitment.LocalBalance.ToSatoshis()
+	localCommit := chanState.LocalCommitment
+	localBalance := localCommit.LocalBalance.ToSatoshis()
 
 	// If this is an anchor channel, and we're the initiator, then we'll
 	// regain the stats allocated to the anchor outputs with the co-op
 	// close transaction.
-	if chanState.ChanType.HasAnchors() && chanState.IsInitiator {
-		localBalance += 2 * AnchorSize
+	if chanState.IsInitiator {
+		localBalance += AnchorOutputsValue(chanState.ChanTypeAt(
+			lntypes.Local, localCommit.CommitHeight,
+		))
 	}
 
 	localDust := chanState.LocalChanCfg.DustLimit
@@ -9084,13 +9764,16 @@ func (lc *LightningChannel) RemoteBalanceDust() (bool, btcutil.Amount) {
 	defer lc.RUnlock()
 
 	chanState := lc.channelState
-	remoteBalance := chanState.RemoteCommitment.RemoteBalance.ToSatoshis()
+	remoteCommit := chanState.RemoteCommitment
+	remoteBalance := remoteCommit.RemoteBalance.ToSatoshis()
 
 	// If this is an anchor channel, and they're the initiator, then we'll
 	// regain the stats allocated to the anchor outputs with the co-op
 	// close transaction.
-	if chanState.ChanType.HasAnchors() && !chanState.IsInitiator {
-		remoteBalance += 2 * AnchorSize
+	if !chanState.IsInitiator {
+		remoteBalance += AnchorOutputsValue(chanState.ChanTypeAt(
+			lntypes.Remote, remoteCommit.CommitHeight,
+		))
 	}
 
 	remoteDust := chanState.RemoteChanCfg.DustLimit
@@ -9110,12 +9793,13 @@ func (lc *LightningChannel) CommitBalances() (btcutil.Amount, btcutil.Amount) {
 	localBalance := localCommit.LocalBalance.ToSatoshis()
 	remoteBalance := localCommit.RemoteBalance.ToSatoshis()
 
-	if chanState.ChanType.HasAnchors() {
-		if chanState.IsInitiator {
-			localBalance += 2 * AnchorSize
-		} else {
-			remoteBalance += 2 * AnchorSize
-		}
+	anchorsValue := AnchorOutputsValue(chanState.ChanTypeAt(
+		lntypes.Local, localCommit.CommitHeight,
+	))
+	if chanState.IsInitiator {
+		localBalance += anchorsValue
+	} else {
+		remoteBalance += anchorsValue
 	}
 
 	return localBalance, remoteBalance
@@ -1006,6 +1006,24 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 		require.Nil(t,
 			res.RemotePending, "expected no anchor resolution",
 		)
+
+		// As the commitment hasn't been broadcast yet, it isn't
+		// attached to the local resolution.
+		require.Nil(t, res.Local.CommitTx)
+
+		// Once broadcast, the signed commitment is attached so it can
+		// be submitted together with the anchor sweep.
+		err = aliceChannel.MarkCommitmentBroadcasted(
+			closeSummary.CloseTx, lntypes.Local,
+		)
+		require.NoError(t, err)
+
+		res, err = aliceChannel.NewAnchorResolutions()
+		require.NoError(t, err)
+		require.NotNil(t, res.Local.CommitTx)
+		require.Equal(t, closeSummary.CloseTx.TxHash(),
+			res.Local.CommitTx.TxHash())
+		require.Nil(t, res.Remote.CommitTx)
 	}
 
 	// The SelfOutputSignDesc should be non-nil since the output to-self is
@@ -2875,6 +2893,85 @@ func TestUpdateFeeMultipleUpdates(t *testing.T) {
 	require.NoError(t, err, "bob unable to process alice's revocation")
 }
 
+// TestZeroFeeCommitments tests that the commitments of a zero-fee commitment
+// channel are TRUC transactions that pay no fee, with a single pay-to-anchor
+// output carrying the value of trimmed HTLCs, and that fee updates are
+// rejected.
+func TestZeroFeeCommitments(t *testing.T) {
+	t.Parallel()
+
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit |
+		channeldb.ZeroFeeCommitmentsBit
+
+	aliceChannel, bobChannel, err := CreateTestChannels(t, chanType)
+	require.NoError(t, err, "unable to create test channels")
+
+	// Alice adds an HTLC that is trimmed from both commitments, and one
+	// that isn't.
+	const (
+		dustHtlcAmt = btcutil.Amount(150)
+		htlcAmt     = btcutil.Amount(50_000)
+	)
+	dustHtlc, _ := createHTLC(0, lnwire.NewMSatFromSatoshis(dustHtlcAmt))
+	addAndReceiveHTLC(t, aliceChannel, bobChannel, dustHtlc, nil)
+
+	htlc, _ := createHTLC(1, lnwire.NewMSatFromSatoshis(htlcAmt))
+	addAndReceiveHTLC(t, aliceChannel, bobChannel, htlc, nil)
+
+	err = ForceStateTransition(aliceChannel, bobChannel)
+	require.NoError(t, err, "unable to complete state transition")
+
+	capacity := aliceChannel.channelState.Capacity
+	for _, c := range []*LightningChannel{aliceChannel, bobChannel} {
+		commitTx := c.channelState.LocalCommitment.CommitTx
+		require.EqualValues(t, 3, commitTx.Version)
+
+		// The commitment has a single anchor output that holds the
+		// value of the trimmed HTLC, so it pays no fee.
+		var (
+			numAnchors int
+			totalOut   btcutil.Amount
+		)
+		for _, txOut := range commitTx.TxOut {
+			totalOut += btcutil.Amount(txOut.Value)
+
+			if !input.IsPayToAnchor(txOut.PkScript) {
+				continue
+			}
+
+			numAnchors++
+			require.EqualValues(t, dustHtlcAmt, txOut.Value)
+		}
+		require.Equal(t, 1, numAnchors)
+		require.Equal(t, capacity, totalOut)
+		require.Zero(t, c.channelState.LocalCommitment.CommitFee)
+	}
+
+	// The anchor resolution of the local commitment covers the
+	// pay-to-anchor output and tracks our balance at stake.
+	anchors, err := aliceChannel.NewAnchorResolutions()
+	require.NoError(t, err)
+	require.NotNil(t, anchors.Local)
+
+	signDesc := anchors.Local.AnchorSignDescriptor
+	require.True(t, input.IsPayToAnchor(signDesc.Output.PkScript))
+	require.EqualValues(t, dustHtlcAmt, signDesc.Output.Value)
+
+	aliceBalance := aliceChannel.channelState.LocalCommitment.LocalBalance
+	require.Equal(t, aliceBalance.ToSatoshis(), anchors.Local.CommitBalance)
+
+	// Neither party may update the fee of the commitments.
+	require.ErrorIs(
+		t, aliceChannel.UpdateFee(chainfee.FeePerKwFloor),
+		ErrZeroFeeCommitFeeUpdate,
+	)
+	require.ErrorIs(
+		t, bobChannel.ReceiveUpdateFee(chainfee.FeePerKwFloor),
+		ErrZeroFeeCommitFeeUpdate,
+	)
+}
+
 // TestAddHTLCNegativeBalance tests that if enough HTLC's are added to the
 // state machine to drive the balance to zero, then the next HTLC attempted to
 // be added will result in an error being returned.
@@ -9719,8 +9816,10 @@ func TestCreateHtlcRetribution(t *testing.T) {
 
 	// Create the htlc retribution.
 	hr, err := createHtlcRetribution(
-		aliceChannel.channelState, keyRing, commitHash,
-		dummyPrivate, leaseExpiry, htlc, fn.None[CommitAuxLeaves](),
+		aliceChannel.channelState, aliceChannel.channelState.ChanType,
+		keyRing, commitHash, dummyPrivate, leaseExpiry,
+		uint32(aliceChannel.channelState.RemoteChanCfg.CsvDelay), htlc,
+		fn.None[CommitAuxLeaves](),
 	)
 	// Expect no error.
 	require.NoError(t, err)
@@ -9768,6 +9867,7 @@ func TestCreateBreachRetribution(t *testing.T) {
 	leaseExpiry, keyRing, commitHash := deriveDummyRetributionParams(
 		aliceChannel.channelState,
 	)
+	theirDelay := uint32(aliceChannel.channelState.RemoteChanCfg.CsvDelay)
 	htlc := &channeldb.HTLCEntry{
 		Amt: tlv.NewRecordT[tlv.TlvType4](
 			tlv.NewBigSizeT(btcutil.Amount(testAmt)),
@@ -9924,8 +10024,9 @@ func TestCreateBreachRetribution(t *testing.T) {
 
 			br, our, their, err := createBreachRetribution(
 				tc.revocationLog, tx,
-				aliceChannel.channelState, keyRing,
-				dummyPrivate, leaseExpiry,
+				aliceChannel.channelState,
+				aliceChannel.channelState.ChanType, keyRing,
+				dummyPrivate, leaseExpiry, theirDelay,
 				fn.None[CommitAuxLeaves](),
 			)
 
@@ -9983,8 +10084,10 @@ func TestCreateBreachRetributionLegacy(t *testing.T) {
 
 	// Create the breach retribution using the legacy format.
 	br, ourAmt, theirAmt, err := createBreachRetributionLegacy(
-		&revokedLog, aliceChannel.channelState, keyRing,
+		&revokedLog, aliceChannel.channelState,
+		aliceChannel.channelState.ChanType, keyRing,
 		dummyPrivate, ourScript, theirScript, leaseExpiry,
+		aliceChannel.channelState.RemoteChanCfg.CommitmentParams,
 	)
 	require.NoError(t, err)
 
@@ -10172,7 +10275,8 @@ func TestExtractPayDescs(t *testing.T) {
 	// NOTE: we use nil commitment key rings to avoid checking the htlc
 	// scripts(`genHtlcScript`) as it should be tested independently.
 	incomingPDs, outgoingPDs, err := lnChan.extractPayDescs(
-		0, htlcs, lntypes.Dual[*CommitmentKeyRing]{}, lntypes.Local,
+		0, htlcs, lntypes.Dual[*CommitmentKeyRing]{},
+		lntypes.Dual[btcutil.Amount]{}, lntypes.Local,
 		fn.None[CommitAuxLeaves](),
 	)
 	require.NoError(t, err)
@@ -11172,3 +11276,317 @@ func TestCreateCooperativeCloseTx(t *testing.T) {
 		})
 	}
 }
+
+// TestUpdateChannelParams tests that the channel state bounds can only be
+// updated while the channel is quiescent, that the new bounds are validated,
+// and that they are enforced for subsequent updates.
+func TestUpdateChannelParams(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceState := aliceChannel.State()
+	localBounds := aliceState.LocalChanCfg.ChannelStateBounds
+	remoteBounds := aliceState.RemoteChanCfg.ChannelStateBounds
+	localParams := aliceState.LocalChanCfg.CommitmentParams
+	remoteParams := aliceState.RemoteChanCfg.CommitmentParams
+	chanType := aliceState.ChanType
+
+	// We'll only allow Bob to offer us a single HTLC at a time. Bob's
+	// perspective of the bounds is the mirror image of Alice's.
+	newRemoteBounds := remoteBounds
+	newRemoteBounds.MaxAcceptedHtlcs = 5
+
+	// Alice adds an HTLC which isn't yet committed, so the channel isn't
+	// quiescent and the update should be refused.
+	htlc, _ := createHTLC(0, lnwire.MilliSatoshi(10_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, newRemoteBounds, localParams, remoteParams,
+		chanType,
+	)
+	require.ErrorIs(t, err, ErrChanNotQuiescent)
+
+	// Once the HTLC is fully locked in, the channel is quiescent again.
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// A set of bounds that violates our constraints should be rejected,
+	// such as a channel reserve above 20% of the channel capacity.
+	invalidBounds := newRemoteBounds
+	invalidBounds.ChanReserve = aliceState.Capacity/5 + 1
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, invalidBounds, localParams, remoteParams,
+		chanType,
+	)
+	require.Error(t, err)
+
+	// The same goes for a dust limit outside of the range we'd accept
+	// when opening a channel, and a zero csv delay.
+	invalidParams := remoteParams
+	invalidParams.DustLimit = 3*DustLimitUnknownWitness() + 1
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, newRemoteBounds, localParams, invalidParams,
+		chanType,
+	)
+	require.ErrorContains(t, err, "dust limit")
+
+	invalidParams = remoteParams
+	invalidParams.CsvDelay = 0
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, newRemoteBounds, localParams, invalidParams,
+		chanType,
+	)
+	require.Error(t, err)
+
+	// Valid bounds should be applied by both parties.
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, newRemoteBounds, localParams, remoteParams,
+		chanType,
+	)
+	require.NoError(t, err)
+	err = bobChannel.UpdateChannelParams(
+		newRemoteBounds, localBounds, remoteParams, localParams,
+		chanType,
+	)
+	require.NoError(t, err)
+
+	require.Equal(
+		t, newRemoteBounds, aliceState.RemoteChanCfg.ChannelStateBounds,
+	)
+	require.Equal(
+		t, newRemoteBounds,
+		bobChannel.State().LocalChanCfg.ChannelStateBounds,
+	)
+
+	// Bob should now be unable to offer more HTLCs than the new limit.
+	for i := 0; i < 5; i++ {
+		htlc, _ := createHTLC(i, lnwire.MilliSatoshi(10_000_000))
+		_, err = bobChannel.AddHTLC(htlc, nil)
+		require.NoError(t, err)
+		_, err = aliceChannel.ReceiveHTLC(htlc)
+		require.NoError(t, err)
+	}
+
+	htlc, _ = createHTLC(5, lnwire.MilliSatoshi(10_000_000))
+	_, err = bobChannel.AddHTLC(htlc, nil)
+	require.ErrorIs(t, err, ErrMaxHTLCNumber)
+}
+
+// TestUpdateChannelParamsCommitments tests that changed commitment params only
+// apply to commitments created after the change, while breach retributions
+// and force close resolutions of earlier commitments use the params those
+// commitments were created with.
+func TestUpdateChannelParamsCommitments(t *testing.T) {
+	t.Parallel()
+
+	// We use zero-fee HTLC transactions, so whether an HTLC is dust only
+	// depends on the dust limit.
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit|
+			channeldb.AnchorOutputsBit|channeldb.ZeroHtlcTxFeeBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceState := aliceChannel.State()
+	bobState := bobChannel.State()
+
+	// Alice offers an HTLC that is above her current dust limit, so it
+	// materializes on her commitment.
+	htlc, _ := createHTLC(0, lnwire.NewMSatFromSatoshis(500))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// Two anchors, two balance outputs and the HTLC.
+	require.Len(t, aliceState.LocalCommitment.CommitTx.TxOut, 5)
+
+	// We'll capture Bob's current commitment, which will later be
+	// breached after the params have changed.
+	oldBobDelay := bobState.LocalChanCfg.CsvDelay
+	revokedStateNum := aliceState.RemoteCommitment.CommitHeight
+	breachTx := aliceState.RemoteCommitment.CommitTx
+
+	// Alice raises her dust limit above the HTLC amount, and requires Bob
+	// to use a longer csv delay.
+	localBounds := aliceState.LocalChanCfg.ChannelStateBounds
+	remoteBounds := aliceState.RemoteChanCfg.ChannelStateBounds
+	localParams := aliceState.LocalChanCfg.CommitmentParams
+	localParams.DustLimit = 2 * DustLimitUnknownWitness()
+	remoteParams := aliceState.RemoteChanCfg.CommitmentParams
+	remoteParams.CsvDelay = oldBobDelay * 2
+	chanType := aliceState.ChanType
+
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams,
+		chanType,
+	)
+	require.NoError(t, err)
+	err = bobChannel.UpdateChannelParams(
+		remoteBounds, localBounds, remoteParams, localParams,
+		chanType,
+	)
+	require.NoError(t, err)
+
+	// The current commitments were created with the old params, so they
+	// can still be resolved with them.
+	require.Equal(t, oldBobDelay, aliceState.CommitParamsAt(
+		lntypes.Remote, revokedStateNum,
+	).CsvDelay)
+	closeSummary, err := aliceChannel.ForceClose()
+	require.NoError(t, err)
+	require.Len(t, closeSummary.HtlcResolutions.OutgoingHTLCs, 1)
+
+	// The next commitments use the new params. Bob's commitment is
+	// revoked as part of the state transition.
+	htlc, _ = createHTLC(1, lnwire.NewMSatFromSatoshis(600))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// Both HTLCs are now dust on Alice's commitment.
+	require.Len(t, aliceState.LocalCommitment.CommitTx.TxOut, 4)
+
+	closeSummary, err = bobChannel.ForceClose()
+	require.NoError(t, err)
+	require.EqualValues(
+		t, remoteParams.CsvDelay,
+		closeSummary.CommitResolution.MaturityDelay,
+	)
+
+	// Breaching Bob's revoked commitment must be punished using the csv
+	// delay it was created with, otherwise we wouldn't be able to locate
+	// and sweep his output.
+	breachRet, err := NewBreachRetribution(
+		aliceState, revokedStateNum, 100, breachTx,
+		fn.None[AuxLeafStore](), fn.None[AuxContractResolver](),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, oldBobDelay, breachRet.RemoteDelay)
+	require.NotNil(t, breachRet.RemoteOutputSignDesc)
+
+	breachOutput := breachTx.TxOut[breachRet.RemoteOutpoint.Index]
+	require.Equal(
+		t, breachOutput.PkScript,
+		breachRet.RemoteOutputSignDesc.Output.PkScript,
+	)
+}
+
+// TestUpdateChannelParamsChanTypeUpgrade tests that a tweakless channel can be
+// upgraded to anchor outputs with zero-fee HTLC transactions, and that
+// commitments created before the upgrade are still resolved as such.
+func TestUpdateChannelParamsChanTypeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceState := aliceChannel.State()
+	bobState := bobChannel.State()
+	localBounds := aliceState.LocalChanCfg.ChannelStateBounds
+	remoteBounds := aliceState.RemoteChanCfg.ChannelStateBounds
+	localParams := aliceState.LocalChanCfg.CommitmentParams
+	remoteParams := aliceState.RemoteChanCfg.CommitmentParams
+	oldChanType := aliceState.ChanType
+	newChanType := AnchorsUpgradeChanType(oldChanType)
+
+	// Switching to taproot requires a new funding output, so it must be
+	// refused.
+	err = aliceChannel.ValidateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams,
+		oldChanType|channeldb.SimpleTaprootFeatureBit,
+	)
+	require.ErrorIs(t, err, ErrUnsupportedChanTypeUpgrade)
+
+	// The upgrade is also refused while there are HTLCs on the
+	// commitments, as they'd need to be re-signed.
+	htlc, preimage := createHTLC(0, lnwire.MilliSatoshi(10_000_000))
+	addIdx, err := aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	recvIdx, err := bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	err = aliceChannel.ValidateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams,
+		newChanType,
+	)
+	require.ErrorIs(t, err, ErrUnsupportedChanTypeUpgrade)
+
+	require.NoError(t, bobChannel.SettleHTLC(preimage, recvIdx, nil, nil,
+		nil))
+	require.NoError(t, aliceChannel.ReceiveHTLCSettle(preimage, addIdx))
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	// We'll capture Bob's current commitment, which will later be
+	// breached after the upgrade.
+	revokedStateNum := aliceState.RemoteCommitment.CommitHeight
+	breachTx := aliceState.RemoteCommitment.CommitTx
+	require.Len(t, breachTx.TxOut, 2)
+
+	err = aliceChannel.UpdateChannelParams(
+		localBounds, remoteBounds, localParams, remoteParams,
+		newChanType,
+	)
+	require.NoError(t, err)
+	err = bobChannel.UpdateChannelParams(
+		remoteBounds, localBounds, remoteParams, localParams,
+		newChanType,
+	)
+	require.NoError(t, err)
+	require.Equal(t, newChanType, aliceState.ChanType)
+	require.Equal(t, newChanType, bobState.ChanType)
+
+	// The current commitments are still resolved as tweakless ones, so
+	// there's no anchor to sweep.
+	require.Equal(t, oldChanType, aliceState.ChanTypeAt(
+		lntypes.Remote, revokedStateNum,
+	))
+	closeSummary, err := aliceChannel.ForceClose()
+	require.NoError(t, err)
+	require.Nil(t, closeSummary.AnchorResolution)
+
+	// The next commitments have anchor outputs. Bob's commitment is
+	// revoked as part of the state transition.
+	htlc, _ = createHTLC(1, lnwire.MilliSatoshi(10_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// Two anchors, two balance outputs and the HTLC.
+	require.Len(t, aliceState.LocalCommitment.CommitTx.TxOut, 5)
+	require.Len(t, bobState.LocalCommitment.CommitTx.TxOut, 5)
+
+	closeSummary, err = bobChannel.ForceClose()
+	require.NoError(t, err)
+	require.NotNil(t, closeSummary.AnchorResolution)
+
+	// Breaching Bob's revoked tweakless commitment must still be
+	// punishable.
+	breachRet, err := NewBreachRetribution(
+		aliceState, revokedStateNum, 100, breachTx,
+		fn.None[AuxLeafStore](), fn.None[AuxContractResolver](),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, breachRet.RemoteOutputSignDesc)
+
+	breachOutput := breachTx.TxOut[breachRet.RemoteOutpoint.Index]
+	require.Equal(
+		t, breachOutput.PkScript,
+		breachRet.RemoteOutputSignDesc.Output.PkScript,
+	)
+}
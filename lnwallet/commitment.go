@@ -2,6 +2,7 @@ package lnwallet
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/btcsuite/btcd/blockchain"
@@ -21,6 +22,13 @@ import (
 // AnchorSize is the constant anchor output size.
 const AnchorSize = btcutil.Amount(330)
 
+// MaxPayToAnchorValue is the maximum value of the pay-to-anchor (P2A) output of
+// a zero-fee commitment. P2A outputs are exempt from the dust threshold, so the
+// anchor may carry any value up to this cap. The cap limits the value moved
+// into the anchor from trimmed HTLCs and from balances lost to millisatoshi
+// rounding, and any excess goes to miners instead.
+const MaxPayToAnchorValue = btcutil.Amount(240)
+
 // DefaultAnchorsCommitMaxFeeRateSatPerVByte is the default max fee rate in
 // sat/vbyte the initiator will use for anchor channels. This should be enough
 // to ensure propagation before anchoring down the commitment transaction.
@@ -240,10 +248,10 @@ func CommitScriptToSelf(chanType channeldb.ChannelType, initiator bool,
 			csvDelay, selfKey, revokeKey, auxLeaf,
 		)
 
-	// If we are the initiator of a leased channel, then we have an
+	// If we sold the lease of a leased channel, then we have an
 	// additional CLTV requirement in addition to the usual CSV
 	// requirement.
-	case initiator && chanType.HasLeaseExpiration():
+	case chanType.IsLessor(initiator):
 		toLocalRedeemScript, err := input.LeaseCommitScriptToSelf(
 			selfKey, revokeKey, csvDelay, leaseExpiry,
 		)
@@ -295,10 +303,10 @@ func CommitScriptToRemote(chanType channeldb.ChannelType, initiator bool,
 	auxLeaf input.AuxTapLeaf) (input.ScriptDescriptor, uint32, error) {
 
 	switch {
-	// If we are not the initiator of a leased channel, then the remote
-	// party has an additional CLTV requirement in addition to the 1 block
-	// CSV requirement.
-	case chanType.HasLeaseExpiration() && !initiator:
+	// If the remote party sold the lease of a leased channel, then it has
+	// an additional CLTV requirement in addition to the 1 block CSV
+	// requirement.
+	case chanType.IsLessor(!initiator):
 		script, err := input.LeaseCommitScriptToRemoteConfirmed(
 			remoteKey, leaseExpiry,
 		)
@@ -430,10 +438,10 @@ func SecondLevelHtlcScript(chanType channeldb.ChannelType, initiator bool,
 			revocationKey, delayKey, csvDelay, auxLeaf,
 		)
 
-	// If we are the initiator of a leased channel, then we have an
+	// If we sold the lease of a leased channel, then we have an
 	// additional CLTV requirement in addition to the usual CSV
 	// requirement.
-	case initiator && chanType.HasLeaseExpiration():
+	case chanType.IsLessor(initiator):
 		witnessScript, err := input.LeaseSecondLevelHtlcScript(
 			revocationKey, delayKey, csvDelay, leaseExpiry,
 		)
@@ -477,6 +485,10 @@ func CommitWeight(chanType channeldb.ChannelType) lntypes.WeightUnit {
 	case chanType.IsTaproot():
 		return input.TaprootCommitWeight
 
+	// Zero-fee commitments only have a single, keyless anchor output.
+	case chanType.HasZeroFeeCommitments():
+		return input.ZeroFeeCommitWeight
+
 	// If this commitment has anchors, it will be slightly heavier.
 	case chanType.HasAnchors():
 		return input.AnchorCommitWeight
@@ -686,15 +698,36 @@ type unsignedCommitmentTx struct {
 	cltvs []uint32
 }
 
+// commitFunding describes the funding output spent by a commitment
+// transaction.
+type commitFunding struct {
+	// txIn is the input spending the funding output.
+	txIn wire.TxIn
+
+	// capacity is the value of the funding output.
+	capacity btcutil.Amount
+}
+
+// channelFunding returns the current funding output of the channel.
+func channelFunding(chanState *channeldb.OpenChannel) commitFunding {
+	return commitFunding{
+		txIn:     fundingTxIn(chanState),
+		capacity: chanState.Capacity,
+	}
+}
+
 // createUnsignedCommitmentTx generates the unsigned commitment transaction for
 // a commitment view and returns it as part of the unsignedCommitmentTx. The
 // passed in balances should be balances *before* subtracting any commitment
-// fees, but after anchor outputs.
+// fees, but after anchor outputs. The transaction spends the passed funding
+// output, which is the current funding output of the channel unless the
+// commitment is created for a pending splice.
 func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 	theirBalance lnwire.MilliSatoshi, whoseCommit lntypes.ChannelParty,
 	feePerKw chainfee.SatPerKWeight, height uint64, originalHtlcView,
 	filteredHTLCView *HtlcView, keyRing *CommitmentKeyRing,
-	prevCommit *commitment) (*unsignedCommitmentTx, error) {
+	prevCommit *commitment,
+	funding commitFunding) (*unsignedCommitmentTx, error) {
 
 	dustLimit := cb.chanState.LocalChanCfg.DustLimit
 	if whoseCommit.IsRemote() {
@@ -734,7 +767,12 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 
 	// With the weight known, we can now calculate the commitment fee,
 	// ensuring that we account for any dust outputs trimmed above.
+	// Zero-fee commitments don't pay any fee, they are fee bumped by a
+	// child spending their anchor output instead.
 	commitFee := feePerKw.FeeForWeight(totalCommitWeight)
+	if cb.chanState.ChanType.HasZeroFeeCommitments() {
+		commitFee = 0
+	}
 	commitFeeMSat := lnwire.NewMSatFromSatoshis(commitFee)
 
 	// Currently, within the protocol, the initiator always pays the fees.
@@ -784,7 +822,7 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 	}
 	if whoseCommit.IsLocal() {
 		commitTx, err = CreateCommitTx(
-			cb.chanState.ChanType, fundingTxIn(cb.chanState), keyRing,
+			cb.chanState.ChanType, funding.txIn, keyRing,
 			&cb.chanState.LocalChanCfg, &cb.chanState.RemoteChanCfg,
 			ourBalance.ToSatoshis(), theirBalance.ToSatoshis(),
 			numHTLCs, cb.chanState.IsInitiator, leaseExpiry,
@@ -792,7 +830,7 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 		)
 	} else {
 		commitTx, err = CreateCommitTx(
-			cb.chanState.ChanType, fundingTxIn(cb.chanState), keyRing,
+			cb.chanState.ChanType, funding.txIn, keyRing,
 			&cb.chanState.RemoteChanCfg, &cb.chanState.LocalChanCfg,
 			theirBalance.ToSatoshis(), ourBalance.ToSatoshis(),
 			numHTLCs, !cb.chanState.IsInitiator, leaseExpiry,
@@ -884,6 +922,15 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 		htlcIndexes = append(htlcIndexes, htlc.HtlcIndex) //nolint
 	}
 
+	// Now that all outputs are known, the anchor output of a zero-fee
+	// commitment picks up the value of the trimmed HTLCs.
+	if cb.chanState.ChanType.HasZeroFeeCommitments() {
+		err := SetPayToAnchorValue(commitTx, funding.capacity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Set the state hint of the commitment transaction to facilitate
 	// quickly recovering the necessary penalty state in the case of an
 	// uncooperative broadcast.
@@ -917,11 +964,11 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 	for _, txOut := range commitTx.TxOut {
 		totalOut += btcutil.Amount(txOut.Value)
 	}
-	if totalOut+commitFee > cb.chanState.Capacity {
+	if totalOut+commitFee > funding.capacity {
 		return nil, fmt.Errorf("height=%v, for ChannelPoint(%v) "+
 			"attempts to consume %v while channel capacity is %v",
 			height, cb.chanState.FundingOutpoint,
-			totalOut+commitFee, cb.chanState.Capacity)
+			totalOut+commitFee, funding.capacity)
 	}
 
 	return &unsignedCommitmentTx{
@@ -978,8 +1025,13 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 
 	// Now that both output scripts have been created, we can finally create
 	// the transaction itself. We use a transaction version of 2 since CSV
-	// will fail unless the tx version is >= 2.
-	commitTx := wire.NewMsgTx(2)
+	// will fail unless the tx version is >= 2. Zero-fee commitments are
+	// TRUC transactions, which are signaled with version 3.
+	txVersion := int32(2)
+	if chanType.HasZeroFeeCommitments() {
+		txVersion = 3
+	}
+	commitTx := wire.NewMsgTx(txVersion)
 	commitTx.AddTxIn(&fundingOutput)
 
 	// Avoid creating dust outputs within the commitment transaction.
@@ -999,8 +1051,18 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 		})
 	}
 
+	switch {
+	// Zero-fee commitments always have a single anchor output that anyone
+	// can spend. Its value is set once all outputs of the commitment are
+	// known.
+	case chanType.HasZeroFeeCommitments():
+		commitTx.AddTxOut(&wire.TxOut{
+			PkScript: input.PayToAnchorScript(),
+			Value:    0,
+		})
+
 	// If this channel type has anchors, we'll also add those.
-	if chanType.HasAnchors() {
+	case chanType.HasAnchors():
 		localAnchor, remoteAnchor, err := CommitScriptAnchors(
 			chanType, localChanCfg, remoteChanCfg, keyRing,
 		)
@@ -1030,11 +1092,58 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 	return commitTx, nil
 }
 
+// SetPayToAnchorValue sets the value of the pay-to-anchor output of a zero-fee
+// commitment transaction to the part of the channel capacity that isn't paid
+// to any other output, capped at MaxPayToAnchorValue.
+func SetPayToAnchorValue(commitTx *wire.MsgTx, capacity btcutil.Amount) error {
+	var (
+		anchor   *wire.TxOut
+		totalOut btcutil.Amount
+	)
+	for _, txOut := range commitTx.TxOut {
+		if input.IsPayToAnchor(txOut.PkScript) {
+			anchor = txOut
+			continue
+		}
+
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+
+	if anchor == nil {
+		return errors.New("commitment transaction has no " +
+			"pay-to-anchor output")
+	}
+
+	if totalOut > capacity {
+		return fmt.Errorf("commitment outputs of %v exceed channel "+
+			"capacity %v", totalOut, capacity)
+	}
+
+	anchor.Value = int64(min(capacity-totalOut, MaxPayToAnchorValue))
+
+	return nil
+}
+
+// AnchorOutputsValue returns the value of the anchor outputs that is deducted
+// from the balance of the initiator on commitments of the given channel type.
+// The anchor output of zero-fee commitments is only funded by trimmed outputs,
+// so it doesn't reduce the balance of either party.
+func AnchorOutputsValue(chanType channeldb.ChannelType) btcutil.Amount {
+	if !chanType.HasAnchors() || chanType.HasZeroFeeCommitments() {
+		return 0
+	}
+
+	return 2 * AnchorSize
+}
+
 // CoopCloseBalance returns the final balances that should be used to create
-// the cooperative close tx, given the channel type and transaction fee.
+// the cooperative close tx, given the channel type and transaction fee. If a
+// fee payer is specified, then the close fee is deducted from the balance of
+// that party, otherwise the initiator pays the full fee.
 func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
-	coopCloseFee, ourBalance, theirBalance,
-	commitFee btcutil.Amount) (btcutil.Amount, btcutil.Amount, error) {
+	coopCloseFee, ourBalance, theirBalance, commitFee btcutil.Amount,
+	feePayer fn.Option[lntypes.ChannelParty]) (btcutil.Amount,
+	btcutil.Amount, error) {
 
 	// We'll make sure we account for the complete balance by adding the
 	// current dangling commitment fee to the balance of the initiator.
@@ -1042,13 +1151,7 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 
 	// Since the initiator's balance also is stored after subtracting the
 	// anchor values, add that back in case this was an anchor commitment.
-	if chanType.HasAnchors() {
-		initiatorDelta += 2 * AnchorSize
-	}
-
-	// The initiator will pay the full coop close fee, subtract that value
-	// from their balance.
-	initiatorDelta -= coopCloseFee
+	initiatorDelta += AnchorOutputsValue(chanType)
 
 	if isInitiator {
 		ourBalance += initiatorDelta
@@ -1056,11 +1159,23 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 		theirBalance += initiatorDelta
 	}
 
-	// During fee negotiation it should always be verified that the
-	// initiator can pay the proposed fee, but we do a sanity check just to
-	// be sure here.
+	// Unless specified otherwise, the initiator will pay the full coop
+	// close fee, so we subtract that value from their balance.
+	initiator := lntypes.Remote
+	if isInitiator {
+		initiator = lntypes.Local
+	}
+	if feePayer.UnwrapOr(initiator).IsLocal() {
+		ourBalance -= coopCloseFee
+	} else {
+		theirBalance -= coopCloseFee
+	}
+
+	// During fee negotiation it should always be verified that the fee
+	// payer can pay the proposed fee, but we do a sanity check just to be
+	// sure here.
 	if ourBalance < 0 || theirBalance < 0 {
-		return 0, 0, fmt.Errorf("initiator cannot afford proposed " +
+		return 0, 0, fmt.Errorf("fee payer cannot afford proposed " +
 			"coop close fee")
 	}
 
@@ -1280,24 +1395,33 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 	chanCommit := chanState.RemoteCommitment
 	_, commitmentPoint := btcec.PrivKeyFromBytes(revocationPreimage[:])
 
+	// The commitment may have been created before the channel type was
+	// upgraded, so we'll use the type it was created with.
+	chanType := chanState.ChanTypeAt(
+		lntypes.Remote, chanCommit.CommitHeight,
+	)
+
 	// With the commitment point generated, we can now derive the king ring
 	// which will be used to generate the output scripts.
 	keyRing := DeriveCommitmentKeys(
-		commitmentPoint, lntypes.Remote, chanState.ChanType,
+		commitmentPoint, lntypes.Remote, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
 	// Since it's remote commitment chain, we'd used the mirrored values.
 	//
-	// We use the remote's channel config for the csv delay.
-	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
+	// We use the csv delay of the remote's params the commitment was
+	// created with.
+	theirDelay := uint32(chanState.CommitParamsAt(
+		lntypes.Remote, chanCommit.CommitHeight,
+	).CsvDelay)
 
 	// If we are the initiator of this channel, then it's be false from the
 	// remote's PoV.
 	isRemoteInitiator := !chanState.IsInitiator
 
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 
@@ -1329,7 +1453,7 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 		},
 	)(auxResult.AuxLeaves)
 	theirScript, err := CommitScriptToSelf(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToLocalKey,
+		chanType, isRemoteInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, theirDelay, leaseExpiry, localAuxLeaf,
 	)
 	if err != nil {
@@ -1344,7 +1468,7 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 		},
 	)(auxResult.AuxLeaves)
 	ourScript, _, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry, remoteAuxLeaf,
 	)
 	if err != nil {
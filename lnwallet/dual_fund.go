@@ -0,0 +1,610 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// addDualFundContributionMsg carries the counterparty's contribution to a
+// dual-funded channel. Once processed, the funding transaction can be
+// constructed interactively with the counterparty.
+type addDualFundContributionMsg struct {
+	pendingFundingID uint64
+
+	contribution *ChannelContribution
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
+// completeDualFundTxMsg carries the funding transaction of a dual-funded
+// channel once it has been constructed interactively with the counterparty.
+// Once processed, our inputs to the funding transaction are signed and so is
+// the counterparty's version of the commitment transaction.
+type completeDualFundTxMsg struct {
+	pendingFundingID uint64
+
+	fundingTx *wire.MsgTx
+
+	prevOutFetcher txscript.PrevOutputFetcher
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
+// addDualFundCommitSigMsg carries the counterparty's signature for our
+// version of the commitment transaction of a dual-funded channel. Once it is
+// verified, the pending channel is written to disk.
+type addDualFundCommitSigMsg struct {
+	pendingFundingID uint64
+
+	theirCommitmentSig input.Signature
+
+	// This channel is used to return the pending channel once it has been
+	// written to disk.
+	completeChan chan *channeldb.OpenChannel
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
+// dualFundBalances returns the initial balances of a dual-funded channel,
+// where each party starts out with the amount it contributed and the opener
+// pays the fees of the commitment transaction. If the channel is leased, the
+// opener additionally pays the lease fee to the acceptor.
+//
+// NOTE: The balance of the opener underflows if it can't pay the fees, so
+// callers must check it before using it.
+func dualFundBalances(initiator bool, localAmt, remoteAmt btcutil.Amount,
+	feeMSat lnwire.MilliSatoshi, leaseFee btcutil.Amount) (
+	lnwire.MilliSatoshi, lnwire.MilliSatoshi) {
+
+	leaseFeeMSat := lnwire.NewMSatFromSatoshis(leaseFee)
+	ourBalance := lnwire.NewMSatFromSatoshis(localAmt)
+	theirBalance := lnwire.NewMSatFromSatoshis(remoteAmt)
+	if initiator {
+		ourBalance -= leaseFeeMSat + feeMSat
+		theirBalance += leaseFeeMSat
+	} else {
+		ourBalance += leaseFeeMSat
+		theirBalance -= leaseFeeMSat + feeMSat
+	}
+
+	return ourBalance, theirBalance
+}
+
+// SetDualFundRemoteAmt records the amount the counterparty contributes to a
+// dual-funded channel we opened. This updates the capacity and the initial
+// balances of the channel, and must be called before the constraints of the
+// counterparty are committed, as those are verified against the capacity.
+func (r *ChannelReservation) SetDualFundRemoteAmt(amt btcutil.Amount) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.dualFundOpener.IsNone() {
+		return fmt.Errorf("reservation is not for a dual-funded " +
+			"channel")
+	}
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return fmt.Errorf("dual-funded channels require a wallet "+
+			"backed funding intent, got %T", r.fundingIntent)
+	}
+
+	chanState := r.partialState
+	feeMSat := lnwire.NewMSatFromSatoshis(
+		chanState.LocalCommitment.CommitFee +
+			AnchorOutputsValue(chanState.ChanType),
+	)
+
+	localAmt := fullIntent.LocalFundingAmt()
+	ourBalance, theirBalance := dualFundBalances(
+		chanState.IsInitiator, localAmt, amt, feeMSat, r.leaseFee,
+	)
+
+	openerBalance := theirBalance
+	if chanState.IsInitiator {
+		openerBalance = ourBalance
+	}
+	if int64(openerBalance) < 0 && r.leaseFee != 0 {
+		return ErrLeaseFeeUnpaid(r.leaseFee)
+	}
+	if int64(openerBalance) < 0 {
+		return ErrFunderBalanceDust(
+			int64(chanState.LocalCommitment.CommitFee),
+			int64(openerBalance.ToSatoshis()),
+			int64(2*DustLimitUnknownWitness()),
+		)
+	}
+
+	fullIntent.SetRemoteFundingAmt(amt)
+
+	chanState.Capacity = localAmt + amt
+	chanState.LocalCommitment.LocalBalance = ourBalance
+	chanState.LocalCommitment.RemoteBalance = theirBalance
+	chanState.RemoteCommitment.LocalBalance = ourBalance
+	chanState.RemoteCommitment.RemoteBalance = theirBalance
+	chanState.InitialLocalBalance = ourBalance
+	chanState.InitialRemoteBalance = theirBalance
+
+	r.ourContribution.FundingAmount = ourBalance.ToSatoshis()
+	r.theirContribution.FundingAmount = theirBalance.ToSatoshis()
+
+	return nil
+}
+
+// ProcessDualFundContribution verifies and records the counterparty's
+// contribution to a pending dual-funded channel. Afterwards, the funding
+// transaction can be constructed interactively with the counterparty, and
+// passed to CompleteDualFundTx once complete.
+func (r *ChannelReservation) ProcessDualFundContribution(
+	theirContribution *ChannelContribution) error {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addDualFundContributionMsg{
+		pendingFundingID: r.reservationID,
+		contribution:     theirContribution,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
+// DualFundTxContribution returns the inputs and change outputs we contribute
+// to the funding transaction of a dual-funded channel, along with the funding
+// output. As the funding output commits to both multi-sig keys, this must be
+// called after ProcessDualFundContribution.
+func (r *ChannelReservation) DualFundTxContribution() ([]wire.OutPoint,
+	[]*wire.TxOut, *wire.TxOut, error) {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("dual-funded channels "+
+			"require a wallet backed funding intent, got %T",
+			r.fundingIntent)
+	}
+
+	_, fundingOutput, err := fullIntent.FundingOutput()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return fullIntent.Inputs(), fullIntent.ChangeOutputs, fundingOutput,
+		nil
+}
+
+// CompleteDualFundTx signs our inputs to the interactively constructed
+// funding transaction of a dual-funded channel, and generates our signature
+// for the counterparty's version of the commitment transaction, which is
+// returned. The passed fetcher must be able to return the previous outputs
+// of all inputs of the funding transaction.
+//
+// NOTE: The witnesses of the counterparty's inputs aren't known yet at this
+// point, so the returned transaction can't be broadcast.
+func (r *ChannelReservation) CompleteDualFundTx(fundingTx *wire.MsgTx,
+	prevOutFetcher txscript.PrevOutputFetcher) (input.Signature, error) {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &completeDualFundTxMsg{
+		pendingFundingID: r.reservationID,
+		fundingTx:        fundingTx,
+		prevOutFetcher:   prevOutFetcher,
+		err:              errChan,
+	}
+
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
+
+	_, commitSig := r.OurSignatures()
+
+	return commitSig, nil
+}
+
+// CompleteDualFundReservation verifies the counterparty's signature for our
+// version of the commitment transaction of a dual-funded channel, and then
+// writes the pending channel to disk. Once this method returns, we're safe to
+// send the witnesses of our inputs to the funding transaction to the
+// counterparty.
+func (r *ChannelReservation) CompleteDualFundReservation(
+	commitSig input.Signature) (*channeldb.OpenChannel, error) {
+
+	errChan := make(chan error, 1)
+	completeChan := make(chan *channeldb.OpenChannel, 1)
+
+	r.wallet.msgChan <- &addDualFundCommitSigMsg{
+		pendingFundingID:   r.reservationID,
+		theirCommitmentSig: commitSig,
+		completeChan:       completeChan,
+		err:                errChan,
+	}
+
+	return <-completeChan, <-errChan
+}
+
+// handleDualFundContribution processes the counterparty's contribution to a
+// dual-funded channel.
+func (l *LightningWallet) handleDualFundContribution(
+	req *addDualFundContributionMsg) {
+
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	// Grab the mutex on the ChannelReservation to ensure thread-safety.
+	pendingReservation.Lock()
+	defer pendingReservation.Unlock()
+
+	fullIntent, ok := pendingReservation.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		req.err <- fmt.Errorf("dual-funded channels require a wallet "+
+			"backed funding intent, got %T",
+			pendingReservation.fundingIntent)
+		return
+	}
+
+	// Validate that the remote's UpfrontShutdownScript is a valid script
+	// if it's set.
+	shutdown := req.contribution.UpfrontShutdown
+	if len(shutdown) > 0 {
+		if !ValidateUpfrontShutdown(shutdown, &l.Cfg.NetParams) {
+			req.err <- fmt.Errorf("invalid shutdown script")
+			return
+		}
+	}
+
+	// The amount the counterparty contributes has already been recorded,
+	// so we carry it over to its contribution.
+	theirFundingAmt := pendingReservation.theirContribution.FundingAmount
+	pendingReservation.theirContribution = req.contribution
+	pendingReservation.theirContribution.FundingAmount = theirFundingAmt
+
+	// Perform bounds checking on both ChannelReserve and DustLimit
+	// parameters.
+	if !pendingReservation.validateReserveBounds() {
+		req.err <- fmt.Errorf("invalid reserve and dust bounds")
+		return
+	}
+
+	// With both multi-sig keys known, the funding output can now be
+	// created.
+	fullIntent.BindKeys(
+		&pendingReservation.ourContribution.MultiSigKey,
+		req.contribution.MultiSigKey.PubKey,
+	)
+
+	req.err <- nil
+}
+
+// handleCompleteDualFundTx signs our inputs to the funding transaction of a
+// dual-funded channel, along with the counterparty's version of the
+// commitment transaction.
+func (l *LightningWallet) handleCompleteDualFundTx(req *completeDualFundTxMsg) {
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	// Grab the mutex on the ChannelReservation to ensure thread-safety.
+	pendingReservation.Lock()
+	defer pendingReservation.Unlock()
+
+	fullIntent, ok := pendingReservation.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		req.err <- fmt.Errorf("dual-funded channels require a wallet "+
+			"backed funding intent, got %T",
+			pendingReservation.fundingIntent)
+		return
+	}
+
+	err := fullIntent.SignFundingTx(req.fundingTx, req.prevOutFetcher)
+	if err != nil {
+		req.err <- fmt.Errorf("unable to sign funding tx: %w", err)
+		return
+	}
+	chanPoint, err := fullIntent.ChanPoint()
+	if err != nil {
+		req.err <- fmt.Errorf("unable to obtain chan point: %w", err)
+		return
+	}
+
+	pendingReservation.fundingTx = req.fundingTx
+	pendingReservation.partialState.FundingOutpoint = *chanPoint
+
+	walletLog.Tracef("Dual-funded tx for ChannelPoint(%v) constructed",
+		chanPoint)
+
+	// With the channel point known, we can now create both commitment
+	// transactions and sign the counterparty's version.
+	l.handleChanPointReady(&continueContributionMsg{
+		pendingFundingID: req.pendingFundingID,
+		err:              req.err,
+	})
+}
+
+// handleDualFundCommitSig verifies the counterparty's signature for our
+// version of the commitment transaction of a dual-funded channel, and writes
+// the pending channel to disk.
+func (l *LightningWallet) handleDualFundCommitSig(
+	req *addDualFundCommitSigMsg) {
+
+	l.limboMtx.RLock()
+	res, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.RUnlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		req.completeChan <- nil
+		return
+	}
+
+	// Grab the mutex on the ChannelReservation to ensure thread-safety.
+	res.Lock()
+	defer res.Unlock()
+
+	res.theirCommitmentSig = req.theirCommitmentSig
+	commitTx := res.partialState.LocalCommitment.CommitTx
+
+	err := l.verifyCommitSig(res, req.theirCommitmentSig, commitTx)
+	if err != nil {
+		req.err <- fmt.Errorf("counterparty's commitment signature is "+
+			"invalid: %w", err)
+		req.completeChan <- nil
+		return
+	}
+
+	res.partialState.LocalCommitment.CommitSig =
+		req.theirCommitmentSig.Serialize()
+
+	_, bestHeight, err := l.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		req.err <- err
+		req.completeChan <- nil
+		return
+	}
+
+	res.partialState.LocalChanCfg = res.ourContribution.toChanConfig()
+	res.partialState.RemoteChanCfg = res.theirContribution.toChanConfig()
+
+	// We'll also record the funding transaction, which only carries the
+	// witnesses of our own inputs at this point. The opener replaces it
+	// once the counterparty has sent its witnesses.
+	res.partialState.FundingTxn = res.fundingTx
+
+	// Set optional upfront shutdown scripts on the channel state so that
+	// they are persisted. These values may be nil.
+	res.partialState.LocalShutdownScript =
+		res.ourContribution.UpfrontShutdown
+	res.partialState.RemoteShutdownScript =
+		res.theirContribution.UpfrontShutdown
+
+	res.partialState.RevocationKeyLocator = res.nextRevocationKeyLoc
+
+	err = res.partialState.SyncPending(res.nodeAddr, uint32(bestHeight))
+	if err != nil {
+		req.err <- err
+		req.completeChan <- nil
+		return
+	}
+
+	req.completeChan <- res.partialState
+	req.err <- nil
+
+	// Funding complete, this entry can be removed from limbo.
+	l.limboMtx.Lock()
+	delete(l.fundingLimbo, res.reservationID)
+	delete(l.reservationIDs, res.pendingChanID)
+	l.limboMtx.Unlock()
+
+	l.intentMtx.Lock()
+	delete(l.fundingIntents, res.pendingChanID)
+	l.intentMtx.Unlock()
+}
+
+// VerifyFundingTxInputs verifies the witnesses of all inputs of a funding
+// transaction, using the passed fetcher to obtain the outputs they spend.
+// This is used for dual-funded channels, where the inputs of the
+// counterparty are only known once it sent the witnesses for them.
+func VerifyFundingTxInputs(fundingTx *wire.MsgTx,
+	prevOutFetcher txscript.PrevOutputFetcher) error {
+
+	// All previous outputs must be known before computing the sighashes,
+	// which would panic otherwise.
+	for _, txIn := range fundingTx.TxIn {
+		prevOut := prevOutFetcher.FetchPrevOutput(txIn.PreviousOutPoint)
+		if prevOut == nil {
+			return fmt.Errorf("unknown previous output %v",
+				txIn.PreviousOutPoint)
+		}
+	}
+
+	sigHashes := txscript.NewTxSigHashes(fundingTx, prevOutFetcher)
+	for i, txIn := range fundingTx.TxIn {
+		prevOut := prevOutFetcher.FetchPrevOutput(txIn.PreviousOutPoint)
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, fundingTx, i,
+			txscript.StandardVerifyFlags, nil, sigHashes,
+			prevOut.Value, prevOutFetcher,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot create script engine: %w",
+				err)
+		}
+		if err := vm.Execute(); err != nil {
+			return fmt.Errorf("cannot validate input %v: %w",
+				txIn.PreviousOutPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// SetDualFundFeeRate updates our contribution to the funding transaction of a
+// dual-funded channel for a replacement at the given fee rate. We keep
+// spending the same inputs, so only our change is reduced to pay for the
+// higher fee. This must be called before the replacement is constructed with
+// DualFundTxContribution.
+func (r *ChannelReservation) SetDualFundFeeRate(
+	feeRate chainfee.SatPerKWeight) error {
+
+	r.Lock()
+	defer r.Unlock()
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return fmt.Errorf("dual-funded channels require a wallet "+
+			"backed funding intent, got %T", r.fundingIntent)
+	}
+
+	// Just like for the initial funding transaction, the opener pays for
+	// the funding output.
+	return fullIntent.UpdateChange(
+		feeRate, DustLimitForSize(input.P2WSHSize),
+		r.partialState.IsInitiator,
+	)
+}
+
+// SignDualFundInputs signs our inputs to a replacement of the funding
+// transaction of a dual-funded channel. The passed fetcher must be able to
+// return the previous outputs of all inputs of the transaction.
+func (r *ChannelReservation) SignDualFundInputs(fundingTx *wire.MsgTx,
+	prevOutFetcher txscript.PrevOutputFetcher) error {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return fmt.Errorf("dual-funded channels require a wallet "+
+			"backed funding intent, got %T", r.fundingIntent)
+	}
+
+	return fullIntent.SignFundingTx(fundingTx, prevOutFetcher)
+}
+
+// NewFundingCandidate creates the commitments spending the funding output of
+// the passed replacement of the funding transaction of a pending dual-funded
+// channel, and signs the counterparty's version. The balances of the channel
+// don't change, so the commitments are the ones of the channel spending the
+// new funding outpoint.
+func (l *LightningWallet) NewFundingCandidate(channel *channeldb.OpenChannel,
+	fundingTx *wire.MsgTx) (*channeldb.FundingCandidate, input.Signature,
+	error) {
+
+	if channel.ChanType.IsTaproot() {
+		return nil, nil, fmt.Errorf("funding transaction replacement " +
+			"not supported for taproot channels")
+	}
+
+	witnessScript, fundingOutput, err := input.GenFundingPkScript(
+		channel.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		channel.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		int64(channel.Capacity),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found, index := input.FindScriptOutputIndex(
+		fundingTx, fundingOutput.PkScript,
+	)
+	if !found || fundingTx.TxOut[index].Value != fundingOutput.Value {
+		return nil, nil, fmt.Errorf("funding output of %v not found "+
+			"in tx %v", channel.Capacity, fundingTx.TxHash())
+	}
+
+	candidate := &channeldb.FundingCandidate{
+		FundingTxn: fundingTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  fundingTx.TxHash(),
+			Index: index,
+		},
+		LocalCommitment:  channel.LocalCommitment,
+		RemoteCommitment: channel.RemoteCommitment,
+	}
+
+	localCommitTx := channel.LocalCommitment.CommitTx.Copy()
+	localCommitTx.TxIn[0].PreviousOutPoint = candidate.FundingOutpoint
+	candidate.LocalCommitment.CommitTx = localCommitTx
+	candidate.LocalCommitment.CommitSig = nil
+
+	remoteCommitTx := channel.RemoteCommitment.CommitTx.Copy()
+	remoteCommitTx.TxIn[0].PreviousOutPoint = candidate.FundingOutpoint
+	candidate.RemoteCommitment.CommitTx = remoteCommitTx
+
+	signDesc := input.SignDescriptor{
+		WitnessScript: witnessScript,
+		KeyDesc:       channel.LocalChanCfg.MultiSigKey,
+		Output:        fundingOutput,
+		HashType:      txscript.SigHashAll,
+		SigHashes:     input.NewTxSigHashesV0Only(remoteCommitTx),
+		InputIndex:    0,
+	}
+	sig, err := l.Cfg.Signer.SignOutputRaw(remoteCommitTx, &signDesc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return candidate, sig, nil
+}
+
+// VerifyFundingCandidate verifies the counterparty's signature for our version
+// of the commitment spending the funding output of the passed candidate, and
+// records it.
+func (l *LightningWallet) VerifyFundingCandidate(
+	channel *channeldb.OpenChannel, candidate *channeldb.FundingCandidate,
+	commitSig input.Signature) error {
+
+	localKey := channel.LocalChanCfg.MultiSigKey.PubKey
+	remoteKey := channel.RemoteChanCfg.MultiSigKey.PubKey
+	channelValue := int64(channel.Capacity)
+
+	witnessScript, _, err := input.GenFundingPkScript(
+		localKey.SerializeCompressed(), remoteKey.SerializeCompressed(),
+		channelValue,
+	)
+	if err != nil {
+		return err
+	}
+
+	commitTx := candidate.LocalCommitment.CommitTx
+	sigHash, err := txscript.CalcWitnessSigHash(
+		witnessScript, input.NewTxSigHashesV0Only(commitTx),
+		txscript.SigHashAll, commitTx, 0, channelValue,
+	)
+	if err != nil {
+		return err
+	}
+
+	if !commitSig.Verify(sigHash, remoteKey) {
+		return fmt.Errorf("counterparty's commitment signature is " +
+			"invalid")
+	}
+
+	candidate.LocalCommitment.CommitSig = commitSig.Serialize()
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDualFundBalances tests that the opener of a dual-funded channel pays the
+// commitment fee, and the lease fee if the channel is leased.
+func TestDualFundBalances(t *testing.T) {
+	t.Parallel()
+
+	const feeMSat = lnwire.MilliSatoshi(5_000_000)
+
+	// Without lease, the opener only pays the commitment fee.
+	ours, theirs := dualFundBalances(true, 100_000, 50_000, feeMSat, 0)
+	require.Equal(t, lnwire.MilliSatoshi(95_000_000), ours)
+	require.Equal(t, lnwire.MilliSatoshi(50_000_000), theirs)
+
+	ours, theirs = dualFundBalances(false, 50_000, 100_000, feeMSat, 0)
+	require.Equal(t, lnwire.MilliSatoshi(50_000_000), ours)
+	require.Equal(t, lnwire.MilliSatoshi(95_000_000), theirs)
+
+	// The opener of a lease pays the lease fee to the acceptor.
+	ours, theirs = dualFundBalances(true, 7_000, 100_000, feeMSat, 2_000)
+	require.Equal(t, lnwire.MilliSatoshi(0), ours)
+	require.Equal(t, lnwire.MilliSatoshi(102_000_000), theirs)
+
+	ours, theirs = dualFundBalances(false, 100_000, 7_000, feeMSat, 2_000)
+	require.Equal(t, lnwire.MilliSatoshi(102_000_000), ours)
+	require.Equal(t, lnwire.MilliSatoshi(0), theirs)
+}
+
+// TestVerifyFundingTxInputs tests that the witnesses of all inputs of a
+// dual-funded channel's funding transaction are verified.
+func TestVerifyFundingTxInputs(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pkScript, err := txscript.PayToAddrScript(
+		mustP2WPKHAddr(t, privKey.PubKey()),
+	)
+	require.NoError(t, err)
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	fundingTx := wire.NewMsgTx(2)
+	for i := byte(0); i < 2; i++ {
+		outPoint := wire.OutPoint{Hash: chainhash.Hash{i + 1}}
+		prevOuts.AddPrevOut(outPoint, &wire.TxOut{
+			Value:    100_000,
+			PkScript: pkScript,
+		})
+		fundingTx.AddTxIn(wire.NewTxIn(&outPoint, nil, nil))
+	}
+	fundingTx.AddTxOut(&wire.TxOut{Value: 190_000, PkScript: pkScript})
+
+	sigHashes := txscript.NewTxSigHashes(fundingTx, prevOuts)
+	for i := range fundingTx.TxIn {
+		witness, err := txscript.WitnessSignature(
+			fundingTx, sigHashes, i, 100_000, pkScript,
+			txscript.SigHashAll, privKey, true,
+		)
+		require.NoError(t, err)
+		fundingTx.TxIn[i].Witness = witness
+	}
+	require.NoError(t, VerifyFundingTxInputs(fundingTx, prevOuts))
+
+	// An invalid witness for any of the inputs must be rejected.
+	invalidTx := fundingTx.Copy()
+	invalidTx.TxIn[1].Witness = invalidTx.TxIn[0].Witness
+	require.Error(t, VerifyFundingTxInputs(invalidTx, prevOuts))
+
+	// So must an input spending an unknown output.
+	unknownTx := fundingTx.Copy()
+	unknownTx.TxIn[1].PreviousOutPoint.Index = 1
+	err = VerifyFundingTxInputs(unknownTx, prevOuts)
+	require.ErrorContains(t, err, "unknown previous output")
+}
+
+// mustP2WPKHAddr returns the P2WPKH address of the given public key.
+func mustP2WPKHAddr(t *testing.T,
+	pubKey *btcec.PublicKey) *btcutil.AddressWitnessPubKeyHash {
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(pubKey.SerializeCompressed()),
+		&chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+
+	return addr
+}
@@ -50,6 +50,16 @@ func ErrFunderBalanceDust(commitFee, funderBalance,
 	}
 }
 
+// ErrLeaseFeeUnpaid returns an error indicating that the contribution of the
+// opener of a leased channel doesn't cover the lease fee along with the fees
+// of the commitment transaction.
+func ErrLeaseFeeUnpaid(leaseFee btcutil.Amount) ReservationError {
+	return ReservationError{
+		fmt.Errorf("opener contribution doesn't cover lease fee "+
+			"of %v", leaseFee),
+	}
+}
+
 // ErrCsvDelayTooLarge returns an error indicating that the CSV delay was to
 // large to be accepted, along with the current max.
 func ErrCsvDelayTooLarge(remoteDelay, maxDelay uint16) ReservationError {
@@ -79,6 +79,11 @@ var (
 	// requirements of the mempool backend are not met.
 	ErrMempoolFee = errors.New("transaction rejected by the mempool " +
 		"because of low fees")
+
+	// ErrPackageRelayUnsupported is returned from SubmitPackage in case
+	// the chain backend doesn't accept packages of transactions.
+	ErrPackageRelayUnsupported = errors.New("package relay not " +
+		"supported by chain backend")
 )
 
 // ErrNoOutputs is returned if we try to create a transaction with no outputs
@@ -550,6 +555,13 @@ type WalletController interface {
 	// policies and returns an error if it cannot be accepted into the
 	// mempool.
 	CheckMempoolAcceptance(tx *wire.MsgTx) error
+
+	// SubmitPackage submits an unconfirmed parent transaction together
+	// with a child spending it to the mempool of the chain backend, so
+	// the child can pay for a parent that doesn't meet the mempool
+	// minimum fee on its own. If the chain backend doesn't support
+	// package relay, ErrPackageRelayUnsupported is returned.
+	SubmitPackage(parent, child *wire.MsgTx, label string) error
 }
 
 // BlockChainIO is a dedicated source which will be used to obtain queries
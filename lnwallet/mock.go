@@ -315,6 +315,13 @@ func (w *mockWalletController) CheckMempoolAcceptance(tx *wire.MsgTx) error {
 	return nil
 }
 
+// SubmitPackage submits a package of a parent and a child transaction.
+func (w *mockWalletController) SubmitPackage(parent, child *wire.MsgTx,
+	_ string) error {
+
+	return ErrPackageRelayUnsupported
+}
+
 // mockChainNotifier is a mock implementation of the ChainNotifier interface.
 type mockChainNotifier struct {
 	SpendChan chan *chainntnfs.SpendDetail
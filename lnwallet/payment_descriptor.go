@@ -191,6 +191,11 @@ type paymentDescriptor struct {
 	// NOTE: Populate only in fail payment descriptor entry types.
 	FailReason []byte
 
+	// FailAttrData stores the attribution data of the failure, if any.
+	//
+	// NOTE: Populate only in fail payment descriptor entry types.
+	FailAttrData []byte
+
 	// FailCode stores the code why a particular payment was canceled.
 	//
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
@@ -256,11 +261,15 @@ func (pd *paymentDescriptor) toLogUpdate() channeldb.LogUpdate {
 			PaymentPreimage: pd.RPreimage,
 		}
 	case Fail:
-		msg = &lnwire.UpdateFailHTLC{
+		failMsg := &lnwire.UpdateFailHTLC{
 			ChanID: pd.ChanID,
 			ID:     pd.ParentIndex,
 			Reason: pd.FailReason,
 		}
+		if len(pd.FailAttrData) > 0 {
+			failMsg.AttrData = lnwire.SomeAttrData(pd.FailAttrData)
+		}
+		msg = failMsg
 	case MalformedFail:
 		msg = &lnwire.UpdateFailMalformedHTLC{
 			ChanID:       pd.ChanID,
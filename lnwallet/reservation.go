@@ -56,6 +56,12 @@ const (
 	// CommitmentTypeSimpleTaproot type but layers on a special overlay
 	// protocol.
 	CommitmentTypeSimpleTaprootOverlay
+
+	// CommitmentTypeZeroFeeCommitments is a commitment type that builds
+	// upon CommitmentTypeAnchorsZeroFeeHtlcTx, but uses version 3
+	// commitment transactions that pay no fee and have a single keyless
+	// pay-to-anchor output instead of one anchor output per party.
+	CommitmentTypeZeroFeeCommitments
 )
 
 // HasStaticRemoteKey returns whether the commitment type supports remote
@@ -66,7 +72,8 @@ func (c CommitmentType) HasStaticRemoteKey() bool {
 		CommitmentTypeAnchorsZeroFeeHtlcTx,
 		CommitmentTypeScriptEnforcedLease,
 		CommitmentTypeSimpleTaproot,
-		CommitmentTypeSimpleTaprootOverlay:
+		CommitmentTypeSimpleTaprootOverlay,
+		CommitmentTypeZeroFeeCommitments:
 
 		return true
 
@@ -81,7 +88,8 @@ func (c CommitmentType) HasAnchors() bool {
 	case CommitmentTypeAnchorsZeroFeeHtlcTx,
 		CommitmentTypeScriptEnforcedLease,
 		CommitmentTypeSimpleTaproot,
-		CommitmentTypeSimpleTaprootOverlay:
+		CommitmentTypeSimpleTaprootOverlay,
+		CommitmentTypeZeroFeeCommitments:
 
 		return true
 
@@ -111,6 +119,8 @@ func (c CommitmentType) String() string {
 		return "simple-taproot"
 	case CommitmentTypeSimpleTaprootOverlay:
 		return "simple-taproot-overlay"
+	case CommitmentTypeZeroFeeCommitments:
+		return "zero-fee-commitments"
 	default:
 		return "invalid"
 	}
@@ -251,6 +261,14 @@ type ChannelReservation struct {
 	// commitment state.
 	pushMSat lnwire.MilliSatoshi
 
+	// dualFundOpener is set if this reservation is for a dual-funded
+	// channel, and denotes the party that opened it.
+	dualFundOpener fn.Option[lntypes.ChannelParty]
+
+	// leaseFee is the fee the opener of a dual-funded channel lease pays
+	// to the acceptor within the initial balances.
+	leaseFee btcutil.Amount
+
 	wallet     *LightningWallet
 	chanFunder chanfunding.Assembler
 
@@ -283,34 +301,84 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 	// Based on the channel type, we determine the initial commit weight
 	// and fee.
 	commitWeight := input.CommitWeight
-	if req.CommitType.IsTaproot() {
+	switch {
+	case req.CommitType.IsTaproot():
 		commitWeight = input.TaprootCommitWeight
-	} else if req.CommitType.HasAnchors() {
+
+	case req.CommitType == CommitmentTypeZeroFeeCommitments:
+		commitWeight = input.ZeroFeeCommitWeight
+
+	case req.CommitType.HasAnchors():
 		commitWeight = input.AnchorCommitWeight
 	}
 	commitFee := req.CommitFeePerKw.FeeForWeight(
 		lntypes.WeightUnit(commitWeight),
 	)
 
+	// Zero-fee commitments are fee bumped by a child spending their
+	// anchor output, so they must not pay a fee on their own.
+	if req.CommitType == CommitmentTypeZeroFeeCommitments &&
+		req.CommitFeePerKw != 0 {
+
+		return nil, fmt.Errorf("zero-fee commitments must not pay a "+
+			"fee, got fee rate %v", req.CommitFeePerKw)
+	}
+
 	localFundingMSat := lnwire.NewMSatFromSatoshis(localFundingAmt)
 	// TODO(halseth): make method take remote funding amount directly
 	// instead of inferring it from capacity and local amt.
 	capacityMSat := lnwire.NewMSatFromSatoshis(capacity)
 
 	// The total fee paid by the initiator will be the commitment fee in
-	// addition to the two anchor outputs.
+	// addition to the two anchor outputs, unless the single anchor output
+	// of zero-fee commitments is used.
 	feeMSat := lnwire.NewMSatFromSatoshis(commitFee)
-	if req.CommitType.HasAnchors() {
+	if req.CommitType.HasAnchors() &&
+		req.CommitType != CommitmentTypeZeroFeeCommitments {
+
 		feeMSat += 2 * lnwire.NewMSatFromSatoshis(AnchorSize)
 	}
 
 	// Used to cut down on verbosity.
 	defaultDust := DustLimitUnknownWitness()
 
+	switch {
+	// If this is a dual-funded channel, then each party starts out with
+	// the amount it contributed, while the opener pays the commitment fee.
+	// The amount the remote party contributes may not be known yet, in
+	// which case the balances are updated once it is.
+	case req.DualFundOpener.IsSome():
+		initiator = req.DualFundOpener.UnwrapOr(lntypes.Local).IsLocal()
+		ourBalance, theirBalance = dualFundBalances(
+			initiator, localFundingAmt, capacity-localFundingAmt,
+			feeMSat, req.LeaseFee,
+		)
+
+		openerBalance := theirBalance
+		if initiator {
+			openerBalance = ourBalance
+		}
+
+		// The opener of a lease must be able to pay the lease fee on
+		// top of the fees of the commitment transaction.
+		if int64(openerBalance) < 0 && req.LeaseFee != 0 {
+			return nil, ErrLeaseFeeUnpaid(req.LeaseFee)
+		}
+
+		// If the opener doesn't have enough funds to actually pay the
+		// fees, then we'll bail out early.
+		if int64(openerBalance) < 0 {
+			return nil, ErrFunderBalanceDust(
+				int64(commitFee),
+				int64(openerBalance.ToSatoshis()),
+				int64(2*defaultDust),
+			)
+		}
+
 	// If we're the responder to a single-funder reservation, then we have
 	// no initial balance in the channel unless the remote party is pushing
 	// some funds to us within the first commitment state.
-	if localFundingAmt == 0 {
+	case localFundingAmt == 0:
 		ourBalance = req.PushMSat
 		theirBalance = capacityMSat - feeMSat - req.PushMSat
 		initiator = false
@@ -323,7 +391,8 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 				int64(2*defaultDust),
 			)
 		}
-	} else {
+
+	default:
 		// TODO(roasbeef): need to rework fee structure in general and
 		// also when we "unlock" dual funder within the daemon
 
@@ -383,8 +452,13 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 
 	// If either of the balances are zero at this point, or we have a
 	// non-zero push amt (there's no pushing for dual funder), then this is
-	// a single-funder channel.
-	if ourBalance == 0 || theirBalance == 0 || req.PushMSat != 0 {
+	// a single-funder channel. Channels opened through the dual-funded
+	// channel establishment protocol still have an opener that pays the
+	// commitment fee, so they use the single-funder type as well.
+	dualFund := req.DualFundOpener.IsSome()
+	if ourBalance == 0 || theirBalance == 0 || req.PushMSat != 0 ||
+		dualFund {
+
 		// Both the tweakless type and the anchor type is tweakless,
 		// hence set the bit.
 		if req.CommitType.HasStaticRemoteKey() {
@@ -435,6 +509,11 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 				"for script enforced lease commitment type")
 		}
 		chanType |= channeldb.LeaseExpirationBit
+
+		// Leases of dual-funded channels are sold by the acceptor.
+		if req.DualFundOpener.IsSome() {
+			chanType |= channeldb.AcceptorLeaseBit
+		}
 	} else if thawHeight > 0 {
 		chanType |= channeldb.FrozenBit
 	}
@@ -443,6 +522,10 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 		chanType |= channeldb.SimpleTaprootFeatureBit
 	}
 
+	if req.CommitType == CommitmentTypeZeroFeeCommitments {
+		chanType |= channeldb.ZeroFeeCommitmentsBit
+	}
+
 	if req.ZeroConf {
 		chanType |= channeldb.ZeroConfBit
 	}
@@ -502,12 +585,14 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 			Memo:                 req.Memo,
 			TapscriptRoot:        req.TapscriptRoot,
 		},
-		pushMSat:      req.PushMSat,
-		pendingChanID: req.PendingChanID,
-		reservationID: id,
-		wallet:        wallet,
-		chanFunder:    req.ChanFunder,
-		state:         WaitingToSend,
+		pushMSat:       req.PushMSat,
+		dualFundOpener: req.DualFundOpener,
+		leaseFee:       req.LeaseFee,
+		pendingChanID:  req.PendingChanID,
+		reservationID:  id,
+		wallet:         wallet,
+		chanFunder:     req.ChanFunder,
+		state:          WaitingToSend,
 	}, nil
 }
 
@@ -588,6 +673,17 @@ func (r *ChannelReservation) CommitConstraints(
 		return err
 	}
 
+	// Zero-fee commitments must stay within the size limit of TRUC
+	// transactions, which leaves room for fewer HTLCs.
+	maxZeroFeeHtlcs := uint16(input.MaxZeroFeeCommitHTLCNumber / 2)
+	if r.partialState.ChanType.HasZeroFeeCommitments() &&
+		bounds.MaxAcceptedHtlcs > maxZeroFeeHtlcs {
+
+		return ErrMaxHtlcNumTooLarge(
+			bounds.MaxAcceptedHtlcs, maxZeroFeeHtlcs,
+		)
+	}
+
 	// Our dust limit should always be less than or equal to our proposed
 	// channel reserve.
 	if responder && r.ourContribution.DustLimit > bounds.ChanReserve {
@@ -950,6 +1046,14 @@ func VerifyConstraints(bounds *channeldb.ChannelStateBounds,
 		return ErrInvalidDustLimit(commitParams.DustLimit)
 	}
 
+	return verifyStateBounds(bounds, channelCapacity)
+}
+
+// verifyStateBounds checks that the channel state bounds are sane for a channel
+// of the given capacity, independently of the commitment parameters.
+func verifyStateBounds(bounds *channeldb.ChannelStateBounds,
+	channelCapacity btcutil.Amount) error {
+
 	// Fail if we consider the channel reserve to be too large.  We
 	// currently fail if it is greater than 20% of the channel capacity.
 	maxChanReserve := channelCapacity / 5
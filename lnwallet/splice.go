@@ -0,0 +1,1263 @@
+package lnwallet
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrSpliceUnsupported is returned when a splice is attempted for a
+	// channel type that doesn't support splicing yet.
+	ErrSpliceUnsupported = errors.New("splicing is not supported for " +
+		"this channel type")
+
+	// ErrInvalidSpliceTx is returned when a splice transaction doesn't
+	// spend the current funding output or doesn't create a new one.
+	ErrInvalidSpliceTx = errors.New("invalid splice transaction")
+)
+
+// SpliceContribution describes the inputs and outputs a party contributes to
+// a splice transaction.
+type SpliceContribution struct {
+	// Tx holds our inputs and outputs of the splice transaction, along
+	// with the input spending the current funding output and the new
+	// funding output, which only accounts for our own contribution. If
+	// the remote party initiates the splice instead, these two are left
+	// to it.
+	Tx *wire.MsgTx
+
+	// PrevTxs are the transactions that created the outputs spent by the
+	// inputs of Tx, keyed by the outpoint of the input. The shared input
+	// spending the current funding output doesn't have an entry.
+	PrevTxs map[wire.OutPoint]*wire.MsgTx
+
+	// LocalDelta is the amount we add to (or remove from, if negative)
+	// our balance in the channel.
+	LocalDelta btcutil.Amount
+
+	// FeeRate is the fee rate of the splice transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// SignInputs signs our inputs of the final splice transaction, which
+	// may include inputs and outputs of the remote party as well. The
+	// passed fetcher returns the previous outputs of all inputs of the
+	// transaction. If nil, the inputs of Tx are already signed, which
+	// means that the remote party can't contribute to the splice.
+	SignInputs func(tx *wire.MsgTx,
+		prevOuts txscript.PrevOutputFetcher) error
+}
+
+// hasPendingUpdates returns true if any update in either log hasn't been
+// locked in on both commitments yet.
+func (lc *LightningChannel) hasPendingUpdates() bool {
+	parties := []lntypes.ChannelParty{lntypes.Local, lntypes.Remote}
+	for _, whoseUpdates := range parties {
+		updateIndex := lc.updateLogs.GetForParty(whoseUpdates).logIndex
+
+		for _, whoseCommit := range parties {
+			tip := lc.commitChains.GetForParty(whoseCommit).tip()
+			committed := tip.messageIndices.GetForParty(whoseUpdates)
+
+			if updateIndex != committed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// pendingSplice is a splice of the channel that has been signed, but not
+// locked by both parties yet.
+type pendingSplice struct {
+	// txid is the txid of the splice transaction.
+	txid chainhash.Hash
+
+	// fundingTxIn is the input spending the new funding output.
+	fundingTxIn wire.TxIn
+
+	// capacity is the value of the new funding output.
+	capacity btcutil.Amount
+
+	// balanceDeltas are the amounts the balances of both parties change
+	// by with the splice.
+	balanceDeltas lntypes.Dual[btcutil.Amount]
+}
+
+// newPendingSplice creates the pending splice for the given splice candidate.
+func newPendingSplice(candidate *channeldb.SpliceCandidate) *pendingSplice {
+	return &pendingSplice{
+		txid:        candidate.Txid(),
+		fundingTxIn: *wire.NewTxIn(&candidate.FundingOutpoint, nil, nil),
+		capacity:    candidate.Capacity,
+		balanceDeltas: lntypes.Dual[btcutil.Amount]{
+			Local:  candidate.LocalDelta,
+			Remote: candidate.RemoteDelta,
+		},
+	}
+}
+
+// funding returns the new funding output created by the splice.
+func (s *pendingSplice) funding() commitFunding {
+	return commitFunding{
+		txIn:     s.fundingTxIn,
+		capacity: s.capacity,
+	}
+}
+
+// applySpliceDelta returns the balance after changing it by the given splice
+// delta, or an error if the balance would become negative.
+func applySpliceDelta(balance lnwire.MilliSatoshi,
+	delta btcutil.Amount) (lnwire.MilliSatoshi, error) {
+
+	if delta >= 0 {
+		return balance + lnwire.NewMSatFromSatoshis(delta), nil
+	}
+
+	deltaMSat := lnwire.NewMSatFromSatoshis(-delta)
+	if deltaMSat > balance {
+		return 0, fmt.Errorf("%w: splice out of %v exceeds balance %v",
+			ErrBelowChanReserve, -delta, balance)
+	}
+
+	return balance - deltaMSat, nil
+}
+
+// spliceCommitment creates the commitment that spends the funding output of
+// the given splice at the same height and with the same HTLCs as the passed
+// commitment. Only the balances of both parties differ by the amounts they
+// contributed to the splice.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) spliceCommitment(base *commitment,
+	splice *pendingSplice, keyRing *CommitmentKeyRing) (*commitment,
+	error) {
+
+	// The balances of the base commitment already had the commitment fee
+	// deducted, so we'll add it back to the balance of the initiator
+	// before applying the splice.
+	ourBalance, theirBalance := base.ourBalance, base.theirBalance
+	fee := lnwire.NewMSatFromSatoshis(base.fee)
+	if lc.channelState.IsInitiator {
+		ourBalance += fee
+	} else {
+		theirBalance += fee
+	}
+
+	ourBalance, err := applySpliceDelta(
+		ourBalance, splice.balanceDeltas.Local,
+	)
+	if err != nil {
+		return nil, err
+	}
+	theirBalance, err = applySpliceDelta(
+		theirBalance, splice.balanceDeltas.Remote,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The HTLCs are copied, so that creating the commitment doesn't mutate
+	// the ones of the base commitment.
+	copyHtlcs := func(htlcs []paymentDescriptor) []*paymentDescriptor {
+		copied := make([]*paymentDescriptor, len(htlcs))
+		for i := range htlcs {
+			htlc := htlcs[i]
+			htlc.sig = nil
+			copied[i] = &htlc
+		}
+
+		return copied
+	}
+	view := &HtlcView{
+		NextHeight: base.height,
+		FeePerKw:   base.feePerKw,
+		Updates: lntypes.Dual[[]*paymentDescriptor]{
+			Local:  copyHtlcs(base.outgoingHTLCs),
+			Remote: copyHtlcs(base.incomingHTLCs),
+		},
+	}
+
+	commitTx, err := lc.commitBuilder.createUnsignedCommitmentTx(
+		ourBalance, theirBalance, base.whoseCommit, base.feePerKw,
+		base.height, view, view, keyRing, base, splice.funding(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &commitment{
+		height:         base.height,
+		whoseCommit:    base.whoseCommit,
+		messageIndices: base.messageIndices,
+		ourHtlcIndex:   base.ourHtlcIndex,
+		theirHtlcIndex: base.theirHtlcIndex,
+		txn:            commitTx.txn,
+		ourBalance:     commitTx.ourBalance,
+		theirBalance:   commitTx.theirBalance,
+		fee:            commitTx.fee,
+		feePerKw:       base.feePerKw,
+		dustLimit:      base.dustLimit,
+		customBlob:     base.customBlob,
+	}
+
+	c.outgoingHTLCs = make([]paymentDescriptor, len(view.Updates.Local))
+	for i, htlc := range view.Updates.Local {
+		c.outgoingHTLCs[i] = *htlc
+	}
+	c.incomingHTLCs = make([]paymentDescriptor, len(view.Updates.Remote))
+	for i, htlc := range view.Updates.Remote {
+		c.incomingHTLCs[i] = *htlc
+	}
+
+	err = c.populateHtlcIndexes(lc.channelState.ChanType, commitTx.cltvs)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// addSpliceCommitments creates the commitments spending the funding outputs
+// of all pending splices for the passed commitment.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) addSpliceCommitments(c *commitment,
+	keyRing *CommitmentKeyRing) error {
+
+	if len(lc.pendingSplices) == 0 {
+		return nil
+	}
+
+	c.spliceCommits = make(
+		map[chainhash.Hash]*commitment, len(lc.pendingSplices),
+	)
+	for _, splice := range lc.pendingSplices {
+		spliceCommit, err := lc.spliceCommitment(c, splice, keyRing)
+		if err != nil {
+			return fmt.Errorf("unable to create commitment for "+
+				"splice %v: %w", splice.txid, err)
+		}
+
+		c.spliceCommits[splice.txid] = spliceCommit
+	}
+
+	return nil
+}
+
+// spliceDiskCommits returns the splice commitments of the passed commitment
+// in their on-disk format.
+func spliceDiskCommits(c *commitment,
+	whoseCommit lntypes.ChannelParty) []channeldb.SpliceCommitment {
+
+	commits := make([]channeldb.SpliceCommitment, 0, len(c.spliceCommits))
+	for txid, spliceCommit := range c.spliceCommits {
+		commits = append(commits, channeldb.SpliceCommitment{
+			SpliceTxid: txid,
+			Commitment: *spliceCommit.toDiskCommit(whoseCommit),
+		})
+	}
+
+	return commits
+}
+
+// spliceBatchFundingTxid returns the txid of the current funding transaction
+// if commitments are signed in batches, which is the case while the channel
+// has pending splices.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) spliceBatchFundingTxid() fn.Option[chainhash.Hash] {
+	if len(lc.pendingSplices) == 0 {
+		return fn.None[chainhash.Hash]()
+	}
+
+	return fn.Some(lc.channelState.CurrentFundingOutpoint().Hash)
+}
+
+// setCommitSigBatch marks the passed commit_sig message as part of a batch of
+// the given size, signing the commitment spending the funding output of the
+// given transaction.
+func setCommitSigBatch(msg *lnwire.CommitSig, batchSize uint16,
+	fundingTxid chainhash.Hash) {
+
+	msg.BatchSize = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType0](batchSize),
+	)
+	msg.FundingTxid = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType1, [32]byte](fundingTxid),
+	)
+}
+
+// CommitSigBatch returns the commit_sig messages to send for the passed
+// signatures. If the channel has pending splices, these are a batch of
+// messages, starting with the one for the current funding output.
+func CommitSigBatch(chanID lnwire.ChannelID, sigs *CommitSigs,
+	customRecords lnwire.CustomRecords) []*lnwire.CommitSig {
+
+	commitSig := &lnwire.CommitSig{
+		ChanID:        chanID,
+		CommitSig:     sigs.CommitSig,
+		HtlcSigs:      sigs.HtlcSigs,
+		PartialSig:    sigs.PartialSig,
+		CustomRecords: customRecords,
+	}
+	if len(sigs.SpliceSigs) == 0 {
+		return []*lnwire.CommitSig{commitSig}
+	}
+
+	batchSize := uint16(1 + len(sigs.SpliceSigs))
+	sigs.FundingTxid.WhenSome(func(txid chainhash.Hash) {
+		setCommitSigBatch(commitSig, batchSize, txid)
+	})
+
+	batch := []*lnwire.CommitSig{commitSig}
+	for _, spliceSigs := range sigs.SpliceSigs {
+		spliceCommitSig := &lnwire.CommitSig{
+			ChanID:    chanID,
+			CommitSig: spliceSigs.CommitSig,
+			HtlcSigs:  spliceSigs.HtlcSigs,
+		}
+		spliceSigs.FundingTxid.WhenSome(func(txid chainhash.Hash) {
+			setCommitSigBatch(spliceCommitSig, batchSize, txid)
+		})
+
+		batch = append(batch, spliceCommitSig)
+	}
+
+	return batch
+}
+
+// CommitSigsFromBatch converts a batch of commit_sig messages received while
+// the channel has pending splices into the signatures for the next local
+// commitment. The message for the current funding output carries the
+// signatures of the commitment itself, all others are the signatures for the
+// commitments spending the funding outputs of the pending splices. Signatures
+// for funding outputs that are neither are ignored, as the remote party might
+// have signed them before learning that a splice was locked.
+func (lc *LightningChannel) CommitSigsFromBatch(
+	batch []*lnwire.CommitSig) (*CommitSigs, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	fundingTxid := lc.channelState.CurrentFundingOutpoint().Hash
+	isPending := func(txid chainhash.Hash) bool {
+		return fn.Any(func(s *pendingSplice) bool {
+			return s.txid == txid
+		}, lc.pendingSplices)
+	}
+
+	var (
+		commitSigs *CommitSigs
+		spliceSigs []*CommitSigs
+	)
+	for _, msg := range batch {
+		txid, err := msg.FundingTxid.ValOpt().UnwrapOrErr(
+			errors.New("commit_sig in batch without funding txid"),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if chainhash.Hash(txid) != fundingTxid {
+			if isPending(txid) {
+				spliceSigs = append(spliceSigs, &CommitSigs{
+					CommitSig: msg.CommitSig,
+					HtlcSigs:  msg.HtlcSigs,
+					FundingTxid: fn.Some(
+						chainhash.Hash(txid),
+					),
+				})
+			}
+
+			continue
+		}
+
+		if commitSigs != nil {
+			return nil, fmt.Errorf("duplicate commit_sig for "+
+				"funding tx %v", fundingTxid)
+		}
+
+		auxSigBlob, err := msg.CustomRecords.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		commitSigs = &CommitSigs{
+			CommitSig:   msg.CommitSig,
+			HtlcSigs:    msg.HtlcSigs,
+			PartialSig:  msg.PartialSig,
+			AuxSigBlob:  auxSigBlob,
+			FundingTxid: fn.Some(fundingTxid),
+		}
+	}
+
+	if commitSigs == nil {
+		return nil, fmt.Errorf("missing commit_sig for funding tx %v",
+			fundingTxid)
+	}
+	commitSigs.SpliceSigs = spliceSigs
+
+	return commitSigs, nil
+}
+
+// signSpliceCommit signs the passed remote commitment that spends the funding
+// output of the given splice, along with its HTLCs.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) signSpliceCommit(ctx context.Context,
+	splice *pendingSplice, remoteCommit *commitment,
+	keyRing *CommitmentKeyRing) (*CommitSigs, error) {
+
+	var leaseExpiry uint32
+	if lc.channelState.ChanType.HasLeaseExpiration() {
+		leaseExpiry = lc.channelState.ThawHeight
+	}
+	sigBatch, _, cancelChan, err := genRemoteHtlcSigJobs(
+		keyRing, lc.channelState, leaseExpiry, remoteCommit,
+		lc.leafStore,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(sigBatch, func(i, j SignJob) int {
+		return cmp.Compare(i.OutputIndex, j.OutputIndex)
+	})
+	lc.sigPool.SubmitSignBatch(sigBatch)
+
+	signDesc := lc.spliceSignDesc(remoteCommit.txn, 0, splice.capacity)
+	rawSig, err := lc.Signer.SignOutputRaw(remoteCommit.txn, signDesc)
+	if err != nil {
+		close(cancelChan)
+		return nil, err
+	}
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		close(cancelChan)
+		return nil, err
+	}
+
+	htlcSigs := make([]lnwire.Sig, 0, len(sigBatch))
+	for _, htlcSigJob := range sigBatch {
+		var jobResp SignJobResp
+		select {
+		case jobResp = <-htlcSigJob.Resp:
+		case <-ctx.Done():
+			return nil, errQuit
+		}
+
+		if jobResp.Err != nil {
+			close(cancelChan)
+			return nil, jobResp.Err
+		}
+
+		htlcSigs = append(htlcSigs, jobResp.Sig)
+	}
+
+	return &CommitSigs{
+		CommitSig:   sig,
+		HtlcSigs:    htlcSigs,
+		FundingTxid: fn.Some(splice.txid),
+	}, nil
+}
+
+// signSpliceCommitments signs the commitments of the passed remote commitment
+// that spend the funding outputs of the pending splices.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) signSpliceCommitments(ctx context.Context,
+	remoteCommit *commitment,
+	keyRing *CommitmentKeyRing) ([]*CommitSigs, error) {
+
+	spliceSigs := make([]*CommitSigs, 0, len(lc.pendingSplices))
+	for _, splice := range lc.pendingSplices {
+		spliceCommit, ok := remoteCommit.spliceCommits[splice.txid]
+		if !ok {
+			return nil, fmt.Errorf("missing commitment for "+
+				"splice %v", splice.txid)
+		}
+
+		sigs, err := lc.signSpliceCommit(
+			ctx, splice, spliceCommit, keyRing,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		spliceSigs = append(spliceSigs, sigs)
+	}
+
+	return spliceSigs, nil
+}
+
+// addSpliceCommitDiff adds the signed splice commitments of the passed remote
+// commitment to the commit diff, so they can be retransmitted along with it.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) addSpliceCommitDiff(diff *channeldb.CommitDiff,
+	remoteCommit *commitment, spliceSigs []*CommitSigs) {
+
+	if len(spliceSigs) == 0 {
+		return
+	}
+
+	batchSize := uint16(1 + len(spliceSigs))
+	setCommitSigBatch(
+		diff.CommitSig, batchSize,
+		lc.channelState.CurrentFundingOutpoint().Hash,
+	)
+
+	for _, sigs := range spliceSigs {
+		txid := sigs.FundingTxid.UnwrapOr(chainhash.Hash{})
+		spliceCommit := remoteCommit.spliceCommits[txid]
+
+		commitSig := &lnwire.CommitSig{
+			ChanID:    diff.CommitSig.ChanID,
+			CommitSig: sigs.CommitSig,
+			HtlcSigs:  sigs.HtlcSigs,
+		}
+		setCommitSigBatch(commitSig, batchSize, txid)
+
+		diff.SpliceCommitments = append(
+			diff.SpliceCommitments, channeldb.SpliceCommitment{
+				SpliceTxid: txid,
+				Commitment: *spliceCommit.toDiskCommit(
+					lntypes.Remote,
+				),
+				CommitSig: commitSig,
+			},
+		)
+	}
+}
+
+// pendingSpliceCommitSigs returns the commit_sig messages we sent for the
+// pending remote commitments spending the funding outputs of the pending
+// splices, which have to be retransmitted along with the commit diff.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) pendingSpliceCommitSigs() ([]*lnwire.CommitSig,
+	error) {
+
+	if len(lc.pendingSplices) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := lc.channelState.SpliceCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	commitSigs := make([]*lnwire.CommitSig, 0, len(lc.pendingSplices))
+	for _, splice := range lc.pendingSplices {
+		candidate, err := fn.Find(
+			func(c *channeldb.SpliceCandidate) bool {
+				return c.Txid() == splice.txid
+			}, candidates,
+		).UnwrapOrErr(channeldb.ErrSpliceCandidateNotFound)
+		if err != nil {
+			return nil, err
+		}
+
+		if candidate.RemotePendingCommitSig == nil {
+			return nil, fmt.Errorf("missing pending commit_sig "+
+				"for splice %v", splice.txid)
+		}
+
+		commitSigs = append(
+			commitSigs, candidate.RemotePendingCommitSig,
+		)
+	}
+
+	return commitSigs, nil
+}
+
+// verifySpliceCommit verifies the remote party's signatures for the passed
+// local commitment that spends the funding output of the given splice. On
+// success, the signatures are stored within the commitment.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) verifySpliceCommit(splice *pendingSplice,
+	localCommit *commitment, keyRing *CommitmentKeyRing,
+	sigs *CommitSigs) error {
+
+	var leaseExpiry uint32
+	if lc.channelState.ChanType.HasLeaseExpiration() {
+		leaseExpiry = lc.channelState.ThawHeight
+	}
+	verifyJobs, _, err := genHtlcSigValidationJobs(
+		lc.channelState, localCommit, keyRing, sigs.HtlcSigs,
+		leaseExpiry, lc.leafStore, fn.None[AuxSigner](),
+		fn.None[tlv.Blob](),
+	)
+	if err != nil {
+		return err
+	}
+
+	cancelChan := make(chan struct{})
+	verifyResps := lc.sigPool.SubmitVerifyBatch(verifyJobs, cancelChan)
+
+	sig, err := sigs.CommitSig.ToSignature()
+	if err != nil {
+		close(cancelChan)
+		return err
+	}
+	err = lc.verifySpliceSig(localCommit.txn, 0, splice.capacity, sig)
+	if err != nil {
+		close(cancelChan)
+		return fmt.Errorf("invalid commitment signature for splice "+
+			"%v: %w", splice.txid, err)
+	}
+
+	for range verifyJobs {
+		htlcErr := <-verifyResps
+		if htlcErr != nil {
+			close(cancelChan)
+			return fmt.Errorf("invalid htlc signature for splice "+
+				"%v: %w", splice.txid, htlcErr)
+		}
+	}
+
+	localCommit.sig = sigs.CommitSig.ToSignatureBytes()
+
+	return nil
+}
+
+// checkSpliceSigCount makes sure that the remote party signed a commitment for
+// each of the pending splices of the channel.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) checkSpliceSigCount(commitSigs *CommitSigs) error {
+	if len(commitSigs.SpliceSigs) != len(lc.pendingSplices) {
+		return fmt.Errorf("expected signatures for %d splice "+
+			"commitments, got %d", len(lc.pendingSplices),
+			len(commitSigs.SpliceSigs))
+	}
+
+	return nil
+}
+
+// verifySpliceCommitments verifies the remote party's signatures for the
+// commitments of the passed local commitment that spend the funding outputs of
+// the pending splices.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) verifySpliceCommitments(localCommit *commitment,
+	keyRing *CommitmentKeyRing, spliceSigs []*CommitSigs) error {
+
+	for _, splice := range lc.pendingSplices {
+		sigs, err := fn.Find(func(s *CommitSigs) bool {
+			return s.FundingTxid.UnwrapOr(chainhash.Hash{}) ==
+				splice.txid
+		}, spliceSigs).UnwrapOrErr(
+			fmt.Errorf("missing commitment signature for splice "+
+				"%v", splice.txid),
+		)
+		if err != nil {
+			return err
+		}
+
+		spliceCommit, ok := localCommit.spliceCommits[splice.txid]
+		if !ok {
+			return fmt.Errorf("missing commitment for splice %v",
+				splice.txid)
+		}
+
+		err = lc.verifySpliceCommit(splice, spliceCommit, keyRing, sigs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSpliceable makes sure that the channel can be spliced in its current
+// state.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) checkSpliceable() error {
+	chanState := lc.channelState
+	if chanState.ChanType.IsTaproot() || chanState.CustomBlob.IsSome() {
+		return ErrSpliceUnsupported
+	}
+
+	return nil
+}
+
+// CheckSpliceable returns an error if the channel can't be spliced in its
+// current state.
+func (lc *LightningChannel) CheckSpliceable() error {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.checkSpliceable()
+}
+
+// FundingPkScript returns the pkScript of the channel's funding output.
+func (lc *LightningChannel) FundingPkScript() []byte {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.fundingOutput.PkScript
+}
+
+// FindSpliceOutput returns the index of the output of the given splice
+// transaction that creates the new funding output of the channel. This also
+// makes sure that the transaction spends the current funding output.
+func (lc *LightningChannel) FindSpliceOutput(spliceTx *wire.MsgTx) (uint32,
+	error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.findSpliceOutput(spliceTx)
+}
+
+// findSpliceOutput is the lock-free version of FindSpliceOutput.
+func (lc *LightningChannel) findSpliceOutput(spliceTx *wire.MsgTx) (uint32,
+	error) {
+
+	fundingOutpoint := lc.channelState.CurrentFundingOutpoint()
+	spendsFunding := fn.Any(func(txIn *wire.TxIn) bool {
+		return txIn.PreviousOutPoint == fundingOutpoint
+	}, spliceTx.TxIn)
+	if !spendsFunding {
+		return 0, fmt.Errorf("%w: doesn't spend funding output %v",
+			ErrInvalidSpliceTx, fundingOutpoint)
+	}
+
+	var (
+		outputIndex uint32
+		found       bool
+	)
+	for i, txOut := range spliceTx.TxOut {
+		if !bytes.Equal(txOut.PkScript, lc.fundingOutput.PkScript) {
+			continue
+		}
+
+		if found {
+			return 0, fmt.Errorf("%w: multiple funding outputs",
+				ErrInvalidSpliceTx)
+		}
+		outputIndex = uint32(i)
+		found = true
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: no funding output",
+			ErrInvalidSpliceTx)
+	}
+
+	return outputIndex, nil
+}
+
+// spliceTailKeyRings returns the commitment key rings of the current local
+// and remote commitments.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) spliceTailKeyRings() (
+	lntypes.Dual[*CommitmentKeyRing], error) {
+
+	var keyRings lntypes.Dual[*CommitmentKeyRing]
+
+	chanState := lc.channelState
+	localSecret, err := chanState.RevocationProducer.AtIndex(
+		lc.currentHeight,
+	)
+	if err != nil {
+		return keyRings, err
+	}
+	localCommitPoint := input.ComputeCommitmentPoint(localSecret[:])
+
+	keyRings.Local = DeriveCommitmentKeys(
+		localCommitPoint, lntypes.Local, chanState.ChanType,
+		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+	)
+	keyRings.Remote = DeriveCommitmentKeys(
+		chanState.RemoteCurrentRevocation, lntypes.Remote,
+		chanState.ChanType, &chanState.LocalChanCfg,
+		&chanState.RemoteChanCfg,
+	)
+
+	return keyRings, nil
+}
+
+// spliceTailCommit creates the commitment of the given party that spends the
+// funding output of the splice, based on its current commitment.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) spliceTailCommit(splice *pendingSplice,
+	whoseCommit lntypes.ChannelParty) (*commitment, *CommitmentKeyRing,
+	error) {
+
+	keyRings, err := lc.spliceTailKeyRings()
+	if err != nil {
+		return nil, nil, err
+	}
+	keyRing := keyRings.GetForParty(whoseCommit)
+
+	tail := lc.commitChains.GetForParty(whoseCommit).tail()
+	spliceCommit, err := lc.spliceCommitment(tail, splice, keyRing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spliceCommit, keyRing, nil
+}
+
+// NewSpliceCandidate creates the commitments of both parties that spend the
+// new funding output created by the given splice transaction. The balances of
+// both parties change by the given amounts, which must add up to the
+// difference between the new and the current channel capacity. The returned
+// commitments aren't signed yet.
+//
+// NOTE: The channel must be quiescent, so that neither party has a commitment
+// in flight.
+func (lc *LightningChannel) NewSpliceCandidate(spliceTx *wire.MsgTx,
+	balanceDeltas lntypes.Dual[btcutil.Amount]) (*channeldb.SpliceCandidate,
+	error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if err := lc.checkSpliceable(); err != nil {
+		return nil, err
+	}
+
+	// The commitments spending the new funding output are created at the
+	// current height, so none of them may be pending.
+	for _, chain := range []*commitmentChain{
+		lc.commitChains.Local, lc.commitChains.Remote,
+	} {
+		if chain.tip() != chain.tail() {
+			return nil, ErrChanNotQuiescent
+		}
+	}
+
+	outputIndex, err := lc.findSpliceOutput(spliceTx)
+	if err != nil {
+		return nil, err
+	}
+
+	chanState := lc.channelState
+	capacity := btcutil.Amount(spliceTx.TxOut[outputIndex].Value)
+	capacityDelta := capacity - chanState.Capacity
+	if balanceDeltas.Local+balanceDeltas.Remote != capacityDelta {
+		return nil, fmt.Errorf("%w: capacity changes by %v, but "+
+			"contributions add up to %v", ErrInvalidSpliceTx,
+			capacityDelta, balanceDeltas.Local+balanceDeltas.Remote)
+	}
+
+	fundingOutpoint := wire.OutPoint{
+		Hash:  spliceTx.TxHash(),
+		Index: outputIndex,
+	}
+	splice := &pendingSplice{
+		txid:          fundingOutpoint.Hash,
+		fundingTxIn:   *wire.NewTxIn(&fundingOutpoint, nil, nil),
+		capacity:      capacity,
+		balanceDeltas: balanceDeltas,
+	}
+
+	localCommit, _, err := lc.spliceTailCommit(splice, lntypes.Local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSpliceTx, err)
+	}
+	remoteCommit, _, err := lc.spliceTailCommit(splice, lntypes.Remote)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSpliceTx, err)
+	}
+
+	// A splice out must not push the balance of a party below its
+	// channel reserve.
+	parties := []lntypes.ChannelParty{lntypes.Local, lntypes.Remote}
+	for _, party := range parties {
+		if balanceDeltas.GetForParty(party) >= 0 {
+			continue
+		}
+
+		reserve := chanState.LocalChanCfg.ChanReserve
+		balance := localCommit.ourBalance
+		if party.IsRemote() {
+			reserve = chanState.RemoteChanCfg.ChanReserve
+			balance = localCommit.theirBalance
+		}
+
+		if balance.ToSatoshis() < reserve {
+			return nil, fmt.Errorf("%w: splice out leaves %v "+
+				"balance %v below reserve %v",
+				ErrInvalidSpliceTx, party, balance, reserve)
+		}
+	}
+
+	return &channeldb.SpliceCandidate{
+		SpliceTx:         spliceTx,
+		FundingOutpoint:  fundingOutpoint,
+		Capacity:         capacity,
+		LocalDelta:       balanceDeltas.Local,
+		RemoteDelta:      balanceDeltas.Remote,
+		LocalCommitment:  *localCommit.toDiskCommit(lntypes.Local),
+		RemoteCommitment: *remoteCommit.toDiskCommit(lntypes.Remote),
+	}, nil
+}
+
+// spliceSignDesc returns a sign descriptor for spending a funding output of
+// the given value from the given transaction.
+func (lc *LightningChannel) spliceSignDesc(tx *wire.MsgTx, inputIndex int,
+	value btcutil.Amount) *input.SignDescriptor {
+
+	return &input.SignDescriptor{
+		KeyDesc:       lc.channelState.LocalChanCfg.MultiSigKey,
+		WitnessScript: lc.signDesc.WitnessScript,
+		Output: &wire.TxOut{
+			PkScript: lc.fundingOutput.PkScript,
+			Value:    int64(value),
+		},
+		HashType:   txscript.SigHashAll,
+		SigHashes:  input.NewTxSigHashesV0Only(tx),
+		InputIndex: inputIndex,
+	}
+}
+
+// verifySpliceSig verifies a signature of the remote party for the input at
+// the given index of the transaction, which spends a funding output of the
+// given value.
+func (lc *LightningChannel) verifySpliceSig(tx *wire.MsgTx, inputIndex int,
+	value btcutil.Amount, sig input.Signature) error {
+
+	witnessScript := lc.signDesc.WitnessScript
+	sigHash, err := txscript.CalcWitnessSigHash(
+		witnessScript, input.NewTxSigHashesV0Only(tx),
+		txscript.SigHashAll, tx, inputIndex, int64(value),
+	)
+	if err != nil {
+		return err
+	}
+
+	verifyKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey
+	if !sig.Verify(sigHash, verifyKey) {
+		return fmt.Errorf("invalid signature for input %d of %v",
+			inputIndex, tx.TxHash())
+	}
+
+	return nil
+}
+
+// SignSpliceCommitment signs the remote commitment of the given splice
+// candidate, along with its HTLCs.
+func (lc *LightningChannel) SignSpliceCommitment(ctx context.Context,
+	candidate *channeldb.SpliceCandidate) (*CommitSigs, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	splice := newPendingSplice(candidate)
+	remoteCommit, keyRing, err := lc.spliceTailCommit(
+		splice, lntypes.Remote,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return lc.signSpliceCommit(ctx, splice, remoteCommit, keyRing)
+}
+
+// ReceiveSpliceCommitment verifies the signatures of the remote party for our
+// commitment of the given splice candidate and stores them within the
+// candidate.
+func (lc *LightningChannel) ReceiveSpliceCommitment(
+	candidate *channeldb.SpliceCandidate, sigs *CommitSigs) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	splice := newPendingSplice(candidate)
+	localCommit, keyRing, err := lc.spliceTailCommit(splice, lntypes.Local)
+	if err != nil {
+		return err
+	}
+
+	err = lc.verifySpliceCommit(splice, localCommit, keyRing, sigs)
+	if err != nil {
+		return err
+	}
+
+	candidate.LocalCommitment = *localCommit.toDiskCommit(lntypes.Local)
+
+	return nil
+}
+
+// spliceInputIndex returns the index of the input of the splice transaction
+// spending the current funding output.
+func (lc *LightningChannel) spliceInputIndex(spliceTx *wire.MsgTx) (int,
+	error) {
+
+	fundingOutpoint := lc.channelState.CurrentFundingOutpoint()
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == fundingOutpoint {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: doesn't spend funding output %v",
+		ErrInvalidSpliceTx, fundingOutpoint)
+}
+
+// SignSpliceInput creates our signature for the input of the splice
+// transaction that spends the current funding output.
+func (lc *LightningChannel) SignSpliceInput(
+	spliceTx *wire.MsgTx) (input.Signature, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	inputIndex, err := lc.spliceInputIndex(spliceTx)
+	if err != nil {
+		return nil, err
+	}
+
+	signDesc := lc.spliceSignDesc(
+		spliceTx, inputIndex, lc.channelState.Capacity,
+	)
+
+	return lc.Signer.SignOutputRaw(spliceTx, signDesc)
+}
+
+// CompleteSpliceInput verifies the remote party's signature for the input of
+// the splice transaction that spends the current funding output and, if
+// valid, sets the witness of that input using both signatures.
+func (lc *LightningChannel) CompleteSpliceInput(spliceTx *wire.MsgTx,
+	localSig input.Signature, remoteSig lnwire.Sig) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	inputIndex, err := lc.spliceInputIndex(spliceTx)
+	if err != nil {
+		return err
+	}
+
+	theirSig, err := remoteSig.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	err = lc.verifySpliceSig(
+		spliceTx, inputIndex, lc.channelState.Capacity, theirSig,
+	)
+	if err != nil {
+		return err
+	}
+
+	chanState := lc.channelState
+	spliceTx.TxIn[inputIndex].Witness = input.SpendMultiSig(
+		lc.signDesc.WitnessScript,
+		chanState.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		localSig,
+		chanState.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		theirSig,
+	)
+
+	return nil
+}
+
+// attachSpliceCandidate registers the given splice candidate as a pending
+// splice of the channel. Its stored commitments are attached to the
+// commitments of the channel at the same height, so that all following
+// commitments are created for its funding output as well.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) attachSpliceCandidate(
+	candidate *channeldb.SpliceCandidate) error {
+
+	splice := newPendingSplice(candidate)
+
+	chanState := lc.channelState
+	localSecret, err := chanState.RevocationProducer.AtIndex(
+		lc.currentHeight,
+	)
+	if err != nil {
+		return err
+	}
+	localCommitPoint := input.ComputeCommitmentPoint(localSecret[:])
+	remoteCommitPoint := chanState.RemoteCurrentRevocation
+
+	attach := func(c *commitment, diskCommit *channeldb.ChannelCommitment,
+		localPoint, remotePoint *btcec.PublicKey) error {
+
+		if diskCommit.CommitHeight != c.height {
+			return fmt.Errorf("%v commitment of splice %v at "+
+				"height %d, expected %d", c.whoseCommit,
+				splice.txid, diskCommit.CommitHeight, c.height)
+		}
+
+		spliceCommit, err := lc.diskCommitToMemCommit(
+			c.whoseCommit, diskCommit, localPoint, remotePoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		if c.spliceCommits == nil {
+			c.spliceCommits = make(map[chainhash.Hash]*commitment)
+		}
+		c.spliceCommits[splice.txid] = spliceCommit
+
+		return nil
+	}
+
+	localChain := lc.commitChains.Local
+	if localChain.tip() != localChain.tail() {
+		return fmt.Errorf("unable to attach splice %v with pending "+
+			"local commitment", splice.txid)
+	}
+	err = attach(
+		localChain.tail(), &candidate.LocalCommitment,
+		localCommitPoint, remoteCommitPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	remoteChain := lc.commitChains.Remote
+	err = attach(
+		remoteChain.tail(), &candidate.RemoteCommitment,
+		localCommitPoint, remoteCommitPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	// If the remote party owes us a revocation, we'll also need the
+	// pending commitment we signed for it.
+	if remoteChain.tip() != remoteChain.tail() {
+		if candidate.RemotePendingCommitment == nil {
+			return fmt.Errorf("splice %v is missing pending remote "+
+				"commitment", splice.txid)
+		}
+
+		err := attach(
+			remoteChain.tip(), candidate.RemotePendingCommitment,
+			nil, chanState.RemoteNextRevocation,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	lc.pendingSplices = fn.Filter(func(s *pendingSplice) bool {
+		return s.txid != splice.txid
+	}, lc.pendingSplices)
+	lc.pendingSplices = append(lc.pendingSplices, splice)
+
+	return nil
+}
+
+// restorePendingSplices restores the pending splices of the channel from
+// disk.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) restorePendingSplices() error {
+	candidates, err := lc.channelState.SpliceCandidates()
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if err := lc.attachSpliceCandidate(candidate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddSpliceCandidate persists the given splice candidate, whose commitments
+// must have been signed by both parties, and adds it to the pending splices
+// of the channel. Until the splice is locked, all commitments are signed for
+// its funding output as well. The candidate may be added again once the
+// splice transaction has been fully signed.
+func (lc *LightningChannel) AddSpliceCandidate(
+	candidate *channeldb.SpliceCandidate) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if err := lc.channelState.AddSpliceCandidate(candidate); err != nil {
+		return err
+	}
+
+	return lc.attachSpliceCandidate(candidate)
+}
+
+// PendingSplices returns the txids of the splices of the channel that haven't
+// been locked yet.
+func (lc *LightningChannel) PendingSplices() []chainhash.Hash {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return fn.Map(func(s *pendingSplice) chainhash.Hash {
+		return s.txid
+	}, lc.pendingSplices)
+}
+
+// LockSplice switches the channel over to the funding output of the pending
+// splice with the given txid, once it has been locked by both parties. The
+// short channel ID describes where the splice transaction confirmed. The
+// commitments spending the new funding output replace the commitments of the
+// channel, and all other pending splices are discarded.
+func (lc *LightningChannel) LockSplice(txid chainhash.Hash,
+	scid lnwire.ShortChannelID) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	// Make sure that we have the commitments for the splice at every
+	// height before touching the channel state.
+	chains := []*commitmentChain{
+		lc.commitChains.Local, lc.commitChains.Remote,
+	}
+	for _, chain := range chains {
+		for e := chain.commitments.Front(); e != nil; e = e.Next() {
+			if _, ok := e.Value.spliceCommits[txid]; !ok {
+				return fmt.Errorf("%w: no %v commitment at "+
+					"height %d", channeldb.
+					ErrSpliceCandidateNotFound,
+					e.Value.whoseCommit, e.Value.height)
+			}
+		}
+	}
+
+	if _, err := lc.channelState.LockSplice(txid, scid); err != nil {
+		return err
+	}
+
+	for _, chain := range chains {
+		for e := chain.commitments.Front(); e != nil; e = e.Next() {
+			spliceCommit := e.Value.spliceCommits[txid]
+			spliceCommit.spliceCommits = nil
+			e.Value = spliceCommit
+		}
+	}
+
+	lc.pendingSplices = nil
+	lc.Capacity = lc.channelState.Capacity
+
+	return lc.createSignDesc()
+}
@@ -0,0 +1,276 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// assertSpendsOutput asserts that the input at the given index of the
+// transaction validly spends the given output.
+func assertSpendsOutput(t *testing.T, tx *wire.MsgTx, inputIndex int,
+	prevOut *wire.TxOut) {
+
+	t.Helper()
+
+	prevOutputFetcher := txscript.NewCannedPrevOutputFetcher(
+		prevOut.PkScript, prevOut.Value,
+	)
+	hashCache := txscript.NewTxSigHashes(tx, prevOutputFetcher)
+	vm, err := txscript.NewEngine(
+		prevOut.PkScript, tx, inputIndex, txscript.StandardVerifyFlags,
+		nil, hashCache, prevOut.Value, prevOutputFetcher,
+	)
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+}
+
+// addTestHTLC adds an HTLC offered by the sender to both channels.
+func addTestHTLC(t *testing.T, sender, receiver *LightningChannel, id int,
+	amt lnwire.MilliSatoshi) [32]byte {
+
+	t.Helper()
+
+	htlc, preimage := createHTLC(id, amt)
+	_, err := sender.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = receiver.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+
+	return preimage
+}
+
+// TestSplice tests that both parties derive the same commitments for a splice
+// transaction both of them contribute to, that the channel keeps working with
+// HTLCs signed for both funding outputs while the splice is pending, also
+// across a restart, and that the channel switches over to the new funding
+// output once the splice is locked.
+func TestSplice(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceState := aliceChannel.State()
+	oldCapacity := aliceState.Capacity
+	oldFundingOutput := aliceChannel.fundingOutput
+
+	// Alice splices in one BTC from a wallet input, while Bob adds half a
+	// BTC from one of his.
+	const (
+		aliceAmt = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		bobAmt   = aliceAmt / 2
+	)
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+	})
+	spliceTx.AddTxIn(wire.NewTxIn(&aliceState.FundingOutpoint, nil, nil))
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{2}},
+	})
+	spliceTx.AddTxOut(&wire.TxOut{
+		PkScript: aliceChannel.FundingPkScript(),
+		Value:    int64(oldCapacity + aliceAmt + bobAmt),
+	})
+	aliceDeltas := lntypes.Dual[btcutil.Amount]{
+		Local:  aliceAmt,
+		Remote: bobAmt,
+	}
+	bobDeltas := lntypes.Dual[btcutil.Amount]{
+		Local:  bobAmt,
+		Remote: aliceAmt,
+	}
+
+	// An HTLC that is locked in doesn't prevent the splice.
+	addTestHTLC(t, aliceChannel, bobChannel, 0, 10_000_000)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// The contributions must add up to the change in capacity.
+	_, err = aliceChannel.NewSpliceCandidate(
+		spliceTx, lntypes.Dual[btcutil.Amount]{Local: aliceAmt},
+	)
+	require.ErrorIs(t, err, ErrInvalidSpliceTx)
+
+	// A commitment in flight does prevent it, though.
+	addTestHTLC(t, aliceChannel, bobChannel, 1, 10_000_000)
+	aliceNewCommit, err := aliceChannel.SignNextCommitment(ctxb)
+	require.NoError(t, err)
+	_, err = aliceChannel.NewSpliceCandidate(spliceTx, aliceDeltas)
+	require.ErrorIs(t, err, ErrChanNotQuiescent)
+
+	require.NoError(t, bobChannel.ReceiveNewCommitment(
+		aliceNewCommit.CommitSigs,
+	))
+	bobRevocation, _, _, err := bobChannel.RevokeCurrentCommitment()
+	require.NoError(t, err)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	aliceCandidate, err := aliceChannel.NewSpliceCandidate(
+		spliceTx, aliceDeltas,
+	)
+	require.NoError(t, err)
+	bobCandidate, err := bobChannel.NewSpliceCandidate(spliceTx, bobDeltas)
+	require.NoError(t, err)
+
+	require.Equal(t, oldCapacity+aliceAmt+bobAmt, aliceCandidate.Capacity)
+	require.Equal(t, aliceCandidate.FundingOutpoint,
+		bobCandidate.FundingOutpoint)
+	require.Equal(
+		t, aliceState.LocalCommitment.RemoteBalance+
+			lnwire.NewMSatFromSatoshis(bobAmt),
+		aliceCandidate.LocalCommitment.RemoteBalance,
+	)
+	require.Len(t, aliceCandidate.LocalCommitment.Htlcs, 2)
+
+	// Both parties must arrive at the same commitment transactions.
+	require.Equal(
+		t, aliceCandidate.LocalCommitment.CommitTx.TxHash(),
+		bobCandidate.RemoteCommitment.CommitTx.TxHash(),
+	)
+	require.Equal(
+		t, aliceCandidate.RemoteCommitment.CommitTx.TxHash(),
+		bobCandidate.LocalCommitment.CommitTx.TxHash(),
+	)
+
+	// Exchange the signatures for the new commitments, which include the
+	// signatures for the HTLCs.
+	aliceCommitSigs, err := aliceChannel.SignSpliceCommitment(
+		ctxb, aliceCandidate,
+	)
+	require.NoError(t, err)
+	require.Len(t, aliceCommitSigs.HtlcSigs, 2)
+	bobCommitSigs, err := bobChannel.SignSpliceCommitment(
+		ctxb, bobCandidate,
+	)
+	require.NoError(t, err)
+
+	// Signatures for the wrong commitment must be rejected.
+	err = bobChannel.ReceiveSpliceCommitment(bobCandidate, bobCommitSigs)
+	require.Error(t, err)
+
+	err = bobChannel.ReceiveSpliceCommitment(bobCandidate, aliceCommitSigs)
+	require.NoError(t, err)
+	err = aliceChannel.ReceiveSpliceCommitment(
+		aliceCandidate, bobCommitSigs,
+	)
+	require.NoError(t, err)
+
+	// Now both parties sign the shared input and Alice assembles its
+	// witness, which must be valid.
+	aliceInputSig, err := aliceChannel.SignSpliceInput(spliceTx)
+	require.NoError(t, err)
+	bobInputSig, err := bobChannel.SignSpliceInput(spliceTx)
+	require.NoError(t, err)
+	bobInputWireSig, err := lnwire.NewSigFromSignature(bobInputSig)
+	require.NoError(t, err)
+
+	err = aliceChannel.CompleteSpliceInput(
+		spliceTx, aliceInputSig, bobInputWireSig,
+	)
+	require.NoError(t, err)
+	assertSpendsOutput(t, spliceTx, 1, &oldFundingOutput)
+
+	require.NoError(t, aliceChannel.AddSpliceCandidate(aliceCandidate))
+	require.NoError(t, bobChannel.AddSpliceCandidate(bobCandidate))
+	spliceTxid := spliceTx.TxHash()
+	require.Equal(
+		t, []chainhash.Hash{spliceTxid}, aliceChannel.PendingSplices(),
+	)
+
+	// While the splice is pending, new commitments are signed for both
+	// funding outputs.
+	preimage := addTestHTLC(t, aliceChannel, bobChannel, 2, 20_000_000)
+	aliceNewCommit, err = aliceChannel.SignNextCommitment(ctxb)
+	require.NoError(t, err)
+	require.Len(t, aliceNewCommit.SpliceSigs, 1)
+	require.Len(t, aliceNewCommit.SpliceSigs[0].HtlcSigs, 3)
+
+	// The signatures are sent as a batch of commit_sig messages, starting
+	// with the one for the current funding output.
+	chanID := lnwire.NewChanIDFromOutPoint(aliceState.FundingOutpoint)
+	batch := CommitSigBatch(chanID, aliceNewCommit.CommitSigs, nil)
+	require.Len(t, batch, 2)
+	for _, msg := range batch {
+		require.Equal(t, uint16(2), msg.BatchSize.ValOpt().UnwrapOr(0))
+	}
+	require.Equal(
+		t, [32]byte(spliceTxid),
+		batch[1].FundingTxid.ValOpt().UnwrapOr([32]byte{}),
+	)
+
+	// A batch missing the signature for the splice commitment must be
+	// rejected.
+	commitSigs, err := bobChannel.CommitSigsFromBatch(batch[:1])
+	require.NoError(t, err)
+	require.Error(t, bobChannel.ReceiveNewCommitment(commitSigs))
+
+	// Alice restarts while Bob still owes her a revocation, which must
+	// restore her pending commitment for the splice as well.
+	aliceChannel, err = restartChannel(aliceChannel)
+	require.NoError(t, err)
+	require.Equal(
+		t, []chainhash.Hash{spliceTxid}, aliceChannel.PendingSplices(),
+	)
+
+	commitSigs, err = bobChannel.CommitSigsFromBatch(batch)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.ReceiveNewCommitment(commitSigs))
+	bobRevocation, _, _, err = bobChannel.RevokeCurrentCommitment()
+	require.NoError(t, err)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	// The stored splice commitments must have followed the channel.
+	aliceCandidates, err := aliceChannel.State().SpliceCandidates()
+	require.NoError(t, err)
+	require.Len(t, aliceCandidates, 1)
+	require.Equal(
+		t, aliceChannel.State().LocalCommitment.CommitHeight,
+		aliceCandidates[0].LocalCommitment.CommitHeight,
+	)
+	require.Len(t, aliceCandidates[0].LocalCommitment.Htlcs, 3)
+	require.Nil(t, aliceCandidates[0].RemotePendingCommitment)
+
+	// Once locked, both channels switch over to the new funding output.
+	spliceScid := lnwire.NewShortChanIDFromInt(0xdeadbeef)
+	require.NoError(t, aliceChannel.LockSplice(spliceTxid, spliceScid))
+	require.NoError(t, bobChannel.LockSplice(spliceTxid, spliceScid))
+	require.Equal(t, aliceCandidate.Capacity, aliceChannel.Capacity)
+	require.Equal(t, aliceCandidate.Capacity, bobChannel.Capacity)
+	aliceState = aliceChannel.State()
+	require.Equal(t, fn.Some(spliceScid), aliceState.SpliceShortChanID())
+	require.Empty(t, aliceChannel.PendingSplices())
+
+	// Alice's signed commitment must spend the new funding output.
+	signedCommit, err := aliceChannel.getSignedCommitTx()
+	require.NoError(t, err)
+	require.Equal(
+		t, aliceCandidate.FundingOutpoint,
+		signedCommit.TxIn[0].PreviousOutPoint,
+	)
+	newFundingOutput := spliceTx.TxOut[aliceCandidate.FundingOutpoint.Index]
+	assertSpendsOutput(t, signedCommit, 0, newFundingOutput)
+
+	// Finally, the channel must keep working with the new capacity.
+	err = bobChannel.SettleHTLC(preimage, 2, nil, nil, nil)
+	require.NoError(t, err)
+	err = aliceChannel.ReceiveHTLCSettle(preimage, 2)
+	require.NoError(t, err)
+	addTestHTLC(t, aliceChannel, bobChannel, 3, 70_000_000_000)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+}
@@ -238,6 +238,7 @@ func CreateTestChannels(t *testing.T, chanType channeldb.ChannelType,
 	aliceCommitTx, bobCommitTx, err := CreateCommitmentTxns(
 		channelBal, channelBal, &aliceCfg, &bobCfg, aliceCommitPoint,
 		bobCommitPoint, *fundingTxIn, chanType, isAliceInitiator, 0,
+		WithCapacity(channelCapacity),
 	)
 	if err != nil {
 		return nil, nil, err
@@ -265,9 +266,12 @@ func CreateTestChannels(t *testing.T, chanType channeldb.ChannelType,
 		return nil, nil, err
 	}
 	commitFee := calcStaticFee(chanType, 0)
-	var anchorAmt btcutil.Amount
-	if chanType.HasAnchors() {
-		anchorAmt += 2 * AnchorSize
+	anchorAmt := AnchorOutputsValue(chanType)
+
+	// Zero-fee commitments don't pay any fee.
+	if chanType.HasZeroFeeCommitments() {
+		feePerKw = 0
+		commitFee = 0
 	}
 
 	aliceBalance := lnwire.NewMSatFromSatoshis(
@@ -243,6 +243,25 @@ type InitFundingReserveMsg struct {
 	// be used to create the combined key for musig2 based channels.
 	TapscriptRoot fn.Option[chainhash.Hash]
 
+	// DualFundOpener is set if the channel is opened using the dual-funded
+	// channel establishment protocol, and denotes the party that opened
+	// it. The opener pays the commitment fee, while both parties may
+	// contribute funds. The funding transaction of such a channel is
+	// constructed interactively, so it's completed through
+	// ProcessDualFundContribution and CompleteDualFundTx rather than the
+	// single funder methods of the reservation.
+	DualFundOpener fn.Option[lntypes.ChannelParty]
+
+	// LeaseExpiry is the absolute height a dual-funded channel lease
+	// expires at. If set, the channel uses a script enforced lease, which
+	// locks the funds of the acceptor until the lease expires.
+	LeaseExpiry uint32
+
+	// LeaseFee is the fee the opener of a dual-funded channel lease pays
+	// to the acceptor. It's deducted from the initial balance of the
+	// opener and added to the one of the acceptor.
+	LeaseFee btcutil.Amount
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -675,6 +694,12 @@ out:
 				l.handleSingleFunderSigs(msg)
 			case *addCounterPartySigsMsg:
 				l.handleFundingCounterPartySigs(msg)
+			case *addDualFundContributionMsg:
+				l.handleDualFundContribution(msg)
+			case *completeDualFundTxMsg:
+				l.handleCompleteDualFundTx(msg)
+			case *addDualFundCommitSigMsg:
+				l.handleDualFundCommitSig(msg)
 			}
 		case <-l.quit:
 			// TODO: do some clean up
@@ -771,6 +796,12 @@ func (l *LightningWallet) PsbtFundingVerify(pendingChanID [32]byte,
 		return fmt.Errorf("error verifying PSBT: %w", err)
 	}
 
+	// Splices are funded without a channel reservation, so there's nothing
+	// left to check.
+	if psbtIntent.SharedInput().IsSome() {
+		return nil
+	}
+
 	// Get the channel reservation for that corresponds to this pending
 	// channel ID.
 	l.limboMtx.Lock()
@@ -857,6 +888,131 @@ func (l *LightningWallet) CancelFundingIntent(pid [32]byte) error {
 	return nil
 }
 
+// ForgetFundingIntent removes a previously registered funding intent without
+// canceling it. This is used once the funding transaction of the intent has
+// been negotiated, so the inputs leased for it must not be released.
+func (l *LightningWallet) ForgetFundingIntent(pid [32]byte) {
+	l.intentMtx.Lock()
+	defer l.intentMtx.Unlock()
+
+	delete(l.fundingIntents, pid)
+}
+
+// SpliceRequest describes a splice in of an existing channel, where funds of
+// the wallet or of an external PSBT are added to the channel.
+type SpliceRequest struct {
+	// PendingID is the ID under which the funding intent of the splice is
+	// registered. This is used to drive the PSBT funding flow.
+	PendingID [32]byte
+
+	// Channel is the channel that is being spliced.
+	Channel *channeldb.OpenChannel
+
+	// LocalAmt is the amount that is added to the channel.
+	LocalAmt btcutil.Amount
+
+	// FeeRate is the fee rate of the splice transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// MinConfs is the minimum number of confirmations the wallet inputs
+	// used for the splice must have.
+	MinConfs int32
+
+	// ChanFunder is an optional assembler that provisions the inputs of
+	// the splice. If nil, the wallet funds the splice using coin
+	// selection.
+	ChanFunder chanfunding.Assembler
+}
+
+// ProvisionSplice provisions the inputs and outputs of a splice in, which
+// creates a new funding output for the channel spending its current funding
+// output together with the provisioned inputs. The returned intent is
+// registered under the pending ID of the request, so PSBT based splices can be
+// advanced using PsbtFundingVerify and PsbtFundingFinalize.
+func (l *LightningWallet) ProvisionSplice(
+	req *SpliceRequest) (chanfunding.Intent, error) {
+
+	channel := req.Channel
+	if channel.ChanType.IsTaproot() {
+		return nil, ErrSpliceUnsupported
+	}
+
+	localKey := channel.LocalChanCfg.MultiSigKey
+	remoteKey := channel.RemoteChanCfg.MultiSigKey.PubKey
+	_, fundingOutput, err := input.GenFundingPkScript(
+		localKey.PubKey.SerializeCompressed(),
+		remoteKey.SerializeCompressed(), int64(channel.Capacity),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	chanFunder := req.ChanFunder
+	if chanFunder == nil {
+		cfg := chanfunding.WalletConfig{
+			CoinSource:       NewCoinSource(l, nil),
+			CoinSelectLocker: l,
+			CoinLeaser:       l,
+			Signer:           l.Cfg.Signer,
+			DustLimit: DustLimitForSize(
+				input.P2WSHSize,
+			),
+			CoinSelectionStrategy: l.Cfg.CoinSelectionStrategy,
+		}
+		chanFunder = chanfunding.NewWalletAssembler(cfg)
+	}
+
+	numAnchorChans, err := l.CurrentNumAnchorChans()
+	if err != nil {
+		return nil, err
+	}
+
+	// The current funding output is accounted for as the remote amount,
+	// so the new funding output carries both the current capacity and the
+	// added funds.
+	fundingReq := &chanfunding.Request{
+		LocalAmt:      req.LocalAmt,
+		RemoteAmt:     channel.Capacity,
+		WalletReserve: l.RequiredReserve(uint32(numAnchorChans)),
+		MinConfs:      req.MinConfs,
+		FeeRate:       req.FeeRate,
+		ChangeAddr: func() (btcutil.Address, error) {
+			return l.NewAddress(
+				TaprootPubkey, true, DefaultAccountName,
+			)
+		},
+		SharedInput: fn.Some(chanfunding.SharedInput{
+			OutPoint:    channel.CurrentFundingOutpoint(),
+			TxOut:       *fundingOutput,
+			WitnessSize: input.MultiSigWitnessSize,
+		}),
+	}
+	intent, err := chanFunder.ProvisionChannel(fundingReq)
+	if err != nil {
+		return nil, err
+	}
+
+	switch intent := intent.(type) {
+	case *chanfunding.PsbtIntent:
+		intent.BindKeys(&localKey, remoteKey)
+
+	case *chanfunding.FullIntent:
+		intent.BindKeys(&localKey, remoteKey)
+
+	default:
+		intent.Cancel()
+		return nil, fmt.Errorf("unsupported splice funding intent %T",
+			intent)
+	}
+
+	if err := l.RegisterFundingIntent(req.PendingID, intent); err != nil {
+		intent.Cancel()
+		return nil, err
+	}
+
+	return intent, nil
+}
+
 // handleFundingReserveRequest processes a message intending to create, and
 // validate a funding reservation request.
 func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg) {
@@ -984,6 +1140,15 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 				)
 			},
 			Musig2: req.CommitType.IsTaproot(),
+
+			// The opener of a dual-funded channel pays for the
+			// funding output.
+			SkipFundingOutputFee: fn.MapOptionZ(
+				req.DualFundOpener,
+				func(opener lntypes.ChannelParty) bool {
+					return opener.IsRemote()
+				},
+			),
 		}
 		fundingIntent, err = req.ChanFunder.ProvisionChannel(
 			fundingReq,
@@ -1039,6 +1204,11 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 		thawHeight = shimIntent.ThawHeight()
 	}
 
+	// A dual-funded channel lease carries its expiry within the request.
+	if req.LeaseExpiry != 0 {
+		thawHeight = req.LeaseExpiry
+	}
+
 	// Now that we have a funding intent, we'll check whether funding a
 	// channel using it would violate our reserved value for anchor channel
 	// fee bumping.
@@ -1500,6 +1670,7 @@ func (l *LightningWallet) handleFundingCancelRequest(req *fundingReserveCancelMs
 type createCommitOpts struct {
 	localAuxLeaves  fn.Option[CommitAuxLeaves]
 	remoteAuxLeaves fn.Option[CommitAuxLeaves]
+	capacity        fn.Option[btcutil.Amount]
 }
 
 // defaultCommitOpts returns a new createCommitOpts with default values.
@@ -1518,6 +1689,16 @@ func WithAuxLeaves(localLeaves,
 	}
 }
 
+// WithCapacity is a functional option that can be used to set the capacity of
+// the channel a new commitment transaction is created for. This is required
+// for zero-fee commitments, as their anchor output receives the part of the
+// capacity that isn't paid to the parties.
+func WithCapacity(capacity btcutil.Amount) CreateCommitOpt {
+	return func(o *createCommitOpts) {
+		o.capacity = fn.Some(capacity)
+	}
+}
+
 // CreateCommitOpt is a functional option that can be used to modify the way a
 // new commitment transaction is created.
 type CreateCommitOpt func(*createCommitOpts)
@@ -1557,6 +1738,24 @@ func CreateCommitmentTxns(localBalance, remoteBalance btcutil.Amount,
 		return nil, nil, err
 	}
 
+	// The anchor output of zero-fee commitments can only be valued once
+	// the capacity of the channel is known.
+	var capacity btcutil.Amount
+	if chanType.HasZeroFeeCommitments() {
+		capacity, err = options.capacity.UnwrapOrErr(
+			errors.New("channel capacity required for zero-fee " +
+				"commitments"),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = SetPayToAnchorValue(ourCommitTx, capacity)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	otxn := btcutil.NewTx(ourCommitTx)
 	if err := blockchain.CheckTransactionSanity(otxn); err != nil {
 		return nil, nil, err
@@ -1571,6 +1770,13 @@ func CreateCommitmentTxns(localBalance, remoteBalance btcutil.Amount,
 		return nil, nil, err
 	}
 
+	if chanType.HasZeroFeeCommitments() {
+		err = SetPayToAnchorValue(theirCommitTx, capacity)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	ttxn := btcutil.NewTx(theirCommitTx)
 	if err := blockchain.CheckTransactionSanity(ttxn); err != nil {
 		return nil, nil, err
@@ -1957,6 +2163,7 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 		pendingReservation.partialState.ChanType,
 		pendingReservation.partialState.IsInitiator, leaseExpiry,
 		WithAuxLeaves(localAuxLeaves, remoteAuxLeaves),
+		WithCapacity(pendingReservation.partialState.Capacity),
 	)
 	if err != nil {
 		req.err <- err
@@ -1967,12 +2174,24 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	// obfuscator then use it to encode the current state number within
 	// both commitment transactions.
 	var stateObfuscator [StateHintSize]byte
-	if chanState.ChanType.IsSingleFunder() {
+	switch {
+	// The obfuscator of a single funder channel is derived from the
+	// payment basepoints of the initiator and the responder, in that
+	// order. We're usually the initiator when we get here, unless we're
+	// the responder of a dual-funded channel.
+	case chanState.ChanType.IsSingleFunder() && chanState.IsInitiator:
 		stateObfuscator = DeriveStateHintObfuscator(
 			ourContribution.PaymentBasePoint.PubKey,
 			theirContribution.PaymentBasePoint.PubKey,
 		)
-	} else {
+
+	case chanState.ChanType.IsSingleFunder():
+		stateObfuscator = DeriveStateHintObfuscator(
+			theirContribution.PaymentBasePoint.PubKey,
+			ourContribution.PaymentBasePoint.PubKey,
+		)
+
+	default:
 		ourSer := ourContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		theirSer := theirContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		switch bytes.Compare(ourSer, theirSer) {
@@ -2419,6 +2638,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 		*fundingTxIn, chanType,
 		pendingReservation.partialState.IsInitiator, leaseExpiry,
 		WithAuxLeaves(localAuxLeaves, remoteAuxLeaves),
+		WithCapacity(pendingReservation.partialState.Capacity),
 	)
 	if err != nil {
 		req.err <- err
@@ -0,0 +1,253 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// AcceptChannel2 is the message a node sends in response to OpenChannel2 if it
+// accepts the channel. It carries the amount the sender contributes to the
+// channel, along with its channel parameters and keys. Once sent, the opener
+// starts the interactive construction of the funding transaction.
+type AcceptChannel2 struct {
+	// PendingChannelID is the temporary channel ID of the OpenChannel2
+	// message this message responds to.
+	PendingChannelID [32]byte
+
+	// FundingAmount is the amount of satoshis that the sender contributes
+	// to the channel. It may be zero.
+	FundingAmount btcutil.Amount
+
+	// DustLimit is the specific dust limit the sender of this message
+	// would like enforced on their version of the commitment transaction.
+	DustLimit btcutil.Amount
+
+	// MaxValueInFlight represents the maximum amount of coins that can be
+	// pending within the channel at any given time.
+	MaxValueInFlight MilliSatoshi
+
+	// HtlcMinimum is the smallest HTLC that the sender of this message
+	// will accept.
+	HtlcMinimum MilliSatoshi
+
+	// MinAcceptDepth is the minimum depth that the sender of this message
+	// requires the funding transaction to reach before the channel is
+	// considered open.
+	MinAcceptDepth uint32
+
+	// CsvDelay is the number of blocks to use for the relative time lock
+	// in the pay-to-self output of the receiver's commitment transaction.
+	CsvDelay uint16
+
+	// MaxAcceptedHTLCs is the total number of incoming HTLC's that the
+	// sender of this channel will accept.
+	MaxAcceptedHTLCs uint16
+
+	// FundingKey is the key that should be used on behalf of the sender
+	// within the 2-of-2 multi-sig output that it contained within the
+	// funding transaction.
+	FundingKey *btcec.PublicKey
+
+	// RevocationPoint is the base revocation point for the sending party.
+	RevocationPoint *btcec.PublicKey
+
+	// PaymentPoint is the base payment point for the sending party.
+	PaymentPoint *btcec.PublicKey
+
+	// DelayedPaymentPoint is the delay point for the sending party.
+	DelayedPaymentPoint *btcec.PublicKey
+
+	// HtlcPoint is the base point used to derive the set of keys for this
+	// party that will be used within the HTLC public key scripts.
+	HtlcPoint *btcec.PublicKey
+
+	// FirstCommitmentPoint is the first commitment point for the sending
+	// party.
+	FirstCommitmentPoint *btcec.PublicKey
+
+	// SecondCommitmentPoint is the second commitment point for the sending
+	// party.
+	SecondCommitmentPoint *btcec.PublicKey
+
+	// UpfrontShutdownScript is the script to which the channel funds
+	// should be paid when mutually closing the channel. This field is
+	// optional and only encoded if set.
+	UpfrontShutdownScript DeliveryAddress
+
+	// ChannelType is the explicit channel type the opener proposed. If it
+	// is set in OpenChannel2, it must be echoed back.
+	ChannelType *ChannelType
+
+	// WillFund is set if the acceptor leases inbound liquidity to the
+	// opener, as requested by the RequestFunding record of OpenChannel2.
+	WillFund *WillFund
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure AcceptChannel2 implements the lnwire.Message
+// interface.
+var _ Message = (*AcceptChannel2)(nil)
+
+// Encode serializes the target AcceptChannel2 into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (a *AcceptChannel2) Encode(w *bytes.Buffer, _ uint32) error {
+	var recordProducers []tlv.RecordProducer
+	if len(a.UpfrontShutdownScript) > 0 {
+		recordProducers = append(
+			recordProducers, &a.UpfrontShutdownScript,
+		)
+	}
+	if a.ChannelType != nil {
+		recordProducers = append(recordProducers, a.ChannelType)
+	}
+	if a.WillFund != nil {
+		recordProducers = append(recordProducers, a.WillFund)
+	}
+	err := EncodeMessageExtraData(&a.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, a.PendingChannelID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, a.FundingAmount); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, a.DustLimit); err != nil {
+		return err
+	}
+
+	if err := WriteMilliSatoshi(w, a.MaxValueInFlight); err != nil {
+		return err
+	}
+
+	if err := WriteMilliSatoshi(w, a.HtlcMinimum); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, a.MinAcceptDepth); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, a.CsvDelay); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, a.MaxAcceptedHTLCs); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.FundingKey); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.RevocationPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.PaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.DelayedPaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.HtlcPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.FirstCommitmentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, a.SecondCommitmentPoint); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, a.ExtraData)
+}
+
+// Decode deserializes the serialized AcceptChannel2 stored in the passed
+// io.Reader into the target AcceptChannel2 using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (a *AcceptChannel2) Decode(r io.Reader, _ uint32) error {
+	err := ReadElements(r,
+		a.PendingChannelID[:],
+		&a.FundingAmount,
+		&a.DustLimit,
+		&a.MaxValueInFlight,
+		&a.HtlcMinimum,
+		&a.MinAcceptDepth,
+		&a.CsvDelay,
+		&a.MaxAcceptedHTLCs,
+		&a.FundingKey,
+		&a.RevocationPoint,
+		&a.PaymentPoint,
+		&a.DelayedPaymentPoint,
+		&a.HtlcPoint,
+		&a.FirstCommitmentPoint,
+		&a.SecondCommitmentPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var (
+		shutdownScript DeliveryAddress
+		chanType       ChannelType
+		willFund       WillFund
+	)
+	typeMap, err := tlvRecords.ExtractRecords(
+		&shutdownScript, &chanType, &willFund,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[DeliveryAddrType]; ok && val == nil {
+		a.UpfrontShutdownScript = shutdownScript
+	}
+	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
+		a.ChannelType = &chanType
+	}
+	if val, ok := typeMap[WillFundRecordType]; ok && val == nil {
+		a.WillFund = &willFund
+	}
+
+	if len(tlvRecords) != 0 {
+		a.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as an AcceptChannel2 on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (a *AcceptChannel2) MsgType() MessageType {
+	return MsgAcceptChannel2
+}
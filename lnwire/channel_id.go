@@ -1,10 +1,13 @@
 package lnwire
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"math"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 )
@@ -52,6 +55,43 @@ func NewChanIDFromOutPoint(op wire.OutPoint) ChannelID {
 	return cid
 }
 
+// NewTempChanIDFromRevocationBasepoint returns the temporary channel ID the
+// opener of a channel uses in the v2 channel establishment protocol. It is the
+// SHA256 of 33 zero bytes followed by the opener's revocation basepoint.
+func NewTempChanIDFromRevocationBasepoint(
+	revocationBasepoint *btcec.PublicKey) ChannelID {
+
+	var zeroBasepoint [btcec.PubKeyBytesLenCompressed]byte
+
+	h := sha256.New()
+	h.Write(zeroBasepoint[:])
+	h.Write(revocationBasepoint.SerializeCompressed())
+
+	var cid ChannelID
+	copy(cid[:], h.Sum(nil))
+
+	return cid
+}
+
+// NewChanIDFromRevocationBasepoints returns the channel ID of a channel opened
+// with the v2 channel establishment protocol. It is the SHA256 of the
+// revocation basepoints of both parties, ordered lexicographically.
+func NewChanIDFromRevocationBasepoints(a, b *btcec.PublicKey) ChannelID {
+	lesser, greater := a.SerializeCompressed(), b.SerializeCompressed()
+	if bytes.Compare(lesser, greater) > 0 {
+		lesser, greater = greater, lesser
+	}
+
+	h := sha256.New()
+	h.Write(lesser)
+	h.Write(greater)
+
+	var cid ChannelID
+	copy(cid[:], h.Sum(nil))
+
+	return cid
+}
+
 // xorTxid performs the transformation needed to transform an OutPoint into a
 // ChannelID. To do this, we expect the cid parameter to contain the txid
 // unaltered and the outputIndex to be the output index
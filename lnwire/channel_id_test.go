@@ -1,6 +1,10 @@
 package lnwire
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
 
 // TestChannelIDOutPointConversion ensures that the IsChanPoint always
 // recognizes its seed OutPoint for all possible values of an output index.
@@ -70,3 +74,24 @@ func TestGenPossibleOutPoints(t *testing.T) {
 		t.Fatalf("possible outpoints did not contain the root outpoint")
 	}
 }
+
+// TestChanIDFromRevocationBasepoints ensures that the v2 channel ID doesn't
+// depend on the order of the revocation basepoints, and differs from the
+// temporary channel IDs of both parties.
+func TestChanIDFromRevocationBasepoints(t *testing.T) {
+	t.Parallel()
+
+	keyA, err := randPubKey()
+	require.NoError(t, err)
+	keyB, err := randPubKey()
+	require.NoError(t, err)
+
+	cid := NewChanIDFromRevocationBasepoints(keyA, keyB)
+	require.Equal(t, cid, NewChanIDFromRevocationBasepoints(keyB, keyA))
+
+	tempA := NewTempChanIDFromRevocationBasepoint(keyA)
+	tempB := NewTempChanIDFromRevocationBasepoint(keyB)
+	require.NotEqual(t, tempA, tempB)
+	require.NotEqual(t, cid, tempA)
+	require.NotEqual(t, cid, tempB)
+}
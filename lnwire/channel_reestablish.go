@@ -89,6 +89,13 @@ type ChannelReestablish struct {
 	// a dynamic commitment negotiation
 	DynHeight fn.Option[DynHeight]
 
+	// NextFundingTxid is an optional field that stores the txid of an
+	// interactively constructed funding or splice transaction the sending
+	// party signed, but hasn't received the receiving party's
+	// tx_signatures for. The receiving party should retransmit them, or
+	// respond with tx_abort if it doesn't know the transaction.
+	NextFundingTxid tlv.OptionalRecordT[tlv.TlvType0, [32]byte]
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -136,7 +143,12 @@ func (a *ChannelReestablish) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
-	recordProducers := make([]tlv.RecordProducer, 0, 1)
+	recordProducers := make([]tlv.RecordProducer, 0, 3)
+	a.NextFundingTxid.WhenSome(
+		func(txid tlv.RecordT[tlv.TlvType0, [32]byte]) {
+			recordProducers = append(recordProducers, &txid)
+		},
+	)
 	a.LocalNonce.WhenSome(func(localNonce Musig2NonceTLV) {
 		recordProducers = append(recordProducers, &localNonce)
 	})
@@ -203,16 +215,20 @@ func (a *ChannelReestablish) Decode(r io.Reader, pver uint32) error {
 	}
 
 	var (
-		dynHeight  DynHeight
-		localNonce = a.LocalNonce.Zero()
+		dynHeight       DynHeight
+		localNonce      = a.LocalNonce.Zero()
+		nextFundingTxid = a.NextFundingTxid.Zero()
 	)
 	typeMap, err := tlvRecords.ExtractRecords(
-		&localNonce, &dynHeight,
+		&nextFundingTxid, &localNonce, &dynHeight,
 	)
 	if err != nil {
 		return err
 	}
 
+	if val, ok := typeMap[a.NextFundingTxid.TlvType()]; ok && val == nil {
+		a.NextFundingTxid = tlv.SomeRecordT(nextFundingTxid)
+	}
 	if val, ok := typeMap[a.LocalNonce.TlvType()]; ok && val == nil {
 		a.LocalNonce = tlv.SomeRecordT(localNonce)
 	}
@@ -45,6 +45,17 @@ type CommitSig struct {
 	// being signed for. In this case, the above Sig type MUST be blank.
 	PartialSig OptPartialSigWithNonceTLV
 
+	// BatchSize is the number of commit_sig messages sent together for
+	// the same commitment height. While a splice of the channel is
+	// pending, a commitment is signed for the current funding output as
+	// well as the funding output of each pending splice.
+	BatchSize tlv.OptionalRecordT[tlv.TlvType0, uint16]
+
+	// FundingTxid is the txid of the funding transaction whose output the
+	// signed commitment spends. It is only set when the commitment is
+	// signed as part of a batch.
+	FundingTxid tlv.OptionalRecordT[tlv.TlvType1, [32]byte]
+
 	// CustomRecords maps TLV types to byte slices, storing arbitrary data
 	// intended for inclusion in the ExtraData field.
 	CustomRecords CustomRecords
@@ -85,9 +96,11 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 
 	// Extract TLV records from the extra data field.
 	partialSig := c.PartialSig.Zero()
+	batchSize := c.BatchSize.Zero()
+	fundingTxid := c.FundingTxid.Zero()
 
 	customRecords, parsed, extraData, err := ParseAndExtractCustomRecords(
-		msgExtraData, &partialSig,
+		msgExtraData, &partialSig, &batchSize, &fundingTxid,
 	)
 	if err != nil {
 		return err
@@ -97,6 +110,12 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 	if _, ok := parsed[partialSig.TlvType()]; ok {
 		c.PartialSig = tlv.SomeRecordT(partialSig)
 	}
+	if _, ok := parsed[batchSize.TlvType()]; ok {
+		c.BatchSize = tlv.SomeRecordT(batchSize)
+	}
+	if _, ok := parsed[fundingTxid.TlvType()]; ok {
+		c.FundingTxid = tlv.SomeRecordT(fundingTxid)
+	}
 
 	c.CustomRecords = customRecords
 	c.ExtraData = extraData
@@ -109,10 +128,18 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *CommitSig) Encode(w *bytes.Buffer, pver uint32) error {
-	recordProducers := make([]tlv.RecordProducer, 0, 1)
+	recordProducers := make([]tlv.RecordProducer, 0, 3)
 	c.PartialSig.WhenSome(func(sig PartialSigWithNonceTLV) {
 		recordProducers = append(recordProducers, &sig)
 	})
+	c.BatchSize.WhenSome(func(size tlv.RecordT[tlv.TlvType0, uint16]) {
+		recordProducers = append(recordProducers, &size)
+	})
+	c.FundingTxid.WhenSome(
+		func(txid tlv.RecordT[tlv.TlvType1, [32]byte]) {
+			recordProducers = append(recordProducers, &txid)
+		},
+	)
 
 	extraData, err := MergeAndEncode(
 		recordProducers, c.ExtraData, c.CustomRecords,
@@ -116,6 +116,25 @@ func generateCommitSigTestCases(t *testing.T) []commitSigTestCase {
 				PartialSig: partialSig,
 			},
 		},
+		// Add a test case for a commitment that is signed as part of
+		// a batch.
+		{
+			Msg: CommitSig{
+				ChanID:    chanID,
+				CommitSig: sig,
+				BatchSize: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType0](
+						uint16(2),
+					),
+				),
+				FundingTxid: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType1](
+						[32]byte{1, 2, 3},
+					),
+				),
+				CustomRecords: customRecords,
+			},
+		},
 		// Add a case where the custom records are invalid.
 		{
 			Msg: CommitSig{
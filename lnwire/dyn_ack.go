@@ -136,3 +136,15 @@ func (da *DynAck) Decode(r io.Reader, _ uint32) error {
 func (da *DynAck) MsgType() MessageType {
 	return MsgDynAck
 }
+
+// A compile time check to ensure DynAck implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*DynAck)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (da *DynAck) TargetChanID() ChannelID {
+	return da.ChanID
+}
@@ -317,3 +317,15 @@ func (dp *DynPropose) Decode(r io.Reader, _ uint32) error {
 func (dp *DynPropose) MsgType() MessageType {
 	return MsgDynPropose
 }
+
+// A compile time check to ensure DynPropose implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*DynPropose)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (dp *DynPropose) TargetChanID() ChannelID {
+	return dp.ChanID
+}
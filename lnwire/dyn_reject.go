@@ -74,3 +74,15 @@ func (dr *DynReject) Decode(r io.Reader, _ uint32) error {
 func (dr *DynReject) MsgType() MessageType {
 	return MsgDynReject
 }
+
+// A compile time check to ensure DynReject implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*DynReject)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (dr *DynReject) TargetChanID() ChannelID {
+	return dr.ChanID
+}
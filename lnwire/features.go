@@ -161,6 +161,17 @@ const (
 	// addresses for cooperative closure addresses.
 	ShutdownAnySegwitOptional FeatureBit = 27
 
+	// DualFundRequired is a required feature bit that denotes that a
+	// connection established with this node must support the v2 channel
+	// establishment protocol, which allows both parties to contribute
+	// funds to a new channel.
+	DualFundRequired FeatureBit = 28
+
+	// DualFundOptional is an optional feature bit that denotes that a
+	// connection established with this node is permitted to open dual
+	// funded channels using the v2 channel establishment protocol.
+	DualFundOptional FeatureBit = 29
+
 	// AMPRequired is a required feature bit that signals that the receiver
 	// of a payment supports accepts spontaneous payments, i.e.
 	// sender-generated preimages according to BOLT XX.
@@ -171,6 +182,45 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// QuiescenceRequired is a required feature bit that denotes that a
+	// connection established with this node must support the quiescence
+	// protocol if it wants to have a channel relationship.
+	QuiescenceRequired FeatureBit = 34
+
+	// QuiescenceOptional is an optional feature bit that denotes that a
+	// connection established with this node is permitted to use the
+	// quiescence protocol.
+	QuiescenceOptional FeatureBit = 35
+
+	// OnionMessagesRequired is a required feature bit that denotes that a
+	// connection established with this node must support forwarding onion
+	// messages.
+	OnionMessagesRequired FeatureBit = 38
+
+	// OnionMessagesOptional is an optional feature bit that denotes that
+	// this node forwards onion messages.
+	OnionMessagesOptional FeatureBit = 39
+
+	// ZeroFeeCommitmentsRequired is a required feature bit that signals
+	// that the node requires channels having zero-fee v3 commitment
+	// transactions with a single pay-to-anchor output.
+	ZeroFeeCommitmentsRequired FeatureBit = 40
+
+	// ZeroFeeCommitmentsOptional is an optional feature bit that signals
+	// that the node supports channels having zero-fee v3 commitment
+	// transactions with a single pay-to-anchor output.
+	ZeroFeeCommitmentsOptional FeatureBit = 41
+
+	// ProvideStorageRequired is a required feature bit that denotes that a
+	// connection established with this node must support storing a small
+	// blob of data on behalf of its peers.
+	ProvideStorageRequired FeatureBit = 42
+
+	// ProvideStorageOptional is an optional feature bit that denotes that
+	// this node stores a small blob of data on behalf of its peers and
+	// returns it on reconnection.
+	ProvideStorageOptional FeatureBit = 43
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -223,6 +273,37 @@ const (
 	// able and willing to accept keysend payments.
 	KeysendOptional = 55
 
+	// TrampolineRoutingRequired is a required feature bit that denotes
+	// that the node is able to forward trampoline payments, finding a
+	// route to the next trampoline node or the final recipient on behalf
+	// of the sender.
+	TrampolineRoutingRequired FeatureBit = 56
+
+	// TrampolineRoutingOptional is an optional feature bit that denotes
+	// that the node is able to forward trampoline payments.
+	TrampolineRoutingOptional FeatureBit = 57
+
+	// SimpleCloseRequired is a required feature bit that denotes that a
+	// connection established with this node must support the simple
+	// cooperative close protocol, in which each party pays the fee of the
+	// closing transaction it proposes and can replace it later.
+	SimpleCloseRequired FeatureBit = 60
+
+	// SimpleCloseOptional is an optional feature bit that denotes that a
+	// connection established with this node is permitted to use the simple
+	// cooperative close protocol.
+	SimpleCloseOptional FeatureBit = 61
+
+	// SpliceRequired is a required feature bit that denotes that a
+	// connection established with this node must support splicing of
+	// channels if it wants to have a channel relationship.
+	SpliceRequired FeatureBit = 62
+
+	// SpliceOptional is an optional feature bit that denotes that a
+	// connection established with this node is permitted to splice
+	// channels.
+	SpliceOptional FeatureBit = 63
+
 	// ScriptEnforcedLeaseRequired is a required feature bit that signals
 	// that the node requires channels having zero-fee second-level HTLC
 	// transactions, which also imply anchor commitments, along with an
@@ -327,6 +408,18 @@ var Features = map[FeatureBit]string{
 	WumboChannelsOptional:                "wumbo-channels",
 	AMPRequired:                          "amp",
 	AMPOptional:                          "amp",
+	QuiescenceRequired:                   "quiescence",
+	QuiescenceOptional:                   "quiescence",
+	OnionMessagesRequired:                "onion-messages",
+	OnionMessagesOptional:                "onion-messages",
+	ZeroFeeCommitmentsRequired:           "zero-fee-commitments",
+	ZeroFeeCommitmentsOptional:           "zero-fee-commitments",
+	ProvideStorageRequired:               "provide-storage",
+	ProvideStorageOptional:               "provide-storage",
+	SimpleCloseRequired:                  "simple-close",
+	SimpleCloseOptional:                  "simple-close",
+	SpliceRequired:                       "splice",
+	SpliceOptional:                       "splice",
 	PaymentMetadataOptional:              "payment-metadata",
 	PaymentMetadataRequired:              "payment-metadata",
 	ExplicitChannelTypeOptional:          "explicit-commitment-type",
@@ -339,10 +432,14 @@ var Features = map[FeatureBit]string{
 	ScidAliasOptional:                    "scid-alias",
 	ZeroConfRequired:                     "zero-conf",
 	ZeroConfOptional:                     "zero-conf",
+	TrampolineRoutingRequired:            "trampoline-routing",
+	TrampolineRoutingOptional:            "trampoline-routing",
 	RouteBlindingRequired:                "route-blinding",
 	RouteBlindingOptional:                "route-blinding",
 	ShutdownAnySegwitRequired:            "shutdown-any-segwit",
 	ShutdownAnySegwitOptional:            "shutdown-any-segwit",
+	DualFundRequired:                     "dual-fund",
+	DualFundOptional:                     "dual-fund",
 	SimpleTaprootChannelsRequiredFinal:   "simple-taproot-chans",
 	SimpleTaprootChannelsOptionalFinal:   "simple-taproot-chans",
 	SimpleTaprootChannelsRequiredStaging: "simple-taproot-chans-x",
@@ -659,6 +659,160 @@ func FuzzDynAck(f *testing.F) {
 	})
 }
 
+func FuzzSpliceInit(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with SpliceInit.
+		data = prefixWithMsgType(data, MsgSpliceInit)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzSpliceAck(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with SpliceAck.
+		data = prefixWithMsgType(data, MsgSpliceAck)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzSpliceLocked(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with SpliceLocked.
+		data = prefixWithMsgType(data, MsgSpliceLocked)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxAddInput(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxAddInput.
+		data = prefixWithMsgType(data, MsgTxAddInput)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxAddOutput(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxAddOutput.
+		data = prefixWithMsgType(data, MsgTxAddOutput)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxRemoveInput(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxRemoveInput.
+		data = prefixWithMsgType(data, MsgTxRemoveInput)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxRemoveOutput(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxRemoveOutput.
+		data = prefixWithMsgType(data, MsgTxRemoveOutput)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxComplete(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxComplete.
+		data = prefixWithMsgType(data, MsgTxComplete)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxSignatures(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxSignatures.
+		data = prefixWithMsgType(data, MsgTxSignatures)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzOpenChannel2(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with OpenChannel2.
+		data = prefixWithMsgType(data, MsgOpenChannel2)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzAcceptChannel2(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with AcceptChannel2.
+		data = prefixWithMsgType(data, MsgAcceptChannel2)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxInitRbf(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxInitRbf.
+		data = prefixWithMsgType(data, MsgTxInitRbf)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxAckRbf(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxAckRbf.
+		data = prefixWithMsgType(data, MsgTxAckRbf)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzTxAbort(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with TxAbort.
+		data = prefixWithMsgType(data, MsgTxAbort)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
 func FuzzKickoffSig(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with KickoffSig
@@ -670,6 +824,39 @@ func FuzzKickoffSig(f *testing.F) {
 	})
 }
 
+func FuzzPeerStorage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with PeerStorage.
+		data = prefixWithMsgType(data, MsgPeerStorage)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzYourPeerStorage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with YourPeerStorage.
+		data = prefixWithMsgType(data, MsgYourPeerStorage)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
+func FuzzOnionMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with OnionMessage.
+		data = prefixWithMsgType(data, MsgOnionMessage)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages.
+		harness(t, data)
+	})
+}
+
 func FuzzCustomMessage(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data []byte, customMessageType uint16) {
 		if customMessageType < uint16(CustomTypeStart) {
@@ -925,6 +925,353 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(da)
 		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{
+				FundingContribution: btcutil.Amount(r.Int63() -
+					r.Int63()),
+				FundingFeerate: chainfee.SatPerKWeight(
+					r.Uint32(),
+				),
+				Locktime: r.Uint32(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{
+				FundingContribution: btcutil.Amount(r.Int63() -
+					r.Int63()),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddInput{
+				SerialID:  r.Uint64(),
+				PrevTxOut: r.Uint32(),
+				Sequence:  r.Uint32(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// The shared input of a splice is identified by its
+			// txid instead of the full previous transaction.
+			if r.Intn(2) == 0 {
+				txid := req.SharedInputTxid.Zero()
+				if _, err := r.Read(txid.Val[:]); err != nil {
+					t.Fatalf("unable to generate txid: %v",
+						err)
+				}
+				req.SharedInputTxid = tlv.SomeRecordT(txid)
+			} else {
+				req.PrevTx = make([]byte, r.Intn(1000)+1)
+				if _, err := r.Read(req.PrevTx); err != nil {
+					t.Fatalf("unable to generate tx: %v",
+						err)
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddOutput{
+				SerialID: r.Uint64(),
+				Amount:   btcutil.Amount(r.Int63()),
+				PkScript: make([]byte, r.Intn(100)+1),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+			if _, err := r.Read(req.PkScript); err != nil {
+				t.Fatalf("unable to generate script: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceLocked: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceLocked{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+			if _, err := r.Read(req.SpliceTxid[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxRemoveInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxRemoveInput{
+				SerialID: r.Uint64(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxRemoveOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxRemoveOutput{
+				SerialID: r.Uint64(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxComplete: func(v []reflect.Value, r *rand.Rand) {
+			req := TxComplete{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxSignatures: func(v []reflect.Value, r *rand.Rand) {
+			req := TxSignatures{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+			if _, err := r.Read(req.Txid[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+			}
+
+			numWitnesses := r.Intn(5)
+			for i := 0; i < numWitnesses; i++ {
+				witness := make(wire.TxWitness, r.Intn(4))
+				for j := range witness {
+					witness[j] = make([]byte, r.Intn(80))
+					_, err := r.Read(witness[j])
+					if err != nil {
+						t.Fatalf("unable to generate "+
+							"witness: %v", err)
+					}
+				}
+				req.Witnesses = append(req.Witnesses, witness)
+			}
+
+			if r.Intn(2) == 0 {
+				sig := req.SharedInputSig.Zero()
+				_, err := r.Read(sig.Val.bytes[:])
+				if err != nil {
+					t.Fatalf("unable to generate sig: %v",
+						err)
+				}
+				req.SharedInputSig = tlv.SomeRecordT(sig)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgOpenChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := OpenChannel2{
+				FundingFeePerKWeight: chainfee.SatPerKWeight(
+					r.Uint32(),
+				),
+				CommitFeePerKWeight: chainfee.SatPerKWeight(
+					r.Uint32(),
+				),
+				FundingAmount:    btcutil.Amount(r.Int63()),
+				DustLimit:        btcutil.Amount(r.Int63()),
+				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				CsvDelay:         uint16(r.Int31()),
+				MaxAcceptedHTLCs: uint16(r.Int31()),
+				LockTime:         r.Uint32(),
+				ChannelFlags:     FundingFlag(uint8(r.Int31())),
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+			}
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v",
+					err)
+			}
+
+			keys := []**btcec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+				&req.SecondCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+				}
+			}
+
+			// 1/2 chance empty TLV records.
+			if r.Intn(2) == 0 {
+				var err error
+				req.UpfrontShutdownScript, err = randDeliveryAddress(
+					r,
+				)
+				if err != nil {
+					t.Fatalf("unable to generate delivery "+
+						"address: %v", err)
+				}
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+
+				req.RequestFunding = &RequestFunding{
+					RequestedAmount: btcutil.Amount(
+						r.Int63(),
+					),
+					BlockHeight: r.Uint32(),
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgAcceptChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := AcceptChannel2{
+				FundingAmount:    btcutil.Amount(r.Int63()),
+				DustLimit:        btcutil.Amount(r.Int63()),
+				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				MinAcceptDepth:   r.Uint32(),
+				CsvDelay:         uint16(r.Int31()),
+				MaxAcceptedHTLCs: uint16(r.Int31()),
+			}
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v",
+					err)
+			}
+
+			keys := []**btcec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+				&req.SecondCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+				}
+			}
+
+			// 1/2 chance empty TLV records.
+			if r.Intn(2) == 0 {
+				var err error
+				req.UpfrontShutdownScript, err = randDeliveryAddress(
+					r,
+				)
+				if err != nil {
+					t.Fatalf("unable to generate delivery "+
+						"address: %v", err)
+				}
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+
+				sig, err := NewSigFromSignature(testSig)
+				if err != nil {
+					t.Fatalf("unable to parse sig: %v", err)
+				}
+				req.WillFund = &WillFund{
+					Sig: sig,
+					Rates: LeaseRates{
+						FundingWeight: uint16(
+							r.Int31(),
+						),
+						LeaseFeeBasis: uint16(
+							r.Int31(),
+						),
+						ChannelFeeMaxProportional: uint16(
+							r.Int31(),
+						),
+						LeaseFeeBase: btcutil.Amount(
+							r.Uint32(),
+						),
+						ChannelFeeMaxBase: MilliSatoshi(
+							r.Uint32(),
+						),
+					},
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxInitRbf: func(v []reflect.Value, r *rand.Rand) {
+			req := TxInitRbf{
+				LockTime: r.Uint32(),
+				FeeRate:  chainfee.SatPerKWeight(r.Uint32()),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			if r.Intn(2) == 0 {
+				req.FundingOutputContribution = tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType0](
+						r.Uint64(),
+					),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAckRbf: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAckRbf{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			if r.Intn(2) == 0 {
+				req.FundingOutputContribution = tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType0](
+						r.Uint64(),
+					),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAbort: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAbort{
+				Data: make([]byte, r.Intn(100)),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+			if _, err := r.Read(req.Data); err != nil {
+				t.Fatalf("unable to generate data: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgKickoffSig: func(v []reflect.Value, r *rand.Rand) {
 			ks := KickoffSig{
 				ExtraData: make([]byte, 0),
@@ -935,6 +1282,42 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(ks)
 		},
+		MsgPeerStorage: func(v []reflect.Value, r *rand.Rand) {
+			req := PeerStorage{
+				Blob: make([]byte, r.Intn(2000)),
+			}
+			if _, err := r.Read(req.Blob); err != nil {
+				t.Fatalf("unable to generate blob: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgYourPeerStorage: func(v []reflect.Value, r *rand.Rand) {
+			req := YourPeerStorage{
+				Blob: make([]byte, r.Intn(2000)),
+			}
+			if _, err := r.Read(req.Blob); err != nil {
+				t.Fatalf("unable to generate blob: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			pathKey, err := randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+			}
+
+			req := OnionMessage{
+				PathKey:   pathKey,
+				OnionBlob: make([]byte, r.Intn(2000)),
+			}
+			if _, err := r.Read(req.OnionBlob); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgCommitSig: func(v []reflect.Value, r *rand.Rand) {
 			req := NewCommitSig()
 			if _, err := r.Read(req.ChanID[:]); err != nil {
@@ -973,6 +1356,23 @@ func TestLightningWireProtocol(t *testing.T) {
 				req.PartialSig = somePartialSigWithNonce(t, r)
 			}
 
+			// 50/50 chance to sign the commitment as part of a
+			// batch.
+			if r.Int31()%2 == 0 {
+				var txid [32]byte
+				_, err := r.Read(txid[:])
+				require.NoError(t, err)
+
+				req.BatchSize = tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType0](
+						uint16(r.Int31n(10) + 1),
+					),
+				)
+				req.FundingTxid = tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType1](txid),
+				)
+			}
+
 			v[0] = reflect.ValueOf(*req)
 		},
 		MsgRevokeAndAck: func(v []reflect.Value, r *rand.Rand) {
@@ -1204,6 +1604,17 @@ func TestLightningWireProtocol(t *testing.T) {
 
 				//nolint:lll
 				req.LocalNonce = someLocalNonce[NonceRecordTypeT](r)
+
+				if r.Int()%2 == 0 {
+					var txid [32]byte
+					_, err := r.Read(txid[:])
+					require.NoError(t, err)
+
+					//nolint:lll
+					req.NextFundingTxid = tlv.SomeRecordT(
+						tlv.NewPrimitiveRecord[tlv.TlvType0](txid),
+					)
+				}
 			}
 
 			v[0] = reflect.ValueOf(req)
@@ -1502,6 +1913,32 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(*req)
 		},
+		MsgUpdateFailHTLC: func(v []reflect.Value, r *rand.Rand) {
+			req := &UpdateFailHTLC{
+				ID: r.Uint64(),
+			}
+
+			_, err := r.Read(req.ChanID[:])
+			require.NoError(t, err)
+
+			req.Reason = make([]byte, r.Intn(300)+1)
+			_, err = r.Read(req.Reason)
+			require.NoError(t, err)
+
+			req.CustomRecords = randCustomRecords(t, r)
+
+			// Generate attribution data 50% of the time, since not
+			// all nodes attribute their failures.
+			if r.Int31()%2 == 0 {
+				attrData := make([]byte, 920)
+				_, err = r.Read(attrData)
+				require.NoError(t, err)
+
+				req.AttrData = SomeAttrData(attrData)
+			}
+
+			v[0] = reflect.ValueOf(*req)
+		},
 		MsgAnnounceSignatures2: func(v []reflect.Value,
 			r *rand.Rand) {
 
@@ -1779,12 +2216,114 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceLocked,
+			scenario: func(m SpliceLocked) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddInput,
+			scenario: func(m TxAddInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddOutput,
+			scenario: func(m TxAddOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveInput,
+			scenario: func(m TxRemoveInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveOutput,
+			scenario: func(m TxRemoveOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxComplete,
+			scenario: func(m TxComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxSignatures,
+			scenario: func(m TxSignatures) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgOpenChannel2,
+			scenario: func(m OpenChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgAcceptChannel2,
+			scenario: func(m AcceptChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxInitRbf,
+			scenario: func(m TxInitRbf) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAckRbf,
+			scenario: func(m TxAckRbf) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAbort,
+			scenario: func(m TxAbort) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgKickoffSig,
 			scenario: func(m KickoffSig) bool {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgPeerStorage,
+			scenario: func(m PeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgYourPeerStorage,
+			scenario: func(m YourPeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgUpdateAddHTLC,
 			scenario: func(m UpdateAddHTLC) bool {
@@ -24,6 +24,8 @@ type MessageType uint16
 const (
 	MsgWarning                 MessageType = 1
 	MsgStfu                                = 2
+	MsgPeerStorage                         = 7
+	MsgYourPeerStorage                     = 9
 	MsgInit                                = 16
 	MsgError                               = 17
 	MsgPing                                = 18
@@ -37,6 +39,20 @@ const (
 	MsgClosingSigned                       = 39
 	MsgClosingComplete                     = 40
 	MsgClosingSig                          = 41
+	MsgOpenChannel2                        = 64
+	MsgAcceptChannel2                      = 65
+	MsgTxAddInput                          = 66
+	MsgTxAddOutput                         = 67
+	MsgTxRemoveInput                       = 68
+	MsgTxRemoveOutput                      = 69
+	MsgTxComplete                          = 70
+	MsgTxSignatures                        = 71
+	MsgTxInitRbf                           = 72
+	MsgTxAckRbf                            = 73
+	MsgTxAbort                             = 74
+	MsgSpliceLocked                        = 77
+	MsgSpliceInit                          = 80
+	MsgSpliceAck                           = 81
 	MsgDynPropose                          = 111
 	MsgDynAck                              = 113
 	MsgDynReject                           = 115
@@ -60,6 +76,7 @@ const (
 	MsgGossipTimestampRange                = 265
 	MsgChannelAnnouncement2                = 267
 	MsgChannelUpdate2                      = 271
+	MsgOnionMessage                        = 513
 	MsgKickoffSig                          = 777
 )
 
@@ -106,6 +123,34 @@ func (t MessageType) String() string {
 		return "Shutdown"
 	case MsgClosingSigned:
 		return "ClosingSigned"
+	case MsgOpenChannel2:
+		return "OpenChannel2"
+	case MsgAcceptChannel2:
+		return "AcceptChannel2"
+	case MsgTxAddInput:
+		return "TxAddInput"
+	case MsgTxAddOutput:
+		return "TxAddOutput"
+	case MsgTxRemoveInput:
+		return "TxRemoveInput"
+	case MsgTxRemoveOutput:
+		return "TxRemoveOutput"
+	case MsgTxComplete:
+		return "TxComplete"
+	case MsgTxSignatures:
+		return "TxSignatures"
+	case MsgTxInitRbf:
+		return "TxInitRbf"
+	case MsgTxAckRbf:
+		return "TxAckRbf"
+	case MsgTxAbort:
+		return "TxAbort"
+	case MsgSpliceLocked:
+		return "SpliceLocked"
+	case MsgSpliceInit:
+		return "SpliceInit"
+	case MsgSpliceAck:
+		return "SpliceAck"
 	case MsgDynPropose:
 		return "DynPropose"
 	case MsgDynAck:
@@ -114,6 +159,12 @@ func (t MessageType) String() string {
 		return "DynReject"
 	case MsgKickoffSig:
 		return "KickoffSig"
+	case MsgPeerStorage:
+		return "PeerStorage"
+	case MsgYourPeerStorage:
+		return "YourPeerStorage"
+	case MsgOnionMessage:
+		return "OnionMessage"
 	case MsgUpdateAddHTLC:
 		return "UpdateAddHTLC"
 	case MsgUpdateFailHTLC:
@@ -241,6 +292,34 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &Shutdown{}
 	case MsgClosingSigned:
 		msg = &ClosingSigned{}
+	case MsgOpenChannel2:
+		msg = &OpenChannel2{}
+	case MsgAcceptChannel2:
+		msg = &AcceptChannel2{}
+	case MsgTxAddInput:
+		msg = &TxAddInput{}
+	case MsgTxAddOutput:
+		msg = &TxAddOutput{}
+	case MsgTxRemoveInput:
+		msg = &TxRemoveInput{}
+	case MsgTxRemoveOutput:
+		msg = &TxRemoveOutput{}
+	case MsgTxComplete:
+		msg = &TxComplete{}
+	case MsgTxSignatures:
+		msg = &TxSignatures{}
+	case MsgTxInitRbf:
+		msg = &TxInitRbf{}
+	case MsgTxAckRbf:
+		msg = &TxAckRbf{}
+	case MsgTxAbort:
+		msg = &TxAbort{}
+	case MsgSpliceLocked:
+		msg = &SpliceLocked{}
+	case MsgSpliceInit:
+		msg = &SpliceInit{}
+	case MsgSpliceAck:
+		msg = &SpliceAck{}
 	case MsgDynPropose:
 		msg = &DynPropose{}
 	case MsgDynAck:
@@ -249,6 +328,12 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &DynReject{}
 	case MsgKickoffSig:
 		msg = &KickoffSig{}
+	case MsgPeerStorage:
+		msg = &PeerStorage{}
+	case MsgYourPeerStorage:
+		msg = &YourPeerStorage{}
+	case MsgOnionMessage:
+		msg = &OnionMessage{}
 	case MsgUpdateAddHTLC:
 		msg = &UpdateAddHTLC{}
 	case MsgUpdateFailHTLC:
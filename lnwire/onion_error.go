@@ -82,6 +82,8 @@ const (
 	CodeInvalidOnionPayload                       = FlagPerm | 22
 	CodeMPPTimeout                       FailCode = 23
 	CodeInvalidBlinding                           = FlagBadOnion | FlagPerm | 24 //nolint:lll
+	CodeTrampolineFeeInsufficient                 = FlagNode | 51
+	CodeTrampolineExpiryTooSoon                   = FlagNode | 52
 )
 
 // String returns the string representation of the failure code.
@@ -162,6 +164,12 @@ func (c FailCode) String() string {
 	case CodeInvalidBlinding:
 		return "InvalidBlinding"
 
+	case CodeTrampolineFeeInsufficient:
+		return "TrampolineFeeInsufficient"
+
+	case CodeTrampolineExpiryTooSoon:
+		return "TrampolineExpiryTooSoon"
+
 	default:
 		return "<unknown>"
 	}
@@ -1291,6 +1299,48 @@ func NewInvalidBlinding(
 	return &FailInvalidBlinding{OnionSHA256: shaSum}
 }
 
+// FailTrampolineFeeInsufficient is returned by a trampoline node if the fee
+// left to it by the sender is not enough to reach the next trampoline node or
+// the final recipient.
+//
+// NOTE: May only be returned by trampoline nodes.
+type FailTrampolineFeeInsufficient struct{}
+
+// Code returns the failure unique code.
+//
+// NOTE: Part of the FailureMessage interface.
+func (f *FailTrampolineFeeInsufficient) Code() FailCode {
+	return CodeTrampolineFeeInsufficient
+}
+
+// Returns a human readable string describing the target FailureMessage.
+//
+// NOTE: Implements the error interface.
+func (f *FailTrampolineFeeInsufficient) Error() string {
+	return f.Code().String()
+}
+
+// FailTrampolineExpiryTooSoon is returned by a trampoline node if the CLTV
+// delta left to it by the sender is not enough to reach the next trampoline
+// node or the final recipient.
+//
+// NOTE: May only be returned by trampoline nodes.
+type FailTrampolineExpiryTooSoon struct{}
+
+// Code returns the failure unique code.
+//
+// NOTE: Part of the FailureMessage interface.
+func (f *FailTrampolineExpiryTooSoon) Code() FailCode {
+	return CodeTrampolineExpiryTooSoon
+}
+
+// Returns a human readable string describing the target FailureMessage.
+//
+// NOTE: Implements the error interface.
+func (f *FailTrampolineExpiryTooSoon) Error() string {
+	return f.Code().String()
+}
+
 // DecodeFailure decodes, validates, and parses the lnwire onion failure, for
 // the provided protocol version.
 func DecodeFailure(r io.Reader, pver uint32) (FailureMessage, error) {
@@ -1512,6 +1562,12 @@ func makeEmptyOnionError(code FailCode) (FailureMessage, error) {
 	case CodeInvalidBlinding:
 		return &FailInvalidBlinding{}, nil
 
+	case CodeTrampolineFeeInsufficient:
+		return &FailTrampolineFeeInsufficient{}, nil
+
+	case CodeTrampolineExpiryTooSoon:
+		return &FailTrampolineExpiryTooSoon{}, nil
+
 	default:
 		return nil, errors.Errorf("unknown error code: %v", code)
 	}
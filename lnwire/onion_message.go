@@ -0,0 +1,105 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// OnionMessage is a message that carries an onion routed message between
+// nodes. Unlike HTLCs, onion messages don't require any channel or payment,
+// and are relayed along blinded paths. The PathKey is used by the receiving
+// node to decrypt its layer of the onion, along with the data the creator of
+// the blinded path encrypted for it.
+type OnionMessage struct {
+	// PathKey is the current ephemeral key of the blinded path the message
+	// travels along.
+	PathKey *btcec.PublicKey
+
+	// OnionBlob is the serialized onion packet, which is 1366 bytes for
+	// onions with the regular routing info size, but may be larger.
+	OnionBlob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewOnionMessage creates a new OnionMessage with the given path key and
+// onion.
+func NewOnionMessage(pathKey *btcec.PublicKey,
+	onion []byte) *OnionMessage {
+
+	return &OnionMessage{
+		PathKey:   pathKey,
+		OnionBlob: onion,
+	}
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Encode serializes the target OnionMessage into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WritePublicKey(w, o.PathKey); err != nil {
+		return err
+	}
+
+	if len(o.OnionBlob) > int(^uint16(0)) {
+		return fmt.Errorf("onion blob too large: %d bytes",
+			len(o.OnionBlob))
+	}
+
+	if err := WriteUint16(w, uint16(len(o.OnionBlob))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// Decode deserializes the serialized OnionMessage stored in the passed
+// io.Reader into the target OnionMessage using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, _ uint32) error {
+	var onionLen uint16
+	if err := ReadElements(r, &o.PathKey, &onionLen); err != nil {
+		return err
+	}
+
+	o.OnionBlob = make([]byte, onionLen)
+	if err := ReadElement(r, o.OnionBlob); err != nil {
+		return err
+	}
+
+	if err := ReadElement(r, &o.ExtraData); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(o.ExtraData) == 0 {
+		o.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as an OnionMessage on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -0,0 +1,297 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// OpenChannel2 is the message a node sends to start the v2 channel
+// establishment protocol. Unlike OpenChannel, both parties may contribute
+// funds to the channel, and the funding transaction is constructed
+// interactively using the tx_add_input, tx_add_output and tx_complete
+// messages once the remote party responds with AcceptChannel2.
+type OpenChannel2 struct {
+	// ChainHash is the target chain that the opener wishes to open a
+	// channel within.
+	ChainHash chainhash.Hash
+
+	// PendingChannelID serves to uniquely identify the future channel
+	// until the v2 channel ID can be derived from both revocation
+	// basepoints. It is the SHA256 of 33 zero bytes followed by the
+	// opener's revocation basepoint.
+	PendingChannelID [32]byte
+
+	// FundingFeePerKWeight is the fee rate the opener wishes the funding
+	// transaction to pay. Each party pays for the inputs and outputs it
+	// contributes at this fee rate.
+	FundingFeePerKWeight chainfee.SatPerKWeight
+
+	// CommitFeePerKWeight is the initial fee rate that the opener suggests
+	// for both commitment transactions.
+	CommitFeePerKWeight chainfee.SatPerKWeight
+
+	// FundingAmount is the amount of satoshis that the opener contributes
+	// to the channel.
+	FundingAmount btcutil.Amount
+
+	// DustLimit is the specific dust limit the sender of this message
+	// would like enforced on their version of the commitment transaction.
+	DustLimit btcutil.Amount
+
+	// MaxValueInFlight represents the maximum amount of coins that can be
+	// pending within the channel at any given time.
+	MaxValueInFlight MilliSatoshi
+
+	// HtlcMinimum is the smallest HTLC that the sender of this message
+	// will accept.
+	HtlcMinimum MilliSatoshi
+
+	// CsvDelay is the number of blocks to use for the relative time lock
+	// in the pay-to-self output of the receiver's commitment transaction.
+	CsvDelay uint16
+
+	// MaxAcceptedHTLCs is the total number of incoming HTLC's that the
+	// sender of this channel will accept.
+	MaxAcceptedHTLCs uint16
+
+	// LockTime is the lock time of the funding transaction.
+	LockTime uint32
+
+	// FundingKey is the key that should be used on behalf of the sender
+	// within the 2-of-2 multi-sig output that it contained within the
+	// funding transaction.
+	FundingKey *btcec.PublicKey
+
+	// RevocationPoint is the base revocation point for the sending party.
+	RevocationPoint *btcec.PublicKey
+
+	// PaymentPoint is the base payment point for the sending party.
+	PaymentPoint *btcec.PublicKey
+
+	// DelayedPaymentPoint is the delay point for the sending party.
+	DelayedPaymentPoint *btcec.PublicKey
+
+	// HtlcPoint is the base point used to derive the set of keys for this
+	// party that will be used within the HTLC public key scripts.
+	HtlcPoint *btcec.PublicKey
+
+	// FirstCommitmentPoint is the first commitment point for the sending
+	// party.
+	FirstCommitmentPoint *btcec.PublicKey
+
+	// SecondCommitmentPoint is the second commitment point for the sending
+	// party. As the funding transaction may be replaced before it
+	// confirms, it is sent upfront instead of in channel_ready.
+	SecondCommitmentPoint *btcec.PublicKey
+
+	// ChannelFlags is a bit-field which allows the opener of the channel
+	// to specify further behavior surrounding the channel.
+	ChannelFlags FundingFlag
+
+	// UpfrontShutdownScript is the script to which the channel funds
+	// should be paid when mutually closing the channel. This field is
+	// optional and only encoded if set.
+	UpfrontShutdownScript DeliveryAddress
+
+	// ChannelType is the explicit channel type the opener wishes to open.
+	ChannelType *ChannelType
+
+	// RequestFunding is set if the opener buys inbound liquidity from the
+	// acceptor through a channel lease. It's only set for channels with a
+	// script enforced lease.
+	RequestFunding *RequestFunding
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure OpenChannel2 implements the lnwire.Message
+// interface.
+var _ Message = (*OpenChannel2)(nil)
+
+// Encode serializes the target OpenChannel2 into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) Encode(w *bytes.Buffer, _ uint32) error {
+	var recordProducers []tlv.RecordProducer
+	if len(o.UpfrontShutdownScript) > 0 {
+		recordProducers = append(
+			recordProducers, &o.UpfrontShutdownScript,
+		)
+	}
+	if o.ChannelType != nil {
+		recordProducers = append(recordProducers, o.ChannelType)
+	}
+	if o.RequestFunding != nil {
+		recordProducers = append(recordProducers, o.RequestFunding)
+	}
+	err := EncodeMessageExtraData(&o.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.ChainHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.PendingChannelID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, uint32(o.FundingFeePerKWeight)); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, uint32(o.CommitFeePerKWeight)); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, o.FundingAmount); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, o.DustLimit); err != nil {
+		return err
+	}
+
+	if err := WriteMilliSatoshi(w, o.MaxValueInFlight); err != nil {
+		return err
+	}
+
+	if err := WriteMilliSatoshi(w, o.HtlcMinimum); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, o.CsvDelay); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, o.MaxAcceptedHTLCs); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.LockTime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.FundingKey); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.RevocationPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.PaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.DelayedPaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.HtlcPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.FirstCommitmentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.SecondCommitmentPoint); err != nil {
+		return err
+	}
+
+	if err := WriteFundingFlag(w, o.ChannelFlags); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// Decode deserializes the serialized OpenChannel2 stored in the passed
+// io.Reader into the target OpenChannel2 using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) Decode(r io.Reader, _ uint32) error {
+	var fundingFeeRate, commitFeeRate uint32
+	err := ReadElements(r,
+		o.ChainHash[:],
+		o.PendingChannelID[:],
+		&fundingFeeRate,
+		&commitFeeRate,
+		&o.FundingAmount,
+		&o.DustLimit,
+		&o.MaxValueInFlight,
+		&o.HtlcMinimum,
+		&o.CsvDelay,
+		&o.MaxAcceptedHTLCs,
+		&o.LockTime,
+		&o.FundingKey,
+		&o.RevocationPoint,
+		&o.PaymentPoint,
+		&o.DelayedPaymentPoint,
+		&o.HtlcPoint,
+		&o.FirstCommitmentPoint,
+		&o.SecondCommitmentPoint,
+		&o.ChannelFlags,
+	)
+	if err != nil {
+		return err
+	}
+	o.FundingFeePerKWeight = chainfee.SatPerKWeight(fundingFeeRate)
+	o.CommitFeePerKWeight = chainfee.SatPerKWeight(commitFeeRate)
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var (
+		shutdownScript DeliveryAddress
+		chanType       ChannelType
+		requestFunding RequestFunding
+	)
+	typeMap, err := tlvRecords.ExtractRecords(
+		&shutdownScript, &chanType, &requestFunding,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[DeliveryAddrType]; ok && val == nil {
+		o.UpfrontShutdownScript = shutdownScript
+	}
+	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
+		o.ChannelType = &chanType
+	}
+	if val, ok := typeMap[RequestFundingRecordType]; ok && val == nil {
+		o.RequestFunding = &requestFunding
+	}
+
+	if len(tlvRecords) != 0 {
+		o.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as an OpenChannel2 on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) MsgType() MessageType {
+	return MsgOpenChannel2
+}
@@ -0,0 +1,141 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// MaxPeerStorageBlobSize is the largest blob a peer can ask us to store, which
+// leaves room for the message type and blob length within the maximum message
+// size.
+const MaxPeerStorageBlobSize = 65531
+
+// PeerStorage is sent to a peer that advertises option_provide_storage, asking
+// it to store the given blob for us. The peer returns the latest blob it
+// stored within a YourPeerStorage message whenever we reconnect.
+type PeerStorage struct {
+	// Blob is the data the peer should store for us. It should be
+	// encrypted, as the peer is free to inspect it.
+	Blob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Encode serializes the target PeerStorage into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w *bytes.Buffer, _ uint32) error {
+	return encodeStorageBlob(w, p.Blob, p.ExtraData)
+}
+
+// Decode deserializes the serialized PeerStorage stored in the passed
+// io.Reader into the target PeerStorage using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, _ uint32) error {
+	return decodeStorageBlob(r, &p.Blob, &p.ExtraData)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a PeerStorage on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MsgType() MessageType {
+	return MsgPeerStorage
+}
+
+// YourPeerStorage is sent to a peer that previously asked us to store a blob
+// for it, returning the latest blob we stored. It's sent after every
+// reconnection, so that the peer is able to recover its data.
+type YourPeerStorage struct {
+	// Blob is the latest data the peer asked us to store.
+	Blob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure YourPeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*YourPeerStorage)(nil)
+
+// Encode serializes the target YourPeerStorage into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (y *YourPeerStorage) Encode(w *bytes.Buffer, _ uint32) error {
+	return encodeStorageBlob(w, y.Blob, y.ExtraData)
+}
+
+// Decode deserializes the serialized YourPeerStorage stored in the passed
+// io.Reader into the target YourPeerStorage using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (y *YourPeerStorage) Decode(r io.Reader, _ uint32) error {
+	return decodeStorageBlob(r, &y.Blob, &y.ExtraData)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a YourPeerStorage on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (y *YourPeerStorage) MsgType() MessageType {
+	return MsgYourPeerStorage
+}
+
+// encodeStorageBlob writes the length prefixed blob of a peer storage message,
+// followed by its extra data.
+func encodeStorageBlob(w *bytes.Buffer, blob []byte,
+	extraData ExtraOpaqueData) error {
+
+	if len(blob) > MaxPeerStorageBlobSize {
+		return fmt.Errorf("peer storage blob too large: %d bytes",
+			len(blob))
+	}
+
+	if err := WriteUint16(w, uint16(len(blob))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, blob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, extraData)
+}
+
+// decodeStorageBlob reads the length prefixed blob of a peer storage message,
+// followed by its extra data.
+func decodeStorageBlob(r io.Reader, blob *[]byte,
+	extraData *ExtraOpaqueData) error {
+
+	var blobLen uint16
+	if err := ReadElement(r, &blobLen); err != nil {
+		return err
+	}
+
+	*blob = make([]byte, blobLen)
+	if err := ReadElements(r, *blob, extraData); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(*extraData) == 0 {
+		*extraData = nil
+	}
+
+	return nil
+}
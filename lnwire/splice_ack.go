@@ -0,0 +1,94 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// SpliceAck is sent in response to a SpliceInit to accept the splice and
+// declare the funds the receiver wishes to contribute to it, if any.
+type SpliceAck struct {
+	// ChanID identifies the channel that is to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the sender adds to, or removes
+	// from its balance in the channel if negative.
+	FundingContribution btcutil.Amount
+
+	// FundingPubKey is the key the sender will use for the funding output
+	// created by the splice transaction.
+	FundingPubKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Encode serializes the target SpliceAck into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceAck stored in the passed io.Reader
+// into the target SpliceAck using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(
+		r, &s.ChanID, &s.FundingContribution, &s.FundingPubKey,
+		&s.ExtraData,
+	); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(s.ExtraData) == 0 {
+		s.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceAck on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*SpliceAck)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (s *SpliceAck) TargetChanID() ChannelID {
+	return s.ChanID
+}
@@ -0,0 +1,112 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// SpliceInit is sent by the quiescence initiator of a channel to start
+// negotiating a splice, which replaces the funding output of the channel with
+// a new one in order to add or remove funds without closing it.
+type SpliceInit struct {
+	// ChanID identifies the channel that is to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the sender adds to, or removes
+	// from its balance in the channel if negative.
+	FundingContribution btcutil.Amount
+
+	// FundingFeerate is the fee rate the splice transaction will pay.
+	FundingFeerate chainfee.SatPerKWeight
+
+	// Locktime is the locktime of the splice transaction.
+	Locktime uint32
+
+	// FundingPubKey is the key the sender will use for the funding output
+	// created by the splice transaction.
+	FundingPubKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Encode serializes the target SpliceInit into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, uint32(s.FundingFeerate)); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.Locktime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceInit stored in the passed io.Reader
+// into the target SpliceInit using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, _ uint32) error {
+	var feerate uint32
+	if err := ReadElements(
+		r, &s.ChanID, &s.FundingContribution, &feerate, &s.Locktime,
+		&s.FundingPubKey, &s.ExtraData,
+	); err != nil {
+		return err
+	}
+	s.FundingFeerate = chainfee.SatPerKWeight(feerate)
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(s.ExtraData) == 0 {
+		s.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceInit on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
+
+// A compile time check to ensure SpliceInit implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*SpliceInit)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (s *SpliceInit) TargetChanID() ChannelID {
+	return s.ChanID
+}
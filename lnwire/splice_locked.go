@@ -0,0 +1,90 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// SpliceLocked is sent by each party once the splice transaction of a channel
+// has reached sufficient depth, after which the channel continues to operate
+// on the new funding output.
+type SpliceLocked struct {
+	// ChanID identifies the channel that was spliced.
+	ChanID ChannelID
+
+	// SpliceTxid is the txid of the splice transaction that confirmed.
+	SpliceTxid chainhash.Hash
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceLocked implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceLocked)(nil)
+
+// Encode serializes the target SpliceLocked into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceLocked) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, s.SpliceTxid[:]); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceLocked stored in the passed
+// io.Reader into the target SpliceLocked using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceLocked) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElement(r, &s.ChanID); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(r, s.SpliceTxid[:]); err != nil {
+		return err
+	}
+
+	if err := ReadElement(r, &s.ExtraData); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(s.ExtraData) == 0 {
+		s.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceLocked on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) MsgType() MessageType {
+	return MsgSpliceLocked
+}
+
+// A compile time check to ensure SpliceLocked implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*SpliceLocked)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (s *SpliceLocked) TargetChanID() ChannelID {
+	return s.ChanID
+}
@@ -0,0 +1,81 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxAbort is sent to abort the interactive construction of a transaction, or
+// to abandon a transaction that was constructed but not yet fully signed.
+type TxAbort struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// Data is an optional human readable reason for the abort.
+	Data ErrorData
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAbort implements the lnwire.Message
+// interface.
+var _ Message = (*TxAbort)(nil)
+
+// Encode serializes the target TxAbort into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAbort) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteErrorData(w, t.Data); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAbort stored in the passed io.Reader
+// into the target TxAbort using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAbort) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(
+		r, &t.ChanID, &t.Data, &t.ExtraData,
+	); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(t.ExtraData) == 0 {
+		t.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAbort on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) MsgType() MessageType {
+	return MsgTxAbort
+}
+
+// A compile time check to ensure TxAbort implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*TxAbort)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxAbort) TargetChanID() ChannelID {
+	return t.ChanID
+}
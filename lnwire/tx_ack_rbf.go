@@ -0,0 +1,102 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// TxAckRbf is sent in response to TxInitRbf to accept the replacement of the
+// funding transaction of a dual funded channel.
+type TxAckRbf struct {
+	// ChanID identifies the channel whose funding transaction is replaced.
+	ChanID ChannelID
+
+	// FundingOutputContribution is the amount the sender contributes to
+	// the funding output of the replacement transaction. The value is
+	// encoded as a signed 64-bit integer.
+	FundingOutputContribution tlv.OptionalRecordT[tlv.TlvType0, uint64]
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAckRbf implements the lnwire.Message
+// interface.
+var _ Message = (*TxAckRbf)(nil)
+
+// Encode serializes the target TxAckRbf into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAckRbf) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	var recordProducers []tlv.RecordProducer
+	t.FundingOutputContribution.WhenSome(
+		func(amt tlv.RecordT[tlv.TlvType0, uint64]) {
+			recordProducers = append(recordProducers, &amt)
+		},
+	)
+
+	err := EncodeMessageExtraData(&t.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAckRbf stored in the passed io.Reader
+// into the target TxAckRbf using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAckRbf) Decode(r io.Reader, _ uint32) error {
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &t.ChanID, &tlvRecords); err != nil {
+		return err
+	}
+
+	amt := t.FundingOutputContribution.Zero()
+	typeMap, err := tlvRecords.ExtractRecords(&amt)
+	if err != nil {
+		return err
+	}
+
+	tlvType := t.FundingOutputContribution.TlvType()
+	if val, ok := typeMap[tlvType]; ok && val == nil {
+		t.FundingOutputContribution = tlv.SomeRecordT(amt)
+	}
+
+	if len(tlvRecords) != 0 {
+		t.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAckRbf on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) MsgType() MessageType {
+	return MsgTxAckRbf
+}
+
+// A compile time check to ensure TxAckRbf implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*TxAckRbf)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxAckRbf) TargetChanID() ChannelID {
+	return t.ChanID
+}
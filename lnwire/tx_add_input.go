@@ -0,0 +1,157 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// TxAddInput is sent during the interactive construction of a transaction to
+// add an input to it.
+type TxAddInput struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// SerialID uniquely identifies the input within the transaction and
+	// determines its position in it. Its parity indicates which party
+	// added it: the initiator of the construction uses even values.
+	SerialID uint64
+
+	// PrevTx is the serialized transaction whose output is spent by this
+	// input. It is empty for the shared input of a splice transaction,
+	// which is identified by SharedInputTxid instead.
+	PrevTx []byte
+
+	// PrevTxOut is the index of the spent output within PrevTx.
+	PrevTxOut uint32
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// SharedInputTxid is set if the input spends the current funding
+	// output of the channel, which is shared by both parties.
+	SharedInputTxid tlv.OptionalRecordT[tlv.TlvType0, [32]byte]
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddInput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddInput)(nil)
+
+// Encode serializes the target TxAddInput into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAddInput) Encode(w *bytes.Buffer, _ uint32) error {
+	if len(t.PrevTx) > math.MaxUint16 {
+		return fmt.Errorf("prev tx too large: %v bytes", len(t.PrevTx))
+	}
+
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, t.PrevTx); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.PrevTxOut); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.Sequence); err != nil {
+		return err
+	}
+
+	var recordProducers []tlv.RecordProducer
+	t.SharedInputTxid.WhenSome(
+		func(txid tlv.RecordT[tlv.TlvType0, [32]byte]) {
+			recordProducers = append(recordProducers, &txid)
+		},
+	)
+
+	err := EncodeMessageExtraData(&t.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAddInput stored in the passed io.Reader
+// into the target TxAddInput using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAddInput) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(r, &t.ChanID, &t.SerialID); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+	t.PrevTx = nil
+	if prevTxLen := binary.BigEndian.Uint16(l[:]); prevTxLen > 0 {
+		t.PrevTx = make([]byte, prevTxLen)
+		if _, err := io.ReadFull(r, t.PrevTx); err != nil {
+			return err
+		}
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(
+		r, &t.PrevTxOut, &t.Sequence, &tlvRecords,
+	); err != nil {
+		return err
+	}
+
+	txid := t.SharedInputTxid.Zero()
+	typeMap, err := tlvRecords.ExtractRecords(&txid)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[t.SharedInputTxid.TlvType()]; ok && val == nil {
+		t.SharedInputTxid = tlv.SomeRecordT(txid)
+	}
+
+	if len(tlvRecords) != 0 {
+		t.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAddInput on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) MsgType() MessageType {
+	return MsgTxAddInput
+}
+
+// A compile time check to ensure TxAddInput implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*TxAddInput)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxAddInput) TargetChanID() ChannelID {
+	return t.ChanID
+}
@@ -0,0 +1,123 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// TxAddOutput is sent during the interactive construction of a transaction to
+// add an output to it.
+type TxAddOutput struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// SerialID uniquely identifies the output within the transaction and
+	// determines its position in it. Its parity indicates which party
+	// added it: the initiator of the construction uses even values.
+	SerialID uint64
+
+	// Amount is the value of the output.
+	Amount btcutil.Amount
+
+	// PkScript is the script the output pays to.
+	PkScript []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddOutput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddOutput)(nil)
+
+// Encode serializes the target TxAddOutput into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAddOutput) Encode(w *bytes.Buffer, _ uint32) error {
+	if len(t.PkScript) > math.MaxUint16 {
+		return fmt.Errorf("script too large: %v bytes",
+			len(t.PkScript))
+	}
+
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, t.Amount); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, t.PkScript); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAddOutput stored in the passed
+// io.Reader into the target TxAddOutput using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxAddOutput) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(
+		r, &t.ChanID, &t.SerialID, &t.Amount,
+	); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+	t.PkScript = nil
+	if scriptLen := binary.BigEndian.Uint16(l[:]); scriptLen > 0 {
+		t.PkScript = make([]byte, scriptLen)
+		if _, err := io.ReadFull(r, t.PkScript); err != nil {
+			return err
+		}
+	}
+
+	if err := ReadElement(r, &t.ExtraData); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(t.ExtraData) == 0 {
+		t.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAddOutput on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) MsgType() MessageType {
+	return MsgTxAddOutput
+}
+
+// A compile time check to ensure TxAddOutput implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*TxAddOutput)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxAddOutput) TargetChanID() ChannelID {
+	return t.ChanID
+}
@@ -0,0 +1,73 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxComplete is sent during the interactive construction of a transaction to
+// signal that the sender has no further inputs or outputs to add. The
+// construction is complete once both parties have sent it in succession.
+type TxComplete struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxComplete implements the lnwire.Message
+// interface.
+var _ Message = (*TxComplete)(nil)
+
+// Encode serializes the target TxComplete into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxComplete) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxComplete stored in the passed io.Reader
+// into the target TxComplete using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxComplete) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(r, &t.ChanID, &t.ExtraData); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(t.ExtraData) == 0 {
+		t.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxComplete on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxComplete) MsgType() MessageType {
+	return MsgTxComplete
+}
+
+// A compile time check to ensure TxComplete implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*TxComplete)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxComplete) TargetChanID() ChannelID {
+	return t.ChanID
+}
@@ -0,0 +1,125 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// TxInitRbf is sent by the opener of a dual funded channel to replace the
+// funding transaction that was constructed before, but didn't confirm yet,
+// with one paying a higher fee rate. It restarts the interactive transaction
+// construction.
+type TxInitRbf struct {
+	// ChanID identifies the channel whose funding transaction is replaced.
+	ChanID ChannelID
+
+	// LockTime is the lock time of the replacement transaction.
+	LockTime uint32
+
+	// FeeRate is the fee rate of the replacement transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// FundingOutputContribution is the amount the sender contributes to
+	// the funding output of the replacement transaction. The value is
+	// encoded as a signed 64-bit integer.
+	FundingOutputContribution tlv.OptionalRecordT[tlv.TlvType0, uint64]
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxInitRbf implements the lnwire.Message
+// interface.
+var _ Message = (*TxInitRbf)(nil)
+
+// Encode serializes the target TxInitRbf into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxInitRbf) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.LockTime); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, uint32(t.FeeRate)); err != nil {
+		return err
+	}
+
+	var recordProducers []tlv.RecordProducer
+	t.FundingOutputContribution.WhenSome(
+		func(amt tlv.RecordT[tlv.TlvType0, uint64]) {
+			recordProducers = append(recordProducers, &amt)
+		},
+	)
+
+	err := EncodeMessageExtraData(&t.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxInitRbf stored in the passed io.Reader
+// into the target TxInitRbf using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxInitRbf) Decode(r io.Reader, _ uint32) error {
+	var (
+		feeRate    uint32
+		tlvRecords ExtraOpaqueData
+	)
+	if err := ReadElements(
+		r, &t.ChanID, &t.LockTime, &feeRate, &tlvRecords,
+	); err != nil {
+		return err
+	}
+	t.FeeRate = chainfee.SatPerKWeight(feeRate)
+
+	amt := t.FundingOutputContribution.Zero()
+	typeMap, err := tlvRecords.ExtractRecords(&amt)
+	if err != nil {
+		return err
+	}
+
+	tlvType := t.FundingOutputContribution.TlvType()
+	if val, ok := typeMap[tlvType]; ok && val == nil {
+		t.FundingOutputContribution = tlv.SomeRecordT(amt)
+	}
+
+	if len(tlvRecords) != 0 {
+		t.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxInitRbf on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) MsgType() MessageType {
+	return MsgTxInitRbf
+}
+
+// A compile time check to ensure TxInitRbf implements the lnwire.LinkUpdater
+// interface.
+var _ LinkUpdater = (*TxInitRbf)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxInitRbf) TargetChanID() ChannelID {
+	return t.ChanID
+}
@@ -0,0 +1,81 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxRemoveInput is sent during the interactive construction of a transaction
+// to remove an input the sender previously added to it.
+type TxRemoveInput struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// SerialID identifies the input that is to be removed.
+	SerialID uint64
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxRemoveInput implements the lnwire.Message
+// interface.
+var _ Message = (*TxRemoveInput)(nil)
+
+// Encode serializes the target TxRemoveInput into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxRemoveInput) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxRemoveInput stored in the passed
+// io.Reader into the target TxRemoveInput using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxRemoveInput) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(
+		r, &t.ChanID, &t.SerialID, &t.ExtraData,
+	); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(t.ExtraData) == 0 {
+		t.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxRemoveInput on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxRemoveInput) MsgType() MessageType {
+	return MsgTxRemoveInput
+}
+
+// A compile time check to ensure TxRemoveInput implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*TxRemoveInput)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxRemoveInput) TargetChanID() ChannelID {
+	return t.ChanID
+}
@@ -0,0 +1,81 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxRemoveOutput is sent during the interactive construction of a transaction
+// to remove an output the sender previously added to it.
+type TxRemoveOutput struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// SerialID identifies the output that is to be removed.
+	SerialID uint64
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxRemoveOutput implements the lnwire.Message
+// interface.
+var _ Message = (*TxRemoveOutput)(nil)
+
+// Encode serializes the target TxRemoveOutput into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxRemoveOutput) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxRemoveOutput stored in the passed
+// io.Reader into the target TxRemoveOutput using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxRemoveOutput) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(
+		r, &t.ChanID, &t.SerialID, &t.ExtraData,
+	); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(t.ExtraData) == 0 {
+		t.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxRemoveOutput on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxRemoveOutput) MsgType() MessageType {
+	return MsgTxRemoveOutput
+}
+
+// A compile time check to ensure TxRemoveOutput implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*TxRemoveOutput)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxRemoveOutput) TargetChanID() ChannelID {
+	return t.ChanID
+}
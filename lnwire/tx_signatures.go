@@ -0,0 +1,229 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// TxSignatures is sent once a transaction has been interactively constructed
+// and the commitment transactions spending its funding output have been
+// signed. It carries the witnesses of all inputs the sender added to the
+// transaction.
+type TxSignatures struct {
+	// ChanID identifies the channel the transaction is constructed for.
+	ChanID ChannelID
+
+	// Txid is the txid of the constructed transaction.
+	Txid chainhash.Hash
+
+	// Witnesses are the witnesses of the inputs the sender added to the
+	// transaction, ordered by their serial ID.
+	Witnesses []wire.TxWitness
+
+	// SharedInputSig is the sender's signature for the shared input of a
+	// splice transaction, which spends the current funding output.
+	SharedInputSig tlv.OptionalRecordT[tlv.TlvType0, Sig]
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxSignatures implements the lnwire.Message
+// interface.
+var _ Message = (*TxSignatures)(nil)
+
+// Encode serializes the target TxSignatures into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxSignatures) Encode(w *bytes.Buffer, _ uint32) error {
+	if len(t.Witnesses) > math.MaxUint16 {
+		return fmt.Errorf("too many witnesses: %v", len(t.Witnesses))
+	}
+
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.Txid[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(t.Witnesses))); err != nil {
+		return err
+	}
+
+	// Each witness is serialized as in the bitcoin consensus encoding and
+	// prefixed with its length.
+	for _, witness := range t.Witnesses {
+		var b bytes.Buffer
+		if err := writeTxWitness(&b, witness); err != nil {
+			return err
+		}
+
+		if b.Len() > math.MaxUint16 {
+			return fmt.Errorf("witness too large: %v bytes",
+				b.Len())
+		}
+
+		if err := writeDataWithLength(w, b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	var recordProducers []tlv.RecordProducer
+	t.SharedInputSig.WhenSome(func(sig tlv.RecordT[tlv.TlvType0, Sig]) {
+		recordProducers = append(recordProducers, &sig)
+	})
+
+	err := EncodeMessageExtraData(&t.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxSignatures stored in the passed
+// io.Reader into the target TxSignatures using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (t *TxSignatures) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElement(r, &t.ChanID); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(r, t.Txid[:]); err != nil {
+		return err
+	}
+
+	var numWitnesses uint16
+	if err := ReadElement(r, &numWitnesses); err != nil {
+		return err
+	}
+
+	t.Witnesses = nil
+	for i := 0; i < int(numWitnesses); i++ {
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return err
+		}
+
+		witnessBytes := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(r, witnessBytes); err != nil {
+			return err
+		}
+
+		witnessReader := bytes.NewReader(witnessBytes)
+		witness, err := readTxWitness(witnessReader)
+		if err != nil {
+			return err
+		}
+
+		// The witness must make up the entire length prefixed data,
+		// otherwise we wouldn't be able to encode it again.
+		if witnessReader.Len() != 0 {
+			return fmt.Errorf("witness %d has %d trailing bytes", i,
+				witnessReader.Len())
+		}
+
+		t.Witnesses = append(t.Witnesses, witness)
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElement(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	sig := t.SharedInputSig.Zero()
+	typeMap, err := tlvRecords.ExtractRecords(&sig)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[t.SharedInputSig.TlvType()]; ok && val == nil {
+		t.SharedInputSig = tlv.SomeRecordT(sig)
+	}
+
+	if len(tlvRecords) != 0 {
+		t.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxSignatures on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) MsgType() MessageType {
+	return MsgTxSignatures
+}
+
+// A compile time check to ensure TxSignatures implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*TxSignatures)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (t *TxSignatures) TargetChanID() ChannelID {
+	return t.ChanID
+}
+
+// writeTxWitness serializes the passed witness stack using the bitcoin
+// consensus encoding.
+func writeTxWitness(w io.Writer, witness wire.TxWitness) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(w, 0, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTxWitness deserializes a witness stack that was serialized using the
+// bitcoin consensus encoding.
+func readTxWitness(r io.Reader) (wire.TxWitness, error) {
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each item takes up at least one byte, so a witness of a message
+	// can't have more items than the maximum message size.
+	if numItems > MaxMsgBody {
+		return nil, fmt.Errorf("too many witness items: %v", numItems)
+	}
+
+	witness := make(wire.TxWitness, 0, numItems)
+	for i := uint64(0); i < numItems; i++ {
+		item, err := wire.ReadVarBytes(
+			r, 0, MaxMsgBody, "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		witness = append(witness, item)
+	}
+
+	return witness, nil
+}
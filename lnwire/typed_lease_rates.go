@@ -0,0 +1,175 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// LeaseRatesRecordType is the type of the node announcement record
+	// that advertises the rates a node sells channel leases at.
+	LeaseRatesRecordType tlv.Type = 1
+
+	// leaseRatesMinLength is the length of an encoded LeaseRates record
+	// with a zero ChannelFeeMaxBase, which is truncated to nothing.
+	leaseRatesMinLength = 10
+
+	// leaseRatesMaxLength is the maximum length of an encoded LeaseRates
+	// record.
+	leaseRatesMaxLength = 14
+
+	// leaseFeeBasisDenominator is the denominator of LeaseFeeBasis, which
+	// is expressed in basis points.
+	leaseFeeBasisDenominator = 10_000
+
+	// channelFeeMaxProportionalUnit is the unit of
+	// ChannelFeeMaxProportional in parts per million.
+	channelFeeMaxProportionalUnit = 1_000
+)
+
+// LeaseRates are the terms a node advertises in its node announcement for
+// leasing inbound liquidity to other nodes. The lessor contributes the leased
+// amount to a channel opened with the buyer, and keeps its funds in the
+// channel until the lease expires, in exchange for the lease fee.
+type LeaseRates struct {
+	// FundingWeight is the weight of the inputs and outputs the lessor
+	// adds to the funding transaction, which the buyer pays for as part of
+	// the lease fee.
+	FundingWeight uint16
+
+	// LeaseFeeBasis is the proportional part of the lease fee, in basis
+	// points of the leased amount.
+	LeaseFeeBasis uint16
+
+	// ChannelFeeMaxProportional is the largest proportional routing fee,
+	// in units of 1000 parts per million, the lessor promises to charge
+	// for forwarding over the leased channel for the duration of the
+	// lease.
+	ChannelFeeMaxProportional uint16
+
+	// LeaseFeeBase is the fixed part of the lease fee.
+	LeaseFeeBase btcutil.Amount
+
+	// ChannelFeeMaxBase is the largest base routing fee the lessor
+	// promises to charge for forwarding over the leased channel for the
+	// duration of the lease.
+	ChannelFeeMaxBase MilliSatoshi
+}
+
+// LeaseFee returns the fee a buyer pays to lease the given amount, when the
+// funding transaction of the leased channel pays the given fee rate.
+func (l *LeaseRates) LeaseFee(amt btcutil.Amount,
+	fundingFeeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	proportional := amt * btcutil.Amount(l.LeaseFeeBasis) /
+		leaseFeeBasisDenominator
+	fundingFee := fundingFeeRate.FeeForWeight(
+		lntypes.WeightUnit(l.FundingWeight),
+	)
+
+	return l.LeaseFeeBase + proportional + fundingFee
+}
+
+// ChannelFeeMaxPPM returns the largest proportional routing fee the lessor
+// charges on a leased channel, in parts per million.
+func (l *LeaseRates) ChannelFeeMaxPPM() uint64 {
+	return uint64(l.ChannelFeeMaxProportional) *
+		channelFeeMaxProportionalUnit
+}
+
+// Record returns a TLV record that can be used to encode/decode the lease
+// rates from a given TLV stream.
+func (l *LeaseRates) Record() tlv.Record {
+	return tlv.MakeDynamicRecord(
+		LeaseRatesRecordType, l, l.size, leaseRatesEncoder,
+		leaseRatesDecoder,
+	)
+}
+
+// size returns the length of the encoded lease rates.
+func (l *LeaseRates) size() uint64 {
+	return leaseRatesMinLength +
+		tlv.SizeTUint32(uint32(l.ChannelFeeMaxBase))
+}
+
+// leaseRatesEncoder is a custom TLV encoder for the LeaseRates record.
+func leaseRatesEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	v, ok := val.(*LeaseRates)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(val, "lnwire.LeaseRates")
+	}
+
+	if err := tlv.EUint16T(w, v.FundingWeight, buf); err != nil {
+		return err
+	}
+	if err := tlv.EUint16T(w, v.LeaseFeeBasis, buf); err != nil {
+		return err
+	}
+	err := tlv.EUint16T(w, v.ChannelFeeMaxProportional, buf)
+	if err != nil {
+		return err
+	}
+	if err := tlv.EUint32T(w, uint32(v.LeaseFeeBase), buf); err != nil {
+		return err
+	}
+
+	return tlv.ETUint32T(w, uint32(v.ChannelFeeMaxBase), buf)
+}
+
+// leaseRatesDecoder is a custom TLV decoder for the LeaseRates record.
+func leaseRatesDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(*LeaseRates)
+	if !ok || l < leaseRatesMinLength || l > leaseRatesMaxLength {
+		return tlv.NewTypeForDecodingErr(
+			val, "lnwire.LeaseRates", l, leaseRatesMaxLength,
+		)
+	}
+
+	var leaseFeeBase, channelFeeMaxBase uint32
+	if err := tlv.DUint16(r, &v.FundingWeight, buf, 2); err != nil {
+		return err
+	}
+	if err := tlv.DUint16(r, &v.LeaseFeeBasis, buf, 2); err != nil {
+		return err
+	}
+	err := tlv.DUint16(r, &v.ChannelFeeMaxProportional, buf, 2)
+	if err != nil {
+		return err
+	}
+	if err := tlv.DUint32(r, &leaseFeeBase, buf, 4); err != nil {
+		return err
+	}
+	err = tlv.DTUint32(
+		r, &channelFeeMaxBase, buf, l-leaseRatesMinLength,
+	)
+	if err != nil {
+		return err
+	}
+
+	v.LeaseFeeBase = btcutil.Amount(leaseFeeBase)
+	v.ChannelFeeMaxBase = MilliSatoshi(channelFeeMaxBase)
+
+	return nil
+}
+
+// ExtractLeaseRates returns the lease rates advertised in the extra data of
+// a node announcement, if any.
+func ExtractLeaseRates(extraData ExtraOpaqueData) (*LeaseRates, error) {
+	var rates LeaseRates
+	typeMap, err := extraData.ExtractRecords(&rates)
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := typeMap[LeaseRatesRecordType]; !ok || val != nil {
+		return nil, nil
+	}
+
+	return &rates, nil
+}
@@ -0,0 +1,68 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLeaseRatesEncodeDecode tests that we're able to properly encode and
+// decode lease rates within TLV streams, including a truncated maximum base
+// fee.
+func TestLeaseRatesEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []LeaseRates{
+		{
+			FundingWeight:             444,
+			LeaseFeeBasis:             65,
+			ChannelFeeMaxProportional: 2,
+			LeaseFeeBase:              1000,
+			ChannelFeeMaxBase:         5000,
+		},
+		{
+			FundingWeight: 444,
+			LeaseFeeBasis: 100,
+		},
+	}
+
+	for _, rates := range testCases {
+		var extraData ExtraOpaqueData
+		require.NoError(t, extraData.PackRecords(&rates))
+
+		decoded, err := ExtractLeaseRates(extraData)
+		require.NoError(t, err)
+		require.NotNil(t, decoded)
+		require.Equal(t, rates, *decoded)
+	}
+
+	// Extra data without lease rates doesn't advertise any.
+	leaseExpiry := LeaseExpiry(1337)
+	var extraData ExtraOpaqueData
+	require.NoError(t, extraData.PackRecords(&leaseExpiry))
+
+	decoded, err := ExtractLeaseRates(extraData)
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+}
+
+// TestLeaseFee tests the computation of the fee of a lease.
+func TestLeaseFee(t *testing.T) {
+	t.Parallel()
+
+	rates := LeaseRates{
+		FundingWeight:             500,
+		LeaseFeeBasis:             100,
+		ChannelFeeMaxProportional: 3,
+		LeaseFeeBase:              1000,
+	}
+
+	// 1% of 1M sats, plus the base fee, plus 500 weight units at 2000
+	// sat/kw.
+	fee := rates.LeaseFee(1_000_000, chainfee.SatPerKWeight(2000))
+	require.Equal(t, btcutil.Amount(10_000+1000+1000), fee)
+
+	require.EqualValues(t, 3000, rates.ChannelFeeMaxPPM())
+}
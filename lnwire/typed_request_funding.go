@@ -0,0 +1,90 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// RequestFundingRecordType is the type of the open_channel2 record
+	// that requests the acceptor to lease inbound liquidity to the opener.
+	RequestFundingRecordType tlv.Type = 3
+
+	// requestFundingLength is the length of an encoded RequestFunding
+	// record.
+	requestFundingLength = 12
+
+	// LeaseDuration is the number of blocks a channel lease bought through
+	// liquidity ads lasts, which is roughly four weeks.
+	LeaseDuration = 4032
+)
+
+// RequestFunding is sent by the opener of a dual-funded channel to buy inbound
+// liquidity from the acceptor, at the lease rates the acceptor advertises in
+// its node announcement. The funds of the acceptor are locked in the channel
+// until the lease expires, LeaseDuration blocks after BlockHeight.
+type RequestFunding struct {
+	// RequestedAmount is the amount the acceptor should contribute to the
+	// channel.
+	RequestedAmount btcutil.Amount
+
+	// BlockHeight is the height the lease starts at, which is the current
+	// height of the opener.
+	BlockHeight uint32
+}
+
+// LeaseExpiry returns the absolute height the requested lease expires at.
+func (r *RequestFunding) LeaseExpiry() uint32 {
+	return r.BlockHeight + LeaseDuration
+}
+
+// Record returns a TLV record that can be used to encode/decode the
+// RequestFunding record from a given TLV stream.
+func (r *RequestFunding) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		RequestFundingRecordType, r, requestFundingLength,
+		requestFundingEncoder, requestFundingDecoder,
+	)
+}
+
+// requestFundingEncoder is a custom TLV encoder for the RequestFunding
+// record.
+func requestFundingEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	v, ok := val.(*RequestFunding)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(val, "lnwire.RequestFunding")
+	}
+
+	amt := uint64(v.RequestedAmount)
+	if err := tlv.EUint64T(w, amt, buf); err != nil {
+		return err
+	}
+
+	return tlv.EUint32T(w, v.BlockHeight, buf)
+}
+
+// requestFundingDecoder is a custom TLV decoder for the RequestFunding
+// record.
+func requestFundingDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(*RequestFunding)
+	if !ok || l != requestFundingLength {
+		return tlv.NewTypeForDecodingErr(
+			val, "lnwire.RequestFunding", l, requestFundingLength,
+		)
+	}
+
+	var amt uint64
+	if err := tlv.DUint64(r, &amt, buf, 8); err != nil {
+		return err
+	}
+	if err := tlv.DUint32(r, &v.BlockHeight, buf, 4); err != nil {
+		return err
+	}
+	v.RequestedAmount = btcutil.Amount(amt)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// WillFundRecordType is the type of the accept_channel2 record with
+	// which the acceptor commits to the lease requested by the opener.
+	WillFundRecordType tlv.Type = 3
+
+	// sigLength is the length of an encoded signature.
+	sigLength = 64
+)
+
+// willFundTag is the tag prefixed to the lease commitment signed by the
+// acceptor of a leased channel.
+var willFundTag = []byte("option_will_fund")
+
+// WillFund is sent by the acceptor of a dual-funded channel that leases
+// inbound liquidity to the opener, in response to RequestFunding. It carries
+// the rates the lease is sold at, and the signature of the acceptor's node key
+// over the lease commitment, which proves that the acceptor agreed to the
+// maximum routing fees of the lease.
+type WillFund struct {
+	// Sig is the signature of the acceptor's node key over the digest of
+	// the message returned by LeaseCommitment.
+	Sig Sig
+
+	// Rates are the rates the lease is sold at, which must match the ones
+	// the acceptor advertises in its node announcement.
+	Rates LeaseRates
+}
+
+// Record returns a TLV record that can be used to encode/decode the WillFund
+// record from a given TLV stream.
+func (w *WillFund) Record() tlv.Record {
+	return tlv.MakeDynamicRecord(
+		WillFundRecordType, w, w.size, willFundEncoder,
+		willFundDecoder,
+	)
+}
+
+// size returns the length of the encoded WillFund record.
+func (w *WillFund) size() uint64 {
+	return sigLength + w.Rates.size()
+}
+
+// willFundEncoder is a custom TLV encoder for the WillFund record.
+func willFundEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	v, ok := val.(*WillFund)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(val, "lnwire.WillFund")
+	}
+
+	if _, err := w.Write(v.Sig.RawBytes()); err != nil {
+		return err
+	}
+
+	return leaseRatesEncoder(w, &v.Rates, buf)
+}
+
+// willFundDecoder is a custom TLV decoder for the WillFund record.
+func willFundDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(*WillFund)
+	if !ok || l < sigLength+leaseRatesMinLength ||
+		l > sigLength+leaseRatesMaxLength {
+
+		return tlv.NewTypeForDecodingErr(
+			val, "lnwire.WillFund", l,
+			sigLength+leaseRatesMaxLength,
+		)
+	}
+
+	if _, err := io.ReadFull(r, v.Sig.bytes[:]); err != nil {
+		return err
+	}
+
+	return leaseRatesDecoder(r, &v.Rates, buf, l-sigLength)
+}
+
+// LeaseCommitment returns the message the acceptor of a leased channel signs
+// to commit to the lease. It commits to the acceptor's funding key, the expiry
+// of the lease and the maximum routing fees the acceptor charges on the leased
+// channel until the lease expires. The signature covers the single SHA256
+// digest of the message.
+func LeaseCommitment(fundingKey *btcec.PublicKey, leaseExpiry uint32,
+	rates *LeaseRates) []byte {
+
+	var b bytes.Buffer
+	b.Write(willFundTag)
+	b.Write(fundingKey.SerializeCompressed())
+
+	var scratch [4]byte
+	binary.BigEndian.PutUint32(scratch[:], leaseExpiry)
+	b.Write(scratch[:])
+	binary.BigEndian.PutUint32(scratch[:], uint32(rates.ChannelFeeMaxBase))
+	b.Write(scratch[:])
+	binary.BigEndian.PutUint16(scratch[:2], rates.ChannelFeeMaxProportional)
+	b.Write(scratch[:2])
+
+	return b.Bytes()
+}
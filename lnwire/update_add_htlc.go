@@ -14,6 +14,22 @@ import (
 // of per-hop data, and a 32-byte HMAC over the entire packet.
 const OnionPacketSize = 1366
 
+const (
+	// ExperimentalEndorsementType is the TLV type of the custom record that
+	// carries the experimental endorsement signal of an htlc.
+	ExperimentalEndorsementType tlv.Type = 106823
+
+	// ExperimentalUnendorsed is the value of the experimental endorsement
+	// signal of an htlc that is not endorsed.
+	ExperimentalUnendorsed = 0
+
+	// ExperimentalEndorsed is the value of the experimental endorsement
+	// signal of an endorsed htlc. The signal is a single byte of which
+	// only the first three bits may be used, and all of them are set to
+	// endorse an htlc.
+	ExperimentalEndorsed = 7
+)
+
 type (
 	// BlindingPointTlvType is the type for ephemeral pubkeys used in
 	// route blinding.
@@ -3,8 +3,25 @@ package lnwire
 import (
 	"bytes"
 	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+type (
+	// AttrDataTlvType is the type of the attribution data of a failure.
+	AttrDataTlvType = tlv.TlvType1
+
+	// AttrDataRecord holds the optional attribution data of a failure.
+	AttrDataRecord = tlv.OptionalRecordT[AttrDataTlvType, []byte]
 )
 
+// SomeAttrData returns an AttrDataRecord holding the given attribution data.
+func SomeAttrData(attrData []byte) AttrDataRecord {
+	return tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[AttrDataTlvType](attrData),
+	)
+}
+
 // OpaqueReason is an opaque encrypted byte slice that encodes the exact
 // failure reason and additional some supplemental data. The contents of this
 // slice can only be decrypted by the sender of the original HTLC.
@@ -28,6 +45,16 @@ type UpdateFailHTLC struct {
 	// HTLC message.
 	Reason OpaqueReason
 
+	// AttrData is the attribution data of the failure, which holds the
+	// hold times and HMACs of the hops that passed the failure back. It
+	// allows the sender to attribute a tampered or delayed failure to a
+	// pair of hops.
+	AttrData AttrDataRecord
+
+	// CustomRecords maps TLV types to byte slices, storing arbitrary data
+	// intended for inclusion in the ExtraData field.
+	CustomRecords CustomRecords
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -43,12 +70,36 @@ var _ Message = (*UpdateFailHTLC)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateFailHTLC) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	// msgExtraData is a temporary variable used to read the message extra
+	// data field from the reader.
+	var msgExtraData ExtraOpaqueData
+	if err := ReadElements(r,
 		&c.ChanID,
 		&c.ID,
 		&c.Reason,
-		&c.ExtraData,
+		&msgExtraData,
+	); err != nil {
+		return err
+	}
+
+	attrData := c.AttrData.Zero()
+
+	customRecords, parsed, extraData, err := ParseAndExtractCustomRecords(
+		msgExtraData, &attrData,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Only set the attribution data if it was included in the stream.
+	if parsed.Contains(attrData.TlvType()) {
+		c.AttrData = tlv.SomeRecordT(attrData)
+	}
+
+	c.CustomRecords = customRecords
+	c.ExtraData = extraData
+
+	return nil
 }
 
 // Encode serializes the target UpdateFailHTLC into the passed io.Writer observing
@@ -68,7 +119,22 @@ func (c *UpdateFailHTLC) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
-	return WriteBytes(w, c.ExtraData)
+	// Only include the attribution data in the extra data if present.
+	var records []tlv.RecordProducer
+	c.AttrData.WhenSome(
+		func(attrData tlv.RecordT[AttrDataTlvType, []byte]) {
+			records = append(records, &attrData)
+		},
+	)
+
+	// Combine the known records, the custom records and the extra data,
+	// then encode the result as a byte slice.
+	extraData, err := MergeAndEncode(records, c.ExtraData, c.CustomRecords)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, extraData)
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
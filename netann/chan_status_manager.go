@@ -654,6 +654,19 @@ func (m *ChanStatusManager) fetchLastChanUpdateByOutPoint(op wire.OutPoint) (
 
 	// Get the edge info and policies for this channel from the graph.
 	info, edge1, edge2, err := m.cfg.Graph.FetchChannelEdgesByOutpoint(&op)
+
+	// The edge of a spliced channel is known by the funding output of its
+	// latest splice, so we'll retry with that one.
+	if errors.Is(err, channeldb.ErrEdgeNotFound) {
+		channel, chanErr := m.cfg.DB.FetchChannel(nil, op)
+		if chanErr == nil && channel.SpliceOutpoint.IsSome() {
+			spliceOp := channel.CurrentFundingOutpoint()
+			info, edge1, edge2, err =
+				m.cfg.Graph.FetchChannelEdgesByOutpoint(
+					&spliceOp,
+				)
+		}
+	}
 	if err != nil {
 		return nil, false, err
 	}
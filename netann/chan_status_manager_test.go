@@ -15,7 +15,9 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/stretchr/testify/require"
@@ -159,6 +161,21 @@ func (g *mockGraph) FetchAllOpenChannels() ([]*channeldb.OpenChannel, error) {
 	return g.chans(), nil
 }
 
+func (g *mockGraph) FetchChannel(_ kvdb.RTx,
+	chanPoint wire.OutPoint) (*channeldb.OpenChannel, error) {
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, c := range g.channels {
+		if c.FundingOutpoint == chanPoint {
+			return c, nil
+		}
+	}
+
+	return nil, channeldb.ErrChannelNotFound
+}
+
 func (g *mockGraph) FetchChannelEdgesByOutpoint(
 	op *wire.OutPoint) (*models.ChannelEdgeInfo,
 	*models.ChannelEdgePolicy, *models.ChannelEdgePolicy, error) {
@@ -254,10 +271,13 @@ func (g *mockGraph) addEdgePolicy(c *channeldb.OpenChannel,
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	g.chanInfos[c.FundingOutpoint] = info
-	g.chanPols1[c.FundingOutpoint] = pol1
-	g.chanPols2[c.FundingOutpoint] = pol2
-	g.sidToCid[c.ShortChanID()] = c.FundingOutpoint
+	// The edge of a spliced channel is known by its current funding
+	// outpoint.
+	chanPoint := c.CurrentFundingOutpoint()
+	g.chanInfos[chanPoint] = info
+	g.chanPols1[chanPoint] = pol1
+	g.chanPols2[chanPoint] = pol2
+	g.sidToCid[c.ShortChanID()] = chanPoint
 }
 
 func (g *mockGraph) removeChannel(channel *channeldb.OpenChannel) {
@@ -269,10 +289,11 @@ func (g *mockGraph) removeChannel(channel *channeldb.OpenChannel) {
 			continue
 		}
 
+		chanPoint := c.CurrentFundingOutpoint()
 		g.channels = append(g.channels[:i], g.channels[i+1:]...)
-		delete(g.chanInfos, c.FundingOutpoint)
-		delete(g.chanPols1, c.FundingOutpoint)
-		delete(g.chanPols2, c.FundingOutpoint)
+		delete(g.chanInfos, chanPoint)
+		delete(g.chanPols1, chanPoint)
+		delete(g.chanPols2, chanPoint)
 		delete(g.sidToCid, c.ShortChanID())
 		return
 	}
@@ -826,6 +847,38 @@ var stateMachineTests = []stateMachineTest{
 			h.assertNoUpdates(h.safeDisableTimeout)
 		},
 	},
+	{
+		name:         "disable spliced channel",
+		startActive:  true,
+		startEnabled: true,
+		fn: func(h testHarness) {
+			// Allow the manager to enter a steady state for the
+			// initial channel set.
+			h.assertNoUpdates(h.safeDisableTimeout)
+
+			// Add a channel that has been spliced, which is known
+			// to the graph by the funding output of the splice.
+			c := createChannel(h.t)
+			c.SpliceOutpoint = fn.Some(randOutpoint(h.t))
+			h.graph.addChannel(c)
+
+			info, pol1, pol2 := createEdgePolicies(
+				h.t, c, h.ourPubKey, true,
+			)
+			h.graph.addEdgePolicy(c, info, pol1, pol2)
+
+			splicedChans := []*channeldb.OpenChannel{c}
+			h.markActive(splicedChans)
+
+			// The channel is still referred to by the outpoint it
+			// was opened with, so disabling it should succeed and
+			// send out an update.
+			h.assertDisables(splicedChans, nil, false)
+			h.assertUpdates(
+				splicedChans, false, h.safeDisableTimeout,
+			)
+		},
+	},
 	{
 		name:         "request manual enable",
 		startActive:  true,
@@ -4,6 +4,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
 )
 
 // DB abstracts the required database functionality needed by the
@@ -12,6 +13,11 @@ type DB interface {
 	// FetchAllOpenChannels returns a slice of all open channels known to
 	// the daemon. This may include private or pending channels.
 	FetchAllOpenChannels() ([]*channeldb.OpenChannel, error)
+
+	// FetchChannel attempts to locate a channel specified by the passed
+	// channel point.
+	FetchChannel(tx kvdb.RTx, chanPoint wire.OutPoint) (
+		*channeldb.OpenChannel, error)
 }
 
 // ChannelGraph abstracts the required channel graph queries used by the
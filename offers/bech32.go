@@ -0,0 +1,89 @@
+package offers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+const (
+	// OfferHRP is the human readable part of encoded offers.
+	OfferHRP = "lno"
+
+	// InvoiceRequestHRP is the human readable part of encoded invoice
+	// requests.
+	InvoiceRequestHRP = "lnr"
+
+	// InvoiceHRP is the human readable part of encoded invoices.
+	InvoiceHRP = "lni"
+
+	// charset is the bech32 character set.
+	charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// encodeBech32 encodes the data with the given human readable part. Unlike
+// BOLT 11 invoices, offers messages are encoded without a checksum, as they
+// are signed and long enough for a checksum to be of little use.
+func encodeBech32(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteString("1")
+	for _, c := range converted {
+		b.WriteByte(charset[c])
+	}
+
+	return b.String(), nil
+}
+
+// decodeBech32 decodes a string encoded without a checksum and checks that it
+// has the expected human readable part. The string may be split into several
+// parts joined by a '+', optionally followed by whitespace.
+func decodeBech32(expectedHRP, s string) ([]byte, error) {
+	parts := strings.Split(s, "+")
+	for i, part := range parts {
+		if i > 0 {
+			part = strings.TrimLeft(part, " \t\r\n")
+		}
+		if part == "" {
+			return nil, fmt.Errorf("invalid '+' separator")
+		}
+
+		parts[i] = part
+	}
+	s = strings.Join(parts, "")
+
+	// Mixed case strings are invalid.
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return nil, fmt.Errorf("string has mixed case")
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 {
+		return nil, fmt.Errorf("missing separator")
+	}
+
+	hrp := lower[:sep]
+	if hrp != expectedHRP {
+		return nil, fmt.Errorf("expected prefix %v, got %v",
+			expectedHRP, hrp)
+	}
+
+	data := make([]byte, 0, len(lower)-sep-1)
+	for _, c := range lower[sep+1:] {
+		idx := strings.IndexRune(charset, c)
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+
+		data = append(data, byte(idx))
+	}
+
+	return bech32.ConvertBits(data, 5, 8, false)
+}
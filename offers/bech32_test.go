@@ -0,0 +1,46 @@
+package offers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBech32 tests the encoding of offers messages without a checksum.
+func TestBech32(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("a reusable offer for a payment")
+	encoded, err := encodeBech32(OfferHRP, data)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "lno1"))
+
+	decoded, err := decodeBech32(OfferHRP, encoded)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	// Upper case strings are accepted, mixed case strings aren't.
+	decoded, err = decodeBech32(OfferHRP, strings.ToUpper(encoded))
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	mixed := "LNO1" + encoded[4:]
+	_, err = decodeBech32(OfferHRP, mixed)
+	require.ErrorContains(t, err, "mixed case")
+
+	// The string may be split with '+' followed by whitespace.
+	split := encoded[:10] + "+\n  " + encoded[10:20] + "+" + encoded[20:]
+	decoded, err = decodeBech32(OfferHRP, split)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	_, err = decodeBech32(OfferHRP, encoded+"+")
+	require.Error(t, err)
+
+	_, err = decodeBech32(InvoiceHRP, encoded)
+	require.ErrorContains(t, err, "expected prefix")
+
+	_, err = decodeBech32(OfferHRP, encoded+"b")
+	require.ErrorContains(t, err, "invalid character")
+}
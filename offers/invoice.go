@@ -0,0 +1,414 @@
+package offers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	invoicePathsType          = 160
+	invoiceBlindedPayType     = 162
+	invoiceCreatedAtType      = 164
+	invoiceRelativeExpiryType = 166
+	invoicePaymentHashType    = 168
+	invoiceAmountType         = 170
+	invoiceFallbacksType      = 172
+	invoiceFeaturesType       = 174
+	invoiceNodeIDType         = 176
+
+	// invoiceRangeEnd is the last type of invoice records.
+	invoiceRangeEnd = 239
+
+	// invoiceName is the message name invoice signatures commit to.
+	invoiceName = "invoice"
+
+	// DefaultInvoiceExpiry is the expiry of invoices that don't specify
+	// one.
+	DefaultInvoiceExpiry = 2 * time.Hour
+)
+
+var (
+	// knownInvoiceTypes is the set of invoice records outside of the
+	// invoice request range that we understand.
+	knownInvoiceTypes = map[uint64]struct{}{
+		invoicePathsType:          {},
+		invoiceBlindedPayType:     {},
+		invoiceCreatedAtType:      {},
+		invoiceRelativeExpiryType: {},
+		invoicePaymentHashType:    {},
+		invoiceAmountType:         {},
+		invoiceFallbacksType:      {},
+		invoiceFeaturesType:       {},
+		invoiceNodeIDType:         {},
+		signatureType:             {},
+	}
+)
+
+// MessageSigner signs the tagged hash of a message with the key of our node.
+type MessageSigner func(tag, msg []byte) (*schnorr.Signature, error)
+
+// FallbackAddress is an on-chain address an invoice can be paid to.
+type FallbackAddress struct {
+	// Version is the witness version of the address.
+	Version uint8
+
+	// Program is the witness program of the address.
+	Program []byte
+}
+
+// Invoice is a BOLT 12 invoice, sent by the issuer of an offer in response to
+// an invoice request.
+type Invoice struct {
+	// InvoiceRequest is the invoice request the invoice responds to. Its
+	// records are mirrored by the invoice.
+	InvoiceRequest *InvoiceRequest
+
+	// Paths is the list of blinded payment paths to the issuer.
+	Paths []*sphinx.BlindedPath
+
+	// PayInfo holds the fees and limits of each of the paths.
+	PayInfo []*BlindedPayInfo
+
+	// CreatedAt is the time the invoice was created.
+	CreatedAt time.Time
+
+	// RelativeExpiry is the time after CreatedAt the invoice expires.
+	RelativeExpiry time.Duration
+
+	// PaymentHash is the hash of the payment preimage.
+	PaymentHash lntypes.Hash
+
+	// Amount is the amount to pay.
+	Amount lnwire.MilliSatoshi
+
+	// Fallbacks is an optional list of on-chain addresses to pay to.
+	Fallbacks []FallbackAddress
+
+	// Features is the set of features of the invoice.
+	Features *lnwire.RawFeatureVector
+
+	// NodeID is the key the invoice is signed with.
+	NodeID *btcec.PublicKey
+
+	// Signature is the signature of the invoice by NodeID.
+	Signature *schnorr.Signature
+
+	// records holds the serialized records of the invoice.
+	records records
+}
+
+// encodeRecords serializes the fields of the invoice, except the signature,
+// into records.
+func (i *Invoice) encodeRecords() (records, error) {
+	var b recordBuilder
+
+	// The signature of the invoice request isn't mirrored.
+	b.addRecords(i.InvoiceRequest.records.inRange(0, invReqRangeEnd))
+
+	paths, err := encodePaths(i.Paths)
+	if err != nil {
+		return nil, err
+	}
+	b.add(invoicePathsType, paths)
+
+	payInfo, err := encodePayInfos(i.PayInfo)
+	if err != nil {
+		return nil, err
+	}
+	b.add(invoiceBlindedPayType, payInfo)
+
+	b.addTU64(invoiceCreatedAtType, uint64(i.CreatedAt.Unix()))
+	if i.RelativeExpiry != 0 && i.RelativeExpiry != DefaultInvoiceExpiry {
+		b.addTU32(
+			invoiceRelativeExpiryType,
+			uint32(i.RelativeExpiry/time.Second),
+		)
+	}
+	b.add(invoicePaymentHashType, i.PaymentHash[:])
+	b.addTU64(invoiceAmountType, uint64(i.Amount))
+
+	if len(i.Fallbacks) > 0 {
+		var fallbacks bytes.Buffer
+		for _, fallback := range i.Fallbacks {
+			var lenBytes [2]byte
+			binary.BigEndian.PutUint16(
+				lenBytes[:], uint16(len(fallback.Program)),
+			)
+
+			fallbacks.WriteByte(fallback.Version)
+			fallbacks.Write(lenBytes[:])
+			fallbacks.Write(fallback.Program)
+		}
+		b.add(invoiceFallbacksType, fallbacks.Bytes())
+	}
+
+	if i.Features != nil && !i.Features.IsEmpty() {
+		b.addFeatures(invoiceFeaturesType, i.Features)
+	}
+	b.addPubKey(invoiceNodeIDType, i.NodeID)
+
+	return b.build(), nil
+}
+
+// validate checks that the invoice is well formed.
+func (i *Invoice) validate() error {
+	switch {
+	case len(i.Paths) == 0:
+		return fmt.Errorf("invoice has no paths")
+
+	case len(i.Paths) != len(i.PayInfo):
+		return fmt.Errorf("invoice has %d paths but %d pay infos",
+			len(i.Paths), len(i.PayInfo))
+
+	case i.CreatedAt.IsZero():
+		return fmt.Errorf("invoice has no creation time")
+
+	case i.Amount == 0:
+		return fmt.Errorf("invoice has no amount")
+
+	case i.NodeID == nil:
+		return fmt.Errorf("invoice has no node ID")
+	}
+
+	for _, fallback := range i.Fallbacks {
+		if len(fallback.Program) > 0xffff {
+			return fmt.Errorf("fallback address is too long")
+		}
+	}
+
+	return nil
+}
+
+// Sign serializes the invoice and signs it with the given signer, which must
+// sign with the key of NodeID.
+func (i *Invoice) Sign(signer MessageSigner) error {
+	if err := i.validate(); err != nil {
+		return err
+	}
+
+	recs, err := i.encodeRecords()
+	if err != nil {
+		return err
+	}
+
+	root := merkleRoot(recs)
+	sig, err := signer(signatureTag(invoiceName, "signature"), root[:])
+	if err != nil {
+		return err
+	}
+
+	i.Signature = sig
+	i.records = append(recs, record{
+		typ:   signatureType,
+		value: sig.Serialize(),
+	})
+
+	return nil
+}
+
+// Bytes returns the serialized invoice.
+func (i *Invoice) Bytes() []byte {
+	return i.records.encode()
+}
+
+// Encode returns the bech32 encoding of the invoice.
+func (i *Invoice) Encode() (string, error) {
+	return encodeBech32(InvoiceHRP, i.Bytes())
+}
+
+// ExpiresAt returns the time the invoice expires.
+func (i *Invoice) ExpiresAt() time.Time {
+	expiry := i.RelativeExpiry
+	if expiry == 0 {
+		expiry = DefaultInvoiceExpiry
+	}
+
+	return i.CreatedAt.Add(expiry)
+}
+
+// IsExpired returns whether the invoice is past its expiry at the given time.
+func (i *Invoice) IsExpired(now time.Time) bool {
+	return now.After(i.ExpiresAt())
+}
+
+// VerifyForRequest checks that the invoice is a valid response to the given
+// invoice request we sent.
+func (i *Invoice) VerifyForRequest(req *InvoiceRequest) error {
+	ours := req.records.inRange(0, invReqRangeEnd).encode()
+	theirs := i.InvoiceRequest.records.encode()
+	if !bytes.Equal(ours, theirs) {
+		return fmt.Errorf("invoice doesn't mirror the invoice request")
+	}
+
+	amt, err := req.InvoiceAmount()
+	if err != nil {
+		return err
+	}
+	if i.Amount != amt {
+		return fmt.Errorf("invoice amount %v doesn't match expected "+
+			"amount %v", i.Amount, amt)
+	}
+
+	// The invoice must be signed by the issuer of the offer, or by the
+	// final node of one of the offer's paths if it has no issuer ID.
+	offer := req.Offer
+	if offer.IssuerID != nil {
+		if !offer.IssuerID.IsEqual(i.NodeID) {
+			return fmt.Errorf("invoice node ID doesn't match " +
+				"offer issuer ID")
+		}
+
+		return nil
+	}
+
+	for _, path := range offer.Paths {
+		last := path.BlindedHops[len(path.BlindedHops)-1]
+		if last.BlindedNodePub.IsEqual(i.NodeID) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invoice node ID doesn't match any offer path")
+}
+
+// DecodeInvoice parses a bech32 encoded invoice.
+func DecodeInvoice(s string) (*Invoice, error) {
+	b, err := decodeBech32(InvoiceHRP, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseInvoice(b)
+}
+
+// ParseInvoice parses a serialized invoice and verifies its signature.
+func ParseInvoice(b []byte) (*Invoice, error) {
+	r, err := decodeRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range r {
+		if rec.typ > invoiceRangeEnd && rec.typ != signatureType {
+			return nil, fmt.Errorf("invoice has record of type %d "+
+				"outside of the invoice range", rec.typ)
+		}
+	}
+
+	req, err := parseInvReqRecords(r.inRange(0, invReqRangeEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &Invoice{
+		InvoiceRequest: req,
+		records:        r,
+	}
+
+	invoiceRecords := r.inRange(invReqRangeEnd+1, signatureRangeEnd)
+	if err := invoiceRecords.checkUnknown(knownInvoiceTypes); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range invoiceRecords {
+		switch rec.typ {
+		case invoicePathsType:
+			invoice.Paths, err = decodePaths(rec.value)
+
+		case invoiceBlindedPayType:
+			invoice.PayInfo, err = decodePayInfos(rec.value)
+
+		case invoiceCreatedAtType:
+			var createdAt uint64
+			createdAt, err = decodeTU64(rec.value)
+			invoice.CreatedAt = time.Unix(int64(createdAt), 0)
+
+		case invoiceRelativeExpiryType:
+			var expiry uint32
+			expiry, err = decodeTU32(rec.value)
+			invoice.RelativeExpiry = time.Duration(expiry) *
+				time.Second
+
+		case invoicePaymentHashType:
+			if len(rec.value) != lntypes.HashSize {
+				err = fmt.Errorf("invalid payment hash")
+				break
+			}
+			copy(invoice.PaymentHash[:], rec.value)
+
+		case invoiceAmountType:
+			var amt uint64
+			amt, err = decodeTU64(rec.value)
+			invoice.Amount = lnwire.MilliSatoshi(amt)
+
+		case invoiceFallbacksType:
+			invoice.Fallbacks, err = decodeFallbacks(rec.value)
+
+		case invoiceFeaturesType:
+			invoice.Features, err = decodeFeatures(rec.value)
+
+		case invoiceNodeIDType:
+			invoice.NodeID, err = btcec.ParsePubKey(rec.value)
+
+		case signatureType:
+			invoice.Signature, err = schnorr.ParseSignature(
+				rec.value,
+			)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice record %d: %w",
+				rec.typ, err)
+		}
+	}
+
+	if err := invoice.validate(); err != nil {
+		return nil, err
+	}
+	if invoice.Signature == nil {
+		return nil, fmt.Errorf("invoice has no signature")
+	}
+
+	err = verifySignature(
+		invoiceName, r, invoice.Signature.Serialize(), invoice.NodeID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// decodeFallbacks parses a list of fallback addresses.
+func decodeFallbacks(value []byte) ([]FallbackAddress, error) {
+	var (
+		r         = bytes.NewReader(value)
+		fallbacks []FallbackAddress
+	)
+	for r.Len() > 0 {
+		var header [3]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, err
+		}
+
+		program := make([]byte, binary.BigEndian.Uint16(header[1:]))
+		if _, err := io.ReadFull(r, program); err != nil {
+			return nil, err
+		}
+
+		fallbacks = append(fallbacks, FallbackAddress{
+			Version: header[0],
+			Program: program,
+		})
+	}
+
+	return fallbacks, nil
+}
@@ -0,0 +1,86 @@
+package offers
+
+import (
+	"fmt"
+)
+
+const (
+	invoiceErrorFieldType          = 1
+	invoiceErrorSuggestedValueType = 3
+	invoiceErrorMessageType        = 5
+)
+
+// InvoiceError is sent in response to an invoice request or invoice that
+// couldn't be handled.
+type InvoiceError struct {
+	// ErroneousField is the type of the record that caused the error, if
+	// any.
+	ErroneousField uint64
+
+	// SuggestedValue is an optional suggested value for the erroneous
+	// field.
+	SuggestedValue []byte
+
+	// Message is an explanation of the error.
+	Message string
+}
+
+// Error returns the error message.
+//
+// NOTE: This is part of the error interface.
+func (e *InvoiceError) Error() string {
+	if e.ErroneousField != 0 {
+		return fmt.Sprintf("invoice error in field %d: %v",
+			e.ErroneousField, e.Message)
+	}
+
+	return fmt.Sprintf("invoice error: %v", e.Message)
+}
+
+// Bytes returns the serialized invoice error.
+func (e *InvoiceError) Bytes() []byte {
+	var b recordBuilder
+
+	if e.ErroneousField != 0 {
+		b.addTU64(invoiceErrorFieldType, e.ErroneousField)
+	}
+	if len(e.SuggestedValue) > 0 {
+		b.add(invoiceErrorSuggestedValueType, e.SuggestedValue)
+	}
+	b.add(invoiceErrorMessageType, []byte(e.Message))
+
+	return b.build().encode()
+}
+
+// ParseInvoiceError parses a serialized invoice error.
+func ParseInvoiceError(b []byte) (*InvoiceError, error) {
+	r, err := decodeRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var invErr InvoiceError
+	for _, rec := range r {
+		switch rec.typ {
+		case invoiceErrorFieldType:
+			invErr.ErroneousField, err = decodeTU64(rec.value)
+
+		case invoiceErrorSuggestedValueType:
+			invErr.SuggestedValue = rec.value
+
+		case invoiceErrorMessageType:
+			invErr.Message, err = decodeUTF8(rec.value)
+
+		default:
+			if rec.typ%2 == 0 {
+				err = ErrUnknownEvenType
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice error record "+
+				"%d: %w", rec.typ, err)
+		}
+	}
+
+	return &invErr, nil
+}
@@ -0,0 +1,354 @@
+package offers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	invReqMetadataType  = 0
+	invReqChainType     = 80
+	invReqAmountType    = 82
+	invReqFeaturesType  = 84
+	invReqQuantityType  = 86
+	invReqPayerIDType   = 88
+	invReqPayerNoteType = 89
+	invReqPathsType     = 90
+
+	// invReqRangeEnd is the last type of invoice request records, which
+	// are mirrored by invoices.
+	invReqRangeEnd = 159
+
+	// invoiceRequestName is the message name invoice request signatures
+	// commit to.
+	invoiceRequestName = "invoice_request"
+)
+
+var (
+	// ErrCurrencyNotSupported is returned when the amount of an offer is
+	// denominated in a currency, which we can't convert.
+	ErrCurrencyNotSupported = errors.New("offers denominated in a " +
+		"currency are not supported")
+
+	// knownInvReqTypes is the set of invoice request records outside of
+	// the offer range that we understand.
+	knownInvReqTypes = map[uint64]struct{}{
+		invReqMetadataType:  {},
+		invReqChainType:     {},
+		invReqAmountType:    {},
+		invReqFeaturesType:  {},
+		invReqQuantityType:  {},
+		invReqPayerIDType:   {},
+		invReqPayerNoteType: {},
+		invReqPathsType:     {},
+		signatureType:       {},
+	}
+)
+
+// InvoiceRequest is a BOLT 12 invoice request, sent by a payer to the issuer
+// of an offer to request an invoice.
+type InvoiceRequest struct {
+	// Offer is the offer the invoice request is for. Its records are
+	// mirrored by the invoice request.
+	Offer *Offer
+
+	// Metadata is random data set by the payer, which makes the invoice
+	// request unique.
+	Metadata []byte
+
+	// Chain is the chain the payer wants to pay on, if it isn't bitcoin
+	// mainnet.
+	Chain fn.Option[chainhash.Hash]
+
+	// Amount is the amount the payer wants to pay, or zero to pay the
+	// amount of the offer.
+	Amount lnwire.MilliSatoshi
+
+	// Features is the set of features of the invoice request.
+	Features *lnwire.RawFeatureVector
+
+	// Quantity is the number of items requested, or zero if the offer
+	// doesn't allow a quantity.
+	Quantity uint64
+
+	// PayerID is the transient key the invoice request is signed with.
+	PayerID *btcec.PublicKey
+
+	// PayerNote is an optional note for the issuer.
+	PayerNote string
+
+	// Paths is an optional list of blinded paths to the payer.
+	Paths []*sphinx.BlindedPath
+
+	// Signature is the signature of the invoice request by PayerID.
+	Signature *schnorr.Signature
+
+	// records holds the serialized records of the invoice request.
+	records records
+}
+
+// encodeRecords serializes the fields of the invoice request, except the
+// signature, into records.
+func (r *InvoiceRequest) encodeRecords() (records, error) {
+	var b recordBuilder
+
+	b.addRecords(r.Offer.records)
+	b.add(invReqMetadataType, r.Metadata)
+	r.Chain.WhenSome(func(chain chainhash.Hash) {
+		b.add(invReqChainType, chain[:])
+	})
+	if r.Amount != 0 {
+		b.addTU64(invReqAmountType, uint64(r.Amount))
+	}
+	if r.Features != nil && !r.Features.IsEmpty() {
+		b.addFeatures(invReqFeaturesType, r.Features)
+	}
+	if r.Quantity != 0 {
+		b.addTU64(invReqQuantityType, r.Quantity)
+	}
+	b.addPubKey(invReqPayerIDType, r.PayerID)
+	if r.PayerNote != "" {
+		b.add(invReqPayerNoteType, []byte(r.PayerNote))
+	}
+	if len(r.Paths) > 0 {
+		paths, err := encodePaths(r.Paths)
+		if err != nil {
+			return nil, err
+		}
+		b.add(invReqPathsType, paths)
+	}
+
+	return b.build(), nil
+}
+
+// Sign serializes the invoice request and signs it with the private key of
+// PayerID.
+func (r *InvoiceRequest) Sign(payerKey *btcec.PrivateKey) error {
+	r.PayerID = payerKey.PubKey()
+
+	if err := r.validate(); err != nil {
+		return err
+	}
+
+	recs, err := r.encodeRecords()
+	if err != nil {
+		return err
+	}
+
+	hash := signatureHash(invoiceRequestName, "signature", merkleRoot(recs))
+	sig, err := schnorr.Sign(payerKey, hash[:])
+	if err != nil {
+		return err
+	}
+
+	r.Signature = sig
+	r.records = append(recs, record{
+		typ:   signatureType,
+		value: sig.Serialize(),
+	})
+
+	return nil
+}
+
+// Bytes returns the serialized invoice request.
+func (r *InvoiceRequest) Bytes() []byte {
+	return r.records.encode()
+}
+
+// Encode returns the bech32 encoding of the invoice request.
+func (r *InvoiceRequest) Encode() (string, error) {
+	return encodeBech32(InvoiceRequestHRP, r.Bytes())
+}
+
+// validate checks that the invoice request is consistent with its offer.
+func (r *InvoiceRequest) validate() error {
+	if len(r.Metadata) == 0 {
+		return fmt.Errorf("invoice request has no metadata")
+	}
+	if r.PayerID == nil {
+		return fmt.Errorf("invoice request has no payer ID")
+	}
+
+	offer := r.Offer
+	if offer.QuantityMax.IsSome() {
+		quantityMax := offer.QuantityMax.UnwrapOr(0)
+		if r.Quantity == 0 {
+			return fmt.Errorf("invoice request has no quantity")
+		}
+		if quantityMax != 0 && r.Quantity > quantityMax {
+			return fmt.Errorf("quantity %d exceeds maximum of %d",
+				r.Quantity, quantityMax)
+		}
+	} else if r.Quantity != 0 {
+		return fmt.Errorf("offer doesn't allow a quantity")
+	}
+
+	if offer.Amount == 0 && r.Amount == 0 {
+		return fmt.Errorf("invoice request has no amount")
+	}
+
+	return nil
+}
+
+// InvoiceAmount returns the amount an invoice for the request must be for.
+func (r *InvoiceRequest) InvoiceAmount() (lnwire.MilliSatoshi, error) {
+	offer := r.Offer
+	if offer.Amount == 0 {
+		return r.Amount, nil
+	}
+
+	if offer.Currency != "" {
+		return 0, ErrCurrencyNotSupported
+	}
+
+	quantity := r.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	expected := lnwire.MilliSatoshi(offer.Amount * quantity)
+
+	// The payer may choose to pay more than the offer asks for.
+	if r.Amount != 0 {
+		if r.Amount < expected {
+			return 0, fmt.Errorf("amount %v is less than the "+
+				"expected %v", r.Amount, expected)
+		}
+
+		return r.Amount, nil
+	}
+
+	return expected, nil
+}
+
+// ChainHash returns the chain the invoice request is for.
+func (r *InvoiceRequest) ChainHash(mainnet chainhash.Hash) chainhash.Hash {
+	return r.Chain.UnwrapOr(mainnet)
+}
+
+// DecodeInvoiceRequest parses a bech32 encoded invoice request.
+func DecodeInvoiceRequest(s string) (*InvoiceRequest, error) {
+	b, err := decodeBech32(InvoiceRequestHRP, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseInvoiceRequest(b)
+}
+
+// ParseInvoiceRequest parses a serialized invoice request and verifies its
+// signature.
+func ParseInvoiceRequest(b []byte) (*InvoiceRequest, error) {
+	r, err := decodeRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range r {
+		if rec.typ > invReqRangeEnd && rec.typ != signatureType {
+			return nil, fmt.Errorf("invoice request has record of "+
+				"type %d outside of the invoice request range",
+				rec.typ)
+		}
+	}
+
+	req, err := parseInvReqRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, ok := r.get(signatureType)
+	if !ok {
+		return nil, fmt.Errorf("invoice request has no signature")
+	}
+	req.Signature, err = schnorr.ParseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifySignature(invoiceRequestName, r, sig, req.PayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// parseInvReqRecords parses the invoice request records of an invoice request
+// or invoice.
+func parseInvReqRecords(r records) (*InvoiceRequest, error) {
+	offer, err := parseOfferRecords(
+		r.inRange(offerRangeStart, offerRangeEnd),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The offer records were checked above, so only the records outside
+	// of the offer range are left to check.
+	nonOffer := append(
+		r.inRange(0, offerRangeStart-1),
+		r.inRange(offerRangeEnd+1, invReqRangeEnd)...,
+	)
+	if err := nonOffer.checkUnknown(knownInvReqTypes); err != nil {
+		return nil, err
+	}
+
+	req := &InvoiceRequest{
+		Offer:   offer,
+		records: r.inRange(0, invReqRangeEnd),
+	}
+
+	for _, rec := range req.records {
+		switch rec.typ {
+		case invReqMetadataType:
+			req.Metadata = rec.value
+
+		case invReqChainType:
+			if len(rec.value) != chainhash.HashSize {
+				err = fmt.Errorf("invalid chain")
+				break
+			}
+
+			var chain chainhash.Hash
+			copy(chain[:], rec.value)
+			req.Chain = fn.Some(chain)
+
+		case invReqAmountType:
+			var amt uint64
+			amt, err = decodeTU64(rec.value)
+			req.Amount = lnwire.MilliSatoshi(amt)
+
+		case invReqFeaturesType:
+			req.Features, err = decodeFeatures(rec.value)
+
+		case invReqQuantityType:
+			req.Quantity, err = decodeTU64(rec.value)
+
+		case invReqPayerIDType:
+			req.PayerID, err = btcec.ParsePubKey(rec.value)
+
+		case invReqPayerNoteType:
+			req.PayerNote, err = decodeUTF8(rec.value)
+
+		case invReqPathsType:
+			req.Paths, err = decodePaths(rec.value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice request "+
+				"record %d: %w", rec.typ, err)
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,589 @@
+package offers
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionmessage"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+const (
+	// InvoiceRequestRecordType is the type of the onion message record
+	// that carries an invoice request.
+	InvoiceRequestRecordType = 64
+
+	// InvoiceRecordType is the type of the onion message record that
+	// carries an invoice.
+	InvoiceRecordType = 66
+
+	// InvoiceErrorRecordType is the type of the onion message record that
+	// carries an invoice error.
+	InvoiceErrorRecordType = 68
+
+	// DefaultFetchTimeout is the default time we wait for the response to
+	// an invoice request.
+	DefaultFetchTimeout = 30 * time.Second
+
+	// pathIDSize is the size of the path IDs of our reply paths.
+	pathIDSize = 32
+)
+
+var (
+	// ErrManagerShuttingDown is returned when the manager is stopped while
+	// waiting for an invoice.
+	ErrManagerShuttingDown = errors.New("offer manager shutting down")
+
+	// ErrFetchTimeout is returned when no response to an invoice request
+	// was received in time.
+	ErrFetchTimeout = errors.New("timed out waiting for invoice")
+)
+
+// Messenger sends and receives onion messages.
+type Messenger interface {
+	// SubscribeMessages returns a client that receives a
+	// onionmessage.ReceivedMessage for each onion message we are the
+	// final recipient of.
+	SubscribeMessages() (*subscribe.Client, error)
+
+	// Send sends an onion message.
+	Send(req *onionmessage.SendRequest) error
+}
+
+// CreatedInvoice holds the payment details of an invoice that was added to
+// our invoice registry in response to an invoice request.
+type CreatedInvoice struct {
+	// PaymentHash is the payment hash of the invoice.
+	PaymentHash lntypes.Hash
+
+	// Paths is the list of blinded payment paths to our node.
+	Paths []*sphinx.BlindedPath
+
+	// PayInfo holds the fees and limits of each of the paths.
+	PayInfo []*BlindedPayInfo
+
+	// Expiry is the time after its creation the invoice expires.
+	Expiry time.Duration
+
+	// Features is the set of features of the invoice.
+	Features *lnwire.RawFeatureVector
+}
+
+// ManagerConfig holds the dependencies of the Manager.
+type ManagerConfig struct {
+	// Store holds the offers we issued.
+	Store Store
+
+	// Messenger is used to exchange invoice requests and invoices.
+	Messenger Messenger
+
+	// NodeKey is the public key of our node, which is the issuer ID of
+	// our offers.
+	NodeKey *btcec.PublicKey
+
+	// SignMessage signs the invoices we issue with our node key.
+	SignMessage MessageSigner
+
+	// ChainHash is the genesis hash of the chain we operate on.
+	ChainHash chainhash.Hash
+
+	// CreateInvoice adds an invoice for the given amount to our invoice
+	// registry in response to the invoice request.
+	CreateInvoice func(req *InvoiceRequest,
+		amt lnwire.MilliSatoshi) (*CreatedInvoice, error)
+
+	// FetchTimeout is the time we wait for the response to an invoice
+	// request, unless the context of the request has a deadline.
+	FetchTimeout time.Duration
+
+	// Clock is the clock used to timestamp invoices and check expiries.
+	Clock clock.Clock
+}
+
+// FetchInvoiceRequest describes an invoice to fetch for an offer.
+type FetchInvoiceRequest struct {
+	// Offer is the offer to fetch an invoice for.
+	Offer *Offer
+
+	// Amount is the amount to pay. It must be set if the offer has no
+	// amount, and may be set to pay more than the offer asks for.
+	Amount lnwire.MilliSatoshi
+
+	// Quantity is the number of items to request, which must be set if
+	// the offer allows a quantity.
+	Quantity uint64
+
+	// PayerNote is an optional note for the issuer.
+	PayerNote string
+}
+
+// fetchResult is the response to an invoice request we sent.
+type fetchResult struct {
+	invoice *Invoice
+	err     error
+}
+
+// pendingRequest is an invoice request we sent and are waiting for the
+// response to.
+type pendingRequest struct {
+	req    *InvoiceRequest
+	result chan *fetchResult
+}
+
+// Manager issues invoices in response to invoice requests for our offers,
+// and fetches invoices for the offers of others.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *ManagerConfig
+
+	// pending holds the invoice requests we are waiting for a response
+	// to, keyed by the path ID of their reply path.
+	pending   map[[pathIDSize]byte]*pendingRequest
+	pendingMu sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewManager creates a new offer manager.
+func NewManager(cfg *ManagerConfig) *Manager {
+	if cfg.FetchTimeout == 0 {
+		cfg.FetchTimeout = DefaultFetchTimeout
+	}
+
+	return &Manager{
+		cfg:     cfg,
+		pending: make(map[[pathIDSize]byte]*pendingRequest),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to incoming onion messages.
+func (m *Manager) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Offer manager starting")
+
+		var client *subscribe.Client
+		client, err = m.cfg.Messenger.SubscribeMessages()
+		if err != nil {
+			return
+		}
+
+		m.wg.Add(1)
+		go m.handleMessages(client)
+	})
+
+	return err
+}
+
+// Stop stops the manager.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Offer manager shutting down...")
+		defer log.Debug("Offer manager shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// handleMessages processes the onion messages we receive.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) handleMessages(client *subscribe.Client) {
+	defer m.wg.Done()
+	defer client.Cancel()
+
+	for {
+		select {
+		case update := <-client.Updates():
+			msg, ok := update.(*onionmessage.ReceivedMessage)
+			if !ok {
+				continue
+			}
+
+			m.handleMessage(msg)
+
+		case <-client.Quit():
+			return
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// handleMessage dispatches an onion message by the offers record it holds.
+func (m *Manager) handleMessage(msg *onionmessage.ReceivedMessage) {
+	if req, ok := msg.CustomRecords[InvoiceRequestRecordType]; ok {
+		m.handleInvoiceRequest(msg, req)
+		return
+	}
+
+	if invoice, ok := msg.CustomRecords[InvoiceRecordType]; ok {
+		m.handleInvoice(msg.PathID, invoice)
+		return
+	}
+
+	if invErr, ok := msg.CustomRecords[InvoiceErrorRecordType]; ok {
+		m.handleInvoiceError(msg.PathID, invErr)
+	}
+}
+
+// handleInvoiceRequest responds to an invoice request for one of our offers
+// with an invoice, or an invoice error if we can't issue one.
+func (m *Manager) handleInvoiceRequest(msg *onionmessage.ReceivedMessage,
+	reqBytes []byte) {
+
+	// Without a reply path there's no way to respond.
+	if msg.ReplyPath == nil {
+		log.Debugf("Ignoring invoice request without reply path")
+		return
+	}
+
+	reply := func(typ uint64, value []byte) {
+		err := m.cfg.Messenger.Send(&onionmessage.SendRequest{
+			BlindedPath: msg.ReplyPath,
+			CustomRecords: map[uint64][]byte{
+				typ: value,
+			},
+		})
+		if err != nil {
+			log.Errorf("Unable to reply to invoice request: %v",
+				err)
+		}
+	}
+
+	req, err := ParseInvoiceRequest(reqBytes)
+	if err != nil {
+		log.Debugf("Invalid invoice request: %v", err)
+
+		reply(InvoiceErrorRecordType, (&InvoiceError{
+			Message: "invalid invoice request",
+		}).Bytes())
+
+		return
+	}
+
+	invoice, err := m.issueInvoice(req)
+	if err != nil {
+		log.Debugf("Unable to issue invoice for offer %v: %v",
+			req.Offer.ID(), err)
+
+		var invErr *InvoiceError
+		if !errors.As(err, &invErr) {
+			invErr = &InvoiceError{
+				Message: "unable to issue invoice",
+			}
+		}
+		reply(InvoiceErrorRecordType, invErr.Bytes())
+
+		return
+	}
+
+	log.Infof("Issued invoice %v for offer %v", invoice.PaymentHash,
+		req.Offer.ID())
+
+	reply(InvoiceRecordType, invoice.Bytes())
+}
+
+// issueInvoice creates and signs an invoice in response to an invoice
+// request.
+func (m *Manager) issueInvoice(req *InvoiceRequest) (*Invoice, error) {
+	offer, err := m.cfg.Store.FetchOffer(req.Offer.ID())
+	if err != nil {
+		return nil, &InvoiceError{
+			Message: "unknown offer",
+		}
+	}
+
+	if offer.IsExpired(m.cfg.Clock.Now()) {
+		return nil, &InvoiceError{
+			Message: ErrOfferExpired.Error(),
+		}
+	}
+
+	if req.ChainHash(*chaincfg.MainNetParams.GenesisHash) !=
+		m.cfg.ChainHash {
+
+		return nil, &InvoiceError{
+			ErroneousField: invReqChainType,
+			Message:        "unsupported chain",
+		}
+	}
+
+	amt, err := req.InvoiceAmount()
+	if err != nil {
+		return nil, &InvoiceError{
+			ErroneousField: invReqAmountType,
+			Message:        err.Error(),
+		}
+	}
+
+	created, err := m.cfg.CreateInvoice(req, amt)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &Invoice{
+		InvoiceRequest: req,
+		Paths:          created.Paths,
+		PayInfo:        created.PayInfo,
+		CreatedAt:      m.cfg.Clock.Now(),
+		RelativeExpiry: created.Expiry,
+		PaymentHash:    created.PaymentHash,
+		Amount:         amt,
+		Features:       created.Features,
+		NodeID:         m.cfg.NodeKey,
+	}
+	if err := invoice.Sign(m.cfg.SignMessage); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// handleInvoice delivers an invoice to the pending request it responds to.
+func (m *Manager) handleInvoice(pathID []byte, invoiceBytes []byte) {
+	pending := m.removePending(pathID)
+	if pending == nil {
+		log.Debugf("Ignoring unexpected invoice")
+		return
+	}
+
+	invoice, err := ParseInvoice(invoiceBytes)
+	if err == nil {
+		err = invoice.VerifyForRequest(pending.req)
+	}
+	if err == nil && invoice.IsExpired(m.cfg.Clock.Now()) {
+		err = fmt.Errorf("invoice has expired")
+	}
+	if err != nil {
+		pending.result <- &fetchResult{
+			err: fmt.Errorf("invalid invoice: %w", err),
+		}
+
+		return
+	}
+
+	pending.result <- &fetchResult{invoice: invoice}
+}
+
+// handleInvoiceError delivers an invoice error to the pending request it
+// responds to.
+func (m *Manager) handleInvoiceError(pathID []byte, errBytes []byte) {
+	pending := m.removePending(pathID)
+	if pending == nil {
+		log.Debugf("Ignoring unexpected invoice error")
+		return
+	}
+
+	invErr, err := ParseInvoiceError(errBytes)
+	if err != nil {
+		pending.result <- &fetchResult{
+			err: fmt.Errorf("invalid invoice error: %w", err),
+		}
+
+		return
+	}
+
+	pending.result <- &fetchResult{err: invErr}
+}
+
+// removePending removes and returns the pending request with the given path
+// ID, if any.
+func (m *Manager) removePending(pathID []byte) *pendingRequest {
+	if len(pathID) != pathIDSize {
+		return nil
+	}
+
+	var id [pathIDSize]byte
+	copy(id[:], pathID)
+
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	pending, ok := m.pending[id]
+	if !ok {
+		return nil
+	}
+	delete(m.pending, id)
+
+	return pending
+}
+
+// AddOffer creates a new offer issued by our node and adds it to the store.
+// The issuer ID of the offer is set to our node key.
+func (m *Manager) AddOffer(offer *Offer) error {
+	if offer.Currency != "" {
+		return ErrCurrencyNotSupported
+	}
+
+	offer.IssuerID = m.cfg.NodeKey
+	if m.cfg.ChainHash != *chaincfg.MainNetParams.GenesisHash {
+		offer.Chains = []chainhash.Hash{m.cfg.ChainHash}
+	}
+
+	if err := offer.Finalize(); err != nil {
+		return err
+	}
+
+	return m.cfg.Store.AddOffer(offer)
+}
+
+// ListOffers returns the offers we issued.
+func (m *Manager) ListOffers() ([]*Offer, error) {
+	return m.cfg.Store.ListOffers()
+}
+
+// FetchInvoice sends an invoice request for an offer to its issuer and waits
+// for the invoice. The invoice request is sent to the issuer directly or to
+// the introduction node of one of the offer's paths, which must be a peer of
+// ours.
+func (m *Manager) FetchInvoice(ctx context.Context,
+	fetchReq *FetchInvoiceRequest) (*Invoice, error) {
+
+	offer := fetchReq.Offer
+	if offer.IsExpired(m.cfg.Clock.Now()) {
+		return nil, ErrOfferExpired
+	}
+
+	mainnet := *chaincfg.MainNetParams.GenesisHash
+	if !offer.SupportsChain(m.cfg.ChainHash, mainnet) {
+		return nil, fmt.Errorf("offer is not valid on our chain")
+	}
+
+	req, err := m.newInvoiceRequest(fetchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	sendReq := &onionmessage.SendRequest{
+		CustomRecords: map[uint64][]byte{
+			InvoiceRequestRecordType: req.Bytes(),
+		},
+	}
+
+	// The reply path leads back to us through the node we send the
+	// request to, so that the issuer can reach us.
+	replyHops := []route.Vertex{route.NewVertex(m.cfg.NodeKey)}
+	if len(offer.Paths) > 0 {
+		path := offer.Paths[0]
+		sendReq.BlindedPath = path
+		replyHops = append(
+			[]route.Vertex{route.NewVertex(path.IntroductionPoint)},
+			replyHops...,
+		)
+	} else {
+		issuer := route.NewVertex(offer.IssuerID)
+		sendReq.Destination = &issuer
+	}
+
+	var pathID [pathIDSize]byte
+	if _, err := rand.Read(pathID[:]); err != nil {
+		return nil, err
+	}
+
+	sendReq.ReplyPath, err = blindedpath.BuildOnionMessagePath(
+		&blindedpath.OnionMessagePathCfg{
+			Hops:   replyHops,
+			PathID: pathID[:],
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &pendingRequest{
+		req:    req,
+		result: make(chan *fetchResult, 1),
+	}
+
+	m.pendingMu.Lock()
+	m.pending[pathID] = pending
+	m.pendingMu.Unlock()
+
+	defer m.removePending(pathID[:])
+
+	if err := m.cfg.Messenger.Send(sendReq); err != nil {
+		return nil, err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.FetchTimeout)
+		defer cancel()
+	}
+
+	select {
+	case result := <-pending.result:
+		return result.invoice, result.err
+
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrFetchTimeout
+		}
+
+		return nil, ctx.Err()
+
+	case <-m.quit:
+		return nil, ErrManagerShuttingDown
+	}
+}
+
+// newInvoiceRequest creates an invoice request signed with a fresh payer
+// key.
+func (m *Manager) newInvoiceRequest(
+	fetchReq *FetchInvoiceRequest) (*InvoiceRequest, error) {
+
+	metadata := make([]byte, 32)
+	if _, err := rand.Read(metadata); err != nil {
+		return nil, err
+	}
+
+	payerKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &InvoiceRequest{
+		Offer:     fetchReq.Offer,
+		Metadata:  metadata,
+		Amount:    fetchReq.Amount,
+		Quantity:  fetchReq.Quantity,
+		PayerNote: fetchReq.PayerNote,
+	}
+	if m.cfg.ChainHash != *chaincfg.MainNetParams.GenesisHash {
+		req.Chain = fn.Some(m.cfg.ChainHash)
+	}
+
+	if err := req.Sign(payerKey); err != nil {
+		return nil, err
+	}
+
+	// Make sure we'll accept the amount of the invoice.
+	if _, err := req.InvoiceAmount(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
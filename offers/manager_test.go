@@ -0,0 +1,206 @@
+package offers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionmessage"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a node with an offer manager that exchanges onion messages with
+// the other nodes of a test network directly.
+type testNode struct {
+	key       *btcec.PrivateKey
+	messenger *onionmessage.Messenger
+	manager   *Manager
+	invoices  []lnwire.MilliSatoshi
+}
+
+// newTestNetwork creates a network of directly connected nodes.
+func newTestNetwork(t *testing.T, numNodes int) []*testNode {
+	nodes := make(map[route.Vertex]*testNode)
+
+	var network []*testNode
+	for i := 0; i < numNodes; i++ {
+		key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		self := route.NewVertex(key.PubKey())
+		node := &testNode{key: key}
+
+		node.messenger = onionmessage.NewMessenger(&onionmessage.Config{
+			NodeKey: &keychain.PrivKeyECDH{PrivKey: key},
+			SendToPeer: func(peer route.Vertex,
+				msg *lnwire.OnionMessage) error {
+
+				target, ok := nodes[peer]
+				if !ok {
+					return fmt.Errorf("unknown peer %v",
+						peer)
+				}
+
+				return target.messenger.HandleMessage(
+					self, msg,
+				)
+			},
+		})
+		require.NoError(t, node.messenger.Start())
+		t.Cleanup(func() {
+			require.NoError(t, node.messenger.Stop())
+		})
+
+		backend, cleanup, err := kvdb.GetTestBackend(
+			t.TempDir(), "offers",
+		)
+		require.NoError(t, err)
+		t.Cleanup(cleanup)
+
+		store, err := NewStore(backend)
+		require.NoError(t, err)
+
+		node.manager = NewManager(&ManagerConfig{
+			Store:       store,
+			Messenger:   node.messenger,
+			NodeKey:     key.PubKey(),
+			SignMessage: keySigner(key),
+			ChainHash:   *chaincfg.RegressionNetParams.GenesisHash,
+			CreateInvoice: func(req *InvoiceRequest,
+				amt lnwire.MilliSatoshi) (*CreatedInvoice,
+				error) {
+
+				node.invoices = append(node.invoices, amt)
+
+				return &CreatedInvoice{
+					PaymentHash: lntypes.Hash{1},
+					Paths: []*sphinx.BlindedPath{
+						newTestPath(t, key.PubKey()),
+					},
+					PayInfo: []*BlindedPayInfo{{
+						CltvExpiryDelta: 80,
+						HtlcMaximumMsat: amt,
+						Features: lnwire.
+							NewRawFeatureVector(),
+					}},
+					Expiry: time.Hour,
+				}, nil
+			},
+			FetchTimeout: time.Second,
+			Clock:        clock.NewDefaultClock(),
+		})
+		require.NoError(t, node.manager.Start())
+		t.Cleanup(func() {
+			require.NoError(t, node.manager.Stop())
+		})
+
+		nodes[self] = node
+		network = append(network, node)
+	}
+
+	return network
+}
+
+// TestFetchInvoice tests that a payer can fetch an invoice for an offer from
+// its issuer.
+func TestFetchInvoice(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t, 2)
+	alice, bob := network[0], network[1]
+
+	offer := &Offer{
+		Amount:      1000,
+		Description: "coffee",
+		QuantityMax: fn.Some[uint64](0),
+	}
+	require.NoError(t, bob.manager.AddOffer(offer))
+	require.ErrorIs(t, bob.manager.AddOffer(offer), ErrDuplicateOffer)
+
+	offers, err := bob.manager.ListOffers()
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, offer.ID(), offers[0].ID())
+
+	// Alice decodes the offer and fetches an invoice for three items.
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	decoded, err := DecodeOffer(encoded)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	invoice, err := alice.manager.FetchInvoice(ctx, &FetchInvoiceRequest{
+		Offer:     decoded,
+		Quantity:  3,
+		PayerNote: "three please",
+	})
+	require.NoError(t, err)
+	require.Equal(t, lnwire.MilliSatoshi(3000), invoice.Amount)
+	require.True(t, invoice.NodeID.IsEqual(bob.key.PubKey()))
+	require.Equal(t, "three please", invoice.InvoiceRequest.PayerNote)
+	require.Equal(t, []lnwire.MilliSatoshi{3000}, bob.invoices)
+
+	// An invoice request for an offer Bob doesn't know is answered with
+	// an invoice error.
+	unknown := &Offer{
+		Description: "unknown",
+		IssuerID:    bob.key.PubKey(),
+		Chains:      decoded.Chains,
+	}
+	require.NoError(t, unknown.Finalize())
+
+	_, err = alice.manager.FetchInvoice(ctx, &FetchInvoiceRequest{
+		Offer:  unknown,
+		Amount: 500,
+	})
+	var invErr *InvoiceError
+	require.ErrorAs(t, err, &invErr)
+	require.Equal(t, "unknown offer", invErr.Message)
+
+	// The amount must be given for offers without one.
+	_, err = alice.manager.FetchInvoice(ctx, &FetchInvoiceRequest{
+		Offer: unknown,
+	})
+	require.ErrorContains(t, err, "no amount")
+}
+
+// TestFetchInvoiceTimeout tests that fetching an invoice times out if the
+// issuer doesn't respond.
+func TestFetchInvoiceTimeout(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t, 2)
+	alice, bob := network[0], network[1]
+
+	// Bob doesn't handle invoice requests once his manager is stopped.
+	require.NoError(t, bob.manager.Stop())
+
+	offer := &Offer{
+		Description: "donation",
+		IssuerID:    bob.key.PubKey(),
+		Chains: []chainhash.Hash{
+			*chaincfg.RegressionNetParams.GenesisHash,
+		},
+	}
+	require.NoError(t, offer.Finalize())
+
+	_, err := alice.manager.FetchInvoice(
+		context.Background(), &FetchInvoiceRequest{
+			Offer:  offer,
+			Amount: 1000,
+		},
+	)
+	require.ErrorIs(t, err, ErrFetchTimeout)
+}
@@ -0,0 +1,120 @@
+package offers
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// signatureType is the type of the signature record of invoice
+	// requests and invoices.
+	signatureType = 240
+
+	// signatureRangeEnd is the last type of the range of signature
+	// records, which aren't part of the merkle tree.
+	signatureRangeEnd = 1000
+)
+
+var (
+	leafTag   = []byte("LnLeaf")
+	nonceTag  = []byte("LnNonce")
+	branchTag = []byte("LnBranch")
+)
+
+// branchHash returns the hash of an inner node of the merkle tree, which
+// commits to its children in sorted order.
+func branchHash(a, b *chainhash.Hash) *chainhash.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return chainhash.TaggedHash(branchTag, a[:], b[:])
+}
+
+// merkleRoot computes the root of the merkle tree of the given records as
+// defined by BOLT 12. Each record is a leaf that is paired with a nonce leaf
+// derived from the first record, so that the tree doesn't reveal the contents
+// of records that are omitted. Signature records are excluded.
+func merkleRoot(r records) chainhash.Hash {
+	if len(r) == 0 {
+		return chainhash.Hash{}
+	}
+
+	// The nonce tag commits to the first record of the stream.
+	first := records{r[0]}.encode()
+	tag := append(append([]byte{}, nonceTag...), first...)
+
+	var (
+		leaves []*chainhash.Hash
+		buf    [8]byte
+	)
+	for _, rec := range r {
+		if rec.typ >= signatureType && rec.typ <= signatureRangeEnd {
+			continue
+		}
+
+		leaf := chainhash.TaggedHash(
+			leafTag, records{rec}.encode(),
+		)
+
+		var typ bytes.Buffer
+		_ = tlv.WriteVarInt(&typ, rec.typ, &buf)
+		nonce := chainhash.TaggedHash(tag, typ.Bytes())
+
+		leaves = append(leaves, branchHash(leaf, nonce))
+	}
+
+	// Combine neighbours until only the root is left. An odd node at the
+	// end of a level is carried up, so the tree is deepest on the left.
+	for len(leaves) > 1 {
+		next := make([]*chainhash.Hash, 0, (len(leaves)+1)/2)
+		for i := 0; i < len(leaves); i += 2 {
+			if i+1 == len(leaves) {
+				next = append(next, leaves[i])
+				continue
+			}
+
+			next = append(next, branchHash(leaves[i], leaves[i+1]))
+		}
+
+		leaves = next
+	}
+
+	return *leaves[0]
+}
+
+// signatureTag returns the tag used for the signature of the given message
+// and field.
+func signatureTag(messageName, fieldName string) []byte {
+	return []byte("lightning" + messageName + fieldName)
+}
+
+// signatureHash returns the hash that the signature of a message commits to.
+func signatureHash(messageName, fieldName string,
+	root chainhash.Hash) *chainhash.Hash {
+
+	return chainhash.TaggedHash(
+		signatureTag(messageName, fieldName), root[:],
+	)
+}
+
+// verifySignature checks a signature of the given message by the key.
+func verifySignature(messageName string, r records, sig []byte,
+	key *btcec.PublicKey) error {
+
+	signature, err := schnorr.ParseSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	hash := signatureHash(messageName, "signature", merkleRoot(r))
+	if !signature.Verify(hash[:], key) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package offers
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMerkleRoot tests the merkle root computation against the test vectors
+// of the BOLT 12 specification.
+func TestMerkleRoot(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tlv1 = "010203e8"
+		tlv2 = "02080000010000020003"
+		tlv3 = "03310266e4598d1d3c415f572a8488830b60f7e744ed9235eb0" +
+			"b1ba93283b315c0351800000000000000010000000000000002"
+	)
+
+	tests := []struct {
+		name   string
+		stream string
+		root   string
+	}{
+		{
+			name:   "one record",
+			stream: tlv1,
+			root: "b013756c8fee86503a0b4abdab4cddeb1af5d344ca6fc2" +
+				"fa8b6c08938caa6f93",
+		},
+		{
+			name:   "two records",
+			stream: tlv1 + tlv2,
+			root: "c3774abbf4815aa54ccaa026bff6581f01f3be5fe814c6" +
+				"20a252534f434bc0d1",
+		},
+		{
+			name:   "three records",
+			stream: tlv1 + tlv2 + tlv3,
+			root: "ab2e79b1283b0b31e0b035258de23782df6b89a38cfa72" +
+				"37bde69aed1a658c5d",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stream, err := hex.DecodeString(test.stream)
+			require.NoError(t, err)
+
+			r, err := decodeRecords(stream)
+			require.NoError(t, err)
+
+			root := merkleRoot(r)
+			require.Equal(t, test.root, hex.EncodeToString(root[:]))
+
+			// Signature records aren't part of the tree.
+			withSig := append(r, record{
+				typ:   signatureType,
+				value: make([]byte, 64),
+			})
+			require.Equal(t, root, merkleRoot(withSig))
+		})
+	}
+}
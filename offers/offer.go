@@ -0,0 +1,332 @@
+package offers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	offerChainsType         = 2
+	offerMetadataType       = 4
+	offerCurrencyType       = 6
+	offerAmountType         = 8
+	offerDescriptionType    = 10
+	offerFeaturesType       = 12
+	offerAbsoluteExpiryType = 14
+	offerPathsType          = 16
+	offerIssuerType         = 18
+	offerQuantityMaxType    = 20
+	offerIssuerIDType       = 22
+
+	// offerRangeStart and offerRangeEnd delimit the types of offer
+	// records, which are mirrored by invoice requests and invoices.
+	offerRangeStart = 1
+	offerRangeEnd   = 79
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of an offers
+	// message doesn't verify.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrOfferExpired is returned when an offer is past its expiry.
+	ErrOfferExpired = errors.New("offer has expired")
+
+	// knownOfferTypes is the set of offer records we understand.
+	knownOfferTypes = map[uint64]struct{}{
+		offerChainsType:         {},
+		offerMetadataType:       {},
+		offerCurrencyType:       {},
+		offerAmountType:         {},
+		offerDescriptionType:    {},
+		offerFeaturesType:       {},
+		offerAbsoluteExpiryType: {},
+		offerPathsType:          {},
+		offerIssuerType:         {},
+		offerQuantityMaxType:    {},
+		offerIssuerIDType:       {},
+	}
+)
+
+// Offer is a BOLT 12 offer, a reusable request for payment that payers use to
+// fetch an invoice from the issuer.
+type Offer struct {
+	// Chains is the list of chains the offer is valid on. An empty list
+	// means bitcoin mainnet.
+	Chains []chainhash.Hash
+
+	// Metadata is opaque data set by the issuer.
+	Metadata []byte
+
+	// Currency is an optional ISO 4217 currency code that Amount is
+	// denominated in. If empty, the amount is in millisatoshis.
+	Currency string
+
+	// Amount is the amount expected per item, or zero if the payer may
+	// choose the amount.
+	Amount uint64
+
+	// Description is a description of the purpose of the payment.
+	Description string
+
+	// Features is the set of features of the offer.
+	Features *lnwire.RawFeatureVector
+
+	// AbsoluteExpiry is the time after which the offer is no longer
+	// valid, or the zero time if it doesn't expire.
+	AbsoluteExpiry time.Time
+
+	// Paths is an optional list of blinded paths to the issuer, which
+	// invoice requests are sent along.
+	Paths []*sphinx.BlindedPath
+
+	// Issuer is an optional description of the issuer.
+	Issuer string
+
+	// QuantityMax is set if the payer may request several items, in which
+	// case it is the maximum number of items, or zero for no maximum.
+	QuantityMax fn.Option[uint64]
+
+	// IssuerID is the public key invoices for the offer are signed with.
+	// It must be set unless Paths is.
+	IssuerID *btcec.PublicKey
+
+	// records holds the serialized records of the offer.
+	records records
+}
+
+// encodeRecords serializes the fields of the offer into records.
+func (o *Offer) encodeRecords() (records, error) {
+	var b recordBuilder
+
+	if len(o.Chains) > 0 {
+		var chains bytes.Buffer
+		for _, chain := range o.Chains {
+			chains.Write(chain[:])
+		}
+		b.add(offerChainsType, chains.Bytes())
+	}
+	if len(o.Metadata) > 0 {
+		b.add(offerMetadataType, o.Metadata)
+	}
+	if o.Currency != "" {
+		b.add(offerCurrencyType, []byte(o.Currency))
+	}
+	if o.Amount != 0 {
+		b.addTU64(offerAmountType, o.Amount)
+	}
+	if o.Description != "" {
+		b.add(offerDescriptionType, []byte(o.Description))
+	}
+	if o.Features != nil && !o.Features.IsEmpty() {
+		b.addFeatures(offerFeaturesType, o.Features)
+	}
+	if !o.AbsoluteExpiry.IsZero() {
+		expiry := uint64(o.AbsoluteExpiry.Unix())
+		b.addTU64(offerAbsoluteExpiryType, expiry)
+	}
+	if len(o.Paths) > 0 {
+		paths, err := encodePaths(o.Paths)
+		if err != nil {
+			return nil, err
+		}
+		b.add(offerPathsType, paths)
+	}
+	if o.Issuer != "" {
+		b.add(offerIssuerType, []byte(o.Issuer))
+	}
+	o.QuantityMax.WhenSome(func(quantityMax uint64) {
+		b.addTU64(offerQuantityMaxType, quantityMax)
+	})
+	if o.IssuerID != nil {
+		b.addPubKey(offerIssuerIDType, o.IssuerID)
+	}
+
+	return b.build(), nil
+}
+
+// validate checks that the offer is well formed.
+func (o *Offer) validate() error {
+	if o.Currency != "" && o.Amount == 0 {
+		return fmt.Errorf("offer has currency without amount")
+	}
+	if o.Amount != 0 && o.Description == "" {
+		return fmt.Errorf("offer has amount without description")
+	}
+	if o.IssuerID == nil && len(o.Paths) == 0 {
+		return fmt.Errorf("offer has neither issuer ID nor paths")
+	}
+
+	return nil
+}
+
+// Finalize validates the offer and serializes its records. It must be called
+// once all fields are set, before the offer is encoded.
+func (o *Offer) Finalize() error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	r, err := o.encodeRecords()
+	if err != nil {
+		return err
+	}
+	o.records = r
+
+	return nil
+}
+
+// ID returns the ID of the offer, which is the merkle root of its records.
+func (o *Offer) ID() chainhash.Hash {
+	return merkleRoot(o.records)
+}
+
+// Bytes returns the serialized offer.
+func (o *Offer) Bytes() []byte {
+	return o.records.encode()
+}
+
+// Encode returns the bech32 encoding of the offer.
+func (o *Offer) Encode() (string, error) {
+	return encodeBech32(OfferHRP, o.Bytes())
+}
+
+// IsExpired returns whether the offer is past its expiry at the given time.
+func (o *Offer) IsExpired(now time.Time) bool {
+	return !o.AbsoluteExpiry.IsZero() && now.After(o.AbsoluteExpiry)
+}
+
+// SupportsChain returns whether the offer is valid on the given chain.
+func (o *Offer) SupportsChain(chain chainhash.Hash,
+	mainnet chainhash.Hash) bool {
+
+	if len(o.Chains) == 0 {
+		return chain == mainnet
+	}
+
+	for _, c := range o.Chains {
+		if c == chain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DecodeOffer parses a bech32 encoded offer.
+func DecodeOffer(s string) (*Offer, error) {
+	b, err := decodeBech32(OfferHRP, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseOffer(b)
+}
+
+// ParseOffer parses a serialized offer.
+func ParseOffer(b []byte) (*Offer, error) {
+	r, err := decodeRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range r {
+		if rec.typ < offerRangeStart || rec.typ > offerRangeEnd {
+			return nil, fmt.Errorf("offer has record of type %d "+
+				"outside of the offer range", rec.typ)
+		}
+	}
+
+	offer, err := parseOfferRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := offer.validate(); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// parseOfferRecords parses the offer records of an offer, invoice request or
+// invoice.
+func parseOfferRecords(r records) (*Offer, error) {
+	if err := r.checkUnknown(knownOfferTypes); err != nil {
+		return nil, err
+	}
+
+	offer := &Offer{
+		records: r,
+	}
+
+	var err error
+	for _, rec := range r {
+		switch rec.typ {
+		case offerChainsType:
+			if len(rec.value) == 0 ||
+				len(rec.value)%chainhash.HashSize != 0 {
+
+				return nil, fmt.Errorf("invalid offer chains")
+			}
+
+			const hashSize = chainhash.HashSize
+			for i := 0; i < len(rec.value); i += hashSize {
+				var chain chainhash.Hash
+				copy(chain[:], rec.value[i:])
+				offer.Chains = append(offer.Chains, chain)
+			}
+
+		case offerMetadataType:
+			offer.Metadata = rec.value
+
+		case offerCurrencyType:
+			offer.Currency, err = decodeUTF8(rec.value)
+
+		case offerAmountType:
+			offer.Amount, err = decodeTU64(rec.value)
+			if err == nil && offer.Amount == 0 {
+				err = fmt.Errorf("offer amount is zero")
+			}
+
+		case offerDescriptionType:
+			offer.Description, err = decodeUTF8(rec.value)
+
+		case offerFeaturesType:
+			offer.Features, err = decodeFeatures(rec.value)
+
+		case offerAbsoluteExpiryType:
+			var expiry uint64
+			expiry, err = decodeTU64(rec.value)
+			offer.AbsoluteExpiry = time.Unix(int64(expiry), 0)
+
+		case offerPathsType:
+			offer.Paths, err = decodePaths(rec.value)
+
+		case offerIssuerType:
+			offer.Issuer, err = decodeUTF8(rec.value)
+
+		case offerQuantityMaxType:
+			var quantityMax uint64
+			quantityMax, err = decodeTU64(rec.value)
+			offer.QuantityMax = fn.Some(quantityMax)
+
+		case offerIssuerIDType:
+			offer.IssuerID, err = btcec.ParsePubKey(rec.value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid offer record %d: %w",
+				rec.typ, err)
+		}
+	}
+
+	return offer, nil
+}
@@ -0,0 +1,271 @@
+package offers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPath creates a blinded path to the given node through a random
+// introduction node.
+func newTestPath(t *testing.T, node *btcec.PublicKey) *sphinx.BlindedPath {
+	t.Helper()
+
+	intro, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	path, err := blindedpath.BuildOnionMessagePath(
+		&blindedpath.OnionMessagePathCfg{
+			Hops: []route.Vertex{
+				route.NewVertex(intro.PubKey()),
+				route.NewVertex(node),
+			},
+			PathID: []byte{1, 2, 3},
+		},
+	)
+	require.NoError(t, err)
+
+	return path
+}
+
+// keySigner returns a MessageSigner that signs with the given key.
+func keySigner(key *btcec.PrivateKey) MessageSigner {
+	return func(tag, msg []byte) (*schnorr.Signature, error) {
+		hash := chainhash.TaggedHash(tag, msg)
+		return schnorr.Sign(key, hash[:])
+	}
+}
+
+// newTestOffer creates an offer with all fields set.
+func newTestOffer(t *testing.T, issuer *btcec.PublicKey) *Offer {
+	t.Helper()
+
+	features := lnwire.NewRawFeatureVector()
+	features.Set(lnwire.FeatureBit(101))
+
+	offer := &Offer{
+		Chains: []chainhash.Hash{
+			*chaincfg.TestNet3Params.GenesisHash,
+		},
+		Metadata:       []byte{7, 7, 7},
+		Amount:         1000,
+		Description:    "coffee",
+		Features:       features,
+		AbsoluteExpiry: time.Unix(2000000000, 0),
+		Paths:          []*sphinx.BlindedPath{newTestPath(t, issuer)},
+		Issuer:         "cafe",
+		QuantityMax:    fn.Some[uint64](5),
+		IssuerID:       issuer,
+	}
+	require.NoError(t, offer.Finalize())
+
+	return offer
+}
+
+// TestOfferEncoding tests that offers survive an encoding round trip.
+func TestOfferEncoding(t *testing.T) {
+	t.Parallel()
+
+	issuer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	offer := newTestOffer(t, issuer.PubKey())
+
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+
+	decoded, err := DecodeOffer(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer.ID(), decoded.ID())
+	require.Equal(t, offer.Chains, decoded.Chains)
+	require.Equal(t, offer.Metadata, decoded.Metadata)
+	require.Equal(t, offer.Amount, decoded.Amount)
+	require.Equal(t, offer.Description, decoded.Description)
+	require.Equal(t, offer.Features, decoded.Features)
+	require.True(t, offer.AbsoluteExpiry.Equal(decoded.AbsoluteExpiry))
+	require.Equal(t, offer.Issuer, decoded.Issuer)
+	require.Equal(t, offer.QuantityMax, decoded.QuantityMax)
+	require.True(t, offer.IssuerID.IsEqual(decoded.IssuerID))
+	require.Len(t, decoded.Paths, 1)
+	require.True(t, offer.Paths[0].IntroductionPoint.IsEqual(
+		decoded.Paths[0].IntroductionPoint,
+	))
+
+	// An offer needs an issuer ID or paths.
+	_, err = ParseOffer(nil)
+	require.ErrorContains(t, err, "neither issuer ID nor paths")
+
+	// Unknown even records are rejected, unknown odd ones are ignored.
+	b := recordBuilder{records: append(records{}, offer.records...)}
+	b.add(25, []byte{1})
+	_, err = ParseOffer(b.build().encode())
+	require.NoError(t, err)
+
+	b.add(24, []byte{1})
+	_, err = ParseOffer(b.build().encode())
+	require.ErrorIs(t, err, ErrUnknownEvenType)
+
+	// Records outside of the offer range are rejected.
+	b = recordBuilder{records: append(records{}, offer.records...)}
+	b.add(81, []byte{1})
+	_, err = ParseOffer(b.build().encode())
+	require.ErrorContains(t, err, "outside of the offer range")
+}
+
+// TestInvoiceRequestEncoding tests that invoice requests survive an encoding
+// round trip, and that their signature is verified.
+func TestInvoiceRequestEncoding(t *testing.T) {
+	t.Parallel()
+
+	issuer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	payer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	offer := newTestOffer(t, issuer.PubKey())
+
+	req := &InvoiceRequest{
+		Offer:     offer,
+		Metadata:  []byte{1, 2, 3, 4},
+		Chain:     fn.Some(*chaincfg.TestNet3Params.GenesisHash),
+		Amount:    4000,
+		Quantity:  3,
+		PayerNote: "thanks",
+	}
+	require.NoError(t, req.Sign(payer))
+
+	encoded, err := req.Encode()
+	require.NoError(t, err)
+
+	decoded, err := DecodeInvoiceRequest(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer.ID(), decoded.Offer.ID())
+	require.Equal(t, req.Metadata, decoded.Metadata)
+	require.Equal(t, req.Chain, decoded.Chain)
+	require.Equal(t, req.Amount, decoded.Amount)
+	require.Equal(t, req.Quantity, decoded.Quantity)
+	require.Equal(t, req.PayerNote, decoded.PayerNote)
+	require.True(t, payer.PubKey().IsEqual(decoded.PayerID))
+
+	// The payer may pay more than the offer asks for, but not less.
+	amt, err := decoded.InvoiceAmount()
+	require.NoError(t, err)
+	require.Equal(t, lnwire.MilliSatoshi(4000), amt)
+
+	decoded.Amount = 2000
+	_, err = decoded.InvoiceAmount()
+	require.ErrorContains(t, err, "less than the expected")
+
+	// A tampered invoice request doesn't verify.
+	tampered := append(records{}, req.records...)
+	for i, rec := range tampered {
+		if rec.typ == invReqPayerNoteType {
+			tampered[i] = record{typ: rec.typ, value: []byte("no")}
+		}
+	}
+	_, err = ParseInvoiceRequest(tampered.encode())
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// The quantity must not exceed the maximum of the offer.
+	req.Quantity = 6
+	require.ErrorContains(t, req.Sign(payer), "exceeds maximum")
+}
+
+// TestInvoiceEncoding tests that invoices survive an encoding round trip, and
+// that they are verified against the invoice request they respond to.
+func TestInvoiceEncoding(t *testing.T) {
+	t.Parallel()
+
+	issuer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	payer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	req := &InvoiceRequest{
+		Offer:    newTestOffer(t, issuer.PubKey()),
+		Metadata: []byte{1},
+		Quantity: 2,
+	}
+	require.NoError(t, req.Sign(payer))
+
+	features := lnwire.NewRawFeatureVector(lnwire.AMPOptional)
+
+	invoice := &Invoice{
+		InvoiceRequest: req,
+		Paths: []*sphinx.BlindedPath{
+			newTestPath(t, issuer.PubKey()),
+		},
+		PayInfo: []*BlindedPayInfo{{
+			FeeBaseMsat:               1,
+			FeeProportionalMillionths: 2,
+			CltvExpiryDelta:           3,
+			HtlcMinimumMsat:           4,
+			HtlcMaximumMsat:           5,
+			Features:                  features,
+		}},
+		CreatedAt:      time.Unix(1700000000, 0),
+		RelativeExpiry: time.Hour,
+		PaymentHash:    lntypes.Hash{1, 2, 3},
+		Amount:         2000,
+		Fallbacks: []FallbackAddress{{
+			Version: 1,
+			Program: make([]byte, 32),
+		}},
+		NodeID: issuer.PubKey(),
+	}
+	require.NoError(t, invoice.Sign(keySigner(issuer)))
+
+	encoded, err := invoice.Encode()
+	require.NoError(t, err)
+
+	decoded, err := DecodeInvoice(encoded)
+	require.NoError(t, err)
+	require.NoError(t, decoded.VerifyForRequest(req))
+	require.Equal(t, invoice.PayInfo, decoded.PayInfo)
+	require.True(t, invoice.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, invoice.RelativeExpiry, decoded.RelativeExpiry)
+	require.Equal(t, invoice.PaymentHash, decoded.PaymentHash)
+	require.Equal(t, invoice.Amount, decoded.Amount)
+	require.Equal(t, invoice.Fallbacks, decoded.Fallbacks)
+	require.Equal(
+		t, invoice.CreatedAt.Add(time.Hour), decoded.ExpiresAt(),
+	)
+
+	// An invoice signed by another key doesn't verify against its node
+	// ID.
+	other, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.NoError(t, invoice.Sign(keySigner(other)))
+	_, err = ParseInvoice(invoice.Bytes())
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// An invoice for a different amount or signed by a node other than
+	// the issuer is rejected by the payer.
+	invoice.Amount = 1000
+	require.NoError(t, invoice.Sign(keySigner(issuer)))
+	decoded, err = ParseInvoice(invoice.Bytes())
+	require.NoError(t, err)
+	require.ErrorContains(
+		t, decoded.VerifyForRequest(req), "doesn't match expected",
+	)
+
+	invoice.Amount = 2000
+	invoice.NodeID = other.PubKey()
+	require.NoError(t, invoice.Sign(keySigner(other)))
+	decoded, err = ParseInvoice(invoice.Bytes())
+	require.NoError(t, err)
+	require.ErrorContains(
+		t, decoded.VerifyForRequest(req), "issuer ID",
+	)
+}
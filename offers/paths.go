@@ -0,0 +1,156 @@
+package offers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionmessage"
+)
+
+// encodePaths serializes a list of blinded paths.
+func encodePaths(paths []*sphinx.BlindedPath) ([]byte, error) {
+	var b bytes.Buffer
+	for _, path := range paths {
+		if err := onionmessage.EncodeBlindedPath(&b, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodePaths parses a non-empty list of blinded paths.
+func decodePaths(value []byte) ([]*sphinx.BlindedPath, error) {
+	var (
+		r     = bytes.NewReader(value)
+		paths []*sphinx.BlindedPath
+	)
+	for r.Len() > 0 {
+		path, err := onionmessage.DecodeBlindedPath(r)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("empty list of blinded paths")
+	}
+
+	return paths, nil
+}
+
+// BlindedPayInfo holds the fees and limits of a blinded payment path of an
+// invoice.
+type BlindedPayInfo struct {
+	// FeeBaseMsat is the base fee charged for forwarding along the path.
+	FeeBaseMsat uint32
+
+	// FeeProportionalMillionths is the proportional fee charged for
+	// forwarding along the path.
+	FeeProportionalMillionths uint32
+
+	// CltvExpiryDelta is the total CLTV delta of the path.
+	CltvExpiryDelta uint16
+
+	// HtlcMinimumMsat is the minimum amount that can be sent along the
+	// path.
+	HtlcMinimumMsat lnwire.MilliSatoshi
+
+	// HtlcMaximumMsat is the maximum amount that can be sent along the
+	// path.
+	HtlcMaximumMsat lnwire.MilliSatoshi
+
+	// Features is the set of features required by the path.
+	Features *lnwire.RawFeatureVector
+}
+
+// encode serializes the pay info.
+func (p *BlindedPayInfo) encode(w io.Writer) error {
+	var features bytes.Buffer
+	if p.Features != nil {
+		if err := p.Features.EncodeBase256(&features); err != nil {
+			return err
+		}
+	}
+
+	var b [4 + 4 + 2 + 8 + 8 + 2]byte
+	binary.BigEndian.PutUint32(b[0:4], p.FeeBaseMsat)
+	binary.BigEndian.PutUint32(b[4:8], p.FeeProportionalMillionths)
+	binary.BigEndian.PutUint16(b[8:10], p.CltvExpiryDelta)
+	binary.BigEndian.PutUint64(b[10:18], uint64(p.HtlcMinimumMsat))
+	binary.BigEndian.PutUint64(b[18:26], uint64(p.HtlcMaximumMsat))
+	binary.BigEndian.PutUint16(b[26:28], uint16(features.Len()))
+
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(features.Bytes())
+
+	return err
+}
+
+// decodePayInfo parses a single pay info.
+func decodePayInfo(r io.Reader) (*BlindedPayInfo, error) {
+	var b [4 + 4 + 2 + 8 + 8 + 2]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+
+	info := &BlindedPayInfo{
+		FeeBaseMsat:               binary.BigEndian.Uint32(b[0:4]),
+		FeeProportionalMillionths: binary.BigEndian.Uint32(b[4:8]),
+		CltvExpiryDelta:           binary.BigEndian.Uint16(b[8:10]),
+		HtlcMinimumMsat: lnwire.MilliSatoshi(
+			binary.BigEndian.Uint64(b[10:18]),
+		),
+		HtlcMaximumMsat: lnwire.MilliSatoshi(
+			binary.BigEndian.Uint64(b[18:26]),
+		),
+		Features: lnwire.NewRawFeatureVector(),
+	}
+
+	featuresLen := int(binary.BigEndian.Uint16(b[26:28]))
+	err := info.Features.DecodeBase256(r, featuresLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// encodePayInfos serializes a list of pay infos.
+func encodePayInfos(infos []*BlindedPayInfo) ([]byte, error) {
+	var b bytes.Buffer
+	for _, info := range infos {
+		if err := info.encode(&b); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodePayInfos parses a list of pay infos.
+func decodePayInfos(value []byte) ([]*BlindedPayInfo, error) {
+	var (
+		r     = bytes.NewReader(value)
+		infos []*BlindedPayInfo
+	)
+	for r.Len() > 0 {
+		info, err := decodePayInfo(r)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,235 @@
+package offers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrUnknownEvenType is returned when a message holds a record of an
+	// even type we don't understand.
+	ErrUnknownEvenType = errors.New("unknown even type")
+
+	// ErrInvalidUTF8 is returned when a string field isn't valid UTF-8.
+	ErrInvalidUTF8 = errors.New("string is not valid utf-8")
+)
+
+// record is a single TLV record of an offers message in its serialized form.
+// Offers messages are signed over their serialized records, and invoice
+// requests and invoices mirror the records of the message they respond to,
+// so the records are kept as they were received.
+type record struct {
+	typ   uint64
+	value []byte
+}
+
+// records is a set of TLV records sorted by type.
+type records []record
+
+// decodeRecords parses a TLV stream into its records. The stream must be
+// canonical, i.e. sorted by type without duplicates.
+func decodeRecords(b []byte) (records, error) {
+	var (
+		r       = bytes.NewReader(b)
+		buf     [8]byte
+		decoded records
+	)
+	for r.Len() > 0 {
+		typ, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(decoded) > 0 && typ <= decoded[len(decoded)-1].typ {
+			return nil, tlv.ErrStreamNotCanonical
+		}
+
+		length, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(r.Len()) {
+			return nil, fmt.Errorf("record %d length %d exceeds "+
+				"remaining %d bytes", typ, length, r.Len())
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+
+		decoded = append(decoded, record{typ: typ, value: value})
+	}
+
+	return decoded, nil
+}
+
+// encode serializes the records as a TLV stream.
+func (r records) encode() []byte {
+	var (
+		b   bytes.Buffer
+		buf [8]byte
+	)
+	for _, rec := range r {
+		// Writing to a bytes.Buffer can't fail.
+		_ = tlv.WriteVarInt(&b, rec.typ, &buf)
+		_ = tlv.WriteVarInt(&b, uint64(len(rec.value)), &buf)
+		b.Write(rec.value)
+	}
+
+	return b.Bytes()
+}
+
+// get returns the value of the record with the given type.
+func (r records) get(typ uint64) ([]byte, bool) {
+	for _, rec := range r {
+		if rec.typ == typ {
+			return rec.value, true
+		}
+	}
+
+	return nil, false
+}
+
+// inRange returns the records with types in [start, end].
+func (r records) inRange(start, end uint64) records {
+	var filtered records
+	for _, rec := range r {
+		if rec.typ >= start && rec.typ <= end {
+			filtered = append(filtered, rec)
+		}
+	}
+
+	return filtered
+}
+
+// checkUnknown returns an error if any of the records has an even type that
+// isn't in the set of known types.
+func (r records) checkUnknown(known map[uint64]struct{}) error {
+	for _, rec := range r {
+		if _, ok := known[rec.typ]; ok {
+			continue
+		}
+
+		if rec.typ%2 == 0 {
+			return fmt.Errorf("%w: %d", ErrUnknownEvenType, rec.typ)
+		}
+	}
+
+	return nil
+}
+
+// recordBuilder assembles a set of records.
+type recordBuilder struct {
+	records records
+}
+
+// add adds a record with the given value.
+func (b *recordBuilder) add(typ uint64, value []byte) {
+	b.records = append(b.records, record{typ: typ, value: value})
+}
+
+// addTU64 adds a record holding a truncated uint64.
+func (b *recordBuilder) addTU64(typ uint64, value uint64) {
+	var (
+		w   bytes.Buffer
+		buf [8]byte
+	)
+	_ = tlv.ETUint64T(&w, value, &buf)
+
+	b.add(typ, w.Bytes())
+}
+
+// addTU32 adds a record holding a truncated uint32.
+func (b *recordBuilder) addTU32(typ uint64, value uint32) {
+	var (
+		w   bytes.Buffer
+		buf [8]byte
+	)
+	_ = tlv.ETUint32T(&w, value, &buf)
+
+	b.add(typ, w.Bytes())
+}
+
+// addPubKey adds a record holding a compressed public key.
+func (b *recordBuilder) addPubKey(typ uint64, key *btcec.PublicKey) {
+	b.add(typ, key.SerializeCompressed())
+}
+
+// addFeatures adds a record holding a feature vector.
+func (b *recordBuilder) addFeatures(typ uint64,
+	features *lnwire.RawFeatureVector) {
+
+	var w bytes.Buffer
+	_ = features.EncodeBase256(&w)
+
+	b.add(typ, w.Bytes())
+}
+
+// addRecords adds the given records, which are kept as they are.
+func (b *recordBuilder) addRecords(r records) {
+	b.records = append(b.records, r...)
+}
+
+// build returns the records sorted by type.
+func (b *recordBuilder) build() records {
+	sort.Slice(b.records, func(i, j int) bool {
+		return b.records[i].typ < b.records[j].typ
+	})
+
+	return b.records
+}
+
+// decodeTU64 parses a truncated uint64.
+func decodeTU64(value []byte) (uint64, error) {
+	var (
+		v   uint64
+		buf [8]byte
+	)
+	err := tlv.DTUint64(
+		bytes.NewReader(value), &v, &buf, uint64(len(value)),
+	)
+
+	return v, err
+}
+
+// decodeTU32 parses a truncated uint32.
+func decodeTU32(value []byte) (uint32, error) {
+	var (
+		v   uint32
+		buf [8]byte
+	)
+	err := tlv.DTUint32(
+		bytes.NewReader(value), &v, &buf, uint64(len(value)),
+	)
+
+	return v, err
+}
+
+// decodeUTF8 parses a UTF-8 string.
+func decodeUTF8(value []byte) (string, error) {
+	if !utf8.Valid(value) {
+		return "", ErrInvalidUTF8
+	}
+
+	return string(value), nil
+}
+
+// decodeFeatures parses a feature vector.
+func decodeFeatures(value []byte) (*lnwire.RawFeatureVector, error) {
+	features := lnwire.NewRawFeatureVector()
+	err := features.DecodeBase256(bytes.NewReader(value), len(value))
+	if err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
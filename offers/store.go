@@ -0,0 +1,135 @@
+package offers
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// offersBucketKey is the key of the bucket that holds the offers we
+	// issued.
+	//
+	// maps: offerID -> serialized offer
+	offersBucketKey = []byte("offers")
+
+	errNoOffersBucket = errors.New("offers bucket does not exist")
+
+	// ErrOfferNotFound is returned when an offer isn't in the store.
+	ErrOfferNotFound = errors.New("offer not found")
+
+	// ErrDuplicateOffer is returned when adding an offer that is already
+	// in the store.
+	ErrDuplicateOffer = errors.New("offer already exists")
+)
+
+// Store stores the offers we issued.
+type Store interface {
+	// AddOffer adds an offer to the store. Returns ErrDuplicateOffer if an
+	// offer with the same ID already exists.
+	AddOffer(offer *Offer) error
+
+	// FetchOffer returns the offer with the given ID. Returns
+	// ErrOfferNotFound if it cannot be found.
+	FetchOffer(id chainhash.Hash) (*Offer, error)
+
+	// ListOffers returns all offers in the store.
+	ListOffers() ([]*Offer, error)
+}
+
+type offerStore struct {
+	db kvdb.Backend
+}
+
+// NewStore returns a new offer store backed by the given database.
+func NewStore(db kvdb.Backend) (Store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(offersBucketKey)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &offerStore{
+		db: db,
+	}, nil
+}
+
+// AddOffer adds an offer to the store.
+func (s *offerStore) AddOffer(offer *Offer) error {
+	id := offer.ID()
+
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(offersBucketKey)
+		if bucket == nil {
+			return errNoOffersBucket
+		}
+
+		if bucket.Get(id[:]) != nil {
+			return ErrDuplicateOffer
+		}
+
+		return bucket.Put(id[:], offer.Bytes())
+	}, func() {})
+}
+
+// FetchOffer returns the offer with the given ID.
+func (s *offerStore) FetchOffer(id chainhash.Hash) (*Offer, error) {
+	var offer *Offer
+
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(offersBucketKey)
+		if bucket == nil {
+			return errNoOffersBucket
+		}
+
+		offerBytes := bucket.Get(id[:])
+		if offerBytes == nil {
+			return ErrOfferNotFound
+		}
+
+		var err error
+		offer, err = ParseOffer(offerBytes)
+
+		return err
+	}, func() {
+		offer = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// ListOffers returns all offers in the store.
+func (s *offerStore) ListOffers() ([]*Offer, error) {
+	var offers []*Offer
+
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(offersBucketKey)
+		if bucket == nil {
+			return errNoOffersBucket
+		}
+
+		return bucket.ForEach(func(_, offerBytes []byte) error {
+			offer, err := ParseOffer(offerBytes)
+			if err != nil {
+				return err
+			}
+
+			offers = append(offers, offer)
+
+			return nil
+		})
+	}, func() {
+		offers = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
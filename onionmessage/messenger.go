@@ -0,0 +1,402 @@
+package onionmessage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// OnionPacketSize is the size of the onion packet carried by an onion
+	// message: a version byte, the ephemeral key, the routing info and
+	// the HMAC.
+	OnionPacketSize = 1 + btcec.PubKeyBytesLenCompressed + 1300 + 32
+)
+
+var (
+	// ErrNoNextNode is returned when an onion message that isn't meant
+	// for us doesn't tell us where to forward it.
+	ErrNoNextNode = errors.New("onion message has no next node")
+
+	// ErrSendToSelf is returned when the first hop of an onion message we
+	// send is our own node.
+	ErrSendToSelf = errors.New("can't send onion message to ourselves")
+)
+
+// Config holds the dependencies of the Messenger.
+type Config struct {
+	// NodeKey is our node's key, which is used to decrypt our layer of
+	// incoming onion messages.
+	NodeKey sphinx.SingleKeyECDH
+
+	// SendToPeer sends an onion message to a connected peer.
+	SendToPeer func(peer route.Vertex, msg *lnwire.OnionMessage) error
+}
+
+// ReceivedMessage is an onion message that we are the final recipient of. It
+// is sent to all subscribers of the Messenger.
+type ReceivedMessage struct {
+	// Peer is the node that forwarded the message to us. This is not the
+	// sender of the message, which remains unknown to us.
+	Peer route.Vertex
+
+	// PathID is the path ID we included in the blinded path the message
+	// was sent along, if any.
+	PathID []byte
+
+	// ReplyPath is an optional blinded path that the sender provided for
+	// replies to the message.
+	ReplyPath *sphinx.BlindedPath
+
+	// CustomRecords holds the contents of the message.
+	CustomRecords map[uint64][]byte
+}
+
+// SendRequest describes an onion message to send.
+type SendRequest struct {
+	// Destination is the node ID of the recipient. Either it or
+	// BlindedPath must be set.
+	Destination *route.Vertex
+
+	// BlindedPath is a blinded path to the recipient. Either it or
+	// Destination must be set.
+	BlindedPath *sphinx.BlindedPath
+
+	// Hops is an optional list of nodes the message is sent through
+	// before it reaches the recipient or the introduction node of the
+	// blinded path. The first of these nodes, or the recipient or
+	// introduction node if there are none, must be a connected peer.
+	Hops []route.Vertex
+
+	// ReplyPath is an optional blinded path the recipient can use to
+	// reply to the message.
+	ReplyPath *sphinx.BlindedPath
+
+	// CustomRecords holds the contents of the message. The types of the
+	// records must be at least MinCustomRecordType.
+	CustomRecords map[uint64][]byte
+}
+
+// Messenger relays onion messages for our peers, and allows sending and
+// receiving onion messages.
+type Messenger struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	ourNode route.Vertex
+	router  *sphinx.Router
+
+	ntfnServer *subscribe.Server
+}
+
+// NewMessenger creates a new onion messenger.
+func NewMessenger(cfg *Config) *Messenger {
+	// Onion messages aren't protected against replays, so the router is
+	// created without a replay log.
+	return &Messenger{
+		cfg:        cfg,
+		ourNode:    route.NewVertex(cfg.NodeKey.PubKey()),
+		router:     sphinx.NewRouter(cfg.NodeKey, nil),
+		ntfnServer: subscribe.NewServer(),
+	}
+}
+
+// Start starts the messenger's subscription server.
+func (m *Messenger) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Onion messenger starting")
+		err = m.ntfnServer.Start()
+	})
+
+	return err
+}
+
+// Stop stops the messenger.
+func (m *Messenger) Stop() error {
+	var err error
+	m.stopped.Do(func() {
+		log.Info("Onion messenger shutting down...")
+		defer log.Debug("Onion messenger shutdown complete")
+
+		err = m.ntfnServer.Stop()
+	})
+
+	return err
+}
+
+// SubscribeMessages returns a client that receives a ReceivedMessage for each
+// onion message we are the final recipient of.
+func (m *Messenger) SubscribeMessages() (*subscribe.Client, error) {
+	return m.ntfnServer.Subscribe()
+}
+
+// HandleMessage processes an onion message received from the given peer. The
+// message is either forwarded to the next node on its path, or delivered to
+// our subscribers if we are its final recipient.
+func (m *Messenger) HandleMessage(peer route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	if len(msg.OnionBlob) != OnionPacketSize {
+		return fmt.Errorf("invalid onion message size: %d",
+			len(msg.OnionBlob))
+	}
+
+	var pkt sphinx.OnionPacket
+	if err := pkt.Decode(bytes.NewReader(msg.OnionBlob)); err != nil {
+		return fmt.Errorf("unable to decode onion: %w", err)
+	}
+
+	pathKey := msg.PathKey
+
+	// A path may pass through our node several times in a row, in which
+	// case we keep peeling layers of the onion. Each pass removes a hop,
+	// so this is bounded by the number of hops an onion can hold.
+	for i := 0; i < sphinx.NumMaxHops; i++ {
+		processed, err := m.router.ReconstructOnionPacket(
+			&pkt, nil, sphinx.WithBlindingPoint(pathKey),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to process onion: %w", err)
+		}
+
+		payload, err := DecodePayload(
+			bytes.NewReader(processed.Payload.Payload),
+		)
+		if err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		plainText, err := m.router.DecryptBlindedHopData(
+			pathKey, payload.EncryptedData,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt data: %w", err)
+		}
+
+		data, err := record.DecodeBlindedRouteData(
+			bytes.NewReader(plainText),
+		)
+		if err != nil {
+			return fmt.Errorf("invalid encrypted data: %w", err)
+		}
+
+		if processed.Action == sphinx.ExitNode {
+			return m.deliver(peer, payload, data)
+		}
+
+		var nextNodeKey *btcec.PublicKey
+		data.NextNodeID.WhenSome(
+			func(r tlv.RecordT[tlv.TlvType4, *btcec.PublicKey]) {
+				nextNodeKey = r.Val
+			},
+		)
+		if nextNodeKey == nil {
+			return ErrNoNextNode
+		}
+		nextNode := route.NewVertex(nextNodeKey)
+
+		// The path key of the next hop is either derived from ours, or
+		// replaced if the message is joining another blinded path.
+		nextPathKey, err := m.router.NextEphemeral(pathKey)
+		if err != nil {
+			return err
+		}
+		data.NextBlindingOverride.WhenSome(
+			func(r tlv.RecordT[tlv.TlvType8, *btcec.PublicKey]) {
+				nextPathKey = r.Val
+			},
+		)
+
+		if nextNode != m.ourNode {
+			return m.forward(nextNode, nextPathKey, processed)
+		}
+
+		pkt = *processed.NextPacket
+		pathKey = nextPathKey
+	}
+
+	return fmt.Errorf("onion message passes through our node more than "+
+		"%d times", sphinx.NumMaxHops)
+}
+
+// forward sends the next onion packet on to the given node.
+func (m *Messenger) forward(nextNode route.Vertex, pathKey *btcec.PublicKey,
+	processed *sphinx.ProcessedPacket) error {
+
+	var b bytes.Buffer
+	if err := processed.NextPacket.Encode(&b); err != nil {
+		return err
+	}
+
+	log.Tracef("Forwarding onion message to %v", nextNode)
+
+	return m.cfg.SendToPeer(
+		nextNode, lnwire.NewOnionMessage(pathKey, b.Bytes()),
+	)
+}
+
+// deliver notifies our subscribers of an onion message we are the final
+// recipient of.
+func (m *Messenger) deliver(peer route.Vertex, payload *Payload,
+	data *record.BlindedRouteData) error {
+
+	if data.NextNodeID.IsSome() {
+		return fmt.Errorf("final onion message hop has next node")
+	}
+
+	received := &ReceivedMessage{
+		Peer:          peer,
+		ReplyPath:     payload.ReplyPath,
+		CustomRecords: payload.CustomRecords,
+	}
+	data.PathID.WhenSome(func(r tlv.RecordT[tlv.TlvType6, []byte]) {
+		received.PathID = r.Val
+	})
+
+	log.Debugf("Received onion message via %v with %d records", peer,
+		len(received.CustomRecords))
+
+	return m.ntfnServer.SendUpdate(received)
+}
+
+// Send sends an onion message.
+func (m *Messenger) Send(req *SendRequest) error {
+	var (
+		firstHop    route.Vertex
+		pathKey     *btcec.PublicKey
+		blindedHops []*sphinx.BlindedHopInfo
+	)
+	switch {
+	// The introduction node of the recipient's blinded path is our peer,
+	// so we send the message along the path as is.
+	case req.BlindedPath != nil && len(req.Hops) == 0:
+		firstHop = route.NewVertex(req.BlindedPath.IntroductionPoint)
+		pathKey = req.BlindedPath.BlindingPoint
+		blindedHops = req.BlindedPath.BlindedHops
+
+	// Otherwise we build a path to the introduction node, and have the
+	// final hop of it switch over to the recipient's path.
+	case req.BlindedPath != nil:
+		path, err := blindedpath.BuildOnionMessagePath(
+			&blindedpath.OnionMessagePathCfg{
+				Hops: req.Hops,
+				Next: req.BlindedPath,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		firstHop = req.Hops[0]
+		pathKey = path.BlindingPoint
+		blindedHops = append(
+			path.BlindedHops, req.BlindedPath.BlindedHops...,
+		)
+
+	case req.Destination != nil:
+		hops := append(
+			append([]route.Vertex{}, req.Hops...), *req.Destination,
+		)
+		path, err := blindedpath.BuildOnionMessagePath(
+			&blindedpath.OnionMessagePathCfg{
+				Hops: hops,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		firstHop = hops[0]
+		pathKey = path.BlindingPoint
+		blindedHops = path.BlindedHops
+
+	default:
+		return fmt.Errorf("destination or blinded path required")
+	}
+
+	if firstHop == m.ourNode {
+		return ErrSendToSelf
+	}
+
+	onion, err := buildOnion(blindedHops, req.ReplyPath, req.CustomRecords)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Sending onion message with %d hops via %v",
+		len(blindedHops), firstHop)
+
+	return m.cfg.SendToPeer(
+		firstHop, lnwire.NewOnionMessage(pathKey, onion),
+	)
+}
+
+// buildOnion creates the serialized onion packet of a message sent along the
+// given blinded hops. The reply path and custom records are included in the
+// payload of the final hop.
+func buildOnion(hops []*sphinx.BlindedHopInfo, replyPath *sphinx.BlindedPath,
+	customRecords map[uint64][]byte) ([]byte, error) {
+
+	if len(hops) > sphinx.NumMaxHops {
+		return nil, fmt.Errorf("onion message path has %d hops, "+
+			"maximum is %d", len(hops), sphinx.NumMaxHops)
+	}
+
+	var path sphinx.PaymentPath
+	for i, hop := range hops {
+		payload := &Payload{
+			EncryptedData: hop.CipherText,
+		}
+		if i == len(hops)-1 {
+			payload.ReplyPath = replyPath
+			payload.CustomRecords = customRecords
+		}
+
+		var b bytes.Buffer
+		if err := payload.Encode(&b); err != nil {
+			return nil, err
+		}
+
+		hopPayload, err := sphinx.NewTLVHopPayload(b.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		path[i] = sphinx.OnionHop{
+			NodePub:    *hop.BlindedNodePub,
+			HopPayload: hopPayload,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	pkt, err := sphinx.NewOnionPacket(
+		&path, sessionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := pkt.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package onionmessage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetwork is a set of messengers that deliver onion messages to each other
+// directly.
+type testNetwork struct {
+	t        *testing.T
+	nodes    map[route.Vertex]*Messenger
+	forwards int
+}
+
+// addNode creates a new messenger and adds it to the network.
+func (n *testNetwork) addNode() (*Messenger, route.Vertex) {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(n.t, err)
+
+	node := route.NewVertex(priv.PubKey())
+
+	var m *Messenger
+	m = NewMessenger(&Config{
+		NodeKey: &keychain.PrivKeyECDH{PrivKey: priv},
+		SendToPeer: func(peer route.Vertex,
+			msg *lnwire.OnionMessage) error {
+
+			target, ok := n.nodes[peer]
+			if !ok {
+				return fmt.Errorf("unknown peer %v", peer)
+			}
+			n.forwards++
+
+			return target.HandleMessage(node, msg)
+		},
+	})
+	require.NoError(n.t, m.Start())
+	n.t.Cleanup(func() {
+		require.NoError(n.t, m.Stop())
+	})
+
+	n.nodes[node] = m
+
+	return m, node
+}
+
+// TestSendOnionMessage tests that onion messages are forwarded along their
+// path and delivered to the final recipient.
+func TestSendOnionMessage(t *testing.T) {
+	t.Parallel()
+
+	network := &testNetwork{t: t, nodes: make(map[route.Vertex]*Messenger)}
+	alice, aliceNode := network.addNode()
+	_, bobNode := network.addNode()
+	carol, carolNode := network.addNode()
+	dave, daveNode := network.addNode()
+
+	// Subscribe before sending, as messages are delivered synchronously.
+	carolClient, err := carol.SubscribeMessages()
+	require.NoError(t, err)
+	defer carolClient.Cancel()
+
+	// Alice sends a message to Carol via Bob, including a reply path
+	// to herself via Bob.
+	replyPath, err := blindedpath.BuildOnionMessagePath(
+		&blindedpath.OnionMessagePathCfg{
+			Hops:   []route.Vertex{bobNode, aliceNode},
+			PathID: []byte{9},
+		},
+	)
+	require.NoError(t, err)
+
+	records := map[uint64][]byte{64: []byte("hello")}
+	err = alice.Send(&SendRequest{
+		Destination:   &carolNode,
+		Hops:          []route.Vertex{bobNode},
+		ReplyPath:     replyPath,
+		CustomRecords: records,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, network.forwards)
+
+	var received *ReceivedMessage
+	select {
+	case update := <-carolClient.Updates():
+		received = update.(*ReceivedMessage)
+
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+	require.Equal(t, bobNode, received.Peer)
+	require.Equal(t, records, received.CustomRecords)
+	require.Nil(t, received.PathID)
+	require.NotNil(t, received.ReplyPath)
+
+	// Carol replies via Dave along the reply path, which leads to Alice
+	// with the path ID she expects.
+	aliceClient, err := alice.SubscribeMessages()
+	require.NoError(t, err)
+	defer aliceClient.Cancel()
+
+	err = carol.Send(&SendRequest{
+		BlindedPath:   received.ReplyPath,
+		Hops:          []route.Vertex{daveNode},
+		CustomRecords: map[uint64][]byte{66: []byte("hi")},
+	})
+	require.NoError(t, err)
+
+	select {
+	case update := <-aliceClient.Updates():
+		reply := update.(*ReceivedMessage)
+		require.Equal(t, []byte{9}, reply.PathID)
+		require.Equal(t, []byte("hi"), reply.CustomRecords[66])
+
+	case <-time.After(time.Second):
+		t.Fatal("reply not received")
+	}
+
+	// Messages can't be sent to ourselves, and invalid onions are
+	// rejected.
+	err = dave.Send(&SendRequest{Destination: &daveNode})
+	require.ErrorIs(t, err, ErrSendToSelf)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	err = dave.HandleMessage(carolNode, lnwire.NewOnionMessage(
+		priv.PubKey(), make([]byte, OnionPacketSize-1),
+	))
+	require.Error(t, err)
+}
@@ -0,0 +1,289 @@
+package onionmessage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// replyPathType is the type of the record that holds a blinded path
+	// the recipient can use to reply to a message.
+	replyPathType tlv.Type = 2
+
+	// encryptedDataType is the type of the record that holds the data
+	// the creator of the blinded path encrypted for a hop.
+	encryptedDataType tlv.Type = 4
+
+	// MinCustomRecordType is the lowest type of the records that carry the
+	// contents of a message for its final recipient.
+	MinCustomRecordType = 64
+
+	// maxBlindedHops is the maximum number of hops a reply path may have.
+	// The number of hops is encoded in a single byte.
+	maxBlindedHops = math.MaxUint8
+)
+
+var (
+	// ErrMissingEncryptedData is returned when an onion message payload
+	// doesn't carry the encrypted data of its blinded path.
+	ErrMissingEncryptedData = errors.New("onion message payload is " +
+		"missing encrypted data")
+
+	// ErrSciddirNotSupported is returned when the introduction node of a
+	// blinded path is specified by a channel rather than a node ID.
+	ErrSciddirNotSupported = errors.New("blinded paths with a channel " +
+		"as the introduction node are not supported")
+)
+
+// Payload is the per-hop payload of an onion message.
+type Payload struct {
+	// ReplyPath is an optional blinded path that the final recipient can
+	// use to reply to the message.
+	ReplyPath *sphinx.BlindedPath
+
+	// EncryptedData is the data the creator of the blinded path encrypted
+	// for the hop.
+	EncryptedData []byte
+
+	// CustomRecords holds the contents of the message for the final
+	// recipient, keyed by their types which must be at least
+	// MinCustomRecordType.
+	CustomRecords map[uint64][]byte
+}
+
+// Encode serializes the payload as a TLV stream.
+func (p *Payload) Encode(w io.Writer) error {
+	var records []tlv.Record
+
+	if p.ReplyPath != nil {
+		var b bytes.Buffer
+		if err := EncodeBlindedPath(&b, p.ReplyPath); err != nil {
+			return err
+		}
+		replyPath := b.Bytes()
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			replyPathType, &replyPath,
+		))
+	}
+
+	if p.EncryptedData != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			encryptedDataType, &p.EncryptedData,
+		))
+	}
+
+	for typ, value := range p.CustomRecords {
+		if typ < MinCustomRecordType {
+			return fmt.Errorf("custom record type %d below "+
+				"minimum of %d", typ, MinCustomRecordType)
+		}
+
+		value := value
+		records = append(records, tlv.MakePrimitiveRecord(
+			tlv.Type(typ), &value,
+		))
+	}
+
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// DecodePayload parses the per-hop payload of an onion message.
+func DecodePayload(r io.Reader) (*Payload, error) {
+	var (
+		payload   Payload
+		replyPath []byte
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(replyPathType, &replyPath),
+		tlv.MakePrimitiveRecord(
+			encryptedDataType, &payload.EncryptedData,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[encryptedDataType]; !ok {
+		return nil, ErrMissingEncryptedData
+	}
+
+	if _, ok := parsedTypes[replyPathType]; ok {
+		payload.ReplyPath, err = DecodeBlindedPath(
+			bytes.NewReader(replyPath),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply path: %w", err)
+		}
+	}
+
+	for typ, value := range parsedTypes {
+		if value == nil {
+			continue
+		}
+
+		// Unknown records below the custom range must be odd, as we
+		// don't understand any of the even ones.
+		if typ < MinCustomRecordType {
+			if typ%2 == 0 {
+				return nil, fmt.Errorf("unknown required "+
+					"type %d", typ)
+			}
+
+			continue
+		}
+
+		if payload.CustomRecords == nil {
+			payload.CustomRecords = make(map[uint64][]byte)
+		}
+		payload.CustomRecords[uint64(typ)] = value
+	}
+
+	return &payload, nil
+}
+
+// EncodeBlindedPath serializes a blinded path for onion messages. The path is
+// encoded as its introduction node, the first path key and the list of hops,
+// each consisting of the blinded node ID and the encrypted data of the hop.
+func EncodeBlindedPath(w io.Writer, path *sphinx.BlindedPath) error {
+	if len(path.BlindedHops) == 0 {
+		return fmt.Errorf("blinded path has no hops")
+	}
+	if len(path.BlindedHops) > maxBlindedHops {
+		return fmt.Errorf("blinded path has %d hops, maximum is %d",
+			len(path.BlindedHops), maxBlindedHops)
+	}
+
+	_, err := w.Write(path.IntroductionPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(path.BlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte{uint8(len(path.BlindedHops))})
+	if err != nil {
+		return err
+	}
+
+	for _, hop := range path.BlindedHops {
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		if len(hop.CipherText) > math.MaxUint16 {
+			return fmt.Errorf("encrypted data of %d bytes is too "+
+				"large", len(hop.CipherText))
+		}
+
+		var dataLen [2]byte
+		binary.BigEndian.PutUint16(
+			dataLen[:], uint16(len(hop.CipherText)),
+		)
+		if _, err := w.Write(dataLen[:]); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeBlindedPath parses a blinded path for onion messages.
+func DecodeBlindedPath(r io.Reader) (*sphinx.BlindedPath, error) {
+	var path sphinx.BlindedPath
+
+	// The introduction node may also be given as a direction and channel
+	// ID, which is signaled by a first byte of 0 or 1.
+	var firstByte [1]byte
+	if _, err := io.ReadFull(r, firstByte[:]); err != nil {
+		return nil, err
+	}
+	if firstByte[0] == 0 || firstByte[0] == 1 {
+		return nil, ErrSciddirNotSupported
+	}
+
+	introPoint, err := readPubKey(io.MultiReader(
+		bytes.NewReader(firstByte[:]), r,
+	))
+	if err != nil {
+		return nil, err
+	}
+	path.IntroductionPoint = introPoint
+
+	path.BlindingPoint, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return nil, err
+	}
+	if numHops[0] == 0 {
+		return nil, fmt.Errorf("blinded path has no hops")
+	}
+
+	path.BlindedHops = make([]*sphinx.BlindedHopInfo, numHops[0])
+	for i := range path.BlindedHops {
+		nodePub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var dataLen [2]byte
+		if _, err := io.ReadFull(r, dataLen[:]); err != nil {
+			return nil, err
+		}
+
+		cipherText := make([]byte, binary.BigEndian.Uint16(dataLen[:]))
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &sphinx.BlindedHopInfo{
+			BlindedNodePub: nodePub,
+			CipherText:     cipherText,
+		}
+	}
+
+	return &path, nil
+}
+
+// readPubKey reads a compressed public key from the reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var b [btcec.PubKeyBytesLenCompressed]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(b[:])
+}
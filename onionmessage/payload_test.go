@@ -0,0 +1,118 @@
+package onionmessage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlindedPath returns a blinded path with the given number of hops and
+// random keys.
+func testBlindedPath(t *testing.T, numHops int) *sphinx.BlindedPath {
+	randKey := func() *btcec.PublicKey {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return priv.PubKey()
+	}
+
+	path := &sphinx.BlindedPath{
+		IntroductionPoint: randKey(),
+		BlindingPoint:     randKey(),
+	}
+	for i := 0; i < numHops; i++ {
+		path.BlindedHops = append(path.BlindedHops,
+			&sphinx.BlindedHopInfo{
+				BlindedNodePub: randKey(),
+				CipherText: bytes.Repeat(
+					[]byte{byte(i)}, 20,
+				),
+			},
+		)
+	}
+
+	return path
+}
+
+// TestPayloadEncoding tests that onion message payloads survive a round trip
+// and that unknown required records are rejected.
+func TestPayloadEncoding(t *testing.T) {
+	t.Parallel()
+
+	payload := &Payload{
+		ReplyPath:     testBlindedPath(t, 2),
+		EncryptedData: []byte{1, 2, 3},
+		CustomRecords: map[uint64][]byte{
+			64:    {4},
+			65537: {5, 6},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, payload.Encode(&b))
+
+	decoded, err := DecodePayload(&b)
+	require.NoError(t, err)
+	require.Equal(t, payload, decoded)
+
+	// Custom records must not use the types of the payload itself.
+	payload.CustomRecords = map[uint64][]byte{4: {1}}
+	require.Error(t, payload.Encode(&b))
+
+	// The encrypted data is required.
+	payload = &Payload{CustomRecords: map[uint64][]byte{64: {1}}}
+	b.Reset()
+	require.NoError(t, payload.Encode(&b))
+	_, err = DecodePayload(&b)
+	require.ErrorIs(t, err, ErrMissingEncryptedData)
+
+	// Unknown odd types below the custom range are ignored, while unknown
+	// even types are rejected.
+	encode := func(typ tlv.Type) *bytes.Buffer {
+		var (
+			b       bytes.Buffer
+			encData = []byte{1}
+			value   = []byte{2}
+		)
+		records := []tlv.Record{
+			tlv.MakePrimitiveRecord(encryptedDataType, &encData),
+			tlv.MakePrimitiveRecord(typ, &value),
+		}
+		tlv.SortRecords(records)
+		require.NoError(t, tlv.MustNewStream(records...).Encode(&b))
+
+		return &b
+	}
+
+	decoded, err = DecodePayload(encode(7))
+	require.NoError(t, err)
+	require.Empty(t, decoded.CustomRecords)
+
+	_, err = DecodePayload(encode(8))
+	require.ErrorContains(t, err, "unknown required type")
+}
+
+// TestBlindedPathEncoding tests the encoding of blinded paths.
+func TestBlindedPathEncoding(t *testing.T) {
+	t.Parallel()
+
+	path := testBlindedPath(t, 3)
+
+	var b bytes.Buffer
+	require.NoError(t, EncodeBlindedPath(&b, path))
+
+	decoded, err := DecodeBlindedPath(&b)
+	require.NoError(t, err)
+	require.Equal(t, path, decoded)
+
+	// Paths without hops are invalid.
+	require.Error(t, EncodeBlindedPath(&b, testBlindedPath(t, 0)))
+
+	// Introduction nodes given as a channel aren't supported.
+	_, err = DecodeBlindedPath(bytes.NewReader(make([]byte, 100)))
+	require.ErrorIs(t, err, ErrSciddirNotSupported)
+}
@@ -51,6 +51,7 @@ import (
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -90,6 +91,14 @@ const (
 	// torTimeoutMultiplier is the scaling factor we use on network timeouts
 	// for Tor peers.
 	torTimeoutMultiplier = 3
+
+	// onionMessageRate is the number of onion messages per second we
+	// handle from a single peer. Messages beyond this rate are dropped.
+	onionMessageRate = 10
+
+	// onionMessageBurst is the number of onion messages we handle from a
+	// single peer in a burst before rate limiting kicks in.
+	onionMessageBurst = 50
 )
 
 var (
@@ -134,6 +143,14 @@ type closeMsg struct {
 	msg lnwire.Message
 }
 
+// closeFeeBumpReq is a local request to replace the cooperative close
+// transaction of a channel with one paying a higher fee rate.
+type closeFeeBumpReq struct {
+	chanID  lnwire.ChannelID
+	feeRate chainfee.SatPerKWeight
+	resp    chan fn.Result[chainhash.Hash]
+}
+
 // PendingUpdate describes the pending state of a closing channel.
 type PendingUpdate struct {
 	Txid        []byte
@@ -291,6 +308,11 @@ type Config struct {
 	// invoice-related logic.
 	Invoices *invoices.InvoiceRegistry
 
+	// TrampolineHandler is passed to the ChannelLink on creation and
+	// forwards the trampoline payments we receive. It is nil if we don't
+	// forward trampoline payments.
+	TrampolineHandler htlcswitch.TrampolineHandler
+
 	// ChannelNotifier is used by the link to notify other sub-systems about
 	// channel-related events and by the Brontide to subscribe to
 	// ActiveLinkEvents.
@@ -323,6 +345,11 @@ type Config struct {
 	// FundingManager is an implementation of the funding.Controller interface.
 	FundingManager funding.Controller
 
+	// NotifySpliceLocked is used to let the funding manager know that a
+	// splice of the channel with the given outpoint has been locked, so it
+	// can move the channel to its new funding output in the graph.
+	NotifySpliceLocked func(wire.OutPoint)
+
 	// Hodl is used when creating ChannelLinks to specify HodlFlags as
 	// breakpoints in dev builds.
 	Hodl *hodl.Config
@@ -376,6 +403,31 @@ type Config struct {
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
 
+	// HandleOnionMessage is called whenever an onion message is received
+	// from the peer. If nil, onion messages are ignored.
+	HandleOnionMessage func(peer [33]byte, msg *lnwire.OnionMessage) error
+
+	// HandlePeerStorage is called whenever the peer asks us to store a
+	// blob on its behalf. If nil, peer_storage messages are ignored.
+	HandlePeerStorage func(peer [33]byte, msg *lnwire.PeerStorage) error
+
+	// HandleYourPeerStorage is called whenever the peer returns the blob
+	// it stores on our behalf. If nil, your_peer_storage messages are
+	// ignored.
+	HandleYourPeerStorage func(peer [33]byte,
+		msg *lnwire.YourPeerStorage) error
+
+	// FetchPeerStorage returns the your_peer_storage message with the blob
+	// we store on behalf of the peer, which is sent to it once the
+	// connection is established. It returns nil if we don't store a blob
+	// for the peer. If nil, no blob is sent.
+	FetchPeerStorage func(peer [33]byte) (*lnwire.YourPeerStorage, error)
+
+	// OwnPeerStorage returns the peer_storage message with the blob we
+	// want peers that provide storage to store on our behalf, or nil if
+	// there's none. If nil, no blob is sent.
+	OwnPeerStorage func() *lnwire.PeerStorage
+
 	// GetAliases is passed to created links so the Switch and link can be
 	// aware of the channel's aliases.
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
@@ -411,6 +463,27 @@ type Config struct {
 	// invalid.
 	DisallowRouteBlinding bool
 
+	// DisallowQuiescence is a flag that indicates whether the Brontide
+	// should have the quiescence feature disabled.
+	DisallowQuiescence bool
+
+	// MaxLocalCSVDelay is the maximum delay we accept on our own funds
+	// when the remote party proposes a new one for an existing channel.
+	MaxLocalCSVDelay uint16
+
+	// DisallowSplice is a flag that indicates whether the Brontide should
+	// have the splice feature disabled.
+	DisallowSplice bool
+
+	// DisallowExpEndorsement is a flag that indicates whether the Brontide
+	// should stop forwarding the experimental endorsement signal of htlcs.
+	DisallowExpEndorsement bool
+
+	// DisallowRbfCoopClose is a flag that indicates whether the Brontide
+	// should use the legacy cooperative close negotiation even if the
+	// remote peer supports the simple close protocol.
+	DisallowRbfCoopClose bool
+
 	// MaxFeeExposure limits the number of outstanding fees in a channel.
 	// This value will be passed to created links.
 	MaxFeeExposure lnwire.MilliSatoshi
@@ -541,6 +614,10 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// closeFeeBumpReqs is a channel in which any local requests to bump
+	// the fee of a cooperative close transaction are sent over.
+	closeFeeBumpReqs chan *closeFeeBumpReq
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
@@ -569,6 +646,10 @@ type Brontide struct {
 	// when a peer disconnects.
 	globalMsgRouter bool
 
+	// onionMsgLimiter limits the rate at which we handle onion messages
+	// from the peer, as they are relayed at no cost to the sender.
+	onionMsgLimiter *rate.Limiter
+
 	startReady chan struct{}
 	quit       chan struct{}
 	wg         sync.WaitGroup
@@ -613,12 +694,16 @@ func NewBrontide(cfg Config) *Brontide {
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		linkFailures:       make(chan linkFailureReport),
 		chanCloseMsgs:      make(chan *closeMsg),
+		closeFeeBumpReqs:   make(chan *closeFeeBumpReq),
 		resentChanSyncMsg:  make(map[lnwire.ChannelID]struct{}),
 		startReady:         make(chan struct{}),
 		quit:               make(chan struct{}),
 		log:                build.NewPrefixLog(logPrefix, peerLog),
 		msgRouter:          msgRouter,
 		globalMsgRouter:    globalMsgRouter,
+		onionMsgLimiter: rate.NewLimiter(
+			onionMessageRate, onionMessageBurst,
+		),
 	}
 
 	if cfg.Conn != nil && cfg.Conn.RemoteAddr() != nil {
@@ -853,9 +938,10 @@ func (p *Brontide) Start() error {
 	//
 	// TODO(wilmer): Remove this once we're able to query for node
 	// announcements through their timestamps.
-	p.wg.Add(2)
+	p.wg.Add(3)
 	go p.maybeSendNodeAnn(activeChans)
 	go p.maybeSendChannelUpdates()
+	go p.maybeSendPeerStorage()
 
 	return nil
 }
@@ -1086,15 +1172,27 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 
 		// Before we register this new link with the HTLC Switch, we'll
 		// need to fetch its current link-layer forwarding policy from
-		// the database.
+		// the database. The edge of a spliced channel is known by its
+		// current funding outpoint.
 		graph := p.cfg.ChannelGraph
+		edgePoint := dbChan.CurrentFundingOutpoint()
 		info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(
-			&chanPoint,
+			&edgePoint,
 		)
 		if err != nil && !errors.Is(err, channeldb.ErrEdgeNotFound) {
 			return nil, err
 		}
 
+		// If we went down after a splice was locked but before its
+		// edge was moved to the new funding outpoint, we'll have the
+		// funding manager move it now.
+		if info == nil && dbChan.SpliceShortChanID().IsSome() {
+			p.log.Infof("Edge of spliced ChannelPoint(%v) not "+
+				"found at %v, moving it", chanPoint, edgePoint)
+
+			p.cfg.NotifySpliceLocked(chanPoint)
+		}
+
 		// We'll filter out our policy from the directional channel
 		// edges based whom the edge connects to. If it doesn't connect
 		// to us, then we know that we were the one that advertised the
@@ -1285,6 +1383,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		FetchLastChannelUpdate: p.cfg.FetchLastChanUpdate,
 		HodlMask:               p.cfg.Hodl.Mask(),
 		Registry:               p.cfg.Invoices,
+		TrampolineHandler:      p.cfg.TrampolineHandler,
 		BestHeight:             p.cfg.Switch.BestHeight,
 		Circuits:               p.cfg.Switch.CircuitModifier(),
 		ForwardPackets:         p.cfg.InterceptSwitch.ForwardPackets,
@@ -1314,11 +1413,23 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		NotifyActiveChannel:     p.cfg.ChannelNotifier.NotifyActiveChannelEvent,
 		NotifyInactiveChannel:   p.cfg.ChannelNotifier.NotifyInactiveChannelEvent,
 		NotifyInactiveLinkEvent: p.cfg.ChannelNotifier.NotifyInactiveLinkEvent,
+		NotifySpliceLocked:      p.cfg.NotifySpliceLocked,
 		HtlcNotifier:            p.cfg.HtlcNotifier,
 		GetAliases:              p.cfg.GetAliases,
 		PreviouslySentShutdown:  shutdownMsg,
 		DisallowRouteBlinding:   p.cfg.DisallowRouteBlinding,
 		MaxFeeExposure:          p.cfg.MaxFeeExposure,
+		MaxLocalCSVDelay:        p.cfg.MaxLocalCSVDelay,
+		DisallowQuiescence: p.cfg.DisallowQuiescence ||
+			!p.remoteFeatures.HasFeature(lnwire.QuiescenceOptional),
+		DisallowSplice: p.cfg.DisallowSplice ||
+			!p.remoteFeatures.HasFeature(lnwire.SpliceOptional),
+		DisallowChanTypeUpgrade: !p.cfg.Features.HasFeature(
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+		) || !p.remoteFeatures.HasFeature(
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+		),
+		DisallowExpEndorsement: p.cfg.DisallowExpEndorsement,
 	}
 
 	// Before adding our new link, purge the switch of any pending or live
@@ -1366,6 +1477,40 @@ func (p *Brontide) maybeSendNodeAnn(channels []*channeldb.OpenChannel) {
 	}
 }
 
+// maybeSendPeerStorage returns the blob we store on behalf of the remote peer,
+// and hands it our own blob if it provides storage.
+func (p *Brontide) maybeSendPeerStorage() {
+	defer p.wg.Done()
+
+	if p.cfg.FetchPeerStorage != nil {
+		yourStorage, err := p.cfg.FetchPeerStorage(p.PubKey())
+		if err != nil {
+			p.log.Errorf("Unable to fetch peer storage: %v", err)
+		}
+
+		if yourStorage != nil {
+			err := p.SendMessageLazy(false, yourStorage)
+			if err != nil {
+				p.log.Debugf("Unable to send peer storage: %v",
+					err)
+			}
+		}
+	}
+
+	if p.cfg.OwnPeerStorage == nil ||
+		!p.remoteFeatures.HasFeature(lnwire.ProvideStorageOptional) {
+
+		return
+	}
+
+	if ownStorage := p.cfg.OwnPeerStorage(); ownStorage != nil {
+		if err := p.SendMessageLazy(false, ownStorage); err != nil {
+			p.log.Debugf("Unable to send our peer storage: %v",
+				err)
+		}
+	}
+}
+
 // maybeSendChannelUpdates sends our channel updates to the remote peer if we
 // have any active channels with them.
 func (p *Brontide) maybeSendChannelUpdates() {
@@ -1973,6 +2118,8 @@ out:
 
 		case *lnwire.OpenChannel,
 			*lnwire.AcceptChannel,
+			*lnwire.OpenChannel2,
+			*lnwire.AcceptChannel2,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
 			*lnwire.ChannelReady:
@@ -1991,6 +2138,18 @@ out:
 			case <-p.quit:
 				break out
 			}
+		case *lnwire.ClosingComplete:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+		case *lnwire.ClosingSig:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
 
 		case *lnwire.Warning:
 			targetChan = msg.ChanID
@@ -2027,6 +2186,18 @@ out:
 			targetChan = msg.TargetChanID()
 			isLinkUpdate = p.hasChannel(targetChan)
 
+			// The funding transaction of a dual-funded channel is
+			// constructed interactively before the channel exists,
+			// so those messages are handled by the funding
+			// manager, as are the ones replacing the funding
+			// transaction while the channel is pending.
+			if !isLinkUpdate && p.cfg.FundingManager.
+				IsPendingChannel(targetChan, p) {
+
+				p.cfg.FundingManager.ProcessFundingMsg(msg, p)
+				break
+			}
+
 			// Log an error if we don't have this channel. This
 			// means the peer has sent us a message with unknown
 			// channel ID.
@@ -2055,6 +2226,15 @@ out:
 				p.log.Errorf("%v", err)
 			}
 
+		case *lnwire.OnionMessage:
+			p.handleOnionMessage(msg)
+
+		case *lnwire.PeerStorage:
+			p.handlePeerStorage(msg)
+
+		case *lnwire.YourPeerStorage:
+			p.handleYourPeerStorage(msg)
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -2090,6 +2270,58 @@ func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
 	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
 }
 
+// handleOnionMessage hands the given onion message to the registered handler,
+// unless the peer exceeds its onion message rate limit. Onion messages are
+// best effort, so failures to handle one are only logged.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) {
+	if p.cfg.HandleOnionMessage == nil {
+		p.log.Debugf("Ignoring onion message, no handler registered")
+		return
+	}
+
+	if !p.onionMsgLimiter.Allow() {
+		p.log.Debugf("Dropping onion message, rate limit exceeded")
+		return
+	}
+
+	err := p.cfg.HandleOnionMessage(p.PubKey(), msg)
+	if err != nil {
+		p.log.Debugf("Unable to handle onion message: %v", err)
+	}
+}
+
+// handlePeerStorage hands the blob the peer asks us to store to the registered
+// handler. Peers may send us blobs we refuse to store, so failures are only
+// logged.
+func (p *Brontide) handlePeerStorage(msg *lnwire.PeerStorage) {
+	if p.cfg.HandlePeerStorage == nil {
+		p.log.Debugf("Ignoring peer storage, no handler registered")
+		return
+	}
+
+	err := p.cfg.HandlePeerStorage(p.PubKey(), msg)
+	if err != nil {
+		p.log.Debugf("Unable to store blob of peer: %v", err)
+	}
+}
+
+// handleYourPeerStorage hands the blob the peer returns to us to the
+// registered handler. Failures to process it are only logged.
+func (p *Brontide) handleYourPeerStorage(msg *lnwire.YourPeerStorage) {
+	if p.cfg.HandleYourPeerStorage == nil {
+		p.log.Debugf("Ignoring your peer storage, no handler " +
+			"registered")
+
+		return
+	}
+
+	err := p.cfg.HandleYourPeerStorage(p.PubKey(), msg)
+	if err != nil {
+		p.log.Debugf("Unable to handle returned peer storage: %v",
+			err)
+	}
+}
+
 // isLoadedFromDisk returns true if the provided channel ID is loaded from
 // disk.
 //
@@ -2236,6 +2468,18 @@ func messageSummary(msg lnwire.Message) string {
 			msg.PendingChannelID[:], msg.ChannelReserve, msg.CsvDelay,
 			msg.MinAcceptDepth)
 
+	case *lnwire.OpenChannel2:
+		return fmt.Sprintf("temp_chan_id=%x, chain=%v, csv=%v, amt=%v, "+
+			"funding_feerate=%v, flags=%v",
+			msg.PendingChannelID[:], msg.ChainHash, msg.CsvDelay,
+			msg.FundingAmount, msg.FundingFeePerKWeight,
+			msg.ChannelFlags)
+
+	case *lnwire.AcceptChannel2:
+		return fmt.Sprintf("temp_chan_id=%x, amt=%v, csv=%v, num_confs=%v",
+			msg.PendingChannelID[:], msg.FundingAmount, msg.CsvDelay,
+			msg.MinAcceptDepth)
+
 	case *lnwire.FundingCreated:
 		return fmt.Sprintf("temp_chan_id=%x, chan_point=%v",
 			msg.PendingChannelID[:], msg.FundingPoint)
@@ -2255,6 +2499,13 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, fee_sat=%v", msg.ChannelID,
 			msg.FeeSatoshis)
 
+	case *lnwire.ClosingComplete:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v, sequence=%v",
+			msg.ChannelID, msg.FeeSatoshis, msg.Sequence)
+
+	case *lnwire.ClosingSig:
+		return fmt.Sprintf("chan_id=%v", msg.ChannelID)
+
 	case *lnwire.UpdateAddHTLC:
 		var blindingPoint []byte
 		msg.BlindingPoint.WhenSome(
@@ -2360,8 +2611,67 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, initiator=%v", msg.ChanID,
 			msg.Initiator)
 
+	case *lnwire.DynPropose:
+		return fmt.Sprintf("chan_id=%v, initiator=%v", msg.ChanID,
+			msg.Initiator)
+
+	case *lnwire.DynAck:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.DynReject:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.SpliceInit:
+		return fmt.Sprintf("chan_id=%v, contribution=%v, feerate=%v",
+			msg.ChanID, msg.FundingContribution, msg.FundingFeerate)
+
+	case *lnwire.SpliceAck:
+		return fmt.Sprintf("chan_id=%v, contribution=%v", msg.ChanID,
+			msg.FundingContribution)
+
+	case *lnwire.SpliceLocked:
+		return fmt.Sprintf("chan_id=%v, splice_txid=%v", msg.ChanID,
+			msg.SpliceTxid)
+
+	case *lnwire.TxAddInput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChanID,
+			msg.SerialID)
+
+	case *lnwire.TxAddOutput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v, amt=%v",
+			msg.ChanID, msg.SerialID, msg.Amount)
+
+	case *lnwire.TxRemoveInput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChanID,
+			msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChanID,
+			msg.SerialID)
+
+	case *lnwire.TxComplete:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.TxSignatures:
+		return fmt.Sprintf("chan_id=%v, txid=%v, num_witnesses=%v",
+			msg.ChanID, msg.Txid, len(msg.Witnesses))
+
+	case *lnwire.TxAbort:
+		return fmt.Sprintf("chan_id=%v, data=%q", msg.ChanID,
+			string(msg.Data))
+
 	case *lnwire.Custom:
 		return fmt.Sprintf("type=%d", msg.Type)
+
+	case *lnwire.OnionMessage:
+		return fmt.Sprintf("path_key=%x, onion_len=%d",
+			msg.PathKey.SerializeCompressed(), len(msg.OnionBlob))
+
+	case *lnwire.PeerStorage:
+		return fmt.Sprintf("blob_len=%d", len(msg.Blob))
+
+	case *lnwire.YourPeerStorage:
+		return fmt.Sprintf("blob_len=%d", len(msg.Blob))
 	}
 
 	return fmt.Sprintf("unknown msg type=%T", msg)
@@ -2778,6 +3088,11 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've received a local request to bump the fee of a
+		// cooperative close transaction.
+		case req := <-p.closeFeeBumpReqs:
+			p.handleCloseFeeBump(req)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -3139,12 +3454,16 @@ func (p *Brontide) restartCoopClose(lnChan *lnwallet.LightningChannel) (
 	// Shutdown exactly, but doing so would mean persisting the RPC
 	// provided close script. Instead use the LocalUpfrontShutdownScript
 	// or generate a script.
+	//
+	// With the simple close protocol, the closing transaction may still be
+	// replaced by either party, so we'll also restart the process if we
+	// already have one, resuming from the latest closing transaction.
 	c := lnChan.State()
-	_, err := c.BroadcastedCooperative()
+	closeTx, err := c.BroadcastedCooperative()
 	if err != nil && err != channeldb.ErrNoCloseTx {
 		// An error other than ErrNoCloseTx was encountered.
 		return nil, err
-	} else if err == nil {
+	} else if err == nil && !p.simpleCloseSupported(lnChan) {
 		// This channel has already completed the coop close
 		// negotiation.
 		return nil, nil
@@ -3207,6 +3526,12 @@ func (p *Brontide) restartCoopClose(lnChan *lnwallet.LightningChannel) (
 		return nil, fmt.Errorf("unable to create chan closer")
 	}
 
+	if closeTx != nil {
+		if err := chanCloser.RestoreSimpleClose(closeTx); err != nil {
+			return nil, err
+		}
+	}
+
 	// This does not need a mutex even though it is in a different
 	// goroutine since this is done before the channelManager goroutine is
 	// created.
@@ -3224,6 +3549,24 @@ func (p *Brontide) restartCoopClose(lnChan *lnwallet.LightningChannel) (
 	return shutdownMsg, nil
 }
 
+// simpleCloseSupported returns true if the closing transaction of the given
+// channel should be negotiated using the simple close protocol. Taproot and
+// custom channels keep using the legacy negotiation.
+func (p *Brontide) simpleCloseSupported(
+	channel *lnwallet.LightningChannel) bool {
+
+	if p.cfg.DisallowRbfCoopClose || p.cfg.AuxChanCloser.IsSome() {
+		return false
+	}
+
+	if channel.ChanType().IsTaproot() {
+		return false
+	}
+
+	return p.RemoteFeatures().HasFeature(lnwire.SimpleCloseOptional) &&
+		p.LocalFeatures().HasFeature(lnwire.SimpleCloseOptional)
+}
+
 // createChanCloser constructs a ChanCloser from the passed parameters and is
 // used to de-duplicate code.
 func (p *Brontide) createChanCloser(channel *lnwallet.LightningChannel,
@@ -3261,6 +3604,7 @@ func (p *Brontide) createChanCloser(channel *lnwallet.LightningChannel,
 			},
 			ChainParams: &p.cfg.Wallet.Cfg.NetParams,
 			Quit:        p.quit,
+			SimpleClose: p.simpleCloseSupported(channel),
 		},
 		*deliveryScript,
 		fee,
@@ -3574,6 +3918,143 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 	)
 }
 
+// beginSimpleClose kicks off the simple close protocol once the channel has
+// been flushed. Our initial ClosingComplete is sent if we initiated the
+// shutdown, and any ClosingComplete the remote party sent while we were
+// waiting for the flush is processed again.
+func (p *Brontide) beginSimpleClose(chanCloser *chancloser.ChanCloser,
+	handleErr func(error)) {
+
+	oClosingComplete, err := chanCloser.BeginSimpleClose()
+	if err != nil {
+		handleErr(err)
+		return
+	}
+
+	oClosingComplete.WhenSome(func(msg lnwire.ClosingComplete) {
+		p.queueMsg(&msg, nil)
+	})
+
+	// As we may be called from the link once it's flushed, we re-deliver
+	// the cached message so it's processed by the channelManager.
+	chanCloser.CachedClosingComplete().WhenSome(
+		func(msg lnwire.ClosingComplete) {
+			go func() {
+				select {
+				case p.chanCloseMsgs <- &closeMsg{
+					msg.ChannelID, &msg,
+				}:
+				case <-p.quit:
+				}
+			}()
+		},
+	)
+}
+
+// finalizeSimpleClose performs the clean up steps once a closing transaction
+// negotiated with the simple close protocol has been broadcast. As either
+// party may replace the closing transaction with one paying a higher fee, the
+// chan closer is kept around, and we wait for the funding output to be spent
+// rather than for a specific transaction to confirm.
+func (p *Brontide) finalizeSimpleClose(chanCloser *chancloser.ChanCloser,
+	replacement bool) {
+
+	closeReq := chanCloser.CloseRequest()
+
+	closingTx, err := chanCloser.ClosingTx()
+	if err != nil {
+		p.log.Errorf("Unable to fetch closing tx: %v", err)
+		return
+	}
+	closingTxid := closingTx.TxHash()
+
+	// If this is a locally requested shutdown, update the caller with the
+	// latest closing transaction. The caller may no longer be listening
+	// for replacements, so we won't block on it.
+	if closeReq != nil {
+		select {
+		case closeReq.Updates <- &PendingUpdate{Txid: closingTxid[:]}:
+		default:
+		}
+	}
+
+	// If we already broadcast a closing transaction before, we're already
+	// waiting for the channel to close.
+	if replacement {
+		p.log.Infof("Replaced closing tx of ChannelPoint(%v) with %v",
+			chanCloser.Channel().ChannelPoint(), closingTxid)
+
+		return
+	}
+
+	chanPoint := chanCloser.Channel().ChannelPoint()
+	p.WipeChannel(&chanPoint)
+
+	// If this channel closure is not locally initiated, closeReq will be
+	// nil, so we'll ignore any error.
+	errChan := make(chan error, 1)
+	if closeReq != nil {
+		errChan = closeReq.Err
+	}
+
+	localOut := chanCloser.LocalCloseOutput()
+	remoteOut := chanCloser.RemoteCloseOutput()
+	auxOut := chanCloser.AuxOutputs()
+	fundingScript := chanCloser.Channel().FundingTxOut().PkScript
+	go waitForCoopCloseSpend(
+		chanCloser.NegotiationHeight(), p.cfg.ChainNotifier, errChan,
+		&chanPoint, fundingScript, func(closingTxid chainhash.Hash) {
+			// Respond to the local subsystem which requested the
+			// channel closure.
+			if closeReq != nil {
+				closeReq.Updates <- &ChannelCloseUpdate{
+					ClosingTxid:       closingTxid[:],
+					Success:           true,
+					LocalCloseOutput:  localOut,
+					RemoteCloseOutput: remoteOut,
+					AuxOutputs:        auxOut,
+				}
+			}
+		},
+	)
+}
+
+// waitForCoopCloseSpend waits until the funding output of the channel has been
+// spent on chain by any of the closing transactions, and then executes the
+// callback with the txid of the confirmed closing transaction. If any error is
+// encountered, then it will be sent over the errChan.
+func waitForCoopCloseSpend(bestHeight uint32,
+	notifier chainntnfs.ChainNotifier, errChan chan error,
+	chanPoint *wire.OutPoint, fundingScript []byte,
+	cb func(chainhash.Hash)) {
+
+	peerLog.Infof("Waiting for cooperative close of ChannelPoint(%v)",
+		chanPoint)
+
+	spendNtfn, err := notifier.RegisterSpendNtfn(
+		chanPoint, fundingScript, bestHeight,
+	)
+	if err != nil {
+		if errChan != nil {
+			errChan <- err
+		}
+		return
+	}
+	defer spendNtfn.Cancel()
+
+	// In the case that the ChainNotifier is shutting down, all subscriber
+	// notification channels will be closed, generating a nil receive.
+	spend, ok := <-spendNtfn.Spend
+	if !ok {
+		return
+	}
+
+	peerLog.Infof("ChannelPoint(%v) is now closed by %v at height %v",
+		chanPoint, spend.SpenderTxHash, spend.SpendingHeight)
+
+	cb(*spend.SpenderTxHash)
+}
+
 // WaitForChanToClose uses the passed notifier to wait until the channel has
 // been detected as closed on chain and then concludes by executing the
 // following actions: the channel point will be sent over the settleChan, and
@@ -4000,6 +4481,12 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		p.Disconnect(err)
 	}
 
+	// With the simple close protocol, a closing transaction that was
+	// already broadcast may be replaced, so we note whether we already had
+	// one before processing this message.
+	_, err = chanCloser.ClosingTx()
+	hadClosingTx := err == nil
+
 	// Next, we'll process the next message using the target state machine.
 	// We'll either continue negotiation, or halt.
 	switch typed := msg.msg.(type) {
@@ -4041,6 +4528,11 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		})
 
 		beginNegotiation := func() {
+			if chanCloser.SimpleClose() {
+				p.beginSimpleClose(chanCloser, handleErr)
+				return
+			}
+
 			oClosingSigned, err := chanCloser.BeginNegotiation()
 			if err != nil {
 				handleErr(err)
@@ -4076,6 +4568,23 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 			p.queueMsg(&msg, nil)
 		})
 
+	case *lnwire.ClosingComplete:
+		oClosingSig, err := chanCloser.ReceiveClosingComplete(*typed)
+		if err != nil {
+			handleErr(err)
+			return
+		}
+
+		oClosingSig.WhenSome(func(msg lnwire.ClosingSig) {
+			p.queueMsg(&msg, nil)
+		})
+
+	case *lnwire.ClosingSig:
+		if err := chanCloser.ReceiveClosingSig(*typed); err != nil {
+			handleErr(err)
+			return
+		}
+
 	default:
 		panic("impossible closeMsg type")
 	}
@@ -4086,6 +4595,13 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		return
 	}
 
+	// With the simple close protocol, the closing transaction may be
+	// replaced later on, so it's finalized differently.
+	if chanCloser.SimpleClose() {
+		p.finalizeSimpleClose(chanCloser, hadClosingTx)
+		return
+	}
+
 	// Otherwise, we've agreed on a closing fee! In this case, we'll wrap up
 	// the channel closure by notifying relevant sub-systems and launching a
 	// goroutine to wait for close tx conf.
@@ -4106,6 +4622,57 @@ func (p *Brontide) HandleLocalCloseChanReqs(req *htlcswitch.ChanClose) {
 	}
 }
 
+// BumpCloseFee replaces the cooperative close transaction of the given channel
+// with one paying the given fee rate, which is paid for by us. The channel
+// must be closing using the simple close protocol. The txid of the proposed
+// closing transaction is returned once the proposal has been sent to the
+// remote party.
+func (p *Brontide) BumpCloseFee(chanPoint wire.OutPoint,
+	feeRate chainfee.SatPerKWeight) (chainhash.Hash, error) {
+
+	req := &closeFeeBumpReq{
+		chanID:  lnwire.NewChanIDFromOutPoint(chanPoint),
+		feeRate: feeRate,
+		resp:    make(chan fn.Result[chainhash.Hash], 1),
+	}
+
+	select {
+	case p.closeFeeBumpReqs <- req:
+	case <-p.quit:
+		return chainhash.Hash{}, lnpeer.ErrPeerExiting
+	}
+
+	select {
+	case res := <-req.resp:
+		return res.Unpack()
+	case <-p.quit:
+		return chainhash.Hash{}, lnpeer.ErrPeerExiting
+	}
+}
+
+// handleCloseFeeBump proposes a new closing transaction at a higher fee rate
+// for a channel that is being closed with the simple close protocol.
+func (p *Brontide) handleCloseFeeBump(req *closeFeeBumpReq) {
+	chanCloser, ok := p.activeChanCloses[req.chanID]
+	if !ok {
+		req.resp <- fn.Errf[chainhash.Hash]("no active cooperative "+
+			"close for channel %v", req.chanID)
+		return
+	}
+
+	closingComplete, txid, err := chanCloser.ProposeSimpleClose(
+		req.feeRate,
+	)
+	if err != nil {
+		req.resp <- fn.Err[chainhash.Hash](err)
+		return
+	}
+
+	p.queueMsg(closingComplete, nil)
+
+	req.resp <- fn.Ok(*txid)
+}
+
 // NetAddress returns the network of the remote peer as an lnwire.NetAddress.
 func (p *Brontide) NetAddress() *lnwire.NetAddress {
 	return p.cfg.Addr
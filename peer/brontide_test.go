@@ -1457,3 +1457,27 @@ func TestRemovePendingChannel(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+// TestHandleOnionMessageRateLimit tests that onion messages received from a
+// peer beyond its rate limit are dropped.
+func TestHandleOnionMessageRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var handled int
+	peer := NewBrontide(Config{
+		HandleOnionMessage: func([33]byte, *lnwire.OnionMessage) error {
+			handled++
+			return nil
+		},
+	})
+
+	msg := lnwire.NewOnionMessage(nil, nil)
+	for i := 0; i < onionMessageBurst*2; i++ {
+		peer.handleOnionMessage(msg)
+	}
+
+	// Only the burst is handled, plus at most one message allowed by
+	// the limiter refilling while the test runs.
+	require.LessOrEqual(t, handled, onionMessageBurst+1)
+	require.GreaterOrEqual(t, handled, onionMessageBurst)
+}
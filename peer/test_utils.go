@@ -4,6 +4,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"math/rand"
 	"net"
@@ -24,6 +25,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lntest/channels"
 	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -478,6 +480,35 @@ func (m *mockUpdateHandler) OnCommitOnce(
 	hook()
 }
 
+func (m *mockUpdateHandler) InitStfu() <-chan fn.Result[lntypes.ChannelParty] {
+	// TODO(proofofkeags): Implement
+	c := make(chan fn.Result[lntypes.ChannelParty], 1)
+
+	c <- fn.Errf[lntypes.ChannelParty]("InitStfu not implemented")
+
+	return c
+}
+
+func (m *mockUpdateHandler) ProposeDynCommit(
+	htlcswitch.DynCommitParams) <-chan error {
+
+	c := make(chan error, 1)
+
+	c <- fmt.Errorf("ProposeDynCommit not implemented")
+
+	return c
+}
+
+func (m *mockUpdateHandler) InitSplice(
+	*lnwallet.SpliceContribution) <-chan fn.Result[*wire.MsgTx] {
+
+	c := make(chan fn.Result[*wire.MsgTx], 1)
+
+	c <- fn.Errf[*wire.MsgTx]("InitSplice not implemented")
+
+	return c
+}
+
 func newMockConn(t *testing.T, expectedMessages int) *mockMessageConn {
 	return &mockMessageConn{
 		t:               t,
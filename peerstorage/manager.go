@@ -0,0 +1,340 @@
+package peerstorage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// ErrBlobTooLarge is returned when a peer asks us to store a blob
+	// larger than our per-peer limit.
+	ErrBlobTooLarge = errors.New("peer storage blob too large")
+
+	// ErrQuotaExceeded is returned when storing a blob would exceed the
+	// total number of bytes we store on behalf of our peers.
+	ErrQuotaExceeded = errors.New("peer storage quota exceeded")
+
+	// ErrNoChannel is returned when a peer we don't have a channel with
+	// asks us to store a blob.
+	ErrNoChannel = errors.New("no channel with peer")
+
+	// ErrNoRetrievedBackup is returned when none of our peers returned a
+	// backup of our channels.
+	ErrNoRetrievedBackup = errors.New("no backup retrieved from peers")
+)
+
+// ManagerConfig holds the dependencies of the Manager.
+type ManagerConfig struct {
+	// Store holds the blobs we store on behalf of our peers.
+	Store Store
+
+	// Provide indicates whether we store blobs on behalf of our peers.
+	Provide bool
+
+	// MaxBlobSize is the largest blob in bytes we store on behalf of a
+	// single peer.
+	MaxBlobSize uint32
+
+	// MaxTotalSize is the largest number of bytes we store on behalf of
+	// all our peers combined.
+	MaxTotalSize uint64
+
+	// HasChannel returns true if we have a channel with the given peer.
+	// We only store blobs on behalf of peers we have a channel with.
+	HasChannel func(peer route.Vertex) (bool, error)
+
+	// UploadBackup indicates whether we hand our own encrypted channel
+	// backup to peers that provide storage.
+	UploadBackup bool
+
+	// SendToStoragePeers sends the given message to all connected peers
+	// that advertise option_provide_storage.
+	SendToStoragePeers func(msg *lnwire.PeerStorage)
+
+	// KeyRing is used to decrypt the backups our peers return to us.
+	KeyRing keychain.KeyRing
+}
+
+// Manager stores blobs on behalf of our peers, and hands our own encrypted
+// static channel backup to the peers that store blobs for us.
+type Manager struct {
+	cfg *ManagerConfig
+
+	// mu guards the fields below, and serializes the quota checks of
+	// blobs stored on behalf of peers.
+	mu sync.Mutex
+
+	// ownBackup is the latest packed multi backup of our channels that
+	// we hand to our peers.
+	ownBackup []byte
+
+	// retrieved holds the channel backups our peers returned to us,
+	// keyed by the funding outpoint of the channel.
+	retrieved map[wire.OutPoint]chanbackup.Single
+}
+
+// NewManager creates a new peer storage manager.
+func NewManager(cfg *ManagerConfig) *Manager {
+	return &Manager{
+		cfg:       cfg,
+		retrieved: make(map[wire.OutPoint]chanbackup.Single),
+	}
+}
+
+// HandlePeerStorage stores the blob of a peer_storage message sent by the
+// given peer, replacing the blob we previously stored for it.
+func (m *Manager) HandlePeerStorage(peer route.Vertex,
+	msg *lnwire.PeerStorage) error {
+
+	if !m.cfg.Provide {
+		log.Debugf("Ignoring peer storage of %v, storage not provided",
+			peer)
+
+		return nil
+	}
+
+	if len(msg.Blob) > int(m.cfg.MaxBlobSize) {
+		return fmt.Errorf("%w: %d bytes exceeds limit of %d bytes",
+			ErrBlobTooLarge, len(msg.Blob), m.cfg.MaxBlobSize)
+	}
+
+	hasChannel, err := m.cfg.HasChannel(peer)
+	if err != nil {
+		return err
+	}
+	if !hasChannel {
+		return ErrNoChannel
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	total, err := m.cfg.Store.TotalSize()
+	if err != nil {
+		return err
+	}
+
+	// The new blob replaces the previous one of the peer, so it doesn't
+	// count towards the quota.
+	prevBlob, err := m.cfg.Store.FetchBlob(peer)
+	switch {
+	case errors.Is(err, ErrBlobNotFound):
+		// This is the first blob of the peer.
+
+	case err != nil:
+		return err
+
+	default:
+		total -= uint64(len(prevBlob))
+	}
+
+	if total+uint64(len(msg.Blob)) > m.cfg.MaxTotalSize {
+		return fmt.Errorf("%w: storing %d bytes exceeds limit of %d "+
+			"bytes", ErrQuotaExceeded, len(msg.Blob),
+			m.cfg.MaxTotalSize)
+	}
+
+	log.Debugf("Storing %d byte blob of peer %v", len(msg.Blob), peer)
+
+	return m.cfg.Store.PutBlob(peer, msg.Blob)
+}
+
+// StoredBlob returns the your_peer_storage message with the blob we store on
+// behalf of the given peer, or nil if we don't store a blob for it.
+func (m *Manager) StoredBlob(peer route.Vertex) (*lnwire.YourPeerStorage,
+	error) {
+
+	if !m.cfg.Provide {
+		return nil, nil
+	}
+
+	blob, err := m.cfg.Store.FetchBlob(peer)
+	switch {
+	case errors.Is(err, ErrBlobNotFound):
+		return nil, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnwire.YourPeerStorage{
+		Blob: blob,
+	}, nil
+}
+
+// ForgetPeer removes the blob we store on behalf of the given peer.
+func (m *Manager) ForgetPeer(peer route.Vertex) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.cfg.Store.DeleteBlob(peer)
+}
+
+// ChannelClosed removes the blob we store on behalf of the given peer if the
+// channel that was just closed was the last one we had with it.
+func (m *Manager) ChannelClosed(peer route.Vertex) error {
+	hasChannel, err := m.cfg.HasChannel(peer)
+	if err != nil {
+		return err
+	}
+	if hasChannel {
+		return nil
+	}
+
+	log.Debugf("Removing blob of peer %v, no channels left", peer)
+
+	return m.ForgetPeer(peer)
+}
+
+// PruneBlobs removes the blobs of all peers we no longer have a channel with.
+// This covers peers whose last channel closed before we were able to remove
+// their blob, for example because we shut down in between.
+func (m *Manager) PruneBlobs() error {
+	peers, err := m.cfg.Store.Peers()
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if err := m.ChannelClosed(peer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HandleYourPeerStorage processes the blob a peer returned to us. If the blob
+// is a backup of our channels, the channels are added to the set of channels
+// that can be restored using RetrievedBackup.
+func (m *Manager) HandleYourPeerStorage(peer route.Vertex,
+	msg *lnwire.YourPeerStorage) error {
+
+	if len(msg.Blob) == 0 {
+		return nil
+	}
+
+	packedMulti := chanbackup.PackedMulti(msg.Blob)
+	multi, err := packedMulti.Unpack(m.cfg.KeyRing)
+	if err != nil {
+		return fmt.Errorf("unable to unpack backup returned by "+
+			"peer %v: %w", peer, err)
+	}
+
+	log.Infof("Retrieved backup of %d channels from peer %v",
+		len(multi.StaticBackups), peer)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, single := range multi.StaticBackups {
+		m.retrieved[single.FundingOutpoint] = single
+	}
+
+	return nil
+}
+
+// RetrievedBackup returns the channel backups our peers returned to us.
+// Returns ErrNoRetrievedBackup if no peer returned a backup.
+func (m *Manager) RetrievedBackup() ([]chanbackup.Single, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.retrieved) == 0 {
+		return nil, ErrNoRetrievedBackup
+	}
+
+	singles := make([]chanbackup.Single, 0, len(m.retrieved))
+	for _, single := range m.retrieved {
+		singles = append(singles, single)
+	}
+
+	return singles, nil
+}
+
+// OwnBackup returns the peer_storage message with our latest channel backup,
+// or nil if we don't upload our backup to peers.
+func (m *Manager) OwnBackup() *lnwire.PeerStorage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ownBackup == nil {
+		return nil
+	}
+
+	return &lnwire.PeerStorage{
+		Blob: m.ownBackup,
+	}
+}
+
+// UpdateBackup sets our latest packed channel backup, and hands it to all
+// connected peers that provide storage.
+func (m *Manager) UpdateBackup(backup chanbackup.PackedMulti) {
+	if !m.cfg.UploadBackup {
+		return
+	}
+
+	// Peers can't store blobs that don't fit into a single message.
+	if len(backup) > lnwire.MaxPeerStorageBlobSize {
+		log.Warnf("Channel backup of %d bytes exceeds peer storage "+
+			"limit of %d bytes, not uploading to peers",
+			len(backup), lnwire.MaxPeerStorageBlobSize)
+
+		return
+	}
+
+	m.mu.Lock()
+	if bytes.Equal(m.ownBackup, backup) {
+		m.mu.Unlock()
+		return
+	}
+	m.ownBackup = backup
+	m.mu.Unlock()
+
+	log.Debugf("Uploading %d byte channel backup to peers", len(backup))
+
+	m.cfg.SendToStoragePeers(&lnwire.PeerStorage{
+		Blob: backup,
+	})
+}
+
+// backupSwapper wraps a chanbackup.Swapper to hand each updated channel backup
+// to our peers.
+type backupSwapper struct {
+	chanbackup.Swapper
+
+	manager *Manager
+}
+
+// NewBackupSwapper returns a chanbackup.Swapper that hands each channel backup
+// written by the given swapper to our peers.
+func NewBackupSwapper(swapper chanbackup.Swapper,
+	manager *Manager) chanbackup.Swapper {
+
+	return &backupSwapper{
+		Swapper: swapper,
+		manager: manager,
+	}
+}
+
+// UpdateAndSwap writes the new backup using the wrapped swapper, and then
+// hands it to our peers.
+//
+// NOTE: This is part of the chanbackup.Swapper interface.
+func (b *backupSwapper) UpdateAndSwap(newBackup chanbackup.PackedMulti) error {
+	if err := b.Swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	b.manager.UpdateBackup(newBackup)
+
+	return nil
+}
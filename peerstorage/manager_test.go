@@ -0,0 +1,273 @@
+package peerstorage
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestManager creates a manager backed by a test database that considers
+// the given peers to be channel peers.
+func newTestManager(t *testing.T, maxBlobSize uint32, maxTotalSize uint64,
+	channelPeers ...route.Vertex) (*Manager, *[]*lnwire.PeerStorage) {
+
+	backend, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "peerstorage")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	store, err := NewStore(backend)
+	require.NoError(t, err)
+
+	rootKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	var sent []*lnwire.PeerStorage
+	manager := NewManager(&ManagerConfig{
+		Store:        store,
+		Provide:      true,
+		MaxBlobSize:  maxBlobSize,
+		MaxTotalSize: maxTotalSize,
+		HasChannel: func(peer route.Vertex) (bool, error) {
+			for _, channelPeer := range channelPeers {
+				if channelPeer == peer {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		},
+		UploadBackup: true,
+		SendToStoragePeers: func(msg *lnwire.PeerStorage) {
+			sent = append(sent, msg)
+		},
+		KeyRing: &mock.SecretKeyRing{RootKey: rootKey},
+	})
+
+	return manager, &sent
+}
+
+// TestHandlePeerStorage tests that we store the blobs of our channel peers
+// within the configured limits, and return them on request.
+func TestHandlePeerStorage(t *testing.T) {
+	t.Parallel()
+
+	alice := route.Vertex{1}
+	bob := route.Vertex{2}
+	carol := route.Vertex{3}
+	manager, _ := newTestManager(t, 100, 150, alice, bob)
+
+	// We don't store blobs for peers we don't have a channel with.
+	err := manager.HandlePeerStorage(carol, &lnwire.PeerStorage{
+		Blob: []byte{1},
+	})
+	require.ErrorIs(t, err, ErrNoChannel)
+
+	// Blobs exceeding the per-peer limit are rejected.
+	err = manager.HandlePeerStorage(alice, &lnwire.PeerStorage{
+		Blob: make([]byte, 101),
+	})
+	require.ErrorIs(t, err, ErrBlobTooLarge)
+
+	// Nothing was stored yet, so we don't return a blob.
+	yourStorage, err := manager.StoredBlob(alice)
+	require.NoError(t, err)
+	require.Nil(t, yourStorage)
+
+	aliceBlob := bytes.Repeat([]byte{1}, 100)
+	err = manager.HandlePeerStorage(alice, &lnwire.PeerStorage{
+		Blob: aliceBlob,
+	})
+	require.NoError(t, err)
+
+	yourStorage, err = manager.StoredBlob(alice)
+	require.NoError(t, err)
+	require.Equal(t, aliceBlob, yourStorage.Blob)
+
+	// Storing another 100 bytes for bob exceeds the total quota.
+	err = manager.HandlePeerStorage(bob, &lnwire.PeerStorage{
+		Blob: make([]byte, 100),
+	})
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+
+	// Alice replacing her blob doesn't count her previous blob towards
+	// the quota.
+	aliceBlob = bytes.Repeat([]byte{2}, 50)
+	err = manager.HandlePeerStorage(alice, &lnwire.PeerStorage{
+		Blob: aliceBlob,
+	})
+	require.NoError(t, err)
+
+	yourStorage, err = manager.StoredBlob(alice)
+	require.NoError(t, err)
+	require.Equal(t, aliceBlob, yourStorage.Blob)
+
+	// Which leaves enough room for bob's blob.
+	err = manager.HandlePeerStorage(bob, &lnwire.PeerStorage{
+		Blob: make([]byte, 100),
+	})
+	require.NoError(t, err)
+
+	// Once we forget alice, we no longer return her blob.
+	require.NoError(t, manager.ForgetPeer(alice))
+
+	yourStorage, err = manager.StoredBlob(alice)
+	require.NoError(t, err)
+	require.Nil(t, yourStorage)
+}
+
+// TestPruneBlobs tests that we remove the blobs of peers once we no longer
+// have a channel with them.
+func TestPruneBlobs(t *testing.T) {
+	t.Parallel()
+
+	alice := route.Vertex{1}
+	bob := route.Vertex{2}
+	carol := route.Vertex{3}
+	manager, _ := newTestManager(t, 100, 300, alice, bob, carol)
+
+	for _, peer := range []route.Vertex{alice, bob, carol} {
+		err := manager.HandlePeerStorage(peer, &lnwire.PeerStorage{
+			Blob: []byte{peer[0]},
+		})
+		require.NoError(t, err)
+	}
+
+	// From now on, we only have channels with alice and bob.
+	openChannels := map[route.Vertex]bool{alice: true, bob: true}
+	manager.cfg.HasChannel = func(peer route.Vertex) (bool, error) {
+		return openChannels[peer], nil
+	}
+
+	assertStored := func(peer route.Vertex, stored bool) {
+		t.Helper()
+
+		yourStorage, err := manager.StoredBlob(peer)
+		require.NoError(t, err)
+		require.Equal(t, stored, yourStorage != nil)
+	}
+
+	// Carol's last channel closed while we were offline, so her blob is
+	// removed on startup.
+	require.NoError(t, manager.PruneBlobs())
+	assertStored(alice, true)
+	assertStored(bob, true)
+	assertStored(carol, false)
+
+	// Closing a channel with alice doesn't remove her blob while she has
+	// another channel left.
+	require.NoError(t, manager.ChannelClosed(alice))
+	assertStored(alice, true)
+
+	// Once her last channel is closed, it is removed.
+	delete(openChannels, alice)
+	require.NoError(t, manager.ChannelClosed(alice))
+	assertStored(alice, false)
+	assertStored(bob, true)
+}
+
+// testSingle returns a channel backup with the given funding outpoint.
+func testSingle(t *testing.T, fundingOutpoint wire.OutPoint) chanbackup.Single {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pubKey := key.PubKey()
+	keyDesc := keychain.KeyDescriptor{PubKey: pubKey}
+
+	return chanbackup.Single{
+		Version:         chanbackup.AnchorsCommitVersion,
+		ChainHash:       chainhash.Hash{1},
+		FundingOutpoint: fundingOutpoint,
+		RemoteNodePub:   pubKey,
+		Addresses: []net.Addr{
+			&net.TCPAddr{IP: net.IP{127, 0, 0, 1}, Port: 9735},
+		},
+		Capacity: 1_000_000,
+		RemoteChanCfg: channeldb.ChannelConfig{
+			MultiSigKey:         keyDesc,
+			RevocationBasePoint: keyDesc,
+			PaymentBasePoint:    keyDesc,
+			DelayBasePoint:      keyDesc,
+			HtlcBasePoint:       keyDesc,
+		},
+		ShaChainRootDesc: keyDesc,
+	}
+}
+
+// TestOwnBackup tests that we upload our channel backup to our peers, and are
+// able to restore the channels from the backups they return.
+func TestOwnBackup(t *testing.T) {
+	t.Parallel()
+
+	manager, sent := newTestManager(t, 100, 100)
+	require.Nil(t, manager.OwnBackup())
+
+	// Without any backup returned by our peers, there's nothing to
+	// restore.
+	_, err := manager.RetrievedBackup()
+	require.ErrorIs(t, err, ErrNoRetrievedBackup)
+
+	single1 := testSingle(t, wire.OutPoint{Index: 1})
+	single2 := testSingle(t, wire.OutPoint{Index: 2})
+
+	packBackup := func(singles ...chanbackup.Single) []byte {
+		var b bytes.Buffer
+		multi := chanbackup.Multi{StaticBackups: singles}
+		require.NoError(t, multi.PackToWriter(
+			&b, manager.cfg.KeyRing,
+		))
+
+		return b.Bytes()
+	}
+
+	// Updating our backup hands it to our peers.
+	backup := packBackup(single1)
+	manager.UpdateBackup(backup)
+	require.Len(t, *sent, 1)
+	require.Equal(t, backup, (*sent)[0].Blob)
+	require.Equal(t, backup, manager.OwnBackup().Blob)
+
+	// Backups that don't fit into a message aren't uploaded.
+	manager.UpdateBackup(make([]byte, lnwire.MaxPeerStorageBlobSize+1))
+	require.Len(t, *sent, 1)
+	require.Equal(t, backup, manager.OwnBackup().Blob)
+
+	// Blobs that can't be decrypted are rejected.
+	err = manager.HandleYourPeerStorage(route.Vertex{1},
+		&lnwire.YourPeerStorage{Blob: []byte{1, 2, 3}},
+	)
+	require.Error(t, err)
+
+	// The backups returned by different peers are merged.
+	err = manager.HandleYourPeerStorage(route.Vertex{1},
+		&lnwire.YourPeerStorage{Blob: backup},
+	)
+	require.NoError(t, err)
+
+	err = manager.HandleYourPeerStorage(route.Vertex{2},
+		&lnwire.YourPeerStorage{Blob: packBackup(single1, single2)},
+	)
+	require.NoError(t, err)
+
+	singles, err := manager.RetrievedBackup()
+	require.NoError(t, err)
+	require.Len(t, singles, 2)
+
+	outpoints := []wire.OutPoint{
+		singles[0].FundingOutpoint, singles[1].FundingOutpoint,
+	}
+	require.ElementsMatch(t, []wire.OutPoint{
+		single1.FundingOutpoint, single2.FundingOutpoint,
+	}, outpoints)
+}
@@ -0,0 +1,171 @@
+package peerstorage
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// peerStorageBucketKey is the key of the bucket that holds the blobs
+	// we store on behalf of our peers.
+	//
+	// maps: peer pubkey -> blob
+	peerStorageBucketKey = []byte("peer-storage")
+
+	errNoPeerStorageBucket = errors.New("peer storage bucket does not " +
+		"exist")
+
+	// ErrBlobNotFound is returned when we don't store a blob for a peer.
+	ErrBlobNotFound = errors.New("no blob stored for peer")
+)
+
+// Store stores the blobs we hold on behalf of our peers.
+type Store interface {
+	// PutBlob stores the blob of the given peer, replacing any blob we
+	// previously stored for it.
+	PutBlob(peer route.Vertex, blob []byte) error
+
+	// FetchBlob returns the blob of the given peer. Returns
+	// ErrBlobNotFound if we don't store a blob for it.
+	FetchBlob(peer route.Vertex) ([]byte, error)
+
+	// DeleteBlob removes the blob of the given peer from the store.
+	DeleteBlob(peer route.Vertex) error
+
+	// Peers returns all peers we store a blob for.
+	Peers() ([]route.Vertex, error)
+
+	// TotalSize returns the combined size in bytes of all blobs in the
+	// store.
+	TotalSize() (uint64, error)
+}
+
+type peerStorageStore struct {
+	db kvdb.Backend
+}
+
+// NewStore returns a new peer storage store backed by the given database.
+func NewStore(db kvdb.Backend) (Store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(peerStorageBucketKey)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &peerStorageStore{
+		db: db,
+	}, nil
+}
+
+// PutBlob stores the blob of the given peer, replacing any blob we previously
+// stored for it.
+func (s *peerStorageStore) PutBlob(peer route.Vertex, blob []byte) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(peerStorageBucketKey)
+		if bucket == nil {
+			return errNoPeerStorageBucket
+		}
+
+		return bucket.Put(peer[:], blob)
+	}, func() {})
+}
+
+// FetchBlob returns the blob of the given peer.
+func (s *peerStorageStore) FetchBlob(peer route.Vertex) ([]byte, error) {
+	var blob []byte
+
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(peerStorageBucketKey)
+		if bucket == nil {
+			return errNoPeerStorageBucket
+		}
+
+		blobBytes := bucket.Get(peer[:])
+		if blobBytes == nil {
+			return ErrBlobNotFound
+		}
+
+		// The returned bytes are only valid for the lifetime of the
+		// transaction, so we copy them.
+		blob = make([]byte, len(blobBytes))
+		copy(blob, blobBytes)
+
+		return nil
+	}, func() {
+		blob = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// DeleteBlob removes the blob of the given peer from the store.
+func (s *peerStorageStore) DeleteBlob(peer route.Vertex) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(peerStorageBucketKey)
+		if bucket == nil {
+			return errNoPeerStorageBucket
+		}
+
+		return bucket.Delete(peer[:])
+	}, func() {})
+}
+
+// Peers returns all peers we store a blob for.
+func (s *peerStorageStore) Peers() ([]route.Vertex, error) {
+	var peers []route.Vertex
+
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(peerStorageBucketKey)
+		if bucket == nil {
+			return errNoPeerStorageBucket
+		}
+
+		return bucket.ForEach(func(k, _ []byte) error {
+			peer, err := route.NewVertexFromBytes(k)
+			if err != nil {
+				return err
+			}
+			peers = append(peers, peer)
+
+			return nil
+		})
+	}, func() {
+		peers = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
+
+// TotalSize returns the combined size in bytes of all blobs in the store.
+func (s *peerStorageStore) TotalSize() (uint64, error) {
+	var total uint64
+
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(peerStorageBucketKey)
+		if bucket == nil {
+			return errNoPeerStorageBucket
+		}
+
+		return bucket.ForEach(func(_, blob []byte) error {
+			total += uint64(len(blob))
+			return nil
+		})
+	}, func() {
+		total = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
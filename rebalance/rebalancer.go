@@ -0,0 +1,408 @@
+package rebalance
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// PaymentLabel is the label of the circular payments of rebalances.
+	// It distinguishes them from regular payments in the payment history.
+	PaymentLabel = "rebalance"
+
+	// DefaultInterval is the default time between two rebalances of the
+	// autonomous rebalancer.
+	DefaultInterval = 10 * time.Minute
+
+	// DefaultFeeRatio is the default fraction of the outbound fee rate of
+	// the incoming channel that a rebalance may spend on fees.
+	DefaultFeeRatio = 0.5
+
+	// DefaultMaxAmount is the default maximum amount of a rebalance of the
+	// autonomous rebalancer.
+	DefaultMaxAmount = lnwire.MilliSatoshi(1_000_000_000)
+
+	// DefaultPaymentTimeout is the default time we spend trying to
+	// complete a rebalance.
+	DefaultPaymentTimeout = time.Minute
+
+	// minAutonomousAmount is the smallest amount that the autonomous
+	// rebalancer moves. Smaller deviations from the targets are ignored.
+	minAutonomousAmount = lnwire.MilliSatoshi(10_000_000)
+)
+
+var (
+	// ErrUnknownChannel is returned when a rebalance refers to a channel
+	// that isn't one of our open channels.
+	ErrUnknownChannel = errors.New("unknown channel")
+
+	// ErrNoFeeBudget is returned when the fee budget of a rebalance is
+	// zero.
+	ErrNoFeeBudget = errors.New("rebalance has no fee budget")
+)
+
+// Config holds the dependencies and the targets of the rebalancer.
+type Config struct {
+	// SelfNode is our own node, which is the destination of the circular
+	// payments.
+	SelfNode route.Vertex
+
+	// FetchChannels returns our open channels.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// FetchFeeRate returns the proportional fee in parts per million that
+	// we charge for forwarding over the given channel.
+	FetchFeeRate func(chanID uint64) (uint32, error)
+
+	// AddInvoice adds an invoice that the circular payment pays.
+	AddInvoice func(amt lnwire.MilliSatoshi, memo string) (lntypes.Hash,
+		*invoices.Invoice, error)
+
+	// SendPayment sends a payment and blocks until it completes.
+	SendPayment func(*routing.LightningPayment) ([32]byte, *route.Route,
+		error)
+
+	// Store persists the history of rebalances.
+	Store *Store
+
+	// FeeRatio is the fraction of the outbound fee rate of the incoming
+	// channel that a rebalance may spend on fees, if the request doesn't
+	// set a fee limit. Moving liquidity into a channel is only worth it if
+	// the fees we pay are covered by the fees that we earn when the
+	// liquidity is forwarded again.
+	FeeRatio float64
+
+	// CltvLimit is the maximum time lock of the circular routes.
+	CltvLimit uint32
+
+	// PaymentTimeout is the time we spend trying to complete a rebalance.
+	PaymentTimeout time.Duration
+
+	// Targets maps channels to their target ratio of local balance to
+	// capacity. The autonomous rebalancer moves liquidity between these
+	// channels to approach their targets. If empty, the autonomous
+	// rebalancer doesn't run.
+	Targets map[uint64]float64
+
+	// Interval is the time between two rebalances of the autonomous
+	// rebalancer.
+	Interval time.Duration
+
+	// MaxAmount is the maximum amount of a rebalance of the autonomous
+	// rebalancer.
+	MaxAmount lnwire.MilliSatoshi
+}
+
+// Request describes a rebalance.
+type Request struct {
+	// OutgoingChanID is the channel that liquidity is moved out of.
+	OutgoingChanID uint64
+
+	// IncomingChanID is the channel that liquidity is moved into.
+	IncomingChanID uint64
+
+	// Amount is the amount to move.
+	Amount lnwire.MilliSatoshi
+
+	// MaxFeePPM is the maximum fee in parts per million of the amount. If
+	// zero, the fee ratio of the config is applied to the outbound fee
+	// rate of the incoming channel.
+	MaxFeePPM uint64
+}
+
+// Rebalancer moves liquidity between our channels by paying ourselves in a
+// circle. Rebalances are requested explicitly or started periodically by the
+// autonomous rebalancer to approach the target balances of the channels.
+type Rebalancer struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new rebalancer.
+func New(cfg *Config) *Rebalancer {
+	return &Rebalancer{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts the autonomous rebalancer if any targets are configured.
+func (r *Rebalancer) Start() error {
+	r.started.Do(func() {
+		log.Info("Rebalancer starting")
+
+		if len(r.cfg.Targets) == 0 {
+			return
+		}
+
+		r.wg.Add(1)
+		go r.rebalanceLoop()
+	})
+
+	return nil
+}
+
+// Stop stops the rebalancer and waits for the autonomous rebalancer to
+// complete its current rebalance.
+func (r *Rebalancer) Stop() error {
+	r.stopped.Do(func() {
+		log.Info("Rebalancer shutting down...")
+		defer log.Debug("Rebalancer shutdown complete")
+
+		close(r.quit)
+		r.wg.Wait()
+	})
+
+	return nil
+}
+
+// History returns the history of rebalances, oldest first.
+func (r *Rebalancer) History() ([]*Record, error) {
+	return r.cfg.Store.FetchRecords()
+}
+
+// Rebalance moves liquidity from the outgoing to the incoming channel by
+// paying ourselves over a circular route. It blocks until the payment
+// completes and returns the record of the rebalance, which is also added to
+// the history.
+func (r *Rebalancer) Rebalance(req *Request) (*Record, error) {
+	return r.rebalance(req, false)
+}
+
+// rebalance executes the rebalance and records its outcome.
+func (r *Rebalancer) rebalance(req *Request, autonomous bool) (*Record,
+	error) {
+
+	if req.Amount == 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	if req.OutgoingChanID == req.IncomingChanID {
+		return nil, errors.New("outgoing and incoming channel must " +
+			"differ")
+	}
+
+	channels, err := r.fetchChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := channels[req.OutgoingChanID]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownChannel,
+			req.OutgoingChanID)
+	}
+
+	incoming, ok := channels[req.IncomingChanID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownChannel,
+			req.IncomingChanID)
+	}
+
+	feeLimit, err := r.feeLimit(req)
+	if err != nil {
+		return nil, err
+	}
+
+	memo := fmt.Sprintf("rebalance %v -> %v", req.OutgoingChanID,
+		req.IncomingChanID)
+	hash, invoice, err := r.cfg.AddInvoice(req.Amount, memo)
+	if err != nil {
+		return nil, fmt.Errorf("unable to add invoice: %w", err)
+	}
+
+	// The route has to leave over the outgoing channel and come back over
+	// the incoming channel, so the peer of the incoming channel is the
+	// last hop.
+	lastHop := route.NewVertex(incoming.IdentityPub)
+	payment := &routing.LightningPayment{
+		Target:             r.cfg.SelfNode,
+		Amount:             req.Amount,
+		FeeLimit:           feeLimit,
+		CltvLimit:          r.cfg.CltvLimit,
+		FinalCLTVDelta:     uint16(invoice.Terms.FinalCltvDelta),
+		PayAttemptTimeout:  r.cfg.PaymentTimeout,
+		OutgoingChannelIDs: []uint64{req.OutgoingChanID},
+		LastHop:            &lastHop,
+		DestFeatures:       invoice.Terms.Features,
+		PaymentAddr:        fn.Some(invoice.Terms.PaymentAddr),
+		PaymentRequest:     invoice.PaymentRequest,
+		Label:              PaymentLabel,
+		MaxParts:           1,
+	}
+	if err := payment.SetPaymentHash(hash); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Rebalancing %v from channel %v to %v, fee limit %v",
+		req.Amount, req.OutgoingChanID, req.IncomingChanID, feeLimit)
+
+	record := &Record{
+		OutgoingChanID: req.OutgoingChanID,
+		IncomingChanID: req.IncomingChanID,
+		Amount:         req.Amount,
+		PaymentHash:    hash,
+		Autonomous:     autonomous,
+	}
+
+	_, rt, payErr := r.cfg.SendPayment(payment)
+	if payErr == nil {
+		record.Succeeded = true
+		record.Fee = rt.TotalFees()
+	}
+	record.Time = time.Now()
+
+	if err := r.cfg.Store.AddRecord(record); err != nil {
+		log.Errorf("Unable to record rebalance %v: %v", hash, err)
+	}
+
+	if payErr != nil {
+		return record, fmt.Errorf("rebalance failed: %w", payErr)
+	}
+
+	log.Infof("Rebalanced %v from channel %v to %v for a fee of %v",
+		req.Amount, req.OutgoingChanID, req.IncomingChanID, record.Fee)
+
+	return record, nil
+}
+
+// fetchChannels returns our open channels by their short channel ID.
+func (r *Rebalancer) fetchChannels() (map[uint64]*channeldb.OpenChannel,
+	error) {
+
+	channels, err := r.cfg.FetchChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[uint64]*channeldb.OpenChannel, len(channels))
+	for _, channel := range channels {
+		index[channel.ShortChannelID.ToUint64()] = channel
+	}
+
+	return index, nil
+}
+
+// feeLimit returns the maximum fee of the rebalance.
+func (r *Rebalancer) feeLimit(req *Request) (lnwire.MilliSatoshi, error) {
+	feePPM := req.MaxFeePPM
+	if feePPM == 0 {
+		feeRate, err := r.cfg.FetchFeeRate(req.IncomingChanID)
+		if err != nil {
+			return 0, fmt.Errorf("unable to fetch fee rate of "+
+				"channel %v: %w", req.IncomingChanID, err)
+		}
+
+		feePPM = uint64(float64(feeRate) * r.cfg.FeeRatio)
+	}
+
+	feeLimit := req.Amount * lnwire.MilliSatoshi(feePPM) / 1_000_000
+	if feeLimit == 0 {
+		return 0, ErrNoFeeBudget
+	}
+
+	return feeLimit, nil
+}
+
+// rebalanceLoop starts a rebalance towards the targets on every tick of the
+// interval.
+func (r *Rebalancer) rebalanceLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			req, err := r.nextRequest()
+			if err != nil {
+				log.Errorf("Unable to select rebalance: %v",
+					err)
+				continue
+			}
+			if req == nil {
+				continue
+			}
+
+			_, err = r.rebalance(req, true)
+			if err != nil {
+				log.Warnf("Autonomous rebalance failed: %v",
+					err)
+			}
+
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// nextRequest selects the next rebalance of the autonomous rebalancer. It
+// moves liquidity from the channel with the largest surplus over its target
+// to the channel with the largest deficit. It returns nil if no channel
+// deviates enough from its target.
+func (r *Rebalancer) nextRequest() (*Request, error) {
+	channels, err := r.fetchChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		source, sink           uint64
+		maxSurplus, maxDeficit lnwire.MilliSatoshi
+		foundSource, foundSink bool
+	)
+	for chanID, ratio := range r.cfg.Targets {
+		channel, ok := channels[chanID]
+		if !ok {
+			continue
+		}
+
+		var (
+			capacity = lnwire.NewMSatFromSatoshis(channel.Capacity)
+			local    = channel.LocalCommitment.LocalBalance
+			target   = lnwire.MilliSatoshi(
+				float64(capacity) * ratio,
+			)
+		)
+
+		switch {
+		case local > target && local-target > maxSurplus:
+			source, maxSurplus, foundSource = chanID, local-target,
+				true
+
+		case target > local && target-local > maxDeficit:
+			sink, maxDeficit, foundSink = chanID, target-local,
+				true
+		}
+	}
+
+	if !foundSource || !foundSink {
+		return nil, nil
+	}
+
+	amt := min(maxSurplus, maxDeficit, r.cfg.MaxAmount)
+	if amt < minAutonomousAmount {
+		return nil, nil
+	}
+
+	return &Request{
+		OutgoingChanID: source,
+		IncomingChanID: sink,
+		Amount:         amt,
+	}, nil
+}
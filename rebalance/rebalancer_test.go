@@ -0,0 +1,197 @@
+package rebalance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestChannel returns an open channel with the given short channel ID,
+// capacity and local balance.
+func newTestChannel(t *testing.T, chanID uint64, capacity btcutil.Amount,
+	local lnwire.MilliSatoshi) *channeldb.OpenChannel {
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &channeldb.OpenChannel{
+		ShortChannelID: lnwire.NewShortChanIDFromInt(chanID),
+		IdentityPub:    key.PubKey(),
+		Capacity:       capacity,
+		LocalCommitment: channeldb.ChannelCommitment{
+			LocalBalance: local,
+		},
+	}
+}
+
+// TestRebalance tests that a rebalance pays ourselves over the requested
+// channels within the fee budget and records the outcome.
+func TestRebalance(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	var (
+		self     = route.Vertex{1}
+		outgoing = newTestChannel(t, 1, 1_000_000, 900_000_000)
+		incoming = newTestChannel(t, 2, 1_000_000, 100_000_000)
+		hash     = lntypes.Hash{3}
+		amt      = lnwire.MilliSatoshi(100_000_000)
+		payErr   error
+	)
+
+	rebalancer := New(&Config{
+		SelfNode: self,
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return []*channeldb.OpenChannel{outgoing, incoming}, nil
+		},
+		FetchFeeRate: func(chanID uint64) (uint32, error) {
+			require.EqualValues(t, 2, chanID)
+
+			return 1000, nil
+		},
+		AddInvoice: func(value lnwire.MilliSatoshi, _ string) (
+			lntypes.Hash, *invoices.Invoice, error) {
+
+			require.Equal(t, amt, value)
+
+			return hash, &invoices.Invoice{
+				Terms: invoices.ContractTerm{
+					FinalCltvDelta: 40,
+					PaymentAddr:    [32]byte{4},
+				},
+			}, nil
+		},
+		SendPayment: func(payment *routing.LightningPayment) ([32]byte,
+			*route.Route, error) {
+
+			lastHop := route.NewVertex(incoming.IdentityPub)
+
+			require.Equal(t, self, payment.Target)
+			require.Equal(t, amt, payment.Amount)
+			require.Equal(
+				t, []uint64{1}, payment.OutgoingChannelIDs,
+			)
+			require.Equal(t, &lastHop, payment.LastHop)
+			require.Equal(t, PaymentLabel, payment.Label)
+
+			// Half of the outbound fee rate of the incoming
+			// channel may be spent.
+			require.EqualValues(t, 50_000, payment.FeeLimit)
+
+			if payErr != nil {
+				return [32]byte{}, nil, payErr
+			}
+
+			return [32]byte{}, &route.Route{
+				TotalAmount: amt + 20_000,
+				Hops: []*route.Hop{{
+					AmtToForward: amt,
+				}},
+			}, nil
+		},
+		Store:    store,
+		FeeRatio: DefaultFeeRatio,
+	})
+
+	record, err := rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         amt,
+	})
+	require.NoError(t, err)
+	require.True(t, record.Succeeded)
+	require.EqualValues(t, 20_000, record.Fee)
+
+	// A failed rebalance is recorded as well.
+	payErr = errors.New("no route")
+	_, err = rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         amt,
+	})
+	require.ErrorIs(t, err, payErr)
+
+	history, err := rebalancer.History()
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.True(t, history[0].Succeeded)
+	require.False(t, history[1].Succeeded)
+	require.Zero(t, history[1].Fee)
+
+	// Unknown channels are rejected.
+	_, err = rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 3,
+		Amount:         amt,
+	})
+	require.ErrorIs(t, err, ErrUnknownChannel)
+
+	// A fee limit that rounds to zero is rejected.
+	_, err = rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         1000,
+		MaxFeePPM:      1,
+	})
+	require.ErrorIs(t, err, ErrNoFeeBudget)
+}
+
+// TestNextRequest tests that the autonomous rebalancer moves liquidity from
+// the channel with the largest surplus to the channel with the largest
+// deficit.
+func TestNextRequest(t *testing.T) {
+	t.Parallel()
+
+	channels := []*channeldb.OpenChannel{
+		newTestChannel(t, 1, 1_000_000, 600_000_000),
+		newTestChannel(t, 2, 1_000_000, 900_000_000),
+		newTestChannel(t, 3, 1_000_000, 200_000_000),
+		newTestChannel(t, 4, 1_000_000, 0),
+	}
+
+	cfg := &Config{
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return channels, nil
+		},
+		Targets: map[uint64]float64{
+			1: 0.5,
+			2: 0.5,
+			3: 0.5,
+		},
+		MaxAmount: 250_000_000,
+	}
+	rebalancer := New(cfg)
+
+	// Channel 4 isn't managed, so channel 3 has the largest deficit. The
+	// amount is capped by the maximum.
+	req, err := rebalancer.nextRequest()
+	require.NoError(t, err)
+	require.Equal(t, &Request{
+		OutgoingChanID: 2,
+		IncomingChanID: 3,
+		Amount:         250_000_000,
+	}, req)
+
+	// Deviations below the minimum amount are ignored.
+	cfg.Targets = map[uint64]float64{
+		1: 0.595,
+		3: 0.205,
+	}
+	req, err = rebalancer.nextRequest()
+	require.NoError(t, err)
+	require.Nil(t, req)
+}
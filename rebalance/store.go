@@ -0,0 +1,210 @@
+package rebalance
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// historyBucketKey is the key of the bucket that holds the history of
+	// rebalances.
+	//
+	// maps: sequence number -> Record
+	historyBucketKey = []byte("rebalance-history")
+
+	byteOrder = binary.BigEndian
+
+	errNoHistoryBucket = errors.New("rebalance history bucket does not " +
+		"exist")
+)
+
+// Record is the outcome of a rebalance, as stored in the history.
+type Record struct {
+	// Time is the time at which the rebalance completed.
+	Time time.Time
+
+	// OutgoingChanID is the channel that liquidity was moved out of.
+	OutgoingChanID uint64
+
+	// IncomingChanID is the channel that liquidity was moved into.
+	IncomingChanID uint64
+
+	// Amount is the amount that was moved, excluding fees.
+	Amount lnwire.MilliSatoshi
+
+	// Fee is the routing fee that was paid.
+	Fee lnwire.MilliSatoshi
+
+	// PaymentHash is the hash of the circular payment.
+	PaymentHash lntypes.Hash
+
+	// Succeeded indicates whether the rebalance succeeded. Failed
+	// rebalances don't move liquidity and don't pay fees.
+	Succeeded bool
+
+	// Autonomous indicates whether the rebalance was started by the
+	// autonomous rebalancer rather than by a user request.
+	Autonomous bool
+}
+
+// toTlvStream converts the record into a tlv representation.
+func (r *Record) toTlvStream(timestamp *uint64, amt, fee *uint64,
+	hash *[32]byte) (*tlv.Stream, error) {
+
+	const (
+		// NOTE: A migration should be added whenever the existing type
+		// changes.
+		timeType           tlv.Type = 0
+		outgoingChanIDType tlv.Type = 1
+		incomingChanIDType tlv.Type = 2
+		amountType         tlv.Type = 3
+		feeType            tlv.Type = 4
+		paymentHashType    tlv.Type = 5
+		succeededType      tlv.Type = 6
+		autonomousType     tlv.Type = 7
+	)
+
+	return tlv.NewStream(
+		tlv.MakePrimitiveRecord(timeType, timestamp),
+		tlv.MakePrimitiveRecord(outgoingChanIDType, &r.OutgoingChanID),
+		tlv.MakePrimitiveRecord(incomingChanIDType, &r.IncomingChanID),
+		tlv.MakeBigSizeRecord(amountType, amt),
+		tlv.MakeBigSizeRecord(feeType, fee),
+		tlv.MakePrimitiveRecord(paymentHashType, hash),
+		tlv.MakePrimitiveRecord(succeededType, &r.Succeeded),
+		tlv.MakePrimitiveRecord(autonomousType, &r.Autonomous),
+	)
+}
+
+// encode serializes the record.
+func (r *Record) encode() ([]byte, error) {
+	var (
+		timestamp = uint64(r.Time.UnixNano())
+		amt       = uint64(r.Amount)
+		fee       = uint64(r.Fee)
+		hash      = [32]byte(r.PaymentHash)
+	)
+
+	stream, err := r.toTlvStream(&timestamp, &amt, &fee, &hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeRecord deserializes a record.
+func decodeRecord(b []byte) (*Record, error) {
+	var (
+		r              Record
+		timestamp, amt uint64
+		fee            uint64
+		hash           [32]byte
+	)
+
+	stream, err := r.toTlvStream(&timestamp, &amt, &fee, &hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Decode(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	r.Time = time.Unix(0, int64(timestamp))
+	r.Amount = lnwire.MilliSatoshi(amt)
+	r.Fee = lnwire.MilliSatoshi(fee)
+	r.PaymentHash = hash
+
+	return &r, nil
+}
+
+// Store persists the history of rebalances.
+type Store struct {
+	db kvdb.Backend
+}
+
+// NewStore returns a new store instance.
+func NewStore(db kvdb.Backend) (*Store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		if tx.ReadWriteBucket(historyBucketKey) != nil {
+			return nil
+		}
+
+		_, err := tx.CreateTopLevelBucket(historyBucketKey)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		db: db,
+	}, nil
+}
+
+// AddRecord appends the record to the history.
+func (s *Store) AddRecord(r *Record) error {
+	value, err := r.encode()
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(historyBucketKey)
+		if bucket == nil {
+			return errNoHistoryBucket
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var key [8]byte
+		byteOrder.PutUint64(key[:], seq)
+
+		return bucket.Put(key[:], value)
+	}, func() {})
+}
+
+// FetchRecords returns the history of rebalances, oldest first.
+func (s *Store) FetchRecords() ([]*Record, error) {
+	var records []*Record
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(historyBucketKey)
+		if bucket == nil {
+			return errNoHistoryBucket
+		}
+
+		return bucket.ForEach(func(_, v []byte) error {
+			r, err := decodeRecord(v)
+			if err != nil {
+				return err
+			}
+			records = append(records, r)
+
+			return nil
+		})
+	}, func() {
+		records = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
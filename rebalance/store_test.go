@@ -0,0 +1,54 @@
+package rebalance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStore tests that records are persisted and returned in the order in
+// which they were added.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	records, err := store.FetchRecords()
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	first := &Record{
+		Time:           time.Unix(0, 1000),
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         5000,
+		Fee:            3,
+		PaymentHash:    lntypes.Hash{1},
+		Succeeded:      true,
+	}
+	second := &Record{
+		Time:           time.Unix(0, 2000),
+		OutgoingChanID: 2,
+		IncomingChanID: 1,
+		Amount:         7000,
+		PaymentHash:    lntypes.Hash{2},
+		Autonomous:     true,
+	}
+	require.NoError(t, store.AddRecord(first))
+	require.NoError(t, store.AddRecord(second))
+
+	// The records survive reopening the store.
+	store, err = NewStore(db)
+	require.NoError(t, err)
+
+	records, err = store.FetchRecords()
+	require.NoError(t, err)
+	require.Equal(t, []*Record{first, second}, records)
+}
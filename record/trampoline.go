@@ -0,0 +1,52 @@
+package record
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// TrampolineOnionType is the type used in the outer onion payload of
+	// the final hop to carry the nested trampoline onion packet.
+	TrampolineOnionType tlv.Type = 20
+
+	// OutgoingNodeIDOnionType is the type used in a trampoline onion
+	// payload to reference the node the trampoline node should forward
+	// the payment to.
+	OutgoingNodeIDOnionType tlv.Type = 14
+
+	// InvoiceFeaturesOnionType is the type used in a trampoline onion
+	// payload to carry the invoice features of a recipient that doesn't
+	// support trampoline routing itself.
+	InvoiceFeaturesOnionType tlv.Type = 66097
+)
+
+// NewTrampolineOnionRecord creates a tlv.Record that encodes the
+// trampoline_onion_packet (type 20) for an onion payload.
+func NewTrampolineOnionRecord(onion *[]byte) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		TrampolineOnionType, onion,
+		func() uint64 {
+			return uint64(len(*onion))
+		},
+		tlv.EVarBytes, tlv.DVarBytes,
+	)
+}
+
+// NewOutgoingNodeIDRecord creates a tlv.Record that encodes the
+// outgoing_node_id (type 14) for a trampoline onion payload.
+func NewOutgoingNodeIDRecord(pubKey **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(OutgoingNodeIDOnionType, pubKey)
+}
+
+// NewInvoiceFeaturesRecord creates a tlv.Record that encodes the raw
+// invoice_features (type 66097) for a trampoline onion payload.
+func NewInvoiceFeaturesRecord(features *[]byte) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		InvoiceFeaturesOnionType, features,
+		func() uint64 {
+			return uint64(len(*features))
+		},
+		tlv.EVarBytes, tlv.DVarBytes,
+	)
+}
@@ -0,0 +1,50 @@
+package reputation
+
+import (
+	"math"
+	"time"
+)
+
+// decayingAverage is a value that decays over time, so that recent additions
+// weigh more than older ones. The value halves every half of its window, so
+// contributions that are older than the window have mostly been forgotten.
+type decayingAverage struct {
+	// value is the value of the average at lastUpdate.
+	value float64
+
+	// lastUpdate is the time the value was last updated.
+	lastUpdate time.Time
+
+	// window is the period over which the average is tracked.
+	window time.Duration
+}
+
+// newDecayingAverage creates an empty average tracked over the given window.
+func newDecayingAverage(window time.Duration) *decayingAverage {
+	return &decayingAverage{
+		window: window,
+	}
+}
+
+// valueAt returns the value of the average at the given time. A time before
+// the last update returns the value at the last update.
+func (d *decayingAverage) valueAt(now time.Time) float64 {
+	if d.lastUpdate.IsZero() || !now.After(d.lastUpdate) {
+		return d.value
+	}
+
+	elapsed := now.Sub(d.lastUpdate).Seconds()
+	halfLife := d.window.Seconds() / 2
+
+	return d.value * math.Pow(0.5, elapsed/halfLife)
+}
+
+// add adds the given value, which may be negative, to the average at the
+// given time.
+func (d *decayingAverage) add(value float64, now time.Time) {
+	d.value = d.valueAt(now) + value
+
+	if now.After(d.lastUpdate) {
+		d.lastUpdate = now
+	}
+}
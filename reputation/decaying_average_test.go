@@ -0,0 +1,42 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecayingAverage tests that the decaying average halves every half of
+// its window, and that additions are applied to the decayed value.
+func TestDecayingAverage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		window = time.Hour
+		start  = time.Unix(1000, 0)
+		avg    = newDecayingAverage(window)
+	)
+
+	require.Zero(t, avg.valueAt(start))
+
+	avg.add(1000, start)
+	require.Equal(t, 1000.0, avg.valueAt(start))
+
+	// After half of the window, the value is halved.
+	halfWindow := start.Add(window / 2)
+	require.InDelta(t, 500, avg.valueAt(halfWindow), 0.001)
+
+	// After the full window, it is halved again.
+	require.InDelta(t, 250, avg.valueAt(start.Add(window)), 0.001)
+
+	// Times before the last update return the value at the last update.
+	require.Equal(t, 1000.0, avg.valueAt(start.Add(-time.Minute)))
+
+	// Additions are applied to the decayed value, and may be negative.
+	avg.add(-100, halfWindow)
+	require.InDelta(t, 400, avg.valueAt(halfWindow), 0.001)
+	require.InDelta(
+		t, 200, avg.valueAt(halfWindow.Add(window/2)), 0.001,
+	)
+}
@@ -0,0 +1,630 @@
+// Package reputation protects the htlc slots and liquidity of our channels
+// against jamming attacks. It tracks the reputation of the peers that forward
+// htlcs to us, built from the fees their htlcs earned us and the time it took
+// to resolve them, and reserves part of the resources of every outgoing
+// channel for endorsed htlcs from peers that have a good reputation.
+//
+// Reputation: the fees that the htlcs a peer forwarded over an incoming channel
+// earned us, minus the opportunity cost of endorsed htlcs that were held for
+// too long. This is tracked as a decaying average over the reputation window.
+//
+// Revenue: the fees that the htlcs forwarded over an outgoing channel earned
+// us, tracked as a decaying average over the revenue window. An incoming
+// channel is reputable for an outgoing channel if its reputation exceeds the
+// revenue of the outgoing channel plus the risk of its in-flight htlcs.
+package reputation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+const (
+	// DefaultRevenueWindow is the default period over which the revenue of
+	// an outgoing channel is tracked.
+	DefaultRevenueWindow = 14 * 24 * time.Hour
+
+	// DefaultReputationMultiplier is the default number of revenue windows
+	// over which the reputation of an incoming channel is tracked.
+	DefaultReputationMultiplier = 12
+
+	// DefaultResolutionPeriod is the default time within which we expect
+	// htlcs to be resolved. Endorsed htlcs that take longer than this are
+	// charged an opportunity cost.
+	DefaultResolutionPeriod = 90 * time.Second
+
+	// DefaultProtectedPercentage is the default percentage of the htlc
+	// slots and liquidity of a channel that is reserved for endorsed htlcs
+	// from reputable peers.
+	DefaultProtectedPercentage = 50
+
+	// blockTime is the expected time between blocks, used to estimate the
+	// longest time an htlc may be held.
+	blockTime = 10 * time.Minute
+)
+
+var (
+	// ErrChannelNotFound is returned when a report is requested for a
+	// channel that the manager has not seen any htlcs for.
+	ErrChannelNotFound = errors.New("channel not found in reputation " +
+		"manager")
+)
+
+// Config holds the configuration of the reputation manager.
+type Config struct {
+	// RevenueWindow is the period over which the revenue of an outgoing
+	// channel is tracked.
+	RevenueWindow time.Duration
+
+	// ReputationMultiplier is the number of revenue windows over which the
+	// reputation of an incoming channel is tracked.
+	ReputationMultiplier uint32
+
+	// ResolutionPeriod is the time within which we expect htlcs to be
+	// resolved.
+	ResolutionPeriod time.Duration
+
+	// ProtectedPercentage is the percentage of the htlc slots and
+	// liquidity of a channel that is reserved for endorsed htlcs from
+	// reputable peers.
+	ProtectedPercentage uint8
+
+	// SubscribeHtlcEvents provides a subscription to htlc events, which is
+	// used to learn when forwarded htlcs are resolved.
+	SubscribeHtlcEvents func() (subscribe.Subscription, error)
+
+	// Clock is the time source of the manager.
+	Clock clock.Clock
+}
+
+// validate checks that the configuration is sane.
+func (c *Config) validate() error {
+	switch {
+	case c.RevenueWindow <= 0:
+		return errors.New("revenue window must be positive")
+
+	case c.ReputationMultiplier == 0:
+		return errors.New("reputation multiplier must be positive")
+
+	case c.ResolutionPeriod <= 0:
+		return errors.New("resolution period must be positive")
+
+	case c.ProtectedPercentage > 100:
+		return fmt.Errorf("protected percentage %v exceeds 100",
+			c.ProtectedPercentage)
+
+	case c.SubscribeHtlcEvents == nil:
+		return errors.New("htlc event subscription required")
+
+	case c.Clock == nil:
+		return errors.New("clock required")
+	}
+
+	return nil
+}
+
+// bucket tracks the resources of a channel that are in use by htlcs.
+type bucket struct {
+	// slots is the number of htlcs in the bucket.
+	slots uint16
+
+	// liquidity is the total amount of the htlcs in the bucket.
+	liquidity lnwire.MilliSatoshi
+}
+
+// channelState is the state the manager tracks for a single channel, both in
+// its role as incoming and as outgoing channel.
+type channelState struct {
+	// reputation is the reputation of the channel as incoming channel.
+	reputation *decayingAverage
+
+	// revenue is the revenue of the channel as outgoing channel.
+	revenue *decayingAverage
+
+	// inFlightRisk is the total risk of the unresolved htlcs that were
+	// forwarded to us over the channel and that we endorsed.
+	inFlightRisk float64
+
+	// limits are the limits the remote peer last imposed on the htlcs we
+	// offer on the channel.
+	limits htlcswitch.ChannelLimits
+
+	// general holds the htlcs that use the general resources of the
+	// channel as outgoing channel.
+	general bucket
+
+	// protected holds the htlcs that use the resources of the channel
+	// that are reserved for endorsed htlcs from reputable peers.
+	protected bucket
+}
+
+// inFlightHTLC is an htlc that we forwarded and that isn't resolved yet.
+type inFlightHTLC struct {
+	// outgoingChannel is the channel the htlc was forwarded on.
+	outgoingChannel lnwire.ShortChannelID
+
+	// amount is the amount of the outgoing htlc.
+	amount lnwire.MilliSatoshi
+
+	// fee is the fee the htlc earns us if it is settled.
+	fee lnwire.MilliSatoshi
+
+	// risk is the risk of the htlc, counted towards the in-flight risk of
+	// the incoming channel if we endorsed the htlc.
+	risk float64
+
+	// endorsed is true if we endorsed the outgoing htlc, which means that
+	// the incoming channel is charged for holding it too long.
+	endorsed bool
+
+	// protected is true if the htlc uses the protected resources of the
+	// outgoing channel.
+	protected bool
+
+	// addedAt is the time the htlc was forwarded.
+	addedAt time.Time
+}
+
+// BucketReport describes the usage of a resource bucket of a channel.
+type BucketReport struct {
+	// SlotsUsed is the number of htlc slots in use.
+	SlotsUsed uint16
+
+	// Slots is the number of htlc slots of the bucket.
+	Slots uint16
+
+	// LiquidityUsed is the liquidity in use.
+	LiquidityUsed lnwire.MilliSatoshi
+
+	// Liquidity is the liquidity of the bucket.
+	Liquidity lnwire.MilliSatoshi
+}
+
+// ChannelReport describes the reputation and resource usage of a channel.
+type ChannelReport struct {
+	// ChannelID is the short channel id of the channel.
+	ChannelID lnwire.ShortChannelID
+
+	// ReputationMsat is the reputation of the channel as incoming channel.
+	// This is negative if the opportunity cost of its slow htlcs exceeds
+	// the fees they earned us.
+	ReputationMsat int64
+
+	// RevenueMsat is the revenue of the channel as outgoing channel.
+	RevenueMsat int64
+
+	// InFlightRisk is the total risk of the unresolved htlcs that were
+	// forwarded to us over the channel and that we endorsed.
+	InFlightRisk lnwire.MilliSatoshi
+
+	// General is the usage of the general resources of the channel.
+	General BucketReport
+
+	// Protected is the usage of the protected resources of the channel.
+	Protected BucketReport
+}
+
+// Manager tracks the reputation of our channels and decides which resources
+// forwarded htlcs may use.
+type Manager struct {
+	started atomic.Bool
+	stopped atomic.Bool
+
+	cfg *Config
+
+	// channels holds the state of every channel we have seen htlcs on.
+	channels map[lnwire.ShortChannelID]*channelState
+
+	// inFlight holds the forwarded htlcs that aren't resolved yet, keyed
+	// by their incoming circuit.
+	inFlight map[models.CircuitKey]*inFlightHTLC
+
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile-time check to ensure the Manager implements the ResourceManager
+// interface of the switch.
+var _ htlcswitch.ResourceManager = (*Manager)(nil)
+
+// NewManager creates a new reputation manager. Start must be called before
+// the manager learns about resolved htlcs.
+func NewManager(cfg *Config) (*Manager, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		cfg:      cfg,
+		channels: make(map[lnwire.ShortChannelID]*channelState),
+		inFlight: make(map[models.CircuitKey]*inFlightHTLC),
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to htlc events and starts processing them.
+func (m *Manager) Start() error {
+	log.Info("Reputation manager starting...")
+
+	if m.started.Swap(true) {
+		return fmt.Errorf("reputation manager started more than once")
+	}
+
+	client, err := m.cfg.SubscribeHtlcEvents()
+	if err != nil {
+		return err
+	}
+
+	m.wg.Add(1)
+	go m.consume(client)
+
+	log.Debug("Reputation manager started")
+
+	return nil
+}
+
+// Stop terminates the goroutine that processes htlc events.
+func (m *Manager) Stop() error {
+	log.Info("Reputation manager shutting down...")
+
+	if m.stopped.Swap(true) {
+		return fmt.Errorf("reputation manager stopped more than once")
+	}
+
+	close(m.quit)
+	m.wg.Wait()
+
+	log.Debug("Reputation manager shutdown complete")
+
+	return nil
+}
+
+// consume processes htlc events until the manager is stopped or the
+// subscription is cancelled.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) consume(client subscribe.Subscription) {
+	defer m.wg.Done()
+	defer client.Cancel()
+
+	for {
+		select {
+		case update, ok := <-client.Updates():
+			if !ok {
+				return
+			}
+
+			// Every incoming htlc is eventually resolved with a
+			// final htlc event, either off-chain or on-chain.
+			event, isFinal := update.(*htlcswitch.FinalHtlcEvent)
+			if !isFinal {
+				continue
+			}
+
+			m.resolveHTLC(event.CircuitKey, event.Settled)
+
+		case <-client.Quit():
+			log.Warn("Htlc event subscription cancelled")
+			return
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// channel returns the state of the given channel, creating it if we haven't
+// seen the channel before.
+//
+// NOTE: The caller must hold the mutex.
+func (m *Manager) channel(chanID lnwire.ShortChannelID) *channelState {
+	state, ok := m.channels[chanID]
+	if ok {
+		return state
+	}
+
+	window := m.cfg.RevenueWindow
+	state = &channelState{
+		reputation: newDecayingAverage(
+			window * time.Duration(m.cfg.ReputationMultiplier),
+		),
+		revenue: newDecayingAverage(window),
+	}
+	m.channels[chanID] = state
+
+	return state
+}
+
+// protectedLimits returns the resources of a channel with the given limits
+// that are reserved for endorsed htlcs from reputable peers.
+func (m *Manager) protectedLimits(limits htlcswitch.ChannelLimits) bucket {
+	percentage := uint64(m.cfg.ProtectedPercentage)
+
+	return bucket{
+		slots: uint16(uint64(limits.MaxHTLCs) * percentage / 100),
+		liquidity: limits.MaxInFlight / 100 *
+			lnwire.MilliSatoshi(percentage),
+	}
+}
+
+// generalLimits returns the resources of a channel with the given limits that
+// are available to all htlcs.
+func (m *Manager) generalLimits(limits htlcswitch.ChannelLimits) bucket {
+	protected := m.protectedLimits(limits)
+
+	return bucket{
+		slots:     limits.MaxHTLCs - protected.slots,
+		liquidity: limits.MaxInFlight - protected.liquidity,
+	}
+}
+
+// fits returns whether an htlc of the given amount fits into a bucket with
+// the given usage and limits.
+func fits(used, limit bucket, amt lnwire.MilliSatoshi) bool {
+	return used.slots < limit.slots && used.liquidity+amt <= limit.liquidity
+}
+
+// htlcRisk returns the risk of forwarding an htlc, which is the fee it would
+// earn us for every resolution period that it may be held for.
+func (m *Manager) htlcRisk(htlc *htlcswitch.ProposedHTLC,
+	fee lnwire.MilliSatoshi) float64 {
+
+	var maxHold time.Duration
+	if htlc.IncomingExpiry > htlc.CurrentHeight {
+		blocks := htlc.IncomingExpiry - htlc.CurrentHeight
+		maxHold = time.Duration(blocks) * blockTime
+	}
+
+	periods := math.Max(1, maxHold.Seconds()/
+		m.cfg.ResolutionPeriod.Seconds())
+
+	return float64(fee) * periods
+}
+
+// ForwardHTLC decides which resources of the outgoing channel an htlc may
+// use. Endorsed htlcs from reputable incoming channels may use the protected
+// resources, and are endorsed to the next peer. All other htlcs are limited
+// to the general resources.
+//
+// NOTE: This is part of the htlcswitch.ResourceManager interface.
+func (m *Manager) ForwardHTLC(htlc *htlcswitch.ProposedHTLC,
+	limits htlcswitch.ChannelLimits) (htlcswitch.ForwardOutcome, error) {
+
+	if htlc.OutgoingAmount > htlc.IncomingAmount {
+		return htlcswitch.ForwardNoResources, fmt.Errorf("outgoing "+
+			"amount %v exceeds incoming amount %v",
+			htlc.OutgoingAmount, htlc.IncomingAmount)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.cfg.Clock.Now()
+
+	// A circuit that is forwarded again after a restart replaces its
+	// previous allocation.
+	if _, ok := m.inFlight[htlc.IncomingCircuit]; ok {
+		m.release(htlc.IncomingCircuit)
+	}
+
+	incoming := m.channel(htlc.IncomingCircuit.ChanID)
+	outgoing := m.channel(htlc.OutgoingChannel)
+	outgoing.limits = limits
+
+	fee := htlc.IncomingAmount - htlc.OutgoingAmount
+	risk := m.htlcRisk(htlc, fee)
+
+	reputable := incoming.reputation.valueAt(now) >=
+		outgoing.revenue.valueAt(now)+incoming.inFlightRisk+risk
+
+	inFlight := &inFlightHTLC{
+		outgoingChannel: htlc.OutgoingChannel,
+		amount:          htlc.OutgoingAmount,
+		fee:             fee,
+		risk:            risk,
+		addedAt:         now,
+	}
+
+	generalFits := fits(
+		outgoing.general, m.generalLimits(limits), htlc.OutgoingAmount,
+	)
+
+	outcome := htlcswitch.ForwardUnendorsed
+	switch {
+	// Endorsed htlcs from reputable peers use the protected resources,
+	// and fall back to the general resources if those are exhausted.
+	case htlc.IncomingEndorsed && reputable:
+		outcome = htlcswitch.ForwardEndorsed
+		inFlight.endorsed = true
+
+		protectedFits := fits(
+			outgoing.protected, m.protectedLimits(limits),
+			htlc.OutgoingAmount,
+		)
+		if protectedFits {
+			inFlight.protected = true
+			break
+		}
+
+		if !generalFits {
+			return htlcswitch.ForwardNoResources, nil
+		}
+
+	case !generalFits:
+		return htlcswitch.ForwardNoResources, nil
+	}
+
+	if inFlight.protected {
+		outgoing.protected.slots++
+		outgoing.protected.liquidity += htlc.OutgoingAmount
+	} else {
+		outgoing.general.slots++
+		outgoing.general.liquidity += htlc.OutgoingAmount
+	}
+
+	if inFlight.endorsed {
+		incoming.inFlightRisk += risk
+	}
+
+	m.inFlight[htlc.IncomingCircuit] = inFlight
+
+	log.Debugf("Forwarding htlc %v to %v: outcome=%v, reputable=%v, "+
+		"protected=%v", htlc.IncomingCircuit, htlc.OutgoingChannel,
+		outcome, reputable, inFlight.protected)
+
+	return outcome, nil
+}
+
+// release frees the resources held by the given in-flight htlc, and returns
+// the htlc.
+//
+// NOTE: The caller must hold the mutex.
+func (m *Manager) release(key models.CircuitKey) (*inFlightHTLC, bool) {
+	htlc, ok := m.inFlight[key]
+	if !ok {
+		return nil, false
+	}
+	delete(m.inFlight, key)
+
+	outgoing := m.channel(htlc.outgoingChannel)
+	used := &outgoing.general
+	if htlc.protected {
+		used = &outgoing.protected
+	}
+	used.slots--
+	used.liquidity -= htlc.amount
+
+	if htlc.endorsed {
+		incoming := m.channel(key.ChanID)
+		incoming.inFlightRisk = math.Max(
+			0, incoming.inFlightRisk-htlc.risk,
+		)
+	}
+
+	return htlc, true
+}
+
+// resolveHTLC releases the resources of a resolved htlc, and updates the
+// reputation of its incoming channel and the revenue of its outgoing channel.
+func (m *Manager) resolveHTLC(key models.CircuitKey, settled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	htlc, ok := m.release(key)
+	if !ok {
+		return
+	}
+
+	now := m.cfg.Clock.Now()
+	holdTime := now.Sub(htlc.addedAt)
+
+	// Htlcs that are held for longer than the resolution period cost us
+	// the fees we could have earned with the resources they held.
+	period := m.cfg.ResolutionPeriod
+	var opportunityCost float64
+	if holdTime > period {
+		periods := math.Ceil(float64(holdTime-period) / float64(period))
+		opportunityCost = periods * float64(htlc.fee)
+	}
+
+	// Endorsed htlcs are charged the opportunity cost, while unendorsed
+	// htlcs only build reputation if they are settled in time.
+	var effectiveFees float64
+	switch {
+	case htlc.endorsed && settled:
+		effectiveFees = float64(htlc.fee) - opportunityCost
+
+	case htlc.endorsed:
+		effectiveFees = -opportunityCost
+
+	case settled && opportunityCost == 0:
+		effectiveFees = float64(htlc.fee)
+	}
+
+	m.channel(key.ChanID).reputation.add(effectiveFees, now)
+
+	if settled {
+		outgoing := m.channel(htlc.outgoingChannel)
+		outgoing.revenue.add(float64(htlc.fee), now)
+	}
+
+	log.Debugf("Resolved htlc %v (settled=%v) after %v, effective "+
+		"fees: %v msat", key, settled, holdTime, effectiveFees)
+}
+
+// report creates the report of a channel.
+//
+// NOTE: The caller must hold the mutex.
+func (m *Manager) report(chanID lnwire.ShortChannelID,
+	state *channelState, now time.Time) *ChannelReport {
+
+	general := m.generalLimits(state.limits)
+	protected := m.protectedLimits(state.limits)
+
+	return &ChannelReport{
+		ChannelID:      chanID,
+		ReputationMsat: int64(state.reputation.valueAt(now)),
+		RevenueMsat:    int64(state.revenue.valueAt(now)),
+		InFlightRisk:   lnwire.MilliSatoshi(state.inFlightRisk),
+		General: BucketReport{
+			SlotsUsed:     state.general.slots,
+			Slots:         general.slots,
+			LiquidityUsed: state.general.liquidity,
+			Liquidity:     general.liquidity,
+		},
+		Protected: BucketReport{
+			SlotsUsed:     state.protected.slots,
+			Slots:         protected.slots,
+			LiquidityUsed: state.protected.liquidity,
+			Liquidity:     protected.liquidity,
+		},
+	}
+}
+
+// ChannelReport returns the report of the given channel.
+func (m *Manager) ChannelReport(
+	chanID lnwire.ShortChannelID) (*ChannelReport, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.channels[chanID]
+	if !ok {
+		return nil, ErrChannelNotFound
+	}
+
+	return m.report(chanID, state, m.cfg.Clock.Now()), nil
+}
+
+// ChannelReports returns the reports of all channels the manager has seen
+// htlcs on, ordered by channel id.
+func (m *Manager) ChannelReports() []*ChannelReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.cfg.Clock.Now()
+	reports := make([]*ChannelReport, 0, len(m.channels))
+	for chanID, state := range m.channels {
+		reports = append(reports, m.report(chanID, state, now))
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ChannelID.ToUint64() <
+			reports[j].ChannelID.ToUint64()
+	})
+
+	return reports
+}
@@ -0,0 +1,282 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/stretchr/testify/require"
+)
+
+const timeout = time.Second * 5
+
+var (
+	testTime = time.Unix(1_700_000_000, 0)
+
+	chanIn  = lnwire.NewShortChanIDFromInt(1)
+	chanOut = lnwire.NewShortChanIDFromInt(2)
+
+	// testLimits gives the outgoing channel 10 slots and 10_000 msat of
+	// liquidity, of which half is protected with the default config.
+	testLimits = htlcswitch.ChannelLimits{
+		MaxHTLCs:    10,
+		MaxInFlight: 10_000,
+	}
+)
+
+// mockSubscription is a subscription of which the test controls the updates.
+type mockSubscription struct {
+	updates chan interface{}
+	quit    chan struct{}
+}
+
+func (m *mockSubscription) Updates() <-chan interface{} {
+	return m.updates
+}
+
+func (m *mockSubscription) Quit() <-chan struct{} {
+	return m.quit
+}
+
+func (m *mockSubscription) Cancel() {}
+
+// newTestManager creates a manager with the default config and a test clock.
+func newTestManager(t *testing.T) (*Manager, *clock.TestClock,
+	*mockSubscription) {
+
+	t.Helper()
+
+	testClock := clock.NewTestClock(testTime)
+	sub := &mockSubscription{
+		updates: make(chan interface{}),
+		quit:    make(chan struct{}),
+	}
+
+	manager, err := NewManager(&Config{
+		RevenueWindow:        DefaultRevenueWindow,
+		ReputationMultiplier: DefaultReputationMultiplier,
+		ResolutionPeriod:     DefaultResolutionPeriod,
+		ProtectedPercentage:  DefaultProtectedPercentage,
+		SubscribeHtlcEvents: func() (subscribe.Subscription, error) {
+			return sub, nil
+		},
+		Clock: testClock,
+	})
+	require.NoError(t, err)
+
+	return manager, testClock, sub
+}
+
+// proposedHTLC creates an htlc from the incoming to the outgoing test channel
+// that pays the given fee and expires in 36 blocks.
+func proposedHTLC(htlcID uint64, amt, fee lnwire.MilliSatoshi,
+	endorsed bool) *htlcswitch.ProposedHTLC {
+
+	return &htlcswitch.ProposedHTLC{
+		IncomingCircuit: models.CircuitKey{
+			ChanID: chanIn,
+			HtlcID: htlcID,
+		},
+		OutgoingChannel:  chanOut,
+		IncomingAmount:   amt + fee,
+		OutgoingAmount:   amt,
+		IncomingExpiry:   136,
+		CurrentHeight:    100,
+		IncomingEndorsed: endorsed,
+	}
+}
+
+// TestForwardBuckets tests that htlcs of incoming channels without reputation
+// are limited to the general resources of the outgoing channel, and that the
+// resources are freed once the htlcs are resolved.
+func TestForwardBuckets(t *testing.T) {
+	t.Parallel()
+
+	manager, _, _ := newTestManager(t)
+
+	// Five htlcs fit into the general bucket, even if they are endorsed,
+	// as the incoming channel has no reputation yet.
+	for i := uint64(0); i < 5; i++ {
+		outcome, err := manager.ForwardHTLC(
+			proposedHTLC(i, 100, 1, true), testLimits,
+		)
+		require.NoError(t, err)
+		require.Equal(t, htlcswitch.ForwardUnendorsed, outcome)
+	}
+
+	outcome, err := manager.ForwardHTLC(
+		proposedHTLC(5, 100, 1, false), testLimits,
+	)
+	require.NoError(t, err)
+	require.Equal(t, htlcswitch.ForwardNoResources, outcome)
+
+	report, err := manager.ChannelReport(chanOut)
+	require.NoError(t, err)
+	require.Equal(t, BucketReport{
+		SlotsUsed:     5,
+		Slots:         5,
+		LiquidityUsed: 500,
+		Liquidity:     5000,
+	}, report.General)
+	require.Equal(t, BucketReport{
+		Slots:     5,
+		Liquidity: 5000,
+	}, report.Protected)
+
+	// Resolving one of the htlcs frees a slot for the next one.
+	manager.resolveHTLC(proposedHTLC(0, 0, 0, false).IncomingCircuit, true)
+
+	outcome, err = manager.ForwardHTLC(
+		proposedHTLC(5, 100, 1, false), testLimits,
+	)
+	require.NoError(t, err)
+	require.Equal(t, htlcswitch.ForwardUnendorsed, outcome)
+
+	// Liquidity is limited as well.
+	manager.resolveHTLC(proposedHTLC(1, 0, 0, false).IncomingCircuit, true)
+
+	outcome, err = manager.ForwardHTLC(
+		proposedHTLC(6, 4700, 1, false), testLimits,
+	)
+	require.NoError(t, err)
+	require.Equal(t, htlcswitch.ForwardNoResources, outcome)
+
+	// An htlc that pays us a negative fee is rejected.
+	_, err = manager.ForwardHTLC(&htlcswitch.ProposedHTLC{
+		IncomingAmount: 100,
+		OutgoingAmount: 101,
+	}, testLimits)
+	require.Error(t, err)
+}
+
+// TestReputation tests that an incoming channel builds reputation with htlcs
+// that are resolved in time, after which its endorsed htlcs may use the
+// protected resources, and that it loses reputation with slow htlcs.
+func TestReputation(t *testing.T) {
+	t.Parallel()
+
+	manager, testClock, _ := newTestManager(t)
+
+	// The outgoing channel earns revenue from an htlc of another incoming
+	// channel, which the incoming channel has to exceed.
+	other := proposedHTLC(0, 100, 10, false)
+	other.IncomingCircuit.ChanID = lnwire.NewShortChanIDFromInt(3)
+	_, err := manager.ForwardHTLC(other, testLimits)
+	require.NoError(t, err)
+	manager.resolveHTLC(other.IncomingCircuit, true)
+
+	// Unendorsed htlcs that are settled in time build reputation, while
+	// failed htlcs don't. They are forwarded over another outgoing
+	// channel, so that the revenue of the outgoing channel stays low.
+	for i := uint64(0); i < 3; i++ {
+		htlc := proposedHTLC(i, 100, 10_000, false)
+		htlc.OutgoingChannel = lnwire.NewShortChanIDFromInt(4)
+		_, err := manager.ForwardHTLC(htlc, testLimits)
+		require.NoError(t, err)
+
+		manager.resolveHTLC(htlc.IncomingCircuit, i != 2)
+	}
+
+	report, err := manager.ChannelReport(chanIn)
+	require.NoError(t, err)
+	require.Equal(t, int64(20_000), report.ReputationMsat)
+
+	outReport, err := manager.ChannelReport(chanOut)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), outReport.RevenueMsat)
+
+	// The reputation of the incoming channel must exceed the revenue of
+	// the outgoing channel plus the risk of the htlc. With 36 blocks until
+	// expiry, an htlc may be held for 240 resolution periods, so a fee of
+	// 1 msat is a risk of 240 msat.
+	reputable := proposedHTLC(10, 100, 1, true)
+	outcome, err := manager.ForwardHTLC(reputable, testLimits)
+	require.NoError(t, err)
+	require.Equal(t, htlcswitch.ForwardEndorsed, outcome)
+
+	report, err = manager.ChannelReport(chanOut)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, report.Protected.SlotsUsed)
+	require.Zero(t, report.General.SlotsUsed)
+
+	inReport, err := manager.ChannelReport(chanIn)
+	require.NoError(t, err)
+	require.EqualValues(t, 240, inReport.InFlightRisk)
+
+	// Unendorsed htlcs are not endorsed, regardless of the reputation.
+	outcome, err = manager.ForwardHTLC(
+		proposedHTLC(11, 100, 1, false), testLimits,
+	)
+	require.NoError(t, err)
+	require.Equal(t, htlcswitch.ForwardUnendorsed, outcome)
+
+	// An htlc with a risk that exceeds the reputation is not endorsed.
+	outcome, err = manager.ForwardHTLC(
+		proposedHTLC(12, 100, 1000, true), testLimits,
+	)
+	require.NoError(t, err)
+	require.Equal(t, htlcswitch.ForwardUnendorsed, outcome)
+
+	// The endorsed htlc is held for three and a half resolution periods
+	// before it is settled, which costs it three times its fee.
+	testClock.SetTime(testTime.Add(DefaultResolutionPeriod * 7 / 2))
+	manager.resolveHTLC(reputable.IncomingCircuit, true)
+
+	inReport, err = manager.ChannelReport(chanIn)
+	require.NoError(t, err)
+	require.Zero(t, inReport.InFlightRisk)
+	require.InDelta(t, 20_000-2, inReport.ReputationMsat, 1)
+}
+
+// TestConsumeEvents tests that the manager resolves htlcs when it receives
+// final htlc events.
+func TestConsumeEvents(t *testing.T) {
+	t.Parallel()
+
+	manager, _, sub := newTestManager(t)
+	require.NoError(t, manager.Start())
+	t.Cleanup(func() {
+		require.NoError(t, manager.Stop())
+	})
+
+	htlc := proposedHTLC(0, 100, 10, false)
+	_, err := manager.ForwardHTLC(htlc, testLimits)
+	require.NoError(t, err)
+
+	// Other events are ignored.
+	events := []interface{}{
+		&htlcswitch.SettleEvent{},
+		&htlcswitch.FinalHtlcEvent{
+			CircuitKey: htlc.IncomingCircuit,
+			Settled:    true,
+			Offchain:   true,
+		},
+	}
+	for _, event := range events {
+		select {
+		case sub.updates <- event:
+		case <-time.After(timeout):
+			t.Fatalf("event not consumed")
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		report, err := manager.ChannelReport(chanOut)
+		require.NoError(t, err)
+
+		return report.General.SlotsUsed == 0 &&
+			report.RevenueMsat == 10
+	}, timeout, 10*time.Millisecond)
+
+	reports := manager.ChannelReports()
+	require.Len(t, reports, 2)
+	require.Equal(t, chanIn, reports[0].ChannelID)
+	require.Equal(t, chanOut, reports[1].ChannelID)
+
+	_, err = manager.ChannelReport(lnwire.NewShortChanIDFromInt(9))
+	require.ErrorIs(t, err, ErrChannelNotFound)
+}
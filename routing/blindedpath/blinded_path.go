@@ -1017,3 +1017,106 @@ func padHopInfo(hopInfo []*hopData, prePad bool, minSize int) (
 
 	return paymentPath, &stats, nil
 }
+
+// OnionMessagePathCfg holds the parameters of a blinded path that onion
+// messages are sent along.
+type OnionMessagePathCfg struct {
+	// Hops are the real node IDs of the nodes on the path, starting with
+	// the introduction node.
+	Hops []route.Vertex
+
+	// PathID is an optional secret that is included in the data of the
+	// final hop. It allows the creator of the path to verify that a message
+	// arrived via this path.
+	PathID []byte
+
+	// Next is an optional blinded path that the final hop of this path
+	// should forward the message on to. It is used to reach a node that
+	// only provided a blinded path to itself. The PathID must not be set
+	// along with it.
+	Next *sphinx.BlindedPath
+}
+
+// BuildOnionMessagePath constructs a blinded path for onion messages along the
+// given hops. Each hop other than the final one is told the node ID of the
+// next hop, since onion messages don't require a channel between the nodes.
+func BuildOnionMessagePath(cfg *OnionMessagePathCfg) (*sphinx.BlindedPath,
+	error) {
+
+	if len(cfg.Hops) == 0 {
+		return nil, fmt.Errorf("onion message path must have at " +
+			"least one hop")
+	}
+	if cfg.Next != nil && len(cfg.PathID) != 0 {
+		return nil, fmt.Errorf("path ID can't be set for a path " +
+			"that is forwarded onwards")
+	}
+
+	hopDataSet := make([]*hopData, len(cfg.Hops))
+	for i, hop := range cfg.Hops {
+		nodeID, err := btcec.ParsePubKey(hop[:])
+		if err != nil {
+			return nil, err
+		}
+
+		var data *record.BlindedRouteData
+		switch {
+		// Every hop before the final one forwards to the next node on
+		// the path.
+		case i < len(cfg.Hops)-1:
+			nextNode, err := btcec.ParsePubKey(cfg.Hops[i+1][:])
+			if err != nil {
+				return nil, err
+			}
+
+			data = &record.BlindedRouteData{
+				NextNodeID: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType4](
+						nextNode,
+					),
+				),
+			}
+
+		// If the path continues with another blinded path, the final
+		// hop forwards to its introduction point and switches in its
+		// blinding point.
+		case cfg.Next != nil:
+			data = &record.BlindedRouteData{
+				NextNodeID: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType4](
+						cfg.Next.IntroductionPoint,
+					),
+				),
+				NextBlindingOverride: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType8](
+						cfg.Next.BlindingPoint,
+					),
+				),
+			}
+
+		default:
+			data = record.NewFinalHopBlindedRouteData(
+				nil, cfg.PathID,
+			)
+		}
+
+		hopDataSet[i] = &hopData{
+			data:   data,
+			nodeID: nodeID,
+		}
+	}
+
+	// Pad the data so that an intermediate hop can't tell its position
+	// on the path from the size of its payload.
+	path, _, err := padHopInfo(hopDataSet, true, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return sphinx.BuildBlindedPath(sessionKey, path)
+}
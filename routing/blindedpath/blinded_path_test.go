@@ -1039,6 +1039,77 @@ func TestSingleHopBlindedPath(t *testing.T) {
 	require.EqualValues(t, 12, path.CltvExpiryDelta)
 }
 
+// TestBuildOnionMessagePath tests that each hop of an onion message path is
+// told the next node, and that a path can be joined with another blinded path.
+func TestBuildOnionMessagePath(t *testing.T) {
+	var (
+		privC, pkC = btcec.PrivKeyFromBytes([]byte{1})
+		privB, pkB = btcec.PrivKeyFromBytes([]byte{2})
+		privA, pkA = btcec.PrivKeyFromBytes([]byte{3})
+
+		carol = route.NewVertex(pkC)
+		bob   = route.NewVertex(pkB)
+		alice = route.NewVertex(pkA)
+	)
+
+	// A path ID can't be set if the path is forwarded onwards.
+	_, err := BuildOnionMessagePath(&OnionMessagePathCfg{
+		Hops:   []route.Vertex{carol},
+		PathID: []byte{1},
+		Next:   &sphinx.BlindedPath{},
+	})
+	require.Error(t, err)
+
+	// Build a path from Carol to Alice via Bob.
+	path, err := BuildOnionMessagePath(&OnionMessagePathCfg{
+		Hops:   []route.Vertex{carol, bob, alice},
+		PathID: []byte{1, 2, 3},
+	})
+	require.NoError(t, err)
+	require.True(t, path.IntroductionPoint.IsEqual(pkC))
+	require.Len(t, path.BlindedHops, 3)
+
+	// All the encrypted blobs are padded to the same size.
+	hops := path.BlindedHops
+	require.Len(t, hops[0].CipherText, len(hops[1].CipherText))
+	require.Len(t, hops[1].CipherText, len(hops[2].CipherText))
+
+	data, pathKey := decryptAndDecodeHopData(
+		t, privC, path.BlindingPoint, hops[0].CipherText,
+	)
+	require.True(t, data.NextNodeID.UnwrapOrFail(t).Val.IsEqual(pkB))
+	require.True(t, data.PathID.IsNone())
+
+	data, pathKey = decryptAndDecodeHopData(
+		t, privB, pathKey, hops[1].CipherText,
+	)
+	require.True(t, data.NextNodeID.UnwrapOrFail(t).Val.IsEqual(pkA))
+
+	data, _ = decryptAndDecodeHopData(t, privA, pathKey, hops[2].CipherText)
+	require.True(t, data.NextNodeID.IsNone())
+	require.Equal(t, []byte{1, 2, 3}, data.PathID.UnwrapOrFail(t).Val)
+
+	// Now build a path to Carol that continues with the path above. Bob's
+	// data should point to Carol and switch in the blinding point of the
+	// path above.
+	joined, err := BuildOnionMessagePath(&OnionMessagePathCfg{
+		Hops: []route.Vertex{bob},
+		Next: path,
+	})
+	require.NoError(t, err)
+
+	data, _ = decryptAndDecodeHopData(
+		t, privB, joined.BlindingPoint,
+		joined.BlindedHops[0].CipherText,
+	)
+	require.True(t, data.NextNodeID.UnwrapOrFail(t).Val.IsEqual(pkC))
+	require.True(
+		t, data.NextBlindingOverride.UnwrapOrFail(t).Val.IsEqual(
+			path.BlindingPoint,
+		),
+	)
+}
+
 func decryptAndDecodeHopData(t *testing.T, priv *btcec.PrivateKey,
 	ephem *btcec.PublicKey, cipherText []byte) (*record.BlindedRouteData,
 	*btcec.PublicKey) {
@@ -0,0 +1,474 @@
+package chainview
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/esplora"
+)
+
+// EsploraFilteredChainView is an implementation of the FilteredChainView
+// interface which is backed by an Esplora server. New blocks are fetched in
+// full and filtered locally, while filter updates are rescanned using the
+// output spend index of the server.
+type EsploraFilteredChainView struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	// bestHeight is the height of the latest block added to the
+	// blockQueue. It is used to determine up to what height we would need
+	// to rescan in case of a filter update.
+	bestHeightMtx sync.Mutex
+	bestHeight    uint32
+
+	conn *esplora.Conn
+	sub  *esplora.BlockSubscription
+
+	// blockEventQueue is the ordered queue used to keep the order
+	// of connected and disconnected blocks sent to the reader of the
+	// chainView.
+	blockQueue *blockEventQueue
+
+	// blockCache is an LRU block cache.
+	blockCache *blockcache.BlockCache
+
+	// filterUpdates is a channel in which updates to the utxo filter
+	// attached to this instance are sent over.
+	filterUpdates chan filterUpdate
+
+	// chainFilter is the set of utox's that we're currently watching
+	// spends for within the chain.
+	filterMtx   sync.RWMutex
+	chainFilter map[wire.OutPoint]struct{}
+
+	// filterBlockReqs is a channel in which requests to filter select
+	// blocks will be sent over.
+	filterBlockReqs chan *filterBlockReq
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile time check to ensure EsploraFilteredChainView implements the
+// chainview.FilteredChainView.
+var _ FilteredChainView = (*EsploraFilteredChainView)(nil)
+
+// NewEsploraFilteredChainView creates a new instance of a FilteredChainView
+// from a started connection to an Esplora server.
+func NewEsploraFilteredChainView(conn *esplora.Conn,
+	blockCache *blockcache.BlockCache) *EsploraFilteredChainView {
+
+	return &EsploraFilteredChainView{
+		conn:            conn,
+		chainFilter:     make(map[wire.OutPoint]struct{}),
+		filterUpdates:   make(chan filterUpdate),
+		filterBlockReqs: make(chan *filterBlockReq),
+		blockCache:      blockCache,
+		blockQueue:      newBlockEventQueue(),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Start starts all goroutines necessary for normal operation.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (e *EsploraFilteredChainView) Start() error {
+	// Already started?
+	if atomic.AddInt32(&e.started, 1) != 1 {
+		return nil
+	}
+
+	log.Infof("FilteredChainView starting")
+
+	sub, err := e.conn.SubscribeBlocks()
+	if err != nil {
+		return err
+	}
+	e.sub = sub
+
+	e.bestHeightMtx.Lock()
+	e.bestHeight = uint32(sub.BestBlock.Height)
+	e.bestHeightMtx.Unlock()
+
+	e.blockQueue.Start()
+
+	e.wg.Add(1)
+	go e.chainFilterer()
+
+	return nil
+}
+
+// Stop stops all goroutines which we launched by the prior call to the Start
+// method.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (e *EsploraFilteredChainView) Stop() error {
+	log.Debug("EsploraFilteredChainView stopping")
+	defer log.Debug("EsploraFilteredChainView stopped")
+
+	// Already shutting down?
+	if atomic.AddInt32(&e.stopped, 1) != 1 {
+		return nil
+	}
+
+	if e.sub != nil {
+		e.sub.Cancel()
+	}
+
+	e.blockQueue.Stop()
+
+	close(e.quit)
+	e.wg.Wait()
+
+	return nil
+}
+
+// filterBlock scans the given block, and notes which transactions spend
+// outputs which are currently being watched. Additionally, the chain filter
+// will also be updated by removing any spent outputs.
+func (e *EsploraFilteredChainView) filterBlock(
+	blk *wire.MsgBlock) []*wire.MsgTx {
+
+	e.filterMtx.Lock()
+	defer e.filterMtx.Unlock()
+
+	var filteredTxns []*wire.MsgTx
+	for _, tx := range blk.Transactions {
+		var txAlreadyFiltered bool
+		for _, txIn := range tx.TxIn {
+			prevOp := txIn.PreviousOutPoint
+			if _, ok := e.chainFilter[prevOp]; !ok {
+				continue
+			}
+
+			// We can delete this outpoint from the chainFilter,
+			// as we just received a block where it was spent. In
+			// case of a reorg, this outpoint might get
+			// "un-spent", but that's okay since it would never
+			// be wise to consider the channel open again (since a
+			// spending transaction exists on the network).
+			delete(e.chainFilter, prevOp)
+
+			// Only add this txn to our list of filtered txns if
+			// it is the first previous outpoint to cause a match.
+			if txAlreadyFiltered {
+				continue
+			}
+
+			filteredTxns = append(filteredTxns, tx.Copy())
+			txAlreadyFiltered = true
+		}
+	}
+
+	return filteredTxns
+}
+
+// onBlockConnected filters a block that was connected to the end of the main
+// chain and adds it to the block queue.
+func (e *EsploraFilteredChainView) onBlockConnected(block *esplora.Block) {
+	rawBlock, err := e.GetBlock(&block.Hash)
+	if err != nil {
+		log.Errorf("Unable to get block %v at height %d: %v",
+			block.Hash, block.Height, err)
+		return
+	}
+
+	// We record the height of the last connected block added to the
+	// blockQueue such that we can scan up to this height in case of a
+	// rescan. It must be protected by a mutex since a filter update
+	// might be trying to read it concurrently.
+	e.bestHeightMtx.Lock()
+	e.bestHeight = uint32(block.Height)
+	e.bestHeightMtx.Unlock()
+
+	e.blockQueue.Add(&blockEvent{
+		eventType: connected,
+		block: &FilteredBlock{
+			Hash:         block.Hash,
+			Height:       uint32(block.Height),
+			Transactions: e.filterBlock(rawBlock),
+		},
+	})
+}
+
+// onBlockDisconnected adds a block that was disconnected from the end of the
+// main chain to the block queue.
+func (e *EsploraFilteredChainView) onBlockDisconnected(block *esplora.Block) {
+	log.Debugf("got disconnected block at height %d: %v", block.Height,
+		block.Hash)
+
+	e.blockQueue.Add(&blockEvent{
+		eventType: disconnected,
+		block: &FilteredBlock{
+			Hash:   block.Hash,
+			Height: uint32(block.Height),
+		},
+	})
+}
+
+// rescanSpends looks up the confirmed spends of the given outpoints within
+// the blocks after the update height and up to the best height, and returns
+// them as filtered blocks in ascending order.
+func (e *EsploraFilteredChainView) rescanSpends(ops []wire.OutPoint,
+	updateHeight, bestHeight uint32) ([]*FilteredBlock, error) {
+
+	blocks := make(map[uint32]*FilteredBlock)
+	for i := range ops {
+		op := ops[i]
+		outSpend, err := e.conn.GetTxOutSpend(&op)
+		switch {
+		case errors.Is(err, esplora.ErrNotFound):
+			continue
+
+		case err != nil:
+			return nil, err
+
+		case !outSpend.Spent || outSpend.Status == nil ||
+			!outSpend.Status.Confirmed:
+
+			continue
+		}
+
+		height := uint32(outSpend.Status.BlockHeight)
+		if height <= updateHeight || height > bestHeight {
+			continue
+		}
+
+		txid, err := chainhash.NewHashFromStr(outSpend.TxID)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := e.conn.GetRawTransaction(txid)
+		if err != nil {
+			return nil, err
+		}
+
+		e.filterMtx.Lock()
+		delete(e.chainFilter, op)
+		e.filterMtx.Unlock()
+
+		block, ok := blocks[height]
+		if !ok {
+			blockHash, err := chainhash.NewHashFromStr(
+				outSpend.Status.BlockHash,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			block = &FilteredBlock{
+				Hash:   *blockHash,
+				Height: height,
+			}
+			blocks[height] = block
+		}
+
+		// A transaction might spend several of the outpoints, but we
+		// only include it once.
+		var included bool
+		for _, blockTx := range block.Transactions {
+			if blockTx.TxHash() == *txid {
+				included = true
+				break
+			}
+		}
+		if !included {
+			block.Transactions = append(block.Transactions, tx)
+		}
+	}
+
+	filteredBlocks := make([]*FilteredBlock, 0, len(blocks))
+	for _, block := range blocks {
+		filteredBlocks = append(filteredBlocks, block)
+	}
+	sort.Slice(filteredBlocks, func(i, j int) bool {
+		return filteredBlocks[i].Height < filteredBlocks[j].Height
+	})
+
+	return filteredBlocks, nil
+}
+
+// chainFilterer is the primary goroutine which: listens for new blocks coming
+// and dispatches the relevant FilteredBlock notifications, updates the filter
+// due to requests by callers, and finally is able to perform targeted block
+// filtration.
+func (e *EsploraFilteredChainView) chainFilterer() {
+	defer e.wg.Done()
+
+	for {
+		select {
+		// The caller has just sent an update to the current chain
+		// filter, so we'll apply the update, possibly rewinding our
+		// state partially.
+		case update := <-e.filterUpdates:
+			// First, we'll add all the new UTXO's to the set of
+			// watched UTXO's, eliminating any duplicates in the
+			// process.
+			log.Tracef("Updating chain filter with new UTXO's: %v",
+				update.newUtxos)
+
+			e.filterMtx.Lock()
+			for _, newOp := range update.newUtxos {
+				e.chainFilter[newOp] = struct{}{}
+			}
+			e.filterMtx.Unlock()
+
+			e.bestHeightMtx.Lock()
+			bestHeight := e.bestHeight
+			e.bestHeightMtx.Unlock()
+
+			// If the update height matches our best known height,
+			// then we don't need to do any rewinding.
+			if update.updateHeight >= bestHeight {
+				continue
+			}
+
+			// Otherwise, we'll look up whether any of the new
+			// outpoints were spent in the blocks after the update
+			// height, to ensure the caller doesn't miss any
+			// relevant notifications.
+			blocks, err := e.rescanSpends(
+				update.newUtxos, update.updateHeight,
+				bestHeight,
+			)
+			if err != nil {
+				log.Errorf("Unable to rescan filter update: %v",
+					err)
+				continue
+			}
+
+			for _, block := range blocks {
+				e.blockQueue.Add(&blockEvent{
+					eventType: connected,
+					block:     block,
+				})
+			}
+
+		// We've received a new request to manually filter a block.
+		case req := <-e.filterBlockReqs:
+			// First we'll fetch the block itself as well as some
+			// additional information including its height.
+			block, err := e.GetBlock(req.blockHash)
+			if err != nil {
+				req.err <- err
+				req.resp <- nil
+				continue
+			}
+			header, err := e.conn.GetBlockHeaderVerbose(
+				req.blockHash,
+			)
+			if err != nil {
+				req.err <- err
+				req.resp <- nil
+				continue
+			}
+
+			// Once we have this info, we can directly filter the
+			// block and dispatch the proper notification.
+			req.resp <- &FilteredBlock{
+				Hash:         *req.blockHash,
+				Height:       uint32(header.Height),
+				Transactions: e.filterBlock(block),
+			}
+			req.err <- nil
+
+		// We've received a new block event from the server.
+		case event := <-e.sub.Events:
+			if event.Disconnected {
+				e.onBlockDisconnected(event.Block)
+				continue
+			}
+
+			e.onBlockConnected(event.Block)
+
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+// FilterBlock takes a block hash, and returns a FilteredBlocks which is the
+// result of applying the current registered UTXO sub-set on the block
+// corresponding to that block hash. If any watched UTOX's are spent by the
+// selected lock, then the internal chainFilter will also be updated.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (e *EsploraFilteredChainView) FilterBlock(
+	blockHash *chainhash.Hash) (*FilteredBlock, error) {
+
+	req := &filterBlockReq{
+		blockHash: blockHash,
+		resp:      make(chan *FilteredBlock, 1),
+		err:       make(chan error, 1),
+	}
+
+	select {
+	case e.filterBlockReqs <- req:
+	case <-e.quit:
+		return nil, fmt.Errorf("FilteredChainView shutting down")
+	}
+
+	return <-req.resp, <-req.err
+}
+
+// UpdateFilter updates the UTXO filter which is to be consulted when creating
+// FilteredBlocks to be sent to subscribed clients. This method is cumulative
+// meaning repeated calls to this method should _expand_ the size of the UTXO
+// sub-set currently being watched.  If the set updateHeight is _lower_ than
+// the best known height of the implementation, then the state should be
+// rewound to ensure all relevant notifications are dispatched.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (e *EsploraFilteredChainView) UpdateFilter(ops []channeldb.EdgePoint,
+	updateHeight uint32) error {
+
+	newUtxos := make([]wire.OutPoint, len(ops))
+	for i, op := range ops {
+		newUtxos[i] = op.OutPoint
+	}
+
+	select {
+	case e.filterUpdates <- filterUpdate{
+		newUtxos:     newUtxos,
+		updateHeight: updateHeight,
+	}:
+		return nil
+
+	case <-e.quit:
+		return fmt.Errorf("chain filter shutting down")
+	}
+}
+
+// FilteredBlocks returns the channel that filtered blocks are to be sent over.
+// Each time a block is connected to the end of a main chain, and appropriate
+// FilteredBlock which contains the transactions which mutate our watched UTXO
+// set is to be returned.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (e *EsploraFilteredChainView) FilteredBlocks() <-chan *FilteredBlock {
+	return e.blockQueue.newBlocks
+}
+
+// DisconnectedBlocks returns a receive only channel which will be sent upon
+// with the empty filtered blocks of blocks which are disconnected from the
+// main chain in the case of a re-org.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (e *EsploraFilteredChainView) DisconnectedBlocks() <-chan *FilteredBlock {
+	return e.blockQueue.staleBlocks
+}
+
+// GetBlock is used to retrieve the block with the given hash. This function
+// wraps the blockCache's GetBlock function.
+func (e *EsploraFilteredChainView) GetBlock(hash *chainhash.Hash) (
+	*wire.MsgBlock, error) {
+
+	return e.blockCache.GetBlock(hash, e.conn.GetBlock)
+}
@@ -0,0 +1,112 @@
+package chainview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/esplora"
+	"github.com/lightningnetwork/lnd/esplora/esploratest"
+	"github.com/stretchr/testify/require"
+)
+
+// newEsploraTestTx returns a transaction that spends the given outpoint.
+func newEsploraTestTx(prevOut wire.OutPoint) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: prevOut})
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+
+	return tx
+}
+
+// receiveFilteredBlock returns the next block of the given channel.
+func receiveFilteredBlock(t *testing.T,
+	blocks <-chan *FilteredBlock) *FilteredBlock {
+
+	t.Helper()
+
+	select {
+	case block := <-blocks:
+		return block
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no filtered block received")
+		return nil
+	}
+}
+
+// TestEsploraFilteredChainView checks that spends of watched outpoints are
+// found in new blocks as well as in rescans of filter updates.
+func TestEsploraFilteredChainView(t *testing.T) {
+	t.Parallel()
+
+	server := esploratest.NewServer(t)
+	coinbase1 := server.MineBlock().Transactions[0]
+	coinbase2 := server.MineBlock().Transactions[0]
+
+	// The first coinbase output is spent before the chain view starts.
+	op1 := wire.OutPoint{Hash: coinbase1.TxHash()}
+	spendTx1 := newEsploraTestTx(op1)
+	spendBlock1 := server.MineBlock(spendTx1)
+
+	conn := esplora.NewConn(&esplora.Config{
+		URL:            server.URL,
+		RequestTimeout: 5 * time.Second,
+		PollInterval:   10 * time.Millisecond,
+	})
+	require.NoError(t, conn.Start())
+	t.Cleanup(conn.Stop)
+
+	chainView := NewEsploraFilteredChainView(
+		conn, blockcache.NewBlockCache(1000),
+	)
+	require.NoError(t, chainView.Start())
+	t.Cleanup(func() {
+		require.NoError(t, chainView.Stop())
+	})
+
+	// Updating the filter from a height before the spend should rescan
+	// the spend.
+	op2 := wire.OutPoint{Hash: coinbase2.TxHash()}
+	err := chainView.UpdateFilter([]channeldb.EdgePoint{
+		{OutPoint: op1}, {OutPoint: op2},
+	}, 1)
+	require.NoError(t, err)
+
+	block := receiveFilteredBlock(t, chainView.FilteredBlocks())
+	require.Equal(t, spendBlock1.BlockHash(), block.Hash)
+	require.EqualValues(t, 3, block.Height)
+	require.Len(t, block.Transactions, 1)
+	require.Equal(t, spendTx1.TxHash(), block.Transactions[0].TxHash())
+
+	// The spend of the second outpoint is found in a new block.
+	spendTx2 := newEsploraTestTx(op2)
+	spendBlock2 := server.MineBlock(spendTx2)
+
+	block = receiveFilteredBlock(t, chainView.FilteredBlocks())
+	require.Equal(t, spendBlock2.BlockHash(), block.Hash)
+	require.EqualValues(t, 4, block.Height)
+	require.Len(t, block.Transactions, 1)
+	require.Equal(t, spendTx2.TxHash(), block.Transactions[0].TxHash())
+
+	// Once the block is disconnected, it should be sent as stale block.
+	server.DisconnectBlocks(1)
+	newBlock := server.MineBlock()
+
+	block = receiveFilteredBlock(t, chainView.DisconnectedBlocks())
+	require.Equal(t, spendBlock2.BlockHash(), block.Hash)
+
+	block = receiveFilteredBlock(t, chainView.FilteredBlocks())
+	require.Equal(t, newBlock.BlockHash(), block.Hash)
+	require.Empty(t, block.Transactions)
+
+	// Manually filtering the first spend block doesn't find anything
+	// anymore, as the spent outpoint isn't watched anymore.
+	hash := spendBlock1.BlockHash()
+	block, err = chainView.FilterBlock(&hash)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, block.Height)
+	require.Empty(t, block.Transactions)
+}
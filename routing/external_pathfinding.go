@@ -0,0 +1,323 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// ErrPathFindingProviderExists is returned when a path finding
+	// provider is registered while another one is active.
+	ErrPathFindingProviderExists = errors.New("external path finding " +
+		"provider already registered")
+)
+
+// PathFindingRequest describes a path finding run that is handed to an
+// external path finding provider.
+type PathFindingRequest struct {
+	// Source is the node that the path starts at.
+	Source route.Vertex
+
+	// Target is the node that the path ends at.
+	Target route.Vertex
+
+	// Amount is the amount that is to be delivered to the target.
+	Amount lnwire.MilliSatoshi
+
+	// FeeLimit is the maximum total fee of the path.
+	FeeLimit lnwire.MilliSatoshi
+
+	// CltvLimit is the maximum time lock of the path, excluding the final
+	// cltv delta.
+	CltvLimit uint32
+
+	// OutgoingChannelIDs is the list of channels that are allowed for the
+	// first hop. If nil, any channel may be used.
+	OutgoingChannelIDs []uint64
+
+	// LastHop is the node that must precede the target, if set.
+	LastHop *route.Vertex
+}
+
+// PathFindingResponse is the answer of an external path finding provider.
+// Either field may be empty, in which case the builtin path finding is used
+// for that part.
+type PathFindingResponse struct {
+	// Probabilities holds success probabilities for node pairs that take
+	// precedence over the estimates of mission control during path
+	// finding.
+	Probabilities map[DirectedNodePair]float64
+
+	// Hops is a complete path to the target, excluding the source. If it
+	// is set, it is used instead of running path finding.
+	Hops []route.Vertex
+}
+
+// PathFindingProvider is an external source of success probabilities or
+// complete paths.
+type PathFindingProvider interface {
+	// QueryPathFinding hands the request to the provider and waits for its
+	// response. If an error is returned, the builtin path finding is used
+	// instead.
+	QueryPathFinding(req *PathFindingRequest) (*PathFindingResponse,
+		error)
+}
+
+// ExternalPathFinder allows a PathFindingProvider to be plugged into the path
+// finding of payments at runtime. At most one provider can be registered at a
+// time. Without a provider, the builtin path finding is used.
+type ExternalPathFinder struct {
+	provider PathFindingProvider
+	mu       sync.RWMutex
+}
+
+// NewExternalPathFinder returns an ExternalPathFinder without a provider.
+func NewExternalPathFinder() *ExternalPathFinder {
+	return &ExternalPathFinder{}
+}
+
+// RegisterProvider registers the given provider. It fails if another
+// provider is registered already.
+func (e *ExternalPathFinder) RegisterProvider(
+	provider PathFindingProvider) error {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.provider != nil {
+		return ErrPathFindingProviderExists
+	}
+	e.provider = provider
+
+	return nil
+}
+
+// UnregisterProvider removes the given provider if it is the registered one.
+func (e *ExternalPathFinder) UnregisterProvider(
+	provider PathFindingProvider) {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.provider == provider {
+		e.provider = nil
+	}
+}
+
+// activeProvider returns the currently registered provider, if any.
+func (e *ExternalPathFinder) activeProvider() PathFindingProvider {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.provider
+}
+
+// findPath is a pathFinder that consults the registered provider before
+// falling back to findPath. Probabilities that are returned by the provider
+// override the ones of the restrictions, except for pairs that the
+// restrictions exclude entirely. A path that is returned by the provider is
+// used as is if it satisfies the restrictions.
+func (e *ExternalPathFinder) findPath(g *graphParams, r *RestrictParams,
+	cfg *PathFindingConfig, self, source, target route.Vertex,
+	amt lnwire.MilliSatoshi, timePref float64, finalHtlcExpiry int32) (
+	[]*unifiedEdge, float64, error) {
+
+	provider := e.activeProvider()
+	if provider == nil {
+		return findPath(
+			g, r, cfg, self, source, target, amt, timePref,
+			finalHtlcExpiry,
+		)
+	}
+
+	resp, err := provider.QueryPathFinding(&PathFindingRequest{
+		Source:             source,
+		Target:             target,
+		Amount:             amt,
+		FeeLimit:           r.FeeLimit,
+		CltvLimit:          r.CltvLimit,
+		OutgoingChannelIDs: r.OutgoingChannelIDs,
+		LastHop:            r.LastHop,
+	})
+	if err != nil {
+		log.Warnf("External path finding failed, using builtin path "+
+			"finding: %v", err)
+
+		return findPath(
+			g, r, cfg, self, source, target, amt, timePref,
+			finalHtlcExpiry,
+		)
+	}
+
+	if len(resp.Hops) > 0 {
+		path, probability, err := externalPath(
+			g, r, cfg, source, target, resp.Hops, amt,
+			finalHtlcExpiry,
+		)
+		if err == nil {
+			return path, probability, nil
+		}
+
+		log.Warnf("Unable to use external path, using builtin path "+
+			"finding: %v", err)
+	}
+
+	if len(resp.Probabilities) > 0 {
+		r = withProbabilities(r, resp.Probabilities)
+	}
+
+	return findPath(
+		g, r, cfg, self, source, target, amt, timePref, finalHtlcExpiry,
+	)
+}
+
+// withProbabilities returns a copy of the restrictions with a probability
+// source that prefers the given probabilities. Pairs that the original
+// probability source excludes with a zero probability, such as ignored nodes,
+// remain excluded.
+func withProbabilities(r *RestrictParams,
+	probabilities map[DirectedNodePair]float64) *RestrictParams {
+
+	restrictions := *r
+	restrictions.ProbabilitySource = func(fromNode, toNode route.Vertex,
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
+
+		probability := r.ProbabilitySource(
+			fromNode, toNode, amt, capacity,
+		)
+		if probability == 0 {
+			return 0
+		}
+
+		pair := NewDirectedNodePair(fromNode, toNode)
+		if external, ok := probabilities[pair]; ok {
+			return external
+		}
+
+		return probability
+	}
+
+	return &restrictions
+}
+
+// externalPath resolves the hops of an external path to the edges of our
+// graph and checks that the path satisfies the restrictions. It returns the
+// edges and the success probability of the path.
+func externalPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
+	source, target route.Vertex, hops []route.Vertex,
+	amt lnwire.MilliSatoshi, finalHtlcExpiry int32) ([]*unifiedEdge,
+	float64, error) {
+
+	switch {
+	case hops[len(hops)-1] != target:
+		return nil, 0, errors.New("path doesn't end at target")
+
+	case len(hops) > sphinx.NumMaxHops:
+		return nil, 0, fmt.Errorf("path exceeds %v hops",
+			sphinx.NumMaxHops)
+
+	case r.BlindedPaymentPathSet != nil:
+		return nil, 0, errors.New("external paths to blinded paths " +
+			"are not supported")
+	}
+
+	if r.LastHop != nil {
+		lastHop := source
+		if len(hops) > 1 {
+			lastHop = hops[len(hops)-2]
+		}
+
+		if lastHop != *r.LastHop {
+			return nil, 0, errors.New("path violates last hop " +
+				"restriction")
+		}
+	}
+
+	var outgoingChans map[uint64]struct{}
+	if len(r.OutgoingChannelIDs) > 0 {
+		outgoingChans = make(map[uint64]struct{})
+		for _, chanID := range r.OutgoingChannelIDs {
+			outgoingChans[chanID] = struct{}{}
+		}
+	}
+
+	unifiers, err := getEdgeUnifiers(source, hops, outgoingChans, g.graph)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	edges, senderAmt, err := senderAmtBackwardPass(
+		unifiers, fn.Some(amt), g.bandwidthHints,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if senderAmt-amt > r.FeeLimit {
+		return nil, 0, fmt.Errorf("path fee %v exceeds fee limit %v",
+			senderAmt-amt, r.FeeLimit)
+	}
+
+	// The source doesn't add a time lock delta to the path. Use uint64 to
+	// prevent an overflow if the cltv limit is MaxUint32.
+	totalCltv := uint64(finalHtlcExpiry)
+	for _, edge := range edges[1:] {
+		totalCltv += uint64(edge.policy.TimeLockDelta)
+	}
+	if totalCltv > uint64(r.CltvLimit)+uint64(finalHtlcExpiry) {
+		return nil, 0, errors.New("path exceeds cltv limit")
+	}
+
+	// Evaluate the probability of the path with our own estimates, so
+	// that paths over channels that just failed or that are excluded
+	// aren't retried endlessly.
+	var (
+		probability = 1.0
+		htlcAmt     = amt
+	)
+	for i := len(edges) - 1; i >= 0; i-- {
+		fromNode := source
+		if i > 0 {
+			fromNode = hops[i-1]
+		}
+
+		probability *= r.ProbabilitySource(
+			fromNode, hops[i], htlcAmt, edges[i].capacity,
+		)
+
+		// The htlc of the previous edge also pays the fee of the
+		// node that forwards over this edge.
+		if i > 0 {
+			htlcAmt += edges[i].policy.ComputeFee(htlcAmt)
+		}
+	}
+	if probability == 0 || probability < cfg.MinProbability {
+		return nil, 0, fmt.Errorf("path probability %v below minimum",
+			probability)
+	}
+
+	// As in findPath, the final hop carries the features of the
+	// destination, which are needed to construct its payload.
+	features := r.DestFeatures
+	if features == nil {
+		features, err = g.graph.FetchNodeFeatures(target)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	lastEdge := *edges[len(edges)-1]
+	policy := *lastEdge.policy
+	policy.ToNodeFeatures = features
+	lastEdge.policy = &policy
+	edges[len(edges)-1] = &lastEdge
+
+	return edges, probability, nil
+}
@@ -0,0 +1,136 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPathFindingProvider is a PathFindingProvider that returns a fixed
+// response.
+type mockPathFindingProvider struct {
+	resp *PathFindingResponse
+	err  error
+
+	requests []*PathFindingRequest
+}
+
+// QueryPathFinding records the request and returns the fixed response.
+func (m *mockPathFindingProvider) QueryPathFinding(
+	req *PathFindingRequest) (*PathFindingResponse, error) {
+
+	m.requests = append(m.requests, req)
+
+	return m.resp, m.err
+}
+
+// TestExternalPathFinder tests that the probabilities and paths of an
+// external provider are used in path finding, and that the builtin path
+// finding is used if the provider fails.
+func TestExternalPathFinder(t *testing.T) {
+	t.Parallel()
+
+	policy := func(feeRate lnwire.MilliSatoshi) *testChannelPolicy {
+		return &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: feeRate,
+			MinHTLC: 1,
+			MaxHTLC: 100000000,
+		}
+	}
+
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", 100000, policy(100)),
+		symmetricTestChannel("a", "target", 100000, policy(100)),
+		symmetricTestChannel("roasbeef", "b", 100000, policy(200)),
+		symmetricTestChannel("b", "target", 100000, policy(200)),
+	}
+
+	ctx := newPathFindingTestContext(t, true, testChannels, "roasbeef")
+	ctx.pathFindingConfig.AttemptCost = 100000
+
+	var (
+		paymentAmt = lnwire.NewMSatFromSatoshis(10000)
+		target     = ctx.keyFromAlias("target")
+		nodeA      = ctx.keyFromAlias("a")
+		nodeB      = ctx.keyFromAlias("b")
+		finder     = NewExternalPathFinder()
+	)
+
+	findPath := func() []route.Vertex {
+		t.Helper()
+
+		graphSessFactory := newMockGraphSessionFactoryFromChanDB(
+			ctx.graph,
+		)
+		graphSess, closeGraphSess, err :=
+			graphSessFactory.NewGraphSession()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, closeGraphSess())
+		}()
+
+		path, _, err := finder.findPath(
+			&graphParams{
+				bandwidthHints: ctx.bandwidthHints,
+				graph:          graphSess,
+			},
+			&ctx.restrictParams, &ctx.pathFindingConfig,
+			ctx.source, ctx.source, target, paymentAmt, 0, 0,
+		)
+		require.NoError(t, err)
+
+		nodes := make([]route.Vertex, 0, len(path))
+		for _, edge := range path {
+			nodes = append(nodes, edge.policy.ToNodePubKey())
+		}
+
+		return nodes
+	}
+
+	// Without a provider, the cheapest path is found.
+	require.Equal(t, []route.Vertex{nodeA, target}, findPath())
+
+	// A provider that fails makes us fall back to the builtin path
+	// finding.
+	provider := &mockPathFindingProvider{
+		err: errors.New("timeout"),
+	}
+	require.NoError(t, finder.RegisterProvider(provider))
+	require.ErrorIs(
+		t, finder.RegisterProvider(&mockPathFindingProvider{}),
+		ErrPathFindingProviderExists,
+	)
+	require.Equal(t, []route.Vertex{nodeA, target}, findPath())
+	require.Len(t, provider.requests, 1)
+	require.Equal(t, paymentAmt, provider.requests[0].Amount)
+	require.Equal(t, target, provider.requests[0].Target)
+
+	// External probabilities take precedence over our own.
+	provider.err = nil
+	provider.resp = &PathFindingResponse{
+		Probabilities: map[DirectedNodePair]float64{
+			NewDirectedNodePair(nodeA, target): 0.01,
+		},
+	}
+	require.Equal(t, []route.Vertex{nodeB, target}, findPath())
+
+	// An external path is used as is.
+	provider.resp = &PathFindingResponse{
+		Hops: []route.Vertex{nodeB, target},
+	}
+	require.Equal(t, []route.Vertex{nodeB, target}, findPath())
+
+	// An external path that violates the restrictions is not used.
+	ctx.restrictParams.FeeLimit = 1500
+	require.Equal(t, []route.Vertex{nodeA, target}, findPath())
+
+	// After the provider is unregistered, it isn't queried anymore.
+	finder.UnregisterProvider(provider)
+	numRequests := len(provider.requests)
+	require.Equal(t, []route.Vertex{nodeA, target}, findPath())
+	require.Len(t, provider.requests, numRequests)
+}
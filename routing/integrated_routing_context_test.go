@@ -45,7 +45,7 @@ func (m *mockBandwidthHints) firstHopCustomBlob() fn.Option[tlv.Blob] {
 // tests run.
 type integratedRoutingContext struct {
 	graph *mockGraph
-	t     *testing.T
+	t     testing.TB
 
 	source *mockNode
 	target *mockNode
@@ -57,11 +57,12 @@ type integratedRoutingContext struct {
 	mcCfg          MissionControlConfig
 	pathFindingCfg PathFindingConfig
 	routeHints     [][]zpay32.HopHint
+	splitStrategy  SplitStrategy
 }
 
 // newIntegratedRoutingContext instantiates a new integrated routing test
 // context with a source and a target node.
-func newIntegratedRoutingContext(t *testing.T) *integratedRoutingContext {
+func newIntegratedRoutingContext(t testing.TB) *integratedRoutingContext {
 	// Instantiate a mock graph.
 	source := newMockNode(sourceNodeID)
 	target := newMockNode(targetNodeID)
@@ -220,6 +221,12 @@ func (c *integratedRoutingContext) testPayment(maxParts uint32,
 	// Override default minimum shard amount.
 	session.minShardAmt = lnwire.NewMSatFromSatoshis(5000)
 
+	// Plan the shards with a min cost flow if requested.
+	var paySession PaymentSession = session
+	if c.splitStrategy == SplitStrategyMinCostFlow {
+		paySession = newFlowPaymentSession(session)
+	}
+
 	// Now the payment control loop starts. It will keep trying routes until
 	// the payment succeeds.
 	var (
@@ -234,7 +241,7 @@ func (c *integratedRoutingContext) testPayment(maxParts uint32,
 		}
 
 		// Find a route.
-		route, err := session.RequestRoute(
+		route, err := paySession.RequestRoute(
 			amtRemaining, lnwire.MaxMilliSatoshi, inFlightHtlcs, 0,
 			lnwire.CustomRecords{
 				lnwire.MinCustomRecordsTlvType: []byte{1, 2, 3},
@@ -267,7 +274,7 @@ func (c *integratedRoutingContext) testPayment(maxParts uint32,
 		if success {
 			inFlightHtlcs++
 
-			err := mc.ReportPaymentSuccess(pid, route)
+			err := mc.ReportPaymentSuccess(pid, time.Now(), route)
 			if err != nil {
 				c.t.Fatal(err)
 			}
@@ -286,9 +293,9 @@ func (c *integratedRoutingContext) testPayment(maxParts uint32,
 
 		// Failure, update mission control and retry.
 		finalResult, err := mc.ReportPaymentFail(
-			pid, route,
+			pid, time.Now(), route,
 			getNodeIndex(route, htlcResult.failureSource),
-			htlcResult.failure,
+			htlcResult.failure, nil,
 		)
 		if err != nil {
 			c.t.Fatal(err)
@@ -2,6 +2,7 @@ package routing
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -406,3 +407,100 @@ func TestPaymentAddrOnlyNoSplit(t *testing.T) {
 	// MPP.
 	require.Equal(t, err.Error(), errNoPathFound.Error())
 }
+
+// TestMinCostFlowSplitting tests that planning the shards of a payment with a
+// min cost flow splits the payment across both paths of the graph right
+// away, where binary splitting needs five attempts (see mppTestCases).
+func TestMinCostFlowSplitting(t *testing.T) {
+	t.Parallel()
+
+	ctx := newIntegratedRoutingContext(t)
+	twoPathGraph(ctx.graph, 200000, 100000)
+
+	ctx.amt = lnwire.NewMSatFromSatoshis(70000)
+	ctx.splitStrategy = SplitStrategyMinCostFlow
+
+	attempts, err := ctx.testPayment(1000)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+
+	assertSuccessAttempts(t, attempts, []expectedHtlcSuccess{
+		{
+			amt:   35000,
+			chans: []uint64{chanSourceIm1, chanIm1Target},
+		},
+		{
+			amt:   35000,
+			chans: []uint64{chanSourceIm2, chanIm2Target},
+		},
+	})
+}
+
+// randomBalanceGraph creates a graph in which the source is connected to the
+// target through the given number of intermediate nodes. All channels have
+// the given capacity. The balances of the channels of the source are split
+// equally, while the balances of the channels to the target are random.
+func randomBalanceGraph(g *mockGraph, r *rand.Rand, paths int,
+	capacity btcutil.Amount) {
+
+	target := g.nodes[createPubkey(targetNodeID)]
+	capMsat := lnwire.NewMSatFromSatoshis(capacity)
+
+	for i := 0; i < paths; i++ {
+		imNodeID := byte(10 + i)
+		imNode := newMockNode(imNodeID)
+		g.addNode(imNode)
+
+		g.addChannel(uint64(100+i), sourceNodeID, imNodeID, capacity)
+		g.addChannel(uint64(200+i), targetNodeID, imNodeID, capacity)
+
+		balance := lnwire.MilliSatoshi(r.Int63n(int64(capMsat)))
+		imNode.channels[target.pubkey].balance = balance
+		target.channels[imNode.pubkey].balance = capMsat - balance
+	}
+}
+
+// BenchmarkSplitStrategies compares the split strategies for payments over a
+// graph with random balances. Besides the time per payment, it reports the
+// number of attempts and failed attempts per payment.
+func BenchmarkSplitStrategies(b *testing.B) {
+	strategies := []SplitStrategy{
+		SplitStrategyBinary, SplitStrategyMinCostFlow,
+	}
+
+	for _, strategy := range strategies {
+		b.Run(strategy.String(), func(b *testing.B) {
+			var (
+				r        = rand.New(rand.NewSource(1))
+				attempts int
+				failed   int
+			)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				ctx := newIntegratedRoutingContext(b)
+				randomBalanceGraph(ctx.graph, r, 8, 200_000)
+
+				ctx.amt = lnwire.NewMSatFromSatoshis(300_000)
+				ctx.splitStrategy = strategy
+				b.StartTimer()
+
+				htlcs, err := ctx.testPayment(32)
+				require.NoError(b, err)
+
+				attempts += len(htlcs)
+				for _, htlc := range htlcs {
+					if !htlc.success {
+						failed++
+					}
+				}
+			}
+
+			b.ReportMetric(
+				float64(attempts)/float64(b.N), "attempts/op",
+			)
+			b.ReportMetric(
+				float64(failed)/float64(b.N), "failed/op",
+			)
+		})
+	}
+}
@@ -77,22 +77,31 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 		info *models.ChannelEdgeInfo,
 		edge *models.ChannelEdgePolicy) error {
 
+		// The edge of a spliced channel is known by its current
+		// funding outpoint, while the channel itself is still referred
+		// to by the outpoint it was opened with.
+		chanPoint := info.ChannelPoint
+		channel, err := r.FetchChannel(tx, info.ChannelPoint)
+		if err == nil {
+			chanPoint = channel.FundingOutpoint
+		}
+
 		// If we have a channel filter, and this channel isn't a part
 		// of it, then we'll skip it.
-		_, ok := unprocessedChans[info.ChannelPoint]
+		_, ok := unprocessedChans[chanPoint]
 		if !ok && haveChanFilter {
 			return nil
 		}
 
 		// Mark this channel as found by removing it. unprocessedChans
 		// will be used to report invalid channels later on.
-		delete(unprocessedChans, info.ChannelPoint)
+		delete(unprocessedChans, chanPoint)
 
 		// Apply the new policy to the edge.
-		err := r.updateEdge(tx, info.ChannelPoint, edge, newSchema)
+		err = r.updateEdge(tx, chanPoint, edge, newSchema)
 		if err != nil {
 			failedUpdates = append(failedUpdates,
-				makeFailureItem(info.ChannelPoint,
+				makeFailureItem(chanPoint,
 					lnrpc.UpdateFailure_UPDATE_FAILURE_INVALID_PARAMETER,
 					err.Error(),
 				))
@@ -115,7 +124,7 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 		inboundFee := models.NewInboundFeeFromWire(inboundWireFee)
 
 		// Add updated policy to list of policies to send to switch.
-		policiesToUpdate[info.ChannelPoint] = models.ForwardingPolicy{
+		policiesToUpdate[chanPoint] = models.ForwardingPolicy{
 			BaseFee:       edge.FeeBaseMSat,
 			FeeRate:       edge.FeeProportionalMillionths,
 			TimeLockDelta: uint32(edge.TimeLockDelta),
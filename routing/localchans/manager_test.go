@@ -29,6 +29,7 @@ func TestManager(t *testing.T) {
 		chanPointValid     = wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
 		chanCap            = btcutil.Amount(1000)
 		chanPointMissing   = wire.OutPoint{Hash: chainhash.Hash{2}, Index: 2}
+		chanPointSplice    = wire.OutPoint{Hash: chainhash.Hash{3}, Index: 2}
 		maxPendingAmount   = lnwire.MilliSatoshi(999000)
 		minHTLC            = lnwire.MilliSatoshi(2000)
 		expectedNumUpdates int
@@ -130,7 +131,15 @@ func TestManager(t *testing.T) {
 			MinHTLC:          minHTLC,
 		}
 
+		// A spliced channel is found by the funding outpoint of its
+		// splice, but keeps the outpoint it was opened with.
+		fundingOutpoint := chanPoint
+		if chanPoint == chanPointSplice {
+			fundingOutpoint = chanPointValid
+		}
+
 		return &channeldb.OpenChannel{
+			FundingOutpoint: fundingOutpoint,
 			LocalChanCfg: channeldb.ChannelConfig{
 				ChannelStateBounds: bounds,
 			},
@@ -213,6 +222,26 @@ func TestManager(t *testing.T) {
 			},
 			expectErr: nil,
 		},
+		{
+			// The edge of a spliced channel is known by the
+			// funding outpoint of its splice, but its policy is
+			// updated through the outpoint it was opened with.
+			name:          "spliced channel",
+			currentPolicy: currentPolicy,
+			newPolicy:     newPolicy,
+			channelSet: []channel{
+				{
+					edgeInfo: &models.ChannelEdgeInfo{
+						Capacity:     chanCap,
+						ChannelPoint: chanPointSplice,
+					},
+				},
+			},
+			specifiedChanPoints:    []wire.OutPoint{chanPointValid},
+			expectedNumUpdates:     1,
+			expectedUpdateFailures: []lnrpc.UpdateFailure{},
+			expectErr:              nil,
+		},
 		{
 			// Here, no max htlc is specified, the max htlc value
 			// should be kept unchanged.
@@ -0,0 +1,364 @@
+package routing
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+// flowArc is a directed arc of a flow network. The cost of sending flow over
+// the arc is a convex function of the number of units sent, expressed as the
+// marginal cost of every additional unit.
+type flowArc struct {
+	from int
+	to   int
+
+	// marginalCost holds the cost of sending the i-th unit of flow over
+	// the arc. Its length is the capacity of the arc and its values must
+	// be non-negative and non-decreasing.
+	marginalCost []float64
+
+	// flow is the number of units currently sent over the arc.
+	flow int
+}
+
+// flowPath is a path from source to target together with the number of units
+// of flow that are sent along it.
+type flowPath struct {
+	// arcs are the indices of the arcs that make up the path, in the
+	// order from source to target.
+	arcs []int
+
+	// units is the number of units of flow sent along the path.
+	units int
+}
+
+// flowNetwork is a directed graph with integer node indices on which a
+// minimum cost flow can be computed.
+type flowNetwork struct {
+	arcs []*flowArc
+
+	// outArcs and inArcs hold the indices of the arcs that leave and enter
+	// each node.
+	outArcs [][]int
+	inArcs  [][]int
+}
+
+// newFlowNetwork creates an empty flow network.
+func newFlowNetwork() *flowNetwork {
+	return &flowNetwork{}
+}
+
+// addNode adds a node to the network and returns its index.
+func (n *flowNetwork) addNode() int {
+	n.outArcs = append(n.outArcs, nil)
+	n.inArcs = append(n.inArcs, nil)
+
+	return len(n.outArcs) - 1
+}
+
+// addArc adds an arc with the given marginal costs to the network and returns
+// its index.
+func (n *flowNetwork) addArc(from, to int, marginalCost []float64) int {
+	idx := len(n.arcs)
+	n.arcs = append(n.arcs, &flowArc{
+		from:         from,
+		to:           to,
+		marginalCost: marginalCost,
+	})
+
+	n.outArcs[from] = append(n.outArcs[from], idx)
+	n.inArcs[to] = append(n.inArcs[to], idx)
+
+	return idx
+}
+
+// residualStep is a step of an augmenting path in the residual network. It
+// either sends one more unit over an arc or takes one unit back.
+type residualStep struct {
+	arc      int
+	backward bool
+}
+
+// minCostFlow sends up to the given number of units from source to target at
+// minimum total cost, and returns the number of units that could be sent.
+//
+// It uses successive shortest paths: every unit is sent along the cheapest
+// path of the residual network, which may take back flow that was sent
+// before. Because the costs are convex, the reduced costs with respect to
+// the shortest path distances of the previous round are non-negative, so
+// that Dijkstra's algorithm can be used to find each path.
+func (n *flowNetwork) minCostFlow(source, target, units int) int {
+	numNodes := len(n.outArcs)
+	potential := make([]float64, numNodes)
+
+	for sent := 0; sent < units; sent++ {
+		dist, pred := n.shortestPaths(source, potential)
+		if math.IsInf(dist[target], 1) {
+			return sent
+		}
+
+		// Update the potentials so that reduced costs stay
+		// non-negative. Nodes that can't be reached anymore will
+		// never be reached again, as flow is only sent along
+		// reachable paths.
+		for node := range potential {
+			if !math.IsInf(dist[node], 1) {
+				potential[node] += dist[node]
+			}
+		}
+
+		// Send one unit along the path by walking back from the
+		// target.
+		for node := target; node != source; {
+			step := pred[node]
+			arc := n.arcs[step.arc]
+
+			if step.backward {
+				arc.flow--
+				node = arc.to
+			} else {
+				arc.flow++
+				node = arc.from
+			}
+		}
+	}
+
+	return units
+}
+
+// shortestPaths runs Dijkstra's algorithm on the residual network using the
+// reduced costs for the given potentials. It returns the distance to every
+// node and the step over which each node was reached.
+func (n *flowNetwork) shortestPaths(source int,
+	potential []float64) ([]float64, []residualStep) {
+
+	numNodes := len(n.outArcs)
+	dist := make([]float64, numNodes)
+	pred := make([]residualStep, numNodes)
+	done := make([]bool, numNodes)
+	for node := range dist {
+		dist[node] = math.Inf(1)
+	}
+	dist[source] = 0
+
+	queue := &flowNodeHeap{}
+	heap.Push(queue, flowNodeDist{node: source})
+
+	relax := func(from, to int, cost float64, step residualStep) {
+		// Rounding errors may make reduced costs slightly negative.
+		reduced := math.Max(cost+potential[from]-potential[to], 0)
+
+		if dist[from]+reduced < dist[to] {
+			dist[to] = dist[from] + reduced
+			pred[to] = step
+			heap.Push(queue, flowNodeDist{node: to, dist: dist[to]})
+		}
+	}
+
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(flowNodeDist).node
+		if done[node] {
+			continue
+		}
+		done[node] = true
+
+		// Sending more flow over outgoing arcs costs the marginal cost
+		// of the next unit.
+		for _, idx := range n.outArcs[node] {
+			arc := n.arcs[idx]
+			if arc.flow >= len(arc.marginalCost) || done[arc.to] {
+				continue
+			}
+
+			relax(
+				node, arc.to, arc.marginalCost[arc.flow],
+				residualStep{arc: idx},
+			)
+		}
+
+		// Taking back flow from incoming arcs saves the marginal cost
+		// of the last unit.
+		for _, idx := range n.inArcs[node] {
+			arc := n.arcs[idx]
+			if arc.flow == 0 || done[arc.from] {
+				continue
+			}
+
+			relax(
+				node, arc.from, -arc.marginalCost[arc.flow-1],
+				residualStep{arc: idx, backward: true},
+			)
+		}
+	}
+
+	return dist, pred
+}
+
+// decompose splits the flow of the network into paths from source to target.
+// Cycles of flow don't contribute to the paths and are dropped. The flow of
+// the network is consumed in the process.
+func (n *flowNetwork) decompose(source, target int) []flowPath {
+	var paths []flowPath
+
+	for {
+		var (
+			arcs    []int
+			visited = map[int]int{source: 0}
+			node    = source
+		)
+
+		// Follow arcs that carry flow until the target is reached.
+		for node != target {
+			next := -1
+			for _, idx := range n.outArcs[node] {
+				if n.arcs[idx].flow > 0 {
+					next = idx
+					break
+				}
+			}
+
+			// No flow leaves the source anymore, so all paths
+			// have been found.
+			if next == -1 {
+				return paths
+			}
+
+			arcs = append(arcs, next)
+			node = n.arcs[next].to
+
+			// If the node was visited before, we found a cycle
+			// which is cancelled by removing its flow. We then
+			// continue from where the cycle started.
+			pos, ok := visited[node]
+			if !ok {
+				visited[node] = len(arcs)
+				continue
+			}
+
+			cycle := arcs[pos:]
+			units := n.minFlow(cycle)
+			for _, idx := range cycle {
+				n.arcs[idx].flow -= units
+			}
+
+			for _, idx := range cycle {
+				delete(visited, n.arcs[idx].to)
+			}
+			visited[node] = pos
+			arcs = arcs[:pos]
+		}
+
+		units := n.minFlow(arcs)
+		for _, idx := range arcs {
+			n.arcs[idx].flow -= units
+		}
+
+		paths = append(paths, flowPath{
+			arcs:  arcs,
+			units: units,
+		})
+	}
+}
+
+// minFlow returns the smallest flow of the given arcs.
+func (n *flowNetwork) minFlow(arcs []int) int {
+	units := math.MaxInt
+	for _, idx := range arcs {
+		units = lntypes.Min(units, n.arcs[idx].flow)
+	}
+
+	return units
+}
+
+// convexMarginalCosts returns the marginal costs of the largest convex
+// function that doesn't exceed the given costs, where costs[i] is the total
+// cost of sending i+1 units and sending nothing is free. Fixed costs such as
+// base fees are thereby spread across the units, which the flow computation
+// requires.
+func convexMarginalCosts(costs []float64) []float64 {
+	// Compute the lower convex hull of the points (i, cost(i)), starting
+	// at the origin.
+	hull := []int{0}
+	cost := func(i int) float64 {
+		if i == 0 {
+			return 0
+		}
+
+		return costs[i-1]
+	}
+	for i := 1; i <= len(costs); i++ {
+		for len(hull) >= 2 {
+			a, b := hull[len(hull)-2], hull[len(hull)-1]
+
+			// Remove b if it lies on or above the line from a to
+			// i.
+			cross := (cost(b)-cost(a))*float64(i-a) -
+				(cost(i)-cost(a))*float64(b-a)
+			if cross < 0 {
+				break
+			}
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, i)
+	}
+
+	// Every unit between two points of the hull has the same marginal
+	// cost.
+	marginalCosts := make([]float64, 0, len(costs))
+	for j := 1; j < len(hull); j++ {
+		a, b := hull[j-1], hull[j]
+		slope := (cost(b) - cost(a)) / float64(b-a)
+
+		for i := a; i < b; i++ {
+			marginalCosts = append(marginalCosts, slope)
+		}
+	}
+
+	return marginalCosts
+}
+
+// flowNodeDist is a node of the flow network together with its tentative
+// distance from the source.
+type flowNodeDist struct {
+	node int
+	dist float64
+}
+
+// flowNodeHeap is a min-heap of nodes ordered by their distance.
+type flowNodeHeap []flowNodeDist
+
+// Len returns the number of nodes in the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h flowNodeHeap) Len() int { return len(h) }
+
+// Less returns whether the node at index i is closer than the node at index j.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h flowNodeHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+
+// Swap swaps the nodes at the given indices.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h flowNodeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+// Push adds a node to the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h *flowNodeHeap) Push(x interface{}) {
+	*h = append(*h, x.(flowNodeDist))
+}
+
+// Pop removes the last node of the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h *flowNodeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+
+	return x
+}
@@ -0,0 +1,160 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// linearCosts returns the marginal costs of an arc with the given capacity
+// and a constant cost per unit.
+func linearCosts(capacity int, cost float64) []float64 {
+	costs := make([]float64, capacity)
+	for i := range costs {
+		costs[i] = cost
+	}
+
+	return costs
+}
+
+// TestMinCostFlow tests that the flow is distributed across the paths of a
+// network according to their marginal costs.
+func TestMinCostFlow(t *testing.T) {
+	t.Parallel()
+
+	// Create the following network, where the direct connection from a to
+	// b gets more expensive with every unit sent:
+	//
+	//	source -> a -> b -> target
+	//	source -> c ------> target
+	//	          a -> c
+	network := newFlowNetwork()
+	source := network.addNode()
+	a := network.addNode()
+	b := network.addNode()
+	c := network.addNode()
+	target := network.addNode()
+
+	sourceA := network.addArc(source, a, linearCosts(10, 0))
+	ab := network.addArc(a, b, []float64{1, 1, 5, 10})
+	bTarget := network.addArc(b, target, linearCosts(10, 0))
+	sourceC := network.addArc(source, c, linearCosts(2, 2))
+	ac := network.addArc(a, c, linearCosts(10, 6))
+	cTarget := network.addArc(c, target, linearCosts(10, 0))
+
+	// The first two units use the cheap part of a -> b. The next two units
+	// go via c, after which a -> b is cheaper than a -> c.
+	require.Equal(t, 5, network.minCostFlow(source, target, 5))
+
+	flows := make([]int, len(network.arcs))
+	for i, arc := range network.arcs {
+		flows[i] = arc.flow
+	}
+	require.Equal(t, 3, flows[sourceA])
+	require.Equal(t, 3, flows[ab])
+	require.Equal(t, 3, flows[bTarget])
+	require.Equal(t, 2, flows[sourceC])
+	require.Equal(t, 0, flows[ac])
+	require.Equal(t, 2, flows[cTarget])
+
+	paths := network.decompose(source, target)
+	require.ElementsMatch(t, []flowPath{
+		{arcs: []int{sourceA, ab, bTarget}, units: 3},
+		{arcs: []int{sourceC, cTarget}, units: 2},
+	}, paths)
+
+	// The capacity of the network is limited to 10 units by the arcs into
+	// the target.
+	network = newFlowNetwork()
+	source = network.addNode()
+	target = network.addNode()
+	network.addArc(source, target, linearCosts(4, 1))
+	network.addArc(source, target, linearCosts(6, 2))
+	require.Equal(t, 10, network.minCostFlow(source, target, 12))
+}
+
+// TestMinCostFlowTakeBack tests that flow that was sent along a path is taken
+// back when that leads to a cheaper total cost.
+func TestMinCostFlowTakeBack(t *testing.T) {
+	t.Parallel()
+
+	// Create the following network with a cheap shortcut from a to d:
+	//
+	//	source -> a -> b -> target
+	//	source -> c -> d -> target
+	//	          a ------> d
+	//
+	// The first unit takes the cheapest path source -> a -> d -> target.
+	// The second unit can only be sent if the first one is rerouted.
+	network := newFlowNetwork()
+	source := network.addNode()
+	a := network.addNode()
+	b := network.addNode()
+	c := network.addNode()
+	d := network.addNode()
+	target := network.addNode()
+
+	sourceA := network.addArc(source, a, linearCosts(1, 0))
+	ab := network.addArc(a, b, linearCosts(1, 5))
+	bTarget := network.addArc(b, target, linearCosts(1, 0))
+	sourceC := network.addArc(source, c, linearCosts(1, 0))
+	cd := network.addArc(c, d, linearCosts(1, 5))
+	dTarget := network.addArc(d, target, linearCosts(1, 0))
+	ad := network.addArc(a, d, linearCosts(1, 1))
+
+	require.Equal(t, 2, network.minCostFlow(source, target, 2))
+	require.Zero(t, network.arcs[ad].flow)
+
+	paths := network.decompose(source, target)
+	require.ElementsMatch(t, []flowPath{
+		{arcs: []int{sourceA, ab, bTarget}, units: 1},
+		{arcs: []int{sourceC, cd, dTarget}, units: 1},
+	}, paths)
+}
+
+// TestDecomposeCycle tests that cycles of flow are dropped when the flow is
+// decomposed into paths.
+func TestDecomposeCycle(t *testing.T) {
+	t.Parallel()
+
+	network := newFlowNetwork()
+	source := network.addNode()
+	a := network.addNode()
+	b := network.addNode()
+	target := network.addNode()
+
+	sourceA := network.addArc(source, a, linearCosts(5, 0))
+	ab := network.addArc(a, b, linearCosts(5, 0))
+	ba := network.addArc(b, a, linearCosts(5, 0))
+	bTarget := network.addArc(b, target, linearCosts(5, 0))
+
+	network.arcs[sourceA].flow = 2
+	network.arcs[ab].flow = 5
+	network.arcs[ba].flow = 3
+	network.arcs[bTarget].flow = 2
+
+	paths := network.decompose(source, target)
+	require.Equal(t, []flowPath{
+		{arcs: []int{sourceA, ab, bTarget}, units: 2},
+	}, paths)
+}
+
+// TestConvexMarginalCosts tests that costs are turned into the marginal costs
+// of their lower convex hull.
+func TestConvexMarginalCosts(t *testing.T) {
+	t.Parallel()
+
+	// Convex costs are left as they are.
+	require.Equal(
+		t, []float64{1, 2, 3}, convexMarginalCosts([]float64{1, 3, 6}),
+	)
+
+	// A fixed cost for the first unit is spread across the units, up to
+	// the point where the costs start rising faster.
+	require.Equal(
+		t, []float64{2, 2, 2, 5},
+		convexMarginalCosts([]float64{6, 6, 6, 11}),
+	)
+
+	require.Empty(t, convexMarginalCosts(nil))
+}
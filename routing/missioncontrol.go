@@ -71,6 +71,15 @@ const (
 	// amount may be raised.
 	DefaultMinFailureRelaxInterval = time.Minute
 
+	// DefaultPairLatency is the latency that is assumed for node pairs
+	// without latency measurements.
+	DefaultPairLatency = 500 * time.Millisecond
+
+	// latencyMinWeight is the minimum weight of a new latency measurement
+	// in the moving average of a pair. Until a pair has enough
+	// measurements to reach this weight, the average is a plain mean.
+	latencyMinWeight = 0.2
+
 	// DefaultFeeEstimationTimeout is the default value for
 	// FeeEstimationTimeout. It defines the maximum duration that the
 	// probing fee estimation is allowed to take.
@@ -146,6 +155,11 @@ type MissionController struct {
 	mc map[string]*MissionControl
 	mu sync.Mutex
 
+	// storesRunning indicates whether the store tickers are running, so
+	// that the stores of namespaces that are created later on are started
+	// as well.
+	storesRunning bool
+
 	// TODO(roasbeef): further counters, if vertex continually unavailable,
 	// add to another generation
 
@@ -164,7 +178,7 @@ func (m *MissionController) GetNamespacedStore(ns string) (*MissionControl,
 		return mc, nil
 	}
 
-	return m.initMissionControl(ns)
+	return m.initMissionControl(ns, m.defaultMCCfg)
 }
 
 // ListNamespaces returns a list of the namespaces that the MissionController
@@ -259,6 +273,23 @@ type MissionControlPairSnapshot struct {
 
 	// TimedPairResult contains the data for this pair.
 	TimedPairResult
+
+	// Latency contains the latency statistics of this pair.
+	Latency PairLatency
+}
+
+// PairLatency holds the latency statistics of a node pair.
+type PairLatency struct {
+	// Latency is the moving average of the time it took to forward an
+	// htlc over the pair and to relay the result back.
+	Latency time.Duration
+
+	// Samples is the number of measurements that the average is based
+	// on.
+	Samples uint32
+
+	// LastUpdate is the time of the last measurement.
+	LastUpdate time.Time
 }
 
 // paymentResult is the information that becomes available when a payment
@@ -345,7 +376,16 @@ func (m *MissionController) loadMissionControls() error {
 	defer m.mu.Unlock()
 
 	// Always initialise the default namespace.
-	_, err := m.initMissionControl(DefaultMissionControlNamespace)
+	_, err := m.initMissionControl(
+		DefaultMissionControlNamespace, m.defaultMCCfg,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Namespaces that were created with their own config are restored
+	// with it.
+	configs, err := m.fetchNamespaceConfigs()
 	if err != nil {
 		return err
 	}
@@ -378,7 +418,12 @@ func (m *MissionController) loadMissionControls() error {
 
 	// Now, iterate through all the namespaces and initialise them.
 	for ns := range namespaces {
-		_, err = m.initMissionControl(ns)
+		cfg, ok := configs[ns]
+		if !ok {
+			cfg = m.defaultMCCfg
+		}
+
+		_, err = m.initMissionControl(ns, cfg)
 		if err != nil {
 			return err
 		}
@@ -388,11 +433,11 @@ func (m *MissionController) loadMissionControls() error {
 }
 
 // initMissionControl creates a new MissionControl instance with the given
-// namespace if one does not yet exist.
+// namespace and config if one does not yet exist.
 //
 // NOTE: the MissionController's mutex must be held before calling this method.
-func (m *MissionController) initMissionControl(namespace string) (
-	*MissionControl, error) {
+func (m *MissionController) initMissionControl(namespace string,
+	cfg *MissionControlConfig) (*MissionControl, error) {
 
 	// If a mission control with this namespace has already been initialised
 	// then there is nothing left to do.
@@ -400,8 +445,6 @@ func (m *MissionController) initMissionControl(namespace string) (
 		return mc, nil
 	}
 
-	cfg := m.defaultMCCfg
-
 	store, err := newMissionControlStore(
 		newNamespacedDB(m.db, namespace), cfg.MaxMcHistory,
 		cfg.McFlushInterval,
@@ -421,6 +464,18 @@ func (m *MissionController) initMissionControl(namespace string) (
 		onConfigUpdate: cfg.OnConfigUpdate,
 	}
 
+	// Config updates of namespaces other than the default one are stored
+	// in the database.
+	if namespace != DefaultMissionControlNamespace {
+		mc.onConfigUpdate = m.onNamespaceConfigUpdate(namespace)
+	}
+
+	// If the stores are running already, the store of the new namespace
+	// is started right away.
+	if m.storesRunning {
+		mc.store.run()
+	}
+
 	m.mc[namespace] = mc
 
 	return mc, nil
@@ -434,6 +489,7 @@ func (m *MissionController) RunStoreTickers() {
 	for _, mc := range m.mc {
 		mc.store.run()
 	}
+	m.storesRunning = true
 }
 
 // StopStoreTickers stops the mission control store's tickers.
@@ -447,6 +503,7 @@ func (m *MissionController) StopStoreTickers() {
 	for _, mc := range m.mc {
 		mc.store.stop()
 	}
+	m.storesRunning = false
 }
 
 // init initializes mission control with historical data.
@@ -555,6 +612,23 @@ func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
 	)
 }
 
+// GetLatency returns the expected latency of forwarding an htlc from fromNode
+// to toNode and relaying the result back. If the pair hasn't been measured,
+// DefaultPairLatency is returned.
+func (m *MissionControl) GetLatency(fromNode,
+	toNode route.Vertex) time.Duration {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latency, ok := m.state.getPairLatency(fromNode, toNode)
+	if !ok {
+		return DefaultPairLatency
+	}
+
+	return latency.Latency
+}
+
 // GetHistorySnapshot takes a snapshot from the current mission control state
 // and actual probability estimates.
 func (m *MissionControl) GetHistorySnapshot() *MissionControlSnapshot {
@@ -609,34 +683,56 @@ func (m *MissionControl) GetPairHistorySnapshot(
 	return result
 }
 
+// FailureAttribution holds what the attribution data of a failure tells us
+// about the hops of the route.
+type FailureAttribution struct {
+	// FailingHopIdx is the index of the node whose HMAC in the attribution
+	// data of an unreadable failure was invalid. Either this node or the
+	// one before it garbled the failure. It is zero if the failure could
+	// be decrypted, or if we don't know which node garbled it.
+	FailingHopIdx int
+
+	// HoldTimes are the hold times reported by the hops, in route order.
+	// The hold time of a hop includes the hold times of all hops after it.
+	HoldTimes []time.Duration
+}
+
 // ReportPaymentFail reports a failed payment to mission control as input for
-// future probability estimates. The failureSourceIdx argument indicates the
-// failure source. If it is nil, the failure source is unknown. This function
-// returns a reason if this failure is a final failure. In that case no further
-// payment attempts need to be made.
-func (m *MissionControl) ReportPaymentFail(paymentID uint64, rt *route.Route,
-	failureSourceIdx *int, failure lnwire.FailureMessage) (
-	*channeldb.FailureReason, error) {
+// future probability estimates. The attemptTime is the time at which the htlc
+// was sent, which is used to measure the latency of the pairs. The
+// failureSourceIdx argument indicates the failure source. If it is nil, the
+// failure source is unknown. The attribution argument holds what the
+// attribution data of the failure tells us about the hops, and may be nil.
+// This function returns a reason if this failure is a final failure. In that
+// case no further payment attempts need to be made.
+func (m *MissionControl) ReportPaymentFail(paymentID uint64,
+	attemptTime time.Time, rt *route.Route, failureSourceIdx *int,
+	failure lnwire.FailureMessage,
+	attribution *FailureAttribution) (*channeldb.FailureReason, error) {
 
 	timestamp := m.cfg.clock.Now()
 
+	paymentFailure := newPaymentFailure(failureSourceIdx, failure)
+	paymentFailure.setAttribution(attribution)
+
 	result := newPaymentResult(
-		paymentID, extractMCRoute(rt), timestamp, timestamp,
-		newPaymentFailure(failureSourceIdx, failure),
+		paymentID, extractMCRoute(rt), attemptTime, timestamp,
+		paymentFailure,
 	)
 
 	return m.processPaymentResult(result)
 }
 
 // ReportPaymentSuccess reports a successful payment to mission control as input
-// for future probability estimates.
+// for future probability estimates. The attemptTime is the time at which the
+// htlc was sent, which is used to measure the latency of the pairs.
 func (m *MissionControl) ReportPaymentSuccess(paymentID uint64,
-	rt *route.Route) error {
+	attemptTime time.Time, rt *route.Route) error {
 
 	timestamp := m.cfg.clock.Now()
 
 	result := newPaymentResult(
-		paymentID, extractMCRoute(rt), timestamp, timestamp, nil,
+		paymentID, extractMCRoute(rt), attemptTime, timestamp, nil,
 	)
 
 	_, err := m.processPaymentResult(result)
@@ -726,9 +822,36 @@ func (m *MissionControl) applyPaymentResult(
 		)
 	}
 
+	m.applyLatency(result)
+
 	return i.finalFailureReason
 }
 
+// applyLatency records the latency of the pairs that the htlc of the payment
+// attempt traversed. The time between sending the htlc and receiving the
+// result is split evenly between these pairs. Results that don't reveal how
+// far the htlc got, and results without a send time, are skipped.
+func (m *MissionControl) applyLatency(result *paymentResult) {
+	var (
+		timeFwd   = time.Unix(0, int64(result.timeFwd.Val))
+		timeReply = time.Unix(0, int64(result.timeReply.Val))
+		roundTrip = timeReply.Sub(timeFwd)
+	)
+	if roundTrip <= 0 {
+		return
+	}
+
+	pairs := latencyPairs(&result.route.Val, result.failure.ValOpt())
+	if len(pairs) == 0 {
+		return
+	}
+
+	sample := roundTrip / time.Duration(len(pairs))
+	for _, pair := range pairs {
+		m.state.addLatencySample(pair, sample, timeReply)
+	}
+}
+
 // namespacedDB is an implementation of the missionControlDB that gives a user
 // of the interface access to a namespaced bucket within the top level mission
 // control bucket.
@@ -832,6 +955,14 @@ func (n *namespacedDB) purge() error {
 // not include additional information about said failure.
 type paymentFailure struct {
 	info tlv.OptionalRecordT[tlv.TlvType0, paymentFailureInfo]
+
+	// failingHopIdx is the index of the node whose HMAC in the attribution
+	// data of an unreadable failure was invalid.
+	failingHopIdx tlv.OptionalRecordT[tlv.TlvType1, uint8]
+
+	// holdTimes are the hold times reported by the hops in the attribution
+	// data of the failure.
+	holdTimes tlv.OptionalRecordT[tlv.TlvType2, holdTimes]
 }
 
 // newPaymentFailure constructs a new paymentFailure struct. If the source
@@ -857,6 +988,30 @@ func newPaymentFailure(sourceIdx *int,
 	}
 }
 
+// setAttribution adds what the attribution data of the failure tells us about
+// the hops to the payment failure.
+func (r *paymentFailure) setAttribution(attribution *FailureAttribution) {
+	if attribution == nil {
+		return
+	}
+
+	if attribution.FailingHopIdx > 0 {
+		r.failingHopIdx = tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType1](
+				uint8(attribution.FailingHopIdx),
+			),
+		)
+	}
+
+	if len(attribution.HoldTimes) > 0 {
+		r.holdTimes = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType2](
+				holdTimes(attribution.HoldTimes),
+			),
+		)
+	}
+}
+
 // Record returns a TLV record that can be used to encode/decode a
 // paymentFailure to/from a TLV stream.
 func (r *paymentFailure) Record() tlv.Record {
@@ -885,6 +1040,16 @@ func encodePaymentFailure(w io.Writer, val interface{}, _ *[8]byte) error {
 				recordProducers = append(recordProducers, &r)
 			},
 		)
+		v.failingHopIdx.WhenSome(
+			func(r tlv.RecordT[tlv.TlvType1, uint8]) {
+				recordProducers = append(recordProducers, &r)
+			},
+		)
+		v.holdTimes.WhenSome(
+			func(r tlv.RecordT[tlv.TlvType2, holdTimes]) {
+				recordProducers = append(recordProducers, &r)
+			},
+		)
 
 		return lnwire.EncodeRecordsTo(
 			w, lnwire.ProduceRecordsSorted(recordProducers...),
@@ -900,9 +1065,19 @@ func decodePaymentFailure(r io.Reader, val interface{}, _ *[8]byte,
 	if v, ok := val.(*paymentFailure); ok {
 		var h paymentFailure
 
-		info := tlv.ZeroRecordT[tlv.TlvType0, paymentFailureInfo]()
+		var (
+			info = tlv.ZeroRecordT[
+				tlv.TlvType0, paymentFailureInfo,
+			]()
+			failingHopIdx = tlv.ZeroRecordT[tlv.TlvType1, uint8]()
+			times         = tlv.ZeroRecordT[
+				tlv.TlvType2, holdTimes,
+			]()
+		)
 		typeMap, err := lnwire.DecodeRecords(
-			r, lnwire.ProduceRecordsSorted(&info)...,
+			r, lnwire.ProduceRecordsSorted(
+				&info, &failingHopIdx, &times,
+			)...,
 		)
 		if err != nil {
 			return err
@@ -911,6 +1086,12 @@ func decodePaymentFailure(r io.Reader, val interface{}, _ *[8]byte,
 		if _, ok := typeMap[h.info.TlvType()]; ok {
 			h.info = tlv.SomeRecordT(info)
 		}
+		if _, ok := typeMap[h.failingHopIdx.TlvType()]; ok {
+			h.failingHopIdx = tlv.SomeRecordT(failingHopIdx)
+		}
+		if _, ok := typeMap[h.holdTimes.TlvType()]; ok {
+			h.holdTimes = tlv.SomeRecordT(times)
+		}
 
 		*v = h
 
@@ -965,8 +1146,10 @@ func decodePaymentFailureInfo(r io.Reader, val interface{}, _ *[8]byte,
 	if v, ok := val.(*paymentFailureInfo); ok {
 		var h paymentFailureInfo
 
+		// The info is followed by the attribution records of the
+		// failure, so we must not read beyond its own length.
 		_, err := lnwire.DecodeRecords(
-			r,
+			io.LimitReader(r, int64(l)),
 			lnwire.ProduceRecordsSorted(&h.sourceIdx, &h.msg)...,
 		)
 		if err != nil {
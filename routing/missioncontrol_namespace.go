@@ -0,0 +1,344 @@
+package routing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// mcNamespaceConfigKey is the key of the top level bucket that holds
+	// the configs of the mission control namespaces. The default namespace
+	// isn't part of it, as its config is taken from the lnd config.
+	//
+	// maps: namespace -> serialized config
+	mcNamespaceConfigKey = []byte("missioncontrol-namespace-configs")
+
+	// ErrNamespaceExists is returned when a mission control namespace is
+	// created that already exists.
+	ErrNamespaceExists = errors.New("mission control namespace already " +
+		"exists")
+
+	// ErrNamespaceNotFound is returned when a mission control namespace
+	// is requested that doesn't exist.
+	ErrNamespaceNotFound = errors.New("mission control namespace not " +
+		"found")
+
+	// ErrDefaultNamespace is returned when the default mission control
+	// namespace is deleted.
+	ErrDefaultNamespace = errors.New("the default mission control " +
+		"namespace can't be deleted")
+)
+
+// FetchNamespacedStore returns the MissionControl in the given namespace. In
+// contrast to GetNamespacedStore, ErrNamespaceNotFound is returned if the
+// namespace doesn't exist yet. An empty namespace refers to the default one.
+func (m *MissionController) FetchNamespacedStore(ns string) (*MissionControl,
+	error) {
+
+	if ns == "" {
+		ns = DefaultMissionControlNamespace
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mc, ok := m.mc[ns]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNamespaceNotFound, ns)
+	}
+
+	return mc, nil
+}
+
+// CreateNamespace creates a new mission control namespace that estimates
+// probabilities with the given config. If the config is nil, the config of
+// the default namespace is used. The config is persisted, so that it is
+// restored on restart.
+func (m *MissionController) CreateNamespace(ns string,
+	cfg *MissionControlConfig) (*MissionControl, error) {
+
+	if ns == "" {
+		return nil, errors.New("empty mission control namespace")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.mc[ns]; ok {
+		return nil, fmt.Errorf("%w: %v", ErrNamespaceExists, ns)
+	}
+
+	if cfg == nil {
+		cfg = m.mc[DefaultMissionControlNamespace].GetConfig()
+	}
+
+	if cfg.Estimator == nil {
+		return nil, errors.New("no estimator configured")
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	// The flush interval isn't configurable per namespace.
+	nsCfg := *cfg
+	nsCfg.McFlushInterval = m.defaultMCCfg.McFlushInterval
+
+	if err := m.storeNamespaceConfig(ns, &nsCfg); err != nil {
+		return nil, err
+	}
+
+	return m.initMissionControl(ns, &nsCfg)
+}
+
+// DeleteNamespace deletes a mission control namespace along with its payment
+// results and config. Payments that are still in flight in the namespace
+// report their results to the deleted namespace, without them being stored.
+func (m *MissionController) DeleteNamespace(ns string) error {
+	if ns == "" || ns == DefaultMissionControlNamespace {
+		return ErrDefaultNamespace
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mc, ok := m.mc[ns]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNamespaceNotFound, ns)
+	}
+
+	if m.storesRunning {
+		mc.store.stop()
+	}
+
+	err := kvdb.Update(m.db, func(tx kvdb.RwTx) error {
+		resultsBkt := tx.ReadWriteBucket(resultsKey)
+		if resultsBkt != nil {
+			err := resultsBkt.DeleteNestedBucket([]byte(ns))
+			if err != nil &&
+				!errors.Is(err, walletdb.ErrBucketNotFound) {
+
+				return err
+			}
+		}
+
+		configBkt := tx.ReadWriteBucket(mcNamespaceConfigKey)
+		if configBkt == nil {
+			return nil
+		}
+
+		return configBkt.Delete([]byte(ns))
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	delete(m.mc, ns)
+
+	log.Infof("Deleted mission control namespace %v", ns)
+
+	return nil
+}
+
+// storeNamespaceConfig persists the config of the given namespace.
+func (m *MissionController) storeNamespaceConfig(ns string,
+	cfg *MissionControlConfig) error {
+
+	value, err := serializeNamespaceConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(m.db, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(mcNamespaceConfigKey)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(ns), value)
+	}, func() {})
+}
+
+// fetchNamespaceConfigs returns the persisted configs of all namespaces. The
+// flush interval of the configs is taken from the default config.
+func (m *MissionController) fetchNamespaceConfigs() (
+	map[string]*MissionControlConfig, error) {
+
+	var configs map[string]*MissionControlConfig
+	err := kvdb.View(m.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(mcNamespaceConfigKey)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			cfg, err := deserializeNamespaceConfig(v)
+			if err != nil {
+				return fmt.Errorf("invalid config of mission "+
+					"control namespace %s: %w", k, err)
+			}
+			cfg.McFlushInterval = m.defaultMCCfg.McFlushInterval
+
+			configs[string(k)] = cfg
+
+			return nil
+		})
+	}, func() {
+		configs = make(map[string]*MissionControlConfig)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// onNamespaceConfigUpdate returns the callback that persists config updates
+// of the given namespace.
+func (m *MissionController) onNamespaceConfigUpdate(
+	ns string) fn.Option[func(cfg *MissionControlConfig)] {
+
+	return fn.Some(func(cfg *MissionControlConfig) {
+		if err := m.storeNamespaceConfig(ns, cfg); err != nil {
+			log.Errorf("Unable to store config of mission control "+
+				"namespace %v: %v", ns, err)
+		}
+	})
+}
+
+// namespaceConfigRecords returns the tlv records of a namespace config. All
+// estimator parameters are included, the ones of the estimator that isn't used
+// are zero.
+func namespaceConfigRecords(maxHistory, minRelax *uint64, estimator *[]byte,
+	params *[7]uint64) []tlv.Record {
+
+	const (
+		// NOTE: A migration should be added whenever the existing type
+		// changes.
+		maxHistoryType       tlv.Type = 0
+		minRelaxIntervalType tlv.Type = 1
+		estimatorType        tlv.Type = 2
+		halfLifeType         tlv.Type = 3
+		hopProbabilityType   tlv.Type = 4
+		aprioriWeightType    tlv.Type = 5
+		capacityFractionType tlv.Type = 6
+		nodeWeightType       tlv.Type = 7
+		scaleType            tlv.Type = 8
+		decayTimeType        tlv.Type = 9
+	)
+
+	return []tlv.Record{
+		tlv.MakePrimitiveRecord(maxHistoryType, maxHistory),
+		tlv.MakePrimitiveRecord(minRelaxIntervalType, minRelax),
+		tlv.MakePrimitiveRecord(estimatorType, estimator),
+		tlv.MakePrimitiveRecord(halfLifeType, &params[0]),
+		tlv.MakePrimitiveRecord(hopProbabilityType, &params[1]),
+		tlv.MakePrimitiveRecord(aprioriWeightType, &params[2]),
+		tlv.MakePrimitiveRecord(capacityFractionType, &params[3]),
+		tlv.MakePrimitiveRecord(nodeWeightType, &params[4]),
+		tlv.MakePrimitiveRecord(scaleType, &params[5]),
+		tlv.MakePrimitiveRecord(decayTimeType, &params[6]),
+	}
+}
+
+// serializeNamespaceConfig serializes the config of a namespace, excluding the
+// flush interval.
+func serializeNamespaceConfig(cfg *MissionControlConfig) ([]byte, error) {
+	var (
+		maxHistory = uint64(cfg.MaxMcHistory)
+		minRelax   = uint64(cfg.MinFailureRelaxInterval)
+		estimator  []byte
+		params     [7]uint64
+	)
+
+	switch c := cfg.Estimator.Config().(type) {
+	case AprioriConfig:
+		estimator = []byte(AprioriEstimatorName)
+		params[0] = uint64(c.PenaltyHalfLife)
+		params[1] = math.Float64bits(c.AprioriHopProbability)
+		params[2] = math.Float64bits(c.AprioriWeight)
+		params[3] = math.Float64bits(c.CapacityFraction)
+
+	case BimodalConfig:
+		estimator = []byte(BimodalEstimatorName)
+		params[4] = math.Float64bits(c.BimodalNodeWeight)
+		params[5] = uint64(c.BimodalScaleMsat)
+		params[6] = uint64(c.BimodalDecayTime)
+
+	default:
+		return nil, fmt.Errorf("unknown estimator config type %T", c)
+	}
+
+	stream, err := tlv.NewStream(namespaceConfigRecords(
+		&maxHistory, &minRelax, &estimator, &params,
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// deserializeNamespaceConfig deserializes the config of a namespace.
+func deserializeNamespaceConfig(b []byte) (*MissionControlConfig, error) {
+	var (
+		maxHistory, minRelax uint64
+		estimatorName        []byte
+		params               [7]uint64
+	)
+
+	stream, err := tlv.NewStream(namespaceConfigRecords(
+		&maxHistory, &minRelax, &estimatorName, &params,
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Decode(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	var estimator Estimator
+	switch string(estimatorName) {
+	case AprioriEstimatorName:
+		estimator, err = NewAprioriEstimator(AprioriConfig{
+			PenaltyHalfLife:       time.Duration(params[0]),
+			AprioriHopProbability: math.Float64frombits(params[1]),
+			AprioriWeight:         math.Float64frombits(params[2]),
+			CapacityFraction:      math.Float64frombits(params[3]),
+		})
+
+	case BimodalEstimatorName:
+		estimator, err = NewBimodalEstimator(BimodalConfig{
+			BimodalNodeWeight: math.Float64frombits(params[4]),
+			BimodalScaleMsat:  lnwire.MilliSatoshi(params[5]),
+			BimodalDecayTime:  time.Duration(params[6]),
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown estimator %s", estimatorName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &MissionControlConfig{
+		Estimator:               estimator,
+		MaxMcHistory:            int(maxHistory),
+		MinFailureRelaxInterval: time.Duration(minRelax),
+	}, nil
+}
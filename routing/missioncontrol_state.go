@@ -20,6 +20,10 @@ type missionControlState struct {
 	// a directed node pair.
 	lastSecondChance map[DirectedNodePair]time.Time
 
+	// pairLatency tracks the latency statistics of each directed node
+	// pair.
+	pairLatency map[DirectedNodePair]PairLatency
+
 	// minFailureRelaxInterval is the minimum time that must have passed
 	// since the previously recorded failure before the failure amount may
 	// be raised.
@@ -33,6 +37,7 @@ func newMissionControlState(
 	return &missionControlState{
 		lastPairResult:          make(map[route.Vertex]NodeResults),
 		lastSecondChance:        make(map[DirectedNodePair]time.Time),
+		pairLatency:             make(map[DirectedNodePair]PairLatency),
 		minFailureRelaxInterval: minFailureRelaxInterval,
 	}
 }
@@ -51,6 +56,38 @@ func (m *missionControlState) getLastPairResult(node route.Vertex) (NodeResults,
 func (m *missionControlState) resetHistory() {
 	m.lastPairResult = make(map[route.Vertex]NodeResults)
 	m.lastSecondChance = make(map[DirectedNodePair]time.Time)
+	m.pairLatency = make(map[DirectedNodePair]PairLatency)
+}
+
+// getPairLatency returns the latency statistics of the given node pair.
+func (m *missionControlState) getPairLatency(fromNode,
+	toNode route.Vertex) (PairLatency, bool) {
+
+	latency, ok := m.pairLatency[NewDirectedNodePair(fromNode, toNode)]
+
+	return latency, ok
+}
+
+// addLatencySample adds a latency measurement to the moving average of the
+// node pair. The first measurements are averaged evenly, after which the
+// weight of a new measurement doesn't drop below latencyMinWeight. This lets
+// the average follow changes in the latency of the pair.
+func (m *missionControlState) addLatencySample(pair DirectedNodePair,
+	sample time.Duration, timestamp time.Time) {
+
+	current := m.pairLatency[pair]
+
+	current.Samples++
+	weight := max(1/float64(current.Samples), latencyMinWeight)
+	current.Latency += time.Duration(
+		weight * float64(sample-current.Latency),
+	)
+	current.LastUpdate = timestamp
+
+	log.Tracef("Setting %v->%v latency to %v (%v samples)", pair.From,
+		pair.To, current.Latency, current.Samples)
+
+	m.pairLatency[pair] = current
 }
 
 // setLastPairResult stores a result for a node pair.
@@ -200,12 +237,25 @@ func (m *missionControlState) getSnapshot() *MissionControlSnapshot {
 			pairSnapshot := MissionControlPairSnapshot{
 				Pair:            pair,
 				TimedPairResult: result,
+				Latency:         m.pairLatency[pair],
 			}
 
 			pairs = append(pairs, pairSnapshot)
 		}
 	}
 
+	// Also include the pairs of which we only know the latency.
+	for pair, latency := range m.pairLatency {
+		if _, ok := m.lastPairResult[pair.From][pair.To]; ok {
+			continue
+		}
+
+		pairs = append(pairs, MissionControlPairSnapshot{
+			Pair:    pair,
+			Latency: latency,
+		})
+	}
+
 	snapshot := MissionControlSnapshot{
 		Pairs: pairs,
 	}
@@ -225,6 +275,13 @@ func (m *missionControlState) importSnapshot(snapshot *MissionControlSnapshot,
 		fromNode := pair.Pair.From
 		toNode := pair.Pair.To
 
+		imported += m.importLatency(pair.Pair, pair.Latency, force)
+
+		// Pairs of which we only know the latency carry no results.
+		if pair.FailTime.IsZero() && pair.SuccessTime.IsZero() {
+			continue
+		}
+
 		results, found := m.getLastPairResult(fromNode)
 		if !found {
 			results = make(map[route.Vertex]TimedPairResult)
@@ -267,3 +324,22 @@ func (m *missionControlState) importResult(currentTs, importedTs time.Time,
 
 	return 1
 }
+
+// importLatency replaces the latency statistics of the pair with the imported
+// ones if they are more recent. It returns the number of imported statistics.
+func (m *missionControlState) importLatency(pair DirectedNodePair,
+	imported PairLatency, force bool) int {
+
+	if imported.Samples == 0 {
+		return 0
+	}
+
+	current := m.pairLatency[pair]
+	if !force && current.LastUpdate.After(imported.LastUpdate) {
+		return 0
+	}
+
+	m.pairLatency[pair] = imported
+
+	return 1
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"sync"
 	"time"
 
@@ -546,6 +547,65 @@ func getResultKey(rp *paymentResult) []byte {
 	return keyBytes[:]
 }
 
+// holdTimes wraps the hold times reported by the hops of a route such that we
+// can apply a Record method and use them in a TLV encoded type. Every hold
+// time is encoded as a number of milliseconds.
+type holdTimes []time.Duration
+
+// Record returns a TLV record that can be used to encode/decode a list of
+// hold times to/from a TLV stream.
+func (h *holdTimes) Record() tlv.Record {
+	recordSize := func() uint64 {
+		return uint64(len(*h) * 4)
+	}
+
+	return tlv.MakeDynamicRecord(
+		0, h, recordSize, encodeHoldTimes, decodeHoldTimes,
+	)
+}
+
+func encodeHoldTimes(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*holdTimes); ok {
+		for _, holdTime := range *v {
+			millis := holdTime.Milliseconds()
+			if millis > math.MaxUint32 {
+				millis = math.MaxUint32
+			}
+
+			err := tlv.EUint32T(w, uint32(millis), buf)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "routing.holdTimes")
+}
+
+func decodeHoldTimes(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*holdTimes); ok && l%4 == 0 {
+		h := make(holdTimes, 0, l/4)
+		for i := uint64(0); i < l/4; i++ {
+			var millis uint32
+			if err := tlv.DUint32(r, &millis, buf, 4); err != nil {
+				return err
+			}
+
+			h = append(h, time.Duration(millis)*time.Millisecond)
+		}
+
+		*v = h
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "routing.holdTimes", l, l)
+}
+
 // failureMessage wraps the lnwire.FailureMessage interface such that we can
 // apply a Record method and use the failureMessage in a TLV encoded type.
 type failureMessage struct {
@@ -90,13 +90,20 @@ func TestMissionControlStore(t *testing.T) {
 		),
 	)
 
+	// The second result also carries what the attribution data of the
+	// failure told us.
+	failure2 := newPaymentFailure(
+		&failureSourceIdx, lnwire.NewFailIncorrectDetails(100, 1000),
+	)
+	failure2.setAttribution(&FailureAttribution{
+		HoldTimes: []time.Duration{
+			1500 * time.Millisecond, 300 * time.Millisecond,
+		},
+	})
+
 	result2 := newPaymentResult(
 		2, mcStoreTestRoute, testTime.Add(time.Hour),
-		testTime.Add(time.Hour),
-		newPaymentFailure(
-			&failureSourceIdx,
-			lnwire.NewFailIncorrectDetails(100, 1000),
-		),
+		testTime.Add(time.Hour), failure2,
 	)
 
 	// Store result.
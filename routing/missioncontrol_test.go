@@ -147,13 +147,16 @@ func (ctx *mcTestContext) reportFailure(amt lnwire.MilliSatoshi,
 
 	errorSourceIdx := 1
 	ctx.mc.ReportPaymentFail(
-		ctx.pid, mcTestRoute, &errorSourceIdx, failure,
+		ctx.pid, ctx.clock.Now(), mcTestRoute, &errorSourceIdx,
+		failure, nil,
 	)
 }
 
 // reportSuccess reports a success by using a test route.
 func (ctx *mcTestContext) reportSuccess() {
-	err := ctx.mc.ReportPaymentSuccess(ctx.pid, mcTestRoute)
+	err := ctx.mc.ReportPaymentSuccess(
+		ctx.pid, ctx.clock.Now(), mcTestRoute,
+	)
 	if err != nil {
 		ctx.t.Fatal(err)
 	}
@@ -226,6 +229,74 @@ func TestMissionControl(t *testing.T) {
 	ctx.reportSuccess()
 }
 
+// TestMissionControlLatency tests that the time between sending an htlc and
+// receiving its result is recorded as the latency of the traversed pairs, and
+// that the latencies survive a restart.
+func TestMissionControlLatency(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	// Without measurements, the default latency is assumed.
+	latency := ctx.mc.GetLatency(mcTestSelf, mcTestNode1)
+	require.Equal(t, DefaultPairLatency, latency)
+
+	// A success is split evenly over both pairs of the route.
+	err := ctx.mc.ReportPaymentSuccess(
+		ctx.pid, mcTestTime.Add(-400*time.Millisecond), mcTestRoute,
+	)
+	require.NoError(t, err)
+	ctx.pid++
+
+	expectLatency := func(from, to route.Vertex, latency time.Duration) {
+		t.Helper()
+
+		require.Equal(t, latency, ctx.mc.GetLatency(from, to))
+	}
+	expectLatency(mcTestSelf, mcTestNode1, 200*time.Millisecond)
+	expectLatency(mcTestNode1, mcTestNode2, 200*time.Millisecond)
+
+	// A failure at the first hop only measures the first pair. The first
+	// measurements are averaged evenly.
+	errorSourceIdx := 1
+	_, err = ctx.mc.ReportPaymentFail(
+		ctx.pid, mcTestTime.Add(-100*time.Millisecond), mcTestRoute,
+		&errorSourceIdx, lnwire.NewTemporaryChannelFailure(nil), nil,
+	)
+	require.NoError(t, err)
+	ctx.pid++
+
+	expectLatency(mcTestSelf, mcTestNode1, 150*time.Millisecond)
+	expectLatency(mcTestNode1, mcTestNode2, 200*time.Millisecond)
+
+	// A failure of which the source is unknown isn't measured.
+	_, err = ctx.mc.ReportPaymentFail(
+		ctx.pid, mcTestTime.Add(-time.Second), mcTestRoute, nil, nil,
+		nil,
+	)
+	require.NoError(t, err)
+	ctx.pid++
+
+	expectLatency(mcTestSelf, mcTestNode1, 150*time.Millisecond)
+
+	// The latencies are rebuilt from the stored results.
+	ctx.restartMc()
+	expectLatency(mcTestSelf, mcTestNode1, 150*time.Millisecond)
+	expectLatency(mcTestNode1, mcTestNode2, 200*time.Millisecond)
+
+	// The snapshot contains the latency statistics.
+	var found bool
+	for _, pair := range ctx.mc.GetHistorySnapshot().Pairs {
+		if pair.Pair != NewDirectedNodePair(mcTestSelf, mcTestNode1) {
+			continue
+		}
+
+		found = true
+		require.Equal(t, 150*time.Millisecond, pair.Latency.Latency)
+		require.EqualValues(t, 2, pair.Latency.Samples)
+		require.True(t, pair.Latency.LastUpdate.Equal(mcTestTime))
+	}
+	require.True(t, found)
+}
+
 // TestMissionControlChannelUpdate tests that the first channel update is not
 // penalizing the channel yet.
 func TestMissionControlChannelUpdate(t *testing.T) {
@@ -311,6 +382,93 @@ func TestMissionControlNamespaces(t *testing.T) {
 	ctx.expectP(1000, testAprioriHopProbability+0.05)
 }
 
+// TestMissionControlNamespaceLifecycle tests that namespaces can be created
+// with their own config, and that they can be deleted again.
+func TestMissionControlNamespaceLifecycle(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	// The default namespace can be fetched, an unknown one can't.
+	_, err := ctx.mcController.FetchNamespacedStore("")
+	require.NoError(t, err)
+
+	const newNs = "bimodal-namespace"
+	_, err = ctx.mcController.FetchNamespacedStore(newNs)
+	require.ErrorIs(t, err, ErrNamespaceNotFound)
+
+	bCfg := BimodalConfig{
+		BimodalNodeWeight: 0.3,
+		BimodalScaleMsat:  400_000,
+		BimodalDecayTime:  time.Hour,
+	}
+	estimator, err := NewBimodalEstimator(bCfg)
+	require.NoError(t, err)
+
+	_, err = ctx.mcController.CreateNamespace(newNs, &MissionControlConfig{
+		Estimator:               estimator,
+		MaxMcHistory:            50,
+		MinFailureRelaxInterval: time.Minute,
+	})
+	require.NoError(t, err)
+
+	_, err = ctx.mcController.CreateNamespace(newNs, nil)
+	require.ErrorIs(t, err, ErrNamespaceExists)
+
+	// Report a result, so that the namespace has results to delete.
+	ctx.setNamespacedMC(newNs)
+	ctx.reportSuccess()
+
+	// The config is restored after a restart.
+	ctx.restartMc()
+	mc, err := ctx.mcController.FetchNamespacedStore(newNs)
+	require.NoError(t, err)
+
+	cfg := mc.GetConfig()
+	require.Equal(t, bCfg, cfg.Estimator.Config())
+	require.Equal(t, 50, cfg.MaxMcHistory)
+	require.Equal(t, time.Minute, cfg.MinFailureRelaxInterval)
+
+	// Config updates are persisted as well.
+	aCfg := AprioriConfig{
+		PenaltyHalfLife:       time.Hour,
+		AprioriHopProbability: 0.7,
+		AprioriWeight:         0.2,
+		CapacityFraction:      0.9,
+	}
+	aprioriEstimator, err := NewAprioriEstimator(aCfg)
+	require.NoError(t, err)
+	require.NoError(t, mc.SetConfig(&MissionControlConfig{
+		Estimator:    aprioriEstimator,
+		MaxMcHistory: 10,
+	}))
+
+	ctx.restartMc()
+	mc, err = ctx.mcController.FetchNamespacedStore(newNs)
+	require.NoError(t, err)
+	require.Equal(t, aCfg, mc.GetConfig().Estimator.Config())
+	require.Len(t, mc.GetHistorySnapshot().Pairs, 2)
+
+	// The default namespace can't be deleted.
+	err = ctx.mcController.DeleteNamespace(DefaultMissionControlNamespace)
+	require.ErrorIs(t, err, ErrDefaultNamespace)
+
+	// After deleting the namespace, neither its results nor its config
+	// come back on restart.
+	require.NoError(t, ctx.mcController.DeleteNamespace(newNs))
+	require.ErrorIs(
+		t, ctx.mcController.DeleteNamespace(newNs),
+		ErrNamespaceNotFound,
+	)
+
+	ctx.restartMc()
+	require.ElementsMatch(t, ctx.mcController.ListNamespaces(), []string{
+		DefaultMissionControlNamespace,
+	})
+
+	configs, err := ctx.mcController.fetchNamespaceConfigs()
+	require.NoError(t, err)
+	require.Empty(t, configs)
+}
+
 // testClock is an implementation of clock.Clock that lets the caller overwrite
 // the current time at any point.
 type testClock struct {
@@ -108,13 +108,13 @@ func (m *mockNode) fwd(from *mockNode, route *hop) (htlcResult, error) {
 
 // mockGraph contains a set of nodes that together for a mocked graph.
 type mockGraph struct {
-	t      *testing.T
+	t      testing.TB
 	nodes  map[route.Vertex]*mockNode
 	source *mockNode
 }
 
 // newMockGraph instantiates a new mock graph.
-func newMockGraph(t *testing.T) *mockGraph {
+func newMockGraph(t testing.TB) *mockGraph {
 	return &mockGraph{
 		nodes: make(map[route.Vertex]*mockNode),
 		t:     t,
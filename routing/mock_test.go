@@ -3,6 +3,7 @@ package routing
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -131,9 +132,9 @@ type mockMissionControlOld struct {
 var _ MissionControlQuerier = (*mockMissionControlOld)(nil)
 
 func (m *mockMissionControlOld) ReportPaymentFail(
-	paymentID uint64, rt *route.Route,
-	failureSourceIdx *int, failure lnwire.FailureMessage) (
-	*channeldb.FailureReason, error) {
+	paymentID uint64, _ time.Time, rt *route.Route,
+	failureSourceIdx *int, failure lnwire.FailureMessage,
+	_ *FailureAttribution) (*channeldb.FailureReason, error) {
 
 	// Report a permanent failure if this is an error caused
 	// by incorrect details.
@@ -146,7 +147,7 @@ func (m *mockMissionControlOld) ReportPaymentFail(
 }
 
 func (m *mockMissionControlOld) ReportPaymentSuccess(paymentID uint64,
-	rt *route.Route) error {
+	_ time.Time, rt *route.Route) error {
 
 	return nil
 }
@@ -157,6 +158,12 @@ func (m *mockMissionControlOld) GetProbability(fromNode, toNode route.Vertex,
 	return 0
 }
 
+func (m *mockMissionControlOld) GetLatency(fromNode,
+	toNode route.Vertex) time.Duration {
+
+	return DefaultPairLatency
+}
+
 type mockPaymentSessionOld struct {
 	routes []*route.Route
 
@@ -660,9 +667,9 @@ type mockMissionControl struct {
 var _ MissionControlQuerier = (*mockMissionControl)(nil)
 
 func (m *mockMissionControl) ReportPaymentFail(
-	paymentID uint64, rt *route.Route,
-	failureSourceIdx *int, failure lnwire.FailureMessage) (
-	*channeldb.FailureReason, error) {
+	paymentID uint64, _ time.Time, rt *route.Route,
+	failureSourceIdx *int, failure lnwire.FailureMessage,
+	_ *FailureAttribution) (*channeldb.FailureReason, error) {
 
 	args := m.Called(paymentID, rt, failureSourceIdx, failure)
 
@@ -675,7 +682,7 @@ func (m *mockMissionControl) ReportPaymentFail(
 }
 
 func (m *mockMissionControl) ReportPaymentSuccess(paymentID uint64,
-	rt *route.Route) error {
+	_ time.Time, rt *route.Route) error {
 
 	args := m.Called(paymentID, rt)
 	return args.Error(0)
@@ -688,6 +695,13 @@ func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
 	return args.Get(0).(float64)
 }
 
+func (m *mockMissionControl) GetLatency(fromNode,
+	toNode route.Vertex) time.Duration {
+
+	args := m.Called(fromNode, toNode)
+	return args.Get(0).(time.Duration)
+}
+
 type mockPaymentSession struct {
 	mock.Mock
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"time"
 
@@ -116,6 +117,10 @@ type finalHopParams struct {
 	// metadata is additional data that is sent along with the payment to
 	// the payee.
 	metadata []byte
+
+	// trampolineOnion is the serialized trampoline onion to deliver to
+	// the final hop, which must be a trampoline node.
+	trampolineOnion []byte
 }
 
 // newRoute constructs a route using the provided path and final hop constraints.
@@ -185,6 +190,7 @@ func newRoute(sourceVertex route.Vertex,
 			customRecords       record.CustomSet
 			mpp                 *record.MPP
 			metadata            []byte
+			trampolineOnion     []byte
 		)
 
 		// Define a helper function that checks this edge's feature
@@ -240,6 +246,15 @@ func newRoute(sourceVertex route.Vertex,
 
 			metadata = finalHop.metadata
 
+			// A trampoline onion can only be handed to a node that
+			// signals support for trampoline routing.
+			trampoline := supports(lnwire.TrampolineRoutingOptional)
+			if !trampoline && finalHop.trampolineOnion != nil {
+				return nil, errors.New("cannot attach " +
+					"trampoline onion")
+			}
+			trampolineOnion = finalHop.trampolineOnion
+
 			if blindedPathSet != nil {
 				totalAmtMsatBlinded = finalHop.totalAmt
 			}
@@ -287,6 +302,7 @@ func newRoute(sourceVertex route.Vertex,
 			MPP:              mpp,
 			Metadata:         metadata,
 			TotalAmtMsat:     totalAmtMsatBlinded,
+			TrampolineOnion:  trampolineOnion,
 		}
 
 		hops = append([]*route.Hop{currentHop}, hops...)
@@ -391,6 +407,20 @@ func edgeWeight(lockedAmt lnwire.MilliSatoshi, fee lnwire.MilliSatoshi,
 	return int64(fee) + timeLockPenalty
 }
 
+// latencyPenalty returns the virtual cost of the expected latency of
+// forwarding an htlc from fromNode to toNode.
+func latencyPenalty(r *RestrictParams, cfg *PathFindingConfig, fromNode,
+	toNode route.Vertex) int64 {
+
+	if r.LatencySource == nil || cfg.LatencyCost == 0 {
+		return 0
+	}
+
+	latency := r.LatencySource(fromNode, toNode)
+
+	return int64(float64(cfg.LatencyCost) * latency.Seconds())
+}
+
 // graphParams wraps the set of graph parameters passed to findPath.
 type graphParams struct {
 	// graph is the ChannelGraph to be used during path finding.
@@ -420,6 +450,18 @@ type RestrictParams struct {
 	ProbabilitySource func(route.Vertex, route.Vertex,
 		lnwire.MilliSatoshi, btcutil.Amount) float64
 
+	// LatencySource is an optional callback that is expected to return the
+	// expected latency of forwarding an htlc from the first to the second
+	// node and relaying the result back. If nil, latency isn't taken into
+	// account.
+	LatencySource func(route.Vertex, route.Vertex) time.Duration
+
+	// EdgeRejected is an optional callback that is invoked whenever path
+	// finding discards an edge for a reason other than a better
+	// alternative being known already.
+	EdgeRejected func(fromNode, toNode route.Vertex,
+		amt lnwire.MilliSatoshi, reason EdgeRejectReason)
+
 	// FeeLimit is a maximum fee amount allowed to be used on the path from
 	// the source to the target.
 	FeeLimit lnwire.MilliSatoshi
@@ -460,6 +502,11 @@ type RestrictParams struct {
 	// the payee.
 	Metadata []byte
 
+	// TrampolineOnion is the trampoline onion that is delivered to the
+	// final hop, if any. It is needed to determine the size of the last
+	// hop's payload.
+	TrampolineOnion []byte
+
 	// BlindedPaymentPathSet is necessary to determine the hop size of the
 	// last/exit hop.
 	BlindedPaymentPathSet *BlindedPaymentPathSet
@@ -486,6 +533,12 @@ type PathFindingConfig struct {
 	// MinProbability defines the minimum success probability of the
 	// returned route.
 	MinProbability float64
+
+	// LatencyCost is the virtual cost per second of expected latency of a
+	// route. It expresses the time preference of payments by trading off
+	// fees against the latency of the pairs as measured by mission
+	// control. If zero, latency isn't taken into account.
+	LatencyCost lnwire.MilliSatoshi
 }
 
 // getOutgoingBalance returns the maximum available balance in any of the
@@ -708,30 +761,26 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	// if the cltv limit is MaxUint32.
 	absoluteCltvLimit := uint64(r.CltvLimit) + uint64(finalHtlcExpiry)
 
-	// Calculate the default attempt cost as configured globally.
-	defaultAttemptCost := float64(
-		cfg.AttemptCost +
-			amt*lnwire.MilliSatoshi(cfg.AttemptCostPPM)/1000000,
-	)
-
-	// Validate time preference value.
-	if math.Abs(timePref) > 1 {
-		return nil, 0, fmt.Errorf("time preference %v out of range "+
-			"[-1, 1]", timePref)
+	absoluteAttemptCost, err := getAbsoluteAttemptCost(cfg, amt, timePref)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Scale to avoid the extremes -1 and 1 which run into infinity issues.
-	timePref *= 0.9
-
-	// Apply time preference. At 0, the default attempt cost will
-	// be used.
-	absoluteAttemptCost := defaultAttemptCost * (1/(0.5-timePref/2) - 1)
-
 	log.Debugf("Pathfinding absolute attempt cost: %v sats",
 		absoluteAttemptCost/1000)
 
 	// processEdge is a helper closure that will be used to make sure edges
 	// satisfy our specific requirements.
+	// rejectEdge reports an edge that is discarded to the caller, if
+	// requested.
+	rejectEdge := func(fromNode, toNode route.Vertex,
+		amt lnwire.MilliSatoshi, reason EdgeRejectReason) {
+
+		if r.EdgeRejected != nil {
+			r.EdgeRejected(fromNode, toNode, amt, reason)
+		}
+	}
+
 	processEdge := func(fromVertex route.Vertex,
 		edge *unifiedEdge, toNodeDist *nodeWithDist) {
 
@@ -774,6 +823,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}))
 
 		if totalFee > 0 && lnwire.MilliSatoshi(totalFee) > r.FeeLimit {
+			rejectEdge(
+				fromVertex, toNodeDist.node, amountToSend,
+				EdgeRejectFeeLimit,
+			)
+
 			return
 		}
 
@@ -792,6 +846,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 		// If the probability is zero, there is no point in trying.
 		if edgeProbability == 0 {
+			rejectEdge(
+				fromVertex, toNodeDist.node, amountToSend,
+				EdgeRejectZeroProbability,
+			)
+
 			return
 		}
 
@@ -821,6 +880,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 		// Check that we are within our CLTV limit.
 		if uint64(incomingCltv) > absoluteCltvLimit {
+			rejectEdge(
+				fromVertex, toNodeDist.node, amountToSend,
+				EdgeRejectCltvLimit,
+			)
+
 			return
 		}
 
@@ -842,6 +906,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// abandon this direction. Adding further nodes can only lower
 		// the probability more.
 		if probability < cfg.MinProbability {
+			rejectEdge(
+				fromVertex, toNodeDist.node, amountToSend,
+				EdgeRejectMinProbability,
+			)
+
 			return
 		}
 
@@ -859,7 +928,8 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// weight composed of the fee that this node will charge and
 		// the amount that will be locked for timeLockDelta blocks in
 		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToSend, fee, timeLockDelta)
+		weight := edgeWeight(amountToSend, fee, timeLockDelta) +
+			latencyPenalty(r, cfg, fromVertex, toNodeDist.node)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -925,6 +995,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		routingInfoSize := toNodeDist.routingInfoSize + payloadSize
 		// Skip paths that would exceed the maximum routing info size.
 		if routingInfoSize > sphinx.MaxPayloadSize {
+			rejectEdge(
+				fromVertex, toNodeDist.node, amountToSend,
+				EdgeRejectPayloadSize,
+			)
+
 			return
 		}
 
@@ -1065,6 +1140,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			)
 
 			if edge == nil {
+				rejectEdge(
+					fromNode, pivot, netAmountReceived,
+					EdgeRejectNoCapacity,
+				)
+
 				continue
 			}
 
@@ -1147,6 +1227,280 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	return pathEdges, distance[source].probability, nil
 }
 
+// candidatePath is a path found by findPaths together with the route it
+// results in and the values it is ranked by.
+type candidatePath struct {
+	// edges are the edges of the path, from source to target.
+	edges []*unifiedEdge
+
+	// nodes are the nodes that the path visits, starting with the source.
+	nodes []route.Vertex
+
+	// route is the route that is created from the path.
+	route *route.Route
+
+	// probability is the estimated success probability of the route.
+	probability float64
+
+	// dist is the probability based distance of the route, which is the
+	// value that path finding minimizes.
+	dist int64
+}
+
+// newCandidatePath creates the route for the given path and evaluates its
+// success probability and distance in the same way as findPath does.
+func newCandidatePath(r *RestrictParams, cfg *PathFindingConfig,
+	source route.Vertex, edges []*unifiedEdge, absoluteAttemptCost float64,
+	toRoute func([]*unifiedEdge) (*route.Route, error)) (*candidatePath,
+	error) {
+
+	rt, err := toRoute(edges)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		nodes       = make([]route.Vertex, 0, len(edges)+1)
+		weight      int64
+		probability = 1.0
+		amt         = rt.TotalAmount
+	)
+	nodes = append(nodes, source)
+	for i, edge := range edges {
+		toNode := edge.policy.ToNodePubKey()
+
+		// The source node doesn't charge a fee, so only the nodes
+		// after it add to the weight of the route.
+		if i > 0 {
+			amt = rt.Hops[i-1].AmtToForward
+			weight += edgeWeight(
+				amt, rt.HopFee(i-1), edge.policy.TimeLockDelta,
+			)
+		}
+
+		weight += latencyPenalty(r, cfg, nodes[i], toNode)
+
+		probability *= r.ProbabilitySource(
+			nodes[i], toNode, amt, edge.capacity,
+		)
+		nodes = append(nodes, toNode)
+	}
+
+	return &candidatePath{
+		edges:       edges,
+		nodes:       nodes,
+		route:       rt,
+		probability: probability,
+		dist: getProbabilityBasedDist(
+			weight, probability, absoluteAttemptCost,
+		),
+	}, nil
+}
+
+// valid returns whether the path satisfies the restrictions that findPath
+// applies. This is needed for paths that are combined from a root path and a
+// spur path, as the amounts and time locks along the root path change when
+// the spur path is replaced.
+func (c *candidatePath) valid(r *RestrictParams, cfg *PathFindingConfig,
+	finalHtlcExpiry int32) bool {
+
+	if c.probability == 0 || c.probability < cfg.MinProbability {
+		return false
+	}
+
+	if c.route.TotalFees() > r.FeeLimit {
+		return false
+	}
+
+	// Use uint64 to prevent an overflow if the cltv limit is MaxUint32.
+	absoluteCltvLimit := uint64(r.CltvLimit) + uint64(finalHtlcExpiry)
+	if uint64(c.route.TotalTimeLock) > absoluteCltvLimit {
+		return false
+	}
+
+	var payloadSize uint64
+	for i, hop := range c.route.Hops {
+		amt := c.route.TotalAmount
+		if i > 0 {
+			amt = c.route.Hops[i-1].AmtToForward
+		}
+
+		// The policies of the root path were selected for the amount
+		// of the original path.
+		if !c.edges[i].amtInRange(amt) {
+			return false
+		}
+
+		var nextChanID uint64
+		if i < len(c.route.Hops)-1 {
+			nextChanID = c.route.Hops[i+1].ChannelID
+		}
+		payloadSize += hop.PayloadSize(nextChanID)
+	}
+
+	return payloadSize <= sphinx.MaxPayloadSize
+}
+
+// pathKey returns a key that identifies a path by the nodes it visits.
+func pathKey(nodes []route.Vertex) string {
+	key := make([]byte, 0, len(nodes)*len(route.Vertex{}))
+	for _, node := range nodes {
+		key = append(key, node[:]...)
+	}
+
+	return string(key)
+}
+
+// findPaths finds up to numPaths distinct paths from source to target, ranked
+// by the probability based distance that findPath minimizes. It uses Yen's
+// k-shortest paths algorithm: the first path is the one that findPath
+// returns, and every further path is the best of the candidates that are
+// derived from the earlier paths. A candidate keeps a root part of an earlier
+// path and continues with a spur path from the last node of the root to the
+// target. The spur path is found by findPath with the same restrictions,
+// avoiding the nodes of the root and the next hops that earlier paths with
+// the same root took. Paths are distinct in the sequence of nodes that they
+// visit, as parallel channels are unified during path finding.
+//
+// The toRoute closure creates the route for a path, which is needed to
+// evaluate the fees, time locks and probabilities of combined paths.
+func findPaths(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
+	self, source, target route.Vertex, amt lnwire.MilliSatoshi,
+	timePref float64, finalHtlcExpiry int32, numPaths int,
+	toRoute func([]*unifiedEdge) (*route.Route, error)) ([]*candidatePath,
+	error) {
+
+	absoluteAttemptCost, err := getAbsoluteAttemptCost(cfg, amt, timePref)
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := findPath(
+		g, r, cfg, self, source, target, amt, timePref, finalHtlcExpiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	best, err := newCandidatePath(
+		r, cfg, source, path, absoluteAttemptCost, toRoute,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		paths      = []*candidatePath{best}
+		candidates []*candidatePath
+		seen       = map[string]struct{}{pathKey(best.nodes): {}}
+	)
+	for len(paths) < numPaths {
+		prev := paths[len(paths)-1]
+
+		for i := range prev.edges {
+			spurNode := prev.nodes[i]
+			rootNodes := prev.nodes[:i+1]
+
+			// The spur path may not revisit the nodes of the root
+			// path. The target is exempt for routes to self.
+			excludedNodes := make(map[route.Vertex]struct{})
+			for _, node := range prev.nodes[:i] {
+				if node != target {
+					excludedNodes[node] = struct{}{}
+				}
+			}
+
+			// Paths that share the root can't be found again by
+			// leaving the spur node the same way.
+			excludedNext := make(map[route.Vertex]struct{})
+			for _, p := range paths {
+				if len(p.nodes) > i+1 &&
+					slices.Equal(p.nodes[:i+1], rootNodes) {
+
+					excludedNext[p.nodes[i+1]] = struct{}{}
+				}
+			}
+
+			spurRestrictions := *r
+			spurRestrictions.ProbabilitySource = func(fromNode,
+				toNode route.Vertex, amt lnwire.MilliSatoshi,
+				capacity btcutil.Amount) float64 {
+
+				_, fromExcluded := excludedNodes[fromNode]
+				_, toExcluded := excludedNodes[toNode]
+				if fromExcluded || toExcluded {
+					return 0
+				}
+
+				_, nextExcluded := excludedNext[toNode]
+				if fromNode == spurNode && nextExcluded {
+					return 0
+				}
+
+				return r.ProbabilitySource(
+					fromNode, toNode, amt, capacity,
+				)
+			}
+
+			spurPath, _, err := findPath(
+				g, &spurRestrictions, cfg, self, spurNode,
+				target, amt, timePref, finalHtlcExpiry,
+			)
+			switch {
+			case errors.Is(err, errNoPathFound),
+				errors.Is(err, errInsufficientBalance):
+
+				continue
+
+			case err != nil:
+				return nil, err
+			}
+
+			edges := make([]*unifiedEdge, 0, i+len(spurPath))
+			edges = append(edges, prev.edges[:i]...)
+			edges = append(edges, spurPath...)
+
+			candidate, err := newCandidatePath(
+				r, cfg, source, edges, absoluteAttemptCost,
+				toRoute,
+			)
+			if err != nil {
+				log.Debugf("Skipping alternative path: %v", err)
+				continue
+			}
+
+			key := pathKey(candidate.nodes)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if !candidate.valid(r, cfg, finalHtlcExpiry) {
+				continue
+			}
+
+			candidates = append(candidates, candidate)
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Move the best candidate over to the result. Ties are broken
+		// by the order in which the candidates were found.
+		bestIdx := 0
+		for i, candidate := range candidates {
+			if candidate.dist < candidates[bestIdx].dist {
+				bestIdx = i
+			}
+		}
+		paths = append(paths, candidates[bestIdx])
+		candidates = slices.Delete(candidates, bestIdx, bestIdx+1)
+	}
+
+	return paths, nil
+}
+
 // blindedPathRestrictions are a set of constraints to adhere to when
 // choosing a set of blinded paths to this node.
 type blindedPathRestrictions struct {
@@ -1350,6 +1704,31 @@ func processNodeForBlindedPath(g Graph, node route.Vertex,
 	return hopSets, chanCount > 1, nil
 }
 
+// getAbsoluteAttemptCost returns the cost of a payment attempt for the given
+// amount, taking into account the time preference of the payment.
+func getAbsoluteAttemptCost(cfg *PathFindingConfig, amt lnwire.MilliSatoshi,
+	timePref float64) (float64, error) {
+
+	// Calculate the default attempt cost as configured globally.
+	defaultAttemptCost := float64(
+		cfg.AttemptCost +
+			amt*lnwire.MilliSatoshi(cfg.AttemptCostPPM)/1000000,
+	)
+
+	// Validate time preference value.
+	if math.Abs(timePref) > 1 {
+		return 0, fmt.Errorf("time preference %v out of range "+
+			"[-1, 1]", timePref)
+	}
+
+	// Scale to avoid the extremes -1 and 1 which run into infinity issues.
+	timePref *= 0.9
+
+	// Apply time preference. At 0, the default attempt cost will
+	// be used.
+	return defaultAttemptCost * (1/(0.5-timePref/2) - 1), nil
+}
+
 // getProbabilityBasedDist converts a weight into a distance that takes into
 // account the success probability and the (virtual) cost of a failed payment
 // attempt.
@@ -1451,6 +1830,7 @@ func lastHopPayloadSize(r *RestrictParams, finalHtlcExpiry int32,
 		MPP:              mpp,
 		AMP:              amp,
 		Metadata:         r.Metadata,
+		TrampolineOnion:  r.TrampolineOnion,
 	}
 
 	// The final hop does not have a short chanID set.
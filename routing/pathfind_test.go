@@ -819,6 +819,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "equal cost route selection",
 		fn:   runEqualCostRouteSelection,
+	}, {
+		name: "latency routing",
+		fn:   runLatencyRouting,
 	}, {
 		name: "no cycle",
 		fn:   runNoCycle,
@@ -831,6 +834,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "inbound fees",
 		fn:   runInboundFees,
+	}, {
+		name: "k shortest paths",
+		fn:   runFindPaths,
 	}}
 
 	// Run with graph cache enabled.
@@ -889,6 +895,108 @@ func runFindPathWithMetadata(t *testing.T, useCache bool) {
 	require.ErrorIs(t, errNoPathFound, err)
 }
 
+// runFindPaths tests that distinct paths are returned in the order of their
+// cost and that they adhere to the path finding restrictions.
+func runFindPaths(t *testing.T, useCache bool) {
+	policy := func(feeRate lnwire.MilliSatoshi) *testChannelPolicy {
+		return &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: feeRate,
+			MinHTLC: 1,
+			MaxHTLC: 100000000,
+		}
+	}
+
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", 100000, policy(100)),
+		symmetricTestChannel("a", "target", 100000, policy(100)),
+		symmetricTestChannel("roasbeef", "b", 100000, policy(200)),
+		symmetricTestChannel("b", "target", 100000, policy(200)),
+		symmetricTestChannel("roasbeef", "c", 100000, policy(400)),
+		symmetricTestChannel("c", "target", 100000, policy(400)),
+		symmetricTestChannel("a", "b", 100000, policy(50)),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "roasbeef")
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(10000)
+	target := ctx.keyFromAlias("target")
+
+	assertPaths := func(paths []*candidatePath, expected [][]string) {
+		t.Helper()
+
+		aliases := make([][]string, 0, len(paths))
+		for _, path := range paths {
+			var hops []string
+			for _, hop := range path.route.Hops {
+				alias := ctx.aliasFromKey(hop.PubKeyBytes)
+				hops = append(hops, alias)
+			}
+			aliases = append(aliases, hops)
+		}
+		require.Equal(t, expected, aliases)
+	}
+
+	// Requesting more paths than there are in the graph returns all of
+	// them, ordered by their fees.
+	paths, err := ctx.findPaths(target, paymentAmt, 10)
+	require.NoError(t, err)
+	assertPaths(paths, [][]string{
+		{"a", "target"},
+		{"b", "a", "target"},
+		{"b", "target"},
+		{"a", "b", "target"},
+		{"c", "target"},
+	})
+	for i := 1; i < len(paths); i++ {
+		require.Greater(t, paths[i].dist, paths[i-1].dist)
+	}
+
+	// The number of paths is limited to the requested number.
+	paths, err = ctx.findPaths(target, paymentAmt, 2)
+	require.NoError(t, err)
+	assertPaths(paths, [][]string{
+		{"a", "target"},
+		{"b", "a", "target"},
+	})
+
+	// Paths that exceed the fee limit are skipped.
+	ctx.restrictParams.FeeLimit = 2000
+	paths, err = ctx.findPaths(target, paymentAmt, 10)
+	require.NoError(t, err)
+	assertPaths(paths, [][]string{
+		{"a", "target"},
+		{"b", "a", "target"},
+		{"b", "target"},
+	})
+	for _, path := range paths {
+		require.LessOrEqual(
+			t, path.route.TotalFees(), ctx.restrictParams.FeeLimit,
+		)
+	}
+
+	// Ignored nodes are also avoided by the alternative paths.
+	ctx.restrictParams.FeeLimit = noFeeLimit
+	ctx.restrictParams.ProbabilitySource = func(fromNode,
+		toNode route.Vertex, _ lnwire.MilliSatoshi,
+		_ btcutil.Amount) float64 {
+
+		if fromNode == ctx.keyFromAlias("a") ||
+			toNode == ctx.keyFromAlias("a") {
+
+			return 0
+		}
+
+		return 1
+	}
+	paths, err = ctx.findPaths(target, paymentAmt, 10)
+	require.NoError(t, err)
+	assertPaths(paths, [][]string{
+		{"b", "target"},
+		{"c", "target"},
+	})
+}
+
 // runFindLowestFeePath tests that out of two routes with identical total
 // time lock values, the route with the lowest total fee should be returned.
 // The fee rates are chosen such that the test failed on the previous edge
@@ -1614,6 +1722,58 @@ func TestNewRoute(t *testing.T) {
 	}
 }
 
+// TestNewRouteTrampolineOnion asserts that newRoute only attaches a
+// trampoline onion to the final hop if it signals trampoline support.
+func TestNewRouteTrampolineOnion(t *testing.T) {
+	t.Parallel()
+
+	trampolineOnion := []byte{1, 2, 3}
+	payAddr := [32]byte{0x01}
+
+	newEdge := func(features *lnwire.FeatureVector) *unifiedEdge {
+		return &unifiedEdge{
+			policy: &models.CachedEdgePolicy{
+				ToNodePubKey: func() route.Vertex {
+					return route.Vertex{}
+				},
+				ToNodeFeatures: features,
+				TimeLockDelta:  10,
+			},
+		}
+	}
+
+	params := finalHopParams{
+		amt:             1000,
+		totalAmt:        1000,
+		cltvDelta:       40,
+		paymentAddr:     fn.Some(payAddr),
+		trampolineOnion: trampolineOnion,
+	}
+
+	// A final hop that doesn't know about trampoline routing must not be
+	// handed a trampoline onion.
+	_, err := newRoute(
+		route.Vertex{}, []*unifiedEdge{newEdge(tlvPayAddrFeatures)},
+		100, params, nil,
+	)
+	require.Error(t, err)
+
+	trampolineFeatures := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadRequired,
+			lnwire.PaymentAddrOptional,
+			lnwire.TrampolineRoutingOptional,
+		), lnwire.Features,
+	)
+	rt, err := newRoute(
+		route.Vertex{}, []*unifiedEdge{newEdge(trampolineFeatures)},
+		100, params, nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, trampolineOnion, rt.Hops[0].TrampolineOnion)
+	require.Equal(t, payAddr, rt.Hops[0].MPP.PaymentAddr())
+}
+
 func runNewRoutePathTooLong(t *testing.T, useCache bool) {
 	var testChannels []*testChannel
 
@@ -2776,6 +2936,59 @@ func runEqualCostRouteSelection(t *testing.T, useCache bool) {
 	}
 }
 
+// runLatencyRouting asserts that the expected latency of the pairs is traded
+// off against fees if a latency cost is configured.
+func runLatencyRouting(t *testing.T, useCache bool) {
+	// Set up a test graph with two possible paths to the target: a cheap
+	// but slow path via a and a more expensive but fast path via b.
+	testChannels := []*testChannel{
+		symmetricTestChannel("source", "a", 100000, &testChannelPolicy{}),
+		symmetricTestChannel("source", "b", 100000, &testChannelPolicy{}),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(6),
+			MinHTLC:     1,
+		}, 1),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(8),
+			MinHTLC:     1,
+		}, 2),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "source")
+
+	alias := ctx.testGraphInstance.aliasMap
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := alias["target"]
+
+	ctx.restrictParams.LatencySource = func(fromNode,
+		toNode route.Vertex) time.Duration {
+
+		if fromNode == alias["a"] && toNode == target {
+			return 2 * time.Second
+		}
+
+		return 100 * time.Millisecond
+	}
+
+	// Without a latency cost, the cheapest path is selected.
+	path, err := ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, path[1].policy.ChannelID)
+
+	// With a latency cost of 10 sat per second, the path via a costs 6 +
+	// 10 * 2.1 = 27 sat and the path via b costs 8 + 10 * 0.2 = 10 sat.
+	ctx.pathFindingConfig = PathFindingConfig{
+		LatencyCost: lnwire.NewMSatFromSatoshis(10),
+	}
+
+	path, err = ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, path[1].policy.ChannelID)
+}
+
 // runNoCycle tries to guide the path finding algorithm into reconstructing an
 // endless route. It asserts that the algorithm is able to handle this properly.
 func runNoCycle(t *testing.T, useCache bool) {
@@ -3083,6 +3296,41 @@ func (c *pathFindingTestContext) findPath(target route.Vertex,
 	)
 }
 
+func (c *pathFindingTestContext) findPaths(target route.Vertex,
+	amt lnwire.MilliSatoshi, numPaths int) ([]*candidatePath, error) {
+
+	graphSessFactory := newMockGraphSessionFactoryFromChanDB(c.graph)
+
+	graphSess, closeGraphSess, err := graphSessFactory.NewGraphSession()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := closeGraphSess(); err != nil {
+			log.Errorf("Error closing graph session: %v", err)
+		}
+	}()
+
+	toRoute := func(path []*unifiedEdge) (*route.Route, error) {
+		return newRoute(
+			c.source, path, 0, finalHopParams{
+				amt:      amt,
+				totalAmt: amt,
+			}, nil,
+		)
+	}
+
+	return findPaths(
+		&graphParams{
+			bandwidthHints: c.bandwidthHints,
+			graph:          graphSess,
+		},
+		&c.restrictParams, &c.pathFindingConfig, c.source, c.source,
+		target, amt, c.timePref, 0, numPaths, toRoute,
+	)
+}
+
 func (c *pathFindingTestContext) findBlindedPaths(
 	restrictions *blindedPathRestrictions) ([][]blindedHop, error) {
 
@@ -35,6 +35,10 @@ type paymentLifecycle struct {
 	currentHeight         int32
 	firstHopCustomRecords lnwire.CustomRecords
 
+	// missionControl is the mission control that the results of the
+	// attempts are reported to.
+	missionControl MissionControlQuerier
+
 	// quit is closed to signal the sub goroutines of the payment lifecycle
 	// to stop.
 	quit chan struct{}
@@ -56,7 +60,8 @@ type paymentLifecycle struct {
 func newPaymentLifecycle(r *ChannelRouter, feeLimit lnwire.MilliSatoshi,
 	identifier lntypes.Hash, paySession PaymentSession,
 	shardTracker shards.ShardTracker, currentHeight int32,
-	firstHopCustomRecords lnwire.CustomRecords) *paymentLifecycle {
+	firstHopCustomRecords lnwire.CustomRecords,
+	missionControl MissionControlQuerier) *paymentLifecycle {
 
 	p := &paymentLifecycle{
 		router:                r,
@@ -68,6 +73,7 @@ func newPaymentLifecycle(r *ChannelRouter, feeLimit lnwire.MilliSatoshi,
 		quit:                  make(chan struct{}),
 		resultCollected:       make(chan error, 1),
 		firstHopCustomRecords: firstHopCustomRecords,
+		missionControl:        missionControl,
 	}
 
 	// Mount the result collector.
@@ -510,6 +516,7 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 	// switch.
 	errorDecryptor := &htlcswitch.SphinxErrorDecrypter{
 		OnionErrorDecrypter: sphinx.NewOnionErrorDecrypter(circuit),
+		Circuit:             circuit,
 	}
 
 	// Now ask the switch to return the result of the payment when
@@ -562,8 +569,8 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		p.identifier, attempt.AttemptID)
 
 	// Report success to mission control.
-	err = p.router.cfg.MissionControl.ReportPaymentSuccess(
-		attempt.AttemptID, &attempt.Route,
+	err = p.missionControl.ReportPaymentSuccess(
+		attempt.AttemptID, attempt.AttemptTime, &attempt.Route,
 	)
 	if err != nil {
 		log.Errorf("Error reporting payment success to mc: %v", err)
@@ -844,12 +851,13 @@ func (p *paymentLifecycle) handleSwitchErr(attempt *channeldb.HTLCAttempt,
 	// mission control, which helps us to decide whether we want to retry
 	// the payment or not. If a non nil reason is returned from mission
 	// control, it will further fail the payment via control tower.
-	reportAndFail := func(srcIdx *int,
-		msg lnwire.FailureMessage) (*attemptResult, error) {
+	reportAndFail := func(srcIdx *int, msg lnwire.FailureMessage,
+		attribution *FailureAttribution) (*attemptResult, error) {
 
 		// Report outcome to mission control.
-		reason, err := p.router.cfg.MissionControl.ReportPaymentFail(
-			attemptID, &attempt.Route, srcIdx, msg,
+		reason, err := p.missionControl.ReportPaymentFail(
+			attemptID, attempt.AttemptTime, &attempt.Route, srcIdx,
+			msg, attribution,
 		)
 		if err != nil {
 			log.Errorf("Error reporting payment result to mc: %v",
@@ -885,8 +893,20 @@ func (p *paymentLifecycle) handleSwitchErr(attempt *channeldb.HTLCAttempt,
 
 		// Since this error message cannot be decrypted, we will send a
 		// nil error message to our mission controller and fail the
-		// payment.
-		return reportAndFail(nil, nil)
+		// payment. If the attribution data tells us which pair of
+		// nodes garbled the failure, we pass that on too.
+		var (
+			attribution *FailureAttribution
+			unreadable  *htlcswitch.UnreadableFailureError
+		)
+		if errors.As(sendErr, &unreadable) {
+			attribution = &FailureAttribution{
+				FailingHopIdx: unreadable.FailingHopIdx,
+				HoldTimes:     unreadable.HoldTimes,
+			}
+		}
+
+		return reportAndFail(nil, nil, attribution)
 	}
 
 	// If the error is a ClearTextError, we have received a valid wire
@@ -909,10 +929,19 @@ func (p *paymentLifecycle) handleSwitchErr(attempt *channeldb.HTLCAttempt,
 	// ForwardingError, it did not originate at our node, so we set
 	// failureSourceIdx to the index of the node where the failure occurred.
 	failureSourceIdx := 0
-	var source *htlcswitch.ForwardingError
+	var (
+		source      *htlcswitch.ForwardingError
+		attribution *FailureAttribution
+	)
 	ok = errors.As(rtErr, &source)
 	if ok {
 		failureSourceIdx = source.FailureSourceIdx
+
+		if len(source.HoldTimes) > 0 {
+			attribution = &FailureAttribution{
+				HoldTimes: source.HoldTimes,
+			}
+		}
 	}
 
 	// Extract the wire failure and apply channel update if it contains one.
@@ -931,7 +960,7 @@ func (p *paymentLifecycle) handleSwitchErr(attempt *channeldb.HTLCAttempt,
 	log.Tracef("Node=%v reported failure when sending htlc",
 		failureSourceIdx)
 
-	return reportAndFail(&failureSourceIdx, failureMessage)
+	return reportAndFail(&failureSourceIdx, failureMessage, attribution)
 }
 
 // handleFailureMessage tries to apply a channel update present in the failure
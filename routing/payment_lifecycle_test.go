@@ -97,7 +97,7 @@ func newTestPaymentLifecycle(t *testing.T) (*paymentLifecycle, *mockers) {
 	// Create a test payment lifecycle with no fee limit and no timeout.
 	p := newPaymentLifecycle(
 		rt, noFeeLimit, paymentHash, mockPaymentSession,
-		mockShardTracker, 0, nil,
+		mockShardTracker, 0, nil, mockMissionControl,
 	)
 
 	// Create a mock payment which is returned from mockControlTower.
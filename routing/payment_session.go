@@ -220,6 +220,15 @@ func newPaymentSession(p *LightningPayment, selfNode route.Vertex,
 		}
 	}
 
+	// Simulated payments don't lock up any local balance, so the amounts
+	// of the routes that were handed out already are deducted from the
+	// bandwidth of our channels.
+	if p.simulation != nil {
+		getBandwidthHints = p.simulation.wrapBandwidthHints(
+			getBandwidthHints,
+		)
+	}
+
 	logPrefix := fmt.Sprintf("PaymentSession(%x):", p.Identifier())
 
 	return &paymentSession{
@@ -270,6 +279,7 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 	// MissionController.
 	restrictions := &RestrictParams{
 		ProbabilitySource:     p.missionControl.GetProbability,
+		LatencySource:         p.missionControl.GetLatency,
 		FeeLimit:              feeLimit,
 		OutgoingChannelIDs:    p.payment.OutgoingChannelIDs,
 		LastHop:               p.payment.LastHop,
@@ -279,9 +289,15 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		PaymentAddr:           p.payment.PaymentAddr,
 		Amp:                   p.payment.amp,
 		Metadata:              p.payment.Metadata,
+		TrampolineOnion:       p.payment.TrampolineOnion,
 		FirstHopCustomRecords: firstHopCustomRecords,
 	}
 
+	sim := p.payment.simulation
+	if sim != nil {
+		restrictions.EdgeRejected = sim.edgeRejected
+	}
+
 	finalHtlcExpiry := int32(height) + int32(finalCltvDelta)
 
 	// Before we enter the loop below, we'll make sure to respect the max
@@ -417,18 +433,23 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		route, err := newRoute(
 			p.selfNode, path, height,
 			finalHopParams{
-				amt:         maxAmt,
-				totalAmt:    p.payment.Amount,
-				cltvDelta:   finalCltvDelta,
-				records:     p.payment.DestCustomRecords,
-				paymentAddr: p.payment.PaymentAddr,
-				metadata:    p.payment.Metadata,
+				amt:             maxAmt,
+				totalAmt:        p.payment.Amount,
+				cltvDelta:       finalCltvDelta,
+				records:         p.payment.DestCustomRecords,
+				paymentAddr:     p.payment.PaymentAddr,
+				metadata:        p.payment.Metadata,
+				trampolineOnion: p.payment.TrampolineOnion,
 			}, p.payment.BlindedPathSet,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		if sim != nil {
+			sim.routeFound(route, path)
+		}
+
 		return route, err
 	}
 }
@@ -0,0 +1,609 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/feature"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// SplitStrategy determines how a payment is split into multiple shards.
+type SplitStrategy uint8
+
+const (
+	// SplitStrategyBinary halves the amount of a shard whenever no route
+	// can be found for it.
+	SplitStrategyBinary SplitStrategy = iota
+
+	// SplitStrategyMinCostFlow plans all shards of a payment at once by
+	// computing a minimum cost flow that takes both fees and the success
+	// probabilities of mission control into account.
+	SplitStrategyMinCostFlow
+)
+
+// String returns a human-readable name of the split strategy.
+func (s SplitStrategy) String() string {
+	switch s {
+	case SplitStrategyBinary:
+		return "binary"
+
+	case SplitStrategyMinCostFlow:
+		return "min_cost_flow"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(s))
+	}
+}
+
+const (
+	// flowMaxUnits is the maximum number of units the amount is divided
+	// into when planning a flow. More units give a more precise
+	// distribution of the amount, at the expense of planning time.
+	flowMaxUnits = 100
+
+	// flowMaxDepth is the maximum distance in hops from the target up to
+	// which the graph is explored when planning a flow.
+	flowMaxDepth = 10
+)
+
+var (
+	// errFlowNotSplittable is returned when the payment can't be split,
+	// so there is nothing to plan.
+	errFlowNotSplittable = errors.New("payment not splittable")
+
+	// errFlowInsufficientCapacity is returned when the capacity of the
+	// explored graph isn't sufficient to carry the amount.
+	errFlowInsufficientCapacity = errors.New("insufficient flow capacity")
+)
+
+// flowPaymentSession is a payment session that splits the payment according
+// to a minimum cost flow. Rather than halving the amount until a route is
+// found, it computes the distribution of the amount across multiple routes
+// that minimizes the sum of fees and expected failure costs in one go. The
+// planned routes are then handed out one by one, so that the payment
+// lifecycle launches them together.
+//
+// Whenever a plan can't be made, the session falls back to the binary
+// splitting of the regular payment session.
+type flowPaymentSession struct {
+	*paymentSession
+
+	// plan holds the planned routes that haven't been requested yet.
+	plan []*route.Route
+
+	// planHeight is the block height the plan was made for.
+	planHeight uint32
+}
+
+// A compile time assertion to ensure flowPaymentSession meets the
+// PaymentSession interface.
+var _ PaymentSession = (*flowPaymentSession)(nil)
+
+// newFlowPaymentSession wraps the given payment session in a session that
+// splits payments according to a minimum cost flow.
+func newFlowPaymentSession(session *paymentSession) *flowPaymentSession {
+	return &flowPaymentSession{
+		paymentSession: session,
+	}
+}
+
+// RequestRoute returns the next planned route if it fits the remaining
+// amount, and otherwise plans the routes for the remaining amount.
+//
+// NOTE: This is part of the PaymentSession interface.
+func (f *flowPaymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
+	activeShards, height uint32,
+	firstHopCustomRecords lnwire.CustomRecords) (*route.Route, error) {
+
+	if f.empty {
+		return nil, errEmptyPaySession
+	}
+
+	// Hand out the next route of the current plan. If a shard of the
+	// plan failed, the remaining amount is larger than the planned
+	// routes, and its amount will be planned again once the plan is
+	// used up.
+	if len(f.plan) > 0 {
+		next := f.plan[0]
+		if height == f.planHeight && next.ReceiverAmt() <= maxAmt {
+			f.plan = f.plan[1:]
+			return next, nil
+		}
+
+		f.log.Debugf("Discarding plan of %v routes", len(f.plan))
+		f.plan = nil
+	}
+
+	plan, err := f.planRoutes(maxAmt, feeLimit, activeShards, height)
+	if err != nil {
+		f.log.Debugf("Unable to plan flow for amt=%v, falling back to "+
+			"binary splitting: %v", maxAmt, err)
+
+		return f.paymentSession.RequestRoute(
+			maxAmt, feeLimit, activeShards, height,
+			firstHopCustomRecords,
+		)
+	}
+
+	f.log.Debugf("Planned %v routes for amt=%v", len(plan), maxAmt)
+
+	f.plan = plan[1:]
+	f.planHeight = height
+
+	return plan[0], nil
+}
+
+// splittable returns whether the payment may be split into more shards.
+func (f *flowPaymentSession) splittable(activeShards uint32) bool {
+	payment := f.payment
+
+	// Payments to blinded paths and trampoline nodes are left to the
+	// regular session, as their final hops need special treatment.
+	if payment.BlindedPathSet != nil || payment.TrampolineOnion != nil {
+		return false
+	}
+
+	if payment.PaymentAddr.IsNone() || payment.DestFeatures == nil {
+		return false
+	}
+
+	if !payment.DestFeatures.HasFeature(lnwire.MPPOptional) &&
+		!payment.DestFeatures.HasFeature(lnwire.AMPOptional) {
+
+		return false
+	}
+
+	return activeShards+1 < payment.MaxParts
+}
+
+// planRoutes computes the minimum cost flow for the given amount and turns it
+// into routes.
+func (f *flowPaymentSession) planRoutes(maxAmt, feeLimit lnwire.MilliSatoshi,
+	activeShards, height uint32) ([]*route.Route, error) {
+
+	if !f.splittable(activeShards) {
+		return nil, errFlowNotSplittable
+	}
+
+	// Divide the amount into units that are no smaller than the minimum
+	// shard amount.
+	unit := maxAmt / flowMaxUnits
+	if unit < f.minShardAmt {
+		unit = f.minShardAmt
+	}
+	units := int(maxAmt / unit)
+	if units < 2 {
+		return nil, errFlowNotSplittable
+	}
+
+	penalty, err := getAbsoluteAttemptCost(
+		&f.pathFindingConfig, maxAmt, f.payment.TimePref,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, closeGraph, err := f.graphSessFactory.NewGraphSession()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := closeGraph(); err != nil {
+			log.Errorf("could not close graph session: %v", err)
+		}
+	}()
+
+	bandwidthHints, err := f.getBandwidthHints(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	network, err := newPaymentFlowNetwork(
+		f.paymentSession, graph, bandwidthHints, unit, units, penalty,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := network.minCostFlow(network.source, network.target, units)
+	if sent < units {
+		return nil, fmt.Errorf("%w: %v of %v units",
+			errFlowInsufficientCapacity, sent, units)
+	}
+
+	paths := network.decompose(network.source, network.target)
+
+	// Distribute the amount across the paths. The remainder that isn't
+	// covered by whole units is added to the first path. Paths that carry
+	// more than the max shard amount are split further.
+	var (
+		remainder = maxAmt - unit*lnwire.MilliSatoshi(units)
+		amounts   []lnwire.MilliSatoshi
+		edges     [][]*unifiedEdge
+	)
+	for i, path := range paths {
+		amt := unit * lnwire.MilliSatoshi(path.units)
+		if i == 0 {
+			amt += remainder
+		}
+
+		pathEdges := make([]*unifiedEdge, len(path.arcs))
+		for j, arc := range path.arcs {
+			pathEdges[j] = network.edges[arc]
+		}
+
+		// The features of the final hop are taken from the
+		// destination features, as the target may not be part of the
+		// graph. The edge is copied because it is shared between
+		// paths.
+		finalEdge := *pathEdges[len(pathEdges)-1]
+		finalPolicy := *finalEdge.policy
+		finalPolicy.ToNodeFeatures = f.payment.DestFeatures
+		finalEdge.policy = &finalPolicy
+		pathEdges[len(pathEdges)-1] = &finalEdge
+
+		for _, shardAmt := range f.splitShardAmt(amt) {
+			amounts = append(amounts, shardAmt)
+			edges = append(edges, pathEdges)
+		}
+	}
+
+	maxShards := f.payment.MaxParts - activeShards
+	if uint32(len(amounts)) > maxShards {
+		return nil, fmt.Errorf("plan needs %v shards, but only %v "+
+			"are allowed", len(amounts), maxShards)
+	}
+
+	// Add BlockPadding to the finalCltvDelta so that the receiving node
+	// does not reject the HTLC if some blocks are mined while it's
+	// in-flight.
+	finalCltvDelta := f.payment.FinalCLTVDelta + BlockPadding
+	cltvLimit := uint64(height) + uint64(f.payment.CltvLimit)
+
+	var (
+		routes   []*route.Route
+		totalFee lnwire.MilliSatoshi
+	)
+	for i, amt := range amounts {
+		if len(edges[i]) > sphinx.NumMaxHops {
+			return nil, fmt.Errorf("path of %v hops exceeds "+
+				"maximum", len(edges[i]))
+		}
+
+		route, err := newRoute(
+			f.selfNode, edges[i], height,
+			finalHopParams{
+				amt:         amt,
+				totalAmt:    f.payment.Amount,
+				cltvDelta:   finalCltvDelta,
+				records:     f.payment.DestCustomRecords,
+				paymentAddr: f.payment.PaymentAddr,
+				metadata:    f.payment.Metadata,
+			}, nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.payment.simulation != nil {
+			f.payment.simulation.routeFound(route, edges[i])
+		}
+
+		if uint64(route.TotalTimeLock) > cltvLimit {
+			return nil, fmt.Errorf("route time lock %v exceeds "+
+				"limit %v", route.TotalTimeLock, cltvLimit)
+		}
+
+		totalFee += route.TotalFees()
+		routes = append(routes, route)
+	}
+
+	if totalFee > feeLimit {
+		return nil, fmt.Errorf("plan fee %v exceeds limit %v",
+			totalFee, feeLimit)
+	}
+
+	return routes, nil
+}
+
+// splitShardAmt splits the given amount into equal shards that don't exceed
+// the max shard amount of the payment.
+func (f *flowPaymentSession) splitShardAmt(
+	amt lnwire.MilliSatoshi) []lnwire.MilliSatoshi {
+
+	maxShardAmt := f.payment.MaxShardAmt
+	if maxShardAmt == nil || *maxShardAmt == 0 || amt <= *maxShardAmt {
+		return []lnwire.MilliSatoshi{amt}
+	}
+
+	numShards := (amt + *maxShardAmt - 1) / *maxShardAmt
+	shards := make([]lnwire.MilliSatoshi, numShards)
+	for i := range shards {
+		shards[i] = amt / numShards
+	}
+	shards[0] += amt % numShards
+
+	return shards
+}
+
+// paymentFlowNetwork is a flow network that is derived from the channel
+// graph. Every arc corresponds to the unified edge between a pair of nodes.
+type paymentFlowNetwork struct {
+	*flowNetwork
+
+	// source and target are the node indices of the sender and the
+	// receiver of the payment.
+	source int
+	target int
+
+	// edges holds the unified edge of every arc.
+	edges []*unifiedEdge
+}
+
+// newPaymentFlowNetwork explores the graph backwards from the target of the
+// payment and creates an arc for every connection that can carry at least
+// one unit. The marginal costs of an arc are derived from the fees and the
+// success probability for every number of units sent over it.
+func newPaymentFlowNetwork(p *paymentSession, graph Graph,
+	bandwidthHints bandwidthHints, unit lnwire.MilliSatoshi, units int,
+	penalty float64) (*paymentFlowNetwork, error) {
+
+	var (
+		self    = p.selfNode
+		target  = p.payment.Target
+		payment = p.payment
+	)
+
+	network := &paymentFlowNetwork{
+		flowNetwork: newFlowNetwork(),
+	}
+	nodes := make(map[route.Vertex]int)
+	depth := make(map[route.Vertex]int)
+
+	addNode := func(node route.Vertex) int {
+		idx, ok := nodes[node]
+		if !ok {
+			idx = network.addNode()
+			nodes[node] = idx
+		}
+
+		return idx
+	}
+	network.source = addNode(self)
+	network.target = addNode(target)
+
+	var outgoingChanMap map[uint64]struct{}
+	if len(payment.OutgoingChannelIDs) > 0 {
+		outgoingChanMap = make(map[uint64]struct{})
+		for _, outChan := range payment.OutgoingChannelIDs {
+			outgoingChanMap[outChan] = struct{}{}
+		}
+	}
+
+	// Build a reverse lookup of the additional edges, as the graph is
+	// explored from the target towards the source.
+	additionalEdgesWithSrc := make(map[route.Vertex][]*edgePolicyWithSource)
+	for vertex, additionalEdges := range p.additionalEdges {
+		if vertex == self {
+			continue
+		}
+
+		for _, additionalEdge := range additionalEdges {
+			toVertex := additionalEdge.EdgePolicy().ToNodePubKey()
+
+			additionalEdgesWithSrc[toVertex] = append(
+				additionalEdgesWithSrc[toVertex],
+				&edgePolicyWithSource{
+					sourceNode: vertex,
+					edge:       additionalEdge,
+				},
+			)
+		}
+	}
+
+	// Cache features because nodes are checked multiple times.
+	featureCache := make(map[route.Vertex]bool)
+	validFeatures := func(node route.Vertex) (bool, error) {
+		valid, ok := featureCache[node]
+		if ok {
+			return valid, nil
+		}
+
+		features, err := graph.FetchNodeFeatures(node)
+		if err != nil {
+			return false, err
+		}
+
+		valid = feature.ValidateRequired(features) == nil &&
+			feature.ValidateDeps(features) == nil
+		featureCache[node] = valid
+
+		return valid, nil
+	}
+
+	queue := []route.Vertex{target}
+	for len(queue) > 0 {
+		pivot := queue[0]
+		queue = queue[1:]
+
+		u := newNodeEdgeUnifier(
+			self, pivot, pivot != target, outgoingChanMap,
+		)
+		if err := u.addGraphPolicies(graph); err != nil {
+			return nil, err
+		}
+
+		// Hop hints don't contain a capacity, so we assume there is
+		// enough liquidity like regular path finding does.
+		for _, reverseEdge := range additionalEdgesWithSrc[pivot] {
+			u.addPolicy(
+				reverseEdge.sourceNode,
+				reverseEdge.edge.EdgePolicy(),
+				models.InboundFee{}, fakeHopHintCapacity,
+				reverseEdge.edge.IntermediatePayloadSize,
+				reverseEdge.edge.BlindedPayment(),
+			)
+		}
+
+		for fromNode, edgeUnifier := range u.edgeUnifiers {
+			if fromNode == target {
+				continue
+			}
+
+			if payment.LastHop != nil && pivot == target &&
+				fromNode != *payment.LastHop {
+
+				continue
+			}
+
+			edge := edgeUnifier.getEdge(unit, bandwidthHints, 0)
+			if edge == nil {
+				continue
+			}
+
+			valid, err := validFeatures(fromNode)
+			if err != nil {
+				return nil, err
+			}
+			if !valid {
+				continue
+			}
+
+			marginalCost := flowMarginalCosts(
+				p, fromNode, pivot, edge, bandwidthHints, unit,
+				units, penalty,
+			)
+			if len(marginalCost) == 0 {
+				continue
+			}
+
+			network.addArc(
+				addNode(fromNode), nodes[pivot], marginalCost,
+			)
+			network.edges = append(network.edges, edge)
+
+			// Explore the node further if it is new, unless it is
+			// the sender or too far away from the target.
+			if _, ok := depth[fromNode]; ok || fromNode == self {
+				continue
+			}
+			depth[fromNode] = depth[pivot] + 1
+			if depth[fromNode] < flowMaxDepth {
+				queue = append(queue, fromNode)
+			}
+		}
+	}
+
+	return network, nil
+}
+
+// flowMarginalCosts returns the marginal cost of every unit that can be sent
+// over the given edge. The cost of sending an amount is the fee charged for
+// it plus the penalty weighted by the negative log of its success
+// probability. The number of units is limited by the capacity of the edge,
+// the available bandwidth of local channels and the amounts that mission
+// control deems possible.
+//
+// Mission control estimates rarely depend on the amount below the amounts
+// that failed before. To make it more expensive to send large parts of the
+// liquidity over a remote channel, the estimate is combined with a uniform
+// distribution of the liquidity up to the capacity or the smallest amount
+// that mission control deems impossible.
+func flowMarginalCosts(p *paymentSession, fromNode, toNode route.Vertex,
+	edge *unifiedEdge, bandwidthHints bandwidthHints,
+	unit lnwire.MilliSatoshi, units int, penalty float64) []float64 {
+
+	capMsat := lnwire.NewMSatFromSatoshis(edge.capacity)
+
+	maxAmt := lnwire.MaxMilliSatoshi
+	if capMsat > 0 {
+		maxAmt = capMsat
+	}
+	if edge.policy.MessageFlags.HasMaxHtlc() &&
+		edge.policy.MaxHTLC < maxAmt {
+
+		maxAmt = edge.policy.MaxHTLC
+	}
+
+	// For local channels, we know the available bandwidth, so there is
+	// no uncertainty about the liquidity.
+	localChan := fromNode == p.selfNode
+	if localChan {
+		bandwidth, ok := bandwidthHints.availableChanBandwidth(
+			edge.policy.ChannelID, unit,
+		)
+		if ok && bandwidth < maxAmt {
+			maxAmt = bandwidth
+		}
+	}
+
+	// Query the estimates of mission control for every number of units,
+	// up to the first amount that isn't possible.
+	liquidityBound := capMsat
+	var probabilities []float64
+	for x := 1; x <= units; x++ {
+		amt := unit * lnwire.MilliSatoshi(x)
+		if amt > maxAmt {
+			break
+		}
+
+		probability := p.missionControl.GetProbability(
+			fromNode, toNode, amt, edge.capacity,
+		)
+		if probability == 0 ||
+			probability < p.pathFindingConfig.MinProbability {
+
+			liquidityBound = amt
+			break
+		}
+
+		probabilities = append(probabilities, probability)
+	}
+
+	costs := make([]float64, len(probabilities))
+	for i, probability := range probabilities {
+		amt := unit * lnwire.MilliSatoshi(i+1)
+
+		if !localChan && liquidityBound > 0 {
+			probability *= float64(liquidityBound-amt+1) /
+				float64(liquidityBound+1)
+		}
+
+		// The source node doesn't pay a fee to itself.
+		var fee lnwire.MilliSatoshi
+		if !localChan {
+			fee = edge.policy.ComputeFee(amt)
+		}
+
+		costs[i] = float64(fee) - penalty*math.Log(probability)
+	}
+
+	if len(costs) == 0 {
+		return nil
+	}
+
+	// Costs that don't depend on the amount, like the base fee and the
+	// part of the failure probability that doesn't depend on the amount,
+	// would favor sending everything over a single path. They can't be
+	// expressed in a convex flow, so they are estimated from the first
+	// two units and spread evenly over the units of the payment.
+	fixedCost := costs[0]
+	if len(costs) > 1 {
+		fixedCost = math.Min(math.Max(2*costs[0]-costs[1], 0), costs[0])
+	}
+	for i := range costs {
+		costs[i] -= fixedCost
+	}
+
+	marginalCosts := convexMarginalCosts(costs)
+	for i := range marginalCosts {
+		marginalCosts[i] += fixedCost / float64(units)
+	}
+
+	return marginalCosts
+}
@@ -45,6 +45,10 @@ type SessionSource struct {
 	// PathFindingConfig defines global parameters that control the
 	// trade-off in path finding between fees and probability.
 	PathFindingConfig PathFindingConfig
+
+	// ExternalPathFinder is an optional hook that allows an external
+	// provider to supply probabilities or paths to payment sessions.
+	ExternalPathFinder *ExternalPathFinder
 }
 
 // NewPaymentSession creates a new payment session backed by the latest prune
@@ -62,14 +66,29 @@ func (m *SessionSource) NewPaymentSession(p *LightningPayment,
 		)
 	}
 
+	// Payments that select a mission control namespace use its
+	// probabilities.
+	missionControl := m.MissionControl
+	if p.missionControl != nil {
+		missionControl = p.missionControl
+	}
+
 	session, err := newPaymentSession(
 		p, m.SourceNode.PubKeyBytes, getBandwidthHints,
-		m.GraphSessionFactory, m.MissionControl, m.PathFindingConfig,
+		m.GraphSessionFactory, missionControl, m.PathFindingConfig,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if m.ExternalPathFinder != nil {
+		session.pathFinder = m.ExternalPathFinder.findPath
+	}
+
+	if p.SplitStrategy == SplitStrategyMinCostFlow {
+		return newFlowPaymentSession(session), nil
+	}
+
 	return session, nil
 }
 
@@ -0,0 +1,612 @@
+package routing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// DefaultProbeNamespace is the mission control namespace that probe
+	// results are recorded in by default.
+	DefaultProbeNamespace = "probe"
+
+	// DefaultProbeInterval is the default time between two probes.
+	DefaultProbeInterval = time.Minute
+
+	// DefaultProbeAmount is the default amount of probes to configured
+	// targets.
+	DefaultProbeAmount = lnwire.MilliSatoshi(100_000_000)
+
+	// DefaultProbeMaxInFlight is the default maximum total amount of the
+	// probes that are in flight at the same time.
+	DefaultProbeMaxInFlight = lnwire.MilliSatoshi(1_000_000_000)
+
+	// DefaultProbeTopDestinations is the default number of the most
+	// frequent destinations of our payments that are probed.
+	DefaultProbeTopDestinations = 10
+
+	// probeHistoryDepth is the number of recent payments that the top
+	// destinations are selected from.
+	probeHistoryDepth = 1000
+
+	// probeFeeLimitPPM is the fee limit of probe routes in parts per
+	// million of the probe amount. Probes don't pay fees, but routes that
+	// are too expensive for real payments aren't worth probing.
+	probeFeeLimitPPM = 50_000
+)
+
+// ProbeTarget is a destination of the prober.
+type ProbeTarget struct {
+	// Node is the node that the probes are sent to.
+	Node route.Vertex
+
+	// LastHop, if set, is the node that must precede Node in the probe
+	// route. This probes a specific channel.
+	LastHop *route.Vertex
+
+	// Amount is the amount that is probed.
+	Amount lnwire.MilliSatoshi
+}
+
+// String returns a human-readable representation of the target.
+func (t ProbeTarget) String() string {
+	if t.LastHop == nil {
+		return fmt.Sprintf("%v (amt=%v)", t.Node, t.Amount)
+	}
+
+	return fmt.Sprintf("%v via %v (amt=%v)", t.Node, *t.LastHop, t.Amount)
+}
+
+// ProberConfig holds the dependencies, targets and budgets of the Prober.
+type ProberConfig struct {
+	// SelfNode is our own node.
+	SelfNode route.Vertex
+
+	// MissionControl is the mission control store that the probe results
+	// are recorded in. Its estimates are also used to find probe routes.
+	MissionControl MissionControlQuerier
+
+	// FindRoute finds a route for the given request.
+	FindRoute func(req *RouteRequest) (*route.Route, float64, error)
+
+	// Payer is the dispatcher that the probe htlcs are sent through.
+	Payer PaymentAttemptDispatcher
+
+	// NextPaymentID returns the next unique attempt ID.
+	NextPaymentID func() (uint64, error)
+
+	// QueryPayments queries the payment history for the top destinations.
+	QueryPayments func(query channeldb.PaymentsQuery) (
+		channeldb.PaymentsResponse, error)
+
+	// FetchChannelNodes returns the two nodes of the given channel.
+	FetchChannelNodes func(chanID uint64) (route.Vertex, route.Vertex,
+		error)
+
+	// FinalCltvDelta is the final cltv delta of the probes.
+	FinalCltvDelta uint16
+
+	// CltvLimit is the maximum time lock of probe routes.
+	CltvLimit uint32
+
+	// Nodes are the nodes that are probed with Amount.
+	Nodes []route.Vertex
+
+	// Channels are the channels that are probed with Amount in both
+	// directions.
+	Channels []uint64
+
+	// TopDestinations is the number of the most frequent destinations of
+	// recent payments that are probed. They are probed with the average
+	// amount that we paid them.
+	TopDestinations int
+
+	// Amount is the amount of probes to the configured nodes and
+	// channels.
+	Amount lnwire.MilliSatoshi
+
+	// Interval is the time between two probes, which limits the rate of
+	// probing.
+	Interval time.Duration
+
+	// MaxInFlight is the maximum total amount of the probes that are in
+	// flight at the same time. Probes can't settle, but their amount is
+	// locked up in our channels until they fail back.
+	MaxInFlight lnwire.MilliSatoshi
+}
+
+// Prober periodically sends probes with a random payment hash to a set of
+// targets and records their outcome in mission control. That keeps the
+// liquidity information of the routes to the targets up to date, so that the
+// first attempts of real payments use accurate estimates.
+type Prober struct {
+	cfg *ProberConfig
+
+	// targets is the current round of targets and next is the index of
+	// the next target to probe. They are only accessed by the probe loop.
+	targets []ProbeTarget
+	next    int
+
+	// inFlight is the total amount of the probes that are in flight.
+	inFlight   lnwire.MilliSatoshi
+	inFlightMu sync.Mutex
+
+	started sync.Once
+	stopped sync.Once
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewProber creates a new prober with the given config.
+func NewProber(cfg *ProberConfig) (*Prober, error) {
+	switch {
+	case cfg.Interval <= 0:
+		return nil, errors.New("probe interval must be positive")
+
+	case cfg.Amount == 0:
+		return nil, errors.New("probe amount must be positive")
+
+	case cfg.MaxInFlight < cfg.Amount:
+		return nil, fmt.Errorf("max in flight amount %v is below the "+
+			"probe amount %v", cfg.MaxInFlight, cfg.Amount)
+	}
+
+	return &Prober{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start launches the probe loop.
+func (p *Prober) Start() error {
+	p.started.Do(func() {
+		log.Info("Prober starting")
+
+		p.wg.Add(1)
+		go p.probeLoop()
+	})
+
+	return nil
+}
+
+// Stop stops the prober and waits for the pending probes.
+func (p *Prober) Stop() error {
+	p.stopped.Do(func() {
+		log.Info("Prober shutting down...")
+		defer log.Debug("Prober shutdown complete")
+
+		close(p.quit)
+		p.wg.Wait()
+	})
+
+	return nil
+}
+
+// probeLoop sends a probe to the next target on every tick of the interval.
+func (p *Prober) probeLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.probeNext()
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// probeNext probes the next target. The targets are fetched again once all
+// targets of the current round have been probed.
+func (p *Prober) probeNext() {
+	if p.next >= len(p.targets) {
+		targets, err := p.fetchTargets()
+		if err != nil {
+			log.Errorf("Unable to fetch probe targets: %v", err)
+			return
+		}
+
+		p.targets, p.next = targets, 0
+	}
+
+	if len(p.targets) == 0 {
+		return
+	}
+
+	target := p.targets[p.next]
+	p.next++
+
+	if err := p.probe(target); err != nil {
+		log.Debugf("Unable to probe %v: %v", target, err)
+	}
+}
+
+// fetchTargets returns the configured targets followed by the top
+// destinations of our payment history.
+func (p *Prober) fetchTargets() ([]ProbeTarget, error) {
+	var (
+		targets []ProbeTarget
+		self    = p.cfg.SelfNode
+	)
+
+	for _, node := range p.cfg.Nodes {
+		if node == self {
+			continue
+		}
+
+		targets = append(targets, ProbeTarget{
+			Node:   node,
+			Amount: p.cfg.Amount,
+		})
+	}
+
+	// Probe the channels in both directions. Our own channels are
+	// skipped, as we know their balance.
+	for _, chanID := range p.cfg.Channels {
+		node1, node2, err := p.cfg.FetchChannelNodes(chanID)
+		if err != nil {
+			log.Warnf("Unable to fetch probe channel %v: %v",
+				chanID, err)
+
+			continue
+		}
+
+		if node1 == self || node2 == self {
+			continue
+		}
+
+		targets = append(targets, ProbeTarget{
+			Node:    node2,
+			LastHop: &node1,
+			Amount:  p.cfg.Amount,
+		}, ProbeTarget{
+			Node:    node1,
+			LastHop: &node2,
+			Amount:  p.cfg.Amount,
+		})
+	}
+
+	if p.cfg.TopDestinations == 0 {
+		return targets, nil
+	}
+
+	destinations, err := p.topDestinations()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(targets, destinations...), nil
+}
+
+// topDestinations returns the most frequent destinations of our recent
+// successful payments together with the average amount that we paid them.
+func (p *Prober) topDestinations() ([]ProbeTarget, error) {
+	resp, err := p.cfg.QueryPayments(channeldb.PaymentsQuery{
+		MaxPayments: probeHistoryDepth,
+		Reversed:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type destination struct {
+		node  route.Vertex
+		count int
+		total lnwire.MilliSatoshi
+	}
+
+	var (
+		destinations []*destination
+		index        = make(map[route.Vertex]*destination)
+	)
+	for _, payment := range resp.Payments {
+		node, ok := paymentDestination(payment)
+		if !ok || node == p.cfg.SelfNode {
+			continue
+		}
+
+		dest, ok := index[node]
+		if !ok {
+			dest = &destination{node: node}
+			index[node] = dest
+			destinations = append(destinations, dest)
+		}
+
+		dest.count++
+		dest.total += payment.Info.Value
+	}
+
+	// The payments are returned from most to least recent, so the stable
+	// sort prefers the destinations that we paid most recently.
+	sort.SliceStable(destinations, func(i, j int) bool {
+		return destinations[i].count > destinations[j].count
+	})
+
+	if len(destinations) > p.cfg.TopDestinations {
+		destinations = destinations[:p.cfg.TopDestinations]
+	}
+
+	targets := make([]ProbeTarget, 0, len(destinations))
+	for _, dest := range destinations {
+		amt := dest.total / lnwire.MilliSatoshi(dest.count)
+		if amt > p.cfg.MaxInFlight {
+			amt = p.cfg.MaxInFlight
+		}
+
+		targets = append(targets, ProbeTarget{
+			Node:   dest.node,
+			Amount: amt,
+		})
+	}
+
+	return targets, nil
+}
+
+// paymentDestination returns the final node of a settled htlc of the payment.
+// Payments to blinded paths are skipped, as their final node is unknown.
+func paymentDestination(payment *channeldb.MPPayment) (route.Vertex, bool) {
+	for _, htlc := range payment.HTLCs {
+		if htlc.Settle == nil || len(htlc.Route.Hops) == 0 {
+			continue
+		}
+
+		finalHop := htlc.Route.FinalHop()
+		if finalHop.BlindingPoint != nil ||
+			len(finalHop.EncryptedData) > 0 {
+
+			return route.Vertex{}, false
+		}
+
+		return finalHop.PubKeyBytes, true
+	}
+
+	return route.Vertex{}, false
+}
+
+// reserve reserves the amount of a probe in the in flight budget. It returns
+// false if the budget is exhausted.
+func (p *Prober) reserve(amt lnwire.MilliSatoshi) bool {
+	p.inFlightMu.Lock()
+	defer p.inFlightMu.Unlock()
+
+	if p.inFlight+amt > p.cfg.MaxInFlight {
+		return false
+	}
+	p.inFlight += amt
+
+	return true
+}
+
+// release releases the amount of a probe from the in flight budget.
+func (p *Prober) release(amt lnwire.MilliSatoshi) {
+	p.inFlightMu.Lock()
+	p.inFlight -= amt
+	p.inFlightMu.Unlock()
+}
+
+// probe sends a probe to the target. The outcome of the probe is collected in
+// the background.
+func (p *Prober) probe(target ProbeTarget) error {
+	feeLimit := target.Amount * probeFeeLimitPPM / 1_000_000
+	routeReq, err := NewRouteRequest(
+		p.cfg.SelfNode, &target.Node, target.Amount, 0,
+		&RestrictParams{
+			ProbabilitySource: p.cfg.MissionControl.GetProbability,
+			FeeLimit:          feeLimit,
+			CltvLimit:         p.cfg.CltvLimit,
+			LastHop:           target.LastHop,
+		}, nil, nil, nil, p.cfg.FinalCltvDelta,
+	)
+	if err != nil {
+		return err
+	}
+
+	rt, _, err := p.cfg.FindRoute(routeReq)
+	if err != nil {
+		return err
+	}
+
+	// The htlc on the first hop also carries the fees of the route.
+	if !p.reserve(rt.TotalAmount) {
+		return fmt.Errorf("in flight budget of %v exhausted",
+			p.cfg.MaxInFlight)
+	}
+
+	attemptTime := time.Now()
+	attemptID, sessionKey, hash, err := p.sendProbe(rt, attemptTime)
+	if err != nil {
+		p.release(rt.TotalAmount)
+		return err
+	}
+
+	log.Debugf("Sent probe %v to %v", attemptID, target)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.release(rt.TotalAmount)
+
+		err := p.collectResult(
+			attemptID, attemptTime, rt, sessionKey, hash,
+		)
+		if err != nil {
+			log.Debugf("Unable to collect result of probe %v: %v",
+				attemptID, err)
+		}
+	}()
+
+	return nil
+}
+
+// sendProbe sends an htlc with a random payment hash along the route. Local
+// failures are reported to mission control like the ones of real payments.
+func (p *Prober) sendProbe(rt *route.Route, attemptTime time.Time) (uint64,
+	*btcec.PrivateKey, lntypes.Hash, error) {
+
+	var hash lntypes.Hash
+	if _, err := rand.Read(hash[:]); err != nil {
+		return 0, nil, hash, err
+	}
+
+	sessionKey, err := generateNewSessionKey()
+	if err != nil {
+		return 0, nil, hash, err
+	}
+
+	attemptID, err := p.cfg.NextPaymentID()
+	if err != nil {
+		return 0, nil, hash, err
+	}
+
+	onionBlob, _, err := generateSphinxPacket(rt, hash[:], sessionKey)
+	if err != nil {
+		return 0, nil, hash, err
+	}
+
+	htlcAdd := &lnwire.UpdateAddHTLC{
+		Amount:      rt.TotalAmount,
+		Expiry:      rt.TotalTimeLock,
+		PaymentHash: hash,
+	}
+	copy(htlcAdd.OnionBlob[:], onionBlob)
+
+	firstHop := lnwire.NewShortChanIDFromInt(rt.Hops[0].ChannelID)
+	err = p.cfg.Payer.SendHTLC(firstHop, attemptID, htlcAdd)
+	if err != nil {
+		p.reportResult(attemptID, attemptTime, rt, err)
+		return 0, nil, hash, err
+	}
+
+	return attemptID, sessionKey, hash, nil
+}
+
+// collectResult waits for the outcome of the probe and reports it to mission
+// control.
+func (p *Prober) collectResult(attemptID uint64, attemptTime time.Time,
+	rt *route.Route, sessionKey *btcec.PrivateKey,
+	hash lntypes.Hash) error {
+
+	_, circuit, err := generateSphinxPacket(rt, hash[:], sessionKey)
+	if err != nil {
+		return err
+	}
+
+	errorDecryptor := &htlcswitch.SphinxErrorDecrypter{
+		OnionErrorDecrypter: sphinx.NewOnionErrorDecrypter(circuit),
+		Circuit:             circuit,
+	}
+
+	resultChan, err := p.cfg.Payer.GetAttemptResult(
+		attemptID, hash, errorDecryptor,
+	)
+	if err != nil {
+		return err
+	}
+
+	var (
+		result *htlcswitch.PaymentResult
+		ok     bool
+	)
+	select {
+	case result, ok = <-resultChan:
+		if !ok {
+			return htlcswitch.ErrSwitchExiting
+		}
+
+	case <-p.quit:
+		return errors.New("prober exiting")
+	}
+
+	// A probe can't settle, as nobody knows the preimage of its hash.
+	if result.Error == nil {
+		return errors.New("probe settled unexpectedly")
+	}
+
+	p.reportResult(attemptID, attemptTime, rt, result.Error)
+
+	return nil
+}
+
+// reportResult reports the failure of a probe to mission control. A failure
+// with incorrect payment details means that the probe reached its target,
+// which mission control records as a success of all pairs of the route.
+func (p *Prober) reportResult(attemptID uint64, attemptTime time.Time,
+	rt *route.Route, probeErr error) {
+
+	var (
+		srcIdx      *int
+		msg         lnwire.FailureMessage
+		attribution *FailureAttribution
+		unreadable  *htlcswitch.UnreadableFailureError
+		rtErr       htlcswitch.ClearTextError
+	)
+
+	switch {
+	case errors.As(probeErr, &unreadable):
+		attribution = &FailureAttribution{
+			FailingHopIdx: unreadable.FailingHopIdx,
+			HoldTimes:     unreadable.HoldTimes,
+		}
+
+	case errors.Is(probeErr, htlcswitch.ErrUnreadableFailureMessage):
+
+	case errors.As(probeErr, &rtErr):
+		// Failures that aren't forwarding errors occurred at our own
+		// node.
+		idx := 0
+		var source *htlcswitch.ForwardingError
+		if errors.As(rtErr, &source) {
+			idx = source.FailureSourceIdx
+
+			if len(source.HoldTimes) > 0 {
+				attribution = &FailureAttribution{
+					HoldTimes: source.HoldTimes,
+				}
+			}
+		}
+
+		srcIdx = &idx
+		msg = rtErr.WireMessage()
+
+	default:
+		log.Debugf("Probe %v failed without a failure message: %v",
+			attemptID, probeErr)
+
+		return
+	}
+
+	reason, err := p.cfg.MissionControl.ReportPaymentFail(
+		attemptID, attemptTime, rt, srcIdx, msg, attribution,
+	)
+	if err != nil {
+		log.Errorf("Error reporting probe result to mc: %v", err)
+		return
+	}
+
+	if reason != nil && *reason == channeldb.FailureReasonPaymentDetails {
+		log.Debugf("Probe %v reached %v", attemptID,
+			rt.FinalHop().PubKeyBytes)
+
+		return
+	}
+
+	log.Debugf("Probe %v to %v failed: %v", attemptID,
+		rt.FinalHop().PubKeyBytes, probeErr)
+}
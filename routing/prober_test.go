@@ -0,0 +1,182 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProberTargets tests that the prober probes the configured nodes and
+// channels and the top destinations of the payment history.
+func TestProberTargets(t *testing.T) {
+	t.Parallel()
+
+	var (
+		self  = route.Vertex{1}
+		nodeA = route.Vertex{2}
+		nodeB = route.Vertex{3}
+		nodeC = route.Vertex{4}
+		amt   = lnwire.MilliSatoshi(1000)
+	)
+
+	// settledPayment returns a payment to the given node.
+	settledPayment := func(dest route.Vertex,
+		value lnwire.MilliSatoshi) *channeldb.MPPayment {
+
+		return &channeldb.MPPayment{
+			Info: &channeldb.PaymentCreationInfo{
+				Value: value,
+			},
+			HTLCs: []channeldb.HTLCAttempt{{
+				HTLCAttemptInfo: channeldb.HTLCAttemptInfo{
+					Route: route.Route{
+						Hops: []*route.Hop{{
+							PubKeyBytes: dest,
+						}},
+					},
+				},
+				Settle: &channeldb.HTLCSettleInfo{},
+			}},
+		}
+	}
+
+	prober, err := NewProber(&ProberConfig{
+		SelfNode: self,
+		Nodes:    []route.Vertex{nodeA, self},
+		Channels: []uint64{1, 2, 3},
+		FetchChannelNodes: func(chanID uint64) (route.Vertex,
+			route.Vertex, error) {
+
+			switch chanID {
+			case 1:
+				return nodeA, nodeB, nil
+
+			// Our own channels are skipped.
+			case 2:
+				return self, nodeA, nil
+
+			default:
+				return route.Vertex{}, route.Vertex{},
+					errors.New("unknown channel")
+			}
+		},
+		TopDestinations: 3,
+		QueryPayments: func(query channeldb.PaymentsQuery) (
+			channeldb.PaymentsResponse, error) {
+
+			return channeldb.PaymentsResponse{
+				Payments: []*channeldb.MPPayment{
+					settledPayment(nodeC, 100),
+					settledPayment(nodeB, 300),
+					settledPayment(nodeC, 200),
+					settledPayment(nodeA, 5000),
+				},
+			}, nil
+		},
+		Amount:      amt,
+		Interval:    time.Minute,
+		MaxInFlight: 4000,
+	})
+	require.NoError(t, err)
+
+	targets, err := prober.fetchTargets()
+	require.NoError(t, err)
+
+	// The most frequent destination comes first, followed by the most
+	// recent one. Amounts above the in flight budget are capped.
+	require.Equal(t, []ProbeTarget{
+		{Node: nodeA, Amount: amt},
+		{Node: nodeB, LastHop: &nodeA, Amount: amt},
+		{Node: nodeA, LastHop: &nodeB, Amount: amt},
+		{Node: nodeC, Amount: 150},
+		{Node: nodeB, Amount: 300},
+		{Node: nodeA, Amount: 4000},
+	}, targets)
+}
+
+// TestProberProbe tests that the outcome of a probe is reported to mission
+// control, and that probes aren't sent if the in flight budget is exhausted.
+func TestProberProbe(t *testing.T) {
+	t.Parallel()
+
+	var (
+		payer   = &mockPaymentAttemptDispatcher{}
+		mc      = &mockMissionControl{}
+		amt     = lnwire.MilliSatoshi(10000)
+		rt      = createDummyRoute(t, amt)
+		target  = rt.FinalHop().PubKeyBytes
+		results = make(chan *htlcswitch.PaymentResult, 1)
+	)
+
+	prober, err := NewProber(&ProberConfig{
+		MissionControl: mc,
+		FindRoute: func(req *RouteRequest) (*route.Route, float64,
+			error) {
+
+			require.Equal(t, target, req.Target)
+			require.Equal(t, amt, req.Amount)
+
+			return rt, 1, nil
+		},
+		Payer: payer,
+		NextPaymentID: func() (uint64, error) {
+			return 7, nil
+		},
+		Amount:      amt,
+		Interval:    time.Minute,
+		MaxInFlight: rt.TotalAmount,
+	})
+	require.NoError(t, err)
+
+	firstHop := lnwire.NewShortChanIDFromInt(rt.Hops[0].ChannelID)
+	payer.On(
+		"SendHTLC", firstHop, uint64(7), mock.MatchedBy(
+			func(htlc *lnwire.UpdateAddHTLC) bool {
+				return htlc.Amount == rt.TotalAmount
+			},
+		),
+	).Return(nil).Once()
+	payer.On(
+		"GetAttemptResult", uint64(7), mock.Anything, mock.Anything,
+	).Return(results, nil).Once()
+
+	probeTarget := ProbeTarget{Node: target, Amount: amt}
+	require.NoError(t, prober.probe(probeTarget))
+
+	// While the probe is in flight, the budget is exhausted.
+	require.ErrorContains(t, prober.probe(probeTarget), "budget")
+
+	// The probe reaches the target, which is reported to mission control.
+	failure := lnwire.NewFailIncorrectDetails(amt, 100)
+	reason := channeldb.FailureReasonPaymentDetails
+	reported := make(chan struct{})
+	srcIdx := 2
+	mc.On("ReportPaymentFail", uint64(7), rt, &srcIdx, failure).
+		Return(&reason, nil).Once().
+		Run(func(mock.Arguments) {
+			close(reported)
+		})
+
+	results <- &htlcswitch.PaymentResult{
+		Error: htlcswitch.NewForwardingError(failure, 2),
+	}
+
+	select {
+	case <-reported:
+	case <-time.After(time.Second):
+		t.Fatal("probe result not reported")
+	}
+
+	require.NoError(t, prober.Stop())
+	require.Zero(t, prober.inFlight)
+
+	payer.AssertExpectations(t)
+	mc.AssertExpectations(t)
+}
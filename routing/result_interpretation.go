@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/fn"
@@ -18,6 +19,12 @@ var (
 	reasonIncorrectDetails = channeldb.FailureReasonPaymentDetails
 )
 
+// maxPairHoldTime is the maximum time a pair of nodes may add to the hold
+// time of a failed htlc before we penalize the pair. Forwarding an htlc and
+// passing back its failure takes a few commitment updates, which shouldn't
+// come close to this.
+const maxPairHoldTime = 5 * time.Second
+
 // pairResult contains the result of the interpretation of a payment attempt for
 // a specific node pair.
 type pairResult struct {
@@ -107,8 +114,22 @@ func (i *interpretedResult) processSuccess(route *mcRoute) {
 
 // processFail processes a failed payment attempt.
 func (i *interpretedResult) processFail(rt *mcRoute, failure paymentFailure) {
+	// Penalize the pairs that held the htlc for too long, after the
+	// failure itself has been interpreted.
+	defer failure.holdTimes.WhenSomeV(func(h holdTimes) {
+		i.processHoldTimes(rt, h)
+	})
+
 	if failure.info.IsNone() {
-		i.processPaymentOutcomeUnknown(rt)
+		// If the attribution data tells us which pair of nodes
+		// garbled the failure, we only penalize that pair.
+		failingIdx := failure.failingHopIdx.ValOpt().UnwrapOr(0)
+		if failingIdx == 0 {
+			i.processPaymentOutcomeUnknown(rt)
+			return
+		}
+
+		i.processPaymentOutcomeGarbled(rt, int(failingIdx))
 		return
 	}
 
@@ -563,6 +584,59 @@ func (i *interpretedResult) processPaymentOutcomeUnknown(route *mcRoute) {
 	i.failPairRange(route, 0, n-1)
 }
 
+// processPaymentOutcomeGarbled handles failures that couldn't be decrypted,
+// but whose attribution data tells us which pair of nodes garbled them. The
+// node at failingIdx didn't add a valid HMAC, so either that node or the one
+// before it tampered with the failure.
+func (i *interpretedResult) processPaymentOutcomeGarbled(route *mcRoute,
+	failingIdx int) {
+
+	// For a direct payment there is no pair to narrow the failure down
+	// to, so it is handled like any other unreadable failure.
+	n := len(route.hops.Val)
+	if n == 1 || failingIdx > n {
+		i.processPaymentOutcomeUnknown(route)
+		return
+	}
+
+	i.failPair(route, failingIdx-1)
+
+	// The nodes before the failing pair passed back a valid HMAC, so they
+	// must have forwarded the htlc.
+	if failingIdx > 1 {
+		i.successPairRange(route, 0, failingIdx-2)
+	}
+}
+
+// processHoldTimes penalizes the pairs of nodes that added more than
+// maxPairHoldTime to the hold time of the htlc. The hold time reported by a
+// node includes the hold times of the nodes after it, so the time a pair added
+// is the difference between the hold times of its nodes. The final node may
+// hold htlcs for as long as it likes, so we never penalize the pair leading to
+// it for its own hold time.
+func (i *interpretedResult) processHoldTimes(rt *mcRoute,
+	holdTimes []time.Duration) {
+
+	n := len(rt.hops.Val)
+	for hopIdx, holdTime := range holdTimes {
+		// The hold time of the node at index hopIdx+1 is attributed to
+		// its incoming pair if it is the last node that reported one.
+		pairIdx := hopIdx
+		if hopIdx+1 < len(holdTimes) {
+			holdTime -= holdTimes[hopIdx+1]
+			pairIdx = hopIdx + 1
+		} else if hopIdx+1 >= n {
+			continue
+		}
+
+		if pairIdx >= n || holdTime <= maxPairHoldTime {
+			continue
+		}
+
+		i.failPair(rt, pairIdx)
+	}
+}
+
 // extractMCRoute extracts the fields required by MC from the Route struct to
 // create the more minimal mcRoute struct.
 func extractMCRoute(r *route.Route) *mcRoute {
@@ -851,3 +925,31 @@ func getPair(rt *mcRoute, channelIdx int) (DirectedNodePair,
 
 	return pair, amt
 }
+
+// latencyPairs returns the pairs of the route that the htlc traversed before
+// the result was returned. For a successful attempt these are all pairs of the
+// route. For a failed attempt these are the pairs up to the failure source.
+// If the failure source is unknown, no pairs are returned.
+func latencyPairs(rt *mcRoute,
+	failure fn.Option[paymentFailure]) []DirectedNodePair {
+
+	hops := rt.hops.Val
+	n := len(hops)
+	failure.WhenSome(func(f paymentFailure) {
+		n = 0
+		f.info.ValOpt().WhenSome(func(info paymentFailureInfo) {
+			n = min(int(info.sourceIdx.Val), len(hops))
+		})
+	})
+
+	pairs := make([]DirectedNodePair, 0, n)
+	from := rt.sourcePubKey.Val
+	for _, hop := range hops[:n] {
+		pairs = append(
+			pairs, NewDirectedNodePair(from, hop.pubKeyBytes.Val),
+		)
+		from = hop.pubKeyBytes.Val
+	}
+
+	return pairs
+}
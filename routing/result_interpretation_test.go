@@ -3,6 +3,7 @@ package routing
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/davecgh/go-spew/spew"
@@ -195,6 +196,7 @@ type resultTestCase struct {
 	success       bool
 	failureSrcIdx int
 	failure       lnwire.FailureMessage
+	attribution   *FailureAttribution
 
 	expectedResult *interpretedResult
 }
@@ -682,6 +684,86 @@ var resultTestCases = []resultTestCase{
 			finalFailureReason: &reasonError,
 		},
 	},
+	// An unreadable failure with attribution data that narrows it down to
+	// the pair of nodes that garbled it.
+	{
+		name:  "unreadable failure attributed",
+		route: routeFourHop,
+		attribution: &FailureAttribution{
+			FailingHopIdx: 3,
+		},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+				getTestPair(2, 3): failPairResult(0),
+				getTestPair(3, 2): failPairResult(0),
+			},
+		},
+	},
+	// An unreadable failure from a direct peer can't be narrowed down any
+	// further by attribution data.
+	{
+		name:  "unreadable failure attributed direct",
+		route: routeOneHop,
+		attribution: &FailureAttribution{
+			FailingHopIdx: 1,
+		},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): failPairResult(0),
+				getTestPair(1, 0): failPairResult(0),
+			},
+			nodeFailure:        &hops[1],
+			finalFailureReason: &reasonError,
+		},
+	},
+	// A failure whose hold times show that a pair of nodes delayed it.
+	{
+		name:          "fail with hold times",
+		route:         routeFourHop,
+		failureSrcIdx: 4,
+		failure:       lnwire.NewFailIncorrectDetails(90, 0),
+		attribution: &FailureAttribution{
+			HoldTimes: []time.Duration{
+				10 * time.Second, 9 * time.Second,
+				2 * time.Second, time.Second,
+			},
+		},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+				getTestPair(2, 3): failPairResult(0),
+				getTestPair(3, 2): failPairResult(0),
+				getTestPair(3, 4): successPairResult(94),
+			},
+			finalFailureReason: &reasonIncorrectDetails,
+		},
+	},
+	// The final node may hold a failure for as long as it likes.
+	{
+		name:          "fail with final hold time",
+		route:         routeTwoHop,
+		failureSrcIdx: 2,
+		failure:       lnwire.NewFailIncorrectDetails(97, 0),
+		attribution: &FailureAttribution{
+			HoldTimes: []time.Duration{
+				time.Minute, time.Minute,
+			},
+		},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+			},
+			finalFailureReason: &reasonIncorrectDetails,
+		},
+	},
 }
 
 // TestResultInterpretation executes a list of test cases that test the result
@@ -693,10 +775,16 @@ func TestResultInterpretation(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			var failure fn.Option[paymentFailure]
 			if !testCase.success {
-				failure = fn.Some(*newPaymentFailure(
-					&testCase.failureSrcIdx,
-					testCase.failure,
-				))
+				// A failure without a message is one that we
+				// couldn't decrypt.
+				var srcIdx *int
+				if testCase.failure != nil {
+					srcIdx = &testCase.failureSrcIdx
+				}
+
+				f := newPaymentFailure(srcIdx, testCase.failure)
+				f.setAttribution(testCase.attribution)
+				failure = fn.Some(*f)
 			}
 
 			i := interpretResult(testCase.route, failure)
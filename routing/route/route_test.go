@@ -160,6 +160,35 @@ func TestAMPHop(t *testing.T) {
 	}
 }
 
+// TestTrampolineHop asserts that a Hop will encode a non-nil trampoline onion
+// to final nodes, and fail when trying to send to intermediaries.
+func TestTrampolineHop(t *testing.T) {
+	t.Parallel()
+
+	hop := Hop{
+		ChannelID:        1,
+		OutgoingTimeLock: 44,
+		AmtToForward:     testAmt,
+		MPP:              record.NewMPP(testAmt, testAddr),
+		TrampolineOnion:  []byte{1, 2, 3},
+	}
+
+	// Encoding a trampoline onion to an intermediate hop should result in
+	// a failure.
+	var b bytes.Buffer
+	err := hop.PackHopPayload(&b, 2, false)
+	require.ErrorIs(t, err, ErrIntermediateMPPHop)
+
+	hop.MPP = nil
+	b.Reset()
+	err = hop.PackHopPayload(&b, 2, false)
+	require.ErrorIs(t, err, ErrIntermediateTrampolineHop)
+
+	// Encoding a trampoline onion to a final hop should be successful.
+	b.Reset()
+	require.NoError(t, hop.PackHopPayload(&b, 0, true))
+}
+
 // TestBlindedHops tests packing of a hop payload for various types of hops in
 // a blinded route.
 func TestBlindedHops(t *testing.T) {
@@ -300,7 +329,8 @@ func TestPayloadSize(t *testing.T) {
 				100000:  {1, 2, 3},
 				1000000: {4, 5},
 			},
-			Metadata: []byte{10, 11},
+			Metadata:        []byte{10, 11},
+			TrampolineOnion: []byte{12, 13, 14},
 		},
 	}
 
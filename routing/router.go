@@ -171,21 +171,31 @@ type PaymentSessionSource interface {
 // probability estimation.
 type MissionControlQuerier interface {
 	// ReportPaymentFail reports a failed payment to mission control as
-	// input for future probability estimates. It returns a bool indicating
-	// whether this error is a final error and no further payment attempts
-	// need to be made.
-	ReportPaymentFail(attemptID uint64, rt *route.Route,
-		failureSourceIdx *int, failure lnwire.FailureMessage) (
-		*channeldb.FailureReason, error)
+	// input for future probability estimates. The attemptTime is the time
+	// at which the htlc was sent. The attribution holds what the
+	// attribution data of the failure tells us about the hops, and may be
+	// nil. It returns a bool indicating whether this error is a final
+	// error and no further payment attempts need to be made.
+	ReportPaymentFail(attemptID uint64, attemptTime time.Time,
+		rt *route.Route, failureSourceIdx *int,
+		failure lnwire.FailureMessage,
+		attribution *FailureAttribution) (*channeldb.FailureReason,
+		error)
 
 	// ReportPaymentSuccess reports a successful payment to mission control
-	// as input for future probability estimates.
-	ReportPaymentSuccess(attemptID uint64, rt *route.Route) error
+	// as input for future probability estimates. The attemptTime is the
+	// time at which the htlc was sent.
+	ReportPaymentSuccess(attemptID uint64, attemptTime time.Time,
+		rt *route.Route) error
 
 	// GetProbability is expected to return the success probability of a
 	// payment from fromNode along edge.
 	GetProbability(fromNode, toNode route.Vertex,
 		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
+
+	// GetLatency is expected to return the expected latency of forwarding
+	// an htlc from fromNode to toNode and relaying the result back.
+	GetLatency(fromNode, toNode route.Vertex) time.Duration
 }
 
 // FeeSchema is the set fee configuration for a Lightning Node on the network.
@@ -262,6 +272,13 @@ type Config struct {
 	// gained to the next execution.
 	MissionControl MissionControlQuerier
 
+	// NamespacedMissionControl returns the mission control of the given
+	// namespace. It is used for payments that select a namespace other
+	// than the default one. If nil, only the default namespace is
+	// available.
+	NamespacedMissionControl func(namespace string) (
+		MissionControlQuerier, error)
+
 	// SessionSource defines a source for the router to retrieve new payment
 	// sessions.
 	SessionSource PaymentSessionSource
@@ -518,8 +535,35 @@ func getTargetNode(target *route.Vertex,
 func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 	error) {
 
-	log.Debugf("Searching for path to %v, sending %v", req.Target,
-		req.Amount)
+	routes, probabilities, err := r.FindRoutes(req, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return routes[0], probabilities[0], nil
+}
+
+// FindRoutes queries the ChannelRouter for up to numRoutes distinct routes to
+// the target destination, ordered from best to worst by the same metric that
+// is used to select a single route. The success probability of every route is
+// returned alongside it. Fewer routes are returned if the graph doesn't
+// contain more distinct paths that satisfy the restrictions of the request.
+// Routes to blinded paths are limited to the single best route.
+func (r *ChannelRouter) FindRoutes(req *RouteRequest,
+	numRoutes uint32) ([]*route.Route, []float64, error) {
+
+	log.Debugf("Searching for %v path(s) to %v, sending %v", numRoutes,
+		req.Target, req.Amount)
+
+	if numRoutes == 0 {
+		return nil, nil, errors.New("number of routes must be positive")
+	}
+
+	// The hops of a blinded path are fixed, so alternative paths to them
+	// can't be derived by path finding.
+	if req.BlindedPathSet != nil {
+		numRoutes = 1
+	}
 
 	// We'll attempt to obtain a set of bandwidth hints that can help us
 	// eliminate certain routes early on in the path finding process.
@@ -528,14 +572,14 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 		fn.None[tlv.Blob](), r.cfg.TrafficShaper,
 	)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	// We'll fetch the current block height, so we can properly calculate
 	// the required HTLC time locks within the route.
 	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	// Now that we know the destination is reachable within the graph, we'll
@@ -545,10 +589,23 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 	// Validate time preference.
 	timePref := req.TimePreference
 	if timePref < -1 || timePref > 1 {
-		return nil, 0, errors.New("time preference out of range")
+		return nil, nil, errors.New("time preference out of range")
+	}
+
+	// Create the routes with absolute time lock values.
+	toRoute := func(path []*unifiedEdge) (*route.Route, error) {
+		return newRoute(
+			req.Source, path, uint32(currentHeight),
+			finalHopParams{
+				amt:       req.Amount,
+				totalAmt:  req.Amount,
+				cltvDelta: req.FinalExpiry,
+				records:   req.CustomRecords,
+			}, req.BlindedPathSet,
+		)
 	}
 
-	path, probability, err := findPath(
+	paths, err := findPaths(
 		&graphParams{
 			additionalEdges: req.RouteHints,
 			bandwidthHints:  bandwidthHints,
@@ -556,30 +613,24 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 		},
 		req.Restrictions, &r.cfg.PathFindingConfig,
 		r.cfg.SelfNode, req.Source, req.Target, req.Amount,
-		req.TimePreference, finalHtlcExpiry,
+		req.TimePreference, finalHtlcExpiry, int(numRoutes), toRoute,
 	)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
-	// Create the route with absolute time lock values.
-	route, err := newRoute(
-		req.Source, path, uint32(currentHeight),
-		finalHopParams{
-			amt:       req.Amount,
-			totalAmt:  req.Amount,
-			cltvDelta: req.FinalExpiry,
-			records:   req.CustomRecords,
-		}, req.BlindedPathSet,
-	)
-	if err != nil {
-		return nil, 0, err
+	routes := make([]*route.Route, 0, len(paths))
+	probabilities := make([]float64, 0, len(paths))
+	for _, path := range paths {
+		routes = append(routes, path.route)
+		probabilities = append(probabilities, path.probability)
 	}
 
-	go log.Tracef("Obtained path to send %v to %x: %v",
-		req.Amount, req.Target, lnutils.SpewLogClosure(route))
+	go log.Tracef("Obtained %v path(s) to send %v to %x: %v",
+		len(routes), req.Amount, req.Target,
+		lnutils.SpewLogClosure(routes))
 
-	return route, probability, nil
+	return routes, probabilities, nil
 }
 
 // probabilitySource defines the signature of a function that can be used to
@@ -814,6 +865,10 @@ type LightningPayment struct {
 	// payment.
 	amp *AMPOptions
 
+	// simulation is set if the payment is only simulated. Its payment
+	// sessions report to it instead of just handing out routes.
+	simulation *paymentSimulation
+
 	// FinalCLTVDelta is the CTLV expiry delta to use for the _final_ hop
 	// in the route. This means that the final hop will have a CLTV delta
 	// of at least: currentHeight + FinalCLTVDelta.
@@ -879,6 +934,19 @@ type LightningPayment struct {
 	// wire message and therefore do not affect the onion payload size.
 	FirstHopCustomRecords lnwire.CustomRecords
 
+	// Label is an optional label that is stored with the payment. It
+	// marks payments that are made by internal services.
+	Label string
+
+	// MissionControlNamespace is the mission control namespace that
+	// provides the success probabilities for the payment and that its
+	// results are reported to. If empty, the default namespace is used.
+	MissionControlNamespace string
+
+	// missionControl is the mission control of the payment's namespace.
+	// It is set when the payment is prepared.
+	missionControl MissionControlQuerier
+
 	// MaxParts is the maximum number of partial payments that may be used
 	// to complete the full amount.
 	MaxParts uint32
@@ -896,9 +964,18 @@ type LightningPayment struct {
 	// value in between for a mix.
 	TimePref float64
 
+	// SplitStrategy determines how the payment is split into multiple
+	// shards if it can't be sent in one piece.
+	SplitStrategy SplitStrategy
+
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// TrampolineOnion is the serialized trampoline onion that is attached
+	// to the final hop. If set, Target must be a trampoline node which
+	// forwards the payment according to the instructions in the onion.
+	TrampolineOnion []byte
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -962,7 +1039,7 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte,
 	return r.sendPayment(
 		context.Background(), payment.FeeLimit, payment.Identifier(),
 		payment.PayAttemptTimeout, paySession, shardTracker,
-		payment.FirstHopCustomRecords,
+		payment.FirstHopCustomRecords, payment.missionControl,
 	)
 }
 
@@ -983,7 +1060,7 @@ func (r *ChannelRouter) SendPaymentAsync(ctx context.Context,
 		_, _, err := r.sendPayment(
 			ctx, payment.FeeLimit, payment.Identifier(),
 			payment.PayAttemptTimeout, ps, st,
-			payment.FirstHopCustomRecords,
+			payment.FirstHopCustomRecords, payment.missionControl,
 		)
 		if err != nil {
 			log.Errorf("Payment %x failed: %v",
@@ -992,6 +1069,44 @@ func (r *ChannelRouter) SendPaymentAsync(ctx context.Context,
 	}()
 }
 
+// missionControl returns the mission control of the given namespace. An empty
+// namespace refers to the default one.
+func (r *ChannelRouter) missionControl(
+	namespace string) (MissionControlQuerier, error) {
+
+	if namespace == "" || namespace == DefaultMissionControlNamespace {
+		return r.cfg.MissionControl, nil
+	}
+
+	if r.cfg.NamespacedMissionControl == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNamespaceNotFound,
+			namespace)
+	}
+
+	return r.cfg.NamespacedMissionControl(namespace)
+}
+
+// firstHopBlob returns the serialized custom records of the payment that are
+// sent to the first hop only, if there are any.
+func (l *LightningPayment) firstHopBlob() (fn.Option[tlv.Blob], error) {
+	if len(l.FirstHopCustomRecords) == 0 {
+		return fn.None[tlv.Blob](), nil
+	}
+
+	if err := l.FirstHopCustomRecords.Validate(); err != nil {
+		return fn.None[tlv.Blob](), fmt.Errorf("invalid first hop "+
+			"custom records: %w", err)
+	}
+
+	firstHopBlob, err := l.FirstHopCustomRecords.Serialize()
+	if err != nil {
+		return fn.None[tlv.Blob](), fmt.Errorf("unable to serialize "+
+			"first hop custom records: %w", err)
+	}
+
+	return fn.Some(firstHopBlob), nil
+}
+
 // spewPayment returns a log closures that provides a spewed string
 // representation of the passed payment.
 func spewPayment(payment *LightningPayment) lnutils.LogClosure {
@@ -1019,20 +1134,18 @@ func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 	PaymentSession, shards.ShardTracker, error) {
 
 	// Assemble any custom data we want to send to the first hop only.
-	var firstHopData fn.Option[tlv.Blob]
-	if len(payment.FirstHopCustomRecords) > 0 {
-		if err := payment.FirstHopCustomRecords.Validate(); err != nil {
-			return nil, nil, fmt.Errorf("invalid first hop custom "+
-				"records: %w", err)
-		}
-
-		firstHopBlob, err := payment.FirstHopCustomRecords.Serialize()
-		if err != nil {
-			return nil, nil, fmt.Errorf("unable to serialize "+
-				"first hop custom records: %w", err)
-		}
+	firstHopData, err := payment.firstHopBlob()
+	if err != nil {
+		return nil, nil, err
+	}
 
-		firstHopData = fn.Some(firstHopBlob)
+	// Look up the mission control of the payment's namespace, which is
+	// used by both the payment session and the payment lifecycle.
+	payment.missionControl, err = r.missionControl(
+		payment.MissionControlNamespace,
+	)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Before starting the HTLC routing attempt, we'll create a fresh
@@ -1050,11 +1163,13 @@ func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 	//
 	// TODO(roasbeef): store records as part of creation info?
 	info := &channeldb.PaymentCreationInfo{
-		PaymentIdentifier:     payment.Identifier(),
-		Value:                 payment.Amount,
-		CreationTime:          r.cfg.Clock.Now(),
-		PaymentRequest:        payment.PaymentRequest,
-		FirstHopCustomRecords: payment.FirstHopCustomRecords,
+		PaymentIdentifier:       payment.Identifier(),
+		Value:                   payment.Amount,
+		CreationTime:            r.cfg.Clock.Now(),
+		PaymentRequest:          payment.PaymentRequest,
+		FirstHopCustomRecords:   payment.FirstHopCustomRecords,
+		Label:                   payment.Label,
+		MissionControlNamespace: payment.MissionControlNamespace,
 	}
 
 	// Create a new ShardTracker that we'll use during the life cycle of
@@ -1186,7 +1301,7 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 	// - no current block height.
 	p := newPaymentLifecycle(
 		r, 0, paymentIdentifier, nil, shardTracker, 0,
-		firstHopCustomRecords,
+		firstHopCustomRecords, r.cfg.MissionControl,
 	)
 
 	// Allow the traffic shaper to add custom records to the outgoing HTLC
@@ -1291,7 +1406,8 @@ func (r *ChannelRouter) sendPayment(ctx context.Context,
 	feeLimit lnwire.MilliSatoshi, identifier lntypes.Hash,
 	paymentAttemptTimeout time.Duration, paySession PaymentSession,
 	shardTracker shards.ShardTracker,
-	firstHopCustomRecords lnwire.CustomRecords) ([32]byte, *route.Route,
+	firstHopCustomRecords lnwire.CustomRecords,
+	missionControl MissionControlQuerier) ([32]byte, *route.Route,
 	error) {
 
 	// If the user provides a timeout, we will additionally wrap the context
@@ -1322,7 +1438,7 @@ func (r *ChannelRouter) sendPayment(ctx context.Context,
 	// can resume the payment from the current state.
 	p := newPaymentLifecycle(
 		r, feeLimit, identifier, paySession, shardTracker,
-		currentHeight, firstHopCustomRecords,
+		currentHeight, firstHopCustomRecords, missionControl,
 	)
 
 	return p.resumePayment(ctx)
@@ -1524,10 +1640,23 @@ func (r *ChannelRouter) resumePayments() error {
 		// it to timeout. It will stop immediately after the existing
 		// attempt has finished anyway. We also set a zero fee limit,
 		// as no more routes should be tried.
+		// The results of the in-flight attempts are reported to the
+		// mission control namespace of the payment. If it was deleted
+		// in the meantime, the default namespace is used.
+		mc, err := r.missionControl(
+			payment.Info.MissionControlNamespace,
+		)
+		if err != nil {
+			log.Warnf("Reporting results of payment %v to the "+
+				"default mission control: %v", payHash, err)
+
+			mc = r.cfg.MissionControl
+		}
+
 		noTimeout := time.Duration(0)
-		_, _, err := r.sendPayment(
+		_, _, err = r.sendPayment(
 			context.Background(), 0, payHash, noTimeout, paySession,
-			shardTracker, payment.Info.FirstHopCustomRecords,
+			shardTracker, payment.Info.FirstHopCustomRecords, mc,
 		)
 		if err != nil {
 			log.Errorf("Resuming payment %v failed: %v", payHash,
@@ -0,0 +1,391 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// maxRejectedEdges is the maximum number of rejected edges that are returned
+// for a simulated payment. The edges that were rejected most often are
+// returned first.
+const maxRejectedEdges = 100
+
+// EdgeRejectReason describes why path finding discarded an edge.
+type EdgeRejectReason uint8
+
+const (
+	// EdgeRejectNoCapacity is used when none of the channels between the
+	// two nodes is able to carry the amount, because of its capacity,
+	// its htlc limits or the local balance.
+	EdgeRejectNoCapacity EdgeRejectReason = iota
+
+	// EdgeRejectFeeLimit is used when the fees up to the edge exceed the
+	// fee limit of the payment.
+	EdgeRejectFeeLimit
+
+	// EdgeRejectZeroProbability is used when mission control considers
+	// the edge to fail for certain.
+	EdgeRejectZeroProbability
+
+	// EdgeRejectCltvLimit is used when the time lock up to the edge
+	// exceeds the cltv limit of the payment.
+	EdgeRejectCltvLimit
+
+	// EdgeRejectMinProbability is used when the success probability of
+	// the path up to the edge drops below the configured minimum.
+	EdgeRejectMinProbability
+
+	// EdgeRejectPayloadSize is used when the onion payloads of the path up
+	// to the edge don't fit into the onion.
+	EdgeRejectPayloadSize
+)
+
+// String returns a human-readable name of the reject reason.
+func (r EdgeRejectReason) String() string {
+	switch r {
+	case EdgeRejectNoCapacity:
+		return "no_capacity"
+
+	case EdgeRejectFeeLimit:
+		return "fee_limit"
+
+	case EdgeRejectZeroProbability:
+		return "zero_probability"
+
+	case EdgeRejectCltvLimit:
+		return "cltv_limit"
+
+	case EdgeRejectMinProbability:
+		return "min_probability"
+
+	case EdgeRejectPayloadSize:
+		return "payload_size"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(r))
+	}
+}
+
+// SimulatedShard is a shard of a simulated payment.
+type SimulatedShard struct {
+	// Route is the route that the shard would be sent along.
+	Route *route.Route
+
+	// Probability is the success probability of the route according to
+	// mission control.
+	Probability float64
+}
+
+// RejectedEdge is an edge that path finding discarded while planning a
+// simulated payment.
+type RejectedEdge struct {
+	// FromNode is the node that would have forwarded the payment.
+	FromNode route.Vertex
+
+	// ToNode is the node that would have received the payment.
+	ToNode route.Vertex
+
+	// Reason is the reason why the edge was discarded.
+	Reason EdgeRejectReason
+
+	// Amount is the largest amount for which the edge was discarded.
+	Amount lnwire.MilliSatoshi
+
+	// Count is the number of times the edge was discarded for this
+	// reason.
+	Count uint32
+}
+
+// PaymentSimulation is the outcome of a simulated payment.
+type PaymentSimulation struct {
+	// Shards are the shards that the payment would be split into. If the
+	// simulation failed, these are the shards that could be planned
+	// before it failed.
+	Shards []*SimulatedShard
+
+	// TotalFees is the sum of the fees of all shards.
+	TotalFees lnwire.MilliSatoshi
+
+	// TotalTimeLock is the highest time lock of all shards.
+	TotalTimeLock uint32
+
+	// SuccessProbability is the probability that all shards succeed. It
+	// is zero if no complete set of shards could be planned.
+	SuccessProbability float64
+
+	// FailureReason is set if no complete set of shards could be
+	// planned. A real payment would fail with the same reason.
+	FailureReason *channeldb.FailureReason
+
+	// RejectedEdges are the edges that path finding discarded, ordered by
+	// how often that happened.
+	RejectedEdges []*RejectedEdge
+
+	// RejectionCounts holds the number of discarded edges per reason.
+	RejectionCounts map[EdgeRejectReason]uint64
+}
+
+// rejectionKey identifies a rejected edge.
+type rejectionKey struct {
+	pair   DirectedNodePair
+	reason EdgeRejectReason
+}
+
+// paymentSimulation collects what the payment sessions of a simulated payment
+// observe during path finding.
+type paymentSimulation struct {
+	missionControl MissionControlQuerier
+
+	// spent holds the amounts that the planned shards send out through
+	// our channels.
+	spent map[uint64]lnwire.MilliSatoshi
+
+	// probabilities holds the success probabilities of the routes that
+	// were handed out.
+	probabilities map[*route.Route]float64
+
+	rejections      map[rejectionKey]*RejectedEdge
+	rejectionCounts map[EdgeRejectReason]uint64
+}
+
+// newPaymentSimulation returns a new simulation that estimates probabilities
+// using the given mission control.
+func newPaymentSimulation(mc MissionControlQuerier) *paymentSimulation {
+	return &paymentSimulation{
+		missionControl:  mc,
+		spent:           make(map[uint64]lnwire.MilliSatoshi),
+		probabilities:   make(map[*route.Route]float64),
+		rejections:      make(map[rejectionKey]*RejectedEdge),
+		rejectionCounts: make(map[EdgeRejectReason]uint64),
+	}
+}
+
+// simulatedBandwidthHints deducts the amounts of the planned shards from the
+// bandwidth of our channels.
+type simulatedBandwidthHints struct {
+	bandwidthHints
+
+	spent map[uint64]lnwire.MilliSatoshi
+}
+
+// availableChanBandwidth returns the bandwidth of the channel that isn't used
+// by the planned shards.
+//
+// NOTE: This is part of the bandwidthHints interface.
+func (s *simulatedBandwidthHints) availableChanBandwidth(channelID uint64,
+	amount lnwire.MilliSatoshi) (lnwire.MilliSatoshi, bool) {
+
+	bandwidth, ok := s.bandwidthHints.availableChanBandwidth(
+		channelID, amount,
+	)
+	if !ok {
+		return bandwidth, ok
+	}
+
+	spent := s.spent[channelID]
+	if spent >= bandwidth {
+		return 0, true
+	}
+
+	return bandwidth - spent, true
+}
+
+// wrapBandwidthHints wraps the bandwidth hints of a payment session, such
+// that they account for the planned shards.
+func (s *paymentSimulation) wrapBandwidthHints(
+	getBandwidthHints func(Graph) (bandwidthHints, error)) func(Graph) (
+	bandwidthHints, error) {
+
+	return func(graph Graph) (bandwidthHints, error) {
+		hints, err := getBandwidthHints(graph)
+		if err != nil {
+			return nil, err
+		}
+
+		return &simulatedBandwidthHints{
+			bandwidthHints: hints,
+			spent:          s.spent,
+		}, nil
+	}
+}
+
+// edgeRejected records an edge that path finding discarded.
+func (s *paymentSimulation) edgeRejected(fromNode, toNode route.Vertex,
+	amt lnwire.MilliSatoshi, reason EdgeRejectReason) {
+
+	s.rejectionCounts[reason]++
+
+	key := rejectionKey{
+		pair:   NewDirectedNodePair(fromNode, toNode),
+		reason: reason,
+	}
+	rejection, ok := s.rejections[key]
+	if !ok {
+		rejection = &RejectedEdge{
+			FromNode: fromNode,
+			ToNode:   toNode,
+			Reason:   reason,
+		}
+		s.rejections[key] = rejection
+	}
+
+	rejection.Count++
+	if amt > rejection.Amount {
+		rejection.Amount = amt
+	}
+}
+
+// routeFound records the success probability of a route that was built from
+// the given path.
+func (s *paymentSimulation) routeFound(rt *route.Route,
+	path []*unifiedEdge) {
+
+	probability := 1.0
+	fromNode := rt.SourcePubKey
+	for i, edge := range path {
+		// The amount that is sent along the edge is the amount that
+		// the previous hop forwards.
+		amt := rt.TotalAmount
+		if i > 0 && i <= len(rt.Hops) {
+			amt = rt.Hops[i-1].AmtToForward
+		}
+
+		toNode := edge.policy.ToNodePubKey()
+		probability *= s.missionControl.GetProbability(
+			fromNode, toNode, amt, edge.capacity,
+		)
+		fromNode = toNode
+	}
+
+	s.probabilities[rt] = probability
+}
+
+// result assembles the outcome of the simulation from the planned routes.
+func (s *paymentSimulation) result(routes []*route.Route,
+	failure *channeldb.FailureReason) *PaymentSimulation {
+
+	sim := &PaymentSimulation{
+		FailureReason:   failure,
+		RejectionCounts: s.rejectionCounts,
+	}
+
+	successProb := 1.0
+	for _, rt := range routes {
+		probability := s.probabilities[rt]
+		sim.Shards = append(sim.Shards, &SimulatedShard{
+			Route:       rt,
+			Probability: probability,
+		})
+
+		sim.TotalFees += rt.TotalFees()
+		if rt.TotalTimeLock > sim.TotalTimeLock {
+			sim.TotalTimeLock = rt.TotalTimeLock
+		}
+
+		successProb *= probability
+	}
+
+	if failure == nil && len(routes) > 0 {
+		sim.SuccessProbability = successProb
+	}
+
+	for _, rejection := range s.rejections {
+		sim.RejectedEdges = append(sim.RejectedEdges, rejection)
+	}
+	sort.Slice(sim.RejectedEdges, func(i, j int) bool {
+		a, b := sim.RejectedEdges[i], sim.RejectedEdges[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+
+		return a.Amount > b.Amount
+	})
+	if len(sim.RejectedEdges) > maxRejectedEdges {
+		sim.RejectedEdges = sim.RejectedEdges[:maxRejectedEdges]
+	}
+
+	return sim
+}
+
+// SimulatePayment runs the path finding of the given payment against the
+// local graph and mission control without sending anything. It plans the
+// shards the same way the payment session of SendPayment does, assuming that
+// every shard succeeds, and returns them along with the reasons why edges
+// were discarded along the way.
+func (r *ChannelRouter) SimulatePayment(
+	payment *LightningPayment) (*PaymentSimulation, error) {
+
+	firstHopData, err := payment.firstHopBlob()
+	if err != nil {
+		return nil, err
+	}
+
+	mc, err := r.missionControl(payment.MissionControlNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payment is copied, so that the caller's payment isn't tied to
+	// the simulation.
+	sim := newPaymentSimulation(mc)
+	simPayment := *payment
+	simPayment.simulation = sim
+	simPayment.missionControl = mc
+
+	paySession, err := r.cfg.SessionSource.NewPaymentSession(
+		&simPayment, firstHopData, r.cfg.TrafficShaper,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		routes    []*route.Route
+		remaining = payment.Amount
+		feesPaid  lnwire.MilliSatoshi
+	)
+	for remaining > 0 {
+		feeBudget := lnwire.MilliSatoshi(0)
+		if feesPaid < payment.FeeLimit {
+			feeBudget = payment.FeeLimit - feesPaid
+		}
+
+		rt, err := paySession.RequestRoute(
+			remaining, feeBudget, uint32(len(routes)),
+			uint32(currentHeight), payment.FirstHopCustomRecords,
+		)
+
+		// Just like the payment lifecycle, a non-critical path finding
+		// error fails the payment with its reason.
+		var routeErr noRouteError
+		switch {
+		case errors.As(err, &routeErr):
+			reason := routeErr.FailureReason()
+
+			return sim.result(routes, &reason), nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		// The shard is assumed to be in flight from now on, so its
+		// amount is no longer available on the first hop channel.
+		sim.spent[rt.Hops[0].ChannelID] += rt.TotalAmount
+
+		routes = append(routes, rt)
+		remaining -= rt.ReceiverAmt()
+		feesPaid += rt.TotalFees()
+	}
+
+	return sim.result(routes, nil), nil
+}
@@ -0,0 +1,125 @@
+package routing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulatePayment tests that a simulated payment is split across our
+// channels like a real payment, and that discarded edges are reported.
+func TestSimulatePayment(t *testing.T) {
+	t.Parallel()
+
+	// Set up a network with two paths to c, of which a->b->c is the
+	// cheaper one.
+	chanCapSat := btcutil.Amount(100000)
+	testChannels := []*testChannel{
+		symmetricTestChannel("a", "b", chanCapSat, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+			MinHTLC: 1,
+			MaxHTLC: lnwire.NewMSatFromSatoshis(chanCapSat),
+		}, 1),
+		symmetricTestChannel("b", "c", chanCapSat, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+			MinHTLC: 1,
+			MaxHTLC: lnwire.NewMSatFromSatoshis(chanCapSat),
+		}, 3),
+		symmetricTestChannel("a", "d", chanCapSat, &testChannelPolicy{
+			Expiry:      144,
+			FeeRate:     800,
+			FeeBaseMsat: 1000,
+			MinHTLC:     1,
+			MaxHTLC:     lnwire.NewMSatFromSatoshis(chanCapSat),
+		}, 2),
+		symmetricTestChannel("d", "c", chanCapSat, &testChannelPolicy{
+			Expiry:      144,
+			FeeRate:     800,
+			FeeBaseMsat: 1000,
+			MinHTLC:     1,
+			MaxHTLC:     lnwire.NewMSatFromSatoshis(chanCapSat),
+		}, 4),
+	}
+
+	testGraph, err := createTestGraphFromChannels(
+		t, true, testChannels, "a",
+	)
+	require.NoError(t, err)
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromGraphInstance(t, startingBlockHeight, testGraph)
+
+	// The amount exceeds the capacity of each of our channels, so it must
+	// be split.
+	payment := createDummyLightningPayment(
+		t, ctx.aliases["c"], lnwire.NewMSatFromSatoshis(120000),
+	)
+	payment.PaymentAddr = fn.Some([32]byte{1})
+	payment.DestFeatures = lnwire.NewFeatureVector(
+		mppFeatures, lnwire.Features,
+	)
+	payment.MaxParts = 16
+	payment.CltvLimit = math.MaxUint32
+
+	sim, err := ctx.router.SimulatePayment(payment)
+	require.NoError(t, err)
+	require.Nil(t, sim.FailureReason)
+
+	// The second shard can't use the cheaper path anymore, because the
+	// first shard uses up the balance of our channel with b.
+	require.Len(t, sim.Shards, 2)
+	require.Equal(t, uint64(1), sim.Shards[0].Route.Hops[0].ChannelID)
+	require.Equal(t, uint64(2), sim.Shards[1].Route.Hops[0].ChannelID)
+
+	var (
+		totalAmt lnwire.MilliSatoshi
+		totalFee lnwire.MilliSatoshi
+		prob     = 1.0
+	)
+	for _, shard := range sim.Shards {
+		require.Greater(t, shard.Probability, 0.0)
+		require.Less(t, shard.Probability, 1.0)
+
+		totalAmt += shard.Route.ReceiverAmt()
+		totalFee += shard.Route.TotalFees()
+		prob *= shard.Probability
+	}
+	require.Equal(t, payment.Amount, totalAmt)
+	require.Equal(t, totalFee, sim.TotalFees)
+	require.InDelta(t, prob, sim.SuccessProbability, 1e-9)
+	require.NotZero(t, sim.TotalTimeLock)
+
+	// The full amount didn't fit through any of our channels.
+	require.NotZero(t, sim.RejectionCounts[EdgeRejectNoCapacity])
+
+	// Nothing is registered with the control tower.
+	_, err = ctx.router.cfg.Control.FetchPayment(payment.Identifier())
+	require.ErrorIs(t, err, channeldb.ErrPaymentNotInitiated)
+
+	// Without a fee budget, the payment can't be routed through b or d.
+	payment.FeeLimit = 0
+	sim, err = ctx.router.SimulatePayment(payment)
+	require.NoError(t, err)
+
+	require.NotNil(t, sim.FailureReason)
+	require.Equal(t, channeldb.FailureReasonNoRoute, *sim.FailureReason)
+	require.Empty(t, sim.Shards)
+	require.Zero(t, sim.SuccessProbability)
+	require.NotZero(t, sim.RejectionCounts[EdgeRejectFeeLimit])
+
+	rejected := make(map[DirectedNodePair]EdgeRejectReason)
+	for _, edge := range sim.RejectedEdges {
+		pair := NewDirectedNodePair(edge.FromNode, edge.ToNode)
+		rejected[pair] = edge.Reason
+	}
+	require.Equal(t, EdgeRejectFeeLimit, rejected[NewDirectedNodePair(
+		ctx.aliases["a"], ctx.aliases["b"],
+	)])
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/davecgh/go-spew/spew"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainreg"
@@ -71,6 +72,8 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/offers"
+	"github.com/lightningnetwork/lnd/onionmessage"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/record"
@@ -331,6 +334,27 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/SpliceIn": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SpliceOut": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/BumpCloseFee": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/CloseChannel": {{
 			Entity: "onchain",
 			Action: "write",
@@ -495,6 +519,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/UpdateChannelParams": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/ForwardingHistory": {{
 			Entity: "offchain",
 			Action: "read",
@@ -569,6 +597,37 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SendOnionMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeOnionMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AddOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListOffers": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/FetchInvoice": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListLiquidityAds": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/PurchaseLease": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/LookupHtlcResolution": {{
 			Entity: "offchain",
 			Action: "read",
@@ -693,6 +752,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		return err
 	}
 	graph := s.graphDB
+	trampolineCfg := subServerCgs.RouterRPC.TrampolineConfig
 
 	routerBackend := &routerrpc.RouterBackend{
 		SelfNode: selfNode.PubKeyBytes,
@@ -729,6 +789,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return info.NodeKey1Bytes, info.NodeKey2Bytes, nil
 		},
 		FindRoute:              s.chanRouter.FindRoute,
+		FindRoutes:             s.chanRouter.FindRoutes,
 		MissionControl:         s.defaultMC,
 		ActiveNetParams:        r.cfg.ActiveNetParams.Params,
 		Tower:                  s.controlTower,
@@ -744,6 +805,14 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		},
 		SetChannelAuto:     s.chanStatusMgr.RequestAuto,
 		UseStatusInitiated: subServerCgs.RouterRPC.UseStatusInitiated,
+		TrampolineBudget:   trampolineCfg.Budget(),
+		BestHeight: func() (uint32, error) {
+			_, height, err := s.cc.ChainIO.GetBestBlock()
+			return uint32(height), err
+		},
+		Reputation:         s.reputationMgr,
+		ExternalPathFinder: s.externalPathFinder,
+		Rebalancer:         s.rebalancer,
 		ParseCustomChannelData: func(msg proto.Message) error {
 			err = fn.MapOptionZ(
 				r.server.implCfg.AuxDataParser,
@@ -758,6 +827,26 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 
 			return nil
 		},
+		FetchMissionControl: func(ns string) (routerrpc.MissionControl,
+			error) {
+
+			mc, err := s.missionController.FetchNamespacedStore(ns)
+			if err != nil {
+				return nil, err
+			}
+
+			return mc, nil
+		},
+		CreateMissionControlNamespace: func(ns string,
+			cfg *routing.MissionControlConfig) error {
+
+			_, err := s.missionController.CreateNamespace(ns, cfg)
+			return err
+		},
+		DeleteMissionControlNamespace: s.missionController.
+			DeleteNamespace,
+		ListMissionControlNamespaces: s.missionController.
+			ListNamespaces,
 	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
@@ -2335,6 +2424,22 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 
 		*channelType = lnwire.ChannelType(*fv)
 
+	case lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS:
+		channelType = new(lnwire.ChannelType)
+		fv := lnwire.NewRawFeatureVector(
+			lnwire.ZeroFeeCommitmentsRequired,
+		)
+
+		if in.ZeroConf {
+			fv.Set(lnwire.ZeroConfRequired)
+		}
+
+		if in.ScidAlias {
+			fv.Set(lnwire.ScidAliasRequired)
+		}
+
+		*channelType = lnwire.ChannelType(*fv)
+
 	default:
 		return nil, fmt.Errorf("unhandled request channel type %v",
 			in.CommitmentType)
@@ -2766,8 +2871,10 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	} else {
 		// If this is a frozen channel, then we only allow the co-op
 		// close to proceed if we were the responder to this channel if
-		// the absolute thaw height has not been met.
-		if channel.IsInitiator {
+		// the absolute thaw height has not been met. If the lease of a
+		// leased channel was sold by its acceptor, the acceptor is
+		// restricted instead.
+		if channel.IsInitiator != channel.ChanType.HasAcceptorLease() {
 			absoluteThawHeight, err := channel.AbsoluteThawHeight()
 			if err != nil {
 				return err
@@ -2924,6 +3031,259 @@ out:
 	return nil
 }
 
+// parseSpliceChanPoint parses the channel point of a splice request.
+func parseSpliceChanPoint(chanPoint *lnrpc.ChannelPoint) (wire.OutPoint,
+	error) {
+
+	if chanPoint == nil {
+		return wire.OutPoint{}, fmt.Errorf("channel_point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	return wire.OutPoint{
+		Hash:  *txid,
+		Index: chanPoint.OutputIndex,
+	}, nil
+}
+
+// SpliceIn adds funds to an active channel without closing it. The funds are
+// either taken from the on-chain wallet or from an externally funded PSBT.
+func (r *rpcServer) SpliceIn(in *lnrpc.SpliceInRequest,
+	updateStream lnrpc.Lightning_SpliceInServer) error {
+
+	if err := r.canOpenChannel(); err != nil {
+		return err
+	}
+
+	chanPoint, err := parseSpliceChanPoint(in.ChannelPoint)
+	if err != nil {
+		return err
+	}
+
+	if in.LocalAmt <= 0 {
+		return fmt.Errorf("local_amt must be positive")
+	}
+	localAmt := btcutil.Amount(in.LocalAmt)
+
+	minConfs, err := lnrpc.ExtractMinConfs(in.MinConfs, in.SpendUnconfirmed)
+	if err != nil {
+		return err
+	}
+
+	req := &funding.SpliceReq{
+		ChanPoint:   chanPoint,
+		SpliceInAmt: localAmt,
+		MinConfs:    minConfs,
+		Updates:     make(chan *lnrpc.SpliceUpdate, 2),
+		Err:         make(chan error, 1),
+	}
+
+	// If we have a PSBT shim, the splice transaction will be funded
+	// outside of the wallet. Otherwise, the wallet funds it at the
+	// requested fee rate.
+	if psbtShim := in.PsbtShim; psbtShim != nil {
+		if len(psbtShim.PendingChanId) != 32 {
+			return fmt.Errorf("pending chan ID not set")
+		}
+		if in.SatPerVbyte != 0 || in.TargetConf != 0 {
+			return fmt.Errorf("specifying fee estimation " +
+				"parameters is not supported for PSBT funding")
+		}
+
+		var packet *psbt.Packet
+		if len(psbtShim.BasePsbt) > 0 {
+			packet, err = psbt.NewFromRawBytes(
+				bytes.NewReader(psbtShim.BasePsbt), false,
+			)
+			if err != nil {
+				return fmt.Errorf("error parsing base PSBT: %w",
+					err)
+			}
+		}
+
+		copy(req.PendingChanID[:], psbtShim.PendingChanId)
+		req.ChanFunder = chanfunding.NewPsbtAssembler(
+			localAmt, packet, &r.server.cc.Wallet.Cfg.NetParams,
+			!psbtShim.NoPublish,
+		)
+	} else {
+		targetConf := maybeUseDefaultConf(
+			0, in.SatPerVbyte, uint32(in.TargetConf),
+		)
+		req.FeeRate, err = lnrpc.CalculateFeeRate(
+			0, in.SatPerVbyte, targetConf, r.server.cc.FeeEstimator,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	rpcsLog.Infof("[splicein] ChannelPoint(%v), amt=%v, sat/kw=%v, "+
+		"min_confs=%v, psbt=%v", chanPoint, localAmt,
+		int64(req.FeeRate), minConfs, in.PsbtShim != nil)
+
+	r.server.fundingMgr.SpliceChannel(req)
+
+	for {
+		select {
+		case err := <-req.Err:
+			return err
+
+		case update := <-req.Updates:
+			rpcsLog.Tracef("[splicein] sending update: %v", update)
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+
+			// Once the splice is pending, there are no further
+			// updates.
+			if update.GetSplicePending() != nil {
+				return nil
+			}
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// SpliceOut moves funds out of an active channel to an on-chain address
+// without closing it.
+func (r *rpcServer) SpliceOut(ctx context.Context,
+	in *lnrpc.SpliceOutRequest) (*lnrpc.SpliceOutResponse, error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	chanPoint, err := parseSpliceChanPoint(in.ChannelPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	params := r.cfg.ActiveNetParams.Params
+	addr, err := btcutil.DecodeAddress(in.Addr, params)
+	if err != nil {
+		return nil, err
+	}
+	if !addr.IsForNet(params) {
+		return nil, fmt.Errorf("address: %v is not valid for this "+
+			"network: %v", addr.String(), params.Name)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	targetConf := maybeUseDefaultConf(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+	)
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, targetConf, r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[spliceout] ChannelPoint(%v), addr=%v, amt=%v, "+
+		"sat/kw=%v", chanPoint, in.Addr, btcutil.Amount(in.Amount),
+		int64(feeRate))
+
+	req := &funding.SpliceReq{
+		ChanPoint:       chanPoint,
+		SpliceOutAmt:    btcutil.Amount(in.Amount),
+		SpliceOutScript: script,
+		FeeRate:         feeRate,
+		Updates:         make(chan *lnrpc.SpliceUpdate, 1),
+		Err:             make(chan error, 1),
+	}
+	r.server.fundingMgr.SpliceChannel(req)
+
+	select {
+	case err := <-req.Err:
+		return nil, err
+
+	case update := <-req.Updates:
+		pending := update.GetSplicePending()
+		if pending == nil {
+			return nil, fmt.Errorf("unexpected splice update: %v",
+				update)
+		}
+
+		txid, err := chainhash.NewHash(pending.Txid)
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.SpliceOutResponse{
+			Txid: txid.String(),
+		}, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, errors.New("rpc server shutting down")
+	}
+}
+
+// BumpCloseFee replaces the cooperative close transaction of a channel with
+// one paying a higher fee rate. The channel must be closing with the simple
+// close protocol.
+func (r *rpcServer) BumpCloseFee(_ context.Context,
+	in *lnrpc.BumpCloseFeeRequest) (*lnrpc.BumpCloseFeeResponse, error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	chanPoint, err := parseSpliceChanPoint(in.ChannelPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	targetConf := maybeUseDefaultConf(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+	)
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, targetConf, r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[bumpclosefee] ChannelPoint(%v), sat/kw=%v", chanPoint,
+		int64(feeRate))
+
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find peer of channel: %w",
+			err)
+	}
+
+	closingTxid, err := peer.BumpCloseFee(chanPoint, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BumpCloseFeeResponse{
+		ClosingTxid: closingTxid.String(),
+	}, nil
+}
+
 func createRPCCloseUpdate(
 	update interface{}) (*lnrpc.CloseStatusUpdate, error) {
 
@@ -3139,7 +3499,8 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 		//
 		// TODO(guggero): Properly store the funding type (wallet, shim,
 		// PSBT) on the channel so we don't need to use the thaw height.
-		isShimFunded := dbChan.ThawHeight > 0
+		isShimFunded := dbChan.ThawHeight > 0 &&
+			!dbChan.ChanType.HasAcceptorLease()
 		isPendingShimFunded := isShimFunded && dbChan.IsPending
 		if !in.IKnowWhatIAmDoing && in.PendingFundingShimOnly &&
 			!isPendingShimFunded {
@@ -4570,6 +4931,9 @@ func rpcCommitmentType(chanType channeldb.ChannelType) lnrpc.CommitmentType {
 	case chanType.HasLeaseExpiration():
 		return lnrpc.CommitmentType_SCRIPT_ENFORCED_LEASE
 
+	case chanType.HasZeroFeeCommitments():
+		return lnrpc.CommitmentType_ZERO_FEE_COMMITMENTS
+
 	case chanType.HasAnchors():
 		return lnrpc.CommitmentType_ANCHORS
 
@@ -6019,9 +6383,8 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
 
 	var (
-		defaultDelta = r.cfg.Bitcoin.TimeLockDelta
-		blindCfg     = invoice.BlindedPathConfig
-		blind        = invoice.IsBlinded
+		blindCfg = invoice.BlindedPathConfig
+		blind    = invoice.IsBlinded
 	)
 
 	globalBlindCfg := r.server.cfg.Routing.BlindedPaths
@@ -6069,51 +6432,9 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 			"be included in each path")
 	}
 
-	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
-		AddInvoice:        r.server.invoices.AddInvoice,
-		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
-		ChainParams:       r.cfg.ActiveNetParams.Params,
-		NodeSigner:        r.server.nodeSigner,
-		DefaultCLTVExpiry: defaultDelta,
-		ChanDB:            r.server.chanStateDB,
-		Graph:             r.server.graphDB,
-		GenInvoiceFeatures: func() *lnwire.FeatureVector {
-			v := r.server.featureMgr.Get(feature.SetInvoice)
-
-			if blind {
-				// If an invoice includes blinded paths, then a
-				// payment address is not required since we use
-				// the PathID in the final hop's encrypted data
-				// as equivalent to the payment address
-				v.Unset(lnwire.PaymentAddrRequired)
-				v.Set(lnwire.PaymentAddrOptional)
-
-				// The invoice payer will also need to
-				// understand the new BOLT 11 tagged field
-				// containing the blinded path, so we switch
-				// the bit to required.
-				v = feature.SetBit(
-					v, lnwire.Bolt11BlindedPathsRequired,
-				)
-			}
-
-			return v
-		},
-		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
-		},
-		GetAlias:   r.server.aliasMgr.GetPeerAlias,
-		BestHeight: r.server.cc.BestBlockTracker.BestHeight,
-		QueryBlindedRoutes: func(amt lnwire.MilliSatoshi) (
-			[]*route.Route, error) {
-
-			return r.server.chanRouter.FindBlindedPaths(
-				r.selfNode, amt,
-				r.server.defaultMC.GetProbability,
-				blindingRestrictions,
-			)
-		},
-	}
+	addInvoiceCfg := r.server.newAddInvoiceConfig(
+		blind, blindingRestrictions,
+	)
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
@@ -6128,28 +6449,9 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 
 	var blindedPathCfg *invoicesrpc.BlindedPathConfig
 	if blind {
-		bpConfig := r.server.cfg.Routing.BlindedPaths
-
-		blindedPathCfg = &invoicesrpc.BlindedPathConfig{
-			RoutePolicyIncrMultiplier: bpConfig.
-				PolicyIncreaseMultiplier,
-			RoutePolicyDecrMultiplier: bpConfig.
-				PolicyDecreaseMultiplier,
-			DefaultDummyHopPolicy: &blindedpath.BlindedHopPolicy{
-				CLTVExpiryDelta: uint16(defaultDelta),
-				FeeRate: uint32(
-					r.server.cfg.Bitcoin.FeeRate,
-				),
-				BaseFee:     r.server.cfg.Bitcoin.BaseFee,
-				MinHTLCMsat: r.server.cfg.Bitcoin.MinHTLCIn,
-
-				// MaxHTLCMsat will be calculated on the fly by
-				// using the introduction node's channel's
-				// capacities.
-				MaxHTLCMsat: 0,
-			},
-			MinNumPathHops: blindingRestrictions.NumHops,
-		}
+		blindedPathCfg = r.server.newBlindedPathConfig(
+			blindingRestrictions.NumHops,
+		)
 	}
 
 	addInvoiceData := &invoicesrpc.AddInvoiceData{
@@ -7772,32 +8074,135 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	}, nil
 }
 
-// ForwardingHistory allows the caller to query the htlcswitch for a record of
-// all HTLC's forwarded within the target time range, and integer offset within
-// that time range. If no time-range is specified, then the first chunk of the
-// past 24 hrs of forwarding history are returned.
-
-// A list of forwarding events are returned. The size of each forwarding event
-// is 40 bytes, and the max message size able to be returned in gRPC is 4 MiB.
-// In order to safely stay under this max limit, we'll return 50k events per
-// response.  Each response has the index offset of the last entry. The index
-// offset can be provided to the request to allow the caller to skip a series
-// of records.
-func (r *rpcServer) ForwardingHistory(ctx context.Context,
-	req *lnrpc.ForwardingHistoryRequest) (*lnrpc.ForwardingHistoryResponse,
-	error) {
+// UpdateChannelParams allows the caller to renegotiate the parameters of an
+// active channel with the remote peer. The call blocks until the negotiation
+// has either completed or been rejected by the remote peer.
+func (r *rpcServer) UpdateChannelParams(ctx context.Context,
+	req *lnrpc.UpdateChannelParamsRequest) (
+	*lnrpc.UpdateChannelParamsResponse, error) {
 
-	// Before we perform the queries below, we'll instruct the switch to
-	// flush any pending events to disk. This ensure we get a complete
-	// snapshot at this particular time.
-	if err := r.server.htlcSwitch.FlushForwardingEvents(); err != nil {
-		return nil, fmt.Errorf("unable to flush forwarding "+
-			"events: %v", err)
+	if req.ChanPoint == nil {
+		return nil, fmt.Errorf("chan_point must be set")
 	}
 
-	var (
-		startTime, endTime time.Time
-
+	txid, err := lnrpc.GetChanPointFundingTxid(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.OutPoint{
+		Hash:  *txid,
+		Index: req.ChanPoint.OutputIndex,
+	}
+
+	var params htlcswitch.DynCommitParams
+	if req.RemoteMaxValueInFlightMsat != 0 {
+		params.MaxValueInFlight = fn.Some(
+			lnwire.MilliSatoshi(req.RemoteMaxValueInFlightMsat),
+		)
+	}
+	if req.RemoteChanReserveSat != 0 {
+		params.ChannelReserve = fn.Some(
+			btcutil.Amount(req.RemoteChanReserveSat),
+		)
+	}
+	if req.RemoteMaxHtlcs != 0 {
+		if req.RemoteMaxHtlcs > math.MaxUint16 {
+			return nil, fmt.Errorf("remote_max_htlcs must not "+
+				"exceed %v", math.MaxUint16)
+		}
+		params.MaxAcceptedHTLCs = fn.Some(uint16(req.RemoteMaxHtlcs))
+	}
+	if req.LocalDustLimitSat != 0 {
+		params.DustLimit = fn.Some(
+			btcutil.Amount(req.LocalDustLimitSat),
+		)
+	}
+	if req.RemoteCsvDelay != 0 {
+		if req.RemoteCsvDelay > math.MaxUint16 {
+			return nil, fmt.Errorf("remote_csv_delay must not "+
+				"exceed %v", math.MaxUint16)
+		}
+		params.CsvDelay = fn.Some(uint16(req.RemoteCsvDelay))
+	}
+	if req.UpgradeToAnchors {
+		channel, err := r.server.chanStateDB.FetchChannel(
+			nil, chanPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if channel.ChanType.HasAnchors() {
+			return nil, fmt.Errorf("channel %v already has anchor "+
+				"outputs", chanPoint)
+		}
+
+		params.ChannelType = fn.Some(
+			lnwallet.AnchorsUpgradeChanType(channel.ChanType),
+		)
+	}
+	if params.IsEmpty() {
+		return nil, htlcswitch.ErrNoDynCommitParams
+	}
+
+	// The negotiation is driven by the link, so the peer must be online
+	// for us to proceed.
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	link, err := r.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update params of channel "+
+			"%v: %w", chanPoint, err)
+	}
+
+	rpcsLog.Debugf("[updatechanparams] updating params of channel %v: "+
+		"max_value_in_flight=%v, chan_reserve=%v, max_htlcs=%v, "+
+		"dust_limit=%v, csv_delay=%v, upgrade_to_anchors=%v",
+		chanPoint, req.RemoteMaxValueInFlightMsat,
+		req.RemoteChanReserveSat, req.RemoteMaxHtlcs,
+		req.LocalDustLimitSat, req.RemoteCsvDelay,
+		req.UpgradeToAnchors)
+
+	select {
+	case err := <-link.ProposeDynCommit(params):
+		if err != nil {
+			return nil, err
+		}
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, errors.New("rpc server shutting down")
+	}
+
+	return &lnrpc.UpdateChannelParamsResponse{}, nil
+}
+
+// ForwardingHistory allows the caller to query the htlcswitch for a record of
+// all HTLC's forwarded within the target time range, and integer offset within
+// that time range. If no time-range is specified, then the first chunk of the
+// past 24 hrs of forwarding history are returned.
+
+// A list of forwarding events are returned. The size of each forwarding event
+// is 40 bytes, and the max message size able to be returned in gRPC is 4 MiB.
+// In order to safely stay under this max limit, we'll return 50k events per
+// response.  Each response has the index offset of the last entry. The index
+// offset can be provided to the request to allow the caller to skip a series
+// of records.
+func (r *rpcServer) ForwardingHistory(ctx context.Context,
+	req *lnrpc.ForwardingHistoryRequest) (*lnrpc.ForwardingHistoryResponse,
+	error) {
+
+	// Before we perform the queries below, we'll instruct the switch to
+	// flush any pending events to disk. This ensure we get a complete
+	// snapshot at this particular time.
+	if err := r.server.htlcSwitch.FlushForwardingEvents(); err != nil {
+		return nil, fmt.Errorf("unable to flush forwarding "+
+			"events: %v", err)
+	}
+
+	var (
+		startTime, endTime time.Time
+
 		numEvents uint32
 	)
 
@@ -8211,6 +8616,22 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 			return nil, fmt.Errorf("unable to unpack chan "+
 				"backup: %v", err)
 		}
+
+	case in.GetPeerStorage():
+		// The backups our peers returned to us were already unpacked
+		// when they were received, so we can restore them directly.
+		singles, err := r.server.peerStorage.RetrievedBackup()
+		if err != nil {
+			return nil, err
+		}
+
+		numRestored, err = chanbackup.Recover(
+			singles, chanRestorer, r.server,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to restore backup from "+
+				"peer storage: %v", err)
+		}
 	}
 
 	return &lnrpc.RestoreBackupResponse{
@@ -8877,6 +9298,486 @@ func (r *rpcServer) SubscribeCustomMessages(
 	}
 }
 
+// SendOnionMessage sends an onion message to a node, either directly or along
+// a blinded path.
+func (r *rpcServer) SendOnionMessage(_ context.Context,
+	req *lnrpc.SendOnionMessageRequest) (*lnrpc.SendOnionMessageResponse,
+	error) {
+
+	messenger := r.server.onionMessenger
+	if messenger == nil {
+		return nil, status.Error(
+			codes.Unimplemented, ErrOnionMessagesDisabled.Error(),
+		)
+	}
+
+	sendReq := &onionmessage.SendRequest{
+		CustomRecords: req.CustomRecords,
+	}
+
+	var err error
+	switch {
+	case len(req.Destination) != 0 && req.BlindedPath != nil:
+		return nil, fmt.Errorf("destination and blinded path can't " +
+			"both be set")
+
+	case len(req.Destination) != 0:
+		dest, err := route.NewVertexFromBytes(req.Destination)
+		if err != nil {
+			return nil, err
+		}
+		sendReq.Destination = &dest
+
+	case req.BlindedPath != nil:
+		sendReq.BlindedPath, err = unmarshalOnionMessagePath(
+			req.BlindedPath,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("destination or blinded path required")
+	}
+
+	sendReq.Hops, err = unmarshalVertices(req.Hops)
+	if err != nil {
+		return nil, err
+	}
+
+	for typ := range req.CustomRecords {
+		if typ < onionmessage.MinCustomRecordType {
+			return nil, fmt.Errorf("custom record type %d below "+
+				"minimum of %d", typ,
+				onionmessage.MinCustomRecordType)
+		}
+	}
+
+	// The reply path passes through the given hops and ends at our node.
+	if req.IncludeReplyPath {
+		hops, err := unmarshalVertices(req.ReplyPathHops)
+		if err != nil {
+			return nil, err
+		}
+		hops = append(
+			hops, route.NewVertex(r.server.identityECDH.PubKey()),
+		)
+
+		sendReq.ReplyPath, err = blindedpath.BuildOnionMessagePath(
+			&blindedpath.OnionMessagePathCfg{
+				Hops:   hops,
+				PathID: req.ReplyPathId,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build reply path: "+
+				"%w", err)
+		}
+	}
+
+	if err := messenger.Send(sendReq); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendOnionMessageResponse{}, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+// that we are the final recipient of.
+func (r *rpcServer) SubscribeOnionMessages(
+	_ *lnrpc.SubscribeOnionMessagesRequest,
+	server lnrpc.Lightning_SubscribeOnionMessagesServer) error {
+
+	messenger := r.server.onionMessenger
+	if messenger == nil {
+		return status.Error(
+			codes.Unimplemented, ErrOnionMessagesDisabled.Error(),
+		)
+	}
+
+	client, err := messenger.SubscribeMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			msg := update.(*onionmessage.ReceivedMessage)
+
+			replyPath := marshalOnionMessagePath(msg.ReplyPath)
+			err := server.Send(&lnrpc.OnionMessageUpdate{
+				Peer:          msg.Peer[:],
+				PathId:        msg.PathID,
+				ReplyPath:     replyPath,
+				CustomRecords: msg.CustomRecords,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// unmarshalVertices parses a list of serialized node public keys.
+func unmarshalVertices(keys [][]byte) ([]route.Vertex, error) {
+	vertices := make([]route.Vertex, 0, len(keys))
+	for _, key := range keys {
+		vertex, err := route.NewVertexFromBytes(key)
+		if err != nil {
+			return nil, err
+		}
+
+		vertices = append(vertices, vertex)
+	}
+
+	return vertices, nil
+}
+
+// unmarshalOnionMessagePath converts an RPC blinded path to the path an onion
+// message is sent along.
+func unmarshalOnionMessagePath(rpcPath *lnrpc.BlindedPath) (
+	*sphinx.BlindedPath, error) {
+
+	introduction, err := btcec.ParsePubKey(rpcPath.IntroductionNode)
+	if err != nil {
+		return nil, err
+	}
+
+	blinding, err := btcec.ParsePubKey(rpcPath.BlindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rpcPath.BlindedHops) == 0 {
+		return nil, errors.New("blinded path has no hops")
+	}
+
+	path := &sphinx.BlindedPath{
+		IntroductionPoint: introduction,
+		BlindingPoint:     blinding,
+		BlindedHops: make(
+			[]*sphinx.BlindedHopInfo, len(rpcPath.BlindedHops),
+		),
+	}
+	for i, hop := range rpcPath.BlindedHops {
+		pubKey, err := btcec.ParsePubKey(hop.BlindedNode)
+		if err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &sphinx.BlindedHopInfo{
+			BlindedNodePub: pubKey,
+			CipherText:     hop.EncryptedData,
+		}
+	}
+
+	return path, nil
+}
+
+// marshalOnionMessagePath converts the blinded path of an onion message to its
+// RPC representation. A nil path is returned as nil.
+func marshalOnionMessagePath(path *sphinx.BlindedPath) *lnrpc.BlindedPath {
+	if path == nil {
+		return nil
+	}
+
+	hops := make([]*lnrpc.BlindedHop, len(path.BlindedHops))
+	for i, hop := range path.BlindedHops {
+		hops[i] = &lnrpc.BlindedHop{
+			BlindedNode:   hop.BlindedNodePub.SerializeCompressed(),
+			EncryptedData: hop.CipherText,
+		}
+	}
+
+	return &lnrpc.BlindedPath{
+		IntroductionNode: path.IntroductionPoint.SerializeCompressed(),
+		BlindingPoint:    path.BlindingPoint.SerializeCompressed(),
+		BlindedHops:      hops,
+	}
+}
+
+// AddOffer creates a new BOLT 12 offer issued by our node.
+func (r *rpcServer) AddOffer(_ context.Context,
+	req *lnrpc.AddOfferRequest) (*lnrpc.AddOfferResponse, error) {
+
+	manager := r.server.offerManager
+	if manager == nil {
+		return nil, status.Error(
+			codes.Unimplemented, ErrOnionMessagesDisabled.Error(),
+		)
+	}
+
+	offer := &offers.Offer{
+		Description: req.Description,
+		Amount:      req.AmountMsat,
+		Issuer:      req.Issuer,
+	}
+	if req.AllowQuantity {
+		offer.QuantityMax = fn.Some(req.QuantityMax)
+	} else if req.QuantityMax != 0 {
+		return nil, errors.New("quantity_max requires allow_quantity")
+	}
+	if req.AbsoluteExpiry != 0 {
+		offer.AbsoluteExpiry = time.Unix(req.AbsoluteExpiry, 0)
+	}
+
+	if err := manager.AddOffer(offer); err != nil {
+		return nil, err
+	}
+
+	encoded, err := offer.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	id := offer.ID()
+
+	return &lnrpc.AddOfferResponse{
+		OfferId: id[:],
+		Offer:   encoded,
+	}, nil
+}
+
+// ListOffers returns the BOLT 12 offers issued by our node.
+func (r *rpcServer) ListOffers(_ context.Context,
+	_ *lnrpc.ListOffersRequest) (*lnrpc.ListOffersResponse, error) {
+
+	manager := r.server.offerManager
+	if manager == nil {
+		return nil, status.Error(
+			codes.Unimplemented, ErrOnionMessagesDisabled.Error(),
+		)
+	}
+
+	offerList, err := manager.ListOffers()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListOffersResponse{}
+	for _, offer := range offerList {
+		encoded, err := offer.Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		var expiry int64
+		if !offer.AbsoluteExpiry.IsZero() {
+			expiry = offer.AbsoluteExpiry.Unix()
+		}
+
+		id := offer.ID()
+		resp.Offers = append(resp.Offers, &lnrpc.Bolt12Offer{
+			OfferId:        id[:],
+			Offer:          encoded,
+			Description:    offer.Description,
+			AmountMsat:     offer.Amount,
+			Issuer:         offer.Issuer,
+			AbsoluteExpiry: expiry,
+		})
+	}
+
+	return resp, nil
+}
+
+// FetchInvoice requests a BOLT 12 invoice for an offer from its issuer.
+func (r *rpcServer) FetchInvoice(ctx context.Context,
+	req *lnrpc.FetchInvoiceRequest) (*lnrpc.FetchInvoiceResponse, error) {
+
+	manager := r.server.offerManager
+	if manager == nil {
+		return nil, status.Error(
+			codes.Unimplemented, ErrOnionMessagesDisabled.Error(),
+		)
+	}
+
+	offer, err := offers.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode offer: %w", err)
+	}
+
+	if req.TimeoutSeconds != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(
+			ctx, time.Duration(req.TimeoutSeconds)*time.Second,
+		)
+		defer cancel()
+	}
+
+	invoice, err := manager.FetchInvoice(ctx, &offers.FetchInvoiceRequest{
+		Offer:     offer,
+		Amount:    lnwire.MilliSatoshi(req.AmountMsat),
+		Quantity:  req.Quantity,
+		PayerNote: req.PayerNote,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := invoice.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.FetchInvoiceResponse{
+		Invoice:     encoded,
+		PaymentHash: invoice.PaymentHash[:],
+		AmountMsat:  uint64(invoice.Amount),
+		ExpiresAt:   invoice.ExpiresAt().Unix(),
+	}, nil
+}
+
+// leaseFeeRate returns the funding fee rate used to compute lease fees, which
+// is estimated for the default confirmation target unless set explicitly.
+func (r *rpcServer) leaseFeeRate(
+	satPerVByte uint64) (chainfee.SatPerKWeight, error) {
+
+	targetConf := maybeUseDefaultConf(0, satPerVByte, 0)
+
+	return lnrpc.CalculateFeeRate(
+		0, satPerVByte, targetConf, r.server.cc.FeeEstimator,
+	)
+}
+
+// marshalLeaseRates converts lease rates into their RPC representation.
+func marshalLeaseRates(rates *lnwire.LeaseRates) *lnrpc.LeaseRates {
+	return &lnrpc.LeaseRates{
+		FundingWeight:         uint32(rates.FundingWeight),
+		LeaseFeeBasis:         uint32(rates.LeaseFeeBasis),
+		LeaseFeeBaseSat:       int64(rates.LeaseFeeBase),
+		ChannelFeeMaxBaseMsat: uint64(rates.ChannelFeeMaxBase),
+		ChannelFeeMaxPpm:      rates.ChannelFeeMaxPPM(),
+	}
+}
+
+// ListLiquidityAds returns the nodes of the graph advertising lease rates.
+func (r *rpcServer) ListLiquidityAds(_ context.Context,
+	req *lnrpc.ListLiquidityAdsRequest) (*lnrpc.ListLiquidityAdsResponse,
+	error) {
+
+	if req.AmountSat < 0 {
+		return nil, fmt.Errorf("amount must not be negative")
+	}
+	amt := btcutil.Amount(req.AmountSat)
+
+	feeRate, err := r.leaseFeeRate(req.SatPerVbyte)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListLiquidityAdsResponse{}
+	err = r.server.graphDB.ForEachNode(func(_ kvdb.RTx,
+		node *channeldb.LightningNode) error {
+
+		rates, err := lnwire.ExtractLeaseRates(node.ExtraOpaqueData)
+		if err != nil {
+			rpcsLog.Debugf("Invalid lease rates of node %x: %v",
+				node.PubKeyBytes, err)
+
+			return nil
+		}
+		if rates == nil {
+			return nil
+		}
+
+		ad := &lnrpc.LiquidityAd{
+			PubKey: hex.EncodeToString(node.PubKeyBytes[:]),
+			Alias:  node.Alias,
+			Rates:  marshalLeaseRates(rates),
+		}
+		if amt != 0 {
+			ad.LeaseFeeSat = int64(rates.LeaseFee(amt, feeRate))
+		}
+		resp.Ads = append(resp.Ads, ad)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// PurchaseLease buys inbound liquidity from a peer advertising lease rates,
+// and streams the status of the leased channel.
+func (r *rpcServer) PurchaseLease(in *lnrpc.PurchaseLeaseRequest,
+	updateStream lnrpc.Lightning_PurchaseLeaseServer) error {
+
+	if err := r.canOpenChannel(); err != nil {
+		return err
+	}
+
+	nodePubKey, err := btcec.ParsePubKey(in.NodePubkey)
+	if err != nil {
+		return err
+	}
+
+	if in.AmountSat <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+
+	feeRate, err := r.leaseFeeRate(in.SatPerVbyte)
+	if err != nil {
+		return err
+	}
+
+	// The lease is bought at the rates the peer currently advertises.
+	node, err := r.server.graphDB.FetchLightningNode(
+		route.NewVertex(nodePubKey),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch node %x: %w",
+			in.NodePubkey, err)
+	}
+	rates, err := lnwire.ExtractLeaseRates(node.ExtraOpaqueData)
+	if err != nil {
+		return err
+	}
+	if rates == nil {
+		return fmt.Errorf("node %x doesn't sell leases", in.NodePubkey)
+	}
+
+	updateChan, errChan := r.server.PurchaseLease(
+		nodePubKey, &funding.LeaseRequest{
+			Amount:          btcutil.Amount(in.AmountSat),
+			Rates:           *rates,
+			MaxFee:          btcutil.Amount(in.MaxFeeSat),
+			FundingFeePerKw: feeRate,
+		},
+	)
+
+	for {
+		select {
+		case err := <-errChan:
+			rpcsLog.Errorf("Unable to purchase lease from "+
+				"NodeKey(%x): %v", in.NodePubkey, err)
+			return err
+
+		case update := <-updateChan:
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+
+			// The stream ends once the leased channel is open.
+			if update.GetChanOpen() != nil {
+				return nil
+			}
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
 // ListAliases returns the set of all aliases we have ever allocated along with
 // their base SCIDs and possibly a separate confirmed SCID in the case of
 // zero-conf.
@@ -17,6 +17,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -55,7 +56,9 @@ import (
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
@@ -63,17 +66,24 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/nat"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/offers"
+	"github.com/lightningnetwork/lnd/onionmessage"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
+	"github.com/lightningnetwork/lnd/peerstorage"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/rebalance"
+	"github.com/lightningnetwork/lnd/reputation"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/routing/localchans"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/trampoline"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
@@ -123,6 +133,10 @@ var (
 	// gracefully exiting.
 	ErrServerShuttingDown = errors.New("server is shutting down")
 
+	// ErrOnionMessagesDisabled is returned when onion messages are used
+	// while the onion messages protocol option isn't set.
+	ErrOnionMessagesDisabled = errors.New("onion messages are disabled")
+
 	// MaxFundingAmount is a soft-limit of the maximum channel size
 	// currently accepted within the Lightning Protocol. This is
 	// defined in BOLT-0002, and serves as an initial precautionary limit
@@ -270,6 +284,31 @@ type server struct {
 
 	peerNotifier *peernotifier.PeerNotifier
 
+	// onionMessenger relays, sends and receives onion messages. It is nil
+	// if onion messages are disabled.
+	onionMessenger *onionmessage.Messenger
+
+	// offerManager issues invoices for our BOLT 12 offers and fetches
+	// invoices for the offers of others. It is nil if onion messages are
+	// disabled.
+	offerManager *offers.Manager
+
+	// peerStorage stores blobs on behalf of our peers, and hands our own
+	// encrypted channel backup to the peers that store blobs for us.
+	peerStorage *peerstorage.Manager
+
+	// trampolineForwarder forwards the trampoline payments we receive.
+	// It is nil if trampoline forwarding is disabled.
+	trampolineForwarder *trampoline.Forwarder
+
+	// prober periodically probes the liquidity of routes to keep mission
+	// control up to date. It is nil if the prober is disabled.
+	prober *routing.Prober
+
+	// rebalancer moves liquidity between our channels with circular
+	// payments.
+	rebalancer *rebalance.Rebalancer
+
 	htlcNotifier *htlcswitch.HtlcNotifier
 
 	witnessBeacon contractcourt.WitnessBeacon
@@ -279,6 +318,10 @@ type server struct {
 	missionController *routing.MissionController
 	defaultMC         *routing.MissionControl
 
+	// externalPathFinder allows an external process to supply
+	// probabilities or paths for our payments.
+	externalPathFinder *routing.ExternalPathFinder
+
 	graphBuilder *graph.Builder
 
 	chanRouter *routing.ChannelRouter
@@ -331,6 +374,11 @@ type server struct {
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
 
+	// reputationMgr tracks the reputation of the peers that forward htlcs
+	// to us, and protects the resources of our channels against jamming.
+	// It is nil if reputation tracking is disabled.
+	reputationMgr *reputation.Manager
+
 	hostAnn *netann.HostAnnouncer
 
 	// livenessMonitor monitors that lnd has access to critical resources.
@@ -557,6 +605,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			"aux controllers")
 	}
 
+	trampolineCfg := cfg.SubRPCServers.RouterRPC.TrampolineConfig
+
 	//nolint:lll
 	featureMgr, err := feature.NewManager(feature.Config{
 		NoTLVOnion:               cfg.ProtocolOptions.LegacyOnion(),
@@ -571,7 +621,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		CustomFeatures:           cfg.ProtocolOptions.CustomFeatures(),
 		NoTaprootChans:           !cfg.ProtocolOptions.TaprootChans,
 		NoTaprootOverlay:         !cfg.ProtocolOptions.TaprootOverlayChans,
+		NoZeroFeeCommitments:     !cfg.ProtocolOptions.ZeroFeeCommitments,
 		NoRouteBlinding:          cfg.ProtocolOptions.NoRouteBlinding(),
+		NoQuiescence:             cfg.ProtocolOptions.NoQuiescence(),
+		NoSplice:                 !cfg.ProtocolOptions.Splice,
+		NoDualFund:               !cfg.ProtocolOptions.DualFund,
+		NoOnionMessages:          !cfg.ProtocolOptions.OnionMessages,
+		NoPeerStorage:            !cfg.PeerStorage.Provide,
+		NoTrampoline:             !trampolineCfg.Forward,
+		NoRbfCoopClose:           !cfg.ProtocolOptions.RbfCoopClose,
 	})
 	if err != nil {
 		return nil, err
@@ -680,6 +738,29 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	// The switch consults the reputation manager before forwarding htlcs,
+	// if reputation tracking is enabled.
+	var resourceMgr htlcswitch.ResourceManager
+	if reputationCfg := cfg.Htlcswitch.Reputation; reputationCfg.Enable {
+		s.reputationMgr, err = reputation.NewManager(&reputation.Config{
+			RevenueWindow:        reputationCfg.RevenueWindow,
+			ReputationMultiplier: reputationCfg.Multiplier,
+			ResolutionPeriod:     reputationCfg.ResolutionPeriod,
+			ProtectedPercentage:  reputationCfg.ProtectedPercent,
+			SubscribeHtlcEvents: func() (subscribe.Subscription,
+				error) {
+
+				return s.htlcNotifier.SubscribeHtlcEvents()
+			},
+			Clock: clock.NewDefaultClock(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resourceMgr = s.reputationMgr
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB:                   dbs.ChanStateDB,
 		FetchAllOpenChannels: s.chanStateDB.FetchAllOpenChannels,
@@ -715,6 +796,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MaxFeeExposure:         thresholdMSats,
 		SignAliasUpdate:        s.signAliasUpdate,
 		IsAlias:                aliasmgr.IsAlias,
+		ResourceManager:        resourceMgr,
 	}, uint32(currentHeight))
 	if err != nil {
 		return nil, err
@@ -862,6 +944,21 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, err
 	}
+
+	// If we sell channel leases, we'll advertise our lease rates in our
+	// node announcement.
+	var (
+		leaseRates    = fn.None[lnwire.LeaseRates]()
+		nodeExtraData lnwire.ExtraOpaqueData
+	)
+	if cfg.LiquidityAds.Active {
+		rates := cfg.LiquidityAds.LeaseRates()
+		if err := nodeExtraData.PackRecords(&rates); err != nil {
+			return nil, err
+		}
+		leaseRates = fn.Some(rates)
+	}
+
 	selfNode := &channeldb.LightningNode{
 		HaveNodeAnnouncement: true,
 		LastUpdate:           time.Now(),
@@ -869,6 +966,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Alias:                nodeAlias.String(),
 		Features:             s.featureMgr.Get(feature.SetNodeAnn),
 		Color:                color,
+		ExtraOpaqueData:      nodeExtraData,
 	}
 	copy(selfNode.PubKeyBytes[:], nodeKeyDesc.PubKey.SerializeCompressed())
 
@@ -997,20 +1095,25 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		),
 		AttemptCostPPM: routingConfig.AttemptCostPPM,
 		MinProbability: routingConfig.MinRouteProbability,
+		LatencyCost: lnwire.NewMSatFromSatoshis(
+			routingConfig.LatencyCost,
+		),
 	}
 
 	sourceNode, err := chanGraph.SourceNode()
 	if err != nil {
 		return nil, fmt.Errorf("error getting source node: %w", err)
 	}
+	s.externalPathFinder = routing.NewExternalPathFinder()
 	paymentSessionSource := &routing.SessionSource{
 		GraphSessionFactory: graphsession.NewGraphSessionFactory(
 			chanGraph,
 		),
-		SourceNode:        sourceNode,
-		MissionControl:    s.defaultMC,
-		GetLink:           s.htlcSwitch.GetLinkByShortID,
-		PathFindingConfig: pathFindingConfig,
+		SourceNode:         sourceNode,
+		MissionControl:     s.defaultMC,
+		GetLink:            s.htlcSwitch.GetLinkByShortID,
+		PathFindingConfig:  pathFindingConfig,
+		ExternalPathFinder: s.externalPathFinder,
 	}
 
 	paymentControl := channeldb.NewPaymentControl(dbs.ChanStateDB)
@@ -1032,6 +1135,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		AssumeChannelValid:  cfg.Routing.AssumeChannelValid,
 		StrictZombiePruning: strictPruning,
 		IsAlias:             aliasmgr.IsAlias,
+		IsLocalSplice:       s.isLocalSplice,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create graph builder: %w", err)
@@ -1052,11 +1156,53 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ApplyChannelUpdate: s.graphBuilder.ApplyChannelUpdate,
 		ClosedSCIDs:        s.fetchClosedChannelSCIDs(),
 		TrafficShaper:      implCfg.TrafficShaper,
+		NamespacedMissionControl: func(ns string) (
+			routing.MissionControlQuerier, error) {
+
+			mc, err := s.missionController.FetchNamespacedStore(ns)
+			if err != nil {
+				return nil, err
+			}
+
+			return mc, nil
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %w", err)
 	}
 
+	if trampolineCfg.Forward {
+		//nolint:lll
+		s.trampolineForwarder = trampoline.NewForwarder(
+			&trampoline.ForwarderConfig{
+				NodeKey:          nodeKeyECDH,
+				Budget:           trampolineCfg.Budget(),
+				MinCltvDelta:     uint16(cfg.Bitcoin.TimeLockDelta),
+				MppTimeout:       trampoline.DefaultMppTimeout,
+				PaymentTimeout:   trampoline.DefaultPaymentTimeout,
+				MaxParts:         routerrpc.DefaultMaxParts,
+				SendPayment:      s.chanRouter.SendPayment,
+				SubscribePayment: s.controlTower.SubscribePayment,
+			},
+		)
+	}
+
+	if proberCfg := routingConfig.ProberConfig; proberCfg.Enable {
+		s.prober, err = s.newProber(
+			proberCfg, sequencer.NextID, dbs.ChanStateDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't create prober: %w", err)
+		}
+	}
+
+	s.rebalancer, err = s.newRebalancer(
+		routingConfig.RebalancerConfig, dbs.ChanStateDB,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't create rebalancer: %w", err)
+	}
+
 	chanSeries := discovery.NewChanSeries(s.graphDB)
 	gossipMessageStore, err := discovery.NewMessageStore(dbs.ChanStateDB)
 	if err != nil {
@@ -1305,7 +1451,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		},
 		Sweeper:                       s.sweeper,
 		Registry:                      s.invoices,
-		NotifyClosedChannel:           s.channelNotifier.NotifyClosedChannelEvent,
+		NotifyClosedChannel:           s.notifyClosedChannel,
 		NotifyFullyResolvedChannel:    s.channelNotifier.NotifyFullyResolvedChannelEvent,
 		OnionProcessor:                s.sphinx,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
@@ -1408,6 +1554,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			devCfg, reservationTimeout, zombieSweeperInterval)
 	}
 
+	// For dual-funded channels opened by remote peers, we match the
+	// opener's contribution up to the configured maximum.
+	dualFundPolicy := chanacceptor.MatchFundingPolicy(
+		btcutil.Amount(cfg.DualFundMaxContribution),
+	)
+
 	//nolint:lll
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		Dev:                devCfg,
@@ -1530,6 +1682,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			// the chain arb so it can react to on-chain events.
 			return s.chainArb.WatchNewChannel(channel)
 		},
+		StopWatchingChannel: s.chainArb.StopWatchingChannel,
 		ReportShortChanID: func(chanPoint wire.OutPoint) error {
 			cid := lnwire.NewChanIDFromOutPoint(chanPoint)
 			return s.htlcSwitch.UpdateShortChanID(cid)
@@ -1574,13 +1727,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
 		NotifyOpenChannelEvent:        s.channelNotifier.NotifyOpenChannelEvent,
 		OpenChannelPredicate:          chanPredicate,
+		DualFundContribution:          dualFundPolicy,
+		LeaseRates:                    leaseRates,
+		MaxLeaseAmt:                   btcutil.Amount(cfg.LiquidityAds.MaxLeaseAmt),
 		NotifyPendingOpenChannelEvent: s.channelNotifier.NotifyPendingOpenChannelEvent,
 		EnableUpfrontShutdown:         cfg.EnableUpfrontShutdown,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		DeleteAliasEdge:      deleteAliasEdge,
+		DeleteSplicedEdge:    s.deleteSplicedEdge,
 		AliasManager:         s.aliasMgr,
 		IsSweeperOutpoint:    s.sweeper.IsSweeperOutpoint,
+		InitSplice:           s.initSplice,
 		AuxFundingController: implCfg.AuxFundingController,
 		AuxSigner:            implCfg.AuxSigner,
 		AuxResolver:          implCfg.AuxContractResolver,
@@ -1602,8 +1760,28 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, err
 	}
+
+	peerStorageStore, err := peerstorage.NewStore(dbs.ChanStateDB)
+	if err != nil {
+		srvrLog.Errorf("unable to create peer storage store: %v", err)
+		return nil, err
+	}
+	s.peerStorage = peerstorage.NewManager(&peerstorage.ManagerConfig{
+		Store:              peerStorageStore,
+		Provide:            cfg.PeerStorage.Provide,
+		MaxBlobSize:        cfg.PeerStorage.MaxBlobSize,
+		MaxTotalSize:       cfg.PeerStorage.MaxTotalSize,
+		HasChannel:         s.hasOpenChannel,
+		UploadBackup:       cfg.PeerStorage.UploadBackup,
+		SendToStoragePeers: s.sendToStoragePeers,
+		KeyRing:            s.cc.KeyRing,
+	})
+
+	// Each update of our channel backup is also handed to the peers that
+	// store it on our behalf.
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		startingChans, chanNotifier, s.cc.KeyRing,
+		peerstorage.NewBackupSwapper(backupFile, s.peerStorage),
 	)
 	if err != nil {
 		return nil, err
@@ -1613,6 +1791,38 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// to peer online and offline events.
 	s.peerNotifier = peernotifier.New()
 
+	if cfg.ProtocolOptions.OnionMessages {
+		s.onionMessenger = onionmessage.NewMessenger(
+			&onionmessage.Config{
+				NodeKey:    nodeKeyECDH,
+				SendToPeer: s.sendOnionMessage,
+			},
+		)
+
+		offerStore, err := offers.NewStore(dbs.ChanStateDB)
+		if err != nil {
+			srvrLog.Errorf("unable to create offer store: %v", err)
+			return nil, err
+		}
+
+		s.offerManager = offers.NewManager(&offers.ManagerConfig{
+			Store:     offerStore,
+			Messenger: s.onionMessenger,
+			NodeKey:   nodeKeyDesc.PubKey,
+			SignMessage: func(tag, msg []byte) (*schnorr.Signature,
+				error) {
+
+				return cc.KeyRing.SignMessageSchnorr(
+					nodeKeyDesc.KeyLocator, msg, false,
+					nil, tag,
+				)
+			},
+			ChainHash:     *cfg.ActiveNetParams.GenesisHash,
+			CreateInvoice: s.createOfferInvoice,
+			Clock:         clock.NewDefaultClock(),
+		})
+	}
+
 	// Create a channel event store which monitors all open channels.
 	s.chanEventStore = chanfitness.NewChannelEventStore(&chanfitness.Config{
 		SubscribeChannelEvents: func() (subscribe.Subscription, error) {
@@ -2118,6 +2328,20 @@ func (s *server) Start() error {
 			return
 		}
 
+		if s.onionMessenger != nil {
+			cleanup = cleanup.add(s.onionMessenger.Stop)
+			if err := s.onionMessenger.Start(); err != nil {
+				startErr = err
+				return
+			}
+
+			cleanup = cleanup.add(s.offerManager.Stop)
+			if err := s.offerManager.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup = cleanup.add(s.htlcNotifier.Stop)
 		if err := s.htlcNotifier.Start(); err != nil {
 			startErr = err
@@ -2200,6 +2424,29 @@ func (s *server) Start() error {
 			startErr = err
 			return
 		}
+
+		if s.trampolineForwarder != nil {
+			cleanup = cleanup.add(s.trampolineForwarder.Stop)
+			if err := s.trampolineForwarder.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
+		if s.prober != nil {
+			cleanup = cleanup.add(s.prober.Stop)
+			if err := s.prober.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
+		cleanup = cleanup.add(s.rebalancer.Stop)
+		if err := s.rebalancer.Start(); err != nil {
+			startErr = err
+			return
+		}
+
 		// The authGossiper depends on the chanRouter and therefore
 		// should be started after it.
 		cleanup = cleanup.add(s.authGossiper.Stop)
@@ -2232,6 +2479,14 @@ func (s *server) Start() error {
 			return
 		}
 
+		if s.reputationMgr != nil {
+			cleanup = cleanup.add(s.reputationMgr.Stop)
+			if err := s.reputationMgr.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup.add(func() error {
 			s.missionController.StopStoreTickers()
 			return nil
@@ -2279,6 +2534,11 @@ func (s *server) Start() error {
 			return
 		}
 
+		if err := s.peerStorage.PruneBlobs(); err != nil {
+			startErr = err
+			return
+		}
+
 		if s.torController != nil {
 			cleanup = cleanup.add(s.torController.Stop)
 			if err := s.createNewHiddenService(); err != nil {
@@ -2470,9 +2730,30 @@ func (s *server) Stop() error {
 			srvrLog.Warnf("failed to stop htlc invoices "+
 				"modifier: %v", err)
 		}
+		if s.prober != nil {
+			if err := s.prober.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop prober: %v", err)
+			}
+		}
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
+
+		// The trampoline forwarder waits for the payments it sent, so
+		// it is stopped after the router.
+		if s.trampolineForwarder != nil {
+			err := s.trampolineForwarder.Stop()
+			if err != nil {
+				srvrLog.Warnf("failed to stop trampoline "+
+					"forwarder: %v", err)
+			}
+		}
+
+		// Like the trampoline forwarder, the rebalancer waits for its
+		// current payment.
+		if err := s.rebalancer.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop rebalancer: %v", err)
+		}
 		if err := s.chainArb.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chainArb: %v", err)
 		}
@@ -2501,6 +2782,18 @@ func (s *server) Stop() error {
 		if err := s.peerNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop peerNotifier: %v", err)
 		}
+		if s.offerManager != nil {
+			if err := s.offerManager.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop offerManager: "+
+					"%v", err)
+			}
+		}
+		if s.onionMessenger != nil {
+			if err := s.onionMessenger.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop onionMessenger: "+
+					"%v", err)
+			}
+		}
 		if err := s.htlcNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcNotifier: %v", err)
 		}
@@ -2535,6 +2828,12 @@ func (s *server) Stop() error {
 			srvrLog.Warnf("Unable to stop ChannelEventStore: %v",
 				err)
 		}
+		if s.reputationMgr != nil {
+			if err := s.reputationMgr.Stop(); err != nil {
+				srvrLog.Warnf("Unable to stop reputation "+
+					"manager: %v", err)
+			}
+		}
 		s.missionController.StopStoreTickers()
 
 		// Disconnect from each active peers to ensure that
@@ -4056,6 +4355,215 @@ func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
 	return s.customMessageServer.Subscribe()
 }
 
+// handleOnionMessage passes an onion message received from a peer on to the
+// onion messenger.
+func (s *server) handleOnionMessage(peer [33]byte,
+	msg *lnwire.OnionMessage) error {
+
+	return s.onionMessenger.HandleMessage(peer, msg)
+}
+
+// sendOnionMessage sends an onion message to a connected peer that supports
+// onion messages.
+func (s *server) sendOnionMessage(peerPub route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
+	if err != nil {
+		return err
+	}
+
+	if !peer.RemoteFeatures().HasFeature(lnwire.OnionMessagesOptional) {
+		return fmt.Errorf("peer %v doesn't support onion messages",
+			peerPub)
+	}
+
+	// Onion messages are best effort, so they're sent as low-priority
+	// messages.
+	return peer.SendMessageLazy(false, msg)
+}
+
+// hasOpenChannel returns true if we have an open or pending channel with the
+// given peer.
+func (s *server) hasOpenChannel(peer route.Vertex) (bool, error) {
+	pubKey, err := btcec.ParsePubKey(peer[:])
+	if err != nil {
+		return false, err
+	}
+
+	channels, err := s.chanStateDB.FetchOpenChannels(pubKey)
+	if err != nil {
+		return false, err
+	}
+
+	return len(channels) > 0, nil
+}
+
+// notifyClosedChannel notifies the subscribers of channel events about the
+// closed channel, and stops storing the blob of the channel peer if this was
+// our last channel with it.
+func (s *server) notifyClosedChannel(chanPoint wire.OutPoint) {
+	s.channelNotifier.NotifyClosedChannelEvent(chanPoint)
+
+	summary, err := s.chanStateDB.FetchClosedChannel(&chanPoint)
+	if err != nil {
+		srvrLog.Errorf("Unable to fetch closed channel %v: %v",
+			chanPoint, err)
+
+		return
+	}
+
+	peer := route.NewVertex(summary.RemotePub)
+	if err := s.peerStorage.ChannelClosed(peer); err != nil {
+		srvrLog.Errorf("Unable to remove peer storage of %v: %v",
+			peer, err)
+	}
+}
+
+// handlePeerStorage stores the blob the given peer asks us to store.
+func (s *server) handlePeerStorage(peer [33]byte,
+	msg *lnwire.PeerStorage) error {
+
+	return s.peerStorage.HandlePeerStorage(peer, msg)
+}
+
+// fetchPeerStorage returns the blob we store on behalf of the given peer.
+func (s *server) fetchPeerStorage(peer [33]byte) (*lnwire.YourPeerStorage,
+	error) {
+
+	return s.peerStorage.StoredBlob(peer)
+}
+
+// handleYourPeerStorage processes the blob the given peer returned to us.
+func (s *server) handleYourPeerStorage(peer [33]byte,
+	msg *lnwire.YourPeerStorage) error {
+
+	return s.peerStorage.HandleYourPeerStorage(peer, msg)
+}
+
+// sendToStoragePeers sends the given peer storage message to all connected
+// peers that provide storage.
+func (s *server) sendToStoragePeers(msg *lnwire.PeerStorage) {
+	for _, peer := range s.Peers() {
+		// Peers that aren't active yet receive our latest blob once
+		// they are, and their features aren't known before.
+		select {
+		case <-peer.ActiveSignal():
+		default:
+			continue
+		}
+
+		features := peer.RemoteFeatures()
+		if !features.HasFeature(lnwire.ProvideStorageOptional) {
+			continue
+		}
+
+		if err := peer.SendMessageLazy(false, msg); err != nil {
+			srvrLog.Debugf("Unable to send peer storage to %x: %v",
+				peer.PubKey(), err)
+		}
+	}
+}
+
+// newAddInvoiceConfig returns the config used to add invoices to the invoice
+// registry. If blind is set, the invoices carry blinded paths to our node that
+// satisfy the given restrictions.
+func (s *server) newAddInvoiceConfig(blind bool,
+	restrictions *routing.BlindedPathRestrictions,
+) *invoicesrpc.AddInvoiceConfig {
+
+	selfNode := route.NewVertex(s.identityECDH.PubKey())
+
+	return &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: s.cfg.Bitcoin.TimeLockDelta,
+		ChanDB:            s.chanStateDB,
+		Graph:             s.graphDB,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			v := s.featureMgr.Get(feature.SetInvoice)
+
+			if blind {
+				// If an invoice includes blinded paths, then a
+				// payment address is not required since we use
+				// the PathID in the final hop's encrypted data
+				// as equivalent to the payment address
+				v.Unset(lnwire.PaymentAddrRequired)
+				v.Set(lnwire.PaymentAddrOptional)
+
+				// The invoice payer will also need to
+				// understand the new BOLT 11 tagged field
+				// containing the blinded path, so we switch
+				// the bit to required.
+				v = feature.SetBit(
+					v, lnwire.Bolt11BlindedPathsRequired,
+				)
+			}
+
+			return v
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		GetAlias:   s.aliasMgr.GetPeerAlias,
+		BestHeight: s.cc.BestBlockTracker.BestHeight,
+		QueryBlindedRoutes: func(amt lnwire.MilliSatoshi) (
+			[]*route.Route, error) {
+
+			return s.chanRouter.FindBlindedPaths(
+				selfNode, amt, s.defaultMC.GetProbability,
+				restrictions,
+			)
+		},
+	}
+}
+
+// newBlindedPathConfig returns the config for the blinded paths added to
+// invoices, which are padded with dummy hops to the given number of hops.
+func (s *server) newBlindedPathConfig(
+	minNumHops uint8) *invoicesrpc.BlindedPathConfig {
+
+	bpConfig := s.cfg.Routing.BlindedPaths
+
+	return &invoicesrpc.BlindedPathConfig{
+		RoutePolicyIncrMultiplier: bpConfig.PolicyIncreaseMultiplier,
+		RoutePolicyDecrMultiplier: bpConfig.PolicyDecreaseMultiplier,
+		DefaultDummyHopPolicy: &blindedpath.BlindedHopPolicy{
+			CLTVExpiryDelta: uint16(s.cfg.Bitcoin.TimeLockDelta),
+			FeeRate:         uint32(s.cfg.Bitcoin.FeeRate),
+			BaseFee:         s.cfg.Bitcoin.BaseFee,
+			MinHTLCMsat:     s.cfg.Bitcoin.MinHTLCIn,
+
+			// MaxHTLCMsat will be calculated on the fly by using
+			// the introduction node's channel's capacities.
+			MaxHTLCMsat: 0,
+		},
+		MinNumPathHops: minNumHops,
+	}
+}
+
+// createOfferInvoice adds an invoice with blinded paths to our node to the
+// invoice registry in response to a BOLT 12 invoice request for one of our
+// offers.
+func (s *server) createOfferInvoice(req *offers.InvoiceRequest,
+	amt lnwire.MilliSatoshi) (*offers.CreatedInvoice, error) {
+
+	bpConfig := s.cfg.Routing.BlindedPaths
+	restrictions := &routing.BlindedPathRestrictions{
+		MinDistanceFromIntroNode: bpConfig.MinNumRealHops,
+		NumHops:                  bpConfig.NumHops,
+		MaxNumPaths:              bpConfig.MaxNumPaths,
+		NodeOmissionSet:          fn.NewSet[route.Vertex](),
+	}
+
+	return invoicesrpc.AddOfferInvoice(
+		context.Background(), s.newAddInvoiceConfig(true, restrictions),
+		s.newBlindedPathConfig(restrictions.NumHops), req, amt,
+	)
+}
+
 // peerConnected is a function that handles initialization a newly connected
 // peer by adding it to the server's global list of all active peers, and
 // starting all the goroutines the peer needs to function properly. The inbound
@@ -4160,7 +4668,8 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 
 		FetchLastChanUpdate: s.fetchLastChanUpdate(),
 
-		FundingManager: s.fundingMgr,
+		FundingManager:     s.fundingMgr,
+		NotifySpliceLocked: s.fundingMgr.SpliceLocked,
 
 		Hodl:                    s.cfg.Hodl,
 		UnsafeReplay:            s.cfg.UnsafeReplay,
@@ -4177,6 +4686,11 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		RequestAlias:           s.aliasMgr.RequestAlias,
 		AddLocalAlias:          s.aliasMgr.AddLocalAlias,
 		DisallowRouteBlinding:  s.cfg.ProtocolOptions.NoRouteBlinding(),
+		DisallowQuiescence:     s.cfg.ProtocolOptions.NoQuiescence(),
+		MaxLocalCSVDelay:       s.cfg.Bitcoin.MaxLocalDelay,
+		DisallowSplice:         !s.cfg.ProtocolOptions.Splice,
+		DisallowRbfCoopClose:   !s.cfg.ProtocolOptions.RbfCoopClose,
+		DisallowExpEndorsement: s.cfg.ProtocolOptions.NoExpEndorsement(),
 		MaxFeeExposure:         thresholdMSats,
 		Quit:                   s.quit,
 		AuxLeafStore:           s.implCfg.AuxLeafStore,
@@ -4189,6 +4703,21 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	copy(pCfg.PubKeyBytes[:], peerAddr.IdentityKey.SerializeCompressed())
 	copy(pCfg.ServerPubKey[:], s.identityECDH.PubKey().SerializeCompressed())
 
+	if s.onionMessenger != nil {
+		pCfg.HandleOnionMessage = s.handleOnionMessage
+	}
+
+	if s.cfg.PeerStorage.Provide {
+		pCfg.HandlePeerStorage = s.handlePeerStorage
+		pCfg.FetchPeerStorage = s.fetchPeerStorage
+	}
+	pCfg.HandleYourPeerStorage = s.handleYourPeerStorage
+	pCfg.OwnPeerStorage = s.peerStorage.OwnBackup
+
+	if s.trampolineForwarder != nil {
+		pCfg.TrampolineHandler = s.trampolineForwarder
+	}
+
 	p := peer.NewBrontide(pCfg)
 
 	// TODO(roasbeef): update IP address for link-node
@@ -4852,6 +5381,43 @@ func (s *server) OpenChannel(
 	return req.Updates, req.Err
 }
 
+// PurchaseLease buys a channel lease from the target peer by opening a
+// dual-funded channel to it that requests the leased amount. Updates on the
+// status of the leased channel are sent on the returned update channel.
+func (s *server) PurchaseLease(targetPubkey *btcec.PublicKey,
+	req *funding.LeaseRequest) (chan *lnrpc.OpenStatusUpdate, chan error) {
+
+	// As with OpenChannel, we expect a ChanPending and a ChanOpen update.
+	req.Updates = make(chan *lnrpc.OpenStatusUpdate, 2)
+	req.Err = make(chan error, 1)
+
+	pubKeyBytes := targetPubkey.SerializeCompressed()
+	s.mu.RLock()
+	peer, ok := s.peersByPub[string(pubKeyBytes)]
+	s.mu.RUnlock()
+	if !ok {
+		req.Err <- fmt.Errorf("peer %x is not online", pubKeyBytes)
+		return req.Updates, req.Err
+	}
+	req.Peer = peer
+
+	select {
+	case <-peer.ActiveSignal():
+	case <-peer.QuitSignal():
+		req.Err <- fmt.Errorf("peer %x disconnected", pubKeyBytes)
+		return req.Updates, req.Err
+	case <-s.quit:
+		req.Err <- ErrServerShuttingDown
+		return req.Updates, req.Err
+	}
+
+	if err := s.fundingMgr.PurchaseLease(req); err != nil {
+		req.Err <- err
+	}
+
+	return req.Updates, req.Err
+}
+
 // Peers returns a slice of all active peers.
 //
 // NOTE: This function is safe for concurrent access.
@@ -4926,6 +5492,21 @@ func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
 	ourPubKey := s.identityECDH.PubKey().SerializeCompressed()
 	return func(cid lnwire.ShortChannelID) (*lnwire.ChannelUpdate1, error) {
 		info, edge1, edge2, err := s.graphBuilder.GetChannelByID(cid)
+
+		// The edge of a spliced channel is known by the SCID of its
+		// latest splice, while the channel itself keeps the SCID it
+		// was opened with.
+		if errors.Is(err, channeldb.ErrEdgeNotFound) {
+			spliceScid, scidErr := s.fetchSpliceScid(cid)
+			if scidErr != nil {
+				return nil, scidErr
+			}
+
+			spliceScid.WhenSome(func(scid lnwire.ShortChannelID) {
+				info, edge1, edge2, err =
+					s.graphBuilder.GetChannelByID(scid)
+			})
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -4936,6 +5517,28 @@ func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
 	}
 }
 
+// fetchSpliceScid returns the SCID of the latest splice of the channel with
+// the given SCID, if the channel has been spliced. Zero-conf channels are
+// found by their confirmed SCID as well.
+func (s *server) fetchSpliceScid(
+	scid lnwire.ShortChannelID) (fn.Option[lnwire.ShortChannelID], error) {
+
+	channels, err := s.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return fn.None[lnwire.ShortChannelID](), err
+	}
+
+	for _, c := range channels {
+		isConfirmedScid := c.IsZeroConf() && c.ZeroConfConfirmed() &&
+			c.ZeroConfRealScid() == scid
+		if c.ShortChanID() == scid || isConfirmedScid {
+			return c.SpliceShortChanID(), nil
+		}
+	}
+
+	return fn.None[lnwire.ShortChannelID](), nil
+}
+
 // applyChannelUpdate applies the channel update to the different sub-systems of
 // the server. The useAlias boolean denotes whether or not to send an alias in
 // place of the real SCID.
@@ -5096,3 +5699,226 @@ func (s *server) fetchClosedChannelSCIDs() map[lnwire.ShortChannelID]struct{} {
 
 	return closedSCIDs
 }
+
+// newProber creates the background liquidity prober, which records its results
+// in the configured mission control namespace.
+func (s *server) newProber(cfg *routerrpc.ProberConfig,
+	nextPaymentID func() (uint64, error),
+	db *channeldb.DB) (*routing.Prober, error) {
+
+	nodes := make([]route.Vertex, 0, len(cfg.Nodes))
+	for _, node := range cfg.Nodes {
+		vertex, err := route.NewVertexFromStr(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid probe node %v: %w",
+				node, err)
+		}
+		nodes = append(nodes, vertex)
+	}
+
+	mc, err := s.missionController.GetNamespacedStore(cfg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchChannelNodes := func(chanID uint64) (route.Vertex, route.Vertex,
+		error) {
+
+		info, _, _, err := s.graphDB.FetchChannelEdgesByID(chanID)
+		if err != nil {
+			return route.Vertex{}, route.Vertex{}, err
+		}
+
+		return info.NodeKey1Bytes, info.NodeKey2Bytes, nil
+	}
+
+	// Probes always fail at the destination, so they use the smallest
+	// final CLTV delta, which keeps the probed liquidity locked for as
+	// short as possible.
+	return routing.NewProber(&routing.ProberConfig{
+		SelfNode:          route.NewVertex(s.identityECDH.PubKey()),
+		MissionControl:    mc,
+		FindRoute:         s.chanRouter.FindRoute,
+		Payer:             s.htlcSwitch,
+		NextPaymentID:     nextPaymentID,
+		QueryPayments:     db.QueryPayments,
+		FetchChannelNodes: fetchChannelNodes,
+		FinalCltvDelta:    routing.MinCLTVDelta,
+		CltvLimit:         s.cfg.MaxOutgoingCltvExpiry,
+		Nodes:             nodes,
+		Channels:          cfg.Channels,
+		TopDestinations:   cfg.TopDestinations,
+		Amount:            lnwire.NewMSatFromSatoshis(cfg.Amount),
+		Interval:          cfg.Interval,
+		MaxInFlight:       lnwire.NewMSatFromSatoshis(cfg.MaxInFlight),
+	})
+}
+
+// newRebalancer creates the rebalancer, which records the history of
+// rebalances in the given database.
+func (s *server) newRebalancer(cfg *routerrpc.RebalancerConfig,
+	db *channeldb.DB) (*rebalance.Rebalancer, error) {
+
+	targets, err := cfg.ParseTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := rebalance.NewStore(db)
+	if err != nil {
+		return nil, err
+	}
+
+	selfNode := route.NewVertex(s.identityECDH.PubKey())
+
+	// fetchFeeRate returns the proportional fee of our policy of the
+	// channel.
+	fetchFeeRate := func(chanID uint64) (uint32, error) {
+		info, e1, e2, err := s.graphDB.FetchChannelEdgesByID(chanID)
+		if err != nil {
+			return 0, err
+		}
+
+		ourPolicy := e2
+		if info.NodeKey1Bytes == selfNode {
+			ourPolicy = e1
+		}
+
+		if ourPolicy == nil {
+			return 0, fmt.Errorf("no policy for channel %v", chanID)
+		}
+
+		return uint32(ourPolicy.FeeProportionalMillionths), nil
+	}
+
+	addInvoice := func(amt lnwire.MilliSatoshi, memo string) (
+		lntypes.Hash, *invoices.Invoice, error) {
+
+		hash, invoice, err := invoicesrpc.AddInvoice(
+			context.Background(), s.newAddInvoiceConfig(false, nil),
+			&invoicesrpc.AddInvoiceData{
+				Memo:  memo,
+				Value: amt,
+			},
+		)
+		if err != nil {
+			return lntypes.Hash{}, nil, err
+		}
+
+		return *hash, invoice, nil
+	}
+
+	return rebalance.New(&rebalance.Config{
+		SelfNode:       selfNode,
+		FetchChannels:  s.chanStateDB.FetchAllOpenChannels,
+		FetchFeeRate:   fetchFeeRate,
+		AddInvoice:     addInvoice,
+		SendPayment:    s.chanRouter.SendPayment,
+		Store:          store,
+		FeeRatio:       cfg.FeeRatio,
+		CltvLimit:      s.cfg.MaxOutgoingCltvExpiry,
+		PaymentTimeout: cfg.PaymentTimeout,
+		Targets:        targets,
+		Interval:       cfg.Interval,
+		MaxAmount:      lnwire.NewMSatFromSatoshis(cfg.MaxAmount),
+	}), nil
+}
+
+// deleteSplicedEdge deletes the edge of the given spliced channel from the
+// graph. The edge is still known by the funding output the channel had before
+// its latest splice, so it's found through the funding keys of the channel,
+// which a splice doesn't change. The SCID of the deleted edge is returned
+// along with our policy, which is nil if no such edge exists.
+func (s *server) deleteSplicedEdge(c *channeldb.OpenChannel) (
+	lnwire.ShortChannelID, *models.ChannelEdgePolicy, error) {
+
+	localKey := c.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed()
+	remoteKey := c.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed()
+
+	var (
+		scids     []uint64
+		ourPolicy *models.ChannelEdgePolicy
+	)
+	ourNode := route.NewVertex(s.identityECDH.PubKey())
+	err := s.graphDB.ForEachNodeChannel(ourNode, func(_ kvdb.RTx,
+		info *models.ChannelEdgeInfo, outPolicy,
+		_ *models.ChannelEdgePolicy) error {
+
+		key1, key2 := info.BitcoinKey1Bytes[:], info.BitcoinKey2Bytes[:]
+		isChannel := bytes.Equal(key1, localKey) &&
+			bytes.Equal(key2, remoteKey) ||
+			bytes.Equal(key1, remoteKey) &&
+				bytes.Equal(key2, localKey)
+		if !isChannel {
+			return nil
+		}
+
+		scids = append(scids, info.ChannelID)
+		if outPolicy != nil {
+			ourPolicy = outPolicy
+		}
+
+		return nil
+	})
+	if err != nil {
+		return lnwire.ShortChannelID{}, nil, err
+	}
+
+	if len(scids) == 0 {
+		return lnwire.ShortChannelID{}, nil, nil
+	}
+
+	err = s.graphDB.DeleteChannelEdges(false, false, scids...)
+	if err != nil {
+		return lnwire.ShortChannelID{}, nil, err
+	}
+
+	return lnwire.NewShortChanIDFromInt(scids[0]), ourPolicy, nil
+}
+
+// isLocalSplice returns whether the given transaction splices one of our
+// channels, either because it was negotiated for the channel or because it
+// was locked already.
+func (s *server) isLocalSplice(tx *wire.MsgTx) (bool, error) {
+	channels, err := s.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return false, err
+	}
+
+	txid := tx.TxHash()
+	for _, c := range channels {
+		fundingPoint := c.CurrentFundingOutpoint()
+		if fundingPoint != c.FundingOutpoint &&
+			fundingPoint.Hash == txid {
+
+			return true, nil
+		}
+
+		candidates, err := c.SpliceCandidates()
+		if err != nil {
+			return false, err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Txid() == txid {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// initSplice asks the link of the given channel to negotiate a splice with the
+// remote peer.
+func (s *server) initSplice(chanID lnwire.ChannelID,
+	c *lnwallet.SpliceContribution) (<-chan fn.Result[*wire.MsgTx], error) {
+
+	link, err := s.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find link for channel %v: %w",
+			chanID, err)
+	}
+
+	return link.InitSplice(c), nil
+}
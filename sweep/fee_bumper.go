@@ -6,6 +6,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
@@ -42,6 +43,21 @@ var (
 	// ErrThirdPartySpent is returned when a third party has spent the
 	// input in the sweeping tx.
 	ErrThirdPartySpent = errors.New("third party spent the output")
+
+	// ErrTRUCChildTooLarge is returned when a sweeping tx that pays for an
+	// unconfirmed TRUC parent exceeds the size limit of a TRUC child.
+	ErrTRUCChildTooLarge = errors.New("TRUC child tx too large")
+)
+
+const (
+	// trucTxVersion is the tx version that opts a tx into the topologically
+	// restricted until confirmation (TRUC) policy of BIP 431. An
+	// unconfirmed TRUC tx can only be spent by a single TRUC child.
+	trucTxVersion = 3
+
+	// maxTRUCChildWeight is the max weight of a TRUC tx that spends an
+	// unconfirmed TRUC parent, which is 1,000 vbytes.
+	maxTRUCChildWeight lntypes.WeightUnit = 4_000
 )
 
 var (
@@ -455,6 +471,7 @@ func (t *TxPublisher) createRBFCompliantTx(req *BumpRequest,
 			// The tx is valid, return the request ID.
 			requestID := t.storeRecord(
 				sweepCtx.tx, req, f, sweepCtx.fee,
+				sweepCtx.parentTx,
 			)
 
 			log.Infof("Created tx %v for %v inputs: feerate=%v, "+
@@ -510,7 +527,8 @@ func (t *TxPublisher) createRBFCompliantTx(req *BumpRequest,
 
 // storeRecord stores the given record in the records map.
 func (t *TxPublisher) storeRecord(tx *wire.MsgTx, req *BumpRequest,
-	f FeeFunction, fee btcutil.Amount) uint64 {
+	f FeeFunction, fee btcutil.Amount,
+	parentTx fn.Option[*wire.MsgTx]) uint64 {
 
 	// Increase the request counter.
 	//
@@ -524,6 +542,7 @@ func (t *TxPublisher) storeRecord(tx *wire.MsgTx, req *BumpRequest,
 		req:         req,
 		feeFunction: f,
 		fee:         fee,
+		parentTx:    parentTx,
 	})
 
 	return requestID
@@ -578,6 +597,22 @@ func (t *TxPublisher) createAndCheckTx(req *BumpRequest,
 		return sweepCtx, nil
 	}
 
+	// If the inputs are missing, the unconfirmed parent of the tx may not
+	// have made it into the mempool on its own, e.g. because a commitment
+	// doesn't meet the mempool minimum fee. If we know the parent, we'll
+	// submit both as a package, which is only validated as a whole once
+	// submitted.
+	if errors.Is(err, chain.ErrMissingInputs) {
+		sweepCtx.parentTx = packageParent(req.Inputs)
+		if sweepCtx.parentTx.IsSome() {
+			log.Debugf("Inputs of tx=%v missing from mempool, "+
+				"will submit it as package",
+				sweepCtx.tx.TxHash())
+
+			return sweepCtx, nil
+		}
+	}
+
 	return sweepCtx, fmt.Errorf("tx=%v failed mempool check: %w",
 		sweepCtx.tx.TxHash(), err)
 }
@@ -616,9 +651,7 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 	// Publish the sweeping tx with customized label. If the publish fails,
 	// this error will be saved in the `BumpResult` and it will be removed
 	// from being monitored.
-	err = t.cfg.Wallet.PublishTransaction(
-		tx, labels.MakeLabel(labels.LabelTypeSweepTransaction, nil),
-	)
+	err = t.publish(record)
 	if err != nil {
 		// NOTE: we decide to attach this error to the result instead
 		// of returning it here because by the time the tx reaches
@@ -644,6 +677,41 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 	return result, nil
 }
 
+// publish broadcasts the tx of the given record. If the tx pays for an
+// unconfirmed parent that's missing from the mempool, both are submitted as a
+// package. If the chain backend doesn't support package relay, we fall back to
+// broadcasting them one at a time.
+func (t *TxPublisher) publish(record *monitorRecord) error {
+	label := labels.MakeLabel(labels.LabelTypeSweepTransaction, nil)
+
+	parentTx := record.parentTx.UnwrapOr(nil)
+	if parentTx == nil {
+		return t.cfg.Wallet.PublishTransaction(record.tx, label)
+	}
+
+	log.Debugf("Submitting package of parent=%v and child=%v",
+		parentTx.TxHash(), record.tx.TxHash())
+
+	err := t.cfg.Wallet.SubmitPackage(parentTx, record.tx, label)
+	if !errors.Is(err, lnwallet.ErrPackageRelayUnsupported) {
+		return err
+	}
+
+	log.Warnf("Chain backend doesn't support package relay, publishing "+
+		"parent=%v and child=%v separately, consider upgrading it "+
+		"to a newer version", parentTx.TxHash(), record.tx.TxHash())
+
+	// The parent is likely to be rejected again if it doesn't meet the
+	// mempool minimum fee on its own, in which case the child is rejected
+	// as well. We still try, as the mempool may have cleared up since.
+	if err := t.cfg.Wallet.PublishTransaction(parentTx, ""); err != nil {
+		log.Debugf("Failed to publish parent tx %v: %v",
+			parentTx.TxHash(), err)
+	}
+
+	return t.cfg.Wallet.PublishTransaction(record.tx, label)
+}
+
 // notifyResult sends the result to the resultChan specified by the requestID.
 // This channel is expected to be read by the caller.
 func (t *TxPublisher) notifyResult(result *BumpResult) {
@@ -725,6 +793,11 @@ type monitorRecord struct {
 
 	// fee is the fee paid by the tx.
 	fee btcutil.Amount
+
+	// parentTx is the unconfirmed parent of the tx that's submitted
+	// together with it as a package, if the parent is missing from the
+	// mempool.
+	parentTx fn.Option[*wire.MsgTx]
 }
 
 // Start starts the publisher by subscribing to block epoch updates and kicking
@@ -1046,6 +1119,7 @@ func (t *TxPublisher) createAndPublishTx(requestID uint64,
 		req:         r.req,
 		feeFunction: r.feeFunction,
 		fee:         sweepCtx.fee,
+		parentTx:    sweepCtx.parentTx,
 	})
 
 	// Attempt to broadcast this new tx.
@@ -1199,6 +1273,10 @@ type sweepTxCtx struct {
 	fee btcutil.Amount
 
 	extraTxOut fn.Option[SweepOutput]
+
+	// parentTx is the unconfirmed parent that needs to be submitted
+	// together with the tx as a package.
+	parentTx fn.Option[*wire.MsgTx]
 }
 
 // createSweepTx creates a sweeping tx based on the given inputs, change
@@ -1218,8 +1296,10 @@ func (t *TxPublisher) createSweepTx(inputs []input.Input,
 
 	var (
 		// Create the sweep transaction that we will be building. We
-		// use version 2 as it is required for CSV.
-		sweepTx = wire.NewMsgTx(2)
+		// use version 2 as it is required for CSV, unless we pay for
+		// an unconfirmed TRUC parent, which only a TRUC child can
+		// spend.
+		sweepTx = wire.NewMsgTx(sweepTxVersion(inputs))
 
 		// We'll add the inputs as we go so we know the final ordering
 		// of inputs to sign.
@@ -1302,6 +1382,18 @@ func (t *TxPublisher) createSweepTx(inputs []input.Input,
 		}
 	}
 
+	// A TRUC child is limited in size, so it can't pin its parent.
+	if sweepTx.Version == trucTxVersion {
+		weight := lntypes.WeightUnit(
+			blockchain.GetTransactionWeight(btcutil.NewTx(sweepTx)),
+		)
+		if weight > maxTRUCChildWeight {
+			return nil, fmt.Errorf("%w: weight=%v, max=%v",
+				ErrTRUCChildTooLarge, weight,
+				maxTRUCChildWeight)
+		}
+	}
+
 	log.Debugf("Created sweep tx %v for inputs:\n%v", sweepTx.TxHash(),
 		inputTypeSummary(inputs))
 
@@ -1337,6 +1429,61 @@ func (t *TxPublisher) createSweepTx(inputs []input.Input,
 	}, nil
 }
 
+// sweepTxVersion returns the version of the tx sweeping the given inputs. It's
+// the TRUC version if any of the inputs spends an unconfirmed TRUC parent or
+// the pay-to-anchor output of a zero-fee commitment, and version 2 otherwise.
+func sweepTxVersion(inputs []input.Input) int32 {
+	for _, inp := range inputs {
+		// The anchor of a zero-fee commitment may be swept without
+		// knowing the commitment itself, e.g. for the commitment of the
+		// remote party.
+		if inp.WitnessType() == input.PayToAnchorSpend {
+			return trucTxVersion
+		}
+
+		parent := inp.UnconfParent()
+		if parent == nil || parent.Tx == nil {
+			continue
+		}
+
+		if parent.Tx.Version == trucTxVersion {
+			return trucTxVersion
+		}
+	}
+
+	return 2
+}
+
+// packageParent returns the unconfirmed parent of the given inputs that can
+// be submitted as a package together with the tx sweeping them. As only
+// packages of one parent and one child are relayed, None is returned if the
+// inputs spend more than one known parent.
+func packageParent(inputs []input.Input) fn.Option[*wire.MsgTx] {
+	var parentTx *wire.MsgTx
+	for _, inp := range inputs {
+		parent := inp.UnconfParent()
+		if parent == nil || parent.Tx == nil {
+			continue
+		}
+
+		if parentTx != nil && parentTx.TxHash() != parent.Tx.TxHash() {
+			log.Debugf("Inputs spend multiple unconfirmed "+
+				"parents, unable to submit package: %v, %v",
+				parentTx.TxHash(), parent.Tx.TxHash())
+
+			return fn.None[*wire.MsgTx]()
+		}
+
+		parentTx = parent.Tx
+	}
+
+	if parentTx == nil {
+		return fn.None[*wire.MsgTx]()
+	}
+
+	return fn.Some(parentTx)
+}
+
 // prepareSweepTx returns the tx fee, a set of optional change outputs and an
 // optional locktime after a series of validations:
 // 1. check the locktime has been reached.
@@ -324,7 +324,9 @@ func TestStoreRecord(t *testing.T) {
 	initialCounter := tp.requestCounter.Load()
 
 	// Call the method under test.
-	requestID := tp.storeRecord(tx, req, feeFunc, fee)
+	requestID := tp.storeRecord(
+		tx, req, feeFunc, fee, fn.None[*wire.MsgTx](),
+	)
 
 	// Check the request ID is as expected.
 	require.Equal(t, initialCounter+1, requestID)
@@ -667,7 +669,9 @@ func TestTxPublisherBroadcast(t *testing.T) {
 
 	// Create a testing record and put it in the map.
 	fee := btcutil.Amount(1000)
-	requestID := tp.storeRecord(tx, req, m.feeFunc, fee)
+	requestID := tp.storeRecord(
+		tx, req, m.feeFunc, fee, fn.None[*wire.MsgTx](),
+	)
 
 	// Quickly check when the requestID cannot be found, an error is
 	// returned.
@@ -737,6 +741,210 @@ func TestTxPublisherBroadcast(t *testing.T) {
 	}
 }
 
+// createTestChildInput creates a test input that spends an output of the given
+// unconfirmed parent tx.
+func createTestChildInput(value int64, parentTx *wire.MsgTx) input.BaseInput {
+	return input.MakeBaseInput(
+		&wire.OutPoint{Hash: parentTx.TxHash()},
+		input.WitnessKeyHash,
+		&input.SignDescriptor{
+			Output: &wire.TxOut{
+				Value: value,
+			},
+			KeyDesc: keychain.KeyDescriptor{
+				PubKey: testPubKey,
+			},
+		},
+		0,
+		&input.TxInfo{
+			Fee:    1000,
+			Weight: 500,
+			Tx:     parentTx,
+		},
+	)
+}
+
+// TestPackageParent checks that the unconfirmed parent of the inputs is only
+// returned if it's the only one known.
+func TestPackageParent(t *testing.T) {
+	t.Parallel()
+
+	parentA := &wire.MsgTx{Version: 2, LockTime: 1}
+	parentB := &wire.MsgTx{Version: 2, LockTime: 2}
+
+	confirmed := createTestInput(1000, input.WitnessKeyHash)
+	childA := createTestChildInput(1000, parentA)
+	childA2 := createTestChildInput(2000, parentA)
+	childB := createTestChildInput(1000, parentB)
+
+	testCases := []struct {
+		name     string
+		inputs   []input.Input
+		expected fn.Option[*wire.MsgTx]
+	}{{
+		name:     "no unconfirmed parent",
+		inputs:   []input.Input{&confirmed},
+		expected: fn.None[*wire.MsgTx](),
+	}, {
+		name:     "single parent",
+		inputs:   []input.Input{&confirmed, &childA, &childA2},
+		expected: fn.Some(parentA),
+	}, {
+		name:     "multiple parents",
+		inputs:   []input.Input{&childA, &childB},
+		expected: fn.None[*wire.MsgTx](),
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, packageParent(tc.inputs))
+		})
+	}
+}
+
+// TestCreateAndCheckTxPackage checks that a tx whose unconfirmed parent is
+// missing from the mempool is accepted to be submitted as a package, and that
+// a TRUC parent is spent by a TRUC child.
+func TestCreateAndCheckTxPackage(t *testing.T) {
+	t.Parallel()
+
+	// Create a publisher using the mocks.
+	tp, m := createTestPublisher(t)
+
+	feerate := chainfee.SatPerKWeight(1000)
+	m.feeFunc.On("FeeRate").Return(feerate)
+
+	script := &input.Script{}
+	m.signer.On("ComputeInputScript", mock.Anything,
+		mock.Anything).Return(script, nil)
+
+	// The mempool doesn't know the parents of the inputs.
+	m.wallet.On("CheckMempoolAcceptance", mock.Anything).Return(
+		chain.ErrMissingInputs,
+	)
+
+	parentTx := &wire.MsgTx{Version: 2, LockTime: 1}
+	trucParentTx := &wire.MsgTx{Version: trucTxVersion, LockTime: 1}
+
+	confirmed := createTestInput(10_000, input.WitnessKeyHash)
+	child := createTestChildInput(10_000, parentTx)
+	trucChild := createTestChildInput(10_000, trucParentTx)
+
+	testCases := []struct {
+		name            string
+		inp             input.Input
+		expectedParent  fn.Option[*wire.MsgTx]
+		expectedVersion int32
+		expectedErr     error
+	}{{
+		name:        "unknown parent",
+		inp:         &confirmed,
+		expectedErr: chain.ErrMissingInputs,
+	}, {
+		name:            "known parent",
+		inp:             &child,
+		expectedParent:  fn.Some(parentTx),
+		expectedVersion: 2,
+	}, {
+		name:            "known TRUC parent",
+		inp:             &trucChild,
+		expectedParent:  fn.Some(trucParentTx),
+		expectedVersion: trucTxVersion,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &BumpRequest{
+				DeliveryAddress: changePkScript,
+				Inputs:          []input.Input{tc.inp},
+				Budget:          btcutil.Amount(1000),
+			}
+
+			sweepCtx, err := tp.createAndCheckTx(req, m.feeFunc)
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+
+			require.Equal(t, tc.expectedParent, sweepCtx.parentTx)
+			require.Equal(t, tc.expectedVersion,
+				sweepCtx.tx.Version)
+		})
+	}
+}
+
+// TestTxPublisherBroadcastPackage checks that a tx with an unconfirmed parent
+// missing from the mempool is submitted as a package, and that the parent and
+// the tx are published separately if package relay isn't supported.
+func TestTxPublisherBroadcastPackage(t *testing.T) {
+	t.Parallel()
+
+	// Create a publisher using the mocks.
+	tp, m := createTestPublisher(t)
+
+	req := createTestBumpRequest()
+	parentTx := &wire.MsgTx{LockTime: 1}
+	tx := &wire.MsgTx{LockTime: 2}
+
+	feerate := chainfee.SatPerKWeight(1000)
+	m.feeFunc.On("FeeRate").Return(feerate)
+
+	fee := btcutil.Amount(1000)
+	requestID := tp.storeRecord(
+		tx, req, m.feeFunc, fee, fn.Some(parentTx),
+	)
+
+	testCases := []struct {
+		name          string
+		setupMock     func()
+		expectedEvent BumpEvent
+		expectedErr   error
+	}{{
+		name: "package accepted",
+		setupMock: func() {
+			m.wallet.On("SubmitPackage", parentTx, tx,
+				mock.Anything).Return(nil).Once()
+		},
+		expectedEvent: TxPublished,
+	}, {
+		name: "package rejected",
+		setupMock: func() {
+			m.wallet.On("SubmitPackage", parentTx, tx,
+				mock.Anything).Return(errDummy).Once()
+		},
+		expectedEvent: TxFailed,
+		expectedErr:   errDummy,
+	}, {
+		name: "package relay unsupported",
+		setupMock: func() {
+			m.wallet.On("SubmitPackage", parentTx, tx,
+				mock.Anything).Return(
+				lnwallet.ErrPackageRelayUnsupported).Once()
+
+			// A failure to publish the parent on its own doesn't
+			// prevent publishing the tx.
+			m.wallet.On("PublishTransaction", parentTx, "").Return(
+				lnwallet.ErrMempoolFee).Once()
+			m.wallet.On("PublishTransaction", tx,
+				mock.Anything).Return(nil).Once()
+		},
+		expectedEvent: TxPublished,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			result, err := tp.broadcast(requestID)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedEvent, result.Event)
+			require.ErrorIs(t, result.Err, tc.expectedErr)
+		})
+	}
+}
+
 // TestRemoveResult checks the records and subscriptions are removed when a tx
 // is confirmed or failed.
 func TestRemoveResult(t *testing.T) {
@@ -765,7 +973,10 @@ func TestRemoveResult(t *testing.T) {
 			// removed.
 			name: "remove on TxConfirmed",
 			setupRecord: func() uint64 {
-				id := tp.storeRecord(tx, req, m.feeFunc, fee)
+				id := tp.storeRecord(
+					tx, req, m.feeFunc, fee,
+					fn.None[*wire.MsgTx](),
+				)
 				tp.subscriberChans.Store(id, nil)
 
 				return id
@@ -780,7 +991,10 @@ func TestRemoveResult(t *testing.T) {
 			// When the tx is failed, the records will be removed.
 			name: "remove on TxFailed",
 			setupRecord: func() uint64 {
-				id := tp.storeRecord(tx, req, m.feeFunc, fee)
+				id := tp.storeRecord(
+					tx, req, m.feeFunc, fee,
+					fn.None[*wire.MsgTx](),
+				)
 				tp.subscriberChans.Store(id, nil)
 
 				return id
@@ -796,7 +1010,10 @@ func TestRemoveResult(t *testing.T) {
 			// Noop when the tx is neither confirmed or failed.
 			name: "noop when tx is not confirmed or failed",
 			setupRecord: func() uint64 {
-				id := tp.storeRecord(tx, req, m.feeFunc, fee)
+				id := tp.storeRecord(
+					tx, req, m.feeFunc, fee,
+					fn.None[*wire.MsgTx](),
+				)
 				tp.subscriberChans.Store(id, nil)
 
 				return id
@@ -846,7 +1063,9 @@ func TestNotifyResult(t *testing.T) {
 
 	// Create a testing record and put it in the map.
 	fee := btcutil.Amount(1000)
-	requestID := tp.storeRecord(tx, req, m.feeFunc, fee)
+	requestID := tp.storeRecord(
+		tx, req, m.feeFunc, fee, fn.None[*wire.MsgTx](),
+	)
 
 	// Create a subscription to the event.
 	subscriber := make(chan *BumpResult, 1)
@@ -1203,7 +1422,9 @@ func TestHandleTxConfirmed(t *testing.T) {
 
 	// Create a testing record and put it in the map.
 	fee := btcutil.Amount(1000)
-	requestID := tp.storeRecord(tx, req, m.feeFunc, fee)
+	requestID := tp.storeRecord(
+		tx, req, m.feeFunc, fee, fn.None[*wire.MsgTx](),
+	)
 	record, ok := tp.records.Load(requestID)
 	require.True(t, ok)
 
@@ -1275,7 +1496,9 @@ func TestHandleFeeBumpTx(t *testing.T) {
 
 	// Create a testing record and put it in the map.
 	fee := btcutil.Amount(1000)
-	requestID := tp.storeRecord(tx, req, m.feeFunc, fee)
+	requestID := tp.storeRecord(
+		tx, req, m.feeFunc, fee, fn.None[*wire.MsgTx](),
+	)
 
 	// Create a subscription to the event.
 	subscriber := make(chan *BumpResult, 1)
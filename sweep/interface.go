@@ -51,6 +51,12 @@ type Wallet interface {
 	// mempool.
 	CheckMempoolAcceptance(tx *wire.MsgTx) error
 
+	// SubmitPackage submits an unconfirmed parent transaction together
+	// with a child spending it to the mempool of the chain backend. If
+	// the chain backend doesn't support package relay,
+	// lnwallet.ErrPackageRelayUnsupported is returned.
+	SubmitPackage(parent, child *wire.MsgTx, label string) error
+
 	// GetTransactionDetails returns a detailed description of a tx given
 	// its transaction hash.
 	GetTransactionDetails(txHash *chainhash.Hash) (
@@ -128,6 +128,16 @@ func (m *MockWallet) CheckMempoolAcceptance(tx *wire.MsgTx) error {
 	return args.Error(0)
 }
 
+// SubmitPackage submits a package of a parent and a child transaction to the
+// mempool.
+func (m *MockWallet) SubmitPackage(parent, child *wire.MsgTx,
+	label string) error {
+
+	args := m.Called(parent, child, label)
+
+	return args.Error(0)
+}
+
 // PublishTransaction performs cursory validation (dust checks, etc) and
 // broadcasts the passed transaction to the Bitcoin network.
 func (m *MockWallet) PublishTransaction(tx *wire.MsgTx, label string) error {
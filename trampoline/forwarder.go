@@ -0,0 +1,604 @@
+package trampoline
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// DefaultMppTimeout is the default time we wait for all parts of a
+	// trampoline payment to arrive.
+	DefaultMppTimeout = 2 * time.Minute
+
+	// DefaultPaymentTimeout is the default time we spend trying to pay
+	// the next node of a trampoline payment.
+	DefaultPaymentTimeout = time.Minute
+)
+
+// errShuttingDown is returned when the forwarder is stopped while waiting for
+// the outcome of a payment.
+var errShuttingDown = errors.New("trampoline forwarder shutting down")
+
+// ForwarderConfig holds the configuration of the trampoline forwarder.
+type ForwarderConfig struct {
+	// NodeKey is used to decrypt the trampoline onions we receive.
+	NodeKey sphinx.SingleKeyECDH
+
+	// Budget is the minimum fee and CLTV budget a trampoline payment must
+	// leave us to be forwarded. The fee is what we charge for the
+	// trampoline hop, which we keep on top of the routing fees to the
+	// next node.
+	Budget Budget
+
+	// MinCltvDelta is the number of blocks we keep between the expiry of
+	// the incoming htlcs and the timelock of the outgoing payment.
+	MinCltvDelta uint16
+
+	// MppTimeout is the time we wait for all parts of a trampoline
+	// payment to arrive before failing them back.
+	MppTimeout time.Duration
+
+	// PaymentTimeout is the time we spend trying to pay the next node.
+	PaymentTimeout time.Duration
+
+	// MaxParts is the maximum number of parts we split the outgoing
+	// payment into.
+	MaxParts uint32
+
+	// SendPayment sends a payment and blocks until it completes.
+	SendPayment func(*routing.LightningPayment) ([32]byte, *route.Route,
+		error)
+
+	// SubscribePayment subscribes to the updates of a payment that was
+	// sent before.
+	SubscribePayment func(lntypes.Hash) (routing.ControlTowerSubscriber,
+		error)
+}
+
+// setKey identifies a set of htlcs that together make up a trampoline
+// payment.
+type setKey struct {
+	hash lntypes.Hash
+	addr [32]byte
+}
+
+// pendingHtlc is an incoming htlc of a trampoline payment.
+type pendingHtlc struct {
+	amt      lnwire.MilliSatoshi
+	expiry   uint32
+	hodlChan chan<- interface{}
+
+	// resolution is the resolution of the htlc if it was resolved while
+	// nobody was subscribed to it.
+	resolution invoices.HtlcResolution
+}
+
+// htlcSet is the set of incoming htlcs of a trampoline payment.
+type htlcSet struct {
+	total        lnwire.MilliSatoshi
+	onion        []byte
+	acceptHeight int32
+	htlcs        map[models.CircuitKey]*pendingHtlc
+	timer        *time.Timer
+
+	// complete is set once the set doesn't accept any more parts,
+	// because all parts arrived or because it timed out.
+	complete bool
+}
+
+// Forwarder accepts exit hop htlcs that carry a trampoline onion. Once all
+// parts of a trampoline payment have arrived, it finds a route to the next
+// node of the trampoline onion and pays it. The incoming htlcs are settled
+// or failed depending on the outcome of the outgoing payment.
+type Forwarder struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *ForwarderConfig
+
+	sets map[setKey]*htlcSet
+	mu   sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewForwarder creates a new trampoline forwarder.
+func NewForwarder(cfg *ForwarderConfig) *Forwarder {
+	return &Forwarder{
+		cfg:  cfg,
+		sets: make(map[setKey]*htlcSet),
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts the trampoline forwarder.
+func (f *Forwarder) Start() error {
+	f.started.Do(func() {
+		log.Info("Trampoline forwarder starting")
+	})
+
+	return nil
+}
+
+// Stop stops the trampoline forwarder. Payments that are in flight are not
+// aborted, the incoming htlcs are resolved after the next restart once the
+// links notify us about them again.
+func (f *Forwarder) Stop() error {
+	f.stopped.Do(func() {
+		log.Info("Trampoline forwarder shutting down...")
+		defer log.Debug("Trampoline forwarder shutdown complete")
+
+		close(f.quit)
+
+		f.mu.Lock()
+		for _, set := range f.sets {
+			set.timer.Stop()
+		}
+		f.mu.Unlock()
+
+		f.wg.Wait()
+	})
+
+	return nil
+}
+
+// NotifyTrampolineHtlc hands an exit hop htlc carrying the given trampoline
+// onion to the forwarder. The htlc is held until all parts of the trampoline
+// payment have arrived and the payment to the next node completed, after
+// which its resolution is sent on the passed in hodlChan.
+//
+// NOTE: Part of the htlcswitch.TrampolineHandler interface.
+func (f *Forwarder) NotifyTrampolineHtlc(payHash lntypes.Hash,
+	paidAmount lnwire.MilliSatoshi, expiry uint32, currentHeight int32,
+	circuitKey models.CircuitKey, hodlChan chan<- interface{},
+	payload invoices.Payload, onion []byte) (invoices.HtlcResolution,
+	error) {
+
+	// The outer onion must tie the parts of the payment together, which
+	// the payload validation already enforces.
+	mpp := payload.MultiPath()
+	if mpp == nil {
+		return invoices.NewFailResolution(
+			circuitKey, currentHeight,
+			invoices.ResultTrampolineIncorrectDetails,
+		), nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := setKey{hash: payHash, addr: mpp.PaymentAddr()}
+	set, ok := f.sets[key]
+
+	// If the link notifies us about an htlc we already know, it is
+	// replaying it after a reconnect. Either hand out the resolution that
+	// we couldn't deliver, or keep holding the htlc.
+	if ok {
+		if htlc, ok := set.htlcs[circuitKey]; ok {
+			if htlc.resolution != nil {
+				f.removeHtlc(key, set, circuitKey)
+
+				return htlc.resolution, nil
+			}
+
+			htlc.hodlChan = hodlChan
+
+			return nil, nil
+		}
+	}
+
+	if !ok {
+		set = &htlcSet{
+			total:        mpp.TotalMsat(),
+			onion:        onion,
+			acceptHeight: currentHeight,
+			htlcs:        make(map[models.CircuitKey]*pendingHtlc),
+		}
+		set.timer = time.AfterFunc(f.cfg.MppTimeout, func() {
+			f.timeoutSet(key)
+		})
+		f.sets[key] = set
+	}
+
+	switch {
+	// Once we started to forward the payment or timed out, no more parts
+	// are accepted.
+	case set.complete:
+		return invoices.NewFailResolution(
+			circuitKey, currentHeight, invoices.ResultMppInProgress,
+		), nil
+
+	// All parts must agree on the total amount of the payment.
+	case mpp.TotalMsat() != set.total:
+		return invoices.NewFailResolution(
+			circuitKey, currentHeight,
+			invoices.ResultHtlcSetTotalMismatch,
+		), nil
+	}
+
+	set.htlcs[circuitKey] = &pendingHtlc{
+		amt:      paidAmount,
+		expiry:   expiry,
+		hodlChan: hodlChan,
+	}
+
+	var (
+		received  lnwire.MilliSatoshi
+		minExpiry uint32 = math.MaxUint32
+	)
+	for _, htlc := range set.htlcs {
+		received += htlc.amt
+		if htlc.expiry < minExpiry {
+			minExpiry = htlc.expiry
+		}
+	}
+
+	log.Debugf("Received trampoline htlc %v for payment %v (%v of %v)",
+		circuitKey, payHash, received, set.total)
+
+	if received < set.total {
+		return nil, nil
+	}
+
+	set.complete = true
+	set.timer.Stop()
+
+	f.wg.Add(1)
+	go f.forward(key, set.onion, received, minExpiry, currentHeight)
+
+	return nil, nil
+}
+
+// TrampolineUnsubscribeAll removes the given subscriber from all htlcs it was
+// subscribed to.
+//
+// NOTE: Part of the htlcswitch.TrampolineHandler interface.
+func (f *Forwarder) TrampolineUnsubscribeAll(subscriber chan<- interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, set := range f.sets {
+		for _, htlc := range set.htlcs {
+			if htlc.hodlChan == subscriber {
+				htlc.hodlChan = nil
+			}
+		}
+	}
+}
+
+// timeoutSet fails all htlcs of a trampoline payment whose parts didn't all
+// arrive in time.
+func (f *Forwarder) timeoutSet(key setKey) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	set, ok := f.sets[key]
+	if !ok || set.complete {
+		return
+	}
+	set.complete = true
+
+	log.Debugf("Trampoline payment %v timed out waiting for all parts",
+		key.hash)
+
+	f.resolveSet(key, set, func(
+		circuitKey models.CircuitKey) invoices.HtlcResolution {
+
+		return invoices.NewFailResolution(
+			circuitKey, set.acceptHeight, invoices.ResultMppTimeout,
+		)
+	})
+}
+
+// forward pays the next node of a trampoline payment and resolves the
+// incoming htlcs with the outcome.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Forwarder) forward(key setKey, onion []byte,
+	received lnwire.MilliSatoshi, minExpiry uint32, height int32) {
+
+	defer f.wg.Done()
+
+	preimage, outcome, err := f.pay(
+		key.hash, onion, received, minExpiry, uint32(height),
+	)
+
+	// If we don't know the outcome of the payment, we must not resolve
+	// the incoming htlcs. They are notified to us again after a restart.
+	if err != nil {
+		log.Errorf("Unable to forward trampoline payment %v: %v",
+			key.hash, err)
+
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	set, ok := f.sets[key]
+	if !ok {
+		return
+	}
+
+	resolve := func(circuitKey models.CircuitKey) invoices.HtlcResolution {
+		return invoices.NewFailResolution(
+			circuitKey, set.acceptHeight, outcome,
+		)
+	}
+	if preimage != nil {
+		log.Infof("Forwarded trampoline payment %v", key.hash)
+
+		resolve = func(
+			circuitKey models.CircuitKey) invoices.HtlcResolution {
+
+			return invoices.NewSettleResolution(
+				*preimage, circuitKey, set.acceptHeight,
+				invoices.ResultSettled,
+			)
+		}
+	} else {
+		log.Infof("Failed to forward trampoline payment %v: %v",
+			key.hash, outcome)
+	}
+
+	f.resolveSet(key, set, resolve)
+}
+
+// pay decodes the trampoline onion, checks that it leaves us enough budget
+// and pays the next node. It returns either the preimage of the payment, or
+// the failure to resolve the incoming htlcs with. An error is returned if
+// the outcome of the payment is unknown.
+func (f *Forwarder) pay(hash lntypes.Hash, onion []byte,
+	received lnwire.MilliSatoshi, minExpiry,
+	height uint32) (*lntypes.Preimage, invoices.FailResolutionResult,
+	error) {
+
+	payment, outcome := f.newPayment(hash, onion, received, minExpiry,
+		height)
+	if payment == nil {
+		return nil, outcome, nil
+	}
+
+	log.Debugf("Forwarding trampoline payment %v of %v to %v",
+		hash, payment.Amount, payment.Target)
+
+	var reason channeldb.FailureReason
+	preimage, _, err := f.cfg.SendPayment(payment)
+	switch {
+	case err == nil:
+		p := lntypes.Preimage(preimage)
+		return &p, 0, nil
+
+	case errors.As(err, &reason):
+		return nil, failureOutcome(reason), nil
+	}
+
+	// The payment may already have been sent before a restart, or we
+	// may not know whether it was sent at all. We consult the control
+	// tower to find out.
+	log.Debugf("Unable to send trampoline payment %v, waiting for "+
+		"its outcome: %v", hash, err)
+
+	return f.waitForPayment(hash)
+}
+
+// newPayment creates the payment to the next node of a trampoline payment.
+// If the payment can't be forwarded, the failure to resolve the incoming
+// htlcs with is returned instead.
+func (f *Forwarder) newPayment(hash lntypes.Hash, onion []byte,
+	received lnwire.MilliSatoshi, minExpiry,
+	height uint32) (*routing.LightningPayment,
+	invoices.FailResolutionResult) {
+
+	packet, err := DecodeOnionPacket(onion)
+	if err != nil {
+		log.Debugf("Invalid trampoline onion for %v: %v", hash, err)
+		return nil, invoices.ResultTrampolineUnsupported
+	}
+
+	processed, err := ProcessOnionPacket(f.cfg.NodeKey, packet, hash[:])
+	if err != nil {
+		log.Debugf("Unable to process trampoline onion for %v: %v",
+			hash, err)
+		return nil, invoices.ResultTrampolineUnsupported
+	}
+
+	payload, err := DecodePayload(processed.Payload)
+	if err != nil {
+		log.Debugf("Invalid trampoline payload for %v: %v", hash, err)
+		return nil, invoices.ResultTrampolineUnsupported
+	}
+
+	switch {
+	// We don't accept trampoline payments ourselves.
+	case payload.OutgoingNodeID == nil:
+		log.Debugf("Trampoline payment %v without outgoing node", hash)
+		return nil, invoices.ResultTrampolineUnsupported
+
+	// If we are the last trampoline node, we pay the recipient directly
+	// and need its payment secret to do so. We don't split up the
+	// recipient's invoice between several trampoline nodes.
+	case processed.NextPacket == nil && (payload.MPP == nil ||
+		payload.MPP.TotalMsat() != payload.AmtToForward):
+
+		log.Debugf("Trampoline payment %v with invalid recipient "+
+			"details", hash)
+		return nil, invoices.ResultTrampolineIncorrectDetails
+	}
+
+	// The fee of our trampoline hop is ours to keep, so only what the
+	// sender left on top of it can be spent on routing fees.
+	amt := payload.AmtToForward
+	ownFee := f.cfg.Budget.Fee(amt)
+	if received < amt || received-amt < ownFee {
+		return nil, invoices.ResultTrampolineFeeInsufficient
+	}
+
+	cltvBudget := uint32(f.cfg.Budget.CltvDelta)
+	if payload.OutgoingCltv <= height ||
+		payload.OutgoingCltv-height > math.MaxUint16 ||
+		minExpiry < payload.OutgoingCltv+cltvBudget ||
+		minExpiry < height+uint32(f.cfg.MinCltvDelta) {
+
+		return nil, invoices.ResultTrampolineExpiryTooSoon
+	}
+
+	payment := &routing.LightningPayment{
+		Target:    route.NewVertex(payload.OutgoingNodeID),
+		Amount:    amt,
+		FeeLimit:  received - amt - ownFee,
+		CltvLimit: minExpiry - uint32(f.cfg.MinCltvDelta) - height,
+		FinalCLTVDelta: uint16(
+			payload.OutgoingCltv - height,
+		),
+		PayAttemptTimeout: f.cfg.PaymentTimeout,
+		MaxParts:          f.cfg.MaxParts,
+		Metadata:          payload.Metadata,
+	}
+	if err := payment.SetPaymentHash(hash); err != nil {
+		return nil, invoices.ResultTrampolineUnsupported
+	}
+
+	// If there is another trampoline node, we tie the parts of our
+	// payment together with a fresh payment address and hand it the rest
+	// of the trampoline onion.
+	if processed.NextPacket != nil {
+		next, err := processed.NextPacket.Bytes()
+		if err != nil {
+			return nil, invoices.ResultTrampolineUnsupported
+		}
+
+		var addr [32]byte
+		if _, err := rand.Read(addr[:]); err != nil {
+			return nil, invoices.ResultTrampolineForwardFailed
+		}
+
+		payment.PaymentAddr = fn.Some(addr)
+		payment.TrampolineOnion = next
+
+		return payment, 0
+	}
+
+	payment.PaymentAddr = fn.Some(payload.MPP.PaymentAddr())
+	if payload.InvoiceFeatures != nil {
+		payment.DestFeatures = lnwire.NewFeatureVector(
+			payload.InvoiceFeatures, lnwire.Features,
+		)
+	}
+
+	return payment, 0
+}
+
+// waitForPayment waits for a payment that was sent before to complete.
+func (f *Forwarder) waitForPayment(hash lntypes.Hash) (*lntypes.Preimage,
+	invoices.FailResolutionResult, error) {
+
+	sub, err := f.cfg.SubscribePayment(hash)
+
+	// If the payment was never initiated, it is safe to fail the
+	// incoming htlcs.
+	if errors.Is(err, channeldb.ErrPaymentNotInitiated) {
+		return nil, invoices.ResultTrampolineForwardFailed, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case item, ok := <-sub.Updates():
+			if !ok {
+				return nil, 0, fmt.Errorf("payment %v "+
+					"subscription closed", hash)
+			}
+
+			payment, ok := item.(*channeldb.MPPayment)
+			if !ok || !payment.Terminated() {
+				continue
+			}
+
+			htlc, reason := payment.TerminalInfo()
+			switch {
+			case htlc != nil && htlc.Settle != nil:
+				preimage := htlc.Settle.Preimage
+				return &preimage, 0, nil
+
+			case reason != nil:
+				return nil, failureOutcome(*reason), nil
+			}
+
+		case <-f.quit:
+			return nil, 0, errShuttingDown
+		}
+	}
+}
+
+// failureOutcome maps the failure reason of the outgoing payment to the
+// failure of the incoming htlcs.
+func failureOutcome(
+	reason channeldb.FailureReason) invoices.FailResolutionResult {
+
+	// If the recipient rejected the payment, there's no point for the
+	// sender to retry through another trampoline node.
+	if reason == channeldb.FailureReasonPaymentDetails {
+		return invoices.ResultTrampolineIncorrectDetails
+	}
+
+	return invoices.ResultTrampolineForwardFailed
+}
+
+// resolveSet resolves all htlcs of a trampoline payment. The resolution is
+// delivered to the subscribers of the htlcs, or kept until the htlc is
+// notified again.
+//
+// NOTE: The caller MUST hold the mutex.
+func (f *Forwarder) resolveSet(key setKey, set *htlcSet,
+	resolve func(models.CircuitKey) invoices.HtlcResolution) {
+
+	for circuitKey, htlc := range set.htlcs {
+		resolution := resolve(circuitKey)
+
+		if htlc.hodlChan == nil {
+			htlc.resolution = resolution
+			continue
+		}
+
+		select {
+		case htlc.hodlChan <- resolution:
+		case <-f.quit:
+			return
+		}
+
+		f.removeHtlc(key, set, circuitKey)
+	}
+}
+
+// removeHtlc removes a resolved htlc from its set, and the set itself once
+// it is empty.
+//
+// NOTE: The caller MUST hold the mutex.
+func (f *Forwarder) removeHtlc(key setKey, set *htlcSet,
+	circuitKey models.CircuitKey) {
+
+	delete(set.htlcs, circuitKey)
+	if len(set.htlcs) == 0 {
+		set.timer.Stop()
+		delete(f.sets, key)
+	}
+}
@@ -0,0 +1,373 @@
+package trampoline
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHeight     = 100
+	testFinalDelta = 40
+	testAmount     = lnwire.MilliSatoshi(100_000)
+)
+
+var (
+	testBudget = Budget{
+		FeeBaseMsat: 1000,
+		FeeRatePPM:  5000,
+		CltvDelta:   144,
+	}
+
+	testPreimage = lntypes.Preimage{1, 2, 3}
+	testAddr     = [32]byte{4, 5, 6}
+)
+
+// mockPayload is the outer onion payload of a trampoline htlc.
+type mockPayload struct {
+	mpp *record.MPP
+}
+
+func (p *mockPayload) MultiPath() *record.MPP {
+	return p.mpp
+}
+
+func (p *mockPayload) AMPRecord() *record.AMP {
+	return nil
+}
+
+func (p *mockPayload) CustomRecords() record.CustomSet {
+	return nil
+}
+
+func (p *mockPayload) Metadata() []byte {
+	return nil
+}
+
+func (p *mockPayload) PathID() *chainhash.Hash {
+	return nil
+}
+
+func (p *mockPayload) TotalAmtMsat() lnwire.MilliSatoshi {
+	return 0
+}
+
+// mockSubscriber delivers a fixed list of payment updates.
+type mockSubscriber struct {
+	updates chan interface{}
+}
+
+func (s *mockSubscriber) Updates() <-chan interface{} {
+	return s.updates
+}
+
+func (s *mockSubscriber) Close() {}
+
+// forwarderTestCtx holds a forwarder and the trampoline payment sent to it.
+type forwarderTestCtx struct {
+	t         *testing.T
+	forwarder *Forwarder
+	payment   *Payment
+	hash      lntypes.Hash
+	target    *btcec.PublicKey
+	hodlChan  chan interface{}
+
+	sent   []*routing.LightningPayment
+	sentMu sync.Mutex
+}
+
+func newForwarderTestCtx(t *testing.T, budget Budget,
+	sendErr error) *forwarderTestCtx {
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	target, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	ctx := &forwarderTestCtx{
+		t:        t,
+		hash:     testPreimage.Hash(),
+		target:   target.PubKey(),
+		hodlChan: make(chan interface{}, 10),
+	}
+
+	ctx.payment, err = NewPayment(&PaymentRequest{
+		TrampolineNode: nodeKey.PubKey(),
+		Target:         target.PubKey(),
+		Amount:         testAmount,
+		PaymentHash:    ctx.hash,
+		PaymentAddr:    fn.Some(testAddr),
+		FinalCLTVDelta: testFinalDelta,
+		DestFeatures: lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(
+				lnwire.PaymentAddrRequired,
+				lnwire.MPPOptional,
+			), lnwire.Features,
+		),
+		CurrentHeight: testHeight,
+		Budget:        testBudget,
+	})
+	require.NoError(t, err)
+
+	settled := &channeldb.MPPayment{
+		Status: channeldb.StatusSucceeded,
+		HTLCs: []channeldb.HTLCAttempt{{
+			Settle: &channeldb.HTLCSettleInfo{
+				Preimage: testPreimage,
+			},
+		}},
+	}
+
+	ctx.forwarder = NewForwarder(&ForwarderConfig{
+		NodeKey:        &keychain.PrivKeyECDH{PrivKey: nodeKey},
+		Budget:         budget,
+		MinCltvDelta:   testFinalDelta,
+		MppTimeout:     50 * time.Millisecond,
+		PaymentTimeout: time.Minute,
+		MaxParts:       16,
+		SendPayment: func(p *routing.LightningPayment) ([32]byte,
+			*route.Route, error) {
+
+			ctx.sentMu.Lock()
+			ctx.sent = append(ctx.sent, p)
+			ctx.sentMu.Unlock()
+
+			return testPreimage, nil, sendErr
+		},
+		SubscribePayment: func(lntypes.Hash) (
+			routing.ControlTowerSubscriber, error) {
+
+			updates := make(chan interface{}, 1)
+			updates <- settled
+			close(updates)
+
+			return &mockSubscriber{updates: updates}, nil
+		},
+	})
+	require.NoError(t, ctx.forwarder.Start())
+	t.Cleanup(func() {
+		require.NoError(t, ctx.forwarder.Stop())
+	})
+
+	return ctx
+}
+
+// notify hands a part of the trampoline payment to the forwarder.
+func (c *forwarderTestCtx) notify(htlcID uint64,
+	amt lnwire.MilliSatoshi) invoices.HtlcResolution {
+
+	expiry := uint32(testHeight) + uint32(c.payment.FinalCLTVDelta)
+	resolution, err := c.forwarder.NotifyTrampolineHtlc(
+		c.hash, amt, expiry, testHeight,
+		models.CircuitKey{HtlcID: htlcID}, c.hodlChan,
+		&mockPayload{
+			mpp: record.NewMPP(
+				c.payment.Amount, c.payment.PaymentAddr,
+			),
+		},
+		c.payment.Onion,
+	)
+	require.NoError(c.t, err)
+
+	return resolution
+}
+
+// receive waits for the resolution of an htlc.
+func (c *forwarderTestCtx) receive() invoices.HtlcResolution {
+	select {
+	case resolution := <-c.hodlChan:
+		return resolution.(invoices.HtlcResolution)
+
+	case <-time.After(time.Second):
+		c.t.Fatal("no resolution received")
+		return nil
+	}
+}
+
+// TestForwarderSettle asserts that the forwarder waits for all parts of a
+// trampoline payment, pays the recipient and settles the incoming htlcs.
+func TestForwarderSettle(t *testing.T) {
+	t.Parallel()
+
+	// We charge less than the budget the sender leaves us, so the rest
+	// can be spent on routing fees.
+	ownBudget := Budget{
+		FeeBaseMsat: testBudget.FeeBaseMsat / 2,
+		FeeRatePPM:  testBudget.FeeRatePPM / 2,
+		CltvDelta:   testBudget.CltvDelta,
+	}
+	ctx := newForwarderTestCtx(t, ownBudget, nil)
+
+	first := ctx.payment.Amount / 2
+	require.Nil(t, ctx.notify(0, first))
+	require.Nil(t, ctx.notify(1, ctx.payment.Amount-first))
+
+	for i := 0; i < 2; i++ {
+		resolution := ctx.receive()
+
+		settle, ok := resolution.(*invoices.HtlcSettleResolution)
+		require.True(t, ok)
+		require.Equal(t, testPreimage, settle.Preimage)
+	}
+
+	// Parts that arrive after the payment completed are held until they
+	// time out.
+	require.Nil(t, ctx.notify(2, 1000))
+
+	fail, ok := ctx.receive().(*invoices.HtlcFailResolution)
+	require.True(t, ok)
+	require.Equal(t, invoices.ResultMppTimeout, fail.Outcome)
+
+	ctx.sentMu.Lock()
+	defer ctx.sentMu.Unlock()
+
+	require.Len(t, ctx.sent, 1)
+	sent := ctx.sent[0]
+	require.Equal(t, route.NewVertex(ctx.target), sent.Target)
+	require.Equal(t, testAmount, sent.Amount)
+	require.Equal(
+		t, testBudget.Fee(testAmount)-ownBudget.Fee(testAmount),
+		sent.FeeLimit,
+	)
+	require.EqualValues(t, testFinalDelta, sent.FinalCLTVDelta)
+	require.EqualValues(t, testBudget.CltvDelta, sent.CltvLimit)
+	require.Equal(t, fn.Some(testAddr), sent.PaymentAddr)
+	require.Equal(t, ctx.hash, lntypes.Hash(sent.Identifier()))
+	require.Nil(t, sent.TrampolineOnion)
+	require.True(t, sent.DestFeatures.HasFeature(lnwire.MPPOptional))
+}
+
+// TestForwarderAlreadyPaid asserts that the incoming htlcs are settled if the
+// payment to the recipient was made before.
+func TestForwarderAlreadyPaid(t *testing.T) {
+	t.Parallel()
+
+	ctx := newForwarderTestCtx(t, testBudget, channeldb.ErrAlreadyPaid)
+
+	require.Nil(t, ctx.notify(0, ctx.payment.Amount))
+
+	settle, ok := ctx.receive().(*invoices.HtlcSettleResolution)
+	require.True(t, ok)
+	require.Equal(t, testPreimage, settle.Preimage)
+}
+
+// TestForwarderFailures asserts that trampoline payments are failed back if
+// they can't or couldn't be forwarded.
+func TestForwarderFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		budget  Budget
+		sendErr error
+		amt     func(*Payment) lnwire.MilliSatoshi
+		outcome invoices.FailResolutionResult
+	}{
+		{
+			name: "fee insufficient",
+			budget: Budget{
+				FeeBaseMsat: testBudget.FeeBaseMsat * 2,
+				CltvDelta:   testBudget.CltvDelta,
+			},
+			outcome: invoices.ResultTrampolineFeeInsufficient,
+		},
+		{
+			name: "expiry too soon",
+			budget: Budget{
+				CltvDelta: testBudget.CltvDelta + 1,
+			},
+			outcome: invoices.ResultTrampolineExpiryTooSoon,
+		},
+		{
+			name:    "payment failed",
+			budget:  testBudget,
+			sendErr: channeldb.FailureReasonNoRoute,
+			outcome: invoices.ResultTrampolineForwardFailed,
+		},
+		{
+			name:    "incorrect payment details",
+			budget:  testBudget,
+			sendErr: channeldb.FailureReasonPaymentDetails,
+			outcome: invoices.ResultTrampolineIncorrectDetails,
+		},
+		{
+			name:   "mpp timeout",
+			budget: testBudget,
+			amt: func(p *Payment) lnwire.MilliSatoshi {
+				return p.Amount / 2
+			},
+			outcome: invoices.ResultMppTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := newForwarderTestCtx(t, test.budget, test.sendErr)
+
+			amt := ctx.payment.Amount
+			if test.amt != nil {
+				amt = test.amt(ctx.payment)
+			}
+			require.Nil(t, ctx.notify(0, amt))
+
+			fail, ok := ctx.receive().(*invoices.HtlcFailResolution)
+			require.True(t, ok)
+			require.Equal(t, test.outcome, fail.Outcome)
+		})
+	}
+}
+
+// TestForwarderUndelivered asserts that resolutions that couldn't be
+// delivered are handed out when the htlc is notified again.
+func TestForwarderUndelivered(t *testing.T) {
+	t.Parallel()
+
+	ctx := newForwarderTestCtx(t, testBudget, nil)
+
+	// Unsubscribe before the set times out, so the resolution can't be
+	// delivered.
+	ctx.forwarder.cfg.MppTimeout = 10 * time.Millisecond
+	require.Nil(t, ctx.notify(0, ctx.payment.Amount/2))
+	ctx.forwarder.TrampolineUnsubscribeAll(ctx.hodlChan)
+
+	require.Eventually(t, func() bool {
+		ctx.forwarder.mu.Lock()
+		defer ctx.forwarder.mu.Unlock()
+
+		for _, set := range ctx.forwarder.sets {
+			if set.complete {
+				return true
+			}
+		}
+
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	resolution := ctx.notify(0, ctx.payment.Amount/2)
+	fail, ok := resolution.(*invoices.HtlcFailResolution)
+	require.True(t, ok)
+	require.Equal(t, invoices.ResultMppTimeout, fail.Outcome)
+
+	ctx.forwarder.mu.Lock()
+	require.Empty(t, ctx.forwarder.sets)
+	ctx.forwarder.mu.Unlock()
+}
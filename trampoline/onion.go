@@ -0,0 +1,358 @@
+package trampoline
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+)
+
+const (
+	// OnionPayloadSize is the size of the routing info of the trampoline
+	// onions we create. All trampoline onions we create have the same size,
+	// so that they don't leak the number of trampoline hops.
+	OnionPayloadSize = 400
+
+	// onionVersion is the only trampoline onion version we understand.
+	onionVersion = 0
+
+	// hmacSize is the size of the HMAC that authenticates each layer of
+	// the onion.
+	hmacSize = sha256.Size
+
+	// onionOverhead is the number of bytes a serialized onion takes on
+	// top of its routing info: the version byte, the ephemeral key and
+	// the HMAC.
+	onionOverhead = 1 + btcec.PubKeyBytesLenCompressed + hmacSize
+)
+
+var (
+	// ErrInvalidOnionVersion is returned when we receive a trampoline
+	// onion with an unknown version.
+	ErrInvalidOnionVersion = errors.New("invalid trampoline onion version")
+
+	// ErrInvalidOnionHMAC is returned when the HMAC of a trampoline onion
+	// doesn't match its content.
+	ErrInvalidOnionHMAC = errors.New("invalid trampoline onion hmac")
+
+	// ErrPayloadTooLarge is returned when the hop payloads don't fit into
+	// the routing info of a trampoline onion.
+	ErrPayloadTooLarge = errors.New("trampoline hop payloads too large")
+)
+
+// OnionPacket is a trampoline onion. It uses the same construction as the
+// onion of a regular payment, but the size of its routing info is variable
+// so that it can be embedded in the payload of the final hop of an outer
+// onion. The packet of the sphinx package has a fixed size routing info, so
+// only its hop payloads and key primitives are reused here.
+type OnionPacket struct {
+	// Version is the version of the onion packet.
+	Version byte
+
+	// EphemeralKey is the key the receiving node uses together with its
+	// node key to derive the shared secret of this layer.
+	EphemeralKey *btcec.PublicKey
+
+	// RoutingInfo holds the encrypted hop payloads.
+	RoutingInfo []byte
+
+	// HeaderMAC authenticates the routing info and the associated data.
+	HeaderMAC [hmacSize]byte
+}
+
+// Encode serializes the onion packet into the passed writer.
+func (o *OnionPacket) Encode(w io.Writer) error {
+	if _, err := w.Write([]byte{o.Version}); err != nil {
+		return err
+	}
+
+	_, err := w.Write(o.EphemeralKey.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(o.RoutingInfo); err != nil {
+		return err
+	}
+
+	_, err = w.Write(o.HeaderMAC[:])
+
+	return err
+}
+
+// Bytes returns the serialized onion packet.
+func (o *OnionPacket) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if err := o.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeOnionPacket parses a serialized trampoline onion. The size of the
+// routing info is derived from the length of the passed bytes.
+func DecodeOnionPacket(b []byte) (*OnionPacket, error) {
+	if len(b) <= onionOverhead {
+		return nil, fmt.Errorf("trampoline onion of %d bytes too "+
+			"short", len(b))
+	}
+
+	if b[0] != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	pubKeyEnd := 1 + btcec.PubKeyBytesLenCompressed
+	ephemeralKey, err := btcec.ParsePubKey(b[1:pubKeyEnd])
+	if err != nil {
+		return nil, fmt.Errorf("invalid trampoline onion key: %w", err)
+	}
+
+	macStart := len(b) - hmacSize
+	o := &OnionPacket{
+		Version:      b[0],
+		EphemeralKey: ephemeralKey,
+		RoutingInfo:  make([]byte, macStart-pubKeyEnd),
+	}
+	copy(o.RoutingInfo, b[pubKeyEnd:macStart])
+	copy(o.HeaderMAC[:], b[macStart:])
+
+	return o, nil
+}
+
+// OnionHop is a single hop of a trampoline onion.
+type OnionHop struct {
+	// NodeKey is the public key of the trampoline node.
+	NodeKey *btcec.PublicKey
+
+	// Payload is the serialized TLV payload for the node.
+	Payload []byte
+}
+
+// NewOnionPacket creates a trampoline onion that delivers the payloads to the
+// given hops. The routing info of the onion is payloadSize bytes long, and the
+// associated data, usually the payment hash, is authenticated at every hop.
+func NewOnionPacket(hops []OnionHop, sessionKey *btcec.PrivateKey,
+	assocData []byte, payloadSize int) (*OnionPacket, error) {
+
+	if len(hops) == 0 {
+		return nil, errors.New("trampoline onion without hops")
+	}
+
+	// Wrap each payload so that we know the number of bytes it takes up
+	// in the routing info, HMAC included.
+	var (
+		payloads  = make([]sphinx.HopPayload, len(hops))
+		nodeKeys  = make([]*btcec.PublicKey, len(hops))
+		totalSize int
+	)
+	for i, onionHop := range hops {
+		payload, err := sphinx.NewTLVHopPayload(onionHop.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		payloads[i] = payload
+		nodeKeys[i] = onionHop.NodeKey
+		totalSize += payload.NumBytes()
+	}
+	if totalSize > payloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	secrets, err := hop.SharedSecrets(nodeKeys, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	filler, err := headerPadding(payloads, secrets, payloadSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start out with pseudo-random bytes so that the unused tail of the
+	// routing info doesn't reveal the number of hops.
+	var sessionKeyBytes [32]byte
+	copy(sessionKeyBytes[:], sessionKey.Serialize())
+	routingInfo, err := hop.CipherStream(
+		hop.GenerateKey("pad", sessionKeyBytes), payloadSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		nextHMAC   [hmacSize]byte
+		payloadBuf bytes.Buffer
+	)
+	for i := len(hops) - 1; i >= 0; i-- {
+		rhoKey := hop.GenerateKey("rho", secrets[i])
+		muKey := hop.GenerateKey("mu", secrets[i])
+
+		// The HMAC of the last hop is all zeroes, which tells the last
+		// node that it is the final trampoline hop.
+		payloads[i].HMAC = nextHMAC
+
+		payloadBuf.Reset()
+		if err := payloads[i].Encode(&payloadBuf); err != nil {
+			return nil, err
+		}
+
+		// Make room for this hop's payload at the front of the routing
+		// info and encrypt the result for this hop.
+		shift := payloadBuf.Len()
+		copy(routingInfo[shift:], routingInfo[:payloadSize-shift])
+		copy(routingInfo, payloadBuf.Bytes())
+
+		stream, err := hop.CipherStream(rhoKey, payloadSize)
+		if err != nil {
+			return nil, err
+		}
+		xor(routingInfo, stream)
+
+		if i == len(hops)-1 {
+			copy(routingInfo[payloadSize-len(filler):], filler)
+		}
+
+		nextHMAC = calcMAC(muKey, routingInfo, assocData)
+	}
+
+	return &OnionPacket{
+		Version:      onionVersion,
+		EphemeralKey: sessionKey.PubKey(),
+		RoutingInfo:  routingInfo,
+		HeaderMAC:    nextHMAC,
+	}, nil
+}
+
+// ProcessedOnion is the result of peeling a layer off a trampoline onion.
+type ProcessedOnion struct {
+	// Payload is the TLV payload destined for us.
+	Payload []byte
+
+	// NextPacket is the onion to hand to the next trampoline node. It is
+	// nil if we are the final trampoline hop.
+	NextPacket *OnionPacket
+}
+
+// ProcessOnionPacket peels a layer off the trampoline onion using our node
+// key, after checking its HMAC against the routing info and the associated
+// data.
+func ProcessOnionPacket(nodeKey sphinx.SingleKeyECDH, o *OnionPacket,
+	assocData []byte) (*ProcessedOnion, error) {
+
+	if o.Version != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	sharedSecret, err := nodeKey.ECDH(o.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := calcMAC(
+		hop.GenerateKey("mu", sharedSecret), o.RoutingInfo, assocData,
+	)
+	if !hmac.Equal(mac[:], o.HeaderMAC[:]) {
+		return nil, ErrInvalidOnionHMAC
+	}
+
+	// Decrypt the routing info padded with zeroes, which leaves us with
+	// our payload followed by the routing info of the next hop.
+	size := len(o.RoutingInfo)
+	hopInfo := make([]byte, 2*size)
+	copy(hopInfo, o.RoutingInfo)
+
+	stream, err := hop.CipherStream(
+		hop.GenerateKey("rho", sharedSecret), 2*size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	xor(hopInfo, stream)
+
+	var payload sphinx.HopPayload
+	if err := payload.Decode(bytes.NewReader(hopInfo)); err != nil {
+		return nil, err
+	}
+	if payload.Type != sphinx.PayloadTLV {
+		return nil, errors.New("legacy trampoline payload")
+	}
+
+	processed := &ProcessedOnion{
+		Payload: payload.Payload,
+	}
+	if payload.HMAC == [hmacSize]byte{} {
+		return processed, nil
+	}
+
+	nextEphemeral, err := sphinx.NextEphemeral(nodeKey, o.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	start := payload.NumBytes()
+	processed.NextPacket = &OnionPacket{
+		Version:      o.Version,
+		EphemeralKey: nextEphemeral,
+		RoutingInfo:  hopInfo[start : start+size],
+		HeaderMAC:    payload.HMAC,
+	}
+
+	return processed, nil
+}
+
+// headerPadding generates the filler that is placed at the tail of the
+// routing info of the last hop. It matches the bytes every hop appends while
+// peeling its layer, so that the HMACs of all hops check out.
+func headerPadding(payloads []sphinx.HopPayload, secrets [][32]byte,
+	payloadSize int) ([]byte, error) {
+
+	var fillerSize int
+	for _, payload := range payloads[:len(payloads)-1] {
+		fillerSize += payload.NumBytes()
+	}
+	filler := make([]byte, fillerSize)
+
+	fillerStart := payloadSize
+	for i, payload := range payloads[:len(payloads)-1] {
+		fillerEnd := payloadSize + payload.NumBytes()
+
+		stream, err := hop.CipherStream(
+			hop.GenerateKey("rho", secrets[i]), 2*payloadSize,
+		)
+		if err != nil {
+			return nil, err
+		}
+		xor(filler, stream[fillerStart:fillerEnd])
+
+		fillerStart -= payload.NumBytes()
+	}
+
+	return filler, nil
+}
+
+// calcMAC computes the HMAC of the routing info and the associated data.
+func calcMAC(key [32]byte, routingInfo, assocData []byte) [hmacSize]byte {
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(routingInfo)
+	mac.Write(assocData)
+
+	var sum [hmacSize]byte
+	copy(sum[:], mac.Sum(nil))
+
+	return sum
+}
+
+// xor xors b into dst, up to the length of the shorter slice.
+func xor(dst, b []byte) {
+	for i := 0; i < len(dst) && i < len(b); i++ {
+		dst[i] ^= b[i]
+	}
+}
@@ -0,0 +1,115 @@
+package trampoline
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnionRoundTrip asserts that a trampoline onion can be peeled layer by
+// layer by the nodes it was created for.
+func TestOnionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		keys     []*btcec.PrivateKey
+		hops     []OnionHop
+		assoc    = []byte("payment hash")
+		payloads = [][]byte{{1, 2, 3}, {4, 5, 6, 7}, {8}}
+	)
+	for _, payload := range payloads {
+		key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		keys = append(keys, key)
+		hops = append(hops, OnionHop{
+			NodeKey: key.PubKey(),
+			Payload: payload,
+		})
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	packet, err := NewOnionPacket(hops, sessionKey, assoc, OnionPayloadSize)
+	require.NoError(t, err)
+
+	for i, key := range keys {
+		// Each hop receives the serialized onion.
+		b, err := packet.Bytes()
+		require.NoError(t, err)
+
+		packet, err = DecodeOnionPacket(b)
+		require.NoError(t, err)
+
+		nodeKey := &keychain.PrivKeyECDH{PrivKey: key}
+
+		// The onion is bound to the associated data.
+		_, err = ProcessOnionPacket(nodeKey, packet, []byte("other"))
+		require.ErrorIs(t, err, ErrInvalidOnionHMAC)
+
+		processed, err := ProcessOnionPacket(nodeKey, packet, assoc)
+		require.NoError(t, err)
+		require.Equal(t, payloads[i], processed.Payload)
+
+		if i == len(keys)-1 {
+			require.Nil(t, processed.NextPacket)
+			break
+		}
+
+		require.NotNil(t, processed.NextPacket)
+		packet = processed.NextPacket
+	}
+}
+
+// TestOnionTampered asserts that a modified trampoline onion is rejected.
+func TestOnionTampered(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	hops := []OnionHop{{NodeKey: key.PubKey(), Payload: []byte{1, 2, 3}}}
+	packet, err := NewOnionPacket(hops, sessionKey, nil, OnionPayloadSize)
+	require.NoError(t, err)
+
+	packet.RoutingInfo[0] ^= 1
+
+	_, err = ProcessOnionPacket(
+		&keychain.PrivKeyECDH{PrivKey: key}, packet, nil,
+	)
+	require.ErrorIs(t, err, ErrInvalidOnionHMAC)
+
+	packet.RoutingInfo[0] ^= 1
+	packet.Version = 1
+
+	b, err := packet.Bytes()
+	require.NoError(t, err)
+
+	_, err = DecodeOnionPacket(b)
+	require.ErrorIs(t, err, ErrInvalidOnionVersion)
+}
+
+// TestOnionPayloadTooLarge asserts that payloads that don't fit into the
+// onion are rejected.
+func TestOnionPayloadTooLarge(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	hops := []OnionHop{{
+		NodeKey: key.PubKey(),
+		Payload: make([]byte, OnionPayloadSize),
+	}}
+	_, err = NewOnionPacket(hops, sessionKey, nil, OnionPayloadSize)
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+}
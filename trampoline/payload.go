@@ -0,0 +1,160 @@
+package trampoline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// Payload is the payload of a single hop of a trampoline onion. It tells the
+// trampoline node how much to pay to which node, and with which CLTV expiry.
+type Payload struct {
+	// AmtToForward is the amount the next node should receive.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutgoingCltv is the CLTV expiry of the htlcs the next node should
+	// receive.
+	OutgoingCltv uint32
+
+	// OutgoingNodeID is the node the trampoline node should pay. It is
+	// only omitted in the payload of the final recipient.
+	OutgoingNodeID *btcec.PublicKey
+
+	// MPP holds the payment secret and total amount of the recipient's
+	// invoice. It is set in the payload of the final recipient, and in
+	// the payload of the last trampoline node if the recipient doesn't
+	// support trampoline routing itself.
+	MPP *record.MPP
+
+	// Metadata is the payment metadata of the recipient's invoice.
+	Metadata []byte
+
+	// InvoiceFeatures are the features of the recipient's invoice. They
+	// are only set if the recipient doesn't support trampoline routing,
+	// in which case the last trampoline node pays the invoice directly.
+	InvoiceFeatures *lnwire.RawFeatureVector
+}
+
+// Encode serializes the payload as a TLV stream.
+func (p *Payload) Encode() ([]byte, error) {
+	amt := uint64(p.AmtToForward)
+	records := []tlv.Record{
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&p.OutgoingCltv),
+	}
+
+	if p.MPP != nil {
+		records = append(records, p.MPP.Record())
+	}
+
+	if p.OutgoingNodeID != nil {
+		records = append(
+			records, record.NewOutgoingNodeIDRecord(
+				&p.OutgoingNodeID,
+			),
+		)
+	}
+
+	if p.Metadata != nil {
+		records = append(records, record.NewMetadataRecord(&p.Metadata))
+	}
+
+	var features []byte
+	if p.InvoiceFeatures != nil {
+		var b bytes.Buffer
+		if err := p.InvoiceFeatures.EncodeBase256(&b); err != nil {
+			return nil, err
+		}
+
+		features = b.Bytes()
+		records = append(
+			records, record.NewInvoiceFeaturesRecord(&features),
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodePayload parses the payload of a trampoline onion hop and checks that
+// all required fields are present.
+func DecodePayload(b []byte) (*Payload, error) {
+	var (
+		p        Payload
+		amt      uint64
+		mpp      = &record.MPP{}
+		features []byte
+	)
+
+	stream, err := tlv.NewStream(
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&p.OutgoingCltv),
+		mpp.Record(),
+		record.NewOutgoingNodeIDRecord(&p.OutgoingNodeID),
+		record.NewMetadataRecord(&p.Metadata),
+		record.NewInvoiceFeaturesRecord(&features),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payload was created by the sender, so we decode it with the
+	// limits we apply to data from the network.
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(b),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// We can't forward the payment if the sender requires us to
+	// understand a record we don't know.
+	for t, value := range parsedTypes {
+		if value != nil && t%2 == 0 && t < record.CustomTypeStart {
+			return nil, fmt.Errorf("unknown required trampoline "+
+				"payload type %d", t)
+		}
+	}
+
+	if _, ok := parsedTypes[record.AmtOnionType]; !ok {
+		return nil, errors.New("trampoline payload without amount")
+	}
+	if _, ok := parsedTypes[record.LockTimeOnionType]; !ok {
+		return nil, errors.New("trampoline payload without cltv")
+	}
+	p.AmtToForward = lnwire.MilliSatoshi(amt)
+
+	if _, ok := parsedTypes[record.MPPOnionType]; ok {
+		p.MPP = mpp
+	}
+
+	if _, ok := parsedTypes[record.MetadataOnionType]; !ok {
+		p.Metadata = nil
+	}
+
+	if _, ok := parsedTypes[record.InvoiceFeaturesOnionType]; ok {
+		p.InvoiceFeatures = lnwire.NewRawFeatureVector()
+		err := p.InvoiceFeatures.DecodeBase256(
+			bytes.NewReader(features), len(features),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,104 @@
+package trampoline
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPayloadEncodeDecode asserts that trampoline payloads survive a round
+// trip through their TLV encoding.
+func TestPayloadEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload *Payload
+	}{
+		{
+			name: "minimal",
+			payload: &Payload{
+				AmtToForward: 1000,
+				OutgoingCltv: 800_000,
+			},
+		},
+		{
+			name: "next trampoline",
+			payload: &Payload{
+				AmtToForward:   1000,
+				OutgoingCltv:   800_000,
+				OutgoingNodeID: key.PubKey(),
+			},
+		},
+		{
+			name: "invoice details",
+			payload: &Payload{
+				AmtToForward:   1000,
+				OutgoingCltv:   800_000,
+				OutgoingNodeID: key.PubKey(),
+				MPP: record.NewMPP(
+					1000, [32]byte{1},
+				),
+				Metadata: []byte{1, 2, 3},
+				InvoiceFeatures: lnwire.NewRawFeatureVector(
+					lnwire.PaymentAddrRequired,
+					lnwire.MPPOptional,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := test.payload.Encode()
+			require.NoError(t, err)
+
+			decoded, err := DecodePayload(b)
+			require.NoError(t, err)
+			require.Equal(t, test.payload, decoded)
+		})
+	}
+}
+
+// TestDecodePayloadInvalid asserts that payloads missing required fields or
+// carrying unknown required fields are rejected.
+func TestDecodePayloadInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+	}{
+		{
+			name:    "no amount",
+			payload: []byte{4, 1, 1},
+		},
+		{
+			name:    "no cltv",
+			payload: []byte{2, 1, 1},
+		},
+		{
+			name:    "unknown even type",
+			payload: []byte{2, 1, 1, 4, 1, 1, 100, 0},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := DecodePayload(test.payload)
+		require.Error(t, err, test.name)
+	}
+
+	// Unknown odd types are ignored.
+	_, err := DecodePayload([]byte{2, 1, 1, 4, 1, 1, 101, 0})
+	require.NoError(t, err)
+}
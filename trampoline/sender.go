@@ -0,0 +1,167 @@
+package trampoline
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+)
+
+const (
+	// DefaultFeeBaseMsat is the default fixed part of the fee budget of a
+	// trampoline hop.
+	DefaultFeeBaseMsat = lnwire.MilliSatoshi(1000)
+
+	// DefaultFeeRatePPM is the default proportional part of the fee
+	// budget of a trampoline hop.
+	DefaultFeeRatePPM = 5000
+
+	// DefaultCltvDelta is the default CLTV budget of a trampoline hop.
+	DefaultCltvDelta = 576
+)
+
+// Budget is the fee and CLTV budget of a single trampoline hop. Senders leave
+// this budget to each trampoline node to find a route to the next node, and
+// trampoline nodes require at least this budget to forward a payment.
+type Budget struct {
+	// FeeBaseMsat is the fixed part of the fee budget.
+	FeeBaseMsat lnwire.MilliSatoshi
+
+	// FeeRatePPM is the part of the fee budget proportional to the
+	// forwarded amount, in parts per million.
+	FeeRatePPM uint64
+
+	// CltvDelta is the CLTV budget in blocks.
+	CltvDelta uint16
+}
+
+// Fee returns the fee budget for forwarding the given amount.
+func (b Budget) Fee(amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+	return b.FeeBaseMsat + amt*lnwire.MilliSatoshi(b.FeeRatePPM)/1_000_000
+}
+
+// PaymentRequest describes a payment that is sent through a trampoline node.
+type PaymentRequest struct {
+	// TrampolineNode is the trampoline node we pay through.
+	TrampolineNode *btcec.PublicKey
+
+	// Target is the final recipient of the payment.
+	Target *btcec.PublicKey
+
+	// Amount is the amount the recipient should receive.
+	Amount lnwire.MilliSatoshi
+
+	// PaymentHash is the hash of the payment.
+	PaymentHash lntypes.Hash
+
+	// PaymentAddr is the payment secret of the recipient's invoice.
+	PaymentAddr fn.Option[[32]byte]
+
+	// Metadata is the payment metadata of the recipient's invoice.
+	Metadata []byte
+
+	// FinalCLTVDelta is the CLTV delta the recipient requires.
+	FinalCLTVDelta uint16
+
+	// DestFeatures are the features of the recipient's invoice.
+	DestFeatures *lnwire.FeatureVector
+
+	// CurrentHeight is the current block height.
+	CurrentHeight uint32
+
+	// Budget is the fee and CLTV budget we leave to the trampoline node.
+	Budget Budget
+}
+
+// Payment describes how to pay the trampoline node, so that it forwards the
+// payment to the recipient.
+type Payment struct {
+	// Onion is the serialized trampoline onion to attach to the final hop
+	// of the route to the trampoline node.
+	Onion []byte
+
+	// Amount is the amount to pay to the trampoline node, which includes
+	// the fee budget.
+	Amount lnwire.MilliSatoshi
+
+	// FinalCLTVDelta is the CLTV delta of the payment to the trampoline
+	// node, which includes the CLTV budget.
+	FinalCLTVDelta uint16
+
+	// PaymentAddr is the random payment address the parts of the payment
+	// to the trampoline node are tied together with.
+	PaymentAddr [32]byte
+}
+
+// NewPayment creates the trampoline onion for a payment. The trampoline node
+// is the only hop of the onion, and is handed the invoice details to pay the
+// recipient directly. Since we don't accept trampoline payments ourselves, we
+// don't rely on the recipient to do so either.
+func NewPayment(req *PaymentRequest) (*Payment, error) {
+	// The trampoline node pays the invoice on our behalf, so it needs the
+	// payment secret to do so.
+	var mpp *record.MPP
+	req.PaymentAddr.WhenSome(func(addr [32]byte) {
+		mpp = record.NewMPP(req.Amount, addr)
+	})
+	if mpp == nil {
+		return nil, errors.New("trampoline payment requires a " +
+			"payment address")
+	}
+
+	payload := &Payload{
+		AmtToForward:   req.Amount,
+		OutgoingCltv:   req.CurrentHeight + uint32(req.FinalCLTVDelta),
+		OutgoingNodeID: req.Target,
+		MPP:            mpp,
+		Metadata:       req.Metadata,
+	}
+	if req.DestFeatures != nil {
+		payload.InvoiceFeatures = req.DestFeatures.RawFeatureVector
+	}
+
+	payloadBytes, err := payload.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	hops := []OnionHop{{
+		NodeKey: req.TrampolineNode,
+		Payload: payloadBytes,
+	}}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	onion, err := NewOnionPacket(
+		hops, sessionKey, req.PaymentHash[:], OnionPayloadSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create trampoline onion: %w",
+			err)
+	}
+
+	onionBytes, err := onion.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &Payment{
+		Onion:  onionBytes,
+		Amount: req.Amount + req.Budget.Fee(req.Amount),
+		FinalCLTVDelta: req.FinalCLTVDelta +
+			req.Budget.CltvDelta,
+	}
+	if _, err := rand.Read(payment.PaymentAddr[:]); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}